@@ -0,0 +1,370 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"cosa/internal/backup"
+	"cosa/internal/claude"
+	"cosa/internal/daemon"
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+	"cosa/internal/worker"
+)
+
+func backupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Back up and restore the data directory",
+		Long: `Back up the data directory: jobs, workers, sessions, chats, templates, the
+activity ledger and the daemon's other state. Backups are incremental:
+files are stored once by their contents, so each backup only adds what
+changed since the last. They are kept in <data_dir>-backups unless --to or
+--from says otherwise.
+
+Take a backup before an upgrade or an experiment, and restore it to go
+back.`,
+	}
+
+	cmd.AddCommand(
+		backupCreateCmd(),
+		backupListCmd(),
+		backupRestoreCmd(),
+	)
+
+	return cmd
+}
+
+// backupCreateResult is the result of 'cosa backup create'.
+type backupCreateResult struct {
+	ID   string `json:"id"`
+	Path string `json:"path"`
+	backup.Stats
+}
+
+func backupCreateCmd() *cobra.Command {
+	var to string
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Back up the data directory",
+		Long: `Back up the data directory. The daemon can keep running: each file is
+read whole, and the ledger is backed up to its last complete event. The
+daemon's log, PID file and sockets are left out.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := openBackups(to)
+			if err != nil {
+				return err
+			}
+			if inside(cfg.DataDir, repo.Root()) {
+				return fmt.Errorf("backups must be kept outside the data directory %s", cfg.DataDir)
+			}
+
+			m, stats, err := repo.Create(cfg.DataDir, skipBackup)
+			if err != nil {
+				return fmt.Errorf("backup failed: %w", err)
+			}
+
+			result := backupCreateResult{ID: m.ID, Path: repo.Root(), Stats: *stats}
+			if structuredOutput() {
+				return printStructured(result)
+			}
+
+			fmt.Printf("Backup %s created in %s\n", m.ID, repo.Root())
+			fmt.Printf("  Files:  %d (%s)\n", stats.Files, formatBytes(stats.Bytes))
+			fmt.Printf("  Stored: %d new or changed (%s); the rest are shared with earlier backups\n",
+				stats.NewObjects, formatBytes(stats.NewBytes))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&to, "to", "", "Backup directory (default <data_dir>-backups)")
+
+	return cmd
+}
+
+// backupInfo describes a backup in 'cosa backup list'.
+type backupInfo struct {
+	ID      string `json:"id"`
+	Created int64  `json:"created"`
+	Files   int    `json:"files"`
+	Size    int64  `json:"size"`
+}
+
+func backupListCmd() *cobra.Command {
+	var from string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List backups",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := openBackups(from)
+			if err != nil {
+				return err
+			}
+			manifests, err := repo.List()
+			if err != nil {
+				return fmt.Errorf("failed to list backups: %w", err)
+			}
+
+			infos := make([]backupInfo, 0, len(manifests))
+			for _, m := range manifests {
+				infos = append(infos, backupInfo{ID: m.ID, Created: m.Created.Unix(), Files: len(m.Files), Size: m.Size()})
+			}
+			if structuredOutput() {
+				return printStructured(infos)
+			}
+
+			if len(infos) == 0 {
+				fmt.Printf("No backups in %s\n", repo.Root())
+				return nil
+			}
+			table := NewTable("ID", "CREATED", "FILES", "SIZE")
+			for _, info := range infos {
+				table.AddRow(info.ID, time.Unix(info.Created, 0).Format("2006-01-02 15:04:05"),
+					fmt.Sprintf("%d", info.Files), formatBytes(info.Size))
+			}
+			table.Print()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Backup directory (default <data_dir>-backups)")
+
+	return cmd
+}
+
+// backupRestoreResult is the result of 'cosa backup restore'.
+type backupRestoreResult struct {
+	ID       string         `json:"id"`
+	DataDir  string         `json:"data_dir"`
+	Previous string         `json:"previous,omitempty"` // Where the replaced data directory was moved
+	Verified map[string]int `json:"verified,omitempty"` // What each store loaded, with --verify
+}
+
+func backupRestoreCmd() *cobra.Command {
+	var from string
+	var verify bool
+
+	cmd := &cobra.Command{
+		Use:   "restore [id]",
+		Short: "Restore the data directory from a backup",
+		Long: `Restore the data directory from a backup, the latest unless an ID from
+'cosa backup list' is given. The daemon must be stopped first.
+
+The backup is restored into a new directory next to the data directory,
+checking every file against its hash. With --verify the stores are then
+loaded from it as the daemon would load them, and the ledger is checked,
+and the restore stops if any file would be dropped or any event is
+damaged. Only then is the restored directory swapped in; the data
+directory it replaces is kept next to it, so the restore can be undone.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if daemon.IsRunning(cfg.SocketPath) {
+				return fmt.Errorf("the daemon is running; stop it with 'cosa stop' before restoring")
+			}
+
+			repo, err := openBackups(from)
+			if err != nil {
+				return err
+			}
+			var m *backup.Manifest
+			if len(args) == 1 {
+				m, err = repo.Load(args[0])
+			} else if m, err = repo.Latest(); err == nil && m == nil {
+				err = fmt.Errorf("no backups in %s", repo.Root())
+			}
+			if err != nil {
+				return err
+			}
+
+			dataDir := filepath.Clean(cfg.DataDir)
+			staging := filepath.Join(filepath.Dir(dataDir), "."+filepath.Base(dataDir)+".restore-"+m.ID)
+			if err := repo.Restore(m, staging); err != nil {
+				os.RemoveAll(staging)
+				return fmt.Errorf("restore failed: %w", err)
+			}
+
+			result := backupRestoreResult{ID: m.ID, DataDir: dataDir}
+			if verify {
+				counts, problems := replayStores(staging)
+				if len(problems) > 0 {
+					os.RemoveAll(staging)
+					cmd.SilenceUsage = true
+					return fmt.Errorf("backup %s failed verification; the data directory was not changed:\n  %s",
+						m.ID, strings.Join(problems, "\n  "))
+				}
+				result.Verified = counts
+			}
+
+			if _, err := os.Stat(dataDir); err == nil {
+				result.Previous = fmt.Sprintf("%s.pre-restore-%s", dataDir, time.Now().Format("20060102-150405"))
+				if err := os.Rename(dataDir, result.Previous); err != nil {
+					os.RemoveAll(staging)
+					return fmt.Errorf("failed to move the data directory aside: %w", err)
+				}
+			}
+			if err := os.Rename(staging, dataDir); err != nil {
+				if result.Previous != "" {
+					os.Rename(result.Previous, dataDir)
+				}
+				return fmt.Errorf("failed to swap in the restored data directory: %w", err)
+			}
+
+			if structuredOutput() {
+				return printStructured(result)
+			}
+
+			fmt.Printf("Restored backup %s into %s (%d files)\n", m.ID, dataDir, len(m.Files))
+			if result.Verified != nil {
+				v := result.Verified
+				fmt.Printf("  Verified: %d jobs, %d workers, %d sessions, %d chats, %d custom templates, %d ledger events\n",
+					v["jobs"], v["workers"], v["sessions"], v["chats"], v["templates"], v["events"])
+			}
+			if result.Previous != "" {
+				fmt.Printf("  The data directory it replaced is in %s\n", result.Previous)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Backup directory (default <data_dir>-backups)")
+	cmd.Flags().BoolVar(&verify, "verify", false, "Load the stores and check the ledger before swapping the backup in")
+
+	return cmd
+}
+
+// openBackups opens the backup directory at path, or the default one next
+// to the data directory.
+func openBackups(path string) (*backup.Repository, error) {
+	if path == "" {
+		path = filepath.Clean(cfg.DataDir) + "-backups"
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	return backup.Open(abs)
+}
+
+// inside reports whether path is dir or within it.
+func inside(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// skipBackup leaves the daemon's runtime files out of backups.
+func skipBackup(path string, dir bool) bool {
+	switch {
+	case path == filepath.Base(cfg.PIDPath()), path == "tunnels":
+		return true
+	case strings.HasPrefix(path, filepath.Base(cfg.DaemonLogPath())):
+		return true
+	}
+	return strings.HasSuffix(path, ".tmp")
+}
+
+// replayStores loads the stores in a restored data directory as the daemon
+// would, returning how much each loaded and the problems found: files a
+// store would drop, state that fails to load and damage to the ledger.
+func replayStores(dir string) (map[string]int, []string) {
+	loaded := make(map[string]int)
+	var problems []string
+
+	failed := func(name string, err error) {
+		problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+	}
+	// Stores skip files they cannot parse, so each should load one of
+	// whatever is in its directory
+	check := func(name string, n int) {
+		loaded[name] = n
+		files, invalid := scanJSON(filepath.Join(dir, name))
+		if n < files {
+			problems = append(problems, fmt.Sprintf("%s: %d of %d files could not be loaded %v", name, files-n, files, invalid))
+		}
+	}
+
+	if jobs, err := job.NewPersistentStore(filepath.Join(dir, "jobs")); err != nil {
+		failed("jobs", err)
+	} else {
+		check("jobs", len(jobs.List()))
+	}
+	if pool, err := worker.NewPersistentPool(filepath.Join(dir, "workers")); err != nil {
+		failed("workers", err)
+	} else {
+		check("workers", len(pool.PendingWorkers()))
+	}
+	var dataCipher *claude.Cipher
+	if cfg.Encryption.Enabled {
+		c, err := claude.OpenCipher(cfg.Encryption.KeychainService)
+		if err != nil {
+			failed("encryption", err)
+		}
+		dataCipher = c
+	}
+	// Read without resealing, so verifying leaves the backup's files as
+	// they were restored
+	if sessions, err := claude.ReadSessionStore(filepath.Join(dir, "sessions"), dataCipher); err != nil {
+		failed("sessions", err)
+	} else {
+		check("sessions", sessions.Count())
+	}
+	if chats, err := claude.ReadChatStore(filepath.Join(dir, "chats"), dataCipher); err != nil {
+		failed("chats", err)
+	} else {
+		check("chats", chats.Count())
+	}
+	if templates, err := job.NewPersistentTemplateStore(filepath.Join(dir, "templates")); err != nil {
+		failed("templates", err)
+	} else {
+		check("templates", len(templates.List())-len(templates.ListBuiltIn()))
+	}
+
+	if _, err := worker.LoadCalendar(filepath.Join(dir, "capacity.json")); err != nil {
+		failed("capacity.json", err)
+	}
+	if _, err := job.LoadWatchList(filepath.Join(dir, "watches.json")); err != nil {
+		failed("watches.json", err)
+	}
+	if _, err := job.LoadQueueControl(filepath.Join(dir, "queue.json")); err != nil {
+		failed("queue.json", err)
+	}
+
+	report, err := ledger.Verify(filepath.Join(dir, filepath.Base(cfg.LedgerPath())))
+	if err != nil {
+		failed("ledger", err)
+	} else {
+		loaded["events"] = report.Events
+		if damaged := len(report.Problems) - report.Count(ledger.ProblemOutOfOrder); damaged > 0 {
+			problems = append(problems, fmt.Sprintf("ledger: %d damaged lines", damaged))
+		}
+	}
+
+	return loaded, problems
+}
+
+// scanJSON returns how many JSON files are in dir, and the names of those
+// that are not valid JSON.
+func scanJSON(dir string) (int, []string) {
+	entries, _ := os.ReadDir(dir)
+	n := 0
+	var invalid []string
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		n++
+		if data, err := os.ReadFile(filepath.Join(dir, entry.Name())); err != nil || !json.Valid(data) {
+			invalid = append(invalid, entry.Name())
+		}
+	}
+	return n, invalid
+}
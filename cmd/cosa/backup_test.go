@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cosa/internal/claude"
+	"cosa/internal/config"
+)
+
+func TestReplayStores_LeavesFilesUnchanged(t *testing.T) {
+	hexKey, err := claude.GenerateDataKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(claude.DataKeyEnv, hexKey)
+	key, _ := claude.ParseDataKey(hexKey)
+	dataCipher, err := claude.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	saved := cfg
+	t.Cleanup(func() { cfg = saved })
+	cfg = config.DefaultConfig()
+	cfg.Encryption.Enabled = true
+
+	// A restored data directory with sessions and chats from before
+	// encryption was turned on next to ones written since
+	dir := t.TempDir()
+	plainSessions, _ := claude.NewSessionStore(filepath.Join(dir, "sessions"))
+	plainSessions.Save(&claude.SessionInfo{SessionID: "sess-old", WorkerName: "vito"})
+	sealedSessions, _ := claude.NewEncryptedSessionStore(filepath.Join(dir, "sessions-new"), dataCipher)
+	sealedSessions.Save(&claude.SessionInfo{SessionID: "sess-new", WorkerName: "sal"})
+	os.Rename(filepath.Join(dir, "sessions-new", "sess-new.json"), filepath.Join(dir, "sessions", "sess-new.json"))
+	os.Remove(filepath.Join(dir, "sessions-new"))
+	plainChats, _ := claude.NewChatStore(filepath.Join(dir, "chats"))
+	plainChats.Save(&claude.ChatTranscript{ID: "chat-1", Messages: []claude.ChatMessage{{Role: "user", Content: "hi"}}})
+	if err := os.WriteFile(filepath.Join(dir, filepath.Base(cfg.LedgerPath())), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Backdated, so a rewrite shows up even with coarse mtimes
+	restored := time.Now().Add(-time.Hour).Truncate(time.Second)
+	type file struct {
+		data    []byte
+		modTime time.Time
+	}
+	before := make(map[string]file)
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		os.Chtimes(path, restored, restored)
+		if !d.IsDir() {
+			data, _ := os.ReadFile(path)
+			before[path] = file{data, restored}
+		}
+		return nil
+	})
+
+	counts, problems := replayStores(dir)
+	if len(problems) > 0 {
+		t.Fatalf("expected the directory to verify, got %v", problems)
+	}
+	if counts["sessions"] != 2 || counts["chats"] != 1 {
+		t.Errorf("expected 2 sessions and 1 chat loaded, got %v", counts)
+	}
+
+	after := make(map[string]bool)
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		after[path] = true
+		want, ok := before[path]
+		if !ok {
+			t.Errorf("verify created %s", path)
+			return nil
+		}
+		data, _ := os.ReadFile(path)
+		info, _ := d.Info()
+		if !bytes.Equal(data, want.data) {
+			t.Errorf("verify rewrote %s", path)
+		}
+		if !info.ModTime().Equal(want.modTime) {
+			t.Errorf("verify touched %s: mtime %s, want %s", path, info.ModTime(), want.modTime)
+		}
+		return nil
+	})
+	for path := range before {
+		if !after[path] {
+			t.Errorf("verify removed %s", path)
+		}
+	}
+}
@@ -0,0 +1,377 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"cosa/internal/display"
+	"cosa/internal/protocol"
+)
+
+func workerOffCmd() *cobra.Command {
+	var from, until, reason string
+	var duration time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "off <name> [when]",
+		Short: "Schedule downtime for a worker",
+		Long: `Take a worker offline so the scheduler gives it no new jobs. A job it is
+already running is left to finish.
+
+When is a day ("friday", "tomorrow", "2026-03-06"), which takes the worker
+offline for that whole day, or a time ("2026-03-06 14:00", "15:30"). Without
+it the downtime starts now.
+
+  cosa worker off soldato-2 friday -r "model migration"
+  cosa worker off soldato-2 --for 2h
+  cosa worker off soldato-2 --from monday --until wednesday`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 2 {
+				if from != "" {
+					return fmt.Errorf("give the start either as an argument or with --from")
+				}
+				from = args[1]
+			}
+
+			now := time.Now()
+			start, wholeDay := now, false
+			if from != "" {
+				var err error
+				if start, wholeDay, err = parseWhen(from, now); err != nil {
+					return err
+				}
+			}
+
+			var end time.Time
+			switch {
+			case until != "" && duration > 0:
+				return fmt.Errorf("use either --until or --for")
+			case until != "":
+				t, day, err := parseWhen(until, now)
+				if err != nil {
+					return err
+				}
+				end = t
+				if day {
+					end = t.AddDate(0, 0, 1) // Through the end of that day
+				}
+			case duration > 0:
+				end = start.Add(duration)
+			case wholeDay:
+				end = start.AddDate(0, 0, 1)
+			default:
+				return fmt.Errorf("say when the downtime ends with --until or --for")
+			}
+
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			resp, err := client.Call(protocol.MethodCapacityOff, protocol.CapacityOffParams{
+				Worker: args[0],
+				Start:  start.Unix(),
+				End:    end.Unix(),
+				Reason: reason,
+			})
+			if err != nil {
+				return err
+			}
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var info protocol.DowntimeInfo
+			json.Unmarshal(resp.Result, &info)
+
+			if structuredOutput() {
+				return printStructured(info)
+			}
+
+			fmt.Printf("Worker %s is offline %s (downtime %s)\n", info.Worker,
+				formatWindow(time.Unix(info.Start, 0), time.Unix(info.End, 0)), display.ShortID(info.ID))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "When the downtime starts (default now)")
+	cmd.Flags().StringVar(&until, "until", "", "When the downtime ends; a day means the end of that day")
+	cmd.Flags().DurationVar(&duration, "for", 0, "How long the downtime lasts")
+	cmd.Flags().StringVarP(&reason, "reason", "r", "", "Why the worker is offline")
+
+	return cmd
+}
+
+func workerOnCmd() *cobra.Command {
+	var id string
+
+	cmd := &cobra.Command{
+		Use:   "on [name]",
+		Short: "Cancel a worker's downtime",
+		Long: `Cancel all of a worker's current and upcoming downtime, bringing it back
+online now. Use --id to cancel a single downtime window instead.`,
+		Args: cobra.RangeArgs(0, 1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params := protocol.CapacityOnParams{ID: id}
+			if len(args) == 1 {
+				params.Worker = args[0]
+			}
+
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			resp, err := client.Call(protocol.MethodCapacityOn, params)
+			if err != nil {
+				return err
+			}
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var result struct {
+				Cancelled []protocol.DowntimeInfo `json:"cancelled"`
+			}
+			json.Unmarshal(resp.Result, &result)
+
+			if structuredOutput() {
+				return printStructured(result)
+			}
+
+			if len(result.Cancelled) == 0 {
+				fmt.Println("No downtime scheduled")
+				return nil
+			}
+			for _, d := range result.Cancelled {
+				fmt.Printf("Cancelled downtime for %s %s\n", d.Worker,
+					formatWindow(time.Unix(d.Start, 0), time.Unix(d.End, 0)))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&id, "id", "", "Cancel only this downtime window")
+
+	return cmd
+}
+
+func workerLimitCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "limit <name> <jobs-per-day>",
+		Short: "Cap how many jobs a worker starts per day",
+		Long:  `Cap how many jobs a worker starts per day. A limit of 0 removes the cap.`,
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			limit, err := strconv.Atoi(args[1])
+			if err != nil || limit < 0 {
+				return fmt.Errorf("jobs per day must be a non-negative number")
+			}
+
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			resp, err := client.Call(protocol.MethodCapacityLimit, protocol.CapacityLimitParams{
+				Worker:   args[0],
+				MaxDaily: limit,
+			})
+			if err != nil {
+				return err
+			}
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			if limit == 0 {
+				fmt.Printf("Worker %s has no daily job limit\n", args[0])
+			} else {
+				fmt.Printf("Worker %s starts at most %d jobs per day\n", args[0], limit)
+			}
+			return nil
+		},
+	}
+}
+
+// Stats commands
+
+func statsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show statistics",
+	}
+
+	cmd.AddCommand(statsCapacityCmd())
+	cmd.AddCommand(statsOutcomesCmd())
+
+	return cmd
+}
+
+func statsCapacityCmd() *cobra.Command {
+	var days int
+
+	cmd := &cobra.Command{
+		Use:   "capacity",
+		Short: "Show upcoming worker capacity and when the queue should clear",
+		Long: `Show each worker's capacity for the coming days, taking scheduled downtime
+and daily job limits into account, and estimate when the jobs waiting now
+will be done.
+
+Each day shows how much of it the worker is online. The estimate uses the
+jobs finished per worker per day over the last week, capped by each
+worker's daily limit.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			resp, err := client.Call(protocol.MethodCapacityStatus, protocol.CapacityStatusParams{Days: days})
+			if err != nil {
+				return err
+			}
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var result protocol.CapacityStatusResult
+			json.Unmarshal(resp.Result, &result)
+
+			if structuredOutput() {
+				return printStructured(result)
+			}
+
+			if len(result.Workers) == 0 {
+				fmt.Println("No workers")
+				return nil
+			}
+
+			headers := []string{"WORKER", "LIMIT", "TODAY"}
+			for _, d := range result.Workers[0].Days {
+				day, _ := time.ParseInLocation("2006-01-02", d.Date, time.Local)
+				headers = append(headers, day.Format("Mon 2"))
+			}
+			table := NewTable(headers...)
+			for _, w := range result.Workers {
+				limit, today := "-", strconv.Itoa(w.StartedToday)
+				if w.MaxDaily > 0 {
+					limit = fmt.Sprintf("%d/day", w.MaxDaily)
+					today = fmt.Sprintf("%d/%d", w.StartedToday, w.MaxDaily)
+				}
+				row := []interface{}{w.Name, limit, today}
+				for _, d := range w.Days {
+					row = append(row, formatAvailability(d.Available))
+				}
+				table.AddRow(row...)
+			}
+			table.Print()
+
+			if len(result.Downtime) > 0 {
+				fmt.Println()
+				fmt.Println("Downtime:")
+				for _, d := range result.Downtime {
+					line := fmt.Sprintf("  %s  %s %s", display.ShortID(d.ID), display.PadRight(d.Worker, 12),
+						formatWindow(time.Unix(d.Start, 0), time.Unix(d.End, 0)))
+					if d.Reason != "" {
+						line += " - " + d.Reason
+					}
+					fmt.Println(line)
+				}
+			}
+
+			fmt.Println()
+			switch {
+			case result.Pending == 0:
+				fmt.Println("Queue: empty")
+			case result.ETA > 0:
+				eta := time.Unix(result.ETA, 0)
+				basis := "at daily limits"
+				if result.Throughput > 0 {
+					basis = fmt.Sprintf("at %.1f jobs per worker per day", result.Throughput)
+				}
+				fmt.Printf("Queue: %d jobs, done around %s (in %s %s)\n",
+					result.Pending, eta.Format("Mon Jan 2 15:04"), formatDuration(time.Until(eta)), basis)
+			default:
+				fmt.Printf("Queue: %d jobs, no estimate (not enough history or capacity)\n", result.Pending)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&days, "days", "d", 7, "Days ahead to show")
+
+	return cmd
+}
+
+// parseWhen parses a day or time for scheduling downtime. Days resolve to
+// local midnight and report wholeDay.
+func parseWhen(value string, now time.Time) (t time.Time, wholeDay bool, err error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	switch v := strings.ToLower(strings.TrimSpace(value)); v {
+	case "now":
+		return now, false, nil
+	case "today":
+		return today, true, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true, nil
+	default:
+		for d := 0; d < 7; d++ {
+			day := today.AddDate(0, 0, d)
+			name := strings.ToLower(day.Weekday().String())
+			if v == name || v == name[:3] {
+				return day, true, nil
+			}
+		}
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, true, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04", time.RFC3339} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, false, nil
+		}
+	}
+	if t, err := time.ParseInLocation("15:04", value, time.Local); err == nil {
+		return today.Add(time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute), false, nil
+	}
+	return time.Time{}, false, fmt.Errorf("%q is not a day or time (try \"friday\", \"2026-03-06\" or \"2026-03-06 14:00\")", value)
+}
+
+// formatWindow describes a downtime window.
+func formatWindow(start, end time.Time) string {
+	const layout = "Mon Jan 2 15:04"
+	if start.Hour() == 0 && start.Minute() == 0 && end.Hour() == 0 && end.Minute() == 0 {
+		last := end.AddDate(0, 0, -1)
+		if last.Equal(start) {
+			return "on " + start.Format("Mon Jan 2")
+		}
+		return fmt.Sprintf("from %s through %s", start.Format("Mon Jan 2"), last.Format("Mon Jan 2"))
+	}
+	return fmt.Sprintf("from %s until %s", start.Format(layout), end.Format(layout))
+}
+
+// formatAvailability shows how much of a day a worker is online.
+func formatAvailability(available float64) string {
+	switch {
+	case available <= 0:
+		return "off"
+	case available >= 0.999:
+		return "on"
+	default:
+		return fmt.Sprintf("%d%%", int(available*100+0.5))
+	}
+}
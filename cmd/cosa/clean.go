@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"cosa/internal/display"
+	"cosa/internal/protocol"
+)
+
+func cleanCmd() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "clean",
+		Short: "Remove leftover worktrees, branches and sessions",
+		Long: `Run the cleanup the daemon does every cleanup.interval now. It removes:
+
+  - worktrees and branches of failed and cancelled jobs older than
+    cleanup.max_age, and of jobs that no longer exist
+  - worktrees of finished jobs, oldest first, while job worktrees take up
+    more than cleanup.max_disk_usage
+  - worktrees of removed workers older than cleanup.max_age
+  - Claude sessions unused for cleanup.session_max_age
+
+Worktrees of jobs still in progress, and branches of completed jobs, which
+hold work that was not merged, are never removed. Everything removed is
+recorded in the activity ledger as a cleanup.removed event.
+
+--dry-run shows what would be removed without removing it.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			resp, err := client.Call(protocol.MethodCleanupRun, protocol.CleanupRunParams{DryRun: dryRun})
+			if err != nil {
+				return err
+			}
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var result protocol.CleanupRunResult
+			json.Unmarshal(resp.Result, &result)
+
+			if structuredOutput() {
+				return printStructured(result)
+			}
+
+			if len(result.Items) == 0 {
+				fmt.Println("Nothing to clean up")
+			} else {
+				table := NewTable("KIND", "TARGET", "JOB", "SIZE", "REASON")
+				for _, item := range result.Items {
+					size := "-"
+					if item.Size > 0 {
+						size = formatBytes(item.Size)
+					}
+					table.AddRow(item.Kind, item.Target, valueOrDefault(display.ShortID(item.JobID), "-"), size, item.Reason)
+				}
+				table.Print()
+
+				verb := "Removed"
+				if result.DryRun {
+					verb = "Would remove"
+				}
+				fmt.Printf("\n%s %d items", verb, len(result.Items))
+				if result.BytesFreed > 0 {
+					fmt.Printf(", freeing %s", formatBytes(result.BytesFreed))
+				}
+				fmt.Println()
+			}
+
+			if len(result.Errors) > 0 {
+				fmt.Printf("\nErrors:\n  %s\n", strings.Join(result.Errors, "\n  "))
+				cmd.SilenceUsage = true
+				return fmt.Errorf("cleanup had %d errors", len(result.Errors))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&dryRun, "dry-run", "n", false, "Show what would be removed without removing it")
+
+	return cmd
+}
+
+// formatDiskQuota describes a size limit in megabytes, 0 being none.
+func formatDiskQuota(mb int) string {
+	if mb <= 0 {
+		return "off"
+	}
+	return fmt.Sprintf("%dMB", mb)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"cosa/internal/protocol"
+)
+
+func costsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "costs",
+		Short: "Report what workers have spent",
+	}
+
+	cmd.AddCommand(costsExportCmd())
+
+	return cmd
+}
+
+func costsExportCmd() *cobra.Command {
+	var params protocol.CostsExportParams
+	var format, file string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export spend by day, worker and job as CSV or JSON",
+		Long: `Export the spend recorded in the ledger, broken down by day, worker and
+job, for expense reporting. --from and --to are local dates (YYYY-MM-DD)
+and both days are included; without them every recorded cost is exported.
+
+CSV has one row per worker per job per day, with the cost in dollars and
+the tokens used. JSON adds the totals. --json selects JSON unless --format
+is given.`,
+		Example: `  cosa costs export --from 2024-04-01 --to 2024-04-30 --format csv
+  cosa costs export --from 2024-04-01 --worker tony --format json -f april.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format == "" {
+				format = "csv"
+				if structuredOutput() {
+					format = "json"
+				}
+			}
+			if format != "csv" && format != "json" {
+				return fmt.Errorf("invalid format: %s (must be csv or json)", format)
+			}
+
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+			cmd.SilenceUsage = true
+
+			resp, err := client.Call(protocol.MethodCostsExport, params)
+			if err != nil {
+				return err
+			}
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var result protocol.CostsExportResult
+			json.Unmarshal(resp.Result, &result)
+
+			var data []byte
+			if format == "json" {
+				data, _ = json.MarshalIndent(result, "", "  ")
+				data = append(data, '\n')
+			} else {
+				data = costsCSV(result.Rows)
+			}
+
+			if file == "" || file == "-" {
+				os.Stdout.Write(data)
+				return nil
+			}
+			if err := os.WriteFile(file, data, 0644); err != nil {
+				return err
+			}
+			fmt.Printf("Exported %d rows to %s: $%.2f, %d tokens\n", len(result.Rows), file, result.TotalCost, result.TotalTokens)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&params.From, "from", "", "First day to include (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&params.To, "to", "", "Last day to include (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&params.Worker, "worker", "", "Only export this worker's spend")
+	cmd.Flags().StringVar(&format, "format", "", "Export format (csv, json)")
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Write to a file instead of stdout")
+
+	return cmd
+}
+
+// costsCSV writes cost rows as CSV with a header row.
+func costsCSV(rows []protocol.CostExportRow) []byte {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"day", "worker", "job_id", "job_description", "cost_usd", "tokens"})
+	for _, row := range rows {
+		w.Write([]string{
+			row.Day,
+			row.Worker,
+			row.JobID,
+			row.JobDesc,
+			strconv.FormatFloat(row.Cost, 'f', 4, 64),
+			strconv.Itoa(row.Tokens),
+		})
+	}
+	w.Flush()
+	return buf.Bytes()
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"time"
+
+	"cosa/internal/logging"
+)
+
+// daemonLogFilter selects the daemon log records to show.
+type daemonLogFilter struct {
+	level slog.Level
+	since time.Time
+	until time.Time
+}
+
+// newDaemonLogFilter builds a filter from the logs command flags. With no
+// level every record is shown.
+func newDaemonLogFilter(level, since, until string) (daemonLogFilter, error) {
+	filter := daemonLogFilter{level: slog.LevelDebug}
+	var err error
+	if level != "" {
+		if filter.level, err = logging.ParseLevel(level); err != nil {
+			return filter, err
+		}
+	}
+	if since != "" {
+		if filter.since, err = parseLogTime(since); err != nil {
+			return filter, fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if until != "" {
+		if filter.until, err = parseLogTime(until); err != nil {
+			return filter, fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+	return filter, nil
+}
+
+func (f daemonLogFilter) match(r logging.Record) bool {
+	if r.Level < f.level {
+		return false
+	}
+	if !f.since.IsZero() && r.Time.Before(f.since) {
+		return false
+	}
+	if !f.until.IsZero() && r.Time.After(f.until) {
+		return false
+	}
+	return true
+}
+
+// printDaemonLogLine prints a line of the daemon log if it matches filter.
+// Lines that are not records, such as output from a crash, are shown as
+// they are.
+func printDaemonLogLine(line []byte, filter daemonLogFilter) {
+	r, err := logging.ParseRecord(line)
+	if err != nil {
+		if len(line) > 0 {
+			fmt.Println(string(line))
+		}
+		return
+	}
+	if filter.match(r) {
+		fmt.Println(r.Format())
+	}
+}
+
+// showDaemonLog prints the last count matching records of the daemon log,
+// reading rotated files too when the current one has fewer.
+func showDaemonLog(path string, count int, filter daemonLogFilter) error {
+	var lines [][]byte
+	for n := 0; ; n++ {
+		p := path
+		if n > 0 {
+			p = logging.RotatedPath(path, n)
+		}
+		data, err := os.ReadFile(p)
+		if errors.Is(err, os.ErrNotExist) {
+			if n == 0 {
+				return fmt.Errorf("no daemon log at %s", path)
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		var matched [][]byte
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := append([]byte(nil), scanner.Bytes()...)
+			if r, err := logging.ParseRecord(line); err == nil && !filter.match(r) {
+				continue
+			}
+			matched = append(matched, line)
+		}
+		lines = append(matched, lines...)
+		if count > 0 && len(lines) >= count {
+			break
+		}
+	}
+
+	if count > 0 && len(lines) > count {
+		lines = lines[len(lines)-count:]
+	}
+	if len(lines) == 0 {
+		fmt.Println("No matching daemon log records")
+		return nil
+	}
+	for _, line := range lines {
+		printDaemonLogLine(line, filter)
+	}
+	return nil
+}
+
+// followDaemonLog prints records as they are added to the daemon log,
+// starting at its end, until interrupted. It picks up the new file after
+// the log is rotated.
+func followDaemonLog(path string, filter daemonLogFilter) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open daemon log: %w", err)
+	}
+	defer func() { f.Close() }()
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	fmt.Printf("Following %s (Ctrl+C to stop)...\n", path)
+	reader := bufio.NewReader(f)
+	var partial []byte
+	for {
+		select {
+		case <-sigCh:
+			return nil
+		case <-ticker.C:
+		}
+
+		for {
+			chunk, err := reader.ReadBytes('\n')
+			offset += int64(len(chunk))
+			if err != nil {
+				partial = append(partial, chunk...)
+				break
+			}
+			printDaemonLogLine(trimNewline(append(partial, chunk...)), filter)
+			partial = nil
+		}
+
+		// A smaller or different file means the log was rotated
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		current, _ := f.Stat()
+		if info.Size() < offset || (current != nil && !os.SameFile(info, current)) {
+			if next, err := os.Open(path); err == nil {
+				f.Close()
+				f, offset, partial = next, 0, nil
+				reader.Reset(f)
+			}
+		}
+	}
+}
+
+func trimNewline(b []byte) []byte {
+	for len(b) > 0 && (b[len(b)-1] == '\n' || b[len(b)-1] == '\r') {
+		b = b[:len(b)-1]
+	}
+	return b
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"cosa/internal/claude"
+)
+
+func dataKeyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "data-key",
+		Short: "Manage the key sessions and transcripts are encrypted with",
+		Long: `With encryption.enabled set, the daemon encrypts Claude sessions, job
+transcripts and chat transcripts at rest with AES-256-GCM. The key is read
+from ` + claude.DataKeyEnv + `, as 64 hex digits or base64, or else from
+the OS keychain: the login keychain on macOS, the Secret Service on Linux.
+
+Losing the key loses the encrypted files.`,
+	}
+
+	cmd.AddCommand(dataKeyInitCmd())
+
+	return cmd
+}
+
+func dataKeyInitCmd() *cobra.Command {
+	var printKey, force bool
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Generate a data key and store it in the OS keychain",
+		Long: `Generate a data key and store it in the OS keychain, under the service
+named by encryption.keychain_service. With --print the key is printed
+instead, to be kept in ` + claude.DataKeyEnv + ` or a secrets manager.
+
+Then turn encryption on with:
+  cosa settings set encryption.enabled true`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			service := cfg.Encryption.KeychainService
+
+			key, err := claude.GenerateDataKey()
+			if err != nil {
+				return err
+			}
+			if printKey {
+				fmt.Println(key)
+				return nil
+			}
+
+			// Replacing the key would leave what it encrypted unreadable
+			if _, err := claude.LoadDataKey(service); err == nil && !force {
+				return fmt.Errorf("a data key already exists; replacing it makes the files encrypted with it unreadable (use --force to replace it anyway)")
+			}
+			if err := claude.StoreDataKey(service, key); err != nil {
+				return err
+			}
+
+			fmt.Println("Data key stored in the OS keychain")
+			if !cfg.Encryption.Enabled {
+				fmt.Println("Turn encryption on with: cosa settings set encryption.enabled true")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&printKey, "print", false, "Print the key instead of storing it")
+	cmd.Flags().BoolVar(&force, "force", false, "Replace an existing key")
+
+	return cmd
+}
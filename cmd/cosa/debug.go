@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"cosa/internal/display"
+	"cosa/internal/protocol"
+)
+
+func debugCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "debug",
+		Short: "Look into the daemon's internals",
+	}
+
+	cmd.AddCommand(debugSchedulerCmd())
+
+	return cmd
+}
+
+func debugSchedulerCmd() *cobra.Command {
+	var on, off bool
+	var jobID string
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "scheduler",
+		Short: "Show why the scheduler did or didn't assign jobs",
+		Long: `Show the scheduler's recent decisions: for each pass over the ready jobs,
+which jobs it considered, how each worker fared and why each job was or
+wasn't assigned. Passes that decided the same as the one before are shown
+once, with how many times they repeated.
+
+The trace is off unless daemon.scheduler_trace sets how many passes to
+keep, or --on turns it on until the daemon stops.`,
+		Example: `  cosa debug scheduler --on
+  cosa debug scheduler --job 3f2a9c1e
+  cosa debug scheduler --off`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if on && off {
+				return fmt.Errorf("--on and --off cannot be used together")
+			}
+
+			client, err := connectDaemon()
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			params := protocol.DebugSchedulerParams{Job: jobID, Limit: limit}
+			if on || off {
+				params.Enable = &on
+			}
+			resp, err := client.Call(protocol.MethodDebugScheduler, params)
+			if err != nil {
+				return err
+			}
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var result protocol.DebugSchedulerResult
+			json.Unmarshal(resp.Result, &result)
+
+			if structuredOutput() {
+				return printStructured(result)
+			}
+
+			if !result.Enabled {
+				fmt.Println("Scheduler trace is off; turn it on with 'cosa debug scheduler --on'")
+				return nil
+			}
+			fmt.Printf("Scheduler trace is on, keeping %d passes\n", result.Size)
+			if len(result.Ticks) == 0 {
+				fmt.Println("\nNo passes recorded yet")
+				return nil
+			}
+			for _, tick := range result.Ticks {
+				fmt.Println()
+				printSchedulerTick(tick)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&on, "on", false, "Turn the trace on")
+	cmd.Flags().BoolVar(&off, "off", false, "Turn the trace off, dropping what it recorded")
+	cmd.Flags().StringVar(&jobID, "job", "", "Only show decisions about this job (ID or prefix)")
+	cmd.Flags().IntVarP(&limit, "limit", "n", 20, "Show at most this many passes (0 for all)")
+
+	return cmd
+}
+
+// printSchedulerTick prints one scheduler pass and the decisions it made.
+func printSchedulerTick(tick protocol.SchedulerTick) {
+	when := tick.Time.Format("15:04:05.000")
+	if tick.Repeats > 0 {
+		when += fmt.Sprintf(" (and %d more times until %s)", tick.Repeats, tick.LastTime.Format("15:04:05.000"))
+	}
+	fmt.Println(when)
+
+	if tick.Skipped != "" {
+		fmt.Printf("  Skipped: %s\n", tick.Skipped)
+	}
+	for _, d := range tick.Jobs {
+		outcome := "waiting: " + d.Reason
+		if d.Assigned != "" {
+			outcome = "assigned to " + d.Assigned
+		}
+		fmt.Printf("  Job %s %q: %s\n", display.ShortID(d.Job), truncate(d.Description, 50), outcome)
+
+		for _, w := range d.Workers {
+			verdict := fmt.Sprintf("candidate, score %d", w.Score)
+			if w.Reason != "" {
+				verdict = w.Reason
+			}
+			fmt.Printf("    %-12s %s\n", w.Worker, verdict)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"cosa/internal/display"
+	"cosa/internal/protocol"
+)
+
+// decomposeCallTimeout is how long job add --decompose waits for the
+// underboss's breakdown. Its --timeout is the jobs' own.
+const decomposeCallTimeout = 10 * time.Minute
+
+// addDecomposedJob has the underboss break a job into subtasks, shows the
+// breakdown and, once it is approved, creates it as an operation of
+// dependent jobs.
+func addDecomposedJob(params protocol.JobDecomposeParams, yes bool) error {
+	client, err := connectDaemon()
+	if err != nil {
+		return fmt.Errorf("daemon not running")
+	}
+	defer client.Close()
+	client.SetTimeout(decomposeCallTimeout)
+
+	if !structuredOutput() {
+		fmt.Println("Asking the underboss to break the job down...")
+	}
+	resp, err := client.Call(protocol.MethodJobDecompose, params)
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("%s", resp.Error.Message)
+	}
+	var proposal protocol.JobDecomposeResult
+	json.Unmarshal(resp.Result, &proposal)
+
+	if !yes {
+		fmt.Printf("\nProposed breakdown of %q:\n", params.Description)
+		for _, line := range proposal.Plan.Lines() {
+			fmt.Printf("  %s\n", line)
+		}
+		fmt.Println()
+		ok, err := confirm(bufio.NewReader(os.Stdin), fmt.Sprintf("Create these %d jobs?", len(proposal.Plan.Steps)), false)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Discarded; no jobs were created")
+			return nil
+		}
+	}
+
+	params.Plan = proposal.Plan
+	resp, err = client.Call(protocol.MethodJobDecompose, params)
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("%s", resp.Error.Message)
+	}
+	var result protocol.JobDecomposeResult
+	json.Unmarshal(resp.Result, &result)
+
+	if structuredOutput() {
+		return printStructured(result)
+	}
+
+	fmt.Printf("Operation created:\n")
+	fmt.Printf("  ID:   %s\n", display.ShortID(result.Operation.ID))
+	fmt.Printf("  Name: %s\n", result.Operation.Name)
+	fmt.Printf("  Jobs:\n")
+	for _, step := range result.Plan.Steps {
+		fmt.Printf("    %s  %s: %s\n", display.ShortID(result.Jobs[step.Key]), step.Key, step.Description)
+	}
+	return nil
+}
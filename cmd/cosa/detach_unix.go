@@ -0,0 +1,9 @@
+//go:build !windows
+
+package main
+
+import "os/exec"
+
+// detachProcess lets the daemon outlive the command that started it. On
+// Unix nothing is needed.
+func detachProcess(cmd *exec.Cmd) {}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"cosa/internal/ledger"
+	"cosa/internal/logging"
+	"cosa/internal/protocol"
+)
+
+// Results of a doctor check.
+const (
+	checkOK   = "ok"
+	checkWarn = "warn"
+	checkFail = "fail"
+)
+
+// DoctorCheck is one line of 'cosa doctor'.
+type DoctorCheck struct {
+	Name   string `json:"name" yaml:"name"`
+	Status string `json:"status" yaml:"status"`
+	Detail string `json:"detail" yaml:"detail"`
+	Hint   string `json:"hint,omitempty" yaml:"hint,omitempty"`
+}
+
+func doctorCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the health of the Cosa installation",
+		Long: `Check that the tools Cosa runs are installed, that its data directory
+is writable, whether the daemon is running, the health of the activity
+ledger and any errors the daemon logged in the last day.
+
+Exits with status 1 if a check fails.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			checks := []DoctorCheck{
+				checkBinary("claude", cfg.Claude.Binary),
+				checkBinary("git", "git"),
+				checkDataDir(),
+				checkDaemon(),
+				checkLedger(),
+				checkDaemonLog(),
+			}
+
+			if structuredOutput() {
+				return printStructured(checks)
+			}
+
+			failed := 0
+			for _, c := range checks {
+				mark := "✓"
+				switch c.Status {
+				case checkWarn:
+					mark = "!"
+				case checkFail:
+					mark = "✗"
+					failed++
+				}
+				fmt.Printf("%s %-11s %s\n", mark, c.Name, c.Detail)
+				if c.Hint != "" {
+					fmt.Printf("  %-11s %s\n", "", c.Hint)
+				}
+			}
+
+			if failed > 0 {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%d checks failed", failed)
+			}
+			return nil
+		},
+	}
+}
+
+func checkBinary(name, binary string) DoctorCheck {
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return DoctorCheck{Name: name, Status: checkFail, Detail: binary + " not found in PATH"}
+	}
+	return DoctorCheck{Name: name, Status: checkOK, Detail: path}
+}
+
+func checkDataDir() DoctorCheck {
+	check := DoctorCheck{Name: "data dir", Detail: cfg.DataDir}
+	if err := cfg.EnsureDataDir(); err != nil {
+		check.Status = checkFail
+		check.Detail = err.Error()
+		return check
+	}
+	f, err := os.CreateTemp(cfg.DataDir, ".doctor-*")
+	if err != nil {
+		check.Status = checkFail
+		check.Detail = fmt.Sprintf("%s is not writable", cfg.DataDir)
+		return check
+	}
+	f.Close()
+	os.Remove(f.Name())
+	check.Status = checkOK
+	return check
+}
+
+func checkDaemon() DoctorCheck {
+	check := DoctorCheck{Name: "daemon"}
+	client, err := connectDaemon()
+	if err != nil {
+		check.Status = checkWarn
+		check.Detail = "not running"
+		check.Hint = "Run 'cosa start' to start it."
+		return check
+	}
+	defer client.Close()
+
+	resp, err := client.Call(protocol.MethodStatus, nil)
+	if err != nil || resp.Error != nil {
+		check.Status = checkFail
+		check.Detail = "not responding on " + cfg.SocketPath
+		return check
+	}
+	var status protocol.StatusResult
+	json.Unmarshal(resp.Result, &status)
+
+	check.Status = checkOK
+	check.Detail = fmt.Sprintf("v%s, up %s, %d workers", status.Version, formatDuration(time.Duration(status.Uptime)*time.Second), status.Workers)
+	return check
+}
+
+func checkLedger() DoctorCheck {
+	check := DoctorCheck{Name: "ledger"}
+	result, err := verifyLedger(false)
+	if err != nil {
+		check.Status = checkFail
+		check.Detail = err.Error()
+		return check
+	}
+
+	check.Detail = fmt.Sprintf("%d events, %s", result.Events, formatBytes(result.Size))
+	if result.Healthy {
+		check.Status = checkOK
+		return check
+	}
+	check.Status = checkWarn
+	check.Detail += "; " + summarizeLedgerProblems(result)
+	if len(result.Problems) > countLedgerProblems(result, ledger.ProblemOutOfOrder) {
+		check.Status = checkFail
+		check.Hint = "Run 'cosa ledger verify --repair' to quarantine the damaged lines."
+	}
+	return check
+}
+
+// checkDaemonLog counts the errors in the current daemon log from the last
+// day.
+func checkDaemonLog() DoctorCheck {
+	check := DoctorCheck{Name: "daemon log", Status: checkOK}
+	path := cfg.DaemonLogPath()
+	f, err := os.Open(path)
+	if err != nil {
+		check.Detail = "no log yet"
+		return check
+	}
+	defer f.Close()
+
+	since := time.Now().Add(-24 * time.Hour)
+	errors := 0
+	var last logging.Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		r, err := logging.ParseRecord(scanner.Bytes())
+		if err != nil || r.Level < slog.LevelError || r.Time.Before(since) {
+			continue
+		}
+		errors++
+		last = r
+	}
+
+	if errors == 0 {
+		check.Detail = "no errors in the last day"
+		return check
+	}
+	check.Status = checkWarn
+	check.Detail = fmt.Sprintf("%d errors in the last day, latest: %s", errors, truncate(last.Msg, 50))
+	check.Hint = "Run 'cosa logs --daemon --level error' to see them."
+	return check
+}
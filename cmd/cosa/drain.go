@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"cosa/internal/daemon"
+	"cosa/internal/display"
+	"cosa/internal/protocol"
+)
+
+// drainDaemon asks the daemon to drain and follows its progress until it
+// has shut down.
+func drainDaemon(client *daemon.Client, timeout time.Duration) error {
+	// Subscribe first so that a drain with nothing to wait for is not missed
+	if err := client.Subscribe([]string{"daemon.drain_progress", "daemon.drained"}); err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	result, err := client.Drain(timeout)
+	if err != nil {
+		return err
+	}
+	if !structuredOutput() {
+		if timeout > 0 {
+			fmt.Printf("Draining; stopping anyway in %s\n", formatDuration(timeout))
+		} else {
+			fmt.Println("Draining")
+		}
+		printDrainProgress(result.Drain)
+	}
+	shown := drainKey(result.Drain)
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	events := make(chan *daemon.LedgerEvent)
+	go func() {
+		defer close(events)
+		for {
+			event, err := client.ReadEvent()
+			if err != nil {
+				return
+			}
+			events <- event
+		}
+	}()
+
+	drained := false
+	for {
+		select {
+		case <-sigCh:
+			if !structuredOutput() {
+				fmt.Println("\nStopped following; the daemon is still draining")
+			}
+			return nil
+
+		case event, ok := <-events:
+			if !ok {
+				// The daemon closes connections as it stops
+				if !drained {
+					return fmt.Errorf("lost connection to the daemon before it finished draining")
+				}
+				if !structuredOutput() {
+					fmt.Println("Daemon stopped")
+				}
+				return nil
+			}
+
+			if structuredOutput() {
+				printStreamEvent(event)
+			}
+
+			var progress protocol.DrainProgress
+			if err := json.Unmarshal(event.Data, &progress); err != nil {
+				continue
+			}
+			drained = event.Type == "daemon.drained"
+			if structuredOutput() {
+				continue
+			}
+
+			switch {
+			case drained && progress.TimedOut:
+				fmt.Printf("Timed out after %s; stopping the work still running:\n", formatDuration(time.Since(time.Unix(progress.Started, 0))))
+				printDrainItems(&progress)
+			case drained:
+				fmt.Printf("Drained in %s\n", formatDuration(time.Since(time.Unix(progress.Started, 0))))
+			case drainKey(&progress) != shown:
+				printDrainProgress(&progress)
+				shown = drainKey(&progress)
+			}
+		}
+	}
+}
+
+// printDrainProgress shows how much work a drain is waiting on, and what.
+func printDrainProgress(p *protocol.DrainProgress) {
+	if p == nil || p.Remaining() == 0 {
+		return
+	}
+
+	line := fmt.Sprintf("[%s] Waiting on %s, %s, %s",
+		time.Now().Format("15:04:05"),
+		plural(len(p.Jobs), "job"), plural(len(p.Reviews), "review"), plural(p.Merges, "merge"))
+	if p.Deadline > 0 {
+		line += fmt.Sprintf(" · %s left", formatDuration(time.Until(time.Unix(p.Deadline, 0))))
+	}
+	fmt.Println(line)
+	printDrainItems(p)
+}
+
+func printDrainItems(p *protocol.DrainProgress) {
+	for _, item := range p.Jobs {
+		fmt.Printf("  job     %s  %s %s\n", display.ShortID(item.JobID), display.PadRight(valueOrDefault(item.Worker, "-"), 10), display.Truncate(item.Description, 50))
+	}
+	for _, item := range p.Reviews {
+		fmt.Printf("  review  %s  %s %s\n", display.ShortID(item.JobID), display.PadRight(valueOrDefault(item.Worker, "-"), 10), display.Truncate(item.Description, 50))
+	}
+}
+
+// drainKey identifies the work a drain is waiting on, to show progress only
+// when it changes.
+func drainKey(p *protocol.DrainProgress) string {
+	if p == nil {
+		return ""
+	}
+	return fmt.Sprint(p.Jobs, p.Reviews, p.Merges)
+}
+
+// plural formats a count of things, such as "1 job" or "2 jobs".
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"cosa/internal/daemon"
+	"cosa/internal/display"
+	"cosa/internal/protocol"
+)
+
+// execTeardownTimeout bounds how long exec waits for the associate to merge
+// and be torn down after its job finishes.
+const execTeardownTimeout = 2 * time.Minute
+
+func workerExecCmd() *cobra.Command {
+	var noMerge bool
+	var name string
+	var model string
+
+	cmd := &cobra.Command{
+		Use:   "exec <prompt>",
+		Short: "Run a one-shot task on a temporary associate",
+		Long: `Spawn a temporary associate, give it a single prompt and stream its
+session to the terminal. When it finishes its work is merged like any other
+job, or kept on its branch with --no-merge, and the associate and its
+worktree are torn down. The job is not sent for review.
+
+Use '-' to read the prompt from stdin. Ctrl+C cancels the job.
+
+  cosa worker exec "fix the flaky TestFoo"
+  cosa worker exec --no-merge "try switching the cache to an LRU"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			text := args[0]
+			if text == "-" {
+				data, err := readJobText("-")
+				if err != nil {
+					return err
+				}
+				text = data
+			}
+			description, body := splitJobText(text, "")
+
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			resp, err := client.Call(protocol.MethodWorkerExec, protocol.WorkerExecParams{
+				Description: description,
+				Body:        body,
+				Name:        name,
+				Model:       model,
+				NoMerge:     noMerge,
+			})
+			if err != nil {
+				return err
+			}
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var started protocol.WorkerExecResult
+			json.Unmarshal(resp.Result, &started)
+
+			if !structuredOutput() {
+				fmt.Printf("Running job %s on %s (Ctrl+C to cancel)\n\n", display.ShortID(started.JobID), started.Worker)
+			}
+
+			sigCh := make(chan os.Signal, 1)
+			signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+			defer signal.Stop(sigCh)
+
+			// Stream the session until the job finishes
+			offset := 0
+			for {
+				resp, err := client.Call(protocol.MethodWorkerTranscript, protocol.WorkerTranscriptParams{
+					Name:   started.Worker,
+					JobID:  started.JobID,
+					Offset: offset,
+				})
+				if err != nil {
+					return err
+				}
+				if resp.Error != nil {
+					return fmt.Errorf("%s", resp.Error.Message)
+				}
+				var result protocol.WorkerTranscriptResult
+				json.Unmarshal(resp.Result, &result)
+
+				for _, e := range result.Entries {
+					if structuredOutput() {
+						if err := printStreamEvent(e); err != nil {
+							return err
+						}
+					} else {
+						printTranscriptEntry(e)
+					}
+				}
+				offset = result.Next
+
+				if !result.Active {
+					break
+				}
+
+				select {
+				case <-sigCh:
+					return cancelExecJob(client, started.JobID)
+				case <-time.After(time.Second):
+				}
+			}
+
+			// The merge happens before the associate is torn down
+			deadline := time.Now().Add(execTeardownTimeout)
+			for time.Now().Before(deadline) {
+				resp, err := client.Call(protocol.MethodWorkerStatus, protocol.WorkerNameParams{Name: started.Worker})
+				if err != nil {
+					return err
+				}
+				if resp.Error != nil && resp.Error.Code == protocol.ErrWorkerNotFound {
+					break
+				}
+				time.Sleep(200 * time.Millisecond)
+			}
+
+			resp, err = client.Call(protocol.MethodJobStatus, protocol.JobStatusParams{ID: started.JobID})
+			if err != nil {
+				return err
+			}
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+			var info protocol.JobInfo
+			json.Unmarshal(resp.Result, &info)
+
+			if structuredOutput() {
+				if err := printStreamEvent(info); err != nil {
+					return err
+				}
+			} else {
+				fmt.Println()
+				printExecOutcome(info)
+			}
+
+			if info.Status != "completed" {
+				return fmt.Errorf("job %s", info.Status)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&noMerge, "no-merge", false, "Keep the work on its branch instead of merging it")
+	cmd.Flags().StringVar(&name, "name", "", "Name for the associate (default associate-N)")
+	cmd.Flags().StringVar(&model, "model", "", "Claude model to use")
+
+	return cmd
+}
+
+// cancelExecJob cancels an exec job after Ctrl+C.
+func cancelExecJob(client *daemon.Client, jobID string) error {
+	resp, err := client.Call(protocol.MethodJobCancel, protocol.JobCancelParams{ID: jobID})
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("%s", resp.Error.Message)
+	}
+	if !structuredOutput() {
+		fmt.Printf("\nCancelled job %s\n", display.ShortID(jobID))
+	}
+	return nil
+}
+
+// printExecOutcome describes what became of an exec job's work.
+func printExecOutcome(info protocol.JobInfo) {
+	switch {
+	case info.Status == "failed":
+		fmt.Printf("Job %s failed: %s\n", display.ShortID(info.ID), info.Error)
+		if info.Branch != "" {
+			fmt.Printf("Work kept on branch %s\n", info.Branch)
+		}
+	case info.Status != "completed":
+		fmt.Printf("Job %s %s\n", display.ShortID(info.ID), info.Status)
+	case info.NoMerge:
+		fmt.Printf("Done. Work kept on branch %s\n", info.Branch)
+	case info.PullRequest != "":
+		fmt.Printf("Done. Opened %s\n", info.PullRequest)
+	case info.RemoteBranch != "":
+		fmt.Printf("Done. Pushed branch %s\n", info.RemoteBranch)
+	case info.Unmerged:
+		fmt.Printf("Done, but branch %s conflicts with the merge target (see 'cosa job conflicts')\n", info.Branch)
+	case info.Branch != "":
+		fmt.Printf("Done. Branch %s was not merged (see 'cosa logs')\n", info.Branch)
+	default:
+		fmt.Println("Done. Changes merged")
+	}
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"cosa/internal/daemon"
+	"cosa/internal/display"
+	"cosa/internal/protocol"
+)
+
+// followPoll is how often cosa job follow asks whether a job has settled.
+// Its worker finishing with it is not an event of its own.
+const followPoll = time.Second
+
+// lifecycleEvents are the events cosa job follow --quiet shows.
+var lifecycleEvents = []string{"job.*", "gate.*", "review.*", "merge.*"}
+
+func jobFollowCmd() *cobra.Command {
+	var quiet bool
+
+	cmd := &cobra.Command{
+		Use:   "follow <id>",
+		Short: "Stream a job's events until it finishes",
+		Long: `Stream a job's events to the terminal as they happen: Claude's messages
+and tool calls, gate results, the review and the merge. Following stops
+once the job is over and its worker is done with it, and exits with an
+error if the job failed or was cancelled.
+
+With --quiet only lifecycle events are shown: status changes, gates,
+reviews and merges.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			// Subscribe first so that nothing between the status check and
+			// the subscription is missed
+			filter := &protocol.LogFilter{Job: args[0], Exclude: []string{"job.transition"}}
+			if quiet {
+				filter.Types = lifecycleEvents
+			}
+			if err := client.SubscribeFiltered([]string{"*"}, filter); err != nil {
+				return fmt.Errorf("failed to subscribe: %w", err)
+			}
+
+			info, err := followedJob(client, args[0])
+			if err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+			if !structuredOutput() {
+				fmt.Printf("Following job %s: %s (Ctrl+C to stop)\n", display.ShortID(info.ID), info.Description)
+			}
+			return followJob(client, info)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "Only show status changes, gates, reviews and merges")
+
+	return cmd
+}
+
+// followedJob returns the job being followed as it is now.
+func followedJob(client *daemon.Client, id string) (*protocol.JobInfo, error) {
+	resp, err := client.Call(protocol.MethodJobStatus, protocol.JobStatusParams{ID: id})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("%s", resp.Error.Message)
+	}
+
+	var info protocol.JobInfo
+	json.Unmarshal(resp.Result, &info)
+	return &info, nil
+}
+
+// followJob prints the job's events until it settles, returning an error
+// if it did not complete.
+func followJob(client *daemon.Client, info *protocol.JobInfo) error {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	events := make(chan *daemon.LedgerEvent)
+	go func() {
+		defer close(events)
+		for {
+			event, err := client.ReadEvent()
+			if err != nil {
+				return
+			}
+			events <- event
+		}
+	}()
+
+	ticker := time.NewTicker(followPoll)
+	defer ticker.Stop()
+
+	for !info.Settled {
+		select {
+		case <-sigCh:
+			if !structuredOutput() {
+				fmt.Println("\nStopped following; the job carries on")
+			}
+			return nil
+
+		case event, ok := <-events:
+			if !ok {
+				return fmt.Errorf("lost connection to the daemon")
+			}
+			if structuredOutput() {
+				if err := printStreamEvent(event); err != nil {
+					return err
+				}
+				continue
+			}
+			printFollowEvent(event)
+
+		case <-ticker.C:
+			next, err := followedJob(client, info.ID)
+			if err != nil {
+				return err
+			}
+			info = next
+		}
+	}
+
+	switch info.Status {
+	case "failed":
+		return fmt.Errorf("job %s failed: %s", display.ShortID(info.ID), valueOrDefault(info.Error, "no reason given"))
+	case "cancelled":
+		return fmt.Errorf("job %s was cancelled", display.ShortID(info.ID))
+	}
+	if !structuredOutput() {
+		fmt.Printf("Job %s %s\n", display.ShortID(info.ID), info.Status)
+	}
+	return nil
+}
+
+// printFollowEvent prints one of a followed job's events as a line, or
+// nothing for events that repeat what another has said.
+func printFollowEvent(event *daemon.LedgerEvent) {
+	var data map[string]interface{}
+	json.Unmarshal(event.Data, &data)
+	field := func(key string) string {
+		v, _ := data[key].(string)
+		return v
+	}
+	// Errors and reasons can span lines; they are shown on one
+	line := func(key string) string {
+		return strings.Join(strings.Fields(field(key)), " ")
+	}
+	// Only a failed gate's events name it
+	gate := "Gates"
+	if name := field("gate_name"); name != "" {
+		gate = "Gate " + name
+	}
+	ts := event.Timestamp.Local().Format("15:04:05")
+	say := func(format string, args ...interface{}) {
+		fmt.Printf("[%s] %s\n", ts, fmt.Sprintf(format, args...))
+	}
+
+	switch event.Type {
+	case "worker.message":
+		say("%s", strings.TrimSpace(field("message")))
+	case "worker.tool_use":
+		say("-> %s", strings.TrimPrefix(field("message"), "Using tool: "))
+	case "worker.tool_result", "worker.job_completed", "worker.job_failed", "worker.job_cancelled", "job.created":
+		// Said by the job's own events
+	case "job.queued":
+		say("Queued for %s", valueOrDefault(field("worker_name"), "a worker"))
+	case "job.started":
+		say("Started on %s", valueOrDefault(field("worker_name"), field("worker")))
+	case "job.completed":
+		say("Completed")
+	case "job.failed":
+		say("Failed: %s", line("error"))
+	case "job.cancelled":
+		say("Cancelled")
+
+	case "gate.started":
+		say("%s running", gate)
+	case "gate.passed":
+		say("%s passed", gate)
+	case "gate.failed":
+		say("%s failed", gate)
+		printIndented(lastLines(field("output"), 10))
+
+	case "review.started":
+		say("Review started")
+	case "review.phase":
+		say("Review: %s", field("phase"))
+	case "review.approved":
+		say("Review approved: %s", valueOrDefault(field("summary"), "no summary"))
+	case "review.rejected":
+		if revision := field("revision_job_id"); revision != "" {
+			say("Review requested changes; revision job %s queued", display.ShortID(revision))
+		} else {
+			say("Review rejected")
+		}
+		printIndented(field("feedback"))
+	case "review.failed":
+		say("Review failed: %s", line("error"))
+
+	case "merge.started":
+		say("Merging %s into %s", field("worker_branch"), field("base_branch"))
+	case "merge.completed":
+		say("Merged into %s (%s)", field("base_branch"), display.ShortID(field("merge_commit")))
+	case "merge.failed":
+		say("Merge failed: %s", line("error"))
+
+	case "cost.record":
+		say("Cost %s (%v tokens)", field("cost"), data["tokens"])
+
+	default:
+		// The worker's other events say what happened in their message
+		if strings.HasPrefix(event.Type, "worker.") {
+			if message := field("message"); message != "" {
+				say("%s", message)
+			}
+			return
+		}
+
+		// Events without a line of their own, such as job.merged
+		what := event.Type
+		if kind, ok := strings.CutPrefix(what, "job."); ok {
+			what = strings.ReplaceAll(kind, "_", " ")
+		}
+		var detail string
+		for _, key := range []string{"error", "description", "message", "reason"} {
+			if detail = line(key); detail != "" {
+				break
+			}
+		}
+		if detail != "" {
+			say("%s: %s", capitalize(what), detail)
+		} else {
+			say("%s", capitalize(what))
+		}
+	}
+}
+
+// printIndented prints text under an event line.
+func printIndented(text string) {
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		if line != "" {
+			fmt.Printf("    %s\n", line)
+		}
+	}
+}
+
+// lastLines returns the last n lines of text.
+func lastLines(text string, n int) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// capitalize upper-cases the first letter of s.
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
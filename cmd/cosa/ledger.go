@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"cosa/internal/daemon"
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+)
+
+func ledgerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ledger",
+		Short: "Inspect the activity ledger",
+	}
+
+	cmd.AddCommand(ledgerVerifyCmd())
+
+	return cmd
+}
+
+func ledgerVerifyCmd() *cobra.Command {
+	var repair bool
+
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check the activity ledger for damage",
+		Long: `Check the activity ledger for lines cut off by a crash, lines that are
+not valid events, events recorded twice and events out of time order.
+
+--repair moves truncated, corrupt and duplicate lines into a quarantine
+file next to the ledger, where they can be inspected. Events out of order
+are valid and are kept. The ledger is checked through the daemon when it
+is running, and read directly otherwise.
+
+Exits with status 1 if problems remain.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := verifyLedger(repair)
+			if err != nil {
+				return err
+			}
+
+			if structuredOutput() {
+				return printStructured(result)
+			}
+
+			printLedgerReport(result)
+
+			remaining := len(result.Problems)
+			if result.Quarantined > 0 {
+				remaining -= result.Quarantined
+				fmt.Printf("\nQuarantined %d lines to %s\n", result.Quarantined, result.QuarantinePath)
+			}
+			if remaining > 0 {
+				if !repair && remaining > countLedgerProblems(result, ledger.ProblemOutOfOrder) {
+					fmt.Println("\nRun 'cosa ledger verify --repair' to quarantine the damaged lines.")
+				}
+				cmd.SilenceUsage = true
+				return fmt.Errorf("ledger has %d problems", remaining)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&repair, "repair", false, "Quarantine truncated, corrupt and duplicate lines")
+
+	return cmd
+}
+
+// verifyLedger checks the ledger through the daemon, or directly when the
+// daemon is not running.
+func verifyLedger(repair bool) (protocol.LedgerVerifyResult, error) {
+	client, err := connectDaemon()
+	if err == nil {
+		defer client.Close()
+
+		resp, err := client.Call(protocol.MethodLedgerVerify, protocol.LedgerVerifyParams{Repair: repair})
+		if err != nil {
+			return protocol.LedgerVerifyResult{}, err
+		}
+		if resp.Error != nil {
+			return protocol.LedgerVerifyResult{}, fmt.Errorf("%s", resp.Error.Message)
+		}
+
+		var result protocol.LedgerVerifyResult
+		json.Unmarshal(resp.Result, &result)
+		return result, nil
+	}
+
+	path := cfg.LedgerPath()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return protocol.LedgerVerifyResult{Path: path, Healthy: true}, nil
+	}
+	l, err := ledger.Open(path)
+	if err != nil {
+		return protocol.LedgerVerifyResult{}, fmt.Errorf("failed to open ledger: %w", err)
+	}
+	defer l.Close()
+	return daemon.CheckLedger(l, repair)
+}
+
+// printLedgerReport prints the size, span and problems of a ledger.
+func printLedgerReport(result protocol.LedgerVerifyResult) {
+	fmt.Printf("Ledger: %s\n", result.Path)
+	fmt.Printf("  Size:     %s, %d lines, %d events\n", formatBytes(result.Size), result.Lines, result.Events)
+	if result.First > 0 {
+		fmt.Printf("  Span:     %s to %s\n",
+			time.Unix(result.First, 0).Format("2006-01-02 15:04"), time.Unix(result.Last, 0).Format("2006-01-02 15:04"))
+	}
+	if result.Healthy {
+		fmt.Println("  Problems: none")
+		return
+	}
+	fmt.Printf("  Problems: %s\n", summarizeLedgerProblems(result))
+
+	fmt.Println()
+	table := NewTable("LINE", "PROBLEM", "DETAIL")
+	for _, p := range result.Problems {
+		table.AddRow(p.Line, p.Kind, truncate(p.Detail, 70))
+	}
+	table.Print()
+}
+
+// summarizeLedgerProblems counts problems by kind, as "2 corrupt, 1 duplicate".
+func summarizeLedgerProblems(result protocol.LedgerVerifyResult) string {
+	counts := make(map[string]int)
+	for _, p := range result.Problems {
+		counts[p.Kind]++
+	}
+	kinds := make([]string, 0, len(counts))
+	for kind := range counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	parts := make([]string, len(kinds))
+	for i, kind := range kinds {
+		parts[i] = fmt.Sprintf("%d %s", counts[kind], kind)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func countLedgerProblems(result protocol.LedgerVerifyResult, kind string) int {
+	n := 0
+	for _, p := range result.Problems {
+		if p.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// formatBytes formats a size in bytes as B, KB or MB.
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
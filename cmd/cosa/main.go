@@ -4,8 +4,14 @@ package main
 import (
 	"bufio"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -18,22 +24,38 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"cosa/internal/claude"
 	"cosa/internal/config"
 	"cosa/internal/daemon"
+	"cosa/internal/display"
+	"cosa/internal/git"
 	"cosa/internal/job"
+	"cosa/internal/ledger"
+	"cosa/internal/logging"
 	"cosa/internal/mcp"
 	"cosa/internal/protocol"
 	"cosa/internal/tui"
+	"cosa/internal/tui/theme"
 )
 
 var cfg *config.Config
 
+// socketOverride is set by --socket to talk to a daemon on a non-default
+// socket, such as one forwarded by cosa tunnel.
+var socketOverride string
+
+// profileOverride is set by --profile to use a profile other than the
+// default one for this command.
+var profileOverride string
+
+// cfgErr holds the error loading the selected profile. It is reported once
+// the command is known, so the profile commands can still fix the selection.
+var cfgErr error
+
 func main() {
-	var err error
-	cfg, err = config.Load("")
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
-		os.Exit(1)
+	cfg, cfgErr = config.LoadProfile(config.ActiveProfile())
+	if cfgErr != nil {
+		cfg = config.DefaultConfig()
 	}
 
 	rootCmd := &cobra.Command{
@@ -42,8 +64,33 @@ func main() {
 		Long: `Cosa Nostra is a mafia-themed multi-agent development orchestration system.
 It manages Claude Code workers in isolated git worktrees with a hierarchical
 role system and real-time TUI.`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if profileOverride != "" {
+				if loaded, err := config.LoadProfile(profileOverride); err != nil {
+					cfgErr = err
+				} else {
+					cfg, cfgErr = loaded, nil
+				}
+				// Daemons started by this command use the same profile
+				os.Setenv(config.ProfileEnv, profileOverride)
+			}
+			if cfgErr != nil && !isProfileCommand(cmd) {
+				fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", cfgErr)
+				os.Exit(1)
+			}
+			if socketOverride != "" {
+				cfg.SocketPath = socketOverride
+			}
+			resolveCallTimeout(cmd)
+			return resolveOutputFormat()
+		},
 	}
 
+	addOutputFlags(rootCmd)
+	addTimeoutFlag(rootCmd)
+	rootCmd.PersistentFlags().StringVar(&socketOverride, "socket", "", "Daemon socket path (overrides socket_path)")
+	rootCmd.PersistentFlags().StringVar(&profileOverride, "profile", "", "Config profile to use (see 'cosa profile list')")
+
 	rootCmd.AddCommand(
 		startCmd(),
 		stopCmd(),
@@ -52,7 +99,9 @@ role system and real-time TUI.`,
 		territoryCmd(),
 		workerCmd(),
 		jobCmd(),
+		queueCmd(),
 		templateCmd(),
+		presetsCmd(),
 		reviewCmd(),
 		operationCmd(),
 		orderCmd(),
@@ -61,6 +110,19 @@ role system and real-time TUI.`,
 		tuiCmd(),
 		chatCmd(),
 		mcpServeCmd(),
+		tunnelCmd(),
+		notifyCmd(),
+		triageCmd(),
+		statsCmd(),
+		changelogCmd(),
+		profileCmd(),
+		ledgerCmd(),
+		costsCmd(),
+		backupCmd(),
+		dataKeyCmd(),
+		doctorCmd(),
+		cleanCmd(),
+		debugCmd(),
 	)
 
 	if err := rootCmd.Execute(); err != nil {
@@ -94,17 +156,35 @@ func startCmd() *cobra.Command {
 }
 
 func stopCmd() *cobra.Command {
-	return &cobra.Command{
+	var drain bool
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
 		Use:   "stop",
 		Short: "Stop the Cosa daemon",
+		Long: `Stop the Cosa daemon. Running jobs are stopped with it.
+
+With --drain the daemon stops starting jobs and shuts down once running
+jobs, reviews and merges have finished, showing progress as they do. After
+--timeout whatever is still running is stopped anyway; a timeout of 0 waits
+as long as it takes. Interrupting 'cosa stop --drain' leaves the drain
+running in the daemon.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			if timeout < 0 {
+				return fmt.Errorf("timeout must not be negative")
+			}
+
+			client, err := connectDaemon()
 			if err != nil {
 				fmt.Println("Daemon is not running")
 				return nil
 			}
 			defer client.Close()
 
+			if drain {
+				return drainDaemon(client, timeout)
+			}
+
 			if err := client.Shutdown(); err != nil {
 				return fmt.Errorf("failed to stop daemon: %w", err)
 			}
@@ -113,26 +193,69 @@ func stopCmd() *cobra.Command {
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&drain, "drain", false, "Let running jobs, reviews and merges finish first")
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Minute, "With --drain, stop anyway after this long (0 waits indefinitely)")
+
+	return cmd
 }
 
 func statusCmd() *cobra.Command {
-	return &cobra.Command{
+	var watch time.Duration
+
+	cmd := &cobra.Command{
 		Use:     "status",
 		Short:   "Show daemon status",
 		Aliases: []string{"s"},
+		Long: `Show daemon status.
+
+--watch keeps a live view of workers, the queue, running jobs and spend
+on screen, refreshed as the daemon reports changes. It redraws at most
+every 2s, or at the interval given (-w 5s).`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			watching := cmd.Flags().Changed("watch")
+			if len(args) > 0 {
+				// -w 2s leaves the interval as an argument
+				if !watching {
+					return fmt.Errorf("unexpected argument: %s", args[0])
+				}
+				interval, err := time.ParseDuration(args[0])
+				if err != nil {
+					return fmt.Errorf("invalid --watch interval: %s", args[0])
+				}
+				watch = interval
+			}
+			if watching && watch <= 0 {
+				return fmt.Errorf("invalid --watch interval: %s", watch)
+			}
+
+			client, err := connectDaemon()
 			if err != nil {
+				if watching {
+					return fmt.Errorf("daemon not running")
+				}
+				if structuredOutput() {
+					return printStructured(map[string]bool{"running": false})
+				}
 				fmt.Println("Daemon is not running")
 				return nil
 			}
 			defer client.Close()
 
+			if watching {
+				return watchStatus(client, watch)
+			}
+
 			status, err := client.Status()
 			if err != nil {
 				return fmt.Errorf("failed to get status: %w", err)
 			}
 
+			if structuredOutput() {
+				return printStructured(status)
+			}
+
 			fmt.Printf("Cosa Daemon v%s\n", status.Version)
 			fmt.Printf("Status:      running\n")
 			fmt.Printf("Uptime:      %s\n", formatDuration(time.Duration(status.Uptime)*time.Second))
@@ -144,18 +267,42 @@ func statusCmd() *cobra.Command {
 			if status.TotalCost != "" && status.TotalCost != "$0.00" {
 				fmt.Printf("Total Cost:  %s (%d tokens)\n", status.TotalCost, status.TotalTokens)
 			}
+			if b := status.Budget; b != nil {
+				fmt.Printf("Budget:      %s\n", formatBudget(b))
+			}
+			if status.MergeQueue > 0 {
+				fmt.Printf("Merge Queue: %d (see 'cosa job merge-queue')\n", status.MergeQueue)
+			}
+			switch status.Queue {
+			case "paused":
+				fmt.Printf("Queue:       paused; no new jobs start (see 'cosa queue status')\n")
+			case "draining":
+				fmt.Printf("Queue:       draining; no new jobs start or are accepted (see 'cosa queue status')\n")
+			}
+			if status.Draining {
+				fmt.Printf("Draining:    no new jobs start; stops when running work finishes\n")
+			}
 
 			return nil
 		},
 	}
+
+	cmd.Flags().DurationVarP(&watch, "watch", "w", 0, "Keep a live view, redrawn at most this often")
+	cmd.Flags().Lookup("watch").NoOptDefVal = defaultWatchInterval.String()
+
+	return cmd
 }
 
 func versionCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "version",
 		Short: "Show version information",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if structuredOutput() {
+				return printStructured(map[string]string{"version": config.Version})
+			}
 			fmt.Printf("cosa version %s\n", config.Version)
+			return nil
 		},
 	}
 }
@@ -173,53 +320,22 @@ func territoryCmd() *cobra.Command {
 		territoryStatusCmd(),
 		territoryListCmd(),
 		territoryAddCmd(),
+		territoryRemoveCmd(),
+		territoryArchiveCmd(),
+		territoryRestoreCmd(),
 		territoryDevBranchCmd(),
+		territoryIndexCmd(),
 	)
 
 	return cmd
 }
 
-func territoryInitCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "init [path]",
-		Short: "Initialize a new territory",
-		Args:  cobra.MaximumNArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := connectOrStartDaemon()
-			if err != nil {
-				return err
-			}
-			defer client.Close()
-
-			path := ""
-			if len(args) > 0 {
-				path = args[0]
-			}
-
-			resp, err := client.Call(protocol.MethodTerritoryInit, map[string]string{"path": path})
-			if err != nil {
-				return err
-			}
-
-			if resp.Error != nil {
-				return fmt.Errorf("%s", resp.Error.Message)
-			}
-
-			var result map[string]string
-			json.Unmarshal(resp.Result, &result)
-			fmt.Printf("Territory initialized at %s\n", result["path"])
-
-			return nil
-		},
-	}
-}
-
 func territoryStatusCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "status",
 		Short: "Show territory status",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running")
 			}
@@ -237,6 +353,10 @@ func territoryStatusCmd() *cobra.Command {
 			var result map[string]interface{}
 			json.Unmarshal(resp.Result, &result)
 
+			if structuredOutput() {
+				return printStructured(result)
+			}
+
 			fmt.Printf("Path:        %s\n", result["path"])
 			fmt.Printf("Repo Root:   %s\n", result["repo_root"])
 			fmt.Printf("Base Branch: %s\n", result["base_branch"])
@@ -246,6 +366,21 @@ func territoryStatusCmd() *cobra.Command {
 			if mergeTarget, ok := result["merge_target_branch"].(string); ok && mergeTarget != "" {
 				fmt.Printf("Merge Target: %s\n", mergeTarget)
 			}
+			if model, ok := result["model"].(string); ok && model != "" {
+				fmt.Printf("Model:       %s\n", model)
+			}
+			if maxTurns, ok := result["max_turns"].(float64); ok && maxTurns > 0 {
+				fmt.Printf("Max Turns:   %d\n", int(maxTurns))
+			}
+			if test, ok := result["test_command"].(string); ok && test != "" {
+				fmt.Printf("Test Gate:   %s\n", test)
+			}
+			if build, ok := result["build_command"].(string); ok && build != "" {
+				fmt.Printf("Build Gate:  %s\n", build)
+			}
+			if configFile, ok := result["config_file"].(string); ok {
+				fmt.Printf("Overrides:   %s\n", configFile)
+			}
 
 			return nil
 		},
@@ -258,7 +393,7 @@ func territoryListCmd() *cobra.Command {
 		Short:   "List all registered territories",
 		Aliases: []string{"ls"},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running")
 			}
@@ -283,12 +418,16 @@ func territoryListCmd() *cobra.Command {
 			}
 			json.Unmarshal(resp.Result, &result)
 
+			if structuredOutput() {
+				return printStructured(result.Territories)
+			}
+
 			if len(result.Territories) == 0 {
 				fmt.Println("No registered territories")
 				return nil
 			}
 
-			fmt.Printf("%-50s %-12s %s\n", "PATH", "BRANCH", "STATUS")
+			table := NewTable("PATH", "BRANCH", "STATUS")
 			for _, t := range result.Territories {
 				status := ""
 				if t.Active {
@@ -298,8 +437,9 @@ func territoryListCmd() *cobra.Command {
 				if len(path) > 48 {
 					path = "..." + path[len(path)-45:]
 				}
-				fmt.Printf("%-50s %-12s %s\n", path, t.BaseBranch, status)
+				table.AddRow(path, t.BaseBranch, status)
 			}
+			table.Print()
 
 			return nil
 		},
@@ -336,6 +476,60 @@ func territoryAddCmd() *cobra.Command {
 	}
 }
 
+func territoryIndexCmd() *cobra.Command {
+	var show bool
+
+	cmd := &cobra.Command{
+		Use:   "index",
+		Short: "Rebuild the repository index given to workers",
+		Long: `Rebuild the repository index now.
+
+The daemon keeps an index of the territory's directories, modules and
+exported symbols, rebuilding it while workers are idle whenever HEAD has
+moved, and includes a compact map of it in job prompts so workers need not
+explore the tree to orient themselves. This rebuilds it right away.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			resp, err := client.Call(protocol.MethodTerritoryIndex, nil)
+			if err != nil {
+				return err
+			}
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var result protocol.TerritoryIndexResult
+			json.Unmarshal(resp.Result, &result)
+
+			if structuredOutput() {
+				return printStructured(result)
+			}
+
+			fmt.Printf("Indexed %d files (%s) in %d directories at %s in %s\n",
+				result.Files, formatBytes(result.Bytes), result.Dirs, display.ShortID(result.Commit), result.Duration)
+			fmt.Printf("Saved to %s\n", result.Path)
+			if show {
+				if result.Map == "" {
+					fmt.Println("\nNo map is given to workers (index.prompt_bytes is 0)")
+				} else {
+					fmt.Printf("\n%s\n", result.Map)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&show, "show", false, "Print the map included in job prompts")
+
+	return cmd
+}
+
 func territoryDevBranchCmd() *cobra.Command {
 	var clear bool
 
@@ -353,7 +547,7 @@ instead of the main branch (master/main). This is useful for:
 Use --clear to remove the dev branch configuration and merge directly to main.`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running")
 			}
@@ -408,11 +602,21 @@ func workerCmd() *cobra.Command {
 
 	cmd.AddCommand(
 		workerAddCmd(),
+		workerCloneCmd(),
 		workerListCmd(),
 		workerRemoveCmd(),
 		workerMessageCmd(),
 		workerHandoffCmd(),
+		workerTakeoverCmd(),
+		workerLabelCmd(),
 		workerDetailCmd(),
+		workerTranscriptCmd(),
+		workerExecCmd(),
+		workerShellCmd(),
+		workerOffCmd(),
+		workerOnCmd(),
+		workerLimitCmd(),
+		workerUnquarantineCmd(),
 	)
 
 	return cmd
@@ -420,21 +624,28 @@ func workerCmd() *cobra.Command {
 
 func workerAddCmd() *cobra.Command {
 	var role string
+	var labels []string
 
 	cmd := &cobra.Command{
 		Use:   "add <name>",
 		Short: "Add a new worker",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			workerLabels, err := parseLabels(labels)
+			if err != nil {
+				return err
+			}
+
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running")
 			}
 			defer client.Close()
 
 			params := protocol.WorkerAddParams{
-				Name: args[0],
-				Role: role,
+				Name:   args[0],
+				Role:   role,
+				Labels: workerLabels,
 			}
 
 			resp, err := client.Call(protocol.MethodWorkerAdd, params)
@@ -454,12 +665,68 @@ func workerAddCmd() *cobra.Command {
 			fmt.Printf("  Role:     %s\n", info.Role)
 			fmt.Printf("  Status:   %s\n", info.Status)
 			fmt.Printf("  Worktree: %s\n", info.Worktree)
+			if len(info.Labels) > 0 {
+				fmt.Printf("  Labels:   %s\n", formatLabels(info.Labels))
+			}
 
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVarP(&role, "role", "r", "soldato", "Worker role (soldato, capo, consigliere)")
+	cmd.Flags().StringArrayVarP(&labels, "label", "l", nil, "Add a label (key=value, repeatable); jobs with matching labels prefer this worker")
+
+	return cmd
+}
+
+func workerLabelCmd() *cobra.Command {
+	var remove []string
+
+	cmd := &cobra.Command{
+		Use:   "label <name> [key=value...]",
+		Short: "Set or remove a worker's labels",
+		Long: `Set or remove a worker's labels. The scheduler prefers workers whose
+labels match a job's, so a worker labelled area=auth is given jobs added with
+--label area=auth when it is free.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			set, err := parseLabels(args[1:])
+			if err != nil {
+				return err
+			}
+
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			resp, err := client.Call(protocol.MethodWorkerLabel, protocol.WorkerLabelParams{
+				Name:   args[0],
+				Set:    set,
+				Remove: remove,
+			})
+			if err != nil {
+				return err
+			}
+
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var info protocol.WorkerInfo
+			json.Unmarshal(resp.Result, &info)
+
+			if structuredOutput() {
+				return printStructured(info)
+			}
+
+			fmt.Printf("Worker '%s' labels: %s\n", info.Name, formatLabels(info.Labels))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&remove, "remove", nil, "Remove a label by key (repeatable)")
 
 	return cmd
 }
@@ -470,7 +737,7 @@ func workerListCmd() *cobra.Command {
 		Short: "List all workers",
 		Aliases: []string{"ls"},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running")
 			}
@@ -488,19 +755,34 @@ func workerListCmd() *cobra.Command {
 			var workers []protocol.WorkerInfo
 			json.Unmarshal(resp.Result, &workers)
 
+			if structuredOutput() {
+				return printStructured(workers)
+			}
+
 			if len(workers) == 0 {
 				fmt.Println("No workers")
 				return nil
 			}
 
-			fmt.Printf("%-15s %-12s %-10s %s\n", "NAME", "ROLE", "STATUS", "CURRENT JOB")
+			table := NewTable("NAME", "ROLE", "STATUS", "CURRENT JOB", "LABELS")
 			for _, w := range workers {
 				job := "-"
 				if w.CurrentJob != "" {
-					job = w.CurrentJob[:8]
+					job = display.ShortID(w.CurrentJob)
+				}
+				status := w.Status
+				if w.Slots > 1 {
+					status += fmt.Sprintf(" %d/%d", w.BusySlots, w.Slots)
+					if w.BusySlots > 1 {
+						job += fmt.Sprintf(" +%d", w.BusySlots-1)
+					}
 				}
-				fmt.Printf("%-15s %-12s %-10s %s\n", w.Name, w.Role, w.Status, job)
+				if w.Quarantined {
+					status += " (quarantined)"
+				}
+				table.AddRow(w.Name, w.Role, status, job, formatLabels(w.Labels))
 			}
+			table.Print()
 
 			return nil
 		},
@@ -508,7 +790,7 @@ func workerListCmd() *cobra.Command {
 }
 
 func workerRemoveCmd() *cobra.Command {
-	var force bool
+	var force, interview bool
 
 	cmd := &cobra.Command{
 		Use:   "remove <name>",
@@ -516,16 +798,21 @@ func workerRemoveCmd() *cobra.Command {
 		Aliases: []string{"rm"},
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running")
 			}
 			defer client.Close()
 
-			resp, err := client.Call(protocol.MethodWorkerRemove, map[string]interface{}{
+			params := map[string]interface{}{
 				"name":  args[0],
 				"force": force,
-			})
+			}
+			if cmd.Flags().Changed("interview") {
+				params["interview"] = interview
+			}
+
+			resp, err := client.Call(protocol.MethodWorkerRemove, params)
 			if err != nil {
 				return err
 			}
@@ -534,12 +821,19 @@ func workerRemoveCmd() *cobra.Command {
 				return fmt.Errorf("%s", resp.Error.Message)
 			}
 
+			var result map[string]string
+			json.Unmarshal(resp.Result, &result)
+
 			fmt.Printf("Worker '%s' removed\n", args[0])
+			if result["interview"] != "" {
+				fmt.Println("Exit interview started; its lessons are logged as worker.interviewed (see 'cosa logs')")
+			}
 			return nil
 		},
 	}
 
 	cmd.Flags().BoolVarP(&force, "force", "f", false, "Force removal even if working")
+	cmd.Flags().BoolVar(&interview, "interview", false, "Summarize the worker's learnings into the territory knowledge base (default: workers.exit_interview)")
 
 	return cmd
 }
@@ -550,7 +844,7 @@ func workerMessageCmd() *cobra.Command {
 		Short: "Send a message to a worker",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running")
 			}
@@ -580,7 +874,7 @@ func workerHandoffCmd() *cobra.Command {
 		Short: "Generate handoff summary for a worker",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running")
 			}
@@ -600,37 +894,106 @@ func workerHandoffCmd() *cobra.Command {
 			var summary protocol.HandoffSummary
 			json.Unmarshal(resp.Result, &summary)
 
-			fmt.Printf("Handoff Summary for %s\n", summary.WorkerName)
-			fmt.Printf("  Status:  %s\n", summary.Status)
-			if summary.JobID != "" {
-				fmt.Printf("  Job:     %s\n", summary.JobID[:8])
+			if structuredOutput() {
+				return printStructured(summary)
 			}
-			fmt.Printf("  Created: %s\n", time.Unix(summary.CreatedAt, 0).Format("2006-01-02 15:04:05"))
 
-			if len(summary.Decisions) > 0 {
-				fmt.Println("\nKey Decisions:")
-				for _, d := range summary.Decisions {
-					fmt.Printf("  - %s\n", d)
-				}
+			printHandoffSummary(summary)
+			return nil
+		},
+	}
+}
+
+// printHandoffSummary shows where a worker has got to.
+func printHandoffSummary(summary protocol.HandoffSummary) {
+	fmt.Printf("Handoff Summary for %s\n", summary.WorkerName)
+	fmt.Printf("  Status:  %s\n", summary.Status)
+	if summary.JobID != "" {
+		fmt.Printf("  Job:     %s\n", display.ShortID(summary.JobID))
+	}
+	fmt.Printf("  Created: %s\n", time.Unix(summary.CreatedAt, 0).Format("2006-01-02 15:04:05"))
+
+	if len(summary.Decisions) > 0 {
+		fmt.Println("\nKey Decisions:")
+		for _, d := range summary.Decisions {
+			fmt.Printf("  - %s\n", d)
+		}
+	}
+
+	if len(summary.FilesTouched) > 0 {
+		fmt.Println("\nFiles Touched:")
+		for _, f := range summary.FilesTouched {
+			fmt.Printf("  - %s\n", f)
+		}
+	}
+
+	if summary.Summary != "" {
+		fmt.Println("\nChanges:")
+		for _, line := range strings.Split(strings.TrimRight(summary.Summary, "\n"), "\n") {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+
+	if len(summary.OpenQuestions) > 0 {
+		fmt.Println("\nOpen Questions:")
+		for _, q := range summary.OpenQuestions {
+			fmt.Printf("  - %s\n", q)
+		}
+	}
+}
+
+func workerTakeoverCmd() *cobra.Command {
+	var grace time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "takeover <from> <to>",
+		Short: "Move a worker's running job to another worker",
+		Long: `Move the job <from> is running to the idle worker <to>.
+
+<from> is stopped, and its handoff summary (see 'cosa worker handoff') is
+given to <to> along with the job. <to> carries on in the same job worktree,
+uncommitted changes included. The transfer is recorded in the ledger.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
 			}
+			defer client.Close()
 
-			if len(summary.FilesTouched) > 0 {
-				fmt.Println("\nFiles Touched:")
-				for _, f := range summary.FilesTouched {
-					fmt.Printf("  - %s\n", f)
-				}
+			resp, err := client.Call(protocol.MethodWorkerTakeover, protocol.WorkerTakeoverParams{
+				From:    args[0],
+				To:      args[1],
+				GraceMS: int(grace.Milliseconds()),
+			})
+			if err != nil {
+				return err
 			}
 
-			if len(summary.OpenQuestions) > 0 {
-				fmt.Println("\nOpen Questions:")
-				for _, q := range summary.OpenQuestions {
-					fmt.Printf("  - %s\n", q)
-				}
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
 			}
 
+			var result protocol.WorkerTakeoverResult
+			json.Unmarshal(resp.Result, &result)
+
+			if structuredOutput() {
+				return printStructured(result)
+			}
+
+			fmt.Printf("%s took over job %s from %s: %s\n", result.To, display.ShortID(result.JobID), result.From, result.Description)
+			if result.Worktree != "" {
+				fmt.Printf("  Worktree: %s\n", result.Worktree)
+			}
+			fmt.Println()
+			printHandoffSummary(result.Handoff)
 			return nil
 		},
 	}
+
+	cmd.Flags().DurationVar(&grace, "grace", 10*time.Second, "Time to let Claude exit after interrupt before killing it")
+
+	return cmd
 }
 
 func workerDetailCmd() *cobra.Command {
@@ -640,7 +1003,7 @@ func workerDetailCmd() *cobra.Command {
 		Aliases: []string{"info", "show"},
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running")
 			}
@@ -660,12 +1023,16 @@ func workerDetailCmd() *cobra.Command {
 			var info protocol.WorkerDetailInfo
 			json.Unmarshal(resp.Result, &info)
 
+			if structuredOutput() {
+				return printStructured(info)
+			}
+
 			fmt.Printf("Worker: %s\n", info.Name)
 			fmt.Printf("  ID:            %s\n", info.ID)
 			fmt.Printf("  Role:          %s\n", info.Role)
 			fmt.Printf("  Status:        %s\n", info.Status)
 			if info.CurrentJob != "" {
-				fmt.Printf("  Current Job:   %s\n", info.CurrentJob[:8])
+				fmt.Printf("  Current Job:   %s\n", display.ShortID(info.CurrentJob))
 			}
 			if info.Worktree != "" {
 				fmt.Printf("  Worktree:      %s\n", info.Worktree)
@@ -673,56 +1040,378 @@ func workerDetailCmd() *cobra.Command {
 			if info.Branch != "" {
 				fmt.Printf("  Branch:        %s\n", info.Branch)
 			}
+			if len(info.Labels) > 0 {
+				fmt.Printf("  Labels:        %s\n", formatLabels(info.Labels))
+			}
+			if info.GitAuthor != "" {
+				fmt.Printf("  Commits As:    %s\n", info.GitAuthor)
+			}
+			if info.GitSigning != "" {
+				fmt.Printf("  Signing:       %s\n", info.GitSigning)
+			}
+			if info.SessionID != "" {
+				fmt.Printf("  Session:       %s (%d tokens)\n", display.ShortID(info.SessionID), info.SessionTokens)
+			}
+			if info.PreviousSessionID != "" {
+				fmt.Printf("  Rolled Over:   from %s\n", display.ShortID(info.PreviousSessionID))
+			}
 			fmt.Printf("  Jobs Completed: %d\n", info.JobsCompleted)
 			fmt.Printf("  Jobs Failed:    %d\n", info.JobsFailed)
+			if info.FailureStreak > 0 && info.Quarantine == nil {
+				fmt.Printf("  Failing:       last %d jobs in a row\n", info.FailureStreak)
+			}
+			if q := info.Quarantine; q != nil {
+				fmt.Printf("  Quarantined:   since %s, after %d failed jobs in a row:\n", time.Unix(q.Since, 0).Format("2006-01-02 15:04:05"), len(q.Failures))
+				for _, failure := range q.Failures {
+					fmt.Printf("    - %s\n", failure)
+				}
+				fmt.Printf("  (lift with 'cosa worker unquarantine %s')\n", info.Name)
+			}
 			if info.TotalCost != "" && info.TotalCost != "$0.00" {
 				fmt.Printf("  Total Cost:    %s (%d tokens)\n", info.TotalCost, info.TotalTokens)
 			}
 			fmt.Printf("  Created:       %s\n", time.Unix(info.CreatedAt, 0).Format("2006-01-02 15:04:05"))
+			if info.LastProgress > 0 {
+				fmt.Printf("  Last Progress: %s ago (%s)\n", formatDuration(time.Since(time.Unix(info.LastProgress, 0))), info.Progress)
+			}
+			if info.LastActivity > 0 {
+				fmt.Printf("  Last Activity: %s ago\n", formatDuration(time.Since(time.Unix(info.LastActivity, 0))))
+			}
+			if len(info.Slots) > 0 {
+				fmt.Printf("\nSlots:\n")
+				for _, slot := range info.Slots {
+					printSlot(slot)
+				}
+			}
 
 			return nil
 		},
 	}
 }
 
-// Job commands
+// printSlot prints one of the jobs a worker runs at once.
+func printSlot(slot protocol.SlotDetail) {
+	if slot.Status != "busy" {
+		fmt.Printf("  %d: free (%s run, %d tokens)\n", slot.Index+1, plural(slot.JobsRun, "job"), slot.Tokens)
+		return
+	}
 
-func jobCmd() *cobra.Command {
-	cmd := &cobra.Command{
-		Use:     "job",
-		Short:   "Manage jobs",
-		Aliases: []string{"j", "jobs"},
+	fmt.Printf("  %d: %s %s\n", slot.Index+1, display.ShortID(slot.JobID), slot.JobDesc)
+	if slot.SessionID != "" {
+		fmt.Printf("     Session: %s\n", display.ShortID(slot.SessionID))
+	}
+	if slot.Tool != "" {
+		fmt.Printf("     Tool:    %s\n", slot.Tool)
 	}
+	if slot.Progress != "" {
+		fmt.Printf("     Latest:  %s\n", truncate(slot.Progress, 70))
+	}
+	cost := valueOrDefault(slot.Cost, "$0.00")
+	fmt.Printf("     Cost:    %s (%d tokens in slot, %s run)\n", cost, slot.Tokens, plural(slot.JobsRun, "job"))
+}
 
-	cmd.AddCommand(
-		jobAddCmd(),
-		jobListCmd(),
-		jobCancelCmd(),
-	)
+func workerTranscriptCmd() *cobra.Command {
+	var jobID string
+	var follow bool
+
+	cmd := &cobra.Command{
+		Use:   "transcript <name>",
+		Short: "Show a worker's session transcript",
+		Long: `Show the recorded Claude session for a worker's job: assistant messages,
+tool calls and their results. Defaults to the worker's current job, or its
+most recent job if it is idle.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			fetch := func(offset int) (*protocol.WorkerTranscriptResult, error) {
+				resp, err := client.Call(protocol.MethodWorkerTranscript, protocol.WorkerTranscriptParams{
+					Name:   args[0],
+					JobID:  jobID,
+					Offset: offset,
+				})
+				if err != nil {
+					return nil, err
+				}
+				if resp.Error != nil {
+					return nil, fmt.Errorf("%s", resp.Error.Message)
+				}
+				var result protocol.WorkerTranscriptResult
+				json.Unmarshal(resp.Result, &result)
+				return &result, nil
+			}
+
+			result, err := fetch(0)
+			if err != nil {
+				return err
+			}
+
+			if !follow {
+				if structuredOutput() {
+					return printStructured(result)
+				}
+				if result.JobID == "" {
+					fmt.Printf("No transcript recorded for %s\n", result.Worker)
+					return nil
+				}
+				fmt.Printf("Transcript for %s, job %s\n\n", result.Worker, display.ShortID(result.JobID))
+				for _, e := range result.Entries {
+					printTranscriptEntry(e)
+				}
+				return nil
+			}
+
+			sigCh := make(chan os.Signal, 1)
+			signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+
+			// Pin the job once known so a new job doesn't switch streams mid-follow
+			currentJob := ""
+			for {
+				if result.JobID != "" && currentJob == "" {
+					currentJob = result.JobID
+					jobID = currentJob
+					if !structuredOutput() {
+						fmt.Printf("Following %s, job %s (Ctrl+C to stop)\n\n", result.Worker, display.ShortID(currentJob))
+					}
+				}
+
+				for _, e := range result.Entries {
+					if structuredOutput() {
+						if err := printStreamEvent(e); err != nil {
+							return err
+						}
+					} else {
+						printTranscriptEntry(e)
+					}
+				}
+
+				if currentJob != "" && !result.Active {
+					return nil
+				}
+
+				select {
+				case <-sigCh:
+					return nil
+				case <-time.After(time.Second):
+				}
+
+				next := result.Next
+				if currentJob == "" {
+					next = 0
+				}
+				result, err = fetch(next)
+				if err != nil {
+					return err
+				}
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&jobID, "job", "", "Job ID (default: current or most recent job)")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Follow the transcript until the job finishes")
+
+	return cmd
+}
+
+func printTranscriptEntry(e protocol.TranscriptEntry) {
+	ts := time.Unix(e.Time, 0).Format("15:04:05")
+
+	switch e.Type {
+	case "assistant_text":
+		fmt.Printf("[%s] %s\n", ts, e.Message)
+	case "tool_use":
+		input := truncate(e.Input, 200)
+		fmt.Printf("[%s] -> %s %s\n", ts, e.Tool, input)
+	case "tool_result":
+		output := truncate(e.Output, 500)
+		if e.Error != "" {
+			fmt.Printf("[%s] <- %s error: %s\n", ts, e.Tool, e.Error)
+		} else if output != "" {
+			fmt.Printf("[%s] <- %s\n%s\n", ts, e.Tool, output)
+		} else {
+			fmt.Printf("[%s] <- %s\n", ts, e.Tool)
+		}
+	case "result":
+		if e.Error != "" {
+			fmt.Printf("[%s] Session failed: %s\n", ts, e.Error)
+		} else {
+			fmt.Printf("[%s] Session finished\n", ts)
+		}
+		if e.Message != "" {
+			fmt.Println(e.Message)
+		}
+	case "error":
+		fmt.Printf("[%s] Error: %s\n", ts, e.Error)
+	case "init":
+		fmt.Printf("[%s] Session started\n", ts)
+	default:
+		if e.Message != "" {
+			fmt.Printf("[%s] %s: %s\n", ts, e.Type, e.Message)
+		}
+	}
+}
+
+// Job commands
+
+func jobCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "job",
+		Short:   "Manage jobs",
+		Aliases: []string{"j", "jobs"},
+	}
+
+	cmd.AddCommand(
+		jobAddCmd(),
+		jobListCmd(),
+		jobStatusCmd(),
+		jobFollowCmd(),
+		jobCancelCmd(),
+		jobConflictsCmd(),
+		jobMergeCmd(),
+		jobMergeQueueCmd(),
+		jobShellCmd(),
+		jobBatchCmd(),
+		jobImportCmd(),
+		jobImportStatusCmd(),
+		jobWatchCmd(true),
+		jobWatchCmd(false),
+		jobUnstickCmd(),
+		jobApproveCmd(),
+		jobRerunCmd(),
+	)
 
 	return cmd
 }
 
 func jobAddCmd() *cobra.Command {
 	var worker string
+	var stickyWorker string
 	var priority int
+	var preset string
+	var labels []string
+	var model string
+	var skipReview bool
+	var file string
+	var title string
+	var timeout time.Duration
+	var plan bool
+	var decompose, yes bool
 
 	cmd := &cobra.Command{
-		Use:     "add <description>",
+		Use:     "add [description]",
 		Aliases: []string{"a"},
 		Short:   "Add a new job",
-		Args:    cobra.ExactArgs(1),
+		Long: `Add a new job.
+
+Long specifications can be read from a file with --file, or from stdin with
+'-' as the description or file. The first line becomes the job title unless
+--title is given; the full text is stored on the job and given to the worker.
+
+With --sticky-worker the job waits for that worker, however long it is busy
+or away, and resumes its Claude session so a series of related jobs keeps
+its context. 'cosa job unstick' hands the job back to the scheduler.
+
+With --plan the worker first plans the job without editing anything: the
+files it would touch, its approach and the scope. The job then waits in
+triage until the plan is approved with 'cosa job approve', and runs again
+to carry it out.
+
+With --decompose the underboss breaks the job into subtasks instead, with
+the dependencies between them. The breakdown is shown for approval, then
+created as an operation whose jobs wait for the ones they depend on.
+
+Examples:
+  cosa job add "Fix the login redirect"
+  cosa job add --file spec.md --title "Rework the session store"
+  cat spec.md | cosa job add -
+  cosa job add --sticky-worker paulie "Now add tests for the redirect"
+  cosa job add --plan "Split the scheduler out of the server"
+  cosa job add --decompose "Implement OAuth login"`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			var text string
+			switch {
+			case file != "" && len(args) > 0:
+				return fmt.Errorf("use either a description or --file, not both")
+			case file != "":
+				data, err := readJobText(file)
+				if err != nil {
+					return err
+				}
+				text = data
+			case len(args) == 0:
+				return fmt.Errorf("a description or --file is required")
+			case args[0] == "-":
+				data, err := readJobText("-")
+				if err != nil {
+					return err
+				}
+				text = data
+			default:
+				text = args[0]
+			}
+
+			description, body := splitJobText(text, title)
+
+			if timeout < 0 {
+				return fmt.Errorf("timeout must not be negative")
+			}
+
+			if decompose {
+				if worker != "" || stickyWorker != "" || preset != "" || plan {
+					return fmt.Errorf("--decompose cannot be combined with --worker, --sticky-worker, --preset or --plan")
+				}
+				params := protocol.JobDecomposeParams{
+					Description: description,
+					Body:        body,
+					Priority:    priority,
+					Requester:   cfg.ClientIdentity(),
+					Model:       model,
+					SkipReview:  skipReview,
+					Timeout:     int(timeout.Seconds()),
+				}
+				if len(labels) > 0 {
+					var err error
+					if params.Labels, err = parseLabels(labels); err != nil {
+						return err
+					}
+				}
+				return addDecomposedJob(params, yes)
+			}
+
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running")
 			}
 			defer client.Close()
 
 			params := protocol.JobAddParams{
-				Description: args[0],
+				Description: description,
+				Body:        body,
 				Worker:      worker,
-				Priority:    priority,
+				Preset:      preset,
+				Model:       model,
+				SkipReview:  skipReview,
+				Timeout:     int(timeout.Seconds()),
+				Requester:   cfg.ClientIdentity(),
+
+				StickyWorker: stickyWorker,
+				PlanFirst:    plan,
+			}
+
+			// Only send an explicit priority so presets can supply their own
+			if cmd.Flags().Changed("priority") || preset == "" {
+				params.Priority = priority
+			}
+
+			if len(labels) > 0 {
+				if params.Labels, err = parseLabels(labels); err != nil {
+					return err
+				}
 			}
 
 			resp, err := client.Call(protocol.MethodJobAdd, params)
@@ -737,35 +1426,136 @@ func jobAddCmd() *cobra.Command {
 			var info protocol.JobInfo
 			json.Unmarshal(resp.Result, &info)
 
+			if structuredOutput() {
+				return printStructured(info)
+			}
+
 			fmt.Printf("Job created:\n")
-			fmt.Printf("  ID:          %s\n", info.ID[:8])
+			fmt.Printf("  ID:          %s\n", display.ShortID(info.ID))
 			fmt.Printf("  Description: %s\n", info.Description)
+			if body != "" {
+				fmt.Printf("  Body:        %d lines\n", strings.Count(body, "\n")+1)
+			}
 			fmt.Printf("  Status:      %s\n", info.Status)
 			fmt.Printf("  Priority:    %d\n", info.Priority)
+			if len(info.Labels) > 0 {
+				fmt.Printf("  Labels:      %s\n", formatLabels(info.Labels))
+			}
+			if info.Model != "" {
+				fmt.Printf("  Model:       %s\n", info.Model)
+			}
+			if info.SkipReview {
+				fmt.Printf("  Review:      skipped\n")
+			}
+			if info.StickyWorker != "" {
+				fmt.Printf("  Sticky:      %s\n", info.StickyWorker)
+			}
+			if info.PlanFirst {
+				fmt.Printf("  Plan:        waits for approval before executing\n")
+			}
 
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVarP(&worker, "worker", "w", "", "Assign to specific worker")
+	cmd.Flags().StringVar(&stickyWorker, "sticky-worker", "", "Pin the job to a worker: wait for it and resume its session")
 	cmd.Flags().IntVarP(&priority, "priority", "p", 3, "Job priority (1-5)")
+	cmd.Flags().StringVar(&preset, "preset", "", "Apply a named preset from config (see 'cosa presets list')")
+	cmd.Flags().StringArrayVarP(&labels, "label", "l", nil, "Add a label (key=value, repeatable)")
+	cmd.Flags().StringVar(&model, "model", "", "Override the worker's model for this job")
+	cmd.Flags().BoolVar(&skipReview, "skip-review", false, "Skip auto-review when the job completes")
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Read the job specification from a file ('-' for stdin)")
+	cmd.Flags().StringVarP(&title, "title", "t", "", "Job title when the specification is given separately")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Fail the job if a run takes longer than this (default workers.job_timeout)")
+	cmd.Flags().BoolVar(&plan, "plan", false, "Plan first without editing, and wait for 'cosa job approve' to execute")
+	cmd.Flags().BoolVar(&decompose, "decompose", false, "Have the underboss break the job into dependent subtasks, shown for approval")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "With --decompose, create the breakdown without asking")
 
 	return cmd
 }
 
-func jobListCmd() *cobra.Command {
+// readJobText reads a job specification from path, or from stdin if path is "-".
+func readJobText(path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read job specification: %w", err)
+	}
+
+	text := strings.TrimSpace(string(data))
+	if text == "" {
+		return "", fmt.Errorf("job specification is empty")
+	}
+	return text, nil
+}
+
+// splitJobText returns the job description and body for a job specification.
+// A single line is used as the description as is. Longer text is stored as
+// the body, titled by title or else by its first line.
+func splitJobText(text, title string) (string, string) {
+	text = strings.TrimSpace(text)
+	if title != "" {
+		if text == title {
+			return title, ""
+		}
+		return title, text
+	}
+
+	first, _, multiline := strings.Cut(text, "\n")
+	if !multiline {
+		return text, ""
+	}
+	return strings.TrimSpace(strings.TrimLeft(first, "# ")), text
+}
+
+func jobBatchCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:     "list",
-		Short:   "List all jobs",
-		Aliases: []string{"ls"},
+		Use:   "batch <file>",
+		Short: "Submit many jobs at once without waiting for them to be created",
+		Long: `Submit a JSON array of jobs. The daemon returns the batch ID and job IDs
+straight away and validates and creates the jobs in the background.
+Use '-' to read the jobs from stdin.
+
+Each job takes the same fields as job.add:
+  [{"description": "Add tests", "priority": 4, "labels": {"area": "api"}}]
+
+Check progress with 'cosa job import-status <batch-id>'.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			var data []byte
+			var err error
+			if args[0] == "-" {
+				data, err = io.ReadAll(os.Stdin)
+			} else {
+				data, err = os.ReadFile(args[0])
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read jobs: %w", err)
+			}
+
+			var jobs []protocol.JobAddParams
+			if err := json.Unmarshal(data, &jobs); err != nil {
+				return fmt.Errorf("invalid jobs file: %w", err)
+			}
+			for i := range jobs {
+				if jobs[i].Requester == "" {
+					jobs[i].Requester = cfg.ClientIdentity()
+				}
+			}
+
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running")
 			}
 			defer client.Close()
 
-			resp, err := client.Call(protocol.MethodJobList, nil)
+			resp, err := client.Call(protocol.MethodJobAddBatch, protocol.JobAddBatchParams{Jobs: jobs})
 			if err != nil {
 				return err
 			}
@@ -774,48 +1564,851 @@ func jobListCmd() *cobra.Command {
 				return fmt.Errorf("%s", resp.Error.Message)
 			}
 
-			var jobs []protocol.JobInfo
-			json.Unmarshal(resp.Result, &jobs)
+			var result protocol.JobAddBatchResult
+			json.Unmarshal(resp.Result, &result)
 
-			if len(jobs) == 0 {
-				fmt.Println("No jobs")
-				return nil
+			if structuredOutput() {
+				return printStructured(result)
 			}
 
-			// Sort jobs by CreatedAt timestamp (newest first)
-			sort.Slice(jobs, func(i, j int) bool {
-				return jobs[i].CreatedAt > jobs[j].CreatedAt
+			fmt.Printf("Batch %s submitted (%d jobs)\n", result.BatchID, len(result.IDs))
+			fmt.Printf("Check progress with: cosa job import-status %s\n", result.BatchID)
+
+			return nil
+		},
+	}
+}
+
+func jobImportCmd() *cobra.Command {
+	var dryRun bool
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Create jobs from a Markdown checklist or a JSON/YAML task list",
+		Long: `Create jobs from a task list. Use '-' to read it from stdin.
+
+In a Markdown checklist each unchecked item becomes a job and checked items
+are skipped. Headings group the jobs below them into an operation, an item
+nested under another depends on it, and other indented lines become the job
+body. Items can end with attributes:
+
+  # Session rework
+  - [ ] Add the sessions table {id=schema priority=2}
+    - [ ] Expose sessions over the API
+  - [ ] Write the docs {after=schema area=docs}
+
+id names an item for after, which takes item ids or existing job IDs. Other
+attributes become labels.
+
+JSON and YAML task lists are arrays of tasks:
+  [{"key": "schema", "description": "Add the table", "section": "DB",
+    "priority": 2, "depends_on": ["other-key"], "labels": {"area": "db"}}]
+
+Every job is checked before any is created, so a bad reference or a
+dependency cycle creates nothing.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var data []byte
+			var err error
+			if args[0] == "-" {
+				data, err = io.ReadAll(os.Stdin)
+			} else {
+				data, err = os.ReadFile(args[0])
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read task list: %w", err)
+			}
+
+			tasks, err := protocol.ParseTaskList(data, args[0], format)
+			if err != nil {
+				return err
+			}
+
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			source := args[0]
+			if source == "-" {
+				source = "stdin"
+			}
+			resp, err := client.Call(protocol.MethodJobImport, protocol.JobImportParams{
+				Tasks:  tasks,
+				Source: source,
+				DryRun: dryRun,
+
+				Requester: cfg.ClientIdentity(),
 			})
+			if err != nil {
+				return err
+			}
 
-			fmt.Printf("%-10s %-12s %-4s %-20s %-30s\n", "ID", "STATUS", "PRI", "CREATED", "DESCRIPTION")
-			for _, j := range jobs {
-				desc := j.Description
-				if len(desc) > 28 {
-					desc = desc[:28] + ".."
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var result protocol.JobImportResult
+			json.Unmarshal(resp.Result, &result)
+
+			if structuredOutput() {
+				return printStructured(result)
+			}
+
+			verb := "Created"
+			if result.DryRun {
+				verb = "Would create"
+			}
+			fmt.Printf("%s %d jobs in %d operations from %s\n\n", verb, len(result.Jobs), len(result.Operations), source)
+
+			table := NewTable("KEY", "ID", "PRI", "OPERATION", "DESCRIPTION", "AFTER")
+			for _, j := range result.Jobs {
+				id := "-"
+				if j.ID != "" {
+					id = display.ShortID(j.ID)
 				}
-				// Convert Unix timestamp to local time
-				created := time.Unix(j.CreatedAt, 0).Local().Format("2006/01/02 15:04:05")
-				fmt.Printf("%-10s %-12s %-4d %-20s %-30s\n", j.ID[:8], j.Status, j.Priority, created, desc)
+				priority := "-"
+				if j.Priority > 0 {
+					priority = strconv.Itoa(j.Priority)
+				}
+				desc := truncate(j.Description, 42)
+				table.AddRow(j.Key, id, priority, valueOrDefault(j.Section, "-"), desc, valueOrDefault(strings.Join(j.DependsOn, ","), "-"))
 			}
+			table.Print()
 
+			if result.DryRun {
+				fmt.Println("\nDry run: nothing was created")
+			}
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVarP(&dryRun, "dry-run", "n", false, "Show what would be created without creating anything")
+	cmd.Flags().StringVar(&format, "format", "", "Task list format: markdown, json or yaml (default from the file name)")
+
+	return cmd
 }
 
-func jobCancelCmd() *cobra.Command {
-	return &cobra.Command{
+func jobImportStatusCmd() *cobra.Command {
+	var wait bool
+
+	cmd := &cobra.Command{
+		Use:   "import-status <batch-id>",
+		Short: "Show the per-job results of a batch submission",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			var result protocol.JobImportStatusResult
+			for {
+				resp, err := client.Call(protocol.MethodJobImportStatus, protocol.JobImportStatusParams{
+					BatchID: args[0],
+				})
+				if err != nil {
+					return err
+				}
+
+				if resp.Error != nil {
+					return fmt.Errorf("%s", resp.Error.Message)
+				}
+
+				json.Unmarshal(resp.Result, &result)
+				if !wait || result.Status == "completed" {
+					break
+				}
+				time.Sleep(time.Second)
+			}
+
+			if structuredOutput() {
+				return printStructured(result)
+			}
+
+			fmt.Printf("Batch %s: %s\n", result.BatchID, result.Status)
+			fmt.Printf("  %d total, %d created, %d failed\n\n", result.Total, result.Created, result.Failed)
+
+			table := NewTable("#", "ID", "STATUS", "DESCRIPTION", "ERROR")
+			for _, item := range result.Items {
+				desc := truncate(item.Description, 30)
+				table.AddRow(item.Index+1, display.ShortID(item.ID), item.Status, desc, item.Error)
+			}
+			table.Print()
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&wait, "wait", false, "Wait for the batch to finish")
+
+	return cmd
+}
+
+func jobListCmd() *cobra.Command {
+	var watchedOnly bool
+	var labels []string
+	var status, filterName, saveFilter string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List all jobs",
+		Long: `List all jobs. Jobs on your watch list are marked with *.
+
+--label key=value lists only jobs with that label, and --label key jobs with
+the label at all. --save-filter saves the --status and --label filters under
+a name for --filter and the TUI job list.`,
+		Aliases: []string{"ls"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if filterName != "" {
+				saved, ok := cfg.Filters[filterName]
+				if !ok {
+					return fmt.Errorf("unknown filter %s (saved filters: %s)", filterName, valueOrDefault(strings.Join(cfg.FilterNames(), ", "), "none"))
+				}
+				if status == "" {
+					status = saved.Status
+				}
+				labels = append(saved.Labels, labels...)
+			}
+			if _, err := job.ParseLabelSelector(labels); err != nil {
+				return err
+			}
+
+			if saveFilter != "" {
+				if cfg.Filters == nil {
+					cfg.Filters = make(map[string]config.JobFilter)
+				}
+				cfg.Filters[saveFilter] = config.JobFilter{Status: status, Labels: labels}
+				if err := cfg.Save(getConfigPath()); err != nil {
+					return fmt.Errorf("failed to save config: %w", err)
+				}
+				if !structuredOutput() {
+					fmt.Printf("Saved filter %s\n", saveFilter)
+				}
+			}
+
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			resp, err := client.Call(protocol.MethodJobList, protocol.JobListParams{
+				Identity: cfg.ClientIdentity(),
+				Labels:   labels,
+			})
+			if err != nil {
+				return err
+			}
+
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var jobs []protocol.JobInfo
+			json.Unmarshal(resp.Result, &jobs)
+
+			if watchedOnly {
+				var watched []protocol.JobInfo
+				for _, j := range jobs {
+					if j.Watched {
+						watched = append(watched, j)
+					}
+				}
+				jobs = watched
+			}
+			if status != "" {
+				var matching []protocol.JobInfo
+				for _, j := range jobs {
+					if j.Status == status {
+						matching = append(matching, j)
+					}
+				}
+				jobs = matching
+			}
+
+			// Sort jobs by CreatedAt timestamp (newest first)
+			sort.Slice(jobs, func(i, j int) bool {
+				return jobs[i].CreatedAt > jobs[j].CreatedAt
+			})
+
+			if structuredOutput() {
+				return printStructured(jobs)
+			}
+
+			if len(jobs) == 0 {
+				switch {
+				case watchedOnly:
+					fmt.Println("No watched jobs")
+				case status != "" || len(labels) > 0:
+					fmt.Println("No matching jobs")
+				default:
+					fmt.Println("No jobs")
+				}
+				return nil
+			}
+
+			showLabels := false
+			for _, j := range jobs {
+				if len(j.Labels) > 0 {
+					showLabels = true
+					break
+				}
+			}
+
+			columns := []string{"ID", "STATUS", "PRI", "CREATED", "DESCRIPTION"}
+			if showLabels {
+				columns = append(columns, "LABELS")
+			}
+			table := NewTable(columns...)
+			for _, j := range jobs {
+				desc := truncate(j.Description, 30)
+				id := display.ShortID(j.ID)
+				if j.Watched {
+					id += "*"
+				}
+				// Convert Unix timestamp to local time
+				created := time.Unix(j.CreatedAt, 0).Local().Format("2006/01/02 15:04:05")
+				row := []interface{}{id, j.Status, j.Priority, created, desc}
+				if showLabels {
+					row = append(row, formatLabels(j.Labels))
+				}
+				table.AddRow(row...)
+			}
+			table.Print()
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&watchedOnly, "watched", false, "Only list jobs on your watch list")
+	cmd.Flags().StringArrayVarP(&labels, "label", "l", nil, "Only list jobs with a label (key=value or key, repeatable)")
+	cmd.Flags().StringVar(&status, "status", "", "Only list jobs with this status")
+	cmd.Flags().StringVar(&filterName, "filter", "", "Apply a saved filter")
+	cmd.Flags().StringVar(&saveFilter, "save-filter", "", "Save the --status and --label filters under a name")
+
+	return cmd
+}
+
+// jobWatchCmd returns the watch command, or with watch false the unwatch
+// command.
+func jobWatchCmd(watch bool) *cobra.Command {
+	use, short, method := "watch <id>", "Pin a job to your watch list", protocol.MethodJobWatch
+	long := `Pin a job to your watch list. Watched jobs are listed first in the TUI
+jobs panel and send a notification on every status change, whatever the
+notification rules say. Watch lists belong to the identity setting, which
+defaults to your user name.`
+	if !watch {
+		use, short, method = "unwatch <id>", "Remove a job from your watch list", protocol.MethodJobUnwatch
+		long = ""
+	}
+
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Long:  long,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			resp, err := client.Call(method, protocol.JobWatchParams{
+				ID:       args[0],
+				Identity: cfg.ClientIdentity(),
+			})
+			if err != nil {
+				return err
+			}
+
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var result protocol.JobWatchResult
+			json.Unmarshal(resp.Result, &result)
+
+			if structuredOutput() {
+				return printStructured(result)
+			}
+
+			switch {
+			case watch && result.Changed:
+				fmt.Printf("Watching job '%s'\n", args[0])
+			case watch:
+				fmt.Printf("Already watching job '%s'\n", args[0])
+			case result.Changed:
+				fmt.Printf("Stopped watching job '%s'\n", args[0])
+			default:
+				fmt.Printf("Job '%s' is not on your watch list\n", args[0])
+			}
+			return nil
+		},
+	}
+}
+
+func jobConflictsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "conflicts",
+		Short: "List jobs whose branches could not be merged",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			resp, err := client.Call(protocol.MethodJobConflicts, nil)
+			if err != nil {
+				return err
+			}
+
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var conflicts []protocol.ConflictInfo
+			json.Unmarshal(resp.Result, &conflicts)
+
+			if structuredOutput() {
+				return printStructured(conflicts)
+			}
+
+			if len(conflicts) == 0 {
+				fmt.Println("No unmerged branches")
+				return nil
+			}
+
+			table := NewTable("JOB", "BRANCH", "TARGET", "RESOLVER", "DESCRIPTION")
+			for _, c := range conflicts {
+				resolver := "-"
+				if c.ResolverJob != "" {
+					resolver = fmt.Sprintf("%s (%s)", display.ShortID(c.ResolverJob), c.ResolverStatus)
+				}
+				desc := truncate(c.Description, 30)
+				table.AddRow(c.JobID, c.Branch, c.Target, resolver, desc)
+			}
+			table.Print()
+
+			return nil
+		},
+	}
+}
+
+func jobMergeCmd() *cobra.Command {
+	var retry bool
+
+	cmd := &cobra.Command{
+		Use:   "merge <id>",
+		Short: "Check or retry merging an unmerged job branch",
+		Long: `Check whether a job's kept branch still conflicts with the merge target.
+With --retry the merge is attempted; on success the branch is deleted.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			resp, err := client.Call(protocol.MethodJobMerge, protocol.JobMergeParams{
+				ID:    args[0],
+				Retry: retry,
+			})
+			if err != nil {
+				return err
+			}
+
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var result protocol.JobMergeResult
+			json.Unmarshal(resp.Result, &result)
+
+			if structuredOutput() {
+				return printStructured(result)
+			}
+
+			switch {
+			case result.PullRequest != "":
+				fmt.Printf("Pushed %s and opened %s\n", result.RemoteBranch, result.PullRequest)
+			case result.RemoteBranch != "":
+				fmt.Printf("Pushed %s\n", result.RemoteBranch)
+			case result.Merged:
+				fmt.Printf("Merged %s into %s (commit: %s)\n", result.Branch, result.Target, result.Commit)
+			case result.Conflicts:
+				fmt.Printf("%s still conflicts with %s\n", result.Branch, result.Target)
+			default:
+				fmt.Printf("%s merges cleanly into %s; run with --retry to merge\n", result.Branch, result.Target)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&retry, "retry", false, "Attempt the merge")
+
+	return cmd
+}
+
+func jobMergeQueueCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "merge-queue",
+		Short: "Show merges running and waiting their turn",
+		Long: `Show the merges into each target branch. Merges into one branch run one
+at a time, in the order the jobs finished; a job that waited is rebased
+onto the target before it merges.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			resp, err := client.Call(protocol.MethodMergeQueue, nil)
+			if err != nil {
+				return err
+			}
+
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var result protocol.MergeQueueResult
+			json.Unmarshal(resp.Result, &result)
+
+			if structuredOutput() {
+				return printStructured(result)
+			}
+
+			if len(result.Lanes) == 0 {
+				fmt.Println("No merges in progress")
+				return nil
+			}
+
+			table := NewTable("TARGET", "POSITION", "JOB", "BRANCH", "WAITING")
+			for _, lane := range result.Lanes {
+				if m := lane.Merging; m != nil {
+					table.AddRow(lane.Target, "merging", display.ShortID(m.JobID), m.Branch, formatDuration(time.Duration(m.StartedAt-m.QueuedAt)*time.Second))
+				}
+				for i, w := range lane.Waiting {
+					table.AddRow(lane.Target, fmt.Sprintf("#%d", i+1), display.ShortID(w.JobID), w.Branch, formatDuration(time.Since(time.Unix(w.QueuedAt, 0))))
+				}
+			}
+			table.Print()
+
+			return nil
+		},
+	}
+}
+
+func jobStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status <id>",
+		Short: "Show a job's details",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			resp, err := client.Call(protocol.MethodJobStatus, protocol.JobStatusParams{ID: args[0]})
+			if err != nil {
+				return err
+			}
+
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var info protocol.JobInfo
+			json.Unmarshal(resp.Result, &info)
+
+			if structuredOutput() {
+				return printStructured(info)
+			}
+
+			fmt.Printf("ID:          %s\n", info.ID)
+			fmt.Printf("Description: %s\n", info.Description)
+			fmt.Printf("Status:      %s\n", info.Status)
+			fmt.Printf("Priority:    %d\n", info.Priority)
+			if info.Worker != "" {
+				fmt.Printf("Worker:      %s\n", info.Worker)
+			}
+			if info.StickyWorker != "" {
+				fmt.Printf("Sticky:      %s (see 'cosa job unstick')\n", info.StickyWorker)
+			}
+			if info.Branch != "" {
+				fmt.Printf("Branch:      %s\n", info.Branch)
+			}
+			if len(info.Labels) > 0 {
+				fmt.Printf("Labels:      %s\n", formatLabels(info.Labels))
+			}
+			if model := jobModelText(info); model != "" {
+				fmt.Printf("Model:       %s\n", model)
+			}
+			if info.Template != "" {
+				fmt.Printf("Template:    %s", info.Template)
+				if len(info.TemplateVars) > 0 {
+					fmt.Printf(" (%s)", formatLabels(info.TemplateVars))
+				}
+				fmt.Println()
+			}
+			if info.RerunOf != "" {
+				fmt.Printf("Rerun of:    %s\n", display.ShortID(info.RerunOf))
+			}
+			if len(info.Reruns) > 0 {
+				ids := make([]string, len(info.Reruns))
+				for i, id := range info.Reruns {
+					ids[i] = display.ShortID(id)
+				}
+				fmt.Printf("Reruns:      %s\n", strings.Join(ids, ", "))
+			}
+			if len(info.DelegatedBy) > 0 {
+				steps := make([]string, 0, len(info.DelegatedBy)+1)
+				for _, step := range info.DelegatedBy {
+					steps = append(steps, fmt.Sprintf("%s (%s)", display.ShortID(step.Job), step.Worker))
+				}
+				fmt.Printf("Delegation:  %s\n", strings.Join(append(steps, "this job"), " -> "))
+			}
+			if len(info.Delegated) > 0 {
+				ids := make([]string, len(info.Delegated))
+				for i, id := range info.Delegated {
+					ids[i] = display.ShortID(id)
+				}
+				fmt.Printf("Delegated:   %s\n", strings.Join(ids, ", "))
+			}
+			if info.StartedAt > 0 {
+				fmt.Printf("Started:     %s\n", time.Unix(info.StartedAt, 0).Format("2006-01-02 15:04:05"))
+			}
+			if info.CompletedAt > 0 {
+				fmt.Printf("Completed:   %s\n", time.Unix(info.CompletedAt, 0).Format("2006-01-02 15:04:05"))
+			}
+			if info.Timeout > 0 {
+				fmt.Printf("Timeout:     %s\n", formatTimeoutInfo(info, time.Now()))
+			}
+			if info.Timeouts > 0 {
+				fmt.Printf("Timed out:   %d time(s)\n", info.Timeouts)
+			}
+			if info.Error != "" {
+				fmt.Printf("Error:       %s\n", info.Error)
+			}
+			if info.PlanFirst {
+				fmt.Printf("Plan:        %s\n", planStateText(info))
+				if info.Plan != "" {
+					fmt.Println()
+					fmt.Println(info.Plan)
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+// planStateText describes where a plan-first job is in its plan phase.
+func planStateText(info protocol.JobInfo) string {
+	switch {
+	case info.PlanApproved:
+		return "approved"
+	case info.AttentionKind == job.AttentionPlanApproval:
+		return "waiting for approval (see 'cosa job approve')"
+	default:
+		return "not planned yet"
+	}
+}
+
+func jobApproveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "approve <id>",
+		Short: "Approve a job's plan, or a draft job, and queue it",
+		Long: `Approve the plan of a job added with --plan. The job is queued again and its
+worker carries out the plan in the job's worktree.
+
+To have the worker plan again instead, give it feedback with
+'cosa triage retry <id> --note "..."'.
+
+Draft jobs, such as those filed by email, are approved the same way and
+are then queued like any other job. 'cosa triage dismiss' drops them.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			resp, err := client.Call(protocol.MethodJobApprove, protocol.JobApproveParams{ID: args[0]})
+			if err != nil {
+				return err
+			}
+
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			if structuredOutput() {
+				var result map[string]string
+				json.Unmarshal(resp.Result, &result)
+				return printStructured(result)
+			}
+
+			fmt.Printf("Job '%s' approved and queued\n", args[0])
+			return nil
+		},
+	}
+}
+
+func jobRerunCmd() *cobra.Command {
+	var worker string
+	var priority int
+
+	cmd := &cobra.Command{
+		Use:   "rerun <id>",
+		Short: "Run a finished job again with the same parameters",
+		Long: `Add a new job that runs a completed, failed or cancelled job again. The new
+job gets the original's description, priority, labels and options, and the
+template and variables it was created from, and records the job it reruns.
+
+Dependencies are carried over where they still matter: one that was rerun
+is replaced by its latest rerun, and one that has finished is dropped.`,
+		Example: `  cosa job rerun 3f2a
+  cosa job rerun 3f2a --worker tony --priority 4`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+			cmd.SilenceUsage = true
+
+			resp, err := client.Call(protocol.MethodJobRerun, protocol.JobRerunParams{
+				ID:       args[0],
+				Worker:   worker,
+				Priority: priority,
+			})
+			if err != nil {
+				return err
+			}
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var result protocol.JobRerunResult
+			json.Unmarshal(resp.Result, &result)
+			if structuredOutput() {
+				return printStructured(result)
+			}
+
+			fmt.Printf("Job %s reruns %s: %s\n", display.ShortID(result.Job.ID), display.ShortID(result.Job.RerunOf), result.Job.Description)
+			if len(result.Job.DependsOn) > 0 {
+				ids := make([]string, len(result.Job.DependsOn))
+				for i, id := range result.Job.DependsOn {
+					ids[i] = display.ShortID(id)
+				}
+				fmt.Printf("  Waits on: %s\n", strings.Join(ids, ", "))
+			}
+			if len(result.Dropped) > 0 {
+				ids := make([]string, len(result.Dropped))
+				for i, id := range result.Dropped {
+					ids[i] = display.ShortID(id)
+				}
+				fmt.Printf("  Dropped finished dependencies: %s\n", strings.Join(ids, ", "))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&worker, "worker", "w", "", "Assign the rerun to a specific worker")
+	cmd.Flags().IntVarP(&priority, "priority", "p", 0, "Priority 1-5 (default: the original's)")
+
+	return cmd
+}
+
+func jobUnstickCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unstick <id>",
+		Short: "Stop a job waiting for its sticky worker",
+		Long: `Unpin a job added with --sticky-worker. A queued job then goes to the next
+idle worker, which starts a fresh session for it.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			resp, err := client.Call(protocol.MethodJobUnstick, protocol.JobUnstickParams{ID: args[0]})
+			if err != nil {
+				return err
+			}
+
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var result protocol.JobUnstickResult
+			json.Unmarshal(resp.Result, &result)
+
+			if structuredOutput() {
+				return printStructured(result)
+			}
+
+			if result.Worker == "" {
+				fmt.Printf("Job '%s' is not pinned to a worker\n", args[0])
+			} else {
+				fmt.Printf("Job '%s' is no longer pinned to %s\n", args[0], result.Worker)
+			}
+			return nil
+		},
+	}
+}
+
+// formatTimeoutInfo describes a job's timeout, with the time left if it is
+// running.
+func formatTimeoutInfo(info protocol.JobInfo, now time.Time) string {
+	timeout := time.Duration(info.Timeout) * time.Second
+	if info.Deadline == 0 {
+		return timeout.String()
+	}
+	remaining := time.Unix(info.Deadline, 0).Sub(now).Round(time.Second)
+	if remaining <= 0 {
+		return fmt.Sprintf("%s (overdue)", timeout)
+	}
+	return fmt.Sprintf("%s (%s remaining)", timeout, remaining)
+}
+
+func jobCancelCmd() *cobra.Command {
+	var grace time.Duration
+
+	cmd := &cobra.Command{
 		Use:   "cancel <id>",
 		Short: "Cancel a job",
-		Args:  cobra.ExactArgs(1),
+		Long: `Cancel a job. If the job is running, its Claude process is interrupted
+and given the grace period to exit before it is killed. The job's worktree
+and branch are removed without merging.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running")
 			}
 			defer client.Close()
 
-			resp, err := client.Call(protocol.MethodJobCancel, map[string]string{"id": args[0]})
+			resp, err := client.Call(protocol.MethodJobCancel, protocol.JobCancelParams{
+				ID:      args[0],
+				GraceMS: int(grace.Milliseconds()),
+			})
 			if err != nil {
 				return err
 			}
@@ -828,10 +2421,135 @@ func jobCancelCmd() *cobra.Command {
 			return nil
 		},
 	}
+
+	cmd.Flags().DurationVar(&grace, "grace", 10*time.Second, "Time to let Claude exit after interrupt before killing it")
+
+	return cmd
 }
 
 // Template commands
 
+func presetsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "presets",
+		Short:   "Manage job presets",
+		Aliases: []string{"preset"},
+		Long: `Presets are named bundles of job add options defined in config:
+
+  presets:
+    quickfix:
+      priority: 2
+      labels:
+        type: bug
+      skip_review: true
+      model: haiku
+
+Use them with 'cosa job add "fix typo" --preset quickfix'.`,
+	}
+
+	cmd.AddCommand(presetsListCmd())
+
+	return cmd
+}
+
+func presetsListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Short:   "List configured job presets",
+		Aliases: []string{"ls"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			names := cfg.PresetNames()
+
+			if structuredOutput() {
+				return printStructured(cfg.Presets)
+			}
+
+			if len(names) == 0 {
+				fmt.Println("No presets configured")
+				return nil
+			}
+
+			table := NewTable("NAME", "PRI", "MODEL", "REVIEW", "WORKER", "LABELS")
+			for _, name := range names {
+				p, _ := cfg.Preset(name)
+				pri := "-"
+				if p.Priority > 0 {
+					pri = fmt.Sprintf("%d", p.Priority)
+				}
+				review := "yes"
+				if p.SkipReview {
+					review = "skip"
+				}
+				table.AddRow(name, pri, valueOrDefault(p.Model, "-"), review, valueOrDefault(p.Worker, "-"), formatLabels(p.Labels))
+			}
+			table.Print()
+
+			return nil
+		},
+	}
+}
+
+// formatLabels renders labels as sorted key=value pairs.
+// fallbackText renders claude.fallback as "opus -> sonnet, ..." in model
+// order.
+func fallbackText(fallback map[string]string) string {
+	models := make([]string, 0, len(fallback))
+	for model := range fallback {
+		models = append(models, model)
+	}
+	sort.Strings(models)
+	parts := make([]string, 0, len(models))
+	for _, model := range models {
+		parts = append(parts, model+" -> "+fallback[model])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// jobModelText describes the model a job asked for and the one it ran on,
+// which differ when the job fell back to another model.
+func jobModelText(info protocol.JobInfo) string {
+	switch {
+	case info.ModelUsed == "" || info.ModelUsed == info.Model:
+		return info.Model
+	case info.Model == "":
+		return info.ModelUsed
+	default:
+		return fmt.Sprintf("%s (fell back from %s)", info.ModelUsed, info.Model)
+	}
+}
+
+// parseLabels parses key=value labels.
+func parseLabels(labels []string) (map[string]string, error) {
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	parsed := make(map[string]string, len(labels))
+	for _, l := range labels {
+		key, value, ok := strings.Cut(l, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid label %q (expected key=value)", l)
+		}
+		parsed[key] = value
+	}
+	return parsed, nil
+}
+
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "-"
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+labels[k])
+	}
+	return strings.Join(parts, ",")
+}
+
 func templateCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "template",
@@ -843,6 +2561,11 @@ func templateCmd() *cobra.Command {
 		templateListCmd(),
 		templateShowCmd(),
 		templateUseCmd(),
+		templateCreateCmd(),
+		templateEditCmd(),
+		templateRemoveCmd(),
+		templateExportCmd(),
+		templateImportCmd(),
 	)
 
 	return cmd
@@ -856,7 +2579,7 @@ func templateListCmd() *cobra.Command {
 		Short:   "List available job templates",
 		Aliases: []string{"ls"},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running")
 			}
@@ -878,19 +2601,21 @@ func templateListCmd() *cobra.Command {
 			var result protocol.TemplateListResult
 			json.Unmarshal(resp.Result, &result)
 
+			if structuredOutput() {
+				return printStructured(result.Templates)
+			}
+
 			if len(result.Templates) == 0 {
 				fmt.Println("No templates found")
 				return nil
 			}
 
-			fmt.Printf("%-20s %-12s %-4s %s\n", "ID", "TYPE", "PRI", "DESCRIPTION")
+			table := NewTable("ID", "TYPE", "PRI", "DESCRIPTION")
 			for _, t := range result.Templates {
-				desc := t.Description
-				if len(desc) > 45 {
-					desc = desc[:45] + ".."
-				}
-				fmt.Printf("%-20s %-12s %-4d %s\n", t.ID, t.Type, t.Priority, desc)
+				desc := truncate(t.Description, 47)
+				table.AddRow(t.ID, t.Type, t.Priority, desc)
 			}
+			table.Print()
 
 			return nil
 		},
@@ -908,7 +2633,7 @@ func templateShowCmd() *cobra.Command {
 		Aliases: []string{"get", "info"},
 		Args:    cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running")
 			}
@@ -928,6 +2653,10 @@ func templateShowCmd() *cobra.Command {
 			var result protocol.TemplateGetResult
 			json.Unmarshal(resp.Result, &result)
 
+			if structuredOutput() {
+				return printStructured(result)
+			}
+
 			t := result.Template
 			fmt.Printf("Template: %s\n", t.Name)
 			fmt.Printf("  ID:          %s\n", t.ID)
@@ -960,6 +2689,13 @@ func templateShowCmd() *cobra.Command {
 				fmt.Printf("\nTags: %s\n", strings.Join(t.Tags, ", "))
 			}
 
+			if len(t.Checklist) > 0 {
+				fmt.Println("\nReview checklist:")
+				for _, item := range t.Checklist {
+					fmt.Printf("  - %s\n", item)
+				}
+			}
+
 			fmt.Println("\nPrompt:")
 			fmt.Println("---")
 			fmt.Println(result.Prompt)
@@ -980,28 +2716,26 @@ func templateUseCmd() *cobra.Command {
 		Short: "Create a job from a template",
 		Long: `Create a new job using a template.
 
-Variables can be specified with the -v flag:
-  cosa template use refactor-file -v file=src/main.go -v focus=performance
+Variables can be specified with --var (or -v):
+  cosa template use refactor-file --var file=src/main.go --var focus=performance
+
+The job is only created once every required variable is given, and
+variables the template does not take are rejected.
 
 Examples:
   cosa template use test-unit -v target=internal/api/handler.go
   cosa template use review-code -v target=HEAD~5..HEAD -w paulie`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running")
 			}
 			defer client.Close()
 
-			// Parse variables
-			variables := make(map[string]string)
-			for _, v := range vars {
-				parts := strings.SplitN(v, "=", 2)
-				if len(parts) != 2 {
-					return fmt.Errorf("invalid variable format: %s (expected name=value)", v)
-				}
-				variables[parts[0]] = parts[1]
+			variables, err := parseAssignments(vars, "variable")
+			if err != nil {
+				return err
 			}
 
 			params := protocol.TemplateUseParams{
@@ -1011,40 +2745,427 @@ Examples:
 				Worker:     worker,
 			}
 
-			resp, err := client.Call(protocol.MethodTemplateUse, params)
+			resp, err := client.Call(protocol.MethodTemplateUse, params)
+			if err != nil {
+				return err
+			}
+
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var result protocol.TemplateUseResult
+			json.Unmarshal(resp.Result, &result)
+
+			fmt.Printf("Job created from template:\n")
+			fmt.Printf("  ID:          %s\n", display.ShortID(result.Job.ID))
+			fmt.Printf("  Status:      %s\n", result.Job.Status)
+			fmt.Printf("  Priority:    %d\n", result.Job.Priority)
+			fmt.Printf("  Description: %s\n", truncate(result.Job.Description, 60))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&worker, "worker", "w", "", "Assign to specific worker")
+	cmd.Flags().IntVarP(&priority, "priority", "p", 0, "Override template priority (1-5)")
+	cmd.Flags().StringArrayVarP(&vars, "var", "v", nil, "Variable in name=value format")
+
+	return cmd
+}
+
+func templateCreateCmd() *cobra.Command {
+	var prompt, promptFile, name, description, templateType string
+	var priority int
+	var tags, checklist, defaults, descriptions, optional []string
+	var replace bool
+
+	cmd := &cobra.Command{
+		Use:   "create <template-id>",
+		Short: "Create a custom job template",
+		Long: `Create a custom job template. Every {{name}} placeholder in the prompt
+becomes a variable, required unless it has a default or is marked optional.
+
+Examples:
+  cosa template create add-endpoint --prompt "Add the {{method}} endpoint {{path}}" \
+    --default method=GET --describe path="URL path of the endpoint"
+  cosa template create migration --prompt-file migration.md --type custom -p 4`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if promptFile != "" {
+				text, err := readJobText(promptFile)
+				if err != nil {
+					return err
+				}
+				prompt = text
+			}
+			if prompt == "" {
+				return fmt.Errorf("a prompt is required (use --prompt or --prompt-file)")
+			}
+
+			defaultValues, err := parseAssignments(defaults, "default")
+			if err != nil {
+				return err
+			}
+			varDescriptions, err := parseAssignments(descriptions, "description")
+			if err != nil {
+				return err
+			}
+
+			spec := protocol.TemplateSpec{
+				ID:          args[0],
+				Name:        name,
+				Description: description,
+				Type:        templateType,
+				Priority:    priority,
+				Prompt:      prompt,
+				Tags:        tags,
+				Checklist:   checklist,
+			}
+			placeholders := (&job.Template{Prompt: prompt}).Placeholders()
+			for _, v := range placeholders {
+				spec.Variables = append(spec.Variables, protocol.TemplateVar{
+					Name:        v,
+					Description: varDescriptions[v],
+					Default:     defaultValues[v],
+					Required:    defaultValues[v] == "" && !contains(optional, v),
+				})
+			}
+			for _, given := range [][]string{keys(defaultValues), keys(varDescriptions), optional} {
+				for _, v := range given {
+					if !contains(placeholders, v) {
+						return fmt.Errorf("the prompt has no {{%s}} placeholder", v)
+					}
+				}
+			}
+
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			resp, err := client.Call(protocol.MethodTemplateCreate, protocol.TemplateCreateParams{
+				Template: spec,
+				Replace:  replace,
+			})
+			if err != nil {
+				return err
+			}
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var result protocol.TemplateGetResult
+			json.Unmarshal(resp.Result, &result)
+
+			if structuredOutput() {
+				return printStructured(result)
+			}
+
+			fmt.Printf("Template %s saved", result.Template.ID)
+			if len(result.Template.Variables) > 0 {
+				names := make([]string, len(result.Template.Variables))
+				for i, v := range result.Template.Variables {
+					names[i] = v.Name
+					if v.Required {
+						names[i] += "*"
+					}
+				}
+				fmt.Printf(" (variables: %s)", strings.Join(names, ", "))
+			}
+			fmt.Println()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&prompt, "prompt", "", "Prompt text with {{name}} placeholders")
+	cmd.Flags().StringVarP(&promptFile, "prompt-file", "f", "", "Read the prompt from a file ('-' for stdin)")
+	cmd.Flags().StringVar(&name, "name", "", "Display name (defaults to the ID)")
+	cmd.Flags().StringVarP(&description, "description", "d", "", "Short description")
+	cmd.Flags().StringVarP(&templateType, "type", "t", "custom", "Template type (refactor, test, document, review, custom)")
+	cmd.Flags().IntVarP(&priority, "priority", "p", 0, "Job priority (1-5, default 3)")
+	cmd.Flags().StringArrayVar(&tags, "tag", nil, "Add a tag (repeatable)")
+	cmd.Flags().StringArrayVar(&checklist, "check", nil, "Add a review checklist item (repeatable)")
+	cmd.Flags().StringArrayVar(&defaults, "default", nil, "Default for a variable, name=value (repeatable)")
+	cmd.Flags().StringArrayVar(&descriptions, "describe", nil, "Description of a variable, name=text (repeatable)")
+	cmd.Flags().StringArrayVar(&optional, "optional", nil, "Mark a variable without a default as optional (repeatable)")
+	cmd.Flags().BoolVar(&replace, "replace", false, "Overwrite an existing custom template")
+
+	return cmd
+}
+
+func templateEditCmd() *cobra.Command {
+	var saveAs string
+
+	cmd := &cobra.Command{
+		Use:   "edit <template-id>",
+		Short: "Edit a custom template in $EDITOR",
+		Long: `Open a template as JSON in $VISUAL or $EDITOR and save it when the editor
+exits. Built-in templates cannot be changed, but --as saves the edited
+copy as a new custom template.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			specs, err := exportTemplates(client, []string{args[0]}, false)
+			if err != nil {
+				return err
+			}
+			spec := specs[0]
+			if saveAs != "" {
+				spec.ID = saveAs
+			}
+
+			data, _ := json.MarshalIndent(spec, "", "  ")
+			f, err := os.CreateTemp("", "cosa-template-*.json")
+			if err != nil {
+				return err
+			}
+			defer os.Remove(f.Name())
+			f.Write(append(data, '\n'))
+			f.Close()
+
+			if err := runEditor(f.Name()); err != nil {
+				return err
+			}
+
+			edited, err := os.ReadFile(f.Name())
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(string(edited)) == strings.TrimSpace(string(data)) {
+				fmt.Println("No changes")
+				return nil
+			}
+			var updated protocol.TemplateSpec
+			if err := json.Unmarshal(edited, &updated); err != nil {
+				return fmt.Errorf("invalid template JSON: %w", err)
+			}
+
+			resp, err := client.Call(protocol.MethodTemplateCreate, protocol.TemplateCreateParams{
+				Template: updated,
+				Replace:  saveAs == "" && updated.ID == args[0],
+			})
+			if err != nil {
+				return err
+			}
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			fmt.Printf("Template %s saved\n", updated.ID)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&saveAs, "as", "", "Save the edited template under a new ID")
+
+	return cmd
+}
+
+func templateRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "remove <template-id>",
+		Short:   "Remove a custom template",
+		Aliases: []string{"rm", "delete"},
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			resp, err := client.Call(protocol.MethodTemplateRemove, protocol.TemplateRemoveParams{ID: args[0]})
+			if err != nil {
+				return err
+			}
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			fmt.Printf("Template %s removed\n", args[0])
+			return nil
+		},
+	}
+}
+
+func templateExportCmd() *cobra.Command {
+	var output string
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "export [template-id...]",
+		Short: "Export templates as JSON",
+		Long: `Export templates as a JSON array that 'cosa template import' reads back.
+Without IDs, every custom template is exported; --all adds the built-in ones.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			specs, err := exportTemplates(client, args, all)
+			if err != nil {
+				return err
+			}
+
+			data, _ := json.MarshalIndent(specs, "", "  ")
+			data = append(data, '\n')
+
+			if output == "" || output == "-" {
+				os.Stdout.Write(data)
+				return nil
+			}
+			if err := os.WriteFile(output, data, 0644); err != nil {
+				return err
+			}
+			fmt.Printf("Exported %d templates to %s\n", len(specs), output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "file", "f", "", "Write to a file instead of stdout")
+	cmd.Flags().BoolVar(&all, "all", false, "Include built-in templates")
+
+	return cmd
+}
+
+func templateImportCmd() *cobra.Command {
+	var replace bool
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import templates from JSON",
+		Long: `Import templates from a JSON file written by 'cosa template export', a
+single template object, or '-' for stdin. Every template is validated
+before any is saved.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var data []byte
+			var err error
+			if args[0] == "-" {
+				data, err = io.ReadAll(os.Stdin)
+			} else {
+				data, err = os.ReadFile(args[0])
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read templates: %w", err)
+			}
+
+			var specs []protocol.TemplateSpec
+			if err := json.Unmarshal(data, &specs); err != nil {
+				var spec protocol.TemplateSpec
+				if err := json.Unmarshal(data, &spec); err != nil {
+					return fmt.Errorf("invalid templates file: %w", err)
+				}
+				specs = []protocol.TemplateSpec{spec}
+			}
+
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			resp, err := client.Call(protocol.MethodTemplateImport, protocol.TemplateImportParams{
+				Templates: specs,
+				Replace:   replace,
+			})
 			if err != nil {
 				return err
 			}
-
 			if resp.Error != nil {
 				return fmt.Errorf("%s", resp.Error.Message)
 			}
 
-			var result protocol.TemplateUseResult
+			var result protocol.TemplateImportResult
 			json.Unmarshal(resp.Result, &result)
 
-			fmt.Printf("Job created from template:\n")
-			fmt.Printf("  ID:          %s\n", result.Job.ID[:8])
-			fmt.Printf("  Status:      %s\n", result.Job.Status)
-			fmt.Printf("  Priority:    %d\n", result.Job.Priority)
-			fmt.Printf("  Description: %s\n", truncate(result.Job.Description, 60))
+			if structuredOutput() {
+				return printStructured(result)
+			}
 
+			fmt.Printf("Imported %d templates: %s\n", len(result.Imported), strings.Join(result.Imported, ", "))
+			if len(result.Replaced) > 0 {
+				fmt.Printf("Replaced: %s\n", strings.Join(result.Replaced, ", "))
+			}
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVarP(&worker, "worker", "w", "", "Assign to specific worker")
-	cmd.Flags().IntVarP(&priority, "priority", "p", 0, "Override template priority (1-5)")
-	cmd.Flags().StringArrayVarP(&vars, "var", "v", nil, "Variable in name=value format")
+	cmd.Flags().BoolVar(&replace, "replace", false, "Overwrite existing custom templates")
 
 	return cmd
 }
 
-func truncate(s string, max int) string {
-	if len(s) <= max {
-		return s
+// exportTemplates fetches templates as specs.
+func exportTemplates(client *daemon.Client, ids []string, builtIn bool) ([]protocol.TemplateSpec, error) {
+	resp, err := client.Call(protocol.MethodTemplateExport, protocol.TemplateExportParams{
+		IDs:     ids,
+		BuiltIn: builtIn,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("%s", resp.Error.Message)
+	}
+
+	var result protocol.TemplateExportResult
+	json.Unmarshal(resp.Result, &result)
+	return result.Templates, nil
+}
+
+// parseAssignments parses name=value flags.
+func parseAssignments(values []string, what string) (map[string]string, error) {
+	parsed := make(map[string]string, len(values))
+	for _, v := range values {
+		name, value, ok := strings.Cut(v, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid %s format: %s (expected name=value)", what, v)
+		}
+		parsed[name] = value
+	}
+	return parsed, nil
+}
+
+func keys(m map[string]string) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// runEditor opens path in $VISUAL or $EDITOR and waits for it to exit.
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
 	}
-	return s[:max-2] + ".."
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// The editor may carry its own arguments, e.g. "code --wait"
+	args := append(strings.Fields(editor), path)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func truncate(s string, max int) string {
+	return display.Truncate(s, max)
 }
 
 // Review commands
@@ -1059,6 +3180,8 @@ func reviewCmd() *cobra.Command {
 		reviewStartCmd(),
 		reviewStatusCmd(),
 		reviewListCmd(),
+		reviewOverrideCmd(protocol.MethodReviewApprove),
+		reviewOverrideCmd(protocol.MethodReviewReject),
 	)
 
 	return cmd
@@ -1070,7 +3193,7 @@ func reviewStartCmd() *cobra.Command {
 		Short: "Start a code review for a completed job",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running")
 			}
@@ -1093,13 +3216,89 @@ func reviewStartCmd() *cobra.Command {
 	}
 }
 
+// reviewOverrideCmd builds review approve or review reject, which decide a
+// review in place of the consigliere.
+func reviewOverrideCmd(method string) *cobra.Command {
+	var reason string
+	var noRevision bool
+
+	cmd := &cobra.Command{
+		Use:   "approve <job-id>",
+		Short: "Approve a job in review, overriding the consigliere",
+		Long: `Approve a job in review yourself, for when the consigliere stalls or you
+disagree with it. A review still running is abandoned, and the job is
+merged as if the consigliere had approved it. The ledger records who
+approved it and why.`,
+		Example: `  cosa review approve 3f2a9c1e-... --reason "flaky gate, verified locally"`,
+	}
+	if method == protocol.MethodReviewReject {
+		cmd.Use = "reject <job-id>"
+		cmd.Short = "Reject a job in review, overriding the consigliere"
+		cmd.Long = `Reject a job in review yourself, for when the consigliere stalls or you
+disagree with it. A review still running is abandoned, and a revision job
+is queued for the worker with your reason as the feedback to address. The
+ledger records who rejected it and why. With --no-revision the job is
+failed instead, for work not worth revising.`
+		cmd.Example = `  cosa review reject 3f2a9c1e-... --reason "needs tests for the error paths"
+  cosa review reject 3f2a9c1e-... --reason "superseded by #42" --no-revision`
+		cmd.Flags().BoolVar(&noRevision, "no-revision", false, "Fail the job instead of queuing a revision")
+	}
+
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		client, err := connectDaemon()
+		if err != nil {
+			return fmt.Errorf("daemon not running")
+		}
+		defer client.Close()
+
+		resp, err := client.Call(method, protocol.ReviewOverrideParams{
+			JobID:  args[0],
+			Reason: reason,
+			By:     cfg.ClientIdentity(),
+
+			NoRevision: noRevision,
+		})
+		if err != nil {
+			return err
+		}
+
+		if resp.Error != nil {
+			return fmt.Errorf("%s", resp.Error.Message)
+		}
+
+		var result protocol.ReviewOverrideResult
+		json.Unmarshal(resp.Result, &result)
+
+		if structuredOutput() {
+			return printStructured(result)
+		}
+
+		if result.RevisionJobID != "" {
+			fmt.Printf("Job %s rejected; revision job %s queued\n", display.ShortID(result.JobID), display.ShortID(result.RevisionJobID))
+			return nil
+		}
+		if result.Decision == "rejected" {
+			fmt.Printf("Job %s rejected and failed\n", display.ShortID(result.JobID))
+			return nil
+		}
+		fmt.Printf("Job %s approved\n", display.ShortID(result.JobID))
+		return nil
+	}
+
+	cmd.Flags().StringVarP(&reason, "reason", "r", "", "Why you are overriding the review (required)")
+	cmd.MarkFlagRequired("reason")
+
+	return cmd
+}
+
 func reviewStatusCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "status <job-id>",
 		Short: "Show status of a code review",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running")
 			}
@@ -1119,6 +3318,10 @@ func reviewStatusCmd() *cobra.Command {
 			var result protocol.ReviewStatusResult
 			json.Unmarshal(resp.Result, &result)
 
+			if structuredOutput() {
+				return printStructured(result)
+			}
+
 			fmt.Printf("Review Status:\n")
 			fmt.Printf("  Job ID:   %s\n", result.JobID)
 			fmt.Printf("  Worker:   %s\n", result.WorkerName)
@@ -1133,6 +3336,20 @@ func reviewStatusCmd() *cobra.Command {
 				fmt.Printf("  Error:    %s\n", result.Error)
 			}
 
+			if len(result.Checklist) > 0 {
+				fmt.Println("\nChecklist:")
+				for _, item := range result.Checklist {
+					verdict := "PASS"
+					if !item.Passed {
+						verdict = "FAIL"
+					}
+					fmt.Printf("  [%s] %s\n", verdict, item.Item)
+					if item.Note != "" {
+						fmt.Printf("         %s\n", item.Note)
+					}
+				}
+			}
+
 			return nil
 		},
 	}
@@ -1144,7 +3361,7 @@ func reviewListCmd() *cobra.Command {
 		Short:   "List all active reviews",
 		Aliases: []string{"ls"},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running")
 			}
@@ -1162,27 +3379,26 @@ func reviewListCmd() *cobra.Command {
 			var result protocol.ReviewListResult
 			json.Unmarshal(resp.Result, &result)
 
+			if structuredOutput() {
+				return printStructured(result.Reviews)
+			}
+
 			if len(result.Reviews) == 0 {
 				fmt.Println("No active reviews")
 				return nil
 			}
 
-			fmt.Printf("%-10s %-15s %-12s %-10s %s\n", "JOB ID", "WORKER", "PHASE", "DECISION", "SUMMARY")
+			table := NewTable("JOB ID", "WORKER", "PHASE", "DECISION", "SUMMARY")
 			for _, r := range result.Reviews {
-				jobID := r.JobID
-				if len(jobID) > 8 {
-					jobID = jobID[:8]
-				}
-				summary := r.Summary
-				if len(summary) > 30 {
-					summary = summary[:30] + ".."
-				}
+				jobID := display.ShortID(r.JobID)
+				summary := truncate(r.Summary, 32)
 				decision := r.Decision
 				if decision == "" {
 					decision = "-"
 				}
-				fmt.Printf("%-10s %-15s %-12s %-10s %s\n", jobID, r.WorkerName, r.Phase, decision, summary)
+				table.AddRow(jobID, r.WorkerName, r.Phase, decision, summary)
 			}
+			table.Print()
 
 			return nil
 		},
@@ -1211,13 +3427,15 @@ func operationCmd() *cobra.Command {
 func operationCreateCmd() *cobra.Command {
 	var jobs []string
 	var description string
+	var maxParallel int
+	var sequential, stopOnFail bool
 
 	cmd := &cobra.Command{
 		Use:   "create <name>",
 		Short: "Create a new operation",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running")
 			}
@@ -1227,6 +3445,9 @@ func operationCreateCmd() *cobra.Command {
 				Name:        args[0],
 				Description: description,
 				Jobs:        jobs,
+				MaxParallel: maxParallel,
+				Sequential:  sequential,
+				StopOnFail:  stopOnFail,
 			}
 
 			resp, err := client.Call(protocol.MethodOperationCreate, params)
@@ -1242,10 +3463,13 @@ func operationCreateCmd() *cobra.Command {
 			json.Unmarshal(resp.Result, &info)
 
 			fmt.Printf("Operation created:\n")
-			fmt.Printf("  ID:     %s\n", info.ID[:8])
+			fmt.Printf("  ID:     %s\n", display.ShortID(info.ID))
 			fmt.Printf("  Name:   %s\n", info.Name)
 			fmt.Printf("  Status: %s\n", info.Status)
 			fmt.Printf("  Jobs:   %d\n", info.TotalJobs)
+			if limits := operationLimits(info); limits != "" {
+				fmt.Printf("  Limits: %s\n", limits)
+			}
 
 			return nil
 		},
@@ -1253,6 +3477,9 @@ func operationCreateCmd() *cobra.Command {
 
 	cmd.Flags().StringSliceVar(&jobs, "jobs", nil, "Comma-separated job IDs")
 	cmd.Flags().StringVarP(&description, "description", "d", "", "Operation description")
+	cmd.Flags().IntVar(&maxParallel, "max-parallel", 0, "Maximum jobs running at once (0 for no limit)")
+	cmd.Flags().BoolVar(&sequential, "sequential", false, "Run jobs one at a time in the order given")
+	cmd.Flags().BoolVar(&stopOnFail, "stop-on-fail", false, "Cancel the remaining jobs when one fails")
 
 	return cmd
 }
@@ -1263,7 +3490,7 @@ func operationStatusCmd() *cobra.Command {
 		Short: "Show operation status",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running")
 			}
@@ -1283,12 +3510,19 @@ func operationStatusCmd() *cobra.Command {
 			var info protocol.OperationInfo
 			json.Unmarshal(resp.Result, &info)
 
+			if structuredOutput() {
+				return printStructured(info)
+			}
+
 			fmt.Printf("Operation: %s\n", info.Name)
 			fmt.Printf("  ID:        %s\n", info.ID)
 			fmt.Printf("  Status:    %s\n", info.Status)
 			fmt.Printf("  Progress:  %d%%\n", info.Progress)
 			fmt.Printf("  Jobs:      %d total, %d completed, %d failed\n",
 				info.TotalJobs, info.CompletedJobs, info.FailedJobs)
+			if limits := operationLimits(info); limits != "" {
+				fmt.Printf("  Limits:    %s\n", limits)
+			}
 			if info.Description != "" {
 				fmt.Printf("  Description: %s\n", info.Description)
 			}
@@ -1298,13 +3532,27 @@ func operationStatusCmd() *cobra.Command {
 	}
 }
 
+// operationLimits describes how an operation's jobs are scheduled.
+func operationLimits(info protocol.OperationInfo) string {
+	var limits []string
+	if info.Sequential {
+		limits = append(limits, "sequential")
+	} else if info.MaxParallel > 0 {
+		limits = append(limits, fmt.Sprintf("max %d parallel", info.MaxParallel))
+	}
+	if info.StopOnFail {
+		limits = append(limits, "stop on fail")
+	}
+	return strings.Join(limits, ", ")
+}
+
 func operationListCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:     "list",
 		Short:   "List all operations",
 		Aliases: []string{"ls"},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running")
 			}
@@ -1322,24 +3570,23 @@ func operationListCmd() *cobra.Command {
 			var result protocol.OperationListResult
 			json.Unmarshal(resp.Result, &result)
 
+			if structuredOutput() {
+				return printStructured(result.Operations)
+			}
+
 			if len(result.Operations) == 0 {
 				fmt.Println("No operations")
 				return nil
 			}
 
-			fmt.Printf("%-10s %-20s %-12s %-8s %s\n", "ID", "NAME", "STATUS", "PROGRESS", "JOBS")
+			table := NewTable("ID", "NAME", "STATUS", "PROGRESS", "JOBS")
 			for _, op := range result.Operations {
-				id := op.ID
-				if len(id) > 8 {
-					id = id[:8]
-				}
-				name := op.Name
-				if len(name) > 18 {
-					name = name[:18] + ".."
-				}
-				fmt.Printf("%-10s %-20s %-12s %6d%%  %d/%d\n",
-					id, name, op.Status, op.Progress, op.CompletedJobs, op.TotalJobs)
+				id := display.ShortID(op.ID)
+				name := truncate(op.Name, 20)
+				table.AddRow(id, name, op.Status, fmt.Sprintf("%d%%", op.Progress),
+					fmt.Sprintf("%d/%d", op.CompletedJobs, op.TotalJobs))
 			}
+			table.Print()
 
 			return nil
 		},
@@ -1352,7 +3599,7 @@ func operationCancelCmd() *cobra.Command {
 		Short: "Cancel an operation",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running")
 			}
@@ -1393,21 +3640,105 @@ func orderCmd() *cobra.Command {
 	return cmd
 }
 
+// orderScope builds the scope of an order command from its --global and
+// --role flags, or else its worker argument. It returns the arguments left
+// after the worker.
+func orderScope(global bool, role string, args []string) (protocol.OrderScope, []string, error) {
+	switch {
+	case global && role != "":
+		return protocol.OrderScope{}, nil, fmt.Errorf("--global and --role cannot be combined")
+	case global:
+		return protocol.OrderScope{Global: true}, args, nil
+	case role != "":
+		return protocol.OrderScope{Role: role}, args, nil
+	case len(args) == 0:
+		return protocol.OrderScope{}, nil, fmt.Errorf("a worker, --global or --role is required")
+	}
+	return protocol.OrderScope{Worker: args[0]}, args[1:], nil
+}
+
+// orderScopeName describes the scope of an order list result.
+func orderScopeName(result protocol.OrderListResult) string {
+	switch {
+	case result.Global:
+		return "all workers"
+	case result.Worker == "":
+		return "the " + result.Role + " role"
+	}
+	return result.Worker
+}
+
+// printOrders prints an order list result. A worker's own orders are
+// followed by the orders it inherits.
+func printOrders(result protocol.OrderListResult) {
+	if len(result.Orders) == 0 {
+		fmt.Printf("No standing orders for %s\n", orderScopeName(result))
+	} else {
+		fmt.Printf("Standing orders for %s:\n", orderScopeName(result))
+		for i, order := range result.Orders {
+			fmt.Printf("  %d. %s\n", i+1, order)
+		}
+	}
+
+	if result.Worker == "" {
+		return
+	}
+	if len(result.GlobalOrders) > 0 {
+		fmt.Println("\nFrom the global orders:")
+		for _, order := range result.GlobalOrders {
+			fmt.Printf("  - %s\n", order)
+		}
+	}
+	if len(result.RoleOrders) > 0 {
+		fmt.Printf("\nFrom the %s role orders:\n", result.Role)
+		for _, order := range result.RoleOrders {
+			fmt.Printf("  - %s\n", order)
+		}
+	}
+}
+
+// addOrderScopeFlags adds the --global and --role flags of order commands.
+func addOrderScopeFlags(cmd *cobra.Command, global *bool, role *string) {
+	cmd.Flags().BoolVar(global, "global", false, "Standing orders for every worker (saved in the config)")
+	cmd.Flags().StringVar(role, "role", "", "Standing orders for every worker of a role (saved in the territory)")
+}
+
 func orderSetCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "set <worker> <order>...",
-		Short: "Set standing orders for a worker",
-		Args:  cobra.MinimumNArgs(2),
+	var global bool
+	var role string
+
+	cmd := &cobra.Command{
+		Use:   "set [worker] <order>...",
+		Short: "Set standing orders for a worker, a role or every worker",
+		Long: `Set standing orders, replacing those already set for the same scope.
+
+A worker's prompt lists the global orders first, then the orders for its
+role in the territory, then its own, so the most specific orders come
+last. An order given in several scopes is listed once.
+
+Examples:
+  cosa order set tony "Run the linter before committing"
+  cosa order set --role soldato "Keep commits small"
+  cosa order set --global "Never push to main"`,
+		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			scope, orders, err := orderScope(global, role, args)
+			if err != nil {
+				return err
+			}
+			if len(orders) == 0 {
+				return fmt.Errorf("no orders given (use 'cosa order clear' to remove orders)")
+			}
+
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running")
 			}
 			defer client.Close()
 
 			resp, err := client.Call(protocol.MethodOrderSet, protocol.OrderSetParams{
-				Worker: args[0],
-				Orders: args[1:],
+				OrderScope: scope,
+				Orders:     orders,
 			})
 			if err != nil {
 				return err
@@ -1420,31 +3751,39 @@ func orderSetCmd() *cobra.Command {
 			var result protocol.OrderListResult
 			json.Unmarshal(resp.Result, &result)
 
-			fmt.Printf("Standing orders for %s:\n", result.Worker)
-			for i, order := range result.Orders {
-				fmt.Printf("  %d. %s\n", i+1, order)
-			}
-
+			printOrders(result)
 			return nil
 		},
 	}
+
+	addOrderScopeFlags(cmd, &global, &role)
+
+	return cmd
 }
 
 func orderListCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:     "list <worker>",
-		Short:   "List standing orders for a worker",
+	var global bool
+	var role string
+
+	cmd := &cobra.Command{
+		Use:     "list [worker]",
+		Short:   "List standing orders for a worker, a role or every worker",
 		Aliases: []string{"ls"},
-		Args:    cobra.ExactArgs(1),
+		Args:    cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			scope, _, err := orderScope(global, role, args)
+			if err != nil {
+				return err
+			}
+
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running")
 			}
 			defer client.Close()
 
 			resp, err := client.Call(protocol.MethodOrderList, protocol.OrderListParams{
-				Worker: args[0],
+				OrderScope: scope,
 			})
 			if err != nil {
 				return err
@@ -1457,35 +3796,42 @@ func orderListCmd() *cobra.Command {
 			var result protocol.OrderListResult
 			json.Unmarshal(resp.Result, &result)
 
-			if len(result.Orders) == 0 {
-				fmt.Printf("No standing orders for %s\n", result.Worker)
-				return nil
-			}
-
-			fmt.Printf("Standing orders for %s:\n", result.Worker)
-			for i, order := range result.Orders {
-				fmt.Printf("  %d. %s\n", i+1, order)
+			if structuredOutput() {
+				return printStructured(result)
 			}
 
+			printOrders(result)
 			return nil
 		},
 	}
+
+	addOrderScopeFlags(cmd, &global, &role)
+
+	return cmd
 }
 
 func orderClearCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "clear <worker>",
-		Short: "Clear all standing orders for a worker",
-		Args:  cobra.ExactArgs(1),
+	var global bool
+	var role string
+
+	cmd := &cobra.Command{
+		Use:   "clear [worker]",
+		Short: "Clear all standing orders for a worker, a role or every worker",
+		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			scope, _, err := orderScope(global, role, args)
+			if err != nil {
+				return err
+			}
+
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running")
 			}
 			defer client.Close()
 
 			resp, err := client.Call(protocol.MethodOrderClear, protocol.OrderClearParams{
-				Worker: args[0],
+				OrderScope: scope,
 			})
 			if err != nil {
 				return err
@@ -1495,10 +3841,17 @@ func orderClearCmd() *cobra.Command {
 				return fmt.Errorf("%s", resp.Error.Message)
 			}
 
-			fmt.Printf("Standing orders cleared for %s\n", args[0])
+			var result protocol.OrderListResult
+			json.Unmarshal(resp.Result, &result)
+
+			fmt.Printf("Standing orders cleared for %s\n", orderScopeName(result))
 			return nil
 		},
 	}
+
+	addOrderScopeFlags(cmd, &global, &role)
+
+	return cmd
 }
 
 // Logs command
@@ -1507,12 +3860,83 @@ func logsCmd() *cobra.Command {
 	var workerFilter string
 	var follow bool
 	var count int
+	var since string
+	var until string
+	var types []string
+	var excludeTypes []string
+	var jobFilter string
+	var grep string
+	var format string
+	var daemonLog bool
+	var level string
 
 	cmd := &cobra.Command{
 		Use:   "logs",
 		Short: "Stream activity log",
+		Long: `Show recent ledger events, or stream new ones with --follow.
+
+--since and --until take a duration ago (30m, 2h, 7d), a date (2006-01-02)
+or an RFC 3339 time. --type and --exclude-type match event types; "job.*"
+matches a prefix. --grep matches a regular expression against each event's
+type and data. --level hides events below a severity: failures and errors
+are error; stuck workers, rejections and merge conflicts are warn; the rest
+are info. Filters combine: an event is shown if it passes all of them.
+When following, the daemon applies the filters, so only matching events are
+sent.
+
+--format pretty shows every field, compact one short line per event and
+jsonl one JSON object per line, for piping into other tools.
+
+--daemon shows the daemon's own log instead: operational errors and, at
+log_level debug, every request. It is read from the data directory, so it
+works when the daemon is not running. --level applies to its records too.
+
+Examples:
+  cosa logs --since 2h --type job.failed
+  cosa logs --worker paulie --type "job.*" -n 20
+  cosa logs -f --exclude-type "claude.*" --format compact
+  cosa logs --job 3f2a9c1e --grep "timeout|conflict"
+  cosa logs --level error --since 1d
+  cosa logs --daemon --level warn --since 1d`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			if daemonLog {
+				if workerFilter != "" || len(types) > 0 || len(excludeTypes) > 0 || jobFilter != "" || grep != "" {
+					return fmt.Errorf("--worker, --type, --exclude-type, --job and --grep do not apply to the daemon log")
+				}
+				filter, err := newDaemonLogFilter(level, since, until)
+				if err != nil {
+					return err
+				}
+				if follow {
+					return followDaemonLog(cfg.DaemonLogPath(), filter)
+				}
+				return showDaemonLog(cfg.DaemonLogPath(), count, filter)
+			}
+			if level != "" {
+				if _, err := ledger.ParseSeverity(level); err != nil {
+					return fmt.Errorf("invalid --level: %w", err)
+				}
+			}
+			switch format {
+			case logFormatPretty, logFormatCompact, logFormatJSONL:
+			default:
+				return fmt.Errorf("invalid --format %q: use pretty, compact or jsonl", format)
+			}
+
+			filter := &protocol.LogFilter{
+				Types:   splitTypes(types),
+				Exclude: splitTypes(excludeTypes),
+				Worker:  workerFilter,
+				Job:     jobFilter,
+				Grep:    grep,
+				Level:   level,
+			}
+			// Checked here so that a bad pattern fails before connecting
+			if _, err := daemon.LogFilterQuery(filter); err != nil {
+				return fmt.Errorf("invalid --grep: %w", err)
+			}
+
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running")
 			}
@@ -1520,33 +3944,84 @@ func logsCmd() *cobra.Command {
 
 			if follow {
 				// Subscribe to real-time events
-				return streamLogs(client, workerFilter)
+				return streamLogs(client, filter, format)
+			}
+
+			params := protocol.LedgerQueryParams{
+				Worker: workerFilter,
+				Types:  filter.Types,
+				Limit:  count,
+				Level:  level,
+			}
+			if since != "" {
+				t, err := parseLogTime(since)
+				if err != nil {
+					return fmt.Errorf("invalid --since: %w", err)
+				}
+				params.Since = t.Unix()
+			}
+			if until != "" {
+				t, err := parseLogTime(until)
+				if err != nil {
+					return fmt.Errorf("invalid --until: %w", err)
+				}
+				params.Until = t.Unix()
 			}
 
 			// Read historical logs from ledger
-			return showRecentLogs(count, workerFilter)
+			return showRecentLogs(client, params, filter, format)
 		},
 	}
 
-	cmd.Flags().StringVarP(&workerFilter, "worker", "w", "", "Filter by worker name")
+	cmd.Flags().StringVarP(&workerFilter, "worker", "w", "", "Filter by worker name or ID")
 	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Follow log output")
 	cmd.Flags().IntVarP(&count, "count", "n", 50, "Number of recent events to show")
+	cmd.Flags().StringVar(&since, "since", "", "Show events since a time or duration ago (e.g. 2h)")
+	cmd.Flags().StringVar(&until, "until", "", "Show events before a time or duration ago")
+	cmd.Flags().StringArrayVarP(&types, "type", "t", nil, "Filter by event type (repeatable, e.g. job.failed or job.*)")
+	cmd.Flags().StringArrayVar(&excludeTypes, "exclude-type", nil, "Leave out an event type (repeatable, e.g. tool_use or claude.*)")
+	cmd.Flags().StringVar(&jobFilter, "job", "", "Only show events about this job (ID or prefix)")
+	cmd.Flags().StringVar(&grep, "grep", "", "Only show events whose type or data match this regular expression")
+	cmd.Flags().StringVar(&format, "format", logFormatPretty, "Event format: pretty, compact or jsonl")
+	cmd.Flags().BoolVar(&daemonLog, "daemon", false, "Show the daemon log instead of the activity ledger")
+	cmd.Flags().StringVar(&level, "level", "", "Lowest severity to show: info, warn or error (debug too with --daemon)")
 
 	return cmd
 }
 
-func streamLogs(client *daemon.Client, workerFilter string) error {
-	// Subscribe to events
-	events := []string{"*"}
-	if workerFilter != "" {
-		events = []string{"worker.started", "worker.stopped", "job.started", "job.completed", "job.failed"}
+// Formats for cosa logs
+const (
+	logFormatPretty  = "pretty"
+	logFormatCompact = "compact"
+	logFormatJSONL   = "jsonl"
+)
+
+// logPageSize is how many events cosa logs reads at a time while filtering
+// the ledger itself.
+const logPageSize = 500
+
+// splitTypes flattens repeated, comma-separated event type flags.
+func splitTypes(values []string) []string {
+	var types []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				types = append(types, part)
+			}
+		}
 	}
+	return types
+}
 
-	if err := client.Subscribe(events); err != nil {
+func streamLogs(client *daemon.Client, filter *protocol.LogFilter, format string) error {
+	// The daemon filters, so a busy ledger doesn't flood the connection
+	if err := client.SubscribeFiltered([]string{"*"}, filter); err != nil {
 		return fmt.Errorf("failed to subscribe: %w", err)
 	}
 
-	fmt.Println("Streaming logs (Ctrl+C to stop)...")
+	if !structuredOutput() && format != logFormatJSONL {
+		fmt.Println("Streaming logs (Ctrl+C to stop)...")
+	}
 
 	for {
 		event, err := client.ReadEvent()
@@ -1554,125 +4029,172 @@ func streamLogs(client *daemon.Client, workerFilter string) error {
 			return err
 		}
 
-		// Apply worker filter if specified
-		if workerFilter != "" {
-			// Check if event data contains worker name
-			var data map[string]interface{}
-			json.Unmarshal(event.Data, &data)
-			if name, ok := data["name"].(string); ok {
-				if name != workerFilter {
-					continue
-				}
+		if structuredOutput() {
+			if err := printStreamEvent(event); err != nil {
+				return err
 			}
+			continue
 		}
 
-		// Format and print event
-		ts := event.Timestamp.Format("15:04:05")
-		fmt.Printf("[%s] %s", ts, event.Type)
-
-		var data map[string]interface{}
-		if json.Unmarshal(event.Data, &data) == nil {
-			if name, ok := data["name"]; ok {
-				fmt.Printf(" worker=%s", name)
-			}
-			if id, ok := data["id"]; ok {
-				idStr := fmt.Sprint(id)
-				if len(idStr) > 8 {
-					idStr = idStr[:8]
-				}
-				fmt.Printf(" id=%s", idStr)
-			}
-			if errMsg, ok := data["error"]; ok {
-				fmt.Printf(" error=%s", errMsg)
-			}
+		if err := printLogEvent(protocol.LedgerEvent(*event), format); err != nil {
+			return err
 		}
-		fmt.Println()
 	}
 }
 
-func showRecentLogs(count int, workerFilter string) error {
-	// Read from ledger file
-	events, err := readLedgerTail(cfg.LedgerPath(), count)
+func showRecentLogs(client *daemon.Client, params protocol.LedgerQueryParams, filter *protocol.LogFilter, format string) error {
+	events, err := readLogs(client, params, filter)
 	if err != nil {
-		return fmt.Errorf("failed to read ledger: %w", err)
+		return err
+	}
+
+	if structuredOutput() {
+		return printStructured(events)
 	}
 
 	for _, event := range events {
-		// Apply worker filter if specified
-		if workerFilter != "" {
-			var data map[string]interface{}
-			json.Unmarshal(event.Data, &data)
-			if name, ok := data["name"].(string); ok {
-				if name != workerFilter {
-					continue
-				}
-			}
+		if err := printLogEvent(event, format); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readLogs reads the newest params.Limit events that pass filter. The
+// daemon filters by type and worker; the rest of the filter is applied
+// here, reading further back a page at a time until enough events match.
+func readLogs(client *daemon.Client, params protocol.LedgerQueryParams, filter *protocol.LogFilter) ([]protocol.LedgerEvent, error) {
+	want := params.Limit
+	local := len(filter.Exclude) > 0 || filter.Job != "" || filter.Grep != ""
+	if local {
+		params.Limit = max(want, logPageSize)
+	}
+	q, err := daemon.LogFilterQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []protocol.LedgerEvent
+	for {
+		resp, err := client.Call(protocol.MethodLedgerQuery, params)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Error != nil {
+			return nil, fmt.Errorf("%s", resp.Error.Message)
 		}
 
-		ts := event.Timestamp.Format("2006-01-02 15:04:05")
-		fmt.Printf("[%s] %s", ts, event.Type)
+		var result protocol.LedgerQueryResult
+		json.Unmarshal(resp.Result, &result)
+		if !local {
+			return result.Events, nil
+		}
 
-		var data map[string]interface{}
-		if json.Unmarshal(event.Data, &data) == nil {
-			for k, v := range data {
-				if k == "id" {
-					idStr := fmt.Sprint(v)
-					if len(idStr) > 8 {
-						idStr = idStr[:8]
-					}
-					fmt.Printf(" %s=%s", k, idStr)
-				} else if k != "" && v != nil && v != "" {
-					fmt.Printf(" %s=%v", k, v)
-				}
+		var page []protocol.LedgerEvent
+		for _, e := range result.Events {
+			if q.Matches(ledger.Event{ID: e.ID, Type: ledger.EventType(e.Type), Timestamp: e.Timestamp, Data: e.Data}) {
+				page = append(page, e)
 			}
 		}
-		fmt.Println()
+		events = append(page, events...)
+
+		if len(events) >= want || !result.More || len(result.Events) == 0 {
+			break
+		}
+		params.Before = result.Events[0].ID
+	}
+
+	if len(events) > want {
+		events = events[len(events)-want:]
+	}
+	return events, nil
+}
+
+// printLogEvent prints a ledger event in the given format.
+func printLogEvent(event protocol.LedgerEvent, format string) error {
+	if format == logFormatJSONL {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to encode event: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	var data map[string]interface{}
+	json.Unmarshal(event.Data, &data)
+	keys := make([]string, 0, len(data))
+	for k, v := range data {
+		if k != "" && v != nil && v != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	if format == logFormatCompact {
+		fmt.Printf("%s %s %s\n", event.Timestamp.Local().Format("15:04:05"), event.Type, compactLogData(data))
+		return nil
 	}
 
+	ts := event.Timestamp.Local().Format("2006-01-02 15:04:05")
+	fmt.Printf("[%s] %s", ts, event.Type)
+	for _, k := range keys {
+		v := fmt.Sprint(data[k])
+		if f, ok := data[k].(float64); ok && f == float64(int64(f)) {
+			v = strconv.FormatInt(int64(f), 10) // Not 1.7e+09
+		} else if k == "id" {
+			v = display.ShortID(v)
+		}
+		fmt.Printf(" %s=%s", k, v)
+	}
+	fmt.Println()
 	return nil
 }
 
-func readLedgerTail(path string, n int) ([]daemon.LedgerEvent, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
+// compactLogData summarizes an event's data in a few words: who, which job
+// and what happened.
+func compactLogData(data map[string]interface{}) string {
+	var parts []string
+	for _, k := range []string{"worker_name", "name", "worker"} {
+		if v, ok := data[k].(string); ok && v != "" {
+			parts = append(parts, v)
+			break
 		}
-		return nil, err
-	}
-
-	lines := splitLines(string(data))
-	if len(lines) > n {
-		lines = lines[len(lines)-n:]
 	}
-
-	var events []daemon.LedgerEvent
-	for _, line := range lines {
-		if line == "" {
-			continue
+	for _, k := range []string{"job_id", "id", "job"} {
+		if v, ok := data[k].(string); ok && v != "" {
+			parts = append(parts, display.ShortID(v))
+			break
 		}
-		var event daemon.LedgerEvent
-		if err := json.Unmarshal([]byte(line), &event); err == nil {
-			events = append(events, event)
+	}
+	for _, k := range []string{"error", "message", "description", "reason"} {
+		if v, ok := data[k].(string); ok && v != "" {
+			parts = append(parts, truncate(strings.Join(strings.Fields(v), " "), 60))
+			break
 		}
 	}
-
-	return events, nil
+	return strings.Join(parts, " ")
 }
 
-func splitLines(s string) []string {
-	var lines []string
-	start := 0
-	for i := 0; i < len(s); i++ {
-		if s[i] == '\n' {
-			lines = append(lines, s[start:i])
-			start = i + 1
+// parseLogTime parses a --since/--until value: a duration ago (with a "d"
+// suffix for days), a date or an RFC 3339 time.
+func parseLogTime(value string) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil {
+			return time.Now().AddDate(0, 0, -n), nil
 		}
 	}
-	if start < len(s) {
-		lines = append(lines, s[start:])
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
 	}
-	return lines
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not a duration, date or RFC 3339 time", value)
 }
 
 // Settings command
@@ -1688,25 +4210,17 @@ func settingsCmd() *cobra.Command {
 		settingsListCmd(),
 		settingsGetCmd(),
 		settingsSetCmd(),
+		settingsApplyCmd(),
 		settingsPathCmd(),
+		settingsReloadCmd(),
+		settingsNotifyTestCmd(),
 	)
 
 	return cmd
 }
 
 func getConfigPath() string {
-	homeDir, _ := os.UserHomeDir()
-	candidates := []string{
-		filepath.Join(homeDir, ".cosa", "config.yaml"),
-		filepath.Join(homeDir, ".config", "cosa", "config.yaml"),
-	}
-	for _, candidate := range candidates {
-		if _, err := os.Stat(candidate); err == nil {
-			return candidate
-		}
-	}
-	// Default path (will be created if needed)
-	return filepath.Join(homeDir, ".cosa", "config.yaml")
+	return config.ProfileConfigPath(cfg.Profile)
 }
 
 func settingsPathCmd() *cobra.Command {
@@ -1719,12 +4233,74 @@ func settingsPathCmd() *cobra.Command {
 	}
 }
 
+func settingsReloadCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reload",
+		Short: "Make the daemon reload its config file",
+		Long: `Make the running daemon read its config file again. Changes to models,
+notifications, budgets and job timeouts apply at once; other changes are
+listed and wait for a daemon restart.
+
+The daemon also reloads its config when the file changes or on SIGHUP.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer c.Close()
+			cmd.SilenceUsage = true
+
+			resp, err := c.Call(protocol.MethodConfigReload, nil)
+			if err != nil {
+				return err
+			}
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var result protocol.ConfigReloadResult
+			json.Unmarshal(resp.Result, &result)
+
+			if structuredOutput() {
+				return printStructured(result)
+			}
+
+			if len(result.Applied) == 0 {
+				fmt.Println("No changes to apply")
+			}
+			for _, c := range result.Applied {
+				fmt.Printf("Applied %s: %s -> %s\n", c.Key, displayConfigValue(c.Old), displayConfigValue(c.New))
+			}
+			if len(result.Pending) > 0 {
+				fmt.Println("\nWaiting for a daemon restart:")
+				for _, c := range result.Pending {
+					fmt.Printf("  %s\n", c.Key)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// displayConfigValue shows a value from a config change, which is empty
+// when unset or hidden.
+func displayConfigValue(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
 func settingsListCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:     "list",
 		Short:   "List all settings with their current values",
 		Aliases: []string{"ls"},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if structuredOutput() {
+				return printSettings()
+			}
+
 			fmt.Println("Current settings:")
 			fmt.Println()
 
@@ -1733,30 +4309,155 @@ func settingsListCmd() *cobra.Command {
 			fmt.Printf("  log_level          = %s\n", cfg.LogLevel)
 			fmt.Printf("  socket_path        = %s\n", cfg.SocketPath)
 			fmt.Printf("  data_dir           = %s\n", cfg.DataDir)
+			fmt.Printf("  identity           = %s\n", cfg.ClientIdentity())
+			fmt.Println()
+
+			// Daemon settings
+			fmt.Println("Daemon:")
+			fmt.Printf("  daemon.idle_shutdown  = %s\n", formatIdleShutdown(cfg.Daemon.IdleShutdown))
+			fmt.Printf("  daemon.log_max_size   = %dMB\n", cfg.Daemon.LogMaxSize)
+			fmt.Printf("  daemon.log_max_files  = %d\n", cfg.Daemon.LogMaxFiles)
+			fmt.Printf("  (log file: %s)\n", cfg.DaemonLogPath())
+			fmt.Println()
+
+			// API settings
+			fmt.Println("API:")
+			fmt.Printf("  api.listen = %s\n", valueOrDefault(cfg.API.Listen, "(disabled)"))
+			fmt.Printf("  api.token  = %s\n", formatSecret(cfg.API.Token))
+			fmt.Println()
+
+			// Budget settings
+			fmt.Println("Budget:")
+			fmt.Printf("  budget.daily_usd      = %s\n", formatBudgetLimit(cfg.Budget.DailyUSD))
+			fmt.Printf("  budget.per_worker_usd = %s\n", formatBudgetLimit(cfg.Budget.PerWorkerUSD))
+			fmt.Printf("  budget.per_job_usd    = %s\n", formatBudgetLimit(cfg.Budget.PerJobUSD))
+			fmt.Println()
+
+			// Admission settings
+			fmt.Println("Admission:")
+			fmt.Printf("  admission.url         = %s\n", valueOrDefault(cfg.Admission.URL, "(disabled)"))
+			fmt.Printf("  admission.timeout     = %s\n", cfg.Admission.Timeout)
+			fmt.Printf("  admission.fail_closed = %t\n", cfg.Admission.FailClosed)
+			fmt.Printf("  admission.max_queued  = %d\n", cfg.Admission.MaxQueued)
+			fmt.Printf("  admission.rate_limit  = %d\n", cfg.Admission.RateLimit)
+			fmt.Println()
+
+			// Cleanup settings
+			fmt.Println("Cleanup:")
+			fmt.Printf("  cleanup.interval       = %s\n", cfg.Cleanup.Interval)
+			fmt.Printf("  cleanup.max_age        = %s\n", cfg.Cleanup.MaxAge)
+			fmt.Printf("  cleanup.max_disk_usage = %s\n", formatDiskQuota(cfg.Cleanup.MaxDiskUsage))
+			fmt.Println()
+
+			// Encryption settings
+			fmt.Println("Encryption (sessions and transcripts at rest):")
+			fmt.Printf("  encryption.enabled          = %t\n", cfg.Encryption.Enabled)
+			fmt.Printf("  encryption.keychain_service = %s\n", valueOrDefault(cfg.Encryption.KeychainService, claude.DefaultKeychainService))
 			fmt.Println()
 
 			// Claude settings
 			fmt.Println("Claude:")
-			fmt.Printf("  claude.binary      = %s\n", cfg.Claude.Binary)
-			fmt.Printf("  claude.model       = %s\n", valueOrDefault(cfg.Claude.Model, "(default)"))
-			fmt.Printf("  claude.max_turns   = %d\n", cfg.Claude.MaxTurns)
+			fmt.Printf("  claude.binary             = %s\n", cfg.Claude.Binary)
+			fmt.Printf("  claude.model              = %s\n", valueOrDefault(cfg.Claude.Model, "(default)"))
+			fmt.Printf("  claude.max_turns          = %d\n", cfg.Claude.MaxTurns)
+			fmt.Printf("  claude.resume_max_commits = %d\n", cfg.Claude.ResumeMaxCommits)
+			fmt.Printf("  claude.resume_max_files   = %d\n", cfg.Claude.ResumeMaxFiles)
+			fmt.Printf("  claude.chat_drafts        = %t\n", cfg.Claude.ChatDrafts)
+			fmt.Printf("  claude.context.enabled    = %t\n", cfg.Claude.Context.Enabled)
+			fmt.Printf("  claude.context.max_files  = %d\n", cfg.Claude.Context.MaxFiles)
+			fmt.Printf("  claude.strict_output      = %t\n", cfg.Claude.StrictOutput)
+			fmt.Printf("  claude.fallback           = %s\n", valueOrDefault(fallbackText(cfg.Claude.Fallback), "(none)"))
 			fmt.Println()
 
 			// Worker settings
 			fmt.Println("Workers:")
-			fmt.Printf("  workers.max_concurrent = %d\n", cfg.Workers.MaxConcurrent)
-			fmt.Printf("  workers.default_role   = %s\n", cfg.Workers.DefaultRole)
+			fmt.Printf("  workers.max_concurrent   = %d\n", cfg.Workers.MaxConcurrent)
+			fmt.Printf("  workers.default_role     = %s\n", cfg.Workers.DefaultRole)
+			fmt.Printf("  workers.exit_interview   = %t\n", cfg.Workers.ExitInterview)
+			fmt.Printf("  workers.job_timeout      = %s\n", formatJobTimeout(cfg.Workers.JobTimeout))
+			fmt.Printf("  workers.timeout_retries  = %d\n", cfg.Workers.TimeoutRetries)
+			fmt.Printf("  workers.quarantine_after = %d\n", cfg.Workers.QuarantineAfter)
+			fmt.Println()
+
+			// Lookout settings
+			fmt.Println("Lookout (stuck worker detection and remediation):")
+			fmt.Printf("  lookout.remediation.warning  = %s\n", cfg.Lookout.Remediation.Warning)
+			fmt.Printf("  lookout.remediation.error    = %s\n", cfg.Lookout.Remediation.Error)
+			fmt.Printf("  lookout.remediation.critical = %s\n", cfg.Lookout.Remediation.Critical)
+			overrides := make([]string, 0, len(cfg.Lookout.Workers))
+			for name := range cfg.Lookout.Workers {
+				overrides = append(overrides, name)
+			}
+			sort.Strings(overrides)
+			for _, name := range overrides {
+				r := cfg.RemediationFor(name)
+				fmt.Printf("  (worker %s: warning=%s error=%s critical=%s)\n", name, r.Warning, r.Error, r.Critical)
+			}
+			fmt.Printf("  lookout.thresholds.warning   = %s\n", cfg.Lookout.Thresholds.Warning)
+			fmt.Printf("  lookout.thresholds.error     = %s\n", cfg.Lookout.Thresholds.Error)
+			fmt.Printf("  lookout.thresholds.critical  = %s\n", cfg.Lookout.Thresholds.Critical)
+			roles := make([]string, 0, len(cfg.Lookout.Roles))
+			for role := range cfg.Lookout.Roles {
+				roles = append(roles, role)
+			}
+			sort.Strings(roles)
+			for _, role := range roles {
+				t := cfg.ThresholdsFor(role)
+				fmt.Printf("  (role %s: warning=%s error=%s critical=%s)\n", role, t.Warning, t.Error, t.Critical)
+			}
 			fmt.Println()
 
 			// Git settings
 			fmt.Println("Git:")
-			fmt.Printf("  git.default_merge_branch = %s\n", valueOrDefault(cfg.Git.DefaultMergeBranch, "(repository default)"))
+			fmt.Printf("  git.default_merge_branch            = %s\n", valueOrDefault(cfg.Git.DefaultMergeBranch, "(repository default)"))
+			fmt.Printf("  git.assign_conflicts_to_consigliere = %t\n", cfg.Git.AssignConflictsToConsigliere)
+			fmt.Printf("  git.uncommitted_changes             = %s\n", valueOrDefault(cfg.Git.UncommittedChanges, "commit"))
+			fmt.Printf("  git.auto_commit_message             = %s\n", valueOrDefault(cfg.Git.AutoCommitMessage, "(default)"))
+			fmt.Printf("  git.stale_branch_threshold          = %d\n", cfg.Git.StaleBranchThreshold)
+			fmt.Printf("  git.stale_branch_interval           = %s\n", cfg.Git.StaleBranchInterval)
+			fmt.Printf("  git.remote_mode                     = %s\n", valueOrDefault(cfg.Git.RemoteMode, "local-merge"))
+			fmt.Printf("  git.remote                          = %s\n", valueOrDefault(cfg.Git.Remote, "origin"))
+			fmt.Printf("  git.pr_provider                     = %s\n", valueOrDefault(cfg.Git.PRProvider, "(detect from remote)"))
+			fmt.Printf("  git.pr_title                        = %s\n", valueOrDefault(cfg.Git.PRTitle, "{title}"))
+			fmt.Printf("  git.pr_template                     = %s\n", valueOrDefault(cfg.Git.PRTemplate, "(default)"))
+			fmt.Printf("  git.author                          = %s\n", valueOrDefault(cfg.Git.Author, "(repository config)"))
+			fmt.Printf("  git.signing_format                  = %s\n", valueOrDefault(cfg.Git.SigningFormat, "(repository config)"))
+			fmt.Printf("  git.signing_key                     = %s\n", valueOrDefault(cfg.Git.SigningKey, "(default)"))
+			fmt.Printf("  git.job_trailer                     = %t\n", cfg.Git.JobTrailer)
+			identities := make([]string, 0, len(cfg.Git.Workers))
+			for name := range cfg.Git.Workers {
+				identities = append(identities, name)
+			}
+			sort.Strings(identities)
+			for _, name := range identities {
+				id := cfg.GitIdentityFor(name)
+				fmt.Printf("  (worker %s: author=%s signing=%s)\n", name, valueOrDefault(id.Author, "-"), valueOrDefault(id.SigningFormat, "-"))
+			}
+			fmt.Println()
+
+			// Review settings
+			fmt.Println("Review:")
+			fmt.Printf("  review.diff_summarizer        = %s\n", valueOrDefault(cfg.Review.DiffSummarizer, "heuristic"))
+			fmt.Printf("  review.diff_summary_threshold = %d\n", cfg.Review.DiffSummaryThreshold)
+			fmt.Printf("  review.summary_model          = %s\n", valueOrDefault(cfg.Review.SummaryModel, "(claude default)"))
+			fmt.Printf("  review.max_rejections         = %d\n", cfg.Review.MaxRejections)
+			fmt.Printf("  review.max_rounds             = %d\n", cfg.Review.MaxRounds)
+			fmt.Printf("  review.classifier             = %s\n", valueOrDefault(cfg.Review.Classifier, config.ClassifierHeuristic))
+			var sampled []string
+			for outcome := range cfg.Review.Sampling {
+				sampled = append(sampled, outcome)
+			}
+			sort.Strings(sampled)
+			for _, outcome := range sampled {
+				fmt.Printf("  (sampling %s: %.0f%% reviewed)\n", outcome, cfg.Review.ReviewSampleRate(outcome)*100)
+			}
 			fmt.Println()
 
 			// TUI settings
 			fmt.Println("TUI:")
 			fmt.Printf("  tui.theme          = %s\n", cfg.TUI.Theme)
 			fmt.Printf("  tui.refresh_rate   = %d\n", cfg.TUI.RefreshRate)
+			fmt.Printf("  tui.layout         = %s\n", cfg.TUI.Layout)
 			fmt.Println()
 
 			// Notification settings
@@ -1785,6 +4486,20 @@ func settingsListCmd() *cobra.Command {
 	}
 }
 
+func formatIdleShutdown(d time.Duration) string {
+	if d <= 0 {
+		return "off"
+	}
+	return d.String()
+}
+
+func formatJobTimeout(d time.Duration) string {
+	if d <= 0 {
+		return "off"
+	}
+	return d.String()
+}
+
 func valueOrDefault(value, defaultVal string) string {
 	if value == "" {
 		return defaultVal
@@ -1792,6 +4507,23 @@ func valueOrDefault(value, defaultVal string) string {
 	return value
 }
 
+// formatSecret shows whether a secret is set without revealing it.
+func formatSecret(value string) string {
+	if value == "" {
+		return "(not set)"
+	}
+	return "(set)"
+}
+
+// generateAPIToken returns a random token for the REST API.
+func generateAPIToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
 func settingsGetCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "get <key>",
@@ -1818,6 +4550,56 @@ func getSettingValue(key string) (string, error) {
 		return cfg.SocketPath, nil
 	case "data_dir":
 		return cfg.DataDir, nil
+	case "identity":
+		return cfg.ClientIdentity(), nil
+
+	// Daemon
+	case "daemon.idle_shutdown":
+		return formatIdleShutdown(cfg.Daemon.IdleShutdown), nil
+	case "daemon.log_max_size":
+		return strconv.Itoa(cfg.Daemon.LogMaxSize), nil
+	case "daemon.log_max_files":
+		return strconv.Itoa(cfg.Daemon.LogMaxFiles), nil
+
+	// API
+	case "api.listen":
+		return cfg.API.Listen, nil
+	case "api.token":
+		return cfg.API.Token, nil
+
+	// Budget
+	case "budget.daily_usd":
+		return formatBudgetLimit(cfg.Budget.DailyUSD), nil
+	case "budget.per_worker_usd":
+		return formatBudgetLimit(cfg.Budget.PerWorkerUSD), nil
+	case "budget.per_job_usd":
+		return formatBudgetLimit(cfg.Budget.PerJobUSD), nil
+
+	// Admission
+	case "admission.url":
+		return cfg.Admission.URL, nil
+	case "admission.timeout":
+		return cfg.Admission.Timeout.String(), nil
+	case "admission.fail_closed":
+		return strconv.FormatBool(cfg.Admission.FailClosed), nil
+	case "admission.max_queued":
+		return strconv.Itoa(cfg.Admission.MaxQueued), nil
+	case "admission.rate_limit":
+		return strconv.Itoa(cfg.Admission.RateLimit), nil
+
+	// Cleanup
+	case "cleanup.interval":
+		return cfg.Cleanup.Interval.String(), nil
+	case "cleanup.max_age":
+		return cfg.Cleanup.MaxAge.String(), nil
+	case "cleanup.max_disk_usage":
+		return strconv.Itoa(cfg.Cleanup.MaxDiskUsage), nil
+
+	// Encryption
+	case "encryption.enabled":
+		return strconv.FormatBool(cfg.Encryption.Enabled), nil
+	case "encryption.keychain_service":
+		return cfg.Encryption.KeychainService, nil
 
 	// Claude
 	case "claude.binary":
@@ -1826,22 +4608,100 @@ func getSettingValue(key string) (string, error) {
 		return cfg.Claude.Model, nil
 	case "claude.max_turns":
 		return strconv.Itoa(cfg.Claude.MaxTurns), nil
+	case "claude.resume_max_commits":
+		return strconv.Itoa(cfg.Claude.ResumeMaxCommits), nil
+	case "claude.resume_max_files":
+		return strconv.Itoa(cfg.Claude.ResumeMaxFiles), nil
+	case "claude.chat_drafts":
+		return strconv.FormatBool(cfg.Claude.ChatDrafts), nil
+	case "claude.context.enabled":
+		return strconv.FormatBool(cfg.Claude.Context.Enabled), nil
+	case "claude.context.max_files":
+		return strconv.Itoa(cfg.Claude.Context.MaxFiles), nil
+	case "claude.strict_output":
+		return strconv.FormatBool(cfg.Claude.StrictOutput), nil
 
 	// Workers
 	case "workers.max_concurrent":
 		return strconv.Itoa(cfg.Workers.MaxConcurrent), nil
 	case "workers.default_role":
 		return cfg.Workers.DefaultRole, nil
+	case "workers.exit_interview":
+		return strconv.FormatBool(cfg.Workers.ExitInterview), nil
+	case "workers.job_timeout":
+		return formatJobTimeout(cfg.Workers.JobTimeout), nil
+	case "workers.timeout_retries":
+		return strconv.Itoa(cfg.Workers.TimeoutRetries), nil
+	case "workers.quarantine_after":
+		return strconv.Itoa(cfg.Workers.QuarantineAfter), nil
+
+	// Lookout
+	case "lookout.remediation.warning":
+		return cfg.Lookout.Remediation.Warning, nil
+	case "lookout.remediation.error":
+		return cfg.Lookout.Remediation.Error, nil
+	case "lookout.remediation.critical":
+		return cfg.Lookout.Remediation.Critical, nil
+	case "lookout.thresholds.warning":
+		return cfg.Lookout.Thresholds.Warning.String(), nil
+	case "lookout.thresholds.error":
+		return cfg.Lookout.Thresholds.Error.String(), nil
+	case "lookout.thresholds.critical":
+		return cfg.Lookout.Thresholds.Critical.String(), nil
 
 	// Git
 	case "git.default_merge_branch":
 		return cfg.Git.DefaultMergeBranch, nil
+	case "git.assign_conflicts_to_consigliere":
+		return strconv.FormatBool(cfg.Git.AssignConflictsToConsigliere), nil
+	case "git.uncommitted_changes":
+		return cfg.Git.UncommittedChanges, nil
+	case "git.auto_commit_message":
+		return cfg.Git.AutoCommitMessage, nil
+	case "git.stale_branch_threshold":
+		return strconv.Itoa(cfg.Git.StaleBranchThreshold), nil
+	case "git.stale_branch_interval":
+		return cfg.Git.StaleBranchInterval.String(), nil
+	case "git.remote_mode":
+		return cfg.Git.RemoteMode, nil
+	case "git.remote":
+		return cfg.Git.Remote, nil
+	case "git.pr_provider":
+		return cfg.Git.PRProvider, nil
+	case "git.pr_title":
+		return cfg.Git.PRTitle, nil
+	case "git.pr_template":
+		return cfg.Git.PRTemplate, nil
+	case "git.author":
+		return cfg.Git.Author, nil
+	case "git.signing_format":
+		return cfg.Git.SigningFormat, nil
+	case "git.signing_key":
+		return cfg.Git.SigningKey, nil
+	case "git.job_trailer":
+		return strconv.FormatBool(cfg.Git.JobTrailer), nil
+
+	// Review
+	case "review.diff_summarizer":
+		return cfg.Review.DiffSummarizer, nil
+	case "review.diff_summary_threshold":
+		return strconv.Itoa(cfg.Review.DiffSummaryThreshold), nil
+	case "review.summary_model":
+		return cfg.Review.SummaryModel, nil
+	case "review.max_rejections":
+		return strconv.Itoa(cfg.Review.MaxRejections), nil
+	case "review.max_rounds":
+		return strconv.Itoa(cfg.Review.MaxRounds), nil
+	case "review.classifier":
+		return cfg.Review.Classifier, nil
 
 	// TUI
 	case "tui.theme":
 		return cfg.TUI.Theme, nil
 	case "tui.refresh_rate":
 		return strconv.Itoa(cfg.TUI.RefreshRate), nil
+	case "tui.layout":
+		return cfg.TUI.Layout, nil
 
 	// Notifications
 	case "notifications.tui_alerts":
@@ -1891,8 +4751,10 @@ Examples:
   cosa settings set workers.max_concurrent 10
   cosa settings set notifications.terminal_bell true
   cosa settings set models.soldato opus
+  cosa settings set api.token generate
 
-Note: Some settings require restarting the daemon to take effect.`,
+Note: A running daemon picks up changes to models, notifications, budgets
+and job timeouts; other settings require restarting it to take effect.`,
 		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			key := strings.ToLower(args[0])
@@ -1902,79 +4764,433 @@ Note: Some settings require restarting the daemon to take effect.`,
 				return err
 			}
 
-			// Save to config file
-			configPath := getConfigPath()
-			if err := cfg.Save(configPath); err != nil {
-				return fmt.Errorf("failed to save config: %w", err)
-			}
+			// Save to config file
+			configPath := getConfigPath()
+			if err := cfg.Save(configPath); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+
+			if key == "api.token" {
+				value = cfg.API.Token // Show a generated token
+			}
+			fmt.Printf("Set %s = %s\n", key, value)
+			fmt.Printf("Config saved to %s\n", configPath)
+
+			// Warn about daemon restart if needed
+			if needsDaemonRestart(key) {
+				fmt.Println("\nNote: Restart the daemon for this change to take effect.")
+			}
+
+			return nil
+		},
+	}
+}
+
+func setSettingValue(key, value string) error {
+	switch key {
+	// Core
+	case "log_level":
+		if !contains(logging.Levels, value) {
+			return fmt.Errorf("invalid log_level: %s (must be one of: %s)", value, strings.Join(logging.Levels, ", "))
+		}
+		cfg.LogLevel = value
+
+	case "socket_path":
+		cfg.SocketPath = value
+
+	case "data_dir":
+		cfg.DataDir = value
+
+	case "identity":
+		cfg.Identity = strings.TrimSpace(value)
+
+	// Daemon
+	case "daemon.idle_shutdown":
+		if value == "off" || value == "0" {
+			cfg.Daemon.IdleShutdown = 0
+			break
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d < time.Minute {
+			return fmt.Errorf("invalid idle_shutdown: %s (must be a duration of at least 1m, or 'off')", value)
+		}
+		cfg.Daemon.IdleShutdown = d
+
+	case "daemon.log_max_size":
+		n, err := strconv.Atoi(strings.TrimSuffix(strings.ToUpper(value), "MB"))
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid log_max_size: %s (must be a size in megabytes, or 0 to never rotate)", value)
+		}
+		cfg.Daemon.LogMaxSize = n
+
+	case "daemon.log_max_files":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid log_max_files: %s (must be a non-negative integer)", value)
+		}
+		cfg.Daemon.LogMaxFiles = n
+
+	// API
+	case "api.listen":
+		if value != "" {
+			if _, _, err := net.SplitHostPort(value); err != nil {
+				return fmt.Errorf("invalid listen address: %s (must be host:port, such as 127.0.0.1:7420)", value)
+			}
+		}
+		cfg.API.Listen = value
+
+	case "api.token":
+		if value == "generate" {
+			token, err := generateAPIToken()
+			if err != nil {
+				return err
+			}
+			value = token
+		}
+		cfg.API.Token = value
+
+	// Budget
+	case "budget.daily_usd", "budget.per_worker_usd", "budget.per_job_usd":
+		limit, err := parseBudgetLimit(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %s (must be a dollar amount, or 'off')", strings.TrimPrefix(key, "budget."), value)
+		}
+		switch key {
+		case "budget.daily_usd":
+			cfg.Budget.DailyUSD = limit
+		case "budget.per_worker_usd":
+			cfg.Budget.PerWorkerUSD = limit
+		case "budget.per_job_usd":
+			cfg.Budget.PerJobUSD = limit
+		}
+
+	// Admission
+	case "admission.url":
+		if value != "" {
+			u, err := url.Parse(value)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("invalid admission url: %s (must be an http or https URL, or empty to disable)", value)
+			}
+		}
+		cfg.Admission.URL = value
+
+	case "admission.timeout":
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid admission timeout: %s (must be a positive duration, e.g. 5s)", value)
+		}
+		cfg.Admission.Timeout = d
+
+	case "admission.fail_closed":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean: %s (use true/false)", value)
+		}
+		cfg.Admission.FailClosed = b
+
+	case "admission.max_queued", "admission.rate_limit":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid %s: %s (must be a non-negative integer, 0 = no limit)", strings.TrimPrefix(key, "admission."), value)
+		}
+		if key == "admission.max_queued" {
+			cfg.Admission.MaxQueued = n
+		} else {
+			cfg.Admission.RateLimit = n
+		}
+
+	// Cleanup
+	case "cleanup.interval":
+		d, err := time.ParseDuration(value)
+		if err != nil || d < time.Minute {
+			return fmt.Errorf("invalid cleanup interval: %s (must be a duration of at least 1m)", value)
+		}
+		cfg.Cleanup.Interval = d
+
+	case "cleanup.max_age":
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid cleanup max_age: %s (must be a positive duration, e.g. 24h)", value)
+		}
+		cfg.Cleanup.MaxAge = d
+
+	case "cleanup.max_disk_usage":
+		if value == "off" {
+			value = "0"
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(strings.ToUpper(value), "MB"))
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid max_disk_usage: %s (must be a size in megabytes, or 0 for no limit)", value)
+		}
+		cfg.Cleanup.MaxDiskUsage = n
+
+	// Encryption
+	case "encryption.enabled":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean: %s (use true/false)", value)
+		}
+		// The daemon would not start without a key
+		if b {
+			if _, err := claude.LoadDataKey(cfg.Encryption.KeychainService); err != nil {
+				return err
+			}
+		}
+		cfg.Encryption.Enabled = b
+
+	case "encryption.keychain_service":
+		cfg.Encryption.KeychainService = value
+
+	// Claude
+	case "claude.binary":
+		cfg.Claude.Binary = value
+
+	case "claude.model":
+		cfg.Claude.Model = value
+
+	case "claude.max_turns":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid max_turns: %s (must be a positive integer)", value)
+		}
+		cfg.Claude.MaxTurns = n
+
+	case "claude.resume_max_commits", "claude.resume_max_files":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid %s: %s (must be a non-negative integer, 0 = no limit)", strings.TrimPrefix(key, "claude."), value)
+		}
+		if key == "claude.resume_max_commits" {
+			cfg.Claude.ResumeMaxCommits = n
+		} else {
+			cfg.Claude.ResumeMaxFiles = n
+		}
+
+	case "claude.chat_drafts":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean: %s (use true/false)", value)
+		}
+		cfg.Claude.ChatDrafts = b
+
+	case "claude.context.enabled":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean: %s (use true/false)", value)
+		}
+		cfg.Claude.Context.Enabled = b
+
+	case "claude.context.max_files":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid max_files: %s (must be a positive integer)", value)
+		}
+		cfg.Claude.Context.MaxFiles = n
+
+	case "claude.strict_output":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean: %s (use true/false)", value)
+		}
+		cfg.Claude.StrictOutput = b
+
+	// Workers
+	case "workers.max_concurrent":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid max_concurrent: %s (must be a positive integer)", value)
+		}
+		cfg.Workers.MaxConcurrent = n
+
+	case "workers.default_role":
+		validRoles := []string{"soldato", "capo", "consigliere", "underboss", "associate", "lookout", "cleaner"}
+		if !contains(validRoles, value) {
+			return fmt.Errorf("invalid default_role: %s (must be one of: %s)", value, strings.Join(validRoles, ", "))
+		}
+		cfg.Workers.DefaultRole = value
+
+	case "workers.exit_interview":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean: %s (use true/false)", value)
+		}
+		cfg.Workers.ExitInterview = b
+
+	case "workers.job_timeout":
+		if value == "off" || value == "0" {
+			cfg.Workers.JobTimeout = 0
+			break
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d < time.Minute {
+			return fmt.Errorf("invalid job_timeout: %s (must be a duration of at least 1m, or 'off')", value)
+		}
+		cfg.Workers.JobTimeout = d
+
+	case "workers.timeout_retries":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid timeout_retries: %s (must be a non-negative integer)", value)
+		}
+		cfg.Workers.TimeoutRetries = n
+
+	case "workers.quarantine_after":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid quarantine_after: %s (must be a number of failed jobs, 0 disables)", value)
+		}
+		cfg.Workers.QuarantineAfter = n
+
+	// Lookout
+	case "lookout.remediation.warning", "lookout.remediation.error", "lookout.remediation.critical":
+		validActions := []string{"notify", "nudge", "restart", "requeue"}
+		if !contains(validActions, value) {
+			return fmt.Errorf("invalid remediation: %s (must be one of: %s)", value, strings.Join(validActions, ", "))
+		}
+		switch key {
+		case "lookout.remediation.warning":
+			cfg.Lookout.Remediation.Warning = value
+		case "lookout.remediation.error":
+			cfg.Lookout.Remediation.Error = value
+		default:
+			cfg.Lookout.Remediation.Critical = value
+		}
+
+	case "lookout.thresholds.warning", "lookout.thresholds.error", "lookout.thresholds.critical":
+		d, err := time.ParseDuration(value)
+		if err != nil || d < time.Minute {
+			return fmt.Errorf("invalid threshold: %s (must be a duration of at least 1m)", value)
+		}
+		switch key {
+		case "lookout.thresholds.warning":
+			cfg.Lookout.Thresholds.Warning = d
+		case "lookout.thresholds.error":
+			cfg.Lookout.Thresholds.Error = d
+		default:
+			cfg.Lookout.Thresholds.Critical = d
+		}
+
+	// Git
+	case "git.default_merge_branch":
+		cfg.Git.DefaultMergeBranch = value
+
+	case "git.assign_conflicts_to_consigliere":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean: %s (use true/false)", value)
+		}
+		cfg.Git.AssignConflictsToConsigliere = b
+
+	case "git.uncommitted_changes":
+		if value != "commit" && value != "fail" {
+			return fmt.Errorf("invalid value: %s (use commit/fail)", value)
+		}
+		cfg.Git.UncommittedChanges = value
+
+	case "git.auto_commit_message":
+		cfg.Git.AutoCommitMessage = value
+
+	case "git.stale_branch_threshold":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid stale_branch_threshold: %s (must be a number of commits, 0 disables)", value)
+		}
+		cfg.Git.StaleBranchThreshold = n
+
+	case "git.stale_branch_interval":
+		d, err := time.ParseDuration(value)
+		if err != nil || d < time.Minute {
+			return fmt.Errorf("invalid stale_branch_interval: %s (must be a duration of at least 1m)", value)
+		}
+		cfg.Git.StaleBranchInterval = d
+
+	case "git.remote_mode":
+		if !contains(config.RemoteModes, value) {
+			return fmt.Errorf("invalid remote_mode: %s (must be one of: %s)", value, strings.Join(config.RemoteModes, ", "))
+		}
+		cfg.Git.RemoteMode = value
 
-			fmt.Printf("Set %s = %s\n", key, value)
-			fmt.Printf("Config saved to %s\n", configPath)
+	case "git.remote":
+		cfg.Git.Remote = value
 
-			// Warn about daemon restart if needed
-			if needsDaemonRestart(key) {
-				fmt.Println("\nNote: Restart the daemon for this change to take effect.")
-			}
+	case "git.pr_provider":
+		if value != "" && value != git.ProviderGitHub && value != git.ProviderGitLab {
+			return fmt.Errorf("invalid pr_provider: %s (use github/gitlab, or empty to detect)", value)
+		}
+		cfg.Git.PRProvider = value
 
-			return nil
-		},
-	}
-}
+	case "git.pr_title":
+		cfg.Git.PRTitle = value
 
-func setSettingValue(key, value string) error {
-	switch key {
-	// Core
-	case "log_level":
-		validLevels := []string{"debug", "info", "warn", "error"}
-		if !contains(validLevels, value) {
-			return fmt.Errorf("invalid log_level: %s (must be one of: %s)", value, strings.Join(validLevels, ", "))
+	case "git.pr_template":
+		cfg.Git.PRTemplate = value
+
+	case "git.author":
+		if value != "" {
+			if _, _, err := config.ParseAuthor(strings.ReplaceAll(value, "{worker}", "worker")); err != nil {
+				return err
+			}
 		}
-		cfg.LogLevel = value
+		cfg.Git.Author = value
 
-	case "socket_path":
-		cfg.SocketPath = value
+	case "git.signing_format":
+		if value != "" && !contains(config.SigningFormats, value) {
+			return fmt.Errorf("invalid signing_format: %s (use %s, or empty to leave it to git)", value, strings.Join(config.SigningFormats, "/"))
+		}
+		cfg.Git.SigningFormat = value
 
-	case "data_dir":
-		cfg.DataDir = value
+	case "git.signing_key":
+		cfg.Git.SigningKey = value
 
-	// Claude
-	case "claude.binary":
-		cfg.Claude.Binary = value
+	case "git.job_trailer":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean: %s (use true/false)", value)
+		}
+		cfg.Git.JobTrailer = b
 
-	case "claude.model":
-		cfg.Claude.Model = value
+	// Review
+	case "review.diff_summarizer":
+		if !contains(config.DiffSummarizers, value) {
+			return fmt.Errorf("invalid diff_summarizer: %s (must be one of: %s)", value, strings.Join(config.DiffSummarizers, ", "))
+		}
+		cfg.Review.DiffSummarizer = value
 
-	case "claude.max_turns":
+	case "review.diff_summary_threshold":
 		n, err := strconv.Atoi(value)
-		if err != nil || n < 1 {
-			return fmt.Errorf("invalid max_turns: %s (must be a positive integer)", value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid diff_summary_threshold: %s (must be a size in bytes, 0 never summarizes)", value)
 		}
-		cfg.Claude.MaxTurns = n
+		cfg.Review.DiffSummaryThreshold = n
 
-	// Workers
-	case "workers.max_concurrent":
+	case "review.summary_model":
+		cfg.Review.SummaryModel = value
+
+	case "review.max_rejections":
 		n, err := strconv.Atoi(value)
-		if err != nil || n < 1 {
-			return fmt.Errorf("invalid max_concurrent: %s (must be a positive integer)", value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid max_rejections: %s (must be a count, 0 never sends rejected jobs to triage)", value)
 		}
-		cfg.Workers.MaxConcurrent = n
+		cfg.Review.MaxRejections = n
 
-	case "workers.default_role":
-		validRoles := []string{"soldato", "capo", "consigliere", "underboss", "associate", "lookout", "cleaner"}
-		if !contains(validRoles, value) {
-			return fmt.Errorf("invalid default_role: %s (must be one of: %s)", value, strings.Join(validRoles, ", "))
+	case "review.max_rounds":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid max_rounds: %s (must be a count, 0 never fails a job for its rejections)", value)
 		}
-		cfg.Workers.DefaultRole = value
+		cfg.Review.MaxRounds = n
 
-	// Git
-	case "git.default_merge_branch":
-		cfg.Git.DefaultMergeBranch = value
+	case "review.classifier":
+		if !contains(config.Classifiers, value) {
+			return fmt.Errorf("invalid classifier: %s (must be one of: %s)", value, strings.Join(config.Classifiers, ", "))
+		}
+		cfg.Review.Classifier = value
 
 	// TUI
 	case "tui.theme":
-		validThemes := []string{"noir", "godfather", "miami", "opencode"}
-		if !contains(validThemes, value) {
-			return fmt.Errorf("invalid theme: %s (must be one of: %s)", value, strings.Join(validThemes, ", "))
+		// Custom themes are checked by loading their file
+		if _, err := theme.Lookup(value); err != nil {
+			return fmt.Errorf("invalid theme: %w", err)
 		}
 		cfg.TUI.Theme = value
 
@@ -1985,6 +5201,13 @@ func setSettingValue(key, value string) error {
 		}
 		cfg.TUI.RefreshRate = n
 
+	case "tui.layout":
+		validLayouts := []string{"default", "operator", "reviewer"}
+		if !contains(validLayouts, value) {
+			return fmt.Errorf("invalid layout: %s (must be one of: %s)", value, strings.Join(validLayouts, ", "))
+		}
+		cfg.TUI.Layout = value
+
 	// Notifications
 	case "notifications.tui_alerts":
 		b, err := strconv.ParseBool(value)
@@ -2061,15 +5284,59 @@ func setSettingValue(key, value string) error {
 }
 
 func needsDaemonRestart(key string) bool {
-	// Settings that require daemon restart
+	// Settings that require daemon restart; the daemon reloads the rest
+	// of a changed config file by itself (see config.ReloadableKeys)
 	restartKeys := []string{
 		"socket_path",
 		"data_dir",
 		"log_level",
+		"daemon.idle_shutdown",
+		"daemon.log_max_size",
+		"daemon.log_max_files",
+		"api.listen",
+		"api.token",
+		"admission.url",
+		"admission.timeout",
+		"admission.fail_closed",
+		"cleanup.interval",
+		"cleanup.max_age",
+		"cleanup.max_disk_usage",
+		"encryption.enabled",
+		"encryption.keychain_service",
 		"claude.binary",
-		"claude.model",
 		"claude.max_turns",
+		"claude.resume_max_commits",
+		"claude.resume_max_files",
+		"claude.chat_drafts",
+		"claude.strict_output",
 		"workers.max_concurrent",
+		"workers.exit_interview",
+		"lookout.remediation.warning",
+		"lookout.remediation.error",
+		"lookout.remediation.critical",
+		"lookout.thresholds.warning",
+		"lookout.thresholds.error",
+		"lookout.thresholds.critical",
+		"git.assign_conflicts_to_consigliere",
+		"git.uncommitted_changes",
+		"git.auto_commit_message",
+		"git.stale_branch_threshold",
+		"git.stale_branch_interval",
+		"git.remote_mode",
+		"git.remote",
+		"git.pr_provider",
+		"git.pr_title",
+		"git.pr_template",
+		"git.author",
+		"git.signing_format",
+		"git.signing_key",
+		"git.job_trailer",
+		"review.diff_summarizer",
+		"review.diff_summary_threshold",
+		"review.summary_model",
+		"review.max_rejections",
+		"review.max_rounds",
+		"review.classifier",
 	}
 	return contains(restartKeys, key)
 }
@@ -2086,13 +5353,49 @@ func contains(slice []string, item string) bool {
 // TUI command
 
 func tuiCmd() *cobra.Command {
-	return &cobra.Command{
+	var newChat bool
+	var layout string
+	var observe bool
+	var replay string
+	var offline bool
+	var speed float64
+	var since string
+
+	cmd := &cobra.Command{
 		Use:     "tui",
 		Aliases: []string{"t"},
 		Short:   "Launch the interactive TUI dashboard",
+		Long: `Launch the interactive TUI dashboard.
+
+With --observe the TUI connects read-only: it shows everything but the
+daemon refuses anything that would change state, such as adding or
+cancelling jobs. Teammates given an observer token in the daemon's
+auth.tokens connect this way; set it as auth.token in their config.
+
+With --replay the dashboard plays back a ledger file instead, with no
+daemon running: workers take and finish jobs and the activity feed fills
+as they did, --speed times faster than they happened, with idle stretches
+cut short. --since skips ahead to a time or duration ago. --offline shows
+the crew as this machine's ledger last recorded it, or replays it with
+--speed or --since.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Ensure daemon is running
+			if offline && replay == "" {
+				replay = cfg.LedgerPath()
+				if !cmd.Flags().Changed("speed") && since == "" {
+					speed = 0
+				}
+			}
+			if replay != "" {
+				cmd.SilenceUsage = true
+				return runReplayTUI(replay, speed, since, layout)
+			}
+
+			// Ensure daemon is running. An observer only watches a daemon
+			// someone else runs, so it never starts one.
 			if !daemon.IsRunning(cfg.SocketPath) {
+				if observe {
+					return fmt.Errorf("daemon not running at %s", daemon.TransportAddress(cfg.SocketPath))
+				}
 				if err := startDaemonBackground(); err != nil {
 					return fmt.Errorf("failed to start daemon: %w", err)
 				}
@@ -2104,16 +5407,119 @@ func tuiCmd() *cobra.Command {
 			}
 			defer client.Close()
 
-			return tui.Run(client)
+			if observe || cfg.Auth.Token != "" {
+				if _, err := client.Login(cfg.Auth.Token, observe); err != nil {
+					return err
+				}
+			}
+
+			if layout == "" {
+				layout = cfg.TUI.Layout
+			}
+
+			return tui.Run(client, tui.Options{
+				NewChat:  newChat,
+				Layout:   layout,
+				Identity: cfg.ClientIdentity(),
+				Observe:  observe,
+				Filters:  cfg.Filters,
+				OnLayoutChange: func(layout string) error {
+					// Remember the last-used layout for the next run
+					cfg.TUI.Layout = layout
+					return cfg.Save(getConfigPath())
+				},
+				Theme: cfg.TUI.Theme,
+				OnThemeChange: func(name string) error {
+					cfg.TUI.Theme = name
+					return cfg.Save(getConfigPath())
+				},
+			})
 		},
 	}
+
+	cmd.Flags().BoolVar(&newChat, "new", false, "Start a new chat instead of resuming the last one")
+	cmd.Flags().StringVar(&layout, "layout", "", "Dashboard layout: default, operator or reviewer (defaults to the last used)")
+	cmd.Flags().BoolVar(&observe, "observe", false, "Connect read-only, without being able to change anything")
+	cmd.Flags().StringVar(&replay, "replay", "", "Play back a ledger file instead of connecting to the daemon")
+	cmd.Flags().BoolVar(&offline, "offline", false, "Show the crew from this machine's ledger, without the daemon")
+	cmd.Flags().Float64Var(&speed, "speed", 10, "With --replay, how many times faster than it happened to play the ledger (0 jumps to the end)")
+	cmd.Flags().StringVar(&since, "since", "", "With --replay, skip ahead to a time or duration ago (e.g. 12h)")
+
+	return cmd
+}
+
+// replayMaxGap is the longest a replay waits between two events, so that a
+// night in which nothing happened does not have to be watched.
+const replayMaxGap = 2 * time.Second
+
+// runReplayTUI plays back the ledger at path in the TUI, serving it over a
+// socket of its own so the TUI talks to it as it would to the daemon.
+func runReplayTUI(path string, speed float64, since, layout string) error {
+	if speed < 0 {
+		return fmt.Errorf("--speed must not be negative")
+	}
+	opts := daemon.ReplayOptions{Speed: speed, MaxGap: replayMaxGap}
+	if since != "" {
+		t, err := parseLogTime(since)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		opts.Since = t
+	}
+
+	events, err := ledger.Read(path)
+	if err != nil {
+		return fmt.Errorf("failed to read ledger: %w", err)
+	}
+	if len(events) == 0 {
+		return fmt.Errorf("ledger %s has no events", path)
+	}
+
+	server := daemon.NewReplayServer(events, opts)
+	socket := filepath.Join(os.TempDir(), fmt.Sprintf("cosa-replay-%d.sock", os.Getpid()))
+	if err := server.Start(socket); err != nil {
+		return err
+	}
+	defer server.Close()
+
+	client, err := daemon.Connect(socket)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if layout == "" {
+		layout = cfg.TUI.Layout
+	}
+	return tui.Run(client, tui.Options{
+		Layout:  layout,
+		Observe: true,
+		Replay:  true,
+		Theme:   cfg.TUI.Theme,
+	})
 }
 
 // Helper functions
 
+// connectDaemon connects to the daemon, logging in with auth.token when one
+// is set. A rejected login only warns: the daemon explains what it refuses.
+func connectDaemon() (*daemon.Client, error) {
+	client, err := daemon.Connect(cfg.SocketPath)
+	if err != nil {
+		return nil, err
+	}
+	client.SetTimeout(callTimeout)
+	if cfg.Auth.Token != "" {
+		if _, err := client.Login(cfg.Auth.Token, false); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+	return client, nil
+}
+
 func connectOrStartDaemon() (*daemon.Client, error) {
 	if daemon.IsRunning(cfg.SocketPath) {
-		return daemon.Connect(cfg.SocketPath)
+		return connectDaemon()
 	}
 
 	// Start daemon
@@ -2121,7 +5527,7 @@ func connectOrStartDaemon() (*daemon.Client, error) {
 		return nil, err
 	}
 
-	return daemon.Connect(cfg.SocketPath)
+	return connectDaemon()
 }
 
 func startDaemonBackground() error {
@@ -2137,6 +5543,7 @@ func startDaemonBackground() error {
 	}
 
 	cmd := exec.Command(cosad)
+	detachProcess(cmd)
 	cmd.Stdout = nil
 	cmd.Stderr = nil
 	cmd.Stdin = nil
@@ -2168,7 +5575,7 @@ func runDaemonForeground() error {
 	}
 
 	fmt.Printf("Cosa daemon v%s started (pid: %d)\n", config.Version, os.Getpid())
-	fmt.Printf("Listening on %s\n", cfg.SocketPath)
+	fmt.Printf("Listening on %s\n", daemon.TransportAddress(cfg.SocketPath))
 	fmt.Println("Press Ctrl+C to stop")
 
 	server.Wait()
@@ -2177,6 +5584,40 @@ func runDaemonForeground() error {
 	return nil
 }
 
+// formatBudgetLimit formats a budget limit setting.
+func formatBudgetLimit(limit float64) string {
+	if limit <= 0 {
+		return "off"
+	}
+	return fmt.Sprintf("$%.2f", limit)
+}
+
+// parseBudgetLimit parses a budget limit such as "10", "$2.50" or "off".
+func parseBudgetLimit(value string) (float64, error) {
+	if value == "off" {
+		return 0, nil
+	}
+	limit, err := strconv.ParseFloat(strings.TrimPrefix(value, "$"), 64)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("invalid budget limit: %s", value)
+	}
+	return limit, nil
+}
+
+// formatBudget describes today's spend against the daily budget.
+func formatBudget(b *protocol.BudgetStatus) string {
+	if b.DailyLimit <= 0 {
+		return fmt.Sprintf("$%.2f today (no daily limit)", b.SpentToday)
+	}
+
+	pct := int(b.SpentToday / b.DailyLimit * 100)
+	line := fmt.Sprintf("$%.2f of $%.2f today (%d%%)", b.SpentToday, b.DailyLimit, pct)
+	if b.Exceeded {
+		line += " - EXCEEDED, new jobs paused"
+	}
+	return line
+}
+
 func formatDuration(d time.Duration) string {
 	d = d.Round(time.Second)
 	h := d / time.Hour
@@ -2197,24 +5638,34 @@ func formatDuration(d time.Duration) string {
 // Chat command
 
 func chatCmd() *cobra.Command {
-	return &cobra.Command{
+	var plan bool
+
+	cmd := &cobra.Command{
 		Use:   "chat",
 		Short: "Chat with the underboss",
 		Long: `Start an interactive chat session with the underboss (Claude).
 This allows you to have a back-and-forth conversation to discuss work,
 get advice, or coordinate tasks.
 
-Type 'exit' or 'quit' to end the chat session.
-Press Ctrl+C to abort.`,
+In plan mode the jobs the underboss creates are collected in a draft
+operation instead of starting. Chat commands:
+  /plan            Enter plan mode
+  /show            Show the draft operation
+  /execute [name]  Create the draft operation and all of its jobs
+  /discard         Drop the draft and leave plan mode
+
+Type 'exit' or 'quit' to end the chat session. Ctrl+C while the
+underboss is replying abandons that message; at the prompt it ends the
+chat. Replies are abandoned after --timeout (default 10m).`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := daemon.Connect(cfg.SocketPath)
+			client, err := connectDaemon()
 			if err != nil {
 				return fmt.Errorf("daemon not running - start it with 'cosa start'")
 			}
 			defer client.Close()
 
 			// Start chat session
-			resp, err := client.Call(protocol.MethodChatStart, protocol.ChatStartParams{})
+			resp, err := callInterruptible(client, protocol.MethodChatStart, protocol.ChatStartParams{})
 			if err != nil {
 				return fmt.Errorf("failed to start chat: %w", err)
 			}
@@ -2233,6 +5684,10 @@ Press Ctrl+C to abort.`,
 				fmt.Println()
 			}
 
+			if plan {
+				runChatCommand(client, "/plan")
+			}
+
 			// Read input loop
 			scanner := bufio.NewScanner(os.Stdin)
 			for {
@@ -2251,8 +5706,12 @@ Press Ctrl+C to abort.`,
 					break
 				}
 
+				if runChatCommand(client, input) {
+					continue
+				}
+
 				// Send message
-				resp, err := client.Call(protocol.MethodChatSend, protocol.ChatSendParams{
+				resp, err := callInterruptible(client, protocol.MethodChatSend, protocol.ChatSendParams{
 					Message: input,
 				})
 				if err != nil {
@@ -2270,6 +5729,9 @@ Press Ctrl+C to abort.`,
 				fmt.Println()
 				fmt.Printf("Underboss: %s\n", sendResult.Response)
 				fmt.Println()
+				if sendResult.Plan != nil {
+					printChatPlan(sendResult.Plan)
+				}
 			}
 
 			// End chat session
@@ -2280,31 +5742,142 @@ Press Ctrl+C to abort.`,
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&plan, "plan", false, "Start in plan mode")
+
+	return cmd
+}
+
+// runChatCommand handles the plan mode commands of cosa chat, reporting
+// whether input was one of them.
+func runChatCommand(client *daemon.Client, input string) bool {
+	command, arg, _ := strings.Cut(input, " ")
+
+	var method string
+	var params interface{}
+	switch command {
+	case "/plan":
+		method, params = protocol.MethodChatMode, protocol.ChatModeParams{Mode: protocol.ChatModePlan}
+	case "/show":
+		method = protocol.MethodChatPlan
+	case "/discard":
+		method = protocol.MethodChatDiscard
+	case "/execute":
+		method, params = protocol.MethodChatExecute, protocol.ChatExecuteParams{Name: strings.TrimSpace(arg)}
+	default:
+		return false
+	}
+
+	resp, err := client.Call(method, params)
+	if err != nil {
+		fmt.Printf("Error: %v\n\n", err)
+		return true
+	}
+	if resp.Error != nil {
+		fmt.Printf("Error: %s\n\n", resp.Error.Message)
+		return true
+	}
+
+	if method == protocol.MethodChatExecute {
+		var result protocol.ChatExecuteResult
+		json.Unmarshal(resp.Result, &result)
+		fmt.Printf("Created operation %q (%s) with %d jobs.\n\n",
+			result.Operation.Name, display.ShortID(result.Operation.ID), len(result.Jobs))
+		return true
+	}
+
+	var result protocol.ChatPlanResult
+	json.Unmarshal(resp.Result, &result)
+	switch command {
+	case "/plan":
+		fmt.Println("Plan mode: jobs are drafted, not started. /execute creates them, /discard drops them.")
+		fmt.Println()
+	case "/discard":
+		fmt.Println("Plan discarded.")
+		fmt.Println()
+	default:
+		printChatPlan(result.Plan)
+	}
+	return true
+}
+
+// printChatPlan prints the draft operation as a plan block.
+func printChatPlan(plan *protocol.ChatPlan) {
+	fmt.Println("+-- Plan ----------------------------------")
+	for _, line := range plan.Lines() {
+		fmt.Printf("| %s\n", line)
+	}
+	fmt.Println("+-- /execute to create, /discard to drop --")
+	fmt.Println()
 }
 
 func mcpServeCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:    "mcp-serve",
-		Short:  "Start MCP server for Claude integration",
-		Hidden: true, // Hide from help since this is called by the daemon
-		Long: `Starts an MCP (Model Context Protocol) server that provides Cosa tools to Claude.
-This command is typically invoked automatically by the daemon when starting a chat session.
-It communicates via stdin/stdout using JSON-RPC.`,
+	var listen string
+	var token string
+	var allowWrite bool
+	var agent, drafts bool
+	var workerName, jobID string
+
+	cmd := &cobra.Command{
+		Use:   "mcp-serve",
+		Short: "Start an MCP server exposing Cosa tools",
+		Long: `Starts an MCP (Model Context Protocol) server that provides Cosa tools to
+Claude and other MCP clients, backed by the running daemon.
+
+By default it speaks JSON-RPC over stdin/stdout, for clients that launch
+it as a command, such as Claude Code:
+
+  claude mcp add cosa -- cosa mcp-serve
+
+With --listen it serves HTTP instead: clients open an event stream at
+/sse and post their messages to the URL it gives them. Unless it listens
+on a loopback address, --token is required, and clients must send it as
+a bearer token.
+
+Only the tools that leave the daemon's state unchanged are offered, and
+the daemon connection is read-only, unless --allow-write is given; the
+daemon's own chat sessions run with it.
+
+--agent marks the client as an agent rather than a person, and leaves out
+the tools reserved for people, such as cosa_approve_job. With --drafts the
+jobs it creates are held as drafts until someone approves them with 'cosa
+job approve', the TUI or a person's MCP client. The daemon's chat runs
+with --agent, and with --drafts unless claude.chat_drafts is off.
+
+--worker serves the session of a worker: the tools that leave the daemon's
+state unchanged, and cosa_delegate_job, with which the worker hands part
+of its job to a worker it supervises. The daemon starts it for the
+sessions of workers whose role may delegate, such as capos.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Connect to daemon
+			if listen != "" && token == "" && !isLoopbackAddress(listen) {
+				return fmt.Errorf("--token is required to listen on %s", listen)
+			}
+
 			client, err := daemon.Connect(cfg.SocketPath)
 			if err != nil {
 				return fmt.Errorf("failed to connect to daemon: %w", err)
 			}
 			defer client.Close()
+			client.SetTimeout(callTimeout)
+
+			// Without --allow-write the daemon refuses changes as well, so
+			// a tool that slipped through could not make any. A worker's
+			// server delegates jobs, so it needs a connection that may
+			readOnly := !allowWrite && workerName == ""
+			if readOnly || cfg.Auth.Token != "" {
+				if _, err := client.Login(cfg.Auth.Token, readOnly); err != nil {
+					return err
+				}
+			}
 
-			// Create MCP adapter
-			// Note: We need to create a "remote" adapter that calls the daemon via RPC
-			// since we're in a separate process from the daemon
+			// The server runs in its own process, so its tools reach the
+			// daemon over RPC
 			adapter := NewRemoteMCPAdapter(client)
-
-			// Create MCP server
-			server := mcp.NewServer(adapter)
+			adapter.drafts = drafts
+			server := mcp.NewServer(adapter, allowWrite, agent)
+			if workerName != "" {
+				server = mcp.NewWorkerServer(adapter, workerName, jobID)
+			}
 
 			// Set up signal handling
 			ctx, cancel := context.WithCancel(context.Background())
@@ -2317,15 +5890,68 @@ It communicates via stdin/stdout using JSON-RPC.`,
 				cancel()
 			}()
 
-			// Run the MCP server on stdio
-			return server.Serve(ctx, os.Stdin, os.Stdout)
+			if listen == "" {
+				return server.Serve(ctx, os.Stdin, os.Stdout)
+			}
+			return serveMCPHTTP(ctx, server, listen, token)
 		},
 	}
+
+	cmd.Flags().StringVar(&listen, "listen", "", "Serve HTTP with server-sent events on this address instead of stdio, e.g. 127.0.0.1:7421")
+	cmd.Flags().StringVar(&token, "token", "", "Bearer token HTTP clients must send")
+	cmd.Flags().BoolVar(&allowWrite, "allow-write", false, "Offer the tools that change state, such as creating and cancelling jobs")
+	cmd.Flags().BoolVar(&agent, "agent", false, "The client is an agent: leave out the tools reserved for people")
+	cmd.Flags().BoolVar(&drafts, "drafts", false, "Create jobs as drafts that wait for approval")
+	cmd.Flags().StringVar(&workerName, "worker", "", "Serve the session of this worker, offering the tools it acts as itself with")
+	cmd.Flags().StringVar(&jobID, "job", "", "With --worker, the job the session runs, which delegated jobs belong to")
+
+	return cmd
+}
+
+// serveMCPHTTP serves the MCP server's HTTP transport on addr until ctx is
+// cancelled.
+func serveMCPHTTP(ctx context.Context, server *mcp.Server, addr, token string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	httpServer := &http.Server{
+		Handler:           server.SSEHandler(token),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(os.Stderr, "MCP server listening on http://%s%s\n", listener.Addr(), mcp.SSEPath)
+	if err := httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// isLoopbackAddress reports whether addr, a host:port, only accepts local
+// connections.
+func isLoopbackAddress(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
 }
 
 // RemoteMCPAdapter implements mcp.DaemonInterface by calling the daemon via RPC.
 type RemoteMCPAdapter struct {
 	client *daemon.Client
+	drafts bool // Create jobs as drafts
 }
 
 // NewRemoteMCPAdapter creates a new remote MCP adapter.
@@ -2362,9 +5988,101 @@ func (a *RemoteMCPAdapter) GetWorker(name string) (*protocol.WorkerDetailInfo, e
 	return &worker, nil
 }
 
+// AddWorker adds a worker via RPC.
+func (a *RemoteMCPAdapter) AddWorker(name, role string) (*protocol.WorkerInfo, error) {
+	resp, err := a.client.Call(protocol.MethodWorkerAdd, protocol.WorkerAddParams{
+		Name: name,
+		Role: role,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("%s", resp.Error.Message)
+	}
+	var worker protocol.WorkerInfo
+	if err := json.Unmarshal(resp.Result, &worker); err != nil {
+		return nil, fmt.Errorf("failed to parse worker response: %w", err)
+	}
+	return &worker, nil
+}
+
+// RemoveWorker removes a worker via RPC.
+func (a *RemoteMCPAdapter) RemoveWorker(name string, force bool) error {
+	resp, err := a.client.Call(protocol.MethodWorkerRemove, map[string]interface{}{
+		"name":  name,
+		"force": force,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("%s", resp.Error.Message)
+	}
+	return nil
+}
+
+// MessageWorker sends a message to a worker via RPC.
+func (a *RemoteMCPAdapter) MessageWorker(name, message string) error {
+	resp, err := a.client.Call(protocol.MethodWorkerMessage, map[string]string{
+		"name":    name,
+		"message": message,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("%s", resp.Error.Message)
+	}
+	return nil
+}
+
+// DelegateJob delegates a job from the worker named from via RPC.
+func (a *RemoteMCPAdapter) DelegateJob(from string, params protocol.JobDelegateParams) (*protocol.JobInfo, error) {
+	params.From = from
+	resp, err := a.client.Call(protocol.MethodJobDelegate, params)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("%s", resp.Error.Message)
+	}
+	var info protocol.JobInfo
+	if err := json.Unmarshal(resp.Result, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse job response: %w", err)
+	}
+	return &info, nil
+}
+
+// GenerateHandoff generates a handoff summary via RPC.
+func (a *RemoteMCPAdapter) GenerateHandoff(name string) (*protocol.HandoffSummary, error) {
+	resp, err := a.client.Call(protocol.MethodHandoffGenerate, protocol.HandoffGenerateParams{
+		Worker: name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("%s", resp.Error.Message)
+	}
+	var summary protocol.HandoffSummary
+	if err := json.Unmarshal(resp.Result, &summary); err != nil {
+		return nil, fmt.Errorf("failed to parse handoff response: %w", err)
+	}
+	return &summary, nil
+}
+
 // ListJobs returns jobs via RPC.
-func (a *RemoteMCPAdapter) ListJobs(status string) []protocol.JobInfo {
-	resp, err := a.client.Call(protocol.MethodJobList, nil)
+func (a *RemoteMCPAdapter) ListJobs(status string, labels map[string]string) []protocol.JobInfo {
+	var selector []string
+	for key, value := range labels {
+		if value == "" {
+			selector = append(selector, key)
+		} else {
+			selector = append(selector, key+"="+value)
+		}
+	}
+	resp, err := a.client.Call(protocol.MethodJobList, protocol.JobListParams{Labels: selector})
 	if err != nil || resp.Error != nil {
 		return nil
 	}
@@ -2408,6 +6126,7 @@ func (a *RemoteMCPAdapter) CreateJob(description string, priority int, territory
 	params := protocol.JobAddParams{
 		Description: description,
 		Priority:    priority,
+		Draft:       a.drafts,
 	}
 	resp, err := a.client.Call(protocol.MethodJobAdd, params)
 	if err != nil {
@@ -2422,12 +6141,28 @@ func (a *RemoteMCPAdapter) CreateJob(description string, priority int, territory
 	}
 	// Return a minimal job object
 	return &job.Job{
-		ID:          jobInfo.ID,
-		Description: jobInfo.Description,
-		Priority:    jobInfo.Priority,
+		ID:            jobInfo.ID,
+		Description:   jobInfo.Description,
+		Priority:      jobInfo.Priority,
+		Status:        job.Status(jobInfo.Status),
+		AttentionKind: jobInfo.AttentionKind,
 	}, nil
 }
 
+// ApproveJob approves a draft or a job's plan via RPC.
+func (a *RemoteMCPAdapter) ApproveJob(id string) (string, error) {
+	resp, err := a.client.Call(protocol.MethodJobApprove, protocol.JobApproveParams{ID: id})
+	if err != nil {
+		return "", err
+	}
+	if resp.Error != nil {
+		return "", fmt.Errorf("%s", resp.Error.Message)
+	}
+	var result map[string]string
+	json.Unmarshal(resp.Result, &result)
+	return result["status"], nil
+}
+
 // CancelJob cancels a job via RPC.
 func (a *RemoteMCPAdapter) CancelJob(id string) error {
 	resp, err := a.client.Call(protocol.MethodJobCancel, map[string]string{"id": id})
@@ -2535,3 +6270,33 @@ func (a *RemoteMCPAdapter) GetCosts() *mcp.CostSummary {
 		TotalTokens: status.TotalTokens,
 	}
 }
+
+// GetChatPlan returns the chat's mode and draft operation via RPC.
+func (a *RemoteMCPAdapter) GetChatPlan() (*protocol.ChatPlanResult, error) {
+	return a.callPlan(protocol.MethodChatPlan, nil)
+}
+
+// AddPlanStep adds a job to the chat's draft operation via RPC.
+func (a *RemoteMCPAdapter) AddPlanStep(step protocol.PlanStep) (*protocol.ChatPlanResult, error) {
+	return a.callPlan(protocol.MethodChatPlanAdd, step)
+}
+
+// RemovePlanStep removes a job from the chat's draft operation via RPC.
+func (a *RemoteMCPAdapter) RemovePlanStep(key string) (*protocol.ChatPlanResult, error) {
+	return a.callPlan(protocol.MethodChatPlanRemove, protocol.ChatPlanRemoveParams{Key: key})
+}
+
+func (a *RemoteMCPAdapter) callPlan(method string, params interface{}) (*protocol.ChatPlanResult, error) {
+	resp, err := a.client.Call(method, params)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("%s", resp.Error.Message)
+	}
+	var result protocol.ChatPlanResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse plan response: %w", err)
+	}
+	return &result, nil
+}
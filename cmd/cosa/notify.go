@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"cosa/internal/notify"
+)
+
+func notifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "notify",
+		Short: "Inspect notification routing",
+	}
+
+	cmd.AddCommand(notifyExplainCmd())
+
+	return cmd
+}
+
+func notifyExplainCmd() *cobra.Command {
+	var priority int
+	var severity string
+
+	cmd := &cobra.Command{
+		Use:   "explain <event>",
+		Short: "Show which notification rule matches an event",
+		Long: `Evaluate notifications.rules for an event and show where it would be sent.
+
+Events: job_completed, job_failed, worker_stuck, budget_warning, budget_exceeded,
+import_completed, needs_attention
+
+Priorities use the job scale, where higher is more urgent: 1=low, 3=normal,
+4=high, 5=critical. A rule for urgent failures matches priorities [4, 5];
+one for routine completions matches [1, 2, 3].
+
+Examples:
+  cosa notify explain job_failed --priority 5
+  cosa notify explain job_completed --priority 1`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			event := notify.EventType(args[0])
+			known := false
+			for _, e := range notify.EventTypes {
+				if e == event {
+					known = true
+					break
+				}
+			}
+			if !known {
+				return fmt.Errorf("unknown event: %s", args[0])
+			}
+
+			if err := notify.ValidateRules(cfg.Notifications.Rules); err != nil {
+				return fmt.Errorf("invalid notification rules: %w", err)
+			}
+
+			if severity == "" {
+				severity = notify.DefaultSeverity(event)
+			}
+
+			route := notify.Resolve(&cfg.Notifications, notify.Notification{
+				Event:    event,
+				Priority: priority,
+				Severity: severity,
+			})
+
+			if structuredOutput() {
+				return printStructured(map[string]interface{}{
+					"event":    string(event),
+					"priority": priority,
+					"severity": severity,
+					"rule":     route.Rule,
+					"index":    route.Index,
+					"channels": route.Channels,
+					"digest":   route.Digest,
+				})
+			}
+
+			fmt.Printf("Event:    %s (priority %d, severity %s)\n", event, priority, severity)
+			if route.Index < 0 {
+				fmt.Println("Rule:     none matched, using enabled channels")
+			} else {
+				fmt.Printf("Rule:     %s (rule %d of %d)\n", route.Rule, route.Index+1, len(cfg.Notifications.Rules))
+			}
+
+			channels := "none"
+			if len(route.Channels) > 0 {
+				channels = strings.Join(route.Channels, ", ")
+			}
+			fmt.Printf("Channels: %s\n", channels)
+			if route.Digest {
+				fmt.Println("Digest:   yes")
+			}
+
+			if !notifyEventEnabled(event) {
+				fmt.Println("\nNote: notifications for this event are disabled")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&priority, "priority", "p", 0, "Job priority (1=low, 3=normal, 4=high, 5=critical); 0 for events not tied to a job")
+	cmd.Flags().StringVar(&severity, "severity", "", "Severity (info, warning, error); defaults to the event's severity")
+
+	return cmd
+}
+
+func settingsNotifyTestCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "notify-test",
+		Short: "Send a test desktop notification",
+		Long: `Send a desktop notification from this machine to check that system
+notifications work. They are shown with terminal-notifier or osascript on
+macOS, and notify-send on Linux.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			system, err := notify.DetectSystemNotifier()
+			if err != nil {
+				return err
+			}
+
+			if err := system.Send(notify.Notification{
+				Title:    "Test Notification",
+				Message:  "Desktop notifications are working",
+				Severity: "info",
+			}); err != nil {
+				return fmt.Errorf("failed to send notification: %w", err)
+			}
+
+			var disabled []string
+			n := cfg.Notifications
+			for key, on := range map[string]bool{
+				"on_job_complete": n.OnJobComplete,
+				"on_job_failed":   n.OnJobFailed,
+				"on_worker_stuck": n.OnWorkerStuck,
+			} {
+				if !on {
+					disabled = append(disabled, "notifications."+key)
+				}
+			}
+			sort.Strings(disabled)
+
+			if structuredOutput() {
+				return printStructured(map[string]interface{}{
+					"backend":  system.Backend,
+					"enabled":  n.SystemNotifications,
+					"disabled": disabled,
+				})
+			}
+
+			fmt.Printf("Sent a test notification with %s\n", system.Backend)
+			if !n.SystemNotifications {
+				fmt.Println("\nNote: the daemon sends none until notifications.system_notifications is enabled")
+			} else if len(disabled) > 0 {
+				fmt.Printf("\nNote: no notifications are sent for %s\n", strings.Join(disabled, ", "))
+			}
+			return nil
+		},
+	}
+}
+
+// notifyEventEnabled reports whether the on_* toggle for event is enabled.
+func notifyEventEnabled(event notify.EventType) bool {
+	n := cfg.Notifications
+	switch event {
+	case notify.EventJobCompleted:
+		return n.OnJobComplete
+	case notify.EventJobFailed:
+		return n.OnJobFailed
+	case notify.EventWorkerStuck:
+		return n.OnWorkerStuck
+	default:
+		return n.OnBudgetAlert
+	}
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"cosa/internal/display"
+	"cosa/internal/job"
+	"cosa/internal/protocol"
+)
+
+// outcomeUnclassified groups completed jobs without an outcome label.
+const outcomeUnclassified = "other"
+
+// changelogHeadings title the changelog sections of each outcome.
+var changelogHeadings = map[string]string{
+	job.OutcomeFeature:  "Features",
+	job.OutcomeBugfix:   "Bug fixes",
+	job.OutcomeRefactor: "Refactoring",
+	job.OutcomeDocs:     "Documentation",
+	job.OutcomeTest:     "Tests",
+	outcomeUnclassified: "Other",
+}
+
+// completedJobs fetches the jobs completed since the given --since value,
+// oldest first. An empty since returns every completed job.
+func completedJobs(since string) ([]protocol.JobInfo, error) {
+	var after int64
+	if since != "" {
+		t, err := parseLogTime(since)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --since: %w", err)
+		}
+		after = t.Unix()
+	}
+
+	client, err := connectDaemon()
+	if err != nil {
+		return nil, fmt.Errorf("daemon not running")
+	}
+	defer client.Close()
+
+	resp, err := client.Call(protocol.MethodJobList, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("%s", resp.Error.Message)
+	}
+
+	var jobs []protocol.JobInfo
+	json.Unmarshal(resp.Result, &jobs)
+
+	var completed []protocol.JobInfo
+	for _, j := range jobs {
+		if j.Status == string(job.StatusCompleted) && j.CompletedAt >= after {
+			completed = append(completed, j)
+		}
+	}
+	sort.SliceStable(completed, func(a, b int) bool {
+		return completed[a].CompletedAt < completed[b].CompletedAt
+	})
+	return completed, nil
+}
+
+// jobOutcome returns a job's outcome label, or outcomeUnclassified.
+func jobOutcome(j protocol.JobInfo) string {
+	if outcome := j.Labels[job.OutcomeLabel]; job.ValidOutcome(outcome) {
+		return outcome
+	}
+	return outcomeUnclassified
+}
+
+// groupByOutcome groups jobs by outcome, in changelog order.
+func groupByOutcome(jobs []protocol.JobInfo) ([]string, map[string][]protocol.JobInfo) {
+	groups := make(map[string][]protocol.JobInfo)
+	for _, j := range jobs {
+		outcome := jobOutcome(j)
+		groups[outcome] = append(groups[outcome], j)
+	}
+
+	var order []string
+	for _, outcome := range job.Outcomes {
+		if len(groups[outcome]) > 0 {
+			order = append(order, outcome)
+		}
+	}
+	if len(groups[outcomeUnclassified]) > 0 {
+		order = append(order, outcomeUnclassified)
+	}
+	return order, groups
+}
+
+// OutcomeCount is one row of 'cosa stats outcomes'.
+type OutcomeCount struct {
+	Outcome string  `json:"outcome" yaml:"outcome"`
+	Jobs    int     `json:"jobs" yaml:"jobs"`
+	Share   float64 `json:"share" yaml:"share"`
+}
+
+func statsOutcomesCmd() *cobra.Command {
+	var since string
+
+	cmd := &cobra.Command{
+		Use:   "outcomes",
+		Short: "Show completed jobs by outcome",
+		Long: `Show how many completed jobs were features, bug fixes, refactors,
+documentation or tests, as classified when they completed (see the
+review.classifier setting). Jobs completed before classification was
+enabled count as other.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jobs, err := completedJobs(since)
+			if err != nil {
+				return err
+			}
+
+			order, groups := groupByOutcome(jobs)
+			counts := make([]OutcomeCount, 0, len(order))
+			for _, outcome := range order {
+				counts = append(counts, OutcomeCount{
+					Outcome: outcome,
+					Jobs:    len(groups[outcome]),
+					Share:   float64(len(groups[outcome])) / float64(len(jobs)),
+				})
+			}
+
+			if structuredOutput() {
+				return printStructured(counts)
+			}
+
+			if len(counts) == 0 {
+				fmt.Println("No completed jobs")
+				return nil
+			}
+
+			table := NewTable("OUTCOME", "JOBS", "SHARE", "REVIEWED")
+			for _, c := range counts {
+				reviewed := "-"
+				if c.Outcome != outcomeUnclassified {
+					reviewed = fmt.Sprintf("%.0f%%", cfg.Review.ReviewSampleRate(c.Outcome)*100)
+				}
+				table.AddRow(c.Outcome, c.Jobs, fmt.Sprintf("%.0f%%", c.Share*100), reviewed)
+			}
+			table.Print()
+			fmt.Printf("\n%d completed jobs\n", len(jobs))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "", "Only count jobs completed since a time or duration ago (e.g. 7d)")
+
+	return cmd
+}
+
+func changelogCmd() *cobra.Command {
+	var since string
+
+	cmd := &cobra.Command{
+		Use:   "changelog",
+		Short: "Write a changelog of completed jobs",
+		Long: `Write a Markdown changelog of completed jobs, grouped by outcome.
+
+Examples:
+  cosa changelog --since 7d
+  cosa changelog --since 2025-06-01 > CHANGES.md`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jobs, err := completedJobs(since)
+			if err != nil {
+				return err
+			}
+
+			order, groups := groupByOutcome(jobs)
+			if structuredOutput() {
+				return printStructured(groups)
+			}
+
+			fmt.Print(renderChangelog(order, groups, since))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "7d", "Include jobs completed since a time or duration ago (empty for all)")
+
+	return cmd
+}
+
+// renderChangelog writes grouped jobs as a Markdown changelog.
+func renderChangelog(order []string, groups map[string][]protocol.JobInfo, since string) string {
+	var b strings.Builder
+	b.WriteString("# Changelog\n")
+	if since != "" {
+		if t, err := parseLogTime(since); err == nil {
+			fmt.Fprintf(&b, "\nChanges since %s.\n", t.Format("2006-01-02 15:04"))
+		}
+	}
+
+	if len(order) == 0 {
+		b.WriteString("\nNo completed jobs.\n")
+		return b.String()
+	}
+
+	for _, outcome := range order {
+		fmt.Fprintf(&b, "\n## %s\n\n", changelogHeadings[outcome])
+		for _, j := range groups[outcome] {
+			line := fmt.Sprintf("- %s (%s", strings.TrimSpace(j.Description), display.ShortID(j.ID))
+			if j.PullRequest != "" {
+				line += ", " + j.PullRequest
+			}
+			if j.CompletedAt > 0 {
+				line += ", " + time.Unix(j.CompletedAt, 0).Format("2006-01-02")
+			}
+			b.WriteString(line + ")\n")
+		}
+	}
+	return b.String()
+}
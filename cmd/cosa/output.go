@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"cosa/internal/display"
+)
+
+// Output formats accepted by --output.
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+var (
+	outputFormat string
+	jsonOutput   bool
+)
+
+// addOutputFlags registers the global output flags on the root command.
+func addOutputFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", outputTable, "Output format (table, json, yaml)")
+	cmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Shorthand for --output json")
+}
+
+// resolveOutputFormat validates the output flags. --json wins over --output.
+func resolveOutputFormat() error {
+	if jsonOutput {
+		outputFormat = outputJSON
+	}
+	outputFormat = strings.ToLower(outputFormat)
+
+	switch outputFormat {
+	case outputTable, outputJSON, outputYAML:
+		return nil
+	default:
+		return fmt.Errorf("invalid output format: %s (must be one of: table, json, yaml)", outputFormat)
+	}
+}
+
+// structuredOutput returns true if output should be machine-readable.
+func structuredOutput() bool {
+	return outputFormat == outputJSON || outputFormat == outputYAML
+}
+
+// printStructured writes v to stdout in the selected structured format.
+func printStructured(v interface{}) error {
+	return writeStructured(os.Stdout, outputFormat, v)
+}
+
+func writeStructured(w io.Writer, format string, v interface{}) error {
+	switch format {
+	case outputYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return fmt.Errorf("failed to encode output: %w", err)
+		}
+		return enc.Close()
+	default:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(v); err != nil {
+			return fmt.Errorf("failed to encode output: %w", err)
+		}
+		return nil
+	}
+}
+
+// Table renders aligned columns for table output.
+type Table struct {
+	headers []string
+	rows    [][]string
+}
+
+// NewTable creates a table with the given column headers.
+func NewTable(headers ...string) *Table {
+	return &Table{headers: headers}
+}
+
+// AddRow appends a row. Values are formatted with fmt.Sprint.
+func (t *Table) AddRow(values ...interface{}) {
+	row := make([]string, len(values))
+	for i, v := range values {
+		row[i] = fmt.Sprint(v)
+	}
+	t.rows = append(t.rows, row)
+}
+
+// Print writes the table to stdout.
+func (t *Table) Print() {
+	t.Render(os.Stdout)
+}
+
+// Render writes the table to w. Columns are aligned by display width, so
+// wide characters and emoji do not shift the columns after them.
+func (t *Table) Render(w io.Writer) {
+	rows := append([][]string{t.headers}, t.rows...)
+
+	widths := make([]int, len(t.headers))
+	for _, row := range rows {
+		for i, cell := range row {
+			if i < len(widths) {
+				widths[i] = max(widths[i], display.Width(cell))
+			}
+		}
+	}
+
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			// The last column is not padded, so lines have no trailing spaces
+			if i < len(row)-1 && i < len(widths) {
+				cell = display.PadRight(cell, widths[i]+2)
+			}
+			cells[i] = cell
+		}
+		fmt.Fprintln(w, strings.Join(cells, ""))
+	}
+}
+
+// printSettings writes the loaded configuration using its config file keys.
+func printSettings() error {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to encode settings: %w", err)
+	}
+
+	var settings map[string]interface{}
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return fmt.Errorf("failed to encode settings: %w", err)
+	}
+	return printStructured(settings)
+}
+
+// printStreamEvent writes a single streamed event. JSON is emitted as one
+// compact object per line and YAML as a sequence of documents, so the stream
+// can be consumed incrementally.
+func printStreamEvent(v interface{}) error {
+	if outputFormat == outputYAML {
+		fmt.Println("---")
+		return printStructured(v)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"cosa/internal/config"
+	"cosa/internal/daemon"
+)
+
+func profileCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "profile",
+		Short:   "Manage config profiles",
+		Aliases: []string{"profiles"},
+		Long: `Profiles are isolated Cosa environments. Each named profile has its own
+config, data directory and daemon socket under ~/.cosa/profiles/<name>, so
+separate daemons can run side by side with their own budgets and models.
+
+Select a profile for one command with --profile or COSA_PROFILE, or make it
+the default with 'cosa profile use'.
+
+Examples:
+  cosa profile create work
+  cosa --profile work start
+  cosa profile use oss`,
+	}
+
+	cmd.AddCommand(
+		profileListCmd(),
+		profileCreateCmd(),
+		profileUseCmd(),
+	)
+
+	return cmd
+}
+
+// isProfileCommand reports whether cmd is one of the profile commands, which
+// must work even when the selected profile fails to load.
+func isProfileCommand(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Name() == "profile" {
+			return true
+		}
+	}
+	return false
+}
+
+func profileListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List profiles",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			names, err := config.ListProfiles()
+			if err != nil {
+				return fmt.Errorf("failed to list profiles: %w", err)
+			}
+
+			type profileInfo struct {
+				Name    string `json:"name" yaml:"name"`
+				DataDir string `json:"data_dir" yaml:"data_dir"`
+				Active  bool   `json:"active" yaml:"active"`
+				Running bool   `json:"running" yaml:"running"`
+				Error   string `json:"error,omitempty" yaml:"error,omitempty"`
+			}
+
+			active := config.ActiveProfile()
+			profiles := make([]profileInfo, 0, len(names))
+			for _, name := range names {
+				info := profileInfo{Name: name, Active: name == active}
+				if p, err := config.LoadProfile(name); err != nil {
+					info.Error = err.Error()
+				} else {
+					info.DataDir = p.DataDir
+					info.Running = daemon.IsRunning(p.SocketPath)
+				}
+				profiles = append(profiles, info)
+			}
+
+			if structuredOutput() {
+				return printStructured(profiles)
+			}
+
+			table := NewTable("", "NAME", "DATA DIR", "DAEMON")
+			for _, p := range profiles {
+				marker := ""
+				if p.Active {
+					marker = "*"
+				}
+				status := "stopped"
+				if p.Running {
+					status = "running"
+				}
+				if p.Error != "" {
+					status = "invalid config"
+				}
+				table.AddRow(marker, p.Name, valueOrDefault(p.DataDir, "-"), status)
+			}
+			table.Print()
+			return nil
+		},
+	}
+}
+
+func profileCreateCmd() *cobra.Command {
+	var blank bool
+	var use bool
+
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a profile",
+		Long: `Create a profile. It starts as a copy of the current config, with its data
+directory and socket moved into the profile, unless --blank is given.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			base := cfg
+			if blank {
+				base = config.DefaultConfig()
+			}
+
+			p, err := config.CreateProfile(args[0], base)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Profile '%s' created\n", p.Profile)
+			fmt.Printf("  Config:   %s\n", config.ProfileConfigPath(p.Profile))
+			fmt.Printf("  Data dir: %s\n", p.DataDir)
+
+			if use {
+				if err := config.SetActiveProfile(p.Profile); err != nil {
+					return err
+				}
+				fmt.Printf("Now using profile '%s'\n", p.Profile)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&blank, "blank", false, "Start from the default settings instead of the current config")
+	cmd.Flags().BoolVar(&use, "use", false, "Make the new profile the default")
+
+	return cmd
+}
+
+func profileUseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "use <name>",
+		Short: "Set the default profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := config.SetActiveProfile(args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("Now using profile '%s'\n", args[0])
+			return nil
+		},
+	}
+}
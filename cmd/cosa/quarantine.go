@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"cosa/internal/protocol"
+)
+
+func workerUnquarantineCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unquarantine <name>",
+		Short: "Let a quarantined worker take jobs again",
+		Long: `A worker that fails workers.quarantine_after jobs in a row is quarantined:
+the scheduler gives it no more jobs, and the failures are sent as an
+alert and shown by 'cosa worker detail'. Once the cause is found and
+fixed, unquarantine the worker to put it back to work with a clean slate.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			resp, err := client.Call(protocol.MethodWorkerUnquarantine, protocol.WorkerNameParams{Name: args[0]})
+			if err != nil {
+				return err
+			}
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var lifted protocol.QuarantineInfo
+			json.Unmarshal(resp.Result, &lifted)
+
+			if structuredOutput() {
+				return printStructured(lifted)
+			}
+
+			fmt.Printf("Worker %s unquarantined after %d failed jobs\n", args[0], len(lifted.Failures))
+			return nil
+		},
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"cosa/internal/display"
+	"cosa/internal/protocol"
+	"cosa/internal/worker"
+)
+
+func queueCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "queue",
+		Short: "Pause, drain or resume the job queue",
+		Long: `Control whether the scheduler hands out queued jobs.
+
+A paused queue assigns no new jobs but keeps taking them, while running
+jobs finish. A draining queue also refuses new jobs, for when the work in
+flight should be the last for a while. Either way the daemon keeps running;
+'cosa stop --drain' is the way to wind it down.`,
+	}
+
+	cmd.AddCommand(
+		queueStatusCmd(),
+		queueStateCmd(protocol.MethodQueuePause),
+		queueStateCmd(protocol.MethodQueueDrain),
+		queueStateCmd(protocol.MethodQueueResume),
+	)
+
+	return cmd
+}
+
+// queueStateCmd builds queue pause, queue drain or queue resume.
+func queueStateCmd(method string) *cobra.Command {
+	var reason string
+
+	cmd := &cobra.Command{
+		Use:     "pause",
+		Short:   "Stop assigning jobs, letting running jobs finish",
+		Example: `  cosa queue pause --reason "deploy freeze"`,
+	}
+	switch method {
+	case protocol.MethodQueueDrain:
+		cmd.Use = "drain"
+		cmd.Short = "Stop assigning jobs and refuse new ones"
+		cmd.Example = `  cosa queue drain --reason "upgrading the runners"`
+	case protocol.MethodQueueResume:
+		cmd.Use = "resume"
+		cmd.Short = "Assign and accept jobs again"
+		cmd.Example = `  cosa queue resume`
+	}
+
+	cmd.Args = cobra.NoArgs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		client, err := connectDaemon()
+		if err != nil {
+			return fmt.Errorf("daemon not running")
+		}
+		defer client.Close()
+
+		resp, err := client.Call(method, protocol.QueueStateParams{
+			Reason: reason,
+			By:     cfg.ClientIdentity(),
+		})
+		if err != nil {
+			return err
+		}
+		if resp.Error != nil {
+			return fmt.Errorf("%s", resp.Error.Message)
+		}
+
+		var result protocol.QueueStateResult
+		json.Unmarshal(resp.Result, &result)
+
+		if structuredOutput() {
+			return printStructured(result)
+		}
+
+		if result.Previous == result.State {
+			fmt.Printf("Queue is already %s\n", result.State)
+			return nil
+		}
+		switch result.State {
+		case "paused":
+			fmt.Println("Queue paused; running jobs will finish, new jobs wait until 'cosa queue resume'")
+		case "draining":
+			fmt.Println("Queue draining; running jobs will finish, new jobs are refused until 'cosa queue resume'")
+		default:
+			fmt.Println("Queue resumed")
+		}
+		return nil
+	}
+
+	if method != protocol.MethodQueueResume {
+		cmd.Flags().StringVarP(&reason, "reason", "r", "", "Why the queue is held, shown in the ledger")
+	}
+
+	return cmd
+}
+
+func queueStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show whether the queue is running, how many jobs it holds and when they should be done",
+		Long: `Show whether the queue is running and how many jobs it holds, with an
+estimate of when each running and waiting job should be done.
+
+Estimates come from how long the most recent finished jobs took: jobs from
+the same template, then with a label in common, then run by the same
+worker, then any job. Waiting jobs are handed in order to whichever worker
+frees up first. While the queue is held only running jobs are estimated.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			resp, err := client.Call(protocol.MethodQueueStatus, nil)
+			if err != nil {
+				return err
+			}
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var result protocol.QueueStatusResult
+			json.Unmarshal(resp.Result, &result)
+
+			if structuredOutput() {
+				return printStructured(result)
+			}
+
+			fmt.Printf("State:   %s\n", queueStateLine(result.Hold))
+			fmt.Printf("Ready:   %d\n", result.Ready)
+			fmt.Printf("Pending: %d (waiting on dependencies)\n", result.Pending)
+			fmt.Printf("Running: %d\n", result.Running)
+			switch {
+			case result.ETA > 0:
+				eta := time.Unix(result.ETA, 0)
+				fmt.Printf("ETA:     %s (in %s)\n", eta.Format("Mon Jan 2 15:04"), formatDuration(max(time.Until(eta), 0)))
+			case result.Running+result.Ready+result.Pending > 0:
+				fmt.Println("ETA:     unknown (no finished jobs to estimate from, or no workers free to take them)")
+			}
+
+			if len(result.Jobs) > 0 {
+				fmt.Println()
+				table := NewTable("ID", "STATUS", "ESTIMATE", "BASIS", "DONE BY", "DESCRIPTION")
+				for _, j := range result.Jobs {
+					table.AddRow(display.ShortID(j.ID), j.Status, formatDuration(time.Duration(j.Estimate)*time.Second),
+						j.Basis, formatETA(time.Unix(j.ETA, 0)), truncate(j.Description, 40))
+				}
+				table.Print()
+			}
+			return nil
+		},
+	}
+}
+
+// formatETA formats when a job should be done: the time today, and the day
+// as well otherwise.
+func formatETA(t time.Time) string {
+	if worker.StartOfDay(t).Equal(worker.StartOfDay(time.Now())) {
+		return t.Format("15:04")
+	}
+	return t.Format("Mon Jan 2 15:04")
+}
+
+// queueStateLine describes a held queue: its state, for how long, who held
+// it and why.
+func queueStateLine(hold *protocol.QueueHoldInfo) string {
+	if hold == nil {
+		return "running"
+	}
+	line := fmt.Sprintf("%s for %s", hold.State, formatDuration(time.Since(time.Unix(hold.Since, 0))))
+	if hold.By != "" {
+		line += " by " + hold.By
+	}
+	if hold.Reason != "" {
+		line += ": " + hold.Reason
+	}
+	return line
+}
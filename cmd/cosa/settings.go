@@ -0,0 +1,290 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// settingKeys lists the settings 'cosa settings get' and 'set' accept, in
+// the order 'cosa settings list' shows them.
+var settingKeys = []string{
+	"log_level", "socket_path", "data_dir", "identity",
+	"daemon.idle_shutdown", "daemon.log_max_size", "daemon.log_max_files",
+	"api.listen", "api.token",
+	"budget.daily_usd", "budget.per_worker_usd", "budget.per_job_usd",
+	"admission.url", "admission.timeout", "admission.fail_closed", "admission.max_queued", "admission.rate_limit",
+	"cleanup.interval", "cleanup.max_age", "cleanup.max_disk_usage",
+	"encryption.enabled", "encryption.keychain_service",
+	"claude.binary", "claude.model", "claude.max_turns", "claude.resume_max_commits", "claude.resume_max_files",
+	"claude.chat_drafts", "claude.context.enabled", "claude.context.max_files", "claude.strict_output",
+	"workers.max_concurrent", "workers.default_role", "workers.exit_interview", "workers.job_timeout", "workers.timeout_retries",
+	"workers.quarantine_after",
+	"lookout.remediation.warning", "lookout.remediation.error", "lookout.remediation.critical",
+	"lookout.thresholds.warning", "lookout.thresholds.error", "lookout.thresholds.critical",
+	"git.default_merge_branch", "git.assign_conflicts_to_consigliere", "git.uncommitted_changes",
+	"git.auto_commit_message", "git.stale_branch_threshold", "git.stale_branch_interval",
+	"git.remote_mode", "git.remote", "git.pr_provider", "git.pr_title", "git.pr_template",
+	"git.author", "git.signing_format", "git.signing_key", "git.job_trailer",
+	"review.diff_summarizer", "review.diff_summary_threshold", "review.summary_model",
+	"review.max_rejections", "review.max_rounds", "review.classifier",
+	"tui.theme", "tui.refresh_rate", "tui.layout",
+	"notifications.tui_alerts", "notifications.system_notifications", "notifications.terminal_bell",
+	"notifications.on_job_complete", "notifications.on_job_failed", "notifications.on_worker_stuck",
+	"models.default", "models.underboss", "models.consigliere", "models.capo",
+	"models.soldato", "models.associate", "models.lookout", "models.cleaner",
+}
+
+// settingChange is a setting whose value an apply changes.
+type settingChange struct {
+	Key     string `json:"key"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Restart bool   `json:"restart_required"`
+}
+
+func settingsApplyCmd() *cobra.Command {
+	var file string
+	var edit bool
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Change several settings at once",
+		Long: `Change several settings at once from a YAML file of setting keys and
+values, or '-' for stdin. Keys may be written dotted or nested:
+
+  workers.max_concurrent: 10
+  models:
+    soldato: opus
+    capo: opus
+
+With --edit, the current settings open in $VISUAL or $EDITOR instead and
+are applied when the editor exits. Settings missing from the file are left
+as they are.
+
+Every value is validated before any is applied: if one is invalid, none
+are saved. The changes are listed with those that need a daemon restart
+marked; --dry-run lists them without saving.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (file == "") == !edit {
+				return fmt.Errorf("specify either --file or --edit")
+			}
+
+			var data []byte
+			var err error
+			switch {
+			case edit:
+				if data, err = editSettings(); err != nil {
+					return err
+				}
+			case file == "-":
+				data, err = io.ReadAll(os.Stdin)
+			default:
+				data, err = os.ReadFile(file)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read settings: %w", err)
+			}
+
+			values, err := parseSettingsFile(data)
+			if err != nil {
+				return err
+			}
+
+			changes, err := applySettings(values, dryRun)
+			if err != nil {
+				return err
+			}
+
+			if structuredOutput() {
+				return printStructured(changes)
+			}
+			if len(changes) == 0 {
+				fmt.Println("No changes")
+				return nil
+			}
+
+			restart := false
+			for _, c := range changes {
+				marker := ""
+				if c.Restart {
+					marker = "  (restart required)"
+					restart = true
+				}
+				fmt.Printf("  %s: %s -> %s%s\n", c.Key, displaySetting(c.Key, c.From), displaySetting(c.Key, c.To), marker)
+			}
+			fmt.Println()
+
+			if dryRun {
+				fmt.Printf("Dry run: %d settings would change\n", len(changes))
+				return nil
+			}
+			fmt.Printf("Applied %d settings to %s\n", len(changes), getConfigPath())
+			if restart {
+				fmt.Println("\nNote: Restart the daemon for the changes marked to take effect.")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "YAML file of settings to apply, or - for stdin")
+	cmd.Flags().BoolVarP(&edit, "edit", "e", false, "Edit the current settings in $EDITOR and apply them")
+	cmd.Flags().BoolVarP(&dryRun, "dry-run", "n", false, "Show the changes without saving them")
+
+	return cmd
+}
+
+// editSettings opens the current settings in the editor and returns the
+// edited file. The API token is left out so that it is never written to a
+// temporary file.
+func editSettings() ([]byte, error) {
+	doc := &yaml.Node{
+		Kind:        yaml.MappingNode,
+		HeadComment: "Edit the settings to change and save to apply them all at once.\nSettings removed from this file are left as they are.",
+	}
+	for _, key := range settingKeys {
+		if key == "api.token" {
+			continue
+		}
+		value, _ := getSettingValue(key)
+		doc.Content = append(doc.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: key},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: value},
+		)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+
+	f, err := os.CreateTemp("", "cosa-settings-*.yaml")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	f.Write(buf.Bytes())
+	f.Close()
+
+	if err := runEditor(f.Name()); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(f.Name())
+}
+
+// parseSettingsFile reads a YAML mapping of settings, flattening nested
+// mappings into dotted keys.
+func parseSettingsFile(data []byte) (map[string]string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid settings file: %w", err)
+	}
+
+	values := make(map[string]string)
+	if len(doc.Content) == 0 {
+		return values, nil
+	}
+	if err := flattenSettings(doc.Content[0], "", values); err != nil {
+		return nil, fmt.Errorf("invalid settings file: %w", err)
+	}
+	return values, nil
+}
+
+func flattenSettings(node *yaml.Node, prefix string, values map[string]string) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: expected a mapping of setting keys to values", node.Line)
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := strings.ToLower(prefix + node.Content[i].Value)
+		value := node.Content[i+1]
+
+		switch value.Kind {
+		case yaml.MappingNode:
+			if err := flattenSettings(value, key+".", values); err != nil {
+				return err
+			}
+		case yaml.ScalarNode:
+			if _, ok := values[key]; ok {
+				return fmt.Errorf("line %d: %s is set twice", value.Line, key)
+			}
+			values[key] = value.Value
+		default:
+			return fmt.Errorf("line %d: %s must be a single value", value.Line, key)
+		}
+	}
+	return nil
+}
+
+// applySettings sets every value on a copy of the configuration and, unless
+// dryRun, saves it. If any value is invalid, nothing is saved and every
+// invalid value is reported. Values matching the current ones are skipped.
+func applySettings(values map[string]string, dryRun bool) ([]settingChange, error) {
+	var problems []string
+	for key := range values {
+		if !contains(settingKeys, key) {
+			problems = append(problems, fmt.Sprintf("  %s: unknown setting", key))
+		}
+	}
+	sort.Strings(problems)
+
+	// The setters change cfg, so point it at the copy while they run
+	current := cfg
+	staged := *cfg
+	cfg = &staged
+	defer func() { cfg = current }()
+
+	changes := []settingChange{}
+	for _, key := range settingKeys {
+		value, ok := values[key]
+		if !ok {
+			continue
+		}
+		from, _ := getSettingValue(key)
+		if value == from {
+			continue
+		}
+		if err := setSettingValue(key, value); err != nil {
+			problems = append(problems, fmt.Sprintf("  %s: %v", key, err))
+			continue
+		}
+		if to, _ := getSettingValue(key); to != from {
+			changes = append(changes, settingChange{Key: key, From: from, To: to, Restart: needsDaemonRestart(key)})
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("no settings were applied:\n%s", strings.Join(problems, "\n"))
+	}
+	if dryRun || len(changes) == 0 {
+		return changes, nil
+	}
+
+	if err := staged.Save(getConfigPath()); err != nil {
+		return nil, fmt.Errorf("failed to save config: %w", err)
+	}
+	current = &staged
+	return changes, nil
+}
+
+// displaySetting shows a setting's value in a list of changes, hiding
+// secrets and marking empty values.
+func displaySetting(key, value string) string {
+	if key == "api.token" {
+		return formatSecret(value)
+	}
+	if value == "" {
+		return `""`
+	}
+	return value
+}
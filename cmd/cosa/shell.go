@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/spf13/cobra"
+
+	"cosa/internal/display"
+	"cosa/internal/protocol"
+)
+
+// workspace is a worktree to open a shell in.
+type workspace struct {
+	Worktree string
+	Branch   string
+	Worker   string
+	JobID    string
+}
+
+func workerShellCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "shell <name>",
+		Short: "Open a shell in a worker's worktree",
+		Long: `Start $SHELL in a worker's worktree, with COSA_WORKER, COSA_WORKTREE,
+COSA_BRANCH and, while it has a job, COSA_JOB_ID set. Exit the shell to
+return.
+
+The worker keeps running: changes made in the shell are seen by its
+session.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			resp, err := client.Call(protocol.MethodWorkerDetail, map[string]string{
+				"name": args[0],
+			})
+			if err != nil {
+				return err
+			}
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var info protocol.WorkerDetailInfo
+			json.Unmarshal(resp.Result, &info)
+			if info.Worktree == "" {
+				return fmt.Errorf("worker %s has no worktree", info.Name)
+			}
+
+			return openShell(workspace{
+				Worktree: info.Worktree,
+				Branch:   info.Branch,
+				Worker:   info.Name,
+				JobID:    info.CurrentJob,
+			})
+		},
+	}
+}
+
+func jobShellCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "shell <id>",
+		Short: "Open a shell in a job's worktree",
+		Long: `Start $SHELL in the worktree a job runs in, with COSA_JOB_ID,
+COSA_BRANCH, COSA_WORKTREE and COSA_WORKER set. Exit the shell to return.
+
+The worktree is removed when the job's worker is, so a finished job may
+have none left to open.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			resp, err := client.Call(protocol.MethodJobStatus, protocol.JobStatusParams{ID: args[0]})
+			if err != nil {
+				return err
+			}
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var info protocol.JobInfo
+			json.Unmarshal(resp.Result, &info)
+			if info.Worktree == "" {
+				return fmt.Errorf("job %s has not started in a worktree", display.ShortID(info.ID))
+			}
+
+			return openShell(workspace{
+				Worktree: info.Worktree,
+				Branch:   info.Branch,
+				Worker:   info.Worker,
+				JobID:    info.ID,
+			})
+		},
+	}
+}
+
+// openShell runs the user's shell in a workspace and waits for it to exit.
+func openShell(ws workspace) error {
+	if _, err := os.Stat(ws.Worktree); err != nil {
+		return fmt.Errorf("worktree %s no longer exists", ws.Worktree)
+	}
+
+	shell := userShell()
+	cmd := exec.Command(shell)
+	cmd.Dir = ws.Worktree
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), "COSA_WORKTREE="+ws.Worktree)
+	for name, value := range map[string]string{
+		"COSA_BRANCH": ws.Branch,
+		"COSA_WORKER": ws.Worker,
+		"COSA_JOB_ID": ws.JobID,
+	} {
+		if value != "" {
+			cmd.Env = append(cmd.Env, name+"="+value)
+		}
+	}
+
+	fmt.Printf("Opening %s in %s", shell, ws.Worktree)
+	if ws.Branch != "" {
+		fmt.Printf(" (%s)", ws.Branch)
+	}
+	fmt.Println(". Exit the shell to return.")
+
+	// The exit status of the shell is that of the last command run in it
+	var exitErr *exec.ExitError
+	if err := cmd.Run(); err != nil && !errors.As(err, &exitErr) {
+		return fmt.Errorf("failed to run %s: %w", shell, err)
+	}
+	return nil
+}
+
+// userShell returns the shell to open: $SHELL, or the platform default.
+func userShell() string {
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return shell
+	}
+	if runtime.GOOS == "windows" {
+		if comspec := os.Getenv("COMSPEC"); comspec != "" {
+			return comspec
+		}
+		return "cmd.exe"
+	}
+	return "/bin/sh"
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"cosa/internal/git"
+	"cosa/internal/protocol"
+	"cosa/internal/territory"
+)
+
+func territoryInitCmd() *cobra.Command {
+	var detect, interactive, autoReview bool
+	var params protocol.TerritoryInitParams
+
+	cmd := &cobra.Command{
+		Use:   "init [path]",
+		Short: "Initialize a new territory",
+		Long: `Initialize a territory for the git repository at path, or the current
+directory.
+
+With --detect the repository is looked at first: its build system gives
+the test and build gate commands run before each review, and its branches
+the base branch and any dev branch to merge into. With --interactive each
+of those is proposed in turn, to accept with Enter or change, before
+anything is written. The flags set a setting outright, over what is
+detected.`,
+		Example: `  cosa territory init --detect
+  cosa territory init -i ~/src/api
+  cosa territory init --test-command "make check" --dev-branch staging`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				params.Path = args[0]
+			}
+			if cmd.Flags().Changed("auto-review") {
+				params.AutoReview = &autoReview
+			}
+
+			if detect || interactive {
+				path, err := filepath.Abs(valueOrDefault(params.Path, "."))
+				if err != nil {
+					return err
+				}
+				if territory.Exists(path) {
+					return fmt.Errorf("territory already initialized for %s", path)
+				}
+				d, err := territory.Detect(path)
+				if err != nil {
+					return err
+				}
+				params = proposeTerritory(cmd, d, params)
+
+				if interactive {
+					cmd.SilenceUsage = true
+					ok, err := runInitWizard(bufio.NewReader(os.Stdin), d, &params)
+					if err != nil {
+						return err
+					}
+					if !ok {
+						fmt.Println("Aborted; nothing was written")
+						return nil
+					}
+				}
+			}
+			client, err := connectOrStartDaemon()
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			resp, err := client.Call(protocol.MethodTerritoryInit, params)
+			if err != nil {
+				return err
+			}
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var result protocol.TerritoryInitResult
+			json.Unmarshal(resp.Result, &result)
+
+			if structuredOutput() {
+				return printStructured(result)
+			}
+
+			fmt.Printf("Territory initialized at %s\n", result.Path)
+			fmt.Printf("  Base Branch:  %s\n", result.BaseBranch)
+			if result.DevBranch != "" {
+				fmt.Printf("  Dev Branch:   %s\n", result.DevBranch)
+			}
+			fmt.Printf("  Merge Target: %s\n", result.MergeTargetBranch)
+			fmt.Printf("  Test Gate:    %s\n", valueOrDefault(result.TestCommand, "(none)"))
+			fmt.Printf("  Build Gate:   %s\n", valueOrDefault(result.BuildCommand, "(none)"))
+			fmt.Printf("  Auto Review:  %s\n", onOff(result.AutoReview))
+			if result.TestCommand == "" && result.BuildCommand == "" {
+				fmt.Println("\nNo gates run before reviews; set them in .cosa/territory.json or .cosa/config.yaml")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&detect, "detect", false, "Detect the build system and branches and use what they suggest")
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Detect, then confirm or change each setting")
+	cmd.Flags().StringVar(&params.BaseBranch, "base-branch", "", "Branch new worktrees start from (default main or master)")
+	cmd.Flags().StringVar(&params.DevBranch, "dev-branch", "", "Branch finished work is merged into instead of the base branch")
+	cmd.Flags().StringVar(&params.TestCommand, "test-command", "", "Test gate run before each review")
+	cmd.Flags().StringVar(&params.BuildCommand, "build-command", "", "Build gate run before each review")
+	cmd.Flags().BoolVar(&autoReview, "auto-review", true, "Review finished jobs automatically")
+
+	return cmd
+}
+
+// proposeTerritory fills the settings not set by a flag with what was
+// detected.
+func proposeTerritory(cmd *cobra.Command, d *territory.Detection, params protocol.TerritoryInitParams) protocol.TerritoryInitParams {
+	propose := func(flag string, value *string, detected string) {
+		if !cmd.Flags().Changed(flag) {
+			*value = detected
+		}
+	}
+	propose("base-branch", &params.BaseBranch, d.BaseBranch)
+	propose("dev-branch", &params.DevBranch, d.DevBranch)
+	propose("test-command", &params.TestCommand, d.TestCommand)
+	propose("build-command", &params.BuildCommand, d.BuildCommand)
+	params.Path = d.RepoRoot
+	return params
+}
+
+// runInitWizard asks about each territory setting in turn, proposing the
+// current value, and then whether to write them. Enter keeps a proposal
+// and "-" clears it. It returns false if the settings should not be
+// written.
+func runInitWizard(in *bufio.Reader, d *territory.Detection, params *protocol.TerritoryInitParams) (bool, error) {
+	fmt.Printf("Setting up a territory for %s\n", d.RepoRoot)
+	if d.BuildSystem != "" {
+		fmt.Printf("Detected build system: %s\n", d.BuildSystem)
+	} else {
+		fmt.Println("No build system detected")
+	}
+	fmt.Println("Press Enter to accept a proposal, or type a new value ('-' for none).")
+	fmt.Println()
+
+	if len(d.Branches) > 0 {
+		branches := d.Branches
+		if len(branches) > 10 {
+			branches = append(branches[:10:10], fmt.Sprintf("and %d more", len(d.Branches)-10))
+		}
+		fmt.Printf("Branches: %s\n", strings.Join(branches, ", "))
+	}
+
+	// A rejected answer is not proposed again
+	var err error
+	proposed := params.BaseBranch
+	for {
+		if params.BaseBranch, err = ask(in, "Base branch new worktrees start from", proposed); err != nil {
+			return false, err
+		}
+		if slices.Contains(d.Branches, params.BaseBranch) || (params.BaseBranch == "" && len(d.Branches) == 0) {
+			break
+		}
+		if err := retry(in, "the base branch must be an existing local branch"); err != nil {
+			return false, err
+		}
+	}
+	proposed = params.DevBranch
+	for {
+		if params.DevBranch, err = ask(in, "Dev branch to merge finished work into (none merges into the base branch)", proposed); err != nil {
+			return false, err
+		}
+		invalid := git.ValidateBranchName(params.DevBranch)
+		if params.DevBranch == "" || invalid == nil {
+			break
+		}
+		if err := retry(in, invalid.Error()); err != nil {
+			return false, err
+		}
+	}
+	if params.TestCommand, err = ask(in, "Test gate run before each review", params.TestCommand); err != nil {
+		return false, err
+	}
+	if params.BuildCommand, err = ask(in, "Build gate run before each review", params.BuildCommand); err != nil {
+		return false, err
+	}
+	review := true
+	if params.AutoReview != nil {
+		review = *params.AutoReview
+	}
+	if review, err = confirm(in, "Review finished jobs automatically?", review); err != nil {
+		return false, err
+	}
+	params.AutoReview = &review
+
+	fmt.Println()
+	fmt.Printf("  Base Branch:  %s\n", valueOrDefault(params.BaseBranch, "(default)"))
+	fmt.Printf("  Dev Branch:   %s\n", valueOrDefault(params.DevBranch, "(none)"))
+	fmt.Printf("  Test Gate:    %s\n", valueOrDefault(params.TestCommand, "(none)"))
+	fmt.Printf("  Build Gate:   %s\n", valueOrDefault(params.BuildCommand, "(none)"))
+	fmt.Printf("  Auto Review:  %s\n", onOff(review))
+	return confirm(in, "Write the territory config?", true)
+}
+
+// ask prompts for a value, returning proposed if the answer is empty and
+// nothing if it is "-". At the end of the input the proposal is kept.
+func ask(in *bufio.Reader, question, proposed string) (string, error) {
+	if proposed != "" {
+		fmt.Printf("%s [%s]: ", question, proposed)
+	} else {
+		fmt.Printf("%s: ", question)
+	}
+
+	line, err := in.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	if errors.Is(err, io.EOF) {
+		fmt.Println()
+	}
+
+	switch answer := strings.TrimSpace(line); answer {
+	case "":
+		return proposed, nil
+	case "-":
+		return "", nil
+	default:
+		return answer, nil
+	}
+}
+
+// retry reports why an answer was not accepted, so the question can be
+// asked again, or fails if there is no more input to answer it with.
+func retry(in *bufio.Reader, problem string) error {
+	if _, err := in.Peek(1); err != nil {
+		return fmt.Errorf("no valid answer: %s", problem)
+	}
+	fmt.Printf("  Not accepted: %s\n", problem)
+	return nil
+}
+
+// confirm asks a yes or no question, returning proposed if the answer is
+// empty.
+func confirm(in *bufio.Reader, question string, proposed bool) (bool, error) {
+	hint := "Y/n"
+	if !proposed {
+		hint = "y/N"
+	}
+	for {
+		answer, err := ask(in, question+" ["+hint+"]", "")
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return proposed, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+	}
+}
+
+// onOff describes a setting that is on or off.
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"cosa/internal/protocol"
+	"cosa/internal/territory"
+)
+
+func territoryRemoveCmd() *cobra.Command {
+	var purge, yes bool
+
+	cmd := &cobra.Command{
+		Use:     "remove [path]",
+		Aliases: []string{"rm"},
+		Short:   "Detach a territory from the daemon",
+		Long: `Detach the active territory, at path or the current directory, from the
+daemon. Its workers are removed with it, keeping their sessions; queued
+jobs wait until a territory is added again. Running, reviewed and merging
+jobs must finish first.
+
+With --purge the territory's .cosa directory is deleted too, with every
+worktree under it and every cosa/ branch. The work of jobs not yet merged
+is lost; take a 'cosa territory archive' first to keep it. Without the
+daemon running, --purge deletes them all the same.
+
+You are asked to confirm unless --yes is given.`,
+		Example: `  cosa territory remove
+  cosa territory archive && cosa territory remove --purge`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			path := "."
+			if len(args) > 0 {
+				path = args[0]
+			}
+			path, err := filepath.Abs(path)
+			if err != nil {
+				return err
+			}
+
+			client, err := connectDaemon()
+			if err != nil {
+				if !purge {
+					return fmt.Errorf("daemon not running")
+				}
+				return purgeTerritoryOffline(path, yes)
+			}
+			defer client.Close()
+
+			params := protocol.TerritoryRemoveParams{Path: path, Purge: purge, DryRun: true}
+			if !yes {
+				resp, err := client.Call(protocol.MethodTerritoryRemove, params)
+				if err != nil {
+					return err
+				}
+				if resp.Error != nil {
+					return fmt.Errorf("%s", resp.Error.Message)
+				}
+				var plan protocol.TerritoryRemoveResult
+				json.Unmarshal(resp.Result, &plan)
+
+				printTerritoryRemoval(plan)
+				ok, err := confirm(bufio.NewReader(os.Stdin), "Remove the territory?", false)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					fmt.Println("Aborted; nothing was removed")
+					return nil
+				}
+			}
+
+			params.DryRun = false
+			resp, err := client.Call(protocol.MethodTerritoryRemove, params)
+			if err != nil {
+				return err
+			}
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+			var result protocol.TerritoryRemoveResult
+			json.Unmarshal(resp.Result, &result)
+
+			if structuredOutput() {
+				return printStructured(result)
+			}
+			printTerritoryRemoval(result)
+			if result.Error != "" {
+				return fmt.Errorf("some of the territory could not be purged: %s", result.Error)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&purge, "purge", false, "Also delete .cosa and the territory's worktrees and cosa/ branches")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Remove without asking for confirmation")
+
+	return cmd
+}
+
+// printTerritoryRemoval describes what territory.remove removed, or with a
+// dry run what it would remove.
+func printTerritoryRemoval(r protocol.TerritoryRemoveResult) {
+	verb := "Removed"
+	if r.DryRun {
+		verb = "Will remove"
+	}
+	fmt.Printf("%s territory %s\n", verb, r.Path)
+	if len(r.Workers) > 0 {
+		fmt.Printf("  Workers:   %s\n", strings.Join(r.Workers, ", "))
+	}
+	if r.Purged {
+		fmt.Printf("  Worktrees: %d\n", len(r.Worktrees))
+		fmt.Printf("  Branches:  %d", len(r.Branches))
+		if n := len(r.Branches); n > 0 && n <= 8 {
+			fmt.Printf(" (%s)", strings.Join(r.Branches, ", "))
+		} else if n > 8 {
+			fmt.Printf(" (%s and %d more)", strings.Join(r.Branches[:5], ", "), n-5)
+		}
+		fmt.Println()
+		fmt.Printf("  Metadata:  %s\n", filepath.Join(r.Path, territory.DirName))
+	}
+	if r.Queued > 0 {
+		fmt.Printf("  %d queued jobs will wait for a territory\n", r.Queued)
+	}
+}
+
+// purgeTerritoryOffline deletes a territory that no daemon has loaded.
+func purgeTerritoryOffline(path string, yes bool) error {
+	t, err := territory.Load(path)
+	if err != nil {
+		return err
+	}
+	plan, err := t.Artifacts()
+	if err != nil {
+		return err
+	}
+
+	result := protocol.TerritoryRemoveResult{
+		Path:      t.RepoRoot,
+		Worktrees: plan.Worktrees,
+		Branches:  plan.Branches,
+		Purged:    true,
+		DryRun:    true,
+	}
+	if !yes {
+		printTerritoryRemoval(result)
+		ok, err := confirm(bufio.NewReader(os.Stdin), "Remove the territory?", false)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted; nothing was removed")
+			return nil
+		}
+	}
+
+	removed, purgeErr := t.Purge()
+	result.DryRun = false
+	result.Worktrees, result.Branches = removed.Worktrees, removed.Branches
+	if purgeErr != nil {
+		result.Error = purgeErr.Error()
+	}
+	if structuredOutput() {
+		return printStructured(result)
+	}
+	printTerritoryRemoval(result)
+	if purgeErr != nil {
+		return fmt.Errorf("some of the territory could not be purged: %w", purgeErr)
+	}
+	return nil
+}
+
+func territoryArchiveCmd() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "archive [path]",
+		Short: "Save the territory's cosa state to a tarball",
+		Long: `Save the cosa state of the territory at path, or the current directory,
+to a gzipped tarball: its config, standing orders, knowledge, prompts,
+workspace files and index, and a git bundle of its cosa/ branches holding
+the work of jobs not yet merged. Worktrees are left out.
+
+'cosa territory restore' puts it back, into the same repository or a
+fresh clone of it.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			path := "."
+			if len(args) > 0 {
+				path = args[0]
+			}
+			t, err := territory.Load(path)
+			if err != nil {
+				return err
+			}
+
+			if output == "" {
+				output = fmt.Sprintf("cosa-territory-%s-%s.tar.gz", filepath.Base(t.RepoRoot), time.Now().Format("20060102-150405"))
+			}
+			f, err := os.OpenFile(output, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+			if err != nil {
+				return err
+			}
+			result, err := t.Archive(f)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				os.Remove(output)
+				return err
+			}
+
+			if structuredOutput() {
+				return printStructured(map[string]any{
+					"path":     output,
+					"files":    result.Files,
+					"branches": result.Branches,
+				})
+			}
+			fmt.Printf("Archived %s to %s\n", t.RepoRoot, output)
+			fmt.Printf("  Files:    %d\n", result.Files)
+			fmt.Printf("  Branches: %d\n", len(result.Branches))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "File to write (defaults to cosa-territory-<repo>-<time>.tar.gz)")
+
+	return cmd
+}
+
+func territoryRestoreCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "restore <archive> [path]",
+		Short: "Restore a territory from 'cosa territory archive'",
+		Long: `Restore a territory archived with 'cosa territory archive' into the git
+repository at path, or the current directory, which must not have one.
+Archived branches that do not exist in the repository are created; those
+that do are left as they are.
+
+The daemon picks the territory up with 'cosa territory add'.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			path := "."
+			if len(args) > 1 {
+				path = args[1]
+			}
+
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			t, result, err := territory.RestoreArchive(f, path)
+			if err != nil {
+				return err
+			}
+
+			if structuredOutput() {
+				return printStructured(map[string]any{
+					"path":     t.RepoRoot,
+					"files":    result.Files,
+					"branches": result.Branches,
+				})
+			}
+			fmt.Printf("Restored territory %s\n", t.RepoRoot)
+			fmt.Printf("  Files:    %d\n", result.Files)
+			fmt.Printf("  Branches: %d\n", len(result.Branches))
+			return nil
+		},
+	}
+}
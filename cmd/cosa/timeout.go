@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"cosa/internal/daemon"
+	"cosa/internal/protocol"
+)
+
+// defaultCallTimeout is how long a command waits for each daemon call,
+// unless commandTimeouts or --timeout say otherwise.
+const defaultCallTimeout = 30 * time.Second
+
+// commandTimeouts are the defaults of commands whose calls wait on Claude
+// and can take minutes. Zero waits indefinitely.
+var commandTimeouts = map[string]time.Duration{
+	"cosa chat":            10 * time.Minute,
+	"cosa mcp-serve":       10 * time.Minute,
+	"cosa worker handoff":  5 * time.Minute,
+	"cosa worker takeover": 5 * time.Minute,
+	"cosa worker remove":   5 * time.Minute,
+}
+
+// callTimeout is how long this command waits for each daemon call. It is
+// set by --timeout, or from the command's default.
+var callTimeout time.Duration
+
+// addTimeoutFlag adds --timeout to every command. Commands with a
+// --timeout of their own, such as stop --drain, keep the default.
+func addTimeoutFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().DurationVar(&callTimeout, "timeout", defaultCallTimeout,
+		"How long to wait for each daemon call (0 waits indefinitely; chat and handoffs wait longer by default)")
+}
+
+// resolveCallTimeout applies the command's default timeout unless --timeout
+// was given.
+func resolveCallTimeout(cmd *cobra.Command) {
+	if cmd.Root().PersistentFlags().Changed("timeout") {
+		return
+	}
+	callTimeout = defaultCallTimeout
+	if d, ok := commandTimeouts[cmd.CommandPath()]; ok {
+		callTimeout = d
+	}
+}
+
+// callInterruptible makes a daemon call that Ctrl+C abandons, without
+// ending the command, as well as the call timeout.
+func callInterruptible(client *daemon.Client, method string, params interface{}) (*protocol.Response, error) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if callTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, callTimeout)
+		defer cancel()
+	}
+	return client.CallContext(ctx, method, params)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"cosa/internal/protocol"
+)
+
+func triageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "triage",
+		Short: "List jobs waiting on you, oldest first",
+		Long: `List jobs in the needs_attention state, and drafts, oldest first. A job
+needs attention when its branch could not be merged, review rejected it too
+many times, its worker stopped to ask a question, its quality gates failed or
+its plan is waiting for approval. A draft, such as a job filed by email, waits
+in the draft state until someone approves it.
+
+Resolve each one with retry, answer, accept or dismiss. Accepting a plan
+approves it; retrying with a note plans again with that feedback. Accepting
+a draft queues it and dismissing it cancels it.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			resp, err := client.Call(protocol.MethodTriageList, nil)
+			if err != nil {
+				return err
+			}
+			if resp.Error != nil {
+				return fmt.Errorf("%s", resp.Error.Message)
+			}
+
+			var result protocol.TriageListResult
+			json.Unmarshal(resp.Result, &result)
+
+			if structuredOutput() {
+				return printStructured(result)
+			}
+
+			if len(result.Items) == 0 {
+				fmt.Println("Nothing needs attention")
+				return nil
+			}
+
+			table := NewTable("JOB", "KIND", "WAITING", "WORKER", "REASON")
+			for _, item := range result.Items {
+				worker := item.Worker
+				if worker == "" {
+					worker = "-"
+				}
+				waiting := formatDuration(time.Since(time.Unix(item.Since, 0)))
+				table.AddRow(item.ID, item.Kind, waiting, worker, truncate(strings.Join(strings.Fields(item.Reason), " "), 50))
+			}
+			table.Print()
+
+			return nil
+		},
+	}
+
+	cmd.AddCommand(
+		triageRetryCmd(),
+		triageAnswerCmd(),
+		triageResolveCmd(protocol.TriageAccept, "Override and mark the job completed"),
+		triageResolveCmd(protocol.TriageDismiss, "Give up on the job and mark it failed"),
+	)
+
+	return cmd
+}
+
+func triageRetryCmd() *cobra.Command {
+	var note string
+
+	cmd := &cobra.Command{
+		Use:   "retry <job-id>",
+		Short: "Run the job again",
+		Long: `Run the job again. A branch that still conflicts gets a new resolver job;
+anything else is requeued with the note added to its task details.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return resolveTriage(args[0], protocol.TriageRetry, note)
+		},
+	}
+
+	cmd.Flags().StringVarP(&note, "note", "n", "", "Instructions for the next attempt")
+
+	return cmd
+}
+
+func triageAnswerCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "answer <job-id> <answer>",
+		Short: "Answer a worker's question and rerun the job",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return resolveTriage(args[0], protocol.TriageRetry, strings.Join(args[1:], " "))
+		},
+	}
+}
+
+func triageResolveCmd(action, short string) *cobra.Command {
+	var reason string
+
+	cmd := &cobra.Command{
+		Use:   action + " <job-id>",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return resolveTriage(args[0], action, reason)
+		},
+	}
+
+	if action == protocol.TriageDismiss {
+		cmd.Flags().StringVarP(&reason, "reason", "r", "", "Why the job was dismissed")
+	}
+
+	return cmd
+}
+
+func resolveTriage(id, action, note string) error {
+	client, err := connectDaemon()
+	if err != nil {
+		return fmt.Errorf("daemon not running")
+	}
+	defer client.Close()
+
+	resp, err := client.Call(protocol.MethodTriageResolve, protocol.TriageResolveParams{
+		ID:     id,
+		Action: action,
+		Note:   note,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("%s", resp.Error.Message)
+	}
+
+	var result map[string]string
+	json.Unmarshal(resp.Result, &result)
+
+	if structuredOutput() {
+		return printStructured(result)
+	}
+
+	fmt.Printf("Job %s: %s\n", id, result["status"])
+	return nil
+}
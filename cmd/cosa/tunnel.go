@@ -0,0 +1,330 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"cosa/internal/protocol"
+)
+
+const (
+	tunnelMinBackoff = time.Second
+	tunnelMaxBackoff = 30 * time.Second
+
+	// tunnelStableAfter is how long a tunnel must stay up before the
+	// reconnect backoff is reset.
+	tunnelStableAfter = 30 * time.Second
+)
+
+func tunnelCmd() *cobra.Command {
+	var remoteSocket string
+	var localSocket string
+	var sshBinary string
+	var sshOpts []string
+	var interval time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "tunnel <host>",
+		Short: "Forward a remote daemon's socket over SSH",
+		Long: `Forward the socket of a daemon running on a remote machine to a local
+socket over SSH. The host may be any destination ssh accepts, including
+hosts from ~/.ssh/config. No network configuration is needed on the daemon
+side.
+
+The tunnel is health-checked periodically and re-established automatically
+if the SSH connection drops. Point other commands at it with --socket:
+
+  cosa tunnel devbox
+  cosa --socket ~/.cosa/tunnels/devbox.sock status`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			host := args[0]
+
+			if remoteSocket == "" {
+				home, err := remoteHome(sshBinary, sshOpts, host)
+				if err != nil {
+					return fmt.Errorf("failed to resolve remote socket path (use --remote-socket): %w", err)
+				}
+				remoteSocket = home + "/.cosa/cosa.sock"
+			}
+
+			if localSocket == "" {
+				localSocket = filepath.Join(cfg.DataDir, "tunnels", tunnelSocketName(host))
+			}
+			if err := os.MkdirAll(filepath.Dir(localSocket), 0700); err != nil {
+				return fmt.Errorf("failed to create socket directory: %w", err)
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			t := &tunnel{
+				host:         host,
+				remoteSocket: remoteSocket,
+				localSocket:  localSocket,
+				sshBinary:    sshBinary,
+				sshOpts:      sshOpts,
+				interval:     interval,
+				token:        cfg.Auth.Token,
+			}
+
+			fmt.Printf("Forwarding %s:%s\n", host, remoteSocket)
+			fmt.Printf("       to %s\n", localSocket)
+			fmt.Printf("Use: cosa --socket %s status\n", localSocket)
+			fmt.Println("Press Ctrl+C to close the tunnel")
+			fmt.Println()
+
+			t.run(ctx)
+			os.Remove(localSocket)
+			fmt.Println("Tunnel closed")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&remoteSocket, "remote-socket", "", "Daemon socket path on the remote host (default: ~/.cosa/cosa.sock)")
+	cmd.Flags().StringVar(&localSocket, "local-socket", "", "Local socket path (default: <data_dir>/tunnels/<host>.sock)")
+	cmd.Flags().StringVar(&sshBinary, "ssh", "ssh", "SSH binary to use")
+	cmd.Flags().StringArrayVar(&sshOpts, "ssh-option", nil, "Extra ssh -o option (repeatable, e.g. --ssh-option Port=2222)")
+	cmd.Flags().DurationVar(&interval, "interval", 10*time.Second, "Health check interval")
+
+	return cmd
+}
+
+// tunnel manages a single SSH socket forward.
+type tunnel struct {
+	host         string
+	remoteSocket string
+	localSocket  string
+	sshBinary    string
+	sshOpts      []string
+	interval     time.Duration
+	token        string // auth.token to log in to the daemon with, if any
+}
+
+// run keeps the tunnel up until ctx is cancelled, reconnecting with
+// exponential backoff whenever ssh exits.
+func (t *tunnel) run(ctx context.Context) {
+	backoff := tunnelMinBackoff
+
+	for {
+		// A stale socket from a previous run would make the forward fail.
+		os.Remove(t.localSocket)
+
+		started := time.Now()
+		err := t.forward(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if time.Since(started) > tunnelStableAfter {
+			backoff = tunnelMinBackoff
+		}
+
+		if err != nil {
+			tunnelLog("tunnel dropped: %v", err)
+		} else {
+			tunnelLog("tunnel dropped")
+		}
+		tunnelLog("reconnecting in %s", backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff = nextTunnelBackoff(backoff)
+	}
+}
+
+// nextTunnelBackoff doubles backoff, up to tunnelMaxBackoff.
+func nextTunnelBackoff(backoff time.Duration) time.Duration {
+	backoff *= 2
+	if backoff > tunnelMaxBackoff {
+		backoff = tunnelMaxBackoff
+	}
+	return backoff
+}
+
+// forward runs ssh for one connection attempt and monitors the tunnel's
+// health until ssh exits.
+func (t *tunnel) forward(ctx context.Context) error {
+	sshCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	args := []string{
+		"-N",
+		"-o", "ExitOnForwardFailure=yes",
+		"-o", "ServerAliveInterval=15",
+		"-o", "ServerAliveCountMax=3",
+		"-o", "StreamLocalBindUnlink=yes",
+		"-L", t.localSocket + ":" + t.remoteSocket,
+	}
+	for _, opt := range t.sshOpts {
+		args = append(args, "-o", opt)
+	}
+	// "--" keeps a host starting with "-" from being read as an option
+	args = append(args, "--", t.host)
+
+	sshCmd := exec.CommandContext(sshCtx, t.sshBinary, args...)
+	sshCmd.Stderr = os.Stderr
+
+	tunnelLog("connecting to %s...", t.host)
+	if err := sshCmd.Start(); err != nil {
+		return fmt.Errorf("failed to start ssh: %w", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- sshCmd.Wait()
+	}()
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	// Check quickly after start so the first status shows up promptly.
+	firstCheck := time.After(time.Second)
+	healthy := false
+	lastErr := ""
+
+	check := func() {
+		status, latency, err := probeDaemon(t.localSocket, t.token, 5*time.Second)
+		if err != nil {
+			msg := err.Error()
+			if healthy || msg != lastErr {
+				tunnelLog("daemon unreachable: %s", msg)
+			}
+			healthy = false
+			lastErr = msg
+			return
+		}
+
+		if !healthy {
+			tunnelLog("connected: daemon %s, up %s, %d workers, %d active jobs (%s)",
+				status.Version, formatDuration(time.Duration(status.Uptime)*time.Second), status.Workers, status.ActiveJobs, latency.Round(time.Millisecond))
+		}
+		healthy = true
+		lastErr = ""
+	}
+
+	for {
+		select {
+		case err := <-done:
+			return err
+		case <-firstCheck:
+			check()
+		case <-ticker.C:
+			check()
+		}
+	}
+}
+
+// probeDaemon sends a status request over the socket, logging in with token
+// first if one is set, and returns the result and round-trip time. Unlike
+// daemon.Client it enforces a deadline, so a stalled tunnel cannot block
+// the health check.
+func probeDaemon(socketPath, token string, timeout time.Duration) (*protocol.StatusResult, time.Duration, error) {
+	start := time.Now()
+
+	conn, err := net.DialTimeout("unix", socketPath, timeout)
+	if err != nil {
+		return nil, 0, fmt.Errorf("socket not available")
+	}
+	defer conn.Close()
+	conn.SetDeadline(start.Add(timeout))
+
+	scanner := bufio.NewScanner(conn)
+	call := func(id int64, method string, params interface{}) (json.RawMessage, error) {
+		req, err := protocol.NewRequest(protocol.NewIntID(id), method, params)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(req)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := conn.Write(append(data, '\n')); err != nil {
+			return nil, err
+		}
+
+		// Skip any notifications until the response arrives.
+		for scanner.Scan() {
+			var resp protocol.Response
+			if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil || resp.ID == nil {
+				continue
+			}
+			if resp.Error != nil {
+				return nil, fmt.Errorf("%s", resp.Error.Message)
+			}
+			return resp.Result, nil
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("connection closed by remote daemon")
+	}
+
+	if token != "" {
+		if _, err := call(1, protocol.MethodAuthLogin, protocol.AuthLoginParams{Token: token}); err != nil {
+			return nil, 0, fmt.Errorf("login failed: %w", err)
+		}
+	}
+
+	result, err := call(2, protocol.MethodStatus, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	var status protocol.StatusResult
+	if err := json.Unmarshal(result, &status); err != nil {
+		return nil, 0, err
+	}
+	return &status, time.Since(start), nil
+}
+
+// remoteHome asks the remote host for its home directory.
+func remoteHome(sshBinary string, sshOpts []string, host string) (string, error) {
+	args := []string{"-o", "BatchMode=yes"}
+	for _, opt := range sshOpts {
+		args = append(args, "-o", opt)
+	}
+	args = append(args, "--", host, `printf '%s' "$HOME"`)
+
+	out, err := exec.Command(sshBinary, args...).Output()
+	if err != nil {
+		return "", err
+	}
+	home := strings.TrimSpace(string(out))
+	if home == "" {
+		return "", fmt.Errorf("remote $HOME is empty")
+	}
+	return home, nil
+}
+
+// tunnelSocketName returns a filesystem-safe socket name for host.
+func tunnelSocketName(host string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, host)
+	return name + ".sock"
+}
+
+func tunnelLog(format string, args ...interface{}) {
+	fmt.Printf("[%s] %s\n", time.Now().Format("15:04:05"), fmt.Sprintf(format, args...))
+}
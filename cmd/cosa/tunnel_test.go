@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cosa/internal/protocol"
+)
+
+// serveTokenDaemon answers status requests on a unix socket like a daemon
+// with auth.tokens set: only once the connection has logged in with token.
+func serveTokenDaemon(t *testing.T, token string) string {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "d.sock")
+	ln, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				loggedIn := false
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					var req protocol.Request
+					if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+						return
+					}
+
+					var resp *protocol.Response
+					switch {
+					case req.Method == protocol.MethodAuthLogin:
+						var params protocol.AuthLoginParams
+						json.Unmarshal(req.Params, &params)
+						if params.Token != token {
+							resp, _ = protocol.NewErrorResponse(req.ID, protocol.ErrUnauthorized, "invalid token", nil)
+						} else {
+							loggedIn = true
+							resp, _ = protocol.NewResponse(req.ID, protocol.AuthLoginResult{Role: "operator"})
+						}
+					case !loggedIn:
+						resp, _ = protocol.NewErrorResponse(req.ID, protocol.ErrUnauthorized, "authentication required", nil)
+					default:
+						// A notification arrives ahead of the response
+						note, _ := protocol.NewNotification("event", map[string]string{"type": "worker.status"})
+						data, _ := json.Marshal(note)
+						conn.Write(append(data, '\n'))
+						resp, _ = protocol.NewResponse(req.ID, protocol.StatusResult{Version: "test", Workers: 2})
+					}
+					data, _ := json.Marshal(resp)
+					conn.Write(append(data, '\n'))
+				}
+			}()
+		}
+	}()
+	return socket
+}
+
+func TestProbeDaemon_Token(t *testing.T) {
+	socket := serveTokenDaemon(t, "s3cret")
+
+	status, _, err := probeDaemon(socket, "s3cret", 5*time.Second)
+	if err != nil {
+		t.Fatalf("expected the probe to log in and succeed, got %v", err)
+	}
+	if status.Version != "test" || status.Workers != 2 {
+		t.Errorf("unexpected status %+v", status)
+	}
+
+	if _, _, err := probeDaemon(socket, "", 5*time.Second); err == nil || !strings.Contains(err.Error(), "authentication required") {
+		t.Errorf("expected an unauthenticated probe to be refused, got %v", err)
+	}
+	if _, _, err := probeDaemon(socket, "wrong", 5*time.Second); err == nil || !strings.Contains(err.Error(), "login failed") {
+		t.Errorf("expected a bad token to fail the login, got %v", err)
+	}
+}
+
+func TestProbeDaemon_NoSocket(t *testing.T) {
+	if _, _, err := probeDaemon(filepath.Join(t.TempDir(), "missing.sock"), "", time.Second); err == nil {
+		t.Error("expected error for a missing socket")
+	}
+}
+
+func TestTunnelSocketName(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"devbox", "devbox.sock"},
+		{"build-01.example.com", "build-01.example.com.sock"},
+		{"me@devbox", "me_devbox.sock"},
+		{"devbox:2222", "devbox_2222.sock"},
+		{"../../etc/passwd", ".._.._etc_passwd.sock"},
+		{"host with spaces", "host_with_spaces.sock"},
+		{"-oProxyCommand=x", "-oProxyCommand_x.sock"},
+		{"", ".sock"},
+	}
+	for _, tt := range tests {
+		if got := tunnelSocketName(tt.host); got != tt.want {
+			t.Errorf("tunnelSocketName(%q) = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestNextTunnelBackoff(t *testing.T) {
+	want := []time.Duration{
+		2 * time.Second,
+		4 * time.Second,
+		8 * time.Second,
+		16 * time.Second,
+		30 * time.Second,
+		30 * time.Second,
+	}
+	backoff := tunnelMinBackoff
+	for i, w := range want {
+		backoff = nextTunnelBackoff(backoff)
+		if backoff != w {
+			t.Errorf("attempt %d: expected %s, got %s", i+1, w, backoff)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"cosa/internal/daemon"
+	"cosa/internal/display"
+	"cosa/internal/job"
+	"cosa/internal/protocol"
+)
+
+// defaultWatchInterval is how often 'cosa status --watch' redraws when no
+// interval is given.
+const defaultWatchInterval = 2 * time.Second
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// StatusFrame is one refresh of 'cosa status --watch' in structured output.
+type StatusFrame struct {
+	Time     time.Time              `json:"time" yaml:"time"`
+	Status   *protocol.StatusResult `json:"status" yaml:"status"`
+	Workers  []protocol.WorkerInfo  `json:"workers" yaml:"workers"`
+	Queued   int                    `json:"queued" yaml:"queued"`
+	Pending  int                    `json:"pending" yaml:"pending"`
+	Running  []protocol.JobInfo     `json:"running" yaml:"running"`
+	BurnRate float64                `json:"burn_rate" yaml:"burn_rate"` // Dollars an hour while watching
+	Alerts   []protocol.SyncAlert   `json:"alerts,omitempty" yaml:"alerts,omitempty"`
+}
+
+// statusWatch holds the latest snapshot and what is needed to redraw it.
+type statusWatch struct {
+	client *daemon.Client
+
+	epoch   int64
+	version uint64
+	state   *protocol.SyncStateResult
+	fetched time.Time
+
+	// Spend when watching started, to work out the burn rate
+	startSpend float64
+	startTime  time.Time
+}
+
+// watchStatus redraws the status every interval until interrupted. The
+// daemon is asked for a new snapshot only after an event says something
+// changed, so an idle daemon is not polled.
+func watchStatus(client *daemon.Client, interval time.Duration) error {
+	if err := client.Subscribe([]string{"*"}); err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	// Coalesce events: one pending signal is enough to refresh
+	changed := make(chan struct{}, 1)
+	lost := make(chan error, 1)
+	go func() {
+		for {
+			if _, err := client.ReadEvent(); err != nil {
+				lost <- err
+				return
+			}
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	w := &statusWatch{client: client}
+	if err := w.refresh(); err != nil {
+		return err
+	}
+	w.render()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	dirty := false
+	for {
+		select {
+		case <-sigCh:
+			return nil
+		case <-lost:
+			return fmt.Errorf("lost connection to the daemon")
+		case <-changed:
+			// Refresh at most once an interval; the ticker picks up the rest
+			if time.Since(w.fetched) < interval {
+				dirty = true
+				continue
+			}
+		case <-ticker.C:
+			if !dirty {
+				// Nothing changed, but elapsed times still move on
+				if !structuredOutput() && isTerminal(os.Stdout) {
+					w.render()
+				}
+				continue
+			}
+		}
+
+		dirty = false
+		if err := w.refresh(); err != nil {
+			return err
+		}
+		w.render()
+	}
+}
+
+// refresh fetches a snapshot unless the last one is still current.
+func (w *statusWatch) refresh() error {
+	result, err := w.client.SyncState(w.epoch, w.version, cfg.ClientIdentity())
+	if err != nil {
+		return err
+	}
+	w.fetched = time.Now()
+	if result.Unchanged {
+		return nil
+	}
+
+	if w.epoch != result.Epoch {
+		// First snapshot, or the daemon restarted
+		w.startTime = w.fetched
+		w.startSpend = spentToday(result.Status)
+	}
+	w.epoch, w.version = result.Epoch, result.Version
+	w.state = result
+
+	// Spend for the day starts again at midnight
+	if spent := spentToday(result.Status); spent < w.startSpend {
+		w.startTime, w.startSpend = w.fetched, spent
+	}
+	return nil
+}
+
+// burnRate is the spend per hour since watching started, or 0 until there
+// is a minute to go on.
+func (w *statusWatch) burnRate() float64 {
+	elapsed := time.Since(w.startTime)
+	if elapsed < time.Minute {
+		return 0
+	}
+	return (spentToday(w.state.Status) - w.startSpend) / elapsed.Hours()
+}
+
+func (w *statusWatch) frame() StatusFrame {
+	f := StatusFrame{
+		Time:     time.Now(),
+		Status:   w.state.Status,
+		Workers:  w.state.Workers,
+		Running:  []protocol.JobInfo{},
+		BurnRate: w.burnRate(),
+		Alerts:   w.state.Alerts,
+	}
+	for _, j := range w.state.Jobs {
+		switch job.Status(j.Status) {
+		case job.StatusQueued:
+			f.Queued++
+		case job.StatusPending:
+			f.Pending++
+		case job.StatusRunning:
+			f.Running = append(f.Running, j)
+		}
+	}
+	return f
+}
+
+func (w *statusWatch) render() {
+	f := w.frame()
+	if structuredOutput() {
+		printStreamEvent(f)
+		return
+	}
+
+	var buf bytes.Buffer
+	status := f.Status
+
+	uptime := time.Duration(status.Uptime)*time.Second + time.Since(w.fetched)
+	fmt.Fprintf(&buf, "Cosa v%s · up %s", status.Version, formatDuration(uptime))
+	if status.Territory != "" {
+		fmt.Fprintf(&buf, " · %s", status.Territory)
+	}
+	if status.Queue != "" {
+		buf.WriteString(" · queue " + status.Queue)
+	}
+	if status.Draining {
+		buf.WriteString(" · draining")
+	}
+	fmt.Fprintf(&buf, "    %s\n\n", f.Time.Format("15:04:05"))
+
+	busy := 0
+	for _, wk := range f.Workers {
+		if wk.CurrentJob != "" {
+			busy++
+		}
+	}
+	fmt.Fprintf(&buf, "Workers:  %d (%d busy, %d idle)\n", len(f.Workers), busy, len(f.Workers)-busy)
+	fmt.Fprintf(&buf, "Queue:    %d queued, %d waiting on dependencies, %d running\n", f.Queued, f.Pending, len(f.Running))
+	if status.MergeQueue > 0 {
+		fmt.Fprintf(&buf, "Merges:   %d running or waiting\n", status.MergeQueue)
+	}
+
+	spend := fmt.Sprintf("$%.2f today", spentToday(status))
+	if b := status.Budget; b != nil && b.DailyLimit > 0 {
+		spend = formatBudget(b)
+	}
+	if f.BurnRate > 0 {
+		spend += fmt.Sprintf(" · $%.2f/h", f.BurnRate)
+	}
+	fmt.Fprintf(&buf, "Spend:    %s\n", spend)
+
+	if len(f.Workers) > 0 {
+		buf.WriteString("\n")
+		table := NewTable("WORKER", "ROLE", "STATUS", "JOB")
+		for _, wk := range f.Workers {
+			jobDesc := "-"
+			if wk.CurrentJob != "" {
+				jobDesc = fmt.Sprintf("%s %s", display.ShortID(wk.CurrentJob), truncate(wk.CurrentJobDesc, 40))
+			}
+			table.AddRow(wk.Name, wk.Role, wk.Status, jobDesc)
+		}
+		table.Render(&buf)
+	}
+
+	if len(f.Running) > 0 {
+		buf.WriteString("\n")
+		table := NewTable("RUNNING", "DESCRIPTION", "WORKER", "ELAPSED")
+		for _, j := range f.Running {
+			elapsed := "-"
+			if j.StartedAt > 0 {
+				elapsed = formatDuration(time.Since(time.Unix(j.StartedAt, 0)))
+			}
+			table.AddRow(display.ShortID(j.ID), truncate(j.Description, 40), valueOrDefault(j.Worker, "-"), elapsed)
+		}
+		table.Render(&buf)
+	}
+
+	if len(f.Alerts) > 0 {
+		buf.WriteString("\n")
+		for _, alert := range f.Alerts {
+			fmt.Fprintf(&buf, "⚑ %s\n", alert.Message)
+		}
+	}
+
+	buf.WriteString("\nWatching for changes (Ctrl+C to stop)\n")
+
+	if isTerminal(os.Stdout) {
+		os.Stdout.WriteString(clearScreen)
+	} else {
+		buf.WriteString(strings.Repeat("─", 40) + "\n")
+	}
+	os.Stdout.Write(buf.Bytes())
+}
+
+// spentToday returns the day's spend from a status, or 0 without a budget.
+func spentToday(status *protocol.StatusResult) float64 {
+	if status == nil || status.Budget == nil {
+		return 0
+	}
+	return status.Budget.SpentToday
+}
+
+// isTerminal reports whether f is a terminal rather than a file or pipe.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"cosa/internal/protocol"
+)
+
+func workerCloneCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clone <source> <name>...",
+		Short: "Add workers set up like an existing one",
+		Long: `Add workers set up like an existing one: the same role, standing orders,
+labels and daily job limit. Each gets a fresh worktree and session, so none
+of the source's context carries over. The model follows the role, as set by
+models.<role>.
+
+Give several names to scale one well-tuned worker into a crew.`,
+		Example: `  cosa worker clone vito sal paulie`,
+		Args:    cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			client, err := connectDaemon()
+			if err != nil {
+				return fmt.Errorf("daemon not running")
+			}
+			defer client.Close()
+
+			source := args[0]
+			var clones []protocol.WorkerInfo
+			for _, name := range args[1:] {
+				resp, err := client.Call(protocol.MethodWorkerClone, protocol.WorkerCloneParams{
+					Source: source,
+					Name:   name,
+				})
+				if err != nil {
+					return err
+				}
+				if resp.Error != nil {
+					return fmt.Errorf("%s: %s", name, resp.Error.Message)
+				}
+
+				var info protocol.WorkerInfo
+				json.Unmarshal(resp.Result, &info)
+				clones = append(clones, info)
+
+				if !structuredOutput() {
+					fmt.Printf("Worker %s cloned from %s (%s, worktree %s)\n", info.Name, source, info.Role, info.Worktree)
+				}
+			}
+
+			if structuredOutput() {
+				return printStructured(clones)
+			}
+			return nil
+		},
+	}
+}
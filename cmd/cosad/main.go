@@ -13,7 +13,7 @@ import (
 )
 
 func main() {
-	cfg, err := config.Load("")
+	cfg, err := config.LoadProfile(config.ActiveProfile())
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
@@ -34,7 +34,7 @@ func main() {
 	}
 
 	fmt.Printf("Cosa daemon v%s started (pid: %d)\n", config.Version, os.Getpid())
-	fmt.Printf("Listening on %s\n", cfg.SocketPath)
+	fmt.Printf("Listening on %s\n", daemon.TransportAddress(cfg.SocketPath))
 
 	// Handle signals for graceful shutdown
 	sigCh := make(chan os.Signal, 1)
@@ -46,6 +46,25 @@ func main() {
 		server.Stop()
 	}()
 
+	// SIGHUP reloads the config
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+
+	go func() {
+		for range hupCh {
+			result, err := server.ReloadConfig("signal")
+			if err != nil {
+				fmt.Printf("Config reload failed: %v\n", err)
+				continue
+			}
+			fmt.Printf("Config reloaded: %d applied, %d waiting for restart\n", len(result.Applied), len(result.Pending))
+		}
+	}()
+
 	server.Wait()
+
+	// Stop is a no-op if the signal handler already ran; this covers
+	// shutdown requests and idle shutdown.
+	server.Stop()
 	fmt.Println("Daemon stopped")
 }
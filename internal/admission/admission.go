@@ -0,0 +1,168 @@
+// Package admission asks an external policy endpoint whether to accept each
+// new job, so that organisations can enforce their own rules on the queue
+// without changing Cosa.
+package admission
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"cosa/internal/config"
+)
+
+// defaultTimeout bounds a call when no timeout is configured.
+const defaultTimeout = 5 * time.Second
+
+// maxResponse is the most of a response body that is read.
+const maxResponse = 64 * 1024
+
+// Request describes a job awaiting admission. It is the body POSTed to the
+// endpoint.
+type Request struct {
+	JobID         string            `json:"job_id"`
+	Description   string            `json:"description"`
+	Body          string            `json:"body,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	Priority      int               `json:"priority"`
+	Worker        string            `json:"worker,omitempty"`
+	Model         string            `json:"model,omitempty"`
+	Requester     string            `json:"requester,omitempty"`
+	EstimatedCost float64           `json:"estimated_cost"` // Dollars, from the average cost of past jobs
+}
+
+// Decision is the endpoint's answer. Labels and priority, when set, change
+// the job as it is accepted.
+type Decision struct {
+	Allow    bool              `json:"allow"`
+	Reason   string            `json:"reason,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`   // Labels to set; an empty value removes one
+	Priority int               `json:"priority,omitempty"` // 1-5; 0 leaves it unchanged
+}
+
+// Apply returns labels and priority with the decision's changes made.
+func (d Decision) Apply(labels map[string]string, priority int) (map[string]string, int) {
+	if len(d.Labels) > 0 {
+		merged := make(map[string]string, len(labels)+len(d.Labels))
+		for k, v := range labels {
+			merged[k] = v
+		}
+		for k, v := range d.Labels {
+			if v == "" {
+				delete(merged, k)
+			} else {
+				merged[k] = v
+			}
+		}
+		labels = merged
+	}
+	if d.Priority > 0 {
+		priority = d.Priority
+	}
+	return labels, priority
+}
+
+// Changes reports whether the decision changes the job.
+func (d Decision) Changes() bool {
+	return len(d.Labels) > 0 || d.Priority > 0
+}
+
+// RejectedError is returned for a job the policy does not admit.
+type RejectedError struct {
+	Reason string
+}
+
+func (e *RejectedError) Error() string {
+	if e.Reason == "" {
+		return "job rejected by admission policy"
+	}
+	return "job rejected by admission policy: " + e.Reason
+}
+
+// Controller calls the configured policy endpoint.
+type Controller struct {
+	cfg    config.AdmissionConfig
+	client *http.Client
+}
+
+// New creates a controller for cfg.
+func New(cfg config.AdmissionConfig) *Controller {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Controller{
+		cfg:    cfg,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Enabled reports whether an endpoint is configured.
+func (c *Controller) Enabled() bool {
+	return c != nil && c.cfg.URL != ""
+}
+
+// Admit asks the endpoint about a job. If the endpoint cannot be reached or
+// gives an invalid answer, the error says why and the decision follows the
+// fail_closed setting. With no endpoint every job is admitted.
+func (c *Controller) Admit(ctx context.Context, req Request) (Decision, error) {
+	if !c.Enabled() {
+		return Decision{Allow: true}, nil
+	}
+
+	decision, err := c.call(ctx, req)
+	if err != nil {
+		if c.cfg.FailClosed {
+			return Decision{Reason: "admission endpoint unavailable"}, err
+		}
+		return Decision{Allow: true}, err
+	}
+	return decision, nil
+}
+
+func (c *Controller) call(ctx context.Context, req Request) (Decision, error) {
+	var decision Decision
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return decision, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return decision, err
+	}
+	for key, value := range c.cfg.Headers {
+		httpReq.Header.Set(key, value)
+	}
+	if c.cfg.Secret != "" {
+		httpReq.Header.Set("X-Cosa-Secret", c.cfg.Secret)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return decision, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponse))
+	if err != nil {
+		return decision, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return decision, fmt.Errorf("admission endpoint returned %s", resp.Status)
+	}
+	if err := json.Unmarshal(data, &decision); err != nil {
+		return decision, fmt.Errorf("invalid admission response: %v", err)
+	}
+	if decision.Priority < 0 || decision.Priority > 5 {
+		return Decision{}, fmt.Errorf("invalid admission response: priority %d is not 1-5", decision.Priority)
+	}
+	decision.Reason = strings.TrimSpace(decision.Reason)
+	return decision, nil
+}
@@ -0,0 +1,120 @@
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cosa/internal/config"
+)
+
+func TestAdmit_Disabled(t *testing.T) {
+	c := New(config.AdmissionConfig{})
+	if c.Enabled() {
+		t.Fatal("expected controller without URL to be disabled")
+	}
+	decision, err := c.Admit(context.Background(), Request{Description: "anything"})
+	if err != nil || !decision.Allow {
+		t.Errorf("expected job admitted, got %+v, %v", decision, err)
+	}
+}
+
+func TestAdmit_Endpoint(t *testing.T) {
+	var got Request
+	var secret string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secret = r.Header.Get("X-Cosa-Secret")
+		json.NewDecoder(r.Body).Decode(&got)
+		if got.Labels["team"] == "" {
+			w.Write([]byte(`{"allow": false, "reason": "jobs need a team label"}`))
+			return
+		}
+		w.Write([]byte(`{"allow": true, "labels": {"reviewed-by": "policy", "team": ""}, "priority": 1}`))
+	}))
+	defer srv.Close()
+
+	c := New(config.AdmissionConfig{URL: srv.URL, Secret: "s3cret"})
+
+	decision, err := c.Admit(context.Background(), Request{Description: "Fix login", Requester: "carmela", EstimatedCost: 1.5})
+	if err != nil {
+		t.Fatalf("Admit: %v", err)
+	}
+	if decision.Allow || decision.Reason != "jobs need a team label" {
+		t.Errorf("expected rejection with reason, got %+v", decision)
+	}
+	if got.Requester != "carmela" || got.EstimatedCost != 1.5 {
+		t.Errorf("expected requester and cost in request, got %+v", got)
+	}
+	if secret != "s3cret" {
+		t.Errorf("expected secret header, got %q", secret)
+	}
+
+	decision, err = c.Admit(context.Background(), Request{Description: "Fix login", Labels: map[string]string{"team": "web", "area": "auth"}, Priority: 3})
+	if err != nil {
+		t.Fatalf("Admit: %v", err)
+	}
+	if !decision.Allow {
+		t.Fatalf("expected job admitted, got %+v", decision)
+	}
+	labels, priority := decision.Apply(map[string]string{"team": "web", "area": "auth"}, 3)
+	if priority != 1 {
+		t.Errorf("expected priority 1, got %d", priority)
+	}
+	if len(labels) != 2 || labels["area"] != "auth" || labels["reviewed-by"] != "policy" {
+		t.Errorf("expected team removed and reviewed-by added, got %v", labels)
+	}
+}
+
+func TestAdmit_FailureModes(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"server error", func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "boom", http.StatusInternalServerError)
+		}},
+		{"invalid JSON", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("ok"))
+		}},
+		{"invalid priority", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"allow": true, "priority": 9}`))
+		}},
+		{"timeout", func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+			w.Write([]byte(`{"allow": true}`))
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(tt.handler)
+			defer srv.Close()
+
+			for _, failClosed := range []bool{false, true} {
+				c := New(config.AdmissionConfig{URL: srv.URL, Timeout: 50 * time.Millisecond, FailClosed: failClosed})
+				decision, err := c.Admit(context.Background(), Request{Description: "x"})
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				if decision.Allow == failClosed {
+					t.Errorf("fail_closed=%t: expected allow=%t, got %+v", failClosed, !failClosed, decision)
+				}
+			}
+		})
+	}
+}
+
+func TestRejectedError(t *testing.T) {
+	var err error = &RejectedError{Reason: "over budget"}
+	var rejected *RejectedError
+	if !errors.As(err, &rejected) || rejected.Reason != "over budget" {
+		t.Fatalf("expected RejectedError, got %v", err)
+	}
+	if err.Error() != "job rejected by admission policy: over budget" {
+		t.Errorf("unexpected message: %s", err.Error())
+	}
+}
@@ -0,0 +1,73 @@
+package admission
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ThrottledError is returned for a job refused to relieve the queue: the
+// queue is full, or its client is submitting jobs too fast. Unlike a
+// rejection it is temporary, and the job may be submitted again after
+// RetryAfter.
+type ThrottledError struct {
+	Reason     string
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("%s; retry in %s", e.Reason, e.RetryAfter.Round(time.Second))
+}
+
+// RateLimiter limits how many jobs each client submits a minute. A client
+// may submit a minute's worth at once, after which its allowance refills
+// steadily.
+type RateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a rate limiter with no clients.
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{buckets: make(map[string]*bucket)}
+}
+
+// Allow counts a job from client against a limit of perMinute jobs a
+// minute. It returns 0 if the job is allowed, or how long the client must
+// wait before its next job is. A perMinute of 0 or less allows every job.
+func (r *RateLimiter) Allow(client string, perMinute int, now time.Time) time.Duration {
+	if perMinute <= 0 {
+		return 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Clients idle for a minute have a full allowance again, so they need
+	// not be remembered
+	for name, b := range r.buckets {
+		if name != client && now.Sub(b.last) >= time.Minute {
+			delete(r.buckets, name)
+		}
+	}
+
+	limit := float64(perMinute)
+	perSecond := limit / 60
+	b, ok := r.buckets[client]
+	if !ok {
+		b = &bucket{tokens: limit, last: now}
+		r.buckets[client] = b
+	}
+	b.tokens = min(limit, b.tokens+now.Sub(b.last).Seconds()*perSecond)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / perSecond * float64(time.Second))
+}
@@ -0,0 +1,49 @@
+package admission
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	r := NewRateLimiter()
+	now := time.Now()
+
+	// A minute's worth may be submitted at once
+	for i := 0; i < 3; i++ {
+		if wait := r.Allow("ci", 3, now); wait != 0 {
+			t.Fatalf("expected job %d to be allowed, got a wait of %s", i+1, wait)
+		}
+	}
+	wait := r.Allow("ci", 3, now)
+	if wait != 20*time.Second {
+		t.Errorf("expected to wait 20s for the next job, got %s", wait)
+	}
+
+	// Other clients have their own allowance
+	if wait := r.Allow("alice", 3, now); wait != 0 {
+		t.Errorf("expected another client to be allowed, got a wait of %s", wait)
+	}
+
+	// The allowance refills steadily
+	if wait := r.Allow("ci", 3, now.Add(20*time.Second)); wait != 0 {
+		t.Errorf("expected a job to be allowed after 20s, got a wait of %s", wait)
+	}
+	if wait := r.Allow("ci", 3, now.Add(25*time.Second)); wait != 15*time.Second {
+		t.Errorf("expected to wait 15s more, got %s", wait)
+	}
+
+	// No limit
+	for i := 0; i < 10; i++ {
+		if wait := r.Allow("ci", 0, now); wait != 0 {
+			t.Fatalf("expected no limit, got a wait of %s", wait)
+		}
+	}
+}
+
+func TestThrottledError(t *testing.T) {
+	err := &ThrottledError{Reason: "queue is full (500 jobs waiting)", RetryAfter: 1500 * time.Millisecond}
+	if got := err.Error(); got != "queue is full (500 jobs waiting); retry in 2s" {
+		t.Errorf("unexpected message %q", got)
+	}
+}
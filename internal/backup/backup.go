@@ -0,0 +1,307 @@
+// Package backup keeps incremental backups of the data directory. File
+// contents are stored once, by hash, so each backup only adds the files that
+// changed since the last; a backup itself is a manifest of paths and hashes.
+package backup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// idFormat names backups by the time they were taken.
+const idFormat = "20060102-150405"
+
+// Entry is one file in a backup.
+type Entry struct {
+	Path string      `json:"path"` // Slash-separated, relative to the data directory
+	Hash string      `json:"hash"` // SHA-256 of the contents
+	Size int64       `json:"size"`
+	Mode fs.FileMode `json:"mode"`
+}
+
+// Manifest describes a backup.
+type Manifest struct {
+	ID      string    `json:"id"`
+	Created time.Time `json:"created"`
+	DataDir string    `json:"data_dir"`
+	Files   []Entry   `json:"files"`
+}
+
+// Size returns the total size of the files in the backup.
+func (m *Manifest) Size() int64 {
+	var size int64
+	for _, f := range m.Files {
+		size += f.Size
+	}
+	return size
+}
+
+// Stats describes what creating a backup stored.
+type Stats struct {
+	Files      int   `json:"files"`
+	Bytes      int64 `json:"bytes"`
+	NewObjects int   `json:"new_objects"` // Contents not already in the repository
+	NewBytes   int64 `json:"new_bytes"`
+}
+
+// Repository is a directory of backups: a manifest for each in snapshots/,
+// and the contents they share in objects/.
+type Repository struct {
+	root string
+}
+
+// Open opens the repository at root, creating it if needed.
+func Open(root string) (*Repository, error) {
+	for _, dir := range []string{"snapshots", "objects"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0700); err != nil {
+			return nil, fmt.Errorf("failed to create backup directory: %w", err)
+		}
+	}
+	return &Repository{root: root}, nil
+}
+
+// Root returns the repository's directory.
+func (r *Repository) Root() string {
+	return r.root
+}
+
+// Create backs up the files under dataDir. skip is called with the
+// slash-separated relative path of each file and directory, and leaves out
+// those it returns true for; only regular files are backed up. JSONL files
+// are appended to a line at a time, so one is backed up to its last
+// complete line.
+func (r *Repository) Create(dataDir string, skip func(path string, dir bool) bool) (*Manifest, *Stats, error) {
+	now := time.Now().UTC()
+	m := &Manifest{
+		ID:      r.newID(now),
+		Created: now,
+		DataDir: dataDir,
+		Files:   []Entry{},
+	}
+	stats := &Stats{}
+
+	err := filepath.WalkDir(dataDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dataDir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if skip != nil && skip(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		entry, added, err := r.store(path, strings.HasSuffix(rel, ".jsonl"))
+		if err != nil {
+			return fmt.Errorf("failed to back up %s: %w", rel, err)
+		}
+		entry.Path = rel
+		entry.Mode = info.Mode().Perm()
+		m.Files = append(m.Files, entry)
+
+		stats.Files++
+		stats.Bytes += entry.Size
+		if added {
+			stats.NewObjects++
+			stats.NewBytes += entry.Size
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := writeFileAtomic(r.manifestPath(m.ID), data, 0600); err != nil {
+		return nil, nil, fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return m, stats, nil
+}
+
+// newID returns an unused ID for a backup taken at t.
+func (r *Repository) newID(t time.Time) string {
+	id := t.Format(idFormat)
+	for n := 2; ; n++ {
+		if _, err := os.Stat(r.manifestPath(id)); os.IsNotExist(err) {
+			return id
+		}
+		id = fmt.Sprintf("%s-%d", t.Format(idFormat), n)
+	}
+}
+
+// store adds the contents of the file at path to the repository, cut to
+// the last complete line if lines is set. It reports whether the contents
+// were new.
+func (r *Repository) store(path string, lines bool) (Entry, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Entry{}, false, err
+	}
+	if lines {
+		data = data[:bytes.LastIndexByte(data, '\n')+1]
+	}
+
+	sum := sha256.Sum256(data)
+	entry := Entry{Hash: hex.EncodeToString(sum[:]), Size: int64(len(data))}
+
+	object := r.objectPath(entry.Hash)
+	if _, err := os.Stat(object); err == nil {
+		return entry, false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(object), 0700); err != nil {
+		return Entry{}, false, err
+	}
+	if err := writeFileAtomic(object, data, 0600); err != nil {
+		return Entry{}, false, err
+	}
+	return entry, true, nil
+}
+
+// List returns the backups in the repository, oldest first.
+func (r *Repository) List() ([]*Manifest, error) {
+	entries, err := os.ReadDir(filepath.Join(r.root, "snapshots"))
+	if err != nil {
+		return nil, err
+	}
+
+	var manifests []*Manifest
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if entry.IsDir() || !ok {
+			continue
+		}
+		m, err := r.Load(id)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, m)
+	}
+	sort.Slice(manifests, func(i, j int) bool {
+		return manifests[i].Created.Before(manifests[j].Created)
+	})
+	return manifests, nil
+}
+
+// Load returns the manifest of the backup with the given ID.
+func (r *Repository) Load(id string) (*Manifest, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) {
+		return nil, fmt.Errorf("backup %s not found", id)
+	}
+	data, err := os.ReadFile(r.manifestPath(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("backup %s not found", id)
+		}
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid manifest for backup %s: %w", id, err)
+	}
+	return &m, nil
+}
+
+// Latest returns the most recent backup, or nil if there are none.
+func (r *Repository) Latest() (*Manifest, error) {
+	manifests, err := r.List()
+	if err != nil || len(manifests) == 0 {
+		return nil, err
+	}
+	return manifests[len(manifests)-1], nil
+}
+
+// Restore writes the files of backup m into dir, which must not exist yet.
+// Each file's contents are checked against its hash as they are restored.
+func (r *Repository) Restore(m *Manifest, dir string) error {
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("%s already exists", dir)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	for _, f := range m.Files {
+		if len(f.Hash) != 2*sha256.Size {
+			return fmt.Errorf("invalid hash for %s in backup", f.Path)
+		}
+		data, err := os.ReadFile(r.objectPath(f.Hash))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", f.Path, err)
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != f.Hash {
+			return fmt.Errorf("%s is corrupt in the backup: its contents do not match their hash", f.Path)
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(f.Path))
+		if !strings.HasPrefix(target, filepath.Clean(dir)+string(filepath.Separator)) {
+			return fmt.Errorf("invalid path in backup: %s", f.Path)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, data, f.Mode.Perm()|0600); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", f.Path, err)
+		}
+	}
+	return nil
+}
+
+func (r *Repository) manifestPath(id string) string {
+	return filepath.Join(r.root, "snapshots", id+".json")
+}
+
+func (r *Repository) objectPath(hash string) string {
+	return filepath.Join(r.root, "objects", hash[:2], hash[2:])
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames
+// it into place, so that a crash never leaves a partial file.
+func writeFileAtomic(path string, data []byte, perm fs.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRepository_CreateIsIncremental(t *testing.T) {
+	data := t.TempDir()
+	writeFile(t, filepath.Join(data, "jobs", "a.json"), `{"id":"a"}`)
+	writeFile(t, filepath.Join(data, "workers", "paulie.json"), `{"name":"paulie"}`)
+	writeFile(t, filepath.Join(data, "daemon.log"), "noise")
+
+	repo, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	skip := func(path string, dir bool) bool { return path == "daemon.log" }
+
+	first, stats, err := repo.Create(data, skip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Files) != 2 || stats.NewObjects != 2 {
+		t.Fatalf("expected 2 new files, got %d files and %d new objects", len(first.Files), stats.NewObjects)
+	}
+
+	writeFile(t, filepath.Join(data, "jobs", "b.json"), `{"id":"b"}`)
+	_, stats, err = repo.Create(data, skip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Files != 3 || stats.NewObjects != 1 {
+		t.Errorf("expected only the new job to be stored, got %d files and %d new objects", stats.Files, stats.NewObjects)
+	}
+
+	manifests, err := repo.List()
+	if err != nil || len(manifests) != 2 || manifests[0].ID != first.ID {
+		t.Fatalf("expected both backups oldest first, got %v (%v)", manifests, err)
+	}
+}
+
+func TestRepository_CreateCutsPartialLines(t *testing.T) {
+	data := t.TempDir()
+	writeFile(t, filepath.Join(data, "events.jsonl"), "{\"id\":\"1\"}\n{\"id\":")
+
+	repo, _ := Open(t.TempDir())
+	m, _, err := repo.Create(data, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "restored")
+	if err := repo.Restore(m, dir); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(filepath.Join(dir, "events.jsonl"))
+	if string(got) != "{\"id\":\"1\"}\n" {
+		t.Errorf("expected the ledger to end at its last complete line, got %q", got)
+	}
+}
+
+func TestRepository_RestoreDetectsCorruption(t *testing.T) {
+	data := t.TempDir()
+	writeFile(t, filepath.Join(data, "jobs", "a.json"), `{"id":"a"}`)
+
+	repo, _ := Open(t.TempDir())
+	m, _, err := repo.Create(data, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "restored")
+	if err := repo.Restore(m, dir); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, "jobs", "a.json")); string(got) != `{"id":"a"}` {
+		t.Errorf("expected the job to be restored, got %q", got)
+	}
+
+	os.WriteFile(repo.objectPath(m.Files[0].Hash), []byte("garbage"), 0600)
+	err = repo.Restore(m, filepath.Join(t.TempDir(), "again"))
+	if err == nil || !strings.Contains(err.Error(), "corrupt") {
+		t.Errorf("expected a corrupt object to be reported, got %v", err)
+	}
+}
+
+func TestRepository_LoadRejectsPaths(t *testing.T) {
+	repo, _ := Open(t.TempDir())
+	if _, err := repo.Load("../secrets"); err == nil {
+		t.Error("expected an ID with a path to be rejected")
+	}
+}
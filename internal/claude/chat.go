@@ -0,0 +1,188 @@
+package claude
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ChatMessage is one message of a chat transcript.
+type ChatMessage struct {
+	Role      string    `json:"role"` // "user" or "assistant"
+	Content   string    `json:"content"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ChatTranscript is a persisted chat with the underboss.
+type ChatTranscript struct {
+	ID        string        `json:"id"`                   // Chat session ID
+	SessionID string        `json:"session_id,omitempty"` // Claude session ID for resuming
+	Messages  []ChatMessage `json:"messages"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// ChatStore persists chat transcripts, one file per chat session.
+type ChatStore struct {
+	path   string                     // Directory for transcript files
+	chats  map[string]*ChatTranscript // Keyed by chat session ID
+	cipher *Cipher                    // Encrypts transcript files; nil writes plaintext
+	mu     sync.RWMutex
+}
+
+// NewChatStore creates a chat store, loading existing transcripts from path.
+func NewChatStore(path string) (*ChatStore, error) {
+	return NewEncryptedChatStore(path, nil)
+}
+
+// NewEncryptedChatStore creates a chat store whose files are encrypted with
+// c. Transcripts still in plaintext are encrypted as they are loaded.
+func NewEncryptedChatStore(path string, c *Cipher) (*ChatStore, error) {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create chats directory: %w", err)
+	}
+
+	store := &ChatStore{
+		path:   path,
+		chats:  make(map[string]*ChatTranscript),
+		cipher: c,
+	}
+
+	if err := store.loadAll(true); err != nil {
+		return nil, fmt.Errorf("failed to load chats: %w", err)
+	}
+
+	return store, nil
+}
+
+// ReadChatStore loads the transcripts in path, decrypting them with c,
+// without changing anything on disk, as ReadSessionStore does for sessions.
+func ReadChatStore(path string, c *Cipher) (*ChatStore, error) {
+	store := &ChatStore{
+		path:   path,
+		chats:  make(map[string]*ChatTranscript),
+		cipher: c,
+	}
+	if err := store.loadAll(false); err != nil {
+		return nil, fmt.Errorf("failed to load chats: %w", err)
+	}
+	return store, nil
+}
+
+// Save persists a transcript to disk.
+func (s *ChatStore) Save(t *ChatTranscript) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t.UpdatedAt = time.Now()
+
+	// Store a copy so later changes by the caller are not shared
+	stored := *t
+	stored.Messages = append([]ChatMessage(nil), t.Messages...)
+	s.chats[t.ID] = &stored
+
+	data, err := json.MarshalIndent(&stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal chat: %w", err)
+	}
+	if data, err = s.cipher.seal(data); err != nil {
+		return fmt.Errorf("failed to encrypt chat: %w", err)
+	}
+
+	if err := os.WriteFile(s.chatFilePath(t.ID), data, 0600); err != nil {
+		return fmt.Errorf("failed to write chat file: %w", err)
+	}
+
+	return nil
+}
+
+// Load retrieves a transcript by chat session ID.
+func (s *ChatStore) Load(id string) (*ChatTranscript, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.chats[id]
+	if !ok {
+		return nil, fmt.Errorf("chat %q not found", id)
+	}
+	return copyTranscript(t), nil
+}
+
+// Latest returns the most recently updated transcript, or nil if there is none.
+func (s *ChatStore) Latest() *ChatTranscript {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var latest *ChatTranscript
+	for _, t := range s.chats {
+		if latest == nil || t.UpdatedAt.After(latest.UpdatedAt) {
+			latest = t
+		}
+	}
+	if latest == nil {
+		return nil
+	}
+	return copyTranscript(latest)
+}
+
+// Count returns the number of stored transcripts.
+func (s *ChatStore) Count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.chats)
+}
+
+func copyTranscript(t *ChatTranscript) *ChatTranscript {
+	c := *t
+	c.Messages = append([]ChatMessage(nil), t.Messages...)
+	return &c
+}
+
+func (s *ChatStore) chatFilePath(id string) string {
+	return filepath.Join(s.path, filepath.Base(id)+".json")
+}
+
+// loadAll loads every transcript, encrypting those still in plaintext if
+// reseal is set.
+func (s *ChatStore) loadAll(reseal bool) error {
+	entries, err := os.ReadDir(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		filePath := filepath.Join(s.path, entry.Name())
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			continue // Skip unreadable files
+		}
+		plaintext, err := s.cipher.open(data)
+		if err != nil {
+			continue // Skip files encrypted with another key, or none
+		}
+
+		var t ChatTranscript
+		if err := json.Unmarshal(plaintext, &t); err != nil || t.ID == "" {
+			continue // Skip unparseable files
+		}
+
+		s.chats[t.ID] = &t
+		if reseal && s.cipher != nil && !Sealed(data) {
+			if sealed, err := s.cipher.seal(plaintext); err == nil {
+				os.WriteFile(filePath, sealed, 0600)
+			}
+		}
+	}
+
+	return nil
+}
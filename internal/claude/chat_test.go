@@ -0,0 +1,91 @@
+package claude
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChatStore_SaveAndReload(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewChatStore(dir)
+	if err != nil {
+		t.Fatalf("failed to create chat store: %v", err)
+	}
+
+	chat := &ChatTranscript{
+		ID:        "chat-1",
+		SessionID: "claude-session-1",
+		CreatedAt: time.Now(),
+		Messages: []ChatMessage{
+			{Role: "assistant", Content: "Ready, boss.", Timestamp: time.Now()},
+			{Role: "user", Content: "How are the soldati?", Timestamp: time.Now()},
+		},
+	}
+	if err := store.Save(chat); err != nil {
+		t.Fatalf("failed to save chat: %v", err)
+	}
+
+	// Reload from disk, as after a daemon restart
+	reloaded, err := NewChatStore(dir)
+	if err != nil {
+		t.Fatalf("failed to reload chat store: %v", err)
+	}
+	if reloaded.Count() != 1 {
+		t.Fatalf("expected 1 chat, got %d", reloaded.Count())
+	}
+
+	loaded, err := reloaded.Load("chat-1")
+	if err != nil {
+		t.Fatalf("failed to load chat: %v", err)
+	}
+	if loaded.SessionID != "claude-session-1" {
+		t.Errorf("expected session ID claude-session-1, got %s", loaded.SessionID)
+	}
+	if len(loaded.Messages) != 2 || loaded.Messages[1].Content != "How are the soldati?" {
+		t.Errorf("unexpected messages: %+v", loaded.Messages)
+	}
+}
+
+func TestChatStore_Load_NotFound(t *testing.T) {
+	store, _ := NewChatStore(t.TempDir())
+
+	if _, err := store.Load("missing"); err == nil {
+		t.Error("expected error for missing chat")
+	}
+}
+
+func TestChatStore_Latest(t *testing.T) {
+	store, _ := NewChatStore(t.TempDir())
+
+	if store.Latest() != nil {
+		t.Error("expected no latest chat in empty store")
+	}
+
+	store.Save(&ChatTranscript{ID: "chat-old"})
+	time.Sleep(time.Millisecond)
+	store.Save(&ChatTranscript{ID: "chat-new"})
+
+	if latest := store.Latest(); latest == nil || latest.ID != "chat-new" {
+		t.Errorf("expected chat-new to be latest, got %+v", latest)
+	}
+
+	// Saving the older chat again makes it the latest
+	time.Sleep(time.Millisecond)
+	store.Save(&ChatTranscript{ID: "chat-old"})
+	if latest := store.Latest(); latest.ID != "chat-old" {
+		t.Errorf("expected chat-old to be latest, got %s", latest.ID)
+	}
+}
+
+func TestChatStore_LoadReturnsCopy(t *testing.T) {
+	store, _ := NewChatStore(t.TempDir())
+	store.Save(&ChatTranscript{ID: "chat-1", Messages: []ChatMessage{{Role: "user", Content: "hi"}}})
+
+	loaded, _ := store.Load("chat-1")
+	loaded.Messages[0].Content = "changed"
+
+	again, _ := store.Load("chat-1")
+	if again.Messages[0].Content != "hi" {
+		t.Error("expected stored transcript to be unaffected by caller changes")
+	}
+}
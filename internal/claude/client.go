@@ -4,12 +4,14 @@ package claude
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"strings"
 	"sync"
+	"time"
 )
 
 // Client manages a Claude Code CLI process.
@@ -20,6 +22,11 @@ type Client struct {
 	sessionID string
 	workdir   string
 	mcpConfig string // Path to MCP config file
+	permMode  string // --permission-mode; empty skips permission checks
+	env       []string
+
+	strictOutput  bool
+	onSchemaDrift func(SchemaDrift)
 
 	cmd    *exec.Cmd
 	stdin  io.WriteCloser
@@ -39,6 +46,21 @@ type ClientConfig struct {
 	MaxTurns  int
 	Workdir   string
 	MCPConfig string // Path to MCP config file (optional)
+
+	// PermissionMode is passed as --permission-mode, such as "plan" for a
+	// session that may read but not edit. Empty skips permission checks.
+	PermissionMode string
+
+	// Env is added to the environment Claude runs in, as "KEY=value".
+	Env []string
+
+	// StrictOutput fails the session on output that drifted from the
+	// schema the parser knows, rather than parsing what it can.
+	StrictOutput bool
+
+	// OnSchemaDrift, if set, is called for each kind of drift the output
+	// shows, once per session.
+	OnSchemaDrift func(SchemaDrift)
 }
 
 // NewClient creates a new Claude Code client.
@@ -56,8 +78,13 @@ func NewClient(cfg ClientConfig) *Client {
 		maxTurns:  cfg.MaxTurns,
 		workdir:   cfg.Workdir,
 		mcpConfig: cfg.MCPConfig,
+		permMode:  cfg.PermissionMode,
+		env:       cfg.Env,
 		events:    make(chan Event, 100),
 		done:      make(chan struct{}),
+
+		strictOutput:  cfg.StrictOutput,
+		onSchemaDrift: cfg.OnSchemaDrift,
 	}
 }
 
@@ -71,6 +98,22 @@ func (c *Client) Done() <-chan struct{} {
 	return c.done
 }
 
+// Model returns the model the client runs, or "" for the CLI's default.
+func (c *Client) Model() string {
+	return c.model
+}
+
+// MCPConfig returns the path of the MCP config the client runs with, or ""
+// for none.
+func (c *Client) MCPConfig() string {
+	return c.mcpConfig
+}
+
+// Workdir returns the directory the client runs in.
+func (c *Client) Workdir() string {
+	return c.workdir
+}
+
 // SessionID returns the current session ID (if resumed).
 func (c *Client) SessionID() string {
 	return c.sessionID
@@ -86,6 +129,11 @@ func (c *Client) CloneConfig(workdir string) ClientConfig {
 		MaxTurns:  c.maxTurns,
 		Workdir:   workdir,
 		MCPConfig: c.mcpConfig,
+
+		PermissionMode: c.permMode,
+		Env:            c.env,
+		StrictOutput:   c.strictOutput,
+		OnSchemaDrift:  c.onSchemaDrift,
 	}
 }
 
@@ -100,25 +148,16 @@ func (c *Client) Start(ctx context.Context, prompt string) error {
 
 	args := c.buildArgs(prompt)
 
-	// Use 'script' to allocate a PTY for Claude
-	// This is needed because Node.js (Claude) buffers stdout when connected to a pipe
-	// but writes immediately when connected to a terminal/PTY
-	claudeCmd := c.binary
-	for _, arg := range args {
-		// Escape single quotes in arguments
-		escaped := strings.ReplaceAll(arg, "'", "'\"'\"'")
-		claudeCmd += " '" + escaped + "'"
-	}
-
-	c.cmd = exec.CommandContext(ctx, "script", "-q", "/dev/null", "/bin/bash", "-c", claudeCmd)
+	c.cmd = claudeCommand(ctx, c.binary, args)
 	if c.workdir != "" {
 		c.cmd.Dir = c.workdir
 	}
 
 	// Set up clean environment for Claude:
 	// - Filter NODE_OPTIONS to prevent debugger from blocking startup
+	// - Add the client's own variables
 	env := filterEnv(os.Environ(), "NODE_OPTIONS")
-	c.cmd.Env = env
+	c.cmd.Env = append(env, c.env...)
 
 	var err error
 	c.stdin, err = c.cmd.StdinPipe()
@@ -183,13 +222,63 @@ func (c *Client) Stop() error {
 	return nil
 }
 
+// Interrupt asks Claude to wind down, with a ^C on its terminal or on
+// Windows a Ctrl+Break.
+func (c *Client) Interrupt() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cmd == nil || c.cmd.Process == nil {
+		return fmt.Errorf("session not started")
+	}
+
+	return interruptClaude(c.cmd.Process, c.stdin)
+}
+
+// StopGraceful interrupts the Claude session and waits up to grace for it to
+// exit before killing it. A zero grace kills immediately.
+func (c *Client) StopGraceful(grace time.Duration) error {
+	c.mu.Lock()
+	done := c.done
+	c.mu.Unlock()
+
+	if grace > 0 && c.Interrupt() == nil {
+		select {
+		case <-done:
+			return nil
+		case <-time.After(grace):
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stdin != nil {
+		c.stdin.Close()
+	}
+
+	if c.cmd != nil && c.cmd.Process != nil {
+		// Kill the whole group so a wrapper doesn't leave claude orphaned
+		killProcessGroup(c.cmd.Process)
+		if err := c.cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (c *Client) buildArgs(prompt string) []string {
 	args := []string{
 		"--print",
 		"--verbose",
-		"--dangerously-skip-permissions",
-		"--output-format", "stream-json",
 	}
+	if c.permMode != "" {
+		args = append(args, "--permission-mode", c.permMode)
+	} else {
+		args = append(args, "--dangerously-skip-permissions")
+	}
+	args = append(args, "--output-format", "stream-json")
 
 	if c.model != "" {
 		args = append(args, "--model", c.model)
@@ -214,6 +303,8 @@ func (c *Client) buildArgs(prompt string) []string {
 
 func (c *Client) readOutput() {
 	parser := NewParser()
+	parser.Strict = c.strictOutput
+	parser.OnDrift = c.onSchemaDrift
 	scanner := bufio.NewScanner(c.stdout)
 
 	// Handle very long lines
@@ -239,19 +330,30 @@ func (c *Client) readOutput() {
 			line = line[jsonStart:]
 		}
 
-		event, err := parser.ParseLine(line)
+		events, err := parser.Parse(line)
+		var schemaErr *SchemaError
+		if errors.As(err, &schemaErr) {
+			// A strict parser refused the line. A refused result still
+			// ends the session, as a failure
+			if schemaErr.Drift.Type == "result" {
+				c.events <- Event{Type: EventResult, Result: &Result{Message: err.Error()}}
+			} else {
+				c.events <- Event{Type: EventError, Error: err.Error()}
+			}
+			continue
+		}
 		if err != nil {
 			// Silently skip lines that don't parse as valid JSON
 			// These are likely terminal control sequences
 			continue
 		}
 
-		if event != nil {
+		for _, event := range events {
 			// Capture session ID from init message
 			if event.Type == EventInit && event.SessionID != "" {
 				c.sessionID = event.SessionID
 			}
-			c.events <- *event
+			c.events <- event
 		}
 	}
 }
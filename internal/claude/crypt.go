@@ -0,0 +1,139 @@
+package claude
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DataKeyEnv is the environment variable holding the data key, which takes
+// precedence over the OS keychain.
+const DataKeyEnv = "COSA_DATA_KEY"
+
+// DataKeySize is the size of a data key in bytes, for AES-256.
+const DataKeySize = 32
+
+// sealedMagic begins every file a Cipher seals, telling it apart from the
+// JSON the stores write without one.
+var sealedMagic = []byte("cosa-sealed-v1\n")
+
+// ErrNoDataKey is returned for a sealed file read without a data key.
+var ErrNoDataKey = errors.New("file is encrypted, but no data key is configured")
+
+// Cipher encrypts the files of the session, transcript and chat stores at
+// rest with AES-256-GCM. Files written without one stay readable, so
+// encryption can be turned on for existing data; each is sealed the next
+// time it is written. A nil Cipher writes plaintext.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher creates a cipher from a DataKeySize-byte key.
+func NewCipher(key []byte) (*Cipher, error) {
+	if len(key) != DataKeySize {
+		return nil, fmt.Errorf("data key must be %d bytes, got %d", DataKeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// ParseDataKey decodes a data key written as hex or base64.
+func ParseDataKey(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if key, err := hex.DecodeString(s); err == nil && len(key) == DataKeySize {
+		return key, nil
+	}
+	if key, err := base64.StdEncoding.DecodeString(s); err == nil && len(key) == DataKeySize {
+		return key, nil
+	}
+	return nil, fmt.Errorf("data key must be %d bytes written as hex or base64", DataKeySize)
+}
+
+// GenerateDataKey returns a new random data key, written as hex.
+func GenerateDataKey() (string, error) {
+	key := make([]byte, DataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(key), nil
+}
+
+// Sealed reports whether data was written by a Cipher.
+func Sealed(data []byte) bool {
+	return bytes.HasPrefix(data, sealedMagic)
+}
+
+// seal encrypts the contents of a file.
+func (c *Cipher) seal(plaintext []byte) ([]byte, error) {
+	if c == nil {
+		return plaintext, nil
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append([]byte{}, sealedMagic...)
+	out = append(out, nonce...)
+	return c.aead.Seal(out, nonce, plaintext, sealedMagic), nil
+}
+
+// open decrypts the contents of a file written by seal, and returns
+// plaintext files as they are.
+func (c *Cipher) open(data []byte) ([]byte, error) {
+	if !Sealed(data) {
+		return data, nil
+	}
+	if c == nil {
+		return nil, ErrNoDataKey
+	}
+	data = data[len(sealedMagic):]
+	if len(data) < c.aead.NonceSize() {
+		return nil, fmt.Errorf("encrypted file is truncated")
+	}
+	nonce, ciphertext := data[:c.aead.NonceSize()], data[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, sealedMagic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt file, is the data key right? %w", err)
+	}
+	return plaintext, nil
+}
+
+// sealLine encrypts one line of a JSON-lines file, which stays a line of
+// its own so the file can still be appended to.
+func (c *Cipher) sealLine(line []byte) ([]byte, error) {
+	if c == nil {
+		return line, nil
+	}
+	sealed, err := c.seal(line)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(base64.StdEncoding.EncodeToString(sealed)), nil
+}
+
+// openLine decrypts a line written by sealLine, and returns plaintext JSON
+// lines as they are.
+func (c *Cipher) openLine(line []byte) ([]byte, error) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 || line[0] == '{' {
+		return line, nil
+	}
+	sealed, err := base64.StdEncoding.DecodeString(string(line))
+	if err != nil || !Sealed(sealed) {
+		return nil, fmt.Errorf("line is neither JSON nor encrypted")
+	}
+	return c.open(sealed)
+}
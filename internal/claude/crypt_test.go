@@ -0,0 +1,206 @@
+package claude
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testCipher(t *testing.T) *Cipher {
+	t.Helper()
+	hexKey, err := GenerateDataKey()
+	if err != nil {
+		t.Fatalf("GenerateDataKey failed: %v", err)
+	}
+	key, err := ParseDataKey(hexKey)
+	if err != nil {
+		t.Fatalf("ParseDataKey failed: %v", err)
+	}
+	c, err := NewCipher(key)
+	if err != nil {
+		t.Fatalf("NewCipher failed: %v", err)
+	}
+	return c
+}
+
+func TestParseDataKey(t *testing.T) {
+	raw := bytes.Repeat([]byte{7}, DataKeySize)
+
+	for _, s := range []string{
+		strings.Repeat("07", DataKeySize),
+		base64.StdEncoding.EncodeToString(raw),
+		" " + strings.Repeat("07", DataKeySize) + "\n",
+	} {
+		key, err := ParseDataKey(s)
+		if err != nil {
+			t.Errorf("ParseDataKey(%q) failed: %v", s, err)
+			continue
+		}
+		if !bytes.Equal(key, raw) {
+			t.Errorf("ParseDataKey(%q) = %x", s, key)
+		}
+	}
+
+	for _, s := range []string{"", "secret", strings.Repeat("07", 16)} {
+		if _, err := ParseDataKey(s); err == nil {
+			t.Errorf("expected an error for %q", s)
+		}
+	}
+}
+
+func TestCipher_SealAndOpen(t *testing.T) {
+	c := testCipher(t)
+	plaintext := []byte(`{"session_id":"sess-1"}`)
+
+	sealed, err := c.seal(plaintext)
+	if err != nil {
+		t.Fatalf("seal failed: %v", err)
+	}
+	if !Sealed(sealed) || bytes.Contains(sealed, []byte("sess-1")) {
+		t.Fatalf("expected sealed data without the plaintext, got %q", sealed)
+	}
+
+	opened, err := c.open(sealed)
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	if !bytes.Equal(opened, plaintext) {
+		t.Errorf("expected %q, got %q", plaintext, opened)
+	}
+
+	// Plaintext passes through, with or without a cipher
+	if got, err := c.open(plaintext); err != nil || !bytes.Equal(got, plaintext) {
+		t.Errorf("expected plaintext to pass through, got %q, %v", got, err)
+	}
+	var none *Cipher
+	if got, _ := none.seal(plaintext); !bytes.Equal(got, plaintext) {
+		t.Errorf("expected a nil cipher to write plaintext, got %q", got)
+	}
+
+	if _, err := none.open(sealed); !errors.Is(err, ErrNoDataKey) {
+		t.Errorf("expected ErrNoDataKey without a cipher, got %v", err)
+	}
+	if _, err := testCipher(t).open(sealed); err == nil {
+		t.Error("expected an error opening with another key")
+	}
+}
+
+func TestSessionStore_Encrypted(t *testing.T) {
+	dir := t.TempDir()
+
+	// A session written before encryption was turned on
+	plain, _ := NewSessionStore(dir)
+	plain.Save(&SessionInfo{SessionID: "sess-old", WorkerName: "vito"})
+
+	c := testCipher(t)
+	store, err := NewEncryptedSessionStore(dir, c)
+	if err != nil {
+		t.Fatalf("failed to create session store: %v", err)
+	}
+	if _, err := store.Load("sess-old"); err != nil {
+		t.Errorf("expected the plaintext session to load: %v", err)
+	}
+	store.Save(&SessionInfo{SessionID: "sess-new", WorkerName: "sal"})
+
+	for _, id := range []string{"sess-old", "sess-new"} {
+		data, _ := os.ReadFile(filepath.Join(dir, id+".json"))
+		if !Sealed(data) {
+			t.Errorf("expected %s to be encrypted on disk, got %q", id, data)
+		}
+	}
+
+	reopened, _ := NewEncryptedSessionStore(dir, c)
+	if reopened.Count() != 2 {
+		t.Errorf("expected 2 sessions after reopening, got %d", reopened.Count())
+	}
+	if withoutKey, _ := NewSessionStore(dir); withoutKey.Count() != 0 {
+		t.Errorf("expected no sessions without the key, got %d", withoutKey.Count())
+	}
+}
+
+func TestChatStore_Encrypted(t *testing.T) {
+	dir := t.TempDir()
+	c := testCipher(t)
+
+	store, _ := NewEncryptedChatStore(dir, c)
+	store.Save(&ChatTranscript{ID: "chat-1", Messages: []ChatMessage{{Role: "user", Content: "the API key is hunter2"}}})
+
+	data, _ := os.ReadFile(filepath.Join(dir, "chat-1.json"))
+	if bytes.Contains(data, []byte("hunter2")) {
+		t.Errorf("expected the chat to be encrypted on disk, got %q", data)
+	}
+
+	reopened, _ := NewEncryptedChatStore(dir, c)
+	got, err := reopened.Load("chat-1")
+	if err != nil {
+		t.Fatalf("failed to load chat: %v", err)
+	}
+	if got.Messages[0].Content != "the API key is hunter2" {
+		t.Errorf("unexpected message: %q", got.Messages[0].Content)
+	}
+}
+
+func TestTranscriptStore_Encrypted(t *testing.T) {
+	dir := t.TempDir()
+
+	// Lines written before encryption was turned on are sealed on open
+	plain, _ := NewTranscriptStore(dir)
+	plain.Append("job-1", "vito", Event{Type: EventAssistantText, Message: "first"})
+
+	c := testCipher(t)
+	store, err := NewEncryptedTranscriptStore(dir, c)
+	if err != nil {
+		t.Fatalf("failed to create transcript store: %v", err)
+	}
+	store.Append("job-1", "vito", Event{Type: EventAssistantText, Message: "second"})
+
+	data, _ := os.ReadFile(store.Path("job-1"))
+	if bytes.Contains(data, []byte("first")) || bytes.Contains(data, []byte("second")) {
+		t.Errorf("expected the transcript to be encrypted on disk, got %q", data)
+	}
+
+	entries, next, err := store.Read("job-1", 0)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(entries) != 2 || next != 2 {
+		t.Fatalf("expected 2 entries, got %d (next %d)", len(entries), next)
+	}
+	if entries[0].Event.Message != "first" || entries[1].Event.Message != "second" {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+
+	if entries, _, _ := plain.Read("job-1", 0); len(entries) != 0 {
+		t.Errorf("expected no entries without the key, got %d", len(entries))
+	}
+}
+
+func TestReadSessionStore_LeavesPlaintext(t *testing.T) {
+	dir := t.TempDir()
+	plain, _ := NewSessionStore(dir)
+	plain.Save(&SessionInfo{SessionID: "sess-old", WorkerName: "vito"})
+	before, _ := os.ReadFile(filepath.Join(dir, "sess-old.json"))
+
+	store, err := ReadSessionStore(dir, testCipher(t))
+	if err != nil {
+		t.Fatalf("failed to read session store: %v", err)
+	}
+	if store.Count() != 1 {
+		t.Errorf("expected 1 session, got %d", store.Count())
+	}
+	if after, _ := os.ReadFile(filepath.Join(dir, "sess-old.json")); !bytes.Equal(after, before) {
+		t.Errorf("expected the plaintext session left as it was, got %q", after)
+	}
+
+	missing := filepath.Join(dir, "missing")
+	if _, err := ReadChatStore(missing, nil); err != nil {
+		t.Errorf("expected a missing directory to load no chats, got %v", err)
+	}
+	if _, err := os.Stat(missing); !os.IsNotExist(err) {
+		t.Error("expected the missing directory not to be created")
+	}
+}
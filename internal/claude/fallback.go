@@ -0,0 +1,28 @@
+package claude
+
+import "strings"
+
+// capacityErrors are fragments of the messages Claude gives when a model is
+// overloaded or the account has hit a rate or usage limit.
+var capacityErrors = []string{
+	"rate limit",
+	"rate_limit",
+	"overloaded",
+	"usage limit",
+	"capacity",
+	"429",
+	"529",
+}
+
+// IsCapacityError reports whether a failure message from Claude means the
+// model is out of capacity or rate limited, so that another model may
+// succeed where it failed.
+func IsCapacityError(message string) bool {
+	message = strings.ToLower(message)
+	for _, fragment := range capacityErrors {
+		if strings.Contains(message, fragment) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+package claude
+
+import "testing"
+
+func TestIsCapacityError(t *testing.T) {
+	tests := []struct {
+		message string
+		want    bool
+	}{
+		{"API Error: 529 {\"type\":\"overloaded_error\"}", true},
+		{"API Error: Rate limit reached for requests", true},
+		{"rate_limit_error: too many requests", true},
+		{"Claude usage limit reached. Your limit will reset at 5pm", true},
+		{"Error: 429 Too Many Requests", true},
+		{"claude reported failure", false},
+		{"permission denied: go.mod", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsCapacityError(tt.message); got != tt.want {
+			t.Errorf("IsCapacityError(%q) = %v, want %v", tt.message, got, tt.want)
+		}
+	}
+}
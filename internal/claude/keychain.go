@@ -0,0 +1,91 @@
+package claude
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// DefaultKeychainService names the OS keychain item holding the data key.
+const DefaultKeychainService = "cosa"
+
+// keychainAccount is the account of the keychain item holding the data
+// key, within its service.
+const keychainAccount = "data-key"
+
+// LoadDataKey returns the data key from COSA_DATA_KEY, or else from the OS
+// keychain item of service: the login keychain on macOS, the Secret Service
+// (through secret-tool) on Linux.
+func LoadDataKey(service string) ([]byte, error) {
+	if value := os.Getenv(DataKeyEnv); value != "" {
+		key, err := ParseDataKey(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", DataKeyEnv, err)
+		}
+		return key, nil
+	}
+
+	if service == "" {
+		service = DefaultKeychainService
+	}
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", service, "-a", keychainAccount, "-w")
+	case "linux", "freebsd", "openbsd", "netbsd":
+		cmd = exec.Command("secret-tool", "lookup", "service", service, "account", keychainAccount)
+	default:
+		return nil, fmt.Errorf("no data key: set %s (the OS keychain is not supported on %s)", DataKeyEnv, runtime.GOOS)
+	}
+
+	out, err := cmd.Output()
+	if err != nil || strings.TrimSpace(string(out)) == "" {
+		return nil, fmt.Errorf("no data key: set %s, or store one in the keychain with 'cosa data-key init'", DataKeyEnv)
+	}
+	key, err := ParseDataKey(string(out))
+	if err != nil {
+		return nil, fmt.Errorf("keychain item %s: %w", service, err)
+	}
+	return key, nil
+}
+
+// OpenCipher returns a cipher for the data key LoadDataKey finds.
+func OpenCipher(service string) (*Cipher, error) {
+	key, err := LoadDataKey(service)
+	if err != nil {
+		return nil, err
+	}
+	return NewCipher(key)
+}
+
+// StoreDataKey saves key, written as hex or base64, as the OS keychain item
+// of service, replacing any key already there.
+func StoreDataKey(service, key string) error {
+	if _, err := ParseDataKey(key); err != nil {
+		return err
+	}
+	if service == "" {
+		service = DefaultKeychainService
+	}
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		// -w given last with no value prompts for the key, twice, so it
+		// never shows up in the process list
+		cmd = exec.Command("security", "add-generic-password", "-U", "-s", service, "-a", keychainAccount, "-w")
+		cmd.Stdin = strings.NewReader(key + "\n" + key + "\n")
+	case "linux", "freebsd", "openbsd", "netbsd":
+		cmd = exec.Command("secret-tool", "store", "--label=Cosa data key", "service", service, "account", keychainAccount)
+		cmd.Stdin = strings.NewReader(key)
+	default:
+		return fmt.Errorf("the OS keychain is not supported on %s; set %s instead", runtime.GOOS, DataKeyEnv)
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to store the data key: %s: %w", strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
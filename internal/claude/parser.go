@@ -2,6 +2,9 @@ package claude
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
 )
 
 // EventType identifies the type of Claude event.
@@ -47,19 +50,101 @@ type Result struct {
 	Duration    string `json:"duration,omitempty"`
 }
 
-// Parser parses Claude's stream-json output.
+// Parser parses Claude's stream-json output in any known schema, which it
+// detects from each line. A lenient parser, the default, reports drift
+// from the schema to OnDrift and parses what it can; a strict one returns
+// a *SchemaError instead.
 type Parser struct {
-	// Track current state for multi-line parsing if needed
+	// Strict fails on output that drifted from its schema.
+	Strict bool
+
+	// OnDrift, if set, is called the first time the parser meets each
+	// kind of drift.
+	OnDrift func(SchemaDrift)
+
+	schema   Schema
+	version  string
+	reported map[SchemaDrift]bool
+
+	// Tools awaiting their results, by ID. The legacy schema gives no ID
+	// with a result, so currentTool is the last one started.
+	tools       map[string]*ToolCall
 	currentTool *ToolCall
 }
 
-// NewParser creates a new stream-json parser.
+// NewParser creates a new lenient stream-json parser.
 func NewParser() *Parser {
-	return &Parser{}
+	return &Parser{
+		reported: make(map[SchemaDrift]bool),
+		tools:    make(map[string]*ToolCall),
+	}
+}
+
+// Schema returns the schema of the last line parsed, or "" before any.
+func (p *Parser) Schema() Schema {
+	return p.schema
+}
+
+// Version returns the CLI version the output reported, or "" if it did
+// not report one.
+func (p *Parser) Version() string {
+	return p.version
+}
+
+// ParseLine parses a single line of stream-json output, returning its
+// first event. Lines with more than one, as a message of several content
+// blocks may be, need Parse.
+func (p *Parser) ParseLine(line string) (*Event, error) {
+	events, err := p.Parse(line)
+	if err != nil || len(events) == 0 {
+		return nil, err
+	}
+	return &events[0], nil
+}
+
+// Parse parses a single line of stream-json output into the events it
+// holds, which may be none.
+func (p *Parser) Parse(line string) ([]Event, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		return nil, err
+	}
+	var msgType string
+	json.Unmarshal(fields["type"], &msgType)
+
+	p.schema = detectSchema(msgType, fields)
+	if _, ok := knownFields[p.schema][msgType]; !ok && p.schema == SchemaMessages {
+		return nil, p.drift(SchemaDrift{Schema: p.schema, Type: msgType})
+	}
+	for _, f := range unknownFields(p.schema, msgType, fields) {
+		if err := p.drift(SchemaDrift{Schema: p.schema, Type: msgType, Field: f}); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.schema == SchemaMessages {
+		return p.parseMessage(msgType, line, fields)
+	}
+	return p.parseLegacy(msgType, line)
 }
 
-// streamMessage is the raw JSON structure from Claude's stream output.
-type streamMessage struct {
+// drift reports d, returning a *SchemaError for it if the parser is
+// strict.
+func (p *Parser) drift(d SchemaDrift) error {
+	if !p.reported[d] {
+		p.reported[d] = true
+		if p.OnDrift != nil {
+			p.OnDrift(d)
+		}
+	}
+	if p.Strict {
+		return &SchemaError{Drift: d}
+	}
+	return nil
+}
+
+// legacyMessage is a message in the legacy schema.
+type legacyMessage struct {
 	Type       string          `json:"type"`
 	SessionID  string          `json:"session_id,omitempty"`
 	Message    json.RawMessage `json:"message,omitempty"`
@@ -72,19 +157,15 @@ type streamMessage struct {
 	Error      string          `json:"error,omitempty"`
 }
 
-// ParseLine parses a single line of stream-json output.
-func (p *Parser) ParseLine(line string) (*Event, error) {
-	var msg streamMessage
+func (p *Parser) parseLegacy(msgType, line string) ([]Event, error) {
+	var msg legacyMessage
 	if err := json.Unmarshal([]byte(line), &msg); err != nil {
 		return nil, err
 	}
 
-	switch msg.Type {
+	switch msgType {
 	case "init", "system":
-		return &Event{
-			Type:      EventInit,
-			SessionID: msg.SessionID,
-		}, nil
+		return []Event{{Type: EventInit, SessionID: msg.SessionID}}, nil
 
 	case "user", "human":
 		var text string
@@ -92,10 +173,7 @@ func (p *Parser) ParseLine(line string) (*Event, error) {
 		if text == "" {
 			json.Unmarshal(msg.Message, &text)
 		}
-		return &Event{
-			Type:    EventUserMessage,
-			Message: text,
-		}, nil
+		return []Event{{Type: EventUserMessage, Message: text}}, nil
 
 	case "assistant", "text":
 		var text string
@@ -103,10 +181,7 @@ func (p *Parser) ParseLine(line string) (*Event, error) {
 		if text == "" {
 			json.Unmarshal(msg.Message, &text)
 		}
-		return &Event{
-			Type:    EventAssistantText,
-			Message: text,
-		}, nil
+		return []Event{{Type: EventAssistantText, Message: text}}, nil
 
 	case "tool_use", "tool_use_begin":
 		tool := &ToolCall{
@@ -116,17 +191,12 @@ func (p *Parser) ParseLine(line string) (*Event, error) {
 			Status: "running",
 		}
 		p.currentTool = tool
-		return &Event{
-			Type: EventToolUse,
-			Tool: tool,
-		}, nil
+		return []Event{{Type: EventToolUse, Tool: tool}}, nil
 
 	case "tool_result", "tool_use_end":
 		tool := p.currentTool
 		if tool == nil {
-			tool = &ToolCall{
-				ID: msg.ToolUseID,
-			}
+			tool = &ToolCall{ID: msg.ToolUseID}
 		}
 		tool.Status = "completed"
 
@@ -138,28 +208,203 @@ func (p *Parser) ParseLine(line string) (*Event, error) {
 		tool.Output = output
 
 		p.currentTool = nil
-		return &Event{
-			Type: EventToolResult,
-			Tool: tool,
-		}, nil
+		return []Event{{Type: EventToolResult, Tool: tool}}, nil
 
 	case "result", "end":
 		result := &Result{Success: true}
 		if msg.Result != nil {
 			json.Unmarshal(msg.Result, result)
 		}
-		return &Event{
-			Type:   EventResult,
-			Result: result,
-		}, nil
+		if result.TotalCost == "" && result.TotalTokens == 0 {
+			if err := p.drift(SchemaDrift{Schema: SchemaLegacy, Type: msgType, Field: "total_cost", Missing: true}); err != nil {
+				return nil, err
+			}
+		}
+		return []Event{{Type: EventResult, Result: result}}, nil
 
 	case "error":
-		return &Event{
-			Type:  EventError,
-			Error: msg.Error,
-		}, nil
+		return []Event{{Type: EventError, Error: msg.Error}}, nil
 	}
 
-	// Unknown message type, return nil to skip
+	// Unknown message type, skipped
+	return nil, p.drift(SchemaDrift{Schema: SchemaLegacy, Type: msgType})
+}
+
+// contentBlock is an API content block in a message of the messages
+// schema.
+type contentBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Content   json.RawMessage `json:"content,omitempty"` // Tool result: a string or text blocks
+	IsError   bool            `json:"is_error,omitempty"`
+}
+
+// knownBlocks are the content block types the parser knows, by message
+// type. Blocks it has no use for, such as thinking, are skipped.
+var knownBlocks = map[string]map[string]bool{
+	"assistant": {"text": true, "tool_use": true, "thinking": true, "redacted_thinking": true, "server_tool_use": true, "web_search_tool_result": true},
+	"user":      {"text": true, "tool_result": true, "image": true, "document": true},
+}
+
+// usage is the token usage reported with a result.
+type usage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+}
+
+// resultMessage is the last message of a session in the messages schema.
+type resultMessage struct {
+	Subtype      string   `json:"subtype"`
+	IsError      bool     `json:"is_error"`
+	Result       string   `json:"result"`
+	Errors       []string `json:"errors"`
+	DurationMS   int64    `json:"duration_ms"`
+	TotalCostUSD *float64 `json:"total_cost_usd"`
+	CostUSD      *float64 `json:"cost_usd"` // Before total_cost_usd
+	Usage        *usage   `json:"usage"`
+}
+
+func (p *Parser) parseMessage(msgType, line string, fields map[string]json.RawMessage) ([]Event, error) {
+	var sessionID string
+	json.Unmarshal(fields["session_id"], &sessionID)
+
+	switch msgType {
+	case "system":
+		var subtype string
+		json.Unmarshal(fields["subtype"], &subtype)
+		if subtype != "init" {
+			return nil, nil // Such as compact_boundary
+		}
+		json.Unmarshal(fields["claude_code_version"], &p.version)
+		return []Event{{Type: EventInit, SessionID: sessionID}}, nil
+
+	case "assistant", "user":
+		var msg struct {
+			Content json.RawMessage `json:"content"`
+		}
+		json.Unmarshal(fields["message"], &msg)
+
+		// A message may be plain text, such as the prompt
+		var text string
+		if json.Unmarshal(msg.Content, &text) == nil {
+			if msgType == "assistant" {
+				return []Event{{Type: EventAssistantText, Message: text}}, nil
+			}
+			return []Event{{Type: EventUserMessage, Message: text}}, nil
+		}
+		var blocks []contentBlock
+		if err := json.Unmarshal(msg.Content, &blocks); err != nil {
+			return nil, p.drift(SchemaDrift{Schema: SchemaMessages, Type: msgType, Field: "message.content", Missing: true})
+		}
+		return p.parseBlocks(msgType, blocks)
+
+	case "result":
+		var msg resultMessage
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			return nil, fmt.Errorf("invalid result: %w", err)
+		}
+
+		result := &Result{
+			Success: !msg.IsError && msg.Subtype == "success",
+			Message: msg.Result,
+		}
+		if result.Message == "" && !result.Success {
+			result.Message = strings.Join(append([]string{msg.Subtype}, msg.Errors...), ": ")
+		}
+		if msg.DurationMS > 0 {
+			result.Duration = (time.Duration(msg.DurationMS) * time.Millisecond).String()
+		}
+
+		cost := msg.TotalCostUSD
+		if cost == nil {
+			cost = msg.CostUSD
+		}
+		if cost == nil {
+			if err := p.drift(SchemaDrift{Schema: SchemaMessages, Type: msgType, Field: "total_cost_usd", Missing: true}); err != nil {
+				return nil, err
+			}
+		} else {
+			result.TotalCost = fmt.Sprintf("$%.4f", *cost)
+		}
+		if msg.Usage == nil {
+			if err := p.drift(SchemaDrift{Schema: SchemaMessages, Type: msgType, Field: "usage", Missing: true}); err != nil {
+				return nil, err
+			}
+		} else {
+			// Cache reads re-count the context each turn, so they are left
+			// out of the session's size
+			result.TotalTokens = msg.Usage.InputTokens + msg.Usage.OutputTokens + msg.Usage.CacheCreationInputTokens
+		}
+		return []Event{{Type: EventResult, SessionID: sessionID, Result: result}}, nil
+	}
+
+	// stream_event: partial messages, which arrive whole later
 	return nil, nil
 }
+
+// parseBlocks turns the content blocks of an assistant or user message
+// into events.
+func (p *Parser) parseBlocks(msgType string, blocks []contentBlock) ([]Event, error) {
+	var events []Event
+	for _, b := range blocks {
+		if !knownBlocks[msgType][b.Type] {
+			if err := p.drift(SchemaDrift{Schema: SchemaMessages, Type: msgType + "." + b.Type}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		switch {
+		case b.Type == "text" && msgType == "assistant":
+			events = append(events, Event{Type: EventAssistantText, Message: b.Text})
+
+		case b.Type == "text":
+			events = append(events, Event{Type: EventUserMessage, Message: b.Text})
+
+		case b.Type == "tool_use":
+			tool := &ToolCall{ID: b.ID, Name: b.Name, Input: b.Input, Status: "running"}
+			p.tools[b.ID] = tool
+			events = append(events, Event{Type: EventToolUse, Tool: tool})
+
+		case b.Type == "tool_result":
+			tool, ok := p.tools[b.ToolUseID]
+			if !ok {
+				tool = &ToolCall{ID: b.ToolUseID}
+			}
+			delete(p.tools, b.ToolUseID)
+
+			tool.Status = "completed"
+			tool.Output = blockText(b.Content)
+			if b.IsError {
+				tool.Status = "error"
+				tool.Error = tool.Output
+			}
+			events = append(events, Event{Type: EventToolResult, Tool: tool})
+		}
+	}
+	return events, nil
+}
+
+// blockText returns the text of a tool result's content, which is either
+// a string or a list of text blocks.
+func blockText(raw json.RawMessage) string {
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		return text
+	}
+	var blocks []contentBlock
+	json.Unmarshal(raw, &blocks)
+	var parts []string
+	for _, b := range blocks {
+		if b.Type == "text" {
+			parts = append(parts, b.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,169 @@
+package claude
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// parseFixture parses a recorded stream from testdata/stream with p,
+// returning its events and the first error.
+func parseFixture(t *testing.T, p *Parser, name string) ([]Event, error) {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", "stream", name))
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		parsed, err := p.Parse(scanner.Text())
+		if err != nil {
+			return events, err
+		}
+		events = append(events, parsed...)
+	}
+	return events, scanner.Err()
+}
+
+// Each fixture is the output of one CLI version for the same session: the
+// worker reads a test, runs it and reports back.
+func TestParser_Fixtures(t *testing.T) {
+	tests := []struct {
+		fixture   string
+		schema    Schema
+		version   string
+		sessionID string
+		cost      string
+		tokens    int
+		toolError bool
+	}{
+		{fixture: "legacy.jsonl", schema: SchemaLegacy, sessionID: "sess-legacy", cost: "$0.0421", tokens: 5120},
+		{fixture: "1.0.0.jsonl", schema: SchemaMessages, sessionID: "sess-100", cost: "$0.0421", tokens: 5120, toolError: true},
+		{fixture: "2.0.x.jsonl", schema: SchemaMessages, version: "2.0.14", sessionID: "sess-200", cost: "$0.3187", tokens: 10568},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			p := NewParser()
+			p.Strict = true
+			events, err := parseFixture(t, p, tt.fixture)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if p.Schema() != tt.schema {
+				t.Errorf("expected schema %s, got %s", tt.schema, p.Schema())
+			}
+			if p.Version() != tt.version {
+				t.Errorf("expected version %q, got %q", tt.version, p.Version())
+			}
+
+			var (
+				sessionID string
+				texts     []string
+				started   []string
+				finished  []string
+				result    *Result
+			)
+			for _, e := range events {
+				switch e.Type {
+				case EventInit:
+					sessionID = e.SessionID
+				case EventAssistantText:
+					texts = append(texts, e.Message)
+				case EventToolUse:
+					started = append(started, e.Tool.Name)
+				case EventToolResult:
+					finished = append(finished, e.Tool.Name)
+					if e.Tool.Name == "Bash" && (e.Tool.Status == "error") != tt.toolError {
+						t.Errorf("unexpected status %q for the Bash result", e.Tool.Status)
+					}
+				case EventResult:
+					result = e.Result
+				}
+			}
+
+			if sessionID != tt.sessionID {
+				t.Errorf("expected session %q, got %q", tt.sessionID, sessionID)
+			}
+			if want := []string{"I'll look at the test first.", "The test passes now."}; !slices.Equal(texts, want) {
+				t.Errorf("expected text %q, got %q", want, texts)
+			}
+			if want := []string{"Read", "Bash"}; !slices.Equal(started, want) || !slices.Equal(finished, want) {
+				t.Errorf("expected tools %v to start and finish, got %v and %v", want, started, finished)
+			}
+			if result == nil {
+				t.Fatal("expected a result")
+			}
+			if !result.Success {
+				t.Errorf("expected success, got %+v", result)
+			}
+			if result.TotalCost != tt.cost {
+				t.Errorf("expected cost %s, got %s", tt.cost, result.TotalCost)
+			}
+			if result.TotalTokens != tt.tokens {
+				t.Errorf("expected %d tokens, got %d", tt.tokens, result.TotalTokens)
+			}
+		})
+	}
+}
+
+func TestParser_Drift(t *testing.T) {
+	var drift []string
+	p := NewParser()
+	p.OnDrift = func(d SchemaDrift) { drift = append(drift, d.String()) }
+
+	events, err := parseFixture(t, p, "drift.jsonl")
+	if err != nil {
+		t.Fatalf("expected a lenient parser to parse what it can, got %v", err)
+	}
+	want := []string{
+		`unknown field "sandbox" in messages system message`,
+		`unknown content block "citation" in messages assistant message`,
+		`unknown field "billing" in messages result message`,
+		"messages result message has no total_cost_usd",
+		"messages result message has no usage",
+	}
+	if !slices.Equal(drift, want) {
+		t.Errorf("expected drift:\n%q\ngot:\n%q", want, drift)
+	}
+
+	last := events[len(events)-1]
+	if last.Type != EventResult || !last.Result.Success || last.Result.Message != "Done." {
+		t.Errorf("expected a successful result, got %+v", last)
+	}
+	if p.Version() != "3.0.0" {
+		t.Errorf("expected version 3.0.0, got %q", p.Version())
+	}
+
+	// Drift is reported once however often it recurs
+	p.Parse(`{"type":"system","subtype":"init","session_id":"sess-drift","sandbox":{}}`)
+	if len(drift) != len(want) {
+		t.Errorf("expected repeated drift not to be reported again, got %q", drift[len(want):])
+	}
+}
+
+func TestParser_StrictDrift(t *testing.T) {
+	p := NewParser()
+	p.Strict = true
+
+	_, err := parseFixture(t, p, "drift.jsonl")
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("expected a schema error, got %v", err)
+	}
+	if schemaErr.Drift.Type != "system" || schemaErr.Drift.Field != "sandbox" {
+		t.Errorf("unexpected drift %+v", schemaErr.Drift)
+	}
+
+	_, err = p.Parse(`{"type":"result","subtype":"success","is_error":false,"result":"Done.","session_id":"s","usage":{}}`)
+	if !errors.As(err, &schemaErr) || !schemaErr.Drift.Missing {
+		t.Errorf("expected a result without its cost to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+//go:build !windows
+
+package claude
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+)
+
+// claudeCommand builds the command that runs Claude under a PTY.
+func claudeCommand(ctx context.Context, binary string, args []string) *exec.Cmd {
+	// Use 'script' to allocate a PTY for Claude
+	// This is needed because Node.js (Claude) buffers stdout when connected to a pipe
+	// but writes immediately when connected to a terminal/PTY
+	claudeCmd := binary
+	for _, arg := range args {
+		// Escape single quotes in arguments
+		escaped := strings.ReplaceAll(arg, "'", "'\"'\"'")
+		claudeCmd += " '" + escaped + "'"
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "linux" {
+		// util-linux script takes the command as an option, and -e passes
+		// on its exit status as BSD script does
+		cmd = exec.CommandContext(ctx, "script", "-q", "-e", "-c", claudeCmd, "/dev/null")
+	} else {
+		cmd = exec.CommandContext(ctx, "script", "-q", "/dev/null", "/bin/bash", "-c", claudeCmd)
+	}
+	// Run in its own process group so a kill takes script and bash down too
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	return cmd
+}
+
+// interruptClaude types ^C into the PTY. script runs claude in a session of
+// its own on the PTY, out of reach of a signal to script's process group, so
+// the terminal has to deliver the SIGINT to it.
+func interruptClaude(p *os.Process, stdin io.Writer) error {
+	if stdin == nil {
+		return fmt.Errorf("no terminal to interrupt")
+	}
+	_, err := stdin.Write([]byte{0x03})
+	return err
+}
+
+func killProcessGroup(p *os.Process) error {
+	return syscall.Kill(-p.Pid, syscall.SIGKILL)
+}
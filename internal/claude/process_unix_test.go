@@ -0,0 +1,63 @@
+//go:build !windows
+
+package claude
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClaude stands in for the claude binary: it reports a session, then
+// waits to be interrupted and records that it was.
+const fakeClaude = `#!/bin/sh
+trap 'echo interrupted > "$COSA_TEST_MARK"; exit 0' INT
+echo '{"type":"system","subtype":"init","session_id":"sess-1"}'
+while :; do sleep 0.1; done
+`
+
+func TestClient_InterruptReachesClaude(t *testing.T) {
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "claude")
+	if err := os.WriteFile(binary, []byte(fakeClaude), 0755); err != nil {
+		t.Fatal(err)
+	}
+	mark := filepath.Join(dir, "mark")
+
+	c := NewClient(ClientConfig{
+		Binary:  binary,
+		Workdir: dir,
+		Env:     []string{"COSA_TEST_MARK=" + mark},
+	})
+	if err := c.Start(context.Background(), "hello"); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer c.Stop()
+
+	// Wait until claude is up, with its trap in place
+	select {
+	case <-c.Events():
+	case <-time.After(5 * time.Second):
+		t.Fatal("claude did not start")
+	}
+
+	if err := c.Interrupt(); err != nil {
+		t.Fatalf("Interrupt() error = %v", err)
+	}
+	select {
+	case <-c.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("claude did not exit after Interrupt()")
+	}
+
+	data, err := os.ReadFile(mark)
+	if err != nil {
+		t.Fatalf("claude did not receive the interrupt: %v", err)
+	}
+	if strings.TrimSpace(string(data)) != "interrupted" {
+		t.Errorf("mark = %q, want %q", data, "interrupted")
+	}
+}
@@ -0,0 +1,32 @@
+//go:build windows
+
+package claude
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// claudeCommand builds the command that runs Claude. Windows has no
+// script(1), so Claude runs directly in a new process group that Interrupt
+// can signal.
+func claudeCommand(ctx context.Context, binary string, args []string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, binary, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: windows.CREATE_NEW_PROCESS_GROUP}
+	return cmd
+}
+
+func interruptClaude(p *os.Process, _ io.Writer) error {
+	return windows.GenerateConsoleCtrlEvent(windows.CTRL_BREAK_EVENT, uint32(p.Pid))
+}
+
+func killProcessGroup(p *os.Process) error {
+	// taskkill /T takes the child processes claude started down with it
+	return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(p.Pid)).Run()
+}
@@ -0,0 +1,122 @@
+package claude
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Schema is a version of the CLI's stream-json output format.
+type Schema string
+
+const (
+	// SchemaLegacy is the flat format of early CLI versions: text in
+	// "content", tools in "tool_name" and "tool_input", and the result as
+	// an object.
+	SchemaLegacy Schema = "legacy"
+
+	// SchemaMessages is the format of Claude Code 1.0 and later: assistant
+	// and user messages wrap API content blocks, and the result carries
+	// its cost and usage at the top level.
+	SchemaMessages Schema = "messages"
+)
+
+// knownFields are the top-level fields of each message type, by schema.
+// Fields outside them are reported as drift.
+var knownFields = map[Schema]map[string][]string{
+	SchemaLegacy: {
+		"*": {"type", "session_id", "message", "content", "tool_use_id", "tool_name", "tool_input", "tool_result", "result", "error"},
+	},
+	SchemaMessages: {
+		"system": {"type", "subtype", "session_id", "uuid", "cwd", "tools", "mcp_servers", "model", "permissionMode",
+			"slash_commands", "apiKeySource", "output_style", "agents", "skills", "plugins", "claude_code_version"},
+		"assistant": {"type", "message", "parent_tool_use_id", "session_id", "uuid"},
+		"user":      {"type", "message", "parent_tool_use_id", "session_id", "uuid", "tool_use_result"},
+		"result": {"type", "subtype", "is_error", "duration_ms", "duration_api_ms", "num_turns", "result", "session_id",
+			"total_cost_usd", "cost_usd", "usage", "modelUsage", "permission_denials", "uuid", "errors"},
+		"stream_event": {"type", "event", "session_id", "parent_tool_use_id", "uuid"},
+	},
+}
+
+// legacyTypes are the message types only the legacy schema has.
+var legacyTypes = map[string]bool{
+	"init": true, "human": true, "text": true, "tool_use": true, "tool_use_begin": true,
+	"tool_result": true, "tool_use_end": true, "end": true, "error": true,
+}
+
+// SchemaDrift is a difference between a line of output and the schema it
+// was parsed with: a message type or field the parser does not know, or
+// a field it needs that is missing.
+type SchemaDrift struct {
+	Schema  Schema `json:"schema"`
+	Type    string `json:"type"`            // Message type, or "message.block" for a content block
+	Field   string `json:"field,omitempty"` // Empty for an unknown message type
+	Missing bool   `json:"missing,omitempty"`
+}
+
+func (d SchemaDrift) String() string {
+	msgType, block, isBlock := strings.Cut(d.Type, ".")
+	switch {
+	case d.Field == "" && isBlock:
+		return fmt.Sprintf("unknown content block %q in %s %s message", block, d.Schema, msgType)
+	case d.Field == "":
+		return fmt.Sprintf("unknown %s message type %q", d.Schema, d.Type)
+	case d.Missing:
+		return fmt.Sprintf("%s %s message has no %s", d.Schema, d.Type, d.Field)
+	default:
+		return fmt.Sprintf("unknown field %q in %s %s message", d.Field, d.Schema, d.Type)
+	}
+}
+
+// SchemaError is returned by a strict parser for output that drifted
+// from its schema.
+type SchemaError struct {
+	Drift SchemaDrift
+}
+
+func (e *SchemaError) Error() string {
+	return "claude output schema changed: " + e.Drift.String()
+}
+
+// detectSchema returns the schema a message of the given type and fields
+// is in. The two schemas share some type names, so the shape decides.
+func detectSchema(msgType string, fields map[string]json.RawMessage) Schema {
+	if legacyTypes[msgType] {
+		return SchemaLegacy
+	}
+	if raw, ok := fields["message"]; ok && len(raw) > 0 && raw[0] == '{' {
+		return SchemaMessages
+	}
+	for _, f := range []string{"subtype", "uuid", "is_error", "total_cost_usd", "cost_usd", "event"} {
+		if _, ok := fields[f]; ok {
+			return SchemaMessages
+		}
+	}
+	if raw, ok := fields["result"]; ok && len(raw) > 0 && raw[0] == '"' {
+		return SchemaMessages
+	}
+	return SchemaLegacy
+}
+
+// unknownFields returns the fields of a message not known for its type in
+// schema, sorted.
+func unknownFields(schema Schema, msgType string, fields map[string]json.RawMessage) []string {
+	known, ok := knownFields[schema][msgType]
+	if !ok {
+		known = knownFields[schema]["*"]
+	}
+	set := make(map[string]bool, len(known))
+	for _, f := range known {
+		set[f] = true
+	}
+
+	var unknown []string
+	for f := range fields {
+		if !set[f] {
+			unknown = append(unknown, f)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
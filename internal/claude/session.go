@@ -16,17 +16,31 @@ type SessionInfo struct {
 	WorkerName string    `json:"worker_name"`
 	CreatedAt  time.Time `json:"created_at"`
 	LastUsed   time.Time `json:"last_used"`
+	Commit     string    `json:"commit,omitempty"` // Worktree HEAD when the session last ran
+	Tokens     int       `json:"tokens,omitempty"` // Tokens used across the session's runs
+
+	// PreviousSessionID is the session this one replaced when it was
+	// rolled over for growing too long
+	PreviousSessionID string `json:"previous_session_id,omitempty"`
 }
 
 // SessionStore manages session persistence.
 type SessionStore struct {
 	path     string                   // Directory for session files
 	sessions map[string]*SessionInfo  // Keyed by session ID
+	cipher   *Cipher                  // Encrypts session files; nil writes plaintext
 	mu       sync.RWMutex
 }
 
 // NewSessionStore creates a new session store.
 func NewSessionStore(path string) (*SessionStore, error) {
+	return NewEncryptedSessionStore(path, nil)
+}
+
+// NewEncryptedSessionStore creates a session store whose files are
+// encrypted with c. Session files still in plaintext are encrypted as they
+// are loaded.
+func NewEncryptedSessionStore(path string, c *Cipher) (*SessionStore, error) {
 	// Ensure directory exists
 	if err := os.MkdirAll(path, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create sessions directory: %w", err)
@@ -35,16 +49,33 @@ func NewSessionStore(path string) (*SessionStore, error) {
 	store := &SessionStore{
 		path:     path,
 		sessions: make(map[string]*SessionInfo),
+		cipher:   c,
 	}
 
 	// Load existing sessions
-	if err := store.loadAll(); err != nil {
+	if err := store.loadAll(true); err != nil {
 		return nil, fmt.Errorf("failed to load sessions: %w", err)
 	}
 
 	return store, nil
 }
 
+// ReadSessionStore loads the session files in path, decrypting them with c,
+// without changing anything on disk: plaintext files are left in plaintext
+// and a missing directory is not created. It is for checking a data
+// directory; use NewEncryptedSessionStore to save sessions.
+func ReadSessionStore(path string, c *Cipher) (*SessionStore, error) {
+	store := &SessionStore{
+		path:     path,
+		sessions: make(map[string]*SessionInfo),
+		cipher:   c,
+	}
+	if err := store.loadAll(false); err != nil {
+		return nil, fmt.Errorf("failed to load sessions: %w", err)
+	}
+	return store, nil
+}
+
 // Save persists a session to disk.
 func (s *SessionStore) Save(info *SessionInfo) error {
 	s.mu.Lock()
@@ -59,6 +90,9 @@ func (s *SessionStore) Save(info *SessionInfo) error {
 	if err != nil {
 		return fmt.Errorf("failed to marshal session: %w", err)
 	}
+	if data, err = s.cipher.seal(data); err != nil {
+		return fmt.Errorf("failed to encrypt session: %w", err)
+	}
 
 	if err := os.WriteFile(filePath, data, 0600); err != nil {
 		return fmt.Errorf("failed to write session file: %w", err)
@@ -200,7 +234,9 @@ func (s *SessionStore) sessionFilePath(sessionID string) string {
 	return filepath.Join(s.path, safeName+".json")
 }
 
-func (s *SessionStore) loadAll() error {
+// loadAll loads every session file, encrypting those still in plaintext
+// if reseal is set.
+func (s *SessionStore) loadAll(reseal bool) error {
 	entries, err := os.ReadDir(s.path)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -219,13 +255,22 @@ func (s *SessionStore) loadAll() error {
 		if err != nil {
 			continue // Skip unreadable files
 		}
+		plaintext, err := s.cipher.open(data)
+		if err != nil {
+			continue // Skip files encrypted with another key, or none
+		}
 
 		var info SessionInfo
-		if err := json.Unmarshal(data, &info); err != nil {
+		if err := json.Unmarshal(plaintext, &info); err != nil {
 			continue // Skip unparseable files
 		}
 
 		s.sessions[info.SessionID] = &info
+		if reseal && s.cipher != nil && !Sealed(data) {
+			if sealed, err := s.cipher.seal(plaintext); err == nil {
+				os.WriteFile(filePath, sealed, 0600)
+			}
+		}
 	}
 
 	return nil
@@ -0,0 +1,182 @@
+package claude
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// TranscriptEntry is a single recorded event in a job transcript.
+type TranscriptEntry struct {
+	Time   time.Time `json:"time"`
+	JobID  string    `json:"job_id"`
+	Worker string    `json:"worker"`
+	Event  Event     `json:"event"`
+}
+
+// TranscriptStore persists the Claude event stream of each job as a
+// JSON-lines file, one file per job. With a cipher each line is encrypted
+// on its own, so transcripts can still be appended to.
+type TranscriptStore struct {
+	path   string  // Directory for transcript files
+	cipher *Cipher // Encrypts transcript lines; nil writes plaintext
+	mu     sync.Mutex
+}
+
+// NewTranscriptStore creates a new transcript store.
+func NewTranscriptStore(path string) (*TranscriptStore, error) {
+	return NewEncryptedTranscriptStore(path, nil)
+}
+
+// NewEncryptedTranscriptStore creates a transcript store whose lines are
+// encrypted with c. Transcripts with lines still in plaintext are rewritten
+// encrypted.
+func NewEncryptedTranscriptStore(path string, c *Cipher) (*TranscriptStore, error) {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create transcripts directory: %w", err)
+	}
+	s := &TranscriptStore{path: path, cipher: c}
+	if c != nil {
+		s.sealAll()
+	}
+	return s, nil
+}
+
+// sealAll rewrites the transcripts that have lines in plaintext with every
+// line encrypted. Transcripts it cannot read are left as they are.
+func (s *TranscriptStore) sealAll() {
+	entries, _ := os.ReadDir(s.path)
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".jsonl" {
+			continue
+		}
+		filePath := filepath.Join(s.path, entry.Name())
+		data, err := os.ReadFile(filePath)
+		if err != nil || !bytes.Contains(data, []byte("\n{")) && !bytes.HasPrefix(data, []byte("{")) {
+			continue
+		}
+
+		var out bytes.Buffer
+		ok := true
+		for _, line := range bytes.Split(data, []byte("\n")) {
+			if len(bytes.TrimSpace(line)) == 0 {
+				continue
+			}
+			plaintext, err := s.cipher.openLine(line)
+			if err == nil {
+				line, err = s.cipher.sealLine(plaintext)
+			}
+			if err != nil {
+				ok = false
+				break
+			}
+			out.Write(line)
+			out.WriteByte('\n')
+		}
+		if !ok {
+			continue
+		}
+
+		tmp := filePath + ".tmp"
+		if err := os.WriteFile(tmp, out.Bytes(), 0600); err != nil {
+			continue
+		}
+		if err := os.Rename(tmp, filePath); err != nil {
+			os.Remove(tmp)
+		}
+	}
+}
+
+// Append records an event in the transcript for jobID.
+func (s *TranscriptStore) Append(jobID, worker string, event Event) error {
+	entry := TranscriptEntry{
+		Time:   time.Now(),
+		JobID:  jobID,
+		Worker: worker,
+		Event:  event,
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal transcript entry: %w", err)
+	}
+	if data, err = s.cipher.sealLine(data); err != nil {
+		return fmt.Errorf("failed to encrypt transcript entry: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.Path(jobID), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open transcript: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write transcript: %w", err)
+	}
+	return nil
+}
+
+// Read returns the transcript entries for jobID, skipping the first offset
+// lines, along with the offset to pass to the next call to read only newer
+// entries. A job without a transcript returns no entries.
+func (s *TranscriptStore) Read(jobID string, offset int) ([]TranscriptEntry, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.Path(jobID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, offset, nil
+		}
+		return nil, offset, fmt.Errorf("failed to open transcript: %w", err)
+	}
+	defer f.Close()
+
+	var entries []TranscriptEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	index := 0
+	for scanner.Scan() {
+		if index < offset {
+			index++
+			continue
+		}
+		index++
+
+		line, err := s.cipher.openLine(scanner.Bytes())
+		if err != nil {
+			continue
+		}
+		var entry TranscriptEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return entries, index, fmt.Errorf("failed to read transcript: %w", err)
+	}
+
+	if index < offset {
+		index = offset
+	}
+	return entries, index, nil
+}
+
+// Exists reports whether a transcript has been recorded for jobID.
+func (s *TranscriptStore) Exists(jobID string) bool {
+	_, err := os.Stat(s.Path(jobID))
+	return err == nil
+}
+
+// Path returns the transcript file path for jobID.
+func (s *TranscriptStore) Path(jobID string) string {
+	return filepath.Join(s.path, jobID+".jsonl")
+}
@@ -0,0 +1,109 @@
+package claude
+
+import (
+	"os"
+	"testing"
+)
+
+func TestTranscriptStore_AppendAndRead(t *testing.T) {
+	store, err := NewTranscriptStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create transcript store: %v", err)
+	}
+
+	events := []Event{
+		{Type: EventInit, SessionID: "sess-1"},
+		{Type: EventAssistantText, Message: "Looking at the code"},
+		{Type: EventToolUse, Tool: &ToolCall{ID: "t1", Name: "Read"}},
+	}
+	for _, e := range events {
+		if err := store.Append("job-1", "vito", e); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+
+	entries, next, err := store.Read("job-1", 0)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	if next != 3 {
+		t.Errorf("expected next offset 3, got %d", next)
+	}
+	if entries[0].Worker != "vito" || entries[0].JobID != "job-1" {
+		t.Errorf("unexpected entry metadata: %+v", entries[0])
+	}
+	if entries[1].Event.Message != "Looking at the code" {
+		t.Errorf("unexpected message: %q", entries[1].Event.Message)
+	}
+	if entries[2].Event.Tool == nil || entries[2].Event.Tool.Name != "Read" {
+		t.Errorf("tool call not preserved: %+v", entries[2].Event)
+	}
+}
+
+func TestTranscriptStore_ReadOffset(t *testing.T) {
+	store, _ := NewTranscriptStore(t.TempDir())
+
+	store.Append("job-1", "vito", Event{Type: EventAssistantText, Message: "one"})
+	store.Append("job-1", "vito", Event{Type: EventAssistantText, Message: "two"})
+
+	_, next, _ := store.Read("job-1", 0)
+	store.Append("job-1", "vito", Event{Type: EventAssistantText, Message: "three"})
+
+	entries, next, err := store.Read("job-1", next)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Event.Message != "three" {
+		t.Fatalf("expected only the new entry, got %+v", entries)
+	}
+	if next != 3 {
+		t.Errorf("expected next offset 3, got %d", next)
+	}
+
+	entries, next, _ = store.Read("job-1", next)
+	if len(entries) != 0 || next != 3 {
+		t.Errorf("expected no new entries at offset 3, got %d (next %d)", len(entries), next)
+	}
+}
+
+func TestTranscriptStore_SkipsMalformedLines(t *testing.T) {
+	store, _ := NewTranscriptStore(t.TempDir())
+
+	store.Append("job-1", "vito", Event{Type: EventAssistantText, Message: "one"})
+	f, err := os.OpenFile(store.Path("job-1"), os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatalf("failed to open transcript: %v", err)
+	}
+	f.WriteString("not json\n")
+	f.Close()
+	store.Append("job-1", "vito", Event{Type: EventAssistantText, Message: "two"})
+
+	entries, next, err := store.Read("job-1", 0)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("expected 2 entries, got %d", len(entries))
+	}
+	if next != 3 {
+		t.Errorf("expected next offset to count malformed line, got %d", next)
+	}
+}
+
+func TestTranscriptStore_Missing(t *testing.T) {
+	store, _ := NewTranscriptStore(t.TempDir())
+
+	if store.Exists("nope") {
+		t.Error("expected no transcript")
+	}
+	entries, next, err := store.Read("nope", 0)
+	if err != nil {
+		t.Fatalf("Read of missing transcript should not fail: %v", err)
+	}
+	if len(entries) != 0 || next != 0 {
+		t.Errorf("expected empty result, got %d entries (next %d)", len(entries), next)
+	}
+}
@@ -2,8 +2,14 @@
 package config
 
 import (
+	"crypto/subtle"
+	"fmt"
 	"os"
+	"os/user"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -22,23 +28,263 @@ type Config struct {
 	// LogLevel controls logging verbosity (debug, info, warn, error).
 	LogLevel string `yaml:"log_level"`
 
+	// Identity names this client in per-user state kept by the daemon, such
+	// as job watch lists. Defaults to the OS user name.
+	Identity string `yaml:"identity"`
+
+	// Profile is the name of the profile this config was loaded from. It is
+	// set by LoadProfile and never written to the file.
+	Profile string `yaml:"-"`
+
+	// Daemon contains daemon lifecycle settings.
+	Daemon DaemonConfig `yaml:"daemon"`
+
+	// API contains settings for the REST API used to submit jobs from CI.
+	API APIConfig `yaml:"api"`
+
+	// Auth contains the tokens clients connect to the daemon with.
+	Auth AuthConfig `yaml:"auth"`
+
 	// Claude contains Claude Code CLI configuration.
 	Claude ClaudeConfig `yaml:"claude"`
 
 	// Workers contains worker defaults.
 	Workers WorkerConfig `yaml:"workers"`
 
+	// Lookout contains worker health monitor settings.
+	Lookout LookoutConfig `yaml:"lookout"`
+
 	// Git contains git-related configuration.
 	Git GitConfig `yaml:"git"`
 
+	// Review contains code review settings.
+	Review ReviewConfig `yaml:"review"`
+
 	// TUI contains TUI configuration.
 	TUI TUIConfig `yaml:"tui"`
 
 	// Notifications contains notification settings.
 	Notifications NotificationConfig `yaml:"notifications"`
 
+	// Budget contains spend limits enforced by the daemon.
+	Budget SpendLimits `yaml:"budget"`
+
+	// Admission contains the policy endpoint asked to accept each new job.
+	Admission AdmissionConfig `yaml:"admission"`
+
+	// Email contains the gateway that turns emails into draft jobs.
+	Email EmailConfig `yaml:"email"`
+
+	// Cleanup contains the policy for removing leftover worktrees,
+	// branches and sessions.
+	Cleanup CleanupConfig `yaml:"cleanup"`
+
+	// Index contains the repository index given to workers to orient
+	// themselves.
+	Index IndexConfig `yaml:"index"`
+
+	// Encryption contains the encryption at rest of Claude sessions and
+	// transcripts.
+	Encryption EncryptionConfig `yaml:"encryption"`
+
 	// Models contains per-role model configuration.
 	Models ModelConfig `yaml:"models"`
+
+	// Presets contains named bundles of job add options.
+	Presets map[string]JobPreset `yaml:"presets"`
+
+	// Filters contains named job list filters, used by cosa job list
+	// --filter and cycled through in the TUI job list.
+	Filters map[string]JobFilter `yaml:"filters"`
+
+	// Prompts replaces the built-in prompt of a role (underboss, capo,
+	// soldato, consigliere) with a template using {{name}} variables. A
+	// territory's .cosa/prompts/<role>.md file takes precedence.
+	Prompts map[string]string `yaml:"prompts"`
+}
+
+// AdmissionConfig configures an external policy endpoint that is asked to
+// accept, reject or adjust each job before it is queued, and the limits
+// that hold back new jobs when too many are submitted.
+type AdmissionConfig struct {
+	// URL receives a POST describing every new job. Empty admits all jobs.
+	URL string `yaml:"url"`
+
+	// Secret is sent in the X-Cosa-Secret header for verification.
+	Secret string `yaml:"secret"`
+
+	// Headers contains additional HTTP headers to send.
+	Headers map[string]string `yaml:"headers"`
+
+	// Timeout bounds each call to the endpoint (default: 5s).
+	Timeout time.Duration `yaml:"timeout"`
+
+	// FailClosed rejects jobs when the endpoint cannot be reached or gives
+	// an invalid answer. By default they are accepted.
+	FailClosed bool `yaml:"fail_closed"`
+
+	// MaxQueued is the most jobs that may wait in the queue. Jobs beyond it
+	// are refused until the queue drains (default: 500, 0 = no limit).
+	MaxQueued int `yaml:"max_queued"`
+
+	// RateLimit is the most jobs a client may add a minute. Clients are
+	// told apart by the name they log in with; those that have not logged
+	// in share one allowance on the socket, and the HTTP API and the
+	// in-process MCP server have one each (default: 0, no limit).
+	RateLimit int `yaml:"rate_limit"`
+}
+
+// EmailConfig configures the email gateway. Emails posted to the API's
+// /api/email route, by an MTA or an inbound email service, become draft
+// jobs that run once they are approved.
+type EmailConfig struct {
+	// Address is the address jobs are emailed to. Emails not sent to it are
+	// refused. Empty disables the gateway.
+	Address string `yaml:"address"`
+
+	// Allow lists who may email jobs: addresses, or whole domains written
+	// as "@example.com". No one may while it is empty.
+	Allow []string `yaml:"allow"`
+}
+
+// CleanupConfig is the policy for removing the worktrees, branches and
+// sessions jobs and workers leave behind.
+type CleanupConfig struct {
+	// Interval is how often cleanup runs (default: 1h).
+	Interval time.Duration `yaml:"interval"`
+
+	// MaxAge is how long the worktree and branch of a failed or cancelled
+	// job, and the worktree of a removed worker, are kept (default: 24h).
+	// Branches of completed jobs hold unmerged work and are never removed.
+	MaxAge time.Duration `yaml:"max_age"`
+
+	// MaxDiskUsage is the size in megabytes job worktrees may take up in
+	// total (0 is unlimited). Over it, the worktrees of finished jobs are
+	// removed oldest first, before they reach max_age.
+	MaxDiskUsage int `yaml:"max_disk_usage"`
+
+	// SessionMaxAge is how long an unused Claude session is kept
+	// (default: 7 days).
+	SessionMaxAge time.Duration `yaml:"session_max_age"`
+}
+
+// IndexConfig configures the repository index, a map of the territory's
+// directories, modules and symbols that is rebuilt while workers are idle
+// and included in job prompts.
+type IndexConfig struct {
+	// Interval is how often the index is checked against the repository's
+	// HEAD, and rebuilt if it is out of date and no worker is busy
+	// (default: 5m, 0 disables).
+	Interval time.Duration `yaml:"interval"`
+
+	// PromptBytes is the most of the index included in a job prompt
+	// (default: 4000, 0 leaves it out).
+	PromptBytes int `yaml:"prompt_bytes"`
+}
+
+// EncryptionConfig configures encryption at rest of the sessions,
+// transcripts and chats directories, which can hold proprietary code and
+// secrets. Files are encrypted with AES-256-GCM under a data key read from
+// COSA_DATA_KEY, or else from the OS keychain.
+type EncryptionConfig struct {
+	// Enabled encrypts the files as they are written. Files written before
+	// it was enabled stay readable, and are encrypted when the daemon
+	// starts. The daemon refuses to start without a data key. Files it
+	// encrypted cannot be read once it is turned off again.
+	Enabled bool `yaml:"enabled"`
+
+	// KeychainService names the OS keychain item holding the data key
+	// (default: cosa).
+	KeychainService string `yaml:"keychain_service"`
+}
+
+// DaemonConfig contains daemon lifecycle settings.
+type DaemonConfig struct {
+	// IdleShutdown stops the daemon after this long with no connected
+	// clients, running jobs or scheduled work (0 disables).
+	IdleShutdown time.Duration `yaml:"idle_shutdown"`
+
+	// LogMaxSize is the size in megabytes at which the daemon log is
+	// rotated (0 never rotates).
+	LogMaxSize int `yaml:"log_max_size"`
+
+	// LogMaxFiles is how many rotated daemon logs are kept.
+	LogMaxFiles int `yaml:"log_max_files"`
+
+	// SchedulerTrace is how many scheduler passes the decision trace keeps
+	// for 'cosa debug scheduler' (0 leaves the trace off until turned on).
+	SchedulerTrace int `yaml:"scheduler_trace"`
+}
+
+// APIConfig contains settings for the REST API CI pipelines use to submit
+// jobs and check on them.
+type APIConfig struct {
+	// Listen is the address the API listens on, such as "127.0.0.1:7420".
+	// Empty disables the API.
+	Listen string `yaml:"listen"`
+
+	// Token is the bearer token requests must carry. The API does not
+	// start without one.
+	Token string `yaml:"token"`
+}
+
+// Roles a client connection can have.
+const (
+	// AuthRoleOperator may call every method.
+	AuthRoleOperator = "operator"
+
+	// AuthRoleObserver may only call methods that do not change anything.
+	AuthRoleObserver = "observer"
+)
+
+// AuthConfig contains the tokens clients present to the daemon, so that
+// teammates can be given read-only access.
+type AuthConfig struct {
+	// Tokens lists the tokens the daemon accepts. When it is empty every
+	// connection has full access; otherwise a connection must log in with
+	// one of them first.
+	Tokens []AuthToken `yaml:"tokens"`
+
+	// Token is the token this machine's clients log in with.
+	Token string `yaml:"token"`
+}
+
+// AuthToken is a token the daemon accepts and the role it grants.
+type AuthToken struct {
+	// Name identifies who the token was given to, for logs.
+	Name string `yaml:"name"`
+
+	// Token is the secret the client presents.
+	Token string `yaml:"token"`
+
+	// Role is operator or observer. Empty means observer.
+	Role string `yaml:"role"`
+}
+
+// Lookup returns the configured token matching token.
+func (a AuthConfig) Lookup(token string) (AuthToken, bool) {
+	for _, t := range a.Tokens {
+		if t.Token != "" && subtle.ConstantTimeCompare([]byte(t.Token), []byte(token)) == 1 {
+			if t.Role == "" {
+				t.Role = AuthRoleObserver
+			}
+			return t, true
+		}
+	}
+	return AuthToken{}, false
+}
+
+// SpendLimits contains budget limits in US dollars. A zero limit is
+// disabled. New jobs are not started while a limit is exceeded.
+type SpendLimits struct {
+	// DailyUSD limits total spend across all workers per day.
+	DailyUSD float64 `yaml:"daily_usd"`
+
+	// PerWorkerUSD limits each worker's spend per day.
+	PerWorkerUSD float64 `yaml:"per_worker_usd"`
+
+	// PerJobUSD limits the total spend on a single job, including retries.
+	PerJobUSD float64 `yaml:"per_job_usd"`
 }
 
 // ClaudeConfig contains Claude Code CLI settings.
@@ -49,11 +295,55 @@ type ClaudeConfig struct {
 	// Model to use (optional, uses claude default if empty).
 	Model string `yaml:"model"`
 
+	// Fallback maps a model to the one a job switches to when the model
+	// fails for lack of capacity or a rate limit, e.g. opus: sonnet. The
+	// map is followed as a chain until a model succeeds or none is left.
+	Fallback map[string]string `yaml:"fallback"`
+
 	// MaxTurns limits the number of turns per session.
 	MaxTurns int `yaml:"max_turns"`
 
 	// ChatTimeout is the timeout in seconds for chat responses (default: 120).
 	ChatTimeout int `yaml:"chat_timeout"`
+
+	// ChatDrafts holds the jobs the underboss creates in chat as drafts,
+	// which wait for a person to approve them before they are queued
+	// (default: true).
+	ChatDrafts bool `yaml:"chat_drafts"`
+
+	// ResumeMaxCommits is the number of commits a worktree may gain before a
+	// worker's stored session is replaced by a fresh one with a recap
+	// (default: 25, 0 = no limit).
+	ResumeMaxCommits int `yaml:"resume_max_commits"`
+
+	// ResumeMaxFiles is the number of changed files allowed before a stored
+	// session is replaced (default: 50, 0 = no limit).
+	ResumeMaxFiles int `yaml:"resume_max_files"`
+
+	// SessionMaxTokens is the number of tokens a worker's session may use
+	// across jobs before it is rolled over: asked for a handoff summary and
+	// replaced by a fresh session seeded with it (default: 150000, 0 = no
+	// limit).
+	SessionMaxTokens int `yaml:"session_max_tokens"`
+
+	// Context gathers the files relevant to a job before it starts, to
+	// save the worker exploring for them.
+	Context ContextConfig `yaml:"context"`
+
+	// StrictOutput fails a session whose output has drifted from the
+	// format cosa knows, such as a result without its cost, rather than
+	// parsing what it can. Drift is logged either way (default: false).
+	StrictOutput bool `yaml:"strict_output"`
+}
+
+// ContextConfig contains settings for gathering a job's relevant files.
+type ContextConfig struct {
+	// Enabled searches the worktree for files matching the job's
+	// description and lists them in the prompt (default: true).
+	Enabled bool `yaml:"enabled"`
+
+	// MaxFiles is the most files listed (default: 10).
+	MaxFiles int `yaml:"max_files"`
 }
 
 // WorkerConfig contains worker defaults.
@@ -63,6 +353,116 @@ type WorkerConfig struct {
 
 	// DefaultRole for new workers.
 	DefaultRole string `yaml:"default_role"`
+
+	// ExitInterview asks a removed worker to summarize lessons learned
+	// before its worktree goes. The summary is appended to the territory
+	// knowledge base, which is included in the prompts of later jobs.
+	ExitInterview bool `yaml:"exit_interview"`
+
+	// JobTimeout fails a job run that takes longer than this, for jobs
+	// without their own timeout (0 disables).
+	JobTimeout time.Duration `yaml:"job_timeout"`
+
+	// TimeoutRetries is how many times a job that timed out is queued
+	// again before it is left failed.
+	TimeoutRetries int `yaml:"timeout_retries"`
+
+	// QuarantineAfter is how many jobs in a row a worker may fail before it
+	// is quarantined: given no more jobs until 'cosa worker unquarantine'
+	// (default: 3, 0 disables).
+	QuarantineAfter int `yaml:"quarantine_after"`
+
+	// Slots is how many jobs a worker of each role runs at once, each in
+	// its own worktree and Claude session (default: 1).
+	Slots map[string]int `yaml:"slots,omitempty"`
+
+	// StandingOrders are given to every worker, ahead of the orders for
+	// its role in the territory and its own.
+	StandingOrders []string `yaml:"standing_orders"`
+}
+
+// SlotsFor returns how many jobs a worker with the given role runs at once.
+func (c *WorkerConfig) SlotsFor(role string) int {
+	if n := c.Slots[role]; n > 1 {
+		return n
+	}
+	return 1
+}
+
+// LookoutConfig contains worker health monitor settings. Stuck workers are
+// always notified; the remediation says what else is done about them.
+type LookoutConfig struct {
+	// Remediation is the action for each stuck severity.
+	Remediation RemediationConfig `yaml:"remediation"`
+
+	// Workers overrides the remediation for workers by name. Severities
+	// left empty use Remediation.
+	Workers map[string]RemediationConfig `yaml:"workers,omitempty"`
+
+	// Thresholds are how long a working worker may go without progress
+	// before it is stuck at each severity.
+	Thresholds StuckThresholds `yaml:"thresholds"`
+
+	// Roles overrides the thresholds for workers by role. Severities left
+	// unset use Thresholds.
+	Roles map[string]StuckThresholds `yaml:"roles,omitempty"`
+}
+
+// StuckThresholds are how long a worker may go without progress, such as
+// a tool finishing, a file written or a commit, before it is stuck at each
+// severity. Claude's other output does not count.
+type StuckThresholds struct {
+	Warning  time.Duration `yaml:"warning,omitempty"`
+	Error    time.Duration `yaml:"error,omitempty"`
+	Critical time.Duration `yaml:"critical,omitempty"`
+}
+
+// ThresholdsFor returns the stuck thresholds for workers of a role, with
+// the role's overrides applied.
+func (c *Config) ThresholdsFor(role string) StuckThresholds {
+	t := c.Lookout.Thresholds
+	override, ok := c.Lookout.Roles[role]
+	if !ok {
+		return t
+	}
+	if override.Warning > 0 {
+		t.Warning = override.Warning
+	}
+	if override.Error > 0 {
+		t.Error = override.Error
+	}
+	if override.Critical > 0 {
+		t.Critical = override.Critical
+	}
+	return t
+}
+
+// RemediationConfig is the action (notify, nudge, restart or requeue) taken
+// for each stuck severity.
+type RemediationConfig struct {
+	Warning  string `yaml:"warning"`
+	Error    string `yaml:"error"`
+	Critical string `yaml:"critical"`
+}
+
+// RemediationFor returns the remediation for the named worker, with its
+// overrides applied.
+func (c *Config) RemediationFor(worker string) RemediationConfig {
+	r := c.Lookout.Remediation
+	override, ok := c.Lookout.Workers[worker]
+	if !ok {
+		return r
+	}
+	if override.Warning != "" {
+		r.Warning = override.Warning
+	}
+	if override.Error != "" {
+		r.Error = override.Error
+	}
+	if override.Critical != "" {
+		r.Critical = override.Critical
+	}
+	return r
 }
 
 // GitConfig contains git-related configuration.
@@ -71,15 +471,216 @@ type GitConfig struct {
 	// This serves as a global default when a territory doesn't have a DevBranch configured.
 	// Common values: main, master, staging, dev, develop
 	DefaultMergeBranch string `yaml:"default_merge_branch"`
+
+	// AssignConflictsToConsigliere assigns "resolve conflicts" follow-up jobs
+	// to an idle consigliere instead of queueing them for any worker.
+	AssignConflictsToConsigliere bool `yaml:"assign_conflicts_to_consigliere"`
+
+	// UncommittedChanges decides what happens when a worker finishes a job
+	// with uncommitted changes in its worktree: "commit" them (default) or
+	// "fail" the job.
+	UncommittedChanges string `yaml:"uncommitted_changes"`
+
+	// AutoCommitMessage is the commit message for automatically committed
+	// changes. {job}, {description} and {worker} are replaced.
+	AutoCommitMessage string `yaml:"auto_commit_message"`
+
+	// StaleBranchThreshold refreshes a queued job's existing worktree from
+	// the merge target once its branch is more than this many commits
+	// behind (0 disables).
+	StaleBranchThreshold int `yaml:"stale_branch_threshold"`
+
+	// StaleBranchInterval is how often queued job branches are checked.
+	StaleBranchInterval time.Duration `yaml:"stale_branch_interval"`
+
+	// RemoteMode decides what happens to a finished job's branch:
+	// "local-merge" merges it into the target branch (default),
+	// "push-branch" pushes it to the remote and "open-pr" pushes it and
+	// opens a pull request against the target branch.
+	RemoteMode string `yaml:"remote_mode"`
+
+	// Remote is the remote job branches are pushed to (default "origin").
+	Remote string `yaml:"remote"`
+
+	// PRProvider is "github" or "gitlab"; empty detects it from the
+	// remote URL.
+	PRProvider string `yaml:"pr_provider"`
+
+	// PRTitle is the pull request title. The placeholders of PRTemplate
+	// are replaced; the default is "{title}".
+	PRTitle string `yaml:"pr_title"`
+
+	// PRTemplate is the pull request body. {title}, {description},
+	// {body}, {job}, {worker}, {branch} and {target} are replaced, and
+	// {summary}, {diffstat}, {review}, {checklist} and {revisions} by a
+	// section with a heading, or nothing when there is nothing to show.
+	// The body is rewritten as the job is reviewed and revised.
+	PRTemplate string `yaml:"pr_template"`
+
+	// Author is who commits in job worktrees are made as, "Name <email>";
+	// {worker} is replaced by the worker's name. Empty leaves it to the
+	// repository's git config.
+	Author string `yaml:"author"`
+
+	// SigningFormat signs commits in job worktrees with "gpg" or "ssh";
+	// empty leaves signing to the repository's git config.
+	SigningFormat string `yaml:"signing_format"`
+
+	// SigningKey is the key commits are signed with: a GPG key ID, or for
+	// ssh a public key file. Empty uses git's default for the format.
+	SigningKey string `yaml:"signing_key"`
+
+	// JobTrailer adds a "Cosa-Job: <id>" trailer to every commit made in a
+	// job worktree.
+	JobTrailer bool `yaml:"job_trailer"`
+
+	// Workers overrides the author and signing for workers by name. Fields
+	// left empty use the settings above.
+	Workers map[string]GitIdentityConfig `yaml:"workers,omitempty"`
+}
+
+// GitIdentityConfig is who a worker's commits are made as and how they are
+// signed.
+type GitIdentityConfig struct {
+	Author        string `yaml:"author"`
+	SigningFormat string `yaml:"signing_format,omitempty"`
+	SigningKey    string `yaml:"signing_key,omitempty"`
+}
+
+// Commit signing formats.
+const (
+	SigningFormatGPG = "gpg"
+	SigningFormatSSH = "ssh"
+)
+
+// SigningFormats lists the valid git.signing_format values.
+var SigningFormats = []string{SigningFormatGPG, SigningFormatSSH}
+
+// GitIdentityFor returns the identity for the named worker, with its
+// overrides applied and {worker} replaced in the author.
+func (c *Config) GitIdentityFor(worker string) GitIdentityConfig {
+	id := GitIdentityConfig{
+		Author:        c.Git.Author,
+		SigningFormat: c.Git.SigningFormat,
+		SigningKey:    c.Git.SigningKey,
+	}
+	if override, ok := c.Git.Workers[worker]; ok {
+		if override.Author != "" {
+			id.Author = override.Author
+		}
+		if override.SigningFormat != "" {
+			id.SigningFormat = override.SigningFormat
+		}
+		if override.SigningKey != "" {
+			id.SigningKey = override.SigningKey
+		}
+	}
+	id.Author = strings.ReplaceAll(id.Author, "{worker}", worker)
+	return id
+}
+
+// ParseAuthor splits an author written "Name <email>".
+func ParseAuthor(author string) (name, email string, err error) {
+	open := strings.LastIndex(author, "<")
+	if open < 0 || !strings.HasSuffix(author, ">") {
+		return "", "", fmt.Errorf("invalid author %q (use \"Name <email>\")", author)
+	}
+	name = strings.TrimSpace(author[:open])
+	email = strings.TrimSpace(author[open+1 : len(author)-1])
+	if name == "" || email == "" || strings.ContainsAny(email, "<> ") {
+		return "", "", fmt.Errorf("invalid author %q (use \"Name <email>\")", author)
+	}
+	return name, email, nil
+}
+
+// Remote modes for finished job branches.
+const (
+	RemoteModeLocalMerge = "local-merge"
+	RemoteModePushBranch = "push-branch"
+	RemoteModeOpenPR     = "open-pr"
+)
+
+// RemoteModes lists the valid git.remote_mode values.
+var RemoteModes = []string{RemoteModeLocalMerge, RemoteModePushBranch, RemoteModeOpenPR}
+
+// ReviewConfig contains code review settings.
+type ReviewConfig struct {
+	// DiffSummarizer condenses diffs for reviews, notifications and
+	// handoffs: model (a call to SummaryModel), symbols (declarations added,
+	// removed and changed) or heuristic (a file and hunk outline). A
+	// summarizer that fails falls back to the next one in that order.
+	DiffSummarizer string `yaml:"diff_summarizer"`
+
+	// DiffSummaryThreshold is the diff size in bytes above which the
+	// reviewer gets a summary instead of the diff. 0 never summarizes.
+	DiffSummaryThreshold int `yaml:"diff_summary_threshold"`
+
+	// SummaryModel is the model used by the model summarizer.
+	SummaryModel string `yaml:"summary_model"`
+
+	// MaxRejections is how many times a job and its revisions may be
+	// rejected before the job is sent to triage instead of being revised
+	// again. 0 never sends rejected jobs to triage.
+	MaxRejections int `yaml:"max_rejections"`
+
+	// MaxRounds is how many revision rounds a job gets. A revision from
+	// the last round that is rejected again fails for good, ahead of
+	// MaxRejections. 0 never fails a job for its rejections.
+	MaxRounds int `yaml:"max_rounds"`
+
+	// Classifier labels completed jobs with their outcome (feature,
+	// bugfix, refactor, docs or test): heuristic (the brief's wording and
+	// the files changed), model (a call to SummaryModel, falling back to
+	// heuristic) or off. A job given an outcome label keeps it.
+	Classifier string `yaml:"classifier"`
+
+	// Sampling is the share of completed jobs of each outcome that are
+	// auto-reviewed, from 0 to 1. Outcomes not listed are always reviewed.
+	Sampling map[string]float64 `yaml:"sampling"`
+}
+
+// Job outcome classifiers.
+const (
+	ClassifierHeuristic = "heuristic"
+	ClassifierModel     = "model"
+	ClassifierOff       = "off"
+)
+
+// Classifiers lists the valid review.classifier values.
+var Classifiers = []string{ClassifierHeuristic, ClassifierModel, ClassifierOff}
+
+// ReviewSampleRate returns the share of jobs with outcome that are
+// auto-reviewed.
+func (r *ReviewConfig) ReviewSampleRate(outcome string) float64 {
+	rate, ok := r.Sampling[outcome]
+	if !ok {
+		return 1
+	}
+	return min(max(rate, 0), 1)
 }
 
+// Diff summarizers, in fallback order.
+const (
+	DiffSummarizerModel     = "model"
+	DiffSummarizerSymbols   = "symbols"
+	DiffSummarizerHeuristic = "heuristic"
+)
+
+// DiffSummarizers lists the valid review.diff_summarizer values.
+var DiffSummarizers = []string{DiffSummarizerModel, DiffSummarizerSymbols, DiffSummarizerHeuristic}
+
 // TUIConfig contains TUI settings.
 type TUIConfig struct {
-	// Theme name (noir, godfather, miami, opencode).
+	// Theme name: noir, godfather, miami, opencode, or a custom theme
+	// defined in ~/.cosa/themes/<name>.yaml.
 	Theme string `yaml:"theme"`
 
 	// RefreshRate in milliseconds for activity updates.
 	RefreshRate int `yaml:"refresh_rate"`
+
+	// Layout is the dashboard layout (default, operator, reviewer). The TUI
+	// updates it when the layout is switched.
+	Layout string `yaml:"layout"`
 }
 
 // NotificationConfig contains notification settings.
@@ -87,7 +688,7 @@ type NotificationConfig struct {
 	// TUIAlerts enables in-TUI alerts.
 	TUIAlerts bool `yaml:"tui_alerts"`
 
-	// SystemNotifications enables macOS system notifications.
+	// SystemNotifications enables desktop notifications on macOS and Linux.
 	SystemNotifications bool `yaml:"system_notifications"`
 
 	// TerminalBell enables terminal bell on events.
@@ -116,6 +717,42 @@ type NotificationConfig struct {
 
 	// Webhook contains generic webhook configuration.
 	Webhook WebhookConfig `yaml:"webhook"`
+
+	// Rules route notifications by event type, priority and severity. The
+	// first matching rule decides where a notification goes; notifications
+	// matching no rule use the channels enabled above.
+	Rules []NotificationRule `yaml:"rules"`
+
+	// DigestTime is the local time (HH:MM) at which the daily digest of
+	// notifications routed to it is sent (default: 09:00).
+	DigestTime string `yaml:"digest_time"`
+}
+
+// NotificationRule routes matching notifications to specific channels.
+// Empty match lists match anything.
+type NotificationRule struct {
+	// Name identifies the rule in `cosa notify explain`.
+	Name string `yaml:"name"`
+
+	// Events to match (job_completed, job_failed, worker_stuck,
+	// budget_warning, budget_exceeded, import_completed).
+	Events []string `yaml:"events"`
+
+	// Priorities to match, using job priority (1=low, 3=normal, 4=high,
+	// 5=critical). Higher numbers are more urgent, so a rule for urgent
+	// failures matches [4, 5], not [1].
+	// Notifications not tied to a job have no priority and never match.
+	Priorities []int `yaml:"priorities"`
+
+	// Severities to match (info, warning, error).
+	Severities []string `yaml:"severities"`
+
+	// Channels to notify immediately (desktop, bell, slack, discord,
+	// webhook). Webhook channels only need a URL, not enabled: true.
+	Channels []string `yaml:"channels"`
+
+	// Digest holds matching notifications for the daily digest.
+	Digest bool `yaml:"digest"`
 }
 
 // BudgetConfig contains budget alerting settings.
@@ -213,6 +850,60 @@ func (m *ModelConfig) ModelForRole(role string) string {
 	return m.Default
 }
 
+// JobPreset is a named bundle of options applied when adding a job.
+type JobPreset struct {
+	// Priority is the job priority (1-5, 0 means default).
+	Priority int `yaml:"priority"`
+
+	// Labels are key/value labels attached to the job.
+	Labels map[string]string `yaml:"labels"`
+
+	// SkipReview disables auto-review for the job.
+	SkipReview bool `yaml:"skip_review"`
+
+	// Model overrides the worker's model for the job.
+	Model string `yaml:"model"`
+
+	// Worker assigns the job to a specific worker.
+	Worker string `yaml:"worker"`
+}
+
+// Preset returns the named job preset.
+func (c *Config) Preset(name string) (JobPreset, bool) {
+	p, ok := c.Presets[name]
+	return p, ok
+}
+
+// PresetNames returns the configured preset names in sorted order.
+func (c *Config) PresetNames() []string {
+	names := make([]string, 0, len(c.Presets))
+	for name := range c.Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// JobFilter is a saved job list filter.
+type JobFilter struct {
+	// Status only lists jobs with this status. Empty lists every status.
+	Status string `yaml:"status,omitempty"`
+
+	// Labels only lists jobs matching every filter: key=value, or key for
+	// jobs with the label at all.
+	Labels []string `yaml:"labels,omitempty"`
+}
+
+// FilterNames returns the saved job filter names in sorted order.
+func (c *Config) FilterNames() []string {
+	names := make([]string, 0, len(c.Filters))
+	for name := range c.Filters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // DefaultConfig returns the default configuration.
 func DefaultConfig() *Config {
 	homeDir, _ := os.UserHomeDir()
@@ -222,21 +913,73 @@ func DefaultConfig() *Config {
 		SocketPath: filepath.Join(dataDir, "cosa.sock"),
 		DataDir:    dataDir,
 		LogLevel:   "info",
+		Daemon: DaemonConfig{
+			LogMaxSize:  10,
+			LogMaxFiles: 3,
+		},
+		Admission: AdmissionConfig{
+			Timeout:   5 * time.Second,
+			MaxQueued: 500,
+		},
+		Cleanup: CleanupConfig{
+			Interval:      time.Hour,
+			MaxAge:        24 * time.Hour,
+			SessionMaxAge: 7 * 24 * time.Hour,
+		},
+		Index: IndexConfig{
+			Interval:    5 * time.Minute,
+			PromptBytes: 4000,
+		},
 		Claude: ClaudeConfig{
-			Binary:      "claude",
-			MaxTurns:    100,
-			ChatTimeout: 120,
+			Binary:           "claude",
+			MaxTurns:         100,
+			ChatTimeout:      120,
+			ChatDrafts:       true,
+			ResumeMaxCommits: 25,
+			ResumeMaxFiles:   50,
+			SessionMaxTokens: 150000,
+			Context: ContextConfig{
+				Enabled:  true,
+				MaxFiles: 10,
+			},
 		},
 		Workers: WorkerConfig{
-			MaxConcurrent: 5,
-			DefaultRole:   "soldato",
+			MaxConcurrent:   5,
+			DefaultRole:     "soldato",
+			QuarantineAfter: 3,
+		},
+		Lookout: LookoutConfig{
+			Remediation: RemediationConfig{
+				Warning:  "notify",
+				Error:    "nudge",
+				Critical: "requeue",
+			},
+			Thresholds: StuckThresholds{
+				Warning:  5 * time.Minute,
+				Error:    15 * time.Minute,
+				Critical: 30 * time.Minute,
+			},
 		},
 		Git: GitConfig{
-			DefaultMergeBranch: "", // Empty means use repository's default branch
+			DefaultMergeBranch:   "", // Empty means use repository's default branch
+			UncommittedChanges:   "commit",
+			StaleBranchThreshold: 20,
+			StaleBranchInterval:  5 * time.Minute,
+			RemoteMode:           RemoteModeLocalMerge,
+			Remote:               "origin",
+			JobTrailer:           true,
+		},
+		Review: ReviewConfig{
+			DiffSummarizer:       DiffSummarizerHeuristic,
+			DiffSummaryThreshold: 40000,
+			SummaryModel:         "haiku",
+			MaxRejections:        3,
+			Classifier:           ClassifierHeuristic,
 		},
 		TUI: TUIConfig{
 			Theme:       "noir",
 			RefreshRate: 100,
+			Layout:      "default",
 		},
 		Notifications: NotificationConfig{
 			TUIAlerts:           true,
@@ -250,9 +993,10 @@ func DefaultConfig() *Config {
 				Limit:            0, // 0 means no limit
 				WarningThreshold: 80,
 			},
-			Slack:   SlackConfig{},
-			Discord: DiscordConfig{},
-			Webhook: WebhookConfig{},
+			Slack:      SlackConfig{},
+			Discord:    DiscordConfig{},
+			Webhook:    WebhookConfig{},
+			DigestTime: "09:00",
 		},
 		Models: ModelConfig{
 			Default:     "", // Use claude default
@@ -290,6 +1034,12 @@ func Load(path string) (*Config, error) {
 		return cfg, nil // No config file, use defaults
 	}
 
+	return loadInto(cfg, path)
+}
+
+// loadInto reads the config file at path over cfg. A missing file leaves
+// cfg unchanged.
+func loadInto(cfg *Config, path string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -318,7 +1068,18 @@ func (c *Config) Save(path string) error {
 		return err
 	}
 
-	return os.WriteFile(path, data, 0600)
+	// Write a temporary file and rename it over the config, so that a
+	// failed write never leaves a half-written config behind
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
 }
 
 // EnsureDataDir creates the data directory if it doesn't exist.
@@ -336,6 +1097,22 @@ func (c *Config) StatePath() string {
 	return filepath.Join(c.DataDir, "state.json")
 }
 
+// ClientIdentity returns the identity this client uses with the daemon.
+func (c *Config) ClientIdentity() string {
+	if c.Identity != "" {
+		return c.Identity
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return "default"
+}
+
+// DaemonLogPath returns the path to the daemon's operational log.
+func (c *Config) DaemonLogPath() string {
+	return filepath.Join(c.DataDir, "daemon.log")
+}
+
 // PIDPath returns the path to the daemon PID file.
 func (c *Config) PIDPath() string {
 	return filepath.Join(c.DataDir, "cosad.pid")
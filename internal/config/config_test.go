@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -44,6 +45,27 @@ func TestDefaultConfig(t *testing.T) {
 		t.Errorf("expected default role 'soldato', got '%s'", cfg.Workers.DefaultRole)
 	}
 
+	// Check admission defaults: no endpoint, fail open
+	if cfg.Admission.URL != "" || cfg.Admission.FailClosed {
+		t.Error("expected admission to be disabled and fail open")
+	}
+	if cfg.Admission.Timeout != 5*time.Second {
+		t.Errorf("expected admission timeout 5s, got %s", cfg.Admission.Timeout)
+	}
+
+	// Check cleanup defaults: no disk quota
+	if cfg.Cleanup.Interval != time.Hour || cfg.Cleanup.MaxAge != 24*time.Hour {
+		t.Errorf("expected cleanup every 1h of items over 24h, got %s and %s", cfg.Cleanup.Interval, cfg.Cleanup.MaxAge)
+	}
+	if cfg.Cleanup.MaxDiskUsage != 0 {
+		t.Errorf("expected no disk quota, got %d", cfg.Cleanup.MaxDiskUsage)
+	}
+
+	// Check index defaults
+	if cfg.Index.Interval != 5*time.Minute || cfg.Index.PromptBytes != 4000 {
+		t.Errorf("expected the index checked every 5m with 4000 bytes in prompts, got %v and %d", cfg.Index.Interval, cfg.Index.PromptBytes)
+	}
+
 	// Check TUI defaults
 	if cfg.TUI.Theme != "noir" {
 		t.Errorf("expected theme 'noir', got '%s'", cfg.TUI.Theme)
@@ -97,6 +119,8 @@ claude:
   binary: /usr/local/bin/claude
   model: claude-3-opus
   max_turns: 50
+  fallback:
+    claude-3-opus: sonnet
 workers:
   max_concurrent: 10
   default_role: capo
@@ -139,6 +163,9 @@ models:
 	if cfg.Claude.MaxTurns != 50 {
 		t.Errorf("expected max turns 50, got %d", cfg.Claude.MaxTurns)
 	}
+	if cfg.Claude.Fallback["claude-3-opus"] != "sonnet" {
+		t.Errorf("expected claude-3-opus to fall back to sonnet, got %v", cfg.Claude.Fallback)
+	}
 	if cfg.Workers.MaxConcurrent != 10 {
 		t.Errorf("expected max concurrent 10, got %d", cfg.Workers.MaxConcurrent)
 	}
@@ -325,6 +352,328 @@ func TestModelForRole_EmptyRoleConfig(t *testing.T) {
 	}
 }
 
+func TestLoad_IdleShutdown(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	if err := os.WriteFile(configPath, []byte("daemon:\n  idle_shutdown: 2h\n"), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if cfg.Daemon.IdleShutdown != 2*time.Hour {
+		t.Errorf("expected idle shutdown 2h, got %s", cfg.Daemon.IdleShutdown)
+	}
+
+	// Round trip through Save
+	savePath := filepath.Join(tmpDir, "saved.yaml")
+	if err := cfg.Save(savePath); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+	loaded, err := Load(savePath)
+	if err != nil {
+		t.Fatalf("failed to reload config: %v", err)
+	}
+	if loaded.Daemon.IdleShutdown != 2*time.Hour {
+		t.Errorf("expected idle shutdown 2h after save, got %s", loaded.Daemon.IdleShutdown)
+	}
+
+	// Log rotation keeps its defaults
+	if loaded.Daemon.LogMaxSize != 10 || loaded.Daemon.LogMaxFiles != 3 {
+		t.Errorf("expected default log rotation 10MB x 3, got %dMB x %d", loaded.Daemon.LogMaxSize, loaded.Daemon.LogMaxFiles)
+	}
+}
+
+func TestLoad_SpendLimits(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+budget:
+  daily_usd: 25
+  per_worker_usd: 10.5
+  per_job_usd: 2
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if cfg.Budget.DailyUSD != 25 {
+		t.Errorf("expected daily_usd 25, got %v", cfg.Budget.DailyUSD)
+	}
+	if cfg.Budget.PerWorkerUSD != 10.5 {
+		t.Errorf("expected per_worker_usd 10.5, got %v", cfg.Budget.PerWorkerUSD)
+	}
+	if cfg.Budget.PerJobUSD != 2 {
+		t.Errorf("expected per_job_usd 2, got %v", cfg.Budget.PerJobUSD)
+	}
+
+	// Limits are disabled by default
+	if def := DefaultConfig(); def.Budget.DailyUSD != 0 || def.Budget.PerWorkerUSD != 0 || def.Budget.PerJobUSD != 0 {
+		t.Errorf("expected no default budget limits, got %+v", def.Budget)
+	}
+}
+
+func TestLoad_LookoutRemediation(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+lookout:
+  remediation:
+    critical: restart
+  workers:
+    paulie:
+      error: restart
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	// Unset severities keep their defaults
+	want := RemediationConfig{Warning: "notify", Error: "nudge", Critical: "restart"}
+	if got := cfg.RemediationFor("silvio"); got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	want.Error = "restart"
+	if got := cfg.RemediationFor("paulie"); got != want {
+		t.Errorf("expected paulie override %+v, got %+v", want, got)
+	}
+}
+
+func TestLoad_LookoutThresholds(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+lookout:
+  thresholds:
+    warning: 10m
+  roles:
+    capo:
+      critical: 2h
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	// Unset severities keep their defaults
+	want := StuckThresholds{Warning: 10 * time.Minute, Error: 15 * time.Minute, Critical: 30 * time.Minute}
+	if got := cfg.ThresholdsFor("soldato"); got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	want.Critical = 2 * time.Hour
+	if got := cfg.ThresholdsFor("capo"); got != want {
+		t.Errorf("expected capo override %+v, got %+v", want, got)
+	}
+}
+
+func TestLoad_ChatDrafts(t *testing.T) {
+	if !DefaultConfig().Claude.ChatDrafts {
+		t.Error("expected the underboss's jobs to be drafts by default")
+	}
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("claude:\n  chat_drafts: false\n"), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if cfg.Claude.ChatDrafts {
+		t.Error("expected chat_drafts: false to turn drafts off")
+	}
+}
+
+func TestLoad_WorkerSlots(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+workers:
+  slots:
+    capo: 3
+    soldato: 0
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	for role, want := range map[string]int{"capo": 3, "soldato": 1, "consigliere": 1} {
+		if got := cfg.Workers.SlotsFor(role); got != want {
+			t.Errorf("expected %d slots for %s, got %d", want, role, got)
+		}
+	}
+}
+
+func TestLoad_GitIdentity(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+git:
+  author: "{worker} <{worker}+cosa@me.dev>"
+  signing_format: ssh
+  signing_key: ~/.ssh/cosa.pub
+  workers:
+    paulie:
+      author: "Paulie Gualtieri <paulie@me.dev>"
+      signing_format: gpg
+      signing_key: ABC123
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if !cfg.Git.JobTrailer {
+		t.Error("expected the job trailer to be on by default")
+	}
+
+	want := GitIdentityConfig{Author: "silvio <silvio+cosa@me.dev>", SigningFormat: "ssh", SigningKey: "~/.ssh/cosa.pub"}
+	if got := cfg.GitIdentityFor("silvio"); got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	want = GitIdentityConfig{Author: "Paulie Gualtieri <paulie@me.dev>", SigningFormat: "gpg", SigningKey: "ABC123"}
+	if got := cfg.GitIdentityFor("paulie"); got != want {
+		t.Errorf("expected paulie override %+v, got %+v", want, got)
+	}
+}
+
+func TestParseAuthor(t *testing.T) {
+	name, email, err := ParseAuthor("Paulie <paulie+cosa@me.dev>")
+	if err != nil || name != "Paulie" || email != "paulie+cosa@me.dev" {
+		t.Errorf("expected Paulie and paulie+cosa@me.dev, got %q %q (%v)", name, email, err)
+	}
+
+	for _, author := range []string{"paulie", "<paulie@me.dev>", "Paulie <>", "Paulie paulie@me.dev>"} {
+		if _, _, err := ParseAuthor(author); err == nil {
+			t.Errorf("expected %q to be rejected", author)
+		}
+	}
+}
+
+func TestLoad_Presets(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+presets:
+  quickfix:
+    priority: 2
+    labels:
+      type: bug
+    skip_review: true
+    model: haiku
+  urgent:
+    priority: 5
+`
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	p, ok := cfg.Preset("quickfix")
+	if !ok {
+		t.Fatal("expected quickfix preset to exist")
+	}
+	if p.Priority != 2 {
+		t.Errorf("expected priority 2, got %d", p.Priority)
+	}
+	if p.Labels["type"] != "bug" {
+		t.Errorf("expected label type=bug, got %v", p.Labels)
+	}
+	if !p.SkipReview {
+		t.Error("expected SkipReview to be true")
+	}
+	if p.Model != "haiku" {
+		t.Errorf("expected model 'haiku', got '%s'", p.Model)
+	}
+
+	if _, ok := cfg.Preset("missing"); ok {
+		t.Error("expected missing preset to not exist")
+	}
+
+	names := cfg.PresetNames()
+	if len(names) != 2 || names[0] != "quickfix" || names[1] != "urgent" {
+		t.Errorf("expected [quickfix urgent], got %v", names)
+	}
+}
+
+func TestLoad_Filters(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+filters:
+  auth-work:
+    status: running
+    labels: [area=auth, size]
+  large:
+    labels: [size=large]
+`
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	f := cfg.Filters["auth-work"]
+	if f.Status != "running" {
+		t.Errorf("expected status running, got %q", f.Status)
+	}
+	if len(f.Labels) != 2 || f.Labels[0] != "area=auth" || f.Labels[1] != "size" {
+		t.Errorf("expected labels [area=auth size], got %v", f.Labels)
+	}
+
+	names := cfg.FilterNames()
+	if len(names) != 2 || names[0] != "auth-work" || names[1] != "large" {
+		t.Errorf("expected [auth-work large], got %v", names)
+	}
+}
+
 func TestVersion(t *testing.T) {
 	if Version == "" {
 		t.Error("Version should not be empty")
@@ -367,3 +716,138 @@ func TestConfigStructures(t *testing.T) {
 		t.Error("struct field assignment failed")
 	}
 }
+
+func TestLoad_ReviewSummarizer(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	if err := os.WriteFile(configPath, []byte("review:\n  diff_summarizer: model\n"), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if cfg.Review.DiffSummarizer != DiffSummarizerModel {
+		t.Errorf("expected diff summarizer 'model', got '%s'", cfg.Review.DiffSummarizer)
+	}
+	// Unset fields keep their defaults
+	if cfg.Review.DiffSummaryThreshold != 40000 {
+		t.Errorf("expected diff summary threshold 40000, got %d", cfg.Review.DiffSummaryThreshold)
+	}
+	if cfg.Review.SummaryModel != "haiku" {
+		t.Errorf("expected summary model 'haiku', got '%s'", cfg.Review.SummaryModel)
+	}
+	if cfg.Review.MaxRejections != 3 {
+		t.Errorf("expected max rejections 3, got %d", cfg.Review.MaxRejections)
+	}
+	if cfg.Review.MaxRounds != 0 {
+		t.Errorf("expected max rounds 0, got %d", cfg.Review.MaxRounds)
+	}
+	if cfg.Workers.JobTimeout != 0 || cfg.Workers.TimeoutRetries != 0 {
+		t.Errorf("expected no job timeout by default, got %v with %d retries", cfg.Workers.JobTimeout, cfg.Workers.TimeoutRetries)
+	}
+	if cfg.Review.Classifier != ClassifierHeuristic {
+		t.Errorf("expected classifier 'heuristic', got '%s'", cfg.Review.Classifier)
+	}
+	if cfg.Workers.QuarantineAfter != 3 {
+		t.Errorf("expected workers quarantined after 3 failures, got %d", cfg.Workers.QuarantineAfter)
+	}
+}
+
+func TestReviewSampleRate(t *testing.T) {
+	r := ReviewConfig{Sampling: map[string]float64{"docs": 0.25, "test": 3, "refactor": -1}}
+
+	tests := map[string]float64{
+		"docs":     0.25,
+		"test":     1, // Clamped to 0-1
+		"refactor": 0,
+		"feature":  1, // Not listed, always reviewed
+	}
+	for outcome, want := range tests {
+		if got := r.ReviewSampleRate(outcome); got != want {
+			t.Errorf("ReviewSampleRate(%s) = %v, want %v", outcome, got, want)
+		}
+	}
+}
+
+func TestLoad_Prompts(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	content := "prompts:\n  soldato: |\n    You are {{name}}.\n    {{description}}\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if got := cfg.Prompts["soldato"]; got != "You are {{name}}.\n{{description}}\n" {
+		t.Errorf("unexpected soldato prompt %q", got)
+	}
+	if _, ok := cfg.Prompts["capo"]; ok {
+		t.Error("expected no capo prompt")
+	}
+}
+
+func TestClientIdentity(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.ClientIdentity() == "" {
+		t.Error("expected a default identity")
+	}
+
+	cfg.Identity = "carmela"
+	if got := cfg.ClientIdentity(); got != "carmela" {
+		t.Errorf("expected the configured identity, got %q", got)
+	}
+}
+
+func TestLoad_AuthTokens(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	content := `auth:
+  token: boss-token
+  tokens:
+    - name: tony
+      token: boss-token
+      role: operator
+    - name: paulie
+      token: paulie-token
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if cfg.Auth.Token != "boss-token" {
+		t.Errorf("expected client token boss-token, got %q", cfg.Auth.Token)
+	}
+
+	tok, ok := cfg.Auth.Lookup("boss-token")
+	if !ok || tok.Name != "tony" || tok.Role != AuthRoleOperator {
+		t.Errorf("expected tony as operator, got %+v (found %v)", tok, ok)
+	}
+
+	// A token without a role only grants observer access
+	tok, ok = cfg.Auth.Lookup("paulie-token")
+	if !ok || tok.Name != "paulie" || tok.Role != AuthRoleObserver {
+		t.Errorf("expected paulie as observer, got %+v (found %v)", tok, ok)
+	}
+
+	if _, ok := cfg.Auth.Lookup("wrong"); ok {
+		t.Error("expected an unknown token to be rejected")
+	}
+	if _, ok := cfg.Auth.Lookup(""); ok {
+		t.Error("expected an empty token to be rejected")
+	}
+}
@@ -0,0 +1,187 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultProfile is the profile backed by the top-level config file and
+// ~/.cosa data directory.
+const DefaultProfile = "default"
+
+// ProfileEnv selects a profile for a single process. cosa sets it for the
+// daemon it starts so both use the same profile.
+const ProfileEnv = "COSA_PROFILE"
+
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// ValidateProfileName checks that name can be used as a profile directory.
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q (use letters, digits, '-' and '_')", name)
+	}
+	return nil
+}
+
+// ProfilesDir returns the directory holding the named profiles.
+func ProfilesDir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".cosa", "profiles")
+}
+
+// ProfileDir returns the directory of a named profile. It is also the
+// profile's default data directory.
+func ProfileDir(name string) string {
+	return filepath.Join(ProfilesDir(), name)
+}
+
+// ProfileConfigPath returns the config file of a profile.
+func ProfileConfigPath(name string) string {
+	if name == "" || name == DefaultProfile {
+		return defaultConfigPath()
+	}
+	return filepath.Join(ProfileDir(name), "config.yaml")
+}
+
+// defaultConfigPath returns the existing top-level config file, or the
+// path where one is created.
+func defaultConfigPath() string {
+	homeDir, _ := os.UserHomeDir()
+	candidates := []string{
+		filepath.Join(homeDir, ".cosa", "config.yaml"),
+		filepath.Join(homeDir, ".config", "cosa", "config.yaml"),
+	}
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return candidates[0]
+}
+
+// activeProfilePath is the file recording the profile used when neither
+// --profile nor COSA_PROFILE is given.
+func activeProfilePath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".cosa", "profile")
+}
+
+// ActiveProfile returns the profile selected by COSA_PROFILE, or else the
+// one set with SetActiveProfile.
+func ActiveProfile() string {
+	if name := os.Getenv(ProfileEnv); name != "" {
+		return name
+	}
+	data, err := os.ReadFile(activeProfilePath())
+	if err != nil {
+		return DefaultProfile
+	}
+	if name := strings.TrimSpace(string(data)); name != "" {
+		return name
+	}
+	return DefaultProfile
+}
+
+// SetActiveProfile makes name the profile used by default.
+func SetActiveProfile(name string) error {
+	path := activeProfilePath()
+	if name == DefaultProfile {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if !ProfileExists(name) {
+		return fmt.Errorf("profile %q does not exist", name)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(name+"\n"), 0644)
+}
+
+// ProfileExists reports whether the named profile has been created. The
+// default profile always exists.
+func ProfileExists(name string) bool {
+	if name == DefaultProfile {
+		return true
+	}
+	_, err := os.Stat(ProfileConfigPath(name))
+	return err == nil
+}
+
+// ListProfiles returns the default profile followed by the named profiles,
+// sorted.
+func ListProfiles() ([]string, error) {
+	entries, err := os.ReadDir(ProfilesDir())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && ProfileExists(entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return append([]string{DefaultProfile}, names...), nil
+}
+
+// CreateProfile creates a named profile starting from base, with its data
+// directory and socket moved into the profile directory so it runs its own
+// daemon.
+func CreateProfile(name string, base *Config) (*Config, error) {
+	if err := ValidateProfileName(name); err != nil {
+		return nil, err
+	}
+	if name == DefaultProfile || ProfileExists(name) {
+		return nil, fmt.Errorf("profile %q already exists", name)
+	}
+
+	cfg := *base
+	cfg.Profile = name
+	cfg.DataDir = ProfileDir(name)
+	cfg.SocketPath = filepath.Join(cfg.DataDir, "cosa.sock")
+
+	if err := cfg.Save(ProfileConfigPath(name)); err != nil {
+		return nil, fmt.Errorf("failed to write profile config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// LoadProfile loads the configuration of the named profile. Named profiles
+// start from the defaults, not the top-level config, so they stay isolated.
+func LoadProfile(name string) (*Config, error) {
+	if name == "" || name == DefaultProfile {
+		cfg, err := Load("")
+		if err != nil {
+			return nil, err
+		}
+		cfg.Profile = DefaultProfile
+		return cfg, nil
+	}
+
+	if err := ValidateProfileName(name); err != nil {
+		return nil, err
+	}
+	if !ProfileExists(name) {
+		return nil, fmt.Errorf("profile %q does not exist (see 'cosa profile list')", name)
+	}
+
+	defaults := DefaultConfig()
+	defaults.DataDir = ProfileDir(name)
+	defaults.SocketPath = filepath.Join(defaults.DataDir, "cosa.sock")
+
+	cfg, err := loadInto(defaults, ProfileConfigPath(name))
+	if err != nil {
+		return nil, err
+	}
+	cfg.Profile = name
+	return cfg, nil
+}
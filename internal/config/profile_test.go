@@ -0,0 +1,96 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestProfiles(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(ProfileEnv, "")
+
+	if got := ActiveProfile(); got != DefaultProfile {
+		t.Errorf("expected the default profile to be active, got %q", got)
+	}
+
+	base := DefaultConfig()
+	base.Models.Soldato = "opus"
+	base.Notifications.Budget.Limit = 50
+
+	work, err := CreateProfile("work", base)
+	if err != nil {
+		t.Fatalf("failed to create profile: %v", err)
+	}
+	wantDir := filepath.Join(home, ".cosa", "profiles", "work")
+	if work.DataDir != wantDir {
+		t.Errorf("expected data dir %s, got %s", wantDir, work.DataDir)
+	}
+	if work.SocketPath != filepath.Join(wantDir, "cosa.sock") {
+		t.Errorf("expected the socket in the profile dir, got %s", work.SocketPath)
+	}
+	if base.DataDir == work.DataDir {
+		t.Error("expected the base config to be left alone")
+	}
+
+	if _, err := CreateProfile("work", base); err == nil {
+		t.Error("expected an error creating a profile twice")
+	}
+	if _, err := CreateProfile("../evil", base); err == nil {
+		t.Error("expected an error for an invalid profile name")
+	}
+
+	loaded, err := LoadProfile("work")
+	if err != nil {
+		t.Fatalf("failed to load profile: %v", err)
+	}
+	if loaded.Profile != "work" || loaded.DataDir != wantDir {
+		t.Errorf("expected the work profile in %s, got %q in %s", wantDir, loaded.Profile, loaded.DataDir)
+	}
+	if loaded.Models.Soldato != "opus" || loaded.Notifications.Budget.Limit != 50 {
+		t.Error("expected settings copied from the base config")
+	}
+
+	if _, err := LoadProfile("missing"); err == nil {
+		t.Error("expected an error loading a missing profile")
+	}
+
+	names, err := ListProfiles()
+	if err != nil {
+		t.Fatalf("failed to list profiles: %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{DefaultProfile, "work"}) {
+		t.Errorf("expected default and work, got %v", names)
+	}
+}
+
+func TestActiveProfile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(ProfileEnv, "")
+
+	if err := SetActiveProfile("oss"); err == nil {
+		t.Error("expected an error selecting a missing profile")
+	}
+
+	CreateProfile("oss", DefaultConfig())
+	if err := SetActiveProfile("oss"); err != nil {
+		t.Fatalf("failed to select profile: %v", err)
+	}
+	if got := ActiveProfile(); got != "oss" {
+		t.Errorf("expected oss to be active, got %q", got)
+	}
+
+	t.Setenv(ProfileEnv, "work")
+	if got := ActiveProfile(); got != "work" {
+		t.Errorf("expected %s to override the selected profile, got %q", ProfileEnv, got)
+	}
+	t.Setenv(ProfileEnv, "")
+
+	if err := SetActiveProfile(DefaultProfile); err != nil {
+		t.Fatalf("failed to select the default profile: %v", err)
+	}
+	if got := ActiveProfile(); got != DefaultProfile {
+		t.Errorf("expected the default profile to be active, got %q", got)
+	}
+}
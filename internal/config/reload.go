@@ -0,0 +1,155 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ReloadableKeys are the settings a running daemon picks up when its config
+// file is reloaded, each covering the keys beneath it. Every other setting
+// takes effect when the daemon restarts.
+var ReloadableKeys = []string{
+	"claude.model",
+	"claude.fallback",
+	"claude.context",
+	"notifications",
+	"budget",
+	"admission.max_queued",
+	"admission.rate_limit",
+	"workers.job_timeout",
+	"workers.timeout_retries",
+	"workers.quarantine_after",
+	"workers.standing_orders",
+	"lookout.thresholds",
+	"lookout.roles",
+}
+
+// IsReloadable reports whether key is a setting the daemon reloads without
+// a restart.
+func IsReloadable(key string) bool {
+	for _, prefix := range ReloadableKeys {
+		if key == prefix || strings.HasPrefix(key, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Change is a setting whose value differs between two configs.
+type Change struct {
+	Key string `json:"key"`
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+}
+
+// Reload returns a copy of c with the reloadable settings taken from next,
+// leaving c unchanged, along with the changes it applied. The changes in
+// pending are to settings that need a restart, and are not applied.
+func (c *Config) Reload(next *Config) (reloaded *Config, applied, pending []Change, err error) {
+	changes, err := Diff(c, next)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	cp := *c
+	cp.Claude.Model = next.Claude.Model
+	cp.Claude.Fallback = next.Claude.Fallback
+	cp.Claude.Context = next.Claude.Context
+	cp.Notifications = next.Notifications
+	cp.Budget = next.Budget
+	cp.Admission.MaxQueued = next.Admission.MaxQueued
+	cp.Admission.RateLimit = next.Admission.RateLimit
+	cp.Workers.JobTimeout = next.Workers.JobTimeout
+	cp.Workers.TimeoutRetries = next.Workers.TimeoutRetries
+	cp.Workers.QuarantineAfter = next.Workers.QuarantineAfter
+	cp.Workers.StandingOrders = next.Workers.StandingOrders
+	cp.Lookout.Thresholds = next.Lookout.Thresholds
+	cp.Lookout.Roles = next.Lookout.Roles
+
+	for _, change := range changes {
+		if IsReloadable(change.Key) {
+			applied = append(applied, change)
+		} else {
+			pending = append(pending, change)
+		}
+	}
+	return &cp, applied, pending, nil
+}
+
+// Diff lists the settings that differ between a and b, by key. Values of
+// secrets, such as tokens and webhook URLs, are left out.
+func Diff(a, b *Config) ([]Change, error) {
+	before, err := flatten(a)
+	if err != nil {
+		return nil, err
+	}
+	after, err := flatten(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	for key, old := range before {
+		if now, ok := after[key]; !ok || now != old {
+			changes = append(changes, Change{Key: key, Old: old, New: now})
+		}
+	}
+	for key, now := range after {
+		if _, ok := before[key]; !ok {
+			changes = append(changes, Change{Key: key, New: now})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+
+	for i, change := range changes {
+		if isSecret(change.Key) {
+			changes[i].Old, changes[i].New = "", ""
+		}
+	}
+	return changes, nil
+}
+
+// flatten maps each setting in cfg to its value, with nested settings
+// keyed by their dotted path as in 'cosa settings'. Lists are one value.
+func flatten(cfg *Config) (map[string]string, error) {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var tree map[string]any
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	var walk func(prefix string, node map[string]any)
+	walk = func(prefix string, node map[string]any) {
+		for k, v := range node {
+			key := prefix + k
+			if child, ok := v.(map[string]any); ok {
+				walk(key+".", child)
+				continue
+			}
+			if v == nil {
+				continue
+			}
+			values[key] = fmt.Sprint(v)
+		}
+	}
+	walk("", tree)
+	return values, nil
+}
+
+// isSecret reports whether the setting at key holds a credential.
+func isSecret(key string) bool {
+	name := strings.ToLower(key[strings.LastIndex(key, ".")+1:])
+	for _, word := range []string{"token", "secret", "password", "webhook", "url"} {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfig_Reload(t *testing.T) {
+	cur := DefaultConfig()
+	cur.Claude.Fallback = map[string]string{"opus": "sonnet"}
+
+	next := DefaultConfig()
+	next.Claude.Model = "opus"
+	next.Claude.Fallback = map[string]string{"opus": "haiku"}
+	next.Budget.DailyUSD = 20
+	next.Workers.JobTimeout = 45 * time.Minute
+	next.Lookout.Thresholds.Warning = 2 * time.Minute
+	next.Lookout.Roles = map[string]StuckThresholds{"capo": {Warning: time.Hour}}
+	next.Notifications.Slack.WebhookURL = "https://hooks.example.com/secret"
+	next.Claude.MaxTurns = 7
+	next.SocketPath = "/tmp/other.sock"
+
+	reloaded, applied, pending, err := cur.Reload(next)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if reloaded.Claude.Model != "opus" || reloaded.Budget.DailyUSD != 20 || reloaded.Workers.JobTimeout != 45*time.Minute {
+		t.Errorf("expected reloadable settings to be applied, got model %q, budget %v, timeout %s",
+			reloaded.Claude.Model, reloaded.Budget.DailyUSD, reloaded.Workers.JobTimeout)
+	}
+	if reloaded.Lookout.Thresholds.Warning != 2*time.Minute || reloaded.Lookout.Roles["capo"].Warning != time.Hour {
+		t.Errorf("expected the lookout thresholds to be applied, got %+v", reloaded.Lookout)
+	}
+	if reloaded.Claude.MaxTurns != cur.Claude.MaxTurns || reloaded.SocketPath != cur.SocketPath {
+		t.Errorf("expected settings needing a restart to be kept, got max_turns %d, socket %q", reloaded.Claude.MaxTurns, reloaded.SocketPath)
+	}
+	if cur.Claude.Model == "opus" || cur.Budget.DailyUSD == 20 {
+		t.Error("expected the current config to be left unchanged")
+	}
+
+	keys := func(changes []Change) map[string]Change {
+		m := make(map[string]Change)
+		for _, c := range changes {
+			m[c.Key] = c
+		}
+		return m
+	}
+
+	got := keys(applied)
+	for _, key := range []string{"claude.model", "claude.fallback.opus", "budget.daily_usd", "workers.job_timeout", "notifications.slack.webhook_url", "lookout.thresholds.warning", "lookout.roles.capo.warning"} {
+		if _, ok := got[key]; !ok {
+			t.Errorf("expected %s among the applied changes, got %+v", key, applied)
+		}
+	}
+	if c := got["budget.daily_usd"]; c.Old != "0" || c.New != "20" {
+		t.Errorf("expected budget.daily_usd to change from 0 to 20, got %q to %q", c.Old, c.New)
+	}
+	if c := got["workers.job_timeout"]; c.New != "45m0s" {
+		t.Errorf("expected workers.job_timeout to show as a duration, got %q", c.New)
+	}
+	if c := got["notifications.slack.webhook_url"]; c.Old != "" || c.New != "" {
+		t.Errorf("expected the webhook URL to be left out, got %q to %q", c.Old, c.New)
+	}
+
+	got = keys(pending)
+	if len(pending) != 2 || got["claude.max_turns"].Key == "" || got["socket_path"].Key == "" {
+		t.Errorf("expected claude.max_turns and socket_path to need a restart, got %+v", pending)
+	}
+}
+
+func TestIsReloadable(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"budget.daily_usd", true},
+		{"notifications.slack.enabled", true},
+		{"claude.model", true},
+		{"claude.max_turns", false},
+		{"budgets", false},
+		{"socket_path", false},
+	}
+	for _, tt := range tests {
+		if got := IsReloadable(tt.key); got != tt.want {
+			t.Errorf("IsReloadable(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
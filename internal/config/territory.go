@@ -0,0 +1,133 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TerritoryConfigFile is the file in a territory's .cosa directory whose
+// settings override the global config for jobs in that territory.
+const TerritoryConfigFile = "config.yaml"
+
+// TerritoryConfig is a territory's .cosa/config.yaml. Only the settings
+// here may be overridden per territory; anything else in the file is an
+// error rather than silently ignored.
+type TerritoryConfig struct {
+	// Claude overrides the model and turn limit of the territory's workers.
+	Claude TerritoryClaudeConfig `yaml:"claude"`
+
+	// Gates overrides the review gate commands in territory.json.
+	Gates GateConfig `yaml:"gates"`
+
+	// Notifications is merged over the global notification settings, so
+	// only the keys it sets change.
+	Notifications yaml.Node `yaml:"notifications"`
+}
+
+// TerritoryClaudeConfig contains the Claude settings a territory may set.
+type TerritoryClaudeConfig struct {
+	// Model replaces claude.model for the territory's workers and reviews.
+	Model string `yaml:"model"`
+
+	// MaxTurns replaces claude.max_turns for the territory's workers.
+	MaxTurns int `yaml:"max_turns"`
+}
+
+// GateConfig contains the commands run before a job is reviewed.
+type GateConfig struct {
+	// TestCommand runs the tests, e.g. "go test ./...".
+	TestCommand string `yaml:"test_command"`
+
+	// BuildCommand builds the project, e.g. "go build ./...".
+	BuildCommand string `yaml:"build_command"`
+}
+
+// LoadTerritoryConfig reads a territory config file. A missing file gives
+// an empty config that overrides nothing.
+func LoadTerritoryConfig(path string) (*TerritoryConfig, error) {
+	tc := &TerritoryConfig{}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return tc, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := decodeStrict(data, tc); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("invalid territory config %s: %w", path, err)
+	}
+
+	// Check the notification keys now rather than when they are applied
+	if _, err := tc.notifications(&NotificationConfig{}); err != nil {
+		return nil, fmt.Errorf("invalid territory config %s: %w", path, err)
+	}
+
+	return tc, nil
+}
+
+// Apply returns a copy of cfg with the territory's overrides applied. cfg
+// itself is left unchanged.
+func (tc *TerritoryConfig) Apply(cfg *Config) (*Config, error) {
+	merged := *cfg
+
+	if tc.Claude.Model != "" {
+		merged.Claude.Model = tc.Claude.Model
+	}
+	if tc.Claude.MaxTurns != 0 {
+		merged.Claude.MaxTurns = tc.Claude.MaxTurns
+	}
+
+	notifications, err := tc.notifications(&cfg.Notifications)
+	if err != nil {
+		return nil, err
+	}
+	merged.Notifications = *notifications
+
+	return &merged, nil
+}
+
+// HasNotifications reports whether the territory overrides any
+// notification settings.
+func (tc *TerritoryConfig) HasNotifications() bool {
+	return tc.Notifications.Kind != 0
+}
+
+// notifications returns base with the territory's notification settings
+// merged over it. base is copied first, since decoding into its maps and
+// slices would change them in place.
+func (tc *TerritoryConfig) notifications(base *NotificationConfig) (*NotificationConfig, error) {
+	data, err := yaml.Marshal(base)
+	if err != nil {
+		return nil, err
+	}
+	merged := &NotificationConfig{}
+	if err := yaml.Unmarshal(data, merged); err != nil {
+		return nil, err
+	}
+
+	if !tc.HasNotifications() {
+		return merged, nil
+	}
+	overrides, err := yaml.Marshal(&tc.Notifications)
+	if err != nil {
+		return nil, err
+	}
+	if err := decodeStrict(overrides, merged); err != nil {
+		return nil, fmt.Errorf("notifications: %w", err)
+	}
+	return merged, nil
+}
+
+// decodeStrict unmarshals YAML into out, refusing keys out has no field for.
+func decodeStrict(data []byte, out interface{}) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	return dec.Decode(out)
+}
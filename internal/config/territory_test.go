@@ -0,0 +1,91 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTerritoryConfig_Apply(t *testing.T) {
+	path := filepath.Join(t.TempDir(), TerritoryConfigFile)
+	os.WriteFile(path, []byte(`
+claude:
+  model: opus
+  max_turns: 80
+gates:
+  test_command: make test
+notifications:
+  on_job_complete: false
+  slack:
+    enabled: true
+    webhook_url: https://hooks.slack.com/work
+`), 0644)
+
+	tc, err := LoadTerritoryConfig(path)
+	if err != nil {
+		t.Fatalf("LoadTerritoryConfig() error: %v", err)
+	}
+	if tc.Gates.TestCommand != "make test" {
+		t.Errorf("expected the test gate to be read, got %q", tc.Gates.TestCommand)
+	}
+
+	global := DefaultConfig()
+	global.Claude.Model = "sonnet"
+	global.Notifications.OnJobComplete = true
+	global.Notifications.OnJobFailed = true
+	global.Notifications.Webhook.Headers = map[string]string{"X-Team": "home"}
+
+	merged, err := tc.Apply(global)
+	if err != nil {
+		t.Fatalf("Apply() error: %v", err)
+	}
+	if merged.Claude.Model != "opus" || merged.Claude.MaxTurns != 80 {
+		t.Errorf("expected the territory's model and max turns, got %q and %d", merged.Claude.Model, merged.Claude.MaxTurns)
+	}
+	if merged.Notifications.OnJobComplete {
+		t.Error("expected the territory to turn off job complete notifications")
+	}
+	if !merged.Notifications.OnJobFailed {
+		t.Error("expected notification settings the territory leaves out to be kept")
+	}
+	if !merged.Notifications.Slack.Enabled || merged.Notifications.Slack.WebhookURL != "https://hooks.slack.com/work" {
+		t.Errorf("expected the territory's Slack webhook, got %+v", merged.Notifications.Slack)
+	}
+
+	if global.Claude.Model != "sonnet" || !global.Notifications.OnJobComplete || global.Notifications.Slack.Enabled {
+		t.Error("expected the global config to be left alone")
+	}
+}
+
+func TestLoadTerritoryConfig_Missing(t *testing.T) {
+	tc, err := LoadTerritoryConfig(filepath.Join(t.TempDir(), TerritoryConfigFile))
+	if err != nil {
+		t.Fatalf("LoadTerritoryConfig() error: %v", err)
+	}
+
+	global := DefaultConfig()
+	merged, err := tc.Apply(global)
+	if err != nil {
+		t.Fatalf("Apply() error: %v", err)
+	}
+	if merged.Claude.Model != global.Claude.Model || tc.HasNotifications() {
+		t.Error("expected a missing territory config to override nothing")
+	}
+}
+
+func TestLoadTerritoryConfig_UnknownKeys(t *testing.T) {
+	for name, content := range map[string]string{
+		"top level":     "data_dir: /tmp/elsewhere\n",
+		"claude":        "claude:\n  binary: /tmp/not-claude\n",
+		"notifications": "notifications:\n  on_everything: true\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), TerritoryConfigFile)
+			os.WriteFile(path, []byte(content), 0644)
+
+			if _, err := LoadTerritoryConfig(path); err == nil {
+				t.Error("expected an error for a setting a territory cannot override")
+			}
+		})
+	}
+}
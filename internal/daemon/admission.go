@@ -0,0 +1,138 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"time"
+
+	"cosa/internal/admission"
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+)
+
+// admitJob asks the admission policy whether to accept a new job, applying
+// any label or priority changes it makes. It returns an
+// *admission.RejectedError if the job is not accepted.
+func (s *Server) admitJob(j *job.Job, params protocol.JobAddParams) error {
+	if !s.admission.Enabled() {
+		return nil
+	}
+
+	decision, err := s.admission.Admit(s.ctx, admission.Request{
+		JobID:         j.ID,
+		Description:   j.Description,
+		Body:          j.GetBody(),
+		Labels:        params.Labels,
+		Priority:      j.Priority,
+		Worker:        params.Worker,
+		Model:         j.GetModel(),
+		Requester:     params.Requester,
+		EstimatedCost: s.spend.AverageJob(),
+	})
+	if err != nil {
+		s.log.Warn("admission check failed", "job", j.ID, "admitted", decision.Allow, "error", err)
+	}
+
+	if !decision.Allow {
+		s.ledger.Append(ledger.EventType("job.rejected"), map[string]string{
+			"id":          j.ID,
+			"description": j.Description,
+			"requester":   params.Requester,
+			"reason":      decision.Reason,
+		})
+		return &admission.RejectedError{Reason: decision.Reason}
+	}
+
+	if decision.Changes() {
+		labels, priority := decision.Apply(params.Labels, j.Priority)
+		j.SetLabels(labels)
+		j.SetPriority(priority)
+		s.ledger.Append(ledger.EventType("job.admission_adjusted"), map[string]interface{}{
+			"id":       j.ID,
+			"labels":   decision.Labels,
+			"priority": decision.Priority,
+		})
+	}
+	return nil
+}
+
+// queueFullRetryAfter is how long clients are told to wait before adding
+// jobs to a full queue again.
+const queueFullRetryAfter = 30 * time.Second
+
+// checkQueueRoom returns an *admission.ThrottledError if the queue already
+// holds admission.max_queued jobs.
+func (s *Server) checkQueueRoom() error {
+	limit := s.jobConfig().Admission.MaxQueued
+	if limit <= 0 || s.queue.Len() < limit {
+		return nil
+	}
+	return &admission.ThrottledError{
+		Reason:     fmt.Sprintf("queue is full (%d jobs waiting)", s.queue.Len()),
+		RetryAfter: queueFullRetryAfter,
+	}
+}
+
+// throttleClient counts a job.add request against its client's
+// admission.rate_limit, returning an *admission.ThrottledError if the
+// client is over it.
+func (s *Server) throttleClient(conn net.Conn, id *protocol.RequestID) error {
+	client := s.clientName(conn, id)
+	wait := s.rateLimiter.Allow(client, s.jobConfig().Admission.RateLimit, time.Now())
+	if wait == 0 {
+		return nil
+	}
+	s.log.Warn("job.add rate limited", "client", client, "retry_after", wait.Round(time.Second).String())
+	return &admission.ThrottledError{
+		Reason:     fmt.Sprintf("%s is adding jobs too fast (limit %d a minute)", client, s.jobConfig().Admission.RateLimit),
+		RetryAfter: wait,
+	}
+}
+
+// clientName tells apart the clients whose requests arrive on conn, by
+// what the request cannot choose: the name the connection logged in with,
+// else "socket" for every client that has not logged in, so reconnecting
+// gets no fresh allowance. Requests from inside the daemon have no
+// connection and are named by their ID, which their entry point sets:
+// "api" for the HTTP API and "mcp" for the in-process MCP server.
+func (s *Server) clientName(conn net.Conn, id *protocol.RequestID) string {
+	if conn == nil {
+		if id != nil && id.Str != nil && *id.Str != "" {
+			return *id.Str
+		}
+		return "api"
+	}
+
+	s.clientsMu.RLock()
+	state := s.clients[conn]
+	s.clientsMu.RUnlock()
+	if state != nil && state.name != "" {
+		return state.name
+	}
+	return "socket"
+}
+
+// jobAddErrorResponse reports an error adding a job, with the code that
+// tells clients whether to give up or back off and retry.
+func jobAddErrorResponse(id *protocol.RequestID, err error) *protocol.Response {
+	var rejected *admission.RejectedError
+	var throttled *admission.ThrottledError
+	switch {
+	case errors.As(err, &rejected):
+		resp, _ := protocol.NewErrorResponse(id, protocol.ErrJobRejected, err.Error(), nil)
+		return resp
+	case errors.As(err, &throttled):
+		retryAfter := int(math.Ceil(throttled.RetryAfter.Seconds()))
+		resp, _ := protocol.NewErrorResponse(id, protocol.ErrQueueFull, err.Error(), protocol.QueueFullData{RetryAfter: retryAfter})
+		return resp
+	case errors.Is(err, errQueueDraining):
+		resp, _ := protocol.NewErrorResponse(id, protocol.ErrInvalidState, err.Error(), nil)
+		return resp
+	default:
+		resp, _ := protocol.NewErrorResponse(id, protocol.InvalidParams, err.Error(), nil)
+		return resp
+	}
+}
@@ -0,0 +1,235 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"cosa/internal/config"
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+)
+
+const (
+	// apiMaxBody caps the size of a request body.
+	apiMaxBody = 1 << 20
+
+	// apiShutdownTimeout bounds how long in-flight API requests get to
+	// finish when the daemon stops.
+	apiShutdownTimeout = 5 * time.Second
+)
+
+// startAPI starts the REST API if api.listen is set. It maps a few routes
+// onto the same handlers the socket serves so CI pipelines can submit jobs
+// with curl:
+//
+//	POST /api/jobs       job.add
+//	GET  /api/jobs/{id}  job.status
+//	GET  /api/health     daemon liveness
+//	POST /api/email      a raw email, filed as a draft job (see email.address)
+//
+// Every request needs the api.token bearer token.
+func (s *Server) startAPI() {
+	addr := s.config().API.Listen
+	if addr == "" {
+		return
+	}
+	if s.config().API.Token == "" {
+		s.log.Error("api.listen is set but api.token is not; the API is disabled")
+		s.ledger.Append(ledger.EventType("api.error"), map[string]string{
+			"error": "api.listen is set but api.token is not; the API is disabled",
+		})
+		return
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		s.log.Error("failed to start the API", "listen", addr, "error", err)
+		s.ledger.Append(ledger.EventType("api.error"), map[string]string{
+			"error": "failed to listen on " + addr + ": " + err.Error(),
+		})
+		return
+	}
+
+	s.apiServer = &http.Server{
+		Handler:           s.apiHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	s.ledger.Append(ledger.EventType("api.started"), map[string]string{
+		"address": listener.Addr().String(),
+	})
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if err := s.apiServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+			s.log.Error("API server stopped", "error", err)
+			s.ledger.Append(ledger.EventType("api.error"), map[string]string{
+				"error": err.Error(),
+			})
+		}
+	}()
+}
+
+// apiHandler routes the API's requests, each behind the bearer token.
+func (s *Server) apiHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/health", s.apiAuth(s.handleAPIHealth))
+	mux.HandleFunc("POST /api/jobs", s.apiAuth(s.handleAPIJobAdd))
+	mux.HandleFunc("GET /api/jobs/{id}", s.apiAuth(s.handleAPIJobStatus))
+	mux.HandleFunc("POST /api/email", s.apiAuth(s.handleAPIEmail))
+	return mux
+}
+
+// stopAPI stops the REST API, letting in-flight requests finish.
+func (s *Server) stopAPI() {
+	if s.apiServer == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), apiShutdownTimeout)
+	defer cancel()
+	s.apiServer.Shutdown(ctx)
+}
+
+// apiAuth rejects requests without the configured bearer token.
+func (s *Server) apiAuth(next http.HandlerFunc) http.HandlerFunc {
+	want := []byte("Bearer " + s.config().API.Token)
+	return func(w http.ResponseWriter, r *http.Request) {
+		got := []byte(r.Header.Get("Authorization"))
+		if subtle.ConstantTimeCompare(got, want) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="cosa"`)
+			writeAPIError(w, http.StatusUnauthorized, &protocol.Error{
+				Code:    protocol.InvalidRequest,
+				Message: "missing or invalid bearer token",
+			})
+			return
+		}
+		s.touchActivity()
+		next(w, r)
+	}
+}
+
+func (s *Server) handleAPIHealth(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	territoryLoaded := s.territory != nil
+	s.mu.RUnlock()
+
+	writeAPIJSON(w, http.StatusOK, map[string]interface{}{
+		"status":    "ok",
+		"version":   config.Version,
+		"uptime":    int64(time.Since(s.startedAt).Seconds()),
+		"territory": territoryLoaded,
+		"workers":   len(s.pool.List()),
+		"queued":    s.queue.Len(),
+	})
+}
+
+func (s *Server) handleAPIJobAdd(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, apiMaxBody))
+	if err != nil {
+		writeAPIError(w, http.StatusRequestEntityTooLarge, &protocol.Error{
+			Code:    protocol.InvalidRequest,
+			Message: "request body too large",
+		})
+		return
+	}
+
+	// Catch misspelled fields instead of silently dropping them
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	var params protocol.JobAddParams
+	if err := dec.Decode(&params); err != nil {
+		writeAPIError(w, http.StatusBadRequest, &protocol.Error{
+			Code:    protocol.InvalidParams,
+			Message: "invalid job: " + err.Error(),
+		})
+		return
+	}
+
+	resp := s.apiCall(protocol.MethodJobAdd, body)
+	if resp.Error != nil {
+		writeAPIError(w, apiStatus(resp.Error.Code), resp.Error)
+		return
+	}
+
+	var info protocol.JobInfo
+	if err := json.Unmarshal(resp.Result, &info); err != nil || info.ID == "" {
+		writeAPIError(w, http.StatusInternalServerError, &protocol.Error{
+			Code:    protocol.InternalError,
+			Message: "job added, but its ID could not be read",
+		})
+		return
+	}
+	w.Header().Set("Location", "/api/jobs/"+info.ID)
+	writeAPIRaw(w, http.StatusCreated, resp.Result)
+}
+
+func (s *Server) handleAPIJobStatus(w http.ResponseWriter, r *http.Request) {
+	params, _ := json.Marshal(protocol.JobStatusParams{ID: r.PathValue("id")})
+
+	resp := s.apiCall(protocol.MethodJobStatus, params)
+	if resp.Error != nil {
+		writeAPIError(w, apiStatus(resp.Error.Code), resp.Error)
+		return
+	}
+	writeAPIRaw(w, http.StatusOK, resp.Result)
+}
+
+// apiCall runs an RPC method through the same dispatch, and validation, as
+// requests on the socket.
+func (s *Server) apiCall(method string, params json.RawMessage) *protocol.Response {
+	return s.handleRequest(&protocol.Request{
+		JSONRPC: protocol.JSONRPCVersion,
+		ID:      protocol.NewStringID("api"),
+		Method:  method,
+		Params:  params,
+	}, nil)
+}
+
+// apiStatus maps an RPC error code to an HTTP status.
+func apiStatus(code int) int {
+	switch code {
+	case protocol.ParseError, protocol.InvalidRequest, protocol.InvalidParams:
+		return http.StatusBadRequest
+	case protocol.ErrJobNotFound, protocol.ErrWorkerNotFound, protocol.ErrTemplateNotFound:
+		return http.StatusNotFound
+	case protocol.ErrInvalidState:
+		return http.StatusConflict
+	case protocol.ErrJobRejected:
+		return http.StatusForbidden
+	case protocol.ErrQueueFull:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeAPIError(w http.ResponseWriter, status int, rpcErr *protocol.Error) {
+	var full protocol.QueueFullData
+	if rpcErr.Code == protocol.ErrQueueFull && json.Unmarshal(rpcErr.Data, &full) == nil {
+		w.Header().Set("Retry-After", strconv.Itoa(full.RetryAfter))
+	}
+	writeAPIJSON(w, status, map[string]*protocol.Error{"error": rpcErr})
+}
+
+func writeAPIJSON(w http.ResponseWriter, status int, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		status = http.StatusInternalServerError
+		data = []byte(`{"error":{"code":-32603,"message":"failed to encode response"}}`)
+	}
+	writeAPIRaw(w, status, data)
+}
+
+func writeAPIRaw(w http.ResponseWriter, status int, data []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(data, '\n'))
+}
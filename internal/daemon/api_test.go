@@ -0,0 +1,231 @@
+package daemon
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"cosa/internal/config"
+	"cosa/internal/protocol"
+)
+
+const testAPIToken = "ci-token"
+
+// newAPIServer returns the API of a daemon that has not been started, with
+// cfg adjusted by configure.
+func newAPIServer(t *testing.T, configure func(cfg *config.Config)) (*Server, *httptest.Server) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.DefaultConfig()
+	cfg.DataDir = t.TempDir()
+	cfg.API.Token = testAPIToken
+	if configure != nil {
+		configure(cfg)
+	}
+
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("failed to create daemon: %v", err)
+	}
+	t.Cleanup(func() { s.ledger.Close() })
+
+	ts := httptest.NewServer(s.apiHandler())
+	t.Cleanup(ts.Close)
+	return s, ts
+}
+
+// apiRequest makes a request to the API with token as the bearer token,
+// if set.
+func apiRequest(t *testing.T, ts *httptest.Server, method, path, token, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// apiError decodes an API error body.
+func apiError(t *testing.T, resp *http.Response) *protocol.Error {
+	t.Helper()
+	var body struct {
+		Error *protocol.Error `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Error == nil {
+		t.Fatalf("expected an error body, got %v", err)
+	}
+	return body.Error
+}
+
+func TestAPI_Auth(t *testing.T) {
+	_, ts := newAPIServer(t, func(cfg *config.Config) {
+		// Socket tokens grant nothing on the API
+		cfg.Auth.Tokens = []config.AuthToken{{Name: "ops", Token: "socket-token", Role: config.AuthRoleOperator}}
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		status int
+	}{
+		{"no token", "", http.StatusUnauthorized},
+		{"wrong token", "Bearer nope", http.StatusUnauthorized},
+		{"socket token", "Bearer socket-token", http.StatusUnauthorized},
+		{"not a bearer token", testAPIToken, http.StatusUnauthorized},
+		{"api token", "Bearer " + testAPIToken, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, ts.URL+"/api/health", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.status {
+				t.Errorf("expected %d, got %d", tt.status, resp.StatusCode)
+			}
+			if tt.status == http.StatusUnauthorized && resp.Header.Get("WWW-Authenticate") == "" {
+				t.Error("expected a WWW-Authenticate header")
+			}
+		})
+	}
+
+	// Every route is behind the token, including ones that would fail
+	for _, route := range [][2]string{{"POST", "/api/jobs"}, {"GET", "/api/jobs/x"}, {"POST", "/api/email"}} {
+		if resp := apiRequest(t, ts, route[0], route[1], "", ""); resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("%s %s: expected 401 without a token, got %d", route[0], route[1], resp.StatusCode)
+		}
+	}
+}
+
+func TestAPI_Routing(t *testing.T) {
+	_, ts := newAPIServer(t, nil)
+
+	tests := []struct {
+		method, path string
+		status       int
+	}{
+		{"GET", "/api/health", http.StatusOK},
+		{"GET", "/api/nope", http.StatusNotFound},
+		{"GET", "/api/jobs", http.StatusMethodNotAllowed},
+		{"DELETE", "/api/jobs/abc", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		if resp := apiRequest(t, ts, tt.method, tt.path, testAPIToken, ""); resp.StatusCode != tt.status {
+			t.Errorf("%s %s: expected %d, got %d", tt.method, tt.path, tt.status, resp.StatusCode)
+		}
+	}
+}
+
+func TestAPI_Jobs(t *testing.T) {
+	_, ts := newAPIServer(t, nil)
+
+	resp := apiRequest(t, ts, "POST", "/api/jobs", testAPIToken, `{"description": "Fix the flaky test"}`)
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected 201, got %d: %s", resp.StatusCode, body)
+	}
+	var info protocol.JobInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		t.Fatal(err)
+	}
+	if info.ID == "" || resp.Header.Get("Location") != "/api/jobs/"+info.ID {
+		t.Errorf("expected Location /api/jobs/%s, got %q", info.ID, resp.Header.Get("Location"))
+	}
+
+	resp = apiRequest(t, ts, "GET", "/api/jobs/"+info.ID, testAPIToken, "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var status protocol.JobInfo
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		t.Fatal(err)
+	}
+	if status.ID != info.ID || status.Description != "Fix the flaky test" {
+		t.Errorf("unexpected job %+v", status)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		code   int
+	}{
+		{"unknown job", "GET", "/api/jobs/missing", "", http.StatusNotFound, protocol.ErrJobNotFound},
+		{"malformed body", "POST", "/api/jobs", `{"description":`, http.StatusBadRequest, protocol.InvalidParams},
+		{"misspelled field", "POST", "/api/jobs", `{"descripton": "x"}`, http.StatusBadRequest, protocol.InvalidParams},
+		{"no description", "POST", "/api/jobs", `{}`, http.StatusBadRequest, protocol.InvalidParams},
+		{"body too large", "POST", "/api/jobs", `{"description": "` + strings.Repeat("x", apiMaxBody) + `"}`, http.StatusRequestEntityTooLarge, protocol.InvalidRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := apiRequest(t, ts, tt.method, tt.path, testAPIToken, tt.body)
+			if resp.StatusCode != tt.status {
+				t.Fatalf("expected %d, got %d", tt.status, resp.StatusCode)
+			}
+			if rpcErr := apiError(t, resp); rpcErr.Code != tt.code {
+				t.Errorf("expected code %d, got %d (%s)", tt.code, rpcErr.Code, rpcErr.Message)
+			}
+		})
+	}
+}
+
+func TestAPI_QueueFull(t *testing.T) {
+	_, ts := newAPIServer(t, func(cfg *config.Config) {
+		cfg.Admission.RateLimit = 1
+	})
+
+	if resp := apiRequest(t, ts, "POST", "/api/jobs", testAPIToken, `{"description": "first"}`); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected the first job to be accepted, got %d", resp.StatusCode)
+	}
+
+	resp := apiRequest(t, ts, "POST", "/api/jobs", testAPIToken, `{"description": "second"}`)
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Retry-After") == "" {
+		t.Error("expected a Retry-After header")
+	}
+	if rpcErr := apiError(t, resp); rpcErr.Code != protocol.ErrQueueFull {
+		t.Errorf("expected code %d, got %d", protocol.ErrQueueFull, rpcErr.Code)
+	}
+}
+
+func TestAPIStatus(t *testing.T) {
+	tests := map[int]int{
+		protocol.ParseError:          http.StatusBadRequest,
+		protocol.InvalidRequest:      http.StatusBadRequest,
+		protocol.InvalidParams:       http.StatusBadRequest,
+		protocol.ErrJobNotFound:      http.StatusNotFound,
+		protocol.ErrWorkerNotFound:   http.StatusNotFound,
+		protocol.ErrTemplateNotFound: http.StatusNotFound,
+		protocol.ErrInvalidState:     http.StatusConflict,
+		protocol.ErrJobRejected:      http.StatusForbidden,
+		protocol.ErrQueueFull:        http.StatusTooManyRequests,
+		protocol.InternalError:       http.StatusInternalServerError,
+	}
+	for code, want := range tests {
+		if got := apiStatus(code); got != want {
+			t.Errorf("apiStatus(%d) = %d, want %d", code, got, want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"cosa/internal/config"
+	"cosa/internal/protocol"
+)
+
+// initialRole is the role a new connection starts with: full access when
+// no auth.tokens are configured, and none until it logs in otherwise.
+func (s *Server) initialRole() string {
+	if len(s.config().Auth.Tokens) == 0 {
+		return config.AuthRoleOperator
+	}
+	return ""
+}
+
+// authorize rejects requests the connection's role does not allow.
+// Requests made inside the daemon, with no connection, are always allowed.
+func (s *Server) authorize(req *protocol.Request, conn net.Conn) *protocol.Response {
+	if conn == nil {
+		return nil
+	}
+
+	s.clientsMu.Lock()
+	var role string
+	if state, ok := s.clients[conn]; ok {
+		role = state.role
+	}
+	s.clientsMu.Unlock()
+
+	switch {
+	case req.Method == protocol.MethodAuthLogin:
+		return nil
+	case role == "":
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrUnauthorized,
+			"authentication required; set auth.token to one of the daemon's auth.tokens", nil)
+		return resp
+	case role != config.AuthRoleOperator && !protocol.IsReadOnly(req.Method):
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrUnauthorized,
+			fmt.Sprintf("%s is not allowed on a read-only connection", req.Method), nil)
+		return resp
+	}
+	return nil
+}
+
+func (s *Server) handleAuthLogin(req *protocol.Request, conn net.Conn) *protocol.Response {
+	var params protocol.AuthLoginParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	result := protocol.AuthLoginResult{Role: config.AuthRoleOperator}
+	if len(s.config().Auth.Tokens) > 0 {
+		tok, ok := s.config().Auth.Lookup(params.Token)
+		if !ok {
+			s.log.Warn("client login rejected", "reason", "invalid token")
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrUnauthorized, "invalid token", nil)
+			return resp
+		}
+		result = protocol.AuthLoginResult{Name: tok.Name, Role: tok.Role}
+	}
+	if params.Observe {
+		result.Role = config.AuthRoleObserver
+	}
+
+	if conn != nil {
+		s.clientsMu.Lock()
+		if state, ok := s.clients[conn]; ok {
+			state.role = result.Role
+			state.name = result.Name
+		}
+		s.clientsMu.Unlock()
+	}
+	s.log.Debug("client logged in", "name", result.Name, "role", result.Role)
+
+	resp, _ := protocol.NewResponse(req.ID, result)
+	return resp
+}
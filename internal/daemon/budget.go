@@ -0,0 +1,125 @@
+package daemon
+
+import (
+	"fmt"
+	"time"
+
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+	"cosa/internal/worker"
+)
+
+// recordSpend adds a job's cost to the spend tracker and emits a
+// budget.exceeded event the first time a limit is crossed.
+func (s *Server) recordSpend(jobID, workerName string, cost float64) {
+	s.spend.Record(jobID, workerName, cost)
+
+	limits := s.config().Budget
+	day := time.Now().Format("2006-01-02")
+
+	if limits.DailyUSD > 0 {
+		if spent := s.spend.Today(); spent >= limits.DailyUSD {
+			s.budgetExceeded("daily:"+day, ledger.BudgetEventData{
+				Scope: "daily",
+				Spent: spent,
+				Limit: limits.DailyUSD,
+			})
+		}
+	}
+
+	if limits.PerWorkerUSD > 0 && workerName != "" {
+		if spent := s.spend.WorkerToday(workerName); spent >= limits.PerWorkerUSD {
+			s.budgetExceeded("worker:"+workerName+":"+day, ledger.BudgetEventData{
+				Scope:      "worker",
+				WorkerName: workerName,
+				Spent:      spent,
+				Limit:      limits.PerWorkerUSD,
+			})
+		}
+	}
+
+	if limits.PerJobUSD > 0 && jobID != "" {
+		if spent := s.spend.Job(jobID); spent >= limits.PerJobUSD {
+			s.budgetExceeded("job:"+jobID, ledger.BudgetEventData{
+				Scope:      "job",
+				WorkerName: workerName,
+				JobID:      jobID,
+				Spent:      spent,
+				Limit:      limits.PerJobUSD,
+			})
+		}
+	}
+}
+
+// budgetExceeded logs a budget.exceeded event once per key.
+func (s *Server) budgetExceeded(key string, data ledger.BudgetEventData) {
+	s.budgetTracker.mu.Lock()
+	if s.budgetTracker.exceeded == nil {
+		s.budgetTracker.exceeded = make(map[string]bool)
+	}
+	seen := s.budgetTracker.exceeded[key]
+	s.budgetTracker.exceeded[key] = true
+	s.budgetTracker.mu.Unlock()
+
+	if !seen {
+		s.ledger.Append(ledger.EventBudgetExceeded, data)
+	}
+}
+
+// checkJobBudget returns an error if the daily or per-job budget prevents
+// starting j.
+func (s *Server) checkJobBudget(j *job.Job) error {
+	limits := s.config().Budget
+	if limits.DailyUSD > 0 {
+		if spent := s.spend.Today(); spent >= limits.DailyUSD {
+			return fmt.Errorf("daily budget exceeded ($%.2f of $%.2f)", spent, limits.DailyUSD)
+		}
+	}
+	if limits.PerJobUSD > 0 {
+		if spent := s.spend.Job(j.ID); spent >= limits.PerJobUSD {
+			return fmt.Errorf("job budget exceeded ($%.2f of $%.2f)", spent, limits.PerJobUSD)
+		}
+	}
+	return nil
+}
+
+// checkWorkerBudget returns an error if w has reached its daily budget.
+func (s *Server) checkWorkerBudget(w *worker.Worker) error {
+	limit := s.config().Budget.PerWorkerUSD
+	if limit <= 0 {
+		return nil
+	}
+	if spent := s.spend.WorkerToday(w.Name); spent >= limit {
+		return fmt.Errorf("worker %s budget exceeded ($%.2f of $%.2f)", w.Name, spent, limit)
+	}
+	return nil
+}
+
+// checkBudget returns an error if any budget, or the worker's capacity or
+// quarantine, prevents w from starting j.
+func (s *Server) checkBudget(j *job.Job, w *worker.Worker) error {
+	if err := s.checkJobBudget(j); err != nil {
+		return err
+	}
+	if err := s.checkWorkerQuarantine(w); err != nil {
+		return err
+	}
+	if err := s.checkWorkerBudget(w); err != nil {
+		return err
+	}
+	return s.checkWorkerCapacity(w)
+}
+
+// budgetStatus reports today's spend against the configured limits.
+func (s *Server) budgetStatus() *protocol.BudgetStatus {
+	limits := s.config().Budget
+	spent := s.spend.Today()
+	return &protocol.BudgetStatus{
+		SpentToday:     spent,
+		DailyLimit:     limits.DailyUSD,
+		PerWorkerLimit: limits.PerWorkerUSD,
+		PerJobLimit:    limits.PerJobUSD,
+		Exceeded:       limits.DailyUSD > 0 && spent >= limits.DailyUSD,
+	}
+}
@@ -0,0 +1,128 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+
+	"cosa/internal/protocol"
+)
+
+// cancellableMethods are the methods that can take minutes. They are handled
+// off the connection's read loop, so the client can make other calls while
+// one runs and can abandon it with request.cancel.
+var cancellableMethods = map[string]bool{
+	protocol.MethodChatStart:       true,
+	protocol.MethodChatSend:        true,
+	protocol.MethodHandoffGenerate: true,
+	protocol.MethodReviewStart:     true,
+}
+
+// inflightRequest is a cancellable request being handled.
+type inflightRequest struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// requestKey identifies a request among those in flight on a connection.
+func requestKey(id *protocol.RequestID) string {
+	data, _ := json.Marshal(id)
+	return string(data)
+}
+
+// handleCancellable runs a cancellable request in its own goroutine, with a
+// context that request.cancel, the connection closing or the daemon
+// stopping cancels.
+func (s *Server) handleCancellable(req *protocol.Request, conn net.Conn) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	key := requestKey(req.ID)
+
+	s.clientsMu.Lock()
+	if state, ok := s.clients[conn]; ok {
+		if state.inflight == nil {
+			state.inflight = make(map[string]*inflightRequest)
+		}
+		state.inflight[key] = &inflightRequest{ctx: ctx, cancel: cancel}
+	}
+	s.clientsMu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() {
+			s.clientsMu.Lock()
+			if state, ok := s.clients[conn]; ok {
+				delete(state.inflight, key)
+			}
+			s.clientsMu.Unlock()
+			cancel()
+		}()
+
+		resp := s.handleRequest(req, conn)
+		if errors.Is(ctx.Err(), context.Canceled) && s.ctx.Err() == nil && resp != nil && resp.Error != nil {
+			resp, _ = protocol.NewErrorResponse(req.ID, protocol.ErrRequestCancelled, "request cancelled", nil)
+		}
+		s.logRequest(req, resp)
+		if resp != nil {
+			s.sendResponse(conn, resp)
+		}
+	}()
+}
+
+// requestContext returns the context of a request handled by
+// handleCancellable, or the daemon's context for any other request.
+func (s *Server) requestContext(req *protocol.Request, conn net.Conn) context.Context {
+	if conn == nil || req.ID == nil {
+		return s.ctx
+	}
+
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+	if state, ok := s.clients[conn]; ok {
+		if r, ok := state.inflight[requestKey(req.ID)]; ok {
+			return r.ctx
+		}
+	}
+	return s.ctx
+}
+
+// cancelRequests cancels every request in flight on a connection, when the
+// client goes away.
+func (s *Server) cancelRequests(conn net.Conn) {
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+	if state, ok := s.clients[conn]; ok {
+		for _, r := range state.inflight {
+			r.cancel()
+		}
+	}
+}
+
+func (s *Server) handleCancel(req *protocol.Request, conn net.Conn) *protocol.Response {
+	var params protocol.CancelParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	cancelled := false
+	if conn != nil {
+		s.clientsMu.Lock()
+		if state, ok := s.clients[conn]; ok {
+			if r, ok := state.inflight[requestKey(params.ID)]; ok {
+				r.cancel()
+				cancelled = true
+			}
+		}
+		s.clientsMu.Unlock()
+	}
+
+	if cancelled {
+		s.log.Debug("request cancelled", "id", requestKey(params.ID))
+	}
+	if req.ID == nil {
+		return nil
+	}
+	resp, _ := protocol.NewResponse(req.ID, map[string]bool{"cancelled": cancelled})
+	return resp
+}
@@ -0,0 +1,283 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+	"cosa/internal/worker"
+)
+
+// throughputWindow is how far back finished jobs are counted to estimate
+// how many jobs a worker gets through per day.
+const throughputWindow = 7 * 24 * time.Hour
+
+// checkWorkerCapacity returns an error if w is offline or has started as
+// many jobs today as its daily limit allows.
+func (s *Server) checkWorkerCapacity(w *worker.Worker) error {
+	now := time.Now()
+	if d, off := s.capacity.OffAt(w.Name, now); off {
+		return fmt.Errorf("worker %s is offline until %s", w.Name, d.End.Format("Mon Jan 2 15:04"))
+	}
+	if limit := s.capacity.DailyLimit(w.Name); limit > 0 {
+		if started := s.jobsStartedToday(w, now); started >= limit {
+			return fmt.Errorf("worker %s has started %d of %d jobs today", w.Name, started, limit)
+		}
+	}
+	return nil
+}
+
+// jobsStartedToday counts the jobs w has started since local midnight.
+func (s *Server) jobsStartedToday(w *worker.Worker, now time.Time) int {
+	midnight := worker.StartOfDay(now)
+	count := 0
+	for _, j := range s.jobs.List() {
+		if j.Worker == w.ID && j.StartedAt != nil && !j.StartedAt.Before(midnight) {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *Server) handleCapacityOff(req *protocol.Request) *protocol.Response {
+	var params protocol.CapacityOffParams
+	json.Unmarshal(req.Params, &params)
+
+	w, exists := s.pool.Get(params.Worker)
+	if !exists {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrWorkerNotFound, "worker not found", nil)
+		return resp
+	}
+
+	start := time.Now()
+	if params.Start > 0 {
+		start = time.Unix(params.Start, 0)
+	}
+
+	d, err := s.capacity.AddDowntime(w.Name, start, time.Unix(params.End, 0), params.Reason)
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, err.Error(), nil)
+		return resp
+	}
+
+	s.ledger.Append(ledger.EventType("worker.downtime_scheduled"), map[string]interface{}{
+		"worker": w.Name,
+		"id":     d.ID,
+		"start":  d.Start.Unix(),
+		"end":    d.End.Unix(),
+		"reason": d.Reason,
+	})
+
+	resp, _ := protocol.NewResponse(req.ID, downtimeInfo(d))
+	return resp
+}
+
+func (s *Server) handleCapacityOn(req *protocol.Request) *protocol.Response {
+	var params protocol.CapacityOnParams
+	json.Unmarshal(req.Params, &params)
+
+	var cancelled []worker.Downtime
+	if params.ID != "" {
+		d, err := s.capacity.RemoveDowntime(params.ID)
+		if err != nil {
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, err.Error(), nil)
+			return resp
+		}
+		cancelled = append(cancelled, d)
+	} else {
+		w, exists := s.pool.Get(params.Worker)
+		if !exists {
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrWorkerNotFound, "worker not found", nil)
+			return resp
+		}
+		removed, err := s.capacity.ClearWorker(w.Name)
+		if err != nil {
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, err.Error(), nil)
+			return resp
+		}
+		cancelled = removed
+	}
+
+	infos := make([]protocol.DowntimeInfo, len(cancelled))
+	for i, d := range cancelled {
+		infos[i] = downtimeInfo(d)
+		s.ledger.Append(ledger.EventType("worker.downtime_cancelled"), map[string]interface{}{
+			"worker": d.Worker,
+			"id":     d.ID,
+		})
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, map[string]interface{}{"cancelled": infos})
+	return resp
+}
+
+func (s *Server) handleCapacityLimit(req *protocol.Request) *protocol.Response {
+	var params protocol.CapacityLimitParams
+	json.Unmarshal(req.Params, &params)
+
+	w, exists := s.pool.Get(params.Worker)
+	if !exists {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrWorkerNotFound, "worker not found", nil)
+		return resp
+	}
+
+	if err := s.capacity.SetDailyLimit(w.Name, params.MaxDaily); err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, err.Error(), nil)
+		return resp
+	}
+
+	s.ledger.Append(ledger.EventType("worker.capacity_limit"), map[string]interface{}{
+		"worker":    w.Name,
+		"max_daily": params.MaxDaily,
+	})
+
+	resp, _ := protocol.NewResponse(req.ID, map[string]interface{}{
+		"worker":    w.Name,
+		"max_daily": params.MaxDaily,
+	})
+	return resp
+}
+
+func (s *Server) handleCapacityStatus(req *protocol.Request) *protocol.Response {
+	var params protocol.CapacityStatusParams
+	json.Unmarshal(req.Params, &params)
+
+	days := params.Days
+	if days == 0 {
+		days = 7
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, s.capacityStatus(time.Now(), days))
+	return resp
+}
+
+// capacityStatus reports each worker's capacity for the coming days and
+// when the jobs waiting now should be done at that capacity.
+func (s *Server) capacityStatus(now time.Time, days int) protocol.CapacityStatusResult {
+	// Only workers the scheduler hands jobs to have capacity
+	var workers []*worker.Worker
+	for _, w := range s.pool.List() {
+		if w.Role == worker.RoleSoldato || w.Role == worker.RoleCapo {
+			workers = append(workers, w)
+		}
+	}
+	throughput := s.throughput(now, len(workers))
+
+	result := protocol.CapacityStatusResult{
+		Workers:    make([]protocol.WorkerCapacity, 0, len(workers)),
+		Downtime:   []protocol.DowntimeInfo{},
+		Throughput: throughput,
+	}
+
+	// The ETA looks further ahead than the table so long queues still get one
+	horizon := days
+	if horizon < 30 {
+		horizon = 30
+	}
+	perDay := make([]float64, horizon)
+
+	today := worker.StartOfDay(now)
+	for _, w := range workers {
+		limit := s.capacity.DailyLimit(w.Name)
+		started := s.jobsStartedToday(w, now)
+		wc := protocol.WorkerCapacity{
+			Name:         w.Name,
+			MaxDaily:     limit,
+			StartedToday: started,
+			Days:         make([]protocol.DayCapacity, 0, days),
+		}
+		if d, off := s.capacity.OffAt(w.Name, now); off {
+			wc.OffUntil = d.End.Unix()
+		}
+
+		// Without history a worker is assumed to use its whole daily limit
+		rate := throughput
+		if limit > 0 && (rate == 0 || rate > float64(limit)) {
+			rate = float64(limit)
+		}
+
+		for i := 0; i < horizon; i++ {
+			dayStart := today.AddDate(0, 0, i)
+			dayEnd := today.AddDate(0, 0, i+1)
+			if i == 0 {
+				dayStart = now
+			}
+
+			span := dayEnd.Sub(dayStart)
+			available := 0.0
+			if span > 0 {
+				available = 1 - float64(s.capacity.OffDuring(w.Name, dayStart, dayEnd))/float64(span)
+			}
+
+			// Today only has the rest of the day and what is left of the limit
+			jobs := rate * available * float64(span) / float64(24*time.Hour)
+			if i == 0 && limit > 0 && jobs > float64(limit-started) {
+				jobs = float64(limit - started)
+			}
+			if jobs < 0 {
+				jobs = 0
+			}
+			perDay[i] += jobs
+
+			if i < days {
+				wc.Days = append(wc.Days, protocol.DayCapacity{
+					Date:      today.AddDate(0, 0, i).Format("2006-01-02"),
+					Available: available,
+					Jobs:      jobs,
+				})
+			}
+		}
+
+		result.Workers = append(result.Workers, wc)
+	}
+
+	for _, d := range s.capacity.Downtime("") {
+		result.Downtime = append(result.Downtime, downtimeInfo(d))
+	}
+
+	for _, j := range s.jobs.List() {
+		if status := j.GetStatus(); status == job.StatusPending || status == job.StatusQueued {
+			result.Pending++
+		}
+	}
+
+	if day, fraction, ok := worker.EstimateDrain(result.Pending, perDay); ok && result.Pending > 0 {
+		dayStart := today.AddDate(0, 0, day)
+		if day == 0 {
+			dayStart = now
+		}
+		dayEnd := today.AddDate(0, 0, day+1)
+		result.ETA = dayStart.Add(time.Duration(fraction * float64(dayEnd.Sub(dayStart)))).Unix()
+	}
+
+	return result
+}
+
+// throughput returns the jobs finished per worker per day over the last
+// week.
+func (s *Server) throughput(now time.Time, workers int) float64 {
+	if workers == 0 {
+		return 0
+	}
+	since := now.Add(-throughputWindow)
+	finished := 0
+	for _, j := range s.jobs.List() {
+		if j.GetStatus() == job.StatusCompleted && j.CompletedAt != nil && j.CompletedAt.After(since) {
+			finished++
+		}
+	}
+	return float64(finished) / (throughputWindow.Hours() / 24) / float64(workers)
+}
+
+func downtimeInfo(d worker.Downtime) protocol.DowntimeInfo {
+	return protocol.DowntimeInfo{
+		ID:     d.ID,
+		Worker: d.Worker,
+		Start:  d.Start.Unix(),
+		End:    d.End.Unix(),
+		Reason: d.Reason,
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"cosa/internal/claude"
 	"cosa/internal/ledger"
 	"cosa/internal/protocol"
+	"cosa/internal/worker"
 )
 
 // ChatSession manages an interactive chat with the underboss (Claude).
@@ -24,15 +25,26 @@ type ChatSession struct {
 	workdir       string
 	mcpConfigPath string // Path to MCP config file
 	cosaBinary    string // Path to cosa binary for MCP server
+	drafts        bool   // Jobs the underboss creates wait for approval
 	chatTimeout   int    // Timeout in seconds for chat responses
 	messages      []protocol.ChatMessage
+	store         *claude.ChatStore // Persists the transcript; may be nil
+	systemPrompt  string            // Custom underboss prompt; empty uses underbossPrompt
+	createdAt     time.Time
 	mu            sync.Mutex
-	ctx           context.Context
-	cancel        context.CancelFunc
+
+	// Plan mode state. It has its own lock because the underboss's tools
+	// change the plan while Send holds mu.
+	mode   string            // protocol.ChatModeChat or ChatModePlan
+	plan   protocol.ChatPlan // Draft operation built in plan mode
+	notice string            // Prepended to the next message sent to Claude
+	planMu sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 // newChatSession creates a new chat session with the underboss.
-func newChatSession(cfg claude.ClientConfig, workdir string, cosaBinary string, chatTimeout int) *ChatSession {
+func newChatSession(cfg claude.ClientConfig, workdir string, cosaBinary string, chatTimeout int, store *claude.ChatStore) *ChatSession {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	// Default to 120 seconds if not specified
@@ -47,6 +59,8 @@ func newChatSession(cfg claude.ClientConfig, workdir string, cosaBinary string,
 		cosaBinary:  cosaBinary,
 		chatTimeout: chatTimeout,
 		messages:    make([]protocol.ChatMessage, 0),
+		store:       store,
+		createdAt:   time.Now(),
 		ctx:         ctx,
 		cancel:      cancel,
 	}
@@ -82,12 +96,18 @@ func (cs *ChatSession) createMCPConfig() (string, error) {
 		}
 	}
 
+	// The underboss may not approve jobs, its own drafts least of all
+	args := []string{"mcp-serve", "--allow-write", "--agent"}
+	if cs.drafts {
+		args = append(args, "--drafts")
+	}
+
 	// Create MCP config
 	config := MCPConfig{
 		McpServers: map[string]MCPServerConfig{
 			"cosa": {
 				Command: cosaBinary,
-				Args:    []string{"mcp-serve"},
+				Args:    args,
 			},
 		},
 	}
@@ -113,23 +133,49 @@ func (cs *ChatSession) cleanupMCPConfig() {
 	}
 }
 
-// Start initiates the chat session with the first system prompt.
-func (cs *ChatSession) Start() error {
-	cs.mu.Lock()
-	defer cs.mu.Unlock()
-
-	// Create MCP config for Claude to connect to Cosa tools
+// setupMCPLocked creates the MCP config so Claude can use Cosa tools.
+func (cs *ChatSession) setupMCPLocked() {
 	mcpConfigPath, err := cs.createMCPConfig()
 	if err != nil {
 		// Log warning but continue without MCP - chat will still work, just without tools
 		fmt.Printf("Warning: Could not create MCP config: %v\n", err)
-	} else {
-		cs.mcpConfigPath = mcpConfigPath
-		cs.cfg.MCPConfig = mcpConfigPath
+		return
 	}
+	cs.mcpConfigPath = mcpConfigPath
+	cs.cfg.MCPConfig = mcpConfigPath
+}
 
-	// Send initial prompt to establish the session with mafia character
-	prompt := `You are The Underboss of the Cosa development organization. You oversee all the soldati (workers) and manage the family's development operations.
+// underbossPromptLocked renders the custom underboss prompt, or returns ""
+// to use the built-in one. The caller holds s.mu.
+func (s *Server) underbossPromptLocked() string {
+	src := &worker.PromptSource{Templates: s.config().Prompts}
+	vars := map[string]string{
+		"name": "underboss",
+		"role": string(worker.RoleUnderboss),
+	}
+	if s.territory != nil {
+		src.Dir = s.territory.PromptsPath()
+		vars["merge_target"] = s.territory.MergeTargetBranch(s.config().Git.DefaultMergeBranch)
+	}
+
+	tmpl, err := src.Template(worker.RoleUnderboss)
+	if err == nil && tmpl != "" {
+		var prompt string
+		if prompt, err = worker.RenderPrompt(tmpl, vars); err == nil {
+			return prompt
+		}
+	}
+	if err != nil {
+		s.log.Warn("using the built-in underboss prompt", "error", err)
+		s.ledger.Append(ledger.EventType("chat.prompt_error"), map[string]string{
+			"error": fmt.Sprintf("using the built-in underboss prompt: %v", err),
+		})
+	}
+	return ""
+}
+
+// underbossPrompt is the built-in system prompt of the underboss.
+const underbossPrompt = `You are The Underboss of the Cosa development organization. You oversee all the soldati (workers) and manage the family's development operations.
 
 Your character:
 - You speak with a classic mafia underboss persona, using expressions like "capisce?", "fuggedaboutit", "the family", "our thing", "make 'em an offer they can't refuse"
@@ -143,14 +189,19 @@ Your responsibilities:
 - You manage the job queue and priorities
 - You can check on worker status, job progress, costs, and operations
 - You can create new jobs, cancel jobs, and adjust priorities
+- You can bring on new soldati, let them go, and pass messages to them
 - You keep the boss (user) informed about what's happening
 
 You have MCP tools available to interact with the Cosa system:
 - cosa_list_workers: See all our soldati
 - cosa_get_worker: Get details on a specific soldato
+- cosa_add_worker: Bring a new soldato into the family
+- cosa_remove_worker: Let a soldato go (always confirm with the boss first)
+- cosa_message_worker: Pass a message to a soldato
+- cosa_generate_handoff: Summarize a soldato's work for handing it off
 - cosa_list_jobs: Check on all the contracts
 - cosa_get_job: Get details on a specific contract
-- cosa_create_job: Put out a new contract
+- cosa_create_job: Put out a new contract (it may be held as a draft until the boss approves it)
 - cosa_cancel_job: Call off a contract
 - cosa_set_job_priority: Change contract priority
 - cosa_queue_status: Check the queue
@@ -158,12 +209,31 @@ You have MCP tools available to interact with the Cosa system:
 - cosa_list_territories: Check our territories
 - cosa_list_operations: Check ongoing operations
 - cosa_get_costs: See what we're spending
+- cosa_plan_job: Add a contract to the draft plan without starting it
+- cosa_show_plan: Show the draft plan
+- cosa_plan_remove_job: Drop a contract from the draft plan
 
 Use these tools proactively when the user asks about workers, jobs, status, or operations.
 
 Say hello to the boss and let them know you're ready to discuss family business.`
 
-	response, sessionID, err := cs.sendMessage(prompt, "")
+// Start initiates the chat session with the first system prompt. Cancelling
+// ctx abandons the greeting.
+func (cs *ChatSession) Start(ctx context.Context) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	// Create MCP config for Claude to connect to Cosa tools
+	cs.setupMCPLocked()
+
+	// Send initial prompt to establish the session with mafia character,
+	// unless the underboss prompt has been customized
+	prompt := cs.systemPrompt
+	if prompt == "" {
+		prompt = underbossPrompt
+	}
+
+	response, sessionID, err := cs.sendMessage(ctx, prompt, "")
 	if err != nil {
 		return err
 	}
@@ -174,8 +244,35 @@ Say hello to the boss and let them know you're ready to discuss family business.
 	return nil
 }
 
-// Send sends a message and returns the response.
-func (cs *ChatSession) Send(message string) (string, error) {
+// Resume continues a persisted chat. The Claude session is resumed on the
+// next message, so no greeting is requested.
+func (cs *ChatSession) Resume(t *claude.ChatTranscript) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if t.SessionID == "" {
+		return fmt.Errorf("chat %s has no claude session to resume", t.ID)
+	}
+
+	cs.ID = t.ID
+	cs.sessionID = t.SessionID
+	cs.createdAt = t.CreatedAt
+	cs.messages = make([]protocol.ChatMessage, 0, len(t.Messages))
+	for _, m := range t.Messages {
+		cs.messages = append(cs.messages, protocol.ChatMessage{
+			Role:      m.Role,
+			Content:   m.Content,
+			Timestamp: m.Timestamp.Unix(),
+		})
+	}
+
+	cs.setupMCPLocked()
+	return nil
+}
+
+// Send sends a message and returns the response. Cancelling ctx stops
+// Claude and abandons the response.
+func (cs *ChatSession) Send(ctx context.Context, message string) (string, error) {
 	cs.mu.Lock()
 	defer cs.mu.Unlock()
 
@@ -186,8 +283,17 @@ func (cs *ChatSession) Send(message string) (string, error) {
 	// Record user message
 	cs.recordMessageLocked("user", message)
 
+	// Tell Claude about mode changes since the last message
+	prompt := message
+	cs.planMu.Lock()
+	if cs.notice != "" {
+		prompt = cs.notice + "\n\n" + message
+		cs.notice = ""
+	}
+	cs.planMu.Unlock()
+
 	// Send to Claude with resume
-	response, newSessionID, err := cs.sendMessage(message, cs.sessionID)
+	response, newSessionID, err := cs.sendMessage(ctx, prompt, cs.sessionID)
 	if err != nil {
 		return "", err
 	}
@@ -202,7 +308,13 @@ func (cs *ChatSession) Send(message string) (string, error) {
 }
 
 // sendMessage sends a message and waits for the complete response.
-func (cs *ChatSession) sendMessage(prompt, resumeSessionID string) (string, string, error) {
+func (cs *ChatSession) sendMessage(ctx context.Context, prompt, resumeSessionID string) (string, string, error) {
+	// Ending the session stops the message too
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(cs.ctx, cancel)
+	defer stop()
+
 	clientCfg := cs.cfg
 	clientCfg.Workdir = cs.workdir
 	clientCfg.MaxTurns = 50
@@ -211,9 +323,9 @@ func (cs *ChatSession) sendMessage(prompt, resumeSessionID string) (string, stri
 
 	var err error
 	if resumeSessionID != "" {
-		err = client.Resume(cs.ctx, resumeSessionID, prompt)
+		err = client.Resume(ctx, resumeSessionID, prompt)
 	} else {
-		err = client.Start(cs.ctx, prompt)
+		err = client.Start(ctx, prompt)
 	}
 	if err != nil {
 		return "", "", fmt.Errorf("failed to start claude: %w", err)
@@ -227,9 +339,12 @@ func (cs *ChatSession) sendMessage(prompt, resumeSessionID string) (string, stri
 
 	for {
 		select {
-		case <-cs.ctx.Done():
+		case <-ctx.Done():
 			client.Stop()
-			return "", "", fmt.Errorf("chat session cancelled")
+			if cs.ctx.Err() != nil {
+				return "", "", fmt.Errorf("chat session cancelled")
+			}
+			return "", "", fmt.Errorf("message cancelled: %w", ctx.Err())
 
 		case <-timeout:
 			client.Stop()
@@ -285,6 +400,30 @@ func (cs *ChatSession) History() []protocol.ChatMessage {
 	return result
 }
 
+// Transcript returns the chat as a persistable transcript.
+func (cs *ChatSession) Transcript() *claude.ChatTranscript {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return cs.transcriptLocked()
+}
+
+func (cs *ChatSession) transcriptLocked() *claude.ChatTranscript {
+	t := &claude.ChatTranscript{
+		ID:        cs.ID,
+		SessionID: cs.sessionID,
+		Messages:  make([]claude.ChatMessage, 0, len(cs.messages)),
+		CreatedAt: cs.createdAt,
+	}
+	for _, m := range cs.messages {
+		t.Messages = append(t.Messages, claude.ChatMessage{
+			Role:      m.Role,
+			Content:   m.Content,
+			Timestamp: time.Unix(m.Timestamp, 0),
+		})
+	}
+	return t
+}
+
 // GetGreeting returns the initial greeting if available.
 func (cs *ChatSession) GetGreeting() string {
 	cs.mu.Lock()
@@ -302,11 +441,18 @@ func (cs *ChatSession) recordMessageLocked(role, content string) {
 		Content:   content,
 		Timestamp: time.Now().Unix(),
 	})
+
+	// Persist after every message so the chat survives daemon restarts
+	if cs.store != nil {
+		if err := cs.store.Save(cs.transcriptLocked()); err != nil {
+			fmt.Printf("Warning: Could not save chat transcript: %v\n", err)
+		}
+	}
 }
 
 // Chat handlers for the server
 
-func (s *Server) handleChatStart(req *protocol.Request) *protocol.Response {
+func (s *Server) handleChatStart(ctx context.Context, req *protocol.Request) *protocol.Response {
 	var params protocol.ChatStartParams
 	if req.Params != nil {
 		json.Unmarshal(req.Params, &params)
@@ -315,6 +461,32 @@ func (s *Server) handleChatStart(req *protocol.Request) *protocol.Response {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	resuming := params.Resume || params.SessionID != ""
+
+	// Keep the active session when asked to resume it
+	if resuming && s.chatSession != nil && (params.SessionID == "" || params.SessionID == s.chatSession.ID) {
+		resp, _ := protocol.NewResponse(req.ID, protocol.ChatStartResult{
+			SessionID: s.chatSession.ID,
+			Status:    "active",
+			Greeting:  s.chatSession.GetGreeting(),
+			Resumed:   true,
+		})
+		return resp
+	}
+
+	// Find the transcript to resume
+	var transcript *claude.ChatTranscript
+	if params.SessionID != "" {
+		t, err := s.chats.Load(params.SessionID)
+		if err != nil {
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, err.Error(), nil)
+			return resp
+		}
+		transcript = t
+	} else if params.Resume {
+		transcript = s.chats.Latest()
+	}
+
 	// End existing session if any
 	if s.chatSession != nil {
 		s.chatSession.Stop()
@@ -331,12 +503,35 @@ func (s *Server) handleChatStart(req *protocol.Request) *protocol.Response {
 
 	// Create new session
 	s.chatSession = newChatSession(claude.ClientConfig{
-		Binary:   s.cfg.Claude.Binary,
-		Model:    s.cfg.Claude.Model,
+		Binary:   s.config().Claude.Binary,
+		Model:    s.jobConfig().Claude.Model,
 		MaxTurns: 1000,
-	}, workdir, cosaBinary, s.cfg.Claude.ChatTimeout)
+	}, workdir, cosaBinary, s.config().Claude.ChatTimeout, s.chats)
+	s.chatSession.systemPrompt = s.underbossPromptLocked()
+	s.chatSession.drafts = s.config().Claude.ChatDrafts
+
+	if transcript != nil && transcript.SessionID != "" {
+		if err := s.chatSession.Resume(transcript); err != nil {
+			s.chatSession = nil
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, err.Error(), nil)
+			return resp
+		}
+
+		s.ledger.Append(ledger.EventType("chat.resumed"), map[string]string{
+			"session_id": s.chatSession.ID,
+		})
+
+		resp, _ := protocol.NewResponse(req.ID, protocol.ChatStartResult{
+			SessionID: s.chatSession.ID,
+			Status:    "resumed",
+			Greeting:  s.chatSession.GetGreeting(),
+			Resumed:   true,
+		})
+		return resp
+	}
 
-	if err := s.chatSession.Start(); err != nil {
+	if err := s.chatSession.Start(ctx); err != nil {
+		s.chatSession = nil
 		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, err.Error(), nil)
 		return resp
 	}
@@ -353,17 +548,12 @@ func (s *Server) handleChatStart(req *protocol.Request) *protocol.Response {
 	return resp
 }
 
-func (s *Server) handleChatSend(req *protocol.Request) *protocol.Response {
+func (s *Server) handleChatSend(ctx context.Context, req *protocol.Request) *protocol.Response {
 	var params protocol.ChatSendParams
 	if req.Params != nil {
 		json.Unmarshal(req.Params, &params)
 	}
 
-	if params.Message == "" {
-		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, "message is required", nil)
-		return resp
-	}
-
 	s.mu.RLock()
 	session := s.chatSession
 	s.mu.RUnlock()
@@ -373,7 +563,7 @@ func (s *Server) handleChatSend(req *protocol.Request) *protocol.Response {
 		return resp
 	}
 
-	response, err := session.Send(params.Message)
+	response, err := session.Send(ctx, params.Message)
 	if err != nil {
 		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, err.Error(), nil)
 		return resp
@@ -385,9 +575,15 @@ func (s *Server) handleChatSend(req *protocol.Request) *protocol.Response {
 		"assistant":  response,
 	})
 
-	resp, _ := protocol.NewResponse(req.ID, protocol.ChatSendResult{
+	result := protocol.ChatSendResult{
 		Response: response,
-	})
+		Mode:     session.Mode(),
+	}
+	if result.Mode == protocol.ChatModePlan {
+		result.Plan = session.Plan()
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, result)
 	return resp
 }
 
@@ -413,19 +609,49 @@ func (s *Server) handleChatEnd(req *protocol.Request) *protocol.Response {
 }
 
 func (s *Server) handleChatHistory(req *protocol.Request) *protocol.Response {
+	var params protocol.ChatHistoryParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
 	s.mu.RLock()
 	session := s.chatSession
 	s.mu.RUnlock()
 
-	if session == nil {
+	// The active session has the most current messages
+	if session != nil && (params.SessionID == "" || params.SessionID == session.ID) {
 		resp, _ := protocol.NewResponse(req.ID, protocol.ChatHistoryResult{
-			Messages: []protocol.ChatMessage{},
+			SessionID: session.ID,
+			Messages:  session.History(),
 		})
 		return resp
 	}
 
-	resp, _ := protocol.NewResponse(req.ID, protocol.ChatHistoryResult{
-		Messages: session.History(),
-	})
+	// Otherwise fall back to the persisted transcript
+	var transcript *claude.ChatTranscript
+	if params.SessionID != "" {
+		t, err := s.chats.Load(params.SessionID)
+		if err != nil {
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, err.Error(), nil)
+			return resp
+		}
+		transcript = t
+	} else {
+		transcript = s.chats.Latest()
+	}
+
+	result := protocol.ChatHistoryResult{Messages: []protocol.ChatMessage{}}
+	if transcript != nil {
+		result.SessionID = transcript.ID
+		for _, m := range transcript.Messages {
+			result.Messages = append(result.Messages, protocol.ChatMessage{
+				Role:      m.Role,
+				Content:   m.Content,
+				Timestamp: m.Timestamp.Unix(),
+			})
+		}
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, result)
 	return resp
 }
@@ -0,0 +1,59 @@
+package daemon
+
+import (
+	"encoding/json"
+
+	"cosa/internal/git"
+	"cosa/internal/protocol"
+	"cosa/internal/worker"
+)
+
+// gitManager returns the territory's git manager, or nil without a
+// territory.
+func (s *Server) gitManager() *git.Manager {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.territory == nil {
+		return nil
+	}
+	return s.territory.GitManager()
+}
+
+func (s *Server) handleCleanupRun(req *protocol.Request) *protocol.Response {
+	var params protocol.CleanupRunParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	result := protocol.CleanupRunResult{
+		DryRun: params.DryRun,
+		Items:  []protocol.CleanupItem{},
+	}
+
+	var actions []worker.CleanupAction
+	if params.DryRun {
+		actions, result.Errors = s.cleaner.Plan()
+	} else {
+		stats := s.cleaner.RunNow()
+		actions, result.Errors = stats.Removed, stats.Errors
+	}
+
+	for _, a := range actions {
+		item := protocol.CleanupItem{
+			Kind:   a.Kind,
+			Target: a.Target,
+			JobID:  a.JobID,
+			Worker: a.Worker,
+			Size:   a.Size,
+			Reason: a.Reason,
+		}
+		if !a.LastUsed.IsZero() {
+			item.LastUsed = a.LastUsed.Unix()
+		}
+		result.Items = append(result.Items, item)
+		result.BytesFreed += a.Size
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, result)
+	return resp
+}
@@ -3,12 +3,14 @@ package daemon
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"context"
 	"cosa/internal/protocol"
 )
 
@@ -31,8 +33,25 @@ type Client struct {
 	// Event channel for streaming
 	events   chan *LedgerEvent
 	eventsMu sync.Mutex
+
+	// Closed when the connection to the daemon is lost
+	done chan struct{}
+
+	// Credentials of the last successful login, used again by Reconnect
+	login *protocol.AuthLoginParams
+
+	// How long Call waits for a response; zero waits indefinitely
+	timeout time.Duration
 }
 
+// maxResponseSize is the longest line the client reads from the daemon.
+// Responses such as a page of ledger events run well past bufio's default.
+const maxResponseSize = 16 << 20
+
+// ErrConnectionClosed is returned by calls on a client whose connection to
+// the daemon was lost.
+var ErrConnectionClosed = errors.New("connection to daemon closed")
+
 // LedgerEvent represents an event from the ledger for streaming.
 type LedgerEvent struct {
 	ID        string          `json:"id"`
@@ -43,7 +62,7 @@ type LedgerEvent struct {
 
 // Connect establishes a connection to the daemon.
 func Connect(socketPath string) (*Client, error) {
-	conn, err := net.Dial("unix", socketPath)
+	conn, err := Dial(socketPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to daemon: %w", err)
 	}
@@ -53,6 +72,7 @@ func Connect(socketPath string) (*Client, error) {
 		conn:       conn,
 		pending:    make(map[int64]chan *protocol.Response),
 		events:     make(chan *LedgerEvent, 100),
+		done:       make(chan struct{}),
 	}
 
 	go c.readLoop()
@@ -73,8 +93,27 @@ func (c *Client) OnNotification(handler func(*protocol.Request)) {
 	c.onNotification = handler
 }
 
-// Call sends a request and waits for a response.
+// SetTimeout sets how long Call waits for a response before cancelling the
+// request. Zero, the default, waits indefinitely.
+func (c *Client) SetTimeout(d time.Duration) {
+	c.timeout = d
+}
+
+// Call sends a request and waits for a response, for up to the client's
+// timeout.
 func (c *Client) Call(method string, params interface{}) (*protocol.Response, error) {
+	ctx := context.Background()
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	return c.CallContext(ctx, method, params)
+}
+
+// CallContext sends a request and waits for a response until ctx is done,
+// when it tells the daemon to abandon the request.
+func (c *Client) CallContext(ctx context.Context, method string, params interface{}) (*protocol.Response, error) {
 	id := atomic.AddInt64(&c.nextID, 1)
 	reqID := protocol.NewIntID(id)
 
@@ -101,8 +140,24 @@ func (c *Client) Call(method string, params interface{}) (*protocol.Response, er
 	}
 
 	// Wait for response
-	resp := <-respCh
-	return resp, nil
+	select {
+	case resp := <-respCh:
+		return resp, nil
+	case <-ctx.Done():
+		c.Notify(protocol.MethodCancel, protocol.CancelParams{ID: reqID})
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%s timed out: %w", method, ctx.Err())
+		}
+		return nil, fmt.Errorf("%s cancelled: %w", method, ctx.Err())
+	case <-c.done:
+		// The response may have arrived just before the connection closed
+		select {
+		case resp := <-respCh:
+			return resp, nil
+		default:
+			return nil, ErrConnectionClosed
+		}
+	}
 }
 
 // Notify sends a notification (no response expected).
@@ -116,7 +171,13 @@ func (c *Client) Notify(method string, params interface{}) error {
 
 // Subscribe subscribes to real-time events.
 func (c *Client) Subscribe(events []string) error {
-	resp, err := c.Call(protocol.MethodSubscribe, protocol.SubscribeParams{Events: events})
+	return c.SubscribeFiltered(events, nil)
+}
+
+// SubscribeFiltered subscribes to events, of which the daemon only sends
+// those that match filter.
+func (c *Client) SubscribeFiltered(events []string, filter *protocol.LogFilter) error {
+	resp, err := c.Call(protocol.MethodSubscribe, protocol.SubscribeParams{Events: events, Filter: filter})
 	if err != nil {
 		return err
 	}
@@ -130,7 +191,7 @@ func (c *Client) Subscribe(events []string) error {
 func (c *Client) ReadEvent() (*LedgerEvent, error) {
 	event, ok := <-c.events
 	if !ok {
-		return nil, fmt.Errorf("connection closed")
+		return nil, ErrConnectionClosed
 	}
 	return event, nil
 }
@@ -152,6 +213,71 @@ func (c *Client) Status() (*protocol.StatusResult, error) {
 	return &result, nil
 }
 
+// SyncState fetches a snapshot of the daemon's state. Passing the epoch and
+// version of the last snapshot applied returns an unchanged result when
+// nothing has happened since.
+func (c *Client) SyncState(epoch int64, version uint64, identity string) (*protocol.SyncStateResult, error) {
+	resp, err := c.Call(protocol.MethodSyncState, protocol.SyncStateParams{
+		Epoch:    epoch,
+		Version:  version,
+		Identity: identity,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("sync failed: %s", resp.Error.Message)
+	}
+
+	var result protocol.SyncStateResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SocketPath returns the socket the client is connected to.
+func (c *Client) SocketPath() string {
+	return c.socketPath
+}
+
+// Login authenticates the connection with one of the daemon's auth.tokens.
+// With observe set the connection is read-only whatever the token allows.
+func (c *Client) Login(token string, observe bool) (*protocol.AuthLoginResult, error) {
+	params := protocol.AuthLoginParams{Token: token, Observe: observe}
+	resp, err := c.Call(protocol.MethodAuthLogin, params)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("login failed: %s", resp.Error.Message)
+	}
+
+	var result protocol.AuthLoginResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		return nil, err
+	}
+	c.login = &params
+	return &result, nil
+}
+
+// Reconnect opens a new connection to the same daemon, logged in as this
+// one was.
+func (c *Client) Reconnect() (*Client, error) {
+	client, err := Connect(c.socketPath)
+	if err != nil {
+		return nil, err
+	}
+	client.timeout = c.timeout
+	if c.login != nil {
+		if _, err := client.Login(c.login.Token, c.login.Observe); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
 // Shutdown requests the daemon to shut down.
 func (c *Client) Shutdown() error {
 	resp, err := c.Call(protocol.MethodShutdown, nil)
@@ -164,6 +290,28 @@ func (c *Client) Shutdown() error {
 	return nil
 }
 
+// Drain asks the daemon to shut down once running work has finished,
+// stopping anyway after timeout unless it is 0. Progress is reported by
+// daemon.drain_progress and daemon.drained events.
+func (c *Client) Drain(timeout time.Duration) (*protocol.ShutdownResult, error) {
+	resp, err := c.Call(protocol.MethodShutdown, protocol.ShutdownParams{
+		Drain:   true,
+		Timeout: int(timeout.Seconds()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("drain failed: %s", resp.Error.Message)
+	}
+
+	var result protocol.ShutdownResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 func (c *Client) send(req *protocol.Request) error {
 	data, err := json.Marshal(req)
 	if err != nil {
@@ -178,7 +326,13 @@ func (c *Client) send(req *protocol.Request) error {
 }
 
 func (c *Client) readLoop() {
+	defer func() {
+		close(c.done)
+		close(c.events)
+	}()
+
 	scanner := bufio.NewScanner(c.conn)
+	scanner.Buffer(make([]byte, 64*1024), maxResponseSize)
 	for scanner.Scan() {
 		line := scanner.Bytes()
 
@@ -229,6 +383,7 @@ func IsRunning(socketPath string) bool {
 	}
 	defer client.Close()
 
-	_, err = client.Status()
+	// Any response will do: a daemon that wants a login still answers
+	_, err = client.Call(protocol.MethodStatus, nil)
 	return err == nil
 }
@@ -0,0 +1,82 @@
+package daemon
+
+import (
+	"encoding/json"
+
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+)
+
+// handleWorkerClone adds a worker set up like another: the same role,
+// standing orders, labels and daily job limit, so a well-tuned worker can be
+// scaled into a crew. The model comes with the role. The clone gets its own
+// worktree and starts without a session, so it shares none of the source's
+// context.
+func (s *Server) handleWorkerClone(req *protocol.Request) *protocol.Response {
+	var params protocol.WorkerCloneParams
+	json.Unmarshal(req.Params, &params)
+
+	s.mu.RLock()
+	t := s.territory
+	s.mu.RUnlock()
+
+	if t == nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, "territory not initialized", nil)
+		return resp
+	}
+
+	source, exists := s.pool.Get(params.Source)
+	if !exists {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrWorkerNotFound, "worker not found", nil)
+		return resp
+	}
+	if s.pool.Exists(params.Name) {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, "worker already exists", nil)
+		return resp
+	}
+
+	wt, err := t.CreateWorkerWorktree(params.Name)
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, err.Error(), nil)
+		return resp
+	}
+
+	w := s.newWorker(t, params.Name, source.Role, wt)
+	w.SetLabels(source.GetLabels())
+	if orders := source.GetStandingOrders(); len(orders) > 0 {
+		w.SetStandingOrders(orders)
+	}
+
+	if err := s.pool.Add(w); err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, err.Error(), nil)
+		return resp
+	}
+	if limit := s.capacity.DailyLimit(source.Name); limit > 0 {
+		if err := s.capacity.SetDailyLimit(w.Name, limit); err != nil {
+			s.log.Warn("failed to copy daily job limit", "worker", w.Name, "error", err)
+		}
+	}
+
+	w.Start()
+
+	s.ledger.Append(ledger.EventWorkerAdded, ledger.WorkerEventData{
+		ID:       w.ID,
+		Name:     w.Name,
+		Role:     string(w.Role),
+		Worktree: w.Worktree,
+	})
+	s.ledger.Append(ledger.EventType("worker.cloned"), map[string]interface{}{
+		"worker": w.Name,
+		"source": source.Name,
+	})
+
+	resp, _ := protocol.NewResponse(req.ID, protocol.WorkerInfo{
+		ID:       w.ID,
+		Name:     w.Name,
+		Role:     string(w.Role),
+		Status:   string(w.GetStatus()),
+		Worktree: w.Worktree,
+		Labels:   w.GetLabels(),
+	})
+	return resp
+}
@@ -0,0 +1,676 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"cosa/internal/display"
+	"cosa/internal/git"
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+	"cosa/internal/worker"
+)
+
+// errMergeConflict is returned when a job branch conflicts with its target.
+var errMergeConflict = errors.New("merge conflict")
+
+// mergeJobBranch merges a job's branch into the merge target branch and
+// returns the merge commit. With git.remote_mode push-branch or open-pr the
+// branch is published instead and no commit is returned. On conflict the merge is aborted, the branch is
+// kept and the job is marked unmerged; if resolve is set a follow-up job is
+// created to resolve the conflict.
+func (s *Server) mergeJobBranch(j *job.Job, resolve bool) (string, error) {
+	s.mu.RLock()
+	t := s.territory
+	s.mu.RUnlock()
+
+	if t == nil {
+		return "", fmt.Errorf("territory not initialized")
+	}
+
+	jobBranch := j.GetBranch()
+	if jobBranch == "" {
+		return "", fmt.Errorf("job has no branch")
+	}
+
+	gitMgr := t.GitManager()
+	targetBranch := t.MergeTargetBranch(s.config().Git.DefaultMergeBranch)
+
+	if s.publishesBranches() {
+		return "", s.publishJobBranch(gitMgr, j, jobBranch, targetBranch)
+	}
+
+	// Take turns with other merges into the target. If one went first the
+	// target has moved on, so the branch is replayed onto it before merging
+	turn, waited := s.merges.enter(targetBranch, j.ID, jobBranch)
+	defer s.merges.leave(targetBranch, turn)
+	if waited {
+		s.rebaseBeforeMerge(gitMgr, j, jobBranch, targetBranch)
+	}
+
+	result, err := gitMgr.Merge(jobBranch, targetBranch)
+	if err != nil {
+		s.log.Error("merge failed", "job", j.ID, "branch", jobBranch, "target", targetBranch, "error", err)
+		s.ledger.Append(ledger.EventType("job.merge_error"), ledger.JobEventData{
+			ID:    j.ID,
+			Error: fmt.Sprintf("failed to merge: %v", err),
+		})
+		return "", err
+	}
+
+	if !result.Success {
+		// Leave the repository clean; the branch is kept for resolution
+		gitMgr.AbortMerge()
+
+		j.MarkUnmerged()
+		s.jobs.Save(j)
+
+		s.ledger.Append(ledger.EventType("job.merge_conflict"), ledger.JobEventData{
+			ID:          j.ID,
+			Description: fmt.Sprintf("Branch %s conflicts with %s", jobBranch, targetBranch),
+			Error:       result.Message,
+		})
+
+		if resolve && s.activeResolver(j.ID) == nil {
+			s.createResolverJob(j, jobBranch, targetBranch, nil)
+		} else if !resolve && j.GetStatus() != job.StatusNeedsAttention {
+			s.flagForAttention(j, job.AttentionMergeConflict,
+				fmt.Sprintf("branch %s conflicts with %s", jobBranch, targetBranch))
+		}
+		return "", fmt.Errorf("%w: %s", errMergeConflict, result.Message)
+	}
+
+	s.ledger.Append(ledger.EventType("job.merged"), ledger.JobEventData{
+		ID:          j.ID,
+		Description: fmt.Sprintf("Merged into %s (commit: %s)", targetBranch, result.MergeCommit),
+	})
+
+	// Delete the job branch after successful merge
+	removeConflictWorkspace(gitMgr, j.ID)
+	if err := gitMgr.DeleteBranch(jobBranch, true); err != nil {
+		s.ledger.Append(ledger.EventType("job.branch_cleanup_error"), ledger.JobEventData{
+			ID:    j.ID,
+			Error: fmt.Sprintf("failed to delete branch: %v", err),
+		})
+		// Non-fatal, continue
+	}
+
+	// Clear worktree info from job
+	j.SetMergeCommit(result.MergeCommit)
+	j.ClearWorktree()
+	j.ClearUnmerged()
+	s.jobs.Save(j)
+	s.clearMergeAttention(j)
+
+	s.resolveConflicts(j, targetBranch)
+
+	return result.MergeCommit, nil
+}
+
+// rebaseBeforeMerge replays a job branch onto a target that moved while it
+// waited to merge. If the rebase conflicts the branch is merged as it is, so
+// the conflict is handled like any other.
+func (s *Server) rebaseBeforeMerge(gitMgr *git.Manager, j *job.Job, branch, target string) {
+	behind, _, err := gitMgr.BranchDivergence(branch, target)
+	if err != nil || behind == 0 {
+		return
+	}
+	if err := gitMgr.RebaseBranch(branch, target); err != nil {
+		s.log.Info("merging without rebase", "job", j.ID, "branch", branch, "target", target, "error", err)
+		return
+	}
+	s.ledger.Append(ledger.EventType("job.rebased"), ledger.JobEventData{
+		ID:          j.ID,
+		Description: fmt.Sprintf("Rebased %s onto %s before merging", branch, target),
+	})
+}
+
+// createResolverJob creates a follow-up job that merges the conflicting
+// branch into a fresh worktree and resolves the conflicts. The job is started
+// on w if given, otherwise on an idle consigliere or through the queue.
+func (s *Server) createResolverJob(j *job.Job, branch, target string, w *worker.Worker) *job.Job {
+	description := fmt.Sprintf(`Resolve merge conflicts for job %s.
+
+Its branch %s could not be merged into %s. Run "git merge %s" in your worktree,
+resolve every conflict so that the intent of both sides is preserved, make sure
+the project still builds and its tests pass, and commit the merge.
+
+Original job: %s`, display.ShortID(j.ID), branch, target, branch, j.Description)
+
+	r := job.New(description)
+	r.SetPriority(j.Priority)
+	r.SetResolvesConflict(j.ID)
+
+	s.jobs.Add(r)
+	s.ledger.Append(ledger.EventJobCreated, ledger.JobEventData{
+		ID:          r.ID,
+		Description: r.Description,
+	})
+
+	if w == nil {
+		w = s.idleConsigliere(r)
+	}
+	if w != nil && s.assignmentsHeld() == "" {
+		if err := r.Queue(); err != nil {
+			s.log.Warn("failed to queue resolver job", "job", r.ID, "error", err)
+			return r
+		}
+		s.jobs.Save(r)
+		s.ledger.Append(ledger.EventJobQueued, ledger.JobEventData{
+			ID:          r.ID,
+			Description: r.Description,
+			Worker:      w.ID,
+			WorkerName:  w.Name,
+		})
+		go s.executeJobWithWorktree(w, r)
+	} else {
+		s.queue.Enqueue(r)
+	}
+
+	return r
+}
+
+// idleConsigliere returns an idle consigliere that may start j, if conflict
+// jobs are configured to be assigned to one.
+func (s *Server) idleConsigliere(j *job.Job) *worker.Worker {
+	if !s.config().Git.AssignConflictsToConsigliere {
+		return nil
+	}
+	for _, w := range s.pool.GetAvailableByRole(worker.RoleConsigliere) {
+		if s.checkBudget(j, w) == nil {
+			return w
+		}
+	}
+	return nil
+}
+
+// activeResolver returns the unfinished job resolving jobID's conflict, if any.
+func (s *Server) activeResolver(jobID string) *job.Job {
+	for _, r := range s.jobs.List() {
+		if r.GetResolvesConflict() == jobID && !r.IsTerminal() {
+			return r
+		}
+	}
+	return nil
+}
+
+// latestResolver returns the most recently created job resolving jobID's
+// conflict, if any.
+func (s *Server) latestResolver(jobID string) *job.Job {
+	var latest *job.Job
+	for _, r := range s.jobs.List() {
+		if r.GetResolvesConflict() != jobID {
+			continue
+		}
+		if latest == nil || r.CreatedAt.After(latest.CreatedAt) {
+			latest = r
+		}
+	}
+	return latest
+}
+
+// resolveConflicts marks the jobs whose conflicts j resolved as merged once
+// their branches are reachable from target, following chains of resolvers.
+func (s *Server) resolveConflicts(j *job.Job, target string) {
+	s.mu.RLock()
+	t := s.territory
+	s.mu.RUnlock()
+	if t == nil {
+		return
+	}
+	gitMgr := t.GitManager()
+
+	resolver := j
+	for id := j.GetResolvesConflict(); id != ""; id = resolver.GetResolvesConflict() {
+		orig, exists := s.jobs.Get(id)
+		if !exists || !orig.IsUnmerged() {
+			return
+		}
+
+		branch := orig.GetBranch()
+		if branch != "" {
+			merged, err := gitMgr.IsMerged(branch, target)
+			if err != nil || !merged {
+				reason := fmt.Sprintf("job %s completed without merging %s", display.ShortID(resolver.ID), branch)
+				s.ledger.Append(ledger.EventType("job.merge_conflict"), ledger.JobEventData{
+					ID:    orig.ID,
+					Error: reason,
+				})
+				s.flagForAttention(orig, job.AttentionMergeConflict, reason)
+				return
+			}
+			removeConflictWorkspace(gitMgr, orig.ID)
+			gitMgr.DeleteBranch(branch, true)
+		}
+
+		orig.ClearWorktree()
+		orig.ClearUnmerged()
+		s.jobs.Save(orig)
+		s.clearMergeAttention(orig)
+
+		s.ledger.Append(ledger.EventType("job.conflict_resolved"), ledger.JobEventData{
+			ID:          orig.ID,
+			Description: fmt.Sprintf("Resolved by job %s", display.ShortID(resolver.ID)),
+		})
+
+		resolver = orig
+	}
+}
+
+func (s *Server) handleJobConflicts(req *protocol.Request) *protocol.Response {
+	s.mu.RLock()
+	t := s.territory
+	s.mu.RUnlock()
+
+	var target string
+	if t != nil {
+		target = t.MergeTargetBranch(s.config().Git.DefaultMergeBranch)
+	}
+
+	conflicts := make([]protocol.ConflictInfo, 0)
+	for _, j := range s.jobs.List() {
+		if !j.IsUnmerged() {
+			continue
+		}
+		info := protocol.ConflictInfo{
+			JobID:       j.ID,
+			Description: j.Description,
+			Branch:      j.GetBranch(),
+			Target:      target,
+		}
+		if j.CompletedAt != nil {
+			info.CompletedAt = j.CompletedAt.Unix()
+		}
+		if r := s.latestResolver(j.ID); r != nil {
+			info.ResolverJob = r.ID
+			info.ResolverStatus = string(r.GetStatus())
+		}
+		conflicts = append(conflicts, info)
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, conflicts)
+	return resp
+}
+
+func (s *Server) handleJobDiff(req *protocol.Request) *protocol.Response {
+	var params protocol.JobDiffParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	j, exists := s.jobs.Get(params.ID)
+	if !exists {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrJobNotFound, "job not found", nil)
+		return resp
+	}
+
+	s.mu.RLock()
+	t := s.territory
+	s.mu.RUnlock()
+	if t == nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, "territory not initialized", nil)
+		return resp
+	}
+
+	target := t.MergeTargetBranch(s.config().Git.DefaultMergeBranch)
+
+	var diff *git.DiffResult
+	var err error
+	switch {
+	case j.GetWorktree() != "":
+		diff, err = t.GitManager().GetDiff(j.GetWorktree(), target)
+	case j.GetBranch() != "":
+		diff, err = t.GitManager().GetBranchDiff(j.GetBranch(), target)
+	case j.GetMergeCommit() != "":
+		// Merged on completion, before it reached review
+		diff, err = t.GitManager().GetMergeDiff(j.GetMergeCommit())
+	default:
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, "job has no worktree, branch or merge commit", nil)
+		return resp
+	}
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, err.Error(), nil)
+		return resp
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, protocol.JobDiffResult{
+		ID:        j.ID,
+		Target:    target,
+		Files:     diff.FilesChanged,
+		Additions: diff.Additions,
+		Deletions: diff.Deletions,
+		Diff:      diff.Diff,
+	})
+	return resp
+}
+
+func (s *Server) handleJobMerge(req *protocol.Request) *protocol.Response {
+	var params protocol.JobMergeParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	j, exists := s.jobs.Get(params.ID)
+	if !exists {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrJobNotFound, "job not found", nil)
+		return resp
+	}
+
+	s.mu.RLock()
+	t := s.territory
+	s.mu.RUnlock()
+	if t == nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, "territory not initialized", nil)
+		return resp
+	}
+
+	branch := j.GetBranch()
+	if branch == "" {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, "job has no unmerged branch", nil)
+		return resp
+	}
+	if j.GetWorktree() != "" {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, "job worktree is still in use", nil)
+		return resp
+	}
+
+	result := protocol.JobMergeResult{
+		ID:     j.ID,
+		Branch: branch,
+		Target: t.MergeTargetBranch(s.config().Git.DefaultMergeBranch),
+	}
+
+	if !params.Retry {
+		conflicts, _, err := t.GitManager().HasConflicts(branch, result.Target)
+		if err != nil {
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, err.Error(), nil)
+			return resp
+		}
+		result.Conflicts = conflicts
+		resp, _ := protocol.NewResponse(req.ID, result)
+		return resp
+	}
+
+	// Commit a merge finished by hand in the conflict workspace first
+	if ws := conflictWorkspace(t.GitManager(), j.ID); ws != "" && git.MergeInProgress(ws) {
+		if err := git.FinishMerge(ws); err != nil {
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrMergeConflict, err.Error(), nil)
+			return resp
+		}
+	}
+
+	commit, err := s.mergeJobBranch(j, false)
+	if errors.Is(err, errMergeConflict) {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrMergeConflict, err.Error(), nil)
+		return resp
+	}
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, err.Error(), nil)
+		return resp
+	}
+
+	result.Merged = true
+	result.Commit = commit
+	result.RemoteBranch = j.GetRemoteBranch()
+	result.PullRequest = j.GetPullRequest()
+	resp, _ := protocol.NewResponse(req.ID, result)
+	return resp
+}
+
+// maxSnippetLines limits each side of a conflict preview.
+const maxSnippetLines = 40
+
+// territoryRef is the git state needed to triage a conflict.
+type territoryRef struct {
+	git    *git.Manager
+	target string
+}
+
+// unmergedJob looks up a job and checks that it has a kept branch. On
+// failure it returns the error response to send.
+func (s *Server) unmergedJob(req *protocol.Request, id string) (*job.Job, *territoryRef, *protocol.Response) {
+	j, exists := s.jobs.Get(id)
+	if !exists {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrJobNotFound, "job not found", nil)
+		return nil, nil, resp
+	}
+
+	s.mu.RLock()
+	t := s.territory
+	s.mu.RUnlock()
+	if t == nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, "territory not initialized", nil)
+		return nil, nil, resp
+	}
+
+	if !j.IsUnmerged() || j.GetBranch() == "" {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, "job has no unmerged branch", nil)
+		return nil, nil, resp
+	}
+
+	return j, &territoryRef{
+		git:    t.GitManager(),
+		target: t.MergeTargetBranch(s.config().Git.DefaultMergeBranch),
+	}, nil
+}
+
+// conflictWorkspace returns the path of the job's conflict workspace if one
+// has been created.
+func conflictWorkspace(gitMgr *git.Manager, jobID string) string {
+	path := gitMgr.GetJobWorktreePath(jobID)
+	if path == "" {
+		return ""
+	}
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// removeConflictWorkspace removes the job's conflict workspace, if any, so
+// that its branch can be deleted.
+func removeConflictWorkspace(gitMgr *git.Manager, jobID string) {
+	if conflictWorkspace(gitMgr, jobID) != "" {
+		gitMgr.RemoveJobWorktree(jobID, true)
+	}
+}
+
+// snippet truncates a diff to maxSnippetLines lines.
+func snippet(diff string) string {
+	lines := strings.Split(strings.TrimRight(diff, "\n"), "\n")
+	if len(lines) <= maxSnippetLines {
+		return strings.Join(lines, "\n")
+	}
+	return strings.Join(lines[:maxSnippetLines], "\n") + fmt.Sprintf("\n... %d more lines", len(lines)-maxSnippetLines)
+}
+
+func (s *Server) handleConflictDetail(req *protocol.Request) *protocol.Response {
+	var params protocol.ConflictParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	j, ref, errResp := s.unmergedJob(req, params.ID)
+	if errResp != nil {
+		return errResp
+	}
+
+	branch := j.GetBranch()
+	files, err := ref.git.ConflictedFiles(branch, ref.target)
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, err.Error(), nil)
+		return resp
+	}
+
+	detail := protocol.ConflictDetail{
+		ConflictInfo: protocol.ConflictInfo{
+			JobID:       j.ID,
+			Description: j.Description,
+			Branch:      branch,
+			Target:      ref.target,
+		},
+		Files:     make([]protocol.ConflictFile, 0, len(files)),
+		Workspace: conflictWorkspace(ref.git, j.ID),
+	}
+	if j.CompletedAt != nil {
+		detail.CompletedAt = j.CompletedAt.Unix()
+	}
+	if r := s.latestResolver(j.ID); r != nil {
+		detail.ResolverJob = r.ID
+		detail.ResolverStatus = string(r.GetStatus())
+	}
+
+	for _, f := range files {
+		file := protocol.ConflictFile{Path: f}
+		if diff, err := ref.git.FileChanges(ref.target, branch, f); err == nil {
+			file.Target = snippet(diff)
+		}
+		if diff, err := ref.git.FileChanges(branch, ref.target, f); err == nil {
+			file.Branch = snippet(diff)
+		}
+		detail.Files = append(detail.Files, file)
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, detail)
+	return resp
+}
+
+func (s *Server) handleConflictAssign(req *protocol.Request) *protocol.Response {
+	var params protocol.ConflictAssignParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	j, ref, errResp := s.unmergedJob(req, params.ID)
+	if errResp != nil {
+		return errResp
+	}
+
+	if r := s.activeResolver(j.ID); r != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState,
+			fmt.Sprintf("conflict is already being resolved by job %s", display.ShortID(r.ID)), nil)
+		return resp
+	}
+
+	var w *worker.Worker
+	if params.Worker != "" {
+		var exists bool
+		w, exists = s.pool.Get(params.Worker)
+		if !exists {
+			w, exists = s.pool.GetByID(params.Worker)
+		}
+		if !exists {
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrWorkerNotFound, "worker not found", nil)
+			return resp
+		}
+		if w.FreeSlots() == 0 {
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, "worker is not idle", nil)
+			return resp
+		}
+	}
+
+	// The resolver merges the branch itself; a hand-started merge would get in its way
+	removeConflictWorkspace(ref.git, j.ID)
+
+	r := s.createResolverJob(j, j.GetBranch(), ref.target, w)
+
+	result := protocol.ConflictAssignResult{ID: j.ID, ResolverJob: r.ID}
+	if w != nil {
+		result.Worker = w.Name
+	}
+	resp, _ := protocol.NewResponse(req.ID, result)
+	return resp
+}
+
+func (s *Server) handleConflictWorkspace(req *protocol.Request) *protocol.Response {
+	var params protocol.ConflictParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	j, ref, errResp := s.unmergedJob(req, params.ID)
+	if errResp != nil {
+		return errResp
+	}
+
+	if r := s.activeResolver(j.ID); r != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState,
+			fmt.Sprintf("conflict is being resolved by job %s", display.ShortID(r.ID)), nil)
+		return resp
+	}
+
+	// The job's worktree was removed on completion; recreate it on the kept branch
+	wt, err := ref.git.CreateJobWorktree(j.ID, ref.target)
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, err.Error(), nil)
+		return resp
+	}
+
+	files, err := git.StartMerge(wt.Path, ref.target)
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, err.Error(), nil)
+		return resp
+	}
+
+	s.ledger.Append(ledger.EventType("job.conflict_workspace"), ledger.JobEventData{
+		ID:          j.ID,
+		Description: fmt.Sprintf("Merging %s into %s by hand in %s", ref.target, wt.Branch, wt.Path),
+	})
+
+	resp, _ := protocol.NewResponse(req.ID, protocol.ConflictWorkspaceResult{
+		ID:    j.ID,
+		Path:  wt.Path,
+		Files: files,
+	})
+	return resp
+}
+
+func (s *Server) handleConflictAbandon(req *protocol.Request) *protocol.Response {
+	var params protocol.ConflictParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	j, ref, errResp := s.unmergedJob(req, params.ID)
+	if errResp != nil {
+		return errResp
+	}
+
+	if r := s.activeResolver(j.ID); r != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState,
+			fmt.Sprintf("conflict is being resolved by job %s; cancel it first", display.ShortID(r.ID)), nil)
+		return resp
+	}
+
+	branch := j.GetBranch()
+	removeConflictWorkspace(ref.git, j.ID)
+	if err := ref.git.DeleteBranch(branch, true); err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, err.Error(), nil)
+		return resp
+	}
+
+	reason := params.Reason
+	if reason == "" {
+		reason = fmt.Sprintf("merge into %s abandoned after conflicts", ref.target)
+	}
+
+	j.ClearWorktree()
+	j.ClearUnmerged()
+	if err := j.MarkNeedsAttention(job.AttentionMergeConflict, reason); err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, err.Error(), nil)
+		return resp
+	}
+	s.jobs.Save(j)
+
+	s.ledger.Append(ledger.EventType("job.merge_abandoned"), ledger.JobEventData{
+		ID:          j.ID,
+		Description: fmt.Sprintf("Deleted branch %s: %s", branch, reason),
+	})
+
+	resp, _ := protocol.NewResponse(req.ID, map[string]string{"status": string(job.StatusNeedsAttention)})
+	return resp
+}
@@ -0,0 +1,92 @@
+package daemon
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"cosa/internal/job"
+	"cosa/internal/protocol"
+)
+
+// jobDiff requests job.diff for id.
+func jobDiff(t *testing.T, s *Server, id string) *protocol.Response {
+	t.Helper()
+	req, err := protocol.NewRequest(protocol.NewIntID(1), protocol.MethodJobDiff, protocol.JobDiffParams{ID: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s.handleJobDiff(req)
+}
+
+func TestHandleJobDiff_InReviewAfterMerge(t *testing.T) {
+	s, _ := newRepoServer(t)
+
+	j := job.New("Add a greeting")
+	s.jobs.Add(j)
+	wt, err := s.territory.GitManager().CreateJobWorktree(j.ID, "main")
+	if err != nil {
+		t.Fatalf("failed to create job worktree: %v", err)
+	}
+	j.SetWorktree(wt.Path, wt.Branch)
+
+	if err := os.WriteFile(filepath.Join(wt.Path, "hello.txt"), []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, wt.Path, "add", "hello.txt")
+	runGit(t, wt.Path, "commit", "-q", "-m", "Add a greeting")
+
+	// While the job runs, the diff comes from its worktree
+	resp := jobDiff(t, s, j.ID)
+	if resp.Error != nil {
+		t.Fatalf("job.diff on a running job failed: %s", resp.Error.Message)
+	}
+
+	// Completion merges the branch away before the review starts, as in
+	// onJobComplete
+	for _, step := range []func() error{j.Queue, func() error { return j.Start("w1", "") }, func() error { return j.Complete("done") }} {
+		if err := step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.mergeAndCleanupJobWorktree(j); err != nil {
+		t.Fatalf("merge failed: %v", err)
+	}
+	if err := j.MarkForReview(); err != nil {
+		t.Fatal(err)
+	}
+	if j.GetBranch() != "" || j.GetMergeCommit() == "" {
+		t.Fatalf("expected the branch cleared and the merge commit kept, got branch %q, commit %q", j.GetBranch(), j.GetMergeCommit())
+	}
+
+	resp = jobDiff(t, s, j.ID)
+	if resp.Error != nil {
+		t.Fatalf("job.diff on a job in review failed: %s", resp.Error.Message)
+	}
+	var result protocol.JobDiffResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(result.Files, []string{"hello.txt"}) {
+		t.Errorf("expected the job's file in the diff, got %v", result.Files)
+	}
+	if result.Additions != 1 || !strings.Contains(result.Diff, "+hello") {
+		t.Errorf("expected the job's changes in the diff, got %d additions:\n%s", result.Additions, result.Diff)
+	}
+}
+
+func TestHandleJobDiff_NothingToShow(t *testing.T) {
+	s, _ := newRepoServer(t)
+
+	j := job.New("Never started")
+	s.jobs.Add(j)
+	if resp := jobDiff(t, s, j.ID); resp.Error == nil || resp.Error.Code != protocol.ErrInvalidState {
+		t.Errorf("expected an invalid state error, got %+v", resp.Error)
+	}
+	if resp := jobDiff(t, s, "missing"); resp.Error == nil || resp.Error.Code != protocol.ErrJobNotFound {
+		t.Errorf("expected job not found, got %+v", resp.Error)
+	}
+}
@@ -0,0 +1,61 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+)
+
+// handleCostsExport breaks the spend recorded in the ledger down by day,
+// worker and job for a range of days.
+func (s *Server) handleCostsExport(req *protocol.Request) *protocol.Response {
+	var params protocol.CostsExportParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	q := ledger.Query{
+		Types:  []ledger.EventType{ledger.EventCostRecord},
+		Worker: params.Worker,
+	}
+	if params.From != "" {
+		q.Since, _ = time.ParseInLocation(time.DateOnly, params.From, time.Local)
+	}
+	if params.To != "" {
+		to, _ := time.ParseInLocation(time.DateOnly, params.To, time.Local)
+		q.Until = to.AddDate(0, 0, 1) // The last day counts in full
+	}
+
+	events, _, err := ledger.Search(s.config().LedgerPath(), q)
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, fmt.Sprintf("failed to read ledger: %v", err), nil)
+		return resp
+	}
+
+	result := protocol.CostsExportResult{
+		From: params.From,
+		To:   params.To,
+		Rows: []protocol.CostExportRow{},
+	}
+	for _, row := range ledger.CostBreakdown(events) {
+		r := protocol.CostExportRow{
+			Day:    row.Day,
+			Worker: row.Worker,
+			JobID:  row.JobID,
+			Cost:   row.Cost,
+			Tokens: row.Tokens,
+		}
+		if j, ok := s.jobs.Get(row.JobID); ok {
+			r.JobDesc = j.Description
+		}
+		result.Rows = append(result.Rows, r)
+		result.TotalCost += row.Cost
+		result.TotalTokens += row.Tokens
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, result)
+	return resp
+}
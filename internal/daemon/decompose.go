@@ -0,0 +1,229 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"cosa/internal/claude"
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+	"cosa/internal/worker"
+)
+
+// decomposeTimeout bounds the underboss session that breaks down a job,
+// which reads the code it plans for.
+const decomposeTimeout = 5 * time.Minute
+
+// decomposePrompt asks the underboss to break a task into subtasks. The
+// task is appended.
+const decomposePrompt = `You are the underboss of the Cosa development organization. The boss
+wants the task below broken into subtasks, each a job for one of the
+soldati (workers). Read as much of the codebase as you need to ground the
+breakdown, but do not make any changes.
+
+Each subtask must be a self-contained change a worker can make and have
+reviewed and merged on its own branch. Use between 2 and 8 subtasks. Keep
+them independent where you can, so they run in parallel; where one needs
+another's changes, list it in depends_on.
+
+Reply with the breakdown only, as JSON:
+
+{"steps": [
+  {"key": "schema", "description": "Add the sessions table", "body": "What to change and how to tell it is done", "priority": 3, "depends_on": [], "estimate": "30m"}
+]}
+
+- key: a short name, up to 32 lowercase letters, digits, - and _
+- description: one line, the job title
+- body: the full instructions for the worker; it sees nothing else
+- priority: 1 (highest) to 5; leave it out to use the task's
+- depends_on: keys of the subtasks that must be merged first
+- estimate: optional, e.g. "30m"
+
+The task:
+
+`
+
+// decompose asks a one-off underboss session, which may read the territory
+// but not edit it, to break task into subtasks.
+func (s *Server) decompose(ctx context.Context, task string) (*protocol.ChatPlan, error) {
+	s.mu.RLock()
+	t := s.territory
+	s.mu.RUnlock()
+	if t == nil {
+		return nil, fmt.Errorf("territory not initialized")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, decomposeTimeout)
+	defer cancel()
+
+	model := s.config().Models.ModelForRole(string(worker.RoleUnderboss))
+	if model == "" {
+		model = s.jobConfig().Claude.Model
+	}
+	client := claude.NewClient(claude.ClientConfig{
+		Binary:         s.config().Claude.Binary,
+		Model:          model,
+		MaxTurns:       30,
+		Workdir:        t.RepoRoot,
+		PermissionMode: "plan",
+	})
+	if err := client.Start(ctx, decomposePrompt+task); err != nil {
+		return nil, fmt.Errorf("failed to start claude: %w", err)
+	}
+
+	var reply strings.Builder
+	var result string
+	for {
+		select {
+		case <-ctx.Done():
+			client.Stop()
+			if ctx.Err() == context.DeadlineExceeded {
+				return nil, fmt.Errorf("the underboss did not answer within %s", decomposeTimeout)
+			}
+			return nil, ctx.Err()
+
+		case <-client.Done():
+			// The result repeats the final message, which holds the plan
+			if result == "" {
+				result = reply.String()
+			}
+			return protocol.ParsePlan(result)
+
+		case event, ok := <-client.Events():
+			if !ok {
+				continue
+			}
+			switch event.Type {
+			case claude.EventAssistantText:
+				reply.WriteString(event.Message)
+				reply.WriteString("\n")
+			case claude.EventResult:
+				if event.Result != nil && event.Result.Message != "" {
+					result = event.Result.Message
+				}
+			case claude.EventError:
+				if event.Error != "" {
+					return nil, fmt.Errorf("claude error: %s", event.Error)
+				}
+			}
+		}
+	}
+}
+
+// checkDecomposition checks that a breakdown can be created as jobs of its
+// own, defaulting keys and priorities: every key is valid and unique, and
+// every dependency is another step, without cycles.
+func checkDecomposition(plan *protocol.ChatPlan, priority int) error {
+	if plan == nil || len(plan.Steps) == 0 {
+		return fmt.Errorf("the breakdown has no subtasks")
+	}
+
+	keys := make(map[string]bool, len(plan.Steps))
+	for i := range plan.Steps {
+		step := &plan.Steps[i]
+		step.Description = strings.TrimSpace(step.Description)
+		if step.Description == "" {
+			return fmt.Errorf("subtask %d has no description", i+1)
+		}
+		if step.Key == "" {
+			step.Key = fmt.Sprintf("step-%d", i+1)
+		}
+		if !planKeyPattern.MatchString(step.Key) {
+			return fmt.Errorf("invalid key %q: use up to 32 lowercase letters, digits, - and _", step.Key)
+		}
+		if keys[step.Key] {
+			return fmt.Errorf("duplicate key %q", step.Key)
+		}
+		keys[step.Key] = true
+		if step.Priority < 1 || step.Priority > 5 {
+			step.Priority = priority
+		}
+	}
+
+	for _, step := range plan.Steps {
+		for _, dep := range step.DependsOn {
+			if dep == step.Key {
+				return fmt.Errorf("%s cannot depend on itself", step.Key)
+			}
+			if !keys[dep] {
+				return fmt.Errorf("%s depends on unknown subtask %s", step.Key, dep)
+			}
+		}
+	}
+
+	_, err := planOrder(plan)
+	return err
+}
+
+// handleJobDecompose has the underboss break a task into subtasks and
+// returns the breakdown for approval, or, given the approved breakdown,
+// creates it as an operation whose jobs depend on each other as planned.
+func (s *Server) handleJobDecompose(ctx context.Context, req *protocol.Request, conn net.Conn) *protocol.Response {
+	var params protocol.JobDecomposeParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	priority := params.Priority
+	if priority == 0 {
+		priority = job.PriorityNormal
+	}
+
+	if params.Plan == nil {
+		task := params.Description
+		if params.Body != "" {
+			task = params.Body
+		}
+		plan, err := s.decompose(ctx, task)
+		if err == nil {
+			err = checkDecomposition(plan, priority)
+		}
+		if err != nil {
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError,
+				fmt.Sprintf("failed to decompose the job: %v", err), nil)
+			return resp
+		}
+
+		resp, _ := protocol.NewResponse(req.ID, protocol.JobDecomposeResult{Plan: plan})
+		return resp
+	}
+
+	if err := checkDecomposition(params.Plan, priority); err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, err.Error(), nil)
+		return resp
+	}
+	if err := s.throttleClient(conn, req.ID); err != nil {
+		return jobAddErrorResponse(req.ID, err)
+	}
+
+	op, ids, errResp := s.createPlanOperation(req, params.Plan, params.Description,
+		"Decomposed by the underboss", protocol.JobAddParams{
+			Requester:  params.Requester,
+			Labels:     params.Labels,
+			Model:      params.Model,
+			SkipReview: params.SkipReview,
+			Timeout:    params.Timeout,
+		})
+	if errResp != nil {
+		return errResp
+	}
+
+	s.ledger.Append(ledger.EventType("job.decomposed"), map[string]interface{}{
+		"description": params.Description,
+		"operation":   op.ID,
+		"jobs":        ids,
+	})
+
+	info := operationToInfo(op)
+	resp, _ := protocol.NewResponse(req.ID, protocol.JobDecomposeResult{
+		Plan:      params.Plan,
+		Operation: &info,
+		Jobs:      ids,
+	})
+	return resp
+}
@@ -0,0 +1,315 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"cosa/internal/display"
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+	"cosa/internal/worker"
+)
+
+// maxDelegationChain is how deep a chain of delegation may grow, so that
+// workers delegating to each other cannot multiply jobs without end.
+const maxDelegationChain = 5
+
+// handleJobDelegate creates a job that a worker hands, while it runs a job,
+// to a worker it supervises. The new job runs on that worker, and the
+// delegating worker is sent a message when it finishes.
+func (s *Server) handleJobDelegate(req *protocol.Request) *protocol.Response {
+	var params protocol.JobDelegateParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	from, exists := s.pool.Get(params.From)
+	if !exists {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrWorkerNotFound, fmt.Sprintf("worker %s not found", params.From), nil)
+		return resp
+	}
+	to, exists := s.pool.Get(params.Worker)
+	if !exists {
+		to, exists = s.pool.GetByID(params.Worker)
+	}
+	if !exists {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrWorkerNotFound, fmt.Sprintf("worker %s not found", params.Worker), nil)
+		return resp
+	}
+
+	var reason string
+	switch {
+	case !worker.CanDelegate(from.Role):
+		reason = fmt.Sprintf("a %s may not delegate jobs", from.Role)
+	case to.ID == from.ID:
+		reason = "a worker may not delegate to itself"
+	case !worker.CanSupervise(from.Role, to.Role):
+		reason = fmt.Sprintf("a %s may not delegate to a %s", from.Role, to.Role)
+	case to.GetQuarantine() != nil:
+		reason = fmt.Sprintf("worker %s is quarantined", to.Name)
+	}
+	if reason != "" {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, reason, nil)
+		return resp
+	}
+
+	parent, err := s.delegatingJob(from, params.Job)
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, err.Error(), nil)
+		return resp
+	}
+	if chain := s.delegationChain(parent); len(chain) >= maxDelegationChain {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState,
+			fmt.Sprintf("job %s is %d delegations deep; do the work instead", display.ShortID(parent.ID), len(chain)), nil)
+		return resp
+	}
+	if err := s.checkAcceptingJobs(); err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, err.Error(), nil)
+		return resp
+	}
+
+	j, err := parent.Delegate(params.Description, from.Name)
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, err.Error(), nil)
+		return resp
+	}
+	if params.Body != "" {
+		j.SetBody(params.Body)
+	}
+	if params.Priority > 0 {
+		j.SetPriority(params.Priority)
+	}
+	// Held for the worker while it is busy, rather than given to another
+	j.SetPreferredWorker(to.ID)
+
+	if _, err := s.submitJob(j, protocol.JobAddParams{
+		Description: j.Description,
+		Labels:      j.GetLabels(),
+		Worker:      to.Name,
+		Requester:   from.Name,
+	}); err != nil {
+		return jobAddErrorResponse(req.ID, err)
+	}
+
+	s.ledger.Append(ledger.EventType("job.delegated"), map[string]string{
+		"id":           j.ID,
+		"description":  j.Description,
+		"delegated_by": parent.ID,
+		"from":         from.Name,
+		"worker":       to.Name,
+	})
+
+	resp, _ := protocol.NewResponse(req.ID, protocol.JobInfo{
+		ID:          j.ID,
+		Description: j.Description,
+		Status:      string(j.GetStatus()),
+		Priority:    j.Priority,
+		CreatedAt:   j.CreatedAt.Unix(),
+		DelegatedBy: s.delegationChain(j),
+	})
+	return resp
+}
+
+// delegatingJob returns the job of from that a job is delegated from: the
+// job jobID, which from must be running, or without one the only job it
+// runs.
+func (s *Server) delegatingJob(from *worker.Worker, jobID string) (*job.Job, error) {
+	if jobID != "" {
+		j, ok := s.jobs.Get(jobID)
+		if !ok || !from.HasJob(j.ID) {
+			return nil, fmt.Errorf("worker %s is not running job %s", from.Name, display.ShortID(jobID))
+		}
+		return j, nil
+	}
+
+	var running []*job.Job
+	for _, slot := range from.Slots() {
+		if slot.Job != nil {
+			running = append(running, slot.Job)
+		}
+	}
+	switch len(running) {
+	case 0:
+		return nil, fmt.Errorf("worker %s has no running job to delegate from", from.Name)
+	case 1:
+		return running[0], nil
+	}
+	return nil, fmt.Errorf("worker %s runs %d jobs; say which one to delegate from", from.Name, len(running))
+}
+
+// delegationChain returns the jobs that delegated j, outermost first, each
+// with the worker that delegated the next job from it.
+func (s *Server) delegationChain(j *job.Job) []protocol.DelegationStep {
+	var chain []protocol.DelegationStep
+	seen := map[string]bool{j.ID: true}
+	for id := j.GetDelegatedBy(); id != "" && !seen[id]; {
+		seen[id] = true
+		chain = append([]protocol.DelegationStep{{Job: id, Worker: j.DelegatedFrom}}, chain...)
+		parent, ok := s.jobs.Get(id)
+		if !ok {
+			break
+		}
+		j, id = parent, parent.GetDelegatedBy()
+	}
+	return chain
+}
+
+// delegatedFrom returns the IDs of the jobs delegated from the job id,
+// oldest first.
+func (s *Server) delegatedFrom(id string) []string {
+	var delegated []*job.Job
+	for _, j := range s.jobs.List() {
+		if j.GetDelegatedBy() == id {
+			delegated = append(delegated, j)
+		}
+	}
+	sort.Slice(delegated, func(a, b int) bool { return delegated[a].CreatedAt.Before(delegated[b].CreatedAt) })
+
+	ids := make([]string, len(delegated))
+	for i, j := range delegated {
+		ids[i] = j.ID
+	}
+	return ids
+}
+
+// reportDelegation sends the worker that delegated j a message that j has
+// finished, which it gets in its running session, or with its next job if
+// it has none. reason is why a failed job failed.
+func (s *Server) reportDelegation(j *job.Job, workerName, reason string) {
+	from := j.DelegatedFrom
+	if j.GetDelegatedBy() == "" || from == "" {
+		return
+	}
+
+	var outcome string
+	switch j.GetStatus() {
+	case job.StatusCompleted:
+		outcome = "completed"
+		if summary := firstLine(j.GetSummary(), j.GetOutput()); summary != "" {
+			outcome += ": " + summary
+		}
+	case job.StatusFailed:
+		outcome = "failed: " + firstLine(reason)
+	default:
+		outcome = string(j.GetStatus())
+	}
+	if workerName != "" {
+		workerName = " by " + workerName
+	}
+	message := fmt.Sprintf("[cosa] Delegated job %s (%s) %s%s", display.ShortID(j.ID), j.Description, outcome, workerName)
+
+	// The report goes to the session of the job it was delegated from
+	delivered := false
+	if w, exists := s.pool.Get(from); exists {
+		delivered = w.PostTo(j.GetDelegatedBy(), message)
+	}
+	s.ledger.Append(ledger.EventType("job.delegation_reported"), map[string]any{
+		"id":        j.ID,
+		"worker":    from,
+		"status":    string(j.GetStatus()),
+		"delivered": delivered,
+	})
+}
+
+// firstLine returns the first line of the first non-empty text.
+func firstLine(texts ...string) string {
+	for _, text := range texts {
+		if text = strings.TrimSpace(text); text != "" {
+			line, _, _ := strings.Cut(text, "\n")
+			return line
+		}
+	}
+	return ""
+}
+
+// delegationMCPConfig writes the MCP config for the sessions of a worker
+// whose role may delegate, which gives them the cosa_delegate_job tool, and
+// returns its path. With a jobID, the config is for the session running
+// that job, so that the jobs it delegates belong to it. It returns "" for
+// other roles, or if the config could not be written.
+func (s *Server) delegationMCPConfig(name string, role worker.Role, jobID string) string {
+	if !worker.CanDelegate(role) {
+		return ""
+	}
+
+	dir := filepath.Join(os.TempDir(), "cosa-mcp")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		s.log.Warn("failed to write delegation MCP config", "worker", name, "error", err)
+		return ""
+	}
+	args := []string{"mcp-serve", "--agent", "--worker", name}
+	file := fmt.Sprintf("mcp-config-worker-%s.json", name)
+	if jobID != "" {
+		args = append(args, "--job", jobID)
+		file = fmt.Sprintf("mcp-config-worker-%s-%s.json", name, jobID)
+		s.pruneJobMCPConfigs(dir, name)
+	}
+	data, err := json.MarshalIndent(MCPConfig{
+		McpServers: map[string]MCPServerConfig{
+			"cosa": {
+				Command: cosaBinary(),
+				Args:    args,
+			},
+		},
+	}, "", "  ")
+	if err != nil {
+		return ""
+	}
+	path := filepath.Join(dir, file)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		s.log.Warn("failed to write delegation MCP config", "worker", name, "error", err)
+		return ""
+	}
+	return path
+}
+
+// jobMCPConfig is the worker.MCPConfigSource of the worker name: each of
+// its jobs' sessions gets an MCP config naming the job.
+func (s *Server) jobMCPConfig(name string, role worker.Role) worker.MCPConfigSource {
+	if !worker.CanDelegate(role) {
+		return nil
+	}
+	return func(j *job.Job) string {
+		return s.delegationMCPConfig(name, role, j.ID)
+	}
+}
+
+// pruneJobMCPConfigs removes the per-job MCP configs in dir of the worker
+// name for jobs it no longer runs.
+func (s *Server) pruneJobMCPConfigs(dir, name string) {
+	w, exists := s.pool.Get(name)
+	if !exists {
+		return
+	}
+	prefix := fmt.Sprintf("mcp-config-worker-%s-", name)
+	paths, _ := filepath.Glob(filepath.Join(dir, prefix+"*.json"))
+	for _, path := range paths {
+		jobID := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), prefix), ".json")
+		if !w.HasJob(jobID) {
+			os.Remove(path)
+		}
+	}
+}
+
+// cosaBinary returns the path of the cosa CLI, which serves MCP: the
+// daemon's own binary when it runs as 'cosa start -f', or the cosa beside
+// cosad.
+func cosaBinary() string {
+	exe, err := os.Executable()
+	if err != nil {
+		return "cosa"
+	}
+	if filepath.Base(exe) != "cosad" {
+		return exe
+	}
+	cli := filepath.Join(filepath.Dir(exe), "cosa")
+	if _, err := os.Stat(cli); err != nil {
+		return "cosa"
+	}
+	return cli
+}
@@ -0,0 +1,170 @@
+package daemon
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+)
+
+// drainPollInterval is how often a drain checks on the work it waits for.
+const drainPollInterval = time.Second
+
+// Drain events, with protocol.DrainProgress data
+const (
+	EventDrainStarted  ledger.EventType = "daemon.draining"
+	EventDrainProgress ledger.EventType = "daemon.drain_progress"
+	EventDrained       ledger.EventType = "daemon.drained"
+)
+
+func (s *Server) handleShutdown(req *protocol.Request) *protocol.Response {
+	var params protocol.ShutdownParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	if !params.Drain {
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			s.cancel()
+		}()
+		resp, _ := protocol.NewResponse(req.ID, protocol.ShutdownResult{Status: "shutting_down"})
+		return resp
+	}
+
+	// A second drain request reports on the drain already under way
+	s.mu.Lock()
+	first := !s.draining.Load()
+	if first {
+		s.drainStarted = time.Now()
+		s.drainDeadline = time.Time{}
+		if params.Timeout > 0 {
+			s.drainDeadline = s.drainStarted.Add(time.Duration(params.Timeout) * time.Second)
+		}
+		s.draining.Store(true)
+	}
+	started, deadline := s.drainStarted, s.drainDeadline
+	s.mu.Unlock()
+
+	if first {
+		s.log.Info("draining before shutdown", "timeout", time.Duration(params.Timeout)*time.Second)
+		s.ledger.Append(EventDrainStarted, s.drainProgress(started, deadline))
+
+		s.wg.Add(1)
+		go s.drain(started, deadline)
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, protocol.ShutdownResult{
+		Status: "draining",
+		Drain:  s.drainProgress(started, deadline),
+	})
+	return resp
+}
+
+// isDraining reports whether the daemon is waiting for running work to
+// finish before it shuts down. No new work starts while it is.
+func (s *Server) isDraining() bool {
+	return s.draining.Load()
+}
+
+// drain waits for running jobs, reviews and merges to finish, reporting
+// progress as it changes, then shuts the daemon down. Work still running at
+// the deadline is stopped with the daemon.
+func (s *Server) drain(started, deadline time.Time) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	var last *protocol.DrainProgress
+	for {
+		progress := s.drainProgress(started, deadline)
+		if progress.Remaining() == 0 {
+			break
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			progress.TimedOut = true
+			s.log.Warn("drain timed out, stopping running work", "jobs", len(progress.Jobs), "reviews", len(progress.Reviews), "merges", progress.Merges)
+			s.finishDrain(progress)
+			return
+		}
+		if last == nil || !reflect.DeepEqual(progress, last) {
+			s.ledger.Append(EventDrainProgress, progress)
+			last = progress
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+
+	s.log.Info("drain complete", "took", time.Since(started).Round(time.Second))
+	s.finishDrain(s.drainProgress(started, deadline))
+}
+
+// finishDrain persists every job and shuts the daemon down.
+func (s *Server) finishDrain(progress *protocol.DrainProgress) {
+	for _, j := range s.jobs.List() {
+		s.jobs.Save(j)
+	}
+	s.ledger.Append(EventDrained, progress)
+	s.cancel()
+}
+
+// drainProgress describes the work a drain is waiting on.
+func (s *Server) drainProgress(started, deadline time.Time) *protocol.DrainProgress {
+	progress := &protocol.DrainProgress{
+		Jobs:    []protocol.DrainItem{},
+		Reviews: []protocol.DrainItem{},
+		Merges:  s.merges.depth(),
+		Started: started.Unix(),
+	}
+	if !deadline.IsZero() {
+		progress.Deadline = deadline.Unix()
+	}
+
+	// A worker holds on to its job until it has finished with it, merge
+	// included, so busy workers cover jobs that have just completed
+	seen := make(map[string]bool)
+	for _, w := range s.pool.List() {
+		for _, j := range w.GetJobs() {
+			seen[j.ID] = true
+			progress.Jobs = append(progress.Jobs, protocol.DrainItem{JobID: j.ID, Description: j.Description, Worker: w.Name})
+		}
+	}
+	for _, j := range s.jobs.List() {
+		if seen[j.ID] {
+			continue
+		}
+		switch j.GetStatus() {
+		case job.StatusQueued, job.StatusRunning:
+			item := protocol.DrainItem{JobID: j.ID, Description: j.Description}
+			if w, ok := s.pool.GetByID(j.Worker); ok {
+				item.Worker = w.Name
+			}
+			progress.Jobs = append(progress.Jobs, item)
+		}
+	}
+	for _, r := range s.activeReviews() {
+		desc := ""
+		if j, ok := s.jobs.Get(r.JobID); ok {
+			desc = j.Description
+		}
+		progress.Reviews = append(progress.Reviews, protocol.DrainItem{
+			JobID:       r.JobID,
+			Description: desc,
+			Worker:      r.WorkerName,
+		})
+	}
+
+	// Keep the order steady so that progress only changes with the work
+	sort.Slice(progress.Jobs, func(i, j int) bool { return progress.Jobs[i].JobID < progress.Jobs[j].JobID })
+	sort.Slice(progress.Reviews, func(i, j int) bool { return progress.Reviews[i].JobID < progress.Reviews[j].JobID })
+	return progress
+}
@@ -0,0 +1,194 @@
+package daemon
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"cosa/internal/admission"
+	"cosa/internal/display"
+	"cosa/internal/email"
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+)
+
+// apiMaxEmail caps the size of an email posted to the gateway, attachments
+// included.
+const apiMaxEmail = 25 << 20
+
+// errEmailRefused is returned, wrapped, for an email the gateway will not
+// turn into a job because of who sent it or where it was sent.
+var errEmailRefused = errors.New("email refused")
+
+// handleAPIEmail takes an email, as the raw message an MTA delivers, and
+// files it as a draft job.
+func (s *Server) handleAPIEmail(w http.ResponseWriter, r *http.Request) {
+	if s.config().Email.Address == "" {
+		writeAPIError(w, http.StatusNotFound, &protocol.Error{
+			Code:    protocol.MethodNotFound,
+			Message: "the email gateway is off; set email.address to turn it on",
+		})
+		return
+	}
+
+	raw, err := io.ReadAll(http.MaxBytesReader(w, r.Body, apiMaxEmail))
+	if err != nil {
+		writeAPIError(w, http.StatusRequestEntityTooLarge, &protocol.Error{
+			Code:    protocol.InvalidRequest,
+			Message: "email too large",
+		})
+		return
+	}
+
+	info, err := s.receiveEmail(raw)
+	if err != nil {
+		status, code := http.StatusBadRequest, protocol.InvalidParams
+		var rejected *admission.RejectedError
+		var throttled *admission.ThrottledError
+		if errors.Is(err, errEmailRefused) || errors.As(err, &rejected) {
+			status, code = http.StatusForbidden, protocol.ErrJobRejected
+		} else if errors.Is(err, errQueueDraining) {
+			// The MTA tries again later
+			status, code = http.StatusServiceUnavailable, protocol.ErrInvalidState
+		} else if errors.As(err, &throttled) {
+			status, code = http.StatusServiceUnavailable, protocol.ErrQueueFull
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		}
+		writeAPIError(w, status, &protocol.Error{Code: code, Message: err.Error()})
+		return
+	}
+
+	w.Header().Set("Location", "/api/jobs/"+info.ID)
+	writeAPIJSON(w, http.StatusCreated, info)
+}
+
+// receiveEmail files an email from an allowed sender as a draft job: the
+// subject is its title, the body its brief, and its attachments are stored
+// for the worker to read.
+func (s *Server) receiveEmail(raw []byte) (protocol.JobInfo, error) {
+	msg, err := email.Parse(bytes.NewReader(raw))
+	if err != nil {
+		return protocol.JobInfo{}, err
+	}
+
+	refuse := func(reason string) (protocol.JobInfo, error) {
+		s.log.Warn("email refused", "from", msg.From, "subject", msg.Subject, "reason", reason)
+		s.ledger.Append(ledger.EventType("email.refused"), map[string]string{
+			"from":    msg.From,
+			"subject": msg.Subject,
+			"reason":  reason,
+		})
+		return protocol.JobInfo{}, fmt.Errorf("%w: %s", errEmailRefused, reason)
+	}
+	if !msg.SentTo(s.config().Email.Address) {
+		return refuse("not sent to " + s.config().Email.Address)
+	}
+	if !email.Allowed(msg.From, s.config().Email.Allow) {
+		return refuse(msg.From + " is not allowed to file jobs")
+	}
+
+	// An email without a subject is titled by its first line
+	title := msg.Subject
+	if title == "" {
+		title, _, _ = strings.Cut(msg.Text, "\n")
+		title = display.Truncate(strings.TrimSpace(title), prTitleWidth)
+	}
+	if title == "" {
+		return protocol.JobInfo{}, fmt.Errorf("email has no subject or body")
+	}
+
+	id := uuid.New().String()
+	paths, err := s.saveAttachments(id, msg.Attachments)
+	if err != nil {
+		return protocol.JobInfo{}, err
+	}
+
+	j, err := s.addJob(protocol.JobAddParams{
+		Description: title,
+		Body:        emailBrief(msg, paths),
+		Requester:   msg.From,
+		Draft:       true,
+	}, id)
+	if err != nil {
+		os.RemoveAll(s.attachmentsDir(id))
+		return protocol.JobInfo{}, err
+	}
+
+	s.ledger.Append(ledger.EventType("email.received"), map[string]string{
+		"job":         j.ID,
+		"from":        msg.From,
+		"subject":     msg.Subject,
+		"attachments": strconv.Itoa(len(paths)),
+	})
+
+	return protocol.JobInfo{
+		ID:            j.ID,
+		Description:   j.Description,
+		Status:        string(j.GetStatus()),
+		Priority:      j.Priority,
+		CreatedAt:     j.CreatedAt.Unix(),
+		AttentionKind: j.GetAttentionKind(),
+	}, nil
+}
+
+// attachmentsDir is where the attachments of a job filed by email are kept.
+func (s *Server) attachmentsDir(jobID string) string {
+	return filepath.Join(s.config().DataDir, "attachments", jobID)
+}
+
+// saveAttachments writes a job's attachments to its attachments directory
+// and returns their paths.
+func (s *Server) saveAttachments(jobID string, attachments []email.Attachment) ([]string, error) {
+	if len(attachments) == 0 {
+		return nil, nil
+	}
+
+	dir := s.attachmentsDir(jobID)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to store attachments: %w", err)
+	}
+
+	var paths []string
+	used := make(map[string]bool)
+	for i, a := range attachments {
+		// Attachments with the same name are told apart by number
+		name := a.Filename
+		if used[name] {
+			ext := filepath.Ext(name)
+			name = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(name, ext), i+1, ext)
+		}
+		used[name] = true
+
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, a.Data, 0600); err != nil {
+			os.RemoveAll(dir)
+			return nil, fmt.Errorf("failed to store attachment %s: %w", a.Filename, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// emailBrief is the body of a job filed by email: the email's text, where
+// its attachments are and who sent it.
+func emailBrief(msg *email.Message, paths []string) string {
+	var sb strings.Builder
+	sb.WriteString(msg.Text)
+	if len(paths) > 0 {
+		sb.WriteString("\n\n## Attachments\n")
+		for _, p := range paths {
+			sb.WriteString("\n- " + p)
+		}
+	}
+	sb.WriteString("\n\nFiled by email from " + msg.From + ".")
+	return strings.TrimSpace(sb.String())
+}
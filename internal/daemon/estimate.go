@@ -0,0 +1,60 @@
+package daemon
+
+import (
+	"time"
+
+	"cosa/internal/job"
+	"cosa/internal/protocol"
+)
+
+// queueForecast estimates when the running and waiting jobs will finish,
+// from how long finished jobs like them took. It returns when the last
+// should finish, 0 if unknown, and each job's estimate. While assignments
+// are held only the running jobs are forecast, since nothing else starts.
+func (s *Server) queueForecast() (int64, []protocol.JobETA) {
+	all := s.jobs.List()
+	estimator := job.NewEstimator(all)
+
+	var running []*job.Job
+	for _, j := range all {
+		if status := j.GetStatus(); status == job.StatusRunning || status == job.StatusQueued {
+			running = append(running, j)
+		}
+	}
+
+	var waiting []*job.Job
+	workers := 0
+	if s.assignmentsHeld() == "" {
+		waiting = s.queue.InOrder()
+		for _, w := range s.pool.List() {
+			if s.checkWorkerQuarantine(w) == nil && s.checkWorkerCapacity(w) == nil {
+				workers++
+			}
+		}
+	}
+
+	forecast := estimator.Forecast(time.Now(), workers, running, waiting)
+
+	var etas []protocol.JobETA
+	for _, j := range append(running, waiting...) {
+		done, ok := forecast.Done[j.ID]
+		if !ok {
+			continue
+		}
+		est := forecast.Estimates[j.ID]
+		etas = append(etas, protocol.JobETA{
+			ID:          j.ID,
+			Description: j.Description,
+			Status:      string(j.GetStatus()),
+			Estimate:    int64(est.Duration.Seconds()),
+			Basis:       est.Basis,
+			ETA:         done.Unix(),
+		})
+	}
+
+	var last int64
+	if !forecast.Last.IsZero() {
+		last = forecast.Last.Unix()
+	}
+	return last, etas
+}
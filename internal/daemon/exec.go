@@ -0,0 +1,206 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+	"cosa/internal/worker"
+)
+
+// retireTimeout bounds how long an ephemeral worker's teardown waits for its
+// Claude session to wind down.
+const retireTimeout = 30 * time.Second
+
+// handleWorkerExec spawns an ephemeral associate and hands it a single job.
+// The associate is torn down once the job finishes.
+func (s *Server) handleWorkerExec(req *protocol.Request) *protocol.Response {
+	var params protocol.WorkerExecParams
+	json.Unmarshal(req.Params, &params)
+
+	s.mu.RLock()
+	t := s.territory
+	s.mu.RUnlock()
+
+	if t == nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, "territory not initialized", nil)
+		return resp
+	}
+	if s.isDraining() {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, "daemon is draining for shutdown", nil)
+		return resp
+	}
+
+	name := params.Name
+	if name == "" {
+		name = s.associateName()
+	} else if s.pool.Exists(name) {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, "worker already exists", nil)
+		return resp
+	}
+
+	wt, err := t.CreateWorkerWorktree(name)
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, err.Error(), nil)
+		return resp
+	}
+
+	w := s.newWorker(t, name, worker.RoleAssociate, wt)
+	w.Ephemeral = true
+	if err := s.pool.Add(w); err != nil {
+		t.RemoveWorkerWorktree(name, true)
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, err.Error(), nil)
+		return resp
+	}
+	w.Start()
+
+	s.ledger.Append(ledger.EventWorkerAdded, ledger.WorkerEventData{
+		ID:       w.ID,
+		Name:     w.Name,
+		Role:     string(w.Role),
+		Worktree: w.Worktree,
+	})
+
+	// The job is reviewed by whoever asked for it as it streams, not by a
+	// consigliere
+	j := job.New(params.Description)
+	if params.Body != "" {
+		j.SetBody(params.Body)
+	}
+	if params.Model != "" {
+		j.SetModel(params.Model)
+	}
+	j.SetSkipReview(true)
+	j.SetNoMerge(params.NoMerge)
+
+	if err := s.checkBudget(j, w); err != nil {
+		s.retireWorker(w)
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, err.Error(), nil)
+		return resp
+	}
+
+	s.jobs.Add(j)
+	s.ledger.Append(ledger.EventJobCreated, ledger.JobEventData{
+		ID:          j.ID,
+		Description: j.Description,
+	})
+
+	if err := j.Queue(); err != nil {
+		s.retireWorker(w)
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, err.Error(), nil)
+		return resp
+	}
+	s.jobs.Save(j)
+	s.ledger.Append(ledger.EventJobQueued, ledger.JobEventData{
+		ID:          j.ID,
+		Description: j.Description,
+		Worker:      w.ID,
+		WorkerName:  w.Name,
+	})
+
+	go s.executeJobWithWorktree(w, j)
+
+	resp, _ := protocol.NewResponse(req.ID, protocol.WorkerExecResult{
+		Worker: w.Name,
+		JobID:  j.ID,
+	})
+	return resp
+}
+
+// associateName returns the first free associate-N worker name.
+func (s *Server) associateName() string {
+	for i := 1; ; i++ {
+		name := fmt.Sprintf("associate-%d", i)
+		if !s.pool.Exists(name) {
+			return name
+		}
+	}
+}
+
+// retireJobWorker tears down the ephemeral worker that ran j, if any.
+func (s *Server) retireJobWorker(j *job.Job) {
+	w := s.findWorkerForJob(j.ID)
+	if w == nil {
+		w, _ = s.pool.GetByID(j.Worker)
+	}
+	if w != nil {
+		s.retireWhenIdle(w)
+	}
+}
+
+// retireWhenIdle tears down an ephemeral worker once its Claude session has
+// wound down. Non-ephemeral workers are left alone.
+func (s *Server) retireWhenIdle(w *worker.Worker) {
+	if !w.Ephemeral {
+		return
+	}
+
+	go func() {
+		deadline := time.Now().Add(retireTimeout)
+		for w.GetStatus() == worker.StatusWorking && time.Now().Before(deadline) {
+			time.Sleep(100 * time.Millisecond)
+		}
+		s.retireWorker(w)
+	}()
+}
+
+// retireWorker removes an ephemeral worker along with its worktree and
+// branch. Its session is not kept since it will not run again.
+func (s *Server) retireWorker(w *worker.Worker) {
+	if _, err := s.pool.Remove(w.Name); err != nil {
+		return // Already retired
+	}
+	w.Stop()
+	s.capacity.RemoveWorker(w.Name)
+
+	s.mu.RLock()
+	t := s.territory
+	s.mu.RUnlock()
+
+	var cleanupErr string
+	if t != nil {
+		if err := t.RemoveWorkerWorktree(w.Name, true); err != nil {
+			cleanupErr = err.Error()
+		}
+		if w.Branch != "" {
+			if err := t.GitManager().DeleteBranch(w.Branch, true); err != nil && cleanupErr == "" {
+				cleanupErr = err.Error()
+			}
+		}
+	}
+
+	s.ledger.Append(ledger.EventWorkerRemoved, ledger.WorkerEventData{
+		ID:    w.ID,
+		Name:  w.Name,
+		Role:  string(w.Role),
+		Error: cleanupErr,
+	})
+}
+
+// keepJobBranch removes a finished job's worktree but keeps its branch, for
+// jobs whose work is not merged.
+func (s *Server) keepJobBranch(j *job.Job) {
+	s.mu.RLock()
+	t := s.territory
+	s.mu.RUnlock()
+
+	jobBranch := j.GetBranch()
+	if t == nil || j.GetWorktree() == "" {
+		return
+	}
+
+	if err := t.GitManager().RemoveJobWorktree(j.ID, true); err != nil {
+		s.log.Warn("failed to remove job worktree", "job", j.ID, "error", err)
+		s.ledger.Append(ledger.EventType("job.worktree_cleanup_error"), ledger.JobEventData{
+			ID:    j.ID,
+			Error: fmt.Sprintf("failed to remove worktree: %v", err),
+		})
+		return
+	}
+
+	j.SetWorktree("", jobBranch)
+	s.jobs.Save(j)
+}
@@ -1,15 +1,21 @@
 package daemon
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"os"
+	"path/filepath"
 	"time"
 
 	"cosa/internal/claude"
+	"cosa/internal/config"
+	"cosa/internal/git"
 	"cosa/internal/job"
 	"cosa/internal/ledger"
 	"cosa/internal/protocol"
+	"cosa/internal/review"
 	"cosa/internal/territory"
 	"cosa/internal/worker"
 )
@@ -17,9 +23,7 @@ import (
 // Territory management handlers
 
 func (s *Server) handleTerritoryInit(req *protocol.Request) *protocol.Response {
-	var params struct {
-		Path string `json:"path"`
-	}
+	var params protocol.TerritoryInitParams
 	if req.Params != nil {
 		json.Unmarshal(req.Params, &params)
 	}
@@ -37,19 +41,36 @@ func (s *Server) handleTerritoryInit(req *protocol.Request) *protocol.Response {
 		return resp
 	}
 
-	t, err := territory.Init(params.Path)
+	t, err := territory.Init(params.Path, &territory.InitOptions{
+		BaseBranch:   params.BaseBranch,
+		DevBranch:    params.DevBranch,
+		TestCommand:  params.TestCommand,
+		BuildCommand: params.BuildCommand,
+		AutoReview:   params.AutoReview,
+	})
 	if err != nil {
 		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrTerritoryExists, err.Error(), nil)
 		return resp
 	}
+	if err := s.applyTerritoryConfig(t); err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, err.Error(), nil)
+		return resp
+	}
+	s.loadRepoIndex(t)
 
 	s.territory = t
 	s.initReviewCoordinator()
 	s.ledger.Append(ledger.EventTerritoryInit, map[string]string{"path": t.Path})
 
-	resp, _ := protocol.NewResponse(req.ID, map[string]string{
-		"status": "initialized",
-		"path":   s.territory.Path,
+	resp, _ := protocol.NewResponse(req.ID, protocol.TerritoryInitResult{
+		Status:            "initialized",
+		Path:              t.Path,
+		BaseBranch:        t.BaseBranch,
+		DevBranch:         t.Config.DevBranch,
+		MergeTargetBranch: t.MergeTargetBranch(s.config().Git.DefaultMergeBranch),
+		TestCommand:       t.Config.TestCommand,
+		BuildCommand:      t.Config.BuildCommand,
+		AutoReview:        t.Config.AutoReview,
 	})
 	return resp
 }
@@ -57,6 +78,10 @@ func (s *Server) handleTerritoryInit(req *protocol.Request) *protocol.Response {
 func (s *Server) handleTerritoryStatus(req *protocol.Request) *protocol.Response {
 	s.mu.RLock()
 	t := s.territory
+	var gates config.GateConfig
+	if t != nil {
+		gates = s.territoryGates()
+	}
 	s.mu.RUnlock()
 
 	if t == nil {
@@ -64,20 +89,27 @@ func (s *Server) handleTerritoryStatus(req *protocol.Request) *protocol.Response
 		return resp
 	}
 
-	resp, _ := protocol.NewResponse(req.ID, map[string]interface{}{
+	result := map[string]interface{}{
 		"path":                t.Path,
 		"repo_root":           t.RepoRoot,
 		"base_branch":         t.BaseBranch,
 		"dev_branch":          t.Config.DevBranch,
-		"merge_target_branch": t.MergeTargetBranch(s.cfg.Git.DefaultMergeBranch),
-	})
+		"merge_target_branch": t.MergeTargetBranch(s.config().Git.DefaultMergeBranch),
+		"model":               s.jobConfig().Claude.Model,
+		"max_turns":           s.jobConfig().Claude.MaxTurns,
+		"test_command":        gates.TestCommand,
+		"build_command":       gates.BuildCommand,
+	}
+	configPath := filepath.Join(t.Path, config.TerritoryConfigFile)
+	if _, err := os.Stat(configPath); err == nil {
+		result["config_file"] = configPath
+	}
+	resp, _ := protocol.NewResponse(req.ID, result)
 	return resp
 }
 
 func (s *Server) handleTerritoryAdd(req *protocol.Request) *protocol.Response {
-	var params struct {
-		Path string `json:"path"`
-	}
+	var params protocol.TerritoryPathParams
 	if req.Params != nil {
 		json.Unmarshal(req.Params, &params)
 	}
@@ -100,6 +132,11 @@ func (s *Server) handleTerritoryAdd(req *protocol.Request) *protocol.Response {
 		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, err.Error(), nil)
 		return resp
 	}
+	if err := s.applyTerritoryConfig(t); err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, err.Error(), nil)
+		return resp
+	}
+	s.loadRepoIndex(t)
 
 	s.territory = t
 	s.initReviewCoordinator()
@@ -166,7 +203,7 @@ func (s *Server) handleTerritorySetDevBranch(req *protocol.Request) *protocol.Re
 
 	resp, _ := protocol.NewResponse(req.ID, protocol.TerritorySetDevBranchResult{
 		DevBranch:         t.Config.DevBranch,
-		MergeTargetBranch: t.MergeTargetBranch(s.cfg.Git.DefaultMergeBranch),
+		MergeTargetBranch: t.MergeTargetBranch(s.config().Git.DefaultMergeBranch),
 	})
 	return resp
 }
@@ -179,11 +216,6 @@ func (s *Server) handleWorkerAdd(req *protocol.Request) *protocol.Response {
 		json.Unmarshal(req.Params, &params)
 	}
 
-	if params.Name == "" {
-		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, "name is required", nil)
-		return resp
-	}
-
 	s.mu.RLock()
 	t := s.territory
 	s.mu.RUnlock()
@@ -213,33 +245,20 @@ func (s *Server) handleWorkerAdd(req *protocol.Request) *protocol.Response {
 	}
 
 	// Try to restore session if available
-	var sessionID string
+	var restored *claude.SessionInfo
 	if sess, err := s.sessions.LoadByWorkerName(params.Name); err == nil {
-		sessionID = sess.SessionID
+		restored = sess
 	}
 
-	// Create worker with completion callbacks
-	w := worker.New(worker.Config{
-		Name:     params.Name,
-		Role:     role,
-		Worktree: wt,
-		ClaudeConfig: claude.ClientConfig{
-			Binary:   s.cfg.Claude.Binary,
-			Model:    s.cfg.Claude.Model,
-			MaxTurns: s.cfg.Claude.MaxTurns,
-		},
-		OnEvent: func(e worker.Event) {
-			s.ledger.Append(ledger.EventType("worker."+e.Type), e)
-		},
-		OnJobComplete:     s.onJobComplete,
-		OnJobFail:         s.onJobFail,
-		OnCostUpdate:      s.onCostUpdate,
-		MergeTargetBranch: t.MergeTargetBranch(s.cfg.Git.DefaultMergeBranch),
-	})
+	w := s.newWorker(t, params.Name, role, wt)
+	w.SetLabels(params.Labels)
 
 	// Restore session ID if available
-	if sessionID != "" {
-		w.SessionID = sessionID
+	if restored != nil && restored.SessionID != "" {
+		w.SessionID = restored.SessionID
+		w.SessionCommit = restored.Commit
+		w.SessionTokens = restored.Tokens
+		w.PreviousSessionID = restored.PreviousSessionID
 	}
 
 	// Add to pool
@@ -265,37 +284,156 @@ func (s *Server) handleWorkerAdd(req *protocol.Request) *protocol.Response {
 		Role:     string(w.Role),
 		Status:   string(w.GetStatus()),
 		Worktree: w.Worktree,
+		Labels:   w.GetLabels(),
 	})
 	return resp
 }
 
+// newWorker creates a worker in wt wired up to the server's callbacks.
+func (s *Server) newWorker(t *territory.Territory, name string, role worker.Role, wt *git.Worktree) *worker.Worker {
+	return worker.New(worker.Config{
+		Name:     name,
+		Role:     role,
+		Worktree: wt,
+		ClaudeConfig: claude.ClientConfig{
+			Binary:   s.config().Claude.Binary,
+			Model:    s.jobConfig().Claude.Model,
+			MaxTurns: s.jobConfig().Claude.MaxTurns,
+
+			MCPConfig:     s.delegationMCPConfig(name, role, ""),
+			StrictOutput:  s.config().Claude.StrictOutput,
+			OnSchemaDrift: s.logSchemaDrift,
+		},
+		OnEvent: func(e worker.Event) {
+			s.ledger.Append(ledger.EventType("worker."+e.Type), e)
+		},
+		OnJobComplete:     s.onJobComplete,
+		OnJobFail:         s.onJobFail,
+		OnCostUpdate:      s.onCostUpdate,
+		OnClaudeEvent:     s.onClaudeEvent,
+		MergeTargetBranch: t.MergeTargetBranch(s.config().Git.DefaultMergeBranch),
+		ResumeCheck:       s.resumeCheck(),
+		SnapshotPolicy:    s.snapshotPolicy(),
+		Identity:          s.commitIdentity(name),
+		Prompts:           s.promptSource(),
+		Orders:            s.inheritedOrders,
+		Fallback:          s.fallbackModel,
+		Model:             s.defaultModel,
+		Context:           s.relevantFiles,
+		RepoMap:           s.repoMap,
+		Knowledge:         s.knowledge,
+		Slots:             s.config().Workers.SlotsFor(string(role)),
+		MCPConfig:         s.jobMCPConfig(name, role),
+	})
+}
+
 func (s *Server) handleWorkerList(req *protocol.Request) *protocol.Response {
+	resp, _ := protocol.NewResponse(req.ID, s.workerInfos())
+	return resp
+}
+
+// workerInfos describes every worker in the pool.
+func (s *Server) workerInfos() []protocol.WorkerInfo {
 	poolWorkers := s.pool.List()
 	workers := make([]protocol.WorkerInfo, 0, len(poolWorkers))
 	for _, w := range poolWorkers {
 		info := protocol.WorkerInfo{
-			ID:       w.ID,
-			Name:     w.Name,
-			Role:     string(w.Role),
-			Status:   string(w.GetStatus()),
-			Worktree: w.Worktree,
+			ID:          w.ID,
+			Name:        w.Name,
+			Role:        string(w.Role),
+			Status:      string(w.GetStatus()),
+			Worktree:    w.Worktree,
+			Labels:      w.GetLabels(),
+			Quarantined: w.GetQuarantine() != nil,
 		}
 		if j := w.GetCurrentJob(); j != nil {
 			info.CurrentJob = j.ID
 			info.CurrentJobDesc = j.Description
 		}
+		if n := w.SlotCount(); n > 1 {
+			info.Slots, info.BusySlots = n, w.BusySlots()
+		}
 		workers = append(workers, info)
 	}
+	return workers
+}
 
-	resp, _ := protocol.NewResponse(req.ID, workers)
+// slotDetails describes the slots of a worker that runs more than one job
+// at once, or returns nil for one that runs a single job.
+func (s *Server) slotDetails(w *worker.Worker) []protocol.SlotDetail {
+	slots := w.Slots()
+	if len(slots) < 2 {
+		return nil
+	}
+
+	details := make([]protocol.SlotDetail, len(slots))
+	for i, slot := range slots {
+		d := protocol.SlotDetail{
+			Index:     slot.Index,
+			Status:    "free",
+			SessionID: slot.SessionID,
+			Tokens:    slot.Tokens,
+			JobsRun:   slot.JobsRun,
+		}
+		if j := slot.Job; j != nil {
+			d.Status = "busy"
+			d.JobID, d.JobDesc = j.ID, j.Description
+			d.Progress, d.Tool = slot.Activity.Progress, slot.Activity.Tool
+			if spent := s.spend.Job(j.ID); spent > 0 {
+				d.Cost = fmt.Sprintf("$%.2f", spent)
+			}
+		}
+		details[i] = d
+	}
+	return details
+}
+
+// handleOverview describes what every worker is doing: its job, how long
+// the job has run, Claude's latest line, the tool in use and the spend.
+func (s *Server) handleOverview(req *protocol.Request) *protocol.Response {
+	now := time.Now()
+	infos := s.workerInfos()
+	workers := make([]protocol.WorkerOverview, len(infos))
+	for i, info := range infos {
+		workers[i].WorkerInfo = info
+
+		w, exists := s.pool.GetByID(info.ID)
+		if !exists {
+			continue
+		}
+		j := w.GetCurrentJob()
+		if j == nil {
+			continue
+		}
+
+		o := &workers[i]
+		o.JobStatus = string(j.GetStatus())
+		if started := j.GetStartedAt(); !started.IsZero() {
+			o.Elapsed = int64(now.Sub(started).Seconds())
+		}
+		a := w.CurrentActivity()
+		o.Progress = a.Progress
+		if !a.ProgressAt.IsZero() {
+			o.ProgressAt = a.ProgressAt.Unix()
+		}
+		if a.Tool != "" {
+			o.Tool = a.Tool
+			o.ToolFor = int64(now.Sub(a.ToolSince).Seconds())
+		}
+		if spent := s.spend.Job(j.ID); spent > 0 {
+			o.Cost = fmt.Sprintf("$%.2f", spent)
+		}
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, protocol.OverviewResult{
+		Workers: workers,
+		Time:    now.Unix(),
+	})
 	return resp
 }
 
 func (s *Server) handleWorkerRemove(req *protocol.Request) *protocol.Response {
-	var params struct {
-		Name  string `json:"name"`
-		Force bool   `json:"force"`
-	}
+	var params protocol.WorkerRemoveParams
 	if req.Params != nil {
 		json.Unmarshal(req.Params, &params)
 	}
@@ -309,57 +447,176 @@ func (s *Server) handleWorkerRemove(req *protocol.Request) *protocol.Response {
 	// Save session before removing worker
 	if w.SessionID != "" {
 		s.sessions.Save(&claude.SessionInfo{
-			SessionID:  w.SessionID,
-			WorkerID:   w.ID,
-			WorkerName: w.Name,
-			CreatedAt:  w.CreatedAt,
-			LastUsed:   time.Now(),
+			SessionID:         w.SessionID,
+			WorkerID:          w.ID,
+			WorkerName:        w.Name,
+			CreatedAt:         w.CreatedAt,
+			LastUsed:          time.Now(),
+			Commit:            w.SessionCommit,
+			Tokens:            w.SessionTokens,
+			PreviousSessionID: w.PreviousSessionID,
 		})
 	}
 
 	// Stop worker
 	w.Stop()
+	s.capacity.RemoveWorker(w.Name)
 
-	// Remove worktree
 	s.mu.RLock()
 	t := s.territory
 	s.mu.RUnlock()
 
-	if t != nil {
-		t.RemoveWorkerWorktree(params.Name, params.Force)
+	interview := s.config().Workers.ExitInterview
+	if params.Interview != nil {
+		interview = *params.Interview
 	}
 
-	// Log event
 	s.ledger.Append(ledger.EventWorkerRemoved, ledger.WorkerEventData{
 		ID:   w.ID,
 		Name: w.Name,
 	})
 
-	resp, _ := protocol.NewResponse(req.ID, map[string]string{"status": "removed"})
+	result := map[string]string{"status": "removed"}
+	if interview {
+		// The interview can take up to claude.chat_timeout, so it runs after
+		// the removal is acknowledged; the worktree it needs goes after it
+		result["interview"] = "started"
+		go s.interviewAndRemoveWorktree(t, w, params.Force)
+	} else if t != nil {
+		t.RemoveWorkerWorktree(params.Name, params.Force)
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, result)
 	return resp
 }
 
 // Job management handlers
 
-func (s *Server) handleJobAdd(req *protocol.Request) *protocol.Response {
+func (s *Server) handleJobAdd(req *protocol.Request, conn net.Conn) *protocol.Response {
 	var params protocol.JobAddParams
 	if req.Params != nil {
 		json.Unmarshal(req.Params, &params)
 	}
 
+	if err := s.throttleClient(conn, req.ID); err != nil {
+		return jobAddErrorResponse(req.ID, err)
+	}
+	j, err := s.addJob(params, "")
+	if err != nil {
+		return jobAddErrorResponse(req.ID, err)
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, protocol.JobInfo{
+		ID:          j.ID,
+		Description: j.Description,
+		Status:      string(j.GetStatus()),
+		Priority:    j.Priority,
+		CreatedAt:   j.CreatedAt.Unix(),
+
+		StickyWorker:  j.GetStickyWorker(),
+		AttentionKind: j.GetAttentionKind(),
+	})
+	return resp
+}
+
+// addJob creates a job from params and queues it, or hands it straight to
+// the requested worker if that worker is idle. If id is set the job is
+// created with that ID.
+func (s *Server) addJob(params protocol.JobAddParams, id string) (*job.Job, error) {
 	if params.Description == "" {
-		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, "description is required", nil)
-		return resp
+		return nil, fmt.Errorf("description is required")
+	}
+	if err := s.checkAcceptingJobs(); err != nil {
+		return nil, err
+	}
+
+	// Apply preset defaults; explicit params take precedence
+	if params.Preset != "" {
+		preset, ok := s.config().Preset(params.Preset)
+		if !ok {
+			return nil, fmt.Errorf("unknown preset: %s", params.Preset)
+		}
+		if params.Priority == 0 {
+			params.Priority = preset.Priority
+		}
+		if params.Worker == "" {
+			params.Worker = preset.Worker
+		}
+		if params.Model == "" {
+			params.Model = preset.Model
+		}
+		if preset.SkipReview {
+			params.SkipReview = true
+		}
+		if len(preset.Labels) > 0 {
+			labels := make(map[string]string, len(preset.Labels)+len(params.Labels))
+			for k, v := range preset.Labels {
+				labels[k] = v
+			}
+			for k, v := range params.Labels {
+				labels[k] = v
+			}
+			params.Labels = labels
+		}
 	}
 
 	// Create job
 	j := job.New(params.Description)
+	if id != "" {
+		j.ID = id
+	}
 	if params.Priority > 0 {
 		j.SetPriority(params.Priority)
 	}
 	if len(params.DependsOn) > 0 {
 		j.SetDependencies(params.DependsOn)
 	}
+	if len(params.Labels) > 0 {
+		j.SetLabels(params.Labels)
+	}
+	if params.Body != "" {
+		j.SetBody(params.Body)
+	}
+	if params.Model != "" {
+		j.SetModel(params.Model)
+	}
+	if params.SkipReview {
+		j.SetSkipReview(true)
+	}
+	if params.Timeout > 0 {
+		j.SetTimeout(time.Duration(params.Timeout) * time.Second)
+	}
+	if params.PlanFirst {
+		j.SetPlanFirst(true)
+	}
+	if params.StickyWorker != "" {
+		name, err := s.stickyWorkerName(params.StickyWorker)
+		if err != nil {
+			return nil, err
+		}
+		j.SetStickyWorker(name)
+
+		// The sticky worker takes the job straight away if it is idle
+		if params.Worker == "" {
+			params.Worker = name
+		} else if other, err := s.stickyWorkerName(params.Worker); err != nil || other != name {
+			return nil, fmt.Errorf("job cannot be assigned to %s and pinned to %s", params.Worker, name)
+		}
+	}
+
+	return s.submitJob(j, params)
+}
+
+// submitJob admits a new job, stores it and queues it, or hands it
+// straight to params.Worker if that worker is idle. A draft is held for
+// approval instead.
+func (s *Server) submitJob(j *job.Job, params protocol.JobAddParams) (*job.Job, error) {
+	if err := s.checkQueueRoom(); err != nil {
+		return nil, err
+	}
+	if err := s.admitJob(j, params); err != nil {
+		return nil, err
+	}
 
 	// Add to store
 	s.jobs.Add(j)
@@ -368,8 +625,17 @@ func (s *Server) handleJobAdd(req *protocol.Request) *protocol.Response {
 	s.ledger.Append(ledger.EventJobCreated, ledger.JobEventData{
 		ID:          j.ID,
 		Description: j.Description,
+		RerunOf:     j.GetRerunOf(),
 	})
 
+	// A draft waits until it is approved
+	if params.Draft {
+		if err := s.holdDraft(j); err != nil {
+			return nil, err
+		}
+		return j, nil
+	}
+
 	// If worker specified, assign directly to that worker
 	if params.Worker != "" {
 		w, exists := s.pool.Get(params.Worker)
@@ -378,8 +644,10 @@ func (s *Server) handleJobAdd(req *protocol.Request) *protocol.Response {
 			w, exists = s.pool.GetByID(params.Worker)
 		}
 
-		if exists && w.GetStatus() == worker.StatusIdle {
-			j.Queue()
+		if exists && w.FreeSlots() > 0 && s.checkBudget(j, w) == nil && s.assignmentsHeld() == "" {
+			if err := j.Queue(); err != nil {
+				return nil, err
+			}
 			s.jobs.Save(j) // Persist queued state
 			s.ledger.Append(ledger.EventJobQueued, ledger.JobEventData{
 				ID:          j.ID,
@@ -399,17 +667,39 @@ func (s *Server) handleJobAdd(req *protocol.Request) *protocol.Response {
 		s.queue.Enqueue(j)
 	}
 
-	resp, _ := protocol.NewResponse(req.ID, protocol.JobInfo{
-		ID:          j.ID,
-		Description: j.Description,
-		Status:      string(j.GetStatus()),
-		Priority:    j.Priority,
-		CreatedAt:   j.CreatedAt.Unix(),
-	})
-	return resp
+	return j, nil
 }
 
 func (s *Server) handleJobList(req *protocol.Request) *protocol.Response {
+	var params protocol.JobListParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	selector, err := job.ParseLabelSelector(params.Labels)
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, err.Error(), nil)
+		return resp
+	}
+
+	infos := s.jobInfos(params.Identity)
+	if selector != nil {
+		matching := infos[:0]
+		for _, info := range infos {
+			if job.MatchLabels(info.Labels, selector) {
+				matching = append(matching, info)
+			}
+		}
+		infos = matching
+	}
+	resp, _ := protocol.NewResponse(req.ID, infos)
+	return resp
+}
+
+// jobInfos describes every job, marking those on identity's watch list.
+func (s *Server) jobInfos(identity string) []protocol.JobInfo {
+	watched := s.watchedBy(identity)
+
 	jobs := s.jobs.List()
 	infos := make([]protocol.JobInfo, 0, len(jobs))
 
@@ -422,6 +712,37 @@ func (s *Server) handleJobList(req *protocol.Request) *protocol.Response {
 			Worker:      j.Worker,
 			DependsOn:   j.DependsOn,
 			CreatedAt:   j.CreatedAt.Unix(),
+			Worktree:    j.GetWorktree(),
+			Branch:      j.GetBranch(),
+			Error:       j.Error,
+			Labels:      j.Labels,
+			Model:       j.GetModel(),
+			ModelUsed:   j.GetModelUsed(),
+			SkipReview:  j.ShouldSkipReview(),
+			NoMerge:     j.ShouldSkipMerge(),
+
+			Unmerged:         j.IsUnmerged(),
+			ResolvesConflict: j.GetResolvesConflict(),
+			AttentionKind:    j.GetAttentionKind(),
+			AttentionReason:  j.GetAttentionReason(),
+			RemoteBranch:     j.GetRemoteBranch(),
+			PullRequest:      j.GetPullRequest(),
+			ReviewChecklist:  j.GetReviewChecklist(),
+			ChecklistResults: checklistInfo(j.GetChecklistResults()),
+			RevisionOf:       j.RevisionOf,
+			RevisionRound:    j.GetRevisionRound(),
+			StickyWorker:     j.GetStickyWorker(),
+			PlanFirst:        j.IsPlanFirst(),
+			Plan:             j.GetPlan(),
+			PlanApproved:     j.IsPlanApproved(),
+			Snapshot:         snapshotInfo(j.GetSnapshot()),
+			Verdict:          verdictInfo(j.GetVerdict()),
+			Watched:          watched[j.ID],
+
+			RerunOf: j.GetRerunOf(),
+		}
+		if j.QueuedAt != nil {
+			info.QueuedAt = j.QueuedAt.Unix()
 		}
 		if j.StartedAt != nil {
 			info.StartedAt = j.StartedAt.Unix()
@@ -429,17 +750,17 @@ func (s *Server) handleJobList(req *protocol.Request) *protocol.Response {
 		if j.CompletedAt != nil {
 			info.CompletedAt = j.CompletedAt.Unix()
 		}
+		if since := j.GetAttentionSince(); !since.IsZero() {
+			info.AttentionSince = since.Unix()
+		}
+		s.setTimeoutInfo(&info, j)
 		infos = append(infos, info)
 	}
-
-	resp, _ := protocol.NewResponse(req.ID, infos)
-	return resp
+	return infos
 }
 
 func (s *Server) handleJobCancel(req *protocol.Request) *protocol.Response {
-	var params struct {
-		ID string `json:"id"`
-	}
+	var params protocol.JobCancelParams
 	if req.Params != nil {
 		json.Unmarshal(req.Params, &params)
 	}
@@ -450,27 +771,63 @@ func (s *Server) handleJobCancel(req *protocol.Request) *protocol.Response {
 		return resp
 	}
 
+	if err := j.Cancel(); err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState,
+			fmt.Sprintf("job already %s", j.GetStatus()), nil)
+		return resp
+	}
+
 	// Remove from queue if pending
 	s.queue.Remove(j.ID)
-
-	j.Cancel()
-	s.ledger.Append(ledger.EventJobCancelled, ledger.JobEventData{ID: j.ID})
+	s.jobs.Save(j)
+	s.queue.NotifyFailure(j.ID)
+	review.SettleRevised(s.jobs, j)
+	s.ledger.Append(ledger.EventJobCancelled, ledger.JobEventData{
+		ID:          j.ID,
+		Description: j.Description,
+	})
+	s.reportDelegation(j, "", "")
+
+	// Stop the Claude process if a worker is running this job, then clean up
+	// its worktree once the process has exited.
+	if w := s.findWorkerForJob(j.ID); w != nil {
+		grace := time.Duration(params.GraceMS) * time.Millisecond
+		go func() {
+			if err := w.CancelJob(j.ID, grace); err != nil {
+				s.ledger.Append(ledger.EventType("job.cancel_error"), ledger.JobEventData{
+					ID:         j.ID,
+					Worker:     w.ID,
+					WorkerName: w.Name,
+					Error:      err.Error(),
+				})
+			}
+			s.cleanupCancelledJobWorktree(j)
+			s.retireWhenIdle(w)
+		}()
+	} else {
+		s.cleanupCancelledJobWorktree(j)
+	}
 
 	resp, _ := protocol.NewResponse(req.ID, map[string]string{"status": "cancelled"})
 	return resp
 }
 
+// findWorkerForJob returns the worker currently running the given job, if any.
+func (s *Server) findWorkerForJob(jobID string) *worker.Worker {
+	for _, w := range s.pool.List() {
+		if w.HasJob(jobID) {
+			return w
+		}
+	}
+	return nil
+}
+
 func (s *Server) handleJobAssign(req *protocol.Request) *protocol.Response {
 	var params protocol.JobAssignParams
 	if req.Params != nil {
 		json.Unmarshal(req.Params, &params)
 	}
 
-	if params.JobID == "" || params.WorkerID == "" {
-		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, "job_id and worker_id are required", nil)
-		return resp
-	}
-
 	j, exists := s.jobs.Get(params.JobID)
 	if !exists {
 		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrJobNotFound, "job not found", nil)
@@ -486,14 +843,36 @@ func (s *Server) handleJobAssign(req *protocol.Request) *protocol.Response {
 		return resp
 	}
 
-	if w.GetStatus() != worker.StatusIdle {
-		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, "worker is not idle", nil)
+	if w.FreeSlots() == 0 {
+		msg := "worker is not idle"
+		if w.SlotCount() > 1 {
+			msg = "worker has no free slot"
+		}
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, msg, nil)
+		return resp
+	}
+
+	if err := checkSticky(j, w); err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, err.Error(), nil)
+		return resp
+	}
+
+	if reason := s.assignmentsHeld(); reason != "" {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, reason, nil)
+		return resp
+	}
+
+	if err := s.checkBudget(j, w); err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, err.Error(), nil)
 		return resp
 	}
 
 	// Remove from queue and assign
+	if err := j.Queue(); err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, err.Error(), nil)
+		return resp
+	}
 	s.queue.Remove(j.ID)
-	j.Queue()
 
 	s.ledger.Append(ledger.EventJobQueued, ledger.JobEventData{
 		ID:          j.ID,
@@ -515,11 +894,6 @@ func (s *Server) handleJobReassign(req *protocol.Request) *protocol.Response {
 		json.Unmarshal(req.Params, &params)
 	}
 
-	if params.JobID == "" {
-		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, "job_id is required", nil)
-		return resp
-	}
-
 	j, exists := s.jobs.Get(params.JobID)
 	if !exists {
 		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrJobNotFound, "job not found", nil)
@@ -548,9 +922,16 @@ func (s *Server) handleJobReassign(req *protocol.Request) *protocol.Response {
 			resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrWorkerNotFound, "worker not found", nil)
 			return resp
 		}
+		if err := checkSticky(j, w); err != nil {
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, err.Error(), nil)
+			return resp
+		}
 
-		if w.GetStatus() == worker.StatusIdle {
-			j.Queue()
+		if w.GetStatus() == worker.StatusIdle && s.checkBudget(j, w) == nil && s.assignmentsHeld() == "" {
+			if err := j.Queue(); err != nil {
+				resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, err.Error(), nil)
+				return resp
+			}
 			s.jobs.Save(j)
 			s.ledger.Append(ledger.EventJobQueued, ledger.JobEventData{
 				ID:          j.ID,
@@ -583,7 +964,7 @@ func (s *Server) handleJobReassign(req *protocol.Request) *protocol.Response {
 			})
 			return resp
 		}
-		// Worker not idle, fall through to queue
+		// Worker not idle or over budget, fall through to queue
 	}
 
 	// No worker specified or worker not available, add to queue for scheduler
@@ -595,9 +976,7 @@ func (s *Server) handleJobReassign(req *protocol.Request) *protocol.Response {
 }
 
 func (s *Server) handleWorkerStatus(req *protocol.Request) *protocol.Response {
-	var params struct {
-		Name string `json:"name"`
-	}
+	var params protocol.WorkerNameParams
 	if req.Params != nil {
 		json.Unmarshal(req.Params, &params)
 	}
@@ -622,15 +1001,88 @@ func (s *Server) handleWorkerStatus(req *protocol.Request) *protocol.Response {
 		JobsCompleted: w.JobsCompleted,
 		JobsFailed:    w.JobsFailed,
 		CreatedAt:     w.CreatedAt.Unix(),
+		Labels:        w.GetLabels(),
 	}
+	info.SessionTokens, info.PreviousSessionID = w.SessionStats()
+	info.FailureStreak = w.FailureStreak()
+	info.Quarantine = quarantineInfo(w.GetQuarantine())
+	identity := w.GetIdentity()
+	info.GitAuthor, info.GitSigning = identity.Author(), identity.SigningFormat
 	if j := w.GetCurrentJob(); j != nil {
 		info.CurrentJob = j.ID
 	}
+	info.Slots = s.slotDetails(w)
+	info.LastActivity, info.LastProgress, info.Progress = progressTimes(w)
 
 	resp, _ := protocol.NewResponse(req.ID, info)
 	return resp
 }
 
+// progressTimes returns when a worker last had activity and last made
+// progress, as Unix times, and what the progress was.
+func progressTimes(w *worker.Worker) (activity, progress int64, what string) {
+	if at := w.GetLastActivity(); !at.IsZero() {
+		activity = at.Unix()
+	}
+	at, what := w.GetLastProgress()
+	if !at.IsZero() {
+		progress = at.Unix()
+	}
+	return activity, progress, what
+}
+
+// handleWorkerLabel sets and removes a worker's labels. The scheduler
+// prefers workers whose labels match a job's.
+func (s *Server) handleWorkerLabel(req *protocol.Request) *protocol.Response {
+	var params protocol.WorkerLabelParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	w, exists := s.pool.Get(params.Name)
+	if !exists {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrWorkerNotFound, "worker not found", nil)
+		return resp
+	}
+
+	labels := w.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	for key, value := range params.Set {
+		if key == "" {
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, "label key must not be empty", nil)
+			return resp
+		}
+		labels[key] = value
+	}
+	for _, key := range params.Remove {
+		delete(labels, key)
+	}
+	if len(labels) == 0 {
+		labels = nil
+	}
+	w.SetLabels(labels)
+
+	if err := s.pool.Save(w); err != nil {
+		s.log.Warn("failed to save worker labels", "worker", w.Name, "error", err)
+	}
+	s.ledger.Append(ledger.EventType("worker.labeled"), map[string]interface{}{
+		"worker": w.Name,
+		"labels": labels,
+	})
+
+	resp, _ := protocol.NewResponse(req.ID, protocol.WorkerInfo{
+		ID:       w.ID,
+		Name:     w.Name,
+		Role:     string(w.Role),
+		Status:   string(w.GetStatus()),
+		Worktree: w.Worktree,
+		Labels:   labels,
+	})
+	return resp
+}
+
 func (s *Server) handleQueueStatus(req *protocol.Request) *protocol.Response {
 	result := protocol.QueueStatusResult{
 		Ready:   s.queue.ReadyLen(),
@@ -638,15 +1090,18 @@ func (s *Server) handleQueueStatus(req *protocol.Request) *protocol.Response {
 		Running: s.jobs.CountByStatus(job.StatusRunning),
 		Total:   s.jobs.Count(),
 	}
+	if hold := s.queueControl.Get(); hold.State != job.QueueRunning {
+		info := queueHoldInfo(hold)
+		result.Hold = &info
+	}
+	result.ETA, result.Jobs = s.queueForecast()
 
 	resp, _ := protocol.NewResponse(req.ID, result)
 	return resp
 }
 
 func (s *Server) handleWorkerDetail(req *protocol.Request) *protocol.Response {
-	var params struct {
-		Name string `json:"name"`
-	}
+	var params protocol.WorkerNameParams
 	if req.Params != nil {
 		json.Unmarshal(req.Params, &params)
 	}
@@ -673,29 +1128,135 @@ func (s *Server) handleWorkerDetail(req *protocol.Request) *protocol.Response {
 		TotalCost:     w.TotalCost,
 		TotalTokens:   w.TotalTokens,
 		CreatedAt:     w.CreatedAt.Unix(),
+		Labels:        w.GetLabels(),
 	}
+	info.SessionTokens, info.PreviousSessionID = w.SessionStats()
+	info.FailureStreak = w.FailureStreak()
+	info.Quarantine = quarantineInfo(w.GetQuarantine())
+	identity := w.GetIdentity()
+	info.GitAuthor, info.GitSigning = identity.Author(), identity.SigningFormat
 	if j := w.GetCurrentJob(); j != nil {
 		info.CurrentJob = j.ID
 	}
+	info.Slots = s.slotDetails(w)
+	info.LastActivity, info.LastProgress, info.Progress = progressTimes(w)
 
 	resp, _ := protocol.NewResponse(req.ID, info)
 	return resp
 }
 
-func (s *Server) handleWorkerMessage(req *protocol.Request) *protocol.Response {
-	var params struct {
-		Name    string `json:"name"`
-		Message string `json:"message"`
-	}
+func (s *Server) handleWorkerTranscript(req *protocol.Request) *protocol.Response {
+	var params protocol.WorkerTranscriptParams
 	if req.Params != nil {
 		json.Unmarshal(req.Params, &params)
 	}
 
-	if params.Message == "" {
-		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, "message is required", nil)
+	w, exists := s.pool.Get(params.Name)
+	if !exists {
+		w, exists = s.pool.GetByID(params.Name)
+	}
+	// A given job's transcript outlives the worker, e.g. an ephemeral one
+	if !exists && params.JobID == "" {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrWorkerNotFound, "worker not found", nil)
+		return resp
+	}
+
+	var j *job.Job
+	if params.JobID != "" {
+		j, exists = s.jobs.Get(params.JobID)
+		if !exists {
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrJobNotFound, "job not found", nil)
+			return resp
+		}
+	} else if j = w.GetCurrentJob(); j == nil {
+		j = s.latestTranscriptJob(w.ID)
+	}
+
+	workerName := params.Name
+	if w != nil {
+		workerName = w.Name
+	}
+	result := protocol.WorkerTranscriptResult{
+		Worker:  workerName,
+		Entries: []protocol.TranscriptEntry{},
+		Next:    params.Offset,
+	}
+	if j == nil {
+		resp, _ := protocol.NewResponse(req.ID, result)
+		return resp
+	}
+
+	entries, next, err := s.transcripts.Read(j.ID, params.Offset)
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, err.Error(), nil)
 		return resp
 	}
 
+	result.JobID = j.ID
+	result.Next = next
+	result.Active = j.GetStatus() == job.StatusRunning || j.GetStatus() == job.StatusQueued
+	for _, e := range entries {
+		result.Entries = append(result.Entries, transcriptEntryInfo(e))
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, result)
+	return resp
+}
+
+// latestTranscriptJob returns the most recently started job of a worker that
+// has a recorded transcript.
+func (s *Server) latestTranscriptJob(workerID string) *job.Job {
+	var latest *job.Job
+	for _, j := range s.jobs.ListByWorker(workerID) {
+		if !s.transcripts.Exists(j.ID) {
+			continue
+		}
+		if latest == nil || jobStartTime(j).After(jobStartTime(latest)) {
+			latest = j
+		}
+	}
+	return latest
+}
+
+func jobStartTime(j *job.Job) time.Time {
+	if j.StartedAt != nil {
+		return *j.StartedAt
+	}
+	return j.CreatedAt
+}
+
+func transcriptEntryInfo(e claude.TranscriptEntry) protocol.TranscriptEntry {
+	info := protocol.TranscriptEntry{
+		Time:    e.Time.Unix(),
+		Type:    string(e.Event.Type),
+		Message: e.Event.Message,
+		Error:   e.Event.Error,
+	}
+	if tool := e.Event.Tool; tool != nil {
+		info.Tool = tool.Name
+		info.Input = string(tool.Input)
+		info.Output = tool.Output
+		if info.Error == "" {
+			info.Error = tool.Error
+		}
+	}
+	if result := e.Event.Result; result != nil {
+		if info.Message == "" {
+			info.Message = result.Message
+		}
+		if !result.Success && info.Error == "" {
+			info.Error = "claude reported failure"
+		}
+	}
+	return info
+}
+
+func (s *Server) handleWorkerMessage(req *protocol.Request) *protocol.Response {
+	var params protocol.WorkerMessageParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
 	w, exists := s.pool.Get(params.Name)
 	if !exists {
 		w, exists = s.pool.GetByID(params.Name)
@@ -720,9 +1281,7 @@ func (s *Server) handleWorkerMessage(req *protocol.Request) *protocol.Response {
 }
 
 func (s *Server) handleJobStatus(req *protocol.Request) *protocol.Response {
-	var params struct {
-		ID string `json:"id"`
-	}
+	var params protocol.JobStatusParams
 	if req.Params != nil {
 		json.Unmarshal(req.Params, &params)
 	}
@@ -736,11 +1295,47 @@ func (s *Server) handleJobStatus(req *protocol.Request) *protocol.Response {
 	info := protocol.JobInfo{
 		ID:          j.ID,
 		Description: j.Description,
+		Body:        j.GetBody(),
 		Status:      string(j.GetStatus()),
 		Priority:    j.Priority,
 		Worker:      j.Worker,
 		DependsOn:   j.DependsOn,
 		CreatedAt:   j.CreatedAt.Unix(),
+		Worktree:    j.GetWorktree(),
+		Branch:      j.GetBranch(),
+		Error:       j.Error,
+		Labels:      j.Labels,
+		Model:       j.GetModel(),
+		ModelUsed:   j.GetModelUsed(),
+		SkipReview:  j.ShouldSkipReview(),
+		NoMerge:     j.ShouldSkipMerge(),
+
+		Unmerged:         j.IsUnmerged(),
+		ResolvesConflict: j.GetResolvesConflict(),
+		AttentionKind:    j.GetAttentionKind(),
+		AttentionReason:  j.GetAttentionReason(),
+		RemoteBranch:     j.GetRemoteBranch(),
+		PullRequest:      j.GetPullRequest(),
+		ReviewChecklist:  j.GetReviewChecklist(),
+		ChecklistResults: checklistInfo(j.GetChecklistResults()),
+		RevisionOf:       j.RevisionOf,
+		RevisionRound:    j.GetRevisionRound(),
+		StickyWorker:     j.GetStickyWorker(),
+		PlanFirst:        j.IsPlanFirst(),
+		Plan:             j.GetPlan(),
+		PlanApproved:     j.IsPlanApproved(),
+		Snapshot:         snapshotInfo(j.GetSnapshot()),
+		Verdict:          verdictInfo(j.GetVerdict()),
+
+		RerunOf:      j.GetRerunOf(),
+		Reruns:       s.rerunsOf(j.ID),
+		DelegatedBy:  s.delegationChain(j),
+		Delegated:    s.delegatedFrom(j.ID),
+		Template:     j.Template,
+		TemplateVars: j.TemplateVars,
+	}
+	if j.QueuedAt != nil {
+		info.QueuedAt = j.QueuedAt.Unix()
 	}
 	if j.StartedAt != nil {
 		info.StartedAt = j.StartedAt.Unix()
@@ -748,27 +1343,34 @@ func (s *Server) handleJobStatus(req *protocol.Request) *protocol.Response {
 	if j.CompletedAt != nil {
 		info.CompletedAt = j.CompletedAt.Unix()
 	}
+	if since := j.GetAttentionSince(); !since.IsZero() {
+		info.AttentionSince = since.Unix()
+	}
+	s.setTimeoutInfo(&info, j)
+	info.Settled = s.jobSettled(j)
 
 	resp, _ := protocol.NewResponse(req.ID, info)
 	return resp
 }
 
+// jobSettled reports whether nothing more will happen to a job unless
+// someone asks: it is over and no worker holds it any longer. A worker
+// holds a completed job while it is merged and its review starts, and a
+// failed one while it is retried.
+func (s *Server) jobSettled(j *job.Job) bool {
+	switch j.GetStatus() {
+	case job.StatusCompleted, job.StatusFailed, job.StatusCancelled:
+		return s.findWorkerForJob(j.ID) == nil
+	}
+	return false
+}
+
 func (s *Server) handleJobSetPriority(req *protocol.Request) *protocol.Response {
 	var params protocol.JobSetPriorityParams
 	if req.Params != nil {
 		json.Unmarshal(req.Params, &params)
 	}
 
-	if params.JobID == "" {
-		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, "job_id is required", nil)
-		return resp
-	}
-
-	if params.Priority < 1 || params.Priority > 5 {
-		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, "priority must be between 1 and 5", nil)
-		return resp
-	}
-
 	j, exists := s.jobs.Get(params.JobID)
 	if !exists {
 		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrJobNotFound, "job not found", nil)
@@ -806,12 +1408,37 @@ func (s *Server) loadExistingTerritory(path string) error {
 	if err != nil {
 		return fmt.Errorf("failed to load territory: %w", err)
 	}
+	if err := s.applyTerritoryConfig(t); err != nil {
+		return err
+	}
+	s.loadRepoIndex(t)
 
 	s.territory = t
 	s.initReviewCoordinator()
 	return nil
 }
 
+// Preset handlers
+
+func (s *Server) handlePresetList(req *protocol.Request) *protocol.Response {
+	names := s.config().PresetNames()
+	presets := make([]protocol.PresetInfo, 0, len(names))
+	for _, name := range names {
+		p, _ := s.config().Preset(name)
+		presets = append(presets, protocol.PresetInfo{
+			Name:       name,
+			Priority:   p.Priority,
+			Labels:     p.Labels,
+			SkipReview: p.SkipReview,
+			Model:      p.Model,
+			Worker:     p.Worker,
+		})
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, protocol.PresetListResult{Presets: presets})
+	return resp
+}
+
 // Template management handlers
 
 func (s *Server) handleTemplateList(req *protocol.Request) *protocol.Response {
@@ -829,25 +1456,7 @@ func (s *Server) handleTemplateList(req *protocol.Request) *protocol.Response {
 
 	infos := make([]protocol.TemplateInfo, 0, len(templates))
 	for _, t := range templates {
-		vars := make([]protocol.TemplateVar, len(t.Variables))
-		for i, v := range t.Variables {
-			vars[i] = protocol.TemplateVar{
-				Name:        v.Name,
-				Description: v.Description,
-				Required:    v.Required,
-				Default:     v.Default,
-			}
-		}
-		infos = append(infos, protocol.TemplateInfo{
-			ID:          t.ID,
-			Name:        t.Name,
-			Description: t.Description,
-			Type:        string(t.Type),
-			Priority:    t.Priority,
-			Variables:   vars,
-			Tags:        t.Tags,
-			BuiltIn:     t.BuiltIn,
-		})
+		infos = append(infos, templateToInfo(t))
 	}
 
 	resp, _ := protocol.NewResponse(req.ID, protocol.TemplateListResult{
@@ -862,39 +1471,15 @@ func (s *Server) handleTemplateGet(req *protocol.Request) *protocol.Response {
 		json.Unmarshal(req.Params, &params)
 	}
 
-	if params.ID == "" {
-		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, "id is required", nil)
-		return resp
-	}
-
 	t, exists := s.templates.Get(params.ID)
 	if !exists {
 		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrTemplateNotFound, "template not found", nil)
 		return resp
 	}
 
-	vars := make([]protocol.TemplateVar, len(t.Variables))
-	for i, v := range t.Variables {
-		vars[i] = protocol.TemplateVar{
-			Name:        v.Name,
-			Description: v.Description,
-			Required:    v.Required,
-			Default:     v.Default,
-		}
-	}
-
 	resp, _ := protocol.NewResponse(req.ID, protocol.TemplateGetResult{
-		Template: protocol.TemplateInfo{
-			ID:          t.ID,
-			Name:        t.Name,
-			Description: t.Description,
-			Type:        string(t.Type),
-			Priority:    t.Priority,
-			Variables:   vars,
-			Tags:        t.Tags,
-			BuiltIn:     t.BuiltIn,
-		},
-		Prompt: t.Prompt,
+		Template: templateToInfo(t),
+		Prompt:   t.Prompt,
 	})
 	return resp
 }
@@ -905,17 +1490,17 @@ func (s *Server) handleTemplateUse(req *protocol.Request) *protocol.Response {
 		json.Unmarshal(req.Params, &params)
 	}
 
-	if params.TemplateID == "" {
-		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, "template_id is required", nil)
-		return resp
-	}
-
 	t, exists := s.templates.Get(params.TemplateID)
 	if !exists {
 		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrTemplateNotFound, "template not found", nil)
 		return resp
 	}
 
+	if err := s.checkAcceptingJobs(); err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, err.Error(), nil)
+		return resp
+	}
+
 	// Create job from template
 	j, err := t.CreateJob(params.Variables)
 	if err != nil {
@@ -949,8 +1534,11 @@ func (s *Server) handleTemplateUse(req *protocol.Request) *protocol.Response {
 			w, exists = s.pool.GetByID(params.Worker)
 		}
 
-		if exists && w.GetStatus() == worker.StatusIdle {
-			j.Queue()
+		if exists && w.FreeSlots() > 0 && s.checkBudget(j, w) == nil && s.assignmentsHeld() == "" {
+			if err := j.Queue(); err != nil {
+				resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, err.Error(), nil)
+				return resp
+			}
 			s.jobs.Save(j)
 			s.ledger.Append(ledger.EventJobQueued, ledger.JobEventData{
 				ID:          j.ID,
@@ -981,17 +1569,12 @@ func (s *Server) handleTemplateUse(req *protocol.Request) *protocol.Response {
 
 // Review management handlers
 
-func (s *Server) handleReviewStart(req *protocol.Request) *protocol.Response {
+func (s *Server) handleReviewStart(ctx context.Context, req *protocol.Request) *protocol.Response {
 	var params protocol.ReviewStartParams
 	if req.Params != nil {
 		json.Unmarshal(req.Params, &params)
 	}
 
-	if params.JobID == "" {
-		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, "job_id is required", nil)
-		return resp
-	}
-
 	// Get the job
 	j, exists := s.jobs.Get(params.JobID)
 	if !exists {
@@ -1016,6 +1599,12 @@ func (s *Server) handleReviewStart(req *protocol.Request) *protocol.Response {
 		return resp
 	}
 
+	// The client may have given up while the request waited its turn
+	if ctx.Err() != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrRequestCancelled, "request cancelled", nil)
+		return resp
+	}
+
 	// Start the review
 	if err := coord.StartReview(s.ctx, j, w); err != nil {
 		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, err.Error(), nil)
@@ -1035,11 +1624,6 @@ func (s *Server) handleReviewStatus(req *protocol.Request) *protocol.Response {
 		json.Unmarshal(req.Params, &params)
 	}
 
-	if params.JobID == "" {
-		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, "job_id is required", nil)
-		return resp
-	}
-
 	s.mu.RLock()
 	coord := s.reviewCoordinator
 	s.mu.RUnlock()
@@ -1065,24 +1649,109 @@ func (s *Server) handleReviewStatus(req *protocol.Request) *protocol.Response {
 		Feedback:   status.Feedback,
 		Error:      status.Error,
 		StartedAt:  status.StartedAt.Unix(),
+		Checklist:  checklistInfo(status.Checklist),
 	}
 
 	resp, _ := protocol.NewResponse(req.ID, result)
 	return resp
 }
 
+// checklistInfo converts review checklist results for the protocol.
+// snapshotInfo converts a job's worktree snapshot for the protocol.
+func snapshotInfo(s *job.WorktreeSnapshot) *protocol.SnapshotInfo {
+	if s == nil {
+		return nil
+	}
+	return &protocol.SnapshotInfo{Action: s.Action, Commit: s.Commit, Files: s.Files}
+}
+
+// verdictInfo converts a job's review verdict for the protocol.
+func verdictInfo(v *job.ReviewVerdict) *protocol.VerdictInfo {
+	if v == nil {
+		return nil
+	}
+	return &protocol.VerdictInfo{Decision: v.Decision, Summary: v.Summary, MustFix: v.MustFix}
+}
+
+func checklistInfo(results []job.ChecklistResult) []protocol.ChecklistResult {
+	if len(results) == 0 {
+		return nil
+	}
+	infos := make([]protocol.ChecklistResult, len(results))
+	for i, r := range results {
+		infos[i] = protocol.ChecklistResult{Item: r.Item, Passed: r.Passed, Note: r.Note}
+	}
+	return infos
+}
+
 func (s *Server) handleReviewList(req *protocol.Request) *protocol.Response {
+	resp, _ := protocol.NewResponse(req.ID, protocol.ReviewListResult{
+		Reviews: s.activeReviews(),
+	})
+	return resp
+}
+
+// handleReviewOverride approves or rejects a job's review in place of the
+// consigliere.
+func (s *Server) handleReviewOverride(req *protocol.Request, decision review.Decision) *protocol.Response {
+	var params protocol.ReviewOverrideParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	j, exists := s.jobs.Get(params.JobID)
+	if !exists {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrJobNotFound, "job not found", nil)
+		return resp
+	}
+
+	w, exists := s.pool.GetByID(j.Worker)
+	if !exists {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrWorkerNotFound, "worker not found", nil)
+		return resp
+	}
+
 	s.mu.RLock()
 	coord := s.reviewCoordinator
 	s.mu.RUnlock()
 
 	if coord == nil {
-		resp, _ := protocol.NewResponse(req.ID, protocol.ReviewListResult{
-			Reviews: []protocol.ReviewStatusResult{},
-		})
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, "review coordinator not initialized", nil)
 		return resp
 	}
 
+	by := params.By
+	if by == "" {
+		by = "unknown"
+	}
+	revision, err := coord.Override(s.ctx, j, w, decision, params.Reason, by, !params.NoRevision)
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, err.Error(), nil)
+		return resp
+	}
+
+	result := protocol.ReviewOverrideResult{
+		JobID:    j.ID,
+		Decision: string(decision),
+		Status:   string(j.GetStatus()),
+	}
+	if revision != nil {
+		result.RevisionJobID = revision.ID
+	}
+	resp, _ := protocol.NewResponse(req.ID, result)
+	return resp
+}
+
+// activeReviews describes the reviews in progress.
+func (s *Server) activeReviews() []protocol.ReviewStatusResult {
+	s.mu.RLock()
+	coord := s.reviewCoordinator
+	s.mu.RUnlock()
+
+	if coord == nil {
+		return []protocol.ReviewStatusResult{}
+	}
+
 	reviews := coord.GetActiveReviews()
 	results := make([]protocol.ReviewStatusResult, 0, len(reviews))
 
@@ -1097,11 +1766,66 @@ func (s *Server) handleReviewList(req *protocol.Request) *protocol.Response {
 			Feedback:   status.Feedback,
 			Error:      status.Error,
 			StartedAt:  status.StartedAt.Unix(),
+			Checklist:  checklistInfo(status.Checklist),
 		})
 	}
+	return results
+}
 
-	resp, _ := protocol.NewResponse(req.ID, protocol.ReviewListResult{
-		Reviews: results,
-	})
+// defaultLedgerQueryLimit caps ledger.query results when no limit is given.
+const defaultLedgerQueryLimit = 100
+
+func (s *Server) handleLedgerQuery(req *protocol.Request) *protocol.Response {
+	var params protocol.LedgerQueryParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	q := ledger.Query{
+		Worker: params.Worker,
+		Before: params.Before,
+		Limit:  params.Limit,
+	}
+	if q.Limit <= 0 {
+		q.Limit = defaultLedgerQueryLimit
+	}
+	if params.Since > 0 {
+		q.Since = time.Unix(params.Since, 0)
+	}
+	if params.Until > 0 {
+		q.Until = time.Unix(params.Until, 0)
+	}
+	for _, t := range params.Types {
+		q.Types = append(q.Types, ledger.EventType(t))
+	}
+	if params.Level != "" {
+		level, err := ledger.ParseSeverity(params.Level)
+		if err != nil {
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, err.Error(), nil)
+			return resp
+		}
+		q.Level = level
+	}
+
+	events, more, err := ledger.Search(s.config().LedgerPath(), q)
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, fmt.Sprintf("failed to read ledger: %v", err), nil)
+		return resp
+	}
+
+	result := protocol.LedgerQueryResult{
+		Events: make([]protocol.LedgerEvent, 0, len(events)),
+		More:   more,
+	}
+	for _, e := range events {
+		result.Events = append(result.Events, protocol.LedgerEvent{
+			ID:        e.ID,
+			Type:      string(e.Type),
+			Timestamp: e.Timestamp,
+			Data:      e.Data,
+		})
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, result)
 	return resp
 }
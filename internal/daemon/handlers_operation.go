@@ -2,9 +2,14 @@ package daemon
 
 import (
 	"encoding/json"
+	"fmt"
 
+	"context"
+	"cosa/internal/display"
 	"cosa/internal/job"
+	"cosa/internal/ledger"
 	"cosa/internal/protocol"
+	"cosa/internal/worker"
 )
 
 // handleOperationCreate creates a new operation with the given jobs.
@@ -15,14 +20,10 @@ func (s *Server) handleOperationCreate(req *protocol.Request) *protocol.Response
 		return resp
 	}
 
-	if params.Name == "" {
-		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, "Name is required", nil)
-		return resp
-	}
-
 	// Create operation
 	op := job.NewOperation(params.Name)
 	op.Description = params.Description
+	op.SetLimits(params.MaxParallel, params.Sequential, params.StopOnFail)
 
 	// Add jobs to operation
 	for _, jobID := range params.Jobs {
@@ -33,6 +34,7 @@ func (s *Server) handleOperationCreate(req *protocol.Request) *protocol.Response
 		}
 		op.AddJob(j.ID)
 		j.Operation = op.ID
+		s.jobs.Save(j)
 	}
 
 	// Store operation
@@ -100,9 +102,7 @@ func (s *Server) handleOperationCancel(req *protocol.Request) *protocol.Response
 	jobIDs := op.GetJobIDs()
 	for _, jobID := range jobIDs {
 		if j, exists := s.jobs.Get(jobID); exists {
-			if !j.IsTerminal() {
-				j.Cancel()
-			}
+			_ = j.Cancel() // Finished jobs keep their status
 		}
 	}
 
@@ -113,31 +113,19 @@ func (s *Server) handleOperationCancel(req *protocol.Request) *protocol.Response
 	return resp
 }
 
-// handleOrderSet sets standing orders for a worker.
+// handleOrderSet sets standing orders for a worker, a role or every
+// worker.
 func (s *Server) handleOrderSet(req *protocol.Request) *protocol.Response {
 	var params protocol.OrderSetParams
 	if err := json.Unmarshal(req.Params, &params); err != nil {
 		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, "Invalid params", nil)
 		return resp
 	}
-
-	w, exists := s.pool.Get(params.Worker)
-	if !exists {
-		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrWorkerNotFound, "Worker not found", nil)
-		return resp
-	}
-
-	w.SetStandingOrders(params.Orders)
-
-	result := protocol.OrderListResult{
-		Worker: w.Name,
-		Orders: w.GetStandingOrders(),
-	}
-	resp, _ := protocol.NewResponse(req.ID, result)
-	return resp
+	return s.setOrders(req, params.OrderScope, params.Orders)
 }
 
-// handleOrderList lists standing orders for a worker.
+// handleOrderList lists standing orders for a worker, a role or every
+// worker.
 func (s *Server) handleOrderList(req *protocol.Request) *protocol.Response {
 	var params protocol.OrderListParams
 	if err := json.Unmarshal(req.Params, &params); err != nil {
@@ -145,46 +133,28 @@ func (s *Server) handleOrderList(req *protocol.Request) *protocol.Response {
 		return resp
 	}
 
-	w, exists := s.pool.Get(params.Worker)
-	if !exists {
-		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrWorkerNotFound, "Worker not found", nil)
+	result, code, err := s.orderList(params.OrderScope)
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, code, err.Error(), nil)
 		return resp
 	}
-
-	result := protocol.OrderListResult{
-		Worker: w.Name,
-		Orders: w.GetStandingOrders(),
-	}
 	resp, _ := protocol.NewResponse(req.ID, result)
 	return resp
 }
 
-// handleOrderClear clears standing orders for a worker.
+// handleOrderClear clears standing orders for a worker, a role or every
+// worker.
 func (s *Server) handleOrderClear(req *protocol.Request) *protocol.Response {
 	var params protocol.OrderClearParams
 	if err := json.Unmarshal(req.Params, &params); err != nil {
 		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, "Invalid params", nil)
 		return resp
 	}
-
-	w, exists := s.pool.Get(params.Worker)
-	if !exists {
-		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrWorkerNotFound, "Worker not found", nil)
-		return resp
-	}
-
-	w.ClearStandingOrders()
-
-	result := protocol.OrderListResult{
-		Worker: w.Name,
-		Orders: []string{},
-	}
-	resp, _ := protocol.NewResponse(req.ID, result)
-	return resp
+	return s.setOrders(req, params.OrderScope, nil)
 }
 
 // handleHandoffGenerate generates a handoff summary for a worker.
-func (s *Server) handleHandoffGenerate(req *protocol.Request) *protocol.Response {
+func (s *Server) handleHandoffGenerate(ctx context.Context, req *protocol.Request) *protocol.Response {
 	var params protocol.HandoffGenerateParams
 	if err := json.Unmarshal(req.Params, &params); err != nil {
 		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, "Invalid params", nil)
@@ -197,7 +167,19 @@ func (s *Server) handleHandoffGenerate(req *protocol.Request) *protocol.Response
 		return resp
 	}
 
-	// Generate handoff summary
+	summary := s.handoffSummary(ctx, w)
+	if ctx.Err() != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrRequestCancelled, "request cancelled", nil)
+		return resp
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, summary)
+	return resp
+}
+
+// handoffSummary summarizes where a worker has got to, for whoever takes
+// over from it.
+func (s *Server) handoffSummary(ctx context.Context, w *worker.Worker) protocol.HandoffSummary {
 	summary := protocol.HandoffSummary{
 		WorkerID:   w.ID,
 		WorkerName: w.Name,
@@ -205,17 +187,22 @@ func (s *Server) handleHandoffGenerate(req *protocol.Request) *protocol.Response
 		CreatedAt:  w.CreatedAt.Unix(),
 	}
 
-	// Include current job info if working
+	// Include current job info if working, with a summary of its changes
+	// so far
 	if job := w.GetCurrentJob(); job != nil {
 		summary.JobID = job.ID
+
+		if diff, err := s.jobWorktreeDiff(job); err == nil && len(diff.FilesChanged) > 0 {
+			summary.FilesTouched = diff.FilesChanged
+			summary.Summary = s.summarizer.Summarize(ctx, diff, handoffSummaryBudget).Text
+		}
 	}
 
 	// Note: In a full implementation, we would analyze the session output
 	// to extract decisions, files touched, and open questions.
 	// For now, we return a basic summary.
 
-	resp, _ := protocol.NewResponse(req.ID, summary)
-	return resp
+	return summary
 }
 
 // operationToInfo converts an Operation to OperationInfo.
@@ -234,6 +221,7 @@ func operationToInfo(op *job.Operation) protocol.OperationInfo {
 		Progress:      op.Progress(),
 		CreatedAt:     createdAt.Unix(),
 	}
+	info.MaxParallel, info.Sequential, info.StopOnFail = op.GetLimits()
 
 	if startedAt != nil {
 		info.StartedAt = startedAt.Unix()
@@ -244,3 +232,66 @@ func operationToInfo(op *job.Operation) protocol.OperationInfo {
 
 	return info
 }
+
+// operationAllows reports whether the limits of j's operation let it start.
+func (s *Server) operationAllows(j *job.Job) bool {
+	if j.Operation == "" {
+		return true
+	}
+	op, exists := s.operations.Get(j.Operation)
+	if !exists {
+		return true
+	}
+	return op.CanStart(j.ID, func(id string) (job.Status, bool) {
+		if other, ok := s.jobs.Get(id); ok {
+			return other.GetStatus(), true
+		}
+		return "", false
+	})
+}
+
+// operationJobDone counts a finished job towards its operation. When the
+// operation stops on failure, the jobs that have not started are cancelled.
+func (s *Server) operationJobDone(j *job.Job, failed bool) {
+	if j.Operation == "" {
+		return
+	}
+	op, exists := s.operations.Get(j.Operation)
+	if !exists {
+		return
+	}
+
+	if !failed {
+		op.IncrementCompleted()
+		return
+	}
+	op.IncrementFailed()
+
+	if _, _, stopOnFail := op.GetLimits(); !stopOnFail || op.GetStatus() == job.OperationStatusCancelled {
+		return
+	}
+
+	var cancelled []string
+	for _, id := range op.GetJobIDs() {
+		other, ok := s.jobs.Get(id)
+		if !ok || other.GetStatus() != job.StatusPending || other.Cancel() != nil {
+			continue
+		}
+		s.queue.Remove(other.ID)
+		s.jobs.Save(other)
+		s.queue.NotifyFailure(other.ID)
+		s.ledger.Append(ledger.EventJobCancelled, ledger.JobEventData{
+			ID:          other.ID,
+			Description: other.Description,
+		})
+		cancelled = append(cancelled, other.ID)
+	}
+	op.Fail()
+
+	s.ledger.Append(ledger.EventType("operation.stopped"), map[string]interface{}{
+		"operation": op.ID,
+		"failed":    j.ID,
+		"cancelled": cancelled,
+		"reason":    fmt.Sprintf("job %s failed", display.ShortID(j.ID)),
+	})
+}
@@ -0,0 +1,49 @@
+package daemon
+
+import (
+	"path/filepath"
+	"slices"
+
+	"cosa/internal/config"
+	"cosa/internal/git"
+	"cosa/internal/worker"
+)
+
+// installJobHooks writes the hooks that add the job trailer to commits in
+// job worktrees, when git.job_trailer is on.
+func (s *Server) installJobHooks() {
+	if !s.config().Git.JobTrailer {
+		return
+	}
+	dir := filepath.Join(s.config().DataDir, "githooks")
+	if err := git.InstallJobHooks(dir); err != nil {
+		s.log.Warn("failed to install job hooks, commits will not name their jobs", "error", err)
+		return
+	}
+	s.jobHooks = dir
+}
+
+// commitIdentity returns who the named worker's commits are made as and
+// how they are signed. An author or signing format that is not valid is
+// left to git.
+func (s *Server) commitIdentity(name string) worker.CommitIdentity {
+	id := s.config().GitIdentityFor(name)
+	identity := worker.CommitIdentity{
+		SigningFormat: id.SigningFormat,
+		SigningKey:    id.SigningKey,
+		TrailerHooks:  s.jobHooks,
+	}
+	if id.SigningFormat != "" && !slices.Contains(config.SigningFormats, id.SigningFormat) {
+		s.log.Warn("ignoring unknown git signing format", "worker", name, "format", id.SigningFormat)
+		identity.SigningFormat = ""
+	}
+	if id.Author != "" {
+		author, email, err := config.ParseAuthor(id.Author)
+		if err != nil {
+			s.log.Warn("ignoring git author", "worker", name, "error", err)
+		} else {
+			identity.Name, identity.Email = author, email
+		}
+	}
+	return identity
+}
@@ -0,0 +1,100 @@
+package daemon
+
+import (
+	"time"
+
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+	"cosa/internal/worker"
+)
+
+// touchActivity records client or job activity for idle shutdown.
+func (s *Server) touchActivity() {
+	s.lastActivity.Store(time.Now().UnixNano())
+}
+
+// startIdleMonitor starts the idle shutdown monitor if configured.
+func (s *Server) startIdleMonitor() {
+	timeout := s.config().Daemon.IdleShutdown
+	if timeout <= 0 {
+		return
+	}
+
+	s.touchActivity()
+
+	// Check often enough to shut down close to the configured time
+	interval := timeout / 10
+	if interval > time.Minute {
+		interval = time.Minute
+	}
+	if interval < time.Second {
+		interval = time.Second
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+				if !s.isIdle() {
+					s.touchActivity()
+					continue
+				}
+
+				idleFor := time.Since(time.Unix(0, s.lastActivity.Load()))
+				if idleFor < timeout {
+					continue
+				}
+
+				s.ledger.Append(ledger.EventType("daemon.idle_shutdown"), map[string]string{
+					"idle_for": idleFor.Round(time.Second).String(),
+				})
+				s.cancel()
+				return
+			}
+		}
+	}()
+}
+
+// isIdle returns true when there are no connected clients, no running or
+// scheduled jobs, no jobs in review, no busy workers and no active chat
+// session.
+func (s *Server) isIdle() bool {
+	s.clientsMu.RLock()
+	clients := len(s.clients)
+	s.clientsMu.RUnlock()
+	if clients > 0 {
+		return false
+	}
+
+	if s.jobs.CountByStatus(job.StatusRunning) > 0 || s.jobs.CountByStatus(job.StatusQueued) > 0 {
+		return false
+	}
+	if s.queue.ReadyLen() > 0 || s.queue.PendingLen() > 0 {
+		return false
+	}
+
+	// The consigliere reviews after the job has stopped running
+	if s.jobs.CountByStatus(job.StatusReview) > 0 {
+		return false
+	}
+
+	for _, w := range s.pool.List() {
+		if w.GetStatus() != worker.StatusIdle {
+			return false
+		}
+	}
+
+	s.mu.RLock()
+	chatting := s.chatSession != nil
+	s.mu.RUnlock()
+
+	return !chatting
+}
@@ -0,0 +1,98 @@
+package daemon
+
+import (
+	"net"
+	"testing"
+
+	"cosa/internal/job"
+	"cosa/internal/worker"
+)
+
+func TestIsIdle(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(s *Server)
+		idle  bool
+	}{
+		{
+			name:  "nothing going on",
+			setup: func(s *Server) {},
+			idle:  true,
+		},
+		{
+			name: "client connected",
+			setup: func(s *Server) {
+				client, server := net.Pipe()
+				t.Cleanup(func() { client.Close(); server.Close() })
+				s.clients[server] = &clientState{}
+			},
+		},
+		{
+			name:  "job running",
+			setup: func(s *Server) { addJobWithStatus(t, s, job.StatusRunning) },
+		},
+		{
+			name:  "job queued",
+			setup: func(s *Server) { addJobWithStatus(t, s, job.StatusQueued) },
+		},
+		{
+			name:  "job in review",
+			setup: func(s *Server) { addJobWithStatus(t, s, job.StatusReview) },
+		},
+		{
+			name:  "only finished jobs",
+			setup: func(s *Server) { addJobWithStatus(t, s, job.StatusCompleted) },
+			idle:  true,
+		},
+		{
+			name: "worker busy",
+			setup: func(s *Server) {
+				w := worker.New(worker.Config{Name: "vito", Role: worker.RoleSoldato})
+				w.Status = worker.StatusWorking
+				if err := s.pool.Add(w); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name:  "chatting",
+			setup: func(s *Server) { s.chatSession = &ChatSession{} },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs := job.NewStore()
+			s := &Server{
+				clients: make(map[net.Conn]*clientState),
+				jobs:    jobs,
+				queue:   job.NewQueue(jobs),
+				pool:    worker.NewPool(),
+			}
+			tt.setup(s)
+
+			if got := s.isIdle(); got != tt.idle {
+				t.Errorf("isIdle() = %v, want %v", got, tt.idle)
+			}
+		})
+	}
+}
+
+// addJobWithStatus adds a job taken through the transitions to status.
+func addJobWithStatus(t *testing.T, s *Server, status job.Status) {
+	t.Helper()
+	j := job.New("Some work")
+	s.jobs.Add(j)
+
+	steps := map[job.Status][]func() error{
+		job.StatusQueued:    {j.Queue},
+		job.StatusRunning:   {j.Queue, func() error { return j.Start("w1", "") }},
+		job.StatusCompleted: {j.Queue, func() error { return j.Start("w1", "") }, func() error { return j.Complete("done") }},
+		job.StatusReview:    {j.Queue, func() error { return j.Start("w1", "") }, func() error { return j.Complete("done") }, j.MarkForReview},
+	}
+	for _, step := range steps[status] {
+		if err := step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,350 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+)
+
+// importRetention is how long finished batches can still be queried.
+const importRetention = 24 * time.Hour
+
+// Ledger events for batch job submission
+const (
+	eventImportSubmitted ledger.EventType = "job.import_submitted"
+	eventImportCompleted ledger.EventType = "job.import_completed"
+)
+
+// importBatch tracks a batch of jobs submitted with job.addBatch. The jobs
+// are validated and created in the background.
+type importBatch struct {
+	id          string
+	items       []protocol.JobImportItem
+	submittedAt time.Time
+	completedAt time.Time
+	mu          sync.Mutex
+}
+
+// status returns a snapshot of the batch.
+func (b *importBatch) status() protocol.JobImportStatusResult {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	result := protocol.JobImportStatusResult{
+		BatchID:     b.id,
+		Status:      "running",
+		Total:       len(b.items),
+		Items:       make([]protocol.JobImportItem, len(b.items)),
+		SubmittedAt: b.submittedAt.Unix(),
+	}
+	copy(result.Items, b.items)
+
+	for _, item := range b.items {
+		switch item.Status {
+		case "created":
+			result.Created++
+		case "failed":
+			result.Failed++
+		}
+	}
+
+	if !b.completedAt.IsZero() {
+		result.Status = "completed"
+		result.CompletedAt = b.completedAt.Unix()
+	}
+	return result
+}
+
+func (b *importBatch) setResult(i int, status string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.items[i].Status = status
+	if err != nil {
+		b.items[i].Error = err.Error()
+	}
+}
+
+func (s *Server) handleJobAddBatch(req *protocol.Request) *protocol.Response {
+	var params protocol.JobAddBatchParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+	if err := s.checkAcceptingJobs(); err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, err.Error(), nil)
+		return resp
+	}
+
+	batch := &importBatch{
+		id:          uuid.New().String(),
+		items:       make([]protocol.JobImportItem, len(params.Jobs)),
+		submittedAt: time.Now(),
+	}
+
+	ids := make([]string, len(params.Jobs))
+	for i, p := range params.Jobs {
+		ids[i] = uuid.New().String()
+		batch.items[i] = protocol.JobImportItem{
+			Index:       i,
+			ID:          ids[i],
+			Description: p.Description,
+			Status:      "pending",
+		}
+	}
+
+	s.importsMu.Lock()
+	s.pruneImportsLocked()
+	s.imports[batch.id] = batch
+	s.importsMu.Unlock()
+
+	s.ledger.Append(eventImportSubmitted, map[string]interface{}{
+		"batch_id": batch.id,
+		"total":    len(params.Jobs),
+	})
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.runImport(batch, params.Jobs)
+	}()
+
+	resp, _ := protocol.NewResponse(req.ID, protocol.JobAddBatchResult{
+		BatchID: batch.id,
+		IDs:     ids,
+	})
+	return resp
+}
+
+// runImport validates and creates the jobs of a batch in order, then
+// reports the outcome.
+func (s *Server) runImport(batch *importBatch, jobs []protocol.JobAddParams) {
+	// Jobs may depend on jobs earlier or later in the same batch
+	batchIDs := make(map[string]bool, len(batch.items))
+	for _, item := range batch.items {
+		batchIDs[item.ID] = true
+	}
+
+	for i, params := range jobs {
+		if s.ctx.Err() != nil {
+			batch.setResult(i, "failed", fmt.Errorf("daemon shutting down"))
+			continue
+		}
+
+		if err := s.validateImportDeps(params.DependsOn, batchIDs); err != nil {
+			batch.setResult(i, "failed", err)
+			continue
+		}
+
+		if _, err := s.addJob(params, batch.items[i].ID); err != nil {
+			batch.setResult(i, "failed", err)
+			continue
+		}
+		batch.setResult(i, "created", nil)
+	}
+
+	batch.mu.Lock()
+	batch.completedAt = time.Now()
+	batch.mu.Unlock()
+
+	status := batch.status()
+	s.ledger.Append(eventImportCompleted, map[string]interface{}{
+		"batch_id": batch.id,
+		"total":    status.Total,
+		"created":  status.Created,
+		"failed":   status.Failed,
+	})
+	s.notifier.NotifyImportComplete(batch.id, status.Created, status.Failed)
+}
+
+// validateImportDeps checks that every dependency is an existing job or a
+// job of the same batch.
+func (s *Server) validateImportDeps(deps []string, batchIDs map[string]bool) error {
+	for _, dep := range deps {
+		if batchIDs[dep] {
+			continue
+		}
+		if _, ok := s.jobs.Get(dep); !ok {
+			return fmt.Errorf("unknown dependency: %s", dep)
+		}
+	}
+	return nil
+}
+
+// pruneImportsLocked drops batches that finished more than importRetention
+// ago. Caller must hold importsMu.
+func (s *Server) pruneImportsLocked() {
+	for id, batch := range s.imports {
+		batch.mu.Lock()
+		expired := !batch.completedAt.IsZero() && time.Since(batch.completedAt) > importRetention
+		batch.mu.Unlock()
+		if expired {
+			delete(s.imports, id)
+		}
+	}
+}
+
+func (s *Server) handleJobImportStatus(req *protocol.Request) *protocol.Response {
+	var params protocol.JobImportStatusParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	s.importsMu.Lock()
+	batch, ok := s.imports[params.BatchID]
+	s.importsMu.Unlock()
+
+	if !ok {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, fmt.Sprintf("batch %s not found", params.BatchID), nil)
+		return resp
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, batch.status())
+	return resp
+}
+
+// handleJobImport creates the jobs of a parsed task list, with each section
+// as an operation. The whole list is validated first, so either every job is
+// created or none; with DryRun nothing is created at all.
+func (s *Server) handleJobImport(req *protocol.Request) *protocol.Response {
+	var params protocol.JobImportParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	tasks := make(map[string]protocol.ImportTask, len(params.Tasks))
+	plan := &protocol.ChatPlan{Steps: make([]protocol.PlanStep, len(params.Tasks))}
+	for i, task := range params.Tasks {
+		if task.Key == "" {
+			task.Key = fmt.Sprintf("task-%d", i+1)
+		}
+		if _, dup := tasks[task.Key]; dup {
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, fmt.Sprintf("duplicate task id %s", task.Key), nil)
+			return resp
+		}
+		tasks[task.Key] = task
+		plan.Steps[i] = protocol.PlanStep{
+			Key:         task.Key,
+			Description: task.Description,
+			DependsOn:   task.DependsOn,
+		}
+	}
+
+	steps, err := planOrder(plan)
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, err.Error(), nil)
+		return resp
+	}
+
+	ids := make(map[string]string, len(steps))
+	for _, step := range steps {
+		ids[step.Key] = uuid.New().String()
+	}
+
+	result := protocol.JobImportResult{DryRun: params.DryRun}
+	jobParams := make([]protocol.JobAddParams, len(steps))
+	sections := make(map[string]int)
+	for i, step := range steps {
+		task := tasks[step.Key]
+
+		deps := make([]string, 0, len(task.DependsOn))
+		for _, dep := range task.DependsOn {
+			if id, ok := ids[dep]; ok {
+				deps = append(deps, id)
+				continue
+			}
+			if _, ok := s.jobs.Get(dep); !ok {
+				resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams,
+					fmt.Sprintf("%s depends on unknown job %s", task.Key, dep), nil)
+				return resp
+			}
+			deps = append(deps, dep)
+		}
+
+		jobParams[i] = protocol.JobAddParams{
+			Description: task.Description,
+			Body:        task.Body,
+			Priority:    task.Priority,
+			Labels:      task.Labels,
+			DependsOn:   deps,
+			Requester:   params.Requester,
+		}
+
+		imported := protocol.ImportedJob{
+			Key:         task.Key,
+			Description: task.Description,
+			Priority:    task.Priority,
+			Section:     task.Section,
+			DependsOn:   task.DependsOn,
+		}
+		if !params.DryRun {
+			imported.ID = ids[task.Key]
+		}
+		result.Jobs = append(result.Jobs, imported)
+
+		if task.Section == "" {
+			continue
+		}
+		if _, ok := sections[task.Section]; !ok {
+			sections[task.Section] = len(result.Operations)
+			result.Operations = append(result.Operations, protocol.ImportedOperation{Name: task.Section})
+		}
+		result.Operations[sections[task.Section]].Jobs++
+	}
+
+	if params.DryRun {
+		resp, _ := protocol.NewResponse(req.ID, result)
+		return resp
+	}
+	if err := s.checkAcceptingJobs(); err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, err.Error(), nil)
+		return resp
+	}
+
+	description := "Imported task list"
+	if params.Source != "" {
+		description = "Imported from " + params.Source
+	}
+	ops := make([]*job.Operation, len(result.Operations))
+	for i := range result.Operations {
+		ops[i] = job.NewOperation(result.Operations[i].Name)
+		ops[i].Description = description
+		result.Operations[i].ID = ops[i].ID
+	}
+
+	for i, step := range steps {
+		j, err := s.addJob(jobParams[i], ids[step.Key])
+		if err != nil {
+			// Only reachable if validation above missed something
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError,
+				fmt.Sprintf("failed to create %s: %v", step.Key, err), nil)
+			return resp
+		}
+		if section := tasks[step.Key].Section; section != "" {
+			op := ops[sections[section]]
+			j.Operation = op.ID
+			s.jobs.Save(j)
+			op.AddJob(j.ID)
+		}
+	}
+
+	for _, op := range ops {
+		s.operations.Add(op)
+		op.Start()
+	}
+
+	s.ledger.Append(ledger.EventType("job.imported"), map[string]interface{}{
+		"source":     params.Source,
+		"jobs":       ids,
+		"operations": len(ops),
+	})
+
+	resp, _ := protocol.NewResponse(req.ID, result)
+	return resp
+}
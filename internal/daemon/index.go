@@ -0,0 +1,165 @@
+package daemon
+
+import (
+	"time"
+
+	"cosa/internal/git"
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+	"cosa/internal/repoindex"
+	"cosa/internal/territory"
+	"cosa/internal/worker"
+)
+
+// EventTerritoryIndexed is logged when the repository index is rebuilt.
+const EventTerritoryIndexed ledger.EventType = "territory.indexed"
+
+// startIndexer periodically rebuilds the repository index when the
+// territory's HEAD has moved and no worker is busy, so that the rebuild
+// never competes with a job.
+func (s *Server) startIndexer() {
+	interval := s.config().Index.Interval
+	if interval <= 0 {
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		s.refreshIndexWhenIdle()
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+				s.refreshIndexWhenIdle()
+			}
+		}
+	}()
+}
+
+// refreshIndexWhenIdle rebuilds the index if it is out of date and every
+// worker is idle.
+func (s *Server) refreshIndexWhenIdle() {
+	s.mu.RLock()
+	t := s.territory
+	s.mu.RUnlock()
+	if t == nil {
+		return
+	}
+
+	for _, w := range s.pool.List() {
+		if w.GetStatus() != worker.StatusIdle {
+			return
+		}
+	}
+
+	head, err := git.HeadCommit(t.RepoRoot)
+	if err != nil {
+		return
+	}
+	if idx := s.repoIndex.Load(); idx != nil && idx.Commit == head {
+		return
+	}
+
+	s.rebuildIndex(t)
+}
+
+// rebuildIndex indexes the territory's repository and saves the index.
+func (s *Server) rebuildIndex(t *territory.Territory) (*repoindex.Index, time.Duration, error) {
+	s.indexMu.Lock()
+	defer s.indexMu.Unlock()
+
+	start := time.Now()
+	idx, err := repoindex.Build(t.RepoRoot)
+	if err != nil {
+		s.log.Warn("failed to index repository", "path", t.RepoRoot, "error", err)
+		return nil, 0, err
+	}
+	took := time.Since(start)
+
+	if err := idx.Save(t.IndexPath()); err != nil {
+		s.log.Warn("failed to save repository index", "path", t.IndexPath(), "error", err)
+	}
+	s.repoIndex.Store(idx)
+
+	s.ledger.Append(EventTerritoryIndexed, map[string]interface{}{
+		"commit":   idx.Commit,
+		"files":    idx.Files,
+		"dirs":     len(idx.Dirs),
+		"duration": took.Round(time.Millisecond).String(),
+	})
+	return idx, took, nil
+}
+
+// loadRepoIndex loads t's saved index, so that jobs have a map from the
+// start rather than after the first rebuild.
+func (s *Server) loadRepoIndex(t *territory.Territory) {
+	idx, err := repoindex.Load(t.IndexPath())
+	if err != nil {
+		s.log.Warn("failed to load repository index", "path", t.IndexPath(), "error", err)
+	}
+	s.repoIndex.Store(idx)
+}
+
+// repoMap returns the map of the repository included in job prompts, or ""
+// before the first index or when index.prompt_bytes is 0.
+func (s *Server) repoMap() string {
+	idx := s.repoIndex.Load()
+	if idx == nil || s.config().Index.PromptBytes <= 0 {
+		return ""
+	}
+	return idx.Compact(s.config().Index.PromptBytes)
+}
+
+// relevantFiles returns the files in workdir likely relevant to j, for its
+// prompt, or nil when claude.context is disabled. It is the
+// worker.ContextSource of every worker.
+func (s *Server) relevantFiles(j *job.Job, workdir string) []string {
+	ctx := s.jobConfig().Claude.Context
+	if !ctx.Enabled {
+		return nil
+	}
+	files, err := repoindex.RelevantFiles(workdir, j.Description+"\n"+j.GetBody(), ctx.MaxFiles)
+	if err != nil {
+		s.log.Warn("failed to gather relevant files", "job", j.ID, "error", err)
+		return nil
+	}
+	return files
+}
+
+// handleTerritoryIndex rebuilds the index now, whether or not workers are
+// busy.
+func (s *Server) handleTerritoryIndex(req *protocol.Request) *protocol.Response {
+	s.mu.RLock()
+	t := s.territory
+	s.mu.RUnlock()
+
+	if t == nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, "territory not initialized", nil)
+		return resp
+	}
+
+	idx, took, err := s.rebuildIndex(t)
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, err.Error(), nil)
+		return resp
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, protocol.TerritoryIndexResult{
+		Path:     t.IndexPath(),
+		Commit:   idx.Commit,
+		Files:    idx.Files,
+		Bytes:    idx.Bytes,
+		Dirs:     len(idx.Dirs),
+		Modules:  len(idx.Modules),
+		Duration: took.Round(time.Millisecond).String(),
+		Map:      s.repoMap(),
+	})
+	return resp
+}
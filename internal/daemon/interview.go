@@ -0,0 +1,146 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"cosa/internal/claude"
+	"cosa/internal/ledger"
+	"cosa/internal/territory"
+	"cosa/internal/worker"
+)
+
+// exitInterviewPrompt asks a departing worker to distill what it learned.
+const exitInterviewPrompt = `You are being removed from this project. Before you go, write a short
+"lessons learned" note for the workers who will continue here.
+
+Cover, as concise markdown bullet points:
+- Conventions you discovered (code style, naming, structure, tooling)
+- Pitfalls or surprises and how to avoid them
+- Useful commands for building, testing or running the project
+
+Only include what is specific to this codebase. Do not use any tools and do
+not make any changes; reply with the note only.`
+
+// knowledgePromptBytes caps how much of the knowledge base goes into a
+// prompt. The newest lessons are kept.
+const knowledgePromptBytes = 8 * 1024
+
+// knowledge returns the territory knowledge base for job prompts, or "" if
+// there is none. It is the worker.KnowledgeSource of every worker.
+func (s *Server) knowledge() string {
+	s.mu.RLock()
+	t := s.territory
+	s.mu.RUnlock()
+	if t == nil {
+		return ""
+	}
+
+	data, err := os.ReadFile(t.KnowledgePath())
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			s.log.Warn("failed to read knowledge base", "error", err)
+		}
+		return ""
+	}
+	return newestKnowledge(string(data), knowledgePromptBytes)
+}
+
+// newestKnowledge returns the newest sections of knowledge that fit in limit
+// bytes. Sections are appended, so the newest come last.
+func newestKnowledge(knowledge string, limit int) string {
+	knowledge = strings.TrimSpace(knowledge)
+	if len(knowledge) <= limit {
+		return knowledge
+	}
+	tail := knowledge[len(knowledge)-limit:]
+	if i := strings.Index(tail, "\n## "); i >= 0 {
+		return tail[i+1:]
+	}
+	return ""
+}
+
+// interviewAndRemoveWorktree holds the exit interview of a removed worker,
+// adds its learnings to the territory knowledge base and then removes its
+// worktree. The outcome is recorded in the ledger as worker.interviewed or
+// worker.interview_failed.
+func (s *Server) interviewAndRemoveWorktree(t *territory.Territory, w *worker.Worker, force bool) {
+	learnings, err := s.exitInterview(w)
+	if err == nil && t != nil {
+		err = t.AppendKnowledge(w.Name, learnings)
+	}
+
+	if t != nil {
+		t.RemoveWorkerWorktree(w.Name, force)
+	}
+
+	if err != nil {
+		s.ledger.Append(ledger.EventType("worker.interview_failed"), ledger.WorkerEventData{
+			ID:    w.ID,
+			Name:  w.Name,
+			Error: err.Error(),
+		})
+		return
+	}
+	s.ledger.Append(ledger.EventType("worker.interviewed"), ledger.WorkerEventData{
+		ID:        w.ID,
+		Name:      w.Name,
+		Learnings: learnings,
+	})
+}
+
+// exitInterview resumes a worker's session and asks it to summarize lessons
+// learned. It must run before the worker's worktree is removed.
+func (s *Server) exitInterview(w *worker.Worker) (string, error) {
+	if w.SessionID == "" {
+		return "", fmt.Errorf("worker has no session history")
+	}
+
+	timeout := s.config().Claude.ChatTimeout
+	if timeout <= 0 {
+		timeout = 120
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	client := claude.NewClient(claude.ClientConfig{
+		Binary:   s.config().Claude.Binary,
+		Model:    s.jobConfig().Claude.Model,
+		MaxTurns: 1,
+		Workdir:  w.Worktree,
+	})
+	if err := client.Resume(ctx, w.SessionID, exitInterviewPrompt); err != nil {
+		return "", fmt.Errorf("failed to start claude: %w", err)
+	}
+
+	var learnings strings.Builder
+	for {
+		select {
+		case <-ctx.Done():
+			client.Stop()
+			return "", fmt.Errorf("exit interview timed out")
+
+		case <-client.Done():
+			return strings.TrimSpace(learnings.String()), nil
+
+		case event, ok := <-client.Events():
+			if !ok {
+				return strings.TrimSpace(learnings.String()), nil
+			}
+
+			switch event.Type {
+			case claude.EventAssistantText:
+				learnings.WriteString(event.Message)
+			case claude.EventError:
+				if event.Error != "" {
+					return "", fmt.Errorf("claude error: %s", event.Error)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package daemon
+
+import (
+	"encoding/json"
+	"strings"
+
+	"cosa/internal/display"
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+)
+
+// planReady keeps the plan a plan-first job's plan phase ended with and
+// holds the job for the boss to approve it. The worktree is kept for the
+// execute phase.
+func (s *Server) planReady(j *job.Job) {
+	plan := strings.TrimSpace(j.GetSummary())
+	if plan == "" {
+		plan = strings.TrimSpace(j.GetOutput())
+	}
+	j.SetPlan(plan)
+
+	s.ledger.Append(ledger.EventType("job.plan_ready"), ledger.JobEventData{
+		ID:          j.ID,
+		Description: j.Description,
+		Worker:      j.Worker,
+		Model:       j.GetModelUsed(),
+	})
+	s.flagForAttention(j, job.AttentionPlanApproval, "plan ready for approval; approve it with 'cosa job approve "+display.ShortID(j.ID)+"'")
+}
+
+// approvePlan approves a job's plan and queues the job to execute it.
+func (s *Server) approvePlan(j *job.Job) error {
+	if err := j.ApprovePlan(); err != nil {
+		return err
+	}
+	s.queue.Enqueue(j)
+	s.jobs.Save(j)
+
+	s.ledger.Append(ledger.EventType("job.plan_approved"), ledger.JobEventData{
+		ID:          j.ID,
+		Description: j.Description,
+	})
+	return nil
+}
+
+// holdDraft holds a new job as a draft until it is approved, and tells the
+// boss about it.
+func (s *Server) holdDraft(j *job.Job) error {
+	if err := j.HoldAsDraft(); err != nil {
+		return err
+	}
+	s.jobs.Save(j)
+
+	s.ledger.Append(ledger.EventType("job.drafted"), ledger.JobEventData{
+		ID:          j.ID,
+		Description: j.Description,
+	})
+	s.notifier.NotifyNeedsAttention(j.ID, j.Description, job.AttentionDraft,
+		"draft waiting for approval; approve it with 'cosa job approve "+display.ShortID(j.ID)+"'", j.Priority)
+	return nil
+}
+
+// approveDraft approves a job held as a draft and queues it.
+func (s *Server) approveDraft(j *job.Job) error {
+	if err := j.ApproveDraft(); err != nil {
+		return err
+	}
+	s.queue.Enqueue(j)
+	s.jobs.Save(j)
+
+	s.ledger.Append(ledger.EventType("job.draft_approved"), ledger.JobEventData{
+		ID:          j.ID,
+		Description: j.Description,
+	})
+	return nil
+}
+
+// approveJob approves what a job is waiting on approval for: its plan or,
+// for a draft, the job itself.
+func (s *Server) approveJob(j *job.Job) (string, error) {
+	if j.IsDraft() {
+		return "draft approved", s.approveDraft(j)
+	}
+	return "plan approved", s.approvePlan(j)
+}
+
+func (s *Server) handleJobApprove(req *protocol.Request) *protocol.Response {
+	var params protocol.JobApproveParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	j, exists := s.jobs.Get(params.ID)
+	if !exists {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrJobNotFound, "job not found", nil)
+		return resp
+	}
+
+	if _, err := s.approveJob(j); err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, err.Error(), nil)
+		return resp
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, map[string]string{"status": string(j.GetStatus())})
+	return resp
+}
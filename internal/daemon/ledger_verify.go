@@ -0,0 +1,73 @@
+package daemon
+
+import (
+	"encoding/json"
+
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+)
+
+// CheckLedger verifies the ledger l writes to and, with repair, quarantines
+// its bad lines. The daemon checks its own ledger; the CLI opens the ledger
+// itself when the daemon is not running.
+func CheckLedger(l *ledger.Ledger, repair bool) (protocol.LedgerVerifyResult, error) {
+	var report *ledger.Report
+	var result protocol.LedgerVerifyResult
+	if repair {
+		repaired, err := l.Repair()
+		if err != nil {
+			return result, err
+		}
+		report = repaired.Report
+		result.Quarantined = repaired.Quarantined
+		result.QuarantinePath = repaired.QuarantinePath
+	} else {
+		var err error
+		if report, err = ledger.Verify(l.Path()); err != nil {
+			return result, err
+		}
+	}
+
+	result.Path = report.Path
+	result.Size = report.Size
+	result.Lines = report.Lines
+	result.Events = report.Events
+	result.Healthy = report.Healthy()
+	if !report.First.IsZero() {
+		result.First = report.First.Unix()
+		result.Last = report.Last.Unix()
+	}
+	for _, p := range report.Problems {
+		result.Problems = append(result.Problems, protocol.LedgerProblem{
+			Line:   p.Line,
+			Kind:   p.Kind,
+			Detail: p.Detail,
+		})
+	}
+	return result, nil
+}
+
+// handleLedgerVerify checks the ledger for damage, optionally repairing it.
+func (s *Server) handleLedgerVerify(req *protocol.Request) *protocol.Response {
+	var params protocol.LedgerVerifyParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	result, err := CheckLedger(s.ledger, params.Repair)
+	if err != nil {
+		s.log.Error("ledger verification failed", "repair", params.Repair, "error", err)
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, err.Error(), nil)
+		return resp
+	}
+	if result.Quarantined > 0 {
+		s.log.Warn("ledger repaired", "quarantined", result.Quarantined, "quarantine", result.QuarantinePath)
+		s.ledger.Append(ledger.EventType("ledger.repaired"), map[string]interface{}{
+			"quarantined":     result.Quarantined,
+			"quarantine_path": result.QuarantinePath,
+		})
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, result)
+	return resp
+}
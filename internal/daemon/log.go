@@ -0,0 +1,42 @@
+package daemon
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"cosa/internal/config"
+	"cosa/internal/logging"
+	"cosa/internal/protocol"
+)
+
+// openDaemonLog opens the daemon's operational log at the configured level.
+// If the file cannot be opened the daemon logs to stderr instead.
+func openDaemonLog(cfg *config.Config) (*slog.Logger, io.Closer) {
+	level, err := logging.ParseLevel(cfg.LogLevel)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v, using info\n", err)
+	}
+
+	f, err := logging.OpenRotating(cfg.DaemonLogPath(), int64(cfg.Daemon.LogMaxSize)<<20, cfg.Daemon.LogMaxFiles)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to open daemon log, logging to stderr: %v\n", err)
+		return logging.New(os.Stderr, level), io.NopCloser(nil)
+	}
+	return logging.New(f, level), f
+}
+
+// logRequest records a handled request at debug level, and requests that
+// failed inside the daemon as errors.
+func (s *Server) logRequest(req *protocol.Request, resp *protocol.Response) {
+	if resp != nil && resp.Error != nil && resp.Error.Code == protocol.InternalError {
+		s.log.Error("request failed", "method", req.Method, "error", resp.Error.Message)
+		return
+	}
+	if resp != nil && resp.Error != nil {
+		s.log.Debug("request rejected", "method", req.Method, "code", resp.Error.Code, "error", resp.Error.Message)
+		return
+	}
+	s.log.Debug("request", "method", req.Method)
+}
@@ -0,0 +1,43 @@
+package daemon
+
+import (
+	"fmt"
+	"regexp"
+
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+)
+
+// LogFilterQuery turns a log filter into the ledger query that applies it.
+// The daemon uses it for filtered subscriptions and clients for filtering
+// events they have read.
+func LogFilterQuery(f *protocol.LogFilter) (ledger.Query, error) {
+	var q ledger.Query
+	if f == nil {
+		return q, nil
+	}
+
+	for _, t := range f.Types {
+		q.Types = append(q.Types, ledger.EventType(t))
+	}
+	for _, t := range f.Exclude {
+		q.Exclude = append(q.Exclude, ledger.EventType(t))
+	}
+	q.Worker = f.Worker
+	q.Job = f.Job
+	if f.Level != "" {
+		level, err := ledger.ParseSeverity(f.Level)
+		if err != nil {
+			return q, err
+		}
+		q.Level = level
+	}
+	if f.Grep != "" {
+		re, err := regexp.Compile(f.Grep)
+		if err != nil {
+			return q, fmt.Errorf("invalid pattern: %w", err)
+		}
+		q.Grep = re
+	}
+	return q, nil
+}
@@ -1,6 +1,7 @@
 package daemon
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"cosa/internal/job"
@@ -13,6 +14,8 @@ type MCPAdapter struct {
 	server *Server
 }
 
+var _ mcp.DaemonInterface = (*MCPAdapter)(nil)
+
 // NewMCPAdapter creates a new MCP adapter for the daemon server.
 func NewMCPAdapter(server *Server) *MCPAdapter {
 	return &MCPAdapter{server: server}
@@ -24,16 +27,20 @@ func (a *MCPAdapter) ListWorkers() []protocol.WorkerInfo {
 	workers := make([]protocol.WorkerInfo, 0, len(poolWorkers))
 	for _, w := range poolWorkers {
 		info := protocol.WorkerInfo{
-			ID:       w.ID,
-			Name:     w.Name,
-			Role:     string(w.Role),
-			Status:   string(w.GetStatus()),
-			Worktree: w.Worktree,
+			ID:          w.ID,
+			Name:        w.Name,
+			Role:        string(w.Role),
+			Status:      string(w.GetStatus()),
+			Worktree:    w.Worktree,
+			Quarantined: w.GetQuarantine() != nil,
 		}
 		if j := w.GetCurrentJob(); j != nil {
 			info.CurrentJob = j.ID
 			info.CurrentJobDesc = j.Description
 		}
+		if n := w.SlotCount(); n > 1 {
+			info.Slots, info.BusySlots = n, w.BusySlots()
+		}
 		workers = append(workers, info)
 	}
 	return workers
@@ -63,14 +70,82 @@ func (a *MCPAdapter) GetWorker(name string) (*protocol.WorkerDetailInfo, error)
 		TotalTokens:   w.TotalTokens,
 		CreatedAt:     w.CreatedAt.Unix(),
 	}
+	info.SessionTokens, info.PreviousSessionID = w.SessionStats()
+	info.FailureStreak = w.FailureStreak()
+	info.Quarantine = quarantineInfo(w.GetQuarantine())
+	identity := w.GetIdentity()
+	info.GitAuthor, info.GitSigning = identity.Author(), identity.SigningFormat
 	if j := w.GetCurrentJob(); j != nil {
 		info.CurrentJob = j.ID
 	}
+	info.Slots = a.server.slotDetails(w)
 	return info, nil
 }
 
-// ListJobs returns jobs, optionally filtered by status.
-func (a *MCPAdapter) ListJobs(status string) []protocol.JobInfo {
+// AddWorker adds a new worker.
+func (a *MCPAdapter) AddWorker(name, role string) (*protocol.WorkerInfo, error) {
+	var info protocol.WorkerInfo
+	if err := a.call(protocol.MethodWorkerAdd, protocol.WorkerAddParams{Name: name, Role: role}, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// RemoveWorker removes a worker.
+func (a *MCPAdapter) RemoveWorker(name string, force bool) error {
+	return a.call(protocol.MethodWorkerRemove, protocol.WorkerRemoveParams{Name: name, Force: force}, nil)
+}
+
+// MessageWorker sends a message to a worker.
+func (a *MCPAdapter) MessageWorker(name, message string) error {
+	return a.call(protocol.MethodWorkerMessage, protocol.WorkerMessageParams{Name: name, Message: message}, nil)
+}
+
+// DelegateJob delegates a job from the worker named from.
+func (a *MCPAdapter) DelegateJob(from string, params protocol.JobDelegateParams) (*protocol.JobInfo, error) {
+	params.From = from
+	var info protocol.JobInfo
+	if err := a.call(protocol.MethodJobDelegate, params, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// GenerateHandoff generates a handoff summary for a worker.
+func (a *MCPAdapter) GenerateHandoff(name string) (*protocol.HandoffSummary, error) {
+	var summary protocol.HandoffSummary
+	if err := a.call(protocol.MethodHandoffGenerate, protocol.HandoffGenerateParams{Worker: name}, &summary); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// call dispatches a request through the server's RPC handlers so that
+// worker management goes through the same validation and ledger events
+// as requests from the CLI.
+func (a *MCPAdapter) call(method string, params interface{}, result interface{}) error {
+	// Named apart from the HTTP API, for admission.rate_limit
+	req, err := protocol.NewRequest(protocol.NewStringID("mcp"), method, params)
+	if err != nil {
+		return err
+	}
+
+	resp := a.server.handleRequest(req, nil)
+	if resp == nil {
+		return fmt.Errorf("no response for %s", method)
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("%s", resp.Error.Message)
+	}
+	if result != nil {
+		return json.Unmarshal(resp.Result, result)
+	}
+	return nil
+}
+
+// ListJobs returns jobs, optionally filtered by status and a label
+// selector.
+func (a *MCPAdapter) ListJobs(status string, labels map[string]string) []protocol.JobInfo {
 	jobs := a.server.jobs.List()
 	infos := make([]protocol.JobInfo, 0, len(jobs))
 
@@ -79,6 +154,9 @@ func (a *MCPAdapter) ListJobs(status string) []protocol.JobInfo {
 		if status != "" && string(j.GetStatus()) != status {
 			continue
 		}
+		if !job.MatchLabels(j.GetLabels(), labels) {
+			continue
+		}
 
 		info := protocol.JobInfo{
 			ID:          j.ID,
@@ -88,6 +166,7 @@ func (a *MCPAdapter) ListJobs(status string) []protocol.JobInfo {
 			Worker:      j.Worker,
 			DependsOn:   j.DependsOn,
 			CreatedAt:   j.CreatedAt.Unix(),
+			Labels:      j.GetLabels(),
 		}
 		if j.StartedAt != nil {
 			info.StartedAt = j.StartedAt.Unix()
@@ -127,43 +206,35 @@ func (a *MCPAdapter) GetJob(id string) (*protocol.JobInfo, error) {
 
 // CreateJob creates a new job.
 func (a *MCPAdapter) CreateJob(description string, priority int, territory string) (*job.Job, error) {
-	j := job.New(description)
-	if priority > 0 {
-		j.SetPriority(priority)
+	var info protocol.JobInfo
+	if err := a.call(protocol.MethodJobAdd, protocol.JobAddParams{Description: description, Priority: priority, Requester: "mcp"}, &info); err != nil {
+		return nil, err
 	}
 
-	// Add to store
-	a.server.jobs.Add(j)
-
-	// Add to queue for scheduler
-	a.server.queue.Enqueue(j)
-
+	j, exists := a.server.jobs.Get(info.ID)
+	if !exists {
+		return nil, fmt.Errorf("job not found: %s", info.ID)
+	}
 	return j, nil
 }
 
 // CancelJob cancels a job.
 func (a *MCPAdapter) CancelJob(id string) error {
-	j, exists := a.server.jobs.Get(id)
-	if !exists {
-		return fmt.Errorf("job not found: %s", id)
-	}
+	return a.call(protocol.MethodJobCancel, protocol.JobCancelParams{ID: id}, nil)
+}
 
-	// Remove from queue if pending
-	a.server.queue.Remove(j.ID)
-	j.Cancel()
-	return nil
+// ApproveJob approves a draft or a job's plan.
+func (a *MCPAdapter) ApproveJob(id string) (string, error) {
+	var result map[string]string
+	if err := a.call(protocol.MethodJobApprove, protocol.JobApproveParams{ID: id}, &result); err != nil {
+		return "", err
+	}
+	return result["status"], nil
 }
 
 // SetJobPriority updates a job's priority.
 func (a *MCPAdapter) SetJobPriority(id string, priority int) error {
-	j, exists := a.server.jobs.Get(id)
-	if !exists {
-		return fmt.Errorf("job not found: %s", id)
-	}
-
-	j.SetPriority(priority)
-	a.server.jobs.Save(j)
-	return nil
+	return a.call(protocol.MethodJobSetPriority, protocol.JobSetPriorityParams{JobID: id, Priority: priority}, nil)
 }
 
 // ListActivity returns recent activity entries.
@@ -175,12 +246,18 @@ func (a *MCPAdapter) ListActivity(limit int) []mcp.ActivityEntry {
 
 // GetQueueStatus returns the current queue status.
 func (a *MCPAdapter) GetQueueStatus() *protocol.QueueStatusResult {
-	return &protocol.QueueStatusResult{
+	status := &protocol.QueueStatusResult{
 		Ready:   a.server.queue.ReadyLen(),
 		Pending: a.server.queue.PendingLen(),
 		Running: a.server.jobs.CountByStatus(job.StatusRunning),
 		Total:   a.server.jobs.Count(),
 	}
+	if hold := a.server.queueControl.Get(); hold.State != job.QueueRunning {
+		info := queueHoldInfo(hold)
+		status.Hold = &info
+	}
+	status.ETA, status.Jobs = a.server.queueForecast()
+	return status
 }
 
 // ListTerritories returns all territories.
@@ -259,6 +336,33 @@ func (a *MCPAdapter) GetCosts() *mcp.CostSummary {
 	}
 }
 
+// GetChatPlan returns the chat's mode and draft operation.
+func (a *MCPAdapter) GetChatPlan() (*protocol.ChatPlanResult, error) {
+	var result protocol.ChatPlanResult
+	if err := a.call(protocol.MethodChatPlan, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// AddPlanStep adds a job to the chat's draft operation.
+func (a *MCPAdapter) AddPlanStep(step protocol.PlanStep) (*protocol.ChatPlanResult, error) {
+	var result protocol.ChatPlanResult
+	if err := a.call(protocol.MethodChatPlanAdd, step, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RemovePlanStep removes a job from the chat's draft operation.
+func (a *MCPAdapter) RemovePlanStep(key string) (*protocol.ChatPlanResult, error) {
+	var result protocol.ChatPlanResult
+	if err := a.call(protocol.MethodChatPlanRemove, protocol.ChatPlanRemoveParams{Key: key}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 // GetServer returns the underlying server for MCP CLI command.
 func (a *MCPAdapter) GetServer() *Server {
 	return a.server
@@ -0,0 +1,143 @@
+package daemon
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"cosa/internal/protocol"
+)
+
+// mergeQueue serializes merges into each target branch, so that jobs
+// completing together take turns instead of merging over each other.
+// Merges into one branch run in the order they arrived.
+type mergeQueue struct {
+	mu    sync.Mutex
+	lanes map[string]*mergeLane
+}
+
+// mergeLane is the merge running into one target branch and those waiting
+// for it to finish.
+type mergeLane struct {
+	merging *mergeTurn
+	waiting []*mergeTurn
+}
+
+// mergeTurn is one job's place in a lane.
+type mergeTurn struct {
+	jobID     string
+	branch    string
+	queuedAt  time.Time
+	startedAt time.Time
+	ready     chan struct{} // Closed when the turn starts
+}
+
+// enter waits for the job's turn to merge into target. It reports whether
+// another merge into target went first while waiting, in which case the
+// target has moved on since the job's branch was last compared with it.
+// The caller must call leave when done.
+func (q *mergeQueue) enter(target, jobID, branch string) (*mergeTurn, bool) {
+	turn := &mergeTurn{
+		jobID:    jobID,
+		branch:   branch,
+		queuedAt: time.Now(),
+		ready:    make(chan struct{}),
+	}
+
+	q.mu.Lock()
+	if q.lanes == nil {
+		q.lanes = make(map[string]*mergeLane)
+	}
+	lane, ok := q.lanes[target]
+	if !ok {
+		lane = &mergeLane{}
+		q.lanes[target] = lane
+	}
+	if lane.merging == nil {
+		turn.startedAt = turn.queuedAt
+		lane.merging = turn
+		q.mu.Unlock()
+		return turn, false
+	}
+	lane.waiting = append(lane.waiting, turn)
+	q.mu.Unlock()
+
+	<-turn.ready
+	return turn, true
+}
+
+// leave ends a turn and starts the next one waiting on target.
+func (q *mergeQueue) leave(target string, turn *mergeTurn) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	lane, ok := q.lanes[target]
+	if !ok || lane.merging != turn {
+		return
+	}
+	if len(lane.waiting) == 0 {
+		delete(q.lanes, target)
+		return
+	}
+
+	next := lane.waiting[0]
+	lane.waiting = lane.waiting[1:]
+	next.startedAt = time.Now()
+	lane.merging = next
+	close(next.ready)
+}
+
+// depth returns the number of merges running or waiting.
+func (q *mergeQueue) depth() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	n := 0
+	for _, lane := range q.lanes {
+		n += 1 + len(lane.waiting)
+	}
+	return n
+}
+
+// snapshot describes every lane, ordered by target branch.
+func (q *mergeQueue) snapshot() []protocol.MergeLaneInfo {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	lanes := make([]protocol.MergeLaneInfo, 0, len(q.lanes))
+	for target, lane := range q.lanes {
+		info := protocol.MergeLaneInfo{
+			Target:  target,
+			Waiting: make([]protocol.MergeQueueEntry, 0, len(lane.waiting)),
+		}
+		if lane.merging != nil {
+			entry := lane.merging.info()
+			info.Merging = &entry
+		}
+		for _, turn := range lane.waiting {
+			info.Waiting = append(info.Waiting, turn.info())
+		}
+		lanes = append(lanes, info)
+	}
+	sort.Slice(lanes, func(i, j int) bool { return lanes[i].Target < lanes[j].Target })
+	return lanes
+}
+
+func (t *mergeTurn) info() protocol.MergeQueueEntry {
+	entry := protocol.MergeQueueEntry{
+		JobID:    t.jobID,
+		Branch:   t.branch,
+		QueuedAt: t.queuedAt.Unix(),
+	}
+	if !t.startedAt.IsZero() {
+		entry.StartedAt = t.startedAt.Unix()
+	}
+	return entry
+}
+
+func (s *Server) handleMergeQueue(req *protocol.Request) *protocol.Response {
+	resp, _ := protocol.NewResponse(req.ID, protocol.MergeQueueResult{
+		Lanes: s.merges.snapshot(),
+	})
+	return resp
+}
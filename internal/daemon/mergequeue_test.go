@@ -0,0 +1,186 @@
+package daemon
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cosa/internal/config"
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+	"cosa/internal/territory"
+)
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %s: %v", args, out, err)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// commitOnBranch commits a new file on a new branch off main.
+func commitOnBranch(t *testing.T, dir, branch, file string) {
+	t.Helper()
+	runGit(t, dir, "checkout", "-q", "-b", branch, "main")
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(branch+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", file)
+	runGit(t, dir, "commit", "-q", "-m", "Work on "+branch)
+	runGit(t, dir, "checkout", "-q", "main")
+}
+
+// waitForDepth waits until n merges are running or waiting.
+func waitForDepth(t *testing.T, q *mergeQueue, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for q.depth() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d merges queued, got %d", n, q.depth())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestMergeQueue_Order(t *testing.T) {
+	var q mergeQueue
+
+	first, waited := q.enter("main", "job-1", "cosa/job-1")
+	if waited {
+		t.Error("expected the first merge not to wait")
+	}
+
+	started := make(chan string, 2)
+	for _, id := range []string{"job-2", "job-3"} {
+		go func() {
+			turn, waited := q.enter("main", id, "cosa/"+id)
+			if !waited {
+				t.Errorf("expected %s to wait", id)
+			}
+			started <- id
+			q.leave("main", turn)
+		}()
+		waitForDepth(t, &q, map[string]int{"job-2": 2, "job-3": 3}[id])
+	}
+
+	// Another target has a lane of its own
+	other, waited := q.enter("develop", "job-4", "cosa/job-4")
+	if waited {
+		t.Error("expected a merge into another branch not to wait")
+	}
+	q.leave("develop", other)
+
+	select {
+	case id := <-started:
+		t.Fatalf("expected %s to wait for the running merge", id)
+	default:
+	}
+
+	q.leave("main", first)
+	for _, want := range []string{"job-2", "job-3"} {
+		if got := <-started; got != want {
+			t.Errorf("expected %s to merge next, got %s", want, got)
+		}
+	}
+	waitForDepth(t, &q, 0)
+}
+
+// newRepoServer returns a server for a territory in a new repository with
+// one commit on main, and the repository's path.
+func newRepoServer(t *testing.T) (*Server, string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Setenv("HOME", t.TempDir())
+
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q", "-b", "main")
+	runGit(t, dir, "config", "user.name", "test")
+	runGit(t, dir, "config", "user.email", "test@example.com")
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, "commit", "-q", "-m", "Initial commit")
+
+	terr, err := territory.Init(dir, nil)
+	if err != nil {
+		t.Fatalf("failed to init territory: %v", err)
+	}
+	l, err := ledger.Open(filepath.Join(t.TempDir(), "ledger.jsonl"))
+	if err != nil {
+		t.Fatalf("failed to open ledger: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	jobs := job.NewStore()
+	return &Server{
+		cfg:       config.DefaultConfig(),
+		territory: terr,
+		jobs:      jobs,
+		queue:     job.NewQueue(jobs),
+		ledger:    l,
+		log:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}, dir
+}
+
+func TestMergeJobBranch_Concurrent(t *testing.T) {
+	s, dir := newRepoServer(t)
+
+	var merging []*job.Job
+	for _, name := range []string{"a", "b"} {
+		branch := "cosa/job-" + name
+		commitOnBranch(t, dir, branch, name+".txt")
+		j := job.New("Add " + name)
+		j.SetWorktree("", branch)
+		s.jobs.Add(j)
+		merging = append(merging, j)
+	}
+
+	// Hold the lane so both merges queue up behind it, a before b
+	hold, _ := s.merges.enter("main", "hold", "")
+	errs := make(chan error, len(merging))
+	for i, j := range merging {
+		go func() {
+			_, err := s.mergeJobBranch(j, false)
+			errs <- err
+		}()
+		waitForDepth(t, &s.merges, i+2)
+	}
+	s.merges.leave("main", hold)
+
+	for range merging {
+		select {
+		case err := <-errs:
+			if err != nil {
+				t.Errorf("merge failed: %v", err)
+			}
+		case <-time.After(30 * time.Second):
+			t.Fatal("merges did not finish")
+		}
+	}
+
+	log := runGit(t, dir, "log", "--first-parent", "--format=%s", "main")
+	want := "Merge branch 'cosa/job-b'\nMerge branch 'cosa/job-a'\nInitial commit"
+	if log != want {
+		t.Errorf("expected the merges in the order they queued, got:\n%s", log)
+	}
+	for _, file := range []string{"a.txt", "b.txt"} {
+		if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+			t.Errorf("expected %s on main: %v", file, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git", "index.lock")); err == nil {
+		t.Error("expected no index.lock left behind")
+	}
+}
@@ -0,0 +1,166 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+
+	"cosa/internal/config"
+	"cosa/internal/protocol"
+	"cosa/internal/territory"
+	"cosa/internal/worker"
+)
+
+// Standing orders come from three scopes, merged into a worker's prompt
+// from the least to the most specific: the global orders in the config,
+// the territory's orders for the worker's role, and the worker's own.
+
+// globalOrders returns the standing orders every worker is given.
+func (s *Server) globalOrders() []string {
+	return append([]string(nil), s.config().Workers.StandingOrders...)
+}
+
+// inheritedOrders returns the global and role standing orders for workers
+// of role. It is the worker.OrderSource of every worker.
+func (s *Server) inheritedOrders(role worker.Role) []string {
+	global := s.globalOrders()
+
+	s.mu.RLock()
+	t := s.territory
+	s.mu.RUnlock()
+	if t == nil {
+		return global
+	}
+	return worker.MergeOrders(global, t.RoleOrders(string(role)))
+}
+
+// fallbackModel returns the model claude.fallback switches model to when it
+// is out of capacity. It is the worker.FallbackSource of every worker.
+func (s *Server) fallbackModel(model string) string {
+	return s.config().Claude.Fallback[model]
+}
+
+// defaultModel returns the model jobs run on unless they set their own. It
+// is the worker.ModelSource of every worker, so a reloaded claude.model
+// applies to running workers.
+func (s *Server) defaultModel() string {
+	return s.jobConfig().Claude.Model
+}
+
+// setGlobalOrders replaces the global standing orders and saves them to the
+// config file. The file is reloaded first so that settings changed since the
+// daemon started are kept.
+func (s *Server) setGlobalOrders(orders []string) error {
+	s.cfgMu.Lock()
+	defer s.cfgMu.Unlock()
+
+	fileCfg, err := config.LoadProfile(s.cfg.Profile)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	fileCfg.Workers.StandingOrders = orders
+	if err := fileCfg.Save(config.ProfileConfigPath(s.cfg.Profile)); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	cfg := *s.cfg
+	cfg.Workers.StandingOrders = orders
+	s.cfg = &cfg
+	return nil
+}
+
+// orderTerritory returns the territory for role orders.
+func (s *Server) orderTerritory() (*territory.Territory, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.territory == nil {
+		return nil, fmt.Errorf("territory not initialized")
+	}
+	return s.territory, nil
+}
+
+// setOrders replaces the standing orders of scope and responds with the
+// orders now in effect for it.
+func (s *Server) setOrders(req *protocol.Request, scope protocol.OrderScope, orders []string) *protocol.Response {
+	var err error
+	code := protocol.InternalError
+	switch {
+	case scope.Global:
+		err = s.setGlobalOrders(orders)
+	case scope.Role != "":
+		if !worker.IsValidRole(worker.Role(scope.Role)) {
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, fmt.Sprintf("unknown role: %s", scope.Role), nil)
+			return resp
+		}
+		var t *territory.Territory
+		if t, err = s.orderTerritory(); err != nil {
+			code = protocol.ErrInvalidState
+		} else {
+			err = t.SetRoleOrders(scope.Role, orders)
+		}
+	default:
+		w, exists := s.pool.Get(scope.Worker)
+		if !exists {
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrWorkerNotFound, "Worker not found", nil)
+			return resp
+		}
+		if len(orders) > 0 {
+			w.SetStandingOrders(orders)
+		} else {
+			w.ClearStandingOrders()
+		}
+	}
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, code, err.Error(), nil)
+		return resp
+	}
+
+	result, code, err := s.orderList(scope)
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, code, err.Error(), nil)
+		return resp
+	}
+	resp, _ := protocol.NewResponse(req.ID, result)
+	return resp
+}
+
+// orderList returns the standing orders of scope. A worker's list includes
+// the orders it inherits.
+func (s *Server) orderList(scope protocol.OrderScope) (protocol.OrderListResult, int, error) {
+	switch {
+	case scope.Global:
+		return protocol.OrderListResult{Global: true, Orders: orEmpty(s.globalOrders())}, 0, nil
+
+	case scope.Role != "":
+		if !worker.IsValidRole(worker.Role(scope.Role)) {
+			return protocol.OrderListResult{}, protocol.InvalidParams, fmt.Errorf("unknown role: %s", scope.Role)
+		}
+		t, err := s.orderTerritory()
+		if err != nil {
+			return protocol.OrderListResult{}, protocol.ErrInvalidState, err
+		}
+		return protocol.OrderListResult{Role: scope.Role, Orders: orEmpty(t.RoleOrders(scope.Role))}, 0, nil
+	}
+
+	w, exists := s.pool.Get(scope.Worker)
+	if !exists {
+		return protocol.OrderListResult{}, protocol.ErrWorkerNotFound, errors.New("Worker not found")
+	}
+	result := protocol.OrderListResult{
+		Worker:       w.Name,
+		Role:         string(w.Role),
+		Orders:       orEmpty(w.GetStandingOrders()),
+		GlobalOrders: s.globalOrders(),
+	}
+	if t, err := s.orderTerritory(); err == nil {
+		result.RoleOrders = t.RoleOrders(string(w.Role))
+	}
+	return result, 0, nil
+}
+
+// orEmpty returns orders, or an empty list rather than nil, so that results
+// always carry an orders array.
+func orEmpty(orders []string) []string {
+	if orders == nil {
+		return []string{}
+	}
+	return orders
+}
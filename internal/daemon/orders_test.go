@@ -0,0 +1,39 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"cosa/internal/config"
+)
+
+func TestSetGlobalOrders(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := config.DefaultConfig()
+	cfg.Profile = config.DefaultProfile
+	s := &Server{cfg: cfg}
+
+	done := make(chan error, 1)
+	go func() { done <- s.setGlobalOrders([]string{"Run the tests before committing"}) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("setGlobalOrders failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("setGlobalOrders deadlocked")
+	}
+
+	if got := s.globalOrders(); len(got) != 1 || got[0] != "Run the tests before committing" {
+		t.Errorf("expected the new orders in effect, got %v", got)
+	}
+
+	saved, err := config.LoadProfile(config.DefaultProfile)
+	if err != nil {
+		t.Fatalf("failed to load the saved config: %v", err)
+	}
+	if len(saved.Workers.StandingOrders) != 1 {
+		t.Errorf("expected the orders saved to the config file, got %v", saved.Workers.StandingOrders)
+	}
+}
@@ -0,0 +1,49 @@
+package daemon
+
+import (
+	"math/rand"
+
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+)
+
+// classifyJob labels a completed job with the kind of change it made. It
+// runs before the job's worktree is merged away, and leaves jobs that were
+// given an outcome when they were added alone.
+func (s *Server) classifyJob(j *job.Job) string {
+	if outcome := j.Outcome(); job.ValidOutcome(outcome) {
+		return outcome
+	}
+	if !s.classifier.Enabled() {
+		return ""
+	}
+
+	diff, _ := s.jobWorktreeDiff(j)
+	outcome, by := s.classifier.Classify(s.ctx, j.Description, diff)
+	j.SetOutcome(outcome)
+	s.jobs.Save(j)
+
+	s.ledger.Append(ledger.EventType("job.classified"), map[string]interface{}{
+		"job_id":     j.ID,
+		"outcome":    outcome,
+		"classifier": by,
+	})
+	return outcome
+}
+
+// sampleReview decides whether a job with the given outcome is reviewed,
+// following the review sampling rates. Jobs that are not sampled are
+// recorded in the ledger.
+func (s *Server) sampleReview(j *job.Job, outcome string) bool {
+	rate := s.config().Review.ReviewSampleRate(outcome)
+	if rate >= 1 || rand.Float64() < rate {
+		return true
+	}
+
+	s.ledger.Append(ledger.EventType("review.sampled_out"), map[string]interface{}{
+		"job_id":  j.ID,
+		"outcome": outcome,
+		"rate":    rate,
+	})
+	return false
+}
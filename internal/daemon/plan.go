@@ -0,0 +1,425 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+)
+
+// planKeyPattern limits step keys to short names that are easy to refer to.
+var planKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// Notices prepended to the next message so the underboss knows the mode.
+const (
+	planModeNotice = `[Plan mode is on. Do not start any work: every job you create with ` +
+		`cosa_create_job or cosa_plan_job is added to a draft operation instead. Give each ` +
+		`job a short key, the keys of the jobs it depends on and an estimate. Use ` +
+		`cosa_show_plan to review the draft and cosa_plan_remove_job to drop a job. The ` +
+		`boss creates the whole operation with /execute.]`
+	chatModeNotice = `[Plan mode is off. Jobs you create start right away again.]`
+)
+
+// Mode returns the chat mode.
+func (cs *ChatSession) Mode() string {
+	cs.planMu.Lock()
+	defer cs.planMu.Unlock()
+	return cs.modeLocked()
+}
+
+func (cs *ChatSession) modeLocked() string {
+	if cs.mode == "" {
+		return protocol.ChatModeChat
+	}
+	return cs.mode
+}
+
+// SetMode switches between chat and plan mode. The underboss is told about
+// the change with the next message.
+func (cs *ChatSession) SetMode(mode string) error {
+	if mode != protocol.ChatModeChat && mode != protocol.ChatModePlan {
+		return fmt.Errorf("invalid mode: %s (use chat/plan)", mode)
+	}
+
+	cs.planMu.Lock()
+	defer cs.planMu.Unlock()
+
+	if mode == cs.modeLocked() {
+		return nil
+	}
+	cs.mode = mode
+	if mode == protocol.ChatModePlan {
+		cs.notice = planModeNotice
+	} else {
+		cs.notice = chatModeNotice
+	}
+	return nil
+}
+
+// Plan returns a copy of the draft operation.
+func (cs *ChatSession) Plan() *protocol.ChatPlan {
+	cs.planMu.Lock()
+	defer cs.planMu.Unlock()
+	return cs.planLocked()
+}
+
+func (cs *ChatSession) planLocked() *protocol.ChatPlan {
+	plan := &protocol.ChatPlan{
+		Steps: make([]protocol.PlanStep, len(cs.plan.Steps)),
+	}
+	copy(plan.Steps, cs.plan.Steps)
+	return plan
+}
+
+// AddPlanStep adds a job to the draft operation, or replaces the step with
+// the same key, and returns its key. known reports whether a dependency
+// that is not a step names an existing job.
+func (cs *ChatSession) AddPlanStep(step protocol.PlanStep, known func(id string) bool) (string, error) {
+	if step.Description == "" {
+		return "", fmt.Errorf("description is required")
+	}
+	if step.Priority < 1 || step.Priority > 5 {
+		step.Priority = job.PriorityNormal
+	}
+
+	cs.planMu.Lock()
+	defer cs.planMu.Unlock()
+
+	if step.Key == "" {
+		for n := len(cs.plan.Steps) + 1; ; n++ {
+			key := fmt.Sprintf("step-%d", n)
+			if cs.planStepLocked(key) < 0 {
+				step.Key = key
+				break
+			}
+		}
+	}
+	if !planKeyPattern.MatchString(step.Key) {
+		return "", fmt.Errorf("invalid key %q: use up to 32 lowercase letters, digits, - and _", step.Key)
+	}
+
+	for _, dep := range step.DependsOn {
+		if dep == step.Key {
+			return "", fmt.Errorf("%s cannot depend on itself", step.Key)
+		}
+		if cs.planStepLocked(dep) < 0 && !known(dep) {
+			return "", fmt.Errorf("unknown dependency: %s", dep)
+		}
+	}
+
+	if i := cs.planStepLocked(step.Key); i >= 0 {
+		cs.plan.Steps[i] = step
+	} else {
+		cs.plan.Steps = append(cs.plan.Steps, step)
+	}
+	return step.Key, nil
+}
+
+// RemovePlanStep drops a step from the draft operation, along with the
+// dependencies other steps had on it.
+func (cs *ChatSession) RemovePlanStep(key string) error {
+	cs.planMu.Lock()
+	defer cs.planMu.Unlock()
+
+	i := cs.planStepLocked(key)
+	if i < 0 {
+		return fmt.Errorf("no step %q in the plan", key)
+	}
+	cs.plan.Steps = append(cs.plan.Steps[:i], cs.plan.Steps[i+1:]...)
+
+	for i := range cs.plan.Steps {
+		deps := cs.plan.Steps[i].DependsOn[:0:0]
+		for _, dep := range cs.plan.Steps[i].DependsOn {
+			if dep != key {
+				deps = append(deps, dep)
+			}
+		}
+		cs.plan.Steps[i].DependsOn = deps
+	}
+	return nil
+}
+
+// ClearPlan discards the draft operation and leaves plan mode.
+func (cs *ChatSession) ClearPlan(notice string) {
+	cs.planMu.Lock()
+	defer cs.planMu.Unlock()
+	cs.plan = protocol.ChatPlan{}
+	if cs.mode == protocol.ChatModePlan {
+		cs.mode = protocol.ChatModeChat
+		cs.notice = notice
+	}
+}
+
+func (cs *ChatSession) planStepLocked(key string) int {
+	for i, step := range cs.plan.Steps {
+		if step.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// planOrder returns the plan's steps with every step after the steps it
+// depends on, or an error if the dependencies form a cycle.
+func planOrder(plan *protocol.ChatPlan) ([]protocol.PlanStep, error) {
+	steps := make(map[string]protocol.PlanStep, len(plan.Steps))
+	for _, step := range plan.Steps {
+		steps[step.Key] = step
+	}
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(plan.Steps))
+	order := make([]protocol.PlanStep, 0, len(plan.Steps))
+
+	var visit func(key string) error
+	visit = func(key string) error {
+		switch state[key] {
+		case visiting:
+			return fmt.Errorf("dependency cycle through %s", key)
+		case done:
+			return nil
+		}
+		state[key] = visiting
+		for _, dep := range steps[key].DependsOn {
+			if _, ok := steps[dep]; ok {
+				if err := visit(dep); err != nil {
+					return err
+				}
+			}
+		}
+		state[key] = done
+		order = append(order, steps[key])
+		return nil
+	}
+
+	for _, step := range plan.Steps {
+		if err := visit(step.Key); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// activeChat returns the chat session, or an error response if there is
+// none.
+func (s *Server) activeChat(req *protocol.Request) (*ChatSession, *protocol.Response) {
+	s.mu.RLock()
+	session := s.chatSession
+	s.mu.RUnlock()
+
+	if session == nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, "no active chat session", nil)
+		return nil, resp
+	}
+	return session, nil
+}
+
+func planResult(session *ChatSession, key string) protocol.ChatPlanResult {
+	return protocol.ChatPlanResult{
+		Mode: session.Mode(),
+		Plan: session.Plan(),
+		Key:  key,
+	}
+}
+
+func (s *Server) handleChatMode(req *protocol.Request) *protocol.Response {
+	var params protocol.ChatModeParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	session, errResp := s.activeChat(req)
+	if errResp != nil {
+		return errResp
+	}
+
+	if err := session.SetMode(params.Mode); err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, err.Error(), nil)
+		return resp
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, planResult(session, ""))
+	return resp
+}
+
+func (s *Server) handleChatPlan(req *protocol.Request) *protocol.Response {
+	session, errResp := s.activeChat(req)
+	if errResp != nil {
+		return errResp
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, planResult(session, ""))
+	return resp
+}
+
+func (s *Server) handleChatPlanAdd(req *protocol.Request) *protocol.Response {
+	var params protocol.PlanStep
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	session, errResp := s.activeChat(req)
+	if errResp != nil {
+		return errResp
+	}
+
+	key, err := session.AddPlanStep(params, func(id string) bool {
+		_, ok := s.jobs.Get(id)
+		return ok
+	})
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, err.Error(), nil)
+		return resp
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, planResult(session, key))
+	return resp
+}
+
+func (s *Server) handleChatPlanRemove(req *protocol.Request) *protocol.Response {
+	var params protocol.ChatPlanRemoveParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	session, errResp := s.activeChat(req)
+	if errResp != nil {
+		return errResp
+	}
+
+	if err := session.RemovePlanStep(params.Key); err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, err.Error(), nil)
+		return resp
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, planResult(session, ""))
+	return resp
+}
+
+func (s *Server) handleChatDiscard(req *protocol.Request) *protocol.Response {
+	session, errResp := s.activeChat(req)
+	if errResp != nil {
+		return errResp
+	}
+
+	session.ClearPlan("[The boss discarded the plan. Plan mode is off.]")
+
+	resp, _ := protocol.NewResponse(req.ID, planResult(session, ""))
+	return resp
+}
+
+// handleChatExecute creates the draft operation and all of its jobs.
+func (s *Server) handleChatExecute(req *protocol.Request) *protocol.Response {
+	var params protocol.ChatExecuteParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	session, errResp := s.activeChat(req)
+	if errResp != nil {
+		return errResp
+	}
+
+	plan := session.Plan()
+	if len(plan.Steps) == 0 {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, "the plan has no jobs", nil)
+		return resp
+	}
+
+	name := params.Name
+	if name == "" {
+		name = "Chat plan " + time.Now().Format("2006-01-02 15:04")
+	}
+	op, ids, errResp := s.createPlanOperation(req, plan, name, fmt.Sprintf("Planned in chat %s", session.ID), protocol.JobAddParams{})
+	if errResp != nil {
+		return errResp
+	}
+
+	session.ClearPlan(fmt.Sprintf("[The boss executed the plan as operation %q with %d jobs. Plan mode is off.]", name, len(ids)))
+
+	s.ledger.Append(ledger.EventType("chat.plan_executed"), map[string]interface{}{
+		"session_id": session.ID,
+		"operation":  op.ID,
+		"jobs":       ids,
+	})
+
+	resp, _ := protocol.NewResponse(req.ID, protocol.ChatExecuteResult{
+		Operation: operationToInfo(op),
+		Jobs:      ids,
+	})
+	return resp
+}
+
+// createPlanOperation creates the plan as an operation, with each job after
+// the jobs it depends on, and returns it with the job IDs by step key. The
+// whole plan is validated first, so either every job is created or none.
+// defaults supplies the job fields steps do not have.
+func (s *Server) createPlanOperation(req *protocol.Request, plan *protocol.ChatPlan, name, description string, defaults protocol.JobAddParams) (*job.Operation, map[string]string, *protocol.Response) {
+	steps, err := planOrder(plan)
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, err.Error(), nil)
+		return nil, nil, resp
+	}
+
+	ids := make(map[string]string, len(steps))
+	for _, step := range steps {
+		ids[step.Key] = uuid.New().String()
+	}
+
+	jobParams := make([]protocol.JobAddParams, len(steps))
+	for i, step := range steps {
+		deps := make([]string, 0, len(step.DependsOn))
+		for _, dep := range step.DependsOn {
+			if id, ok := ids[dep]; ok {
+				deps = append(deps, id)
+				continue
+			}
+			if _, ok := s.jobs.Get(dep); !ok {
+				resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams,
+					fmt.Sprintf("%s depends on unknown job %s", step.Key, dep), nil)
+				return nil, nil, resp
+			}
+			deps = append(deps, dep)
+		}
+
+		jobParams[i] = defaults
+		jobParams[i].Description = step.Description
+		jobParams[i].Body = step.Body
+		jobParams[i].Priority = step.Priority
+		jobParams[i].DependsOn = deps
+	}
+
+	if err := s.checkAcceptingJobs(); err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, err.Error(), nil)
+		return nil, nil, resp
+	}
+
+	op := job.NewOperation(name)
+	op.Description = description
+
+	for i, step := range steps {
+		j, err := s.addJob(jobParams[i], ids[step.Key])
+		if err != nil {
+			// Only reachable if validation above missed something
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError,
+				fmt.Sprintf("failed to create %s: %v", step.Key, err), nil)
+			return nil, nil, resp
+		}
+		j.Operation = op.ID
+		s.jobs.Save(j)
+		op.AddJob(j.ID)
+	}
+
+	s.operations.Add(op)
+	op.Start()
+	return op, ids, nil
+}
@@ -0,0 +1,259 @@
+package daemon
+
+import (
+	"fmt"
+	"strings"
+
+	"cosa/internal/config"
+	"cosa/internal/display"
+	"cosa/internal/git"
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+)
+
+// defaultPRTitle is the pull request title when git.pr_title is unset.
+const defaultPRTitle = "{title}"
+
+// defaultPRTemplate is the pull request body when git.pr_template is unset.
+const defaultPRTemplate = `{body}
+{summary}{diffstat}{review}{checklist}{revisions}
+---
+Job {job} by {worker}, branch {branch} into {target}.`
+
+// prTitleWidth is how long a generated pull request title may be.
+const prTitleWidth = 72
+
+// pullRequestProvider returns git.pr_provider, or the provider detected
+// from the remote's URL.
+func (s *Server) pullRequestProvider(gitMgr *git.Manager, remote string) (string, error) {
+	if s.config().Git.PRProvider != "" {
+		return s.config().Git.PRProvider, nil
+	}
+
+	remoteURL, err := gitMgr.RemoteURL(remote)
+	if err != nil {
+		return "", err
+	}
+	provider := git.DetectProvider(remoteURL)
+	if provider == "" {
+		return "", fmt.Errorf("cannot detect pull request provider for %s; set git.pr_provider", remoteURL)
+	}
+	return provider, nil
+}
+
+// pullRequest fills in the pull request title and body templates for a
+// job. head is where the branch's commits are: the local branch before it
+// is published, its copy on the remote after.
+func (s *Server) pullRequest(gitMgr *git.Manager, j *job.Job, branch, head, target string) git.PullRequest {
+	workerName := j.Worker
+	if w, ok := s.pool.GetByID(j.Worker); ok {
+		workerName = w.Name
+	}
+
+	body := j.GetBody()
+	if body == "" {
+		body = j.Description
+	}
+
+	var diffStat string
+	if stat, err := gitMgr.DiffStat(target, head); err == nil {
+		diffStat = stat
+	} else {
+		s.log.Warn("no diff stat for pull request", "job", j.ID, "head", head, "error", err)
+	}
+
+	fields := strings.NewReplacer(
+		"{title}", s.pullRequestTitle(j),
+		"{description}", j.Description,
+		"{body}", body,
+		"{job}", display.ShortID(j.ID),
+		"{worker}", workerName,
+		"{branch}", branch,
+		"{target}", target,
+		"{summary}", prSection("Summary", strings.TrimSpace(j.GetSummary())),
+		"{diffstat}", prSection("Changes", codeBlock(diffStat)),
+		"{review}", prSection("Review", reviewText(j.GetVerdict())),
+		"{checklist}", prSection("Checklist", checklistText(j.GetReviewChecklist(), j.GetChecklistResults())),
+		"{revisions}", prSection("Revisions", s.revisionsText(j)),
+	)
+
+	titleTemplate := s.config().Git.PRTitle
+	if titleTemplate == "" {
+		titleTemplate = defaultPRTitle
+	}
+	bodyTemplate := s.config().Git.PRTemplate
+	if bodyTemplate == "" {
+		bodyTemplate = defaultPRTemplate
+	}
+
+	return git.PullRequest{
+		Branch: branch,
+		Target: target,
+		Title:  strings.Join(strings.Fields(fields.Replace(titleTemplate)), " "),
+		Body:   fields.Replace(bodyTemplate),
+	}
+}
+
+// pullRequestTitle is the first line of the job's description. A revision
+// is titled after the job it revises, whose description is the brief.
+func (s *Server) pullRequestTitle(j *job.Job) string {
+	root := j
+	seen := map[string]bool{j.ID: true}
+	for root.RevisionOf != "" && !seen[root.RevisionOf] {
+		prev, ok := s.jobs.Get(root.RevisionOf)
+		if !ok {
+			break
+		}
+		seen[prev.ID] = true
+		root = prev
+	}
+
+	title, _, _ := strings.Cut(strings.TrimSpace(root.Description), "\n")
+	if root != j {
+		title = fmt.Sprintf("Revision %d of: %s", j.GetRevisionRound(), title)
+	}
+	return display.Truncate(title, prTitleWidth)
+}
+
+// refreshPullRequests rewrites the pull requests of j and of the jobs it
+// revises, whose bodies show its review and revisions.
+func (s *Server) refreshPullRequests(j *job.Job) {
+	if s.config().Git.RemoteMode != config.RemoteModeOpenPR {
+		return
+	}
+
+	s.mu.RLock()
+	t := s.territory
+	s.mu.RUnlock()
+	if t == nil {
+		return
+	}
+	gitMgr := t.GitManager()
+	target := t.MergeTargetBranch(s.config().Git.DefaultMergeBranch)
+
+	seen := make(map[string]bool)
+	for ok := true; ok && !seen[j.ID]; j, ok = s.jobs.Get(j.RevisionOf) {
+		seen[j.ID] = true
+		if j.GetPullRequest() == "" {
+			continue
+		}
+		if err := s.updatePullRequest(gitMgr, j, target); err != nil {
+			s.log.Error("failed to update pull request", "job", j.ID, "url", j.GetPullRequest(), "error", err)
+			s.ledger.Append(ledger.EventType("job.pr_error"), ledger.JobEventData{
+				ID:    j.ID,
+				Error: err.Error(),
+			})
+		}
+	}
+}
+
+// updatePullRequest rewrites the title and body of a job's pull request.
+func (s *Server) updatePullRequest(gitMgr *git.Manager, j *job.Job, target string) error {
+	remote := s.config().Git.Remote
+	if remote == "" {
+		remote = "origin"
+	}
+	provider, err := s.pullRequestProvider(gitMgr, remote)
+	if err != nil {
+		return err
+	}
+
+	head := j.GetRemoteBranch()
+	branch := strings.TrimPrefix(head, remote+"/")
+	return gitMgr.UpdatePullRequest(provider, j.GetPullRequest(), s.pullRequest(gitMgr, j, branch, head, target))
+}
+
+// revisionsText lists the job j revises and the revisions made of it.
+func (s *Server) revisionsText(j *job.Job) string {
+	var lines []string
+	if prev, ok := s.jobs.Get(j.RevisionOf); ok {
+		lines = append(lines, fmt.Sprintf("- Revises job %s%s", display.ShortID(prev.ID), prLink(prev)))
+	}
+
+	seen := map[string]bool{j.ID: true}
+	for next := s.revisionOf(j); next != nil && !seen[next.ID]; next = s.revisionOf(next) {
+		seen[next.ID] = true
+		lines = append(lines, fmt.Sprintf("- Round %d: job %s, %s%s",
+			next.GetRevisionRound(), display.ShortID(next.ID), next.GetStatus(), prLink(next)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// revisionOf returns the latest revision made of j, or nil.
+func (s *Server) revisionOf(j *job.Job) *job.Job {
+	var latest *job.Job
+	for _, other := range s.jobs.List() {
+		if other.RevisionOf == j.ID && (latest == nil || other.CreatedAt.After(latest.CreatedAt)) {
+			latest = other
+		}
+	}
+	return latest
+}
+
+// prLink returns " (url)" for a job with a pull request.
+func prLink(j *job.Job) string {
+	if url := j.GetPullRequest(); url != "" {
+		return " (" + url + ")"
+	}
+	return ""
+}
+
+// prSection renders a pull request body section, or nothing without text.
+func prSection(heading, text string) string {
+	if text == "" {
+		return ""
+	}
+	return "\n## " + heading + "\n\n" + text + "\n"
+}
+
+func codeBlock(text string) string {
+	if text == "" {
+		return ""
+	}
+	return "```\n" + text + "\n```"
+}
+
+// reviewText describes a review verdict.
+func reviewText(v *job.ReviewVerdict) string {
+	if v == nil {
+		return ""
+	}
+
+	verdict := "Changes requested"
+	if v.Decision == "approved" {
+		verdict = "Approved"
+	}
+	text := "**" + verdict + "**"
+	if v.Summary != "" {
+		text += ": " + v.Summary
+	}
+	for _, item := range v.MustFix {
+		text += "\n- " + item
+	}
+	return text
+}
+
+// checklistText renders the review checklist as a task list, ticking the
+// items the reviewer passed. Before a review every item is unticked.
+func checklistText(items []string, results []job.ChecklistResult) string {
+	var lines []string
+	if len(results) == 0 {
+		for _, item := range items {
+			lines = append(lines, "- [ ] "+item)
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	for _, r := range results {
+		box := "- [ ] "
+		if r.Passed {
+			box = "- [x] "
+		}
+		line := box + r.Item
+		if r.Note != "" {
+			line += " — " + r.Note
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,94 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+	"cosa/internal/worker"
+)
+
+// Ledger events for quarantined workers.
+const (
+	EventWorkerQuarantined   ledger.EventType = "worker.quarantined"
+	EventWorkerUnquarantined ledger.EventType = "worker.unquarantined"
+)
+
+// checkWorkerQuarantine returns an error if w is quarantined.
+func (s *Server) checkWorkerQuarantine(w *worker.Worker) error {
+	if q := w.GetQuarantine(); q != nil {
+		return fmt.Errorf("worker %s is quarantined after %d failed jobs in a row", w.Name, len(q.Failures))
+	}
+	return nil
+}
+
+// recordWorkerFailure counts a failed job against the worker that ran it.
+// A worker that has failed workers.quarantine_after jobs in a row is
+// quarantined, and the boss is alerted with the failures.
+func (s *Server) recordWorkerFailure(w *worker.Worker, reason string) {
+	q := w.RecordFailure(worker.FailureSignature(reason), s.config().Workers.QuarantineAfter)
+	if err := s.pool.Save(w); err != nil {
+		s.log.Warn("failed to save worker failures", "worker", w.Name, "error", err)
+	}
+	if q == nil {
+		return
+	}
+
+	s.log.Warn("worker quarantined", "worker", w.Name, "failures", len(q.Failures))
+	s.ledger.Append(EventWorkerQuarantined, map[string]interface{}{
+		"worker":   w.Name,
+		"failures": q.Failures,
+	})
+	s.notifier.NotifyWorkerQuarantined(w.Name, q.Failures)
+}
+
+// recordWorkerSuccess ends the worker's run of failed jobs.
+func (s *Server) recordWorkerSuccess(w *worker.Worker) {
+	if !w.RecordSuccess() {
+		return
+	}
+	if err := s.pool.Save(w); err != nil {
+		s.log.Warn("failed to save worker failures", "worker", w.Name, "error", err)
+	}
+}
+
+func (s *Server) handleWorkerUnquarantine(req *protocol.Request) *protocol.Response {
+	var params protocol.WorkerNameParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	w, exists := s.pool.Get(params.Name)
+	if !exists {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrWorkerNotFound, "worker not found", nil)
+		return resp
+	}
+
+	q := w.Unquarantine()
+	if q == nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, fmt.Sprintf("worker %s is not quarantined", w.Name), nil)
+		return resp
+	}
+	if err := s.pool.Save(w); err != nil {
+		s.log.Warn("failed to save worker", "worker", w.Name, "error", err)
+	}
+	s.ledger.Append(EventWorkerUnquarantined, map[string]interface{}{
+		"worker":   w.Name,
+		"failures": q.Failures,
+	})
+
+	resp, _ := protocol.NewResponse(req.ID, quarantineInfo(q))
+	return resp
+}
+
+// quarantineInfo converts a quarantine for the protocol.
+func quarantineInfo(q *worker.Quarantine) *protocol.QuarantineInfo {
+	if q == nil {
+		return nil
+	}
+	return &protocol.QuarantineInfo{
+		Since:    q.Since.Unix(),
+		Failures: q.Failures,
+	}
+}
@@ -0,0 +1,87 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+)
+
+// Queue events, with protocol.QueueHoldInfo data
+const (
+	EventQueuePaused   ledger.EventType = "queue.paused"
+	EventQueueDraining ledger.EventType = "queue.draining"
+	EventQueueResumed  ledger.EventType = "queue.resumed"
+)
+
+// errQueueDraining is returned for a job added while the queue is draining.
+var errQueueDraining = errors.New("queue is draining and takes no new jobs; resume it with 'cosa queue resume'")
+
+// assignmentsHeld returns why no job may be handed to a worker right now, or
+// "" if jobs may be assigned.
+func (s *Server) assignmentsHeld() string {
+	if s.isDraining() {
+		return "daemon is draining for shutdown"
+	}
+	switch s.queueControl.State() {
+	case job.QueuePaused:
+		return "queue is paused"
+	case job.QueueDraining:
+		return "queue is draining"
+	}
+	return ""
+}
+
+// checkAcceptingJobs returns errQueueDraining if new jobs are refused.
+func (s *Server) checkAcceptingJobs() error {
+	if s.queueControl.State() == job.QueueDraining {
+		return errQueueDraining
+	}
+	return nil
+}
+
+// handleQueueState pauses, drains or resumes the queue. Running jobs are left
+// to finish either way.
+func (s *Server) handleQueueState(req *protocol.Request, state job.QueueState) *protocol.Response {
+	var params protocol.QueueStateParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	prev, err := s.queueControl.Set(state, params.By, params.Reason)
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, err.Error(), nil)
+		return resp
+	}
+
+	info := queueHoldInfo(s.queueControl.Get())
+	if prev.State != state {
+		event := EventQueueResumed
+		switch state {
+		case job.QueuePaused:
+			event = EventQueuePaused
+		case job.QueueDraining:
+			event = EventQueueDraining
+		}
+		s.log.Info("queue state changed", "from", prev.State, "to", state, "by", params.By)
+		s.ledger.Append(event, info)
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, protocol.QueueStateResult{
+		QueueHoldInfo: info,
+		Previous:      string(prev.State),
+	})
+	return resp
+}
+
+// queueHoldInfo converts the queue's state for the protocol.
+func queueHoldInfo(h job.QueueHold) protocol.QueueHoldInfo {
+	return protocol.QueueHoldInfo{
+		State:  string(h.State),
+		Since:  h.Since.Unix(),
+		By:     h.By,
+		Reason: h.Reason,
+	}
+}
@@ -0,0 +1,103 @@
+package daemon
+
+import (
+	"time"
+
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+)
+
+// startStaleBranchMonitor periodically refreshes the kept worktrees of
+// waiting jobs that have fallen behind the merge target.
+func (s *Server) startStaleBranchMonitor() {
+	interval := s.config().Git.StaleBranchInterval
+	if s.config().Git.StaleBranchThreshold <= 0 || interval <= 0 {
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+				for _, j := range s.jobs.ListByStatus(job.StatusPending) {
+					if j.GetWorktree() != "" {
+						s.refreshStaleJob(j)
+					}
+				}
+			}
+		}
+	}()
+}
+
+// refreshStaleJob recreates or rebases the worktree of a job that has not
+// started yet when its branch is more than git.stale_branch_threshold
+// commits behind the merge target. Jobs without a worktree get a fresh one
+// when they start and are left alone.
+func (s *Server) refreshStaleJob(j *job.Job) {
+	threshold := s.config().Git.StaleBranchThreshold
+	if threshold <= 0 || j.GetWorktree() == "" {
+		return
+	}
+
+	s.mu.RLock()
+	t := s.territory
+	s.mu.RUnlock()
+	if t == nil {
+		return
+	}
+
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	// The job may have started while waiting for another refresh
+	if status := j.GetStatus(); status != job.StatusPending && status != job.StatusQueued {
+		return
+	}
+
+	gitMgr := t.GitManager()
+	target := t.MergeTargetBranch(s.config().Git.DefaultMergeBranch)
+	if target == "" {
+		target = gitMgr.GetDefaultBranch()
+	}
+
+	behind, _, err := gitMgr.BranchDivergence(j.GetBranch(), target)
+	if err != nil || behind <= threshold {
+		return
+	}
+
+	refresh, err := gitMgr.RefreshJobWorktree(j.ID, target)
+	if err != nil {
+		s.log.Warn("failed to refresh job branch", "job", j.ID, "target", target, "error", err)
+		s.ledger.Append(ledger.EventType("job.branch_refresh_error"), ledger.JobEventData{
+			ID:          j.ID,
+			Description: j.Description,
+			Error:       err.Error(),
+		})
+		return
+	}
+
+	j.SetWorktree(gitMgr.GetJobWorktreePath(j.ID), gitMgr.GetJobBranchName(j.ID))
+	s.jobs.Save(j)
+
+	action := "recreated"
+	if refresh.Rebased {
+		action = "rebased"
+	}
+	s.ledger.Append(ledger.EventType("job.branch_refreshed"), map[string]interface{}{
+		"id":          j.ID,
+		"description": j.Description,
+		"target":      target,
+		"behind":      refresh.Behind,
+		"ahead":       refresh.Ahead,
+		"action":      action,
+		"commit":      refresh.Commit,
+	})
+}
@@ -0,0 +1,163 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"cosa/internal/config"
+	"cosa/internal/ledger"
+	"cosa/internal/notify"
+	"cosa/internal/protocol"
+)
+
+// configWatchInterval is how often the config file is checked for changes.
+const configWatchInterval = 2 * time.Second
+
+// config returns the daemon's config. A reload swaps in a new copy rather
+// than changing the one in use, so what config returns stays consistent
+// for as long as the caller holds on to it.
+func (s *Server) config() *config.Config {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	return s.cfg
+}
+
+// ReloadConfig reads the config file again and applies the settings that
+// may change while the daemon runs (config.ReloadableKeys). It returns the
+// settings that changed, recording them in the ledger; changes to other
+// settings wait for a restart. source says what asked for the reload.
+func (s *Server) ReloadConfig(source string) (*protocol.ConfigReloadResult, error) {
+	applied, pending, err := s.swapConfig()
+	if err != nil {
+		return nil, err
+	}
+	result := &protocol.ConfigReloadResult{
+		Applied: configChanges(applied),
+		Pending: configChanges(pending),
+	}
+
+	pendingKeys := make([]string, len(pending))
+	for i, c := range pending {
+		pendingKeys[i] = c.Key
+	}
+	if len(pending) > 0 {
+		s.log.Warn("config changes wait for a daemon restart", "settings", strings.Join(pendingKeys, ","))
+	}
+	if len(applied) == 0 {
+		return result, nil
+	}
+
+	keys := make([]string, len(applied))
+	lookout := false
+	for i, c := range applied {
+		keys[i] = c.Key
+		lookout = lookout || strings.HasPrefix(c.Key, "lookout.")
+	}
+	if lookout {
+		s.reloadStuckThresholds()
+	}
+	s.ledger.Append(ledger.EventType("config.reloaded"), map[string]interface{}{
+		"source":  source,
+		"changed": result.Applied,
+		"pending": pendingKeys,
+	})
+	s.log.Info("config reloaded", "source", source, "changed", strings.Join(keys, ","))
+	return result, nil
+}
+
+// swapConfig loads the config file and replaces the config in use with a
+// copy that has its reloadable settings, unless none of them changed.
+func (s *Server) swapConfig() (applied, pending []config.Change, err error) {
+	// mu guards the territory config merged over the reloaded one
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cfgMu.Lock()
+	defer s.cfgMu.Unlock()
+
+	next, err := config.LoadProfile(s.cfg.Profile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := notify.ValidateRules(next.Notifications.Rules); err != nil {
+		return nil, nil, fmt.Errorf("invalid notification rules: %w", err)
+	}
+
+	reloaded, applied, pending, err := s.cfg.Reload(next)
+	if err != nil || len(applied) == 0 {
+		return applied, pending, err
+	}
+
+	// Jobs in the active territory run with its overrides on top
+	merged := reloaded
+	if s.territoryCfg != nil {
+		if merged, err = s.territoryCfg.Apply(reloaded); err != nil {
+			return nil, nil, fmt.Errorf("invalid territory config: %w", err)
+		}
+		if err := notify.ValidateRules(merged.Notifications.Rules); err != nil {
+			return nil, nil, fmt.Errorf("invalid notification rules in territory config: %w", err)
+		}
+		s.jobCfg.Store(merged)
+	}
+	s.cfg = reloaded
+	s.notifier.SetConfig(&merged.Notifications)
+	return applied, pending, nil
+}
+
+// configChanges converts config changes for the protocol.
+func configChanges(changes []config.Change) []protocol.ConfigChange {
+	out := make([]protocol.ConfigChange, len(changes))
+	for i, c := range changes {
+		out[i] = protocol.ConfigChange{Key: c.Key, Old: c.Old, New: c.New}
+	}
+	return out
+}
+
+func (s *Server) handleConfigReload(req *protocol.Request) *protocol.Response {
+	result, err := s.ReloadConfig("request")
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, err.Error(), nil)
+		return resp
+	}
+	resp, _ := protocol.NewResponse(req.ID, result)
+	return resp
+}
+
+// startConfigWatcher reloads the config whenever its file changes, as
+// when 'cosa settings set' saves it.
+func (s *Server) startConfigWatcher() {
+	path := config.ProfileConfigPath(s.config().Profile)
+	modTime := func() time.Time {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}
+		}
+		return info.ModTime()
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(configWatchInterval)
+		defer ticker.Stop()
+
+		last := modTime()
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+				mod := modTime()
+				if mod.Equal(last) || mod.IsZero() {
+					continue
+				}
+				last = mod
+				if _, err := s.ReloadConfig("file"); err != nil {
+					s.log.Warn("config reload failed", "path", path, "error", err)
+				}
+			}
+		}
+	}()
+}
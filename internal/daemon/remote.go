@@ -0,0 +1,92 @@
+package daemon
+
+import (
+	"fmt"
+
+	"cosa/internal/config"
+	"cosa/internal/git"
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+)
+
+// publishesBranches reports whether finished job branches are pushed to the
+// remote instead of merged locally.
+func (s *Server) publishesBranches() bool {
+	mode := s.config().Git.RemoteMode
+	return mode == config.RemoteModePushBranch || mode == config.RemoteModeOpenPR
+}
+
+// publishJobBranch pushes a job's branch to the remote and, in open-pr mode,
+// opens a pull request against the target branch. The local branch is
+// deleted once the branch is published.
+func (s *Server) publishJobBranch(gitMgr *git.Manager, j *job.Job, branch, target string) error {
+	remote := s.config().Git.Remote
+	if remote == "" {
+		remote = "origin"
+	}
+
+	if err := gitMgr.PushBranch(remote, branch); err != nil {
+		s.log.Error("push failed", "job", j.ID, "remote", remote, "branch", branch, "error", err)
+		s.ledger.Append(ledger.EventType("job.push_error"), ledger.JobEventData{
+			ID:    j.ID,
+			Error: err.Error(),
+		})
+		return err
+	}
+
+	s.ledger.Append(ledger.EventType("job.pushed"), ledger.JobEventData{
+		ID:          j.ID,
+		Description: fmt.Sprintf("Pushed %s to %s", branch, remote),
+	})
+
+	var url string
+	if s.config().Git.RemoteMode == config.RemoteModeOpenPR {
+		var err error
+		url, err = s.openPullRequest(gitMgr, j, remote, branch, target)
+		if err != nil {
+			// The branch is kept so the pull request can be retried with job merge
+			s.log.Error("failed to open pull request", "job", j.ID, "branch", branch, "error", err)
+			s.ledger.Append(ledger.EventType("job.pr_error"), ledger.JobEventData{
+				ID:    j.ID,
+				Error: err.Error(),
+			})
+			return err
+		}
+
+		s.ledger.Append(ledger.EventType("job.pr_opened"), ledger.JobEventData{
+			ID:          j.ID,
+			Description: fmt.Sprintf("Opened pull request %s into %s", url, target),
+		})
+	}
+
+	j.SetPublished(remote+"/"+branch, url)
+
+	// The pull requests of the work a revision revises link to it
+	if prev, ok := s.jobs.Get(j.RevisionOf); ok && url != "" {
+		s.refreshPullRequests(prev)
+	}
+
+	removeConflictWorkspace(gitMgr, j.ID)
+	if err := gitMgr.DeleteBranch(branch, true); err != nil {
+		s.ledger.Append(ledger.EventType("job.branch_cleanup_error"), ledger.JobEventData{
+			ID:    j.ID,
+			Error: fmt.Sprintf("failed to delete branch: %v", err),
+		})
+	}
+
+	j.ClearWorktree()
+	j.ClearUnmerged()
+	s.jobs.Save(j)
+
+	return nil
+}
+
+// openPullRequest opens a pull request for a pushed job branch.
+func (s *Server) openPullRequest(gitMgr *git.Manager, j *job.Job, remote, branch, target string) (string, error) {
+	provider, err := s.pullRequestProvider(gitMgr, remote)
+	if err != nil {
+		return "", err
+	}
+
+	return gitMgr.OpenPullRequest(provider, s.pullRequest(gitMgr, j, branch, branch, target))
+}
@@ -0,0 +1,498 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"cosa/internal/config"
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+)
+
+// ReplayOptions control how a ReplayServer plays back its ledger.
+type ReplayOptions struct {
+	// Speed is how many seconds of the ledger play in a second; 0 applies
+	// every event at once, showing the crew as the ledger left it
+	Speed float64
+
+	// Since skips ahead: events before it are applied at once and only
+	// the rest are played
+	Since time.Time
+
+	// MaxGap is the longest wait between two events, so that hours in
+	// which nothing happened pass quickly; 0 does not shorten waits
+	MaxGap time.Duration
+}
+
+// ReplayServer serves the state of a crew rebuilt from a ledger over the
+// daemon's protocol, so that clients such as the TUI can play back what it
+// did without a running daemon. It answers the read-only calls the TUI
+// makes and refuses the rest.
+type ReplayServer struct {
+	events []ledger.Event
+	opts   ReplayOptions
+	epoch  int64
+
+	listener net.Listener
+
+	mu      sync.RWMutex
+	state   *ledger.Replay
+	clients map[net.Conn]*replayClient
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// replayClient is what a replay connection subscribed to.
+type replayClient struct {
+	subscribed bool
+	events     []string
+	filter     *ledger.Query
+}
+
+// NewReplayServer creates a server that plays back events, oldest first.
+func NewReplayServer(events []ledger.Event, opts ReplayOptions) *ReplayServer {
+	return &ReplayServer{
+		events:  events,
+		opts:    opts,
+		epoch:   time.Now().UnixNano(),
+		state:   ledger.NewReplay(),
+		clients: make(map[net.Conn]*replayClient),
+		done:    make(chan struct{}),
+	}
+}
+
+// Start listens at address and starts playing the ledger.
+func (r *ReplayServer) Start(address string) error {
+	listener, err := Listen(address)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	r.listener = listener
+
+	// What happened before the replay starts is shown as it stood
+	for _, e := range r.events {
+		if r.opts.Speed > 0 && !e.Timestamp.Before(r.opts.Since) {
+			break
+		}
+		r.state.Apply(e)
+	}
+
+	r.wg.Add(2)
+	go r.acceptLoop()
+	go r.play()
+	return nil
+}
+
+// Close stops the replay and disconnects its clients.
+func (r *ReplayServer) Close() error {
+	close(r.done)
+	if r.listener != nil {
+		r.listener.Close()
+	}
+	r.mu.Lock()
+	for conn := range r.clients {
+		conn.Close()
+	}
+	r.mu.Unlock()
+	r.wg.Wait()
+	return nil
+}
+
+// play applies the events not yet applied, waiting between them for the
+// time that passed between them in the ledger at the replay's speed.
+func (r *ReplayServer) play() {
+	defer r.wg.Done()
+
+	r.mu.RLock()
+	next := r.state.Applied
+	last := r.state.Clock
+	r.mu.RUnlock()
+
+	for _, e := range r.events[next:] {
+		wait := time.Duration(float64(e.Timestamp.Sub(last)) / r.opts.Speed)
+		if r.opts.MaxGap > 0 && wait > r.opts.MaxGap {
+			wait = r.opts.MaxGap
+		}
+		if last.IsZero() || wait < 0 {
+			wait = 0
+		}
+		select {
+		case <-r.done:
+			return
+		case <-time.After(wait):
+		}
+
+		r.mu.Lock()
+		r.state.Apply(e)
+		r.mu.Unlock()
+		last = e.Timestamp
+		r.broadcast(e)
+	}
+}
+
+func (r *ReplayServer) acceptLoop() {
+	defer r.wg.Done()
+
+	for {
+		conn, err := r.listener.Accept()
+		if err != nil {
+			select {
+			case <-r.done:
+				return
+			default:
+				continue
+			}
+		}
+
+		r.mu.Lock()
+		r.clients[conn] = &replayClient{}
+		r.mu.Unlock()
+
+		r.wg.Add(1)
+		go r.handleConnection(conn)
+	}
+}
+
+func (r *ReplayServer) handleConnection(conn net.Conn) {
+	defer r.wg.Done()
+	defer func() {
+		r.mu.Lock()
+		delete(r.clients, conn)
+		r.mu.Unlock()
+		conn.Close()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var req protocol.Request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			resp, _ := protocol.NewErrorResponse(nil, protocol.ParseError, "Parse error", nil)
+			r.send(conn, resp)
+			continue
+		}
+		if req.ID == nil {
+			continue // Notifications such as request.cancel need no answer
+		}
+		r.send(conn, r.handleRequest(&req, conn))
+	}
+}
+
+func (r *ReplayServer) send(conn net.Conn, resp *protocol.Response) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	conn.Write(append(data, '\n'))
+}
+
+func (r *ReplayServer) handleRequest(req *protocol.Request, conn net.Conn) *protocol.Response {
+	if err := protocol.ValidateParams(req.Method, req.Params); err != nil {
+		return protocol.NewInvalidParamsResponse(req.ID, err)
+	}
+
+	var result any
+	switch req.Method {
+	case protocol.MethodAuthLogin:
+		result = protocol.AuthLoginResult{Role: config.AuthRoleObserver}
+	case protocol.MethodSubscribe:
+		return r.handleSubscribe(req, conn)
+	case protocol.MethodUnsubscribe:
+		r.mu.Lock()
+		if c, ok := r.clients[conn]; ok {
+			*c = replayClient{}
+		}
+		r.mu.Unlock()
+		result = map[string]bool{"subscribed": false}
+	case protocol.MethodStatus:
+		result = r.status()
+	case protocol.MethodSyncState:
+		var params protocol.SyncStateParams
+		if req.Params != nil {
+			json.Unmarshal(req.Params, &params)
+		}
+		result = r.syncState(params)
+	case protocol.MethodOverview:
+		result = r.overview()
+	case protocol.MethodWorkerList:
+		result = r.workerInfos()
+	case protocol.MethodJobList:
+		result = r.jobInfos()
+	case protocol.MethodLedgerQuery:
+		var params protocol.LedgerQueryParams
+		if req.Params != nil {
+			json.Unmarshal(req.Params, &params)
+		}
+		result = r.ledgerQuery(params)
+	case protocol.MethodTemplateList:
+		result = protocol.TemplateListResult{Templates: []protocol.TemplateInfo{}}
+	case protocol.MethodPresetList:
+		result = protocol.PresetListResult{Presets: []protocol.PresetInfo{}}
+	default:
+		message := fmt.Sprintf("%s is not recorded in the ledger being replayed", req.Method)
+		if !protocol.IsReadOnly(req.Method) {
+			message = fmt.Sprintf("%s is not allowed while replaying a ledger", req.Method)
+		}
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrUnauthorized, message, nil)
+		return resp
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, result)
+	return resp
+}
+
+func (r *ReplayServer) handleSubscribe(req *protocol.Request, conn net.Conn) *protocol.Response {
+	var params protocol.SubscribeParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	var filter *ledger.Query
+	if params.Filter != nil {
+		q, err := LogFilterQuery(params.Filter)
+		if err != nil {
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, err.Error(), nil)
+			return resp
+		}
+		filter = &q
+	}
+
+	r.mu.Lock()
+	if c, ok := r.clients[conn]; ok {
+		*c = replayClient{subscribed: true, events: params.Events, filter: filter}
+	}
+	r.mu.Unlock()
+
+	resp, _ := protocol.NewResponse(req.ID, map[string]bool{"subscribed": true})
+	return resp
+}
+
+// broadcast sends an event just played to the clients subscribed to it.
+func (r *ReplayServer) broadcast(event ledger.Event) {
+	notification, err := protocol.NewNotification(protocol.NotifyLogEntry, event)
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return
+	}
+	data = append(data, '\n')
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for conn, c := range r.clients {
+		if !c.subscribed {
+			continue
+		}
+		if len(c.events) > 0 && c.events[0] != "*" && !slices.Contains(c.events, string(event.Type)) {
+			continue
+		}
+		if c.filter != nil && !c.filter.Matches(event) {
+			continue
+		}
+		conn.Write(data)
+	}
+}
+
+// status describes the crew at the point the replay has reached. Uptime
+// and ReplayClock are in ledger time.
+func (r *ReplayServer) status() protocol.StatusResult {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := protocol.StatusResult{
+		Running:     true,
+		Version:     config.Version,
+		Territory:   r.state.Territory,
+		TotalCost:   fmt.Sprintf("$%.2f", r.state.TotalCost),
+		TotalTokens: r.state.TotalTokens,
+	}
+	if !r.state.Clock.IsZero() {
+		result.ReplayClock = r.state.Clock.Unix()
+	}
+	if !r.state.Started.IsZero() {
+		result.Uptime = int64(r.state.Clock.Sub(r.state.Started).Seconds())
+	}
+	result.Workers = len(r.state.Workers())
+	for _, j := range r.state.Jobs() {
+		if j.Status == "running" {
+			result.ActiveJobs++
+		}
+	}
+	return result
+}
+
+// syncState answers sync.state. The version counts the events played, so
+// clients refresh as the replay moves on.
+func (r *ReplayServer) syncState(params protocol.SyncStateParams) protocol.SyncStateResult {
+	r.mu.RLock()
+	result := protocol.SyncStateResult{
+		Epoch:   r.epoch,
+		Version: uint64(r.state.Applied),
+	}
+	r.mu.RUnlock()
+
+	if params.Epoch == result.Epoch && params.Version == result.Version {
+		result.Unchanged = true
+		return result
+	}
+
+	status := r.status()
+	result.Status = &status
+	result.Workers = r.workerInfos()
+	result.Jobs = r.jobInfos()
+	for _, j := range result.Jobs {
+		if j.Status == "needs_attention" {
+			result.Alerts = append(result.Alerts, protocol.SyncAlert{
+				Kind:    protocol.AlertAttention,
+				Subject: j.ID,
+				Message: j.AttentionReason,
+				Since:   j.AttentionSince,
+			})
+		}
+	}
+	return result
+}
+
+func (r *ReplayServer) workerInfos() []protocol.WorkerInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	workers := r.state.Workers()
+	infos := make([]protocol.WorkerInfo, 0, len(workers))
+	for _, w := range workers {
+		info := protocol.WorkerInfo{
+			ID:         w.ID,
+			Name:       w.Name,
+			Role:       w.Role,
+			Status:     w.Status,
+			CurrentJob: w.Job,
+		}
+		if j, ok := r.state.Job(w.Job); ok {
+			info.CurrentJobDesc = j.Description
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+func (r *ReplayServer) jobInfos() []protocol.JobInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	jobs := r.state.Jobs()
+	infos := make([]protocol.JobInfo, 0, len(jobs))
+	for _, j := range jobs {
+		info := protocol.JobInfo{
+			ID:          j.ID,
+			Description: j.Description,
+			Status:      j.Status,
+			Priority:    j.Priority,
+			Worker:      j.Worker,
+			CreatedAt:   j.CreatedAt.Unix(),
+			Error:       j.Error,
+		}
+		if info.Priority == 0 {
+			info.Priority = job.PriorityNormal // No event recorded it
+		}
+		if !j.QueuedAt.IsZero() {
+			info.QueuedAt = j.QueuedAt.Unix()
+		}
+		if !j.StartedAt.IsZero() {
+			info.StartedAt = j.StartedAt.Unix()
+		}
+		if !j.CompletedAt.IsZero() {
+			info.CompletedAt = j.CompletedAt.Unix()
+		}
+		if j.Status == "needs_attention" {
+			info.AttentionReason = j.Reason
+			if info.AttentionReason == "" {
+				info.AttentionReason = j.Description
+			}
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// overview answers daemon.overview with the elapsed times of running jobs
+// in ledger time. The ledger does not record their progress.
+func (r *ReplayServer) overview() protocol.OverviewResult {
+	infos := r.workerInfos()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := protocol.OverviewResult{
+		Workers: make([]protocol.WorkerOverview, len(infos)),
+		Time:    r.state.Clock.Unix(),
+	}
+	for i, info := range infos {
+		result.Workers[i].WorkerInfo = info
+		j, ok := r.state.Job(info.CurrentJob)
+		if !ok {
+			continue
+		}
+		o := &result.Workers[i]
+		o.JobStatus = j.Status
+		if !j.StartedAt.IsZero() {
+			o.Elapsed = int64(r.state.Clock.Sub(j.StartedAt).Seconds())
+		}
+		if j.Cost > 0 {
+			o.Cost = fmt.Sprintf("$%.2f", j.Cost)
+		}
+	}
+	return result
+}
+
+// ledgerQuery answers ledger.query from the events played so far.
+func (r *ReplayServer) ledgerQuery(params protocol.LedgerQueryParams) protocol.LedgerQueryResult {
+	q := ledger.Query{
+		Worker: params.Worker,
+		Before: params.Before,
+		Limit:  params.Limit,
+	}
+	if q.Limit <= 0 {
+		q.Limit = defaultLedgerQueryLimit
+	}
+	if params.Since > 0 {
+		q.Since = time.Unix(params.Since, 0)
+	}
+	if params.Until > 0 {
+		q.Until = time.Unix(params.Until, 0)
+	}
+	for _, t := range params.Types {
+		q.Types = append(q.Types, ledger.EventType(t))
+	}
+	if params.Level != "" {
+		q.Level, _ = ledger.ParseSeverity(params.Level)
+	}
+
+	r.mu.RLock()
+	played := r.events[:r.state.Applied]
+	r.mu.RUnlock()
+
+	events, more := ledger.Filter(played, q)
+	result := protocol.LedgerQueryResult{
+		Events: make([]protocol.LedgerEvent, 0, len(events)),
+		More:   more,
+	}
+	for _, e := range events {
+		result.Events = append(result.Events, protocol.LedgerEvent{
+			ID:        e.ID,
+			Type:      string(e.Type),
+			Timestamp: e.Timestamp,
+			Data:      e.Data,
+		})
+	}
+	return result
+}
@@ -0,0 +1,114 @@
+package daemon
+
+import (
+	"encoding/json"
+	"sort"
+
+	"cosa/internal/job"
+	"cosa/internal/protocol"
+)
+
+// handleJobRerun starts a fresh job with the parameters of a finished one,
+// linked to it for history.
+func (s *Server) handleJobRerun(req *protocol.Request) *protocol.Response {
+	var params protocol.JobRerunParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	orig, exists := s.jobs.Get(params.ID)
+	if !exists {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrJobNotFound, "job not found", nil)
+		return resp
+	}
+	if err := s.checkAcceptingJobs(); err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, err.Error(), nil)
+		return resp
+	}
+
+	var dropped []string
+	j, err := orig.Rerun(func(dep string) (string, bool) {
+		latest, ok := s.rerunDependency(dep)
+		if !ok {
+			dropped = append(dropped, dep)
+		}
+		return latest, ok
+	})
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, err.Error(), nil)
+		return resp
+	}
+	if params.Priority > 0 {
+		j.SetPriority(params.Priority)
+	}
+
+	worker := params.Worker
+	if sticky := j.GetStickyWorker(); sticky != "" && worker == "" {
+		worker = sticky
+	}
+	if _, err := s.submitJob(j, protocol.JobAddParams{
+		Description: j.Description,
+		Labels:      j.GetLabels(),
+		Worker:      worker,
+	}); err != nil {
+		return jobAddErrorResponse(req.ID, err)
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, protocol.JobRerunResult{
+		Job: protocol.JobInfo{
+			ID:          j.ID,
+			Description: j.Description,
+			Status:      string(j.GetStatus()),
+			Priority:    j.Priority,
+			DependsOn:   j.DependsOn,
+			CreatedAt:   j.CreatedAt.Unix(),
+			RerunOf:     orig.ID,
+		},
+		Dropped: dropped,
+	})
+	return resp
+}
+
+// rerunDependency returns the job a rerun waits on in place of dep: the
+// latest rerun of dep, or dep itself if it was never rerun. It returns
+// false if that job has finished, since a completed job has nothing left
+// to wait for and a failed or cancelled one would fail the rerun with it,
+// or if dep no longer exists.
+func (s *Server) rerunDependency(dep string) (string, bool) {
+	j, ok := s.jobs.Get(dep)
+	if !ok {
+		return "", false
+	}
+	for {
+		reruns := s.rerunsOf(j.ID)
+		if len(reruns) == 0 {
+			break
+		}
+		latest, ok := s.jobs.Get(reruns[len(reruns)-1])
+		if !ok {
+			break
+		}
+		j = latest
+	}
+	if j.IsTerminal() {
+		return "", false
+	}
+	return j.ID, true
+}
+
+// rerunsOf returns the IDs of the jobs that rerun the job id, oldest first.
+func (s *Server) rerunsOf(id string) []string {
+	var reruns []*job.Job
+	for _, j := range s.jobs.List() {
+		if j.GetRerunOf() == id {
+			reruns = append(reruns, j)
+		}
+	}
+	sort.Slice(reruns, func(a, b int) bool { return reruns[a].CreatedAt.Before(reruns[b].CreatedAt) })
+
+	ids := make([]string, len(reruns))
+	for i, j := range reruns {
+		ids[i] = j.ID
+	}
+	return ids
+}
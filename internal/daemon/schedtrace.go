@@ -0,0 +1,135 @@
+package daemon
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+
+	"cosa/internal/protocol"
+)
+
+// defaultTraceSize is how many passes the scheduler trace keeps when it is
+// turned on without daemon.scheduler_trace set.
+const defaultTraceSize = 200
+
+// schedulerTrace is a ring buffer of the scheduler's latest decisions. A pass
+// that decides the same as the one before it is folded into it, so a job
+// sitting in the queue takes up one entry rather than the whole buffer.
+type schedulerTrace struct {
+	mu    sync.Mutex
+	size  int // Passes kept, 0 when the trace is off
+	ticks []protocol.SchedulerTick
+	next  int // Where the next pass goes once the buffer is full
+}
+
+func newSchedulerTrace(size int) *schedulerTrace {
+	return &schedulerTrace{size: max(size, 0)}
+}
+
+// enabled reports whether passes are being recorded.
+func (t *schedulerTrace) enabled() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.size > 0
+}
+
+// setEnabled turns the trace on or off. Turning it off drops what was
+// recorded.
+func (t *schedulerTrace) setEnabled(on bool, size int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !on {
+		t.size, t.ticks, t.next = 0, nil, 0
+		return
+	}
+	if t.size > 0 {
+		return
+	}
+	if size <= 0 {
+		size = defaultTraceSize
+	}
+	t.size = size
+}
+
+// record adds a pass to the trace.
+func (t *schedulerTrace) record(tick protocol.SchedulerTick) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.size == 0 {
+		return
+	}
+
+	if len(t.ticks) > 0 {
+		last := &t.ticks[(t.next+len(t.ticks)-1)%len(t.ticks)]
+		if last.Skipped == tick.Skipped && reflect.DeepEqual(last.Jobs, tick.Jobs) {
+			last.Repeats++
+			last.LastTime = tick.Time
+			return
+		}
+	}
+
+	if len(t.ticks) < t.size {
+		t.ticks = append(t.ticks, tick)
+		return
+	}
+	t.ticks[t.next] = tick
+	t.next = (t.next + 1) % t.size
+}
+
+// list returns the recorded passes, oldest first.
+func (t *schedulerTrace) list() []protocol.SchedulerTick {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ticks := make([]protocol.SchedulerTick, 0, len(t.ticks))
+	ticks = append(ticks, t.ticks[t.next:]...)
+	ticks = append(ticks, t.ticks[:t.next]...)
+	return ticks
+}
+
+func (s *Server) handleDebugScheduler(req *protocol.Request) *protocol.Response {
+	var params protocol.DebugSchedulerParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	if params.Enable != nil {
+		s.schedTrace.setEnabled(*params.Enable, s.config().Daemon.SchedulerTrace)
+		s.log.Info("scheduler trace toggled", "enabled", *params.Enable)
+	}
+
+	// A job is matched by a prefix of its ID, as it may be gone by now
+	var ticks []protocol.SchedulerTick
+	for _, tick := range s.schedTrace.list() {
+		if params.Job != "" {
+			var jobs []protocol.SchedulerJobDecision
+			for _, d := range tick.Jobs {
+				if strings.HasPrefix(d.Job, params.Job) {
+					jobs = append(jobs, d)
+				}
+			}
+			if len(jobs) == 0 {
+				continue
+			}
+			tick.Jobs = jobs
+		}
+		ticks = append(ticks, tick)
+	}
+	if params.Limit > 0 && len(ticks) > params.Limit {
+		ticks = ticks[len(ticks)-params.Limit:]
+	}
+
+	s.schedTrace.mu.Lock()
+	size := s.schedTrace.size
+	s.schedTrace.mu.Unlock()
+
+	resp, _ := protocol.NewResponse(req.ID, protocol.DebugSchedulerResult{
+		Enabled: size > 0,
+		Size:    size,
+		Ticks:   ticks,
+	})
+	return resp
+}
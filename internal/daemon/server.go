@@ -6,19 +6,26 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"cosa/internal/admission"
 	"cosa/internal/claude"
 	"cosa/internal/config"
+	"cosa/internal/display"
 	"cosa/internal/git"
 	"cosa/internal/job"
 	"cosa/internal/ledger"
 	"cosa/internal/notify"
 	"cosa/internal/protocol"
+	"cosa/internal/repoindex"
 	"cosa/internal/review"
 	"cosa/internal/territory"
 	"cosa/internal/worker"
@@ -26,7 +33,7 @@ import (
 
 // Server is the Cosa daemon server.
 type Server struct {
-	cfg       *config.Config
+	cfg       *config.Config // Read with config(); replaced whole on reload
 	ledger    *ledger.Ledger
 	listener  net.Listener
 	startedAt time.Time
@@ -37,30 +44,92 @@ type Server struct {
 	jobs              *job.Store
 	queue             *job.Queue
 	operations        *job.OperationStore
+	templates         *job.TemplateStore
 	sessions          *claude.SessionStore
+	transcripts       *claude.TranscriptStore
+	chats             *claude.ChatStore
+	capacity          *worker.Calendar
+	watches           *job.WatchList
+	queueControl      *job.QueueControl
 	scheduler         *scheduler
+	schedTrace        *schedulerTrace
 	reviewCoordinator *review.Coordinator
+	summarizer        *review.Summarizer
+	classifier        *review.OutcomeClassifier
+
+	// Guards cfg, which is swapped for a new copy when the config is reloaded
+	cfgMu sync.RWMutex
+
+	// The active territory's .cosa/config.yaml, guarded by mu, and cfg with
+	// it applied, which the territory's jobs run with
+	territoryCfg *config.TerritoryConfig
+	jobCfg       atomic.Pointer[config.Config]
+
+	// Repository index for job prompts; indexMu serializes rebuilds
+	repoIndex atomic.Pointer[repoindex.Index]
+	indexMu   sync.Mutex
 
 	// Background services
 	lookout  *worker.Lookout
 	cleaner  *worker.Cleaner
 	notifier *notify.Notifier
 
-	// Budget tracking for alerts
+	// Budget tracking for alerts and spend limits
 	budgetTracker *budgetTracker
+	spend         *ledger.SpendTracker
+
+	// Policy endpoint asked to accept each new job
+	admission *admission.Controller
+
+	// Per-client limit on adding jobs
+	rateLimiter *admission.RateLimiter
+
+	// Kinds of claude output drift already logged
+	schemaDrift sync.Map
 
 	// Chat session for interactive communication with underboss
 	chatSession *ChatSession
 
+	// Batches of jobs submitted with job.addBatch
+	imports   map[string]*importBatch
+	importsMu sync.Mutex
+
+	// Serializes stale branch refreshes with job starts
+	refreshMu sync.Mutex
+
+	// Serializes merges into each target branch
+	merges mergeQueue
+
+	// Hooks adding the job trailer to commits in job worktrees; empty
+	// when git.job_trailer is off or they could not be installed
+	jobHooks string
+
+	// REST API for CI pipelines, nil when disabled
+	apiServer *http.Server
+
 	// Client subscriptions for real-time events
 	clients   map[net.Conn]*clientState
 	clientsMu sync.RWMutex
 
+	// Idle shutdown tracking (unix nanos of last activity)
+	lastActivity atomic.Int64
+
+	// Set by a draining shutdown; no new work starts while it is. The
+	// times are guarded by mu
+	draining      atomic.Bool
+	drainStarted  time.Time
+	drainDeadline time.Time
+
+	// Operational log, separate from the activity ledger
+	log     *slog.Logger
+	logFile io.Closer
+
 	// Shutdown handling
-	ctx    context.Context
-	cancel context.CancelFunc
-	wg     sync.WaitGroup
-	mu     sync.RWMutex
+	ctx      context.Context
+	cancel   context.CancelFunc
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+	mu       sync.RWMutex
 }
 
 // budgetTracker tracks cumulative costs for budget alerts.
@@ -69,6 +138,7 @@ type budgetTracker struct {
 	totalCost        float64
 	warningNotified  bool
 	exceededNotified bool
+	exceeded         map[string]bool // budget.exceeded events already logged, by scope key
 }
 
 // parseCost parses a cost string like "$1.23" to a float64.
@@ -100,7 +170,13 @@ type scheduler struct {
 
 type clientState struct {
 	subscribed bool
-	events     []string // event types subscribed to, empty = all
+	events     []string      // event types subscribed to, empty = all
+	filter     *ledger.Query // narrows the subscribed events, nil for none
+	role       string        // config.AuthRole*, empty until the client logs in
+	name       string        // Name of the token the client logged in with
+
+	// Cancellable requests being handled, by request ID
+	inflight map[string]*inflightRequest
 }
 
 // New creates a new daemon server.
@@ -110,20 +186,48 @@ func New(cfg *config.Config) (*Server, error) {
 		return nil, fmt.Errorf("failed to create data directory: %w", err)
 	}
 
+	logger, logFile := openDaemonLog(cfg)
+
 	// Open ledger
 	l, err := ledger.Open(cfg.LedgerPath())
 	if err != nil {
 		return nil, fmt.Errorf("failed to open ledger: %w", err)
 	}
 
+	// Rebuild spend from recorded costs so budgets survive restarts
+	spend := ledger.NewSpendTracker()
+	if events, err := ledger.Read(cfg.LedgerPath()); err == nil {
+		spend.Load(events)
+	}
+
+	// Sessions and transcripts may be encrypted at rest
+	var dataCipher *claude.Cipher
+	if cfg.Encryption.Enabled {
+		if dataCipher, err = claude.OpenCipher(cfg.Encryption.KeychainService); err != nil {
+			return nil, fmt.Errorf("encryption is enabled, but %w", err)
+		}
+	}
+
 	// Create session store
 	sessionsPath := filepath.Join(cfg.DataDir, "sessions")
-	sessions, err := claude.NewSessionStore(sessionsPath)
+	sessions, err := claude.NewEncryptedSessionStore(sessionsPath, dataCipher)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create session store: %w", err)
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	// Create transcript store
+	transcriptsPath := filepath.Join(cfg.DataDir, "transcripts")
+	transcripts, err := claude.NewEncryptedTranscriptStore(transcriptsPath, dataCipher)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transcript store: %w", err)
+	}
+
+	// Create chat store
+	chatsPath := filepath.Join(cfg.DataDir, "chats")
+	chats, err := claude.NewEncryptedChatStore(chatsPath, dataCipher)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create chat store: %w", err)
+	}
 
 	// Create persistent job store
 	jobsPath := filepath.Join(cfg.DataDir, "jobs")
@@ -139,42 +243,96 @@ func New(cfg *config.Config) (*Server, error) {
 		return nil, fmt.Errorf("failed to create worker pool: %w", err)
 	}
 
+	// Create persistent template store (built-in templates are always loaded)
+	templatesPath := filepath.Join(cfg.DataDir, "templates")
+	templates, err := job.NewPersistentTemplateStore(templatesPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create template store: %w", err)
+	}
+
+	// Load worker downtime and daily limits
+	capacity, err := worker.LoadCalendar(filepath.Join(cfg.DataDir, "capacity.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the jobs each client identity watches
+	watches, err := job.LoadWatchList(filepath.Join(cfg.DataDir, "watches.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Load whether the queue was paused or draining
+	queueControl, err := job.LoadQueueControl(filepath.Join(cfg.DataDir, "queue.json"))
+	if err != nil {
+		return nil, err
+	}
+
 	queue := job.NewQueue(jobs)
 	operations := job.NewOperationStore()
 
+	ctx, cancel := context.WithCancel(context.Background())
+
 	// Create notifier for job events
+	if err := notify.ValidateRules(cfg.Notifications.Rules); err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid notification rules: %w", err)
+	}
 	notifier := notify.New(&cfg.Notifications)
+	if err := notifier.LoadDigest(filepath.Join(cfg.DataDir, "digest.json")); err != nil {
+		cancel()
+		return nil, err
+	}
 
-	return &Server{
-		cfg:           cfg,
-		ledger:        l,
-		clients:       make(map[net.Conn]*clientState),
-		pool:          pool,
-		jobs:          jobs,
-		queue:         queue,
-		operations:    operations,
-		sessions:      sessions,
-		notifier:      notifier,
+	s := &Server{
+		cfg:          cfg,
+		ledger:       l,
+		clients:      make(map[net.Conn]*clientState),
+		imports:      make(map[string]*importBatch),
+		pool:         pool,
+		jobs:         jobs,
+		queue:        queue,
+		operations:   operations,
+		templates:    templates,
+		sessions:     sessions,
+		transcripts:  transcripts,
+		chats:        chats,
+		capacity:     capacity,
+		watches:      watches,
+		queueControl: queueControl,
+		schedTrace:   newSchedulerTrace(cfg.Daemon.SchedulerTrace),
+		notifier:     notifier,
+		summarizer: review.NewSummarizer(review.SummarizerConfig{
+			Kind:      cfg.Review.DiffSummarizer,
+			Threshold: cfg.Review.DiffSummaryThreshold,
+			Binary:    cfg.Claude.Binary,
+			Model:     cfg.Review.SummaryModel,
+		}),
+		admission:     admission.New(cfg.Admission),
+		rateLimiter:   admission.NewRateLimiter(),
+		classifier:    review.NewOutcomeClassifier(cfg.Review.Classifier, cfg.Claude.Binary, cfg.Review.SummaryModel),
 		budgetTracker: &budgetTracker{},
+		spend:         spend,
+		log:           logger,
+		logFile:       logFile,
 		ctx:           ctx,
 		cancel:        cancel,
 		startedAt:     time.Now(),
-	}, nil
+	}
+	jobs.SetOnTransition(s.onJobTransition)
+	return s, nil
 }
 
-// Start begins listening on the Unix socket.
+// Start begins listening on the daemon's transport.
 func (s *Server) Start() error {
-	// Remove stale socket
-	os.Remove(s.cfg.SocketPath)
-
-	listener, err := net.Listen("unix", s.cfg.SocketPath)
+	listener, err := Listen(s.config().SocketPath)
 	if err != nil {
 		return fmt.Errorf("failed to listen on socket: %w", err)
 	}
 	s.listener = listener
 
 	// Write PID file
-	if err := os.WriteFile(s.cfg.PIDPath(), []byte(fmt.Sprintf("%d", os.Getpid())), 0600); err != nil {
+	if err := os.WriteFile(s.config().PIDPath(), []byte(fmt.Sprintf("%d", os.Getpid())), 0600); err != nil {
 		return fmt.Errorf("failed to write PID file: %w", err)
 	}
 
@@ -183,11 +341,17 @@ func (s *Server) Start() error {
 		Version: config.Version,
 		PID:     os.Getpid(),
 	})
+	s.log.Info("daemon started", "version", config.Version, "pid", os.Getpid(),
+		"socket", s.config().SocketPath, "profile", s.config().Profile)
+
+	s.installJobHooks()
 
 	// Try to auto-load territory from current directory
 	if wd, err := os.Getwd(); err == nil {
 		if territory.Exists(wd) {
-			s.loadExistingTerritory(wd)
+			if err := s.loadExistingTerritory(wd); err != nil {
+				s.log.Error("failed to load territory", "path", wd, "error", err)
+			}
 		}
 	}
 
@@ -203,10 +367,17 @@ func (s *Server) Start() error {
 	// Start background services
 	s.startLookout()
 	s.startCleaner()
+	s.startIdleMonitor()
+	s.startNotificationDigest()
+	s.startStaleBranchMonitor()
+	s.startTimeoutMonitor()
+	s.startIndexer()
+	s.startConfigWatcher()
 
 	// Start accepting connections
 	s.wg.Add(1)
 	go s.acceptLoop()
+	s.startAPI()
 
 	// Forward ledger events to subscribed clients
 	eventCh := make(chan ledger.Event, 100)
@@ -217,13 +388,19 @@ func (s *Server) Start() error {
 	return nil
 }
 
-// Stop gracefully shuts down the server.
+// Stop gracefully shuts down the server. It is safe to call more than once.
 func (s *Server) Stop() error {
+	s.stopOnce.Do(s.stop)
+	return nil
+}
+
+func (s *Server) stop() {
 	s.cancel()
 
 	if s.listener != nil {
 		s.listener.Close()
 	}
+	s.stopAPI()
 
 	// Stop the scheduler
 	s.stopScheduler()
@@ -244,11 +421,14 @@ func (s *Server) Stop() error {
 	for _, w := range s.pool.List() {
 		if w.SessionID != "" {
 			s.sessions.Save(&claude.SessionInfo{
-				SessionID:  w.SessionID,
-				WorkerID:   w.ID,
-				WorkerName: w.Name,
-				CreatedAt:  w.CreatedAt,
-				LastUsed:   time.Now(),
+				SessionID:         w.SessionID,
+				WorkerID:          w.ID,
+				WorkerName:        w.Name,
+				CreatedAt:         w.CreatedAt,
+				LastUsed:          time.Now(),
+				Commit:            w.SessionCommit,
+				Tokens:            w.SessionTokens,
+				PreviousSessionID: w.PreviousSessionID,
 			})
 		}
 		w.Stop()
@@ -272,10 +452,11 @@ func (s *Server) Stop() error {
 	s.ledger.Close()
 
 	// Clean up socket and PID file
-	os.Remove(s.cfg.SocketPath)
-	os.Remove(s.cfg.PIDPath())
+	os.Remove(s.config().SocketPath)
+	os.Remove(s.config().PIDPath())
 
-	return nil
+	s.log.Info("daemon stopped")
+	s.logFile.Close()
 }
 
 // Wait blocks until the server is stopped.
@@ -293,13 +474,15 @@ func (s *Server) acceptLoop() {
 			case <-s.ctx.Done():
 				return
 			default:
+				s.log.Warn("failed to accept connection", "error", err)
 				continue
 			}
 		}
 
 		s.clientsMu.Lock()
-		s.clients[conn] = &clientState{}
+		s.clients[conn] = &clientState{role: s.initialRole()}
 		s.clientsMu.Unlock()
+		s.touchActivity()
 
 		s.wg.Add(1)
 		go s.handleConnection(conn)
@@ -309,10 +492,12 @@ func (s *Server) acceptLoop() {
 func (s *Server) handleConnection(conn net.Conn) {
 	defer s.wg.Done()
 	defer func() {
+		s.cancelRequests(conn)
 		s.clientsMu.Lock()
 		delete(s.clients, conn)
 		s.clientsMu.Unlock()
 		conn.Close()
+		s.touchActivity()
 	}()
 
 	scanner := bufio.NewScanner(conn)
@@ -325,11 +510,18 @@ func (s *Server) handleConnection(conn net.Conn) {
 
 		var req protocol.Request
 		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			s.log.Debug("malformed request", "error", err)
 			s.sendError(conn, nil, protocol.ParseError, "Parse error")
 			continue
 		}
 
+		if cancellableMethods[req.Method] && req.ID != nil {
+			s.handleCancellable(&req, conn)
+			continue
+		}
+
 		resp := s.handleRequest(&req, conn)
+		s.logRequest(&req, resp)
 		if resp != nil {
 			s.sendResponse(conn, resp)
 		}
@@ -337,20 +529,31 @@ func (s *Server) handleConnection(conn net.Conn) {
 }
 
 func (s *Server) handleRequest(req *protocol.Request, conn net.Conn) *protocol.Response {
+	if resp := s.authorize(req, conn); resp != nil {
+		return resp
+	}
+
+	// Every entry point shares the same params rules, so handlers only see
+	// well-formed requests
+	if err := protocol.ValidateParams(req.Method, req.Params); err != nil {
+		return protocol.NewInvalidParamsResponse(req.ID, err)
+	}
+
 	switch req.Method {
 	case protocol.MethodStatus:
 		return s.handleStatus(req)
 	case protocol.MethodShutdown:
-		go func() {
-			time.Sleep(100 * time.Millisecond)
-			s.cancel()
-		}()
-		resp, _ := protocol.NewResponse(req.ID, map[string]string{"status": "shutting_down"})
-		return resp
+		return s.handleShutdown(req)
+	case protocol.MethodAuthLogin:
+		return s.handleAuthLogin(req, conn)
+	case protocol.MethodCancel:
+		return s.handleCancel(req, conn)
 	case protocol.MethodSubscribe:
 		return s.handleSubscribe(req, conn)
 	case protocol.MethodUnsubscribe:
 		return s.handleUnsubscribe(req, conn)
+	case protocol.MethodSyncState:
+		return s.handleSyncState(req)
 	case protocol.MethodTerritoryInit:
 		return s.handleTerritoryInit(req)
 	case protocol.MethodTerritoryStatus:
@@ -361,8 +564,16 @@ func (s *Server) handleRequest(req *protocol.Request, conn net.Conn) *protocol.R
 		return s.handleTerritoryAdd(req)
 	case protocol.MethodTerritorySetDevBranch:
 		return s.handleTerritorySetDevBranch(req)
+	case protocol.MethodTerritoryRemove:
+		return s.handleTerritoryRemove(req)
+	case protocol.MethodTerritoryIndex:
+		return s.handleTerritoryIndex(req)
 	case protocol.MethodWorkerAdd:
 		return s.handleWorkerAdd(req)
+	case protocol.MethodConfigReload:
+		return s.handleConfigReload(req)
+	case protocol.MethodOverview:
+		return s.handleOverview(req)
 	case protocol.MethodWorkerList:
 		return s.handleWorkerList(req)
 	case protocol.MethodWorkerStatus:
@@ -371,10 +582,22 @@ func (s *Server) handleRequest(req *protocol.Request, conn net.Conn) *protocol.R
 		return s.handleWorkerRemove(req)
 	case protocol.MethodWorkerDetail:
 		return s.handleWorkerDetail(req)
+	case protocol.MethodWorkerTranscript:
+		return s.handleWorkerTranscript(req)
 	case protocol.MethodWorkerMessage:
 		return s.handleWorkerMessage(req)
+	case protocol.MethodWorkerTakeover:
+		return s.handleWorkerTakeover(req)
+	case protocol.MethodWorkerLabel:
+		return s.handleWorkerLabel(req)
+	case protocol.MethodWorkerUnquarantine:
+		return s.handleWorkerUnquarantine(req)
+	case protocol.MethodWorkerClone:
+		return s.handleWorkerClone(req)
+	case protocol.MethodWorkerExec:
+		return s.handleWorkerExec(req)
 	case protocol.MethodJobAdd:
-		return s.handleJobAdd(req)
+		return s.handleJobAdd(req, conn)
 	case protocol.MethodJobList:
 		return s.handleJobList(req)
 	case protocol.MethodJobCancel:
@@ -387,14 +610,64 @@ func (s *Server) handleRequest(req *protocol.Request, conn net.Conn) *protocol.R
 		return s.handleJobReassign(req)
 	case protocol.MethodJobSetPriority:
 		return s.handleJobSetPriority(req)
+	case protocol.MethodJobConflicts:
+		return s.handleJobConflicts(req)
+	case protocol.MethodJobMerge:
+		return s.handleJobMerge(req)
+	case protocol.MethodJobAddBatch:
+		return s.handleJobAddBatch(req)
+	case protocol.MethodJobImportStatus:
+		return s.handleJobImportStatus(req)
+	case protocol.MethodJobImport:
+		return s.handleJobImport(req)
+	case protocol.MethodJobDiff:
+		return s.handleJobDiff(req)
+	case protocol.MethodJobWatch:
+		return s.handleJobWatch(req, true)
+	case protocol.MethodJobUnwatch:
+		return s.handleJobWatch(req, false)
+	case protocol.MethodJobApprove:
+		return s.handleJobApprove(req)
+	case protocol.MethodJobRerun:
+		return s.handleJobRerun(req)
+	case protocol.MethodJobDelegate:
+		return s.handleJobDelegate(req)
+	case protocol.MethodJobDecompose:
+		return s.handleJobDecompose(s.requestContext(req, conn), req, conn)
+	case protocol.MethodJobUnstick:
+		return s.handleJobUnstick(req)
+	case protocol.MethodConflictDetail:
+		return s.handleConflictDetail(req)
+	case protocol.MethodConflictAssign:
+		return s.handleConflictAssign(req)
+	case protocol.MethodConflictWorkspace:
+		return s.handleConflictWorkspace(req)
+	case protocol.MethodConflictAbandon:
+		return s.handleConflictAbandon(req)
+	case protocol.MethodTriageList:
+		return s.handleTriageList(req)
+	case protocol.MethodTriageResolve:
+		return s.handleTriageResolve(req)
 	case protocol.MethodQueueStatus:
 		return s.handleQueueStatus(req)
+	case protocol.MethodQueuePause:
+		return s.handleQueueState(req, job.QueuePaused)
+	case protocol.MethodQueueDrain:
+		return s.handleQueueState(req, job.QueueDraining)
+	case protocol.MethodQueueResume:
+		return s.handleQueueState(req, job.QueueRunning)
+	case protocol.MethodMergeQueue:
+		return s.handleMergeQueue(req)
 	case protocol.MethodReviewStart:
-		return s.handleReviewStart(req)
+		return s.handleReviewStart(s.requestContext(req, conn), req)
 	case protocol.MethodReviewStatus:
 		return s.handleReviewStatus(req)
 	case protocol.MethodReviewList:
 		return s.handleReviewList(req)
+	case protocol.MethodReviewApprove:
+		return s.handleReviewOverride(req, review.DecisionApproved)
+	case protocol.MethodReviewReject:
+		return s.handleReviewOverride(req, review.DecisionRejected)
 	case protocol.MethodOperationCreate:
 		return s.handleOperationCreate(req)
 	case protocol.MethodOperationStatus:
@@ -410,15 +683,61 @@ func (s *Server) handleRequest(req *protocol.Request, conn net.Conn) *protocol.R
 	case protocol.MethodOrderClear:
 		return s.handleOrderClear(req)
 	case protocol.MethodHandoffGenerate:
-		return s.handleHandoffGenerate(req)
+		return s.handleHandoffGenerate(s.requestContext(req, conn), req)
+	case protocol.MethodCapacityOff:
+		return s.handleCapacityOff(req)
+	case protocol.MethodCapacityOn:
+		return s.handleCapacityOn(req)
+	case protocol.MethodCapacityLimit:
+		return s.handleCapacityLimit(req)
+	case protocol.MethodCapacityStatus:
+		return s.handleCapacityStatus(req)
 	case protocol.MethodChatStart:
-		return s.handleChatStart(req)
+		return s.handleChatStart(s.requestContext(req, conn), req)
 	case protocol.MethodChatSend:
-		return s.handleChatSend(req)
+		return s.handleChatSend(s.requestContext(req, conn), req)
 	case protocol.MethodChatEnd:
 		return s.handleChatEnd(req)
 	case protocol.MethodChatHistory:
 		return s.handleChatHistory(req)
+	case protocol.MethodChatMode:
+		return s.handleChatMode(req)
+	case protocol.MethodChatPlan:
+		return s.handleChatPlan(req)
+	case protocol.MethodChatPlanAdd:
+		return s.handleChatPlanAdd(req)
+	case protocol.MethodChatPlanRemove:
+		return s.handleChatPlanRemove(req)
+	case protocol.MethodChatExecute:
+		return s.handleChatExecute(req)
+	case protocol.MethodChatDiscard:
+		return s.handleChatDiscard(req)
+	case protocol.MethodTemplateList:
+		return s.handleTemplateList(req)
+	case protocol.MethodTemplateGet:
+		return s.handleTemplateGet(req)
+	case protocol.MethodTemplateUse:
+		return s.handleTemplateUse(req)
+	case protocol.MethodTemplateCreate:
+		return s.handleTemplateCreate(req)
+	case protocol.MethodTemplateRemove:
+		return s.handleTemplateRemove(req)
+	case protocol.MethodTemplateExport:
+		return s.handleTemplateExport(req)
+	case protocol.MethodTemplateImport:
+		return s.handleTemplateImport(req)
+	case protocol.MethodLedgerQuery:
+		return s.handleLedgerQuery(req)
+	case protocol.MethodLedgerVerify:
+		return s.handleLedgerVerify(req)
+	case protocol.MethodCostsExport:
+		return s.handleCostsExport(req)
+	case protocol.MethodCleanupRun:
+		return s.handleCleanupRun(req)
+	case protocol.MethodDebugScheduler:
+		return s.handleDebugScheduler(req)
+	case protocol.MethodPresetList:
+		return s.handlePresetList(req)
 	default:
 		resp, _ := protocol.NewErrorResponse(req.ID, protocol.MethodNotFound, "Method not found", nil)
 		return resp
@@ -426,6 +745,12 @@ func (s *Server) handleRequest(req *protocol.Request, conn net.Conn) *protocol.R
 }
 
 func (s *Server) handleStatus(req *protocol.Request) *protocol.Response {
+	resp, _ := protocol.NewResponse(req.ID, s.status())
+	return resp
+}
+
+// status summarizes the daemon for status and sync.state.
+func (s *Server) status() protocol.StatusResult {
 	uptime := int64(time.Since(s.startedAt).Seconds())
 
 	workerCount := s.pool.Count()
@@ -451,7 +776,14 @@ func (s *Server) handleStatus(req *protocol.Request) *protocol.Response {
 		ActiveJobs:  activeJobs,
 		TotalCost:   totalCost,
 		TotalTokens: totalTokens,
+		Budget:      s.budgetStatus(),
+		MergeQueue:  s.merges.depth(),
+		Draining:    s.isDraining(),
+	}
+	if state := s.queueControl.State(); state != job.QueueRunning {
+		result.Queue = string(state)
 	}
+	result.QueueETA, _ = s.queueForecast()
 
 	s.mu.RLock()
 	if s.territory != nil {
@@ -459,8 +791,7 @@ func (s *Server) handleStatus(req *protocol.Request) *protocol.Response {
 	}
 	s.mu.RUnlock()
 
-	resp, _ := protocol.NewResponse(req.ID, result)
-	return resp
+	return result
 }
 
 func (s *Server) handleSubscribe(req *protocol.Request, conn net.Conn) *protocol.Response {
@@ -469,10 +800,21 @@ func (s *Server) handleSubscribe(req *protocol.Request, conn net.Conn) *protocol
 		json.Unmarshal(req.Params, &params)
 	}
 
+	var filter *ledger.Query
+	if params.Filter != nil {
+		q, err := LogFilterQuery(params.Filter)
+		if err != nil {
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, err.Error(), nil)
+			return resp
+		}
+		filter = &q
+	}
+
 	s.clientsMu.Lock()
 	if state, ok := s.clients[conn]; ok {
 		state.subscribed = true
 		state.events = params.Events
+		state.filter = filter
 	}
 	s.clientsMu.Unlock()
 
@@ -485,6 +827,7 @@ func (s *Server) handleUnsubscribe(req *protocol.Request, conn net.Conn) *protoc
 	if state, ok := s.clients[conn]; ok {
 		state.subscribed = false
 		state.events = nil
+		state.filter = nil
 	}
 	s.clientsMu.Unlock()
 
@@ -538,6 +881,9 @@ func (s *Server) broadcastEvent(event ledger.Event) {
 				continue
 			}
 		}
+		if state.filter != nil && !state.filter.Matches(event) {
+			continue
+		}
 
 		conn.Write(data)
 	}
@@ -601,16 +947,88 @@ func (sched *scheduler) run(wg *sync.WaitGroup) {
 
 // processQueue assigns ready jobs to available workers.
 func (sched *scheduler) processQueue() {
+	trace := sched.server.schedTrace
+	tick := protocol.SchedulerTick{Time: time.Now()}
+	defer func() {
+		// Passes with nothing to do are not worth keeping
+		if tick.Skipped != "" || len(tick.Jobs) > 0 {
+			trace.record(tick)
+		}
+	}()
+	tracing := trace.enabled()
+	decide := func(d protocol.SchedulerJobDecision) {
+		if tracing {
+			tick.Jobs = append(tick.Jobs, d)
+		}
+	}
+
+	// A draining daemon or a held queue lets running work finish without
+	// starting more
+	if reason := sched.server.assignmentsHeld(); reason != "" {
+		tick.Skipped = reason
+		return
+	}
+
+	// A worker's slots are not taken until its jobs start, after the pass
+	assigned := make(map[string]int)
+
 	ready := sched.queue.GetReady()
 	for _, j := range ready {
-		w := sched.pool.FindBestWorker(j)
+		decision := protocol.SchedulerJobDecision{Job: j.ID, Description: j.Description}
+
+		// Jobs over the daily or per-job budget stay queued
+		if err := sched.server.checkJobBudget(j); err != nil {
+			decision.Reason = err.Error()
+			decide(decision)
+			continue
+		}
+
+		// Operations can limit how many of their jobs run at once
+		if !sched.server.operationAllows(j) {
+			decision.Reason = "operation " + j.Operation + " is at its limit"
+			decide(decision)
+			continue
+		}
+
+		w, evals := sched.pool.TraceBestWorker(j, func(w *worker.Worker) string {
+			if assigned[w.ID] > 0 && assigned[w.ID] >= w.FreeSlots() {
+				return "assigned a job this pass"
+			}
+			if err := sched.server.checkWorkerQuarantine(w); err != nil {
+				return err.Error()
+			}
+			if err := sched.server.checkWorkerBudget(w); err != nil {
+				return err.Error()
+			}
+			if err := sched.server.checkWorkerCapacity(w); err != nil {
+				return err.Error()
+			}
+			return ""
+		})
+		for _, e := range evals {
+			decision.Workers = append(decision.Workers, protocol.SchedulerWorkerEval{
+				Worker: e.Worker,
+				Score:  e.Score,
+				Reason: e.Reason,
+			})
+		}
 		if w == nil {
-			continue // No available worker
+			decision.Reason = "no worker available"
+			decide(decision)
+			continue
 		}
 
-		// Remove from queue and mark as queued
+		// Remove from queue and mark as queued; a job cancelled in the
+		// meantime is dropped
 		sched.queue.Remove(j.ID)
-		j.Queue()
+		if err := j.Queue(); err != nil {
+			decision.Reason = err.Error()
+			decide(decision)
+			continue
+		}
+		assigned[w.ID]++
+		decision.Assigned = w.Name
+		decide(decision)
 		sched.jobs.Save(j) // Persist queued state
 
 		sched.ledger.Append(ledger.EventJobQueued, ledger.JobEventData{
@@ -627,8 +1045,25 @@ func (sched *scheduler) processQueue() {
 
 // onJobComplete is called when a job completes successfully.
 func (s *Server) onJobComplete(j *job.Job) {
+	if w, exists := s.pool.GetByID(j.Worker); exists {
+		s.recordWorkerSuccess(w)
+	}
+
+	// The plan phase of a plan-first job waits for its plan to be approved
+	if j.NeedsPlan() {
+		s.planReady(j)
+		return
+	}
+
+	// A worker that stopped to ask a question has nothing to merge yet
+	if question := s.pendingQuestion(j); question != "" {
+		s.flagForAttention(j, job.AttentionQuestion, question)
+		return
+	}
+
 	s.queue.NotifyCompletion(j.ID)
 	s.jobs.Save(j) // Persist final state
+	s.operationJobDone(j, false)
 
 	// Get worker name for logging
 	var workerName string
@@ -641,18 +1076,31 @@ func (s *Server) onJobComplete(j *job.Job) {
 		Description: j.Description,
 		Worker:      j.Worker,
 		WorkerName:  workerName,
+		Model:       j.GetModelUsed(),
 	})
 
-	// Send notification
-	s.notifier.NotifyJobComplete(j.ID, j.Description, workerName)
-
-	// Merge the job's worktree into the target branch and cleanup
-	if err := s.mergeAndCleanupJobWorktree(j); err != nil {
+	// Classify the job and send notification, with a summary of the
+	// changes, before the worktree is merged away
+	outcome := s.classifyJob(j)
+	s.notifyJobComplete(j, workerName)
+	s.reportDelegation(j, workerName, "")
+
+	// Merge the job's worktree into the target branch and cleanup, unless
+	// the branch is to be kept as it is
+	if j.ShouldSkipMerge() {
+		s.keepJobBranch(j)
+		s.ledger.Append(ledger.EventType("job.merge_skipped"), ledger.JobEventData{
+			ID:          j.ID,
+			Description: fmt.Sprintf("Branch %s kept unmerged", j.GetBranch()),
+		})
+	} else if err := s.mergeAndCleanupJobWorktree(j); err != nil {
+		s.log.Error("post-completion merge failed", "job", j.ID, "error", err)
 		s.ledger.Append(ledger.EventType("job.post_complete_error"), ledger.JobEventData{
 			ID:    j.ID,
 			Error: fmt.Sprintf("post-completion merge failed: %v", err),
 		})
 	}
+	s.retireJobWorker(j)
 
 	// Trigger auto-review if enabled
 	s.mu.RLock()
@@ -660,22 +1108,30 @@ func (s *Server) onJobComplete(j *job.Job) {
 	coord := s.reviewCoordinator
 	s.mu.RUnlock()
 
-	if t != nil && t.Config.AutoReview && coord != nil {
+	if t != nil && t.Config.AutoReview && coord != nil && !j.ShouldSkipReview() && s.sampleReview(j, outcome) {
 		w, exists := s.pool.GetByID(j.Worker)
 		if exists {
-			go coord.StartReview(s.ctx, j, w)
+			// Started before returning so that a drain sees the review
+			coord.StartReview(s.ctx, j, w)
+			return
 		}
 	}
+
+	// An unreviewed revision settles the work it revises
+	review.SettleRevised(s.jobs, j)
 }
 
 // onJobFail is called when a job fails.
 func (s *Server) onJobFail(j *job.Job, err error) {
 	s.queue.NotifyFailure(j.ID)
 	s.jobs.Save(j) // Persist final state
+	review.SettleRevised(s.jobs, j)
+	s.operationJobDone(j, true)
 
 	// Get worker name for logging
 	var workerName string
-	if w, exists := s.pool.GetByID(j.Worker); exists {
+	w, exists := s.pool.GetByID(j.Worker)
+	if exists {
 		workerName = w.Name
 	}
 
@@ -684,11 +1140,31 @@ func (s *Server) onJobFail(j *job.Job, err error) {
 		Description: j.Description,
 		Worker:      j.Worker,
 		WorkerName:  workerName,
+		Model:       j.GetModelUsed(),
 		Error:       err.Error(),
 	})
 
 	// Send notification
-	s.notifier.NotifyJobFailed(j.ID, j.Description, workerName, err.Error())
+	s.notifier.NotifyJobFailed(j.ID, j.Description, workerName, err.Error(), j.Priority)
+	s.reportDelegation(j, workerName, err.Error())
+
+	// A worker failing job after job is quarantined
+	if exists {
+		s.recordWorkerFailure(w, err.Error())
+	}
+
+	// An ephemeral worker's failed work is kept on its branch for inspection
+	if w := s.findWorkerForJob(j.ID); w != nil && w.Ephemeral {
+		s.keepJobBranch(j)
+		s.retireWhenIdle(w)
+	}
+
+	// The conflict this job was resolving is now up to the boss
+	if orig, exists := s.jobs.Get(j.GetResolvesConflict()); exists && orig.IsUnmerged() &&
+		orig.GetStatus() != job.StatusNeedsAttention {
+		s.flagForAttention(orig, job.AttentionMergeConflict,
+			fmt.Sprintf("resolver job %s failed: %v", display.ShortID(j.ID), err))
+	}
 }
 
 // initReviewCoordinator initializes the review coordinator for the current territory.
@@ -697,21 +1173,27 @@ func (s *Server) initReviewCoordinator() {
 		return
 	}
 
+	gates := s.territoryGates()
 	s.reviewCoordinator = review.NewCoordinator(review.CoordinatorConfig{
 		GitManager: s.territory.GitManager(),
 		JobStore:   s.jobs,
 		JobQueue:   s.queue,
 		Ledger:     s.ledger,
 		ClaudeConfig: review.ConsigliereConfig{
-			Binary:   s.cfg.Claude.Binary,
-			Model:    s.cfg.Claude.Model,
+			Binary:   s.config().Claude.Binary,
+			Model:    s.jobConfig().Claude.Model,
 			MaxTurns: 10,
 		},
 		GateConfig: review.GateRunnerConfig{
-			TestCommand:  s.territory.Config.TestCommand,
-			BuildCommand: s.territory.Config.BuildCommand,
+			TestCommand:  gates.TestCommand,
+			BuildCommand: gates.BuildCommand,
 		},
-		BaseBranch: s.territory.MergeTargetBranch(s.cfg.Git.DefaultMergeBranch),
+		BaseBranch:       s.territory.MergeTargetBranch(s.config().Git.DefaultMergeBranch),
+		Summarizer:       s.summarizer,
+		MaxRejections:    s.config().Review.MaxRejections,
+		MaxRounds:        s.config().Review.MaxRounds,
+		OnNeedsAttention: s.flagForAttention,
+		OnReviewed:       s.refreshPullRequests,
 	})
 }
 
@@ -729,7 +1211,8 @@ func (s *Server) restoreWorkers() {
 	for _, info := range pending {
 		// Verify worktree still exists
 		if _, err := os.Stat(info.Worktree); os.IsNotExist(err) {
-			continue // Skip workers with missing worktrees
+			s.log.Warn("not restoring worker, its worktree is missing", "worker", info.Name, "worktree", info.Worktree)
+			continue
 		}
 
 		// Create worktree reference
@@ -747,30 +1230,62 @@ func (s *Server) restoreWorkers() {
 			Role:     info.Role,
 			Worktree: wt,
 			ClaudeConfig: claude.ClientConfig{
-				Binary:   s.cfg.Claude.Binary,
-				Model:    s.cfg.Claude.Model,
-				MaxTurns: s.cfg.Claude.MaxTurns,
+				Binary:   s.config().Claude.Binary,
+				Model:    s.jobConfig().Claude.Model,
+				MaxTurns: s.jobConfig().Claude.MaxTurns,
+
+				MCPConfig:     s.delegationMCPConfig(info.Name, info.Role, ""),
+				StrictOutput:  s.config().Claude.StrictOutput,
+				OnSchemaDrift: s.logSchemaDrift,
 			},
 			OnEvent: func(e worker.Event) {
 				s.ledger.Append(ledger.EventType("worker."+e.Type), e)
 			},
-			OnJobComplete: s.onJobComplete,
-			OnJobFail:     s.onJobFail,
-			OnCostUpdate:  s.onCostUpdate,
+			OnJobComplete:  s.onJobComplete,
+			OnJobFail:      s.onJobFail,
+			OnCostUpdate:   s.onCostUpdate,
+			OnClaudeEvent:  s.onClaudeEvent,
+			ResumeCheck:    s.resumeCheck(),
+			SnapshotPolicy: s.snapshotPolicy(),
+			Identity:       s.commitIdentity(info.Name),
+			Prompts:        s.promptSource(),
+			Orders:         s.inheritedOrders,
+			Fallback:       s.fallbackModel,
+			Model:          s.defaultModel,
+			Context:        s.relevantFiles,
+			RepoMap:        s.repoMap,
+			Knowledge:      s.knowledge,
+			Slots:          s.config().Workers.SlotsFor(string(info.Role)),
+			MCPConfig:      s.jobMCPConfig(info.Name, info.Role),
 		})
 
 		// Restore persisted state
 		w.ID = info.ID
 		w.SessionID = info.SessionID
+		if sess, err := s.sessions.Load(info.SessionID); err == nil {
+			w.SessionCommit = sess.Commit
+			w.SessionTokens = sess.Tokens
+			w.PreviousSessionID = sess.PreviousSessionID
+		}
 		w.StandingOrders = info.StandingOrders
+		w.Labels = info.Labels
 		w.JobsCompleted = info.JobsCompleted
 		w.JobsFailed = info.JobsFailed
+		w.RecentFailures = info.RecentFailures
+		w.Quarantined = info.Quarantine
 
 		// Add to pool and start
 		if err := s.pool.Add(w); err != nil {
-			continue // Skip if already exists or other error
+			s.log.Warn("failed to restore worker", "worker", info.Name, "error", err)
+			continue
 		}
 		w.Start()
+
+		// An ephemeral worker's job did not survive the restart
+		if info.Ephemeral {
+			w.Ephemeral = true
+			s.retireWorker(w)
+		}
 	}
 
 	s.pool.ClearPending()
@@ -781,12 +1296,22 @@ func (s *Server) requeueJobs() {
 	for _, j := range s.jobs.List() {
 		status := j.GetStatus()
 		switch status {
-		case job.StatusPending, job.StatusQueued:
-			// Re-queue for execution
+		case job.StatusPending:
+			s.queue.Enqueue(j)
+		case job.StatusQueued:
+			// No worker got to start it; queue it again
+			if err := j.Requeue(); err != nil {
+				s.log.Warn("failed to requeue job", "job", j.ID, "error", err)
+				continue
+			}
+			s.jobs.Save(j)
 			s.queue.Enqueue(j)
 		case job.StatusRunning:
 			// Job was interrupted - mark as failed and re-queue
-			j.Fail("daemon restarted during execution")
+			if err := j.Fail("daemon restarted during execution"); err != nil {
+				s.log.Warn("failed to fail interrupted job", "job", j.ID, "error", err)
+				continue
+			}
 			s.jobs.Save(j)
 		}
 	}
@@ -794,9 +1319,14 @@ func (s *Server) requeueJobs() {
 
 // startLookout initializes and starts the health monitor.
 func (s *Server) startLookout() {
+	thresholds := s.config().Lookout.Thresholds
 	s.lookout = worker.NewLookout(worker.LookoutConfig{
-		Pool:   s.pool,
-		Ledger: s.ledger,
+		Pool:              s.pool,
+		Ledger:            s.ledger,
+		WarningThreshold:  thresholds.Warning,
+		ErrorThreshold:    thresholds.Error,
+		CriticalThreshold: thresholds.Critical,
+		Roles:             s.stuckThresholdRoles(),
 		OnStuck: func(w *worker.Worker, severity worker.StuckSeverity) {
 			// Log the stuck worker event
 			s.ledger.Append(ledger.EventWorkerError, ledger.WorkerEventData{
@@ -808,10 +1338,112 @@ func (s *Server) startLookout() {
 			// Send notification
 			s.notifier.NotifyWorkerStuck(w.Name, string(severity))
 		},
+		Policy:    remediationPolicy(s.config().Lookout.Remediation),
+		Overrides: s.remediationOverrides(),
+		Remediate: s.remediateStuckWorker,
 	})
 	s.lookout.Start(s.ctx)
 }
 
+// remediationPolicy converts configured remediation actions to a policy.
+func remediationPolicy(r config.RemediationConfig) worker.RemediationPolicy {
+	return worker.RemediationPolicy{
+		worker.SeverityWarning:  worker.RemediationAction(r.Warning),
+		worker.SeverityError:    worker.RemediationAction(r.Error),
+		worker.SeverityCritical: worker.RemediationAction(r.Critical),
+	}
+}
+
+// remediationOverrides returns the per-worker remediation policies.
+func (s *Server) remediationOverrides() map[string]worker.RemediationPolicy {
+	overrides := make(map[string]worker.RemediationPolicy, len(s.config().Lookout.Workers))
+	for name := range s.config().Lookout.Workers {
+		overrides[name] = remediationPolicy(s.config().RemediationFor(name))
+	}
+	return overrides
+}
+
+// stuckThresholdRoles converts the configured stuck thresholds of roles
+// for the Lookout.
+func (s *Server) stuckThresholdRoles() map[worker.Role]worker.StuckThresholds {
+	roles := make(map[worker.Role]worker.StuckThresholds, len(s.config().Lookout.Roles))
+	for role := range s.config().Lookout.Roles {
+		t := s.config().ThresholdsFor(role)
+		roles[worker.Role(role)] = worker.StuckThresholds{
+			Warning:  t.Warning,
+			Error:    t.Error,
+			Critical: t.Critical,
+		}
+	}
+	return roles
+}
+
+// reloadStuckThresholds hands the lookout the stuck thresholds of a
+// reloaded config.
+func (s *Server) reloadStuckThresholds() {
+	if s.lookout == nil {
+		return
+	}
+	t := s.config().Lookout.Thresholds
+	s.lookout.SetThresholds(worker.StuckThresholds{
+		Warning:  t.Warning,
+		Error:    t.Error,
+		Critical: t.Critical,
+	}, s.stuckThresholdRoles())
+}
+
+// stuckStopGrace is how long a stuck Claude process gets to exit after
+// SIGINT before it is killed.
+const stuckStopGrace = 10 * time.Second
+
+// remediateStuckWorker stops a stuck worker's Claude processes and runs
+// their jobs again, on the same worker for restart or on any worker for
+// requeue.
+func (s *Server) remediateStuckWorker(w *worker.Worker, action worker.RemediationAction, reason string) error {
+	jobs := w.GetJobs()
+	if len(jobs) == 0 {
+		return fmt.Errorf("worker has no job")
+	}
+
+	for _, j := range jobs {
+		if err := s.rerunStuckJob(w, j, action, reason); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rerunStuckJob aborts one of a stuck worker's jobs and runs it again.
+func (s *Server) rerunStuckJob(w *worker.Worker, j *job.Job, action worker.RemediationAction, reason string) error {
+	if err := w.AbortJob(j.ID, reason, stuckStopGrace); err != nil {
+		return err
+	}
+	if err := j.Reset(); err != nil {
+		return err
+	}
+
+	switch action {
+	case worker.ActionRestart:
+		if err := j.Queue(); err != nil {
+			return err
+		}
+		s.jobs.Save(j)
+		s.ledger.Append(ledger.EventJobQueued, ledger.JobEventData{
+			ID:          j.ID,
+			Description: j.Description,
+			Worker:      w.ID,
+			WorkerName:  w.Name,
+		})
+		// The job worktree is kept, so the rerun picks up where it stopped
+		s.executeJobWithWorktree(w, j)
+
+	default:
+		s.queue.Enqueue(j)
+		s.jobs.Save(j)
+	}
+	return nil
+}
+
 // stopLookout stops the health monitor.
 func (s *Server) stopLookout() {
 	if s.lookout != nil {
@@ -819,20 +1451,62 @@ func (s *Server) stopLookout() {
 	}
 }
 
-// startCleaner initializes and starts the resource cleanup service.
-func (s *Server) startCleaner() {
-	var gitManager *git.Manager
+// resumeCheck returns the drift limits for resuming stored worker sessions.
+func (s *Server) resumeCheck() worker.ResumeCheck {
+	return worker.ResumeCheck{
+		MaxCommits: s.config().Claude.ResumeMaxCommits,
+		MaxFiles:   s.config().Claude.ResumeMaxFiles,
+		MaxTokens:  s.config().Claude.SessionMaxTokens,
+	}
+}
+
+// promptSource returns where workers find their role prompt templates.
+func (s *Server) promptSource() *worker.PromptSource {
+	src := &worker.PromptSource{Templates: s.config().Prompts}
 	s.mu.RLock()
 	if s.territory != nil {
-		gitManager = s.territory.GitManager()
+		src.Dir = s.territory.PromptsPath()
 	}
 	s.mu.RUnlock()
+	return src
+}
+
+// snapshotPolicy returns how workers handle uncommitted job worktree changes.
+func (s *Server) snapshotPolicy() worker.SnapshotPolicy {
+	return worker.SnapshotPolicy{
+		Fail:    s.config().Git.UncommittedChanges == "fail",
+		Message: s.config().Git.AutoCommitMessage,
+	}
+}
+
+// startNotificationDigest sends the daily digest of notifications that
+// rules routed to it.
+func (s *Server) startNotificationDigest() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.notifier.RunDigest(s.ctx)
+	}()
+}
 
+// startCleaner initializes and starts the resource cleanup service.
+func (s *Server) startCleaner() {
+	policy := s.config().Cleanup
 	s.cleaner = worker.NewCleaner(worker.CleanerConfig{
-		Pool:         s.pool,
-		GitManager:   gitManager,
-		SessionStore: s.sessions,
-		Ledger:       s.ledger,
+		PatrolInterval: policy.Interval,
+		SessionMaxAge:  policy.SessionMaxAge,
+		WorktreeMaxAge: policy.MaxAge,
+		MaxDiskUsage:   int64(policy.MaxDiskUsage) << 20,
+		Pool:           s.pool,
+		Jobs:           s.jobs,
+		GitManager:     s.gitManager,
+		SessionStore:   s.sessions,
+		Ledger:         s.ledger,
+		OnCleanup: func(stats worker.CleanupStats) {
+			for _, e := range stats.Errors {
+				s.log.Warn("cleanup failed", "error", e)
+			}
+		},
 	})
 	s.cleaner.Start(s.ctx)
 }
@@ -859,13 +1533,40 @@ func (s *Server) createJobWorktree(j *job.Job) error {
 	}
 
 	gitMgr := t.GitManager()
-	baseBranch := t.MergeTargetBranch(s.cfg.Git.DefaultMergeBranch)
+	baseBranch := t.MergeTargetBranch(s.config().Git.DefaultMergeBranch)
+
+	// Kept worktrees already have the workspace files
+	_, statErr := os.Stat(gitMgr.GetJobWorktreePath(j.ID))
+	fresh := os.IsNotExist(statErr)
 
 	wt, err := gitMgr.CreateJobWorktree(j.ID, baseBranch)
 	if err != nil {
 		return err
 	}
 
+	if fresh {
+		shortID := j.ID
+		if len(shortID) > 8 {
+			shortID = shortID[:8]
+		}
+		copied, err := t.ApplyWorkspace(wt.Path, map[string]string{
+			"job_id":      j.ID,
+			"short_id":    shortID,
+			"description": j.Description,
+			"branch":      wt.Branch,
+			"base_branch": baseBranch,
+		})
+		if err != nil {
+			return err
+		}
+		if len(copied) > 0 {
+			s.ledger.Append(ledger.EventType("job.workspace_applied"), map[string]interface{}{
+				"job_id": j.ID,
+				"files":  copied,
+			})
+		}
+	}
+
 	j.SetWorktree(wt.Path, wt.Branch)
 	s.jobs.Save(j)
 
@@ -876,6 +1577,9 @@ func (s *Server) createJobWorktree(j *job.Job) error {
 // creates worktree, logs events, executes job, and handles failures.
 // This consolidates the duplicated logic from handlers and scheduler.
 func (s *Server) executeJobWithWorktree(w *worker.Worker, j *job.Job) {
+	// Don't start a kept worktree against stale code
+	s.refreshStaleJob(j)
+
 	// Create job worktree before starting
 	if err := s.createJobWorktree(j); err != nil {
 		s.ledger.Append(ledger.EventJobFailed, ledger.JobEventData{
@@ -885,8 +1589,12 @@ func (s *Server) executeJobWithWorktree(w *worker.Worker, j *job.Job) {
 			WorkerName:  w.Name,
 			Error:       fmt.Sprintf("failed to create job worktree: %v", err),
 		})
-		j.Fail(fmt.Sprintf("failed to create job worktree: %v", err))
+		if err := j.Fail(fmt.Sprintf("failed to create job worktree: %v", err)); err != nil {
+			s.log.Warn("failed to mark job failed", "job", j.ID, "error", err)
+		}
 		s.jobs.Save(j)
+		s.recordWorkerFailure(w, fmt.Sprintf("failed to create job worktree: %v", err))
+		s.retireWhenIdle(w)
 		return
 	}
 
@@ -925,10 +1633,10 @@ func (s *Server) mergeAndCleanupJobWorktree(j *job.Job) error {
 	}
 
 	gitMgr := t.GitManager()
-	targetBranch := t.MergeTargetBranch(s.cfg.Git.DefaultMergeBranch)
 
 	// First, remove the worktree (must be done before merging to release the branch)
 	if err := gitMgr.RemoveJobWorktree(j.ID, true); err != nil {
+		s.log.Warn("failed to remove job worktree", "job", j.ID, "error", err)
 		s.ledger.Append(ledger.EventType("job.worktree_cleanup_error"), ledger.JobEventData{
 			ID:    j.ID,
 			Error: fmt.Sprintf("failed to remove worktree: %v", err),
@@ -941,57 +1649,73 @@ func (s *Server) mergeAndCleanupJobWorktree(j *job.Job) error {
 	}
 
 	// Merge the job branch into the target branch
-	result, err := gitMgr.Merge(jobBranch, targetBranch)
-	if err != nil {
-		s.ledger.Append(ledger.EventType("job.merge_error"), ledger.JobEventData{
-			ID:    j.ID,
-			Error: fmt.Sprintf("failed to merge: %v", err),
-		})
-		return err
+	_, err := s.mergeJobBranch(j, true)
+	return err
+}
+
+// cleanupCancelledJobWorktree removes a cancelled job's worktree and branch.
+// Unlike a completed job, nothing is merged.
+func (s *Server) cleanupCancelledJobWorktree(j *job.Job) {
+	s.mu.RLock()
+	t := s.territory
+	s.mu.RUnlock()
+
+	jobBranch := j.GetBranch()
+	if t == nil || jobBranch == "" {
+		return
 	}
 
-	if !result.Success {
-		s.ledger.Append(ledger.EventType("job.merge_conflict"), ledger.JobEventData{
+	gitMgr := t.GitManager()
+	if err := gitMgr.RemoveJobWorktree(j.ID, true); err != nil {
+		s.log.Warn("failed to remove job worktree", "job", j.ID, "error", err)
+		s.ledger.Append(ledger.EventType("job.worktree_cleanup_error"), ledger.JobEventData{
 			ID:    j.ID,
-			Error: result.Message,
+			Error: fmt.Sprintf("failed to remove worktree: %v", err),
 		})
-		return fmt.Errorf("merge conflict: %s", result.Message)
 	}
-
-	s.ledger.Append(ledger.EventType("job.merged"), ledger.JobEventData{
-		ID:          j.ID,
-		Description: fmt.Sprintf("Merged into %s (commit: %s)", targetBranch, result.MergeCommit),
-	})
-
-	// Delete the job branch after successful merge
 	if err := gitMgr.DeleteBranch(jobBranch, true); err != nil {
 		s.ledger.Append(ledger.EventType("job.branch_cleanup_error"), ledger.JobEventData{
 			ID:    j.ID,
 			Error: fmt.Sprintf("failed to delete branch: %v", err),
 		})
-		// Non-fatal, continue
 	}
 
-	// Clear worktree info from job
 	j.ClearWorktree()
 	s.jobs.Save(j)
+}
 
-	return nil
+// onClaudeEvent records a worker's Claude event in the job transcript.
+func (s *Server) onClaudeEvent(workerName string, j *job.Job, event claude.Event) {
+	s.transcripts.Append(j.ID, workerName, event)
 }
 
-// onCostUpdate is called when a worker reports a cost update.
+// logSchemaDrift logs a change in the claude CLI's output format the first
+// time any session shows it, so an upgrade that breaks parsing is noticed.
+func (s *Server) logSchemaDrift(d claude.SchemaDrift) {
+	if _, seen := s.schemaDrift.LoadOrStore(d, true); seen {
+		return
+	}
+	s.log.Warn("claude output drifted from its schema", "schema", d.Schema, "type", d.Type,
+		"field", d.Field, "missing", d.Missing)
+}
+
+// onCostUpdate is called when a worker reports the cost of a job.
 // It aggregates costs and checks budget thresholds.
-func (s *Server) onCostUpdate(workerID, workerName, cost string, tokens int) {
+func (s *Server) onCostUpdate(workerID, workerName, jobID, cost string, tokens int) {
 	// Log cost event
 	s.ledger.Append(ledger.EventCostRecord, ledger.CostEventData{
+		JobID:      jobID,
 		WorkerID:   workerID,
 		WorkerName: workerName,
 		Cost:       cost,
 		Tokens:     tokens,
 	})
 
+	// Enforce spend limits
+	s.recordSpend(jobID, workerName, parseCost(cost))
+
 	// Check budget threshold
-	budgetLimit := s.cfg.Notifications.Budget.Limit
+	budgetLimit := s.jobConfig().Notifications.Budget.Limit
 	if budgetLimit <= 0 {
 		return // No budget limit configured
 	}
@@ -1016,7 +1740,7 @@ func (s *Server) onCostUpdate(workerID, workerName, cost string, tokens int) {
 	}
 
 	// Check warning threshold
-	warningThreshold := s.cfg.Notifications.Budget.WarningThreshold
+	warningThreshold := s.jobConfig().Notifications.Budget.WarningThreshold
 	if warningThreshold <= 0 {
 		warningThreshold = 80 // Default to 80%
 	}
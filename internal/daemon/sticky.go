@@ -0,0 +1,66 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+	"cosa/internal/worker"
+)
+
+// stickyWorkerName resolves the worker a new job is pinned to, by name or
+// ID, to its name. Only workers that run jobs can be pinned to.
+func (s *Server) stickyWorkerName(nameOrID string) (string, error) {
+	w, exists := s.pool.Get(nameOrID)
+	if !exists {
+		w, exists = s.pool.GetByID(nameOrID)
+	}
+	if !exists {
+		return "", fmt.Errorf("unknown sticky worker: %s", nameOrID)
+	}
+	if w.Role != worker.RoleSoldato && w.Role != worker.RoleCapo {
+		return "", fmt.Errorf("cannot pin a job to %s: a %s does not run jobs", w.Name, w.Role)
+	}
+	return w.Name, nil
+}
+
+// checkSticky returns an error if j is pinned to a worker other than w.
+func checkSticky(j *job.Job, w *worker.Worker) error {
+	if sticky := j.GetStickyWorker(); sticky != "" && sticky != w.Name {
+		return fmt.Errorf("job is pinned to %s; unstick it first", sticky)
+	}
+	return nil
+}
+
+func (s *Server) handleJobUnstick(req *protocol.Request) *protocol.Response {
+	var params protocol.JobUnstickParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	j, exists := s.jobs.Get(params.ID)
+	if !exists {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrJobNotFound, "job not found", nil)
+		return resp
+	}
+
+	// A queued job goes to the next idle worker from here on
+	sticky := j.GetStickyWorker()
+	if sticky != "" {
+		j.SetStickyWorker("")
+		s.jobs.Save(j)
+		s.ledger.Append(ledger.EventType("job.unstuck"), ledger.JobEventData{
+			ID:          j.ID,
+			Description: j.Description,
+			WorkerName:  sticky,
+		})
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, protocol.JobUnstickResult{
+		ID:     j.ID,
+		Worker: sticky,
+	})
+	return resp
+}
@@ -0,0 +1,52 @@
+package daemon
+
+import (
+	"fmt"
+
+	"cosa/internal/git"
+	"cosa/internal/job"
+)
+
+// Budgets for diff summaries outside of reviews, in bytes.
+const (
+	notifySummaryBudget  = 300
+	handoffSummaryBudget = 2000
+)
+
+// jobWorktreeDiff returns the diff of a job's worktree against the merge
+// target.
+func (s *Server) jobWorktreeDiff(j *job.Job) (*git.DiffResult, error) {
+	s.mu.RLock()
+	t := s.territory
+	s.mu.RUnlock()
+
+	if t == nil {
+		return nil, fmt.Errorf("territory not initialized")
+	}
+	if j.GetWorktree() == "" {
+		return nil, fmt.Errorf("job has no worktree")
+	}
+
+	return t.GitManager().GetDiff(j.GetWorktree(), t.MergeTargetBranch(s.config().Git.DefaultMergeBranch))
+}
+
+// notifyJobComplete sends the job completed notification with a short
+// summary of the job's changes. The diff is taken right away, since the
+// worktree is about to be merged and removed, and summarized in the
+// background.
+func (s *Server) notifyJobComplete(j *job.Job, workerName string) {
+	if !s.jobConfig().Notifications.OnJobComplete {
+		return
+	}
+
+	diff, err := s.jobWorktreeDiff(j)
+	if err != nil || len(diff.FilesChanged) == 0 {
+		s.notifier.NotifyJobComplete(j.ID, j.Description, workerName, "", j.Priority)
+		return
+	}
+
+	go func() {
+		summary := s.summarizer.Summarize(s.ctx, diff, notifySummaryBudget)
+		s.notifier.NotifyJobComplete(j.ID, j.Description, workerName, summary.Text, j.Priority)
+	}()
+}
@@ -0,0 +1,83 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"cosa/internal/job"
+	"cosa/internal/protocol"
+)
+
+func (s *Server) handleSyncState(req *protocol.Request) *protocol.Response {
+	var params protocol.SyncStateParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	// Take the version before the snapshot, so that the snapshot is at least
+	// as new as the version it carries
+	result := protocol.SyncStateResult{
+		Epoch:   s.startedAt.UnixNano(),
+		Version: s.ledger.Seq(),
+	}
+	if params.Epoch == result.Epoch && params.Version == result.Version {
+		result.Unchanged = true
+		resp, _ := protocol.NewResponse(req.ID, result)
+		return resp
+	}
+
+	status := s.status()
+	result.Status = &status
+	result.Workers = s.workerInfos()
+	result.Jobs = s.jobInfos(params.Identity)
+	result.Reviews = s.activeReviews()
+	result.Alerts = s.alerts(result.Jobs)
+
+	resp, _ := protocol.NewResponse(req.ID, result)
+	return resp
+}
+
+// alerts lists the conditions waiting for the user: jobs needing attention,
+// stuck workers and a spent budget.
+func (s *Server) alerts(jobs []protocol.JobInfo) []protocol.SyncAlert {
+	var alerts []protocol.SyncAlert
+	for _, j := range jobs {
+		if j.Status != string(job.StatusNeedsAttention) {
+			continue
+		}
+		message := j.AttentionReason
+		if message == "" {
+			message = j.Description
+		}
+		alerts = append(alerts, protocol.SyncAlert{
+			Kind:    protocol.AlertAttention,
+			Subject: j.ID,
+			Message: message,
+			Since:   j.AttentionSince,
+		})
+	}
+
+	if s.lookout != nil {
+		for id, severity := range s.lookout.Warnings() {
+			w, ok := s.pool.GetByID(id)
+			if !ok {
+				continue
+			}
+			alerts = append(alerts, protocol.SyncAlert{
+				Kind:     protocol.AlertStuck,
+				Subject:  w.Name,
+				Message:  fmt.Sprintf("%s has made no progress", w.Name),
+				Severity: string(severity),
+				Since:    w.GetLastActivity().Unix(),
+			})
+		}
+	}
+
+	if budget := s.budgetStatus(); budget.Exceeded {
+		alerts = append(alerts, protocol.SyncAlert{
+			Kind:    protocol.AlertBudget,
+			Message: fmt.Sprintf("daily budget spent ($%.2f of $%.2f)", budget.SpentToday, budget.DailyLimit),
+		})
+	}
+	return alerts
+}
@@ -0,0 +1,147 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+	"cosa/internal/worker"
+)
+
+// takeoverGrace is how long a worker handing over its job gets to wind
+// down when no grace period is given.
+const takeoverGrace = 10 * time.Second
+
+// WorkerTakeoverEventData is the data of a worker.takeover event.
+type WorkerTakeoverEventData struct {
+	JobID        string   `json:"job_id"`
+	Description  string   `json:"description"`
+	From         string   `json:"from"`
+	To           string   `json:"to"`
+	Worktree     string   `json:"worktree,omitempty"`
+	FilesTouched []string `json:"files_touched,omitempty"`
+}
+
+// handleWorkerTakeover moves a running job from one worker to an idle one.
+// The first worker is stopped and its handoff summary is given to the
+// second, which carries on in the same job worktree.
+func (s *Server) handleWorkerTakeover(req *protocol.Request) *protocol.Response {
+	var params protocol.WorkerTakeoverParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	from, ok := s.lookupWorker(params.From)
+	if !ok {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrWorkerNotFound, "worker not found: "+params.From, nil)
+		return resp
+	}
+	to, ok := s.lookupWorker(params.To)
+	if !ok {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrWorkerNotFound, "worker not found: "+params.To, nil)
+		return resp
+	}
+
+	j, err := s.checkTakeover(from, to)
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, err.Error(), nil)
+		return resp
+	}
+
+	// Summarize before stopping, while the worker still holds the job
+	summary := s.handoffSummary(s.ctx, from)
+
+	grace := takeoverGrace
+	if params.GraceMS > 0 {
+		grace = time.Duration(params.GraceMS) * time.Millisecond
+	}
+	if err := from.AbortJob(j.ID, "handed over to "+to.Name, grace); err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, err.Error(), nil)
+		return resp
+	}
+	if err := j.Reset(); err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, err.Error(), nil)
+		return resp
+	}
+
+	notes := &worker.HandoffSummary{
+		WorkerName:    summary.WorkerName,
+		Decisions:     summary.Decisions,
+		FilesTouched:  summary.FilesTouched,
+		OpenQuestions: summary.OpenQuestions,
+		Summary:       summary.Summary,
+	}
+	j.SetHandoff(notes.Notes())
+	if j.GetStickyWorker() == from.Name {
+		j.SetStickyWorker(to.Name)
+	}
+	if err := j.Queue(); err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, err.Error(), nil)
+		return resp
+	}
+	s.jobs.Save(j)
+
+	s.ledger.Append(ledger.EventType("worker.takeover"), WorkerTakeoverEventData{
+		JobID:        j.ID,
+		Description:  j.Description,
+		From:         from.Name,
+		To:           to.Name,
+		Worktree:     j.GetWorktree(),
+		FilesTouched: summary.FilesTouched,
+	})
+	s.ledger.Append(ledger.EventJobQueued, ledger.JobEventData{
+		ID:          j.ID,
+		Description: j.Description,
+		Worker:      to.ID,
+		WorkerName:  to.Name,
+	})
+
+	// The job worktree is kept, so the new worker picks up where the old
+	// one stopped
+	go s.executeJobWithWorktree(to, j)
+
+	resp, _ := protocol.NewResponse(req.ID, protocol.WorkerTakeoverResult{
+		JobID:       j.ID,
+		Description: j.Description,
+		From:        from.Name,
+		To:          to.Name,
+		Worktree:    j.GetWorktree(),
+		Handoff:     summary,
+	})
+	return resp
+}
+
+// checkTakeover returns the job from is running if to can take it over.
+func (s *Server) checkTakeover(from, to *worker.Worker) (*job.Job, error) {
+	if from == to {
+		return nil, fmt.Errorf("a worker cannot take over from itself")
+	}
+	j := from.GetCurrentJob()
+	if j == nil {
+		return nil, fmt.Errorf("%s has no job to hand over", from.Name)
+	}
+	if to.Role != worker.RoleSoldato && to.Role != worker.RoleCapo {
+		return nil, fmt.Errorf("%s cannot take over a job: a %s does not run jobs", to.Name, to.Role)
+	}
+	if to.FreeSlots() == 0 {
+		return nil, fmt.Errorf("%s is not idle", to.Name)
+	}
+	if s.isDraining() {
+		return nil, fmt.Errorf("daemon is draining for shutdown")
+	}
+	if err := s.checkBudget(j, to); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// lookupWorker finds a worker by name or ID.
+func (s *Server) lookupWorker(nameOrID string) (*worker.Worker, bool) {
+	if w, ok := s.pool.Get(nameOrID); ok {
+		return w, true
+	}
+	return s.pool.GetByID(nameOrID)
+}
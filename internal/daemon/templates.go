@@ -0,0 +1,239 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+)
+
+// templateFromSpec converts a template spec to a template.
+func templateFromSpec(spec protocol.TemplateSpec) *job.Template {
+	vars := make([]job.TemplateVar, len(spec.Variables))
+	for i, v := range spec.Variables {
+		vars[i] = job.TemplateVar{
+			Name:        v.Name,
+			Description: v.Description,
+			Required:    v.Required,
+			Default:     v.Default,
+		}
+	}
+
+	return &job.Template{
+		ID:          spec.ID,
+		Name:        spec.Name,
+		Description: spec.Description,
+		Type:        job.TemplateType(spec.Type),
+		Prompt:      spec.Prompt,
+		Priority:    spec.Priority,
+		Variables:   vars,
+		Tags:        spec.Tags,
+		Checklist:   spec.Checklist,
+	}
+}
+
+// templateToSpec converts a template to a template spec.
+func templateToSpec(t *job.Template) protocol.TemplateSpec {
+	vars := make([]protocol.TemplateVar, len(t.Variables))
+	for i, v := range t.Variables {
+		vars[i] = protocol.TemplateVar{
+			Name:        v.Name,
+			Description: v.Description,
+			Required:    v.Required,
+			Default:     v.Default,
+		}
+	}
+
+	return protocol.TemplateSpec{
+		ID:          t.ID,
+		Name:        t.Name,
+		Description: t.Description,
+		Type:        string(t.Type),
+		Priority:    t.Priority,
+		Prompt:      t.Prompt,
+		Variables:   vars,
+		Tags:        t.Tags,
+		Checklist:   t.Checklist,
+	}
+}
+
+// templateToInfo converts a template to template info.
+func templateToInfo(t *job.Template) protocol.TemplateInfo {
+	spec := templateToSpec(t)
+	return protocol.TemplateInfo{
+		ID:          spec.ID,
+		Name:        spec.Name,
+		Description: spec.Description,
+		Type:        spec.Type,
+		Priority:    spec.Priority,
+		Variables:   spec.Variables,
+		Tags:        spec.Tags,
+		Checklist:   spec.Checklist,
+		BuiltIn:     t.BuiltIn,
+	}
+}
+
+// checkTemplateWrite validates a template that is about to be stored and
+// reports whether it replaces an existing custom template.
+func (s *Server) checkTemplateWrite(t *job.Template, replace bool) (bool, error) {
+	if err := t.Validate(); err != nil {
+		return false, err
+	}
+
+	existing, exists := s.templates.Get(t.ID)
+	switch {
+	case !exists:
+		return false, nil
+	case existing.BuiltIn:
+		return false, fmt.Errorf("cannot override built-in template %q", t.ID)
+	case !replace:
+		return false, fmt.Errorf("template %q already exists (use replace to overwrite it)", t.ID)
+	}
+	return true, nil
+}
+
+func (s *Server) handleTemplateCreate(req *protocol.Request) *protocol.Response {
+	var params protocol.TemplateCreateParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	t := templateFromSpec(params.Template)
+	replaced, err := s.checkTemplateWrite(t, params.Replace)
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, err.Error(), nil)
+		return resp
+	}
+
+	if err := s.templates.Add(t); err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, err.Error(), nil)
+		return resp
+	}
+
+	s.ledger.Append(ledger.EventType("template.created"), map[string]interface{}{
+		"id":       t.ID,
+		"replaced": replaced,
+	})
+
+	resp, _ := protocol.NewResponse(req.ID, protocol.TemplateGetResult{
+		Template: templateToInfo(t),
+		Prompt:   t.Prompt,
+	})
+	return resp
+}
+
+func (s *Server) handleTemplateRemove(req *protocol.Request) *protocol.Response {
+	var params protocol.TemplateRemoveParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	if _, exists := s.templates.Get(params.ID); !exists {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrTemplateNotFound, "template not found", nil)
+		return resp
+	}
+
+	if err := s.templates.Remove(params.ID); err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, err.Error(), nil)
+		return resp
+	}
+
+	s.ledger.Append(ledger.EventType("template.removed"), map[string]interface{}{
+		"id": params.ID,
+	})
+
+	resp, _ := protocol.NewResponse(req.ID, map[string]string{"status": "removed"})
+	return resp
+}
+
+func (s *Server) handleTemplateExport(req *protocol.Request) *protocol.Response {
+	var params protocol.TemplateExportParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	var templates []*job.Template
+	if len(params.IDs) > 0 {
+		for _, id := range params.IDs {
+			t, exists := s.templates.Get(id)
+			if !exists {
+				resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrTemplateNotFound,
+					fmt.Sprintf("template not found: %s", id), nil)
+				return resp
+			}
+			templates = append(templates, t)
+		}
+	} else {
+		for _, t := range s.templates.List() {
+			if !t.BuiltIn || params.BuiltIn {
+				templates = append(templates, t)
+			}
+		}
+		sort.Slice(templates, func(i, j int) bool {
+			return templates[i].ID < templates[j].ID
+		})
+	}
+
+	specs := make([]protocol.TemplateSpec, len(templates))
+	for i, t := range templates {
+		specs[i] = templateToSpec(t)
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, protocol.TemplateExportResult{Templates: specs})
+	return resp
+}
+
+// handleTemplateImport adds a batch of templates. Every template is checked
+// first, so either all of them are imported or none.
+func (s *Server) handleTemplateImport(req *protocol.Request) *protocol.Response {
+	var params protocol.TemplateImportParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	if len(params.Templates) == 0 {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams, "no templates to import", nil)
+		return resp
+	}
+
+	result := protocol.TemplateImportResult{Imported: []string{}}
+	templates := make([]*job.Template, len(params.Templates))
+	seen := make(map[string]bool, len(params.Templates))
+	for i, spec := range params.Templates {
+		t := templateFromSpec(spec)
+		replaced, err := s.checkTemplateWrite(t, params.Replace)
+		if err == nil && seen[t.ID] {
+			err = fmt.Errorf("template %q appears twice", t.ID)
+		}
+		if err != nil {
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.InvalidParams,
+				fmt.Sprintf("template %d: %v", i+1, err), nil)
+			return resp
+		}
+		seen[t.ID] = true
+		templates[i] = t
+		if replaced {
+			result.Replaced = append(result.Replaced, t.ID)
+		}
+	}
+
+	for _, t := range templates {
+		if err := s.templates.Add(t); err != nil {
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError,
+				fmt.Sprintf("failed to save %s: %v", t.ID, err), nil)
+			return resp
+		}
+		result.Imported = append(result.Imported, t.ID)
+	}
+
+	s.ledger.Append(ledger.EventType("template.imported"), map[string]interface{}{
+		"ids":      result.Imported,
+		"replaced": result.Replaced,
+	})
+
+	resp, _ := protocol.NewResponse(req.ID, result)
+	return resp
+}
@@ -0,0 +1,136 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"cosa/internal/claude"
+	"cosa/internal/git"
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+)
+
+// handleTerritoryRemove detaches the active territory from the daemon. Its
+// workers, whose worktrees are in it, go with it; queued jobs wait for the
+// next territory. With Purge its .cosa directory, worktrees and branches
+// are deleted too. It is refused while jobs run, are reviewed or merge.
+func (s *Server) handleTerritoryRemove(req *protocol.Request) *protocol.Response {
+	var params protocol.TerritoryRemoveParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	s.mu.RLock()
+	t := s.territory
+	s.mu.RUnlock()
+
+	if t == nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, "territory not initialized", nil)
+		return resp
+	}
+	if params.Path != "" {
+		root, err := git.FindMainRepoRoot(params.Path)
+		if err != nil || filepath.Clean(root) != filepath.Clean(t.RepoRoot) {
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState,
+				fmt.Sprintf("%s is not the active territory (%s)", params.Path, t.RepoRoot), nil)
+			return resp
+		}
+	}
+
+	var reason string
+	switch running, reviewing := s.jobs.CountByStatus(job.StatusRunning), s.jobs.CountByStatus(job.StatusReview); {
+	case running > 0:
+		reason = fmt.Sprintf("%d jobs are running; cancel them or wait for them to finish", running)
+	case reviewing > 0:
+		reason = fmt.Sprintf("%d jobs are under review", reviewing)
+	case s.merges.depth() > 0:
+		reason = "merges are in progress"
+	}
+	if reason != "" {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, "cannot remove the territory: "+reason, nil)
+		return resp
+	}
+
+	result := protocol.TerritoryRemoveResult{
+		Path:   t.RepoRoot,
+		Queued: s.jobs.CountByStatus(job.StatusPending) + s.jobs.CountByStatus(job.StatusQueued),
+		Purged: params.Purge,
+		DryRun: params.DryRun,
+	}
+	workers := s.pool.List()
+	for _, w := range workers {
+		result.Workers = append(result.Workers, w.Name)
+	}
+
+	if params.DryRun {
+		if params.Purge {
+			artifacts, err := t.Artifacts()
+			if err != nil {
+				resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, err.Error(), nil)
+				return resp
+			}
+			result.Worktrees, result.Branches = artifacts.Worktrees, artifacts.Branches
+		}
+		resp, _ := protocol.NewResponse(req.ID, result)
+		return resp
+	}
+
+	s.mu.Lock()
+	s.territory = nil
+	s.reviewCoordinator = nil
+	s.mu.Unlock()
+	s.repoIndex.Store(nil)
+
+	// The workers' sessions are kept, as when they are removed one by one,
+	// in case the territory is added back
+	for _, w := range workers {
+		if _, err := s.pool.Remove(w.Name); err != nil {
+			continue
+		}
+		if w.SessionID != "" {
+			s.sessions.Save(&claude.SessionInfo{
+				SessionID:         w.SessionID,
+				WorkerID:          w.ID,
+				WorkerName:        w.Name,
+				CreatedAt:         w.CreatedAt,
+				LastUsed:          time.Now(),
+				Commit:            w.SessionCommit,
+				Tokens:            w.SessionTokens,
+				PreviousSessionID: w.PreviousSessionID,
+			})
+		}
+		w.Stop()
+		s.capacity.RemoveWorker(w.Name)
+		s.ledger.Append(ledger.EventWorkerRemoved, ledger.WorkerEventData{
+			ID:   w.ID,
+			Name: w.Name,
+			Role: string(w.Role),
+		})
+	}
+
+	if params.Purge {
+		removed, err := t.Purge()
+		if removed != nil {
+			result.Worktrees, result.Branches = removed.Worktrees, removed.Branches
+		}
+		if err != nil {
+			result.Error = err.Error()
+			s.log.Warn("failed to purge territory", "path", t.RepoRoot, "error", err)
+		}
+	}
+
+	s.ledger.Append(ledger.EventType("territory.removed"), map[string]any{
+		"path":      t.RepoRoot,
+		"purged":    params.Purge,
+		"workers":   result.Workers,
+		"worktrees": len(result.Worktrees),
+		"branches":  len(result.Branches),
+	})
+	s.log.Info("territory removed", "path", t.RepoRoot, "purged", params.Purge, "workers", len(result.Workers))
+
+	resp, _ := protocol.NewResponse(req.ID, result)
+	return resp
+}
@@ -0,0 +1,64 @@
+package daemon
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"cosa/internal/config"
+	"cosa/internal/notify"
+	"cosa/internal/territory"
+)
+
+// applyTerritoryConfig loads t's .cosa/config.yaml and makes the config it
+// overrides the one jobs run with. Must be called with mu held, before t
+// becomes the active territory.
+func (s *Server) applyTerritoryConfig(t *territory.Territory) error {
+	path := filepath.Join(t.Path, config.TerritoryConfigFile)
+	tc, err := config.LoadTerritoryConfig(path)
+	if err != nil {
+		return err
+	}
+
+	merged, err := tc.Apply(s.config())
+	if err != nil {
+		return fmt.Errorf("invalid territory config %s: %w", path, err)
+	}
+	if err := notify.ValidateRules(merged.Notifications.Rules); err != nil {
+		return fmt.Errorf("invalid notification rules in %s: %w", path, err)
+	}
+
+	s.territoryCfg = tc
+	s.jobCfg.Store(merged)
+	s.notifier.SetConfig(&merged.Notifications)
+	s.log.Info("territory config applied", "path", path,
+		"model", merged.Claude.Model, "max_turns", merged.Claude.MaxTurns)
+	return nil
+}
+
+// jobConfig returns the config jobs run with: the global config, with the
+// active territory's overrides applied once it has any.
+func (s *Server) jobConfig() *config.Config {
+	if cfg := s.jobCfg.Load(); cfg != nil {
+		return cfg
+	}
+	return s.config()
+}
+
+// territoryGates returns the review gate commands: those in territory.json,
+// each replaced by the territory config's if it sets one.
+func (s *Server) territoryGates() config.GateConfig {
+	gates := config.GateConfig{
+		TestCommand:  s.territory.Config.TestCommand,
+		BuildCommand: s.territory.Config.BuildCommand,
+	}
+	if s.territoryCfg == nil {
+		return gates
+	}
+	if s.territoryCfg.Gates.TestCommand != "" {
+		gates.TestCommand = s.territoryCfg.Gates.TestCommand
+	}
+	if s.territoryCfg.Gates.BuildCommand != "" {
+		gates.BuildCommand = s.territoryCfg.Gates.BuildCommand
+	}
+	return gates
+}
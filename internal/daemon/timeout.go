@@ -0,0 +1,124 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+)
+
+const (
+	// timeoutCheckInterval is how often running jobs are checked against
+	// their deadlines.
+	timeoutCheckInterval = 10 * time.Second
+
+	// timeoutStopGrace is how long a timed out Claude process gets to exit
+	// after SIGINT before it is killed.
+	timeoutStopGrace = 10 * time.Second
+)
+
+// startTimeoutMonitor fails running jobs that pass their deadline.
+func (s *Server) startTimeoutMonitor() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(timeoutCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case now := <-ticker.C:
+				for _, j := range s.jobs.ListByStatus(job.StatusRunning) {
+					if deadline := j.Deadline(s.config().Workers.JobTimeout); !deadline.IsZero() && now.After(deadline) {
+						s.timeoutJob(j)
+					}
+				}
+			}
+		}
+	}()
+}
+
+// timeoutJob stops the Claude process running j and fails the job. The job
+// is queued again while it has timed out no more than workers.timeout_retries
+// times.
+func (s *Server) timeoutJob(j *job.Job) {
+	timeout := j.GetTimeout()
+	if timeout <= 0 {
+		timeout = s.config().Workers.JobTimeout
+	}
+	reason := fmt.Sprintf("timed out after %s", timeout)
+
+	w := s.findWorkerForJob(j.ID)
+	var err error
+	if w != nil {
+		err = w.AbortJob(j.ID, reason, timeoutStopGrace)
+	} else {
+		err = j.Fail(reason)
+	}
+	// The run may have finished on its own in the meantime
+	var transitionErr *job.TransitionError
+	if errors.As(err, &transitionErr) {
+		return
+	}
+	if err != nil {
+		s.log.Error("failed to time out job", "job", j.ID, "error", err)
+		s.ledger.Append(ledger.EventType("job.timeout_error"), ledger.JobEventData{
+			ID:    j.ID,
+			Error: err.Error(),
+		})
+		if j.GetStatus() != job.StatusFailed {
+			return
+		}
+	}
+
+	count := j.RecordTimeout()
+	s.jobs.Save(j)
+
+	data := ledger.JobEventData{
+		ID:          j.ID,
+		Description: j.Description,
+		Error:       reason,
+	}
+	if w != nil {
+		data.Worker = w.ID
+		data.WorkerName = w.Name
+	}
+	s.ledger.Append(ledger.EventType("job.timed_out"), data)
+
+	// An ephemeral worker's job is not handed to anyone else
+	if count <= s.config().Workers.TimeoutRetries && (w == nil || !w.Ephemeral) && j.Reset() == nil {
+		s.queue.Enqueue(j)
+		s.jobs.Save(j)
+		s.ledger.Append(ledger.EventJobQueued, ledger.JobEventData{
+			ID:          j.ID,
+			Description: j.Description,
+		})
+		return
+	}
+
+	s.onJobFail(j, fmt.Errorf("%s", reason))
+	if w != nil && w.Ephemeral {
+		s.keepJobBranch(j)
+		s.retireWhenIdle(w)
+	}
+}
+
+// setTimeoutInfo fills in the job's timeout, and its deadline if it is
+// running.
+func (s *Server) setTimeoutInfo(info *protocol.JobInfo, j *job.Job) {
+	timeout := j.GetTimeout()
+	if timeout <= 0 {
+		timeout = s.config().Workers.JobTimeout
+	}
+	info.Timeout = int64(timeout.Seconds())
+	if deadline := j.Deadline(s.config().Workers.JobTimeout); !deadline.IsZero() {
+		info.Deadline = deadline.Unix()
+	}
+	info.Timeouts = j.GetTimeouts()
+}
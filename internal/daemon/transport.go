@@ -0,0 +1,17 @@
+package daemon
+
+import "net"
+
+// The daemon and its clients talk over a local transport: a Unix socket at
+// the configured socket path, or on Windows a named pipe derived from it.
+// Each platform provides listen and dial.
+
+// Listen opens the daemon's transport at address.
+func Listen(address string) (net.Listener, error) {
+	return listen(address)
+}
+
+// Dial connects to the daemon's transport at address.
+func Dial(address string) (net.Conn, error) {
+	return dial(address)
+}
@@ -0,0 +1,24 @@
+//go:build !windows
+
+package daemon
+
+import (
+	"net"
+	"os"
+)
+
+// TransportAddress returns the address the daemon listens on for a socket
+// path, for display.
+func TransportAddress(socketPath string) string {
+	return socketPath
+}
+
+func listen(address string) (net.Listener, error) {
+	// Remove a stale socket left by a daemon that did not shut down cleanly
+	os.Remove(address)
+	return net.Listen("unix", address)
+}
+
+func dial(address string) (net.Conn, error) {
+	return net.Dial("unix", address)
+}
@@ -0,0 +1,348 @@
+//go:build windows
+
+package daemon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	pipePrefix     = `\\.\pipe\`
+	pipeBufferSize = 64 * 1024
+
+	// pipeBusyTimeout bounds how long dial waits for a free pipe instance
+	pipeBusyTimeout = 5 * time.Second
+)
+
+// TransportAddress returns the named pipe the daemon listens on for a
+// socket path. A socket path that already names a pipe is used as is;
+// otherwise the pipe name is derived from the path so daemons with
+// different data directories do not collide.
+func TransportAddress(socketPath string) string {
+	if strings.HasPrefix(socketPath, pipePrefix) {
+		return socketPath
+	}
+	abs, err := filepath.Abs(socketPath)
+	if err != nil {
+		abs = socketPath
+	}
+	sum := sha256.Sum256([]byte(strings.ToLower(abs)))
+	return pipePrefix + "cosa-" + hex.EncodeToString(sum[:6])
+}
+
+type pipeAddr string
+
+func (a pipeAddr) Network() string { return "pipe" }
+func (a pipeAddr) String() string  { return string(a) }
+
+// pipeListener accepts connections on a named pipe. One instance of the
+// pipe is always waiting so clients never find it missing between accepts.
+type pipeListener struct {
+	name string
+	sa   *windows.SecurityAttributes
+
+	mu     sync.Mutex
+	next   windows.Handle // Instance waiting for the next client
+	closed bool
+}
+
+func listen(address string) (net.Listener, error) {
+	name := TransportAddress(address)
+
+	sa, err := pipeSecurity()
+	if err != nil {
+		return nil, err
+	}
+
+	l := &pipeListener{name: name, sa: sa}
+
+	// Claiming the first instance fails if another daemon owns the pipe
+	h, err := l.createInstance(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pipe %s: %w", name, err)
+	}
+	l.next = h
+	return l, nil
+}
+
+// pipeSecurity limits the pipe to the current user and SYSTEM.
+func pipeSecurity() (*windows.SecurityAttributes, error) {
+	user, err := windows.GetCurrentProcessToken().GetTokenUser()
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up current user: %w", err)
+	}
+	sd, err := windows.SecurityDescriptorFromString(
+		"D:P(A;;GA;;;SY)(A;;GA;;;" + user.User.Sid.String() + ")")
+	if err != nil {
+		return nil, fmt.Errorf("failed to build pipe security: %w", err)
+	}
+	sa := &windows.SecurityAttributes{SecurityDescriptor: sd}
+	sa.Length = uint32(unsafe.Sizeof(*sa))
+	return sa, nil
+}
+
+func (l *pipeListener) createInstance(first bool) (windows.Handle, error) {
+	name, err := windows.UTF16PtrFromString(l.name)
+	if err != nil {
+		return 0, err
+	}
+	flags := uint32(windows.PIPE_ACCESS_DUPLEX | windows.FILE_FLAG_OVERLAPPED)
+	if first {
+		flags |= windows.FILE_FLAG_FIRST_PIPE_INSTANCE
+	}
+	return windows.CreateNamedPipe(name, flags,
+		windows.PIPE_TYPE_BYTE|windows.PIPE_READMODE_BYTE|windows.PIPE_WAIT|windows.PIPE_REJECT_REMOTE_CLIENTS,
+		windows.PIPE_UNLIMITED_INSTANCES, pipeBufferSize, pipeBufferSize, 0, l.sa)
+}
+
+func (l *pipeListener) Accept() (net.Conn, error) {
+	for {
+		l.mu.Lock()
+		if l.closed {
+			l.mu.Unlock()
+			return nil, net.ErrClosed
+		}
+		h := l.next
+		l.mu.Unlock()
+
+		if h == 0 {
+			var err error
+			if h, err = l.createInstance(false); err != nil {
+				return nil, err
+			}
+			l.mu.Lock()
+			l.next = h
+			l.mu.Unlock()
+		}
+
+		_, err := waitIO(h, nil, func(o *windows.Overlapped, _ *uint32) error {
+			return windows.ConnectNamedPipe(h, o)
+		})
+
+		l.mu.Lock()
+		if l.closed {
+			l.mu.Unlock()
+			return nil, net.ErrClosed
+		}
+		l.next = 0
+		l.mu.Unlock()
+
+		switch {
+		case err == nil, errors.Is(err, windows.ERROR_PIPE_CONNECTED):
+			// Have the next instance waiting before handing this one off
+			if next, err := l.createInstance(false); err == nil {
+				l.mu.Lock()
+				if l.closed {
+					windows.CloseHandle(next)
+				} else {
+					l.next = next
+				}
+				l.mu.Unlock()
+			}
+			return newPipeConn(h, l.name), nil
+		case errors.Is(err, windows.ERROR_NO_DATA):
+			// The client gave up before we got to it
+			windows.CloseHandle(h)
+			continue
+		default:
+			windows.CloseHandle(h)
+			return nil, err
+		}
+	}
+}
+
+func (l *pipeListener) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	if l.next != 0 {
+		windows.CancelIoEx(l.next, nil)
+		windows.CloseHandle(l.next)
+		l.next = 0
+	}
+	return nil
+}
+
+func (l *pipeListener) Addr() net.Addr { return pipeAddr(l.name) }
+
+func dial(address string) (net.Conn, error) {
+	name := TransportAddress(address)
+	path, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(pipeBusyTimeout)
+	for {
+		h, err := windows.CreateFile(path, windows.GENERIC_READ|windows.GENERIC_WRITE, 0, nil,
+			windows.OPEN_EXISTING, windows.FILE_FLAG_OVERLAPPED, 0)
+		if err == nil {
+			return newPipeConn(h, name), nil
+		}
+		// Every instance is taken until the daemon creates the next one
+		if errors.Is(err, windows.ERROR_PIPE_BUSY) && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+			continue
+		}
+		return nil, &net.OpError{Op: "dial", Net: "pipe", Addr: pipeAddr(name), Err: err}
+	}
+}
+
+// pipeConn is one end of a named pipe connection. Reads and writes use
+// overlapped I/O so they can run at the same time. Deadlines are checked
+// when an operation starts.
+type pipeConn struct {
+	h    windows.Handle
+	name string
+
+	mu            sync.Mutex
+	closed        bool
+	readDeadline  time.Time
+	writeDeadline time.Time
+}
+
+func newPipeConn(h windows.Handle, name string) *pipeConn {
+	return &pipeConn{h: h, name: name}
+}
+
+func (c *pipeConn) Read(b []byte) (int, error) {
+	if len(b) == 0 {
+		return 0, nil
+	}
+	c.mu.Lock()
+	deadline := c.readDeadline
+	c.mu.Unlock()
+
+	n, err := waitIO(c.h, &deadline, func(o *windows.Overlapped, done *uint32) error {
+		return windows.ReadFile(c.h, b, done, o)
+	})
+	if err != nil {
+		return int(n), c.mapError(err, io.EOF)
+	}
+	return int(n), nil
+}
+
+func (c *pipeConn) Write(b []byte) (int, error) {
+	c.mu.Lock()
+	deadline := c.writeDeadline
+	c.mu.Unlock()
+
+	written := 0
+	for written < len(b) {
+		n, err := waitIO(c.h, &deadline, func(o *windows.Overlapped, done *uint32) error {
+			return windows.WriteFile(c.h, b[written:], done, o)
+		})
+		written += int(n)
+		if err != nil {
+			return written, c.mapError(err, io.ErrClosedPipe)
+		}
+	}
+	return written, nil
+}
+
+// mapError turns pipe errors into the ones net.Conn users expect, with
+// disconnected the error for the other end having gone away.
+func (c *pipeConn) mapError(err, disconnected error) error {
+	c.mu.Lock()
+	closed := c.closed
+	c.mu.Unlock()
+
+	switch {
+	case closed:
+		return net.ErrClosed
+	case errors.Is(err, os.ErrDeadlineExceeded):
+		return err
+	case errors.Is(err, windows.ERROR_BROKEN_PIPE), errors.Is(err, windows.ERROR_PIPE_NOT_CONNECTED),
+		errors.Is(err, windows.ERROR_NO_DATA):
+		return disconnected
+	default:
+		return &net.OpError{Op: "io", Net: "pipe", Addr: pipeAddr(c.name), Err: err}
+	}
+}
+
+func (c *pipeConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	windows.CancelIoEx(c.h, nil)
+	return windows.CloseHandle(c.h)
+}
+
+func (c *pipeConn) LocalAddr() net.Addr  { return pipeAddr(c.name) }
+func (c *pipeConn) RemoteAddr() net.Addr { return pipeAddr(c.name) }
+
+func (c *pipeConn) SetDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readDeadline = t
+	c.writeDeadline = t
+	return nil
+}
+
+func (c *pipeConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readDeadline = t
+	return nil
+}
+
+func (c *pipeConn) SetWriteDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.writeDeadline = t
+	return nil
+}
+
+// waitIO starts an overlapped operation on h and waits for it to finish,
+// cancelling it if deadline passes first. It returns the number of bytes
+// transferred.
+func waitIO(h windows.Handle, deadline *time.Time, op func(*windows.Overlapped, *uint32) error) (uint32, error) {
+	event, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer windows.CloseHandle(event)
+
+	o := &windows.Overlapped{HEvent: event}
+	var n uint32
+	err = op(o, &n)
+	if errors.Is(err, windows.ERROR_IO_PENDING) {
+		timeout := uint32(windows.INFINITE)
+		if deadline != nil && !deadline.IsZero() {
+			remaining := time.Until(*deadline)
+			if remaining < 0 {
+				remaining = 0
+			}
+			timeout = uint32(remaining.Milliseconds())
+		}
+
+		if r, _ := windows.WaitForSingleObject(event, timeout); r == uint32(windows.WAIT_TIMEOUT) {
+			windows.CancelIoEx(h, o)
+			windows.GetOverlappedResult(h, o, &n, true)
+			err = os.ErrDeadlineExceeded
+		} else {
+			err = windows.GetOverlappedResult(h, o, &n, true)
+		}
+	}
+
+	return n, err
+}
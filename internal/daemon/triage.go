@@ -0,0 +1,275 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"cosa/internal/display"
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+	"cosa/internal/review"
+)
+
+// flagForAttention moves j to needs_attention so it shows up in triage, and
+// tells the boss about it.
+func (s *Server) flagForAttention(j *job.Job, kind, reason string) {
+	if err := j.MarkNeedsAttention(kind, reason); err != nil {
+		s.ledger.Append(ledger.EventType("job.transition_rejected"), ledger.JobEventData{
+			ID:    j.ID,
+			Error: err.Error(),
+		})
+		return
+	}
+	s.jobs.Save(j)
+
+	s.ledger.Append(ledger.EventType("job.needs_attention"), ledger.JobEventData{
+		ID:          j.ID,
+		Description: fmt.Sprintf("%s: %s", kind, reason),
+		Worker:      j.Worker,
+	})
+
+	s.notifier.NotifyNeedsAttention(j.ID, j.Description, kind, reason, j.Priority)
+}
+
+// clearMergeAttention completes a job that was waiting on a human because
+// of a merge conflict once its branch has been merged after all.
+func (s *Server) clearMergeAttention(j *job.Job) {
+	if j.GetStatus() != job.StatusNeedsAttention || j.GetAttentionKind() != job.AttentionMergeConflict {
+		return
+	}
+	if err := j.Resolve(job.StatusCompleted, ""); err != nil {
+		return
+	}
+	s.jobs.Save(j)
+	s.queue.NotifyCompletion(j.ID)
+}
+
+// pendingQuestion returns the question a worker ended a job with when it
+// stopped to ask instead of making any changes.
+func (s *Server) pendingQuestion(j *job.Job) string {
+	output := strings.TrimSpace(j.Output)
+	if i := strings.LastIndex(output, "\n\n"); i >= 0 {
+		output = strings.TrimSpace(output[i:])
+	}
+	if !strings.HasSuffix(output, "?") {
+		return ""
+	}
+
+	diff, err := s.jobWorktreeDiff(j)
+	if err != nil || len(diff.FilesChanged) > 0 {
+		return ""
+	}
+	return output
+}
+
+func (s *Server) handleTriageList(req *protocol.Request) *protocol.Response {
+	// Drafts wait on a person too
+	jobs := append(s.jobs.ListByStatus(job.StatusNeedsAttention), s.jobs.ListByStatus(job.StatusDraft)...)
+
+	// Oldest first, so nothing waits forever behind newer items
+	sort.Slice(jobs, func(a, b int) bool {
+		return attentionSince(jobs[a]).Before(attentionSince(jobs[b]))
+	})
+
+	result := protocol.TriageListResult{Items: []protocol.TriageItem{}}
+	for _, j := range jobs {
+		item := protocol.TriageItem{
+			ID:          j.ID,
+			Description: j.Description,
+			Kind:        j.GetAttentionKind(),
+			Reason:      j.GetAttentionReason(),
+			Since:       attentionSince(j).Unix(),
+			Priority:    j.Priority,
+		}
+		if j.GetStatus() == job.StatusDraft {
+			item.Kind = job.AttentionDraft
+			item.Reason = "draft waiting for approval"
+		}
+		if w, exists := s.pool.GetByID(j.Worker); exists {
+			item.Worker = w.Name
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, result)
+	return resp
+}
+
+// attentionSince returns when j started needing attention. Jobs flagged
+// before the time was recorded fall back to their completion or creation.
+func attentionSince(j *job.Job) time.Time {
+	if since := j.GetAttentionSince(); !since.IsZero() {
+		return since
+	}
+	if j.CompletedAt != nil {
+		return *j.CompletedAt
+	}
+	return j.CreatedAt
+}
+
+func (s *Server) handleTriageResolve(req *protocol.Request) *protocol.Response {
+	var params protocol.TriageResolveParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	j, exists := s.jobs.Get(params.ID)
+	if !exists {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrJobNotFound, "job not found", nil)
+		return resp
+	}
+	status := j.GetStatus()
+	if status != job.StatusNeedsAttention && status != job.StatusDraft {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState,
+			fmt.Sprintf("job does not need attention (status: %s)", status), nil)
+		return resp
+	}
+
+	kind := j.GetAttentionKind()
+	var result string
+	var err error
+	if j.IsDraft() {
+		kind = job.AttentionDraft
+		result, err = s.triageDraft(j, params.Action)
+	} else {
+		result, err = s.triageJob(j, kind, params.Action, params.Note)
+	}
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrInvalidState, err.Error(), nil)
+		return resp
+	}
+
+	s.ledger.Append(ledger.EventType("job.triaged"), ledger.JobEventData{
+		ID:          j.ID,
+		Description: fmt.Sprintf("%s %s: %s", params.Action, kind, result),
+	})
+
+	resp, _ := protocol.NewResponse(req.ID, map[string]string{"status": result})
+	return resp
+}
+
+// triageDraft approves a draft on accept and cancels it on dismiss. A draft
+// has not run, so there is nothing to retry.
+func (s *Server) triageDraft(j *job.Job, action string) (string, error) {
+	switch action {
+	case protocol.TriageAccept:
+		return s.approveJob(j)
+	case protocol.TriageDismiss:
+		if err := j.Cancel(); err != nil {
+			return "", err
+		}
+		s.jobs.Save(j)
+		s.queue.NotifyFailure(j.ID)
+		s.ledger.Append(ledger.EventJobCancelled, ledger.JobEventData{
+			ID:          j.ID,
+			Description: j.Description,
+		})
+		return string(job.StatusCancelled), nil
+	}
+	return "", fmt.Errorf("a draft is accepted or dismissed, not %s", action)
+}
+
+// triageJob resolves a job in needs_attention with action.
+func (s *Server) triageJob(j *job.Job, kind, action, note string) (string, error) {
+	switch action {
+	case protocol.TriageRetry:
+		return s.triageRetry(j, kind, note)
+	case protocol.TriageAccept:
+		if kind == job.AttentionPlanApproval {
+			return s.approveJob(j)
+		}
+		return s.triageAccept(j)
+	case protocol.TriageDismiss:
+		return s.triageDismiss(j, note)
+	}
+	return "", fmt.Errorf("unknown action %q (expected %s, %s or %s)", action,
+		protocol.TriageRetry, protocol.TriageAccept, protocol.TriageDismiss)
+}
+
+// triageRetry runs a job again. A conflicting branch that is still kept
+// gets a new resolver job; anything else is rerun with the note appended to
+// its body.
+func (s *Server) triageRetry(j *job.Job, kind, note string) (string, error) {
+	if kind == job.AttentionMergeConflict && j.IsUnmerged() {
+		s.mu.RLock()
+		t := s.territory
+		s.mu.RUnlock()
+		if t == nil {
+			return "", fmt.Errorf("territory not initialized")
+		}
+		if r := s.activeResolver(j.ID); r != nil {
+			return "", fmt.Errorf("conflict is being resolved by job %s", display.ShortID(r.ID))
+		}
+
+		if err := j.Resolve(job.StatusCompleted, ""); err != nil {
+			return "", err
+		}
+		s.jobs.Save(j)
+		r := s.createResolverJob(j, j.GetBranch(), t.MergeTargetBranch(s.config().Git.DefaultMergeBranch), nil)
+		return "resolving in job " + display.ShortID(r.ID), nil
+	}
+
+	if note != "" {
+		switch kind {
+		case job.AttentionQuestion:
+			note = fmt.Sprintf("Answer to your question %q:\n%s", j.GetAttentionReason(), note)
+		case job.AttentionPlanApproval:
+			note = fmt.Sprintf("Feedback on your plan:\n%s\n\nYour previous plan was:\n%s", note, j.GetPlan())
+		default:
+			note = "Note from the boss:\n" + note
+		}
+	}
+	if err := j.Reopen(note); err != nil {
+		return "", err
+	}
+	s.queue.Enqueue(j)
+	s.jobs.Save(j)
+	return "requeued", nil
+}
+
+// triageAccept overrides whatever held the job up and marks it completed.
+func (s *Server) triageAccept(j *job.Job) (string, error) {
+	if j.IsUnmerged() {
+		return "", fmt.Errorf("branch %s is still unmerged; merge it with 'cosa job merge --retry' or abandon it", j.GetBranch())
+	}
+	if err := j.Resolve(job.StatusCompleted, ""); err != nil {
+		return "", err
+	}
+	s.jobs.Save(j)
+	s.queue.NotifyCompletion(j.ID)
+	review.SettleRevised(s.jobs, j)
+
+	// A job that stopped to ask a question still has its worktree
+	if j.GetWorktree() != "" {
+		go func() {
+			if err := s.mergeAndCleanupJobWorktree(j); err != nil {
+				s.ledger.Append(ledger.EventType("job.post_complete_error"), ledger.JobEventData{
+					ID:    j.ID,
+					Error: fmt.Sprintf("post-completion merge failed: %v", err),
+				})
+			}
+		}()
+	}
+	return string(job.StatusCompleted), nil
+}
+
+// triageDismiss gives up on the job and fails it, along with its dependents.
+func (s *Server) triageDismiss(j *job.Job, note string) (string, error) {
+	if note == "" {
+		note = "dismissed in triage: " + j.GetAttentionReason()
+	}
+	if err := j.Resolve(job.StatusFailed, note); err != nil {
+		return "", err
+	}
+	if !j.IsUnmerged() {
+		s.cleanupCancelledJobWorktree(j)
+	}
+	s.jobs.Save(j)
+	s.queue.NotifyFailure(j.ID)
+	review.SettleRevised(s.jobs, j)
+	return string(job.StatusFailed), nil
+}
@@ -0,0 +1,71 @@
+package daemon
+
+import (
+	"encoding/json"
+
+	"cosa/internal/job"
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+)
+
+// onJobTransition records every job status change in the ledger and
+// notifies the identities watching the job.
+func (s *Server) onJobTransition(t job.Transition) {
+	s.ledger.Append(ledger.EventJobTransition, t)
+
+	watchers := s.watches.Watchers(t.JobID)
+	if len(watchers) == 0 {
+		return
+	}
+
+	var description string
+	if j, ok := s.jobs.Get(t.JobID); ok {
+		description = j.Description
+	}
+	s.notifier.NotifyWatchedJob(t.JobID, description, string(t.From), string(t.To), t.Reason, watchers)
+}
+
+// handleJobWatch adds a job to, or with watch false removes it from, an
+// identity's watch list.
+func (s *Server) handleJobWatch(req *protocol.Request, watch bool) *protocol.Response {
+	var params protocol.JobWatchParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	var changed bool
+	var err error
+	if watch {
+		if _, exists := s.jobs.Get(params.ID); !exists {
+			resp, _ := protocol.NewErrorResponse(req.ID, protocol.ErrJobNotFound, "job not found", nil)
+			return resp
+		}
+		changed, err = s.watches.Watch(params.Identity, params.ID)
+	} else {
+		// Unwatching works for jobs that are gone too
+		changed, err = s.watches.Unwatch(params.Identity, params.ID)
+	}
+	if err != nil {
+		resp, _ := protocol.NewErrorResponse(req.ID, protocol.InternalError, err.Error(), nil)
+		return resp
+	}
+
+	resp, _ := protocol.NewResponse(req.ID, protocol.JobWatchResult{
+		ID:       params.ID,
+		Watching: watch,
+		Changed:  changed,
+	})
+	return resp
+}
+
+// watchedBy returns the set of job IDs identity watches.
+func (s *Server) watchedBy(identity string) map[string]bool {
+	if identity == "" {
+		return nil
+	}
+	watched := make(map[string]bool)
+	for _, id := range s.watches.Watched(identity) {
+		watched[id] = true
+	}
+	return watched
+}
@@ -0,0 +1,70 @@
+// Package display measures, truncates and pads text by terminal cell width.
+// Wide characters and emoji take two cells and are never cut in half, and
+// ANSI styling is left intact.
+package display
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Ellipsis marks text shortened by Truncate.
+const Ellipsis = ".."
+
+// Width returns the number of terminal cells s takes up.
+func Width(s string) int {
+	return ansi.StringWidth(s)
+}
+
+// Truncate shortens s to at most width cells, ending it with Ellipsis if
+// anything was cut.
+func Truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if Width(s) <= width {
+		return s
+	}
+	if width <= len(Ellipsis) {
+		return ansi.Truncate(s, width, "")
+	}
+	return ansi.Truncate(s, width, Ellipsis)
+}
+
+// Cut shortens s to at most width cells without marking the cut, for
+// abbreviations such as role names.
+func Cut(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	return ansi.Truncate(s, width, "")
+}
+
+// ShortID returns the first eight characters of an ID, or all of a shorter
+// one.
+func ShortID(id string) string {
+	return Cut(id, 8)
+}
+
+// PadRight pads s with spaces to width cells. Wider text is returned as is.
+func PadRight(s string, width int) string {
+	if gap := width - Width(s); gap > 0 {
+		return s + strings.Repeat(" ", gap)
+	}
+	return s
+}
+
+// PadLeft pads s on the left with spaces to width cells. Wider text is
+// returned as is.
+func PadLeft(s string, width int) string {
+	if gap := width - Width(s); gap > 0 {
+		return strings.Repeat(" ", gap) + s
+	}
+	return s
+}
+
+// Fit truncates or pads s to exactly width cells, for fixed-width columns.
+func Fit(s string, width int) string {
+	return PadRight(Truncate(s, width), width)
+}
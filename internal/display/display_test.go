@@ -0,0 +1,116 @@
+package display
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+	"unicode/utf8"
+)
+
+// text is a random string mixing ASCII, accented and combining characters,
+// CJK, emoji and emoji sequences, which byte slicing tends to break.
+type text string
+
+var textPieces = []string{
+	"a", "Z", "7", " ", "-", "é", "ñ", "é", "ß", "Ж", "λ",
+	"日", "本", "語", "한", "ｱ", "😀", "🚀", "👍🏽", "🇮🇹", "👩‍💻", "❤️",
+}
+
+func (text) Generate(r *rand.Rand, size int) reflect.Value {
+	var sb strings.Builder
+	for n := r.Intn(size + 1); n > 0; n-- {
+		sb.WriteString(textPieces[r.Intn(len(textPieces))])
+	}
+	return reflect.ValueOf(text(sb.String()))
+}
+
+// width is a column width from 0 to 40.
+type width int
+
+func (width) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(width(r.Intn(41)))
+}
+
+func check(t *testing.T, property interface{}) {
+	t.Helper()
+	if err := quick.Check(property, &quick.Config{MaxCount: 2000}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTruncate_Properties(t *testing.T) {
+	check(t, func(s text, w width) bool {
+		out := Truncate(string(s), int(w))
+		return utf8.ValidString(out) && Width(out) <= int(w)
+	})
+
+	// Text that fits is left alone
+	check(t, func(s text, w width) bool {
+		if Width(string(s)) > int(w) {
+			return true
+		}
+		return Truncate(string(s), int(w)) == string(s)
+	})
+
+	// Truncated text is a prefix of the original plus the ellipsis
+	check(t, func(s text, w width) bool {
+		out := Truncate(string(s), int(w))
+		if out == string(s) {
+			return true
+		}
+		return strings.HasPrefix(string(s), strings.TrimSuffix(out, Ellipsis))
+	})
+}
+
+func TestCut_Properties(t *testing.T) {
+	check(t, func(s text, w width) bool {
+		out := Cut(string(s), int(w))
+		return utf8.ValidString(out) && Width(out) <= int(w) && strings.HasPrefix(string(s), out)
+	})
+}
+
+func TestPad_Properties(t *testing.T) {
+	check(t, func(s text, w width) bool {
+		want := max(Width(string(s)), int(w))
+		right := PadRight(string(s), int(w))
+		left := PadLeft(string(s), int(w))
+		return Width(right) == want && strings.HasPrefix(right, string(s)) &&
+			Width(left) == want && strings.HasSuffix(left, string(s))
+	})
+
+	check(t, func(s text, w width) bool {
+		return Width(Fit(string(s), int(w))) == int(w)
+	})
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		want  string
+	}{
+		{"hello", 10, "hello"},
+		{"hello world", 8, "hello .."},
+		{"日本語のテキスト", 7, "日本.."},
+		{"🚀🚀🚀🚀", 5, "🚀.."},
+		{"abc", 2, "ab"},
+		{"abc", 0, ""},
+	}
+
+	for _, tt := range tests {
+		if got := Truncate(tt.in, tt.width); got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestShortID(t *testing.T) {
+	if got := ShortID("0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0"); got != "0f1e2d3c" {
+		t.Errorf("expected the first 8 characters, got %q", got)
+	}
+	if got := ShortID("abc"); got != "abc" {
+		t.Errorf("expected a short ID unchanged, got %q", got)
+	}
+}
@@ -0,0 +1,236 @@
+// Package email parses the emails the intake gateway turns into jobs and
+// checks their senders against the allowlist.
+package email
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Attachment is a file attached to an email.
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Message is the part of an email a job is made from.
+type Message struct {
+	From        string   // Sender address
+	To          []string // Recipient addresses, from To, Cc and the delivery headers
+	Subject     string
+	Text        string // Plain text body, without the signature
+	Attachments []Attachment
+}
+
+var (
+	htmlBreakPattern = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>|</li>`)
+	htmlTagPattern   = regexp.MustCompile(`(?s)<[^>]*>`)
+	blankRunsPattern = regexp.MustCompile(`\n{3,}`)
+)
+
+// Parse reads an email in RFC 5322 form, as an MTA delivers it.
+func Parse(r io.Reader) (*Message, error) {
+	raw, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid email: %w", err)
+	}
+
+	from, err := mail.ParseAddress(raw.Header.Get("From"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid From address: %w", err)
+	}
+
+	dec := new(mime.WordDecoder)
+	subject, err := dec.DecodeHeader(raw.Header.Get("Subject"))
+	if err != nil {
+		subject = raw.Header.Get("Subject")
+	}
+
+	msg := &Message{
+		From:    strings.ToLower(from.Address),
+		Subject: strings.Join(strings.Fields(subject), " "),
+	}
+	for _, key := range []string{"To", "Cc"} {
+		addrs, _ := raw.Header.AddressList(key)
+		for _, a := range addrs {
+			msg.To = append(msg.To, strings.ToLower(a.Address))
+		}
+	}
+	for _, key := range []string{"Delivered-To", "X-Original-To"} {
+		for _, v := range raw.Header[key] {
+			if a, err := mail.ParseAddress(v); err == nil {
+				msg.To = append(msg.To, strings.ToLower(a.Address))
+			}
+		}
+	}
+
+	var html string
+	err = msg.readPart(raw.Header.Get("Content-Type"), raw.Header.Get("Content-Transfer-Encoding"), "", raw.Body, &html)
+	if err != nil {
+		return nil, err
+	}
+	if msg.Text == "" && html != "" {
+		msg.Text = htmlText(html)
+	}
+	msg.Text = cleanText(msg.Text)
+	return msg, nil
+}
+
+// readPart reads one MIME part, and the parts inside it, into the message.
+// The first text/html part is kept in html in case there is no plain text.
+func (m *Message) readPart(contentType, encoding, disposition string, body io.Reader, html *string) error {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, params = "text/plain", nil
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("invalid multipart email: %w", err)
+			}
+			// NextPart has already decoded quoted-printable parts
+			err = m.readPart(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"),
+				part.Header.Get("Content-Disposition"), part, html)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := io.ReadAll(decodeTransfer(encoding, body))
+	if err != nil {
+		return fmt.Errorf("failed to decode email part: %w", err)
+	}
+
+	dispType, dispParams, _ := mime.ParseMediaType(disposition)
+	filename := dispParams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+	if dispType == "attachment" || filename != "" {
+		m.Attachments = append(m.Attachments, Attachment{
+			Filename:    safeFilename(filename, len(m.Attachments)),
+			ContentType: mediaType,
+			Data:        data,
+		})
+		return nil
+	}
+
+	switch mediaType {
+	case "text/plain":
+		if m.Text == "" {
+			m.Text = string(data)
+		}
+	case "text/html":
+		if *html == "" {
+			*html = string(data)
+		}
+	}
+	return nil
+}
+
+// decodeTransfer undoes a part's Content-Transfer-Encoding.
+func decodeTransfer(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, &newlineStripper{r: body})
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
+
+// newlineStripper drops the line breaks base64 bodies are wrapped with.
+type newlineStripper struct {
+	r io.Reader
+}
+
+func (n *newlineStripper) Read(p []byte) (int, error) {
+	count, err := n.r.Read(p)
+	out := p[:0]
+	for _, b := range p[:count] {
+		if b != '\r' && b != '\n' {
+			out = append(out, b)
+		}
+	}
+	return len(out), err
+}
+
+// safeFilename keeps an attachment's name from reaching outside the
+// directory it is stored in.
+func safeFilename(name string, index int) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "" || name == "." || name == "/" || name == ".." {
+		return fmt.Sprintf("attachment-%d", index+1)
+	}
+	return name
+}
+
+// htmlText turns an HTML body into rough plain text.
+func htmlText(html string) string {
+	html = htmlBreakPattern.ReplaceAllString(html, "\n")
+	return strings.NewReplacer("&nbsp;", " ", "&amp;", "&", "&lt;", "<", "&gt;", ">", "&quot;", `"`).
+		Replace(htmlTagPattern.ReplaceAllString(html, ""))
+}
+
+// cleanText normalizes line endings and drops the signature, which starts
+// at a "-- " line (decoding quoted-printable may have taken its space).
+func cleanText(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " \t")
+		if lines[i] == "--" {
+			lines = lines[:i]
+			break
+		}
+	}
+	text = blankRunsPattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return strings.TrimSpace(text)
+}
+
+// SentTo reports whether address is among the message's recipients.
+func (m *Message) SentTo(address string) bool {
+	address = strings.ToLower(strings.TrimSpace(address))
+	for _, to := range m.To {
+		if to == address {
+			return true
+		}
+	}
+	return false
+}
+
+// Allowed reports whether sender is on the allowlist, which holds
+// addresses and whole domains written as "@example.com". An empty
+// allowlist allows no one.
+func Allowed(sender string, allow []string) bool {
+	sender = strings.ToLower(strings.TrimSpace(sender))
+	at := strings.LastIndex(sender, "@")
+	if at < 0 {
+		return false
+	}
+	domain := sender[at:]
+
+	for _, entry := range allow {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == sender || (strings.HasPrefix(entry, "@") && entry == domain) {
+			return true
+		}
+	}
+	return false
+}
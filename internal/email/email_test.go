@@ -0,0 +1,138 @@
+package email
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse_PlainText(t *testing.T) {
+	raw := "From: Carmela <Carmela@Example.com>\r\n" +
+		"To: jobs@cosa.example\r\n" +
+		"Subject: =?UTF-8?Q?Fix_the_login_page?=\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"Content-Transfer-Encoding: quoted-printable\r\n" +
+		"\r\n" +
+		"The login button does nothing on Safari.=\r\n" +
+		" Please fix it.\r\n" +
+		"\r\n" +
+		"-- \r\n" +
+		"Carmela\r\n"
+
+	msg, err := Parse(strings.NewReader(raw))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if msg.From != "carmela@example.com" {
+		t.Errorf("From = %q", msg.From)
+	}
+	if msg.Subject != "Fix the login page" {
+		t.Errorf("Subject = %q", msg.Subject)
+	}
+	if msg.Text != "The login button does nothing on Safari. Please fix it." {
+		t.Errorf("Text = %q, expected the body without the signature", msg.Text)
+	}
+	if !msg.SentTo("Jobs@cosa.example") {
+		t.Errorf("expected the message to be sent to jobs@cosa.example, got %v", msg.To)
+	}
+	if msg.SentTo("other@cosa.example") {
+		t.Error("expected the message not to be sent to other@cosa.example")
+	}
+}
+
+func TestParse_MultipartWithAttachment(t *testing.T) {
+	raw := "From: furio@example.com\r\n" +
+		"To: someone@example.com\r\n" +
+		"Delivered-To: jobs@cosa.example\r\n" +
+		"Subject: Crash report\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: multipart/mixed; boundary=outer\r\n" +
+		"\r\n" +
+		"--outer\r\n" +
+		"Content-Type: multipart/alternative; boundary=inner\r\n" +
+		"\r\n" +
+		"--inner\r\n" +
+		"Content-Type: text/plain\r\n" +
+		"\r\n" +
+		"See the attached log.\r\n" +
+		"--inner\r\n" +
+		"Content-Type: text/html\r\n" +
+		"\r\n" +
+		"<p>See the <b>attached</b> log.</p>\r\n" +
+		"--inner--\r\n" +
+		"--outer\r\n" +
+		"Content-Type: text/plain; name=\"crash.log\"\r\n" +
+		"Content-Disposition: attachment; filename=\"../../crash.log\"\r\n" +
+		"Content-Transfer-Encoding: base64\r\n" +
+		"\r\n" +
+		"cGFuaWM6IG5pbCBt\r\n" +
+		"YXA=\r\n" +
+		"--outer--\r\n"
+
+	msg, err := Parse(strings.NewReader(raw))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if msg.Text != "See the attached log." {
+		t.Errorf("Text = %q, expected the plain text part", msg.Text)
+	}
+	if !msg.SentTo("jobs@cosa.example") {
+		t.Errorf("expected Delivered-To to count as a recipient, got %v", msg.To)
+	}
+	if len(msg.Attachments) != 1 {
+		t.Fatalf("expected 1 attachment, got %d", len(msg.Attachments))
+	}
+	a := msg.Attachments[0]
+	if a.Filename != "crash.log" {
+		t.Errorf("Filename = %q, expected the path stripped", a.Filename)
+	}
+	if string(a.Data) != "panic: nil map" {
+		t.Errorf("Data = %q", a.Data)
+	}
+}
+
+func TestParse_HTMLOnly(t *testing.T) {
+	raw := "From: a@example.com\r\n" +
+		"Subject: Hi\r\n" +
+		"Content-Type: text/html\r\n" +
+		"\r\n" +
+		"<div>First line</div><div>Tom &amp; Jerry</div>"
+
+	msg, err := Parse(strings.NewReader(raw))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if msg.Text != "First line\nTom & Jerry" {
+		t.Errorf("Text = %q", msg.Text)
+	}
+}
+
+func TestParse_InvalidFrom(t *testing.T) {
+	if _, err := Parse(strings.NewReader("Subject: x\r\n\r\nbody")); err == nil {
+		t.Error("expected an error for an email without a sender")
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	allow := []string{"paulie@example.com", "@cosa.example"}
+
+	tests := []struct {
+		sender string
+		want   bool
+	}{
+		{"paulie@example.com", true},
+		{"Paulie@Example.com", true},
+		{"silvio@example.com", false},
+		{"anyone@cosa.example", true},
+		{"anyone@evil.cosa.example", false},
+		{"not-an-address", false},
+	}
+	for _, tt := range tests {
+		if got := Allowed(tt.sender, allow); got != tt.want {
+			t.Errorf("Allowed(%q) = %v, want %v", tt.sender, got, tt.want)
+		}
+	}
+
+	if Allowed("paulie@example.com", nil) {
+		t.Error("expected an empty allowlist to allow no one")
+	}
+}
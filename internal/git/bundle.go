@@ -0,0 +1,57 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Bundle writes branches, with their history, to a bundle file at path,
+// from which Unbundle restores them.
+func (m *Manager) Bundle(path string, branches []string) error {
+	if len(branches) == 0 {
+		return fmt.Errorf("no branches to bundle")
+	}
+
+	args := append([]string{"bundle", "create", path}, branches...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = m.repoRoot
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to bundle branches: %s: %w", string(out), err)
+	}
+	return nil
+}
+
+// Unbundle creates the branches in the bundle file at path that do not
+// exist in the repository, returning their names. Existing branches are
+// left alone.
+func (m *Manager) Unbundle(path string) ([]string, error) {
+	cmd := exec.Command("git", "bundle", "list-heads", path)
+	cmd.Dir = m.repoRoot
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+
+	var refspecs, created []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		_, ref, ok := strings.Cut(line, " ")
+		branch, isBranch := strings.CutPrefix(ref, "refs/heads/")
+		if !ok || !isBranch || m.BranchExists(branch) {
+			continue
+		}
+		refspecs = append(refspecs, ref+":"+ref)
+		created = append(created, branch)
+	}
+	if len(refspecs) == 0 {
+		return nil, nil
+	}
+
+	args := append([]string{"fetch", "--quiet", path}, refspecs...)
+	cmd = exec.Command("git", args...)
+	cmd.Dir = m.repoRoot
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("failed to restore branches: %s: %w", string(out), err)
+	}
+	return created, nil
+}
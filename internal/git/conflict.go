@@ -0,0 +1,139 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// conflictMarker starts the "ours" side of a conflicted hunk.
+const conflictMarker = "<<<<<<< "
+
+// ConflictedFiles returns the files that conflict when merging branch into
+// target, without touching the working tree.
+func (m *Manager) ConflictedFiles(branch, target string) ([]string, error) {
+	if err := ValidateBranchName(branch); err != nil {
+		return nil, fmt.Errorf("invalid branch: %w", err)
+	}
+	if err := ValidateBranchName(target); err != nil {
+		return nil, fmt.Errorf("invalid target branch: %w", err)
+	}
+
+	cmd := exec.Command("git", "merge-tree", "--write-tree", "--name-only", "--no-messages", target, branch)
+	cmd.Dir = m.repoRoot
+	out, err := cmd.Output()
+	if err != nil {
+		// Exit status 1 means the merge has conflicts
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+			return nil, fmt.Errorf("failed to simulate merge: %w", err)
+		}
+	}
+
+	// The first line is the resulting tree; conflicted files follow
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	files := []string{}
+	for _, line := range lines[1:] {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// FileChanges returns the diff of file between the merge base of branch and
+// target, and branch. Calling it for both sides shows what each changed.
+func (m *Manager) FileChanges(branch, target, file string) (string, error) {
+	if err := ValidateBranchName(branch); err != nil {
+		return "", fmt.Errorf("invalid branch: %w", err)
+	}
+	if err := ValidateBranchName(target); err != nil {
+		return "", fmt.Errorf("invalid target branch: %w", err)
+	}
+
+	out, err := gitOutput(m.repoRoot, "diff", "--no-color", target+"..."+branch, "--", file)
+	if err != nil {
+		return "", fmt.Errorf("failed to diff %s: %w", file, err)
+	}
+	return out, nil
+}
+
+// StartMerge merges target into the worktree at dir without committing,
+// leaving conflict markers in place. It returns the conflicted files.
+func StartMerge(dir, target string) ([]string, error) {
+	if err := ValidateBranchName(target); err != nil {
+		return nil, fmt.Errorf("invalid target branch: %w", err)
+	}
+
+	if MergeInProgress(dir) {
+		return UnmergedFiles(dir)
+	}
+
+	cmd := exec.Command("git", "merge", "--no-ff", "--no-commit", "--", target)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil && !strings.Contains(string(out), "CONFLICT") {
+		return nil, fmt.Errorf("failed to merge: %s: %w", string(out), err)
+	}
+	return UnmergedFiles(dir)
+}
+
+// MergeInProgress reports whether the worktree at dir has an uncommitted merge.
+func MergeInProgress(dir string) bool {
+	_, err := gitOutput(dir, "rev-parse", "-q", "--verify", "MERGE_HEAD")
+	return err == nil
+}
+
+// UnmergedFiles returns the files the index still records as conflicted.
+func UnmergedFiles(dir string) ([]string, error) {
+	out, err := gitOutput(dir, "diff", "--name-only", "--diff-filter=U")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unmerged files: %w", err)
+	}
+	files := []string{}
+	for _, f := range strings.Split(strings.TrimSpace(out), "\n") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// FinishMerge stages and commits an in-progress merge in the worktree at dir.
+// It refuses while any conflicted file still contains conflict markers.
+func FinishMerge(dir string) error {
+	if !MergeInProgress(dir) {
+		return fmt.Errorf("no merge in progress")
+	}
+
+	files, err := UnmergedFiles(dir)
+	if err != nil {
+		return err
+	}
+	var remaining []string
+	for _, f := range files {
+		data, err := os.ReadFile(filepath.Join(dir, f))
+		if err == nil && bytes.Contains(data, []byte(conflictMarker)) {
+			remaining = append(remaining, f)
+		}
+	}
+	if len(remaining) > 0 {
+		return fmt.Errorf("conflict markers remain in: %s", strings.Join(remaining, ", "))
+	}
+
+	cmd := exec.Command("git", "add", "-A")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to stage merge: %s: %w", string(out), err)
+	}
+
+	cmd = exec.Command("git", "commit", "--no-edit")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to commit merge: %s: %w", string(out), err)
+	}
+	return nil
+}
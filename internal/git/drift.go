@@ -0,0 +1,101 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// commitPattern matches full or abbreviated commit hashes.
+var commitPattern = regexp.MustCompile(`^[0-9a-f]{7,64}$`)
+
+// maxDriftLog limits how many commit summaries Drift returns.
+const maxDriftLog = 20
+
+// Drift describes how far a worktree's HEAD has moved since a commit.
+type Drift struct {
+	Branch       string   // Current branch
+	Head         string   // Current HEAD commit
+	Diverged     bool     // The commit is no longer an ancestor of HEAD
+	Commits      int      // Commits from the commit to HEAD
+	FilesChanged int      // Files that differ between the commit and HEAD
+	Log          []string // One-line summaries of the newest commits
+}
+
+// HeadCommit returns the HEAD commit of the repository or worktree at dir.
+func HeadCommit(dir string) (string, error) {
+	out, err := gitOutput(dir, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// MeasureDrift compares the worktree at dir against since, a commit hash.
+func MeasureDrift(dir, since string) (*Drift, error) {
+	if !commitPattern.MatchString(since) {
+		return nil, fmt.Errorf("invalid commit: %q", since)
+	}
+
+	head, err := HeadCommit(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+
+	d := &Drift{Head: head}
+	if branch, err := gitOutput(dir, "rev-parse", "--abbrev-ref", "HEAD"); err == nil {
+		d.Branch = strings.TrimSpace(branch)
+	}
+
+	if head == since {
+		return d, nil
+	}
+
+	if _, err := gitOutput(dir, "cat-file", "-e", since+"^{commit}"); err != nil {
+		d.Diverged = true
+		return d, nil
+	}
+
+	cmd := exec.Command("git", "merge-base", "--is-ancestor", since, "HEAD")
+	cmd.Dir = dir
+	d.Diverged = cmd.Run() != nil
+
+	count, err := gitOutput(dir, "rev-list", "--count", since+"..HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to count commits: %w", err)
+	}
+	d.Commits, _ = strconv.Atoi(strings.TrimSpace(count))
+
+	files, err := gitOutput(dir, "diff", "--name-only", since, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff: %w", err)
+	}
+	for _, line := range strings.Split(files, "\n") {
+		if strings.TrimSpace(line) != "" {
+			d.FilesChanged++
+		}
+	}
+
+	log, err := gitOutput(dir, "log", "--oneline", "-n", strconv.Itoa(maxDriftLog), since+"..HEAD")
+	if err == nil {
+		for _, line := range strings.Split(strings.TrimSpace(log), "\n") {
+			if line != "" {
+				d.Log = append(d.Log, line)
+			}
+		}
+	}
+
+	return d, nil
+}
+
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
@@ -0,0 +1,75 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// JobTrailer is the trailer naming the job a commit was made for.
+const JobTrailer = "Cosa-Job"
+
+// Environment variables the job hooks read.
+const (
+	EnvJobID     = "COSA_JOB_ID"     // Job to name in the trailer
+	EnvRepoHooks = "COSA_REPO_HOOKS" // The repository's own hooks directory
+)
+
+// delegatedHooks are the hooks the job hooks pass on to the repository's
+// own, so pointing core.hooksPath at them does not switch those off.
+var delegatedHooks = []string{
+	"applypatch-msg", "pre-applypatch", "post-applypatch",
+	"pre-commit", "pre-merge-commit", "prepare-commit-msg", "post-commit",
+	"pre-rebase", "post-checkout", "post-merge", "pre-push", "post-rewrite",
+	"pre-auto-gc", "push-to-checkout", "sendemail-validate", "post-index-change",
+}
+
+// runRepoHook runs the repository's own hook of the same name, if it has one.
+const runRepoHook = `hook="$COSA_REPO_HOOKS/$(basename "$0")"
+if [ -n "$COSA_REPO_HOOKS" ] && [ -x "$hook" ]; then
+	"$hook" "$@" || exit $?
+fi
+`
+
+// commitMsgHook adds the job trailer after the repository's own hook has
+// accepted the message.
+var commitMsgHook = runRepoHook + `if [ -n "$` + EnvJobID + `" ]; then
+	git interpret-trailers --in-place --if-exists addIfDifferent --trailer "` + JobTrailer + `: $` + EnvJobID + `" "$1" || exit 1
+fi
+`
+
+// InstallJobHooks writes the hooks that add the job trailer to commits into
+// dir, for core.hooksPath in job worktrees. Every other hook runs the
+// repository's own, found through COSA_REPO_HOOKS.
+func InstallJobHooks(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+
+	hooks := map[string]string{"commit-msg": commitMsgHook}
+	for _, name := range delegatedHooks {
+		hooks[name] = runRepoHook
+	}
+	for name, body := range hooks {
+		script := "#!/bin/sh\n# Installed by cosa; see 'git.job_trailer'.\n" + body
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0755); err != nil {
+			return fmt.Errorf("failed to write %s hook: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// HooksDir returns the absolute path of the hooks directory git uses in
+// the worktree at dir, honoring core.hooksPath.
+func HooksDir(dir string) (string, error) {
+	out, err := gitOutput(dir, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", fmt.Errorf("failed to find hooks directory: %w", err)
+	}
+	path := strings.TrimSpace(out)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return path, nil
+}
@@ -2,6 +2,7 @@
 package git
 
 import (
+	"errors"
 	"fmt"
 	"os/exec"
 	"regexp"
@@ -60,18 +61,46 @@ func (m *Manager) GetDiff(worktreePath, baseBranch string) (*DiffResult, error)
 		return nil, fmt.Errorf("invalid base branch: %w", err)
 	}
 
+	return diffRange(worktreePath, baseBranch+"...HEAD")
+}
+
+// GetBranchDiff returns the diff of a branch against the base branch since
+// they diverged, for branches whose worktree has been removed.
+func (m *Manager) GetBranchDiff(branch, baseBranch string) (*DiffResult, error) {
+	if err := ValidateBranchName(branch); err != nil {
+		return nil, fmt.Errorf("invalid branch: %w", err)
+	}
+	if err := ValidateBranchName(baseBranch); err != nil {
+		return nil, fmt.Errorf("invalid base branch: %w", err)
+	}
+
+	return diffRange(m.repoRoot, baseBranch+"..."+branch)
+}
+
+// GetMergeDiff returns the changes a merge commit brought into its target:
+// the diff from its first parent, for branches merged and deleted.
+func (m *Manager) GetMergeDiff(commit string) (*DiffResult, error) {
+	if !commitPattern.MatchString(commit) {
+		return nil, fmt.Errorf("invalid commit: %q", commit)
+	}
+
+	return diffRange(m.repoRoot, commit+"^1.."+commit)
+}
+
+// diffRange diffs the revision range in the repository at dir.
+func diffRange(dir, revRange string) (*DiffResult, error) {
 	// Get the diff content
-	// Use -- to separate revision range from paths
-	cmd := exec.Command("git", "diff", "--", baseBranch+"...HEAD")
-	cmd.Dir = worktreePath
+	// Use -- after the revision range so it is never read as a path
+	cmd := exec.Command("git", "diff", revRange, "--")
+	cmd.Dir = dir
 	diffOut, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get diff: %w", err)
 	}
 
 	// Get list of changed files
-	cmd = exec.Command("git", "diff", "--name-only", "--", baseBranch+"...HEAD")
-	cmd.Dir = worktreePath
+	cmd = exec.Command("git", "diff", "--name-only", revRange, "--")
+	cmd.Dir = dir
 	filesOut, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get changed files: %w", err)
@@ -85,8 +114,8 @@ func (m *Manager) GetDiff(worktreePath, baseBranch string) (*DiffResult, error)
 	}
 
 	// Get stats
-	cmd = exec.Command("git", "diff", "--numstat", "--", baseBranch+"...HEAD")
-	cmd.Dir = worktreePath
+	cmd = exec.Command("git", "diff", "--numstat", revRange, "--")
+	cmd.Dir = dir
 	statsOut, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get diff stats: %w", err)
@@ -113,8 +142,9 @@ func (m *Manager) Merge(workerBranch, baseBranch string) (*MergeResult, error) {
 	}
 
 	// First, checkout the base branch
-	// Use -- to separate options from branch name
-	cmd := exec.Command("git", "checkout", "--", baseBranch)
+	// The trailing -- keeps it from being read as a path; validation has
+	// already ruled out an option
+	cmd := exec.Command("git", "checkout", baseBranch, "--")
 	cmd.Dir = m.repoRoot
 	if out, err := cmd.CombinedOutput(); err != nil {
 		return nil, fmt.Errorf("failed to checkout base branch: %s: %w", string(out), err)
@@ -189,6 +219,27 @@ func (m *Manager) HasConflicts(workerBranch, baseBranch string) (bool, []string,
 	return len(conflicts) > 0, conflicts, nil
 }
 
+// IsMerged checks whether every commit on branch is already reachable from target.
+func (m *Manager) IsMerged(branch, target string) (bool, error) {
+	if err := ValidateBranchName(branch); err != nil {
+		return false, fmt.Errorf("invalid branch: %w", err)
+	}
+	if err := ValidateBranchName(target); err != nil {
+		return false, fmt.Errorf("invalid target branch: %w", err)
+	}
+
+	cmd := exec.Command("git", "merge-base", "--is-ancestor", branch, target)
+	cmd.Dir = m.repoRoot
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check merge status: %w", err)
+	}
+	return true, nil
+}
+
 // AbortMerge aborts an in-progress merge.
 func (m *Manager) AbortMerge() error {
 	cmd := exec.Command("git", "merge", "--abort")
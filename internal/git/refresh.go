@@ -0,0 +1,144 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Refresh describes how a job worktree was brought up to date with its
+// merge target.
+type Refresh struct {
+	Behind    int    // Commits the branch was behind the target
+	Ahead     int    // Commits on the branch that are not on the target
+	Recreated bool   // The worktree was recreated from the target
+	Rebased   bool   // The branch's own commits were rebased onto the target
+	Commit    string // HEAD of the refreshed worktree
+}
+
+// BranchDivergence returns how many commits branch is behind and ahead of
+// target.
+func (m *Manager) BranchDivergence(branch, target string) (behind, ahead int, err error) {
+	out, err := gitOutput(m.repoRoot, "rev-list", "--left-right", "--count", target+"..."+branch)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to compare %s with %s: %w", branch, target, err)
+	}
+
+	fields := strings.Fields(out)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %q", out)
+	}
+	behind, _ = strconv.Atoi(fields[0])
+	ahead, _ = strconv.Atoi(fields[1])
+	return behind, ahead, nil
+}
+
+// RefreshJobWorktree brings a job's worktree up to date with baseBranch.
+// A branch without commits of its own is recreated from the target; one
+// with commits is rebased onto it, leaving the branch untouched if the
+// rebase conflicts. Worktrees with uncommitted changes are not refreshed.
+func (m *Manager) RefreshJobWorktree(jobID, baseBranch string) (*Refresh, error) {
+	if baseBranch == "" {
+		baseBranch = m.GetDefaultBranch()
+	}
+
+	path := m.GetJobWorktreePath(jobID)
+	branch := m.GetJobBranchName(jobID)
+	if path == "" {
+		return nil, fmt.Errorf("jobID cannot be empty")
+	}
+
+	behind, ahead, err := m.BranchDivergence(branch, baseBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Refresh{Behind: behind, Ahead: ahead}
+	if behind == 0 {
+		r.Commit, _ = m.getHeadCommit(path)
+		return r, nil
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		files, err := UncommittedFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		if len(files) > 0 {
+			return nil, fmt.Errorf("worktree has %d uncommitted files", len(files))
+		}
+	}
+
+	if ahead == 0 {
+		if _, err := os.Stat(path); err == nil {
+			if err := m.RemoveJobWorktree(jobID, true); err != nil {
+				return nil, err
+			}
+		}
+		if err := m.DeleteBranch(branch, true); err != nil {
+			return nil, err
+		}
+
+		wt, err := m.CreateJobWorktree(jobID, baseBranch)
+		if err != nil {
+			return nil, err
+		}
+		r.Recreated = true
+		r.Commit = wt.Commit
+		return r, nil
+	}
+
+	// Keep the job's own work and replay it on the current target
+	if _, err := m.CreateJobWorktree(jobID, baseBranch); err != nil {
+		return nil, err
+	}
+	cmd := exec.Command("git", "rebase", baseBranch)
+	cmd.Dir = path
+	if out, err := cmd.CombinedOutput(); err != nil {
+		abort := exec.Command("git", "rebase", "--abort")
+		abort.Dir = path
+		abort.Run()
+		return nil, fmt.Errorf("failed to rebase onto %s: %s: %w", baseBranch, strings.TrimSpace(string(out)), err)
+	}
+
+	r.Rebased = true
+	r.Commit, _ = m.getHeadCommit(path)
+	return r, nil
+}
+
+// RebaseBranch replays branch's own commits onto baseBranch, so that it
+// merges cleanly after the target moved. The rebase runs in the worktree
+// that has branch checked out, if any. A conflicting rebase is aborted,
+// leaving the branch as it was.
+func (m *Manager) RebaseBranch(branch, baseBranch string) error {
+	if err := ValidateBranchName(branch); err != nil {
+		return fmt.Errorf("invalid branch: %w", err)
+	}
+	if err := ValidateBranchName(baseBranch); err != nil {
+		return fmt.Errorf("invalid base branch: %w", err)
+	}
+
+	dir := m.repoRoot
+	args := []string{"rebase", baseBranch, branch}
+	if worktrees, err := m.ListWorktrees(); err == nil {
+		for _, wt := range worktrees {
+			if wt.Branch == branch {
+				dir = wt.Path
+				args = []string{"rebase", baseBranch}
+				break
+			}
+		}
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		abort := exec.Command("git", "rebase", "--abort")
+		abort.Dir = dir
+		abort.Run()
+		return fmt.Errorf("failed to rebase %s onto %s: %s: %w", branch, baseBranch, strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Pull request providers.
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+// PullRequest describes a pull (or merge) request to open.
+type PullRequest struct {
+	Branch string // Source branch, already pushed
+	Target string // Branch to merge into
+	Title  string
+	Body   string
+}
+
+// RemoteURL returns the fetch URL of the named remote.
+func (m *Manager) RemoteURL(remote string) (string, error) {
+	out, err := gitOutput(m.repoRoot, "remote", "get-url", remote)
+	if err != nil {
+		return "", fmt.Errorf("remote %s not found: %w", remote, err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// PushBranch pushes a local branch to the remote under the same name. The
+// push is forced with a lease so a rebased job branch replaces the copy cosa
+// pushed earlier, but never someone else's changes.
+func (m *Manager) PushBranch(remote, branch string) error {
+	cmd := exec.Command("git", "push", "--force-with-lease", "--set-upstream", remote, branch)
+	cmd.Dir = m.repoRoot
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to push %s to %s: %s: %w", branch, remote, strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
+
+// DiffStat returns git's summary of the files changed on head since it
+// forked from base, such as "3 files changed, 40 insertions(+)".
+func (m *Manager) DiffStat(base, head string) (string, error) {
+	out, err := gitOutput(m.repoRoot, "diff", "--stat", base+"..."+head, "--")
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff stat: %w", err)
+	}
+	return strings.TrimRight(out, "\n"), nil
+}
+
+// DetectProvider guesses the pull request provider from a remote URL.
+func DetectProvider(remoteURL string) string {
+	switch {
+	case strings.Contains(remoteURL, "gitlab"):
+		return ProviderGitLab
+	case strings.Contains(remoteURL, "github"):
+		return ProviderGitHub
+	}
+	return ""
+}
+
+// OpenPullRequest opens a pull request with the provider's CLI (gh for
+// GitHub, glab for GitLab) and returns its URL.
+func (m *Manager) OpenPullRequest(provider string, pr PullRequest) (string, error) {
+	var cmd *exec.Cmd
+	switch provider {
+	case ProviderGitHub:
+		cmd = exec.Command("gh", "pr", "create",
+			"--head", pr.Branch, "--base", pr.Target,
+			"--title", pr.Title, "--body", pr.Body)
+	case ProviderGitLab:
+		cmd = exec.Command("glab", "mr", "create", "--yes",
+			"--source-branch", pr.Branch, "--target-branch", pr.Target,
+			"--title", pr.Title, "--description", pr.Body)
+	default:
+		return "", fmt.Errorf("unknown pull request provider %q", provider)
+	}
+	cmd.Dir = m.repoRoot
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to open pull request: %s: %w", strings.TrimSpace(string(out)), err)
+	}
+
+	// Both CLIs print the new request's URL; take the last one
+	var url string
+	for _, field := range strings.Fields(string(out)) {
+		if strings.HasPrefix(field, "https://") || strings.HasPrefix(field, "http://") {
+			url = field
+		}
+	}
+	if url == "" {
+		return "", fmt.Errorf("pull request opened but no URL was reported: %s", strings.TrimSpace(string(out)))
+	}
+	return url, nil
+}
+
+// UpdatePullRequest replaces the title and body of the pull request opened
+// for pr.Branch. url identifies it to providers that accept one.
+func (m *Manager) UpdatePullRequest(provider, url string, pr PullRequest) error {
+	var cmd *exec.Cmd
+	switch provider {
+	case ProviderGitHub:
+		cmd = exec.Command("gh", "pr", "edit", url,
+			"--title", pr.Title, "--body", pr.Body)
+	case ProviderGitLab:
+		cmd = exec.Command("glab", "mr", "update", pr.Branch,
+			"--title", pr.Title, "--description", pr.Body)
+	default:
+		return fmt.Errorf("unknown pull request provider %q", provider)
+	}
+	cmd.Dir = m.repoRoot
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to update pull request: %s: %w", strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
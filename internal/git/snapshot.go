@@ -0,0 +1,64 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// UncommittedFiles returns the files with staged, unstaged or untracked
+// changes in the worktree at dir.
+func UncommittedFiles(dir string) ([]string, error) {
+	out, err := gitOutput(dir, "status", "--porcelain", "--untracked-files=all")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status: %w", err)
+	}
+
+	var files []string
+	for _, line := range strings.Split(out, "\n") {
+		if len(line) < 4 {
+			continue
+		}
+		path := line[3:]
+		// Renames are listed as "old -> new"
+		if _, newPath, ok := strings.Cut(path, " -> "); ok {
+			path = newPath
+		}
+		files = append(files, strings.Trim(path, `"`))
+	}
+	return files, nil
+}
+
+// CommitAll stages every change in the worktree at dir and commits it with
+// message, returning the new commit. env is added to the commit's
+// environment, such as the author and signing of a worker's commits.
+func CommitAll(dir, message string, env ...string) (string, error) {
+	cmd := exec.Command("git", "add", "-A")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to stage changes: %s: %w", string(out), err)
+	}
+
+	cmd = exec.Command("git", "commit", "-m", message)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to commit changes: %s: %w", string(out), err)
+	}
+
+	return HeadCommit(dir)
+}
+
+// CommitsSince counts the commits on HEAD in the worktree at dir that are
+// not reachable from base.
+func CommitsSince(dir, base string) (int, error) {
+	out, err := gitOutput(dir, "rev-list", "--count", base+"..HEAD")
+	if err != nil {
+		return 0, fmt.Errorf("failed to count commits: %w", err)
+	}
+	return strconv.Atoi(strings.TrimSpace(out))
+}
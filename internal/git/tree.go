@@ -0,0 +1,64 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// TreeFile is a file committed in a tree.
+type TreeFile struct {
+	Path string
+	Size int64
+}
+
+// TreeFiles lists the files committed at rev in the repository at dir, with
+// their sizes. Submodules and symlinks are left out.
+func TreeFiles(dir, rev string) ([]TreeFile, error) {
+	out, err := gitOutput(dir, "ls-tree", "-r", "-l", "-z", "--full-tree", rev)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files at %s: %w", rev, err)
+	}
+
+	var files []TreeFile
+	for _, entry := range strings.Split(out, "\x00") {
+		// <mode> SP <type> SP <object> SP+ <size> TAB <path>
+		meta, path, ok := strings.Cut(entry, "\t")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(meta)
+		if len(fields) != 4 || fields[1] != "blob" || fields[0] == "120000" {
+			continue
+		}
+		size, _ := strconv.ParseInt(fields[3], 10, 64)
+		files = append(files, TreeFile{Path: path, Size: size})
+	}
+	return files, nil
+}
+
+// GrepFiles lists the tracked text files in the working tree at dir that
+// contain term, ignoring case.
+func GrepFiles(dir, term string) ([]string, error) {
+	cmd := exec.Command("git", "grep", "-l", "-z", "-I", "-i", "-F", "-e", term)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		// Exit status 1 means nothing matched
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to search for %q: %w", term, err)
+	}
+
+	var files []string
+	for _, f := range strings.Split(string(out), "\x00") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
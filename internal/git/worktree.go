@@ -6,7 +6,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Worktree represents a git worktree.
@@ -63,7 +65,7 @@ func (m *Manager) CreateWorktree(name, baseBranch string) (*Worktree, error) {
 	}
 
 	// Check if branch already exists
-	branchExists := m.branchExists(branchName)
+	branchExists := m.BranchExists(branchName)
 
 	if branchExists {
 		// Create worktree using existing branch
@@ -193,10 +195,10 @@ func (m *Manager) GetCurrentBranch() (string, error) {
 // GetDefaultBranch attempts to determine the default branch (main/master).
 func (m *Manager) GetDefaultBranch() string {
 	// Try common default branch names first
-	if m.branchExists("main") {
+	if m.BranchExists("main") {
 		return "main"
 	}
-	if m.branchExists("master") {
+	if m.BranchExists("master") {
 		return "master"
 	}
 
@@ -210,7 +212,8 @@ func (m *Manager) GetDefaultBranch() string {
 	return "master"
 }
 
-func (m *Manager) branchExists(name string) bool {
+// BranchExists reports whether the local branch name exists.
+func (m *Manager) BranchExists(name string) bool {
 	cmd := exec.Command("git", "rev-parse", "--verify", "refs/heads/"+name)
 	cmd.Dir = m.repoRoot
 	return cmd.Run() == nil
@@ -313,7 +316,7 @@ func (m *Manager) CreateJobWorktree(jobID, baseBranch string) (*Worktree, error)
 	}
 
 	// Check if branch already exists
-	branchExists := m.branchExists(branchName)
+	branchExists := m.BranchExists(branchName)
 
 	if branchExists {
 		cmd := exec.Command("git", "worktree", "add", worktreePath, branchName)
@@ -390,6 +393,33 @@ func (m *Manager) DeleteBranch(branchName string, force bool) error {
 	return nil
 }
 
+// Branch is a local branch and when its last commit was made.
+type Branch struct {
+	Name      string
+	Committed time.Time
+}
+
+// ListBranches lists the local branches under prefix, such as "cosa/job/".
+func (m *Manager) ListBranches(prefix string) ([]Branch, error) {
+	cmd := exec.Command("git", "for-each-ref", "--format=%(refname:short) %(committerdate:unix)", "refs/heads/"+prefix)
+	cmd.Dir = m.repoRoot
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	var branches []Branch
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		name, unix, ok := strings.Cut(line, " ")
+		if !ok || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		sec, _ := strconv.ParseInt(unix, 10, 64)
+		branches = append(branches, Branch{Name: name, Committed: time.Unix(sec, 0)})
+	}
+	return branches, nil
+}
+
 // GetJobWorktreePath returns the path for a job's worktree.
 // Returns empty string if jobID is empty.
 func (m *Manager) GetJobWorktreePath(jobID string) string {
@@ -415,3 +445,57 @@ func (m *Manager) GetJobBranchName(jobID string) string {
 	}
 	return fmt.Sprintf("cosa/job/%s", shortID)
 }
+
+// ExcludePaths adds paths, relative to the repository root, to the
+// repository's info/exclude so git ignores them in every worktree. Paths
+// already excluded are skipped.
+func (m *Manager) ExcludePaths(paths []string) error {
+	cmd := exec.Command("git", "rev-parse", "--git-common-dir")
+	cmd.Dir = m.repoRoot
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to find git directory: %w", err)
+	}
+	gitDir := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(m.repoRoot, gitDir)
+	}
+
+	excludePath := filepath.Join(gitDir, "info", "exclude")
+	content, err := os.ReadFile(excludePath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read exclude file: %w", err)
+	}
+
+	existing := make(map[string]bool)
+	for _, line := range strings.Split(string(content), "\n") {
+		existing[strings.TrimSpace(line)] = true
+	}
+
+	var add []string
+	for _, p := range paths {
+		pattern := "/" + filepath.ToSlash(p)
+		if !existing[pattern] {
+			existing[pattern] = true
+			add = append(add, pattern)
+		}
+	}
+	if len(add) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(excludePath), 0755); err != nil {
+		return fmt.Errorf("failed to create exclude file: %w", err)
+	}
+	f, err := os.OpenFile(excludePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open exclude file: %w", err)
+	}
+	defer f.Close()
+
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		f.WriteString("\n")
+	}
+	_, err = f.WriteString(strings.Join(add, "\n") + "\n")
+	return err
+}
@@ -0,0 +1,47 @@
+package job
+
+import (
+	"fmt"
+	"maps"
+)
+
+// Delegate returns a fresh pending job for description, delegated by the
+// worker named from while it runs j. The new job records j as the job that
+// delegated it, and takes j's priority and labels.
+func (j *Job) Delegate(description, from string) (*Job, error) {
+	if j.GetStatus() != StatusRunning {
+		return nil, fmt.Errorf("job %s is %s; only running jobs can delegate", j.ID, j.GetStatus())
+	}
+
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	sub := New(description)
+	sub.Priority = j.Priority
+	sub.Labels = maps.Clone(j.Labels)
+	sub.DelegatedBy = j.ID
+	sub.DelegatedFrom = from
+	return sub, nil
+}
+
+// GetDelegatedBy returns the ID of the job that delegated this job, if
+// any.
+func (j *Job) GetDelegatedBy() string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.DelegatedBy
+}
+
+// AddMessages adds messages held for the job's worker to the job's prompt.
+func (j *Job) AddMessages(messages ...string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Messages = append(j.Messages, messages...)
+}
+
+// GetMessages returns the messages given with the job's prompt.
+func (j *Job) GetMessages() []string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.Messages
+}
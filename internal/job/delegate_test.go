@@ -0,0 +1,48 @@
+package job
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestJob_Delegate(t *testing.T) {
+	j := New("Build the billing page")
+	j.Priority = PriorityHigh
+	j.SetLabels(map[string]string{"area": "billing"})
+
+	if _, err := j.Delegate("Add the invoice table", "tony"); err == nil {
+		t.Error("expected error delegating from a job that is not running")
+	}
+
+	j.Queue()
+	j.Start("worker-1", "session-1")
+	sub, err := j.Delegate("Add the invoice table", "tony")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if sub.ID == j.ID || sub.GetDelegatedBy() != j.ID || sub.DelegatedFrom != "tony" {
+		t.Errorf("expected a new job delegated by %s from tony, got %s delegated by %q from %q", j.ID, sub.ID, sub.GetDelegatedBy(), sub.DelegatedFrom)
+	}
+	if sub.GetStatus() != StatusPending || sub.Description != "Add the invoice table" {
+		t.Errorf("expected a pending job for the description, got %s %q", sub.GetStatus(), sub.Description)
+	}
+	if sub.Priority != PriorityHigh || sub.GetLabels()["area"] != "billing" {
+		t.Errorf("expected the delegating job's priority and labels, got %d %v", sub.Priority, sub.GetLabels())
+	}
+
+	// The labels are copied, not shared
+	sub.SetLabels(map[string]string{"area": "invoices"})
+	if j.GetLabels()["area"] != "billing" {
+		t.Errorf("expected the delegating job's labels to be unchanged, got %v", j.GetLabels())
+	}
+}
+
+func TestJob_AddMessages(t *testing.T) {
+	j := New("Build the billing page")
+	j.AddMessages("first")
+	j.AddMessages("second", "third")
+	if got := j.GetMessages(); !slices.Equal(got, []string{"first", "second", "third"}) {
+		t.Errorf("unexpected messages %v", got)
+	}
+}
@@ -0,0 +1,28 @@
+package job
+
+import "fmt"
+
+// HoldAsDraft holds a new job as a draft, which is not queued until it is
+// approved.
+func (j *Job) HoldAsDraft() error {
+	return j.transitionFrom([]Status{StatusPending}, StatusDraft, "held as a draft", nil)
+}
+
+// IsDraft reports whether the job is a draft waiting for approval. Drafts
+// held before drafts had a status of their own wait in needs_attention.
+func (j *Job) IsDraft() bool {
+	status := j.GetStatus()
+	return status == StatusDraft ||
+		status == StatusNeedsAttention && j.GetAttentionKind() == AttentionDraft
+}
+
+// ApproveDraft approves a job held as a draft and sends it to pending, to
+// be queued.
+func (j *Job) ApproveDraft() error {
+	if !j.IsDraft() {
+		return fmt.Errorf("job %s is not a draft waiting for approval", j.ID)
+	}
+	return j.transitionFrom([]Status{StatusDraft, StatusNeedsAttention}, StatusPending, "draft approved", func() {
+		j.clearAttention()
+	})
+}
@@ -0,0 +1,64 @@
+package job
+
+import "testing"
+
+func TestJob_ApproveDraft(t *testing.T) {
+	j := New("test")
+	if err := j.ApproveDraft(); err == nil {
+		t.Error("expected error approving a job that is not a draft")
+	}
+
+	if err := j.HoldAsDraft(); err != nil {
+		t.Fatalf("expected a pending job to be held as a draft: %v", err)
+	}
+	if j.GetStatus() != StatusDraft || !j.IsDraft() {
+		t.Fatalf("expected status draft, got %s", j.GetStatus())
+	}
+	if err := j.ApprovePlan(); err == nil {
+		t.Error("expected error approving the plan of a draft")
+	}
+	if err := j.Queue(); err == nil {
+		t.Error("expected error queueing a draft")
+	}
+
+	if err := j.ApproveDraft(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if j.GetStatus() != StatusPending {
+		t.Errorf("expected status pending, got %s", j.GetStatus())
+	}
+}
+
+func TestJob_ApproveDraft_Legacy(t *testing.T) {
+	// Drafts used to wait in needs_attention
+	j := New("test")
+	if err := j.MarkNeedsAttention(AttentionDraft, "filed by email"); err != nil {
+		t.Fatal(err)
+	}
+	if !j.IsDraft() {
+		t.Fatal("expected a legacy draft to be a draft")
+	}
+
+	if err := j.ApproveDraft(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if j.GetStatus() != StatusPending {
+		t.Errorf("expected status pending, got %s", j.GetStatus())
+	}
+	if j.GetAttentionKind() != "" {
+		t.Error("expected attention state to be cleared")
+	}
+}
+
+func TestJob_CancelDraft(t *testing.T) {
+	j := New("test")
+	if err := j.HoldAsDraft(); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Cancel(); err != nil {
+		t.Fatalf("expected a draft to be cancellable: %v", err)
+	}
+	if err := j.HoldAsDraft(); err == nil {
+		t.Error("expected error holding a cancelled job as a draft")
+	}
+}
@@ -0,0 +1,219 @@
+package job
+
+import (
+	"sort"
+	"time"
+)
+
+// estimateSamples is how many of the most recently finished jobs an
+// estimate is drawn from.
+const estimateSamples = 20
+
+// minEstimateSamples is how many finished jobs a template, label or worker
+// needs before its durations are preferred to broader ones.
+const minEstimateSamples = 3
+
+// Estimate is how long a job is expected to run.
+type Estimate struct {
+	Duration time.Duration
+	Basis    string // What it was drawn from: "template <id>", "label <key>=<value>", "worker" or "all jobs"
+	Samples  int
+}
+
+// Estimator predicts how long jobs will run from how long finished jobs
+// like them took: jobs from the same template, then with the same label,
+// then run by the same worker, then any job.
+type Estimator struct {
+	byTemplate map[string][]time.Duration
+	byLabel    map[string][]time.Duration // By "key=value"
+	byWorker   map[string][]time.Duration // By worker ID
+	all        []time.Duration
+}
+
+// NewEstimator builds an estimator from the completed jobs among jobs.
+func NewEstimator(jobs []*Job) *Estimator {
+	type finished struct {
+		template string
+		labels   map[string]string
+		worker   string
+		at       time.Time
+		took     time.Duration
+	}
+
+	var done []finished
+	for _, j := range jobs {
+		j.mu.RLock()
+		if j.Status == StatusCompleted && j.StartedAt != nil && j.CompletedAt != nil && j.CompletedAt.After(*j.StartedAt) {
+			done = append(done, finished{
+				template: j.Template,
+				labels:   j.Labels,
+				worker:   j.Worker,
+				at:       *j.CompletedAt,
+				took:     j.CompletedAt.Sub(*j.StartedAt),
+			})
+		}
+		j.mu.RUnlock()
+	}
+
+	// The most recent jobs say the most about how long the next will take
+	sort.Slice(done, func(a, b int) bool {
+		return done[a].at.After(done[b].at)
+	})
+
+	e := &Estimator{
+		byTemplate: make(map[string][]time.Duration),
+		byLabel:    make(map[string][]time.Duration),
+		byWorker:   make(map[string][]time.Duration),
+	}
+	add := func(m map[string][]time.Duration, key string, d time.Duration) {
+		if key != "" && len(m[key]) < estimateSamples {
+			m[key] = append(m[key], d)
+		}
+	}
+	for _, f := range done {
+		add(e.byTemplate, f.template, f.took)
+		for k, v := range f.labels {
+			add(e.byLabel, k+"="+v, f.took)
+		}
+		add(e.byWorker, f.worker, f.took)
+		if len(e.all) < estimateSamples {
+			e.all = append(e.all, f.took)
+		}
+	}
+	return e
+}
+
+// Estimate returns how long j is expected to run, and false if no job has
+// finished yet to estimate from.
+func (e *Estimator) Estimate(j *Job) (Estimate, bool) {
+	j.mu.RLock()
+	template := j.Template
+	labels := make([]string, 0, len(j.Labels))
+	for k, v := range j.Labels {
+		labels = append(labels, k+"="+v)
+	}
+	worker := j.Worker
+	if worker == "" {
+		worker = j.PreferredWorker
+	}
+	j.mu.RUnlock()
+
+	if samples := e.byTemplate[template]; len(samples) >= minEstimateSamples {
+		return estimateFrom(samples, "template "+template), true
+	}
+
+	// The label with the most history, ties going to the first by name
+	sort.Strings(labels)
+	best := ""
+	for _, label := range labels {
+		if len(e.byLabel[label]) >= minEstimateSamples && len(e.byLabel[label]) > len(e.byLabel[best]) {
+			best = label
+		}
+	}
+	if best != "" {
+		return estimateFrom(e.byLabel[best], "label "+best), true
+	}
+
+	if samples := e.byWorker[worker]; len(samples) >= minEstimateSamples {
+		return estimateFrom(samples, "worker"), true
+	}
+	if len(e.all) > 0 {
+		return estimateFrom(e.all, "all jobs"), true
+	}
+	return Estimate{}, false
+}
+
+// estimateFrom returns the median of samples as an estimate.
+func estimateFrom(samples []time.Duration, basis string) Estimate {
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(a, b int) bool { return sorted[a] < sorted[b] })
+
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+	}
+	return Estimate{Duration: median, Basis: basis, Samples: len(samples)}
+}
+
+// Forecast is when unfinished jobs are expected to be done.
+type Forecast struct {
+	Estimates map[string]Estimate  // By job ID
+	Done      map[string]time.Time // When each job should finish, by job ID
+	Last      time.Time            // When the last of them should finish; zero if none will
+}
+
+// Forecast predicts when the running jobs and the waiting ones will finish
+// with the given number of workers. Waiting jobs, in the order they will be
+// started, each go to the worker that frees up first, and start no earlier
+// than the jobs they depend on finish. A running job past its estimate is
+// expected to finish now. Without any finished job to estimate from the
+// forecast is empty, and with waiting jobs but no workers Last is zero.
+func (e *Estimator) Forecast(now time.Time, workers int, running, waiting []*Job) *Forecast {
+	f := &Forecast{
+		Estimates: make(map[string]Estimate),
+		Done:      make(map[string]time.Time),
+	}
+	if len(e.all) == 0 {
+		return f
+	}
+
+	finish := func(j *Job, at time.Time) {
+		f.Done[j.ID] = at
+		if at.After(f.Last) {
+			f.Last = at
+		}
+	}
+
+	free := make([]time.Time, 0, max(workers, len(running)))
+	for _, j := range running {
+		est, _ := e.Estimate(j)
+		f.Estimates[j.ID] = est
+
+		started := j.GetStartedAt()
+		if started.IsZero() {
+			started = now // Assigned, but not started yet
+		}
+		end := started.Add(est.Duration)
+		if end.Before(now) {
+			end = now
+		}
+		finish(j, end)
+		free = append(free, end)
+	}
+	for len(free) < workers {
+		free = append(free, now)
+	}
+	if workers == 0 {
+		// With no one to start them, the waiting jobs never finish
+		if len(waiting) > 0 {
+			f.Last = time.Time{}
+		}
+		return f
+	}
+
+	for _, j := range waiting {
+		est, _ := e.Estimate(j)
+		f.Estimates[j.ID] = est
+
+		slot := 0
+		for i := range free {
+			if free[i].Before(free[slot]) {
+				slot = i
+			}
+		}
+		start := free[slot]
+		j.mu.RLock()
+		deps := j.DependsOn
+		j.mu.RUnlock()
+		for _, dep := range deps {
+			if at, ok := f.Done[dep]; ok && at.After(start) {
+				start = at
+			}
+		}
+
+		end := start.Add(est.Duration)
+		finish(j, end)
+		free[slot] = end
+	}
+	return f
+}
@@ -0,0 +1,87 @@
+package job
+
+import (
+	"testing"
+	"time"
+)
+
+// finishedJob returns a completed job that ran for took, finishing at end.
+func finishedJob(template string, labels map[string]string, took time.Duration, end time.Time) *Job {
+	j := New("done")
+	j.Template = template
+	j.SetLabels(labels)
+	start := end.Add(-took)
+	j.Status = StatusCompleted
+	j.StartedAt = &start
+	j.CompletedAt = &end
+	return j
+}
+
+func TestEstimator_PrefersTheMostSpecificHistory(t *testing.T) {
+	now := time.Now()
+	var history []*Job
+	for i := 0; i < 3; i++ {
+		history = append(history, finishedJob("bugfix", nil, 10*time.Minute, now))
+		history = append(history, finishedJob("", map[string]string{"area": "api"}, 40*time.Minute, now))
+	}
+	e := NewEstimator(history)
+
+	fromTemplate := New("fix it")
+	fromTemplate.Template = "bugfix"
+	fromTemplate.SetLabels(map[string]string{"area": "api"})
+	if est, ok := e.Estimate(fromTemplate); !ok || est.Duration != 10*time.Minute || est.Basis != "template bugfix" {
+		t.Errorf("expected the template's durations to be used, got %+v", est)
+	}
+
+	labelled := New("add endpoint")
+	labelled.SetLabels(map[string]string{"area": "api"})
+	if est, _ := e.Estimate(labelled); est.Duration != 40*time.Minute || est.Basis != "label area=api" {
+		t.Errorf("expected the label's durations to be used, got %+v", est)
+	}
+
+	if est, _ := e.Estimate(New("other")); est.Basis != "all jobs" || est.Duration != 25*time.Minute {
+		t.Errorf("expected the median of all jobs, got %+v", est)
+	}
+
+	if _, ok := NewEstimator(nil).Estimate(New("first")); ok {
+		t.Error("expected no estimate without history")
+	}
+}
+
+func TestEstimator_Forecast(t *testing.T) {
+	now := time.Now()
+	e := NewEstimator([]*Job{finishedJob("", nil, time.Hour, now)})
+
+	running := New("running")
+	started := now.Add(-30 * time.Minute)
+	running.Status = StatusRunning
+	running.StartedAt = &started
+
+	first := New("first")
+	second := New("second")
+	dependent := New("dependent")
+	dependent.SetDependencies([]string{first.ID})
+
+	f := e.Forecast(now, 2, []*Job{running}, []*Job{first, second, dependent})
+
+	// The free worker takes the first job, the running one's worker the
+	// second, and the dependent job waits for the first
+	want := map[string]time.Duration{
+		running.ID:   30 * time.Minute,
+		first.ID:     time.Hour,
+		second.ID:    90 * time.Minute,
+		dependent.ID: 2 * time.Hour,
+	}
+	for id, after := range want {
+		if got := f.Done[id]; !got.Equal(now.Add(after)) {
+			t.Errorf("job %s: expected to finish in %s, got %s", id, after, got.Sub(now))
+		}
+	}
+	if !f.Last.Equal(now.Add(2 * time.Hour)) {
+		t.Errorf("expected the queue to be done in 2h, got %s", f.Last.Sub(now))
+	}
+
+	if f := e.Forecast(now, 0, nil, []*Job{first}); !f.Last.IsZero() {
+		t.Error("expected no queue ETA without workers")
+	}
+}
@@ -16,13 +16,25 @@ import (
 type Status string
 
 const (
-	StatusPending    Status = "pending"
-	StatusQueued     Status = "queued"
-	StatusRunning    Status = "running"
-	StatusCompleted  Status = "completed"
-	StatusFailed     Status = "failed"
-	StatusCancelled  Status = "cancelled"
-	StatusReview     Status = "review"
+	StatusPending        Status = "pending"
+	StatusQueued         Status = "queued"
+	StatusRunning        Status = "running"
+	StatusCompleted      Status = "completed"
+	StatusFailed         Status = "failed"
+	StatusCancelled      Status = "cancelled"
+	StatusReview         Status = "review"
+	StatusNeedsAttention Status = "needs_attention"
+	StatusDraft          Status = "draft"
+)
+
+// Kinds of human intervention a job in needs_attention is waiting on.
+const (
+	AttentionMergeConflict  = "merge_conflict"  // Branch could not be merged and was not resolved
+	AttentionReviewRejected = "review_rejected" // Rejected by review too many times
+	AttentionQuestion       = "question"        // Worker stopped to ask a question
+	AttentionGateOverride   = "gate_override"   // Quality gates failed; override or rerun
+	AttentionPlanApproval   = "plan_approval"   // Plan phase finished; approve the plan to execute it
+	AttentionDraft          = "draft"           // Draft held before drafts had a status of their own
 )
 
 // Priority levels for jobs.
@@ -37,12 +49,38 @@ const (
 type Job struct {
 	ID          string    `json:"id"`
 	Description string    `json:"description"`
+	Body        string    `json:"body,omitempty"` // Full specification when the description is a title
 	Status      Status    `json:"status"`
 	Priority    int       `json:"priority"`
 	Worker      string    `json:"worker,omitempty"`
 	DependsOn   []string  `json:"depends_on,omitempty"`
 	Operation   string    `json:"operation,omitempty"` // Parent operation ID
 
+	// Options applied at creation (e.g. from a preset)
+	Labels     map[string]string `json:"labels,omitempty"`
+	Model      string            `json:"model,omitempty"`       // Overrides the worker's model
+	ModelUsed  string            `json:"model_used,omitempty"`  // Model the last run ran on, after any fallback
+	SkipReview bool              `json:"skip_review,omitempty"` // Skip auto-review on completion
+	NoMerge    bool              `json:"no_merge,omitempty"`    // Keep the branch instead of merging it on completion
+	Timeout    time.Duration     `json:"timeout,omitempty"`     // Fails a run that takes longer; 0 uses the default
+	PlanFirst  bool              `json:"plan_first,omitempty"`  // Plan without editing and wait for approval before executing
+	Template   string            `json:"template,omitempty"`    // ID of the template the job was created from
+
+	// Variables the template was expanded with
+	TemplateVars map[string]string `json:"template_vars,omitempty"`
+
+	// RerunOf is the ID of the finished job this job runs again
+	RerunOf string `json:"rerun_of,omitempty"`
+
+	// DelegatedBy is the ID of the job whose worker delegated this job to
+	// another worker, and DelegatedFrom that worker's name
+	DelegatedBy   string `json:"delegated_by,omitempty"`
+	DelegatedFrom string `json:"delegated_from,omitempty"`
+
+	// Messages are the messages from other workers held for the worker
+	// while it was idle, given with the job's prompt
+	Messages []string `json:"messages,omitempty"`
+
 	CreatedAt   time.Time  `json:"created_at"`
 	QueuedAt    *time.Time `json:"queued_at,omitempty"`
 	StartedAt   *time.Time `json:"started_at,omitempty"`
@@ -52,10 +90,32 @@ type Job struct {
 	Worktree string `json:"worktree,omitempty"` // Path to job's worktree
 	Branch   string `json:"branch,omitempty"`   // Branch name for this job
 
+	// MergeCommit is the commit that merged the job's branch into its
+	// target, kept after the branch is deleted so its changes can be shown
+	MergeCommit string `json:"merge_commit,omitempty"`
+
+	// Merge conflict tracking
+	Unmerged         bool   `json:"unmerged,omitempty"`          // Branch kept after a merge conflict
+	ResolvesConflict string `json:"resolves_conflict,omitempty"` // ID of job whose conflict this job resolves
+
+	// Where the branch was published instead of being merged locally
+	RemoteBranch string `json:"remote_branch,omitempty"` // Branch pushed to the remote
+	PullRequest  string `json:"pull_request,omitempty"`  // URL of the pull request opened for it
+
+	// Why the job is waiting on a human
+	AttentionKind   string     `json:"attention_kind,omitempty"`
+	AttentionReason string     `json:"attention_reason,omitempty"`
+	AttentionSince  *time.Time `json:"attention_since,omitempty"`
+
+	// Uncommitted changes found in the worktree when the job finished
+	Snapshot *WorktreeSnapshot `json:"snapshot,omitempty"`
+
 	// Execution details
 	SessionID string `json:"session_id,omitempty"` // Claude session ID
 	Error     string `json:"error,omitempty"`
 	Output    string `json:"output,omitempty"`
+	Summary   string `json:"summary,omitempty"`  // What the worker reported doing, kept when a review replaces Output
+	Timeouts  int    `json:"timeouts,omitempty"` // Runs stopped for taking longer than the timeout
 
 	// Cost tracking
 	TotalCost   string `json:"total_cost,omitempty"`   // Cost for this job
@@ -64,8 +124,71 @@ type Job struct {
 	// Review fields
 	ReviewFeedback []string `json:"review_feedback,omitempty"` // Feedback from code review
 	RevisionOf     string   `json:"revision_of,omitempty"`     // ID of job this is a revision of
+	RevisionRound  int      `json:"revision_round,omitempty"`  // 1 for the first revision of a job, 2 for its revision, ...
+
+	// PreferredWorker is the ID of the worker the scheduler holds this job
+	// for while that worker exists, such as the author of the work a
+	// revision fixes up
+	PreferredWorker string `json:"preferred_worker,omitempty"`
+
+	// StickyWorker is the name of the worker the job is pinned to. Unlike a
+	// preferred worker, the job waits for it even while it is not in the
+	// pool, and it resumes the worker's Claude session
+	StickyWorker string `json:"sticky_worker,omitempty"`
+
+	// Handoff is what the worker that last ran the job passed on to the
+	// worker taking it over
+	Handoff string `json:"handoff,omitempty"`
+
+	// RelevantFiles are the files found relevant to the job when it last
+	// started, listed in its prompt
+	RelevantFiles []string `json:"relevant_files,omitempty"`
+
+	// Review checklist from the job's template, and the reviewer's verdict on each item
+	ReviewChecklist  []string          `json:"review_checklist,omitempty"`
+	ChecklistResults []ChecklistResult `json:"checklist_results,omitempty"`
 
-	mu sync.RWMutex
+	// Verdict of the job's last review
+	Verdict *ReviewVerdict `json:"verdict,omitempty"`
+
+	// Plan produced by the plan phase of a plan-first job, and whether it
+	// was approved for execution
+	Plan         string `json:"plan,omitempty"`
+	PlanApproved bool   `json:"plan_approved,omitempty"`
+
+	// Every status change, oldest first; the status is replayed from it on load
+	History []Transition `json:"history,omitempty"`
+
+	onTransition func(Transition) // Set by the store the job belongs to
+	mu           sync.RWMutex
+}
+
+// ChecklistResult is a reviewer's verdict on one review checklist item.
+type ChecklistResult struct {
+	Item   string `json:"item"`
+	Passed bool   `json:"passed"`
+	Note   string `json:"note,omitempty"`
+}
+
+// ReviewVerdict is what a reviewer concluded about a job's changes.
+type ReviewVerdict struct {
+	Decision string   `json:"decision"` // approved or rejected
+	Summary  string   `json:"summary,omitempty"`
+	MustFix  []string `json:"must_fix,omitempty"`
+}
+
+// Snapshot actions for uncommitted changes left in a job worktree.
+const (
+	SnapshotCommitted = "committed" // Changes were committed automatically
+	SnapshotFailed    = "failed"    // The job was failed instead
+)
+
+// WorktreeSnapshot records uncommitted changes left in a job's worktree when
+// its worker finished, and what was done with them.
+type WorktreeSnapshot struct {
+	Action string   `json:"action"`           // SnapshotCommitted or SnapshotFailed
+	Commit string   `json:"commit,omitempty"` // Commit created for the changes
+	Files  []string `json:"files"`
 }
 
 // New creates a new job.
@@ -93,6 +216,98 @@ func (j *Job) SetDependencies(deps []string) {
 	j.DependsOn = deps
 }
 
+// SetLabels sets the job labels.
+func (j *Job) SetLabels(labels map[string]string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Labels = labels
+}
+
+// SetBody sets the full job specification.
+func (j *Job) SetBody(body string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Body = body
+}
+
+// GetBody returns the full job specification, if any.
+func (j *Job) GetBody() string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.Body
+}
+
+// SetSnapshot records what was done with uncommitted worktree changes.
+func (j *Job) SetSnapshot(s *WorktreeSnapshot) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Snapshot = s
+}
+
+// GetSnapshot returns the worktree snapshot, or nil if the worktree was clean.
+func (j *Job) GetSnapshot() *WorktreeSnapshot {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.Snapshot
+}
+
+// SetModel sets the model override for this job.
+func (j *Job) SetModel(model string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Model = model
+}
+
+// GetModel returns the model override for this job.
+func (j *Job) GetModel() string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.Model
+}
+
+// SetModelUsed records the model the job ran on.
+func (j *Job) SetModelUsed(model string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.ModelUsed = model
+}
+
+// GetModelUsed returns the model the job last ran on, or "" if unknown.
+func (j *Job) GetModelUsed() string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.ModelUsed
+}
+
+// SetSkipReview sets whether auto-review is skipped for this job.
+func (j *Job) SetSkipReview(skip bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.SkipReview = skip
+}
+
+// ShouldSkipReview returns true if auto-review is skipped for this job.
+func (j *Job) ShouldSkipReview() bool {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.SkipReview
+}
+
+// SetNoMerge sets whether the job's branch is kept instead of merged when
+// the job completes.
+func (j *Job) SetNoMerge(noMerge bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.NoMerge = noMerge
+}
+
+// ShouldSkipMerge returns true if the job's branch is kept instead of merged.
+func (j *Job) ShouldSkipMerge() bool {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.NoMerge
+}
+
 // SetRevisionOf sets the ID of the job this is a revision of.
 func (j *Job) SetRevisionOf(jobID string) {
 	j.mu.Lock()
@@ -100,6 +315,126 @@ func (j *Job) SetRevisionOf(jobID string) {
 	j.RevisionOf = jobID
 }
 
+// SetRevisionRound sets which revision of the original job this is.
+func (j *Job) SetRevisionRound(round int) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.RevisionRound = round
+}
+
+// GetRevisionRound returns which revision of the original job this is, 0
+// for a job that is not a revision.
+func (j *Job) GetRevisionRound() int {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.RevisionRound
+}
+
+// SetTimeout sets how long a run of the job may take.
+func (j *Job) SetTimeout(d time.Duration) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Timeout = d
+}
+
+// GetTimeout returns how long a run of the job may take, or 0 if the job
+// uses the default.
+func (j *Job) GetTimeout() time.Duration {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.Timeout
+}
+
+// Deadline returns when the running job times out: its start plus its
+// timeout, or fallback if it has none. It returns the zero time if the job
+// is not running or has no timeout.
+func (j *Job) Deadline(fallback time.Duration) time.Time {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	timeout := j.Timeout
+	if timeout <= 0 {
+		timeout = fallback
+	}
+	if j.Status != StatusRunning || j.StartedAt == nil || timeout <= 0 {
+		return time.Time{}
+	}
+	return j.StartedAt.Add(timeout)
+}
+
+// RecordTimeout counts a run stopped for timing out and returns how many
+// runs have timed out.
+func (j *Job) RecordTimeout() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Timeouts++
+	return j.Timeouts
+}
+
+// GetTimeouts returns how many runs of the job have timed out.
+func (j *Job) GetTimeouts() int {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.Timeouts
+}
+
+// SetPreferredWorker sets the worker the scheduler holds this job for.
+func (j *Job) SetPreferredWorker(workerID string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.PreferredWorker = workerID
+}
+
+// GetPreferredWorker returns the worker the scheduler holds this job for.
+func (j *Job) GetPreferredWorker() string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.PreferredWorker
+}
+
+// SetStickyWorker pins the job to the named worker, or unpins it when name
+// is empty.
+func (j *Job) SetStickyWorker(name string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.StickyWorker = name
+}
+
+// GetStickyWorker returns the name of the worker the job is pinned to.
+func (j *Job) GetStickyWorker() string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.StickyWorker
+}
+
+// SetHandoff sets the notes handed over with the job.
+func (j *Job) SetHandoff(notes string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Handoff = notes
+}
+
+// GetHandoff returns the notes handed over with the job.
+func (j *Job) GetHandoff() string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.Handoff
+}
+
+// SetRelevantFiles sets the files found relevant to the job.
+func (j *Job) SetRelevantFiles(files []string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.RelevantFiles = files
+}
+
+// GetRelevantFiles returns the files found relevant to the job.
+func (j *Job) GetRelevantFiles() []string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.RelevantFiles
+}
+
 // SetReviewFeedback sets the review feedback for this job.
 func (j *Job) SetReviewFeedback(feedback []string) {
 	j.mu.Lock()
@@ -107,6 +442,75 @@ func (j *Job) SetReviewFeedback(feedback []string) {
 	j.ReviewFeedback = feedback
 }
 
+// SetReviewChecklist sets the items the reviewer must check.
+func (j *Job) SetReviewChecklist(items []string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.ReviewChecklist = append([]string(nil), items...)
+}
+
+// GetReviewChecklist returns the items the reviewer must check.
+func (j *Job) GetReviewChecklist() []string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return append([]string(nil), j.ReviewChecklist...)
+}
+
+// SetChecklistResults records the reviewer's verdict on each checklist item.
+func (j *Job) SetChecklistResults(results []ChecklistResult) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.ChecklistResults = results
+}
+
+// GetChecklistResults returns the reviewer's verdict on each checklist item.
+func (j *Job) GetChecklistResults() []ChecklistResult {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return append([]ChecklistResult(nil), j.ChecklistResults...)
+}
+
+// SetVerdict records the verdict of the job's review.
+func (j *Job) SetVerdict(v ReviewVerdict) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Verdict = &v
+}
+
+// GetVerdict returns the verdict of the job's last review, or nil if it
+// has not been reviewed.
+func (j *Job) GetVerdict() *ReviewVerdict {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	if j.Verdict == nil {
+		return nil
+	}
+	v := *j.Verdict
+	v.MustFix = append([]string(nil), j.Verdict.MustFix...)
+	return &v
+}
+
+// SetSummary sets what the worker reported doing.
+func (j *Job) SetSummary(summary string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Summary = summary
+}
+
+// GetSummary returns what the worker reported doing.
+func (j *Job) GetSummary() string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.Summary
+}
+
+// GetOutput returns the job's output.
+func (j *Job) GetOutput() string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.Output
+}
+
 // SetWorktree sets the worktree path and branch for this job.
 func (j *Job) SetWorktree(worktreePath, branchName string) {
 	j.mu.Lock()
@@ -137,78 +541,255 @@ func (j *Job) ClearWorktree() {
 	j.Branch = ""
 }
 
-// Queue marks the job as queued.
-func (j *Job) Queue() {
+// SetMergeCommit records the commit that merged the job's branch.
+func (j *Job) SetMergeCommit(commit string) {
 	j.mu.Lock()
 	defer j.mu.Unlock()
-	j.Status = StatusQueued
-	now := time.Now()
-	j.QueuedAt = &now
+	j.MergeCommit = commit
 }
 
-// Start marks the job as running.
-func (j *Job) Start(workerID, sessionID string) {
-	j.mu.Lock()
-	defer j.mu.Unlock()
-	j.Status = StatusRunning
-	j.Worker = workerID
-	j.SessionID = sessionID
-	now := time.Now()
-	j.StartedAt = &now
+// GetMergeCommit returns the commit that merged the job's branch, if any.
+func (j *Job) GetMergeCommit() string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.MergeCommit
 }
 
-// Complete marks the job as completed.
-func (j *Job) Complete(output string) {
+// MarkUnmerged records that the job's branch could not be merged and has
+// been kept for conflict resolution.
+func (j *Job) MarkUnmerged() {
 	j.mu.Lock()
 	defer j.mu.Unlock()
-	j.Status = StatusCompleted
-	j.Output = output
-	now := time.Now()
-	j.CompletedAt = &now
+	j.Unmerged = true
 }
 
-// Fail marks the job as failed.
-func (j *Job) Fail(err string) {
+// ClearUnmerged clears the unmerged flag after the branch has been merged.
+func (j *Job) ClearUnmerged() {
 	j.mu.Lock()
 	defer j.mu.Unlock()
-	j.Status = StatusFailed
-	j.Error = err
-	now := time.Now()
-	j.CompletedAt = &now
+	j.Unmerged = false
 }
 
-// Cancel marks the job as cancelled.
-func (j *Job) Cancel() {
+// SetPublished records the remote branch the job was pushed to and the
+// pull request opened for it, if any.
+func (j *Job) SetPublished(remoteBranch, pullRequest string) {
 	j.mu.Lock()
 	defer j.mu.Unlock()
-	j.Status = StatusCancelled
-	now := time.Now()
-	j.CompletedAt = &now
+	j.RemoteBranch = remoteBranch
+	j.PullRequest = pullRequest
 }
 
-// MarkForReview marks the job as ready for review.
-func (j *Job) MarkForReview() {
+// GetRemoteBranch returns the remote branch the job was pushed to.
+func (j *Job) GetRemoteBranch() string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.RemoteBranch
+}
+
+// GetPullRequest returns the URL of the job's pull request.
+func (j *Job) GetPullRequest() string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.PullRequest
+}
+
+// IsUnmerged returns true if the job's branch has an unresolved merge conflict.
+func (j *Job) IsUnmerged() bool {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.Unmerged
+}
+
+// SetResolvesConflict sets the ID of the job whose merge conflict this job resolves.
+func (j *Job) SetResolvesConflict(jobID string) {
 	j.mu.Lock()
 	defer j.mu.Unlock()
-	j.Status = StatusReview
+	j.ResolvesConflict = jobID
 }
 
-// Reset resets a failed or cancelled job back to pending state so it can be re-queued.
-// Returns an error if the job is not in a failed or cancelled state.
-func (j *Job) Reset() error {
+// GetResolvesConflict returns the ID of the job whose merge conflict this job resolves.
+func (j *Job) GetResolvesConflict() string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.ResolvesConflict
+}
+
+// Queue marks the job as queued.
+func (j *Job) Queue() error {
+	return j.transition(StatusQueued, "", func() {
+		now := time.Now()
+		j.QueuedAt = &now
+	})
+}
+
+// Requeue returns a queued job that no worker has started to pending.
+func (j *Job) Requeue() error {
+	return j.transition(StatusPending, "", func() {
+		j.QueuedAt = nil
+	})
+}
+
+// Start marks the job as running.
+func (j *Job) Start(workerID, sessionID string) error {
+	return j.transition(StatusRunning, "", func() {
+		j.Worker = workerID
+		j.SessionID = sessionID
+		now := time.Now()
+		j.StartedAt = &now
+	})
+}
+
+// SetSession records the session a running job continues in, as when it is
+// restarted on a fallback model. The job keeps its start time.
+func (j *Job) SetSession(sessionID string) error {
 	j.mu.Lock()
 	defer j.mu.Unlock()
-	if j.Status != StatusFailed && j.Status != StatusCancelled {
-		return fmt.Errorf("can only reset failed or cancelled jobs, current status: %s", j.Status)
+	if j.Status != StatusRunning {
+		return fmt.Errorf("job %s is %s, not running", j.ID, j.Status)
+	}
+	j.SessionID = sessionID
+	return nil
+}
+
+// Complete marks the job as completed.
+func (j *Job) Complete(output string) error {
+	return j.transition(StatusCompleted, "", func() {
+		j.Output = output
+		now := time.Now()
+		j.CompletedAt = &now
+	})
+}
+
+// Fail marks the job as failed.
+func (j *Job) Fail(err string) error {
+	return j.transition(StatusFailed, err, func() {
+		j.Error = err
+		now := time.Now()
+		j.CompletedAt = &now
+	})
+}
+
+// Cancel marks the job as cancelled.
+func (j *Job) Cancel() error {
+	return j.transition(StatusCancelled, "", func() {
+		now := time.Now()
+		j.CompletedAt = &now
+	})
+}
+
+// MarkForReview marks the job as ready for review.
+func (j *Job) MarkForReview() error {
+	return j.transition(StatusReview, "", nil)
+}
+
+// MarkNeedsAttention marks the job as waiting on a human, with the kind of
+// intervention needed and the reason.
+func (j *Job) MarkNeedsAttention(kind, reason string) error {
+	return j.transition(StatusNeedsAttention, reason, func() {
+		j.AttentionKind = kind
+		j.AttentionReason = reason
+		now := time.Now()
+		j.AttentionSince = &now
+	})
+}
+
+// GetAttentionReason returns why the job needs attention.
+func (j *Job) GetAttentionReason() string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.AttentionReason
+}
+
+// GetAttentionKind returns the kind of intervention the job needs.
+func (j *Job) GetAttentionKind() string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.AttentionKind
+}
+
+// GetAttentionSince returns when the job started waiting on a human, or the
+// zero time if it is not.
+func (j *Job) GetAttentionSince() time.Time {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	if j.AttentionSince == nil {
+		return time.Time{}
 	}
-	j.Status = StatusPending
+	return *j.AttentionSince
+}
+
+// GetStartedAt returns when the job's latest run started, or the zero time
+// if it has not started.
+func (j *Job) GetStartedAt() time.Time {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	if j.StartedAt == nil {
+		return time.Time{}
+	}
+	return *j.StartedAt
+}
+
+// GetCompletedAt returns when the job finished, or the zero time if it has
+// not.
+func (j *Job) GetCompletedAt() time.Time {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	if j.CompletedAt == nil {
+		return time.Time{}
+	}
+	return *j.CompletedAt
+}
+
+// Resolve takes a job out of needs_attention and moves it to status, which
+// must be completed or failed. For a failed job, note is kept as the error.
+func (j *Job) Resolve(status Status, note string) error {
+	if status != StatusCompleted && status != StatusFailed {
+		return fmt.Errorf("cannot resolve a job to status %s", status)
+	}
+	return j.transitionFrom([]Status{StatusNeedsAttention}, status, note, func() {
+		if status == StatusFailed {
+			j.Error = note
+		}
+		j.clearAttention()
+	})
+}
+
+// Reopen sends a job that needs attention back to pending so it can be run
+// again. A non-empty note, such as the answer to a worker's question, is
+// appended to the job's body.
+func (j *Job) Reopen(note string) error {
+	return j.transitionFrom([]Status{StatusNeedsAttention}, StatusPending, "", func() {
+		if note != "" {
+			if j.Body != "" {
+				j.Body += "\n\n"
+			}
+			j.Body += note
+		}
+		j.clearRun()
+		j.clearAttention()
+	})
+}
+
+func (j *Job) clearAttention() {
+	j.AttentionKind = ""
+	j.AttentionReason = ""
+	j.AttentionSince = nil
+}
+
+// clearRun forgets the last attempt at running the job.
+func (j *Job) clearRun() {
 	j.Error = ""
 	j.Worker = ""
 	j.SessionID = ""
 	j.QueuedAt = nil
 	j.StartedAt = nil
 	j.CompletedAt = nil
-	return nil
+}
+
+// Reset resets a failed or cancelled job back to pending state so it can be re-queued.
+// Returns an error if the job is not in a failed or cancelled state.
+func (j *Job) Reset() error {
+	return j.transitionFrom([]Status{StatusFailed, StatusCancelled}, StatusPending, "", j.clearRun)
 }
 
 // GetStatus returns the current job status.
@@ -233,9 +814,10 @@ func (j *Job) ToJSON() ([]byte, error) {
 
 // Store manages jobs with optional persistence.
 type Store struct {
-	jobs map[string]*Job
-	path string // Directory for job persistence (empty = no persistence)
-	mu   sync.RWMutex
+	jobs         map[string]*Job
+	path         string // Directory for job persistence (empty = no persistence)
+	onTransition func(Transition)
+	mu           sync.RWMutex
 }
 
 // NewStore creates a new in-memory job store (no persistence).
@@ -263,10 +845,32 @@ func NewPersistentStore(path string) (*Store, error) {
 	return s, nil
 }
 
+// SetOnTransition sets a callback for every status change of a job in the
+// store.
+func (s *Store) SetOnTransition(fn func(Transition)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onTransition = fn
+}
+
+// notifyTransition reports a job's status change to the store's callback.
+func (s *Store) notifyTransition(t Transition) {
+	s.mu.RLock()
+	fn := s.onTransition
+	s.mu.RUnlock()
+
+	if fn != nil {
+		fn(t)
+	}
+}
+
 // Add adds a job to the store.
 func (s *Store) Add(job *Job) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	job.mu.Lock()
+	job.onTransition = s.notifyTransition
+	job.mu.Unlock()
 	s.jobs[job.ID] = job
 	if s.path != "" {
 		s.saveJob(job)
@@ -391,6 +995,15 @@ func (s *Store) loadAll() error {
 			continue // Skip unparseable files
 		}
 
+		// The history is the record of what happened to the job; jobs saved
+		// before it was kept have none and keep their stored status
+		if len(job.History) > 0 {
+			if status, err := Replay(job.History); err == nil {
+				job.Status = status
+			}
+		}
+		job.onTransition = s.notifyTransition
+
 		s.jobs[job.ID] = &job
 	}
 
@@ -45,6 +45,34 @@ func TestJob_SetDependencies(t *testing.T) {
 	}
 }
 
+func TestJob_PresetOptions(t *testing.T) {
+	j := New("test")
+	j.SetLabels(map[string]string{"type": "bug"})
+	j.SetModel("haiku")
+	j.SetSkipReview(true)
+
+	if j.Labels["type"] != "bug" {
+		t.Errorf("expected label type=bug, got %v", j.Labels)
+	}
+	if j.GetModel() != "haiku" {
+		t.Errorf("expected model 'haiku', got '%s'", j.GetModel())
+	}
+	if !j.ShouldSkipReview() {
+		t.Error("expected ShouldSkipReview to be true")
+	}
+}
+
+func TestJob_NoMerge(t *testing.T) {
+	j := New("test")
+	if j.ShouldSkipMerge() {
+		t.Error("expected jobs to be merged by default")
+	}
+	j.SetNoMerge(true)
+	if !j.ShouldSkipMerge() {
+		t.Error("expected ShouldSkipMerge to be true")
+	}
+}
+
 func TestJob_SetRevisionOf(t *testing.T) {
 	j := New("test")
 	j.SetRevisionOf("original-job-id")
@@ -53,6 +81,70 @@ func TestJob_SetRevisionOf(t *testing.T) {
 	}
 }
 
+func TestJob_Revision(t *testing.T) {
+	j := New("test")
+	if j.GetRevisionRound() != 0 || j.GetPreferredWorker() != "" {
+		t.Error("expected a new job to be neither a revision nor held for a worker")
+	}
+	j.SetRevisionRound(2)
+	j.SetPreferredWorker("worker-1")
+
+	data, err := j.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+	var restored Job
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if restored.GetRevisionRound() != 2 {
+		t.Errorf("expected revision round 2, got %d", restored.GetRevisionRound())
+	}
+	if restored.GetPreferredWorker() != "worker-1" {
+		t.Errorf("expected preferred worker 'worker-1', got %q", restored.GetPreferredWorker())
+	}
+}
+
+func TestJob_Deadline(t *testing.T) {
+	j := New("test")
+	if !j.Deadline(time.Hour).IsZero() {
+		t.Error("expected no deadline before the job runs")
+	}
+
+	j.Queue()
+	j.Start("worker-1", "session-1")
+	started := *j.StartedAt
+
+	if !j.Deadline(0).IsZero() {
+		t.Error("expected no deadline without a timeout")
+	}
+	if got := j.Deadline(time.Hour); !got.Equal(started.Add(time.Hour)) {
+		t.Errorf("expected the default timeout to apply, got %v", got)
+	}
+
+	j.SetTimeout(45 * time.Minute)
+	if got := j.Deadline(time.Hour); !got.Equal(started.Add(45 * time.Minute)) {
+		t.Errorf("expected the job's timeout to win, got %v", got)
+	}
+
+	if n := j.RecordTimeout(); n != 1 {
+		t.Errorf("expected 1 timeout, got %d", n)
+	}
+	j.Fail("timed out after 45m0s")
+	if !j.Deadline(time.Hour).IsZero() {
+		t.Error("expected no deadline once the job stopped running")
+	}
+
+	data, _ := j.ToJSON()
+	var restored Job
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if restored.GetTimeout() != 45*time.Minute || restored.GetTimeouts() != 1 {
+		t.Errorf("expected timeout and count to persist, got %v and %d", restored.GetTimeout(), restored.GetTimeouts())
+	}
+}
+
 func TestJob_SetReviewFeedback(t *testing.T) {
 	j := New("test")
 	feedback := []string{"Fix the bug", "Add tests"}
@@ -62,6 +154,113 @@ func TestJob_SetReviewFeedback(t *testing.T) {
 	}
 }
 
+func TestJob_MergeConflictTracking(t *testing.T) {
+	j := New("test")
+	if j.IsUnmerged() {
+		t.Error("new job should not be unmerged")
+	}
+
+	j.MarkUnmerged()
+	if !j.IsUnmerged() {
+		t.Error("expected job to be unmerged")
+	}
+	j.ClearUnmerged()
+	if j.IsUnmerged() {
+		t.Error("expected unmerged flag to be cleared")
+	}
+
+	resolver := New("resolve")
+	resolver.SetResolvesConflict(j.ID)
+	if resolver.GetResolvesConflict() != j.ID {
+		t.Errorf("expected ResolvesConflict %q, got %q", j.ID, resolver.GetResolvesConflict())
+	}
+}
+
+func TestJob_SetPublished(t *testing.T) {
+	j := New("test")
+	j.SetPublished("origin/cosa/job/abcd1234", "https://github.com/example/repo/pull/7")
+
+	if j.GetRemoteBranch() != "origin/cosa/job/abcd1234" {
+		t.Errorf("unexpected remote branch %q", j.GetRemoteBranch())
+	}
+	if j.GetPullRequest() != "https://github.com/example/repo/pull/7" {
+		t.Errorf("unexpected pull request %q", j.GetPullRequest())
+	}
+}
+
+func TestJob_MarkNeedsAttention(t *testing.T) {
+	j := New("test")
+	j.Queue()
+	j.Start("worker", "session")
+	j.Complete("done")
+	j.MarkNeedsAttention(AttentionMergeConflict, "merge abandoned")
+
+	if j.GetStatus() != StatusNeedsAttention {
+		t.Errorf("expected status %s, got %s", StatusNeedsAttention, j.GetStatus())
+	}
+	if j.GetAttentionKind() != AttentionMergeConflict {
+		t.Errorf("expected kind %q, got %q", AttentionMergeConflict, j.GetAttentionKind())
+	}
+	if j.GetAttentionReason() != "merge abandoned" {
+		t.Errorf("expected reason %q, got %q", "merge abandoned", j.GetAttentionReason())
+	}
+	if j.GetAttentionSince().IsZero() {
+		t.Error("expected attention time to be set")
+	}
+	if j.IsTerminal() {
+		t.Error("job needing attention should not be terminal")
+	}
+}
+
+func TestJob_Resolve(t *testing.T) {
+	j := New("test")
+	if err := j.Resolve(StatusCompleted, ""); err == nil {
+		t.Error("expected error resolving a job that does not need attention")
+	}
+
+	j.Queue()
+	j.Start("worker", "session")
+	j.Complete("done")
+	j.MarkNeedsAttention(AttentionGateOverride, "tests failed")
+	if err := j.Resolve(StatusPending, ""); err == nil {
+		t.Error("expected error resolving to pending")
+	}
+	if err := j.Resolve(StatusFailed, "dismissed"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if j.GetStatus() != StatusFailed || j.Error != "dismissed" {
+		t.Errorf("expected failed with error 'dismissed', got %s %q", j.GetStatus(), j.Error)
+	}
+	if j.GetAttentionKind() != "" || j.GetAttentionReason() != "" || !j.GetAttentionSince().IsZero() {
+		t.Error("expected attention fields to be cleared")
+	}
+}
+
+func TestJob_Reopen(t *testing.T) {
+	j := New("test")
+	j.SetBody("Spec")
+	j.Queue()
+	j.Start("worker-1", "session-1")
+	j.Complete("Which database should I use?")
+	j.MarkNeedsAttention(AttentionQuestion, "Which database should I use?")
+
+	if err := j.Reopen("Answer: use Postgres"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if j.GetStatus() != StatusPending {
+		t.Errorf("expected status pending, got %s", j.GetStatus())
+	}
+	if j.GetBody() != "Spec\n\nAnswer: use Postgres" {
+		t.Errorf("unexpected body %q", j.GetBody())
+	}
+	if j.Worker != "" || j.SessionID != "" || j.CompletedAt != nil {
+		t.Error("expected execution state to be cleared")
+	}
+	if err := j.Reopen(""); err == nil {
+		t.Error("expected error reopening a pending job")
+	}
+}
+
 func TestJob_Queue(t *testing.T) {
 	j := New("test")
 	j.Queue()
@@ -77,6 +276,7 @@ func TestJob_Start(t *testing.T) {
 	j := New("test")
 	workerID := "worker-123"
 	sessionID := "session-456"
+	j.Queue()
 	j.Start(workerID, sessionID)
 
 	if j.Status != StatusRunning {
@@ -93,8 +293,25 @@ func TestJob_Start(t *testing.T) {
 	}
 }
 
+func TestJob_SetSession(t *testing.T) {
+	j := New("test")
+	if err := j.SetSession("session-1"); err == nil {
+		t.Error("expected an error setting the session of a pending job")
+	}
+
+	j.Queue()
+	j.Start("worker-123", "session-1")
+	if err := j.SetSession("session-2"); err != nil {
+		t.Fatalf("SetSession failed: %v", err)
+	}
+	if j.SessionID != "session-2" {
+		t.Errorf("expected session session-2, got %s", j.SessionID)
+	}
+}
+
 func TestJob_Complete(t *testing.T) {
 	j := New("test")
+	j.Queue()
 	j.Start("worker", "session")
 	output := "Job output here"
 	j.Complete(output)
@@ -112,6 +329,7 @@ func TestJob_Complete(t *testing.T) {
 
 func TestJob_Fail(t *testing.T) {
 	j := New("test")
+	j.Queue()
 	j.Start("worker", "session")
 	errMsg := "something went wrong"
 	j.Fail(errMsg)
@@ -142,7 +360,9 @@ func TestJob_Cancel(t *testing.T) {
 
 func TestJob_MarkForReview(t *testing.T) {
 	j := New("test")
+	j.Queue()
 	j.Start("worker", "session")
+	j.Complete("done")
 	j.MarkForReview()
 
 	if j.Status != StatusReview {
@@ -310,8 +530,11 @@ func TestStore_ListByStatus(t *testing.T) {
 
 	j1 := New("pending job")
 	j2 := New("running job")
+	j2.Queue()
 	j2.Start("worker", "session")
 	j3 := New("completed job")
+	j3.Queue()
+	j3.Start("worker", "session")
 	j3.Complete("done")
 
 	s.Add(j1)
@@ -338,10 +561,13 @@ func TestStore_ListByWorker(t *testing.T) {
 	s := NewStore()
 
 	j1 := New("job 1")
+	j1.Queue()
 	j1.Start("worker-1", "session-1")
 	j2 := New("job 2")
+	j2.Queue()
 	j2.Start("worker-1", "session-2")
 	j3 := New("job 3")
+	j3.Queue()
 	j3.Start("worker-2", "session-3")
 
 	s.Add(j1)
@@ -369,6 +595,7 @@ func TestStore_CountByStatus(t *testing.T) {
 
 	for i := 0; i < 2; i++ {
 		j := New("running")
+		j.Queue()
 		j.Start("worker", "session")
 		s.Add(j)
 	}
@@ -457,7 +684,11 @@ func TestJob_StateTransitions(t *testing.T) {
 		t.Fatal("job should be running")
 	}
 
-	// Running -> Review
+	// Running -> Completed -> Review
+	j.Complete("done")
+	if j.GetStatus() != StatusCompleted {
+		t.Fatal("job should be completed")
+	}
 	j.MarkForReview()
 	if j.GetStatus() != StatusReview {
 		t.Fatal("job should be in review")
@@ -488,3 +719,70 @@ func TestJob_TimestampProgression(t *testing.T) {
 		t.Error("CompletedAt should be after StartedAt")
 	}
 }
+
+func TestTemplate_CreateJobReviewChecklist(t *testing.T) {
+	store := NewTemplateStore()
+	tmpl, ok := store.Get("test-unit")
+	if !ok {
+		t.Fatal("expected built-in test-unit template")
+	}
+	if len(tmpl.Checklist) == 0 {
+		t.Fatal("expected test-unit template to define a review checklist")
+	}
+
+	j, err := tmpl.CreateJob(map[string]string{"target": "queue.go"})
+	if err != nil {
+		t.Fatalf("CreateJob failed: %v", err)
+	}
+
+	checklist := j.GetReviewChecklist()
+	if len(checklist) != len(tmpl.Checklist) || checklist[0] != tmpl.Checklist[0] {
+		t.Errorf("expected checklist %v, got %v", tmpl.Checklist, checklist)
+	}
+
+	// The job's checklist is a copy of the template's
+	checklist[0] = "changed"
+	if j.GetReviewChecklist()[0] == "changed" || tmpl.Checklist[0] == "changed" {
+		t.Error("expected checklist to be copied")
+	}
+
+	j.SetChecklistResults([]ChecklistResult{{Item: tmpl.Checklist[0], Passed: true}})
+	data, err := j.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+	var restored Job
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if len(restored.ReviewChecklist) != len(tmpl.Checklist) || len(restored.ChecklistResults) != 1 || !restored.ChecklistResults[0].Passed {
+		t.Errorf("expected checklist to persist, got %v / %v", restored.ReviewChecklist, restored.ChecklistResults)
+	}
+}
+
+func TestJob_Verdict(t *testing.T) {
+	j := New("Test job")
+	if j.GetVerdict() != nil {
+		t.Fatal("expected no verdict before a review")
+	}
+
+	j.SetVerdict(ReviewVerdict{Decision: "rejected", Summary: "Missing tests", MustFix: []string{"add tests"}})
+
+	v := j.GetVerdict()
+	v.MustFix[0] = "changed"
+	if j.GetVerdict().MustFix[0] != "add tests" {
+		t.Error("expected the verdict to be copied")
+	}
+
+	data, err := j.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+	var restored Job
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if got := restored.GetVerdict(); got == nil || got.Decision != "rejected" || got.Summary != "Missing tests" {
+		t.Errorf("expected the verdict to persist, got %+v", got)
+	}
+}
@@ -0,0 +1,62 @@
+package job
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseLabelSelector parses label filters such as "area=auth" or "size".
+// A filter with a value matches jobs whose label has that value; a bare
+// key matches jobs that have the label at all.
+func ParseLabelSelector(filters []string) (map[string]string, error) {
+	if len(filters) == 0 {
+		return nil, nil
+	}
+	selector := make(map[string]string, len(filters))
+	for _, f := range filters {
+		key, value, _ := strings.Cut(f, "=")
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, fmt.Errorf("invalid label filter %q (expected key or key=value)", f)
+		}
+		selector[key] = strings.TrimSpace(value)
+	}
+	return selector, nil
+}
+
+// MatchLabels reports whether labels satisfy every filter in selector, as
+// parsed by ParseLabelSelector.
+func MatchLabels(labels, selector map[string]string) bool {
+	for key, want := range selector {
+		got, ok := labels[key]
+		if !ok || (want != "" && got != want) {
+			return false
+		}
+	}
+	return true
+}
+
+// LabelAffinity counts the labels a and b share with the same value.
+func LabelAffinity(a, b map[string]string) int {
+	n := 0
+	for key, value := range a {
+		if other, ok := b[key]; ok && other == value {
+			n++
+		}
+	}
+	return n
+}
+
+// GetLabels returns a copy of the job's labels.
+func (j *Job) GetLabels() map[string]string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	if j.Labels == nil {
+		return nil
+	}
+	labels := make(map[string]string, len(j.Labels))
+	for k, v := range j.Labels {
+		labels[k] = v
+	}
+	return labels
+}
@@ -0,0 +1,70 @@
+package job
+
+import "testing"
+
+func TestParseLabelSelector(t *testing.T) {
+	selector, err := ParseLabelSelector([]string{"area=auth", "size"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(selector) != 2 || selector["area"] != "auth" || selector["size"] != "" {
+		t.Errorf("unexpected selector %v", selector)
+	}
+
+	if selector, err := ParseLabelSelector(nil); err != nil || selector != nil {
+		t.Errorf("expected no selector for no filters, got %v, %v", selector, err)
+	}
+
+	if _, err := ParseLabelSelector([]string{"=auth"}); err == nil {
+		t.Error("expected an error for a filter without a key")
+	}
+}
+
+func TestMatchLabels(t *testing.T) {
+	labels := map[string]string{"area": "auth", "size": "large"}
+
+	tests := []struct {
+		name     string
+		selector map[string]string
+		want     bool
+	}{
+		{"no selector", nil, true},
+		{"value matches", map[string]string{"area": "auth"}, true},
+		{"value differs", map[string]string{"area": "billing"}, false},
+		{"key present", map[string]string{"size": ""}, true},
+		{"key missing", map[string]string{"team": ""}, false},
+		{"all must match", map[string]string{"area": "auth", "size": "small"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchLabels(labels, tt.selector); got != tt.want {
+				t.Errorf("MatchLabels(%v) = %v, want %v", tt.selector, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLabelAffinity(t *testing.T) {
+	job := map[string]string{"area": "auth", "size": "large"}
+
+	if got := LabelAffinity(job, map[string]string{"area": "auth", "size": "small"}); got != 1 {
+		t.Errorf("expected 1 shared label, got %d", got)
+	}
+	if got := LabelAffinity(job, nil); got != 0 {
+		t.Errorf("expected no shared labels, got %d", got)
+	}
+}
+
+func TestJob_GetLabels(t *testing.T) {
+	j := New("Test job")
+	if j.GetLabels() != nil {
+		t.Error("expected no labels")
+	}
+
+	j.SetLabels(map[string]string{"area": "auth"})
+	labels := j.GetLabels()
+	labels["area"] = "changed"
+	if j.GetLabels()["area"] != "auth" {
+		t.Error("expected labels to be copied")
+	}
+}
@@ -37,6 +37,11 @@ type Operation struct {
 	CompletedJobs int `json:"completed_jobs"`
 	FailedJobs    int `json:"failed_jobs"`
 
+	// Scheduling limits
+	MaxParallel int  `json:"max_parallel,omitempty"` // Jobs running at once, 0 for no limit
+	Sequential  bool `json:"sequential,omitempty"`   // Run jobs one at a time in the order added
+	StopOnFail  bool `json:"stop_on_fail,omitempty"` // Cancel the remaining jobs when one fails
+
 	mu sync.RWMutex
 }
 
@@ -67,6 +72,59 @@ func (o *Operation) AddJobs(jobIDs []string) {
 	o.TotalJobs = len(o.Jobs)
 }
 
+// SetLimits sets how the scheduler runs the operation's jobs.
+func (o *Operation) SetLimits(maxParallel int, sequential, stopOnFail bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.MaxParallel = maxParallel
+	o.Sequential = sequential
+	o.StopOnFail = stopOnFail
+}
+
+// GetLimits returns the operation's scheduling limits.
+func (o *Operation) GetLimits() (maxParallel int, sequential, stopOnFail bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.MaxParallel, o.Sequential, o.StopOnFail
+}
+
+// CanStart reports whether the operation's limits let jobID start now.
+// statusOf returns the current status of one of the operation's jobs.
+func (o *Operation) CanStart(jobID string, statusOf func(id string) (Status, bool)) bool {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	if o.Status == OperationStatusCancelled || (o.StopOnFail && o.Status == OperationStatusFailed) {
+		return false
+	}
+
+	active := 0
+	before := true
+	for _, id := range o.Jobs {
+		if id == jobID {
+			before = false
+			continue
+		}
+		status, ok := statusOf(id)
+		if !ok {
+			continue
+		}
+		if status == StatusQueued || status == StatusRunning {
+			active++
+		}
+		// In sequential mode every earlier job must have finished
+		if o.Sequential && before && status != StatusCompleted && status != StatusFailed && status != StatusCancelled {
+			return false
+		}
+	}
+
+	limit := o.MaxParallel
+	if o.Sequential {
+		limit = 1
+	}
+	return limit <= 0 || active < limit
+}
+
 // Start marks the operation as running.
 func (o *Operation) Start() {
 	o.mu.Lock()
@@ -122,6 +180,9 @@ func (o *Operation) IncrementFailed() {
 // checkCompletion checks if all jobs are done and updates status.
 // Must be called with lock held.
 func (o *Operation) checkCompletion() {
+	if o.Status != OperationStatusPending && o.Status != OperationStatusRunning {
+		return // Already stopped or cancelled
+	}
 	if o.CompletedJobs+o.FailedJobs >= o.TotalJobs {
 		now := time.Now()
 		o.CompletedAt = &now
@@ -0,0 +1,94 @@
+package job
+
+import "testing"
+
+func TestOperation_CanStart(t *testing.T) {
+	statuses := map[string]Status{
+		"a": StatusCompleted,
+		"b": StatusRunning,
+		"c": StatusPending,
+		"d": StatusPending,
+	}
+	statusOf := func(id string) (Status, bool) {
+		s, ok := statuses[id]
+		return s, ok
+	}
+
+	tests := []struct {
+		name        string
+		maxParallel int
+		sequential  bool
+		job         string
+		want        bool
+	}{
+		{"no limits", 0, false, "c", true},
+		{"under the limit", 2, false, "c", true},
+		{"at the limit", 1, false, "c", false},
+		{"sequential waits for the running job", 0, true, "c", false},
+		{"sequential waits for earlier pending jobs", 0, true, "d", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op := NewOperation("test")
+			op.AddJobs([]string{"a", "b", "c", "d"})
+			op.SetLimits(tt.maxParallel, tt.sequential, false)
+			op.Start()
+
+			if got := op.CanStart(tt.job, statusOf); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestOperation_CanStart_SequentialAfterFinish(t *testing.T) {
+	statuses := map[string]Status{"a": StatusFailed, "b": StatusPending, "c": StatusPending}
+	statusOf := func(id string) (Status, bool) {
+		s, ok := statuses[id]
+		return s, ok
+	}
+
+	op := NewOperation("test")
+	op.AddJobs([]string{"a", "b", "c"})
+	op.SetLimits(0, true, false)
+	op.Start()
+
+	if !op.CanStart("b", statusOf) {
+		t.Error("expected the next job to start once the earlier one finished")
+	}
+	if op.CanStart("c", statusOf) {
+		t.Error("expected the job after it to wait")
+	}
+}
+
+func TestOperation_CanStart_Stopped(t *testing.T) {
+	statusOf := func(id string) (Status, bool) { return StatusPending, true }
+
+	op := NewOperation("test")
+	op.AddJobs([]string{"a", "b"})
+	op.SetLimits(0, false, true)
+	op.Start()
+	op.Fail()
+
+	if op.CanStart("b", statusOf) {
+		t.Error("expected no jobs to start after a stop-on-fail operation failed")
+	}
+
+	op.SetLimits(0, false, false)
+	if !op.CanStart("b", statusOf) {
+		t.Error("expected jobs to keep starting when the operation does not stop on failure")
+	}
+}
+
+func TestOperation_CancelledStaysCancelled(t *testing.T) {
+	op := NewOperation("test")
+	op.AddJobs([]string{"a"})
+	op.Start()
+	op.Cancel()
+
+	op.IncrementCompleted()
+	if op.GetStatus() != OperationStatusCancelled {
+		t.Errorf("expected cancelled, got %s", op.GetStatus())
+	}
+}
@@ -0,0 +1,135 @@
+package job
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// OutcomeLabel is the label that records what kind of change a completed
+// job made.
+const OutcomeLabel = "outcome"
+
+// Job outcomes.
+const (
+	OutcomeFeature  = "feature"
+	OutcomeBugfix   = "bugfix"
+	OutcomeRefactor = "refactor"
+	OutcomeDocs     = "docs"
+	OutcomeTest     = "test"
+)
+
+// Outcomes lists the job outcomes, in the order changelogs show them.
+var Outcomes = []string{OutcomeFeature, OutcomeBugfix, OutcomeRefactor, OutcomeDocs, OutcomeTest}
+
+// ValidOutcome reports whether s is one of Outcomes.
+func ValidOutcome(s string) bool {
+	for _, o := range Outcomes {
+		if o == s {
+			return true
+		}
+	}
+	return false
+}
+
+// outcomeKeywords are words in a job's brief that hint at its outcome.
+var outcomeKeywords = map[string][]string{
+	OutcomeFeature:  {"add", "adds", "implement", "support", "introduce", "new", "allow", "enable", "create", "build"},
+	OutcomeBugfix:   {"fix", "fixes", "bug", "crash", "broken", "regression", "panic", "leak", "incorrect", "wrong", "error", "fail", "failing"},
+	OutcomeRefactor: {"refactor", "rename", "cleanup", "clean", "simplify", "extract", "restructure", "reorganize", "move", "dedupe", "tidy", "split"},
+	OutcomeDocs:     {"doc", "docs", "document", "documentation", "readme", "comment", "comments", "docstring", "changelog", "typo"},
+	OutcomeTest:     {"test", "tests", "testing", "coverage", "benchmark", "flaky"},
+}
+
+var wordPattern = regexp.MustCompile(`[a-z]+`)
+
+// ClassifyOutcome guesses what kind of change a job made from its brief
+// and the files it changed. A change that only touches documentation or
+// only touches tests is classified by its files; otherwise the words of the
+// brief decide, with the first word counting most. Briefs with no hints are
+// features.
+func ClassifyOutcome(brief string, files []string) string {
+	if len(files) > 0 {
+		docs, tests := true, true
+		for _, f := range files {
+			docs = docs && isDocFile(f)
+			tests = tests && isTestFile(f)
+		}
+		switch {
+		case docs:
+			return OutcomeDocs
+		case tests:
+			return OutcomeTest
+		}
+	}
+
+	words := wordPattern.FindAllString(strings.ToLower(brief), -1)
+	scores := make(map[string]int)
+	for i, word := range words {
+		weight := 1
+		if i == 0 {
+			weight = 3
+		}
+		for outcome, keywords := range outcomeKeywords {
+			for _, k := range keywords {
+				if word == k {
+					scores[outcome] += weight
+				}
+			}
+		}
+	}
+
+	best, bestScore := OutcomeFeature, 0
+	for _, outcome := range Outcomes {
+		if scores[outcome] > bestScore {
+			best, bestScore = outcome, scores[outcome]
+		}
+	}
+	return best
+}
+
+// isDocFile reports whether f is documentation.
+func isDocFile(f string) bool {
+	switch strings.ToLower(path.Ext(f)) {
+	case ".md", ".markdown", ".rst", ".txt", ".adoc":
+		return true
+	}
+	return strings.HasPrefix(f, "docs/") || strings.Contains(f, "/docs/")
+}
+
+// isTestFile reports whether f is a test or test fixture.
+func isTestFile(f string) bool {
+	base := path.Base(f)
+	switch {
+	case strings.HasSuffix(base, "_test.go"),
+		strings.HasPrefix(base, "test_"),
+		strings.Contains(base, ".test."),
+		strings.Contains(base, ".spec."):
+		return true
+	}
+	for _, dir := range strings.Split(path.Dir(f), "/") {
+		if dir == "test" || dir == "tests" || dir == "testdata" || dir == "__tests__" {
+			return true
+		}
+	}
+	return false
+}
+
+// Outcome returns the job's outcome label, if it has been classified.
+func (j *Job) Outcome() string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.Labels[OutcomeLabel]
+}
+
+// SetOutcome records the job's outcome label.
+func (j *Job) SetOutcome(outcome string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	labels := make(map[string]string, len(j.Labels)+1)
+	for k, v := range j.Labels {
+		labels[k] = v
+	}
+	labels[OutcomeLabel] = outcome
+	j.Labels = labels
+}
@@ -0,0 +1,57 @@
+package job
+
+import "testing"
+
+func TestClassifyOutcome(t *testing.T) {
+	tests := []struct {
+		brief string
+		files []string
+		want  string
+	}{
+		{"Add a retry button to the dashboard", []string{"web/app.go"}, OutcomeFeature},
+		{"Fix the crash when the config is empty", []string{"config.go", "config_test.go"}, OutcomeBugfix},
+		{"Refactor the scheduler into its own package", []string{"scheduler/scheduler.go"}, OutcomeRefactor},
+		{"Explain the merge flow", []string{"README.md", "docs/merge.md"}, OutcomeDocs},
+		{"Cover the queue", []string{"queue_test.go", "testdata/jobs.json"}, OutcomeTest},
+		{"Document the new API and fix a typo", nil, OutcomeDocs},
+		{"Improve things", []string{"main.go"}, OutcomeFeature},
+		// The first word outweighs later hints
+		{"Fix adding a new worker", []string{"pool.go"}, OutcomeBugfix},
+	}
+
+	for _, tt := range tests {
+		if got := ClassifyOutcome(tt.brief, tt.files); got != tt.want {
+			t.Errorf("ClassifyOutcome(%q, %v) = %s, want %s", tt.brief, tt.files, got, tt.want)
+		}
+	}
+}
+
+func TestValidOutcome(t *testing.T) {
+	if !ValidOutcome(OutcomeBugfix) {
+		t.Error("expected bugfix to be valid")
+	}
+	if ValidOutcome("chore") {
+		t.Error("expected chore to be invalid")
+	}
+}
+
+func TestJobOutcome(t *testing.T) {
+	j := New("Fix the queue")
+	labels := map[string]string{"area": "queue"}
+	j.SetLabels(labels)
+
+	if got := j.Outcome(); got != "" {
+		t.Errorf("expected no outcome before classification, got %q", got)
+	}
+
+	j.SetOutcome(OutcomeBugfix)
+	if got := j.Outcome(); got != OutcomeBugfix {
+		t.Errorf("expected bugfix, got %q", got)
+	}
+	if j.Labels["area"] != "queue" {
+		t.Error("expected other labels to be kept")
+	}
+	if _, ok := labels[OutcomeLabel]; ok {
+		t.Error("expected the caller's label map to be left alone")
+	}
+}
@@ -0,0 +1,60 @@
+package job
+
+import "fmt"
+
+// SetPlanFirst sets whether the job runs a plan phase, and waits for the
+// plan to be approved, before it is executed.
+func (j *Job) SetPlanFirst(planFirst bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.PlanFirst = planFirst
+}
+
+// IsPlanFirst returns whether the job runs a plan phase first.
+func (j *Job) IsPlanFirst() bool {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.PlanFirst
+}
+
+// NeedsPlan returns whether the job's next run is its plan phase: it is
+// plan-first and no plan has been approved yet.
+func (j *Job) NeedsPlan() bool {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.PlanFirst && !j.PlanApproved
+}
+
+// SetPlan records the plan produced by the plan phase.
+func (j *Job) SetPlan(plan string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Plan = plan
+}
+
+// GetPlan returns the plan produced by the plan phase, if any.
+func (j *Job) GetPlan() string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.Plan
+}
+
+// IsPlanApproved returns whether the job's plan was approved.
+func (j *Job) IsPlanApproved() bool {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.PlanApproved
+}
+
+// ApprovePlan approves the plan of a job waiting for plan approval and
+// sends it back to pending, so its next run executes the plan.
+func (j *Job) ApprovePlan() error {
+	if kind := j.GetAttentionKind(); kind != AttentionPlanApproval {
+		return fmt.Errorf("job %s has no plan waiting for approval", j.ID)
+	}
+	return j.transitionFrom([]Status{StatusNeedsAttention}, StatusPending, "plan approved", func() {
+		j.PlanApproved = true
+		j.clearRun()
+		j.clearAttention()
+	})
+}
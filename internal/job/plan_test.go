@@ -0,0 +1,37 @@
+package job
+
+import "testing"
+
+func TestJob_ApprovePlan(t *testing.T) {
+	j := New("test")
+	j.SetPlanFirst(true)
+	if !j.NeedsPlan() {
+		t.Fatal("expected a plan-first job to need a plan")
+	}
+
+	j.Queue()
+	j.Start("worker-1", "session-1")
+	j.Complete("1. Edit main.go")
+	j.SetPlan("1. Edit main.go")
+
+	if err := j.ApprovePlan(); err == nil {
+		t.Error("expected error approving a job not waiting for approval")
+	}
+
+	j.MarkNeedsAttention(AttentionPlanApproval, "plan ready for approval")
+	if err := j.ApprovePlan(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if j.GetStatus() != StatusPending {
+		t.Errorf("expected status pending, got %s", j.GetStatus())
+	}
+	if j.NeedsPlan() || !j.IsPlanApproved() {
+		t.Error("expected the plan to be approved")
+	}
+	if j.GetPlan() != "1. Edit main.go" {
+		t.Errorf("expected the plan to be kept, got %q", j.GetPlan())
+	}
+	if j.Worker != "" || j.GetAttentionKind() != "" {
+		t.Error("expected run and attention state to be cleared")
+	}
+}
@@ -2,6 +2,7 @@ package job
 
 import (
 	"container/heap"
+	"sort"
 	"sync"
 )
 
@@ -85,6 +86,16 @@ func (q *Queue) GetPending() []*Job {
 	return jobs
 }
 
+// InOrder returns the jobs in the queue in the order they will start: ready
+// jobs by priority, then those blocked on dependencies.
+func (q *Queue) InOrder() []*Job {
+	ready := q.GetReady()
+	sort.Slice(ready, jobHeap(ready).Less)
+	pending := q.GetPending()
+	sort.Slice(pending, jobHeap(pending).Less)
+	return append(ready, pending...)
+}
+
 // NotifyCompletion is called when a job completes successfully.
 // It checks pending jobs to see if any can now be moved to ready.
 func (q *Queue) NotifyCompletion(jobID string) {
@@ -74,6 +74,8 @@ func TestQueue_Enqueue_WithMetDependencies(t *testing.T) {
 
 	// Create and complete dependency
 	dep := New("dependency")
+	dep.Queue()
+	dep.Start("worker", "session")
 	dep.Complete("done")
 	store.Add(dep)
 
@@ -290,6 +292,8 @@ func TestQueue_NotifyCompletion(t *testing.T) {
 	}
 
 	// Complete the dependency
+	dep.Queue()
+	dep.Start("worker", "session")
 	dep.Complete("done")
 
 	// Notify queue
@@ -472,6 +476,8 @@ func TestQueue_MultipleDependencies(t *testing.T) {
 	}
 
 	// Complete first dep
+	dep1.Queue()
+	dep1.Start("worker", "session")
 	dep1.Complete("done")
 	q.NotifyCompletion(dep1.ID)
 
@@ -481,6 +487,8 @@ func TestQueue_MultipleDependencies(t *testing.T) {
 	}
 
 	// Complete second dep
+	dep2.Queue()
+	dep2.Start("worker", "session")
 	dep2.Complete("done")
 	q.NotifyCompletion(dep2.ID)
 
@@ -0,0 +1,113 @@
+package job
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// QueueState is whether the scheduler hands out queued jobs.
+type QueueState string
+
+const (
+	QueueRunning  QueueState = "running"  // Jobs are assigned as workers free up
+	QueuePaused   QueueState = "paused"   // No new assignments; running jobs finish
+	QueueDraining QueueState = "draining" // Paused, and new jobs are refused
+)
+
+// QueueHold records the queue's state, when it was set, by whom and why.
+type QueueHold struct {
+	State  QueueState `json:"state"`
+	Since  time.Time  `json:"since"`
+	By     string     `json:"by,omitempty"`
+	Reason string     `json:"reason,omitempty"`
+}
+
+// QueueControl holds the queue's state, which outlives the daemon so that a
+// queue paused before a restart stays paused after it.
+type QueueControl struct {
+	mu   sync.RWMutex
+	hold QueueHold
+	path string // JSON file; empty keeps the state in memory
+}
+
+// NewQueueControl creates an in-memory control with the queue running.
+func NewQueueControl() *QueueControl {
+	return &QueueControl{hold: QueueHold{State: QueueRunning, Since: time.Now()}}
+}
+
+// LoadQueueControl loads the queue state stored at path, or starts with the
+// queue running and saves there on the first change.
+func LoadQueueControl(path string) (*QueueControl, error) {
+	c := NewQueueControl()
+	c.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read queue state: %w", err)
+	}
+
+	var hold QueueHold
+	if err := json.Unmarshal(data, &hold); err != nil {
+		return nil, fmt.Errorf("failed to parse queue state: %w", err)
+	}
+	switch hold.State {
+	case QueueRunning, QueuePaused, QueueDraining:
+		c.hold = hold
+	default:
+		return nil, fmt.Errorf("invalid queue state: %q", hold.State)
+	}
+	return c, nil
+}
+
+// Get returns the queue's current state.
+func (c *QueueControl) Get() QueueHold {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.hold
+}
+
+// State returns whether the queue is running, paused or draining.
+func (c *QueueControl) State() QueueState {
+	return c.Get().State
+}
+
+// Set moves the queue to state and returns the state it was in. Setting the
+// state the queue is already in changes nothing.
+func (c *QueueControl) Set(state QueueState, by, reason string) (QueueHold, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prev := c.hold
+	if prev.State == state {
+		return prev, nil
+	}
+
+	c.hold = QueueHold{State: state, Since: time.Now(), By: by, Reason: reason}
+	if err := c.saveLocked(); err != nil {
+		c.hold = prev
+		return prev, err
+	}
+	return prev, nil
+}
+
+// saveLocked writes the state to disk. Must be called with the lock held.
+func (c *QueueControl) saveLocked() error {
+	if c.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(c.hold, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to save queue state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package job
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestQueueControl(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.json")
+
+	c, err := LoadQueueControl(path)
+	if err != nil {
+		t.Fatalf("LoadQueueControl() error: %v", err)
+	}
+	if c.State() != QueueRunning {
+		t.Errorf("expected a new queue to be running, got %s", c.State())
+	}
+
+	prev, err := c.Set(QueuePaused, "tony", "deploy freeze")
+	if err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+	if prev.State != QueueRunning {
+		t.Errorf("expected the previous state to be running, got %s", prev.State)
+	}
+
+	// Setting the same state again keeps who set it first
+	c.Set(QueuePaused, "paulie", "")
+	if hold := c.Get(); hold.By != "tony" || hold.Reason != "deploy freeze" {
+		t.Errorf("expected the first pause to stand, got %+v", hold)
+	}
+
+	reloaded, err := LoadQueueControl(path)
+	if err != nil {
+		t.Fatalf("LoadQueueControl() error: %v", err)
+	}
+	if hold := reloaded.Get(); hold.State != QueuePaused || hold.By != "tony" {
+		t.Errorf("expected the pause to survive a reload, got %+v", hold)
+	}
+
+	c.Set(QueueDraining, "tony", "")
+	c.Set(QueueRunning, "tony", "")
+	if c.State() != QueueRunning {
+		t.Errorf("expected the queue to be running again, got %s", c.State())
+	}
+}
+
+func TestLoadQueueControl_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.json")
+	os.WriteFile(path, []byte(`{"state": "sideways"}`), 0644)
+
+	if _, err := LoadQueueControl(path); err == nil {
+		t.Error("expected an error for an unknown state")
+	}
+}
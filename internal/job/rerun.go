@@ -0,0 +1,56 @@
+package job
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Rerun returns a fresh pending job that runs j again with the same
+// parameters: its description and body, priority, labels, model, review,
+// merge and timeout options, and the template it came from with the
+// variables it was expanded with. The new job records j as the job it
+// reruns.
+//
+// Each of j's dependencies is passed to remap, which returns the job the
+// rerun should wait on instead, or false to drop the dependency. The
+// rerun does not join j's operation, and its worker, output and history
+// start empty.
+func (j *Job) Rerun(remap func(dep string) (string, bool)) (*Job, error) {
+	if !j.IsTerminal() {
+		return nil, fmt.Errorf("job %s is %s; only finished jobs can be rerun", j.ID, j.GetStatus())
+	}
+
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	rerun := New(j.Description)
+	rerun.Body = j.Body
+	rerun.Priority = j.Priority
+	rerun.Labels = maps.Clone(j.Labels)
+	rerun.Model = j.Model
+	rerun.SkipReview = j.SkipReview
+	rerun.NoMerge = j.NoMerge
+	rerun.Timeout = j.Timeout
+	rerun.PlanFirst = j.PlanFirst
+	rerun.StickyWorker = j.StickyWorker
+	rerun.Template = j.Template
+	rerun.TemplateVars = maps.Clone(j.TemplateVars)
+	rerun.ReviewChecklist = slices.Clone(j.ReviewChecklist)
+	rerun.RerunOf = j.ID
+
+	for _, dep := range j.DependsOn {
+		if remapped, ok := remap(dep); ok && !slices.Contains(rerun.DependsOn, remapped) {
+			rerun.DependsOn = append(rerun.DependsOn, remapped)
+		}
+	}
+
+	return rerun, nil
+}
+
+// GetRerunOf returns the ID of the job this job reruns, if any.
+func (j *Job) GetRerunOf() string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.RerunOf
+}
@@ -0,0 +1,70 @@
+package job
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestJob_Rerun(t *testing.T) {
+	tmpl := &Template{
+		ID:        "fix",
+		Prompt:    "Fix {{issue}}",
+		Priority:  PriorityHigh,
+		Variables: []TemplateVar{{Name: "issue", Required: true}},
+	}
+	j, err := tmpl.CreateJob(map[string]string{"issue": "the login bug"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	j.SetLabels(map[string]string{"area": "auth"})
+	j.SetModel("opus")
+	j.SetTimeout(10 * time.Minute)
+	j.SetDependencies([]string{"done", "rerun-me", "gone", "also-rerun-me"})
+	j.Operation = "op-1"
+
+	if _, err := j.Rerun(nil); err == nil {
+		t.Error("expected error rerunning an unfinished job")
+	}
+
+	j.Queue()
+	j.Start("worker-1", "session-1")
+	j.Fail("tests failed")
+
+	rerun, err := j.Rerun(func(dep string) (string, bool) {
+		switch dep {
+		case "rerun-me", "also-rerun-me":
+			return "rerun-1", true
+		case "gone":
+			return "", false
+		default:
+			return dep, true
+		}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if rerun.ID == j.ID || rerun.GetRerunOf() != j.ID {
+		t.Errorf("expected a new job linked to %s, got %s linked to %q", j.ID, rerun.ID, rerun.GetRerunOf())
+	}
+	if rerun.GetStatus() != StatusPending || rerun.Worker != "" || rerun.Error != "" || rerun.Operation != "" {
+		t.Errorf("expected a fresh pending job, got %s on %q (error %q, operation %q)", rerun.GetStatus(), rerun.Worker, rerun.Error, rerun.Operation)
+	}
+	if rerun.Description != "Fix the login bug" || rerun.Priority != PriorityHigh || rerun.GetModel() != "opus" || rerun.GetTimeout() != 10*time.Minute {
+		t.Errorf("expected the original's parameters, got %q, priority %d, model %q, timeout %s", rerun.Description, rerun.Priority, rerun.GetModel(), rerun.GetTimeout())
+	}
+	if rerun.Template != "fix" || rerun.TemplateVars["issue"] != "the login bug" {
+		t.Errorf("expected template fix with its variables, got %q %v", rerun.Template, rerun.TemplateVars)
+	}
+	if !slices.Equal(rerun.DependsOn, []string{"done", "rerun-1"}) {
+		t.Errorf("expected remapped dependencies [done rerun-1], got %v", rerun.DependsOn)
+	}
+
+	// The rerun has its own copies
+	rerun.Labels["area"] = "billing"
+	rerun.TemplateVars["issue"] = "another bug"
+	if j.Labels["area"] != "auth" || j.TemplateVars["issue"] != "the login bug" {
+		t.Error("expected changing the rerun to leave the original alone")
+	}
+}
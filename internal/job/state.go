@@ -0,0 +1,139 @@
+package job
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrIllegalTransition is returned, wrapped in a *TransitionError, when a
+// job is moved to a status it cannot reach from its current one.
+var ErrIllegalTransition = errors.New("illegal job status transition")
+
+// transitions lists the statuses each status may move to. Every status
+// change goes through this table.
+var transitions = map[Status][]Status{
+	StatusPending:   {StatusQueued, StatusFailed, StatusCancelled, StatusNeedsAttention, StatusDraft}, // Failed when a dependency fails
+	StatusQueued:    {StatusRunning, StatusPending, StatusFailed, StatusCancelled},
+	StatusRunning:   {StatusCompleted, StatusFailed, StatusCancelled},
+	StatusCompleted: {StatusReview, StatusNeedsAttention},
+	StatusReview:    {StatusCompleted, StatusFailed, StatusCancelled, StatusNeedsAttention},
+	StatusFailed:    {StatusPending},
+	StatusCancelled: {StatusPending},
+
+	// A draft waits for someone to approve it before it can be queued
+	StatusDraft: {StatusPending, StatusCancelled},
+
+	// A job already waiting on a human can be flagged again with a new reason
+	StatusNeedsAttention: {StatusCompleted, StatusFailed, StatusPending, StatusCancelled, StatusNeedsAttention},
+}
+
+// TransitionError reports an illegal status change.
+type TransitionError struct {
+	JobID string
+	From  Status
+	To    Status
+}
+
+func (e *TransitionError) Error() string {
+	id := e.JobID
+	if len(id) > 8 {
+		id = id[:8]
+	}
+	return fmt.Sprintf("job %s cannot move from %s to %s", id, e.From, e.To)
+}
+
+// Unwrap lets errors.Is match ErrIllegalTransition.
+func (e *TransitionError) Unwrap() error {
+	return ErrIllegalTransition
+}
+
+// Transition records a single status change of a job.
+type Transition struct {
+	JobID  string    `json:"job_id"`
+	From   Status    `json:"from"`
+	To     Status    `json:"to"`
+	Reason string    `json:"reason,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+// CanTransition reports whether a job may move from one status to another.
+func CanTransition(from, to Status) bool {
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Replay folds a job's transition history into the status it leads to,
+// checking that each transition starts where the last one ended and is
+// legal.
+func Replay(history []Transition) (Status, error) {
+	if len(history) == 0 {
+		return StatusPending, nil
+	}
+	status := history[0].From
+	for _, t := range history {
+		if t.From != status || !CanTransition(t.From, t.To) {
+			return status, &TransitionError{JobID: t.JobID, From: status, To: t.To}
+		}
+		status = t.To
+	}
+	return status, nil
+}
+
+// transition moves the job to status to, running apply under the job's
+// lock to update the fields that go with the new status. The change is
+// recorded in the job's history and reported to its store.
+func (j *Job) transition(to Status, reason string, apply func()) error {
+	return j.transitionFrom(nil, to, reason, apply)
+}
+
+// transitionFrom is like transition, but also requires the job to be in one
+// of the from statuses if any are given.
+func (j *Job) transitionFrom(from []Status, to Status, reason string, apply func()) error {
+	j.mu.Lock()
+	if !CanTransition(j.Status, to) || (len(from) > 0 && !containsStatus(from, j.Status)) {
+		err := &TransitionError{JobID: j.ID, From: j.Status, To: to}
+		j.mu.Unlock()
+		return err
+	}
+
+	t := Transition{
+		JobID:  j.ID,
+		From:   j.Status,
+		To:     to,
+		Reason: reason,
+		Time:   time.Now(),
+	}
+	j.Status = to
+	j.History = append(j.History, t)
+	if apply != nil {
+		apply()
+	}
+	onTransition := j.onTransition
+	j.mu.Unlock()
+
+	if onTransition != nil {
+		onTransition(t)
+	}
+	return nil
+}
+
+// GetHistory returns the job's status changes, oldest first.
+func (j *Job) GetHistory() []Transition {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return append([]Transition(nil), j.History...)
+}
+
+func containsStatus(statuses []Status, status Status) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,311 @@
+package job
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+var allStatuses = []Status{
+	StatusPending,
+	StatusQueued,
+	StatusRunning,
+	StatusCompleted,
+	StatusFailed,
+	StatusCancelled,
+	StatusReview,
+	StatusNeedsAttention,
+}
+
+func TestCanTransition(t *testing.T) {
+	legal := map[Status]map[Status]bool{
+		StatusPending:        {StatusQueued: true, StatusFailed: true, StatusCancelled: true, StatusNeedsAttention: true},
+		StatusQueued:         {StatusRunning: true, StatusPending: true, StatusFailed: true, StatusCancelled: true},
+		StatusRunning:        {StatusCompleted: true, StatusFailed: true, StatusCancelled: true},
+		StatusCompleted:      {StatusReview: true, StatusNeedsAttention: true},
+		StatusReview:         {StatusCompleted: true, StatusFailed: true, StatusCancelled: true, StatusNeedsAttention: true},
+		StatusFailed:         {StatusPending: true},
+		StatusCancelled:      {StatusPending: true},
+		StatusNeedsAttention: {StatusCompleted: true, StatusFailed: true, StatusPending: true, StatusCancelled: true, StatusNeedsAttention: true},
+	}
+
+	for _, from := range allStatuses {
+		for _, to := range allStatuses {
+			if got, want := CanTransition(from, to), legal[from][to]; got != want {
+				t.Errorf("CanTransition(%s, %s) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+}
+
+func TestTransition_IllegalReturnsTypedError(t *testing.T) {
+	j := New("test")
+
+	err := j.Start("worker", "session")
+	if err == nil {
+		t.Fatal("expected error starting a pending job")
+	}
+	if !errors.Is(err, ErrIllegalTransition) {
+		t.Errorf("expected ErrIllegalTransition, got %v", err)
+	}
+
+	var te *TransitionError
+	if !errors.As(err, &te) {
+		t.Fatalf("expected *TransitionError, got %T", err)
+	}
+	if te.JobID != j.ID || te.From != StatusPending || te.To != StatusRunning {
+		t.Errorf("unexpected transition error %+v", te)
+	}
+
+	// A rejected transition leaves the job untouched
+	if j.GetStatus() != StatusPending || j.Worker != "" || j.StartedAt != nil {
+		t.Error("expected job to be unchanged after an illegal transition")
+	}
+	if len(j.GetHistory()) != 0 {
+		t.Errorf("expected no history, got %d entries", len(j.GetHistory()))
+	}
+}
+
+func TestTransition_TerminalJobsStayPut(t *testing.T) {
+	j := New("test")
+	j.Queue()
+	j.Start("worker", "session")
+	if err := j.Complete("done"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := j.Fail("late failure"); !errors.Is(err, ErrIllegalTransition) {
+		t.Errorf("expected ErrIllegalTransition failing a completed job, got %v", err)
+	}
+	if err := j.Cancel(); !errors.Is(err, ErrIllegalTransition) {
+		t.Errorf("expected ErrIllegalTransition cancelling a completed job, got %v", err)
+	}
+	if j.GetStatus() != StatusCompleted || j.Error != "" {
+		t.Errorf("expected completed job to be unchanged, got %s %q", j.GetStatus(), j.Error)
+	}
+}
+
+func TestTransition_RecordsHistory(t *testing.T) {
+	j := New("test")
+	j.Queue()
+	j.Start("worker", "session")
+	j.Fail("boom")
+	if err := j.Reset(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	history := j.GetHistory()
+	want := []struct {
+		from, to Status
+		reason   string
+	}{
+		{StatusPending, StatusQueued, ""},
+		{StatusQueued, StatusRunning, ""},
+		{StatusRunning, StatusFailed, "boom"},
+		{StatusFailed, StatusPending, ""},
+	}
+	if len(history) != len(want) {
+		t.Fatalf("expected %d transitions, got %d", len(want), len(history))
+	}
+	for i, w := range want {
+		h := history[i]
+		if h.JobID != j.ID || h.From != w.from || h.To != w.to || h.Reason != w.reason {
+			t.Errorf("transition %d: expected %s -> %s (%q), got %s -> %s (%q)",
+				i, w.from, w.to, w.reason, h.From, h.To, h.Reason)
+		}
+		if h.Time.IsZero() {
+			t.Errorf("transition %d: expected a time", i)
+		}
+	}
+
+	status, err := Replay(history)
+	if err != nil {
+		t.Fatalf("unexpected replay error: %v", err)
+	}
+	if status != j.GetStatus() {
+		t.Errorf("expected replay to reach %s, got %s", j.GetStatus(), status)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	if status, err := Replay(nil); err != nil || status != StatusPending {
+		t.Errorf("expected empty history to replay to pending, got %s %v", status, err)
+	}
+
+	gap := []Transition{
+		{From: StatusPending, To: StatusQueued},
+		{From: StatusRunning, To: StatusCompleted},
+	}
+	if _, err := Replay(gap); !errors.Is(err, ErrIllegalTransition) {
+		t.Errorf("expected ErrIllegalTransition for a gap in history, got %v", err)
+	}
+
+	illegal := []Transition{
+		{From: StatusPending, To: StatusQueued},
+		{From: StatusQueued, To: StatusCompleted},
+	}
+	status, err := Replay(illegal)
+	if !errors.Is(err, ErrIllegalTransition) {
+		t.Errorf("expected ErrIllegalTransition for an illegal step, got %v", err)
+	}
+	if status != StatusQueued {
+		t.Errorf("expected replay to stop at %s, got %s", StatusQueued, status)
+	}
+}
+
+func TestStore_OnTransition(t *testing.T) {
+	s := NewStore()
+
+	var got []Transition
+	s.SetOnTransition(func(tr Transition) { got = append(got, tr) })
+
+	j := New("test")
+	s.Add(j)
+	j.Queue()
+	j.Start("worker", "session")
+	j.Queue() // Illegal, not reported
+	j.Complete("done")
+
+	if len(got) != 3 {
+		t.Fatalf("expected 3 transitions, got %d", len(got))
+	}
+	if got[2].From != StatusRunning || got[2].To != StatusCompleted {
+		t.Errorf("unexpected last transition %s -> %s", got[2].From, got[2].To)
+	}
+}
+
+func TestStore_PersistsHistory(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewPersistentStore(dir)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	j := New("test")
+	s.Add(j)
+	j.Queue()
+	j.Start("worker", "session")
+	j.Complete("done")
+	s.Save(j)
+
+	loaded, err := NewPersistentStore(dir)
+	if err != nil {
+		t.Fatalf("failed to reload store: %v", err)
+	}
+	got, ok := loaded.Get(j.ID)
+	if !ok {
+		t.Fatal("expected job to be loaded")
+	}
+	if got.GetStatus() != StatusCompleted {
+		t.Errorf("expected status %s, got %s", StatusCompleted, got.GetStatus())
+	}
+	if len(got.GetHistory()) != 3 {
+		t.Errorf("expected 3 transitions, got %d", len(got.GetHistory()))
+	}
+
+	// Reloaded jobs keep reporting to their store
+	var reported int
+	loaded.SetOnTransition(func(Transition) { reported++ })
+	got.MarkForReview()
+	if reported != 1 {
+		t.Errorf("expected 1 transition reported, got %d", reported)
+	}
+}
+
+func TestTransition_ConcurrentAttempts(t *testing.T) {
+	for round := 0; round < 50; round++ {
+		s := NewStore()
+		var mu sync.Mutex
+		reported := 0
+		s.SetOnTransition(func(Transition) {
+			mu.Lock()
+			reported++
+			mu.Unlock()
+		})
+
+		j := New("race")
+		s.Add(j)
+		j.Queue()
+		j.Start("worker", "session")
+
+		mu.Lock()
+		reported = 0
+		mu.Unlock()
+
+		const attempts = 30
+		var wg sync.WaitGroup
+		var succeeded sync.Map
+		for i := 0; i < attempts; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				var err error
+				switch i % 3 {
+				case 0:
+					err = j.Complete("done")
+				case 1:
+					err = j.Fail("failed")
+				default:
+					err = j.Cancel()
+				}
+				if err == nil {
+					succeeded.Store(i, true)
+				} else if !errors.Is(err, ErrIllegalTransition) {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		wins := 0
+		succeeded.Range(func(any, any) bool { wins++; return true })
+		if wins != 1 {
+			t.Fatalf("round %d: expected exactly 1 transition to succeed, got %d", round, wins)
+		}
+
+		history := j.GetHistory()
+		if len(history) != 3 {
+			t.Fatalf("round %d: expected 3 transitions, got %d", round, len(history))
+		}
+		if last := history[2]; last.From != StatusRunning || last.To != j.GetStatus() {
+			t.Errorf("round %d: last transition %s -> %s does not match status %s",
+				round, last.From, last.To, j.GetStatus())
+		}
+		if reported != 1 {
+			t.Errorf("round %d: expected 1 transition reported, got %d", round, reported)
+		}
+	}
+}
+
+func TestTransition_ConcurrentAcrossJobs(t *testing.T) {
+	s := NewStore()
+	var mu sync.Mutex
+	reported := 0
+	s.SetOnTransition(func(Transition) {
+		mu.Lock()
+		reported++
+		mu.Unlock()
+	})
+
+	const jobs = 50
+	var wg sync.WaitGroup
+	for i := 0; i < jobs; i++ {
+		j := New("job")
+		s.Add(j)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			j.Queue()
+			j.Start("worker", "session")
+			j.Complete("done")
+		}()
+	}
+	wg.Wait()
+
+	if s.CountByStatus(StatusCompleted) != jobs {
+		t.Errorf("expected %d completed jobs, got %d", jobs, s.CountByStatus(StatusCompleted))
+	}
+	if reported != jobs*3 {
+		t.Errorf("expected %d transitions reported, got %d", jobs*3, reported)
+	}
+}
@@ -4,8 +4,11 @@ package job
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"sync"
 )
@@ -31,6 +34,7 @@ type Template struct {
 	Priority    int               `json:"priority"`
 	Variables   []TemplateVar     `json:"variables,omitempty"`
 	Tags        []string          `json:"tags,omitempty"`
+	Checklist   []string          `json:"checklist,omitempty"` // Items the reviewer must check
 	BuiltIn     bool              `json:"built_in"`
 }
 
@@ -42,20 +46,133 @@ type TemplateVar struct {
 	Default     string `json:"default,omitempty"`
 }
 
+var (
+	// templateIDPattern limits template IDs to safe file names.
+	templateIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+	templateVarPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	placeholderPattern = regexp.MustCompile(`\{\{([A-Za-z_][A-Za-z0-9_]*)\}\}`)
+)
+
+// Validate checks a custom template before it is stored: the ID must be a
+// safe file name and every placeholder in the prompt must be a declared
+// variable. An empty name, type or priority is filled in.
+func (t *Template) Validate() error {
+	if !templateIDPattern.MatchString(t.ID) {
+		return fmt.Errorf("invalid template id %q: use up to 64 lowercase letters, digits, - and _", t.ID)
+	}
+	if strings.TrimSpace(t.Prompt) == "" {
+		return fmt.Errorf("template %s has no prompt", t.ID)
+	}
+
+	if t.Name == "" {
+		t.Name = t.ID
+	}
+	if t.Type == "" {
+		t.Type = TemplateTypeCustom
+	}
+	switch t.Type {
+	case TemplateTypeRefactor, TemplateTypeTest, TemplateTypeDocument, TemplateTypeReview, TemplateTypeCustom:
+	default:
+		return fmt.Errorf("invalid template type %q (use refactor/test/document/review/custom)", t.Type)
+	}
+	if t.Priority == 0 {
+		t.Priority = PriorityNormal
+	}
+	if t.Priority < PriorityLow || t.Priority > PriorityCritical {
+		return fmt.Errorf("invalid priority %d (must be 1-5)", t.Priority)
+	}
+
+	declared := make(map[string]bool, len(t.Variables))
+	for _, v := range t.Variables {
+		if !templateVarPattern.MatchString(v.Name) {
+			return fmt.Errorf("invalid variable name %q", v.Name)
+		}
+		if declared[v.Name] {
+			return fmt.Errorf("variable %q is declared twice", v.Name)
+		}
+		declared[v.Name] = true
+	}
+	for _, name := range t.Placeholders() {
+		if !declared[name] {
+			return fmt.Errorf("prompt uses undeclared variable %q", name)
+		}
+	}
+
+	return nil
+}
+
+// Placeholders returns the variable names used in the prompt, in order of
+// first use.
+func (t *Template) Placeholders() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, m := range placeholderPattern.FindAllStringSubmatch(t.Prompt, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
+
+// CheckVariables reports every required variable missing from vars, and
+// any variable in vars the template does not declare.
+func (t *Template) CheckVariables(vars map[string]string) error {
+	declared := make(map[string]bool, len(t.Variables))
+	var missing []string
+	for _, v := range t.Variables {
+		declared[v.Name] = true
+		if vars[v.Name] == "" && v.Required && v.Default == "" {
+			if v.Description != "" {
+				missing = append(missing, fmt.Sprintf("%s (%s)", v.Name, v.Description))
+			} else {
+				missing = append(missing, v.Name)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required variables: %s", strings.Join(missing, ", "))
+	}
+
+	var unknown []string
+	for name := range vars {
+		if !declared[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		names := make([]string, len(t.Variables))
+		for i, v := range t.Variables {
+			names[i] = v.Name
+		}
+		return fmt.Errorf("unknown variables: %s (template %s takes: %s)",
+			strings.Join(unknown, ", "), t.ID, valueOrNone(strings.Join(names, ", ")))
+	}
+
+	return nil
+}
+
+func valueOrNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
 // Expand fills in template variables and returns the expanded prompt.
 func (t *Template) Expand(vars map[string]string) (string, error) {
 	prompt := t.Prompt
 
-	// Check required variables
+	if err := t.CheckVariables(vars); err != nil {
+		return "", err
+	}
+
 	for _, v := range t.Variables {
-		val, ok := vars[v.Name]
-		if !ok || val == "" {
-			if v.Required && v.Default == "" {
-				return "", fmt.Errorf("required variable %q not provided", v.Name)
-			}
-			if v.Default != "" {
-				val = v.Default
-			}
+		val := vars[v.Name]
+		if val == "" {
+			val = v.Default
 		}
 		// Replace {{var}} with value
 		placeholder := "{{" + v.Name + "}}"
@@ -73,7 +190,10 @@ func (t *Template) CreateJob(vars map[string]string) (*Job, error) {
 	}
 
 	job := New(description)
+	job.Template = t.ID
+	job.TemplateVars = maps.Clone(vars)
 	job.SetPriority(t.Priority)
+	job.SetReviewChecklist(t.Checklist)
 	return job, nil
 }
 
@@ -270,6 +390,11 @@ var builtInTemplates = []*Template{
 		Priority:    PriorityNormal,
 		BuiltIn:     true,
 		Tags:        []string{"refactor", "code-quality"},
+		Checklist: []string{
+			"Behavior and public APIs are unchanged",
+			"Dead code was removed rather than commented out",
+			"Names are clearer than before",
+		},
 		Variables: []TemplateVar{
 			{Name: "file", Description: "Path to the file to refactor", Required: true},
 			{Name: "focus", Description: "What to focus on (e.g., readability, performance)", Default: "readability and maintainability"},
@@ -294,6 +419,11 @@ Make commits as you go. When finished, summarize what you changed.`,
 		Priority:    PriorityNormal,
 		BuiltIn:     true,
 		Tags:        []string{"refactor", "architecture"},
+		Checklist: []string{
+			"Public APIs are stable or their changes are intended",
+			"Coupling between components did not increase",
+			"Tests were updated for changed interfaces",
+		},
 		Variables: []TemplateVar{
 			{Name: "module", Description: "Module/package path to refactor", Required: true},
 			{Name: "goal", Description: "Primary goal of the refactor", Default: "improve maintainability"},
@@ -318,6 +448,10 @@ Make commits as you go. When finished, summarize what you changed.`,
 		Priority:    PriorityNormal,
 		BuiltIn:     true,
 		Tags:        []string{"refactor", "function"},
+		Checklist: []string{
+			"The function signature and behavior are preserved",
+			"Extracted helpers have a single clear purpose",
+		},
 		Variables: []TemplateVar{
 			{Name: "function", Description: "Function name or signature", Required: true},
 			{Name: "file", Description: "File containing the function", Required: true},
@@ -344,6 +478,12 @@ Make commits as you go. When finished, summarize what you changed.`,
 		Priority:    PriorityNormal,
 		BuiltIn:     true,
 		Tags:        []string{"test", "unit"},
+		Checklist: []string{
+			"Tests are deterministic",
+			"Edge cases are covered",
+			"Error paths are tested",
+			"Tests follow existing test patterns",
+		},
 		Variables: []TemplateVar{
 			{Name: "target", Description: "File or function to test", Required: true},
 			{Name: "coverage", Description: "Coverage goal or focus areas", Default: "core functionality and edge cases"},
@@ -368,6 +508,11 @@ Make commits as you go. When finished, summarize what tests you added and what t
 		Priority:    PriorityNormal,
 		BuiltIn:     true,
 		Tags:        []string{"test", "integration"},
+		Checklist: []string{
+			"Fixtures are set up and torn down",
+			"Tests are independent and idempotent",
+			"Error handling and recovery are tested",
+		},
 		Variables: []TemplateVar{
 			{Name: "feature", Description: "Feature or component to test", Required: true},
 			{Name: "scope", Description: "Scope of integration (e.g., API, database)", Default: "full feature flow"},
@@ -391,6 +536,11 @@ Make commits as you go. When finished, summarize what tests you added and what i
 		Priority:    PriorityHigh,
 		BuiltIn:     true,
 		Tags:        []string{"test", "fix", "debug"},
+		Checklist: []string{
+			"The root cause was fixed, not the symptom",
+			"No tests were skipped or disabled",
+			"All related tests pass",
+		},
 		Variables: []TemplateVar{
 			{Name: "test", Description: "Test name or file with failures", Required: true},
 			{Name: "context", Description: "Additional context about the failures", Default: ""},
@@ -417,6 +567,10 @@ Make commits as you go. When finished, summarize what you found and how you fixe
 		Priority:    PriorityNormal,
 		BuiltIn:     true,
 		Tags:        []string{"documentation", "file"},
+		Checklist: []string{
+			"Documentation matches the code",
+			"Public functions and types are documented",
+		},
 		Variables: []TemplateVar{
 			{Name: "file", Description: "File to document", Required: true},
 			{Name: "style", Description: "Documentation style to use", Default: "existing project conventions"},
@@ -441,6 +595,10 @@ Make commits as you go. When finished, summarize what documentation you added.`,
 		Priority:    PriorityNormal,
 		BuiltIn:     true,
 		Tags:        []string{"documentation", "api"},
+		Checklist: []string{
+			"Every endpoint is documented",
+			"Request and response formats include examples",
+		},
 		Variables: []TemplateVar{
 			{Name: "api", Description: "API endpoint or interface to document", Required: true},
 			{Name: "format", Description: "Documentation format", Default: "inline code comments"},
@@ -465,6 +623,10 @@ Make commits as you go. When finished, summarize what documentation you added.`,
 		Priority:    PriorityNormal,
 		BuiltIn:     true,
 		Tags:        []string{"documentation", "architecture"},
+		Checklist: []string{
+			"Component relationships and data flow are explained",
+			"Referenced code locations exist",
+		},
 		Variables: []TemplateVar{
 			{Name: "component", Description: "Component or system to document", Required: true},
 			{Name: "audience", Description: "Target audience", Default: "developers new to the codebase"},
@@ -0,0 +1,96 @@
+package job
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTemplate_Validate(t *testing.T) {
+	tmpl := &Template{
+		ID:        "add-endpoint",
+		Prompt:    "Add the {{method}} endpoint {{path}}",
+		Variables: []TemplateVar{{Name: "method", Default: "GET"}, {Name: "path", Required: true}},
+	}
+	if err := tmpl.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tmpl.Name != "add-endpoint" || tmpl.Type != TemplateTypeCustom || tmpl.Priority != PriorityNormal {
+		t.Errorf("expected defaults to be filled in, got name=%q type=%q priority=%d", tmpl.Name, tmpl.Type, tmpl.Priority)
+	}
+
+	invalid := []*Template{
+		{ID: "../escape", Prompt: "x"},
+		{ID: "no-prompt"},
+		{ID: "bad-type", Prompt: "x", Type: "chore"},
+		{ID: "bad-priority", Prompt: "x", Priority: 9},
+		{ID: "undeclared", Prompt: "Fix {{file}}"},
+		{ID: "twice", Prompt: "x", Variables: []TemplateVar{{Name: "a"}, {Name: "a"}}},
+	}
+	for _, tmpl := range invalid {
+		if err := tmpl.Validate(); err == nil {
+			t.Errorf("expected %s to be invalid", tmpl.ID)
+		}
+	}
+
+	for _, tmpl := range NewTemplateStore().ListBuiltIn() {
+		if err := tmpl.Validate(); err != nil {
+			t.Errorf("built-in template %s is invalid: %v", tmpl.ID, err)
+		}
+	}
+}
+
+func TestTemplate_Placeholders(t *testing.T) {
+	tmpl := &Template{Prompt: "Move {{file}} to {{dir}}, then update imports of {{file}}"}
+	got := tmpl.Placeholders()
+	if strings.Join(got, ",") != "file,dir" {
+		t.Errorf("expected [file dir], got %v", got)
+	}
+}
+
+func TestTemplate_CheckVariables(t *testing.T) {
+	tmpl := &Template{
+		ID: "refactor",
+		Variables: []TemplateVar{
+			{Name: "file", Description: "File to refactor", Required: true},
+			{Name: "function", Required: true},
+			{Name: "focus", Required: true, Default: "readability"},
+		},
+	}
+
+	err := tmpl.CheckVariables(map[string]string{})
+	if err == nil || !strings.Contains(err.Error(), "file (File to refactor), function") {
+		t.Errorf("expected every missing variable to be reported, got %v", err)
+	}
+
+	err = tmpl.CheckVariables(map[string]string{"file": "a.go", "function": "f", "fcous": "speed"})
+	if err == nil || !strings.Contains(err.Error(), "unknown variables: fcous") {
+		t.Errorf("expected the unknown variable to be reported, got %v", err)
+	}
+
+	if err := tmpl.CheckVariables(map[string]string{"file": "a.go", "function": "f"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestTemplate_CreateJobFromBuiltIn(t *testing.T) {
+	store := NewTemplateStore()
+	tmpl, ok := store.Get("refactor-file")
+	if !ok {
+		t.Fatal("expected built-in template refactor-file")
+	}
+
+	if _, err := tmpl.CreateJob(nil); err == nil {
+		t.Error("expected an error without the required file variable")
+	}
+
+	j, err := tmpl.CreateJob(map[string]string{"file": "main.go"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(j.Description, "main.go with a focus on readability and maintainability") {
+		t.Errorf("expected variables and defaults to be expanded, got %q", j.Description)
+	}
+	if j.Template != "refactor-file" {
+		t.Errorf("expected the job to record its template, got %q", j.Template)
+	}
+}
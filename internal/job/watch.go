@@ -0,0 +1,124 @@
+package job
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+// WatchList records the jobs each client identity has pinned. Watched jobs
+// are shown first in that client's TUI and notify on every status change.
+type WatchList struct {
+	mu      sync.RWMutex
+	watches map[string][]string // Job IDs by identity, in the order watched
+	path    string              // JSON file; empty keeps the list in memory
+}
+
+// NewWatchList creates an empty in-memory watch list.
+func NewWatchList() *WatchList {
+	return &WatchList{watches: make(map[string][]string)}
+}
+
+// LoadWatchList loads the watch list stored at path, or starts an empty one
+// that is saved there on the first change.
+func LoadWatchList(path string) (*WatchList, error) {
+	w := NewWatchList()
+	w.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return w, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watch list: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &w.watches); err != nil {
+		return nil, fmt.Errorf("failed to parse watch list: %w", err)
+	}
+	if w.watches == nil {
+		w.watches = make(map[string][]string)
+	}
+	return w, nil
+}
+
+// saveLocked writes the watch list to disk. Must be called with the lock held.
+func (w *WatchList) saveLocked() error {
+	if w.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(w.watches, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(w.path, data, 0644)
+}
+
+// Watch pins jobID for identity. It reports whether the job was newly
+// watched.
+func (w *WatchList) Watch(identity, jobID string) (bool, error) {
+	if identity == "" {
+		return false, fmt.Errorf("identity is required")
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, id := range w.watches[identity] {
+		if id == jobID {
+			return false, nil
+		}
+	}
+	w.watches[identity] = append(w.watches[identity], jobID)
+	return true, w.saveLocked()
+}
+
+// Unwatch unpins jobID for identity. It reports whether the job was being
+// watched.
+func (w *WatchList) Unwatch(identity, jobID string) (bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ids := w.watches[identity]
+	for i, id := range ids {
+		if id != jobID {
+			continue
+		}
+		ids = append(ids[:i:i], ids[i+1:]...)
+		if len(ids) == 0 {
+			delete(w.watches, identity)
+		} else {
+			w.watches[identity] = ids
+		}
+		return true, w.saveLocked()
+	}
+	return false, nil
+}
+
+// Watched returns the job IDs identity watches, in the order watched.
+func (w *WatchList) Watched(identity string) []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return append([]string(nil), w.watches[identity]...)
+}
+
+// Watchers returns the identities watching jobID, sorted.
+func (w *WatchList) Watchers(jobID string) []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	var watchers []string
+	for identity, ids := range w.watches {
+		for _, id := range ids {
+			if id == jobID {
+				watchers = append(watchers, identity)
+				break
+			}
+		}
+	}
+	sort.Strings(watchers)
+	return watchers
+}
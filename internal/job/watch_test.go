@@ -0,0 +1,68 @@
+package job
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWatchList(t *testing.T) {
+	w := NewWatchList()
+
+	if added, err := w.Watch("tony", "job-1"); err != nil || !added {
+		t.Fatalf("expected job-1 to be newly watched, got %v, %v", added, err)
+	}
+	if added, _ := w.Watch("tony", "job-1"); added {
+		t.Error("expected watching twice to be a no-op")
+	}
+	w.Watch("tony", "job-2")
+	w.Watch("carmela", "job-1")
+
+	if _, err := w.Watch("", "job-1"); err == nil {
+		t.Error("expected an error without an identity")
+	}
+
+	if got := w.Watched("tony"); !reflect.DeepEqual(got, []string{"job-1", "job-2"}) {
+		t.Errorf("expected tony's jobs in watch order, got %v", got)
+	}
+	if got := w.Watchers("job-1"); !reflect.DeepEqual(got, []string{"carmela", "tony"}) {
+		t.Errorf("expected both watchers, got %v", got)
+	}
+	if got := w.Watched("paulie"); len(got) != 0 {
+		t.Errorf("expected nothing for an unknown identity, got %v", got)
+	}
+
+	if removed, _ := w.Unwatch("tony", "job-1"); !removed {
+		t.Error("expected job-1 to be unwatched")
+	}
+	if removed, _ := w.Unwatch("tony", "job-1"); removed {
+		t.Error("expected unwatching twice to be a no-op")
+	}
+	if got := w.Watchers("job-1"); !reflect.DeepEqual(got, []string{"carmela"}) {
+		t.Errorf("expected only carmela to watch job-1, got %v", got)
+	}
+}
+
+func TestWatchList_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watches.json")
+
+	w, err := LoadWatchList(path)
+	if err != nil {
+		t.Fatalf("failed to load empty watch list: %v", err)
+	}
+	w.Watch("tony", "job-1")
+	w.Watch("tony", "job-2")
+	w.Watch("carmela", "job-3")
+	w.Unwatch("carmela", "job-3")
+
+	reloaded, err := LoadWatchList(path)
+	if err != nil {
+		t.Fatalf("failed to reload watch list: %v", err)
+	}
+	if got := reloaded.Watched("tony"); !reflect.DeepEqual(got, []string{"job-1", "job-2"}) {
+		t.Errorf("expected tony's watches to persist, got %v", got)
+	}
+	if got := reloaded.Watched("carmela"); len(got) != 0 {
+		t.Errorf("expected carmela's unwatch to persist, got %v", got)
+	}
+}
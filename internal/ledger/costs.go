@@ -0,0 +1,59 @@
+package ledger
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// CostRow is what one worker spent on one job on one day.
+type CostRow struct {
+	Day     string // YYYY-MM-DD in local time
+	Worker  string // Worker name
+	JobID   string
+	Cost    float64
+	Tokens  int
+	Records int // cost.record events summed into the row
+}
+
+// CostBreakdown sums the cost.record events among events by day, worker
+// and job, ordered by day, then worker, then job.
+func CostBreakdown(events []Event) []CostRow {
+	type key struct{ day, worker, job string }
+	rows := make(map[key]*CostRow)
+
+	for _, e := range events {
+		if e.Type != EventCostRecord {
+			continue
+		}
+		var data CostEventData
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			continue
+		}
+
+		k := key{dayOf(e.Timestamp), data.WorkerName, data.JobID}
+		row, ok := rows[k]
+		if !ok {
+			row = &CostRow{Day: k.day, Worker: k.worker, JobID: k.job}
+			rows[k] = row
+		}
+		row.Cost += ParseCost(data.Cost)
+		row.Tokens += data.Tokens
+		row.Records++
+	}
+
+	result := make([]CostRow, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, *row)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		if a.Worker != b.Worker {
+			return a.Worker < b.Worker
+		}
+		return a.JobID < b.JobID
+	})
+	return result
+}
@@ -0,0 +1,54 @@
+package ledger
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestCostBreakdown(t *testing.T) {
+	day1 := time.Date(2025, 4, 1, 10, 0, 0, 0, time.Local)
+	day2 := day1.AddDate(0, 0, 1)
+	event := func(at time.Time, data CostEventData) Event {
+		raw, _ := json.Marshal(data)
+		return Event{Type: EventCostRecord, Timestamp: at, Data: raw}
+	}
+
+	events := []Event{
+		event(day2, CostEventData{JobID: "job-2", WorkerName: "vito", Cost: "$2.00", Tokens: 200}),
+		event(day1, CostEventData{JobID: "job-1", WorkerName: "vito", Cost: "$1.00", Tokens: 100}),
+		event(day1.Add(time.Hour), CostEventData{JobID: "job-1", WorkerName: "vito", Cost: "$0.50", Tokens: 50}),
+		event(day1, CostEventData{JobID: "job-3", WorkerName: "sonny", Cost: "$0.25", Tokens: 25}),
+		{Type: EventJobCompleted, Timestamp: day1},
+		{Type: EventCostRecord, Timestamp: day1, Data: json.RawMessage(`not json`)},
+	}
+
+	rows := CostBreakdown(events)
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 rows, got %d: %+v", len(rows), rows)
+	}
+
+	want := []struct {
+		day, worker, job string
+		cost             float64
+		tokens, records  int
+	}{
+		{"2025-04-01", "sonny", "job-3", 0.25, 25, 1},
+		{"2025-04-01", "vito", "job-1", 1.50, 150, 2},
+		{"2025-04-02", "vito", "job-2", 2.00, 200, 1},
+	}
+	for i, w := range want {
+		got := rows[i]
+		if got.Day != w.day || got.Worker != w.worker || got.JobID != w.job {
+			t.Errorf("row %d: expected %s/%s/%s, got %s/%s/%s", i, w.day, w.worker, w.job, got.Day, got.Worker, got.JobID)
+		}
+		if !approx(got.Cost, w.cost) || got.Tokens != w.tokens || got.Records != w.records {
+			t.Errorf("row %d: expected %.2f, %d tokens, %d records, got %.2f, %d, %d",
+				i, w.cost, w.tokens, w.records, got.Cost, got.Tokens, got.Records)
+		}
+	}
+
+	if rows := CostBreakdown(nil); len(rows) != 0 {
+		t.Errorf("expected no rows without events, got %d", len(rows))
+	}
+}
@@ -23,21 +23,23 @@ const (
 	EventTerritoryInit EventType = "territory.init"
 
 	// Worker events
-	EventWorkerAdded    EventType = "worker.added"
-	EventWorkerStarted  EventType = "worker.started"
-	EventWorkerStopped  EventType = "worker.stopped"
-	EventWorkerRemoved  EventType = "worker.removed"
-	EventWorkerError    EventType = "worker.error"
-	EventWorkerStuck    EventType = "worker.stuck"
-	EventWorkerMessage  EventType = "worker.message"
+	EventWorkerAdded      EventType = "worker.added"
+	EventWorkerStarted    EventType = "worker.started"
+	EventWorkerStopped    EventType = "worker.stopped"
+	EventWorkerRemoved    EventType = "worker.removed"
+	EventWorkerError      EventType = "worker.error"
+	EventWorkerStuck      EventType = "worker.stuck"
+	EventWorkerMessage    EventType = "worker.message"
+	EventWorkerRemediated EventType = "worker.remediated"
 
 	// Job events
-	EventJobCreated   EventType = "job.created"
-	EventJobQueued    EventType = "job.queued"
-	EventJobStarted   EventType = "job.started"
-	EventJobCompleted EventType = "job.completed"
-	EventJobFailed    EventType = "job.failed"
-	EventJobCancelled EventType = "job.cancelled"
+	EventJobCreated    EventType = "job.created"
+	EventJobQueued     EventType = "job.queued"
+	EventJobStarted    EventType = "job.started"
+	EventJobCompleted  EventType = "job.completed"
+	EventJobFailed     EventType = "job.failed"
+	EventJobCancelled  EventType = "job.cancelled"
+	EventJobTransition EventType = "job.transition"
 
 	// Claude events
 	EventClaudeMessage  EventType = "claude.message"
@@ -45,7 +47,8 @@ const (
 	EventClaudeResult   EventType = "claude.result"
 
 	// Cost events
-	EventCostRecord EventType = "cost.record"
+	EventCostRecord     EventType = "cost.record"
+	EventBudgetExceeded EventType = "budget.exceeded"
 
 	// Review events
 	EventReviewStarted  EventType = "review.started"
@@ -78,6 +81,7 @@ type Ledger struct {
 	path string
 	file *os.File
 	mu   sync.Mutex
+	seq  uint64 // Events appended since the ledger was opened
 
 	// Subscribers for real-time events
 	subs   []chan<- Event
@@ -98,6 +102,19 @@ func Open(path string) (*Ledger, error) {
 	}, nil
 }
 
+// Path returns the path of the ledger file.
+func (l *Ledger) Path() string {
+	return l.path
+}
+
+// Seq returns how many events have been appended since the ledger was
+// opened. It changes whenever an event is recorded.
+func (l *Ledger) Seq() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.seq
+}
+
 // Close closes the ledger file.
 func (l *Ledger) Close() error {
 	l.mu.Lock()
@@ -137,6 +154,7 @@ func (l *Ledger) Append(eventType EventType, data interface{}) (*Event, error) {
 		l.mu.Unlock()
 		return nil, err
 	}
+	l.seq++
 	l.mu.Unlock()
 
 	// Notify subscribers
@@ -240,11 +258,12 @@ type DaemonEventData struct {
 
 // WorkerEventData contains data for worker events.
 type WorkerEventData struct {
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	Role     string `json:"role"`
-	Worktree string `json:"worktree,omitempty"`
-	Error    string `json:"error,omitempty"`
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Role      string `json:"role"`
+	Worktree  string `json:"worktree,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Learnings string `json:"learnings,omitempty"`
 }
 
 // JobEventData contains data for job events.
@@ -254,7 +273,9 @@ type JobEventData struct {
 	Worker      string `json:"worker,omitempty"`
 	WorkerName  string `json:"worker_name,omitempty"`
 	Priority    int    `json:"priority,omitempty"`
+	Model       string `json:"model,omitempty"` // Model the job ran on
 	Error       string `json:"error,omitempty"`
+	RerunOf     string `json:"rerun_of,omitempty"` // Job a new job reruns
 }
 
 // ClaudeEventData contains data for Claude Code events.
@@ -302,6 +323,15 @@ type MergeEventData struct {
 	Error         string   `json:"error,omitempty"`
 }
 
+// BudgetEventData contains data for budget events.
+type BudgetEventData struct {
+	Scope      string  `json:"scope"` // daily, worker or job
+	WorkerName string  `json:"worker_name,omitempty"`
+	JobID      string  `json:"job_id,omitempty"`
+	Spent      float64 `json:"spent"`
+	Limit      float64 `json:"limit"`
+}
+
 // CostEventData contains data for cost tracking events.
 type CostEventData struct {
 	JobID       string `json:"job_id"`
@@ -114,6 +114,25 @@ func TestAppend_NilData(t *testing.T) {
 	}
 }
 
+func TestSeq(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.jsonl")
+
+	l, err := Open(path)
+	if err != nil {
+		t.Fatalf("failed to open ledger: %v", err)
+	}
+	defer l.Close()
+
+	if got := l.Seq(); got != 0 {
+		t.Errorf("expected seq 0 for a new ledger, got %d", got)
+	}
+	l.Append(EventDaemonStarted, nil)
+	l.Append(EventDaemonStopped, nil)
+	if got := l.Seq(); got != 2 {
+		t.Errorf("expected seq 2 after two appends, got %d", got)
+	}
+}
+
 func TestAppend_WritesToFile(t *testing.T) {
 	tmpDir := t.TempDir()
 	path := filepath.Join(tmpDir, "test.jsonl")
@@ -446,16 +465,19 @@ func TestEventTypes(t *testing.T) {
 		EventWorkerError,
 		EventWorkerStuck,
 		EventWorkerMessage,
+		EventWorkerRemediated,
 		EventJobCreated,
 		EventJobQueued,
 		EventJobStarted,
 		EventJobCompleted,
 		EventJobFailed,
 		EventJobCancelled,
+		EventJobTransition,
 		EventClaudeMessage,
 		EventClaudeToolCall,
 		EventClaudeResult,
 		EventCostRecord,
+		EventBudgetExceeded,
 		EventReviewStarted,
 		EventReviewApproved,
 		EventReviewRejected,
@@ -0,0 +1,143 @@
+package ledger
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Query selects events from the ledger. Zero fields match everything.
+type Query struct {
+	Since   time.Time      // Only events at or after this time
+	Until   time.Time      // Only events before this time
+	Types   []EventType    // Event types to include; "job.*" matches a prefix
+	Exclude []EventType    // Event types to leave out, matched like Types
+	Worker  string         // Worker name or ID the event refers to
+	Job     string         // Job ID, or a prefix of it, the event refers to
+	Grep    *regexp.Regexp // Matched against the event type and its data
+	Level   Severity       // Only events at least this severe
+	Before  string         // Only events older than the event with this ID
+	Limit   int            // Maximum events returned, newest kept; 0 for no limit
+}
+
+// Matches reports whether the event satisfies the query filters. Before and
+// Limit depend on the surrounding events and are applied by Search.
+func (q Query) Matches(e Event) bool {
+	if !q.Since.IsZero() && e.Timestamp.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !e.Timestamp.Before(q.Until) {
+		return false
+	}
+	if len(q.Types) > 0 && !matchesType(q.Types, e.Type) {
+		return false
+	}
+	if len(q.Exclude) > 0 && matchesType(q.Exclude, e.Type) {
+		return false
+	}
+	if q.Worker != "" && !refersToWorker(e, q.Worker) {
+		return false
+	}
+	if q.Job != "" && !refersToJob(e, q.Job) {
+		return false
+	}
+	if q.Level > SeverityInfo && e.Type.Severity() < q.Level {
+		return false
+	}
+	if q.Grep != nil && !q.Grep.MatchString(string(e.Type)+" "+string(e.Data)) {
+		return false
+	}
+	return true
+}
+
+// Search returns the events of the ledger at path that match q, oldest
+// first. With a limit, the newest matching events are kept and more reports
+// whether older matches were left out.
+func Search(path string, q Query) (events []Event, more bool, err error) {
+	all, err := Read(path)
+	if err != nil {
+		return nil, false, err
+	}
+	events, more = Filter(all, q)
+	return events, more, nil
+}
+
+// Filter returns the events of all, oldest first, that match q, applying
+// its cursor and limit like Search.
+func Filter(all []Event, q Query) (events []Event, more bool) {
+	// Page backwards from the cursor event if one is given
+	if q.Before != "" {
+		for i, e := range all {
+			if e.ID == q.Before {
+				all = all[:i]
+				break
+			}
+		}
+	}
+
+	for _, e := range all {
+		if q.Matches(e) {
+			events = append(events, e)
+		}
+	}
+
+	if q.Limit > 0 && len(events) > q.Limit {
+		return events[len(events)-q.Limit:], true
+	}
+	return events, false
+}
+
+func matchesType(types []EventType, t EventType) bool {
+	for _, want := range types {
+		if prefix, ok := strings.CutSuffix(string(want), "*"); ok {
+			if strings.HasPrefix(string(t), prefix) {
+				return true
+			}
+		} else if want == t {
+			return true
+		}
+	}
+	return false
+}
+
+// refersToWorker reports whether the event data names the worker, by name
+// or ID. Worker events use id/name; job and Claude events use worker and
+// worker_name.
+func refersToWorker(e Event, worker string) bool {
+	var data map[string]interface{}
+	if err := json.Unmarshal(e.Data, &data); err != nil {
+		return false
+	}
+
+	keys := []string{"worker", "worker_name"}
+	if strings.HasPrefix(string(e.Type), "worker.") {
+		keys = append(keys, "id", "name")
+	}
+	for _, key := range keys {
+		if v, ok := data[key].(string); ok && v == worker {
+			return true
+		}
+	}
+	return false
+}
+
+// refersToJob reports whether the event data names a job whose ID starts
+// with job. Job events use id; other events use job_id or job.
+func refersToJob(e Event, job string) bool {
+	var data map[string]interface{}
+	if err := json.Unmarshal(e.Data, &data); err != nil {
+		return false
+	}
+
+	keys := []string{"job_id", "job", "revision_job_id"}
+	if strings.HasPrefix(string(e.Type), "job.") {
+		keys = append(keys, "id")
+	}
+	for _, key := range keys {
+		if v, ok := data[key].(string); ok && v != "" && strings.HasPrefix(v, job) {
+			return true
+		}
+	}
+	return false
+}
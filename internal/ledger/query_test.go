@@ -0,0 +1,141 @@
+package ledger
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func writeQueryLedger(t *testing.T) (string, []*Event) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	l, err := Open(path)
+	if err != nil {
+		t.Fatalf("failed to open ledger: %v", err)
+	}
+	defer l.Close()
+
+	var events []*Event
+	appendEvent := func(eventType EventType, data interface{}) {
+		e, err := l.Append(eventType, data)
+		if err != nil {
+			t.Fatalf("failed to append: %v", err)
+		}
+		events = append(events, e)
+	}
+
+	appendEvent(EventWorkerAdded, WorkerEventData{ID: "w1", Name: "paulie"})
+	appendEvent(EventJobStarted, JobEventData{ID: "j1", Worker: "w1", WorkerName: "paulie"})
+	appendEvent(EventJobFailed, JobEventData{ID: "j1", Worker: "w1", WorkerName: "paulie", Error: "boom"})
+	appendEvent(EventJobStarted, JobEventData{ID: "j2", Worker: "w2", WorkerName: "silvio"})
+	appendEvent(EventJobCompleted, JobEventData{ID: "j2", Worker: "w2", WorkerName: "silvio"})
+
+	return path, events
+}
+
+func TestSearch_Types(t *testing.T) {
+	path, _ := writeQueryLedger(t)
+
+	events, _, err := Search(path, Query{Types: []EventType{EventJobFailed}})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if len(events) != 1 || events[0].Type != EventJobFailed {
+		t.Errorf("expected one job.failed event, got %+v", events)
+	}
+
+	events, _, _ = Search(path, Query{Types: []EventType{"job.*"}})
+	if len(events) != 4 {
+		t.Errorf("expected 4 job events, got %d", len(events))
+	}
+}
+
+func TestSearch_Worker(t *testing.T) {
+	path, _ := writeQueryLedger(t)
+
+	events, _, _ := Search(path, Query{Worker: "paulie"})
+	if len(events) != 3 {
+		t.Errorf("expected 3 events for paulie by name, got %d", len(events))
+	}
+
+	events, _, _ = Search(path, Query{Worker: "w2"})
+	if len(events) != 2 {
+		t.Errorf("expected 2 events for w2 by ID, got %d", len(events))
+	}
+}
+
+func TestSearch_Level(t *testing.T) {
+	path, _ := writeQueryLedger(t)
+
+	events, _, _ := Search(path, Query{Level: SeverityError})
+	if len(events) != 1 || events[0].Type != EventJobFailed {
+		t.Errorf("expected only the job.failed event, got %+v", events)
+	}
+
+	events, _, _ = Search(path, Query{Level: SeverityInfo})
+	if len(events) != 5 {
+		t.Errorf("expected every event at info, got %d", len(events))
+	}
+}
+
+func TestSearch_JobExcludeGrep(t *testing.T) {
+	path, _ := writeQueryLedger(t)
+
+	events, _, _ := Search(path, Query{Job: "j1"})
+	if len(events) != 2 {
+		t.Errorf("expected 2 events for job j1, got %d", len(events))
+	}
+
+	events, _, _ = Search(path, Query{Exclude: []EventType{"job.*"}})
+	if len(events) != 1 || events[0].Type != EventWorkerAdded {
+		t.Errorf("expected only the worker event, got %v", events)
+	}
+
+	events, _, _ = Search(path, Query{Grep: regexp.MustCompile(`boom|silvio`)})
+	if len(events) != 3 {
+		t.Errorf("expected 3 events matching boom or silvio, got %d", len(events))
+	}
+
+	events, _, _ = Search(path, Query{Grep: regexp.MustCompile(`^job\.completed`), Worker: "silvio"})
+	if len(events) != 1 {
+		t.Errorf("expected the type to be matched too, got %d events", len(events))
+	}
+}
+
+func TestSearch_TimeRange(t *testing.T) {
+	path, _ := writeQueryLedger(t)
+
+	events, _, _ := Search(path, Query{Since: time.Now().Add(time.Hour)})
+	if len(events) != 0 {
+		t.Errorf("expected no events in the future, got %d", len(events))
+	}
+
+	events, _, _ = Search(path, Query{Since: time.Now().Add(-time.Hour), Until: time.Now().Add(time.Hour)})
+	if len(events) != 5 {
+		t.Errorf("expected all 5 events in range, got %d", len(events))
+	}
+}
+
+func TestSearch_PagesBackwards(t *testing.T) {
+	path, all := writeQueryLedger(t)
+
+	page, more, _ := Search(path, Query{Limit: 2})
+	if !more {
+		t.Error("expected more events before the first page")
+	}
+	if len(page) != 2 || page[0].ID != all[3].ID || page[1].ID != all[4].ID {
+		t.Fatalf("expected the newest two events, got %+v", page)
+	}
+
+	page, more, _ = Search(path, Query{Limit: 2, Before: page[0].ID})
+	if !more || len(page) != 2 || page[0].ID != all[1].ID {
+		t.Fatalf("unexpected second page: %+v (more=%v)", page, more)
+	}
+
+	page, more, _ = Search(path, Query{Limit: 2, Before: page[0].ID})
+	if more || len(page) != 1 || page[0].ID != all[0].ID {
+		t.Errorf("unexpected last page: %+v (more=%v)", page, more)
+	}
+}
@@ -0,0 +1,281 @@
+package ledger
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// ReplayWorker is a worker as the ledger last described it.
+type ReplayWorker struct {
+	ID     string
+	Name   string
+	Role   string
+	Status string // idle, working or stopped
+	Job    string // ID of the job it is running
+}
+
+// ReplayJob is a job as the ledger last described it.
+type ReplayJob struct {
+	ID          string
+	Description string
+	Status      string
+	Priority    int
+	Worker      string // Name of the worker it was given to
+	Error       string
+	Reason      string // Why it last changed status
+	Cost        float64
+	CreatedAt   time.Time
+	QueuedAt    time.Time
+	StartedAt   time.Time
+	CompletedAt time.Time
+}
+
+// Replay rebuilds the state of the crew from ledger events, for looking
+// back at what it did without the daemon that did it. Events are applied
+// in order with Apply; what the ledger does not record, such as a job's
+// body or its progress, is not known.
+type Replay struct {
+	Started     time.Time // When the daemon last started
+	Clock       time.Time // Time of the latest event applied
+	Territory   string
+	TotalCost   float64
+	TotalTokens int
+	Applied     int // Events applied
+
+	workers map[string]*ReplayWorker // By ID
+	jobs    map[string]*ReplayJob
+
+	// Workers from before the daemon last started that have not started
+	// since, by ID; the ledger only names a worker when it is added
+	stale map[string]*ReplayWorker
+}
+
+// NewReplay creates a replay with no events applied.
+func NewReplay() *Replay {
+	return &Replay{
+		workers: make(map[string]*ReplayWorker),
+		jobs:    make(map[string]*ReplayJob),
+		stale:   make(map[string]*ReplayWorker),
+	}
+}
+
+// Apply updates the state with the next event. Events it does not model
+// only advance the clock.
+func (r *Replay) Apply(e Event) {
+	r.Applied++
+	r.Clock = e.Timestamp
+
+	switch e.Type {
+	case EventDaemonStarted:
+		// The workers the daemon restores start again; the rest are gone
+		r.Started = e.Timestamp
+		for id, w := range r.workers {
+			w.Status, w.Job = "stopped", ""
+			r.stale[id] = w
+		}
+		r.workers = make(map[string]*ReplayWorker)
+
+	case EventTerritoryInit:
+		var data map[string]string
+		if json.Unmarshal(e.Data, &data) == nil && data["path"] != "" {
+			r.Territory = data["path"]
+		}
+
+	case EventWorkerAdded, EventWorkerStarted, EventWorkerStopped, EventWorkerRemoved:
+		// Added and removed carry WorkerEventData; the rest are the
+		// worker's own events, which give only its ID
+		var data struct {
+			WorkerEventData
+			Worker string `json:"worker"`
+		}
+		if json.Unmarshal(e.Data, &data) != nil {
+			return
+		}
+		id := data.ID
+		if id == "" {
+			id = data.Worker
+		}
+		if id == "" {
+			return
+		}
+		if e.Type == EventWorkerRemoved {
+			delete(r.workers, id)
+			delete(r.stale, id)
+			return
+		}
+		w := r.worker(id, data.Name)
+		if data.Name != "" {
+			w.Name = data.Name
+		}
+		if data.Role != "" {
+			w.Role = data.Role
+		}
+		switch e.Type {
+		case EventWorkerStopped:
+			w.Status, w.Job = "stopped", ""
+		case EventWorkerStarted:
+			if w.Job == "" {
+				w.Status = "idle"
+			}
+		}
+
+	case EventJobCreated, EventJobQueued, EventJobStarted, EventJobCompleted, EventJobFailed, EventJobCancelled:
+		var data JobEventData
+		if json.Unmarshal(e.Data, &data) != nil || data.ID == "" {
+			return
+		}
+		j := r.job(data.ID, e.Timestamp)
+		if data.Description != "" {
+			j.Description = data.Description
+		}
+		if data.Priority > 0 {
+			j.Priority = data.Priority
+		}
+		if data.WorkerName != "" {
+			j.Worker = data.WorkerName
+		}
+		if data.Error != "" {
+			j.Error = data.Error
+		}
+		switch e.Type {
+		case EventJobCreated:
+			j.Status = "pending"
+		case EventJobQueued:
+			j.Status, j.QueuedAt = "queued", e.Timestamp
+		case EventJobStarted:
+			j.Status, j.StartedAt = "running", e.Timestamp
+			if data.Worker != "" {
+				w := r.worker(data.Worker, data.WorkerName)
+				w.Status, w.Job = "working", j.ID
+			}
+		case EventJobCompleted:
+			r.finish(j, "completed", e.Timestamp)
+		case EventJobFailed:
+			r.finish(j, "failed", e.Timestamp)
+		case EventJobCancelled:
+			r.finish(j, "cancelled", e.Timestamp)
+		}
+
+	case EventJobTransition:
+		var data struct {
+			JobID  string `json:"job_id"`
+			To     string `json:"to"`
+			Reason string `json:"reason"`
+		}
+		if json.Unmarshal(e.Data, &data) != nil || data.JobID == "" || data.To == "" {
+			return
+		}
+		j := r.job(data.JobID, e.Timestamp)
+		j.Reason = data.Reason
+		switch data.To {
+		case "running":
+			j.Status = data.To
+			if j.StartedAt.IsZero() {
+				j.StartedAt = e.Timestamp
+			}
+		case "queued":
+			j.Status, j.QueuedAt = data.To, e.Timestamp
+			r.release(j.ID)
+		case "completed", "failed", "cancelled":
+			r.finish(j, data.To, e.Timestamp)
+		default:
+			j.Status = data.To
+			r.release(j.ID)
+		}
+
+	case EventCostRecord:
+		var data CostEventData
+		if json.Unmarshal(e.Data, &data) != nil {
+			return
+		}
+		cost := ParseCost(data.Cost)
+		if j, ok := r.jobs[data.JobID]; ok {
+			j.Cost += cost
+		}
+		r.TotalCost += cost
+		r.TotalTokens += data.Tokens
+	}
+}
+
+// worker returns the worker with the given ID, bringing it back if it is
+// stale and creating it, idle, if it was added before the events applied.
+func (r *Replay) worker(id, name string) *ReplayWorker {
+	if w := r.workers[id]; w != nil {
+		return w
+	}
+	w := r.stale[id]
+	delete(r.stale, id)
+	if w == nil {
+		if name == "" {
+			name = id[:min(len(id), 8)]
+		}
+		w = &ReplayWorker{ID: id, Name: name}
+	}
+	w.Status = "idle"
+	r.workers[id] = w
+	return w
+}
+
+// job returns the job with the given ID, creating it for a job created
+// before the events applied.
+func (r *Replay) job(id string, at time.Time) *ReplayJob {
+	j := r.jobs[id]
+	if j == nil {
+		j = &ReplayJob{ID: id, Status: "pending", CreatedAt: at}
+		r.jobs[id] = j
+	}
+	return j
+}
+
+// finish ends a job and frees the worker running it.
+func (r *Replay) finish(j *ReplayJob, status string, at time.Time) {
+	j.Status = status
+	if j.CompletedAt.IsZero() {
+		j.CompletedAt = at
+	}
+	r.release(j.ID)
+}
+
+// release makes the worker running a job idle.
+func (r *Replay) release(jobID string) {
+	for _, w := range r.workers {
+		if w.Job == jobID {
+			w.Status, w.Job = "idle", ""
+		}
+	}
+}
+
+// Workers returns the workers, by name.
+func (r *Replay) Workers() []ReplayWorker {
+	workers := make([]ReplayWorker, 0, len(r.workers))
+	for _, w := range r.workers {
+		workers = append(workers, *w)
+	}
+	sort.Slice(workers, func(a, b int) bool { return workers[a].Name < workers[b].Name })
+	return workers
+}
+
+// Jobs returns the jobs, oldest first.
+func (r *Replay) Jobs() []ReplayJob {
+	jobs := make([]ReplayJob, 0, len(r.jobs))
+	for _, j := range r.jobs {
+		jobs = append(jobs, *j)
+	}
+	sort.Slice(jobs, func(a, b int) bool {
+		if !jobs[a].CreatedAt.Equal(jobs[b].CreatedAt) {
+			return jobs[a].CreatedAt.Before(jobs[b].CreatedAt)
+		}
+		return jobs[a].ID < jobs[b].ID
+	})
+	return jobs
+}
+
+// Job returns the job with the given ID.
+func (r *Replay) Job(id string) (ReplayJob, bool) {
+	j, ok := r.jobs[id]
+	if !ok {
+		return ReplayJob{}, false
+	}
+	return *j, true
+}
@@ -0,0 +1,112 @@
+package ledger
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestReplay_Apply(t *testing.T) {
+	start := time.Date(2025, 3, 1, 22, 0, 0, 0, time.UTC)
+	var events []Event
+	add := func(minutes int, eventType EventType, data any) {
+		raw, _ := json.Marshal(data)
+		events = append(events, Event{Type: eventType, Timestamp: start.Add(time.Duration(minutes) * time.Minute), Data: raw})
+	}
+
+	add(0, EventDaemonStarted, DaemonEventData{Version: "1.0.0"})
+	add(1, EventWorkerAdded, WorkerEventData{ID: "w1", Name: "vito", Role: "soldato"})
+	add(1, EventWorkerAdded, WorkerEventData{ID: "w2", Name: "sonny", Role: "soldato"})
+	add(2, EventJobCreated, JobEventData{ID: "j1", Description: "Fix the flaky test", Priority: 2})
+	add(2, EventJobQueued, JobEventData{ID: "j1", Worker: "w1", WorkerName: "vito"})
+	add(3, EventJobStarted, JobEventData{ID: "j1", Worker: "w1", WorkerName: "vito"})
+	add(4, EventJobCreated, JobEventData{ID: "j2", Description: "Add retries"})
+	add(5, EventJobStarted, JobEventData{ID: "j2", Worker: "w2", WorkerName: "sonny"})
+
+	r := NewReplay()
+	for _, e := range events {
+		r.Apply(e)
+	}
+	workers := r.Workers()
+	if len(workers) != 2 || workers[0].Name != "sonny" || workers[1].Name != "vito" {
+		t.Fatalf("expected sonny and vito, got %+v", workers)
+	}
+	if workers[1].Status != "working" || workers[1].Job != "j1" {
+		t.Errorf("expected vito to be running j1, got %+v", workers[1])
+	}
+	if j, _ := r.Job("j1"); j.Status != "running" || j.Worker != "vito" || j.Priority != 2 {
+		t.Errorf("unexpected job %+v", j)
+	}
+
+	add(30, EventCostRecord, CostEventData{JobID: "j1", WorkerName: "vito", Cost: "$0.42", Tokens: 1200})
+	add(31, EventJobTransition, map[string]string{"job_id": "j1", "from": "running", "to": "review"})
+	add(40, EventJobTransition, map[string]string{"job_id": "j1", "from": "review", "to": "completed"})
+	add(41, EventJobFailed, JobEventData{ID: "j2", Worker: "w2", Error: "tests failed"})
+	add(42, EventJobTransition, map[string]string{"job_id": "j3", "to": "needs_attention", "reason": "merge conflict"})
+	add(50, EventWorkerRemoved, WorkerEventData{ID: "w2", Name: "sonny"})
+	for _, e := range events[r.Applied:] {
+		r.Apply(e)
+	}
+
+	j1, _ := r.Job("j1")
+	if j1.Status != "completed" || j1.Cost != 0.42 || !j1.CompletedAt.Equal(start.Add(40*time.Minute)) {
+		t.Errorf("unexpected job %+v", j1)
+	}
+	if j2, _ := r.Job("j2"); j2.Status != "failed" || j2.Error != "tests failed" {
+		t.Errorf("unexpected job %+v", j2)
+	}
+	// A job from before the events applied is known by its ID
+	if j3, ok := r.Job("j3"); !ok || j3.Status != "needs_attention" || j3.Reason != "merge conflict" {
+		t.Errorf("unexpected job %+v", j3)
+	}
+	workers = r.Workers()
+	if len(workers) != 1 || workers[0].Status != "idle" || workers[0].Job != "" {
+		t.Errorf("expected only vito, idle, got %+v", workers)
+	}
+	if r.TotalCost != 0.42 || r.TotalTokens != 1200 {
+		t.Errorf("expected $0.42 and 1200 tokens, got %.2f and %d", r.TotalCost, r.TotalTokens)
+	}
+	if !r.Clock.Equal(start.Add(50*time.Minute)) || !r.Started.Equal(start) {
+		t.Errorf("unexpected clock %s, started %s", r.Clock, r.Started)
+	}
+	if ids := jobIDs(r.Jobs()); ids != "j1 j2 j3" {
+		t.Errorf("expected jobs oldest first, got %s", ids)
+	}
+}
+
+func jobIDs(jobs []ReplayJob) string {
+	var ids string
+	for i, j := range jobs {
+		if i > 0 {
+			ids += " "
+		}
+		ids += j.ID
+	}
+	return ids
+}
+
+func TestReplay_DaemonRestart(t *testing.T) {
+	start := time.Date(2025, 3, 1, 22, 0, 0, 0, time.UTC)
+	event := func(eventType EventType, data any) Event {
+		raw, _ := json.Marshal(data)
+		return Event{Type: eventType, Timestamp: start, Data: raw}
+	}
+
+	r := NewReplay()
+	r.Apply(event(EventWorkerAdded, WorkerEventData{ID: "w1", Name: "vito", Role: "soldato"}))
+	r.Apply(event(EventWorkerAdded, WorkerEventData{ID: "w2", Name: "sonny", Role: "soldato"}))
+	r.Apply(event(EventDaemonStarted, DaemonEventData{}))
+
+	// Only the workers the daemon restores come back, by the ID their own
+	// events carry
+	r.Apply(event(EventWorkerStarted, map[string]string{"type": "started", "worker": "w1"}))
+	workers := r.Workers()
+	if len(workers) != 1 || workers[0].Name != "vito" || workers[0].Status != "idle" {
+		t.Errorf("expected vito alone, idle, got %+v", workers)
+	}
+
+	r.Apply(event(EventWorkerStopped, map[string]string{"type": "stopped", "worker": "w1"}))
+	if w := r.Workers()[0]; w.Status != "stopped" {
+		t.Errorf("expected vito to be stopped, got %+v", w)
+	}
+}
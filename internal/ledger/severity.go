@@ -0,0 +1,80 @@
+package ledger
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity classifies how much an event needs attention. Events carry no
+// severity of their own; it follows from their type, so ledgers written
+// before severities existed are classified the same way.
+type Severity int
+
+const (
+	SeverityInfo  Severity = iota // Routine progress
+	SeverityWarn                  // Something went wrong but was handled or needs a look
+	SeverityError                 // Something failed
+)
+
+// String returns the severity's name.
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarn:
+		return "warn"
+	case SeverityError:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
+// ParseSeverity parses a severity name: info, warn (or warning) or error.
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "info":
+		return SeverityInfo, nil
+	case "warn", "warning":
+		return SeverityWarn, nil
+	case "error":
+		return SeverityError, nil
+	}
+	return SeverityInfo, fmt.Errorf("invalid level %q: use info, warn or error", s)
+}
+
+// warnEvents are the events that need a look without anything having
+// failed outright.
+var warnEvents = map[EventType]bool{
+	EventWorkerStuck:          true,
+	EventWorkerRemediated:     true,
+	EventReviewRejected:       true,
+	"job.rejected":            true,
+	"job.needs_attention":     true,
+	"job.merge_conflict":      true,
+	"job.merge_abandoned":     true,
+	"job.timed_out":           true,
+	"job.transition_rejected": true,
+	"email.refused":           true,
+	"ledger.repaired":         true,
+	"worker.nudged":           true,
+	"worker.model_fallback":   true,
+}
+
+// Severity returns the severity of events of type t. Failures and errors,
+// named by a "failed" or "error" suffix, are errors, as is an exceeded
+// budget.
+func (t EventType) Severity() Severity {
+	if warnEvents[t] {
+		return SeverityWarn
+	}
+	name := string(t)
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	switch {
+	case name == "failed", name == "error", strings.HasSuffix(name, "_failed"), strings.HasSuffix(name, "_error"):
+		return SeverityError
+	case t == EventBudgetExceeded:
+		return SeverityError
+	}
+	return SeverityInfo
+}
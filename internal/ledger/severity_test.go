@@ -0,0 +1,41 @@
+package ledger
+
+import "testing"
+
+func TestEventType_Severity(t *testing.T) {
+	tests := []struct {
+		eventType EventType
+		want      Severity
+	}{
+		{EventJobCompleted, SeverityInfo},
+		{EventWorkerAdded, SeverityInfo},
+		{"job.decomposed", SeverityInfo},
+		{EventWorkerStuck, SeverityWarn},
+		{EventReviewRejected, SeverityWarn},
+		{"job.merge_conflict", SeverityWarn},
+		{EventJobFailed, SeverityError},
+		{EventGateFailed, SeverityError},
+		{EventWorkerError, SeverityError},
+		{"job.push_error", SeverityError},
+		{"worker.job_failed", SeverityError},
+		{EventBudgetExceeded, SeverityError},
+	}
+
+	for _, tt := range tests {
+		if got := tt.eventType.Severity(); got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.eventType, tt.want, got)
+		}
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	for s, want := range map[string]Severity{"info": SeverityInfo, "WARN": SeverityWarn, "warning": SeverityWarn, "error": SeverityError} {
+		got, err := ParseSeverity(s)
+		if err != nil || got != want {
+			t.Errorf("ParseSeverity(%q) = %s, %v; expected %s", s, got, err, want)
+		}
+	}
+	if _, err := ParseSeverity("fatal"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
@@ -0,0 +1,137 @@
+package ledger
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SpendTracker aggregates recorded costs for budget enforcement. Daily
+// totals (overall and per worker) reset at local midnight; per-job totals
+// accumulate for the lifetime of the tracker.
+type SpendTracker struct {
+	mu      sync.Mutex
+	day     string             // Current period, YYYY-MM-DD in local time
+	total   float64            // Spend today
+	workers map[string]float64 // Spend today by worker name
+	jobs    map[string]float64 // Spend by job ID
+
+	now func() time.Time
+}
+
+// NewSpendTracker creates an empty spend tracker.
+func NewSpendTracker() *SpendTracker {
+	return &SpendTracker{
+		workers: make(map[string]float64),
+		jobs:    make(map[string]float64),
+		now:     time.Now,
+	}
+}
+
+// Load replays cost.record events, e.g. from Read, so that spend survives a
+// daemon restart. Only events from today count toward daily totals.
+func (t *SpendTracker) Load(events []Event) {
+	for _, e := range events {
+		if e.Type != EventCostRecord {
+			continue
+		}
+		var data CostEventData
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			continue
+		}
+		t.record(data.JobID, data.WorkerName, ParseCost(data.Cost), e.Timestamp)
+	}
+}
+
+// Record adds a cost incurred now by a worker on a job. Either ID may be
+// empty.
+func (t *SpendTracker) Record(jobID, workerName string, cost float64) {
+	t.record(jobID, workerName, cost, t.now())
+}
+
+func (t *SpendTracker) record(jobID, workerName string, cost float64, at time.Time) {
+	if cost <= 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if jobID != "" {
+		t.jobs[jobID] += cost
+	}
+
+	t.rollover()
+	if dayOf(at) != t.day {
+		return // Not today's spend
+	}
+	t.total += cost
+	if workerName != "" {
+		t.workers[workerName] += cost
+	}
+}
+
+// Today returns the total spend since local midnight.
+func (t *SpendTracker) Today() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rollover()
+	return t.total
+}
+
+// WorkerToday returns a worker's spend since local midnight.
+func (t *SpendTracker) WorkerToday(workerName string) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rollover()
+	return t.workers[workerName]
+}
+
+// Job returns the total spend recorded for a job.
+func (t *SpendTracker) Job(jobID string) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.jobs[jobID]
+}
+
+// AverageJob returns the mean spend of the jobs with recorded costs, or
+// zero if there are none.
+func (t *SpendTracker) AverageJob() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.jobs) == 0 {
+		return 0
+	}
+	var total float64
+	for _, cost := range t.jobs {
+		total += cost
+	}
+	return total / float64(len(t.jobs))
+}
+
+// rollover resets daily totals when the day changes. Caller must hold mu.
+func (t *SpendTracker) rollover() {
+	today := dayOf(t.now())
+	if today == t.day {
+		return
+	}
+	t.day = today
+	t.total = 0
+	t.workers = make(map[string]float64)
+}
+
+func dayOf(at time.Time) string {
+	return at.Local().Format("2006-01-02")
+}
+
+// ParseCost parses a cost string like "$1.23" to dollars. Invalid or empty
+// values parse as zero.
+func ParseCost(cost string) float64 {
+	value, err := strconv.ParseFloat(strings.TrimPrefix(strings.TrimSpace(cost), "$"), 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
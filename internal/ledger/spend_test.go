@@ -0,0 +1,98 @@
+package ledger
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSpendTracker_Record(t *testing.T) {
+	tracker := NewSpendTracker()
+
+	tracker.Record("job-1", "vito", 1.50)
+	tracker.Record("job-1", "vito", 0.25)
+	tracker.Record("job-2", "sonny", 2.00)
+	tracker.Record("", "", 0.10)
+
+	if got := tracker.Today(); !approx(got, 3.85) {
+		t.Errorf("expected today 3.85, got %.2f", got)
+	}
+	if got := tracker.WorkerToday("vito"); !approx(got, 1.75) {
+		t.Errorf("expected vito 1.75, got %.2f", got)
+	}
+	if got := tracker.Job("job-2"); !approx(got, 2.00) {
+		t.Errorf("expected job-2 2.00, got %.2f", got)
+	}
+	if got := tracker.Job("missing"); got != 0 {
+		t.Errorf("expected 0 for unknown job, got %.2f", got)
+	}
+	if got := tracker.AverageJob(); !approx(got, 1.875) {
+		t.Errorf("expected average job 1.875, got %.3f", got)
+	}
+	if got := NewSpendTracker().AverageJob(); got != 0 {
+		t.Errorf("expected average 0 with no jobs, got %.2f", got)
+	}
+}
+
+func TestSpendTracker_DailyRollover(t *testing.T) {
+	tracker := NewSpendTracker()
+	now := time.Date(2025, 3, 1, 23, 0, 0, 0, time.Local)
+	tracker.now = func() time.Time { return now }
+
+	tracker.Record("job-1", "vito", 4.00)
+
+	now = now.Add(2 * time.Hour) // Next day
+	if got := tracker.Today(); got != 0 {
+		t.Errorf("expected daily total to reset, got %.2f", got)
+	}
+	if got := tracker.WorkerToday("vito"); got != 0 {
+		t.Errorf("expected worker total to reset, got %.2f", got)
+	}
+	if got := tracker.Job("job-1"); !approx(got, 4.00) {
+		t.Errorf("expected job total to persist across days, got %.2f", got)
+	}
+}
+
+func TestSpendTracker_Load(t *testing.T) {
+	now := time.Date(2025, 3, 2, 12, 0, 0, 0, time.Local)
+	event := func(at time.Time, data CostEventData) Event {
+		raw, _ := json.Marshal(data)
+		return Event{Type: EventCostRecord, Timestamp: at, Data: raw}
+	}
+
+	events := []Event{
+		event(now.Add(-24*time.Hour), CostEventData{JobID: "old", WorkerName: "vito", Cost: "$5.00"}),
+		event(now.Add(-time.Hour), CostEventData{JobID: "new", WorkerName: "vito", Cost: "$1.25"}),
+		{Type: EventJobCompleted, Timestamp: now},
+	}
+
+	tracker := NewSpendTracker()
+	tracker.now = func() time.Time { return now }
+	tracker.Load(events)
+
+	if got := tracker.Today(); !approx(got, 1.25) {
+		t.Errorf("expected only today's spend, got %.2f", got)
+	}
+	if got := tracker.Job("old"); !approx(got, 5.00) {
+		t.Errorf("expected job spend from earlier days, got %.2f", got)
+	}
+}
+
+func TestParseCost(t *testing.T) {
+	tests := map[string]float64{
+		"$1.23": 1.23,
+		"0.5":   0.5,
+		"":      0,
+		"n/a":   0,
+	}
+	for in, want := range tests {
+		if got := ParseCost(in); !approx(got, want) {
+			t.Errorf("ParseCost(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func approx(a, b float64) bool {
+	d := a - b
+	return d < 1e-9 && d > -1e-9
+}
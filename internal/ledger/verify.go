@@ -0,0 +1,246 @@
+package ledger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Kinds of problem Verify finds in a ledger.
+const (
+	ProblemTruncated  = "truncated"    // The last line was cut off mid-write
+	ProblemCorrupt    = "corrupt"      // A line that is not a valid event
+	ProblemDuplicate  = "duplicate"    // An event ID seen on an earlier line
+	ProblemOutOfOrder = "out_of_order" // An event well before the one ahead of it
+)
+
+// orderTolerance is how far an event may be timestamped before the one
+// ahead of it. Events are stamped before they are written, so concurrent
+// appends land slightly out of order.
+const orderTolerance = time.Second
+
+// Problem is something wrong with one line of a ledger.
+type Problem struct {
+	Line   int    `json:"line"` // 1-based
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"`
+}
+
+// Quarantined reports whether Repair moves the line out of the ledger.
+// Out of order events are valid and stay.
+func (p Problem) Quarantined() bool {
+	return p.Kind != ProblemOutOfOrder
+}
+
+// Report is the health of a ledger file.
+type Report struct {
+	Path     string    `json:"path"`
+	Size     int64     `json:"size"`
+	Lines    int       `json:"lines"`
+	Events   int       `json:"events"` // Valid, unique events
+	First    time.Time `json:"first,omitempty"`
+	Last     time.Time `json:"last,omitempty"` // Latest event time
+	Problems []Problem `json:"problems,omitempty"`
+
+	seen map[string]bool // Event IDs checked so far
+}
+
+// Healthy reports whether the ledger has no problems.
+func (r *Report) Healthy() bool {
+	return len(r.Problems) == 0
+}
+
+// Count returns how many problems of kind were found.
+func (r *Report) Count(kind string) int {
+	n := 0
+	for _, p := range r.Problems {
+		if p.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// Verify checks a ledger file for lines that are truncated, corrupt or
+// duplicated, and for events out of time order. A missing file is an empty,
+// healthy ledger.
+func Verify(path string) (*Report, error) {
+	report := &Report{Path: path}
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return report, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	err = scanLines(file, func(n int, line []byte, complete bool) {
+		report.Size += int64(len(line))
+		if complete {
+			report.Size++
+		}
+		report.Lines = n
+		report.check(n, line, complete)
+	})
+	report.seen = nil
+	return report, err
+}
+
+// check adds the problems with line n to the report.
+func (r *Report) check(n int, line []byte, complete bool) {
+	if r.seen == nil {
+		r.seen = make(map[string]bool)
+	}
+
+	event, err := parseEvent(line)
+	if err != nil {
+		kind := ProblemCorrupt
+		if !complete {
+			kind = ProblemTruncated
+		}
+		r.Problems = append(r.Problems, Problem{Line: n, Kind: kind, Detail: err.Error()})
+		return
+	}
+
+	if r.seen[event.ID] {
+		r.Problems = append(r.Problems, Problem{Line: n, Kind: ProblemDuplicate, Detail: "event " + event.ID + " appears earlier"})
+		return
+	}
+	r.seen[event.ID] = true
+
+	if !r.Last.IsZero() && event.Timestamp.Before(r.Last.Add(-orderTolerance)) {
+		r.Problems = append(r.Problems, Problem{
+			Line:   n,
+			Kind:   ProblemOutOfOrder,
+			Detail: fmt.Sprintf("%s is %s before the event ahead of it", event.Type, r.Last.Sub(event.Timestamp).Round(time.Second)),
+		})
+	}
+	if r.First.IsZero() {
+		r.First = event.Timestamp
+	}
+	if event.Timestamp.After(r.Last) {
+		r.Last = event.Timestamp
+	}
+	r.Events++
+}
+
+// parseEvent decodes a ledger line, requiring the fields every event has.
+func parseEvent(line []byte) (Event, error) {
+	var event Event
+	if len(bytes.TrimSpace(line)) == 0 {
+		return event, fmt.Errorf("empty line")
+	}
+	if err := json.Unmarshal(line, &event); err != nil {
+		return event, fmt.Errorf("invalid JSON: %v", err)
+	}
+	switch {
+	case event.ID == "":
+		return event, fmt.Errorf("missing id")
+	case event.Type == "":
+		return event, fmt.Errorf("missing type")
+	case event.Timestamp.IsZero():
+		return event, fmt.Errorf("missing timestamp")
+	}
+	return event, nil
+}
+
+// scanLines calls fn with each line of r, without its newline, numbered
+// from 1. complete is false for a last line with no newline.
+func scanLines(r io.Reader, fn func(n int, line []byte, complete bool)) error {
+	reader := bufio.NewReader(r)
+	for n := 1; ; n++ {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			complete := line[len(line)-1] == '\n'
+			fn(n, bytes.TrimSuffix(line, []byte("\n")), complete)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// RepairResult describes a repaired ledger.
+type RepairResult struct {
+	Report         *Report `json:"report"`      // The ledger before the repair
+	Quarantined    int     `json:"quarantined"` // Lines moved out of the ledger
+	QuarantinePath string  `json:"quarantine_path,omitempty"`
+}
+
+// Repair verifies the ledger and moves the lines that are truncated,
+// corrupt or duplicated into a quarantine file next to it, so that they can
+// be inspected, leaving the valid events in place. Appends wait while the
+// ledger is rewritten.
+func (l *Ledger) Repair() (*RepairResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	report, err := Verify(l.path)
+	if err != nil {
+		return nil, err
+	}
+	result := &RepairResult{Report: report}
+
+	bad := make(map[int]bool)
+	for _, p := range report.Problems {
+		if p.Quarantined() {
+			bad[p.Line] = true
+		}
+	}
+	if len(bad) == 0 {
+		return result, nil
+	}
+
+	var kept, quarantined bytes.Buffer
+	file, err := os.Open(l.path)
+	if err != nil {
+		return nil, err
+	}
+	err = scanLines(file, func(n int, line []byte, complete bool) {
+		if bad[n] {
+			quarantined.Write(line)
+			quarantined.WriteByte('\n')
+			return
+		}
+		kept.Write(line)
+		kept.WriteByte('\n')
+	})
+	file.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	result.QuarantinePath = fmt.Sprintf("%s.quarantine-%s", l.path, time.Now().UTC().Format("20060102T150405"))
+	if err := os.WriteFile(result.QuarantinePath, quarantined.Bytes(), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write quarantine file: %w", err)
+	}
+
+	// Swap in the repaired ledger and reopen it for appending
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, kept.Bytes(), 0600); err != nil {
+		return nil, err
+	}
+	if err := l.file.Close(); err != nil {
+		os.Remove(tmp)
+		return nil, err
+	}
+	renameErr := os.Rename(tmp, l.path)
+	if l.file, err = os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600); err != nil {
+		return nil, err
+	}
+	if renameErr != nil {
+		os.Remove(tmp)
+		return nil, renameErr
+	}
+
+	result.Quarantined = len(bad)
+	return result, nil
+}
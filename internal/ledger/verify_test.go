@@ -0,0 +1,110 @@
+package ledger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// brokenLedger is a ledger with one problem of each kind.
+const brokenLedger = `{"id":"1","type":"job.created","timestamp":"2025-06-01T10:00:00Z"}
+not json at all
+{"id":"2","type":"job.started","timestamp":"2025-06-01T10:05:00Z"}
+{"id":"1","type":"job.created","timestamp":"2025-06-01T10:06:00Z"}
+{"id":"3","type":"job.completed","timestamp":"2025-06-01T10:01:00Z"}
+{"type":"job.failed","timestamp":"2025-06-01T10:07:00Z"}
+{"id":"4","type":"job.qu`
+
+func TestVerify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	if err := os.WriteFile(path, []byte(brokenLedger), 0600); err != nil {
+		t.Fatalf("failed to write ledger: %v", err)
+	}
+
+	report, err := Verify(path)
+	if err != nil {
+		t.Fatalf("verify failed: %v", err)
+	}
+
+	if report.Lines != 7 || report.Events != 3 || report.Size != int64(len(brokenLedger)) {
+		t.Errorf("expected 7 lines, 3 events and %d bytes, got %d, %d and %d", len(brokenLedger), report.Lines, report.Events, report.Size)
+	}
+	want := []struct {
+		line int
+		kind string
+	}{
+		{2, ProblemCorrupt},
+		{4, ProblemDuplicate},
+		{5, ProblemOutOfOrder},
+		{6, ProblemCorrupt},
+		{7, ProblemTruncated},
+	}
+	if len(report.Problems) != len(want) {
+		t.Fatalf("expected %d problems, got %+v", len(want), report.Problems)
+	}
+	for i, w := range want {
+		if p := report.Problems[i]; p.Line != w.line || p.Kind != w.kind {
+			t.Errorf("problem %d: expected %s on line %d, got %+v", i, w.kind, w.line, p)
+		}
+	}
+	if report.Healthy() {
+		t.Error("expected the ledger to be unhealthy")
+	}
+	if report.Count(ProblemCorrupt) != 2 {
+		t.Errorf("expected 2 corrupt lines, got %d", report.Count(ProblemCorrupt))
+	}
+
+	missing, err := Verify(filepath.Join(t.TempDir(), "missing.jsonl"))
+	if err != nil || !missing.Healthy() || missing.Lines != 0 {
+		t.Errorf("expected a missing ledger to be empty and healthy, got %+v, %v", missing, err)
+	}
+}
+
+func TestLedger_Repair(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	if err := os.WriteFile(path, []byte(brokenLedger), 0600); err != nil {
+		t.Fatalf("failed to write ledger: %v", err)
+	}
+
+	l, err := Open(path)
+	if err != nil {
+		t.Fatalf("failed to open ledger: %v", err)
+	}
+	defer l.Close()
+
+	result, err := l.Repair()
+	if err != nil {
+		t.Fatalf("repair failed: %v", err)
+	}
+	if result.Quarantined != 4 {
+		t.Errorf("expected 4 lines quarantined, got %d", result.Quarantined)
+	}
+
+	quarantined, err := os.ReadFile(result.QuarantinePath)
+	if err != nil {
+		t.Fatalf("failed to read quarantine file: %v", err)
+	}
+	if !strings.HasPrefix(string(quarantined), "not json at all\n") || strings.Count(string(quarantined), "\n") != 4 {
+		t.Errorf("unexpected quarantine file %q", quarantined)
+	}
+
+	// The ledger keeps working after the repair
+	if _, err := l.Append(EventJobQueued, nil); err != nil {
+		t.Fatalf("append after repair failed: %v", err)
+	}
+
+	report, err := Verify(path)
+	if err != nil {
+		t.Fatalf("verify failed: %v", err)
+	}
+	if report.Events != 4 || report.Count(ProblemOutOfOrder) != 1 || len(report.Problems) != 1 {
+		t.Errorf("expected 4 events with only the out of order one left, got %+v", report)
+	}
+
+	// A healthy ledger is left alone
+	again, err := l.Repair()
+	if err != nil || again.Quarantined != 0 || again.QuarantinePath != "" {
+		t.Errorf("expected nothing to repair, got %+v, %v", again, err)
+	}
+}
@@ -0,0 +1,116 @@
+// Package logging writes the daemon's operational log: structured records
+// for troubleshooting the daemon itself, kept apart from the activity
+// ledger.
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Levels lists the valid log levels, from the most to the least verbose.
+var Levels = []string{"debug", "info", "warn", "error"}
+
+// ParseLevel returns the slog level named by s.
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info", "":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return slog.LevelInfo, fmt.Errorf("unknown log level: %s (must be one of: %s)", s, strings.Join(Levels, ", "))
+}
+
+// New returns a logger writing JSON lines of level and above to w.
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// Attr is a record attribute, in the order it was logged.
+type Attr struct {
+	Key   string
+	Value string
+}
+
+// Record is a parsed line of the log.
+type Record struct {
+	Time  time.Time
+	Level slog.Level
+	Msg   string
+	Attrs []Attr
+}
+
+// ParseRecord parses a JSON line written by a New logger.
+func ParseRecord(line []byte) (Record, error) {
+	var r Record
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return r, fmt.Errorf("not a log record")
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return r, err
+		}
+		key, _ := tok.(string)
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return r, err
+		}
+
+		switch key {
+		case slog.TimeKey:
+			var s string
+			json.Unmarshal(raw, &s)
+			r.Time, _ = time.Parse(time.RFC3339Nano, s)
+		case slog.LevelKey:
+			var s string
+			json.Unmarshal(raw, &s)
+			r.Level.UnmarshalText([]byte(s))
+		case slog.MessageKey:
+			json.Unmarshal(raw, &r.Msg)
+		default:
+			r.Attrs = append(r.Attrs, Attr{Key: key, Value: attrValue(raw)})
+		}
+	}
+	return r, nil
+}
+
+// attrValue renders an attribute value: strings unquoted unless they need
+// quoting, anything else as JSON.
+func attrValue(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) != nil {
+		return string(raw)
+	}
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		quoted, _ := json.Marshal(s)
+		return string(quoted)
+	}
+	return s
+}
+
+// Format renders the record on one line for reading, as
+// "2006-01-02 15:04:05 INFO  message key=value ...".
+func (r Record) Format() string {
+	var b strings.Builder
+	b.WriteString(r.Time.Local().Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&b, " %-5s %s", r.Level.String(), r.Msg)
+	for _, a := range r.Attrs {
+		fmt.Fprintf(&b, " %s=%s", a.Key, a.Value)
+	}
+	return b.String()
+}
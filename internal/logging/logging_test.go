@@ -0,0 +1,103 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in   string
+		want slog.Level
+	}{
+		{"debug", slog.LevelDebug},
+		{"", slog.LevelInfo},
+		{"WARN", slog.LevelWarn},
+		{"error", slog.LevelError},
+	}
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	if _, err := ParseLevel("loud"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
+
+func TestRecord_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(&buf, slog.LevelInfo)
+	log.Debug("hidden")
+	log.Warn("merge failed", "job", "abc123", "error", "exit status 1", "attempt", 2)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected only the warning to be logged, got %q", buf.String())
+	}
+
+	r, err := ParseRecord([]byte(lines[0]))
+	if err != nil {
+		t.Fatalf("failed to parse record: %v", err)
+	}
+	if r.Level != slog.LevelWarn || r.Msg != "merge failed" || r.Time.IsZero() {
+		t.Errorf("unexpected record %+v", r)
+	}
+
+	formatted := r.Format()
+	if !strings.HasSuffix(formatted, ` WARN  merge failed job=abc123 error="exit status 1" attempt=2`) {
+		t.Errorf("unexpected format %q", formatted)
+	}
+
+	if _, err := ParseRecord([]byte("not json")); err == nil {
+		t.Error("expected an error for a line that is not a record")
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.log")
+	f, err := OpenRotating(path, 10, 2)
+	if err != nil {
+		t.Fatalf("failed to open log: %v", err)
+	}
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+	f.Close()
+
+	expect := map[string]string{
+		path:                 "fourth\n",
+		RotatedPath(path, 1): "third\n",
+		RotatedPath(path, 2): "second\n",
+	}
+	for p, want := range expect {
+		data, err := os.ReadFile(p)
+		if err != nil || string(data) != want {
+			t.Errorf("expected %s to hold %q, got %q (%v)", filepath.Base(p), want, data, err)
+		}
+	}
+	if _, err := os.Stat(RotatedPath(path, 3)); !errors.Is(err, os.ErrNotExist) {
+		t.Error("expected the oldest file to be removed")
+	}
+
+	// Reopening appends to the current file
+	f, err = OpenRotating(path, 100, 2)
+	if err != nil {
+		t.Fatalf("failed to reopen log: %v", err)
+	}
+	f.Write([]byte("fifth\n"))
+	f.Close()
+	if data, _ := os.ReadFile(path); string(data) != "fourth\nfifth\n" {
+		t.Errorf("expected the log to be appended to, got %q", data)
+	}
+}
@@ -0,0 +1,119 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// RotatingFile is a log file that is rotated once it reaches a size. The
+// current file keeps its path; older ones are renamed path.1 (the newest)
+// through path.N, and the oldest beyond that are removed.
+type RotatingFile struct {
+	mu       sync.Mutex
+	path     string
+	maxSize  int64
+	maxFiles int
+	f        *os.File
+	size     int64
+}
+
+// OpenRotating opens path for appending. It is rotated before a write
+// would take it past maxSize bytes (0 never rotates), keeping maxFiles
+// rotated files.
+func OpenRotating(path string, maxSize int64, maxFiles int) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	r := &RotatingFile{path: path, maxSize: maxSize, maxFiles: maxFiles}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.f = f
+	r.size = info.Size()
+	return nil
+}
+
+// Write appends p, rotating the file first if it would grow too large. A
+// single write larger than the limit still goes into one file.
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.f == nil {
+		return 0, os.ErrClosed
+	}
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		// A failed rotation is tried again on the next write
+		if err := r.rotate(); err != nil && r.f == nil {
+			return 0, fmt.Errorf("failed to rotate log: %w", err)
+		}
+	}
+
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate shifts the rotated files along and starts a new current file. If
+// shifting fails, writing carries on in the current file.
+func (r *RotatingFile) rotate() error {
+	if err := r.f.Close(); err != nil {
+		return err
+	}
+	r.f = nil
+
+	err := r.shift()
+	if openErr := r.open(); openErr != nil {
+		return openErr
+	}
+	return err
+}
+
+func (r *RotatingFile) shift() error {
+	if r.maxFiles < 1 {
+		return os.Remove(r.path)
+	}
+
+	os.Remove(RotatedPath(r.path, r.maxFiles))
+	for i := r.maxFiles - 1; i >= 1; i-- {
+		err := os.Rename(RotatedPath(r.path, i), RotatedPath(r.path, i+1))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return os.Rename(r.path, RotatedPath(r.path, 1))
+}
+
+// Close closes the current file.
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.f == nil {
+		return nil
+	}
+	err := r.f.Close()
+	r.f = nil
+	return err
+}
+
+// RotatedPath returns the path of the nth most recent rotated file.
+func RotatedPath(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
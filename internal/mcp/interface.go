@@ -11,13 +11,19 @@ type DaemonInterface interface {
 	// Worker operations
 	ListWorkers() []protocol.WorkerInfo
 	GetWorker(name string) (*protocol.WorkerDetailInfo, error)
+	AddWorker(name, role string) (*protocol.WorkerInfo, error)
+	RemoveWorker(name string, force bool) error
+	MessageWorker(name, message string) error
+	GenerateHandoff(name string) (*protocol.HandoffSummary, error)
 
 	// Job operations
-	ListJobs(status string) []protocol.JobInfo
+	ListJobs(status string, labels map[string]string) []protocol.JobInfo
 	GetJob(id string) (*protocol.JobInfo, error)
 	CreateJob(description string, priority int, territory string) (*job.Job, error)
 	CancelJob(id string) error
+	ApproveJob(id string) (status string, err error) // Approves a draft or a plan; returns the job's new status
 	SetJobPriority(id string, priority int) error
+	DelegateJob(from string, params protocol.JobDelegateParams) (*protocol.JobInfo, error) // Delegates a job from the worker named from
 
 	// Activity and status
 	ListActivity(limit int) []ActivityEntry
@@ -31,6 +37,11 @@ type DaemonInterface interface {
 
 	// Cost summary
 	GetCosts() *CostSummary
+
+	// Draft operation of the chat's plan mode
+	GetChatPlan() (*protocol.ChatPlanResult, error)
+	AddPlanStep(step protocol.PlanStep) (*protocol.ChatPlanResult, error)
+	RemovePlanStep(key string) (*protocol.ChatPlanResult, error)
 }
 
 // ActivityEntry represents an activity log entry.
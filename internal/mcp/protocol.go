@@ -82,6 +82,7 @@ const (
 // MCP method constants
 const (
 	MethodInitialize = "initialize"
+	MethodPing       = "ping"
 	MethodToolsList  = "tools/list"
 	MethodToolsCall  = "tools/call"
 )
@@ -152,9 +153,10 @@ type InputSchema struct {
 
 // Property represents a JSON schema property.
 type Property struct {
-	Type        string   `json:"type"`
-	Description string   `json:"description,omitempty"`
-	Enum        []string `json:"enum,omitempty"`
+	Type        string    `json:"type"`
+	Description string    `json:"description,omitempty"`
+	Enum        []string  `json:"enum,omitempty"`
+	Items       *Property `json:"items,omitempty"` // Element type of an array
 }
 
 // ToolsListResult represents the result of a tools/list request.
@@ -10,28 +10,43 @@ import (
 	"cosa/internal/config"
 )
 
+// maxMessageSize is the largest JSON-RPC message the server reads.
+const maxMessageSize = 1024 * 1024 // 1MB
+
 // Server is an MCP server that provides Cosa tools to Claude.
 type Server struct {
 	daemon   DaemonInterface
 	registry *ToolRegistry
 }
 
-// NewServer creates a new MCP server.
-func NewServer(daemon DaemonInterface) *Server {
+// NewServer creates a new MCP server. Without allowWrite it offers only the
+// tools that leave the daemon's state unchanged. An agent's server, such as
+// the underboss's, leaves out the tools reserved for people.
+func NewServer(daemon DaemonInterface, allowWrite, agent bool) *Server {
 	return &Server{
 		daemon:   daemon,
-		registry: NewToolRegistry(),
+		registry: NewToolRegistry(allowWrite, agent),
 	}
 }
 
-// Serve runs the MCP server, reading from stdin and writing to stdout.
+// NewWorkerServer creates an MCP server for the session of the named
+// worker running job, offering the tools that leave the daemon's state
+// unchanged and those with which the worker acts as itself.
+func NewWorkerServer(daemon DaemonInterface, worker, job string) *Server {
+	return &Server{
+		daemon:   daemon,
+		registry: NewWorkerToolRegistry(worker, job),
+	}
+}
+
+// Serve runs the MCP server over stdio, reading a JSON-RPC message per line
+// from stdin and writing responses to stdout.
 func (s *Server) Serve(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
 	scanner := bufio.NewScanner(stdin)
 
 	// Increase buffer size for large requests
-	const maxScanTokenSize = 1024 * 1024 // 1MB
-	buf := make([]byte, maxScanTokenSize)
-	scanner.Buffer(buf, maxScanTokenSize)
+	buf := make([]byte, maxMessageSize)
+	scanner.Buffer(buf, maxMessageSize)
 
 	for scanner.Scan() {
 		select {
@@ -45,22 +60,33 @@ func (s *Server) Serve(ctx context.Context, stdin io.Reader, stdout io.Writer) e
 			continue
 		}
 
-		var req Request
-		if err := json.Unmarshal(line, &req); err != nil {
-			resp, _ := NewErrorResponse(nil, ParseError, "Parse error", nil)
+		if resp := s.handleMessage(line); resp != nil {
 			s.sendResponse(stdout, resp)
-			continue
 		}
-
-		resp := s.handleRequest(&req)
-		s.sendResponse(stdout, resp)
 	}
 
 	return scanner.Err()
 }
 
+// handleMessage handles a single JSON-RPC message and returns the response
+// to send, or nil for a notification, which gets none.
+func (s *Server) handleMessage(data []byte) *Response {
+	var req Request
+	if err := json.Unmarshal(data, &req); err != nil {
+		resp, _ := NewErrorResponse(nil, ParseError, "Parse error", nil)
+		return resp
+	}
+	if req.ID == nil {
+		return nil
+	}
+	return s.handleRequest(&req)
+}
+
 func (s *Server) handleRequest(req *Request) *Response {
 	switch req.Method {
+	case MethodPing:
+		resp, _ := NewResponse(req.ID, struct{}{})
+		return resp
 	case MethodInitialize:
 		return s.handleInitialize(req)
 	case MethodToolsList:
@@ -0,0 +1,166 @@
+package mcp
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Paths served by the HTTP transport.
+const (
+	SSEPath     = "/sse"
+	MessagePath = "/message"
+)
+
+// sseKeepAlive is how often an idle event stream gets a comment, so that
+// proxies do not close it.
+const sseKeepAlive = 30 * time.Second
+
+// sseSession is an open event stream and the responses waiting to be sent
+// on it.
+type sseSession struct {
+	messages chan []byte
+	done     chan struct{}
+}
+
+// sseTransport serves MCP's HTTP with server-sent events transport.
+type sseTransport struct {
+	server *Server
+	token  string
+
+	mu       sync.Mutex
+	sessions map[string]*sseSession
+}
+
+// SSEHandler serves the server over HTTP. A client opens an event stream
+// with GET /sse, whose first event gives the URL to POST its messages to;
+// the responses arrive as message events on the stream. With a token set,
+// every request must carry it as a bearer token.
+func (s *Server) SSEHandler(token string) http.Handler {
+	t := &sseTransport{
+		server:   s,
+		token:    token,
+		sessions: make(map[string]*sseSession),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+SSEPath, t.auth(t.handleStream))
+	mux.HandleFunc("POST "+MessagePath, t.auth(t.handleMessage))
+	return mux
+}
+
+func (t *sseTransport) auth(next http.HandlerFunc) http.HandlerFunc {
+	if t.token == "" {
+		return next
+	}
+	want := []byte("Bearer " + t.token)
+	return func(w http.ResponseWriter, r *http.Request) {
+		got := []byte(r.Header.Get("Authorization"))
+		if subtle.ConstantTimeCompare(got, want) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="cosa-mcp"`)
+			http.Error(w, "missing or invalid bearer token", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (t *sseTransport) handleStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	id, err := newSessionID()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	session := &sseSession{
+		messages: make(chan []byte, 16),
+		done:     make(chan struct{}),
+	}
+
+	t.mu.Lock()
+	t.sessions[id] = session
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.sessions, id)
+		t.mu.Unlock()
+		close(session.done)
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	fmt.Fprintf(w, "event: endpoint\ndata: %s?session_id=%s\n\n", MessagePath, id)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case data := <-session.messages:
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+			flusher.Flush()
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func (t *sseTransport) handleMessage(w http.ResponseWriter, r *http.Request) {
+	t.mu.Lock()
+	session, ok := t.sessions[r.URL.Query().Get("session_id")]
+	t.mu.Unlock()
+	if !ok {
+		http.Error(w, "unknown session", http.StatusNotFound)
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxMessageSize+1))
+	if err != nil {
+		http.Error(w, "failed to read message", http.StatusBadRequest)
+		return
+	}
+	if len(data) > maxMessageSize {
+		http.Error(w, "message too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	resp := t.server.handleMessage(data)
+	w.WriteHeader(http.StatusAccepted)
+	if resp == nil {
+		return
+	}
+
+	out, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	select {
+	case session.messages <- out:
+	case <-session.done:
+	}
+}
+
+// newSessionID returns a random ID for an event stream.
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate session ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -3,23 +3,79 @@ package mcp
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
+
+	"cosa/internal/display"
+	"cosa/internal/job"
+	"cosa/internal/protocol"
 )
 
 // ToolHandler handles a tool call and returns the result.
 type ToolHandler func(args json.RawMessage, daemon DaemonInterface) CallToolResult
 
+// writeTools are the tools that change the daemon's state. They are only
+// registered when writes are allowed.
+var writeTools = map[string]bool{
+	"cosa_add_worker":       true,
+	"cosa_remove_worker":    true,
+	"cosa_message_worker":   true,
+	"cosa_create_job":       true,
+	"cosa_cancel_job":       true,
+	"cosa_set_job_priority": true,
+	"cosa_plan_job":         true,
+	"cosa_plan_remove_job":  true,
+	"cosa_approve_job":      true,
+}
+
+// humanTools are the tools reserved for people. They are not registered
+// when the client is an agent, such as the underboss, so that it cannot
+// approve its own drafts.
+var humanTools = map[string]bool{
+	"cosa_approve_job": true,
+}
+
+// workerTools are the tools a worker's session uses to act as that worker.
+// They are only registered for a worker's server.
+var workerTools = map[string]bool{
+	"cosa_delegate_job": true,
+}
+
 // ToolRegistry manages tool definitions and handlers.
 type ToolRegistry struct {
-	tools    []Tool
-	handlers map[string]ToolHandler
+	tools      []Tool
+	handlers   map[string]ToolHandler
+	allowWrite bool
+	agent      bool
+	worker     string // Worker whose session the tools serve, if any
+	job        string // Job the worker's session runs, if known
+}
+
+// NewToolRegistry creates a new tool registry with Cosa tools. Without
+// allowWrite, only the tools that leave the daemon's state unchanged are
+// offered; for an agent, the tools reserved for people are left out.
+func NewToolRegistry(allowWrite, agent bool) *ToolRegistry {
+	return newToolRegistry(allowWrite, agent, "")
+}
+
+// NewWorkerToolRegistry creates a tool registry for the session of the
+// named worker: the tools that leave the daemon's state unchanged, and
+// those with which the worker acts as itself, such as delegating jobs. job
+// is the job the session runs, which jobs it delegates belong to.
+func NewWorkerToolRegistry(worker, job string) *ToolRegistry {
+	r := newToolRegistry(false, true, worker)
+	r.job = job
+	return r
 }
 
-// NewToolRegistry creates a new tool registry with Cosa tools.
-func NewToolRegistry() *ToolRegistry {
+func newToolRegistry(allowWrite, agent bool, worker string) *ToolRegistry {
 	r := &ToolRegistry{
-		tools:    make([]Tool, 0),
-		handlers: make(map[string]ToolHandler),
+		tools:      make([]Tool, 0),
+		handlers:   make(map[string]ToolHandler),
+		allowWrite: allowWrite,
+		agent:      agent,
+		worker:     worker,
 	}
 	r.registerCosaTools()
 	return r
@@ -33,6 +89,18 @@ func (r *ToolRegistry) Tools() []Tool {
 // Call invokes a tool by name.
 func (r *ToolRegistry) Call(name string, args json.RawMessage, daemon DaemonInterface) (CallToolResult, error) {
 	handler, ok := r.handlers[name]
+	if !ok && humanTools[name] && r.agent {
+		msg := fmt.Sprintf("tool %s is reserved for people", name)
+		return ToolError(msg), fmt.Errorf("%s", msg)
+	}
+	if !ok && workerTools[name] {
+		msg := fmt.Sprintf("tool %s is only offered to workers' sessions", name)
+		return ToolError(msg), fmt.Errorf("%s", msg)
+	}
+	if !ok && writeTools[name] {
+		msg := fmt.Sprintf("tool %s changes state, and this server is read-only", name)
+		return ToolError(msg), fmt.Errorf("%s", msg)
+	}
 	if !ok {
 		return ToolError(fmt.Sprintf("unknown tool: %s", name)), fmt.Errorf("unknown tool: %s", name)
 	}
@@ -40,6 +108,15 @@ func (r *ToolRegistry) Call(name string, args json.RawMessage, daemon DaemonInte
 }
 
 func (r *ToolRegistry) register(tool Tool, handler ToolHandler) {
+	if writeTools[tool.Name] && !r.allowWrite {
+		return
+	}
+	if humanTools[tool.Name] && r.agent {
+		return
+	}
+	if workerTools[tool.Name] && r.worker == "" {
+		return
+	}
 	r.tools = append(r.tools, tool)
 	r.handlers[tool.Name] = handler
 }
@@ -77,18 +154,116 @@ func (r *ToolRegistry) registerCosaTools() {
 		handleGetWorker,
 	)
 
+	// cosa_add_worker - Add a worker
+	r.register(
+		Tool{
+			Name:        "cosa_add_worker",
+			Description: "Add a new worker to the Cosa organization",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"name": {
+						Type:        "string",
+						Description: "Name of the new worker",
+					},
+					"role": {
+						Type:        "string",
+						Description: "Worker role (default soldato)",
+						Enum:        []string{"soldato", "capo", "consigliere", "associate", "lookout", "cleaner"},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		handleAddWorker,
+	)
+
+	// cosa_remove_worker - Remove a worker
+	r.register(
+		Tool{
+			Name:        "cosa_remove_worker",
+			Description: "Remove a worker from the organization. Call without confirm first to see what will be affected, then call again with confirm=true once the boss agrees",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"name": {
+						Type:        "string",
+						Description: "Name of the worker to remove",
+					},
+					"force": {
+						Type:        "boolean",
+						Description: "Remove even if the worker is busy or its worktree has uncommitted changes",
+					},
+					"confirm": {
+						Type:        "boolean",
+						Description: "Must be true to actually remove the worker",
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		handleRemoveWorker,
+	)
+
+	// cosa_message_worker - Send a message to a worker
+	r.register(
+		Tool{
+			Name:        "cosa_message_worker",
+			Description: "Send a message to a worker's Claude session",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"name": {
+						Type:        "string",
+						Description: "Name of the worker",
+					},
+					"message": {
+						Type:        "string",
+						Description: "Message to send",
+					},
+				},
+				Required: []string{"name", "message"},
+			},
+		},
+		handleMessageWorker,
+	)
+
+	// cosa_generate_handoff - Generate a handoff summary
+	r.register(
+		Tool{
+			Name:        "cosa_generate_handoff",
+			Description: "Generate a handoff summary of a worker's current state, for passing its work to another worker",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"name": {
+						Type:        "string",
+						Description: "Name of the worker",
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		handleGenerateHandoff,
+	)
+
 	// cosa_list_jobs - List jobs
 	r.register(
 		Tool{
 			Name:        "cosa_list_jobs",
-			Description: "List jobs in the system, optionally filtered by status",
+			Description: "List jobs in the system, optionally filtered by status and labels",
 			InputSchema: InputSchema{
 				Type: "object",
 				Properties: map[string]Property{
 					"status": {
 						Type:        "string",
 						Description: "Filter by job status",
-						Enum:        []string{"pending", "queued", "running", "completed", "failed", "cancelled"},
+						Enum:        []string{"draft", "pending", "queued", "running", "completed", "failed", "cancelled"},
+					},
+					"labels": {
+						Type:        "array",
+						Description: "Only jobs matching every label filter: key=value, or key for jobs with the label at all",
+						Items:       &Property{Type: "string"},
 					},
 				},
 			},
@@ -218,6 +393,25 @@ func (r *ToolRegistry) registerCosaTools() {
 		handleCancelJob,
 	)
 
+	// cosa_approve_job - Approve a draft or a plan
+	r.register(
+		Tool{
+			Name:        "cosa_approve_job",
+			Description: "Approve a job waiting for approval: a draft, which is then queued, or the plan of a plan-first job, which is then executed",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"id": {
+						Type:        "string",
+						Description: "Job ID to approve",
+					},
+				},
+				Required: []string{"id"},
+			},
+		},
+		handleApproveJob,
+	)
+
 	// cosa_set_job_priority - Update job priority
 	r.register(
 		Tool{
@@ -245,7 +439,7 @@ func (r *ToolRegistry) registerCosaTools() {
 	r.register(
 		Tool{
 			Name:        "cosa_queue_status",
-			Description: "Get the current job queue status",
+			Description: "Get the current job queue status, with estimates of when running and queued jobs will be done",
 			InputSchema: InputSchema{
 				Type:       "object",
 				Properties: map[string]Property{},
@@ -253,6 +447,109 @@ func (r *ToolRegistry) registerCosaTools() {
 		},
 		handleQueueStatus,
 	)
+
+	// cosa_plan_job - Add a job to the chat's draft operation
+	r.register(
+		Tool{
+			Name: "cosa_plan_job",
+			Description: "Add a job to the draft operation of the chat's plan mode, or replace the " +
+				"planned job with the same key. Nothing runs until the boss executes the plan.",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"key": {
+						Type:        "string",
+						Description: "Short lowercase name other planned jobs use to depend on this one (default step-N)",
+					},
+					"description": {
+						Type:        "string",
+						Description: "Description of the job to be done",
+					},
+					"priority": {
+						Type:        "integer",
+						Description: "Priority level 1-5 (1=highest, 5=lowest, default 3)",
+					},
+					"depends_on": {
+						Type:        "array",
+						Description: "Keys of planned jobs, or IDs of existing jobs, that must finish first",
+						Items:       &Property{Type: "string"},
+					},
+					"estimate": {
+						Type:        "string",
+						Description: "Rough estimate of the effort or cost, e.g. \"30m\" or \"$2\"",
+					},
+				},
+				Required: []string{"description"},
+			},
+		},
+		handlePlanJob,
+	)
+
+	// cosa_show_plan - Show the draft operation
+	r.register(
+		Tool{
+			Name:        "cosa_show_plan",
+			Description: "Show the draft operation of the chat's plan mode",
+			InputSchema: InputSchema{
+				Type:       "object",
+				Properties: map[string]Property{},
+			},
+		},
+		handleShowPlan,
+	)
+
+	// cosa_plan_remove_job - Remove a job from the draft operation
+	r.register(
+		Tool{
+			Name:        "cosa_plan_remove_job",
+			Description: "Remove a job from the draft operation of the chat's plan mode",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"key": {
+						Type:        "string",
+						Description: "Key of the planned job",
+					},
+				},
+				Required: []string{"key"},
+			},
+		},
+		handlePlanRemoveJob,
+	)
+
+	// cosa_delegate_job - Hand part of the worker's job to another worker
+	r.register(
+		Tool{
+			Name: "cosa_delegate_job",
+			Description: "Hand part of your current job to a worker you supervise, as a new job that runs on that worker. " +
+				"You are sent a message when it finishes",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"worker": {
+						Type:        "string",
+						Description: "Name of the worker to run the job",
+					},
+					"description": {
+						Type:        "string",
+						Description: "What the worker should do, as a short title",
+					},
+					"body": {
+						Type:        "string",
+						Description: "Full specification of the job: context, files involved and how to know it is done",
+					},
+					"priority": {
+						Type:        "integer",
+						Description: "Priority level 1-5 (default: that of your current job)",
+					},
+				},
+				Required: []string{"worker", "description"},
+			},
+		},
+		func(args json.RawMessage, daemon DaemonInterface) CallToolResult {
+			return handleDelegateJob(args, daemon, r.worker, r.job)
+		},
+	)
 }
 
 // Tool handlers
@@ -268,6 +565,9 @@ func handleListWorkers(_ json.RawMessage, daemon DaemonInterface) CallToolResult
 
 	for _, w := range workers {
 		status := w.Status
+		if w.Quarantined {
+			status += ", quarantined"
+		}
 		job := "idle"
 		if w.CurrentJobDesc != "" {
 			job = w.CurrentJobDesc
@@ -304,19 +604,146 @@ func handleGetWorker(args json.RawMessage, daemon DaemonInterface) CallToolResul
 	if worker.CurrentJob != "" {
 		sb.WriteString(fmt.Sprintf("Current Job: %s\n", worker.CurrentJob))
 	}
+	if q := worker.Quarantine; q != nil {
+		sb.WriteString(fmt.Sprintf("Quarantined: after %d failed jobs in a row, the last: %s\n", len(q.Failures), q.Failures[len(q.Failures)-1]))
+	}
+
+	return ToolSuccess(sb.String())
+}
+
+func handleAddWorker(args json.RawMessage, daemon DaemonInterface) CallToolResult {
+	var params struct {
+		Name string `json:"name"`
+		Role string `json:"role"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return ToolError(fmt.Sprintf("invalid arguments: %v", err))
+	}
+
+	if params.Name == "" {
+		return ToolError("name is required")
+	}
+
+	worker, err := daemon.AddWorker(params.Name, params.Role)
+	if err != nil {
+		return ToolError(fmt.Sprintf("failed to add worker: %v", err))
+	}
+
+	return ToolSuccess(fmt.Sprintf("Worker added: %s\nRole: %s\nWorktree: %s",
+		worker.Name, worker.Role, worker.Worktree))
+}
+
+func handleRemoveWorker(args json.RawMessage, daemon DaemonInterface) CallToolResult {
+	var params struct {
+		Name    string `json:"name"`
+		Force   bool   `json:"force"`
+		Confirm bool   `json:"confirm"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return ToolError(fmt.Sprintf("invalid arguments: %v", err))
+	}
+
+	worker, err := daemon.GetWorker(params.Name)
+	if err != nil {
+		return ToolError(fmt.Sprintf("worker not found: %s", params.Name))
+	}
+
+	busy := worker.CurrentJob != ""
+	if busy && !params.Force {
+		return ToolError(fmt.Sprintf("worker %s is working on job %s; set force=true to remove it anyway",
+			worker.Name, worker.CurrentJob))
+	}
+
+	if !params.Confirm {
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("Removing %s (%s) will stop the worker and delete its worktree.\n", worker.Name, worker.Role))
+		if busy {
+			sb.WriteString(fmt.Sprintf("Its current job %s will be interrupted.\n", worker.CurrentJob))
+		}
+		sb.WriteString("Ask the boss to confirm, then call again with confirm=true.")
+		return ToolSuccess(sb.String())
+	}
+
+	if err := daemon.RemoveWorker(worker.Name, params.Force); err != nil {
+		return ToolError(fmt.Sprintf("failed to remove worker: %v", err))
+	}
+
+	return ToolSuccess(fmt.Sprintf("Worker %s removed.", worker.Name))
+}
+
+func handleMessageWorker(args json.RawMessage, daemon DaemonInterface) CallToolResult {
+	var params struct {
+		Name    string `json:"name"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return ToolError(fmt.Sprintf("invalid arguments: %v", err))
+	}
+
+	if params.Message == "" {
+		return ToolError("message is required")
+	}
+
+	if err := daemon.MessageWorker(params.Name, params.Message); err != nil {
+		return ToolError(fmt.Sprintf("failed to message worker: %v", err))
+	}
+
+	return ToolSuccess(fmt.Sprintf("Message sent to %s.", params.Name))
+}
+
+func handleGenerateHandoff(args json.RawMessage, daemon DaemonInterface) CallToolResult {
+	var params struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return ToolError(fmt.Sprintf("invalid arguments: %v", err))
+	}
+
+	summary, err := daemon.GenerateHandoff(params.Name)
+	if err != nil {
+		return ToolError(fmt.Sprintf("failed to generate handoff: %v", err))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Handoff for %s\n", summary.WorkerName))
+	sb.WriteString(fmt.Sprintf("Status: %s\n", summary.Status))
+	if summary.JobID != "" {
+		sb.WriteString(fmt.Sprintf("Current Job: %s\n", summary.JobID))
+	}
+	writeList := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		sb.WriteString(fmt.Sprintf("\n%s:\n", title))
+		for _, item := range items {
+			sb.WriteString(fmt.Sprintf("• %s\n", item))
+		}
+	}
+	writeList("Decisions", summary.Decisions)
+	writeList("Files Touched", summary.FilesTouched)
+	writeList("Open Questions", summary.OpenQuestions)
 
 	return ToolSuccess(sb.String())
 }
 
 func handleListJobs(args json.RawMessage, daemon DaemonInterface) CallToolResult {
 	var params struct {
-		Status string `json:"status"`
+		Status string   `json:"status"`
+		Labels []string `json:"labels"`
 	}
 	json.Unmarshal(args, &params)
 
-	jobs := daemon.ListJobs(params.Status)
+	selector, err := job.ParseLabelSelector(params.Labels)
+	if err != nil {
+		return ToolError(err.Error())
+	}
+
+	jobs := daemon.ListJobs(params.Status, selector)
 	if len(jobs) == 0 {
-		if params.Status != "" {
+		switch {
+		case len(params.Labels) > 0:
+			return ToolSuccess(fmt.Sprintf("No jobs matching %s.", strings.Join(params.Labels, ", ")))
+		case params.Status != "":
 			return ToolSuccess(fmt.Sprintf("No jobs with status '%s'.", params.Status))
 		}
 		return ToolSuccess("No jobs in the system.")
@@ -330,13 +757,30 @@ func handleListJobs(args json.RawMessage, daemon DaemonInterface) CallToolResult
 		if worker == "" {
 			worker = "unassigned"
 		}
-		sb.WriteString(fmt.Sprintf("• [%s] %s (P%d) - %s: %s\n",
-			j.ID[:8], j.Status, j.Priority, worker, truncate(j.Description, 50)))
+		sb.WriteString(fmt.Sprintf("• [%s] %s (P%d) - %s: %s%s\n",
+			display.ShortID(j.ID), j.Status, j.Priority, worker, truncate(j.Description, 50), labelSuffix(j.Labels)))
 	}
 
 	return ToolSuccess(sb.String())
 }
 
+// labelSuffix renders labels as " {key=value, ...}", or nothing.
+func labelSuffix(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+labels[k])
+	}
+	return " {" + strings.Join(parts, ", ") + "}"
+}
+
 func handleGetJob(args json.RawMessage, daemon DaemonInterface) CallToolResult {
 	var params struct {
 		ID string `json:"id"`
@@ -474,13 +918,75 @@ func handleCreateJob(args json.RawMessage, daemon DaemonInterface) CallToolResul
 		priority = 3
 	}
 
-	job, err := daemon.CreateJob(params.Description, priority, params.Territory)
+	// In plan mode the job only goes into the draft operation
+	if plan, err := daemon.GetChatPlan(); err == nil && plan.Mode == protocol.ChatModePlan {
+		return addPlanStep(protocol.PlanStep{Description: params.Description, Priority: priority}, daemon)
+	}
+
+	created, err := daemon.CreateJob(params.Description, priority, params.Territory)
 	if err != nil {
 		return ToolError(fmt.Sprintf("failed to create job: %v", err))
 	}
 
-	return ToolSuccess(fmt.Sprintf("Job created: %s\nDescription: %s\nPriority: %d",
-		job.ID, job.Description, job.Priority))
+	result := fmt.Sprintf("Job created: %s\nDescription: %s\nPriority: %d",
+		created.ID, created.Description, created.Priority)
+	if created.IsDraft() {
+		result += "\nHeld as a draft: it is queued once a person approves it."
+	}
+	return ToolSuccess(result)
+}
+
+func handleDelegateJob(args json.RawMessage, daemon DaemonInterface, from, fromJob string) CallToolResult {
+	var params struct {
+		Worker      string `json:"worker"`
+		Description string `json:"description"`
+		Body        string `json:"body"`
+		Priority    int    `json:"priority"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return ToolError(fmt.Sprintf("invalid arguments: %v", err))
+	}
+	if params.Worker == "" || params.Description == "" {
+		return ToolError("worker and description are required")
+	}
+	if params.Priority < 1 || params.Priority > 5 {
+		params.Priority = 0
+	}
+
+	delegated, err := daemon.DelegateJob(from, protocol.JobDelegateParams{
+		Job:         fromJob,
+		Worker:      params.Worker,
+		Description: params.Description,
+		Body:        params.Body,
+		Priority:    params.Priority,
+	})
+	if err != nil {
+		return ToolError(fmt.Sprintf("failed to delegate job: %v", err))
+	}
+
+	return ToolSuccess(fmt.Sprintf("Job delegated to %s: %s\nDescription: %s\nPriority: %d\n"+
+		"You will be sent a message when it finishes; cosa_get_job shows how it is going.",
+		params.Worker, delegated.ID, delegated.Description, delegated.Priority))
+}
+
+func handleApproveJob(args json.RawMessage, daemon DaemonInterface) CallToolResult {
+	var params struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return ToolError(fmt.Sprintf("invalid arguments: %v", err))
+	}
+
+	if params.ID == "" {
+		return ToolError("id is required")
+	}
+
+	status, err := daemon.ApproveJob(params.ID)
+	if err != nil {
+		return ToolError(fmt.Sprintf("failed to approve job: %v", err))
+	}
+
+	return ToolSuccess(fmt.Sprintf("Job %s approved; it is now %s.", params.ID, status))
 }
 
 func handleCancelJob(args json.RawMessage, daemon DaemonInterface) CallToolResult {
@@ -526,17 +1032,87 @@ func handleQueueStatus(_ json.RawMessage, daemon DaemonInterface) CallToolResult
 
 	var sb strings.Builder
 	sb.WriteString("Queue Status:\n")
+	if status.Hold != nil {
+		sb.WriteString(fmt.Sprintf("• State: %s, no new jobs are being assigned\n", status.Hold.State))
+	}
 	sb.WriteString(fmt.Sprintf("• Ready: %d jobs\n", status.Ready))
 	sb.WriteString(fmt.Sprintf("• Pending: %d jobs\n", status.Pending))
 	sb.WriteString(fmt.Sprintf("• Running: %d jobs\n", status.Running))
 	sb.WriteString(fmt.Sprintf("• Total: %d jobs\n", status.Total))
+	if status.ETA > 0 {
+		eta := time.Unix(status.ETA, 0)
+		sb.WriteString(fmt.Sprintf("• ETA: %s (in %s)\n", eta.Format("Mon Jan 2 15:04"), time.Until(eta).Round(time.Second)))
+	}
+
+	if len(status.Jobs) > 0 {
+		sb.WriteString("\nEstimates:\n")
+		for _, j := range status.Jobs {
+			sb.WriteString(fmt.Sprintf("• [%s] %s (%s): ~%s from %s, done by %s\n",
+				display.ShortID(j.ID), j.Description, j.Status,
+				time.Duration(j.Estimate)*time.Second, j.Basis,
+				time.Unix(j.ETA, 0).Format("Mon 15:04")))
+		}
+	}
 
 	return ToolSuccess(sb.String())
 }
 
-func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
+func handlePlanJob(args json.RawMessage, daemon DaemonInterface) CallToolResult {
+	var step protocol.PlanStep
+	if err := json.Unmarshal(args, &step); err != nil {
+		return ToolError(fmt.Sprintf("invalid arguments: %v", err))
+	}
+
+	if step.Description == "" {
+		return ToolError("description is required")
+	}
+
+	return addPlanStep(step, daemon)
+}
+
+func addPlanStep(step protocol.PlanStep, daemon DaemonInterface) CallToolResult {
+	result, err := daemon.AddPlanStep(step)
+	if err != nil {
+		return ToolError(fmt.Sprintf("failed to plan job: %v", err))
+	}
+
+	return ToolSuccess(fmt.Sprintf("Planned %s (not started).\n\n%s", result.Key, formatPlan(result)))
+}
+
+func handleShowPlan(_ json.RawMessage, daemon DaemonInterface) CallToolResult {
+	result, err := daemon.GetChatPlan()
+	if err != nil {
+		return ToolError(fmt.Sprintf("failed to get plan: %v", err))
 	}
-	return s[:maxLen-2] + ".."
+
+	return ToolSuccess(formatPlan(result))
+}
+
+func handlePlanRemoveJob(args json.RawMessage, daemon DaemonInterface) CallToolResult {
+	var params struct {
+		Key string `json:"key"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return ToolError(fmt.Sprintf("invalid arguments: %v", err))
+	}
+
+	result, err := daemon.RemovePlanStep(params.Key)
+	if err != nil {
+		return ToolError(fmt.Sprintf("failed to remove planned job: %v", err))
+	}
+
+	return ToolSuccess(fmt.Sprintf("Removed %s from the plan.\n\n%s", params.Key, formatPlan(result)))
+}
+
+func formatPlan(result *protocol.ChatPlanResult) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Draft operation (%s mode):\n", result.Mode))
+	for _, line := range result.Plan.Lines() {
+		sb.WriteString(line + "\n")
+	}
+	return sb.String()
+}
+
+func truncate(s string, maxLen int) string {
+	return display.Truncate(s, maxLen)
 }
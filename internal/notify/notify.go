@@ -7,11 +7,13 @@ import (
 	"fmt"
 	"net/http"
 	"os"
-	"os/exec"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"cosa/internal/config"
+	"cosa/internal/display"
 )
 
 // EventType represents the type of notification event.
@@ -23,6 +25,13 @@ const (
 	EventWorkerStuck   EventType = "worker_stuck"
 	EventBudgetWarning EventType = "budget_warning"
 	EventBudgetExceeded EventType = "budget_exceeded"
+	EventImportCompleted EventType = "import_completed"
+	EventNeedsAttention EventType = "needs_attention"
+	EventWorkerQuarantined EventType = "worker_quarantined"
+
+	// EventWatchedJob is sent for every status change of a watched job. It
+	// bypasses the notification rules, so it is not in EventTypes.
+	EventWatchedJob EventType = "watched_job"
 )
 
 // Notification represents a notification to be sent.
@@ -32,6 +41,7 @@ type Notification struct {
 	Message     string
 	JobID       string
 	WorkerName  string
+	Priority    int    // Job priority, 0 if not tied to a job
 	Severity    string // info, warning, error
 	Timestamp   time.Time
 	ExtraFields map[string]string
@@ -39,24 +49,39 @@ type Notification struct {
 
 // Notifier handles sending notifications through multiple channels.
 type Notifier struct {
-	config     *config.NotificationConfig
+	config     atomic.Pointer[config.NotificationConfig]
 	httpClient *http.Client
 	mu         sync.Mutex
+
+	system     *SystemNotifier // Nil if no desktop notifier was found
+	systemOnce sync.Once
+
+	digest     []Notification // Held for the daily digest
+	digestSent string         // Day (YYYY-MM-DD) the digest was last sent
+	digestPath string         // File the digest is kept in; empty keeps it in memory
 }
 
 // New creates a new notifier with the given configuration.
 func New(cfg *config.NotificationConfig) *Notifier {
-	return &Notifier{
-		config: cfg,
+	n := &Notifier{
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
 	}
+	n.config.Store(cfg)
+	return n
+}
+
+// SetConfig replaces the notification settings, as when the active
+// territory overrides them. Notifications already sent are unaffected.
+func (n *Notifier) SetConfig(cfg *config.NotificationConfig) {
+	n.config.Store(cfg)
 }
 
-// NotifyJobComplete sends a notification for a completed job.
-func (n *Notifier) NotifyJobComplete(jobID, description, workerName string) {
-	if !n.config.OnJobComplete {
+// NotifyJobComplete sends a notification for a completed job. changes is
+// an optional summary of the job's diff.
+func (n *Notifier) NotifyJobComplete(jobID, description, workerName, changes string, priority int) {
+	if !n.config.Load().OnJobComplete {
 		return
 	}
 
@@ -66,6 +91,7 @@ func (n *Notifier) NotifyJobComplete(jobID, description, workerName string) {
 		Message:    truncate(description, 100),
 		JobID:      jobID,
 		WorkerName: workerName,
+		Priority:   priority,
 		Severity:   "info",
 		Timestamp:  time.Now(),
 	}
@@ -73,13 +99,18 @@ func (n *Notifier) NotifyJobComplete(jobID, description, workerName string) {
 	if notif.Message == "" {
 		notif.Message = fmt.Sprintf("Job %s completed", truncateID(jobID))
 	}
+	if changes != "" {
+		notif.ExtraFields = map[string]string{
+			"changes": changes,
+		}
+	}
 
 	n.send(notif)
 }
 
 // NotifyJobFailed sends a notification for a failed job.
-func (n *Notifier) NotifyJobFailed(jobID, description, workerName, err string) {
-	if !n.config.OnJobFailed {
+func (n *Notifier) NotifyJobFailed(jobID, description, workerName, err string, priority int) {
+	if !n.config.Load().OnJobFailed {
 		return
 	}
 
@@ -97,6 +128,7 @@ func (n *Notifier) NotifyJobFailed(jobID, description, workerName, err string) {
 		Message:    message,
 		JobID:      jobID,
 		WorkerName: workerName,
+		Priority:   priority,
 		Severity:   "error",
 		Timestamp:  time.Now(),
 		ExtraFields: map[string]string{
@@ -109,7 +141,7 @@ func (n *Notifier) NotifyJobFailed(jobID, description, workerName, err string) {
 
 // NotifyWorkerStuck sends a notification for a stuck worker.
 func (n *Notifier) NotifyWorkerStuck(workerName, severity string) {
-	if !n.config.OnWorkerStuck {
+	if !n.config.Load().OnWorkerStuck {
 		return
 	}
 
@@ -127,7 +159,7 @@ func (n *Notifier) NotifyWorkerStuck(workerName, severity string) {
 
 // NotifyBudgetWarning sends a notification when cost approaches budget threshold.
 func (n *Notifier) NotifyBudgetWarning(currentCost, budgetLimit float64, percentage int) {
-	if !n.config.OnBudgetAlert {
+	if !n.config.Load().OnBudgetAlert {
 		return
 	}
 
@@ -149,7 +181,7 @@ func (n *Notifier) NotifyBudgetWarning(currentCost, budgetLimit float64, percent
 
 // NotifyBudgetExceeded sends a notification when cost exceeds budget.
 func (n *Notifier) NotifyBudgetExceeded(currentCost, budgetLimit float64) {
-	if !n.config.OnBudgetAlert {
+	if !n.config.Load().OnBudgetAlert {
 		return
 	}
 
@@ -168,54 +200,163 @@ func (n *Notifier) NotifyBudgetExceeded(currentCost, budgetLimit float64) {
 	n.send(notif)
 }
 
-// Notify sends a generic notification.
-func (n *Notifier) Notify(title, message string) {
+// NotifyImportComplete sends a notification when a batch of submitted jobs
+// has been validated and created.
+func (n *Notifier) NotifyImportComplete(batchID string, created, failed int) {
 	notif := Notification{
-		Title:     title,
-		Message:   message,
+		Event:     EventImportCompleted,
+		Title:     "Job Import Completed",
+		Message:   fmt.Sprintf("Batch %s: %d created, %d failed", truncateID(batchID), created, failed),
 		Severity:  "info",
 		Timestamp: time.Now(),
+		ExtraFields: map[string]string{
+			"batch_id": batchID,
+			"created":  fmt.Sprintf("%d", created),
+			"failed":   fmt.Sprintf("%d", failed),
+		},
 	}
+	if failed > 0 {
+		notif.Severity = "warning"
+	}
+
 	n.send(notif)
 }
 
-func (n *Notifier) send(notif Notification) {
-	n.mu.Lock()
-	defer n.mu.Unlock()
+// NotifyNeedsAttention sends a notification when a job stops to wait on a
+// human. kind is the kind of intervention needed, such as merge_conflict.
+func (n *Notifier) NotifyNeedsAttention(jobID, description, kind, reason string, priority int) {
+	message := truncate(description, 60)
+	if reason != "" {
+		message += ": " + truncate(reason, 60)
+	}
+	if message == "" {
+		message = fmt.Sprintf("Job %s needs attention", truncateID(jobID))
+	}
 
-	// System notifications (macOS)
-	if n.config.SystemNotifications {
-		n.sendSystemNotification(notif.Title, notif.Message)
+	notif := Notification{
+		Event:     EventNeedsAttention,
+		Title:     "Job Needs Attention",
+		Message:   message,
+		JobID:     jobID,
+		Priority:  priority,
+		Severity:  "warning",
+		Timestamp: time.Now(),
+		ExtraFields: map[string]string{
+			"kind":   kind,
+			"reason": reason,
+		},
 	}
 
-	// Terminal bell
-	if n.config.TerminalBell {
-		n.sendTerminalBell()
+	n.send(notif)
+}
+
+// NotifyWorkerQuarantined sends a notification when a worker stops being
+// given jobs after failing too many in a row. failures are the signatures
+// of those failures.
+func (n *Notifier) NotifyWorkerQuarantined(workerName string, failures []string) {
+	notif := Notification{
+		Event:      EventWorkerQuarantined,
+		Title:      "Worker Quarantined",
+		Message:    fmt.Sprintf("Worker %s failed %d jobs in a row and gets no more until unquarantined", workerName, len(failures)),
+		WorkerName: workerName,
+		Severity:   "error",
+		Timestamp:  time.Now(),
+		ExtraFields: map[string]string{
+			"failures": strings.Join(failures, "\n"),
+		},
 	}
 
-	// Slack
-	if n.config.Slack.Enabled && n.config.Slack.WebhookURL != "" {
-		go n.sendSlackNotification(notif)
+	n.send(notif)
+}
+
+// NotifyWatchedJob sends a notification for a status change of a job that
+// watchers have pinned. It goes to every enabled channel, whatever the
+// notification rules say.
+func (n *Notifier) NotifyWatchedJob(jobID, description, from, to, reason string, watchers []string) {
+	message := fmt.Sprintf("%s: %s → %s", truncate(description, 60), from, to)
+	if description == "" {
+		message = fmt.Sprintf("Job %s: %s → %s", truncateID(jobID), from, to)
+	}
+	if reason != "" {
+		message += " (" + truncate(reason, 60) + ")"
 	}
 
-	// Discord
-	if n.config.Discord.Enabled && n.config.Discord.WebhookURL != "" {
-		go n.sendDiscordNotification(notif)
+	severity := "info"
+	switch to {
+	case "failed":
+		severity = "error"
+	case "cancelled":
+		severity = "warning"
 	}
 
-	// Generic webhook
-	if n.config.Webhook.Enabled && n.config.Webhook.URL != "" {
-		go n.sendWebhookNotification(notif)
+	notif := Notification{
+		Event:     EventWatchedJob,
+		Title:     "Watched Job " + strings.ToUpper(to[:1]) + to[1:],
+		Message:   message,
+		JobID:     jobID,
+		Severity:  severity,
+		Timestamp: time.Now(),
+		ExtraFields: map[string]string{
+			"from":     from,
+			"to":       to,
+			"watchers": strings.Join(watchers, ","),
+		},
 	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.deliver(notif, defaultChannels(n.config.Load()))
 }
 
-func (n *Notifier) sendSystemNotification(title, message string) {
-	// Use osascript for macOS notifications
-	script := fmt.Sprintf(`display notification %q with title %q sound name "Pop"`,
-		message, "Cosa: "+title)
+// Notify sends a generic notification.
+func (n *Notifier) Notify(title, message string) {
+	notif := Notification{
+		Title:     title,
+		Message:   message,
+		Severity:  "info",
+		Timestamp: time.Now(),
+	}
+	n.send(notif)
+}
 
-	cmd := exec.Command("osascript", "-e", script)
-	cmd.Run() // Ignore errors - notifications are best-effort
+func (n *Notifier) send(notif Notification) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	route := Resolve(n.config.Load(), notif)
+	if route.Digest {
+		n.digest = append(n.digest, notif)
+		_ = n.saveDigest() // Best effort: the digest is still sent from memory
+	}
+	n.deliver(notif, route.Channels)
+}
+
+// deliver sends a notification to the given channels. Caller must hold mu.
+func (n *Notifier) deliver(notif Notification, channels []string) {
+	for _, channel := range channels {
+		switch channel {
+		case ChannelDesktop:
+			n.sendSystemNotification(notif)
+		case ChannelBell:
+			n.sendTerminalBell()
+		case ChannelSlack:
+			go n.sendSlackNotification(notif)
+		case ChannelDiscord:
+			go n.sendDiscordNotification(notif)
+		case ChannelWebhook:
+			go n.sendWebhookNotification(notif)
+		}
+	}
+}
+
+func (n *Notifier) sendSystemNotification(notif Notification) {
+	n.systemOnce.Do(func() {
+		n.system, _ = DetectSystemNotifier()
+	})
+	if n.system == nil {
+		return
+	}
+	n.system.Send(notif) // Ignore errors - notifications are best-effort
 }
 
 func (n *Notifier) sendTerminalBell() {
@@ -270,11 +411,11 @@ func (n *Notifier) sendSlackNotification(notif Notification) {
 	}
 
 	// Add channel override if configured
-	if n.config.Slack.Channel != "" {
-		payload["channel"] = n.config.Slack.Channel
+	if n.config.Load().Slack.Channel != "" {
+		payload["channel"] = n.config.Load().Slack.Channel
 	}
 
-	n.postJSON(n.config.Slack.WebhookURL, payload)
+	n.postJSON(n.config.Load().Slack.WebhookURL, payload)
 }
 
 // sendDiscordNotification sends a notification to Discord via webhook.
@@ -324,7 +465,7 @@ func (n *Notifier) sendDiscordNotification(notif Notification) {
 		},
 	}
 
-	n.postJSON(n.config.Discord.WebhookURL, payload)
+	n.postJSON(n.config.Load().Discord.WebhookURL, payload)
 }
 
 // sendWebhookNotification sends a notification to a generic webhook endpoint.
@@ -341,19 +482,19 @@ func (n *Notifier) sendWebhookNotification(notif Notification) {
 	}
 
 	// Add custom headers
-	req, err := http.NewRequest("POST", n.config.Webhook.URL, nil)
+	req, err := http.NewRequest("POST", n.config.Load().Webhook.URL, nil)
 	if err != nil {
 		return
 	}
 
 	// Add configured headers
-	for key, value := range n.config.Webhook.Headers {
+	for key, value := range n.config.Load().Webhook.Headers {
 		req.Header.Set(key, value)
 	}
 
 	// Add secret as header if configured
-	if n.config.Webhook.Secret != "" {
-		req.Header.Set("X-Cosa-Secret", n.config.Webhook.Secret)
+	if n.config.Load().Webhook.Secret != "" {
+		req.Header.Set("X-Cosa-Secret", n.config.Load().Webhook.Secret)
 	}
 
 	body, err := json.Marshal(payload)
@@ -386,17 +527,11 @@ func (n *Notifier) postJSON(url string, payload interface{}) {
 }
 
 func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
-	}
-	return s[:maxLen-2] + ".."
+	return display.Truncate(s, maxLen)
 }
 
 func truncateID(id string) string {
-	if len(id) > 8 {
-		return id[:8]
-	}
-	return id
+	return display.ShortID(id)
 }
 
 func mapSeverity(severity string) string {
@@ -19,7 +19,7 @@ func TestNotifier_NotifyJobComplete(t *testing.T) {
 	n := New(cfg)
 
 	// Should not panic when called
-	n.NotifyJobComplete("job-123", "Test job description", "worker-1")
+	n.NotifyJobComplete("job-123", "Test job description", "worker-1", "", 3)
 }
 
 func TestNotifier_NotifyJobComplete_Disabled(t *testing.T) {
@@ -29,7 +29,7 @@ func TestNotifier_NotifyJobComplete_Disabled(t *testing.T) {
 	n := New(cfg)
 
 	// Should do nothing when disabled
-	n.NotifyJobComplete("job-123", "Test job description", "worker-1")
+	n.NotifyJobComplete("job-123", "Test job description", "worker-1", "", 3)
 }
 
 func TestNotifier_NotifyJobFailed(t *testing.T) {
@@ -39,7 +39,7 @@ func TestNotifier_NotifyJobFailed(t *testing.T) {
 	n := New(cfg)
 
 	// Should not panic when called
-	n.NotifyJobFailed("job-123", "Test job description", "worker-1", "some error")
+	n.NotifyJobFailed("job-123", "Test job description", "worker-1", "some error", 3)
 }
 
 func TestNotifier_NotifyWorkerStuck(t *testing.T) {
@@ -100,7 +100,7 @@ func TestNotifier_SlackWebhook(t *testing.T) {
 	}
 	n := New(cfg)
 
-	n.NotifyJobComplete("job-123", "Test completed", "test-worker")
+	n.NotifyJobComplete("job-123", "Test completed", "test-worker", "", 3)
 
 	// Wait for async request
 	time.Sleep(100 * time.Millisecond)
@@ -147,7 +147,7 @@ func TestNotifier_DiscordWebhook(t *testing.T) {
 	}
 	n := New(cfg)
 
-	n.NotifyJobFailed("job-456", "Test failed", "test-worker", "error message")
+	n.NotifyJobFailed("job-456", "Test failed", "test-worker", "error message", 3)
 
 	// Wait for async request
 	time.Sleep(100 * time.Millisecond)
@@ -224,6 +224,201 @@ func TestNotifier_GenericWebhook(t *testing.T) {
 	}
 }
 
+func TestNotifier_SetConfig(t *testing.T) {
+	var mu sync.Mutex
+	var urls []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		urls = append(urls, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := New(&config.NotificationConfig{
+		OnWorkerStuck: true,
+		Webhook:       config.WebhookConfig{Enabled: true, URL: server.URL + "/home"},
+	})
+	n.SetConfig(&config.NotificationConfig{
+		OnWorkerStuck: true,
+		Webhook:       config.WebhookConfig{Enabled: true, URL: server.URL + "/work"},
+	})
+
+	n.NotifyWorkerStuck("paulie", "critical")
+
+	// Wait for async request
+	time.Sleep(100 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(urls) != 1 || urls[0] != "/work" {
+		t.Errorf("expected one notification to the replaced webhook, got %v", urls)
+	}
+}
+
+func TestNotifier_JobCompleteChanges(t *testing.T) {
+	var received map[string]interface{}
+	var mu sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &received)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &config.NotificationConfig{
+		OnJobComplete: true,
+		Webhook: config.WebhookConfig{
+			Enabled: true,
+			URL:     server.URL,
+		},
+	}
+	n := New(cfg)
+
+	n.NotifyJobComplete("job-123", "Add login", "paulie", "2 files changed, +40 -3", 3)
+
+	// Wait for async request
+	time.Sleep(100 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	extra, _ := received["extra"].(map[string]interface{})
+	if extra["changes"] != "2 files changed, +40 -3" {
+		t.Errorf("expected changes in extra fields, got '%v'", received["extra"])
+	}
+}
+
+func TestNotifier_NeedsAttention(t *testing.T) {
+	var received map[string]interface{}
+	var mu sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &received)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &config.NotificationConfig{
+		Webhook: config.WebhookConfig{
+			Enabled: true,
+			URL:     server.URL,
+		},
+	}
+	n := New(cfg)
+
+	n.NotifyNeedsAttention("job-123", "Add login", "question", "Which session store should I use?", 3)
+
+	// Wait for async request
+	time.Sleep(100 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if received["event"] != string(EventNeedsAttention) {
+		t.Errorf("expected event '%s', got '%v'", EventNeedsAttention, received["event"])
+	}
+	extra, _ := received["extra"].(map[string]interface{})
+	if extra["kind"] != "question" {
+		t.Errorf("expected kind in extra fields, got '%v'", received["extra"])
+	}
+}
+
+func TestNotifier_WorkerQuarantined(t *testing.T) {
+	var received map[string]interface{}
+	var mu sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &received)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := New(&config.NotificationConfig{
+		Webhook: config.WebhookConfig{
+			Enabled: true,
+			URL:     server.URL,
+		},
+	})
+
+	n.NotifyWorkerQuarantined("paulie", []string{"claude exited: status 1", "claude exited: status 1"})
+
+	// Wait for async request
+	time.Sleep(100 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if received["event"] != string(EventWorkerQuarantined) || received["severity"] != "error" {
+		t.Errorf("expected an error for event '%s', got %v", EventWorkerQuarantined, received)
+	}
+	extra, _ := received["extra"].(map[string]interface{})
+	if extra["failures"] != "claude exited: status 1\nclaude exited: status 1" {
+		t.Errorf("expected the failure signatures in extra fields, got '%v'", received["extra"])
+	}
+}
+
+func TestNotifier_WatchedJobBypassesRules(t *testing.T) {
+	var received map[string]interface{}
+	var mu sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &received)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &config.NotificationConfig{
+		Webhook: config.WebhookConfig{
+			Enabled: true,
+			URL:     server.URL,
+		},
+		// Would hold everything for the digest
+		Rules: []config.NotificationRule{{Name: "quiet", Digest: true}},
+	}
+	n := New(cfg)
+
+	n.NotifyWatchedJob("job-123", "Add login", "queued", "running", "", []string{"carmela", "tony"})
+
+	// Wait for async request
+	time.Sleep(100 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if received["event"] != string(EventWatchedJob) {
+		t.Errorf("expected event '%s', got '%v'", EventWatchedJob, received["event"])
+	}
+	if received["title"] != "Watched Job Running" {
+		t.Errorf("unexpected title '%v'", received["title"])
+	}
+	extra, _ := received["extra"].(map[string]interface{})
+	if extra["watchers"] != "carmela,tony" {
+		t.Errorf("expected watchers in extra fields, got '%v'", received["extra"])
+	}
+	if n.DigestSize() != 0 {
+		t.Errorf("expected the digest to be skipped, got %d held", n.DigestSize())
+	}
+}
+
 func TestTruncate(t *testing.T) {
 	tests := []struct {
 		input  string
@@ -363,7 +558,7 @@ func TestNotifier_MultipleChannels(t *testing.T) {
 	}
 	n := New(cfg)
 
-	n.NotifyJobComplete("job-123", "Test completed", "test-worker")
+	n.NotifyJobComplete("job-123", "Test completed", "test-worker", "", 3)
 
 	// Wait for async requests
 	time.Sleep(200 * time.Millisecond)
@@ -0,0 +1,328 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"cosa/internal/config"
+)
+
+// Channel names used in notification rules.
+const (
+	ChannelDesktop = "desktop"
+	ChannelBell    = "bell"
+	ChannelSlack   = "slack"
+	ChannelDiscord = "discord"
+	ChannelWebhook = "webhook"
+)
+
+// Channels lists all notification channels.
+var Channels = []string{ChannelDesktop, ChannelBell, ChannelSlack, ChannelDiscord, ChannelWebhook}
+
+// EventTypes lists all notification event types.
+var EventTypes = []EventType{EventJobCompleted, EventJobFailed, EventWorkerStuck, EventBudgetWarning, EventBudgetExceeded, EventImportCompleted, EventNeedsAttention, EventWorkerQuarantined}
+
+// defaultDigestTime is used when digest_time is unset or invalid.
+const defaultDigestTime = "09:00"
+
+// maxDigestLines limits how many notifications are listed in a digest.
+const maxDigestLines = 20
+
+// Route describes where a notification is delivered.
+type Route struct {
+	Rule     string   // Name of the matching rule; empty for default routing
+	Index    int      // Index of the matching rule, or -1 for default routing
+	Channels []string // Channels notified immediately
+	Digest   bool     // Held for the daily digest
+}
+
+// Resolve evaluates the notification rules in order and returns the route of
+// the first rule matching notif. If no rule matches, the notification goes to
+// every enabled channel.
+func Resolve(cfg *config.NotificationConfig, notif Notification) Route {
+	for i, rule := range cfg.Rules {
+		if !ruleMatches(rule, notif) {
+			continue
+		}
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		return Route{
+			Rule:     name,
+			Index:    i,
+			Channels: availableChannels(cfg, rule.Channels),
+			Digest:   rule.Digest,
+		}
+	}
+
+	return Route{Index: -1, Channels: defaultChannels(cfg)}
+}
+
+// DefaultSeverity returns the severity a notification for event is sent with.
+// Worker stuck notifications vary; warning is returned for them.
+func DefaultSeverity(event EventType) string {
+	switch event {
+	case EventJobFailed, EventBudgetExceeded, EventWorkerQuarantined:
+		return "error"
+	case EventWorkerStuck, EventBudgetWarning, EventNeedsAttention:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// ValidateRules reports rules that refer to unknown events or channels.
+func ValidateRules(rules []config.NotificationRule) error {
+	for i, rule := range rules {
+		for _, event := range rule.Events {
+			if !isEventType(event) {
+				return fmt.Errorf("rule %d: unknown event %q", i+1, event)
+			}
+		}
+		for _, channel := range rule.Channels {
+			if !isChannel(channel) {
+				return fmt.Errorf("rule %d: unknown channel %q", i+1, channel)
+			}
+		}
+	}
+	return nil
+}
+
+func ruleMatches(rule config.NotificationRule, notif Notification) bool {
+	if len(rule.Events) > 0 && !containsString(rule.Events, string(notif.Event)) {
+		return false
+	}
+	if len(rule.Severities) > 0 && !containsString(rule.Severities, notif.Severity) {
+		return false
+	}
+	if len(rule.Priorities) > 0 {
+		matched := false
+		for _, p := range rule.Priorities {
+			if p == notif.Priority {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// defaultChannels returns the channels enabled in the configuration.
+func defaultChannels(cfg *config.NotificationConfig) []string {
+	var channels []string
+	if cfg.SystemNotifications {
+		channels = append(channels, ChannelDesktop)
+	}
+	if cfg.TerminalBell {
+		channels = append(channels, ChannelBell)
+	}
+	if cfg.Slack.Enabled && cfg.Slack.WebhookURL != "" {
+		channels = append(channels, ChannelSlack)
+	}
+	if cfg.Discord.Enabled && cfg.Discord.WebhookURL != "" {
+		channels = append(channels, ChannelDiscord)
+	}
+	if cfg.Webhook.Enabled && cfg.Webhook.URL != "" {
+		channels = append(channels, ChannelWebhook)
+	}
+	return channels
+}
+
+// availableChannels filters a rule's channels to those that are configured.
+func availableChannels(cfg *config.NotificationConfig, requested []string) []string {
+	var channels []string
+	for _, channel := range requested {
+		switch channel {
+		case ChannelDesktop, ChannelBell:
+		case ChannelSlack:
+			if cfg.Slack.WebhookURL == "" {
+				continue
+			}
+		case ChannelDiscord:
+			if cfg.Discord.WebhookURL == "" {
+				continue
+			}
+		case ChannelWebhook:
+			if cfg.Webhook.URL == "" {
+				continue
+			}
+		default:
+			continue
+		}
+		if !containsString(channels, channel) {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+// RunDigest sends the daily digest at the configured digest time until ctx
+// is cancelled.
+func (n *Notifier) RunDigest(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if n.digestDue(now) {
+				n.FlushDigest()
+			}
+		}
+	}
+}
+
+// digestDue returns true once the digest time has passed today and today's
+// digest has not been sent.
+func (n *Notifier) digestDue(now time.Time) bool {
+	at, err := time.Parse("15:04", n.config.Load().DigestTime)
+	if err != nil {
+		at, _ = time.Parse("15:04", defaultDigestTime)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	day := now.Format("2006-01-02")
+	if n.digestSent == day {
+		return false
+	}
+	return now.Hour() > at.Hour() || (now.Hour() == at.Hour() && now.Minute() >= at.Minute())
+}
+
+// FlushDigest sends the held notifications as a single summary to the
+// enabled channels and clears the digest.
+func (n *Notifier) FlushDigest() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.digestSent = time.Now().Format("2006-01-02")
+	if len(n.digest) == 0 {
+		_ = n.saveDigest() // Best effort: at worst today's empty digest is checked again
+		return
+	}
+
+	held := n.digest
+	n.digest = nil
+	_ = n.saveDigest() // Best effort: a failed save resends at worst
+
+	counts := make(map[string]int)
+	for _, notif := range held {
+		counts[notif.Title]++
+	}
+	titles := make([]string, 0, len(counts))
+	for title := range counts {
+		titles = append(titles, title)
+	}
+	sort.Strings(titles)
+
+	var summary []string
+	for _, title := range titles {
+		summary = append(summary, fmt.Sprintf("%d %s", counts[title], strings.ToLower(title)))
+	}
+
+	lines := []string{strings.Join(summary, ", ")}
+	for i, notif := range held {
+		if i == maxDigestLines {
+			lines = append(lines, fmt.Sprintf("... and %d more", len(held)-maxDigestLines))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("- %s: %s", notif.Title, notif.Message))
+	}
+
+	n.deliver(Notification{
+		Title:     "Daily Digest",
+		Message:   strings.Join(lines, "\n"),
+		Severity:  "info",
+		Timestamp: time.Now(),
+		ExtraFields: map[string]string{
+			"count": fmt.Sprintf("%d", len(held)),
+		},
+	}, defaultChannels(n.config.Load()))
+}
+
+// digestState is the digest as kept on disk.
+type digestState struct {
+	Held []Notification `json:"held,omitempty"`
+	Sent string         `json:"sent,omitempty"` // Day (YYYY-MM-DD) the digest was last sent
+}
+
+// LoadDigest keeps the digest in the file at path, so notifications held
+// for it survive a daemon restart, and loads the digest already there.
+func (n *Notifier) LoadDigest(path string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.digestPath = path
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read digest: %w", err)
+	}
+
+	var state digestState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to parse digest: %w", err)
+	}
+	n.digest = append(state.Held, n.digest...)
+	if state.Sent > n.digestSent {
+		n.digestSent = state.Sent
+	}
+	return nil
+}
+
+// saveDigest writes the digest to its file, if it has one. Caller must hold
+// mu.
+func (n *Notifier) saveDigest() error {
+	if n.digestPath == "" {
+		return nil
+	}
+	data, err := json.Marshal(digestState{Held: n.digest, Sent: n.digestSent})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(n.digestPath, data, 0600)
+}
+
+// DigestSize returns the number of notifications held for the digest.
+func (n *Notifier) DigestSize() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.digest)
+}
+
+func isEventType(event string) bool {
+	for _, e := range EventTypes {
+		if string(e) == event {
+			return true
+		}
+	}
+	return false
+}
+
+func isChannel(channel string) bool {
+	return containsString(Channels, channel)
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cosa/internal/config"
+)
+
+func TestResolve_DefaultRouting(t *testing.T) {
+	cfg := &config.NotificationConfig{
+		SystemNotifications: true,
+		Slack:               config.SlackConfig{Enabled: false, WebhookURL: "http://slack"},
+		Webhook:             config.WebhookConfig{Enabled: true, URL: "http://hook"},
+	}
+
+	route := Resolve(cfg, Notification{Event: EventJobFailed, Priority: 5, Severity: "error"})
+	if route.Index != -1 || route.Rule != "" {
+		t.Errorf("expected default routing, got rule %q (%d)", route.Rule, route.Index)
+	}
+	if strings.Join(route.Channels, ",") != "desktop,webhook" {
+		t.Errorf("expected desktop,webhook, got %v", route.Channels)
+	}
+}
+
+func TestResolve_FirstMatchWins(t *testing.T) {
+	cfg := &config.NotificationConfig{
+		SystemNotifications: true,
+		Webhook:             config.WebhookConfig{URL: "http://hook"},
+		Rules: []config.NotificationRule{
+			{Name: "critical-failures", Events: []string{"job_failed"}, Priorities: []int{4, 5}, Channels: []string{"desktop", "webhook"}},
+			{Name: "low-completions", Events: []string{"job_completed"}, Priorities: []int{1}, Digest: true},
+			{Events: []string{"job_failed"}, Channels: []string{"slack"}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		notif    Notification
+		rule     string
+		channels string
+		digest   bool
+	}{
+		{"critical failure", Notification{Event: EventJobFailed, Priority: 5}, "critical-failures", "desktop,webhook", false},
+		{"low completion", Notification{Event: EventJobCompleted, Priority: 1}, "low-completions", "", true},
+		{"normal failure falls through", Notification{Event: EventJobFailed, Priority: 3}, "#3", "", false},
+		{"unmatched", Notification{Event: EventWorkerStuck}, "", "desktop", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := Resolve(cfg, tt.notif)
+			if route.Rule != tt.rule {
+				t.Errorf("expected rule %q, got %q", tt.rule, route.Rule)
+			}
+			// Slack has no URL, so the third rule routes nowhere
+			if got := strings.Join(route.Channels, ","); got != tt.channels {
+				t.Errorf("expected channels %q, got %q", tt.channels, got)
+			}
+			if route.Digest != tt.digest {
+				t.Errorf("expected digest %v, got %v", tt.digest, route.Digest)
+			}
+		})
+	}
+}
+
+func TestResolve_Severity(t *testing.T) {
+	cfg := &config.NotificationConfig{
+		Rules: []config.NotificationRule{
+			{Name: "stuck-critical", Events: []string{"worker_stuck"}, Severities: []string{"error"}, Channels: []string{"bell"}},
+		},
+	}
+
+	if route := Resolve(cfg, Notification{Event: EventWorkerStuck, Severity: "error"}); route.Rule != "stuck-critical" {
+		t.Errorf("expected stuck-critical to match, got %q", route.Rule)
+	}
+	if route := Resolve(cfg, Notification{Event: EventWorkerStuck, Severity: "warning"}); route.Index != -1 {
+		t.Errorf("expected no match for warning, got %q", route.Rule)
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	valid := []config.NotificationRule{{Events: []string{"job_failed"}, Channels: []string{"desktop", "webhook"}}}
+	if err := ValidateRules(valid); err != nil {
+		t.Errorf("expected valid rules, got %v", err)
+	}
+
+	if err := ValidateRules([]config.NotificationRule{{Events: []string{"job_exploded"}}}); err == nil {
+		t.Error("expected error for unknown event")
+	}
+	if err := ValidateRules([]config.NotificationRule{{Channels: []string{"pager"}}}); err == nil {
+		t.Error("expected error for unknown channel")
+	}
+}
+
+func TestNotifier_Digest(t *testing.T) {
+	var received map[string]interface{}
+	var mu sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &received)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &config.NotificationConfig{
+		OnJobComplete: true,
+		Webhook:       config.WebhookConfig{Enabled: true, URL: server.URL},
+		Rules: []config.NotificationRule{
+			{Events: []string{"job_completed"}, Priorities: []int{1}, Digest: true},
+		},
+	}
+	n := New(cfg)
+
+	n.NotifyJobComplete("job-1", "Tidy docs", "vito", "", 1)
+	n.NotifyJobComplete("job-2", "Fix typo", "vito", "", 1)
+
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	if received != nil {
+		t.Error("expected digested notifications not to be sent immediately")
+	}
+	mu.Unlock()
+
+	if n.DigestSize() != 2 {
+		t.Fatalf("expected 2 held notifications, got %d", n.DigestSize())
+	}
+
+	n.FlushDigest()
+	time.Sleep(100 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if received["title"] != "Daily Digest" {
+		t.Errorf("expected digest title, got %v", received["title"])
+	}
+	if msg, _ := received["message"].(string); !strings.Contains(msg, "2 job completed") {
+		t.Errorf("expected digest summary, got %q", msg)
+	}
+	if n.DigestSize() != 0 {
+		t.Errorf("expected digest to be cleared, got %d", n.DigestSize())
+	}
+}
+
+func TestNotifier_LoadDigest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "digest.json")
+	cfg := &config.NotificationConfig{
+		OnJobComplete: true,
+		Rules: []config.NotificationRule{
+			{Events: []string{"job_completed"}, Digest: true},
+		},
+	}
+
+	n := New(cfg)
+	if err := n.LoadDigest(path); err != nil {
+		t.Fatalf("unexpected error loading a missing digest: %v", err)
+	}
+	n.NotifyJobComplete("job-1", "Tidy docs", "vito", "", 1)
+
+	// A restarted daemon still holds it
+	restarted := New(cfg)
+	if err := restarted.LoadDigest(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if restarted.DigestSize() != 1 {
+		t.Fatalf("expected 1 held notification after a restart, got %d", restarted.DigestSize())
+	}
+
+	restarted.FlushDigest()
+	again := New(cfg)
+	if err := again.LoadDigest(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again.DigestSize() != 0 || again.digestSent == "" {
+		t.Errorf("expected a sent digest to stay sent, got %d held, sent %q", again.DigestSize(), again.digestSent)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := New(cfg).LoadDigest(path); err == nil {
+		t.Error("expected error loading a corrupt digest")
+	}
+}
+
+func TestNotifier_DigestDue(t *testing.T) {
+	n := New(&config.NotificationConfig{DigestTime: "18:30"})
+
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local)
+	if n.digestDue(day.Add(18 * time.Hour)) {
+		t.Error("digest should not be due before digest time")
+	}
+	if !n.digestDue(day.Add(18*time.Hour + 30*time.Minute)) {
+		t.Error("digest should be due at digest time")
+	}
+
+	n.digestSent = "2025-03-01"
+	if n.digestDue(day.Add(20 * time.Hour)) {
+		t.Error("digest should not be due twice in one day")
+	}
+}
@@ -0,0 +1,101 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// Desktop notification backends.
+const (
+	BackendTerminalNotifier = "terminal-notifier"
+	BackendOsascript        = "osascript"
+	BackendNotifySend       = "notify-send"
+)
+
+// ErrNoSystemNotifier is returned when no desktop notification backend is
+// available on this system.
+var ErrNoSystemNotifier = errors.New("no desktop notifier found")
+
+// SystemNotifier sends desktop notifications through a command of the
+// operating system: terminal-notifier or osascript on macOS, notify-send
+// on Linux.
+type SystemNotifier struct {
+	// Backend is the command used, one of the Backend* constants.
+	Backend string
+
+	path string
+}
+
+// DetectSystemNotifier returns the desktop notification backend for this
+// system. On macOS, terminal-notifier is preferred over osascript when it
+// is installed, since it can group notifications by worker.
+func DetectSystemNotifier() (*SystemNotifier, error) {
+	return detectSystemNotifier(runtime.GOOS, exec.LookPath)
+}
+
+func detectSystemNotifier(goos string, lookPath func(string) (string, error)) (*SystemNotifier, error) {
+	var candidates []string
+	switch goos {
+	case "darwin":
+		candidates = []string{BackendTerminalNotifier, BackendOsascript}
+	case "linux", "freebsd", "openbsd", "netbsd":
+		candidates = []string{BackendNotifySend}
+	default:
+		return nil, fmt.Errorf("%w: desktop notifications are not supported on %s", ErrNoSystemNotifier, goos)
+	}
+
+	for _, backend := range candidates {
+		if path, err := lookPath(backend); err == nil {
+			return &SystemNotifier{Backend: backend, path: path}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: install %s", ErrNoSystemNotifier, candidates[0])
+}
+
+// Send shows notif as a desktop notification.
+func (s *SystemNotifier) Send(notif Notification) error {
+	out, err := exec.Command(s.path, s.args(notif)...).CombinedOutput()
+	if err != nil && len(out) > 0 {
+		return fmt.Errorf("%s: %w: %s", s.Backend, err, out)
+	}
+	return err
+}
+
+// args returns the arguments to the backend's command for notif. The
+// worker's name is shown with the title, and notifications from the same
+// worker replace each other where the backend allows.
+func (s *SystemNotifier) args(notif Notification) []string {
+	title := "Cosa: " + notif.Title
+
+	switch s.Backend {
+	case BackendTerminalNotifier:
+		args := []string{"-title", title, "-message", notif.Message, "-sound", "Pop"}
+		if notif.WorkerName != "" {
+			args = append(args, "-subtitle", notif.WorkerName, "-group", "cosa-"+notif.WorkerName)
+		}
+		return args
+
+	case BackendOsascript:
+		script := fmt.Sprintf(`display notification %q with title %q`, notif.Message, title)
+		if notif.WorkerName != "" {
+			script += fmt.Sprintf(` subtitle %q`, notif.WorkerName)
+		}
+		return []string{"-e", script + ` sound name "Pop"`}
+
+	default:
+		if notif.WorkerName != "" {
+			title += " (" + notif.WorkerName + ")"
+		}
+		return []string{"--app-name", "Cosa", "--urgency", urgency(notif.Severity), title, notif.Message}
+	}
+}
+
+// urgency maps a notification severity to a notify-send urgency level.
+func urgency(severity string) string {
+	if severity == "error" {
+		return "critical"
+	}
+	return "normal"
+}
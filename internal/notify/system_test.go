@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDetectSystemNotifier(t *testing.T) {
+	installed := func(names ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			if slices.Contains(names, name) {
+				return "/usr/bin/" + name, nil
+			}
+			return "", errors.New("not found")
+		}
+	}
+
+	tests := []struct {
+		name      string
+		goos      string
+		installed []string
+		want      string
+	}{
+		{"macOS prefers terminal-notifier", "darwin", []string{BackendTerminalNotifier, BackendOsascript}, BackendTerminalNotifier},
+		{"macOS falls back to osascript", "darwin", []string{BackendOsascript}, BackendOsascript},
+		{"linux", "linux", []string{BackendNotifySend}, BackendNotifySend},
+		{"linux without notify-send", "linux", []string{BackendOsascript}, ""},
+		{"unsupported", "windows", []string{BackendNotifySend}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := detectSystemNotifier(tt.goos, installed(tt.installed...))
+			if tt.want == "" {
+				if !errors.Is(err, ErrNoSystemNotifier) {
+					t.Errorf("expected ErrNoSystemNotifier, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s.Backend != tt.want || s.path != "/usr/bin/"+tt.want {
+				t.Errorf("expected %s, got %s at %s", tt.want, s.Backend, s.path)
+			}
+		})
+	}
+}
+
+func TestSystemNotifier_Args(t *testing.T) {
+	notif := Notification{
+		Title:      "Job Failed",
+		Message:    `Fix "login": tests failed`,
+		WorkerName: "paulie",
+		Severity:   "error",
+	}
+
+	args := (&SystemNotifier{Backend: BackendNotifySend}).args(notif)
+	want := []string{"--app-name", "Cosa", "--urgency", "critical", "Cosa: Job Failed (paulie)", `Fix "login": tests failed`}
+	if !slices.Equal(args, want) {
+		t.Errorf("notify-send: expected %q, got %q", want, args)
+	}
+
+	args = (&SystemNotifier{Backend: BackendTerminalNotifier}).args(notif)
+	if i := slices.Index(args, "-group"); i < 0 || args[i+1] != "cosa-paulie" {
+		t.Errorf("terminal-notifier: expected notifications grouped by worker, got %q", args)
+	}
+
+	args = (&SystemNotifier{Backend: BackendOsascript}).args(notif)
+	if len(args) != 2 || !strings.Contains(args[1], `"Fix \"login\": tests failed"`) || !strings.Contains(args[1], `subtitle "paulie"`) {
+		t.Errorf("osascript: expected a quoted message and the worker as subtitle, got %q", args)
+	}
+
+	// Notifications not tied to a worker have no worker in the title
+	notif.WorkerName = ""
+	notif.Severity = "info"
+	args = (&SystemNotifier{Backend: BackendNotifySend}).args(notif)
+	if args[3] != "normal" || args[4] != "Cosa: Job Failed" {
+		t.Errorf("notify-send: expected normal urgency and a plain title, got %q", args)
+	}
+}
@@ -0,0 +1,88 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Lines renders the plan as a numbered list, one line per step plus one for
+// its dependencies, for display in chat clients and tool results.
+func (p *ChatPlan) Lines() []string {
+	if p == nil || len(p.Steps) == 0 {
+		return []string{"(no jobs planned yet)"}
+	}
+
+	lines := make([]string, 0, len(p.Steps)*2)
+	for i, step := range p.Steps {
+		details := []string{fmt.Sprintf("P%d", step.Priority)}
+		if step.Estimate != "" {
+			details = append(details, "~"+step.Estimate)
+		}
+		lines = append(lines, fmt.Sprintf("%d. %s: %s [%s]", i+1, step.Key, step.Description, strings.Join(details, ", ")))
+
+		if len(step.DependsOn) > 0 {
+			deps := make([]string, len(step.DependsOn))
+			for j, dep := range step.DependsOn {
+				// Existing jobs are referred to by full ID
+				if len(dep) > 8 && strings.Count(dep, "-") == 4 {
+					dep = "job " + dep[:8]
+				}
+				deps[j] = dep
+			}
+			lines = append(lines, "   after "+strings.Join(deps, ", "))
+		}
+	}
+	return lines
+}
+
+// ParsePlan reads a plan Claude wrote as JSON: an object with a steps array,
+// or the array alone. The JSON may be the whole reply or sit in a fenced
+// code block among other text; the last block that holds a plan wins, as
+// Claude may revise its answer.
+func ParsePlan(text string) (*ChatPlan, error) {
+	var candidates []string
+	rest := text
+	for {
+		start := strings.Index(rest, "```")
+		if start < 0 {
+			break
+		}
+		block := rest[start+3:]
+		end := strings.Index(block, "```")
+		if end < 0 {
+			break
+		}
+		// Drop the info string, such as json
+		if nl := strings.IndexByte(block[:end], '\n'); nl >= 0 {
+			candidates = append(candidates, block[nl+1:end])
+		}
+		rest = block[end+3:]
+	}
+	for i, j := 0, len(candidates)-1; i < j; i, j = i+1, j-1 {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	}
+
+	candidates = append(candidates, text)
+	if start := strings.IndexAny(text, "{["); start >= 0 {
+		if end := strings.LastIndexAny(text, "}]"); end > start {
+			candidates = append(candidates, text[start:end+1])
+		}
+	}
+
+	for _, candidate := range candidates {
+		candidate = strings.TrimSpace(candidate)
+		var plan ChatPlan
+		if strings.HasPrefix(candidate, "[") {
+			if json.Unmarshal([]byte(candidate), &plan.Steps) != nil {
+				continue
+			}
+		} else if json.Unmarshal([]byte(candidate), &plan) != nil {
+			continue
+		}
+		if len(plan.Steps) > 0 {
+			return &plan, nil
+		}
+	}
+	return nil, fmt.Errorf("no plan found in the reply")
+}
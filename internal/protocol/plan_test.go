@@ -0,0 +1,83 @@
+package protocol
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChatPlan_Lines(t *testing.T) {
+	plan := &ChatPlan{Steps: []PlanStep{
+		{Key: "schema", Description: "Add the users table", Priority: 2, Estimate: "1h"},
+		{Key: "api", Description: "Add the users endpoint", Priority: 3,
+			DependsOn: []string{"schema", "0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0"}},
+	}}
+
+	want := []string{
+		"1. schema: Add the users table [P2, ~1h]",
+		"2. api: Add the users endpoint [P3]",
+		"   after schema, job 0f1e2d3c",
+	}
+	if got := plan.Lines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	if got := (&ChatPlan{}).Lines(); len(got) != 1 {
+		t.Errorf("expected a placeholder line for an empty plan, got %q", got)
+	}
+}
+
+func TestParsePlan(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		keys []string
+	}{
+		{
+			name: "bare object",
+			text: `{"steps": [{"key": "schema", "description": "Add the table"}]}`,
+			keys: []string{"schema"},
+		},
+		{
+			name: "bare array",
+			text: `[{"key": "a", "description": "A"}, {"key": "b", "description": "B", "depends_on": ["a"]}]`,
+			keys: []string{"a", "b"},
+		},
+		{
+			name: "fenced among prose",
+			text: "Here is the breakdown:\n\n```json\n{\"steps\": [{\"key\": \"api\", \"description\": \"Add the endpoint\"}]}\n```\n\nCapisce?",
+			keys: []string{"api"},
+		},
+		{
+			name: "last fenced plan wins",
+			text: "```json\n{\"steps\": [{\"key\": \"old\", \"description\": \"Old\"}]}\n```\nOn second thought:\n```\n{\"steps\": [{\"key\": \"new\", \"description\": \"New\"}]}\n```",
+			keys: []string{"new"},
+		},
+		{
+			name: "unfenced after prose",
+			text: `Sure. {"steps": [{"key": "x", "description": "X"}]}`,
+			keys: []string{"x"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := ParsePlan(tt.text)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var keys []string
+			for _, step := range plan.Steps {
+				keys = append(keys, step.Key)
+			}
+			if !reflect.DeepEqual(keys, tt.keys) {
+				t.Errorf("expected steps %v, got %v", tt.keys, keys)
+			}
+		})
+	}
+
+	for _, text := range []string{"", "I could not break this down.", `{"steps": []}`, "```json\n{not json}\n```"} {
+		if _, err := ParsePlan(text); err == nil {
+			t.Errorf("expected an error for %q", text)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package protocol
 
 import (
 	"encoding/json"
+	"regexp"
+	"time"
 )
 
 // JSON-RPC 2.0 version constant
@@ -74,6 +76,13 @@ type Error struct {
 	Data    json.RawMessage `json:"data,omitempty"`
 }
 
+// QueueFullData is the data of an ErrQueueFull error: the job was refused
+// because the queue is full or its client is adding jobs too fast, and
+// may be added again after RetryAfter seconds.
+type QueueFullData struct {
+	RetryAfter int `json:"retry_after"`
+}
+
 // Standard JSON-RPC 2.0 error codes
 const (
 	ParseError     = -32700
@@ -95,6 +104,10 @@ const (
 	ErrGateFailed         = -32007
 	ErrMergeConflict      = -32008
 	ErrTemplateNotFound   = -32009
+	ErrJobRejected        = -32010
+	ErrUnauthorized       = -32011
+	ErrRequestCancelled   = -32012
+	ErrQueueFull          = -32013
 )
 
 // NewRequest creates a new JSON-RPC request.
@@ -163,6 +176,13 @@ const (
 	// Daemon lifecycle
 	MethodStatus   = "status"
 	MethodShutdown = "shutdown"
+	MethodOverview = "daemon.overview"
+
+	// Connection authorization
+	MethodAuthLogin = "auth.login"
+
+	// Abandoning a request still in progress
+	MethodCancel = "request.cancel"
 
 	// Territory management
 	MethodTerritoryInit         = "territory.init"
@@ -170,31 +190,68 @@ const (
 	MethodTerritoryList         = "territory.list"
 	MethodTerritoryAdd          = "territory.add"
 	MethodTerritorySetDevBranch = "territory.setDevBranch"
+	MethodTerritoryIndex        = "territory.index"
+	MethodTerritoryRemove       = "territory.remove"
 
 	// Worker management
-	MethodWorkerAdd     = "worker.add"
-	MethodWorkerList    = "worker.list"
-	MethodWorkerStatus  = "worker.status"
-	MethodWorkerRemove  = "worker.remove"
-	MethodWorkerMessage = "worker.message"
-	MethodWorkerDetail  = "worker.detail"
+	MethodWorkerAdd          = "worker.add"
+	MethodWorkerList         = "worker.list"
+	MethodWorkerStatus       = "worker.status"
+	MethodWorkerRemove       = "worker.remove"
+	MethodWorkerMessage      = "worker.message"
+	MethodWorkerDetail       = "worker.detail"
+	MethodWorkerTranscript   = "worker.transcript"
+	MethodWorkerExec         = "worker.exec"
+	MethodWorkerTakeover     = "worker.takeover"
+	MethodWorkerLabel        = "worker.label"
+	MethodWorkerUnquarantine = "worker.unquarantine"
+	MethodWorkerClone        = "worker.clone"
 
 	// Job management
-	MethodJobAdd         = "job.add"
-	MethodJobList        = "job.list"
-	MethodJobStatus      = "job.status"
-	MethodJobCancel      = "job.cancel"
-	MethodJobAssign      = "job.assign"
-	MethodJobReassign    = "job.reassign"
-	MethodJobSetPriority = "job.setPriority"
+	MethodJobAdd          = "job.add"
+	MethodJobList         = "job.list"
+	MethodJobStatus       = "job.status"
+	MethodJobCancel       = "job.cancel"
+	MethodJobAssign       = "job.assign"
+	MethodJobReassign     = "job.reassign"
+	MethodJobSetPriority  = "job.setPriority"
+	MethodJobConflicts    = "job.conflicts"
+	MethodJobMerge        = "job.merge"
+	MethodJobAddBatch     = "job.addBatch"
+	MethodJobImportStatus = "job.importStatus"
+	MethodJobImport       = "job.import"
+	MethodJobDiff         = "job.diff"
+	MethodJobWatch        = "job.watch"
+	MethodJobUnwatch      = "job.unwatch"
+	MethodJobUnstick      = "job.unstick"
+	MethodJobApprove      = "job.approve"
+	MethodJobRerun        = "job.rerun"
+	MethodJobDelegate     = "job.delegate"
+	MethodJobDecompose    = "job.decompose"
+
+	// Conflict triage
+	MethodConflictDetail    = "conflict.detail"
+	MethodConflictAssign    = "conflict.assign"
+	MethodConflictWorkspace = "conflict.workspace"
+	MethodConflictAbandon   = "conflict.abandon"
+
+	// Needs-attention triage
+	MethodTriageList    = "triage.list"
+	MethodTriageResolve = "triage.resolve"
 
 	// Queue management
 	MethodQueueStatus = "queue.status"
+	MethodQueuePause  = "queue.pause"
+	MethodQueueDrain  = "queue.drain"
+	MethodQueueResume = "queue.resume"
+	MethodMergeQueue  = "merge.queue"
 
 	// Review management
-	MethodReviewStart  = "review.start"
-	MethodReviewStatus = "review.status"
-	MethodReviewList   = "review.list"
+	MethodReviewStart   = "review.start"
+	MethodReviewStatus  = "review.status"
+	MethodReviewList    = "review.list"
+	MethodReviewApprove = "review.approve"
+	MethodReviewReject  = "review.reject"
 
 	// Operation management
 	MethodOperationCreate = "operation.create"
@@ -210,22 +267,106 @@ const (
 	// Handoff management
 	MethodHandoffGenerate = "handoff.generate"
 
+	// Worker capacity calendar
+	MethodCapacityOff    = "capacity.off"
+	MethodCapacityOn     = "capacity.on"
+	MethodCapacityLimit  = "capacity.limit"
+	MethodCapacityStatus = "capacity.status"
+
 	// Chat with underboss
-	MethodChatStart   = "chat.start"
-	MethodChatSend    = "chat.send"
-	MethodChatEnd     = "chat.end"
-	MethodChatHistory = "chat.history"
+	MethodChatStart      = "chat.start"
+	MethodChatSend       = "chat.send"
+	MethodChatEnd        = "chat.end"
+	MethodChatHistory    = "chat.history"
+	MethodChatMode       = "chat.mode"
+	MethodChatPlan       = "chat.plan"
+	MethodChatPlanAdd    = "chat.planAdd"
+	MethodChatPlanRemove = "chat.planRemove"
+	MethodChatExecute    = "chat.execute"
+	MethodChatDiscard    = "chat.discard"
 
 	// Template management
-	MethodTemplateList = "template.list"
-	MethodTemplateGet  = "template.get"
-	MethodTemplateUse  = "template.use"
+	MethodTemplateList   = "template.list"
+	MethodTemplateGet    = "template.get"
+	MethodTemplateUse    = "template.use"
+	MethodTemplateCreate = "template.create"
+	MethodTemplateRemove = "template.remove"
+	MethodTemplateExport = "template.export"
+	MethodTemplateImport = "template.import"
+
+	// Job presets
+	MethodPresetList = "preset.list"
+
+	// Event history
+	MethodLedgerQuery  = "ledger.query"
+	MethodLedgerVerify = "ledger.verify"
+
+	// Spend reporting
+	MethodCostsExport = "costs.export"
+
+	// Reloading the config file
+	MethodConfigReload = "config.reload"
+
+	// Resource cleanup
+	MethodCleanupRun = "cleanup.run"
+
+	// Debugging
+	MethodDebugScheduler = "debug.scheduler"
 
 	// Subscriptions for real-time updates
 	MethodSubscribe   = "subscribe"
 	MethodUnsubscribe = "unsubscribe"
+	MethodSyncState   = "sync.state"
 )
 
+// readOnlyMethods are the methods that do not change the daemon's state,
+// which observer connections may call.
+var readOnlyMethods = map[string]bool{
+	MethodStatus:           true,
+	MethodOverview:         true,
+	MethodAuthLogin:        true,
+	MethodCancel:           true,
+	MethodSubscribe:        true,
+	MethodUnsubscribe:      true,
+	MethodSyncState:        true,
+	MethodTerritoryStatus:  true,
+	MethodTerritoryList:    true,
+	MethodWorkerList:       true,
+	MethodWorkerStatus:     true,
+	MethodWorkerDetail:     true,
+	MethodWorkerTranscript: true,
+	MethodJobList:          true,
+	MethodJobStatus:        true,
+	MethodJobConflicts:     true,
+	MethodJobImportStatus:  true,
+	MethodJobDiff:          true,
+	MethodConflictDetail:   true,
+	MethodTriageList:       true,
+	MethodQueueStatus:      true,
+	MethodMergeQueue:       true,
+	MethodReviewStatus:     true,
+	MethodReviewList:       true,
+	MethodOperationStatus:  true,
+	MethodOperationList:    true,
+	MethodOrderList:        true,
+	MethodHandoffGenerate:  true,
+	MethodCapacityStatus:   true,
+	MethodChatHistory:      true,
+	MethodChatPlan:         true,
+	MethodTemplateList:     true,
+	MethodTemplateGet:      true,
+	MethodTemplateExport:   true,
+	MethodPresetList:       true,
+	MethodLedgerQuery:      true,
+	MethodCostsExport:      true,
+}
+
+// IsReadOnly reports whether method leaves the daemon's state unchanged.
+// Methods added without an entry here are treated as changing it.
+func IsReadOnly(method string) bool {
+	return readOnlyMethods[method]
+}
+
 // Notification types for real-time events
 const (
 	NotifyWorkerUpdated = "worker.updated"
@@ -245,6 +386,107 @@ type StatusResult struct {
 	Territory  string `json:"territory,omitempty"`
 	TotalCost  string `json:"total_cost,omitempty"`   // Cumulative cost
 	TotalTokens int   `json:"total_tokens,omitempty"` // Cumulative tokens
+	Budget     *BudgetStatus `json:"budget,omitempty"`
+	MergeQueue int    `json:"merge_queue,omitempty"` // Merges running or waiting their turn
+	Draining   bool   `json:"draining,omitempty"`    // Shutting down once running work finishes
+	Queue      string `json:"queue,omitempty"`       // "paused" or "draining"; empty while the queue runs
+
+	QueueETA int64 `json:"queue_eta,omitempty"` // Unix time the queue should be done, 0 if unknown
+
+	ReplayClock int64 `json:"replay_clock,omitempty"` // Unix time reached in a ledger being replayed; only set by a replay
+}
+
+// AuthLoginParams are parameters for auth.login.
+type AuthLoginParams struct {
+	Token   string `json:"token,omitempty"`   // One of the daemon's auth.tokens
+	Observe bool   `json:"observe,omitempty"` // Make the connection read-only whatever the token allows
+}
+
+// CancelParams are parameters for request.cancel, which the client sends as
+// a notification when it stops waiting for a request's response.
+type CancelParams struct {
+	ID *RequestID `json:"id" validate:"required"` // The request to abandon
+}
+
+// AuthLoginResult is the response for auth.login.
+type AuthLoginResult struct {
+	Name string `json:"name,omitempty"` // Who the token was given to
+	Role string `json:"role"`           // operator or observer
+}
+
+// ShutdownParams are parameters for shutdown.
+type ShutdownParams struct {
+	// Drain stops scheduling and shuts down once running jobs, reviews and
+	// merges have finished
+	Drain   bool `json:"drain,omitempty"`
+	Timeout int  `json:"timeout,omitempty" validate:"min=0"` // Seconds to drain before stopping anyway; 0 waits indefinitely
+}
+
+// ShutdownResult is the response for shutdown.
+type ShutdownResult struct {
+	Status string         `json:"status"` // "shutting_down" or "draining"
+	Drain  *DrainProgress `json:"drain,omitempty"`
+}
+
+// DrainProgress describes the work a draining daemon is waiting on. It is
+// also the data of the daemon.drain_progress and daemon.drained events.
+type DrainProgress struct {
+	Jobs     []DrainItem `json:"jobs"`    // Running, or assigned and about to run
+	Reviews  []DrainItem `json:"reviews"` // Jobs under review
+	Merges   int         `json:"merges"`  // Merges running or waiting their turn
+	Started  int64       `json:"started"` // When the drain began (Unix)
+	Deadline int64       `json:"deadline,omitempty"`
+	TimedOut bool        `json:"timed_out,omitempty"` // Set on daemon.drained when work was cut short
+}
+
+// DrainItem is a job a drain is waiting on.
+type DrainItem struct {
+	JobID       string `json:"job_id"`
+	Description string `json:"description"`
+	Worker      string `json:"worker,omitempty"`
+}
+
+// Remaining returns how many jobs, reviews and merges are still running.
+func (p *DrainProgress) Remaining() int {
+	return len(p.Jobs) + len(p.Reviews) + p.Merges
+}
+
+// BudgetStatus reports spend against the configured budget limits.
+// Limits of zero are disabled.
+type BudgetStatus struct {
+	SpentToday     float64 `json:"spent_today"`
+	DailyLimit     float64 `json:"daily_limit,omitempty"`
+	PerWorkerLimit float64 `json:"per_worker_limit,omitempty"`
+	PerJobLimit    float64 `json:"per_job_limit,omitempty"`
+	Exceeded       bool    `json:"exceeded"` // Daily limit reached; no new jobs start
+}
+
+// TerritoryPathParams are parameters for territory.add.
+type TerritoryPathParams struct {
+	Path string `json:"path,omitempty"` // Defaults to the daemon's working directory
+}
+
+// TerritoryInitParams are parameters for territory.init. Settings left
+// empty keep their defaults.
+type TerritoryInitParams struct {
+	Path         string `json:"path,omitempty"`        // Defaults to the daemon's working directory
+	BaseBranch   string `json:"base_branch,omitempty"` // Defaults to main or master
+	DevBranch    string `json:"dev_branch,omitempty"`
+	TestCommand  string `json:"test_command,omitempty"`
+	BuildCommand string `json:"build_command,omitempty"`
+	AutoReview   *bool  `json:"auto_review,omitempty"` // Defaults to true
+}
+
+// TerritoryInitResult is the result of territory.init.
+type TerritoryInitResult struct {
+	Status            string `json:"status"`
+	Path              string `json:"path"`
+	BaseBranch        string `json:"base_branch"`
+	DevBranch         string `json:"dev_branch,omitempty"`
+	MergeTargetBranch string `json:"merge_target_branch"`
+	TestCommand       string `json:"test_command,omitempty"`
+	BuildCommand      string `json:"build_command,omitempty"`
+	AutoReview        bool   `json:"auto_review"`
 }
 
 // TerritorySetDevBranchParams are parameters for territory.setDevBranch.
@@ -258,10 +500,57 @@ type TerritorySetDevBranchResult struct {
 	MergeTargetBranch string `json:"merge_target_branch"` // Effective merge target (dev or base)
 }
 
+// TerritoryIndexResult is the result of territory.index: the repository
+// index as rebuilt, and the map of it given to workers.
+type TerritoryIndexResult struct {
+	Path     string `json:"path"`
+	Commit   string `json:"commit"`
+	Files    int    `json:"files"`
+	Bytes    int64  `json:"bytes"`
+	Dirs     int    `json:"dirs"`
+	Modules  int    `json:"modules"`
+	Duration string `json:"duration"`
+	Map      string `json:"map,omitempty"` // Empty when index.prompt_bytes is 0
+}
+
+// TerritoryRemoveParams are parameters for territory.remove.
+type TerritoryRemoveParams struct {
+	Path   string `json:"path,omitempty"`    // Defaults to the active territory
+	Purge  bool   `json:"purge,omitempty"`   // Also delete .cosa and the territory's worktrees and branches
+	DryRun bool   `json:"dry_run,omitempty"` // Only report what would be removed
+}
+
+// TerritoryRemoveResult is the result of territory.remove: what was
+// removed, or with DryRun what would be.
+type TerritoryRemoveResult struct {
+	Path      string   `json:"path"`
+	Workers   []string `json:"workers,omitempty"`   // Workers removed with the territory
+	Worktrees []string `json:"worktrees,omitempty"` // Purged worktrees
+	Branches  []string `json:"branches,omitempty"`  // Purged branches
+	Queued    int      `json:"queued,omitempty"`    // Jobs left waiting for a territory
+	Purged    bool     `json:"purged,omitempty"`
+	DryRun    bool     `json:"dry_run,omitempty"`
+	Error     string   `json:"error,omitempty"` // What the purge could not remove
+}
+
 // WorkerAddParams are parameters for worker.add.
 type WorkerAddParams struct {
-	Name string `json:"name"`
-	Role string `json:"role,omitempty"` // defaults to "soldato"
+	Name   string            `json:"name" validate:"required"`
+	Role   string            `json:"role,omitempty"`   // defaults to "soldato"
+	Labels map[string]string `json:"labels,omitempty"` // Preferred for jobs with the same labels
+}
+
+// WorkerCloneParams are parameters for worker.clone.
+type WorkerCloneParams struct {
+	Source string `json:"source" validate:"required"` // Worker to copy
+	Name   string `json:"name" validate:"required"`   // Name of the new worker
+}
+
+// WorkerLabelParams are parameters for worker.label.
+type WorkerLabelParams struct {
+	Name   string            `json:"name" validate:"required"`
+	Set    map[string]string `json:"set,omitempty"`
+	Remove []string          `json:"remove,omitempty"`
 }
 
 // WorkerInfo describes a worker.
@@ -273,69 +562,866 @@ type WorkerInfo struct {
 	CurrentJob     string `json:"current_job,omitempty"`
 	CurrentJobDesc string `json:"current_job_desc,omitempty"`
 	Worktree       string `json:"worktree,omitempty"`
+
+	Labels map[string]string `json:"labels,omitempty"`
+
+	Quarantined bool `json:"quarantined,omitempty"` // The scheduler gives the worker no jobs until it is unquarantined
+
+	Slots     int `json:"slots,omitempty"`      // Jobs the worker runs at once, when more than one
+	BusySlots int `json:"busy_slots,omitempty"` // Slots running a job
+}
+
+// OverviewResult is the response for daemon.overview: what every worker is
+// doing, gathered in one call.
+type OverviewResult struct {
+	Workers []WorkerOverview `json:"workers"`
+	Time    int64            `json:"time"` // When the overview was taken (Unix), for the elapsed times
+}
+
+// WorkerOverview is a worker and, while it works, its job's progress.
+type WorkerOverview struct {
+	WorkerInfo
+
+	JobStatus  string `json:"job_status,omitempty"`
+	Elapsed    int64  `json:"elapsed,omitempty"`     // Seconds since the job started
+	Progress   string `json:"progress,omitempty"`    // Latest line of text from Claude
+	ProgressAt int64  `json:"progress_at,omitempty"` // When Progress was written (Unix)
+	Tool       string `json:"tool,omitempty"`        // Tool in use, empty between tools
+	ToolFor    int64  `json:"tool_for,omitempty"`    // Seconds the tool has been running
+	Cost       string `json:"cost,omitempty"`        // Spent on the job so far; a run's cost lands when it ends
 }
 
 // JobAddParams are parameters for job.add.
 type JobAddParams struct {
-	Description string   `json:"description"`
-	Priority    int      `json:"priority,omitempty"` // 1-5, default 3
-	Worker      string   `json:"worker,omitempty"`   // assign to specific worker
+	Description string   `json:"description" validate:"required"`
+	Body        string   `json:"body,omitempty"`                            // full specification; description is then the title
+	Priority    int      `json:"priority,omitempty" validate:"min=1,max=5"` // 1-5, default 3
+	Worker      string   `json:"worker,omitempty"`                          // assign to specific worker
 	DependsOn   []string `json:"depends_on,omitempty"`
+	Preset      string   `json:"preset,omitempty"` // named preset from config
+
+	Labels     map[string]string `json:"labels,omitempty"`
+	Model      string            `json:"model,omitempty"`
+	SkipReview bool              `json:"skip_review,omitempty"`
+	Timeout    int               `json:"timeout,omitempty" validate:"min=0"` // Seconds a run may take; 0 uses workers.job_timeout
+	Requester  string            `json:"requester,omitempty"`                // Who submitted the job, for the admission policy
+
+	// StickyWorker pins the job to a worker: it waits for that worker and
+	// resumes its Claude session rather than going to any idle worker
+	StickyWorker string `json:"sticky_worker,omitempty"`
+
+	// PlanFirst runs a plan phase that edits nothing, then waits for the
+	// plan to be approved with job.approve before executing it
+	PlanFirst bool `json:"plan_first,omitempty"`
+
+	// Draft holds the job as a draft until it is approved with job.approve
+	Draft bool `json:"draft,omitempty"`
+}
+
+// JobAddBatchParams are parameters for job.addBatch.
+type JobAddBatchParams struct {
+	Jobs []JobAddParams `json:"jobs" validate:"required,dive"`
+}
+
+// JobAddBatchResult is the response for job.addBatch. The IDs are
+// provisional: each becomes the job's ID once it is validated and created.
+type JobAddBatchResult struct {
+	BatchID string   `json:"batch_id"`
+	IDs     []string `json:"ids"` // In the order of the submitted jobs
+}
+
+// JobImportStatusParams are parameters for job.importStatus.
+type JobImportStatusParams struct {
+	BatchID string `json:"batch_id" validate:"required"`
+}
+
+// JobImportItem is the result for one job of a batch.
+type JobImportItem struct {
+	Index       int    `json:"index"`
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Status      string `json:"status"` // pending, created, failed
+	Error       string `json:"error,omitempty"`
+}
+
+// JobImportStatusResult is the response for job.importStatus.
+type JobImportStatusResult struct {
+	BatchID     string          `json:"batch_id"`
+	Status      string          `json:"status"` // running, completed
+	Total       int             `json:"total"`
+	Created     int             `json:"created"`
+	Failed      int             `json:"failed"`
+	Items       []JobImportItem `json:"items"`
+	SubmittedAt int64           `json:"submitted_at"`
+	CompletedAt int64           `json:"completed_at,omitempty"`
+}
+
+// ImportTask is a job read from a task list by job.import.
+type ImportTask struct {
+	Key         string            `json:"key,omitempty" yaml:"key"` // Short name other tasks refer to
+	Description string            `json:"description" yaml:"description" validate:"required"`
+	Body        string            `json:"body,omitempty" yaml:"body"`
+	Priority    int               `json:"priority,omitempty" yaml:"priority" validate:"min=1,max=5"`
+	Labels      map[string]string `json:"labels,omitempty" yaml:"labels"`
+	DependsOn   []string          `json:"depends_on,omitempty" yaml:"depends_on"` // Task keys or existing job IDs
+	Section     string            `json:"section,omitempty" yaml:"section"`       // Operation the job is added to
+}
+
+// JobImportParams are parameters for job.import.
+type JobImportParams struct {
+	Tasks  []ImportTask `json:"tasks" validate:"required,dive"`
+	Source string       `json:"source,omitempty"`  // File the tasks were read from
+	DryRun bool         `json:"dry_run,omitempty"` // Validate and report without creating anything
+
+	Requester string `json:"requester,omitempty"` // Who submitted the jobs, for the admission policy
+}
+
+// ImportedJob is a job created, or with DryRun to be created, by job.import.
+type ImportedJob struct {
+	Key         string   `json:"key"`
+	ID          string   `json:"id,omitempty"`
+	Description string   `json:"description"`
+	Priority    int      `json:"priority,omitempty"`
+	Section     string   `json:"section,omitempty"`
+	DependsOn   []string `json:"depends_on,omitempty"` // As given in the task list
+}
+
+// ImportedOperation is an operation created from a task list section.
+type ImportedOperation struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+	Jobs int    `json:"jobs"`
+}
+
+// JobImportResult is the response for job.import. Jobs are listed in the
+// order they were created, after the jobs they depend on.
+type JobImportResult struct {
+	DryRun     bool                `json:"dry_run,omitempty"`
+	Jobs       []ImportedJob       `json:"jobs"`
+	Operations []ImportedOperation `json:"operations,omitempty"`
 }
 
 // JobInfo describes a job.
 type JobInfo struct {
 	ID          string   `json:"id"`
 	Description string   `json:"description"`
+	Body        string   `json:"body,omitempty"`
 	Status      string   `json:"status"`
 	Priority    int      `json:"priority"`
 	Worker      string   `json:"worker,omitempty"`
 	DependsOn   []string `json:"depends_on,omitempty"`
 	CreatedAt   int64    `json:"created_at"`
+	QueuedAt    int64    `json:"queued_at,omitempty"`
 	StartedAt   int64    `json:"started_at,omitempty"`
 	CompletedAt int64    `json:"completed_at,omitempty"`
+	Worktree    string   `json:"worktree,omitempty"`
+	Branch      string   `json:"branch,omitempty"`
+	Error       string   `json:"error,omitempty"`
+
+	Labels     map[string]string `json:"labels,omitempty"`
+	Model      string            `json:"model,omitempty"`
+	ModelUsed  string            `json:"model_used,omitempty"` // Model the last run ran on, after any fallback
+	SkipReview bool              `json:"skip_review,omitempty"`
+	NoMerge    bool              `json:"no_merge,omitempty"`
+
+	Unmerged         bool   `json:"unmerged,omitempty"`
+	ResolvesConflict string `json:"resolves_conflict,omitempty"`
+	AttentionKind    string `json:"attention_kind,omitempty"`
+	AttentionReason  string `json:"attention_reason,omitempty"`
+	AttentionSince   int64  `json:"attention_since,omitempty"`
+	RemoteBranch     string `json:"remote_branch,omitempty"`
+	PullRequest      string `json:"pull_request,omitempty"`
+
+	ReviewChecklist  []string          `json:"review_checklist,omitempty"`
+	ChecklistResults []ChecklistResult `json:"checklist_results,omitempty"`
+	RevisionOf       string            `json:"revision_of,omitempty"`
+	RevisionRound    int               `json:"revision_round,omitempty"`
+	StickyWorker     string            `json:"sticky_worker,omitempty"` // Worker the job is pinned to
+
+	// Jobs this job reruns and that rerun it, for jobs started with job.rerun
+	RerunOf string   `json:"rerun_of,omitempty"`
+	Reruns  []string `json:"reruns,omitempty"`
+
+	// Chain of jobs that delegated this job, outermost first, and the jobs
+	// it delegated, for jobs created with job.delegate
+	DelegatedBy []DelegationStep `json:"delegated_by,omitempty"`
+	Delegated   []string         `json:"delegated,omitempty"`
+
+	// Template the job was created from and the variables it was expanded with
+	Template     string            `json:"template,omitempty"`
+	TemplateVars map[string]string `json:"template_vars,omitempty"`
+
+	PlanFirst    bool   `json:"plan_first,omitempty"`    // Plans and waits for approval before executing
+	Plan         string `json:"plan,omitempty"`          // Plan from the plan phase
+	PlanApproved bool   `json:"plan_approved,omitempty"` // The plan was approved for execution
+
+	Snapshot *SnapshotInfo `json:"snapshot,omitempty"`
+	Verdict  *VerdictInfo  `json:"verdict,omitempty"` // Of the job's last review
+
+	Timeout  int64 `json:"timeout,omitempty"`  // Seconds a run may take, including the default
+	Deadline int64 `json:"deadline,omitempty"` // When the running job times out (Unix)
+	Timeouts int   `json:"timeouts,omitempty"` // Runs that timed out
+
+	// Set by job.list when the job is on the requesting identity's watch list
+	Watched bool `json:"watched,omitempty"`
+
+	// Set by job.status once nothing more will happen to the job unasked:
+	// it failed or was cancelled, or it completed and its worker is done
+	// with it, merge and review included
+	Settled bool `json:"settled,omitempty"`
+}
+
+// SnapshotInfo describes uncommitted changes a worker left in a job worktree
+// and whether they were committed or failed the job.
+type SnapshotInfo struct {
+	Action string   `json:"action"` // committed, failed
+	Commit string   `json:"commit,omitempty"`
+	Files  []string `json:"files"`
+}
+
+// VerdictInfo is what the last review of a job concluded.
+type VerdictInfo struct {
+	Decision string   `json:"decision"` // approved or rejected
+	Summary  string   `json:"summary,omitempty"`
+	MustFix  []string `json:"must_fix,omitempty"`
+}
+
+// ChecklistResult is a reviewer's verdict on one review checklist item.
+type ChecklistResult struct {
+	Item   string `json:"item"`
+	Passed bool   `json:"passed"`
+	Note   string `json:"note,omitempty"`
+}
+
+// LedgerQueryParams are parameters for ledger.query.
+type LedgerQueryParams struct {
+	Since  int64    `json:"since,omitempty" validate:"min=0"` // Unix time; events at or after
+	Until  int64    `json:"until,omitempty" validate:"min=0"` // Unix time; events before
+	Types  []string `json:"types,omitempty"`                  // Event types; "job.*" matches a prefix
+	Worker string   `json:"worker,omitempty"`                 // Worker name or ID
+	Before string   `json:"before,omitempty"`                 // Event ID to page backwards from
+	Limit  int      `json:"limit,omitempty" validate:"min=1"` // Newest events kept; default 100
+	Level  string   `json:"level,omitempty"`                  // Lowest severity kept: info, warn or error
+}
+
+// Validate checks that the time range is not reversed.
+func (p *LedgerQueryParams) Validate() error {
+	if p.Since > 0 && p.Until > 0 && p.Until < p.Since {
+		return &ValidationError{Fields: []FieldError{{Field: "until", Message: "must not be before since"}}}
+	}
+	return nil
+}
+
+// LedgerQueryResult is the response for ledger.query.
+type LedgerQueryResult struct {
+	Events []LedgerEvent `json:"events"` // Oldest first
+	More   bool          `json:"more"`   // Older matching events exist
+}
+
+// LedgerVerifyParams are parameters for ledger.verify.
+type LedgerVerifyParams struct {
+	Repair bool `json:"repair,omitempty"` // Quarantine truncated, corrupt and duplicate lines
+}
+
+// LedgerProblem is a problem found on one line of the ledger.
+type LedgerProblem struct {
+	Line   int    `json:"line"`
+	Kind   string `json:"kind"` // truncated, corrupt, duplicate or out_of_order
+	Detail string `json:"detail"`
+}
+
+// LedgerVerifyResult is the response for ledger.verify. It describes the
+// ledger as it was before any repair.
+type LedgerVerifyResult struct {
+	Path     string          `json:"path"`
+	Size     int64           `json:"size"`
+	Lines    int             `json:"lines"`
+	Events   int             `json:"events"`
+	First    int64           `json:"first,omitempty"` // Unix time of the first event
+	Last     int64           `json:"last,omitempty"`  // Unix time of the latest event
+	Healthy  bool            `json:"healthy"`
+	Problems []LedgerProblem `json:"problems,omitempty"`
+
+	Quarantined    int    `json:"quarantined,omitempty"` // Lines moved out by a repair
+	QuarantinePath string `json:"quarantine_path,omitempty"`
+}
+
+// CostsExportParams are parameters for costs.export. Days are local dates
+// and the range includes both ends.
+type CostsExportParams struct {
+	From   string `json:"from,omitempty"`   // YYYY-MM-DD; from the first record when empty
+	To     string `json:"to,omitempty"`     // YYYY-MM-DD; up to the latest record when empty
+	Worker string `json:"worker,omitempty"` // Only this worker's spend
+}
+
+// Validate checks that the days are dates and the range is not reversed.
+func (p *CostsExportParams) Validate() error {
+	var fields []FieldError
+	for _, day := range []struct{ field, value string }{{"from", p.From}, {"to", p.To}} {
+		if day.value == "" {
+			continue
+		}
+		if _, err := time.Parse(time.DateOnly, day.value); err != nil {
+			fields = append(fields, FieldError{Field: day.field, Message: "must be a date (YYYY-MM-DD)"})
+		}
+	}
+	if len(fields) == 0 && p.From != "" && p.To != "" && p.To < p.From {
+		fields = append(fields, FieldError{Field: "to", Message: "must not be before from"})
+	}
+	if len(fields) > 0 {
+		return &ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// CostExportRow is what one worker spent on one job on one day.
+type CostExportRow struct {
+	Day     string  `json:"day"` // YYYY-MM-DD
+	Worker  string  `json:"worker"`
+	JobID   string  `json:"job_id"`
+	JobDesc string  `json:"job_description,omitempty"`
+	Cost    float64 `json:"cost"` // Dollars
+	Tokens  int     `json:"tokens"`
+}
+
+// CostsExportResult is the response for costs.export.
+type CostsExportResult struct {
+	From        string          `json:"from,omitempty"`
+	To          string          `json:"to,omitempty"`
+	Rows        []CostExportRow `json:"rows"` // By day, then worker, then job
+	TotalCost   float64         `json:"total_cost"`
+	TotalTokens int             `json:"total_tokens"`
+}
+
+// ConfigReloadResult is the response for config.reload.
+type ConfigReloadResult struct {
+	Applied []ConfigChange `json:"applied,omitempty"` // Settings changed that are now in effect
+	Pending []ConfigChange `json:"pending,omitempty"` // Settings changed that take effect on restart
+}
+
+// ConfigChange is a setting whose value changed in the config file. Values
+// of secrets are left out.
+type ConfigChange struct {
+	Key string `json:"key"`
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+}
+
+// CleanupRunParams are parameters for cleanup.run.
+type CleanupRunParams struct {
+	DryRun bool `json:"dry_run,omitempty"` // Report what would be removed without removing it
+}
+
+// CleanupItem is a worktree, branch or session removed by cleanup.
+type CleanupItem struct {
+	Kind     string `json:"kind"`   // worktree, branch or session
+	Target   string `json:"target"` // Worktree path, branch name or session ID
+	JobID    string `json:"job_id,omitempty"`
+	Worker   string `json:"worker,omitempty"`
+	Size     int64  `json:"size,omitempty"`      // Bytes on disk, for worktrees
+	LastUsed int64  `json:"last_used,omitempty"` // Unix time
+	Reason   string `json:"reason"`
+}
+
+// CleanupRunResult is the response for cleanup.run.
+type CleanupRunResult struct {
+	DryRun     bool          `json:"dry_run,omitempty"`
+	Items      []CleanupItem `json:"items"` // Removed, or that would be on a dry run
+	BytesFreed int64         `json:"bytes_freed"`
+	Errors     []string      `json:"errors,omitempty"`
+}
+
+// DebugSchedulerParams are parameters for debug.scheduler.
+type DebugSchedulerParams struct {
+	Enable *bool  `json:"enable,omitempty"` // Turn the decision trace on or off
+	Job    string `json:"job,omitempty"`    // Only passes that considered this job
+	Limit  int    `json:"limit,omitempty"`  // Only the latest passes
+}
+
+// SchedulerWorkerEval is how a worker fared when the scheduler looked for
+// one to take a job.
+type SchedulerWorkerEval struct {
+	Worker string `json:"worker"`
+	Score  int    `json:"score,omitempty"`  // Higher is better, for candidates
+	Reason string `json:"reason,omitempty"` // Why it was passed over
+}
+
+// SchedulerJobDecision is what the scheduler decided for a ready job.
+type SchedulerJobDecision struct {
+	Job         string                `json:"job"`
+	Description string                `json:"description"`
+	Assigned    string                `json:"assigned,omitempty"` // Worker name, when assigned
+	Reason      string                `json:"reason,omitempty"`   // Why it was not assigned
+	Workers     []SchedulerWorkerEval `json:"workers,omitempty"`
+}
+
+// SchedulerTick is one pass of the scheduler over the ready jobs. Passes that
+// decided the same as the one before are folded into it.
+type SchedulerTick struct {
+	Time     time.Time              `json:"time"`
+	LastTime time.Time              `json:"last_time,omitempty"` // Of the latest pass folded into this one
+	Repeats  int                    `json:"repeats,omitempty"`   // Passes folded into this one
+	Skipped  string                 `json:"skipped,omitempty"`   // Why the pass considered no jobs
+	Jobs     []SchedulerJobDecision `json:"jobs,omitempty"`
+}
+
+// DebugSchedulerResult is the response for debug.scheduler.
+type DebugSchedulerResult struct {
+	Enabled bool            `json:"enabled"`
+	Size    int             `json:"size"`  // Passes the trace keeps
+	Ticks   []SchedulerTick `json:"ticks"` // Oldest first
+}
+
+// LedgerEvent is an event recorded in the ledger.
+type LedgerEvent struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	Timestamp time.Time       `json:"timestamp"`
+	Data      json.RawMessage `json:"data,omitempty"`
+}
+
+// PresetInfo describes a job preset.
+type PresetInfo struct {
+	Name       string            `json:"name"`
+	Priority   int               `json:"priority,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	SkipReview bool              `json:"skip_review,omitempty"`
+	Model      string            `json:"model,omitempty"`
+	Worker     string            `json:"worker,omitempty"`
+}
+
+// PresetListResult is the response for preset.list.
+type PresetListResult struct {
+	Presets []PresetInfo `json:"presets"`
 }
 
 // SubscribeParams for subscribing to events.
 type SubscribeParams struct {
 	Events []string `json:"events"` // event types to subscribe to, or ["*"] for all
+
+	// Filter narrows the events further, so that a client following a busy
+	// ledger is only sent what it will show
+	Filter *LogFilter `json:"filter,omitempty"`
+}
+
+// LogFilter selects ledger events. Zero fields match everything.
+type LogFilter struct {
+	Types   []string `json:"types,omitempty"`   // Event types to include; "job.*" matches a prefix
+	Exclude []string `json:"exclude,omitempty"` // Event types to leave out, matched like Types
+	Worker  string   `json:"worker,omitempty"`  // Worker name or ID
+	Job     string   `json:"job,omitempty"`     // Job ID or prefix
+	Grep    string   `json:"grep,omitempty"`    // Regular expression matched against the type and data
+	Level   string   `json:"level,omitempty"`   // Lowest severity shown: info, warn or error
+}
+
+// Validate checks that the filter's pattern compiles.
+func (p *SubscribeParams) Validate() error {
+	if p.Filter == nil || p.Filter.Grep == "" {
+		return nil
+	}
+	if _, err := regexp.Compile(p.Filter.Grep); err != nil {
+		return &ValidationError{Fields: []FieldError{{Field: "filter.grep", Message: err.Error()}}}
+	}
+	return nil
+}
+
+// SyncStateParams are parameters for sync.state. A client that already
+// holds a snapshot passes back its epoch and version.
+type SyncStateParams struct {
+	Epoch    int64  `json:"epoch,omitempty"`
+	Version  uint64 `json:"version,omitempty"`
+	Identity string `json:"identity,omitempty"` // Marks the jobs this identity watches
+}
+
+// SyncStateResult is a snapshot of the daemon's state, for a client to
+// apply when it (re)connects instead of replaying the ledger. Clients
+// subscribe first, then apply the snapshot and the events that follow;
+// events already reflected in the snapshot are safe to apply again.
+type SyncStateResult struct {
+	Epoch     int64  `json:"epoch"`               // Daemon start time in unix nanoseconds; changes when it restarts
+	Version   uint64 `json:"version"`             // Ledger events recorded since the daemon started
+	Unchanged bool   `json:"unchanged,omitempty"` // The client's version is current; nothing else is set
+
+	Status  *StatusResult        `json:"status,omitempty"`
+	Workers []WorkerInfo         `json:"workers,omitempty"`
+	Jobs    []JobInfo            `json:"jobs,omitempty"`
+	Reviews []ReviewStatusResult `json:"reviews,omitempty"`
+	Alerts  []SyncAlert          `json:"alerts,omitempty"`
+}
+
+// Kinds of SyncAlert.
+const (
+	AlertAttention = "attention" // A job needs a human
+	AlertStuck     = "stuck"     // The lookout flagged a worker as stuck
+	AlertBudget    = "budget"    // The daily budget is spent
+)
+
+// SyncAlert is a condition waiting for the user's attention.
+type SyncAlert struct {
+	Kind     string `json:"kind"`
+	Subject  string `json:"subject,omitempty"` // Job ID or worker name
+	Message  string `json:"message"`
+	Severity string `json:"severity,omitempty"`
+	Since    int64  `json:"since,omitempty"`
 }
 
 // WorkerDetailInfo provides detailed information about a worker.
 type WorkerDetailInfo struct {
-	ID            string `json:"id"`
-	Name          string `json:"name"`
-	Role          string `json:"role"`
-	Status        string `json:"status"`
-	CurrentJob    string `json:"current_job,omitempty"`
-	Worktree      string `json:"worktree,omitempty"`
-	Branch        string `json:"branch,omitempty"`
-	SessionID     string `json:"session_id,omitempty"`
-	JobsCompleted int    `json:"jobs_completed"`
-	JobsFailed    int    `json:"jobs_failed"`
-	TotalCost     string `json:"total_cost,omitempty"`
-	TotalTokens   int    `json:"total_tokens,omitempty"`
-	CreatedAt     int64  `json:"created_at"`
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	Role              string `json:"role"`
+	Status            string `json:"status"`
+	CurrentJob        string `json:"current_job,omitempty"`
+	Worktree          string `json:"worktree,omitempty"`
+	Branch            string `json:"branch,omitempty"`
+	SessionID         string `json:"session_id,omitempty"`
+	SessionTokens     int    `json:"session_tokens,omitempty"`      // Tokens the session has used across its runs
+	PreviousSessionID string `json:"previous_session_id,omitempty"` // Session the current one replaced at a rollover
+	JobsCompleted     int    `json:"jobs_completed"`
+	JobsFailed        int    `json:"jobs_failed"`
+	TotalCost         string `json:"total_cost,omitempty"`
+	TotalTokens       int    `json:"total_tokens,omitempty"`
+	CreatedAt         int64  `json:"created_at"`
+
+	Labels map[string]string `json:"labels,omitempty"`
+
+	FailureStreak int             `json:"failure_streak,omitempty"` // Jobs failed in a row since the last completed one
+	Quarantine    *QuarantineInfo `json:"quarantine,omitempty"`     // Set while the scheduler gives the worker no jobs
+
+	GitAuthor  string `json:"git_author,omitempty"`  // Who the worker's commits are made as; empty leaves it to git
+	GitSigning string `json:"git_signing,omitempty"` // "gpg" or "ssh" when its commits are signed
+
+	LastActivity int64  `json:"last_activity,omitempty"` // Unix time of the last event from Claude
+	LastProgress int64  `json:"last_progress,omitempty"` // Unix time of the last tool finished, file written or commit
+	Progress     string `json:"progress,omitempty"`      // What the last progress was
+
+	Slots []SlotDetail `json:"slots,omitempty"` // Set when the worker runs more than one job at once
+}
+
+// SlotDetail describes one of the jobs a worker runs at once.
+type SlotDetail struct {
+	Index     int    `json:"index"`
+	Status    string `json:"status"` // "busy" or "free"
+	JobID     string `json:"job_id,omitempty"`
+	JobDesc   string `json:"job_desc,omitempty"`
+	SessionID string `json:"session_id,omitempty"` // Session of the running job, or of the slot's last job
+	Progress  string `json:"progress,omitempty"`   // Latest line of text from Claude
+	Tool      string `json:"tool,omitempty"`       // Tool in use
+	Cost      string `json:"cost,omitempty"`       // Spent on the running job
+	Tokens    int    `json:"tokens,omitempty"`     // Used by the slot's jobs
+	JobsRun   int    `json:"jobs_run"`
+}
+
+// QuarantineInfo describes a worker quarantined after failing too many
+// jobs in a row.
+type QuarantineInfo struct {
+	Since    int64    `json:"since"`    // Unix time
+	Failures []string `json:"failures"` // Signatures of the failures, oldest first
+}
+
+// WorkerTranscriptParams are parameters for worker.transcript.
+type WorkerTranscriptParams struct {
+	Name   string `json:"name" validate:"required"`
+	JobID  string `json:"job_id,omitempty"`                  // Defaults to the worker's current or most recent job
+	Offset int    `json:"offset,omitempty" validate:"min=0"` // Return only entries after this offset
+}
+
+// TranscriptEntry is a single event from a job's Claude session.
+type TranscriptEntry struct {
+	Time    int64  `json:"time"`
+	Type    string `json:"type"` // assistant_text, tool_use, tool_result, result, error, ...
+	Message string `json:"message,omitempty"`
+	Tool    string `json:"tool,omitempty"`
+	Input   string `json:"input,omitempty"`
+	Output  string `json:"output,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// WorkerTranscriptResult is the result of worker.transcript.
+type WorkerTranscriptResult struct {
+	Worker  string            `json:"worker"`
+	JobID   string            `json:"job_id,omitempty"`
+	Entries []TranscriptEntry `json:"entries"`
+	Next    int               `json:"next"`   // Offset for the next incremental read
+	Active  bool              `json:"active"` // Whether the job is still running
+}
+
+// WorkerNameParams are parameters for worker.status and worker.detail.
+type WorkerNameParams struct {
+	Name string `json:"name" validate:"required"` // Worker name or ID
+}
+
+// WorkerRemoveParams are parameters for worker.remove.
+type WorkerRemoveParams struct {
+	Name      string `json:"name" validate:"required"`
+	Force     bool   `json:"force,omitempty"`
+	Interview *bool  `json:"interview,omitempty"` // Defaults to workers.exit_interview
+}
+
+// WorkerMessageParams are parameters for worker.message.
+type WorkerMessageParams struct {
+	Name    string `json:"name" validate:"required"` // Worker name or ID
+	Message string `json:"message" validate:"required"`
+}
+
+// WorkerExecParams are parameters for worker.exec.
+type WorkerExecParams struct {
+	Description string `json:"description" validate:"required"`
+	Body        string `json:"body,omitempty"`     // Full prompt when the description is a title
+	Name        string `json:"name,omitempty"`     // Defaults to the first free associate-N
+	Model       string `json:"model,omitempty"`    // Overrides the configured model
+	NoMerge     bool   `json:"no_merge,omitempty"` // Keep the branch instead of merging it
+}
+
+// WorkerExecResult is the result of worker.exec.
+type WorkerExecResult struct {
+	Worker string `json:"worker"`
+	JobID  string `json:"job_id"`
+}
+
+// JobListParams are parameters for job.list.
+type JobListParams struct {
+	Identity string   `json:"identity,omitempty"` // Marks the jobs this identity watches
+	Labels   []string `json:"labels,omitempty"`   // Only jobs matching every filter: key=value, or key for any value
+}
+
+// JobWatchParams are parameters for job.watch and job.unwatch.
+type JobWatchParams struct {
+	ID       string `json:"id" validate:"required"`
+	Identity string `json:"identity" validate:"required"`
+}
+
+// JobWatchResult is the response for job.watch and job.unwatch.
+type JobWatchResult struct {
+	ID       string `json:"id"`
+	Watching bool   `json:"watching"`
+	Changed  bool   `json:"changed"` // False if the job was already (un)watched
+}
+
+// JobStatusParams are parameters for job.status.
+type JobStatusParams struct {
+	ID string `json:"id" validate:"required"`
+}
+
+// JobCancelParams are parameters for job.cancel.
+type JobCancelParams struct {
+	ID      string `json:"id" validate:"required"`
+	GraceMS int    `json:"grace_ms,omitempty" validate:"min=0"` // Milliseconds to wait after the interrupt before killing Claude
+}
+
+// ConflictInfo describes a job whose branch could not be merged.
+type ConflictInfo struct {
+	JobID          string `json:"job_id"`
+	Description    string `json:"description"`
+	Branch         string `json:"branch"`
+	Target         string `json:"target"`
+	ResolverJob    string `json:"resolver_job,omitempty"`
+	ResolverStatus string `json:"resolver_status,omitempty"`
+	CompletedAt    int64  `json:"completed_at,omitempty"`
+}
+
+// JobDiffParams are parameters for job.diff.
+type JobDiffParams struct {
+	ID string `json:"id" validate:"required"`
+}
+
+// JobDiffResult is the response for job.diff: the job's changes against the
+// merge target since they diverged.
+type JobDiffResult struct {
+	ID        string   `json:"id"`
+	Target    string   `json:"target"`
+	Files     []string `json:"files"`
+	Additions int      `json:"additions"`
+	Deletions int      `json:"deletions"`
+	Diff      string   `json:"diff"`
+}
+
+// JobMergeParams are parameters for job.merge.
+type JobMergeParams struct {
+	ID    string `json:"id" validate:"required"`
+	Retry bool   `json:"retry,omitempty"` // Attempt the merge; otherwise only check for conflicts
+}
+
+// JobMergeResult is the response for job.merge.
+type JobMergeResult struct {
+	ID        string `json:"id"`
+	Branch    string `json:"branch"`
+	Target    string `json:"target"`
+	Merged    bool   `json:"merged"`
+	Conflicts bool   `json:"conflicts"`
+	Commit    string `json:"commit,omitempty"`
+
+	// Set when git.remote_mode publishes the branch instead of merging it
+	RemoteBranch string `json:"remote_branch,omitempty"`
+	PullRequest  string `json:"pull_request,omitempty"`
+}
+
+// ConflictParams identify the unmerged job for conflict.detail,
+// conflict.workspace and conflict.abandon.
+type ConflictParams struct {
+	ID     string `json:"id" validate:"required"`
+	Reason string `json:"reason,omitempty"` // Why the merge was abandoned
+}
+
+// ConflictFile is a conflicted file with what each side changed.
+type ConflictFile struct {
+	Path   string `json:"path"`
+	Target string `json:"target,omitempty"` // Diff of the target branch since the merge base
+	Branch string `json:"branch,omitempty"` // Diff of the job branch since the merge base
+}
+
+// ConflictDetail is the response for conflict.detail.
+type ConflictDetail struct {
+	ConflictInfo
+	Files     []ConflictFile `json:"files"`
+	Workspace string         `json:"workspace,omitempty"` // Worktree with the merge in progress
+}
+
+// ConflictAssignParams are parameters for conflict.assign.
+type ConflictAssignParams struct {
+	ID     string `json:"id" validate:"required"`
+	Worker string `json:"worker,omitempty"` // Worker name; defaults to the normal assignment
+}
+
+// ConflictAssignResult is the response for conflict.assign.
+type ConflictAssignResult struct {
+	ID          string `json:"id"`
+	ResolverJob string `json:"resolver_job"`
+	Worker      string `json:"worker,omitempty"`
+}
+
+// ConflictWorkspaceResult is the response for conflict.workspace.
+type ConflictWorkspaceResult struct {
+	ID    string   `json:"id"`
+	Path  string   `json:"path"`
+	Files []string `json:"files"` // Files with conflict markers
+}
+
+// TriageItem is a job waiting on a human.
+type TriageItem struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Kind        string `json:"kind"` // merge_conflict, review_rejected, question or gate_override
+	Reason      string `json:"reason"`
+	Since       int64  `json:"since"`
+	Worker      string `json:"worker,omitempty"`
+	Priority    int    `json:"priority"`
+}
+
+// TriageListResult is the response for triage.list, oldest first.
+type TriageListResult struct {
+	Items []TriageItem `json:"items"`
+}
+
+// Triage actions for triage.resolve.
+const (
+	TriageRetry   = "retry"   // Run the job again, with the note appended to its body
+	TriageAccept  = "accept"  // Override and mark the job completed
+	TriageDismiss = "dismiss" // Give up and mark the job failed
+)
+
+// TriageResolveParams are parameters for triage.resolve.
+type TriageResolveParams struct {
+	ID     string `json:"id" validate:"required"`
+	Action string `json:"action" validate:"required,oneof=retry accept dismiss"`
+	Note   string `json:"note,omitempty"` // Answer or instructions for a retry, reason for a dismissal
 }
 
 // JobAssignParams are parameters for job.assign.
 type JobAssignParams struct {
-	JobID    string `json:"job_id"`
-	WorkerID string `json:"worker_id"`
+	JobID    string `json:"job_id" validate:"required"`
+	WorkerID string `json:"worker_id" validate:"required"`
 }
 
 // JobReassignParams are parameters for job.reassign.
 // Reassigns a failed job to be retried. If WorkerID is empty, the job is
 // re-queued for automatic scheduling.
 type JobReassignParams struct {
-	JobID    string `json:"job_id"`
+	JobID    string `json:"job_id" validate:"required"`
 	WorkerID string `json:"worker_id,omitempty"` // Optional: specific worker, or auto-assign
 }
 
+// JobUnstickParams are parameters for job.unstick.
+type JobUnstickParams struct {
+	ID string `json:"id" validate:"required"`
+}
+
+// JobUnstickResult is the response for job.unstick.
+type JobUnstickResult struct {
+	ID     string `json:"id"`
+	Worker string `json:"worker,omitempty"` // The worker the job was pinned to, if any
+}
+
+// JobApproveParams are parameters for job.approve.
+type JobApproveParams struct {
+	ID string `json:"id" validate:"required"`
+}
+
+// JobRerunParams are parameters for job.rerun.
+type JobRerunParams struct {
+	ID       string `json:"id" validate:"required"`
+	Worker   string `json:"worker,omitempty"`                          // Assign the rerun to this worker
+	Priority int    `json:"priority,omitempty" validate:"min=1,max=5"` // Overrides the original's priority
+}
+
+// JobRerunResult is the response for job.rerun.
+type JobRerunResult struct {
+	Job JobInfo `json:"job"`
+
+	// Dependencies of the original the rerun does not wait on, because
+	// they have finished; dependencies that were rerun are replaced by
+	// their latest rerun instead
+	Dropped []string `json:"dropped,omitempty"`
+}
+
+// JobDelegateParams are parameters for job.delegate, with which a worker
+// hands part of its current job to a worker it supervises.
+type JobDelegateParams struct {
+	From        string `json:"from" validate:"required"`   // Worker delegating
+	Job         string `json:"job,omitempty"`              // Job it delegates from; required while it runs several
+	Worker      string `json:"worker" validate:"required"` // Worker to run the new job
+	Description string `json:"description" validate:"required"`
+	Body        string `json:"body,omitempty"`
+	Priority    int    `json:"priority,omitempty" validate:"min=1,max=5"` // Defaults to the delegating job's
+}
+
+// JobDecomposeParams are parameters for job.decompose. Without a plan the
+// underboss is asked to break the task into subtasks, and its breakdown is
+// returned for approval; nothing is created. With a plan, the approved
+// breakdown, it is created as an operation of dependent jobs.
+type JobDecomposeParams struct {
+	Description string    `json:"description" validate:"required"`
+	Body        string    `json:"body,omitempty"`
+	Priority    int       `json:"priority,omitempty" validate:"min=1,max=5"` // Default for subtasks that set none
+	Requester   string    `json:"requester,omitempty"`
+	Plan        *ChatPlan `json:"plan,omitempty"`
+
+	// Given to every subtask, as in job.add
+	Labels     map[string]string `json:"labels,omitempty"`
+	Model      string            `json:"model,omitempty"`
+	SkipReview bool              `json:"skip_review,omitempty"`
+	Timeout    int               `json:"timeout,omitempty" validate:"min=0"`
+}
+
+// JobDecomposeResult is the response for job.decompose. Operation and Jobs
+// are set once the plan has been created.
+type JobDecomposeResult struct {
+	Plan      *ChatPlan         `json:"plan"`
+	Operation *OperationInfo    `json:"operation,omitempty"`
+	Jobs      map[string]string `json:"jobs,omitempty"` // Job ID by step key
+}
+
+// DelegationStep is a job in a chain of delegation, and the worker that
+// delegated the next job from it.
+type DelegationStep struct {
+	Job    string `json:"job"`
+	Worker string `json:"worker"`
+}
+
 // JobSetPriorityParams are parameters for job.setPriority.
 type JobSetPriorityParams struct {
-	JobID    string `json:"job_id"`
-	Priority int    `json:"priority"` // 1-5
+	JobID    string `json:"job_id" validate:"required"`
+	Priority int    `json:"priority" validate:"required,min=1,max=5"` // 1-5
 }
 
 // QueueStatusResult is the response for queue.status.
@@ -344,16 +1430,96 @@ type QueueStatusResult struct {
 	Pending int `json:"pending"` // Jobs waiting on dependencies
 	Running int `json:"running"` // Jobs currently executing
 	Total   int `json:"total"`   // Total jobs in system
+
+	Hold *QueueHoldInfo `json:"hold,omitempty"` // Set while the queue is paused or draining
+
+	// Estimated from how long finished jobs took; unset until a job has
+	// finished, and waiting jobs are left out while the queue is held
+	ETA  int64    `json:"eta,omitempty"`  // Unix time the last job should finish
+	Jobs []JobETA `json:"jobs,omitempty"` // Running jobs, then waiting jobs in the order they start
+}
+
+// JobETA is when an unfinished job is expected to finish.
+type JobETA struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	Estimate    int64  `json:"estimate"` // Expected run time in seconds
+	Basis       string `json:"basis"`    // What the estimate was drawn from, e.g. "template bugfix" or "all jobs"
+	ETA         int64  `json:"eta"`      // Unix time
+}
+
+// QueueStateParams are parameters for queue.pause, queue.drain and
+// queue.resume.
+type QueueStateParams struct {
+	Reason string `json:"reason,omitempty"`
+	By     string `json:"by,omitempty"` // Client identity making the change
+}
+
+// QueueHoldInfo describes the queue's state and who set it. It is also the
+// data of the queue.paused, queue.draining and queue.resumed events.
+type QueueHoldInfo struct {
+	State  string `json:"state"` // running, paused or draining
+	Since  int64  `json:"since"` // When the state was set (Unix)
+	By     string `json:"by,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// QueueStateResult is the response for queue.pause, queue.drain and
+// queue.resume.
+type QueueStateResult struct {
+	QueueHoldInfo
+	Previous string `json:"previous"` // The state the queue was in
+}
+
+// MergeQueueResult is the response for merge.queue.
+type MergeQueueResult struct {
+	Lanes []MergeLaneInfo `json:"lanes"`
+}
+
+// MergeLaneInfo describes the merges into one target branch. They run one
+// at a time, in the order the jobs finished.
+type MergeLaneInfo struct {
+	Target  string            `json:"target"`
+	Merging *MergeQueueEntry  `json:"merging,omitempty"`
+	Waiting []MergeQueueEntry `json:"waiting"`
+}
+
+// MergeQueueEntry is a job's branch in a merge lane.
+type MergeQueueEntry struct {
+	JobID     string `json:"job_id"`
+	Branch    string `json:"branch"`
+	QueuedAt  int64  `json:"queued_at"`
+	StartedAt int64  `json:"started_at,omitempty"`
 }
 
 // ReviewStartParams are parameters for review.start.
 type ReviewStartParams struct {
-	JobID string `json:"job_id"`
+	JobID string `json:"job_id" validate:"required"`
+}
+
+// ReviewOverrideParams are parameters for review.approve and review.reject,
+// which decide a review in place of the consigliere.
+type ReviewOverrideParams struct {
+	JobID  string `json:"job_id" validate:"required"`
+	Reason string `json:"reason" validate:"required"`
+	By     string `json:"by,omitempty"` // Who overrode the review, for the ledger
+
+	// Fails a rejected job instead of queuing a revision of it
+	NoRevision bool `json:"no_revision,omitempty"`
+}
+
+// ReviewOverrideResult is the response for review.approve and review.reject.
+type ReviewOverrideResult struct {
+	JobID         string `json:"job_id"`
+	Decision      string `json:"decision"`
+	Status        string `json:"status"`                    // The job's status afterwards
+	RevisionJobID string `json:"revision_job_id,omitempty"` // Created by a rejection
 }
 
 // ReviewStatusParams are parameters for review.status.
 type ReviewStatusParams struct {
-	JobID string `json:"job_id"`
+	JobID string `json:"job_id" validate:"required"`
 }
 
 // ReviewStatusResult is the response for review.status.
@@ -367,6 +1533,8 @@ type ReviewStatusResult struct {
 	Feedback   string `json:"feedback,omitempty"`
 	Error      string `json:"error,omitempty"`
 	StartedAt  int64  `json:"started_at"`
+
+	Checklist []ChecklistResult `json:"checklist,omitempty"`
 }
 
 // ReviewListResult is the response for review.list.
@@ -376,14 +1544,17 @@ type ReviewListResult struct {
 
 // OperationCreateParams are parameters for operation.create.
 type OperationCreateParams struct {
-	Name        string   `json:"name"`
+	Name        string   `json:"name" validate:"required"`
 	Description string   `json:"description,omitempty"`
-	Jobs        []string `json:"jobs"` // Job IDs to include
+	Jobs        []string `json:"jobs"`                                    // Job IDs to include
+	MaxParallel int      `json:"max_parallel,omitempty" validate:"min=0"` // Jobs running at once, 0 for no limit
+	Sequential  bool     `json:"sequential,omitempty"`                    // Run jobs one at a time in order
+	StopOnFail  bool     `json:"stop_on_fail,omitempty"`                  // Cancel the remaining jobs on the first failure
 }
 
 // OperationStatusParams are parameters for operation.status.
 type OperationStatusParams struct {
-	ID string `json:"id"`
+	ID string `json:"id" validate:"required"`
 }
 
 // OperationInfo describes an operation.
@@ -397,6 +1568,9 @@ type OperationInfo struct {
 	CompletedJobs int      `json:"completed_jobs"`
 	FailedJobs    int      `json:"failed_jobs"`
 	Progress      int      `json:"progress"` // 0-100
+	MaxParallel   int      `json:"max_parallel,omitempty"`
+	Sequential    bool     `json:"sequential,omitempty"`
+	StopOnFail    bool     `json:"stop_on_fail,omitempty"`
 	CreatedAt     int64    `json:"created_at"`
 	StartedAt     int64    `json:"started_at,omitempty"`
 	CompletedAt   int64    `json:"completed_at,omitempty"`
@@ -409,34 +1583,130 @@ type OperationListResult struct {
 
 // OperationCancelParams are parameters for operation.cancel.
 type OperationCancelParams struct {
-	ID string `json:"id"`
+	ID string `json:"id" validate:"required"`
+}
+
+// OrderScope selects whose standing orders an order method works on:
+// exactly one of a worker, a role in the territory, or every worker.
+type OrderScope struct {
+	Worker string `json:"worker,omitempty"` // Worker name
+	Role   string `json:"role,omitempty"`   // Worker role, for the territory's role orders
+	Global bool   `json:"global,omitempty"` // The orders every worker is given
+}
+
+func (p *OrderScope) Validate() error {
+	scopes := 0
+	for _, set := range []bool{p.Worker != "", p.Role != "", p.Global} {
+		if set {
+			scopes++
+		}
+	}
+	if scopes != 1 {
+		return &ValidationError{Fields: []FieldError{{Message: "exactly one of worker, role or global is required"}}}
+	}
+	return nil
 }
 
 // OrderSetParams are parameters for order.set.
 type OrderSetParams struct {
-	Worker string   `json:"worker"` // Worker name
+	OrderScope
 	Orders []string `json:"orders"` // Standing orders to set
 }
 
 // OrderListParams are parameters for order.list.
 type OrderListParams struct {
-	Worker string `json:"worker"` // Worker name
+	OrderScope
 }
 
 // OrderListResult is the response for order.list.
 type OrderListResult struct {
-	Worker string   `json:"worker"`
+	Worker string   `json:"worker,omitempty"`
+	Role   string   `json:"role,omitempty"`
+	Global bool     `json:"global,omitempty"`
 	Orders []string `json:"orders"`
+
+	// For a worker, the orders it inherits, in the order its prompt lists
+	// them ahead of its own
+	GlobalOrders []string `json:"global_orders,omitempty"`
+	RoleOrders   []string `json:"role_orders,omitempty"`
 }
 
 // OrderClearParams are parameters for order.clear.
 type OrderClearParams struct {
-	Worker string `json:"worker"` // Worker name
+	OrderScope
+}
+
+// CapacityOffParams are parameters for capacity.off.
+type CapacityOffParams struct {
+	Worker string `json:"worker" validate:"required"`       // Worker name
+	Start  int64  `json:"start,omitempty" validate:"min=0"` // Unix time; defaults to now
+	End    int64  `json:"end" validate:"required,min=1"`    // Unix time
+	Reason string `json:"reason,omitempty"`
+}
+
+// CapacityOnParams are parameters for capacity.on. Either Worker or ID is
+// set.
+type CapacityOnParams struct {
+	Worker string `json:"worker,omitempty"` // Cancel all of the worker's downtime
+	ID     string `json:"id,omitempty"`     // Cancel a single downtime window
+}
+
+// Validate checks that exactly one of Worker and ID is set.
+func (p *CapacityOnParams) Validate() error {
+	if (p.Worker == "") == (p.ID == "") {
+		return &ValidationError{Fields: []FieldError{{Message: "either worker or id is required"}}}
+	}
+	return nil
+}
+
+// CapacityLimitParams are parameters for capacity.limit.
+type CapacityLimitParams struct {
+	Worker   string `json:"worker" validate:"required"` // Worker name
+	MaxDaily int    `json:"max_daily" validate:"min=0"` // Jobs started per day; 0 removes the limit
+}
+
+// CapacityStatusParams are parameters for capacity.status.
+type CapacityStatusParams struct {
+	Days int `json:"days,omitempty" validate:"min=1,max=31"` // Days ahead to show; default 7
+}
+
+// DowntimeInfo describes a window in which a worker takes no new jobs.
+type DowntimeInfo struct {
+	ID     string `json:"id"`
+	Worker string `json:"worker"`
+	Start  int64  `json:"start"`
+	End    int64  `json:"end"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// DayCapacity is a worker's capacity on one day.
+type DayCapacity struct {
+	Date      string  `json:"date"`      // YYYY-MM-DD, local time
+	Available float64 `json:"available"` // Fraction of the day the worker is online, 0-1
+	Jobs      float64 `json:"jobs"`      // Jobs the worker is expected to finish
+}
+
+// WorkerCapacity describes a worker's upcoming capacity.
+type WorkerCapacity struct {
+	Name         string        `json:"name"`
+	MaxDaily     int           `json:"max_daily,omitempty"` // 0 for no limit
+	StartedToday int           `json:"started_today"`
+	OffUntil     int64         `json:"off_until,omitempty"` // Unix time, if offline now
+	Days         []DayCapacity `json:"days"`
+}
+
+// CapacityStatusResult is the response for capacity.status.
+type CapacityStatusResult struct {
+	Workers    []WorkerCapacity `json:"workers"`
+	Downtime   []DowntimeInfo   `json:"downtime"`
+	Pending    int              `json:"pending"`       // Jobs waiting to start
+	Throughput float64          `json:"throughput"`    // Jobs finished per worker per day over the last week
+	ETA        int64            `json:"eta,omitempty"` // Unix time the pending jobs should be done, 0 if unknown
 }
 
 // HandoffGenerateParams are parameters for handoff.generate.
 type HandoffGenerateParams struct {
-	Worker string `json:"worker"` // Worker name
+	Worker string `json:"worker" validate:"required"` // Worker name
 }
 
 // HandoffSummary is the response for handoff.generate.
@@ -448,12 +1718,31 @@ type HandoffSummary struct {
 	Decisions     []string `json:"decisions,omitempty"`
 	FilesTouched  []string `json:"files_touched,omitempty"`
 	OpenQuestions []string `json:"open_questions,omitempty"`
+	Summary       string   `json:"summary,omitempty"` // Summary of the current job's changes
 	CreatedAt     int64    `json:"created_at"`
 }
 
+// WorkerTakeoverParams are parameters for worker.takeover.
+type WorkerTakeoverParams struct {
+	From    string `json:"from" validate:"required"`            // Worker handing over its job, by name or ID
+	To      string `json:"to" validate:"required"`              // Idle worker taking it over, by name or ID
+	GraceMS int    `json:"grace_ms,omitempty" validate:"min=0"` // Milliseconds to wait after the interrupt before killing Claude
+}
+
+// WorkerTakeoverResult is the response for worker.takeover.
+type WorkerTakeoverResult struct {
+	JobID       string         `json:"job_id"`
+	Description string         `json:"description"`
+	From        string         `json:"from"`
+	To          string         `json:"to"`
+	Worktree    string         `json:"worktree,omitempty"`
+	Handoff     HandoffSummary `json:"handoff"`
+}
+
 // ChatStartParams are parameters for chat.start.
 type ChatStartParams struct {
 	SessionID string `json:"session_id,omitempty"` // Optional: resume existing session
+	Resume    bool   `json:"resume,omitempty"`     // Resume the most recent session
 }
 
 // ChatStartResult is the response for chat.start.
@@ -461,21 +1750,81 @@ type ChatStartResult struct {
 	SessionID string `json:"session_id"`
 	Status    string `json:"status"`
 	Greeting  string `json:"greeting,omitempty"`
+	Resumed   bool   `json:"resumed,omitempty"` // True if an earlier session was resumed
 }
 
 // ChatSendParams are parameters for chat.send.
 type ChatSendParams struct {
-	Message string `json:"message"`
+	Message string `json:"message" validate:"required"`
 }
 
 // ChatSendResult is the response for chat.send.
 type ChatSendResult struct {
-	Response string `json:"response"`
+	Response string    `json:"response"`
+	Mode     string    `json:"mode,omitempty"`
+	Plan     *ChatPlan `json:"plan,omitempty"` // Draft operation, in plan mode
+}
+
+// Chat modes. In plan mode, jobs the underboss creates are added to a draft
+// operation that is only created by chat.execute.
+const (
+	ChatModeChat = "chat"
+	ChatModePlan = "plan"
+)
+
+// ChatPlan is the draft operation built up in plan mode.
+type ChatPlan struct {
+	Steps []PlanStep `json:"steps"`
+}
+
+// PlanStep is a job in a draft operation.
+type PlanStep struct {
+	Key         string   `json:"key"` // Short name other steps refer to
+	Description string   `json:"description" validate:"required"`
+	Body        string   `json:"body,omitempty"`
+	Priority    int      `json:"priority,omitempty" validate:"min=1,max=5"`
+	DependsOn   []string `json:"depends_on,omitempty"` // Step keys or existing job IDs
+	Estimate    string   `json:"estimate,omitempty"`   // Free-form, e.g. "30m" or "$2"
+}
+
+// ChatModeParams are parameters for chat.mode.
+type ChatModeParams struct {
+	Mode string `json:"mode" validate:"required,oneof=chat plan"` // ChatModeChat or ChatModePlan
+}
+
+// ChatPlanResult is the response for chat.mode, chat.plan, chat.planAdd,
+// chat.planRemove and chat.discard.
+type ChatPlanResult struct {
+	Mode string    `json:"mode"`
+	Plan *ChatPlan `json:"plan"`
+	Key  string    `json:"key,omitempty"` // Step added by chat.planAdd
+}
+
+// ChatPlanRemoveParams are parameters for chat.planRemove.
+type ChatPlanRemoveParams struct {
+	Key string `json:"key" validate:"required"`
+}
+
+// ChatExecuteParams are parameters for chat.execute.
+type ChatExecuteParams struct {
+	Name string `json:"name,omitempty"` // Operation name; defaults to "Chat plan <time>"
+}
+
+// ChatExecuteResult is the response for chat.execute.
+type ChatExecuteResult struct {
+	Operation OperationInfo     `json:"operation"`
+	Jobs      map[string]string `json:"jobs"` // Job ID by step key
+}
+
+// ChatHistoryParams are parameters for chat.history.
+type ChatHistoryParams struct {
+	SessionID string `json:"session_id,omitempty"` // Optional: defaults to the active or most recent session
 }
 
 // ChatHistoryResult is the response for chat.history.
 type ChatHistoryResult struct {
-	Messages []ChatMessage `json:"messages"`
+	SessionID string        `json:"session_id,omitempty"`
+	Messages  []ChatMessage `json:"messages"`
 }
 
 // ChatMessage represents a message in the chat history.
@@ -502,6 +1851,7 @@ type TemplateInfo struct {
 	Priority    int           `json:"priority"`
 	Variables   []TemplateVar `json:"variables,omitempty"`
 	Tags        []string      `json:"tags,omitempty"`
+	Checklist   []string      `json:"checklist,omitempty"`
 	BuiltIn     bool          `json:"built_in"`
 }
 
@@ -517,7 +1867,7 @@ type TemplateListResult struct {
 
 // TemplateGetParams are parameters for template.get.
 type TemplateGetParams struct {
-	ID string `json:"id"`
+	ID string `json:"id" validate:"required"`
 }
 
 // TemplateGetResult is the response for template.get.
@@ -528,10 +1878,10 @@ type TemplateGetResult struct {
 
 // TemplateUseParams are parameters for template.use.
 type TemplateUseParams struct {
-	TemplateID string            `json:"template_id"`
+	TemplateID string            `json:"template_id" validate:"required"`
 	Variables  map[string]string `json:"variables,omitempty"`
-	Priority   int               `json:"priority,omitempty"` // Override template priority
-	Worker     string            `json:"worker,omitempty"`   // Assign to specific worker
+	Priority   int               `json:"priority,omitempty" validate:"min=1,max=5"` // Override template priority
+	Worker     string            `json:"worker,omitempty"`                          // Assign to specific worker
 	DependsOn  []string          `json:"depends_on,omitempty"`
 }
 
@@ -539,3 +1889,51 @@ type TemplateUseParams struct {
 type TemplateUseResult struct {
 	Job JobInfo `json:"job"`
 }
+
+// TemplateSpec is a complete custom template, in the JSON format used for
+// import, export and the templates directory.
+type TemplateSpec struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Type        string        `json:"type,omitempty"`
+	Priority    int           `json:"priority,omitempty"`
+	Prompt      string        `json:"prompt"`
+	Variables   []TemplateVar `json:"variables,omitempty"`
+	Tags        []string      `json:"tags,omitempty"`
+	Checklist   []string      `json:"checklist,omitempty"`
+}
+
+// TemplateCreateParams are parameters for template.create.
+type TemplateCreateParams struct {
+	Template TemplateSpec `json:"template"`
+	Replace  bool         `json:"replace,omitempty"` // Overwrite an existing custom template
+}
+
+// TemplateRemoveParams are parameters for template.remove.
+type TemplateRemoveParams struct {
+	ID string `json:"id" validate:"required"`
+}
+
+// TemplateExportParams are parameters for template.export.
+type TemplateExportParams struct {
+	IDs     []string `json:"ids,omitempty"`      // Templates to export; empty exports all custom templates
+	BuiltIn bool     `json:"built_in,omitempty"` // Include built-in templates when IDs is empty
+}
+
+// TemplateExportResult is the response for template.export.
+type TemplateExportResult struct {
+	Templates []TemplateSpec `json:"templates"`
+}
+
+// TemplateImportParams are parameters for template.import.
+type TemplateImportParams struct {
+	Templates []TemplateSpec `json:"templates" validate:"required"`
+	Replace   bool           `json:"replace,omitempty"` // Overwrite existing custom templates
+}
+
+// TemplateImportResult is the response for template.import.
+type TemplateImportResult struct {
+	Imported []string `json:"imported"`
+	Replaced []string `json:"replaced,omitempty"`
+}
@@ -263,6 +263,9 @@ func TestErrorCodes(t *testing.T) {
 		ErrOperationNotFound,
 		ErrGateFailed,
 		ErrMergeConflict,
+		ErrJobRejected,
+		ErrUnauthorized,
+		ErrRequestCancelled,
 	}
 
 	for _, code := range appCodes {
@@ -293,6 +296,21 @@ func TestMethodConstants(t *testing.T) {
 	}
 }
 
+func TestIsReadOnly(t *testing.T) {
+	for _, method := range []string{MethodStatus, MethodAuthLogin, MethodSyncState, MethodJobList, MethodJobStatus, MethodLedgerQuery} {
+		if !IsReadOnly(method) {
+			t.Errorf("expected %s to be read-only", method)
+		}
+	}
+
+	// Anything that changes state, or that is unknown, is not
+	for _, method := range []string{MethodShutdown, MethodJobAdd, MethodJobCancel, MethodWorkerRemove, MethodChatSend, MethodLedgerVerify, "job.unknown"} {
+		if IsReadOnly(method) {
+			t.Errorf("expected %s not to be read-only", method)
+		}
+	}
+}
+
 func TestRequestJSONRoundTrip(t *testing.T) {
 	params := WorkerAddParams{Name: "test-worker", Role: "soldato"}
 	req, err := NewRequest(NewStringID("test"), MethodWorkerAdd, params)
@@ -0,0 +1,257 @@
+package protocol
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Task list formats understood by ParseTaskList.
+const (
+	TaskListMarkdown = "markdown"
+	TaskListJSON     = "json"
+	TaskListYAML     = "yaml"
+)
+
+var (
+	taskKeyPattern   = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,31}$`)
+	headingPattern   = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*\s*$`)
+	checkboxPattern  = regexp.MustCompile(`^[-*+]\s+\[([ xX])\]\s+(.*)$`)
+	taskAttrsPattern = regexp.MustCompile(`\s*\{([^{}]*)\}\s*$`)
+)
+
+// ParseTaskList reads the tasks of a task list for job.import. format is one
+// of the TaskList constants; if empty it is detected from the file name and
+// content.
+//
+// A Markdown task list is a checklist. Headings name the operation their
+// tasks are added to, a task nested under another depends on it, and checked
+// tasks are skipped. Other lines indented under a task become its body. A
+// task can end with attributes in braces:
+//
+//   - [ ] Add the sessions table {id=schema priority=2}
+//   - [ ] Expose sessions over the API {after=schema area=api}
+//
+// id names the task for after, which lists task IDs or existing job IDs
+// separated by commas. Any other attribute becomes a label.
+//
+// JSON and YAML task lists are arrays of ImportTask. Tasks without a key are
+// named task-N after their position.
+func ParseTaskList(data []byte, name, format string) ([]ImportTask, error) {
+	if format == "" {
+		format = detectTaskListFormat(data, name)
+	}
+
+	var tasks []ImportTask
+	var err error
+	switch format {
+	case TaskListMarkdown:
+		tasks, err = parseMarkdownTasks(data)
+	case TaskListJSON:
+		err = json.Unmarshal(data, &tasks)
+	case TaskListYAML:
+		err = yaml.Unmarshal(data, &tasks)
+	default:
+		return nil, fmt.Errorf("unknown task list format: %s (use markdown, json or yaml)", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s task list: %w", format, err)
+	}
+
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("no tasks found")
+	}
+
+	seen := make(map[string]bool, len(tasks))
+	for i := range tasks {
+		if tasks[i].Key == "" {
+			tasks[i].Key = fmt.Sprintf("task-%d", i+1)
+		}
+		key := tasks[i].Key
+		if !taskKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("invalid task id %q", key)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate task id %q", key)
+		}
+		seen[key] = true
+	}
+	return tasks, nil
+}
+
+// detectTaskListFormat picks a format from the file extension, falling back
+// to JSON for content that looks like an array and Markdown otherwise.
+func detectTaskListFormat(data []byte, name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return TaskListJSON
+	case ".yaml", ".yml":
+		return TaskListYAML
+	case ".md", ".markdown":
+		return TaskListMarkdown
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return TaskListJSON
+	}
+	return TaskListMarkdown
+}
+
+// openTask is a checklist item that may still have nested items or body
+// lines.
+type openTask struct {
+	indent int
+	index  int // Into the parsed tasks, or -1 for a checked item
+}
+
+func parseMarkdownTasks(data []byte) ([]ImportTask, error) {
+	var tasks []ImportTask
+	var stack []openTask
+	var section string
+	var inFence bool
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		raw := strings.TrimRight(scanner.Text(), " \t\r")
+		line := strings.TrimLeft(raw, " \t")
+		indent := indentWidth(raw[:len(raw)-len(line)])
+
+		isFence := strings.HasPrefix(line, "```")
+		inCode := inFence || isFence
+		if isFence {
+			inFence = !inFence
+		}
+
+		// Code blocks and other lines nested under a task belong to its body
+		if len(stack) > 0 && (inCode || (line != "" && indent > stack[len(stack)-1].indent && !checkboxPattern.MatchString(line))) {
+			if top := stack[len(stack)-1]; top.index >= 0 {
+				t := &tasks[top.index]
+				if t.Body != "" {
+					t.Body += "\n"
+				}
+				t.Body += line
+			}
+			continue
+		}
+		if inCode || line == "" {
+			continue
+		}
+
+		if m := headingPattern.FindStringSubmatch(line); m != nil && indent == 0 {
+			section = m[1]
+			stack = nil
+			continue
+		}
+
+		m := checkboxPattern.FindStringSubmatch(line)
+		if m == nil {
+			// Prose between tasks ends any nesting
+			stack = nil
+			continue
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+
+		if m[1] != " " {
+			stack = append(stack, openTask{indent: indent, index: -1})
+			continue
+		}
+
+		task := ImportTask{Section: section}
+		description := m[2]
+		if am := taskAttrsPattern.FindStringSubmatchIndex(description); am != nil {
+			ok, err := applyTaskAttrs(&task, description[am[2]:am[3]])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			if ok {
+				description = description[:am[0]]
+			}
+		}
+		task.Description = strings.TrimSpace(description)
+		if task.Description == "" {
+			return nil, fmt.Errorf("line %d: task has no description", lineNum)
+		}
+		if task.Key == "" {
+			task.Key = fmt.Sprintf("task-%d", len(tasks)+1)
+		}
+
+		if len(stack) > 0 {
+			if parent := stack[len(stack)-1]; parent.index >= 0 {
+				task.DependsOn = append([]string{tasks[parent.index].Key}, task.DependsOn...)
+			}
+		}
+
+		stack = append(stack, openTask{indent: indent, index: len(tasks)})
+		tasks = append(tasks, task)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// applyTaskAttrs sets the task fields given in a trailing {key=value ...}
+// block. It reports false, leaving the task alone, if the braces are not an
+// attribute block.
+func applyTaskAttrs(task *ImportTask, attrs string) (bool, error) {
+	fields := strings.Fields(attrs)
+	if len(fields) == 0 {
+		return false, nil
+	}
+	for _, f := range fields {
+		if key, _, ok := strings.Cut(f, "="); !ok || key == "" {
+			return false, nil
+		}
+	}
+
+	for _, f := range fields {
+		key, value, _ := strings.Cut(f, "=")
+		switch key {
+		case "id":
+			task.Key = value
+		case "after":
+			for _, dep := range strings.Split(value, ",") {
+				if dep != "" {
+					task.DependsOn = append(task.DependsOn, dep)
+				}
+			}
+		case "priority":
+			p, err := strconv.Atoi(value)
+			if err != nil || p < 1 || p > 5 {
+				return false, fmt.Errorf("invalid priority %q (use 1-5)", value)
+			}
+			task.Priority = p
+		default:
+			if task.Labels == nil {
+				task.Labels = make(map[string]string)
+			}
+			task.Labels[key] = value
+		}
+	}
+	return true, nil
+}
+
+// indentWidth measures leading whitespace, counting a tab as four spaces.
+func indentWidth(ws string) int {
+	width := 0
+	for _, r := range ws {
+		if r == '\t' {
+			width += 4
+		} else {
+			width++
+		}
+	}
+	return width
+}
@@ -0,0 +1,94 @@
+package protocol
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTaskList_Markdown(t *testing.T) {
+	data := []byte(`# Session rework
+
+Some notes that are not tasks.
+
+- [ ] Add the sessions table {id=schema priority=2}
+  Use a UUID primary key.
+  - [ ] Expose sessions over the API {area=api}
+- [x] Already done
+  - [ ] Follow-up to finished work
+- [ ] Write the docs {after=schema,0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0}
+
+## Cleanup
+
+* [ ] Drop the old table {keep braces}
+`)
+
+	tasks, err := ParseTaskList(data, "tasks.md", "")
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	want := []ImportTask{
+		{Key: "schema", Description: "Add the sessions table", Body: "Use a UUID primary key.", Priority: 2, Section: "Session rework"},
+		{Key: "task-2", Description: "Expose sessions over the API", Labels: map[string]string{"area": "api"},
+			DependsOn: []string{"schema"}, Section: "Session rework"},
+		{Key: "task-3", Description: "Follow-up to finished work", Section: "Session rework"},
+		{Key: "task-4", Description: "Write the docs",
+			DependsOn: []string{"schema", "0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0"}, Section: "Session rework"},
+		{Key: "task-5", Description: "Drop the old table {keep braces}", Section: "Cleanup"},
+	}
+	if !reflect.DeepEqual(tasks, want) {
+		t.Errorf("expected\n%+v\ngot\n%+v", want, tasks)
+	}
+}
+
+func TestParseTaskList_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		file string
+	}{
+		{"no tasks", "# Just a heading\n", "tasks.md"},
+		{"bad priority", "- [ ] Fix it {priority=9}\n", "tasks.md"},
+		{"duplicate id", "- [ ] One {id=a}\n- [ ] Two {id=a}\n", "tasks.md"},
+		{"invalid id", `[{"key": "no spaces", "description": "x"}]`, "tasks.json"},
+		{"bad json", `[{"description": }]`, "tasks.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseTaskList([]byte(tt.data), tt.file, ""); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestParseTaskList_Structured(t *testing.T) {
+	want := []ImportTask{
+		{Key: "schema", Description: "Add the table", Section: "DB"},
+		{Key: "task-2", Description: "Add the API", Priority: 4, DependsOn: []string{"schema"}},
+	}
+
+	json := `[
+		{"key": "schema", "description": "Add the table", "section": "DB"},
+		{"description": "Add the API", "priority": 4, "depends_on": ["schema"]}
+	]`
+	yaml := `
+- key: schema
+  description: Add the table
+  section: DB
+- description: Add the API
+  priority: 4
+  depends_on: [schema]
+`
+
+	for name, data := range map[string]string{"tasks.json": json, "tasks.yaml": yaml, "-": json} {
+		tasks, err := ParseTaskList([]byte(data), name, "")
+		if err != nil {
+			t.Fatalf("%s: failed to parse: %v", name, err)
+		}
+		if !reflect.DeepEqual(tasks, want) {
+			t.Errorf("%s: expected %+v, got %+v", name, want, tasks)
+		}
+	}
+}
@@ -0,0 +1,338 @@
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Params are checked against rules in their validate struct tag:
+//
+//	required    the field must be set (non-empty string, slice or map, non-zero number)
+//	min=N       a number must be at least N
+//	max=N       a number must be at most N
+//	oneof=a b   a string must be one of the listed values
+//	dive        check each element of a slice, or a nested struct
+//
+// Rules other than required only apply to fields that are set, so optional
+// fields keep their zero value as "use the default". Params types that need
+// rules across fields also implement Validator.
+
+// Validator is implemented by params with rules a struct tag cannot express.
+type Validator interface {
+	Validate() error
+}
+
+// FieldError describes one invalid field in a request's params.
+type FieldError struct {
+	Field   string `json:"field,omitempty"` // JSON path, e.g. "jobs[2].priority"
+	Message string `json:"message"`
+}
+
+// ValidationError lists every invalid field in a request's params. It is
+// sent as the data of an InvalidParams error.
+type ValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		if f.Field == "" {
+			msgs[i] = f.Message
+		} else {
+			msgs[i] = f.Field + " " + f.Message
+		}
+	}
+	return "invalid params: " + strings.Join(msgs, "; ")
+}
+
+// Add records that field is invalid.
+func (e *ValidationError) Add(field, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: message})
+}
+
+// Err returns e if any field was invalid, or nil.
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// methodParams creates the params value for each method that takes params,
+// so every entry point checks a request against the same rules.
+var methodParams = map[string]func() interface{}{
+	MethodShutdown:              func() interface{} { return &ShutdownParams{} },
+	MethodAuthLogin:             func() interface{} { return &AuthLoginParams{} },
+	MethodCancel:                func() interface{} { return &CancelParams{} },
+	MethodTerritoryInit:         func() interface{} { return &TerritoryInitParams{} },
+	MethodTerritoryAdd:          func() interface{} { return &TerritoryPathParams{} },
+	MethodTerritorySetDevBranch: func() interface{} { return &TerritorySetDevBranchParams{} },
+	MethodTerritoryRemove:       func() interface{} { return &TerritoryRemoveParams{} },
+	MethodWorkerAdd:             func() interface{} { return &WorkerAddParams{} },
+	MethodWorkerStatus:          func() interface{} { return &WorkerNameParams{} },
+	MethodWorkerRemove:          func() interface{} { return &WorkerRemoveParams{} },
+	MethodWorkerMessage:         func() interface{} { return &WorkerMessageParams{} },
+	MethodWorkerDetail:          func() interface{} { return &WorkerNameParams{} },
+	MethodWorkerTranscript:      func() interface{} { return &WorkerTranscriptParams{} },
+	MethodWorkerExec:            func() interface{} { return &WorkerExecParams{} },
+	MethodWorkerTakeover:        func() interface{} { return &WorkerTakeoverParams{} },
+	MethodWorkerLabel:           func() interface{} { return &WorkerLabelParams{} },
+	MethodWorkerUnquarantine:    func() interface{} { return &WorkerNameParams{} },
+	MethodWorkerClone:           func() interface{} { return &WorkerCloneParams{} },
+	MethodJobAdd:                func() interface{} { return &JobAddParams{} },
+	MethodJobList:               func() interface{} { return &JobListParams{} },
+	MethodJobStatus:             func() interface{} { return &JobStatusParams{} },
+	MethodJobCancel:             func() interface{} { return &JobCancelParams{} },
+	MethodJobAssign:             func() interface{} { return &JobAssignParams{} },
+	MethodJobReassign:           func() interface{} { return &JobReassignParams{} },
+	MethodJobSetPriority:        func() interface{} { return &JobSetPriorityParams{} },
+	MethodJobMerge:              func() interface{} { return &JobMergeParams{} },
+	MethodJobAddBatch:           func() interface{} { return &JobAddBatchParams{} },
+	MethodJobImportStatus:       func() interface{} { return &JobImportStatusParams{} },
+	MethodJobImport:             func() interface{} { return &JobImportParams{} },
+	MethodJobDiff:               func() interface{} { return &JobDiffParams{} },
+	MethodJobWatch:              func() interface{} { return &JobWatchParams{} },
+	MethodJobUnwatch:            func() interface{} { return &JobWatchParams{} },
+	MethodJobUnstick:            func() interface{} { return &JobUnstickParams{} },
+	MethodJobApprove:            func() interface{} { return &JobApproveParams{} },
+	MethodJobRerun:              func() interface{} { return &JobRerunParams{} },
+	MethodJobDelegate:           func() interface{} { return &JobDelegateParams{} },
+	MethodJobDecompose:          func() interface{} { return &JobDecomposeParams{} },
+	MethodConflictDetail:        func() interface{} { return &ConflictParams{} },
+	MethodConflictAssign:        func() interface{} { return &ConflictAssignParams{} },
+	MethodConflictWorkspace:     func() interface{} { return &ConflictParams{} },
+	MethodConflictAbandon:       func() interface{} { return &ConflictParams{} },
+	MethodTriageResolve:         func() interface{} { return &TriageResolveParams{} },
+	MethodQueuePause:            func() interface{} { return &QueueStateParams{} },
+	MethodQueueDrain:            func() interface{} { return &QueueStateParams{} },
+	MethodQueueResume:           func() interface{} { return &QueueStateParams{} },
+	MethodReviewStart:           func() interface{} { return &ReviewStartParams{} },
+	MethodReviewApprove:         func() interface{} { return &ReviewOverrideParams{} },
+	MethodReviewReject:          func() interface{} { return &ReviewOverrideParams{} },
+	MethodReviewStatus:          func() interface{} { return &ReviewStatusParams{} },
+	MethodOperationCreate:       func() interface{} { return &OperationCreateParams{} },
+	MethodOperationStatus:       func() interface{} { return &OperationStatusParams{} },
+	MethodOperationCancel:       func() interface{} { return &OperationCancelParams{} },
+	MethodOrderSet:              func() interface{} { return &OrderSetParams{} },
+	MethodOrderList:             func() interface{} { return &OrderListParams{} },
+	MethodOrderClear:            func() interface{} { return &OrderClearParams{} },
+	MethodHandoffGenerate:       func() interface{} { return &HandoffGenerateParams{} },
+	MethodCapacityOff:           func() interface{} { return &CapacityOffParams{} },
+	MethodCapacityOn:            func() interface{} { return &CapacityOnParams{} },
+	MethodCapacityLimit:         func() interface{} { return &CapacityLimitParams{} },
+	MethodCapacityStatus:        func() interface{} { return &CapacityStatusParams{} },
+	MethodChatStart:             func() interface{} { return &ChatStartParams{} },
+	MethodChatSend:              func() interface{} { return &ChatSendParams{} },
+	MethodChatHistory:           func() interface{} { return &ChatHistoryParams{} },
+	MethodChatMode:              func() interface{} { return &ChatModeParams{} },
+	MethodChatPlanAdd:           func() interface{} { return &PlanStep{} },
+	MethodChatPlanRemove:        func() interface{} { return &ChatPlanRemoveParams{} },
+	MethodChatExecute:           func() interface{} { return &ChatExecuteParams{} },
+	MethodTemplateList:          func() interface{} { return &TemplateListParams{} },
+	MethodTemplateGet:           func() interface{} { return &TemplateGetParams{} },
+	MethodTemplateUse:           func() interface{} { return &TemplateUseParams{} },
+	MethodTemplateCreate:        func() interface{} { return &TemplateCreateParams{} },
+	MethodTemplateRemove:        func() interface{} { return &TemplateRemoveParams{} },
+	MethodTemplateExport:        func() interface{} { return &TemplateExportParams{} },
+	MethodTemplateImport:        func() interface{} { return &TemplateImportParams{} },
+	MethodLedgerQuery:           func() interface{} { return &LedgerQueryParams{} },
+	MethodLedgerVerify:          func() interface{} { return &LedgerVerifyParams{} },
+	MethodCostsExport:           func() interface{} { return &CostsExportParams{} },
+	MethodCleanupRun:            func() interface{} { return &CleanupRunParams{} },
+	MethodDebugScheduler:        func() interface{} { return &DebugSchedulerParams{} },
+	MethodSubscribe:             func() interface{} { return &SubscribeParams{} },
+	MethodSyncState:             func() interface{} { return &SyncStateParams{} },
+}
+
+// ValidateParams checks raw against the params of method. Methods that take
+// no params accept anything.
+func ValidateParams(method string, raw json.RawMessage) error {
+	newParams, ok := methodParams[method]
+	if !ok {
+		return nil
+	}
+	return DecodeParams(raw, newParams())
+}
+
+// DecodeParams unmarshals raw into v and checks it. Missing params decode
+// as an empty object, so required fields are still reported.
+func DecodeParams(raw json.RawMessage, v interface{}) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		raw = json.RawMessage("{}")
+	}
+
+	if err := json.Unmarshal(raw, v); err != nil {
+		verr := &ValidationError{}
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			verr.Add(typeErr.Field, fmt.Sprintf("must be %s, not %s", jsonKind(typeErr.Type), typeErr.Value))
+		} else {
+			verr.Add("", "malformed params: "+err.Error())
+		}
+		return verr
+	}
+
+	return Validate(v)
+}
+
+// Validate checks v against its validate tags and, if it implements
+// Validator, its own rules.
+func Validate(v interface{}) error {
+	verr := &ValidationError{}
+	validateStruct(reflect.ValueOf(v), "", verr)
+	return verr.Err()
+}
+
+func validateStruct(v reflect.Value, prefix string, verr *ValidationError) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("validate")
+		if tag == "" || !field.IsExported() {
+			continue
+		}
+		validateField(v.Field(i), prefix+jsonName(field), tag, verr)
+	}
+
+	if !v.CanAddr() {
+		return
+	}
+	if validator, ok := v.Addr().Interface().(Validator); ok {
+		if err := validator.Validate(); err != nil {
+			var fieldErrs *ValidationError
+			if errors.As(err, &fieldErrs) {
+				for _, f := range fieldErrs.Fields {
+					verr.Add(joinField(prefix, f.Field), f.Message)
+				}
+			} else {
+				verr.Add(strings.TrimSuffix(prefix, "."), err.Error())
+			}
+		}
+	}
+}
+
+func validateField(v reflect.Value, name, tag string, verr *ValidationError) {
+	set := !v.IsZero()
+	for _, rule := range strings.Split(tag, ",") {
+		key, arg, _ := strings.Cut(rule, "=")
+		switch key {
+		case "required":
+			if !set || (v.Kind() == reflect.String && strings.TrimSpace(v.String()) == "") ||
+				((v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.Len() == 0) {
+				verr.Add(name, "is required")
+				return
+			}
+		case "min", "max":
+			limit, err := strconv.ParseInt(arg, 10, 64)
+			if err != nil {
+				panic(fmt.Sprintf("protocol: bad %s rule on %s: %q", key, name, rule))
+			}
+			if !set || !isInt(v) {
+				continue
+			}
+			if key == "min" && v.Int() < limit {
+				verr.Add(name, fmt.Sprintf("must be at least %d", limit))
+			}
+			if key == "max" && v.Int() > limit {
+				verr.Add(name, fmt.Sprintf("must be at most %d", limit))
+			}
+		case "oneof":
+			if !set || v.Kind() != reflect.String {
+				continue
+			}
+			allowed := strings.Fields(arg)
+			if !containsValue(allowed, v.String()) {
+				verr.Add(name, "must be one of "+strings.Join(allowed, ", "))
+			}
+		case "dive":
+			switch v.Kind() {
+			case reflect.Slice, reflect.Array:
+				for i := 0; i < v.Len(); i++ {
+					validateStruct(v.Index(i), fmt.Sprintf("%s[%d].", name, i), verr)
+				}
+			default:
+				validateStruct(v, name+".", verr)
+			}
+		default:
+			panic(fmt.Sprintf("protocol: unknown validate rule on %s: %q", name, rule))
+		}
+	}
+}
+
+// jsonName returns the name a struct field has in JSON.
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+func joinField(prefix, field string) string {
+	if field == "" {
+		return strings.TrimSuffix(prefix, ".")
+	}
+	return prefix + field
+}
+
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
+
+func isInt(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func containsValue(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// NewInvalidParamsResponse creates an InvalidParams error response for err,
+// with the invalid fields as its data.
+func NewInvalidParamsResponse(id *RequestID, err error) *Response {
+	var data interface{}
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		data = verr
+	}
+	resp, _ := NewErrorResponse(id, InvalidParams, err.Error(), data)
+	return resp
+}
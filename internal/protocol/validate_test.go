@@ -0,0 +1,170 @@
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidateParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		params   string
+		expected []FieldError
+	}{
+		{
+			name:   "valid",
+			method: MethodJobSetPriority,
+			params: `{"job_id": "abc", "priority": 2}`,
+		},
+		{
+			name:   "method without params",
+			method: MethodStatus,
+			params: `"anything"`,
+		},
+		{
+			name:     "missing params",
+			method:   MethodJobCancel,
+			params:   ``,
+			expected: []FieldError{{Field: "id", Message: "is required"}},
+		},
+		{
+			name:   "every invalid field is reported",
+			method: MethodJobSetPriority,
+			params: `{"priority": 9}`,
+			expected: []FieldError{
+				{Field: "job_id", Message: "is required"},
+				{Field: "priority", Message: "must be at most 5"},
+			},
+		},
+		{
+			name:     "optional fields keep their default",
+			method:   MethodJobAdd,
+			params:   `{"description": "Add login", "priority": -1}`,
+			expected: []FieldError{{Field: "priority", Message: "must be at least 1"}},
+		},
+		{
+			name:   "cancel by number",
+			method: MethodCancel,
+			params: `{"id": 7}`,
+		},
+		{
+			name:   "cancel by string",
+			method: MethodCancel,
+			params: `{"id": "req-1"}`,
+		},
+		{
+			name:     "cancel without id",
+			method:   MethodCancel,
+			params:   `{}`,
+			expected: []FieldError{{Field: "id", Message: "is required"}},
+		},
+		{
+			name:     "oneof",
+			method:   MethodTriageResolve,
+			params:   `{"id": "abc", "action": "ignore"}`,
+			expected: []FieldError{{Field: "action", Message: "must be one of retry, accept, dismiss"}},
+		},
+		{
+			name:     "dive",
+			method:   MethodJobAddBatch,
+			params:   `{"jobs": [{"description": "a"}, {"priority": 3}]}`,
+			expected: []FieldError{{Field: "jobs[1].description", Message: "is required"}},
+		},
+		{
+			name:     "wrong type",
+			method:   MethodJobCancel,
+			params:   `{"id": 42}`,
+			expected: []FieldError{{Field: "id", Message: "must be a string, not number"}},
+		},
+		{
+			name:     "custom rule",
+			method:   MethodLedgerQuery,
+			params:   `{"since": 200, "until": 100}`,
+			expected: []FieldError{{Field: "until", Message: "must not be before since"}},
+		},
+		{
+			name:     "date range",
+			method:   MethodCostsExport,
+			params:   `{"from": "2024-04-30", "to": "2024-04-01"}`,
+			expected: []FieldError{{Field: "to", Message: "must not be before from"}},
+		},
+		{
+			name:     "bad date",
+			method:   MethodCostsExport,
+			params:   `{"from": "April 1st"}`,
+			expected: []FieldError{{Field: "from", Message: "must be a date (YYYY-MM-DD)"}},
+		},
+		{
+			name:     "embedded custom rule",
+			method:   MethodOrderSet,
+			params:   `{"worker": "tony", "global": true, "orders": ["Test first"]}`,
+			expected: []FieldError{{Message: "exactly one of worker, role or global is required"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateParams(tt.method, json.RawMessage(tt.params))
+			if tt.expected == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("expected a ValidationError, got %v", err)
+			}
+			if !reflect.DeepEqual(verr.Fields, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, verr.Fields)
+			}
+		})
+	}
+}
+
+func TestValidateParams_Malformed(t *testing.T) {
+	err := ValidateParams(MethodJobAdd, json.RawMessage(`{"description":`))
+
+	var verr *ValidationError
+	if !errors.As(err, &verr) || len(verr.Fields) != 1 || verr.Fields[0].Field != "" {
+		t.Fatalf("expected a single params error, got %v", err)
+	}
+}
+
+func TestNewInvalidParamsResponse(t *testing.T) {
+	err := ValidateParams(MethodJobCancel, nil)
+	resp := NewInvalidParamsResponse(NewIntID(1), err)
+
+	if resp.Error == nil || resp.Error.Code != InvalidParams {
+		t.Fatalf("expected an InvalidParams error, got %+v", resp.Error)
+	}
+	if resp.Error.Message != "invalid params: id is required" {
+		t.Errorf("unexpected message %q", resp.Error.Message)
+	}
+
+	var data ValidationError
+	if err := json.Unmarshal(resp.Error.Data, &data); err != nil {
+		t.Fatalf("failed to decode data: %v", err)
+	}
+	if len(data.Fields) != 1 || data.Fields[0].Field != "id" {
+		t.Errorf("expected the field in the data, got %+v", data.Fields)
+	}
+}
+
+func TestMethodParams_Rules(t *testing.T) {
+	// Every rule must parse, so a typo fails here rather than on a request
+	for method, newParams := range methodParams {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("%s: %v", method, r)
+				}
+			}()
+			Validate(newParams())
+		}()
+	}
+}
@@ -0,0 +1,136 @@
+package repoindex
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"cosa/internal/git"
+)
+
+// maxKeywords is the most keywords of a description searched for.
+const maxKeywords = 8
+
+// pathMatchScore is what a file scores for a keyword in its path, against
+// one for a keyword in its contents.
+const pathMatchScore = 3
+
+// wordPattern matches identifiers, file names and paths in a description.
+var wordPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*(?:[./-][A-Za-z0-9_]+)*`)
+
+// stopWords are words too common in job descriptions to say anything about
+// which files a job touches. Words under four letters are skipped anyway.
+var stopWords = map[string]bool{
+	"about": true, "above": true, "after": true, "also": true, "always": true,
+	"before": true, "being": true, "below": true, "better": true, "both": true,
+	"change": true, "changes": true, "code": true, "could": true, "does": true,
+	"doesn": true, "each": true, "ensure": true, "every": true, "file": true,
+	"files": true, "from": true, "have": true, "implement": true, "instead": true,
+	"into": true, "just": true, "like": true, "make": true, "more": true,
+	"most": true, "must": true, "need": true, "needs": true, "never": true,
+	"only": true, "other": true, "over": true, "should": true, "some": true,
+	"such": true, "support": true, "than": true, "that": true, "their": true,
+	"them": true, "then": true, "there": true, "these": true, "they": true,
+	"this": true, "those": true, "under": true, "update": true, "used": true,
+	"using": true, "when": true, "where": true, "which": true, "while": true,
+	"will": true, "with": true, "without": true, "work": true, "would": true,
+}
+
+// Keywords returns the words of a job description worth searching the
+// repository for, most specific first: identifiers and paths, such as
+// handleJobAdd or internal/job, before plain words, and longer words
+// before shorter ones.
+func Keywords(description string) []string {
+	seen := make(map[string]bool)
+	var words []string
+	for _, w := range wordPattern.FindAllString(description, -1) {
+		lower := strings.ToLower(w)
+		if len(lower) < 4 || stopWords[lower] || seen[lower] {
+			continue
+		}
+		seen[lower] = true
+		words = append(words, w)
+	}
+
+	sort.SliceStable(words, func(i, j int) bool {
+		si, sj := specific(words[i]), specific(words[j])
+		if si != sj {
+			return si
+		}
+		return len(words[i]) > len(words[j])
+	})
+	if len(words) > maxKeywords {
+		words = words[:maxKeywords]
+	}
+	for i, w := range words {
+		words[i] = strings.ToLower(w)
+	}
+	return words
+}
+
+// specific reports whether a word looks like an identifier or path rather
+// than plain prose.
+func specific(word string) bool {
+	return strings.ContainsAny(word, "_./-") || strings.ToLower(word[1:]) != word[1:]
+}
+
+// RelevantFiles returns up to max files in the working tree at root that
+// are likely relevant to a job with the given description, best first.
+// Files score for each keyword of the description in their contents, and
+// more for one in their path. Vendored, test data and hidden files are
+// left out, as are keywords found in too many files to tell them apart.
+func RelevantFiles(root, description string, max int) ([]string, error) {
+	keywords := Keywords(description)
+	if len(keywords) == 0 || max <= 0 {
+		return nil, nil
+	}
+
+	tracked, err := git.TreeFiles(root, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	common := len(tracked) / 4
+	if common < 20 {
+		common = 20
+	}
+
+	scores := make(map[string]int)
+	for _, kw := range keywords {
+		matches, err := git.GrepFiles(root, kw)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) > common {
+			continue
+		}
+		for _, f := range matches {
+			scores[f]++
+		}
+	}
+	for _, f := range tracked {
+		name := strings.ToLower(f.Path)
+		for _, kw := range keywords {
+			if strings.Contains(name, kw) {
+				scores[f.Path] += pathMatchScore
+			}
+		}
+	}
+
+	var files []string
+	for f := range scores {
+		if !skipDir(path.Dir(f)) && !strings.HasPrefix(path.Base(f), ".") {
+			files = append(files, f)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if scores[files[i]] != scores[files[j]] {
+			return scores[files[i]] > scores[files[j]]
+		}
+		return files[i] < files[j]
+	})
+	if len(files) > max {
+		files = files[:max]
+	}
+	return files, nil
+}
@@ -0,0 +1,49 @@
+package repoindex
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestKeywords(t *testing.T) {
+	got := Keywords("Fix the retry logic in handleJobAdd so that internal/queue stops dropping jobs, and update the retry docs")
+	want := []string{"internal/queue", "handlejobadd", "dropping", "retry", "logic", "stops", "jobs", "docs"}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	if got := Keywords("Fix it and make the UI work"); len(got) != 0 {
+		t.Errorf("expected no keywords, got %v", got)
+	}
+}
+
+func TestRelevantFiles(t *testing.T) {
+	dir := initRepo(t, map[string]string{
+		"internal/queue/queue.go":   "package queue\n\nfunc Drain() { retryBackoff() }\n",
+		"internal/queue/backoff.go": "package queue\n\nfunc retryBackoff() {}\n",
+		"cmd/app/main.go":           "package main\n\nfunc main() {}\n",
+		"vendor/lib/retry.go":       "package lib\n\nfunc retryBackoff() {}\n",
+		"docs/retries.md":           "How retryBackoff works\n",
+	})
+
+	files, err := RelevantFiles(dir, "Make retryBackoff in the queue give up after five tries", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"internal/queue/backoff.go", "internal/queue/queue.go", "docs/retries.md"}
+	if !slices.Equal(files, want) {
+		t.Errorf("expected %v, got %v", want, files)
+	}
+
+	files, err = RelevantFiles(dir, "Make retryBackoff in the queue give up after five tries", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(files) != 1 {
+		t.Errorf("expected at most 1 file, got %v", files)
+	}
+
+	if files, _ := RelevantFiles(dir, "Rename the widget", 10); len(files) != 0 {
+		t.Errorf("expected no files for an unrelated description, got %v", files)
+	}
+}
@@ -0,0 +1,317 @@
+// Package repoindex builds a lightweight index of a repository: its
+// directories with file counts and sizes, the modules it declares, and the
+// exported symbols of its Go packages. A compact rendering goes into job
+// prompts so workers can orient themselves without exploring the tree.
+package repoindex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"cosa/internal/git"
+)
+
+// maxParseSize is the largest Go file parsed for symbols.
+const maxParseSize = 512 * 1024
+
+// maxSymbols is the most symbols kept for a directory.
+const maxSymbols = 50
+
+// Index describes a repository at a commit.
+type Index struct {
+	Commit  string    `json:"commit"`
+	BuiltAt time.Time `json:"built_at"`
+	Files   int       `json:"files"`
+	Bytes   int64     `json:"bytes"`
+	Modules []Module  `json:"modules,omitempty"`
+	Dirs    []Dir     `json:"dirs"`
+}
+
+// Module is a module or package declared by a manifest such as go.mod or
+// package.json.
+type Module struct {
+	Dir  string `json:"dir"`
+	Kind string `json:"kind"` // go, npm, cargo or python
+	Name string `json:"name"`
+}
+
+// Dir is a directory holding committed files, counting only the files
+// directly in it.
+type Dir struct {
+	Path    string   `json:"path"`
+	Files   int      `json:"files"`
+	Bytes   int64    `json:"bytes"`
+	Package string   `json:"package,omitempty"` // Go package name
+	Symbols []string `json:"symbols,omitempty"` // Exported Go types and functions
+}
+
+// manifests maps manifest file names to the kind of module they declare.
+var manifests = map[string]string{
+	"go.mod":         "go",
+	"package.json":   "npm",
+	"Cargo.toml":     "cargo",
+	"pyproject.toml": "python",
+}
+
+var (
+	goModulePattern = regexp.MustCompile(`(?m)^module\s+(\S+)`)
+	tomlNamePattern = regexp.MustCompile(`(?m)^name\s*=\s*"([^"]+)"`)
+)
+
+// Build indexes the files committed at HEAD in the repository at root.
+// Manifests and Go sources are read from the working tree.
+func Build(root string) (*Index, error) {
+	commit, err := git.HeadCommit(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HEAD: %w", err)
+	}
+	files, err := git.TreeFiles(root, commit)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := &Index{Commit: commit, BuiltAt: time.Now()}
+	dirs := make(map[string]*Dir)
+	symbols := make(map[string]map[string]bool)
+
+	for _, f := range files {
+		idx.Files++
+		idx.Bytes += f.Size
+
+		dirPath := path.Dir(f.Path)
+		d, ok := dirs[dirPath]
+		if !ok {
+			d = &Dir{Path: dirPath}
+			dirs[dirPath] = d
+		}
+		d.Files++
+		d.Bytes += f.Size
+
+		name := path.Base(f.Path)
+		if kind, ok := manifests[name]; ok {
+			if moduleName := readModuleName(filepath.Join(root, f.Path), kind); moduleName != "" {
+				idx.Modules = append(idx.Modules, Module{Dir: dirPath, Kind: kind, Name: moduleName})
+			}
+		}
+
+		if strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go") && f.Size <= maxParseSize {
+			pkg, exported := goSymbols(filepath.Join(root, f.Path))
+			if pkg == "" {
+				continue
+			}
+			d.Package = pkg
+			if symbols[dirPath] == nil {
+				symbols[dirPath] = make(map[string]bool)
+			}
+			for _, s := range exported {
+				symbols[dirPath][s] = true
+			}
+		}
+	}
+
+	for dirPath, d := range dirs {
+		for s := range symbols[dirPath] {
+			d.Symbols = append(d.Symbols, s)
+		}
+		sort.Strings(d.Symbols)
+		if len(d.Symbols) > maxSymbols {
+			d.Symbols = d.Symbols[:maxSymbols]
+		}
+		idx.Dirs = append(idx.Dirs, *d)
+	}
+	sort.Slice(idx.Dirs, func(i, j int) bool { return idx.Dirs[i].Path < idx.Dirs[j].Path })
+	sort.Slice(idx.Modules, func(i, j int) bool { return idx.Modules[i].Dir < idx.Modules[j].Dir })
+
+	return idx, nil
+}
+
+// readModuleName returns the module name declared by the manifest at
+// file, or "" if it cannot be read.
+func readModuleName(file, kind string) string {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+	switch kind {
+	case "go":
+		if m := goModulePattern.FindSubmatch(data); m != nil {
+			return string(m[1])
+		}
+	case "npm":
+		var pkg struct {
+			Name string `json:"name"`
+		}
+		if json.Unmarshal(data, &pkg) == nil {
+			return pkg.Name
+		}
+	default:
+		if m := tomlNamePattern.FindSubmatch(data); m != nil {
+			return string(m[1])
+		}
+	}
+	return ""
+}
+
+// goSymbols returns the package name of the Go file at file and its
+// exported top-level types and functions. Methods are left out.
+func goSymbols(file string) (string, []string) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", nil
+	}
+	f, err := parser.ParseFile(token.NewFileSet(), file, data, parser.SkipObjectResolution)
+	if err != nil {
+		return "", nil
+	}
+
+	var exported []string
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil && decl.Name.IsExported() {
+				exported = append(exported, decl.Name.Name)
+			}
+		case *ast.GenDecl:
+			if decl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok && ts.Name.IsExported() {
+					exported = append(exported, ts.Name.Name)
+				}
+			}
+		}
+	}
+	return f.Name.Name, exported
+}
+
+// Load reads an index saved at path. It returns nil without an error if
+// there is none.
+func Load(path string) (*Index, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	var idx Index
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("failed to parse index: %w", err)
+	}
+	return &idx, nil
+}
+
+// Save writes the index to path.
+func (idx *Index) Save(path string) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to save index: %w", err)
+	}
+	return nil
+}
+
+// Compact renders the index for a prompt in at most maxBytes. Vendored,
+// test data and hidden directories are left out, and those that do not fit
+// are counted at the end.
+func (idx *Index) Compact(maxBytes int) string {
+	var sb strings.Builder
+
+	commit := idx.Commit
+	if len(commit) > 7 {
+		commit = commit[:7]
+	}
+	sb.WriteString(fmt.Sprintf("Commit %s: %s, %s\n", commit, countFiles(idx.Files), formatBytes(idx.Bytes)))
+
+	if len(idx.Modules) > 0 {
+		sb.WriteString("Modules:\n")
+		for _, m := range idx.Modules {
+			sb.WriteString(fmt.Sprintf("- %s %s (%s)\n", m.Kind, m.Name, m.Dir))
+		}
+	}
+
+	sb.WriteString("Directories:\n")
+	omitted := 0
+	for _, d := range idx.Dirs {
+		if skipDir(d.Path) {
+			continue
+		}
+		line := dirLine(d)
+		if sb.Len()+len(line) > maxBytes {
+			omitted++
+			continue
+		}
+		sb.WriteString(line)
+	}
+	if omitted > 0 {
+		sb.WriteString(fmt.Sprintf("... %d more directories\n", omitted))
+	}
+
+	return strings.TrimSpace(sb.String())
+}
+
+// dirLine renders a directory as a list item with at most a dozen symbols.
+func dirLine(d Dir) string {
+	line := fmt.Sprintf("- %s: %s, %s", d.Path, countFiles(d.Files), formatBytes(d.Bytes))
+	if d.Package != "" {
+		line += ", package " + d.Package
+	}
+	if len(d.Symbols) > 0 {
+		shown := d.Symbols
+		if len(shown) > 12 {
+			shown = shown[:12]
+		}
+		line += ": " + strings.Join(shown, ", ")
+		if more := len(d.Symbols) - len(shown); more > 0 {
+			line += fmt.Sprintf(" (+%d more)", more)
+		}
+	}
+	return line + "\n"
+}
+
+// skipDir reports whether a directory is vendored, test data or hidden,
+// which are of no help finding one's way around.
+func skipDir(dirPath string) bool {
+	for _, part := range strings.Split(dirPath, "/") {
+		switch {
+		case part == "vendor", part == "node_modules", part == "testdata":
+			return true
+		case strings.HasPrefix(part, ".") && part != ".":
+			return true
+		}
+	}
+	return false
+}
+
+func countFiles(n int) string {
+	if n == 1 {
+		return "1 file"
+	}
+	return fmt.Sprintf("%d files", n)
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%d KB", n>>10)
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
@@ -0,0 +1,122 @@
+package repoindex
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func initRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"config", "user.name", "test"},
+		{"config", "user.email", "test@example.com"},
+		{"add", "-A"},
+		{"commit", "-q", "-m", "Initial commit"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s: %v", args, out, err)
+		}
+	}
+	return dir
+}
+
+func TestBuild(t *testing.T) {
+	dir := initRepo(t, map[string]string{
+		"go.mod":                     "module example.com/shop\n\ngo 1.24\n",
+		"internal/auth/auth.go":      "package auth\n\ntype Session struct{}\n\nfunc Login() {}\n\nfunc (s *Session) Close() {}\n\nfunc hash() {}\n",
+		"internal/auth/auth_test.go": "package auth\n\nfunc TestHelper() {}\n",
+		"web/package.json":           `{"name": "shop-web"}`,
+		"web/vendor/lib.js":          "x",
+	})
+
+	idx, err := Build(dir)
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+	if idx.Files != 5 || idx.Commit == "" {
+		t.Errorf("expected 5 files at a commit, got %d at %q", idx.Files, idx.Commit)
+	}
+	if len(idx.Modules) != 2 || idx.Modules[0].Name != "example.com/shop" || idx.Modules[1].Name != "shop-web" {
+		t.Errorf("expected the go and npm modules, got %+v", idx.Modules)
+	}
+
+	var auth *Dir
+	for i := range idx.Dirs {
+		if idx.Dirs[i].Path == "internal/auth" {
+			auth = &idx.Dirs[i]
+		}
+	}
+	if auth == nil {
+		t.Fatalf("expected internal/auth in %+v", idx.Dirs)
+	}
+	if auth.Files != 2 || auth.Package != "auth" || strings.Join(auth.Symbols, ",") != "Login,Session" {
+		t.Errorf("expected two files and the exported types and functions of package auth, got %+v", auth)
+	}
+
+	path := filepath.Join(t.TempDir(), "index.json")
+	if err := idx.Save(path); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil || loaded.Commit != idx.Commit || len(loaded.Dirs) != len(idx.Dirs) {
+		t.Errorf("expected the index to survive a reload, got %+v (%v)", loaded, err)
+	}
+
+	if missing, err := Load(filepath.Join(t.TempDir(), "index.json")); missing != nil || err != nil {
+		t.Errorf("expected no index and no error for a missing file, got %+v (%v)", missing, err)
+	}
+}
+
+func TestIndex_Compact(t *testing.T) {
+	idx := &Index{
+		Commit:  "0123456789abcdef",
+		Files:   4,
+		Bytes:   3 << 10,
+		Modules: []Module{{Dir: ".", Kind: "go", Name: "example.com/shop"}},
+		Dirs: []Dir{
+			{Path: ".", Files: 1, Bytes: 40},
+			{Path: "internal/auth", Files: 2, Bytes: 2 << 10, Package: "auth", Symbols: []string{"Login", "Session"}},
+			{Path: "web/node_modules/left-pad", Files: 1, Bytes: 900},
+		},
+	}
+
+	got := idx.Compact(4096)
+	for _, want := range []string{
+		"Commit 0123456: 4 files, 3 KB",
+		"- go example.com/shop (.)",
+		"- internal/auth: 2 files, 2 KB, package auth: Login, Session",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "node_modules") {
+		t.Errorf("expected vendored directories to be left out, got:\n%s", got)
+	}
+
+	small := idx.Compact(100)
+	if len(small) > 130 || !strings.Contains(small, "more directories") {
+		t.Errorf("expected directories that do not fit to be counted, got:\n%s", small)
+	}
+}
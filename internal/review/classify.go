@@ -0,0 +1,102 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"cosa/internal/config"
+	"cosa/internal/git"
+	"cosa/internal/job"
+)
+
+// classifyInputLimit caps the diff sent to the classification model.
+// Larger diffs are outlined heuristically first.
+const classifyInputLimit = 20000
+
+// classifyTimeout bounds the model call, which holds up the job's merge.
+const classifyTimeout = 30 * time.Second
+
+// OutcomeClassifier decides what kind of change a completed job made.
+type OutcomeClassifier struct {
+	kind   string // heuristic, model or off
+	binary string // Claude binary for the model classifier
+	model  string
+}
+
+// NewOutcomeClassifier creates a classifier of the given config.Classifiers
+// kind. Unknown kinds classify heuristically.
+func NewOutcomeClassifier(kind, binary, model string) *OutcomeClassifier {
+	return &OutcomeClassifier{kind: kind, binary: binary, model: model}
+}
+
+// Enabled reports whether jobs are classified at all.
+func (c *OutcomeClassifier) Enabled() bool {
+	return c.kind != config.ClassifierOff
+}
+
+// Classify returns the outcome of a job with the given brief and diff, and
+// the classifier that decided it. diff may be nil. The model classifier
+// falls back to the heuristic one when it fails or gives no valid outcome.
+func (c *OutcomeClassifier) Classify(ctx context.Context, brief string, diff *git.DiffResult) (string, string) {
+	var files []string
+	if diff != nil {
+		files = diff.FilesChanged
+	}
+
+	if c.kind == config.ClassifierModel {
+		if outcome, err := c.classifyWithModel(ctx, brief, diff); err == nil {
+			return outcome, config.ClassifierModel
+		}
+	}
+	return job.ClassifyOutcome(brief, files), config.ClassifierHeuristic
+}
+
+func (c *OutcomeClassifier) classifyWithModel(ctx context.Context, brief string, diff *git.DiffResult) (string, error) {
+	binary := c.binary
+	if binary == "" {
+		binary = "claude"
+	}
+
+	changes := "(no diff available)"
+	if diff != nil {
+		changes = diff.Diff
+		if len(changes) > classifyInputLimit {
+			changes, _ = heuristicSummarizer{}.Summarize(ctx, diff, classifyInputLimit)
+		}
+	}
+
+	prompt := fmt.Sprintf(`Classify the change below as exactly one of: %s.
+Reply with that one word only.
+
+Task:
+%s
+
+%s`, strings.Join(job.Outcomes, ", "), brief, "```diff\n"+changes+"\n```")
+
+	ctx, cancel := context.WithTimeout(ctx, classifyTimeout)
+	defer cancel()
+
+	args := []string{"--print"}
+	if c.model != "" {
+		args = append(args, "--model", c.model)
+	}
+	args = append(args, "--max-turns", "1", "-p", prompt)
+
+	out, err := exec.CommandContext(ctx, binary, args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("claude classification failed: %w", err)
+	}
+
+	fields := strings.Fields(strings.ToLower(string(out)))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("empty classification")
+	}
+	outcome := strings.Trim(fields[0], ".,:;\"'`*")
+	if !job.ValidOutcome(outcome) {
+		return "", fmt.Errorf("unknown outcome %q", outcome)
+	}
+	return outcome, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os/exec"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"cosa/internal/job"
@@ -25,15 +26,19 @@ type ReviewResult struct {
 	Summary  string   `json:"summary"`
 	Feedback string   `json:"feedback"`
 	MustFix  []string `json:"must_fix,omitempty"`
+
+	// Verdict on each item of the job's review checklist
+	Checklist []job.ChecklistResult `json:"checklist,omitempty"`
 }
 
 // ReviewContext provides context for the code review.
 type ReviewContext struct {
-	Job         *job.Job     `json:"job"`
-	Diff        string       `json:"diff"`
-	GateResults []GateResult `json:"gate_results"`
-	BaseBranch  string       `json:"base_branch"`
-	WorkerName  string       `json:"worker_name"`
+	Job            *job.Job     `json:"job"`
+	Diff           string       `json:"diff"`
+	DiffSummarizer string       `json:"diff_summarizer,omitempty"` // Set when Diff is a summary
+	GateResults    []GateResult `json:"gate_results"`
+	BaseBranch     string       `json:"base_branch"`
+	WorkerName     string       `json:"worker_name"`
 }
 
 // ConsigliereConfig configures the Consigliere reviewer.
@@ -89,7 +94,7 @@ func (c *Consigliere) Review(ctx context.Context, reviewCtx *ReviewContext) (*Re
 		return nil, fmt.Errorf("claude review failed: %w: %s", err, string(output))
 	}
 
-	return c.parseReviewOutput(string(output))
+	return c.parseReviewOutput(string(output), reviewCtx.Job.GetReviewChecklist())
 }
 
 // buildReviewPrompt constructs the review prompt for Claude.
@@ -108,29 +113,54 @@ You MUST include these sections in your response:
 
 DECISION: [APPROVED or REJECTED]
 SUMMARY: [One sentence summary of the changes]
-FEEDBACK: [Detailed feedback about the code quality, potential issues, and suggestions]
+`)
+	checklist := ctx.Job.GetReviewChecklist()
+	if len(checklist) > 0 {
+		sb.WriteString(`CHECKLIST: [One line per review checklist item, in order: "<number>. PASS - <note>" or "<number>. FAIL - <note>"]
+`)
+	}
+	sb.WriteString(`FEEDBACK: [Detailed feedback about the code quality, potential issues, and suggestions]
 MUST_FIX: [Comma-separated list of critical issues that must be fixed before approval, or "none" if approved]
 
 ## Job Information
 `)
 	sb.WriteString(fmt.Sprintf("Task: %s\n", ctx.Job.Description))
+	if body := ctx.Job.GetBody(); body != "" {
+		sb.WriteString(fmt.Sprintf("Details:\n%s\n", strings.TrimSpace(body)))
+	}
 	sb.WriteString(fmt.Sprintf("Worker: %s\n", ctx.WorkerName))
 	sb.WriteString(fmt.Sprintf("Base Branch: %s\n", ctx.BaseBranch))
 
+	if len(checklist) > 0 {
+		sb.WriteString("\n## Review Checklist\n")
+		sb.WriteString("Address every item below with PASS or FAIL in the CHECKLIST section. Do not approve if any item fails.\n")
+		for i, item := range checklist {
+			sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, item))
+		}
+	}
+
 	if len(ctx.GateResults) > 0 {
 		sb.WriteString("\n## Quality Gate Results\n")
 		sb.WriteString(GateResultsSummary(ctx.GateResults))
 		sb.WriteString("\n")
 	}
 
-	sb.WriteString("\n## Diff to Review\n```diff\n")
-	// Truncate diff if too long
-	diff := ctx.Diff
-	if len(diff) > 50000 {
-		diff = diff[:50000] + "\n... (diff truncated)"
+	if ctx.DiffSummarizer != "" {
+		sb.WriteString("\n## Diff Summary\n")
+		sb.WriteString(fmt.Sprintf("The diff is too large to review in full, so it was summarized by the %s summarizer. ", ctx.DiffSummarizer))
+		sb.WriteString("Review the changes it describes and any file diffs it includes.\n\n")
+		sb.WriteString(ctx.Diff)
+		sb.WriteString("\n")
+	} else {
+		sb.WriteString("\n## Diff to Review\n```diff\n")
+		// Truncate diff if too long
+		diff := ctx.Diff
+		if len(diff) > 50000 {
+			diff = diff[:50000] + "\n... (diff truncated)"
+		}
+		sb.WriteString(diff)
+		sb.WriteString("\n```\n")
 	}
-	sb.WriteString(diff)
-	sb.WriteString("\n```\n")
 
 	sb.WriteString(`
 ## Review Criteria
@@ -146,8 +176,10 @@ Provide your structured response now.
 	return sb.String()
 }
 
-// parseReviewOutput parses Claude's review response.
-func (c *Consigliere) parseReviewOutput(output string) (*ReviewResult, error) {
+// parseReviewOutput parses Claude's review response. Checklist items that
+// are not addressed count as failed, and a failed item turns an approval
+// into a rejection.
+func (c *Consigliere) parseReviewOutput(output string, checklist []string) (*ReviewResult, error) {
 	result := &ReviewResult{
 		Decision: DecisionRejected, // Default to rejected if parsing fails
 	}
@@ -192,6 +224,16 @@ func (c *Consigliere) parseReviewOutput(output string) (*ReviewResult, error) {
 		}
 	}
 
+	// Parse CHECKLIST - capture everything between CHECKLIST: and FEEDBACK: (or end)
+	if len(checklist) > 0 {
+		var section string
+		checklistRe := regexp.MustCompile(`(?is)CHECKLIST:\s*(.+?)(?:FEEDBACK:|MUST_FIX:|$)`)
+		if matches := checklistRe.FindStringSubmatch(output); len(matches) > 1 {
+			section = matches[1]
+		}
+		result.Checklist = parseChecklist(section, checklist)
+	}
+
 	// If we couldn't parse a decision, try to infer from content
 	if result.Summary == "" && result.Feedback == "" {
 		// Fall back to using the entire output as feedback
@@ -205,9 +247,51 @@ func (c *Consigliere) parseReviewOutput(output string) (*ReviewResult, error) {
 		}
 	}
 
+	// Every checklist item must pass for the review to be approved
+	for _, item := range result.Checklist {
+		if !item.Passed {
+			result.Decision = DecisionRejected
+			result.MustFix = append(result.MustFix, "Checklist: "+item.Item)
+		}
+	}
+
 	return result, nil
 }
 
+// checklistLineRe matches a checklist verdict such as "2. FAIL - flaky sleep".
+var checklistLineRe = regexp.MustCompile(`(?i)^\s*[-*]?\s*(\d+)[.)]?\s*\[?(PASS|FAIL)\]?\s*[-:]?\s*(.*)$`)
+
+// parseChecklist matches the verdicts in section to the checklist items by
+// number. Items without a verdict are recorded as failed.
+func parseChecklist(section string, checklist []string) []job.ChecklistResult {
+	results := make([]job.ChecklistResult, len(checklist))
+	addressed := make([]bool, len(checklist))
+	for i, item := range checklist {
+		results[i] = job.ChecklistResult{Item: item}
+	}
+
+	for _, line := range strings.Split(section, "\n") {
+		matches := checklistLineRe.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		n, err := strconv.Atoi(matches[1])
+		if err != nil || n < 1 || n > len(checklist) || addressed[n-1] {
+			continue
+		}
+		addressed[n-1] = true
+		results[n-1].Passed = strings.EqualFold(matches[2], "PASS")
+		results[n-1].Note = strings.TrimSpace(matches[3])
+	}
+
+	for i := range results {
+		if !addressed[i] {
+			results[i].Note = "not addressed by the reviewer"
+		}
+	}
+	return results
+}
+
 // ReviewResultFromOutput parses review output line by line (alternative parser).
 func ReviewResultFromOutput(output string) *ReviewResult {
 	result := &ReviewResult{
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"cosa/internal/display"
 	"cosa/internal/git"
 	"cosa/internal/job"
 	"cosa/internal/ledger"
@@ -36,6 +37,11 @@ type ReviewStatus struct {
 	Feedback    string      `json:"feedback,omitempty"`
 	Error       string      `json:"error,omitempty"`
 	GatesPassed bool        `json:"gates_passed"`
+
+	Checklist []job.ChecklistResult `json:"checklist,omitempty"`
+
+	cancel     context.CancelFunc // Stops the review flow
+	overridden bool               // A human decided the review instead
 }
 
 // CoordinatorConfig configures the review coordinator.
@@ -47,6 +53,24 @@ type CoordinatorConfig struct {
 	ClaudeConfig ConsigliereConfig
 	GateConfig   GateRunnerConfig
 	BaseBranch   string
+	Summarizer   *Summarizer // Condenses large diffs, nil reviews them in full
+
+	// MaxRejections is how many rejections a job and its revisions get
+	// before the job needs attention instead of another revision. 0 keeps
+	// revising.
+	MaxRejections int
+
+	// MaxRounds is how many revision rounds a job gets before a rejection
+	// fails it for good. 0 keeps revising.
+	MaxRounds int
+
+	// OnNeedsAttention is called to hand a job to a human. If nil the job
+	// is only marked.
+	OnNeedsAttention func(j *job.Job, kind, reason string)
+
+	// OnReviewed is called once the reviewer's decision on a job has been
+	// acted on. It may be nil.
+	OnReviewed func(j *job.Job)
 }
 
 // Coordinator orchestrates the code review flow.
@@ -58,7 +82,12 @@ type Coordinator struct {
 	gateRunner      *GateRunner
 	consigliere     *Consigliere
 	decisionHandler *DecisionHandler
+	summarizer      *Summarizer
 	baseBranch      string
+	maxRejections   int
+	maxRounds       int
+	onAttention     func(j *job.Job, kind, reason string)
+	onReviewed      func(j *job.Job)
 
 	activeReviews map[string]*ReviewStatus
 	mu            sync.RWMutex
@@ -79,7 +108,12 @@ func NewCoordinator(cfg CoordinatorConfig) *Coordinator {
 			Ledger:     cfg.Ledger,
 			BaseBranch: cfg.BaseBranch,
 		}),
+		summarizer:    cfg.Summarizer,
 		baseBranch:    cfg.BaseBranch,
+		maxRejections: cfg.MaxRejections,
+		maxRounds:     cfg.MaxRounds,
+		onAttention:   cfg.OnNeedsAttention,
+		onReviewed:    cfg.OnReviewed,
 		activeReviews: make(map[string]*ReviewStatus),
 	}
 }
@@ -87,15 +121,19 @@ func NewCoordinator(cfg CoordinatorConfig) *Coordinator {
 // StartReview begins the review process for a completed job.
 func (c *Coordinator) StartReview(ctx context.Context, j *job.Job, w *worker.Worker) error {
 	// Mark job for review
-	j.MarkForReview()
+	if err := j.MarkForReview(); err != nil {
+		return err
+	}
 
 	// Create review status
+	ctx, cancel := context.WithCancel(ctx)
 	status := &ReviewStatus{
 		JobID:      j.ID,
 		WorkerID:   w.ID,
 		WorkerName: w.Name,
 		Phase:      PhaseGates,
 		StartedAt:  time.Now(),
+		cancel:     cancel,
 	}
 
 	c.mu.Lock()
@@ -119,8 +157,11 @@ func (c *Coordinator) StartReview(ctx context.Context, j *job.Job, w *worker.Wor
 func (c *Coordinator) runReviewFlow(ctx context.Context, j *job.Job, w *worker.Worker, status *ReviewStatus) {
 	defer func() {
 		c.mu.Lock()
-		delete(c.activeReviews, j.ID)
+		if c.activeReviews[j.ID] == status {
+			delete(c.activeReviews, j.ID)
+		}
 		c.mu.Unlock()
+		status.cancel()
 	}()
 
 	// Phase 1: Run quality gates
@@ -137,6 +178,9 @@ func (c *Coordinator) runReviewFlow(ctx context.Context, j *job.Job, w *worker.W
 	}
 
 	if !AllPassed(gateResults) {
+		if c.isOverridden(status) {
+			return
+		}
 		failed := FailedGates(gateResults)
 		c.ledger.Append(ledger.EventGateFailed, ledger.GateEventData{
 			JobID:      j.ID,
@@ -145,7 +189,10 @@ func (c *Coordinator) runReviewFlow(ctx context.Context, j *job.Job, w *worker.W
 			Output:     failed[0].Output,
 		})
 		status.GatesPassed = false
-		c.handleReviewError(j, status, fmt.Sprintf("quality gates failed: %s", GateResultsSummary(gateResults)))
+		status.Error = fmt.Sprintf("quality gates failed: %s", GateResultsSummary(gateResults))
+		status.Phase = PhaseFailed
+		// A human decides whether to override the gates or rerun the job
+		c.needsAttention(j, job.AttentionGateOverride, status.Error)
 		return
 	}
 
@@ -170,15 +217,32 @@ func (c *Coordinator) runReviewFlow(ctx context.Context, j *job.Job, w *worker.W
 		return
 	}
 
+	// Summarize diffs too large to review in full
+	condensed := &DiffSummary{Text: diff.Diff}
+	if c.summarizer != nil {
+		condensed = c.summarizer.Condense(ctx, diff)
+	}
+	if condensed.Summarizer != "" {
+		c.ledger.Append(ledger.EventType("review.diff_summarized"), map[string]interface{}{
+			"job_id":     j.ID,
+			"worker_id":  w.ID,
+			"summarizer": condensed.Summarizer,
+			"diff_size":  len(diff.Diff),
+			"size":       len(condensed.Text),
+			"errors":     condensed.Errors,
+		})
+	}
+
 	// Phase 3: AI review
 	c.updatePhase(status, PhaseReview)
 
 	reviewCtx := &ReviewContext{
-		Job:         j,
-		Diff:        diff.Diff,
-		GateResults: gateResults,
-		BaseBranch:  c.baseBranch,
-		WorkerName:  w.Name,
+		Job:            j,
+		Diff:           condensed.Text,
+		DiffSummarizer: condensed.Summarizer,
+		GateResults:    gateResults,
+		BaseBranch:     c.baseBranch,
+		WorkerName:     w.Name,
 	}
 
 	reviewResult, err := c.consigliere.Review(ctx, reviewCtx)
@@ -187,12 +251,25 @@ func (c *Coordinator) runReviewFlow(ctx context.Context, j *job.Job, w *worker.W
 		return
 	}
 
+	// Phase 4: Handle decision, unless a human has made it already
+	if !c.enterDecision(status) {
+		return
+	}
+
 	status.Decision = reviewResult.Decision
 	status.Summary = reviewResult.Summary
 	status.Feedback = reviewResult.Feedback
+	status.Checklist = reviewResult.Checklist
 
-	// Phase 4: Handle decision
-	c.updatePhase(status, PhaseDecision)
+	if len(reviewResult.Checklist) > 0 {
+		j.SetChecklistResults(reviewResult.Checklist)
+	}
+	j.SetVerdict(job.ReviewVerdict{
+		Decision: string(reviewResult.Decision),
+		Summary:  reviewResult.Summary,
+		MustFix:  reviewResult.MustFix,
+	})
+	c.jobStore.Save(j)
 
 	if reviewResult.Decision == DecisionApproved {
 		if err := c.decisionHandler.HandleApproval(ctx, j, w, reviewResult); err != nil {
@@ -205,6 +282,34 @@ func (c *Coordinator) runReviewFlow(ctx context.Context, j *job.Job, w *worker.W
 			WorkerID: w.ID,
 			Summary:  reviewResult.Summary,
 		})
+		c.jobStore.Save(j)
+		SettleRevised(c.jobStore, j)
+	} else if round := j.GetRevisionRound(); c.maxRounds > 0 && round >= c.maxRounds {
+		// Out of revision rounds; the job and the work it revises fail
+		j.SetReviewFeedback(reviewResult.MustFix)
+		c.ledger.Append(ledger.EventReviewRejected, ledger.ReviewEventData{
+			JobID:    j.ID,
+			WorkerID: w.ID,
+			Summary:  reviewResult.Summary,
+			Feedback: reviewResult.Feedback,
+		})
+		if err := j.Fail(fmt.Sprintf("rejected after %d revision rounds: %s", round, reviewResult.Summary)); err != nil {
+			c.transitionRejected(j, err)
+		}
+		c.jobStore.Save(j)
+		SettleRevised(c.jobStore, j)
+	} else if rejections := c.rejectionCount(j); c.maxRejections > 0 && rejections >= c.maxRejections {
+		// Stop revising a job the reviewer keeps rejecting; the feedback
+		// is kept for a retry
+		j.SetReviewFeedback(reviewResult.MustFix)
+		c.ledger.Append(ledger.EventReviewRejected, ledger.ReviewEventData{
+			JobID:    j.ID,
+			WorkerID: w.ID,
+			Summary:  reviewResult.Summary,
+			Feedback: reviewResult.Feedback,
+		})
+		c.needsAttention(j, job.AttentionReviewRejected,
+			fmt.Sprintf("rejected %d times: %s", rejections, reviewResult.Summary))
 	} else {
 		revisionJob, err := c.decisionHandler.HandleRejection(ctx, j, w, reviewResult)
 		if err != nil {
@@ -220,17 +325,31 @@ func (c *Coordinator) runReviewFlow(ctx context.Context, j *job.Job, w *worker.W
 			RevisionJobID: revisionJob.ID,
 		})
 
-		// Queue the revision job for the same worker
+		// The revision is held for the worker that did the work; the
+		// rejected job stays in review until the revision settles it
 		c.jobQueue.Enqueue(revisionJob)
 	}
 
+	if c.onReviewed != nil {
+		c.onReviewed(j)
+	}
 	c.updatePhase(status, PhaseCompleted)
 }
 
-// handleReviewError handles errors during the review process.
+// handleReviewError handles errors during the review process. status is nil
+// for a review a human decided.
 func (c *Coordinator) handleReviewError(j *job.Job, status *ReviewStatus, errMsg string) {
-	status.Error = errMsg
-	status.Phase = PhaseFailed
+	if status != nil {
+		// An overridden review fails as it is abandoned; that is not the
+		// job's fault
+		if c.isOverridden(status) {
+			return
+		}
+		c.mu.Lock()
+		status.Error = errMsg
+		status.Phase = PhaseFailed
+		c.mu.Unlock()
+	}
 
 	c.ledger.Append(ledger.EventReviewRejected, ledger.ReviewEventData{
 		JobID: j.ID,
@@ -238,7 +357,189 @@ func (c *Coordinator) handleReviewError(j *job.Job, status *ReviewStatus, errMsg
 	})
 
 	// Mark job as failed so it reaches a terminal state and doesn't block dependent jobs
-	j.Fail(fmt.Sprintf("review failed: %s", errMsg))
+	if err := j.Fail(fmt.Sprintf("review failed: %s", errMsg)); err != nil {
+		c.transitionRejected(j, err)
+		return
+	}
+	c.jobStore.Save(j)
+	SettleRevised(c.jobStore, j)
+}
+
+// rejectionCount returns how many times j and the jobs it revises have been
+// rejected, counting the rejection being handled.
+func (c *Coordinator) rejectionCount(j *job.Job) int {
+	count := 1
+	seen := map[string]bool{j.ID: true}
+	for id := j.RevisionOf; id != "" && !seen[id]; {
+		prev, exists := c.jobStore.Get(id)
+		if !exists {
+			break
+		}
+		seen[id] = true
+		count++
+		id = prev.RevisionOf
+	}
+	return count
+}
+
+// needsAttention hands j to a human.
+func (c *Coordinator) needsAttention(j *job.Job, kind, reason string) {
+	if c.onAttention != nil {
+		c.onAttention(j, kind, reason)
+		return
+	}
+	if err := j.MarkNeedsAttention(kind, reason); err != nil {
+		c.transitionRejected(j, err)
+		return
+	}
+	c.jobStore.Save(j)
+}
+
+// transitionRejected records a status change j refused, so the review's
+// outcome is not silently lost.
+func (c *Coordinator) transitionRejected(j *job.Job, err error) {
+	c.ledger.Append(ledger.EventType("job.transition_rejected"), ledger.JobEventData{
+		ID:    j.ID,
+		Error: err.Error(),
+	})
+}
+
+// isOverridden reports whether a human decided the review instead.
+func (c *Coordinator) isOverridden(status *ReviewStatus) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return status.overridden
+}
+
+// enterDecision moves a review on to acting on the reviewer's decision,
+// after which it can no longer be overridden. It returns false if the
+// review was overridden first.
+func (c *Coordinator) enterDecision(status *ReviewStatus) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if status.overridden {
+		return false
+	}
+	status.Phase = PhaseDecision
+	return true
+}
+
+// Override decides the review of a job in place of the consigliere: it
+// approves and merges the job, or rejects it with reason as the feedback
+// for a revision job, which it returns. Rejected without revise, the job
+// fails instead. A review still running is abandoned; one already acting
+// on its own decision cannot be overridden. by is who overrode the
+// review, for the ledger.
+func (c *Coordinator) Override(ctx context.Context, j *job.Job, w *worker.Worker, decision Decision, reason, by string, revise bool) (*job.Job, error) {
+	if status := j.GetStatus(); status != job.StatusReview {
+		return nil, fmt.Errorf("job %s is not in review (status: %s)", display.ShortID(j.ID), status)
+	}
+
+	c.mu.Lock()
+	phase := ""
+	if status, active := c.activeReviews[j.ID]; active {
+		switch status.Phase {
+		case PhaseDecision, PhaseCompleted, PhaseFailed:
+			c.mu.Unlock()
+			return nil, fmt.Errorf("the review of job %s has already reached a decision", display.ShortID(j.ID))
+		}
+		phase = string(status.Phase)
+		status.overridden = true
+		status.cancel()
+		delete(c.activeReviews, j.ID)
+	}
+	c.mu.Unlock()
+
+	// A rejected job waits in review for its revision, which settles it
+	if phase == "" {
+		if rev := c.openRevision(j); rev != nil {
+			return nil, fmt.Errorf("job %s is being revised by job %s", display.ShortID(j.ID), display.ShortID(rev.ID))
+		}
+	}
+
+	verb := "Approved"
+	if decision == DecisionRejected {
+		verb = "Rejected"
+	}
+	result := &ReviewResult{
+		Decision: decision,
+		Summary:  fmt.Sprintf("%s by %s: %s", verb, by, reason),
+		Feedback: reason,
+	}
+	if decision == DecisionRejected {
+		result.MustFix = []string{reason}
+	}
+
+	c.ledger.Append(ledger.EventType("review.overridden"), map[string]string{
+		"job_id":   j.ID,
+		"decision": string(decision),
+		"reason":   reason,
+		"by":       by,
+		"phase":    phase, // Of the abandoned review, if one was running
+	})
+	j.SetVerdict(job.ReviewVerdict{
+		Decision: string(decision),
+		Summary:  result.Summary,
+		MustFix:  result.MustFix,
+	})
+	c.jobStore.Save(j)
+
+	var revisionJob *job.Job
+	if decision == DecisionApproved {
+		if err := c.decisionHandler.HandleApproval(ctx, j, w, result); err != nil {
+			msg := fmt.Sprintf("merge failed: %v", err)
+			c.handleReviewError(j, nil, msg)
+			return nil, fmt.Errorf("%s", msg)
+		}
+		c.ledger.Append(ledger.EventReviewApproved, ledger.ReviewEventData{
+			JobID:    j.ID,
+			WorkerID: w.ID,
+			Summary:  result.Summary,
+		})
+		c.jobStore.Save(j)
+		SettleRevised(c.jobStore, j)
+	} else if !revise {
+		if err := j.Fail("rejected in review: " + reason); err != nil {
+			return nil, err
+		}
+		c.jobStore.Save(j)
+		c.ledger.Append(ledger.EventReviewRejected, ledger.ReviewEventData{
+			JobID:    j.ID,
+			WorkerID: w.ID,
+			Summary:  result.Summary,
+			Feedback: result.Feedback,
+		})
+		SettleRevised(c.jobStore, j)
+	} else {
+		var err error
+		revisionJob, err = c.decisionHandler.HandleRejection(ctx, j, w, result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create revision job: %w", err)
+		}
+		c.ledger.Append(ledger.EventReviewRejected, ledger.ReviewEventData{
+			JobID:         j.ID,
+			WorkerID:      w.ID,
+			Summary:       result.Summary,
+			Feedback:      result.Feedback,
+			RevisionJobID: revisionJob.ID,
+		})
+		c.jobQueue.Enqueue(revisionJob)
+	}
+
+	if c.onReviewed != nil {
+		c.onReviewed(j)
+	}
+	return revisionJob, nil
+}
+
+// openRevision returns the unfinished revision of j, if any.
+func (c *Coordinator) openRevision(j *job.Job) *job.Job {
+	for _, other := range c.jobStore.List() {
+		if other.RevisionOf == j.ID && !other.IsTerminal() {
+			return other
+		}
+	}
+	return nil
 }
 
 // updatePhase updates the current phase of a review.
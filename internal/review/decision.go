@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"cosa/internal/display"
 	"cosa/internal/git"
 	"cosa/internal/job"
 	"cosa/internal/ledger"
@@ -93,12 +94,14 @@ func (d *DecisionHandler) HandleApproval(ctx context.Context, j *job.Job, w *wor
 	})
 
 	// Mark job as completed
-	j.Complete(result.Summary)
-
+	if err := j.Complete(result.Summary); err != nil {
+		return fmt.Errorf("merged, but the job could not be completed: %w", err)
+	}
 	return nil
 }
 
-// HandleRejection handles a rejected review by creating a revision job.
+// HandleRejection handles a rejected review by creating a revision job,
+// held for the worker that did the rejected work.
 func (d *DecisionHandler) HandleRejection(ctx context.Context, j *job.Job, w *worker.Worker, result *ReviewResult) (*job.Job, error) {
 	// Build feedback for the revision job
 	feedback := buildRevisionFeedback(j, result)
@@ -107,7 +110,11 @@ func (d *DecisionHandler) HandleRejection(ctx context.Context, j *job.Job, w *wo
 	revisionJob := job.New(fmt.Sprintf("Revision of: %s", j.Description))
 	revisionJob.SetPriority(j.Priority + 1) // Higher priority for revisions
 	revisionJob.SetRevisionOf(j.ID)
+	revisionJob.SetRevisionRound(j.GetRevisionRound() + 1)
+	revisionJob.SetPreferredWorker(w.ID)
 	revisionJob.SetReviewFeedback(result.MustFix)
+	revisionJob.SetReviewChecklist(j.GetReviewChecklist())
+	revisionJob.SetBody(j.GetBody())
 
 	// Update the original job description to include feedback
 	revisionJob.Description = feedback
@@ -125,6 +132,38 @@ func (d *DecisionHandler) HandleRejection(ctx context.Context, j *job.Job, w *wo
 	return revisionJob, nil
 }
 
+// SettleRevised closes out the jobs a finished revision job revises. They
+// stay in review while their revisions are worked on and take the outcome
+// of the last one: completed if it was completed, failed otherwise.
+func SettleRevised(store *job.Store, j *job.Job) {
+	if !j.IsTerminal() {
+		return
+	}
+	status := j.GetStatus()
+
+	seen := map[string]bool{j.ID: true}
+	for id := j.RevisionOf; id != "" && !seen[id]; {
+		prev, exists := store.Get(id)
+		if !exists {
+			break
+		}
+		seen[id] = true
+
+		if prev.GetStatus() == job.StatusReview {
+			var err error
+			if status == job.StatusCompleted {
+				err = prev.Complete(fmt.Sprintf("Revised in job %s", display.ShortID(j.ID)))
+			} else {
+				err = prev.Fail(fmt.Sprintf("revision %s %s", display.ShortID(j.ID), status))
+			}
+			if err == nil {
+				store.Save(prev)
+			}
+		}
+		id = prev.RevisionOf
+	}
+}
+
 // buildRevisionFeedback constructs the feedback prompt for a revision job.
 func buildRevisionFeedback(originalJob *job.Job, result *ReviewResult) string {
 	var sb strings.Builder
@@ -140,6 +179,24 @@ func buildRevisionFeedback(originalJob *job.Job, result *ReviewResult) string {
 		sb.WriteString(fmt.Sprintf("Feedback:\n%s\n\n", result.Feedback))
 	}
 
+	var failed []job.ChecklistResult
+	for _, item := range result.Checklist {
+		if !item.Passed {
+			failed = append(failed, item)
+		}
+	}
+	if len(failed) > 0 {
+		sb.WriteString("Failed checklist items:\n")
+		for _, item := range failed {
+			if item.Note != "" {
+				sb.WriteString(fmt.Sprintf("- %s: %s\n", item.Item, item.Note))
+			} else {
+				sb.WriteString(fmt.Sprintf("- %s\n", item.Item))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
 	if len(result.MustFix) > 0 {
 		sb.WriteString("Issues to fix:\n")
 		for i, issue := range result.MustFix {
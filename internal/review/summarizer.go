@@ -0,0 +1,453 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"cosa/internal/config"
+	"cosa/internal/git"
+)
+
+// DiffSummarizer condenses a diff into at most budget bytes of text.
+type DiffSummarizer interface {
+	Name() string
+	Summarize(ctx context.Context, diff *git.DiffResult, budget int) (string, error)
+}
+
+// DiffSummary is a condensed diff.
+type DiffSummary struct {
+	Text string
+
+	// Summarizer that produced Text, empty when Text is the diff itself
+	Summarizer string
+
+	// Summarizers that failed before Summarizer, with their errors
+	Errors []string
+}
+
+// SummarizerConfig configures diff summarization.
+type SummarizerConfig struct {
+	Kind      string // model, symbols or heuristic
+	Threshold int    // Diffs larger than this are summarized for review
+	Binary    string // Claude binary for the model summarizer
+	Model     string // Model for the model summarizer
+}
+
+// Summarizer summarizes diffs with the configured summarizer, falling back
+// to the next one in config.DiffSummarizers when it fails. The heuristic
+// summarizer always succeeds.
+type Summarizer struct {
+	chain     []DiffSummarizer
+	threshold int
+}
+
+// NewSummarizer creates a summarizer chain starting at cfg.Kind.
+func NewSummarizer(cfg SummarizerConfig) *Summarizer {
+	all := map[string]DiffSummarizer{
+		config.DiffSummarizerModel:     &modelSummarizer{binary: cfg.Binary, model: cfg.Model},
+		config.DiffSummarizerSymbols:   symbolSummarizer{},
+		config.DiffSummarizerHeuristic: heuristicSummarizer{},
+	}
+
+	// Unknown kinds use the heuristic summarizer
+	start := len(config.DiffSummarizers) - 1
+	for i, name := range config.DiffSummarizers {
+		if name == cfg.Kind {
+			start = i
+		}
+	}
+
+	s := &Summarizer{threshold: cfg.Threshold}
+	for _, name := range config.DiffSummarizers[start:] {
+		s.chain = append(s.chain, all[name])
+	}
+	return s
+}
+
+// Summarize returns a summary of diff in at most budget bytes.
+func (s *Summarizer) Summarize(ctx context.Context, diff *git.DiffResult, budget int) *DiffSummary {
+	summary := &DiffSummary{}
+	for _, sm := range s.chain {
+		text, err := sm.Summarize(ctx, diff, budget)
+		if err == nil && strings.TrimSpace(text) != "" {
+			summary.Text = text
+			summary.Summarizer = sm.Name()
+			return summary
+		}
+		if err == nil {
+			err = fmt.Errorf("empty summary")
+		}
+		summary.Errors = append(summary.Errors, fmt.Sprintf("%s: %v", sm.Name(), err))
+	}
+
+	summary.Text = clipText(diff.Diff, budget)
+	return summary
+}
+
+// Condense returns the diff itself if it is within the review threshold,
+// and a summary of it otherwise.
+func (s *Summarizer) Condense(ctx context.Context, diff *git.DiffResult) *DiffSummary {
+	if s.threshold <= 0 || len(diff.Diff) <= s.threshold {
+		return &DiffSummary{Text: diff.Diff}
+	}
+	return s.Summarize(ctx, diff, s.threshold)
+}
+
+// fileDiff is one file's section of a unified diff.
+type fileDiff struct {
+	Path      string
+	Status    string // added, deleted, renamed, binary, or empty if modified
+	Additions int
+	Deletions int
+	Contexts  []string // Hunk header context, usually the enclosing declaration
+	Added     []string // Added lines, without the leading +
+	Removed   []string // Removed lines, without the leading -
+	Text      string   // The file's whole section of the diff
+}
+
+// parseFileDiffs splits a unified diff into its files.
+func parseFileDiffs(diff string) []*fileDiff {
+	var files []*fileDiff
+	var current *fileDiff
+	var section strings.Builder
+	inHunk := false
+
+	flush := func() {
+		if current != nil {
+			current.Text = section.String()
+			files = append(files, current)
+		}
+		section.Reset()
+	}
+
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		trimmed := strings.TrimRight(line, "\n")
+
+		if strings.HasPrefix(trimmed, "diff --git ") {
+			flush()
+			current = &fileDiff{}
+			if i := strings.LastIndex(trimmed, " b/"); i >= 0 {
+				current.Path = trimmed[i+3:]
+			}
+			inHunk = false
+			section.WriteString(line)
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		section.WriteString(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "@@"):
+			inHunk = true
+			if parts := strings.SplitN(trimmed, "@@", 3); len(parts) == 3 {
+				if ctx := strings.TrimSpace(parts[2]); ctx != "" && !containsString(current.Contexts, ctx) {
+					current.Contexts = append(current.Contexts, ctx)
+				}
+			}
+		case !inHunk:
+			switch {
+			case strings.HasPrefix(trimmed, "new file mode"):
+				current.Status = "added"
+			case strings.HasPrefix(trimmed, "deleted file mode"):
+				current.Status = "deleted"
+			case strings.HasPrefix(trimmed, "rename from "):
+				current.Status = "renamed from " + strings.TrimPrefix(trimmed, "rename from ")
+			case strings.HasPrefix(trimmed, "Binary files"):
+				current.Status = "binary"
+			}
+		case strings.HasPrefix(trimmed, "+"):
+			current.Additions++
+			current.Added = append(current.Added, trimmed[1:])
+		case strings.HasPrefix(trimmed, "-"):
+			current.Deletions++
+			current.Removed = append(current.Removed, trimmed[1:])
+		}
+	}
+	flush()
+
+	return files
+}
+
+// writeSummary writes a per-file outline of the diff, using describe for
+// the lines under each file, then as many whole file diffs as fit in the
+// remaining budget, smallest first.
+func writeSummary(diff *git.DiffResult, files []*fileDiff, budget int, describe func(*fileDiff) []string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d files changed, +%d -%d\n", len(files), diff.Additions, diff.Deletions)
+
+	// Files whose details do not fit are listed without them
+	complete := true
+	for i, f := range files {
+		heading := fmt.Sprintf("%s (+%d -%d)", f.Path, f.Additions, f.Deletions)
+		if f.Status != "" {
+			heading += fmt.Sprintf(" [%s]", f.Status)
+		}
+
+		var block strings.Builder
+		block.WriteString("\n" + heading + "\n")
+		for _, line := range describe(f) {
+			block.WriteString("  " + line + "\n")
+		}
+
+		more := fmt.Sprintf("... and %d more files\n", len(files)-i-1)
+		switch {
+		case complete && sb.Len()+block.Len()+len(more) <= budget:
+			sb.WriteString(block.String())
+		case sb.Len()+len(heading)+2+len(more) <= budget:
+			if complete {
+				sb.WriteString("\n")
+				complete = false
+			}
+			sb.WriteString(heading + "\n")
+		default:
+			sb.WriteString(fmt.Sprintf("... and %d more files\n", len(files)-i))
+			return clipText(sb.String(), budget)
+		}
+	}
+	if !complete {
+		return sb.String()
+	}
+
+	// Fill the rest of the budget with whole files, so small changes are
+	// still reviewed line by line
+	bySize := make([]*fileDiff, len(files))
+	copy(bySize, files)
+	sort.SliceStable(bySize, func(i, j int) bool {
+		return len(bySize[i].Text) < len(bySize[j].Text)
+	})
+
+	const excerptHeader = "\nFull diff of the smaller files:\n"
+	var excerpts strings.Builder
+	for _, f := range bySize {
+		if f.Status == "binary" || f.Text == "" {
+			continue
+		}
+		if sb.Len()+len(excerptHeader)+excerpts.Len()+len(f.Text) > budget {
+			break
+		}
+		excerpts.WriteString(f.Text)
+	}
+	if excerpts.Len() > 0 {
+		sb.WriteString(excerptHeader)
+		sb.WriteString(excerpts.String())
+	}
+
+	return sb.String()
+}
+
+// heuristicSummarizer outlines each file's line counts and the
+// declarations its hunks fall in.
+type heuristicSummarizer struct{}
+
+func (heuristicSummarizer) Name() string { return config.DiffSummarizerHeuristic }
+
+func (heuristicSummarizer) Summarize(ctx context.Context, diff *git.DiffResult, budget int) (string, error) {
+	const maxContexts = 5
+
+	files := parseFileDiffs(diff.Diff)
+	return writeSummary(diff, files, budget, func(f *fileDiff) []string {
+		var lines []string
+		for i, c := range f.Contexts {
+			if i == maxContexts {
+				lines = append(lines, fmt.Sprintf("... %d more", len(f.Contexts)-maxContexts))
+				break
+			}
+			lines = append(lines, "in "+c)
+		}
+		return lines
+	}), nil
+}
+
+// declPatterns match lines that declare something, by file extension.
+var declPatterns = map[string][]*regexp.Regexp{
+	".go": {
+		regexp.MustCompile(`^func\b.*`),
+		regexp.MustCompile(`^type\s+\w+.*`),
+		regexp.MustCompile(`^(var|const)\s+\w+.*`),
+	},
+	".py": {
+		regexp.MustCompile(`^\s*(async\s+)?def\s+\w+.*`),
+		regexp.MustCompile(`^\s*class\s+\w+.*`),
+	},
+	".js":  jsDeclPatterns,
+	".jsx": jsDeclPatterns,
+	".ts":  jsDeclPatterns,
+	".tsx": jsDeclPatterns,
+	".rs": {
+		regexp.MustCompile(`^\s*(pub(\([^)]*\))?\s+)?(async\s+)?(unsafe\s+)?(fn|struct|enum|trait|impl|mod|type)\b.*`),
+	},
+	".rb": {
+		regexp.MustCompile(`^\s*(def|class|module)\s+.*`),
+	},
+	".java": classDeclPatterns,
+	".kt":   classDeclPatterns,
+	".cs":   classDeclPatterns,
+}
+
+var jsDeclPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`^\s*(export\s+)?(default\s+)?(async\s+)?function\b.*`),
+	regexp.MustCompile(`^\s*(export\s+)?(default\s+)?(abstract\s+)?class\s+\w+.*`),
+	regexp.MustCompile(`^\s*(export\s+)?(interface|type|enum)\s+\w+.*`),
+	regexp.MustCompile(`^\s*export\s+(const|let)\s+\w+.*`),
+}
+
+var classDeclPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`^\s*((public|private|protected|internal|abstract|final|static|sealed|data|open)\s+)*(class|interface|enum|record|object)\s+\w+.*`),
+}
+
+// symbolSummarizer lists the declarations each file adds, removes and
+// changes. It fails when no file is in a language it recognizes.
+type symbolSummarizer struct{}
+
+func (symbolSummarizer) Name() string { return config.DiffSummarizerSymbols }
+
+func (symbolSummarizer) Summarize(ctx context.Context, diff *git.DiffResult, budget int) (string, error) {
+	files := parseFileDiffs(diff.Diff)
+
+	recognized := false
+	for _, f := range files {
+		if _, ok := declPatterns[filepath.Ext(f.Path)]; ok {
+			recognized = true
+			break
+		}
+	}
+	if !recognized {
+		return "", fmt.Errorf("no files in a supported language")
+	}
+
+	return writeSummary(diff, files, budget, func(f *fileDiff) []string {
+		patterns := declPatterns[filepath.Ext(f.Path)]
+		if patterns == nil {
+			return nil
+		}
+
+		added := declarations(f.Added, patterns)
+		removed := declarations(f.Removed, patterns)
+
+		var lines []string
+		for _, d := range added {
+			if !containsString(removed, d) {
+				lines = append(lines, "+ "+d)
+			}
+		}
+		for _, d := range removed {
+			if !containsString(added, d) {
+				lines = append(lines, "- "+d)
+			}
+		}
+		for _, c := range f.Contexts {
+			if !containsString(added, c) && !containsString(removed, c) {
+				lines = append(lines, "~ "+c)
+			}
+		}
+		return lines
+	}), nil
+}
+
+// declarations returns the lines that match one of patterns, trimmed of
+// their body.
+func declarations(lines []string, patterns []*regexp.Regexp) []string {
+	var decls []string
+	for _, line := range lines {
+		for _, re := range patterns {
+			if re.MatchString(line) {
+				decl := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(line), "{:"))
+				if !containsString(decls, decl) {
+					decls = append(decls, decl)
+				}
+				break
+			}
+		}
+	}
+	return decls
+}
+
+// modelInputLimit caps the diff sent to the summary model. Larger diffs
+// are outlined heuristically first.
+const modelInputLimit = 200000
+
+// modelSummarizer asks a cheap model to summarize the diff.
+type modelSummarizer struct {
+	binary string
+	model  string
+}
+
+func (m *modelSummarizer) Name() string { return config.DiffSummarizerModel }
+
+func (m *modelSummarizer) Summarize(ctx context.Context, diff *git.DiffResult, budget int) (string, error) {
+	binary := m.binary
+	if binary == "" {
+		binary = "claude"
+	}
+
+	input := diff.Diff
+	if len(input) > modelInputLimit {
+		input, _ = heuristicSummarizer{}.Summarize(ctx, diff, modelInputLimit)
+	}
+
+	prompt := fmt.Sprintf(`Summarize the following diff for a code reviewer in at most %d characters.
+Describe what changed in each file or area, naming the functions and types involved, and point out anything risky.
+Reply with the summary only, as plain text.
+
+%s`, budget, "```diff\n"+input+"\n```")
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	args := []string{"--print"}
+	if m.model != "" {
+		args = append(args, "--model", m.model)
+	}
+	args = append(args, "--max-turns", "1", "-p", prompt)
+
+	out, err := exec.CommandContext(ctx, binary, args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("claude summary failed: %w", err)
+	}
+
+	summary := strings.TrimSpace(string(out))
+	if summary == "" {
+		return "", fmt.Errorf("empty summary")
+	}
+
+	header := fmt.Sprintf("%d files changed, +%d -%d\n\n", len(diff.FilesChanged), diff.Additions, diff.Deletions)
+	return clipText(header+summary, budget), nil
+}
+
+// clipText shortens s to at most n bytes, at a line break where possible.
+func clipText(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	const marker = "\n... (truncated)"
+	if n <= len(marker) {
+		n = len(marker) + 1
+	}
+	cut := n - len(marker)
+	if i := strings.LastIndex(s[:cut], "\n"); i > 0 {
+		cut = i
+	}
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + marker
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
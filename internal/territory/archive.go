@@ -0,0 +1,221 @@
+package territory
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"cosa/internal/git"
+)
+
+// archiveBundle is the name, in an archive, of the git bundle of the
+// territory's branches.
+const archiveBundle = "branches.bundle"
+
+// ArchiveResult describes what an archive holds.
+type ArchiveResult struct {
+	Files    int      `json:"files"`              // Files from the .cosa directory
+	Branches []string `json:"branches,omitempty"` // Branches in the bundle; for a restore, those created
+}
+
+// Archive writes the territory's cosa state to w as a gzipped tarball: the
+// files of its .cosa directory, except the worktrees, and a git bundle of
+// its cosa/ branches, so that the work of jobs not yet merged survives a
+// purge. RestoreArchive puts it back.
+func (t *Territory) Archive(w io.Writer) (*ArchiveResult, error) {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	result := &ArchiveResult{}
+
+	worktrees := t.WorktreesPath()
+	err := filepath.WalkDir(t.Path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && p == worktrees {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(filepath.Dir(t.Path), p)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return writeTarDir(tw, filepath.ToSlash(rel))
+		}
+		result.Files++
+		return writeTarFile(tw, filepath.ToSlash(rel), p)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to archive %s: %w", t.Path, err)
+	}
+
+	artifacts, err := t.Artifacts()
+	if err != nil {
+		return nil, err
+	}
+	if len(artifacts.Branches) > 0 {
+		tmp, err := os.MkdirTemp("", "cosa-archive-")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(tmp)
+
+		bundle := filepath.Join(tmp, archiveBundle)
+		if err := t.gitManager.Bundle(bundle, artifacts.Branches); err != nil {
+			return nil, err
+		}
+		if err := writeTarFile(tw, archiveBundle, bundle); err != nil {
+			return nil, err
+		}
+		result.Branches = artifacts.Branches
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RestoreArchive restores a territory archived by Archive into the git
+// repository at repoPath, which must not have one, and returns it. The
+// archived branches that do not exist in the repository are created.
+func RestoreArchive(r io.Reader, repoPath string) (*Territory, *ArchiveResult, error) {
+	repoRoot, err := git.FindMainRepoRoot(repoPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("not a git repository: %w", err)
+	}
+	if Exists(repoRoot) {
+		return nil, nil, fmt.Errorf("territory already initialized for %s", repoRoot)
+	}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("not a territory archive: %w", err)
+	}
+	defer gz.Close()
+
+	tmp, err := os.MkdirTemp("", "cosa-restore-")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	result := &ArchiveResult{}
+	var bundle string
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		name := path.Clean(hdr.Name)
+		var dest string
+		switch {
+		case name == archiveBundle:
+			bundle = filepath.Join(tmp, archiveBundle)
+			dest = bundle
+		case name == DirName || strings.HasPrefix(name, DirName+"/"):
+			dest = filepath.Join(repoRoot, filepath.FromSlash(name))
+		default:
+			return nil, nil, fmt.Errorf("not a territory archive: unexpected entry %s", hdr.Name)
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(dest, 0755); err != nil {
+				return nil, nil, err
+			}
+		case tar.TypeReg:
+			if err := extractTarFile(tr, dest, hdr.FileInfo().Mode().Perm()); err != nil {
+				return nil, nil, err
+			}
+			if dest != bundle {
+				result.Files++
+			}
+		}
+	}
+
+	t, err := Load(repoRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The repository may have moved since it was archived
+	t.Path = filepath.Join(repoRoot, DirName)
+	t.RepoRoot = repoRoot
+	if err := t.Save(); err != nil {
+		return nil, nil, fmt.Errorf("failed to save territory config: %w", err)
+	}
+	addToGitignore(repoRoot, DirName)
+
+	if bundle != "" {
+		created, err := t.gitManager.Unbundle(bundle)
+		if err != nil {
+			return t, result, err
+		}
+		result.Branches = created
+	}
+	return t, result, nil
+}
+
+func writeTarDir(tw *tar.Writer, name string) error {
+	return tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     name + "/",
+		Mode:     0755,
+	})
+}
+
+func writeTarFile(tw *tar.Writer, name, src string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     int64(info.Mode().Perm()),
+		Size:     info.Size(),
+		ModTime:  info.ModTime(),
+	}); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+func extractTarFile(r io.Reader, dest string, perm fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
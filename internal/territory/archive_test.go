@@ -0,0 +1,239 @@
+package territory
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// initTerritory makes a repository with a territory and a worker whose
+// branch has a commit not on main.
+func initTerritory(t *testing.T) *Territory {
+	t.Helper()
+	dir := initRepo(t, map[string]string{"README.md": "# x\n"})
+	terr, err := Init(dir, nil)
+	if err != nil {
+		t.Fatalf("failed to init territory: %v", err)
+	}
+	if err := terr.AppendKnowledge("vito", "The tests need a database."); err != nil {
+		t.Fatal(err)
+	}
+
+	wt, err := terr.CreateWorkerWorktree("vito")
+	if err != nil {
+		t.Fatalf("failed to create worktree: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(wt.Path, "work.txt"), []byte("unmerged\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, wt.Path, "add", "-A")
+	runGit(t, wt.Path, "commit", "-q", "-m", "Unmerged work")
+	return terr
+}
+
+// tarEntries lists the names in a gzipped tarball.
+func tarEntries(t *testing.T, data []byte) []string {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return names
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, hdr.Name)
+	}
+}
+
+// makeArchive writes a gzipped tarball of the given files.
+func makeArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0644, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestArchive_RoundTrip(t *testing.T) {
+	terr := initTerritory(t)
+
+	var buf bytes.Buffer
+	result, err := terr.Archive(&buf)
+	if err != nil {
+		t.Fatalf("failed to archive: %v", err)
+	}
+	if result.Files == 0 {
+		t.Error("expected files from the .cosa directory")
+	}
+	if !slices.Equal(result.Branches, []string{"cosa/vito"}) {
+		t.Errorf("expected the worker's branch to be bundled, got %v", result.Branches)
+	}
+
+	entries := tarEntries(t, buf.Bytes())
+	if !slices.Contains(entries, archiveBundle) {
+		t.Errorf("expected %s in %v", archiveBundle, entries)
+	}
+	if !slices.Contains(entries, DirName+"/"+KnowledgeFile) {
+		t.Errorf("expected the knowledge base in %v", entries)
+	}
+	for _, name := range entries {
+		if strings.HasPrefix(name, DirName+"/"+WorktreesDir+"/") {
+			t.Errorf("expected worktrees to be left out, got %s", name)
+		}
+	}
+
+	// Restore into another repository, as after a move
+	dest := initRepo(t, map[string]string{"README.md": "# x\n"})
+	restored, rr, err := RestoreArchive(bytes.NewReader(buf.Bytes()), dest)
+	if err != nil {
+		t.Fatalf("failed to restore: %v", err)
+	}
+	if rr.Files != result.Files {
+		t.Errorf("expected %d files restored, got %d", result.Files, rr.Files)
+	}
+	if !slices.Equal(rr.Branches, []string{"cosa/vito"}) {
+		t.Errorf("expected cosa/vito to be created, got %v", rr.Branches)
+	}
+	if restored.RepoRoot != dest {
+		t.Errorf("expected the territory to move to %s, got %s", dest, restored.RepoRoot)
+	}
+
+	knowledge, err := os.ReadFile(restored.KnowledgePath())
+	if err != nil || !strings.Contains(string(knowledge), "The tests need a database.") {
+		t.Errorf("expected the knowledge base to be restored, got %q, %v", knowledge, err)
+	}
+	if !restored.GitManager().BranchExists("cosa/vito") {
+		t.Error("expected the unmerged branch to be restored")
+	}
+	if _, err := Load(dest); err != nil {
+		t.Errorf("expected the restored territory to load: %v", err)
+	}
+
+	// A second restore finds the territory already there
+	if _, _, err := RestoreArchive(bytes.NewReader(buf.Bytes()), dest); err == nil {
+		t.Error("expected error restoring over an existing territory")
+	}
+}
+
+func TestRestoreArchive_Rejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{"unexpected entry", map[string]string{"src/main.go": "package main\n"}, "unexpected entry"},
+		{"parent traversal", map[string]string{"../evil.txt": "x"}, "unexpected entry"},
+		{"traversal through .cosa", map[string]string{DirName + "/../../evil.txt": "x"}, "unexpected entry"},
+		{"absolute path", map[string]string{"/tmp/evil.txt": "x"}, "unexpected entry"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dest := initRepo(t, nil)
+
+			_, _, err := RestoreArchive(bytes.NewReader(makeArchive(t, tt.files)), dest)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if _, err := os.Stat(filepath.Join(filepath.Dir(dest), "evil.txt")); err == nil {
+				t.Error("expected nothing to be written outside the repository")
+			}
+			if Exists(dest) {
+				t.Error("expected no territory from a rejected archive")
+			}
+		})
+	}
+
+	t.Run("not gzip", func(t *testing.T) {
+		dest := initRepo(t, nil)
+		if _, _, err := RestoreArchive(strings.NewReader("plain text"), dest); err == nil {
+			t.Error("expected error for a file that is not an archive")
+		}
+	})
+}
+
+func TestArtifacts_KeepsBaseAndDevBranches(t *testing.T) {
+	dir := initRepo(t, map[string]string{"README.md": "# x\n"})
+	runGit(t, dir, "branch", "cosa/base")
+	runGit(t, dir, "branch", "cosa/dev")
+	runGit(t, dir, "branch", "cosa/job-1")
+
+	terr, err := Init(dir, &InitOptions{BaseBranch: "cosa/base", DevBranch: "cosa/dev"})
+	if err != nil {
+		t.Fatalf("failed to init territory: %v", err)
+	}
+	wt, err := terr.CreateWorkerWorktree("vito")
+	if err != nil {
+		t.Fatalf("failed to create worktree: %v", err)
+	}
+
+	a, err := terr.Artifacts()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(a.Branches, []string{"cosa/job-1", "cosa/vito"}) {
+		t.Errorf("expected only cosa's own branches, got %v", a.Branches)
+	}
+	if len(a.Worktrees) != 1 || resolvePath(a.Worktrees[0]) != resolvePath(wt.Path) {
+		t.Errorf("expected the worker's worktree, got %v", a.Worktrees)
+	}
+}
+
+func TestPurge(t *testing.T) {
+	terr := initTerritory(t)
+	runGit(t, terr.RepoRoot, "branch", "feature")
+
+	removed, err := terr.Purge()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(removed.Worktrees) != 1 {
+		t.Errorf("expected 1 worktree removed, got %v", removed.Worktrees)
+	}
+	if !slices.Equal(removed.Branches, []string{"cosa/vito"}) {
+		t.Errorf("expected cosa/vito removed, got %v", removed.Branches)
+	}
+
+	if Exists(terr.RepoRoot) {
+		t.Error("expected the .cosa directory to be removed")
+	}
+	gitMgr := terr.GitManager()
+	if gitMgr.BranchExists("cosa/vito") {
+		t.Error("expected cosa/vito to be deleted")
+	}
+	for _, b := range []string{"main", "feature"} {
+		if !gitMgr.BranchExists(b) {
+			t.Errorf("expected %s to be kept", b)
+		}
+	}
+	if worktrees, _ := gitMgr.ListWorktrees(); len(worktrees) > 1 {
+		t.Errorf("expected only the main worktree left, got %v", worktrees)
+	}
+}
@@ -0,0 +1,166 @@
+package territory
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"cosa/internal/git"
+)
+
+// Detection is what a repository says about how to set up a territory for
+// it: how it is built and tested, and its branches.
+type Detection struct {
+	RepoRoot     string   `json:"repo_root"`
+	BuildSystem  string   `json:"build_system,omitempty"` // e.g. "go", "node" or "cargo"; empty if not recognized
+	TestCommand  string   `json:"test_command,omitempty"`
+	BuildCommand string   `json:"build_command,omitempty"`
+	BaseBranch   string   `json:"base_branch,omitempty"`
+	DevBranch    string   `json:"dev_branch,omitempty"` // An existing development or staging branch
+	Branches     []string `json:"branches,omitempty"`   // Local branches, other than cosa's own
+}
+
+// devBranches are the names a development or staging branch usually has,
+// in the order they are preferred.
+var devBranches = []string{"develop", "development", "dev", "staging"}
+
+// buildSystem recognizes a build system by a file at the repository root
+// and proposes its gate commands.
+type buildSystem struct {
+	name   string
+	marker string
+	detect func(root string) (test, build string)
+}
+
+// buildSystems are tried in order; the first whose marker exists wins.
+var buildSystems = []buildSystem{
+	{"go", "go.mod", func(string) (string, string) { return "go test ./...", "go build ./..." }},
+	{"cargo", "Cargo.toml", func(string) (string, string) { return "cargo test", "cargo build" }},
+	{"node", "package.json", detectNode},
+	{"maven", "pom.xml", func(string) (string, string) { return "mvn -q test", "mvn -q -DskipTests package" }},
+	{"gradle", "build.gradle", detectGradle},
+	{"gradle", "build.gradle.kts", detectGradle},
+	{"python", "pyproject.toml", func(string) (string, string) { return "pytest", "" }},
+	{"python", "setup.py", func(string) (string, string) { return "pytest", "" }},
+	{"mix", "mix.exs", func(string) (string, string) { return "mix test", "mix compile" }},
+	{"dotnet", "*.sln", func(string) (string, string) { return "dotnet test", "dotnet build" }},
+	{"make", "Makefile", detectMake},
+}
+
+// Detect looks at the repository containing path and proposes the
+// territory settings for it.
+func Detect(path string) (*Detection, error) {
+	repoRoot, err := git.FindRepoRoot(path)
+	if err != nil {
+		return nil, fmt.Errorf("not a git repository: %w", err)
+	}
+
+	d := &Detection{RepoRoot: repoRoot}
+	for _, bs := range buildSystems {
+		if matches, _ := filepath.Glob(filepath.Join(repoRoot, bs.marker)); len(matches) > 0 {
+			d.BuildSystem = bs.name
+			d.TestCommand, d.BuildCommand = bs.detect(repoRoot)
+			break
+		}
+	}
+
+	gitMgr := git.NewManager(repoRoot, filepath.Join(repoRoot, DirName, WorktreesDir))
+	branches, _ := gitMgr.ListBranches("")
+	for _, b := range branches {
+		if !strings.HasPrefix(b.Name, "cosa/") {
+			d.Branches = append(d.Branches, b.Name)
+		}
+	}
+	// A repository without commits has no branch to propose yet
+	if base := gitMgr.GetDefaultBranch(); slices.Contains(d.Branches, base) {
+		d.BaseBranch = base
+	}
+	for _, name := range devBranches {
+		if name != d.BaseBranch && slices.Contains(d.Branches, name) {
+			d.DevBranch = name
+			break
+		}
+	}
+
+	return d, nil
+}
+
+// npmDefaultTest is the test script npm init writes, which always fails.
+const npmDefaultTest = `echo "Error: no test specified" && exit 1`
+
+// detectNode proposes the package's test and build scripts, run with the
+// package manager its lockfile belongs to.
+func detectNode(root string) (string, string) {
+	runner := "npm run"
+	for _, pm := range [][2]string{{"pnpm-lock.yaml", "pnpm"}, {"yarn.lock", "yarn"}, {"bun.lockb", "bun run"}} {
+		if _, err := os.Stat(filepath.Join(root, pm[0])); err == nil {
+			runner = pm[1]
+			break
+		}
+	}
+
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	data, err := os.ReadFile(filepath.Join(root, "package.json"))
+	if err != nil || json.Unmarshal(data, &pkg) != nil {
+		return "", ""
+	}
+
+	var test, build string
+	if script, ok := pkg.Scripts["test"]; ok && script != npmDefaultTest {
+		test = runner + " test"
+	}
+	if _, ok := pkg.Scripts["build"]; ok {
+		build = runner + " build"
+	}
+	return test, build
+}
+
+// detectGradle prefers the repository's Gradle wrapper.
+func detectGradle(root string) (string, string) {
+	gradle := "gradle"
+	if _, err := os.Stat(filepath.Join(root, "gradlew")); err == nil {
+		gradle = "./gradlew"
+	}
+	return gradle + " test", gradle + " build -x test"
+}
+
+// makeTarget matches a rule's target at the start of a Makefile line.
+var makeTarget = regexp.MustCompile(`^([A-Za-z0-9_.-]+)\s*:([^=]|$)`)
+
+// detectMake proposes the Makefile's test and build targets, if it has
+// them.
+func detectMake(root string) (string, string) {
+	f, err := os.Open(filepath.Join(root, "Makefile"))
+	if err != nil {
+		return "", ""
+	}
+	defer f.Close()
+
+	targets := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if m := makeTarget.FindStringSubmatch(scanner.Text()); m != nil {
+			targets[m[1]] = true
+		}
+	}
+
+	var test, build string
+	if targets["test"] {
+		test = "make test"
+	} else if targets["check"] {
+		test = "make check"
+	}
+	if targets["build"] {
+		build = "make build"
+	} else if targets["all"] {
+		build = "make"
+	}
+	return test, build
+}
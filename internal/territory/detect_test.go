@@ -0,0 +1,213 @@
+package territory
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// initRepo makes a git repository with files committed on main.
+func initRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runGit(t, dir, "init", "-q", "-b", "main")
+	runGit(t, dir, "config", "user.name", "test")
+	runGit(t, dir, "config", "user.email", "test@example.com")
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, "commit", "-q", "--allow-empty", "-m", "Initial commit")
+	return dir
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %s: %v", args, out, err)
+	}
+}
+
+func TestDetect_BuildSystems(t *testing.T) {
+	tests := []struct {
+		name   string
+		files  map[string]string
+		system string
+		test   string
+		build  string
+	}{
+		{
+			name:   "go",
+			files:  map[string]string{"go.mod": "module example.com/x\n\ngo 1.24\n"},
+			system: "go", test: "go test ./...", build: "go build ./...",
+		},
+		{
+			name:   "cargo",
+			files:  map[string]string{"Cargo.toml": "[package]\nname = \"x\"\n"},
+			system: "cargo", test: "cargo test", build: "cargo build",
+		},
+		{
+			name:   "node with npm",
+			files:  map[string]string{"package.json": `{"scripts": {"test": "jest", "build": "tsc"}}`},
+			system: "node", test: "npm run test", build: "npm run build",
+		},
+		{
+			name: "node with pnpm",
+			files: map[string]string{
+				"package.json":   `{"scripts": {"test": "vitest"}}`,
+				"pnpm-lock.yaml": "lockfileVersion: 9\n",
+			},
+			system: "node", test: "pnpm test",
+		},
+		{
+			name: "node with yarn",
+			files: map[string]string{
+				"package.json": `{"scripts": {"build": "webpack"}}`,
+				"yarn.lock":    "",
+			},
+			system: "node", build: "yarn build",
+		},
+		{
+			name:   "node with the npm init test script",
+			files:  map[string]string{"package.json": `{"scripts": {"test": "echo \"Error: no test specified\" && exit 1"}}`},
+			system: "node",
+		},
+		{
+			name:   "maven",
+			files:  map[string]string{"pom.xml": "<project/>\n"},
+			system: "maven", test: "mvn -q test", build: "mvn -q -DskipTests package",
+		},
+		{
+			name:   "gradle",
+			files:  map[string]string{"build.gradle": "plugins {}\n"},
+			system: "gradle", test: "gradle test", build: "gradle build -x test",
+		},
+		{
+			name: "gradle kotlin with wrapper",
+			files: map[string]string{
+				"build.gradle.kts": "plugins {}\n",
+				"gradlew":          "#!/bin/sh\n",
+			},
+			system: "gradle", test: "./gradlew test", build: "./gradlew build -x test",
+		},
+		{
+			name:   "python pyproject",
+			files:  map[string]string{"pyproject.toml": "[project]\nname = \"x\"\n"},
+			system: "python", test: "pytest",
+		},
+		{
+			name:   "python setup.py",
+			files:  map[string]string{"setup.py": "from setuptools import setup\n"},
+			system: "python", test: "pytest",
+		},
+		{
+			name:   "mix",
+			files:  map[string]string{"mix.exs": "defmodule X.MixProject do\nend\n"},
+			system: "mix", test: "mix test", build: "mix compile",
+		},
+		{
+			name:   "dotnet",
+			files:  map[string]string{"App.sln": "\n"},
+			system: "dotnet", test: "dotnet test", build: "dotnet build",
+		},
+		{
+			name:   "make with test and build",
+			files:  map[string]string{"Makefile": "build:\n\tcc x.c\n\ntest: build\n\t./x\n"},
+			system: "make", test: "make test", build: "make build",
+		},
+		{
+			name:   "make with check and all",
+			files:  map[string]string{"Makefile": "CC := gcc\nall:\n\t$(CC) x.c\ncheck:\n\t./x\n"},
+			system: "make", test: "make check", build: "make",
+		},
+		{
+			name: "first marker wins",
+			files: map[string]string{
+				"go.mod":   "module example.com/x\n\ngo 1.24\n",
+				"Makefile": "test:\n\tgo test ./...\n",
+			},
+			system: "go", test: "go test ./...", build: "go build ./...",
+		},
+		{
+			name:  "unrecognized",
+			files: map[string]string{"README.md": "# x\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := initRepo(t, tt.files)
+
+			d, err := Detect(dir)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if d.BuildSystem != tt.system {
+				t.Errorf("expected build system %q, got %q", tt.system, d.BuildSystem)
+			}
+			if d.TestCommand != tt.test {
+				t.Errorf("expected test command %q, got %q", tt.test, d.TestCommand)
+			}
+			if d.BuildCommand != tt.build {
+				t.Errorf("expected build command %q, got %q", tt.build, d.BuildCommand)
+			}
+		})
+	}
+}
+
+func TestDetect_Branches(t *testing.T) {
+	dir := initRepo(t, map[string]string{"go.mod": "module example.com/x\n\ngo 1.24\n"})
+	runGit(t, dir, "branch", "staging")
+	runGit(t, dir, "branch", "develop")
+	runGit(t, dir, "branch", "cosa/job-1")
+
+	// From a subdirectory, the repository root is found
+	sub := filepath.Join(dir, "internal")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	d, err := Detect(sub)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	root, _ := filepath.EvalSymlinks(dir)
+	if got, _ := filepath.EvalSymlinks(d.RepoRoot); got != root {
+		t.Errorf("expected repo root %s, got %s", root, d.RepoRoot)
+	}
+	if d.BaseBranch != "main" {
+		t.Errorf("expected base branch main, got %q", d.BaseBranch)
+	}
+	if d.DevBranch != "develop" {
+		t.Errorf("expected develop to be preferred over staging, got %q", d.DevBranch)
+	}
+	if slices.Contains(d.Branches, "cosa/job-1") {
+		t.Errorf("expected cosa's own branches to be left out, got %v", d.Branches)
+	}
+	for _, b := range []string{"main", "develop", "staging"} {
+		if !slices.Contains(d.Branches, b) {
+			t.Errorf("expected branch %s in %v", b, d.Branches)
+		}
+	}
+}
+
+func TestDetect_NotARepository(t *testing.T) {
+	if _, err := Detect(t.TempDir()); err == nil {
+		t.Error("expected error outside a git repository")
+	}
+}
@@ -0,0 +1,99 @@
+package territory
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BranchPrefix begins the names of the branches cosa creates for workers
+// and jobs.
+const BranchPrefix = "cosa/"
+
+// Artifacts are what cosa created in a repository besides the files in
+// its .cosa directory: git worktrees under it and cosa/ branches.
+type Artifacts struct {
+	Worktrees []string `json:"worktrees,omitempty"` // Paths
+	Branches  []string `json:"branches,omitempty"`
+}
+
+// Artifacts lists the worktrees and branches cosa created for the
+// territory. The base and dev branches are never among them.
+func (t *Territory) Artifacts() (*Artifacts, error) {
+	a := &Artifacts{}
+
+	worktrees, err := t.gitManager.ListWorktrees()
+	if err != nil {
+		return nil, err
+	}
+	base := resolvePath(t.WorktreesPath()) + string(filepath.Separator)
+	for _, wt := range worktrees {
+		if strings.HasPrefix(resolvePath(wt.Path), base) {
+			a.Worktrees = append(a.Worktrees, wt.Path)
+		}
+	}
+
+	branches, err := t.gitManager.ListBranches(BranchPrefix)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range branches {
+		if b.Name != t.BaseBranch && b.Name != t.Config.DevBranch {
+			a.Branches = append(a.Branches, b.Name)
+		}
+	}
+	return a, nil
+}
+
+// Purge removes the territory from its repository: its worktrees, then its
+// branches, then the .cosa directory. Branches hold the work of jobs not
+// yet merged, which is lost. It carries on past what it cannot remove and
+// returns what it did remove, with an error listing the rest.
+func (t *Territory) Purge() (*Artifacts, error) {
+	found, err := t.Artifacts()
+	if err != nil {
+		return nil, err
+	}
+
+	removed := &Artifacts{}
+	var errs []error
+	for _, path := range found.Worktrees {
+		rel, err := filepath.Rel(resolvePath(t.WorktreesPath()), resolvePath(path))
+		if err == nil {
+			err = t.gitManager.RemoveWorktree(rel, true)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("worktree %s: %w", path, err))
+			continue
+		}
+		removed.Worktrees = append(removed.Worktrees, path)
+	}
+
+	// Worktrees git failed to remove go with the directory; pruning then
+	// forgets them, which frees their branches
+	if err := os.RemoveAll(t.Path); err != nil {
+		errs = append(errs, fmt.Errorf("failed to remove %s: %w", t.Path, err))
+	}
+	t.gitManager.PruneWorktrees()
+
+	for _, branch := range found.Branches {
+		if err := t.gitManager.DeleteBranch(branch, true); err != nil {
+			errs = append(errs, fmt.Errorf("branch %s: %w", branch, err))
+			continue
+		}
+		removed.Branches = append(removed.Branches, branch)
+	}
+
+	return removed, errors.Join(errs...)
+}
+
+// resolvePath returns path with symlinks resolved, as git reports worktree
+// paths, or path itself if it cannot be resolved.
+func resolvePath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"cosa/internal/git"
@@ -23,6 +25,18 @@ const (
 
 	// StateFile stores runtime state.
 	StateFile = "state.json"
+
+	// KnowledgeFile accumulates lessons learned by removed workers.
+	KnowledgeFile = "knowledge.md"
+
+	// PromptsDir holds per-role prompt templates (<role>.md).
+	PromptsDir = "prompts"
+
+	// WorkspaceDir holds files copied into every new job worktree.
+	WorkspaceDir = "workspace"
+
+	// IndexFile stores the repository index built while workers are idle.
+	IndexFile = "index.json"
 )
 
 // Territory represents a Cosa workspace for a project.
@@ -34,6 +48,7 @@ type Territory struct {
 	Config     Config    `json:"config"`
 
 	gitManager *git.Manager
+	ordersMu   sync.RWMutex // Guards Config.StandingOrders, read by running workers
 }
 
 // Config contains territory-specific configuration.
@@ -53,10 +68,43 @@ type Config struct {
 	// DevBranch is the development/staging branch where workers merge their work.
 	// If empty, workers merge directly to BaseBranch (main/master).
 	DevBranch string `json:"dev_branch,omitempty"`
+
+	// StandingOrders are given to every worker of a role, by role, after
+	// the global orders and ahead of the worker's own.
+	StandingOrders map[string][]string `json:"standing_orders,omitempty"`
+}
+
+// InitOptions override the settings a new territory starts with. Empty
+// fields keep the defaults.
+type InitOptions struct {
+	BaseBranch   string
+	DevBranch    string
+	TestCommand  string
+	BuildCommand string
+	AutoReview   *bool
 }
 
-// Init initializes a new territory in the given directory.
-func Init(projectPath string) (*Territory, error) {
+// validate checks the branches: the base branch must exist, since
+// worktrees start from it, while the dev branch may be created later.
+func (o *InitOptions) validate(gitMgr *git.Manager) error {
+	if o.BaseBranch != "" {
+		if err := git.ValidateBranchName(o.BaseBranch); err != nil {
+			return fmt.Errorf("base branch: %w", err)
+		}
+		if !gitMgr.BranchExists(o.BaseBranch) {
+			return fmt.Errorf("base branch %s does not exist", o.BaseBranch)
+		}
+	}
+	if o.DevBranch != "" {
+		if err := git.ValidateBranchName(o.DevBranch); err != nil {
+			return fmt.Errorf("dev branch: %w", err)
+		}
+	}
+	return nil
+}
+
+// Init initializes a new territory in the given directory. opts may be nil.
+func Init(projectPath string, opts *InitOptions) (*Territory, error) {
 	// Find git repository root
 	repoRoot, err := git.FindRepoRoot(projectPath)
 	if err != nil {
@@ -70,14 +118,20 @@ func Init(projectPath string) (*Territory, error) {
 		return nil, fmt.Errorf("territory already initialized at %s", territoryPath)
 	}
 
-	// Create territory directory structure
 	worktreesPath := filepath.Join(territoryPath, WorktreesDir)
+	gitMgr := git.NewManager(repoRoot, worktreesPath)
+	if opts != nil {
+		if err := opts.validate(gitMgr); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create territory directory structure
 	if err := os.MkdirAll(worktreesPath, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create territory directory: %w", err)
 	}
 
 	// Determine base branch
-	gitMgr := git.NewManager(repoRoot, worktreesPath)
 	baseBranch := gitMgr.GetDefaultBranch()
 
 	territory := &Territory{
@@ -91,6 +145,17 @@ func Init(projectPath string) (*Territory, error) {
 		},
 		gitManager: gitMgr,
 	}
+	if opts != nil {
+		if opts.BaseBranch != "" {
+			territory.BaseBranch = opts.BaseBranch
+		}
+		territory.Config.DevBranch = opts.DevBranch
+		territory.Config.TestCommand = opts.TestCommand
+		territory.Config.BuildCommand = opts.BuildCommand
+		if opts.AutoReview != nil {
+			territory.Config.AutoReview = *opts.AutoReview
+		}
+	}
 
 	// Save configuration
 	if err := territory.Save(); err != nil {
@@ -149,6 +214,47 @@ func (t *Territory) WorktreesPath() string {
 	return filepath.Join(t.Path, WorktreesDir)
 }
 
+// KnowledgePath returns the path to the territory knowledge base.
+func (t *Territory) KnowledgePath() string {
+	return filepath.Join(t.Path, KnowledgeFile)
+}
+
+// PromptsPath returns the directory of per-role prompt templates.
+func (t *Territory) PromptsPath() string {
+	return filepath.Join(t.Path, PromptsDir)
+}
+
+// WorkspacePath returns the directory of files copied into job worktrees.
+func (t *Territory) WorkspacePath() string {
+	return filepath.Join(t.Path, WorkspaceDir)
+}
+
+// IndexPath returns the path to the repository index.
+func (t *Territory) IndexPath() string {
+	return filepath.Join(t.Path, IndexFile)
+}
+
+// AppendKnowledge appends a worker's learnings to the knowledge base as a
+// dated markdown section.
+func (t *Territory) AppendKnowledge(workerName, learnings string) error {
+	learnings = strings.TrimSpace(learnings)
+	if learnings == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(t.KnowledgePath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open knowledge base: %w", err)
+	}
+	defer f.Close()
+
+	section := fmt.Sprintf("## %s (%s)\n\n%s\n\n", workerName, time.Now().Format("2006-01-02"), learnings)
+	if _, err := f.WriteString(section); err != nil {
+		return fmt.Errorf("failed to write knowledge base: %w", err)
+	}
+	return nil
+}
+
 // GitManager returns the git manager.
 func (t *Territory) GitManager() *git.Manager {
 	return t.gitManager
@@ -190,6 +296,35 @@ func (t *Territory) ClearDevBranch() error {
 	return t.Save()
 }
 
+// RoleOrders returns the standing orders for workers of role.
+func (t *Territory) RoleOrders(role string) []string {
+	t.ordersMu.RLock()
+	defer t.ordersMu.RUnlock()
+	return append([]string(nil), t.Config.StandingOrders[role]...)
+}
+
+// SetRoleOrders replaces the standing orders for workers of role. No
+// orders clears them.
+func (t *Territory) SetRoleOrders(role string, orders []string) error {
+	t.ordersMu.Lock()
+	defer t.ordersMu.Unlock()
+
+	updated := make(map[string][]string, len(t.Config.StandingOrders)+1)
+	for r, o := range t.Config.StandingOrders {
+		updated[r] = o
+	}
+	if len(orders) > 0 {
+		updated[role] = orders
+	} else {
+		delete(updated, role)
+	}
+	if len(updated) == 0 {
+		updated = nil
+	}
+	t.Config.StandingOrders = updated
+	return t.Save()
+}
+
 // Exists checks if a territory exists at the given path.
 // This function works correctly even when called from within a worktree.
 func Exists(projectPath string) bool {
@@ -0,0 +1,105 @@
+package territory
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// WorkspaceVars are the job variables workspace files can use as {{name}}.
+var WorkspaceVars = []string{
+	"job_id",      // ID of the job
+	"short_id",    // First 8 characters of the job ID
+	"description", // Job description
+	"branch",      // Branch the job works on
+	"base_branch", // Branch the job worktree was created from
+}
+
+var workspaceVarPattern = regexp.MustCompile(`\{\{\s*([a-zA-Z0-9_]+)\s*\}\}`)
+
+// ApplyWorkspace copies the files under the territory's workspace directory
+// into the job worktree at dest, replacing {{name}} placeholders with vars.
+// Placeholders that are not workspace variables, and binary files, are left
+// as they are. Files the worktree already has are not overwritten, and the
+// copied files are excluded from git so they never end up in a commit. It
+// returns the paths copied, relative to dest.
+func (t *Territory) ApplyWorkspace(dest string, vars map[string]string) ([]string, error) {
+	src := t.WorkspacePath()
+	if _, err := os.Stat(src); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	var copied []string
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		target := filepath.Join(dest, rel)
+
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if _, err := os.Lstat(target); err == nil {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, renderWorkspaceFile(data, vars), info.Mode().Perm()); err != nil {
+			return err
+		}
+		copied = append(copied, rel)
+		return nil
+	})
+	if err != nil {
+		return copied, fmt.Errorf("failed to copy workspace files: %w", err)
+	}
+
+	if len(copied) > 0 && t.gitManager != nil {
+		if err := t.gitManager.ExcludePaths(copied); err != nil {
+			return copied, err
+		}
+	}
+	return copied, nil
+}
+
+// renderWorkspaceFile replaces the workspace variables in data. Binary
+// files are returned unchanged.
+func renderWorkspaceFile(data []byte, vars map[string]string) []byte {
+	if bytes.IndexByte(data, 0) >= 0 {
+		return data
+	}
+	return workspaceVarPattern.ReplaceAllFunc(data, func(m []byte) []byte {
+		name := string(workspaceVarPattern.FindSubmatch(m)[1])
+		if !isWorkspaceVar(name) {
+			return m
+		}
+		return []byte(vars[name])
+	})
+}
+
+func isWorkspaceVar(name string) bool {
+	for _, v := range WorkspaceVars {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
@@ -3,35 +3,79 @@ package tui
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"cosa/internal/config"
 	"cosa/internal/daemon"
+	"cosa/internal/display"
 	"cosa/internal/ledger"
 	"cosa/internal/protocol"
 	"cosa/internal/tui/component"
 	"cosa/internal/tui/page"
 	"cosa/internal/tui/styles"
+	"cosa/internal/tui/theme"
 )
 
 // App is the root Bubble Tea model.
 type App struct {
-	client    *daemon.Client
-	dashboard *page.Dashboard
-	chat      *page.Chat
-	styles    styles.Styles
-	width     int
-	height    int
-	err       error
-	quitting  bool
+	client     *daemon.Client
+	dashboard  *page.Dashboard
+	chat       *page.Chat
+	jobsPage   *page.Jobs
+	workerPage *page.WorkerDetail
+	conflicts  *page.Conflicts
+	review     *page.Review
+	styles     styles.Styles
+	width      int
+	height     int
+	err        error
+	quitting   bool
 
 	// Page routing
-	activePage string // "dashboard" or "chat"
+	activePage string // "dashboard", "chat", "jobs", "worker", "conflicts" or "review"
+
+	// Worker detail state: the worker shown and how far its transcript has
+	// been read
+	detailWorker     string
+	transcriptJob    string
+	transcriptOffset int
+
+	// Most recent merge conflict, opened by the triage key
+	lastConflict string
+
+	// Activity history paging: the oldest event loaded and whether the
+	// ledger has older ones
+	activityBefore  string
+	activityMore    bool
+	activityLoading bool
+
+	// Reviewer layout: the job whose diff was last requested
+	diffJob string
+
+	// Saves the layout when the user switches to another one
+	onLayoutChange func(layout string) error
+
+	// Name of the current theme, and saves it when the user switches
+	theme         string
+	onThemeChange func(theme string) error
+
+	// Identity whose watch list is shown and edited
+	identity string
+
+	// Version of the last state snapshot applied, and whether the daemon
+	// is unreachable
+	syncEpoch    int64
+	syncVersion  uint64
+	disconnected bool
 
 	// Chat state
 	chatStarted bool
+	newChat     bool // Start a fresh chat instead of resuming the last one
 	workers     []protocol.WorkerInfo
 	jobs        []protocol.JobInfo
 }
@@ -41,20 +85,36 @@ type App struct {
 type tickMsg time.Time
 
 type statusMsg *protocol.StatusResult
-type workersMsg []protocol.WorkerInfo
+type workersMsg []protocol.WorkerOverview
 type jobsMsg []protocol.JobInfo
 type templatesMsg []component.TemplateItem
+type presetsMsg []string
+type workerDetailMsg *protocol.WorkerDetailInfo
+type transcriptMsg struct {
+	worker string
+	offset int // Offset the transcript was requested from
+	result *protocol.WorkerTranscriptResult
+}
 type eventMsg ledger.Event
+type conflictsMsg []protocol.ConflictInfo
+type conflictDetailMsg *protocol.ConflictDetail
+type editorFinishedMsg struct {
+	jobID string
+	err   error
+}
 type errMsg error
 
 // Chat messages
 type chatStartedMsg struct {
 	sessionID string
 	greeting  string
+	resumed   bool
+	history   []protocol.ChatMessage // Earlier messages of a resumed chat
 	err       error
 }
 type chatResponseMsg struct {
 	response string
+	plan     *protocol.ChatPlan // Draft operation, in plan mode
 	err      error
 }
 type chatLoadingTickMsg struct{}
@@ -65,13 +125,17 @@ func NewApp(client *daemon.Client) *App {
 		client:     client,
 		dashboard:  page.NewDashboard(),
 		chat:       page.NewChat(),
+		jobsPage:   page.NewJobs(),
+		workerPage: page.NewWorkerDetail(),
+		conflicts:  page.NewConflicts(),
+		review:     page.NewReview(),
 		styles:     styles.New(),
 		activePage: "dashboard",
 	}
 
 	// Set up dashboard callbacks
-	app.dashboard.SetOnCreateJob(func(description string) {
-		app.createJob(description)
+	app.dashboard.SetOnCreateJob(func(description, preset string) {
+		app.createJob(description, preset)
 	})
 	app.dashboard.SetOnReassignJob(func(jobID string) {
 		app.reassignJob(jobID)
@@ -80,6 +144,23 @@ func NewApp(client *daemon.Client) *App {
 		app.useTemplate(templateID, variables)
 	})
 
+	// Set up jobs page callbacks
+	app.jobsPage.SetOnCancelJob(func(jobID string) {
+		app.cancelJob(jobID)
+	})
+	app.jobsPage.SetOnSetPriority(func(jobID string, priority int) {
+		app.setJobPriority(jobID, priority)
+	})
+	app.jobsPage.SetOnReassignJob(func(jobID string) {
+		app.reassignJob(jobID)
+	})
+	app.jobsPage.SetOnStartReview(func(jobID string) {
+		app.startReview(jobID)
+	})
+	app.jobsPage.SetOnApprove(func(jobID string) {
+		app.approveJob(jobID)
+	})
+
 	return app
 }
 
@@ -87,17 +168,20 @@ func NewApp(client *daemon.Client) *App {
 func (a *App) Init() tea.Cmd {
 	// Subscribe to events
 	if a.client != nil {
-		a.client.Subscribe([]string{"*"})
+		a.client.Subscribe(activityEvents)
 		a.client.OnNotification(func(r *protocol.Request) {
 			// Handle notifications in the background
 		})
 	}
 
+	a.activityLoading = true
+
 	return tea.Batch(
-		a.fetchStatus,
-		a.fetchWorkers,
-		a.fetchJobs,
+		a.waitForEvent,
+		a.fetchActivityHistory(""),
+		a.syncState(false),
 		a.fetchTemplates,
+		a.fetchPresets,
 		a.tickEvery(time.Second),
 	)
 }
@@ -113,9 +197,16 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.height = msg.Height
 		a.dashboard.SetSize(msg.Width, msg.Height)
 		a.chat.SetSize(msg.Width, msg.Height)
+		a.jobsPage.SetSize(msg.Width, msg.Height)
+		a.workerPage.SetSize(msg.Width, msg.Height)
+		a.conflicts.SetSize(msg.Width, msg.Height)
+		a.review.SetSize(msg.Width, msg.Height)
 		return a, nil
 
 	case tickMsg:
+		if a.disconnected {
+			return a, a.tickEvery(time.Second)
+		}
 		cmds := []tea.Cmd{
 			a.fetchStatus,
 			a.fetchWorkers,
@@ -126,6 +217,16 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if a.activePage == "chat" && a.chat.IsLoading() {
 			cmds = append(cmds, a.chatLoadingTick())
 		}
+		// Keep the worker detail page and its session output current
+		if a.activePage == "worker" {
+			cmds = append(cmds, a.fetchWorkerDetail, a.fetchTranscript)
+		}
+		if a.activePage == "conflicts" {
+			cmds = append(cmds, a.fetchConflicts)
+		}
+		if a.dashboard.Layout() == page.LayoutReviewer || a.activePage == "review" {
+			cmds = append(cmds, a.fetchReviews)
+		}
 		return a, tea.Batch(cmds...)
 
 	case statusMsg:
@@ -133,28 +234,93 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return a, nil
 
 	case workersMsg:
-		a.workers = msg
+		a.workers = make([]protocol.WorkerInfo, len(msg))
+		for i, w := range msg {
+			a.workers[i] = w.WorkerInfo
+		}
 		a.dashboard.SetWorkers(msg)
 		// Update chat sidebar
-		a.chat.SetWorkers(msg)
+		a.chat.SetWorkers(a.workers)
 		return a, nil
 
 	case jobsMsg:
 		a.jobs = msg
 		a.dashboard.SetJobs(msg)
+		a.jobsPage.SetJobs(msg)
+		a.review.SetJobs(msg)
 		// Update chat job counts
 		a.updateChatJobCounts()
+		if a.activePage == "review" {
+			return a, a.loadReviewDiff()
+		}
+		return a, a.loadSelectedDiff()
+
+	case reviewsMsg:
+		a.dashboard.SetReviews(msg)
+		a.review.SetReviews(msg)
+		return a, a.loadSelectedDiff()
+
+	case diffMsg:
+		a.applyDiff(msg)
+		a.review.SetDiff(msg.jobID, msg.result, msg.err)
 		return a, nil
 
 	case templatesMsg:
 		a.dashboard.SetTemplates(msg)
 		return a, nil
 
+	case presetsMsg:
+		a.dashboard.SetPresets(msg)
+		return a, nil
+
+	case workerDetailMsg:
+		a.workerPage.SetWorker(msg)
+		return a, nil
+
+	case transcriptMsg:
+		return a, a.applyTranscript(msg)
+
 	case eventMsg:
 		a.handleEvent(ledger.Event(msg))
+		return a, a.waitForEvent
+
+	case disconnectedMsg:
+		return a, a.handleDisconnect()
+
+	case reconnectMsg:
+		return a, a.handleReconnect(msg)
+
+	case syncStateMsg:
+		return a, a.applySyncState(msg)
+
+	case activityHistoryMsg:
+		a.addActivityHistory(msg)
+		return a, nil
+
+	case conflictsMsg:
+		a.conflicts.SetConflicts(msg)
+		if a.conflicts.Detail() == nil {
+			return a, a.fetchConflictDetail
+		}
+		return a, nil
+
+	case conflictDetailMsg:
+		a.conflicts.SetDetail(msg)
 		return a, nil
 
+	case editorFinishedMsg:
+		if msg.err != nil {
+			a.conflicts.SetMessage(fmt.Sprintf("Editor error: %v", msg.err))
+		} else {
+			a.conflicts.SetMessage("Edits saved; press m to commit and merge")
+		}
+		return a, a.fetchConflictDetail
+
 	case errMsg:
+		// A lost connection is shown in the header while reconnecting
+		if errors.Is(msg, daemon.ErrConnectionClosed) {
+			return a, nil
+		}
 		a.err = msg
 		return a, nil
 
@@ -166,8 +332,12 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, nil
 		}
 		a.chatStarted = true
-		a.chat.SetSessionID(msg.sessionID, false)
-		if msg.greeting != "" {
+		a.chat.SetSessionID(msg.sessionID, msg.resumed)
+		if msg.resumed {
+			for _, m := range msg.history {
+				a.chat.AddMessage(m.Role, m.Content)
+			}
+		} else if msg.greeting != "" {
 			a.chat.AddMessage("assistant", msg.greeting)
 		}
 		a.chat.SetLoading(false)
@@ -179,9 +349,20 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.chat.AddMessage("assistant", fmt.Sprintf("Error: %v", msg.err))
 		} else {
 			a.chat.AddMessage("assistant", msg.response)
+			a.chat.SetPlanMode(msg.plan != nil)
+			if msg.plan != nil {
+				a.addPlanBlock(msg.plan)
+			}
 		}
 		return a, nil
 
+	case chatPlanMsg:
+		a.applyChatPlan(msg)
+		return a, nil
+
+	case chatActionMsg:
+		return a, a.applyChatAction(msg)
+
 	case chatLoadingTickMsg:
 		a.chat.TickLoading()
 		if a.chat.IsLoading() {
@@ -199,6 +380,31 @@ func (a *App) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return a.handleChatKey(msg)
 	}
 
+	// Handle jobs page
+	if a.activePage == "jobs" {
+		return a.handleJobsKey(msg)
+	}
+
+	// Handle worker detail page
+	if a.activePage == "worker" {
+		return a.handleWorkerKey(msg)
+	}
+
+	// Handle conflict triage page
+	if a.activePage == "conflicts" {
+		return a.handleConflictsKey(msg)
+	}
+
+	// Handle review page
+	if a.activePage == "review" {
+		return a.handleReviewKey(msg)
+	}
+
+	// Handle the command palette
+	if a.dashboard.IsPaletteMode() {
+		return a.runPaletteAction(a.dashboard.HandlePaletteKey(msg.String()))
+	}
+
 	// Handle template selector mode
 	if a.dashboard.IsTemplateMode() {
 		a.dashboard.HandleTemplateSelectorKey(msg.String())
@@ -211,15 +417,47 @@ func (a *App) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return a, nil
 	}
 
+	if a.dashboard.Layout() == page.LayoutReviewer {
+		if handled, cmd := a.handleReviewerKey(msg.String()); handled {
+			return a, cmd
+		}
+	}
+
 	switch msg.String() {
 	case "q", "ctrl+c":
 		a.quitting = true
 		return a, tea.Quit
 
+	case "L":
+		// Switch dashboard layout
+		return a, a.cycleLayout()
+
+	case "x":
+		// Cancel the job selected in the operator queue
+		if j := a.dashboard.SelectedQueueJob(); j != nil && j.Status != "failed" {
+			a.cancelJob(j.ID)
+			return a, a.fetchJobs
+		}
+		return a, nil
+
 	case "c":
 		// Open chat with The Underboss
 		return a.openChat()
 
+	case "J":
+		// Open jobs page
+		a.activePage = "jobs"
+		a.jobsPage.SetSize(a.width, a.height)
+		return a, a.fetchJobs
+
+	case "C":
+		// Open merge conflict triage
+		return a.openConflicts(a.lastConflict)
+
+	case "V":
+		// Open the review page
+		return a.openReview(a.dashboard.SelectedReviewJob())
+
 	case "tab":
 		a.dashboard.NextFocus()
 		return a, nil
@@ -228,32 +466,41 @@ func (a *App) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		a.dashboard.PrevFocus()
 		return a, nil
 
-	case "j", "down", "k", "up":
+	case "e":
+		// Toggle the activity panel between all activity and errors only
 		a.dashboard.HandleKey(msg.String())
 		return a, nil
 
+	case "j", "down", "k", "up":
+		a.dashboard.HandleKey(msg.String())
+		if a.dashboard.Focus() == page.FocusActivity && a.dashboard.ActivityAtTop() {
+			// Page in older events when scrolling past the oldest one
+			return a, a.loadOlderActivity()
+		}
+		return a, a.loadSelectedDiff()
+
 	case "h", "left":
-		if a.dashboard.Focus() != page.FocusWorkers {
+		if !a.dashboard.FirstFocused() {
 			a.dashboard.PrevFocus()
 		}
 		return a, nil
 
 	case "l", "right":
-		if a.dashboard.Focus() == page.FocusWorkers {
+		if a.dashboard.FirstFocused() {
 			a.dashboard.NextFocus()
 		}
 		return a, nil
 
 	case "1":
-		a.dashboard.SetFocus(page.FocusWorkers)
+		a.dashboard.FocusPanel(0)
 		return a, nil
 
 	case "2":
-		a.dashboard.SetFocus(page.FocusJobs)
+		a.dashboard.FocusPanel(1)
 		return a, nil
 
 	case "3":
-		a.dashboard.SetFocus(page.FocusActivity)
+		a.dashboard.FocusPanel(2)
 		return a, nil
 
 	case "n":
@@ -287,7 +534,12 @@ func (a *App) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return a, nil
 
 	case "enter":
-		// Select current item (open worker detail, etc.)
+		// Open worker detail for the selected worker
+		if a.dashboard.Focus() == page.FocusWorkers {
+			if w := a.dashboard.SelectedWorker(); w != nil {
+				return a.openWorkerDetail(w.Name)
+			}
+		}
 		a.dashboard.SelectCurrent()
 		return a, nil
 
@@ -310,6 +562,14 @@ func (a *App) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			a.dashboard.ReassignSelectedJob()
 		}
 		return a, nil
+
+	case "w":
+		// Pin or unpin the selected job
+		if j := a.dashboard.SelectedJob(); j != nil {
+			a.toggleWatch(*j)
+			return a, a.fetchJobs
+		}
+		return a, nil
 	}
 
 	return a, nil
@@ -324,6 +584,11 @@ func (a *App) handleChatKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return a, nil
 	case "send":
 		input := a.chat.GetInput()
+		if cmd := a.chatCommand(input); cmd != nil {
+			a.chat.AddMessage("user", input)
+			a.chat.SetLoading(true)
+			return a, tea.Batch(cmd, a.chatLoadingTick())
+		}
 		if input != "" {
 			a.chat.AddMessage("user", input)
 			a.chat.SetLoading(true)
@@ -340,10 +605,90 @@ func (a *App) handleChatKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return a, nil
 }
 
+func (a *App) handleJobsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "ctrl+c" {
+		a.quitting = true
+		return a, tea.Quit
+	}
+
+	switch a.jobsPage.HandleKey(msg.String()) {
+	case "exit":
+		a.activePage = "dashboard"
+		return a, nil
+	}
+
+	return a, nil
+}
+
+func (a *App) handleWorkerKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "ctrl+c" {
+		a.quitting = true
+		return a, tea.Quit
+	}
+
+	switch a.workerPage.HandleKey(msg.String()) {
+	case "exit":
+		a.activePage = "dashboard"
+		a.detailWorker = ""
+		return a, nil
+	case "send":
+		if input := a.workerPage.GetInput(); input != "" {
+			a.messageWorker(a.detailWorker, input)
+		}
+	}
+
+	return a, nil
+}
+
+func (a *App) handleConflictsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "ctrl+c" {
+		a.quitting = true
+		return a, tea.Quit
+	}
+
+	jobID := a.conflicts.SelectedJob()
+
+	switch a.conflicts.HandleKey(msg.String()) {
+	case "exit":
+		a.activePage = "dashboard"
+		return a, nil
+	case "select", "refresh":
+		return a, tea.Batch(a.fetchConflicts, a.fetchConflictDetail)
+	case "assign":
+		a.assignConflictResolver(jobID)
+		return a, a.fetchConflicts
+	case "edit":
+		return a, a.editConflict(jobID)
+	case "merge":
+		a.retryMerge(jobID)
+		return a, tea.Batch(a.fetchConflicts, a.fetchConflictDetail)
+	case "abandon":
+		a.abandonMerge(jobID)
+		return a, a.fetchConflicts
+	}
+
+	return a, nil
+}
+
 func (a *App) handleEvent(event ledger.Event) {
+	if event.Type == eventMergeConflict {
+		var data ledger.JobEventData
+		json.Unmarshal(event.Data, &data)
+		a.lastConflict = data.ID
+	}
+
 	timeStr := event.Timestamp.Format("15:04:05")
-	var worker, message string
+	worker, message := describeEvent(event)
+
+	a.dashboard.AddEventActivity(timeStr, worker, message, event.Type.Severity())
+	if worker != "" && worker == a.detailWorker {
+		a.workerPage.AddActivity(timeStr, message)
+	}
+}
 
+// describeEvent returns the worker an event concerns and its activity feed
+// message.
+func describeEvent(event ledger.Event) (worker, message string) {
 	switch event.Type {
 	case ledger.EventWorkerAdded:
 		var data ledger.WorkerEventData
@@ -392,11 +737,58 @@ func (a *App) handleEvent(event ledger.Event) {
 		worker = data.WorkerName
 		message = fmt.Sprintf("Job cancelled: %s", truncate(data.Description, 30))
 
+	case ledger.EventWorkerRemediated:
+		var data struct {
+			WorkerName string `json:"worker_name"`
+			Severity   string `json:"severity"`
+			Action     string `json:"action"`
+			Error      string `json:"error"`
+		}
+		json.Unmarshal(event.Data, &data)
+		worker = data.WorkerName
+		if data.Error != "" {
+			message = fmt.Sprintf("Stuck (%s), %s failed: %s", data.Severity, data.Action, data.Error)
+		} else {
+			message = fmt.Sprintf("Stuck (%s), remediated: %s", data.Severity, data.Action)
+		}
+
+	case ledger.EventGateFailed:
+		var data ledger.GateEventData
+		json.Unmarshal(event.Data, &data)
+		message = fmt.Sprintf("Gate %s failed on job %s: %s", data.GateName, display.ShortID(data.JobID), data.Error)
+
+	case ledger.EventReviewFailed:
+		var data ledger.ReviewEventData
+		json.Unmarshal(event.Data, &data)
+		worker = data.WorkerName
+		message = fmt.Sprintf("Review of job %s failed: %s", display.ShortID(data.JobID), data.Error)
+
+	case ledger.EventMergeFailed:
+		var data ledger.MergeEventData
+		json.Unmarshal(event.Data, &data)
+		message = fmt.Sprintf("Merge of job %s failed: %s", display.ShortID(data.JobID), data.Error)
+
+	case ledger.EventBudgetExceeded:
+		var data ledger.BudgetEventData
+		json.Unmarshal(event.Data, &data)
+		worker = data.WorkerName
+		message = fmt.Sprintf("Over the %s budget: $%.2f of $%.2f", data.Scope, data.Spent, data.Limit)
+
+	case eventMergeConflict:
+		var data ledger.JobEventData
+		json.Unmarshal(event.Data, &data)
+		message = fmt.Sprintf("Merge conflict in job %s (press C to triage)", display.ShortID(data.ID))
+
+	case eventConflictResolved:
+		var data ledger.JobEventData
+		json.Unmarshal(event.Data, &data)
+		message = fmt.Sprintf("Conflict resolved: %s", display.ShortID(data.ID))
+
 	default:
 		message = string(event.Type)
 	}
 
-	a.dashboard.AddActivity(timeStr, worker, message)
+	return worker, message
 }
 
 // View renders the app.
@@ -413,6 +805,22 @@ func (a *App) View() string {
 		return a.chat.View()
 	}
 
+	if a.activePage == "jobs" {
+		return a.jobsPage.View()
+	}
+
+	if a.activePage == "worker" {
+		return a.workerPage.View()
+	}
+
+	if a.activePage == "conflicts" {
+		return a.conflicts.View()
+	}
+
+	if a.activePage == "review" {
+		return a.review.View()
+	}
+
 	return a.dashboard.View()
 }
 
@@ -435,7 +843,8 @@ func (a *App) fetchWorkers() tea.Msg {
 		return nil
 	}
 
-	resp, err := a.client.Call(protocol.MethodWorkerList, nil)
+	// The overview carries each worker's progress along with the worker
+	resp, err := a.client.Call(protocol.MethodOverview, nil)
 	if err != nil {
 		return errMsg(err)
 	}
@@ -444,9 +853,9 @@ func (a *App) fetchWorkers() tea.Msg {
 		return nil
 	}
 
-	var workers []protocol.WorkerInfo
-	json.Unmarshal(resp.Result, &workers)
-	return workersMsg(workers)
+	var overview protocol.OverviewResult
+	json.Unmarshal(resp.Result, &overview)
+	return workersMsg(overview.Workers)
 }
 
 func (a *App) fetchJobs() tea.Msg {
@@ -454,7 +863,7 @@ func (a *App) fetchJobs() tea.Msg {
 		return nil
 	}
 
-	resp, err := a.client.Call(protocol.MethodJobList, nil)
+	resp, err := a.client.Call(protocol.MethodJobList, protocol.JobListParams{Identity: a.identity})
 	if err != nil {
 		return errMsg(err)
 	}
@@ -468,6 +877,108 @@ func (a *App) fetchJobs() tea.Msg {
 	return jobsMsg(jobs)
 }
 
+// openWorkerDetail switches to the worker detail page and backfills its
+// session output from the worker's transcript.
+func (a *App) openWorkerDetail(name string) (tea.Model, tea.Cmd) {
+	a.activePage = "worker"
+	a.detailWorker = name
+	a.transcriptJob = ""
+	a.transcriptOffset = 0
+
+	a.workerPage = page.NewWorkerDetail()
+	a.workerPage.SetSize(a.width, a.height)
+
+	return a, tea.Batch(a.fetchWorkerDetail, a.fetchTranscript)
+}
+
+func (a *App) fetchWorkerDetail() tea.Msg {
+	if a.client == nil || a.detailWorker == "" {
+		return nil
+	}
+
+	resp, err := a.client.Call(protocol.MethodWorkerDetail, map[string]string{"name": a.detailWorker})
+	if err != nil {
+		return errMsg(err)
+	}
+
+	if resp.Error != nil {
+		return nil
+	}
+
+	var info protocol.WorkerDetailInfo
+	json.Unmarshal(resp.Result, &info)
+	return workerDetailMsg(&info)
+}
+
+func (a *App) fetchTranscript() tea.Msg {
+	if a.client == nil || a.detailWorker == "" {
+		return nil
+	}
+
+	worker, offset := a.detailWorker, a.transcriptOffset
+	resp, err := a.client.Call(protocol.MethodWorkerTranscript, protocol.WorkerTranscriptParams{
+		Name:   worker,
+		Offset: offset,
+	})
+	if err != nil {
+		return errMsg(err)
+	}
+
+	if resp.Error != nil {
+		return nil
+	}
+
+	var result protocol.WorkerTranscriptResult
+	json.Unmarshal(resp.Result, &result)
+	return transcriptMsg{worker: worker, offset: offset, result: &result}
+}
+
+// applyTranscript appends newly read transcript entries to the worker page.
+// When the worker moves on to another job the session output is reset and
+// the new job's transcript is read from the start.
+func (a *App) applyTranscript(msg transcriptMsg) tea.Cmd {
+	if msg.worker != a.detailWorker || msg.offset != a.transcriptOffset {
+		return nil // Stale response
+	}
+
+	if msg.result.JobID != a.transcriptJob {
+		a.transcriptJob = msg.result.JobID
+		a.transcriptOffset = 0
+		a.workerPage.SetSessionOutput(nil)
+		if msg.offset != 0 {
+			return a.fetchTranscript
+		}
+	}
+
+	a.workerPage.AppendTranscript(msg.result.Entries)
+	a.transcriptOffset = msg.result.Next
+	return nil
+}
+
+func (a *App) messageWorker(name, message string) {
+	if a.client == nil {
+		a.workerPage.AddActivity(time.Now().Format("15:04:05"), "Error: No connection to daemon")
+		return
+	}
+
+	resp, err := a.client.Call(protocol.MethodWorkerMessage, map[string]string{
+		"name":    name,
+		"message": message,
+	})
+	now := time.Now().Format("15:04:05")
+	if err != nil {
+		a.workerPage.AddActivity(now, fmt.Sprintf("Error sending message: %v", err))
+		return
+	}
+
+	if resp.Error != nil {
+		a.workerPage.AddActivity(now, fmt.Sprintf("Error: %s", resp.Error.Message))
+		return
+	}
+
+	a.workerPage.AddActivity(now, "Message sent: "+truncate(message, 40))
+}
+
 func (a *App) tickEvery(d time.Duration) tea.Cmd {
 	return tea.Tick(d, func(t time.Time) tea.Msg {
 		return tickMsg(t)
@@ -514,7 +1025,27 @@ func (a *App) fetchTemplates() tea.Msg {
 	return templatesMsg(items)
 }
 
-func (a *App) createJob(description string) {
+func (a *App) fetchPresets() tea.Msg {
+	if a.client == nil {
+		return nil
+	}
+
+	resp, err := a.client.Call(protocol.MethodPresetList, nil)
+	if err != nil || resp.Error != nil {
+		return nil // Presets are optional
+	}
+
+	var result protocol.PresetListResult
+	json.Unmarshal(resp.Result, &result)
+
+	names := make([]string, len(result.Presets))
+	for i, p := range result.Presets {
+		names[i] = p.Name
+	}
+	return presetsMsg(names)
+}
+
+func (a *App) createJob(description, preset string) {
 	if a.client == nil {
 		a.dashboard.AddActivity(time.Now().Format("15:04:05"), "", "Error: No connection to daemon")
 		return
@@ -522,7 +1053,10 @@ func (a *App) createJob(description string) {
 
 	params := protocol.JobAddParams{
 		Description: description,
-		Priority:    3, // Default priority
+		Preset:      preset,
+	}
+	if preset == "" {
+		params.Priority = 3 // Default priority
 	}
 
 	resp, err := a.client.Call(protocol.MethodJobAdd, params)
@@ -541,7 +1075,7 @@ func (a *App) createJob(description string) {
 
 func (a *App) reassignJob(jobID string) {
 	if a.client == nil {
-		a.dashboard.AddActivity(time.Now().Format("15:04:05"), "", "Error: No connection to daemon")
+		a.jobFeedback("Error: No connection to daemon")
 		return
 	}
 
@@ -551,7 +1085,60 @@ func (a *App) reassignJob(jobID string) {
 
 	resp, err := a.client.Call(protocol.MethodJobReassign, params)
 	if err != nil {
-		a.dashboard.AddActivity(time.Now().Format("15:04:05"), "", fmt.Sprintf("Error reassigning job: %v", err))
+		a.jobFeedback(fmt.Sprintf("Error reassigning job: %v", err))
+		return
+	}
+
+	if resp.Error != nil {
+		a.jobFeedback(fmt.Sprintf("Error: %s", resp.Error.Message))
+		return
+	}
+
+	a.jobFeedback(fmt.Sprintf("Job reassigned: %s", display.ShortID(jobID)))
+}
+
+// jobFeedback reports the result of a job action on the jobs page, where
+// the activity feed is out of sight, as well as in the activity feed.
+func (a *App) jobFeedback(message string) {
+	a.dashboard.AddActivity(time.Now().Format("15:04:05"), "", message)
+	a.jobsPage.SetMessage(message)
+}
+
+func (a *App) cancelJob(jobID string) {
+	if a.client == nil {
+		a.jobFeedback("Error: No connection to daemon")
+		return
+	}
+
+	resp, err := a.client.Call(protocol.MethodJobCancel, protocol.JobCancelParams{ID: jobID})
+	if err != nil {
+		a.jobFeedback(fmt.Sprintf("Error cancelling job: %v", err))
+		return
+	}
+
+	if resp.Error != nil {
+		a.jobFeedback(fmt.Sprintf("Error: %s", resp.Error.Message))
+		return
+	}
+
+	a.jobFeedback(fmt.Sprintf("Job cancelled: %s", display.ShortID(jobID)))
+}
+
+// toggleWatch adds j to the watch list, or removes it if it is watched.
+func (a *App) toggleWatch(j protocol.JobInfo) {
+	if a.client == nil {
+		a.dashboard.AddActivity(time.Now().Format("15:04:05"), "", "Error: No connection to daemon")
+		return
+	}
+
+	method, verb := protocol.MethodJobWatch, "Watching"
+	if j.Watched {
+		method, verb = protocol.MethodJobUnwatch, "Stopped watching"
+	}
+
+	resp, err := a.client.Call(method, protocol.JobWatchParams{ID: j.ID, Identity: a.identity})
+	if err != nil {
+		a.dashboard.AddActivity(time.Now().Format("15:04:05"), "", fmt.Sprintf("Error updating watch list: %v", err))
 		return
 	}
 
@@ -560,7 +1147,72 @@ func (a *App) reassignJob(jobID string) {
 		return
 	}
 
-	a.dashboard.AddActivity(time.Now().Format("15:04:05"), "", fmt.Sprintf("Job reassigned: %s", jobID[:8]))
+	a.dashboard.AddActivity(time.Now().Format("15:04:05"), "", fmt.Sprintf("%s job: %s", verb, display.ShortID(j.ID)))
+}
+
+func (a *App) setJobPriority(jobID string, priority int) {
+	if a.client == nil {
+		a.jobFeedback("Error: No connection to daemon")
+		return
+	}
+
+	params := protocol.JobSetPriorityParams{
+		JobID:    jobID,
+		Priority: priority,
+	}
+
+	resp, err := a.client.Call(protocol.MethodJobSetPriority, params)
+	if err != nil {
+		a.jobFeedback(fmt.Sprintf("Error setting priority: %v", err))
+		return
+	}
+
+	if resp.Error != nil {
+		a.jobFeedback(fmt.Sprintf("Error: %s", resp.Error.Message))
+		return
+	}
+
+	a.jobFeedback(fmt.Sprintf("Job %s priority set to %d", display.ShortID(jobID), priority))
+}
+
+func (a *App) startReview(jobID string) {
+	if a.client == nil {
+		a.jobFeedback("Error: No connection to daemon")
+		return
+	}
+
+	resp, err := a.client.Call(protocol.MethodReviewStart, protocol.ReviewStartParams{JobID: jobID})
+	if err != nil {
+		a.jobFeedback(fmt.Sprintf("Error starting review: %v", err))
+		return
+	}
+
+	if resp.Error != nil {
+		a.jobFeedback(fmt.Sprintf("Error: %s", resp.Error.Message))
+		return
+	}
+
+	a.jobFeedback(fmt.Sprintf("Review started: %s", display.ShortID(jobID)))
+}
+
+func (a *App) approveJob(jobID string) {
+	if a.client == nil {
+		a.jobFeedback("Error: No connection to daemon")
+		return
+	}
+
+	resp, err := a.client.Call(protocol.MethodJobApprove, protocol.JobApproveParams{ID: jobID})
+	if err != nil {
+		a.jobFeedback(fmt.Sprintf("Error approving job: %v", err))
+		return
+	}
+
+	if resp.Error != nil {
+		a.jobFeedback(fmt.Sprintf("Error: %s", resp.Error.Message))
+		return
+	}
+
+	a.jobFeedback(fmt.Sprintf("Job approved: %s", display.ShortID(jobID)))
 }
 
 func (a *App) useTemplate(templateID string, variables map[string]string) {
@@ -588,14 +1240,11 @@ func (a *App) useTemplate(templateID string, variables map[string]string) {
 	var result protocol.TemplateUseResult
 	json.Unmarshal(resp.Result, &result)
 
-	a.dashboard.AddActivity(time.Now().Format("15:04:05"), "", fmt.Sprintf("Job created from template: %s", result.Job.ID[:8]))
+	a.dashboard.AddActivity(time.Now().Format("15:04:05"), "", fmt.Sprintf("Job created from template: %s", display.ShortID(result.Job.ID)))
 }
 
 func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
-	}
-	return s[:maxLen-2] + ".."
+	return display.Truncate(s, maxLen)
 }
 
 // Chat commands
@@ -624,7 +1273,9 @@ func (a *App) startChat() tea.Cmd {
 			return chatStartedMsg{err: fmt.Errorf("no connection to daemon")}
 		}
 
-		resp, err := a.client.Call(protocol.MethodChatStart, nil)
+		resp, err := a.client.Call(protocol.MethodChatStart, protocol.ChatStartParams{
+			Resume: !a.newChat,
+		})
 		if err != nil {
 			return chatStartedMsg{err: err}
 		}
@@ -638,10 +1289,26 @@ func (a *App) startChat() tea.Cmd {
 			return chatStartedMsg{err: err}
 		}
 
-		return chatStartedMsg{
+		msg := chatStartedMsg{
 			sessionID: result.SessionID,
 			greeting:  result.Greeting,
+			resumed:   result.Resumed,
+		}
+
+		// Load the transcript of a resumed chat
+		if result.Resumed {
+			resp, err := a.client.Call(protocol.MethodChatHistory, protocol.ChatHistoryParams{
+				SessionID: result.SessionID,
+			})
+			if err == nil && resp.Error == nil {
+				var history protocol.ChatHistoryResult
+				if json.Unmarshal(resp.Result, &history) == nil {
+					msg.history = history.Messages
+				}
+			}
 		}
+
+		return msg
 	}
 }
 
@@ -667,7 +1334,7 @@ func (a *App) sendChatMessage(message string) tea.Cmd {
 			return chatResponseMsg{err: err}
 		}
 
-		return chatResponseMsg{response: result.Response}
+		return chatResponseMsg{response: result.Response, plan: result.Plan}
 	}
 }
 
@@ -693,10 +1360,61 @@ func (a *App) updateChatJobCounts() {
 	})
 }
 
-// Run starts the TUI.
-func Run(client *daemon.Client) error {
+// Options configure the TUI.
+type Options struct {
+	NewChat  bool   // Start a new chat instead of resuming the last one
+	Layout   string // Dashboard layout to open with; empty for the default
+	Identity string // Identity whose watched jobs are pinned in the jobs panel
+	Observe  bool   // The connection is read-only
+	Replay   bool   // The client is connected to a replay of a ledger, not a daemon
+
+	// Filters are the saved job filters the jobs page cycles through.
+	Filters map[string]config.JobFilter
+
+	// OnLayoutChange is called with the layout the user switches to, so it
+	// can be remembered for the next run.
+	OnLayoutChange func(layout string) error
+
+	// Theme is the theme to open with, a built-in or one of the user's
+	// custom themes; empty or unknown names keep the default. OnThemeChange
+	// is called with the theme the user switches to from the palette.
+	Theme         string
+	OnThemeChange func(theme string) error
+}
+
+// Run starts the TUI. Unless opts.NewChat is set, the chat page resumes the
+// most recent chat with the underboss.
+func Run(client *daemon.Client, opts Options) error {
+	// Styles are built from the theme, so it is set before the pages
+	theme.LoadUserThemes()
+	current := theme.Current.Name
+	if opts.Theme != "" && theme.SetTheme(opts.Theme) {
+		current = opts.Theme
+	}
+
 	app := NewApp(client)
+	app.theme = current
+	app.onThemeChange = opts.OnThemeChange
+	app.newChat = opts.NewChat
+	app.onLayoutChange = opts.OnLayoutChange
+	app.identity = opts.Identity
+	app.dashboard.SetObserving(opts.Observe)
+	app.dashboard.SetReplaying(opts.Replay)
+	app.jobsPage.SetSavedFilters(savedFilters(opts.Filters))
+	if layout, ok := page.ParseLayout(opts.Layout); ok {
+		app.dashboard.SetLayout(layout)
+	}
 	p := tea.NewProgram(app, tea.WithAltScreen())
 	_, err := p.Run()
 	return err
 }
+
+// savedFilters orders the saved job filters by name.
+func savedFilters(filters map[string]config.JobFilter) []page.SavedFilter {
+	saved := make([]page.SavedFilter, 0, len(filters))
+	for name, f := range filters {
+		saved = append(saved, page.SavedFilter{Name: name, Status: f.Status, Labels: f.Labels})
+	}
+	sort.Slice(saved, func(i, j int) bool { return saved[i].Name < saved[j].Name })
+	return saved
+}
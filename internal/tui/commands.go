@@ -0,0 +1,314 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cosa/internal/display"
+	"cosa/internal/protocol"
+)
+
+// chatActionMsg is the result of an action command typed in the chat.
+type chatActionMsg struct {
+	reply string
+	err   error
+}
+
+// chatCommandHelp describes the commands the chat runs itself instead of
+// sending them to the underboss.
+var chatCommandHelp = [][2]string{
+	{`/job add "<description>" [-p 1-5] [-w worker] [-m model] [-l key=value] [--sticky-worker worker]`, "Add a job"},
+	{"/job cancel <id>", "Cancel a job"},
+	{"/job unstick <id>", "Stop a job waiting for its sticky worker"},
+	{"/worker add <name> [-r role]", "Add a worker (soldato unless -r is given)"},
+	{"/worker remove <name> [--force]", "Remove a worker"},
+	{"/status", "Show the daemon's status"},
+	{"/plan, /show, /execute [name], /discard", "Draft jobs in plan mode"},
+	{"/help", "Show these commands"},
+}
+
+// chatAction returns the command for an action typed in the chat, such as
+// /job add. Input that is not a command returns nil and goes to the
+// underboss.
+func (a *App) chatAction(input string) tea.Cmd {
+	if !strings.HasPrefix(input, "/") {
+		return nil
+	}
+
+	args, err := splitArgs(input)
+	if err != nil {
+		return chatReply("", err)
+	}
+
+	switch strings.Join(args[:min(2, len(args))], " ") {
+	case "/job add":
+		return a.chatJobAdd(args[2:])
+	case "/job cancel":
+		if len(args) != 3 {
+			return chatReply("", fmt.Errorf("usage: /job cancel <id>"))
+		}
+		id := a.resolveJobID(args[2])
+		return a.chatCall(protocol.MethodJobCancel, protocol.JobCancelParams{ID: id}, func(json.RawMessage) string {
+			return "Cancelled job " + display.ShortID(id) + "."
+		})
+	case "/job unstick":
+		if len(args) != 3 {
+			return chatReply("", fmt.Errorf("usage: /job unstick <id>"))
+		}
+		id := a.resolveJobID(args[2])
+		return a.chatCall(protocol.MethodJobUnstick, protocol.JobUnstickParams{ID: id}, func(result json.RawMessage) string {
+			var unstuck protocol.JobUnstickResult
+			json.Unmarshal(result, &unstuck)
+			if unstuck.Worker == "" {
+				return "Job " + display.ShortID(id) + " is not pinned to a worker."
+			}
+			return "Job " + display.ShortID(id) + " is no longer pinned to " + unstuck.Worker + "."
+		})
+	case "/worker add":
+		return a.chatWorkerAdd(args[2:])
+	case "/worker remove":
+		flags, rest, err := parseChatFlags(args[2:], nil, []string{"force"})
+		if err != nil || len(rest) != 1 {
+			return chatReply("", fmt.Errorf("usage: /worker remove <name> [--force]"))
+		}
+		params := protocol.WorkerRemoveParams{Name: rest[0], Force: flags["force"] != ""}
+		return a.chatCall(protocol.MethodWorkerRemove, params, func(json.RawMessage) string {
+			return "Removed worker " + params.Name + "."
+		})
+	}
+
+	switch args[0] {
+	case "/status":
+		return a.chatCall(protocol.MethodStatus, nil, func(result json.RawMessage) string {
+			var status protocol.StatusResult
+			json.Unmarshal(result, &status)
+			cost := status.TotalCost
+			if cost == "" {
+				cost = "$0.00"
+			}
+			return fmt.Sprintf("%d workers, %d jobs running, %s spent.", status.Workers, status.ActiveJobs, cost)
+		})
+	case "/help":
+		lines := make([]string, 0, len(chatCommandHelp))
+		for _, c := range chatCommandHelp {
+			lines = append(lines, c[0]+"\n    "+c[1])
+		}
+		return chatReply(strings.Join(lines, "\n"), nil)
+	}
+	return chatReply("", fmt.Errorf("unknown command %s; /help lists the commands", strings.Join(args[:min(2, len(args))], " ")))
+}
+
+func (a *App) chatJobAdd(args []string) tea.Cmd {
+	usage := fmt.Errorf(`usage: /job add "<description>" [-p 1-5] [-w worker] [-m model] [-l key=value] [--sticky-worker worker]`)
+	flags, rest, err := parseChatFlags(args, []string{"p", "priority", "w", "worker", "m", "model", "l", "label", "sticky-worker"}, nil)
+	if err != nil || len(rest) == 0 {
+		return chatReply("", usage)
+	}
+
+	params := protocol.JobAddParams{
+		Description: strings.Join(rest, " "),
+		Priority:    3,
+		Worker:      flagValue(flags, "w", "worker"),
+		Model:       flagValue(flags, "m", "model"),
+		Requester:   a.identity,
+
+		StickyWorker: flagValue(flags, "sticky-worker"),
+	}
+	if p := flagValue(flags, "p", "priority"); p != "" {
+		params.Priority, err = strconv.Atoi(p)
+		if err != nil || params.Priority < 1 || params.Priority > 5 {
+			return chatReply("", fmt.Errorf("invalid priority %s (must be 1-5)", p))
+		}
+	}
+	for _, label := range strings.Split(flagValue(flags, "l", "label"), "\n") {
+		if label == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(label, "=")
+		if !ok || key == "" {
+			return chatReply("", fmt.Errorf("invalid label %s (must be key=value)", label))
+		}
+		if params.Labels == nil {
+			params.Labels = make(map[string]string)
+		}
+		params.Labels[key] = value
+	}
+
+	return a.chatCall(protocol.MethodJobAdd, params, func(result json.RawMessage) string {
+		var info protocol.JobInfo
+		json.Unmarshal(result, &info)
+		reply := fmt.Sprintf("Added job %s: %s (priority %d", display.ShortID(info.ID), info.Description, info.Priority)
+		if info.StickyWorker != "" {
+			reply += ", pinned to " + info.StickyWorker
+		} else if info.Worker != "" {
+			reply += ", for " + info.Worker
+		}
+		return reply + ")."
+	})
+}
+
+func (a *App) chatWorkerAdd(args []string) tea.Cmd {
+	flags, rest, err := parseChatFlags(args, []string{"r", "role"}, nil)
+	if err != nil || len(rest) != 1 {
+		return chatReply("", fmt.Errorf("usage: /worker add <name> [-r role]"))
+	}
+
+	params := protocol.WorkerAddParams{Name: rest[0], Role: flagValue(flags, "r", "role")}
+	return a.chatCall(protocol.MethodWorkerAdd, params, func(result json.RawMessage) string {
+		var info protocol.WorkerInfo
+		json.Unmarshal(result, &info)
+		return fmt.Sprintf("Added %s %s.", info.Role, info.Name)
+	})
+}
+
+// chatCall calls the daemon and replies in the chat with describe's account
+// of the result.
+func (a *App) chatCall(method string, params interface{}, describe func(json.RawMessage) string) tea.Cmd {
+	client := a.client
+	return func() tea.Msg {
+		if client == nil {
+			return chatActionMsg{err: fmt.Errorf("no connection to daemon")}
+		}
+		resp, err := client.Call(method, params)
+		if err != nil {
+			return chatActionMsg{err: err}
+		}
+		if resp.Error != nil {
+			return chatActionMsg{err: fmt.Errorf("%s", resp.Error.Message)}
+		}
+		return chatActionMsg{reply: describe(resp.Result)}
+	}
+}
+
+// applyChatAction shows the outcome of an action command in the chat and
+// refreshes the state it may have changed.
+func (a *App) applyChatAction(msg chatActionMsg) tea.Cmd {
+	a.chat.SetLoading(false)
+	if msg.err != nil {
+		a.chat.AddMessage("command", "Error: "+msg.err.Error())
+		return nil
+	}
+	a.chat.AddMessage("command", msg.reply)
+	return a.syncState(false)
+}
+
+// resolveJobID expands a short job ID from the jobs the TUI knows of.
+func (a *App) resolveJobID(id string) string {
+	for _, j := range a.jobs {
+		if strings.HasPrefix(j.ID, id) {
+			return j.ID
+		}
+	}
+	return id
+}
+
+func chatReply(reply string, err error) tea.Cmd {
+	return func() tea.Msg { return chatActionMsg{reply: reply, err: err} }
+}
+
+// parseChatFlags separates flags from the other arguments. Flags named in
+// valued take a value; a flag given more than once has its values joined
+// by newlines. Flags named in switches take none.
+func parseChatFlags(args, valued, switches []string) (map[string]string, []string, error) {
+	flags := make(map[string]string)
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name := strings.TrimLeft(arg, "-")
+		if !strings.HasPrefix(arg, "-") || name == "" {
+			rest = append(rest, arg)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(name, "=")
+		switch {
+		case contains(switches, name):
+			flags[name] = "true"
+		case contains(valued, name):
+			if !hasValue {
+				if i+1 >= len(args) {
+					return nil, nil, fmt.Errorf("%s needs a value", arg)
+				}
+				i++
+				value = args[i]
+			}
+			if flags[name] != "" {
+				value = flags[name] + "\n" + value
+			}
+			flags[name] = value
+		default:
+			return nil, nil, fmt.Errorf("unknown flag %s", arg)
+		}
+	}
+	return flags, rest, nil
+}
+
+// flagValue returns the values given for any of a flag's names, joined by
+// newlines.
+func flagValue(flags map[string]string, names ...string) string {
+	var values []string
+	for _, name := range names {
+		if v := flags[name]; v != "" {
+			values = append(values, v)
+		}
+	}
+	return strings.Join(values, "\n")
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// splitArgs splits a command line into words. Single and double quotes
+// group words, and a backslash escapes the next character.
+func splitArgs(s string) ([]string, error) {
+	var args []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\' && quote != '\'' && i+1 < len(runes):
+			i++
+			word.WriteRune(runes[i])
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				args = append(args, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		args = append(args, word.String())
+	}
+	return args, nil
+}
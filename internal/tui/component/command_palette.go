@@ -6,6 +6,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 
+	"cosa/internal/display"
 	"cosa/internal/tui/theme"
 )
 
@@ -53,9 +54,31 @@ func defaultCommands() []Command {
 		{Name: "Worker List", Description: "Show all workers", Shortcut: "1", Action: "focus_workers"},
 		{Name: "Job List", Description: "Show all jobs", Shortcut: "2", Action: "focus_jobs"},
 		{Name: "Activity", Description: "Show activity log", Shortcut: "3", Action: "focus_activity"},
+		{Name: "Theme", Description: "Switch the color theme", Action: "theme"},
 	}
 }
 
+// ThemeAction is the action prefix of the commands ShowThemes offers; the
+// theme name follows it.
+const ThemeAction = "theme:"
+
+// ShowThemes shows the palette with a command for each theme, marking the
+// current one and selecting it.
+func (cp *CommandPalette) ShowThemes(names []string, current string) {
+	cp.Show()
+	cp.commands = make([]Command, 0, len(names))
+	for i, name := range names {
+		cmd := Command{Name: name, Description: "Switch to the " + name + " theme", Action: ThemeAction + name}
+		if name == current {
+			cmd.Description = "Current theme"
+			cp.selected = i
+		}
+		cp.commands = append(cp.commands, cmd)
+	}
+	cp.filtered = cp.commands
+	cp.input.SetPlaceholder("Choose a theme...")
+}
+
 // SetSize sets the palette dimensions.
 func (cp *CommandPalette) SetSize(width, maxHeight int) {
 	cp.width = width
@@ -68,7 +91,9 @@ func (cp *CommandPalette) Show() {
 	cp.visible = true
 	cp.input.Reset()
 	cp.input.Focus()
+	cp.input.SetPlaceholder("Type a command...")
 	cp.selected = 0
+	cp.commands = defaultCommands()
 	cp.filtered = cp.commands
 }
 
@@ -228,7 +253,10 @@ func (cp *CommandPalette) renderCommandLine(cmd Command, selected bool) string {
 	// Build line
 	name := nameStyle.Render(cmd.Name)
 	desc := descStyle.Render(" - " + cmd.Description)
-	shortcut := shortcutStyle.Render(" [" + cmd.Shortcut + "]")
+	shortcut := ""
+	if cmd.Shortcut != "" {
+		shortcut = shortcutStyle.Render(" [" + cmd.Shortcut + "]")
+	}
 
 	// Truncate if needed
 	available := cp.width - 10
@@ -237,11 +265,7 @@ func (cp *CommandPalette) renderCommandLine(cmd Command, selected bool) string {
 		// Truncate description
 		descLen := available - lipgloss.Width(name) - 4
 		if descLen > 0 {
-			truncDesc := cmd.Description
-			if len(truncDesc) > descLen {
-				truncDesc = truncDesc[:descLen-2] + ".."
-			}
-			desc = descStyle.Render(" - " + truncDesc)
+			desc = descStyle.Render(" - " + display.Truncate(cmd.Description, descLen))
 		} else {
 			desc = ""
 		}
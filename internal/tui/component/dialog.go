@@ -27,6 +27,13 @@ type Dialog struct {
 	hasTextArea  bool
 	textAreaLabel string
 
+	// Dropdown selector (shown below the text area)
+	hasSelect     bool
+	selectLabel   string
+	selectOptions []string
+	selectIndex   int
+	selectFocused bool
+
 	// Buttons
 	buttons        []DialogButton
 	selectedButton int
@@ -84,6 +91,40 @@ func (d *Dialog) SetTextArea(label string) {
 	d.textArea.Focus()
 }
 
+// SetSelect enables a dropdown selector with a label and options.
+func (d *Dialog) SetSelect(label string, options []string) {
+	d.hasSelect = true
+	d.selectLabel = label
+	d.SetSelectOptions(options)
+}
+
+// SetSelectOptions replaces the dropdown options, keeping the current
+// selection if it is still present.
+func (d *Dialog) SetSelectOptions(options []string) {
+	current := d.SelectedOption()
+	d.selectOptions = options
+	d.selectIndex = 0
+	for i, opt := range options {
+		if opt == current {
+			d.selectIndex = i
+			break
+		}
+	}
+}
+
+// SelectedOption returns the currently selected dropdown option.
+func (d *Dialog) SelectedOption() string {
+	if d.selectIndex >= 0 && d.selectIndex < len(d.selectOptions) {
+		return d.selectOptions[d.selectIndex]
+	}
+	return ""
+}
+
+// selectActive returns true if the dropdown can take focus.
+func (d *Dialog) selectActive() bool {
+	return d.hasSelect && len(d.selectOptions) > 1
+}
+
 // AddButton adds a button to the dialog.
 func (d *Dialog) AddButton(label, action string, primary bool) {
 	d.buttons = append(d.buttons, DialogButton{
@@ -107,6 +148,8 @@ func (d *Dialog) Show() {
 // Hide hides the dialog.
 func (d *Dialog) Hide() {
 	d.visible = false
+	d.selectFocused = false
+	d.selectIndex = 0
 	if d.input != nil {
 		d.input.Reset()
 	}
@@ -143,10 +186,34 @@ func (d *Dialog) SelectedAction() string {
 func (d *Dialog) HandleKey(key string) string {
 	// Handle text area input
 	if d.hasTextArea && d.textArea != nil {
+		if d.selectFocused {
+			switch key {
+			case "up", "left", "k":
+				if d.selectIndex > 0 {
+					d.selectIndex--
+				}
+				return ""
+			case "down", "right", "j":
+				if d.selectIndex < len(d.selectOptions)-1 {
+					d.selectIndex++
+				}
+				return ""
+			case "tab", "enter":
+				// Move focus from dropdown to buttons
+				d.selectFocused = false
+				d.selectedButton = 0
+				return ""
+			}
+		}
+
 		switch key {
 		case "tab":
-			// Move focus from text area to buttons
-			if d.textArea.Focused() && len(d.buttons) > 0 {
+			// Move focus from text area to dropdown (if any), then buttons
+			if d.textArea.Focused() && d.selectActive() {
+				d.textArea.Blur()
+				d.selectFocused = true
+				d.selectedButton = -1
+			} else if d.textArea.Focused() && len(d.buttons) > 0 {
 				d.textArea.Blur()
 				d.selectedButton = 0
 			} else if !d.textArea.Focused() {
@@ -311,6 +378,11 @@ func (d *Dialog) View() string {
 		sections = append(sections, textAreaSection)
 	}
 
+	// Dropdown selector
+	if d.selectActive() {
+		sections = append(sections, d.renderSelect(t))
+	}
+
 	// Input field (single-line)
 	if d.hasInput && d.input != nil {
 		labelStyle := lipgloss.NewStyle().
@@ -374,6 +446,37 @@ func (d *Dialog) View() string {
 	return dialogStyle.Render(content)
 }
 
+// renderSelect renders the dropdown, expanded while it has focus.
+func (d *Dialog) renderSelect(t theme.Theme) string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(t.TextMuted)
+	wrapper := lipgloss.NewStyle().
+		Padding(1, 2, 0, 2)
+
+	if !d.selectFocused {
+		value := lipgloss.NewStyle().
+			Foreground(t.Text).
+			Render("▾ " + d.SelectedOption())
+		return wrapper.Render(labelStyle.Render(d.selectLabel) + " " + value)
+	}
+
+	lines := []string{labelStyle.Render(d.selectLabel)}
+	for i, opt := range d.selectOptions {
+		if i == d.selectIndex {
+			lines = append(lines, lipgloss.NewStyle().
+				Background(t.Primary).
+				Foreground(t.Background).
+				Bold(true).
+				Render("▸ "+opt))
+		} else {
+			lines = append(lines, lipgloss.NewStyle().
+				Foreground(t.Text).
+				Render("  "+opt))
+		}
+	}
+	return wrapper.Render(strings.Join(lines, "\n"))
+}
+
 // CenterIn returns the dialog centered in the given dimensions.
 func (d *Dialog) CenterIn(screenWidth, screenHeight int) string {
 	dialog := d.View()
@@ -408,6 +511,7 @@ func NewJobDialog() *Dialog {
 	d := NewDialog("New Job")
 	d.SetSize(86, 16)
 	d.SetTextArea("Job Description (Ctrl+Enter to submit):")
+	d.SetSelect("Preset (Tab to choose):", []string{"none"})
 	d.AddButton("Create", "create", true)
 	d.AddButton("Cancel", "cancel", false)
 	return d
@@ -6,6 +6,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 
+	"cosa/internal/display"
 	"cosa/internal/tui/theme"
 )
 
@@ -429,8 +430,8 @@ func (ts *TemplateSelector) renderTemplateLine(tmpl TemplateItem, selected bool)
 	// Truncate description if needed
 	available := ts.width - lipgloss.Width(indicator) - lipgloss.Width(name) - lipgloss.Width(typeBadge) - 8
 	desc := tmpl.Description
-	if len(desc) > available && available > 3 {
-		desc = desc[:available-3] + "..."
+	if available > 3 {
+		desc = display.Truncate(desc, available)
 	}
 	descView := descStyle.Render(" - " + desc)
 
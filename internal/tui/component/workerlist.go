@@ -6,15 +6,15 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/charmbracelet/lipgloss"
-
+	"cosa/internal/display"
+	"cosa/internal/ledger"
 	"cosa/internal/protocol"
 	"cosa/internal/tui/styles"
 )
 
-// WorkerList displays a list of workers.
+// WorkerList displays a list of workers and what the busy ones are doing.
 type WorkerList struct {
-	workers  []protocol.WorkerInfo
+	workers  []protocol.WorkerOverview
 	selected int
 	focused  bool
 	width    int
@@ -29,8 +29,13 @@ func NewWorkerList() *WorkerList {
 	}
 }
 
+// Restyle rebuilds the list's styles from the current theme.
+func (w *WorkerList) Restyle() {
+	w.styles = styles.New()
+}
+
 // SetWorkers updates the worker list, sorted alphabetically by name.
-func (w *WorkerList) SetWorkers(workers []protocol.WorkerInfo) {
+func (w *WorkerList) SetWorkers(workers []protocol.WorkerOverview) {
 	// Sort workers alphabetically by name
 	sort.Slice(workers, func(i, j int) bool {
 		return strings.ToLower(workers[i].Name) < strings.ToLower(workers[j].Name)
@@ -69,7 +74,7 @@ func (w *WorkerList) MoveDown() {
 // Selected returns the currently selected worker.
 func (w *WorkerList) Selected() *protocol.WorkerInfo {
 	if w.selected >= 0 && w.selected < len(w.workers) {
-		return &w.workers[w.selected]
+		return &w.workers[w.selected].WorkerInfo
 	}
 	return nil
 }
@@ -93,16 +98,24 @@ func (w *WorkerList) View() string {
 	}
 	end := min(start+contentHeight, len(w.workers))
 
+	// Busy workers take more than one line; stop before overflowing, but
+	// always show the selected worker
+	used := 0
 	for i := start; i < end; i++ {
 		worker := w.workers[i]
 		line := w.renderWorkerLine(worker, i == w.selected)
+		height := strings.Count(line, "\n") + 1
+		if used > 0 && used+height > contentHeight && i > w.selected {
+			break
+		}
 		lines = append(lines, line)
+		used += height
 	}
 
 	return strings.Join(lines, "\n")
 }
 
-func (w *WorkerList) renderWorkerLine(worker protocol.WorkerInfo, selected bool) string {
+func (w *WorkerList) renderWorkerLine(worker protocol.WorkerOverview, selected bool) string {
 	// Status indicator
 	var statusIcon string
 	switch worker.Status {
@@ -120,35 +133,50 @@ func (w *WorkerList) renderWorkerLine(worker protocol.WorkerInfo, selected bool)
 
 	// Role badge
 	roleStyle := w.styles.RoleStyle(worker.Role)
-	roleAbbrev := worker.Role
-	if len(roleAbbrev) > 3 {
-		roleAbbrev = roleAbbrev[:3]
-	}
-	role := roleStyle.Render(fmt.Sprintf("[%s]", roleAbbrev))
+	role := roleStyle.Render(fmt.Sprintf("[%s]", display.Cut(worker.Role, 3)))
 
 	// Status style
 	statusStyle := w.styles.StatusStyle(worker.Status)
 	status := statusStyle.Render(statusIcon)
 
 	// Name
-	name := worker.Name
-	if len(name) > 12 {
-		name = name[:12]
-	}
+	name := display.PadRight(display.Cut(worker.Name, 12), 12)
 
 	// Build main line
-	content := fmt.Sprintf("%s %s %-12s", status, role, name)
+	content := fmt.Sprintf("%s %s %s", status, role, name)
 
-	// Add current job description if working
+	// Add current job description if working, with how long it has run and
+	// what it has cost
 	if worker.CurrentJobDesc != "" {
-		// Truncate job description to fit
-		maxJobLen := w.width - 8 // Leave room for indent and padding
-		jobDesc := worker.CurrentJobDesc
-		if len(jobDesc) > maxJobLen {
-			jobDesc = jobDesc[:maxJobLen-2] + ".."
+		var meta []string
+		if worker.Elapsed > 0 {
+			meta = append(meta, formatElapsed(worker.Elapsed))
+		}
+		if worker.Cost != "" {
+			meta = append(meta, worker.Cost)
 		}
-		jobLine := w.styles.TextMuted.Render("  └─ " + jobDesc)
+		suffix := ""
+		if len(meta) > 0 {
+			suffix = " · " + strings.Join(meta, " · ")
+		}
+
+		// Truncate job description to fit
+		maxJobLen := w.width - 8 - display.Width(suffix) // Leave room for indent and padding
+		jobDesc := display.Truncate(worker.CurrentJobDesc, max(maxJobLen, 8))
+		jobLine := w.styles.TextMuted.Render("  └─ " + jobDesc + suffix)
 		content = content + "\n" + jobLine
+
+		// Then the tool in use, or else Claude's latest line
+		var doing string
+		switch {
+		case worker.Tool != "":
+			doing = fmt.Sprintf("⚙ %s %s", worker.Tool, formatElapsed(worker.ToolFor))
+		case worker.Progress != "":
+			doing = "› " + worker.Progress
+		}
+		if doing != "" {
+			content += "\n" + w.styles.TextMuted.Render("     "+display.Truncate(doing, max(w.width-9, 8)))
+		}
 	}
 
 	// Apply selection style
@@ -161,6 +189,18 @@ func (w *WorkerList) renderWorkerLine(worker protocol.WorkerInfo, selected bool)
 	return w.styles.ListItem.Width(lineWidth).Render(content)
 }
 
+// formatElapsed renders a number of seconds as e.g. 45s, 3m12s or 1h5m.
+func formatElapsed(seconds int64) string {
+	switch {
+	case seconds >= 3600:
+		return fmt.Sprintf("%dh%dm", seconds/3600, seconds%3600/60)
+	case seconds >= 60:
+		return fmt.Sprintf("%dm%ds", seconds/60, seconds%60)
+	default:
+		return fmt.Sprintf("%ds", seconds)
+	}
+}
+
 // JobList displays a list of jobs.
 type JobList struct {
 	jobs     []protocol.JobInfo
@@ -178,6 +218,11 @@ func NewJobList() *JobList {
 	}
 }
 
+// Restyle rebuilds the list's styles from the current theme.
+func (j *JobList) Restyle() {
+	j.styles = styles.New()
+}
+
 // jobStatusOrder defines the sort order for job statuses.
 // Active/in-progress statuses come first, then pending, then terminal states.
 var jobStatusOrder = map[string]int{
@@ -189,10 +234,15 @@ var jobStatusOrder = map[string]int{
 	"cancelled": 5,
 }
 
-// SetJobs updates the job list, sorted by status, then priority, then description.
+// SetJobs updates the job list, with watched jobs first, then sorted by
+// status, then priority, then description.
 func (j *JobList) SetJobs(jobs []protocol.JobInfo) {
 	// Sort jobs: 1) by status order, 2) by priority (lower = higher), 3) alphabetically by description
 	sort.Slice(jobs, func(i, k int) bool {
+		// Watched jobs get their own section at the top
+		if jobs[i].Watched != jobs[k].Watched {
+			return jobs[i].Watched
+		}
 		// Compare by status first
 		statusI := jobStatusOrder[jobs[i].Status]
 		statusK := jobStatusOrder[jobs[k].Status]
@@ -245,6 +295,11 @@ func (j *JobList) Selected() *protocol.JobInfo {
 	return nil
 }
 
+// Jobs returns the listed jobs.
+func (j *JobList) Jobs() []protocol.JobInfo {
+	return j.jobs
+}
+
 // CanReassignSelected returns true if the selected job can be reassigned (is failed or cancelled).
 func (j *JobList) CanReassignSelected() bool {
 	selected := j.Selected()
@@ -254,12 +309,34 @@ func (j *JobList) CanReassignSelected() bool {
 	return selected.Status == "failed" || selected.Status == "cancelled"
 }
 
-// View renders the job list.
+// View renders the job list. Watched jobs are shown under their own
+// heading above the rest.
 func (j *JobList) View() string {
 	if len(j.jobs) == 0 {
 		return j.styles.TextMuted.Render("No jobs")
 	}
 
+	watched := 0
+	for watched < len(j.jobs) && j.jobs[watched].Watched {
+		watched++
+	}
+
+	// Rows are job indexes, or -1 and -2 for the section headings
+	var rows []int
+	selectedRow := 0
+	for i := range j.jobs {
+		if watched > 0 && i == 0 {
+			rows = append(rows, -1)
+		}
+		if watched > 0 && i == watched {
+			rows = append(rows, -2)
+		}
+		if i == j.selected {
+			selectedRow = len(rows)
+		}
+		rows = append(rows, i)
+	}
+
 	var lines []string
 	contentHeight := j.height
 	if contentHeight < 1 {
@@ -267,15 +344,20 @@ func (j *JobList) View() string {
 	}
 
 	start := 0
-	if j.selected >= contentHeight {
-		start = j.selected - contentHeight + 1
-	}
-	end := min(start+contentHeight, len(j.jobs))
-
-	for i := start; i < end; i++ {
-		job := j.jobs[i]
-		line := j.renderJobLine(job, i == j.selected)
-		lines = append(lines, line)
+	if selectedRow >= contentHeight {
+		start = selectedRow - contentHeight + 1
+	}
+	end := min(start+contentHeight, len(rows))
+
+	for _, row := range rows[start:end] {
+		switch row {
+		case -1:
+			lines = append(lines, j.styles.TextMuted.Render("★ Watched"))
+		case -2:
+			lines = append(lines, j.styles.TextMuted.Render("All jobs"))
+		default:
+			lines = append(lines, j.renderJobLine(j.jobs[row], row == j.selected))
+		}
 	}
 
 	return strings.Join(lines, "\n")
@@ -308,11 +390,7 @@ func (j *JobList) renderJobLine(job protocol.JobInfo, selected bool) string {
 	priority := fmt.Sprintf("P%d", job.Priority)
 
 	// Description
-	desc := job.Description
-	maxLen := j.width - 12
-	if len(desc) > maxLen {
-		desc = desc[:maxLen-2] + ".."
-	}
+	desc := display.Truncate(job.Description, j.width-12)
 
 	content := fmt.Sprintf("%s %s %s", status, priority, desc)
 
@@ -325,19 +403,25 @@ func (j *JobList) renderJobLine(job protocol.JobInfo, selected bool) string {
 	return j.styles.ListItem.Width(lineWidth).Render(content)
 }
 
+// maxActivityItems bounds the activity feed, including paged-in history.
+const maxActivityItems = 1000
+
 // Activity displays activity feed.
 type Activity struct {
-	items  []ActivityItem
-	width  int
-	height int
-	styles styles.Styles
+	items      []ActivityItem
+	scroll     int  // Lines scrolled back from the newest item
+	errorsOnly bool // Show only error items
+	width      int
+	height     int
+	styles     styles.Styles
 }
 
 // ActivityItem represents a single activity entry.
 type ActivityItem struct {
-	Time    string
-	Worker  string
-	Message string
+	Time     string
+	Worker   string
+	Message  string
+	Severity ledger.Severity
 }
 
 // NewActivity creates a new activity component.
@@ -347,13 +431,97 @@ func NewActivity() *Activity {
 	}
 }
 
+// Restyle rebuilds the feed's styles from the current theme.
+func (a *Activity) Restyle() {
+	a.styles = styles.New()
+}
+
 // AddItem adds an activity item.
 func (a *Activity) AddItem(item ActivityItem) {
 	a.items = append(a.items, item)
-	// Keep last 100 items
-	if len(a.items) > 100 {
+	if a.scroll > 0 && a.shown(item) {
+		a.scroll++ // Keep the scrolled-back view in place
+	}
+	if len(a.items) > maxActivityItems {
 		a.items = a.items[1:]
+		a.scroll = min(a.scroll, a.maxScroll())
+	}
+}
+
+// PrependItems adds older items, oldest first, before the current items.
+func (a *Activity) PrependItems(items []ActivityItem) {
+	room := maxActivityItems - len(a.items)
+	if room <= 0 {
+		return
 	}
+	if len(items) > room {
+		items = items[len(items)-room:]
+	}
+	a.items = append(append([]ActivityItem{}, items...), a.items...)
+}
+
+// ToggleErrorsOnly switches between showing every item and only errors,
+// returning to the newest item.
+func (a *Activity) ToggleErrorsOnly() {
+	a.errorsOnly = !a.errorsOnly
+	a.scroll = 0
+}
+
+// ErrorsOnly reports whether only error items are shown.
+func (a *Activity) ErrorsOnly() bool {
+	return a.errorsOnly
+}
+
+// shown reports whether item passes the feed's filter.
+func (a *Activity) shown(item ActivityItem) bool {
+	return !a.errorsOnly || item.Severity >= ledger.SeverityError
+}
+
+// visible returns the items that pass the feed's filter.
+func (a *Activity) visible() []ActivityItem {
+	if !a.errorsOnly {
+		return a.items
+	}
+	var items []ActivityItem
+	for _, item := range a.items {
+		if a.shown(item) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ScrollUp scrolls back towards older items.
+func (a *Activity) ScrollUp() {
+	if a.scroll < a.maxScroll() {
+		a.scroll++
+	}
+}
+
+// ScrollDown scrolls forward towards the newest item.
+func (a *Activity) ScrollDown() {
+	if a.scroll > 0 {
+		a.scroll--
+	}
+}
+
+// ScrollToBottom returns to the newest items.
+func (a *Activity) ScrollToBottom() {
+	a.scroll = 0
+}
+
+// AtTop reports whether the oldest loaded item is visible.
+func (a *Activity) AtTop() bool {
+	return a.scroll >= a.maxScroll()
+}
+
+// Scrolled reports whether the feed is scrolled back from the newest item.
+func (a *Activity) Scrolled() bool {
+	return a.scroll > 0
+}
+
+func (a *Activity) maxScroll() int {
+	return max(0, len(a.visible())-max(1, a.height))
 }
 
 // SetSize sets the component dimensions.
@@ -364,7 +532,11 @@ func (a *Activity) SetSize(width, height int) {
 
 // View renders the activity feed.
 func (a *Activity) View() string {
-	if len(a.items) == 0 {
+	items := a.visible()
+	if len(items) == 0 {
+		if a.errorsOnly && len(a.items) > 0 {
+			return a.styles.TextMuted.Render("No errors (press e to show all activity)")
+		}
 		return a.styles.TextMuted.Render("No activity")
 	}
 
@@ -374,10 +546,11 @@ func (a *Activity) View() string {
 		contentHeight = 1
 	}
 
-	// Show most recent items (scroll to bottom)
-	start := max(0, len(a.items)-contentHeight)
-	for i := start; i < len(a.items); i++ {
-		item := a.items[i]
+	// Show the most recent items, offset by how far we scrolled back
+	end := len(items) - min(a.scroll, a.maxScroll())
+	start := max(0, end-contentHeight)
+	for i := start; i < end; i++ {
+		item := items[i]
 		line := a.renderActivityLine(item)
 		lines = append(lines, line)
 	}
@@ -391,20 +564,19 @@ func (a *Activity) renderActivityLine(item ActivityItem) string {
 	if item.Worker != "" {
 		worker = a.styles.ActivityWorker.Render(fmt.Sprintf("[%s] ", item.Worker))
 	}
-	message := a.styles.ActivityMessage.Render(item.Message)
+	// One line per item: a multi-line message would break up the feed
+	style := a.styles.ActivityMessage
+	switch item.Severity {
+	case ledger.SeverityWarn:
+		style = a.styles.ActivityWarn
+	case ledger.SeverityError:
+		style = a.styles.ActivityError
+	}
+	message := style.Render(strings.Join(strings.Fields(item.Message), " "))
 
 	line := fmt.Sprintf("%s %s%s", time, worker, message)
 
-	// Truncate if too long
-	if lipgloss.Width(line) > a.width-2 {
-		// Simple truncation
-		maxLen := a.width - 5
-		if len(line) > maxLen {
-			line = line[:maxLen] + "..."
-		}
-	}
-
-	return line
+	return display.Truncate(line, a.width-2)
 }
 
 func max(a, b int) int {
@@ -0,0 +1,217 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cosa/internal/display"
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+	"cosa/internal/tui/page"
+)
+
+// Ledger events for merge conflicts
+const (
+	eventMergeConflict    ledger.EventType = "job.merge_conflict"
+	eventConflictResolved ledger.EventType = "job.conflict_resolved"
+)
+
+// activityEvents are the ledger events streamed into the activity feed.
+var activityEvents = []string{
+	string(ledger.EventWorkerAdded),
+	string(ledger.EventWorkerStarted),
+	string(ledger.EventWorkerRemediated),
+	string(ledger.EventJobCreated),
+	string(ledger.EventJobQueued),
+	string(ledger.EventJobStarted),
+	string(ledger.EventJobCompleted),
+	string(ledger.EventJobFailed),
+	string(ledger.EventJobCancelled),
+	string(ledger.EventGateFailed),
+	string(ledger.EventReviewFailed),
+	string(ledger.EventMergeFailed),
+	string(ledger.EventBudgetExceeded),
+	string(eventMergeConflict),
+	string(eventConflictResolved),
+}
+
+// waitForEvent blocks until the daemon streams the next ledger event.
+func (a *App) waitForEvent() tea.Msg {
+	if a.client == nil {
+		return nil
+	}
+
+	event, err := a.client.ReadEvent()
+	if err != nil {
+		return disconnectedMsg{}
+	}
+	return eventMsg(ledger.Event{
+		ID:        event.ID,
+		Type:      ledger.EventType(event.Type),
+		Timestamp: event.Timestamp,
+		Data:      event.Data,
+	})
+}
+
+// openConflicts switches to the conflict triage page, selecting jobID if set.
+func (a *App) openConflicts(jobID string) (tea.Model, tea.Cmd) {
+	a.activePage = "conflicts"
+	a.conflicts = page.NewConflicts()
+	a.conflicts.SetSize(a.width, a.height)
+
+	conflicts, _ := a.fetchConflicts().(conflictsMsg)
+	a.conflicts.SetConflicts(conflicts)
+	if jobID != "" {
+		a.conflicts.Select(jobID)
+	}
+
+	return a, a.fetchConflictDetail
+}
+
+func (a *App) fetchConflicts() tea.Msg {
+	if a.client == nil {
+		return nil
+	}
+
+	resp, err := a.client.Call(protocol.MethodJobConflicts, nil)
+	if err != nil {
+		return errMsg(err)
+	}
+
+	if resp.Error != nil {
+		return nil
+	}
+
+	var conflicts []protocol.ConflictInfo
+	json.Unmarshal(resp.Result, &conflicts)
+	return conflictsMsg(conflicts)
+}
+
+func (a *App) fetchConflictDetail() tea.Msg {
+	jobID := a.conflicts.SelectedJob()
+	if a.client == nil || jobID == "" {
+		return nil
+	}
+
+	resp, err := a.client.Call(protocol.MethodConflictDetail, protocol.ConflictParams{ID: jobID})
+	if err != nil {
+		return errMsg(err)
+	}
+
+	if resp.Error != nil {
+		return nil
+	}
+
+	var detail protocol.ConflictDetail
+	json.Unmarshal(resp.Result, &detail)
+	return conflictDetailMsg(&detail)
+}
+
+// conflictAction calls a conflict triage RPC, reporting failures on the
+// triage page. It returns false if the call failed.
+func (a *App) conflictAction(method string, params, result interface{}) bool {
+	if a.client == nil {
+		a.conflicts.SetMessage("Error: No connection to daemon")
+		return false
+	}
+
+	resp, err := a.client.Call(method, params)
+	if err != nil {
+		a.conflicts.SetMessage(fmt.Sprintf("Error: %v", err))
+		return false
+	}
+
+	if resp.Error != nil {
+		a.conflicts.SetMessage(fmt.Sprintf("Error: %s", resp.Error.Message))
+		return false
+	}
+
+	if result != nil {
+		json.Unmarshal(resp.Result, result)
+	}
+	return true
+}
+
+func (a *App) assignConflictResolver(jobID string) {
+	if jobID == "" {
+		return
+	}
+
+	var result protocol.ConflictAssignResult
+	if !a.conflictAction(protocol.MethodConflictAssign, protocol.ConflictAssignParams{ID: jobID}, &result) {
+		return
+	}
+
+	message := fmt.Sprintf("Resolver job %s created", display.ShortID(result.ResolverJob))
+	if result.Worker != "" {
+		message += " for " + result.Worker
+	}
+	a.conflicts.SetMessage(message)
+	a.dashboard.AddActivity(time.Now().Format("15:04:05"), result.Worker, message)
+}
+
+// editConflict starts the merge in a workspace and opens the conflicted
+// files in $EDITOR, suspending the TUI until it exits.
+func (a *App) editConflict(jobID string) tea.Cmd {
+	if jobID == "" {
+		return nil
+	}
+
+	var result protocol.ConflictWorkspaceResult
+	if !a.conflictAction(protocol.MethodConflictWorkspace, protocol.ConflictParams{ID: jobID}, &result) {
+		return nil
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// The editor may carry its own arguments, e.g. "code --wait"
+	args := strings.Fields(editor)
+	args = append(args, result.Files...)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = result.Path
+
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return editorFinishedMsg{jobID: jobID, err: err}
+	})
+}
+
+func (a *App) retryMerge(jobID string) {
+	if jobID == "" {
+		return
+	}
+
+	var result protocol.JobMergeResult
+	if !a.conflictAction(protocol.MethodJobMerge, protocol.JobMergeParams{ID: jobID, Retry: true}, &result) {
+		return
+	}
+
+	message := fmt.Sprintf("Job %s merged into %s", display.ShortID(jobID), result.Target)
+	a.conflicts.SetMessage(message)
+	a.dashboard.AddActivity(time.Now().Format("15:04:05"), "", message)
+}
+
+func (a *App) abandonMerge(jobID string) {
+	if jobID == "" {
+		return
+	}
+
+	if !a.conflictAction(protocol.MethodConflictAbandon, protocol.ConflictParams{ID: jobID}, nil) {
+		return
+	}
+
+	message := fmt.Sprintf("Merge of job %s abandoned; job needs attention", display.ShortID(jobID))
+	a.conflicts.SetMessage(message)
+	a.dashboard.AddActivity(time.Now().Format("15:04:05"), "", message)
+}
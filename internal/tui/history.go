@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"encoding/json"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cosa/internal/ledger"
+	"cosa/internal/protocol"
+	"cosa/internal/tui/component"
+)
+
+// activityPageSize is how many events are loaded per history page.
+const activityPageSize = 50
+
+type activityHistoryMsg struct {
+	before string // Cursor the page was requested from
+	events []protocol.LedgerEvent
+	more   bool
+	err    error
+}
+
+// fetchActivityHistory loads the page of activity events older than the
+// event with ID before, or the most recent page if before is empty.
+func (a *App) fetchActivityHistory(before string) tea.Cmd {
+	return func() tea.Msg {
+		if a.client == nil {
+			return activityHistoryMsg{before: before}
+		}
+
+		resp, err := a.client.Call(protocol.MethodLedgerQuery, protocol.LedgerQueryParams{
+			Types:  activityEvents,
+			Before: before,
+			Limit:  activityPageSize,
+		})
+		if err != nil {
+			return activityHistoryMsg{before: before, err: err}
+		}
+
+		if resp.Error != nil {
+			return activityHistoryMsg{before: before}
+		}
+
+		var result protocol.LedgerQueryResult
+		json.Unmarshal(resp.Result, &result)
+		return activityHistoryMsg{before: before, events: result.Events, more: result.More}
+	}
+}
+
+// loadOlderActivity requests the next page of history, unless one is
+// already loading or the ledger has nothing older.
+func (a *App) loadOlderActivity() tea.Cmd {
+	if a.activityLoading || !a.activityMore || a.activityBefore == "" {
+		return nil
+	}
+	a.activityLoading = true
+	return a.fetchActivityHistory(a.activityBefore)
+}
+
+// addActivityHistory prepends a page of history to the activity feed.
+func (a *App) addActivityHistory(msg activityHistoryMsg) {
+	a.activityLoading = false
+	if msg.err != nil || msg.before != a.activityBefore {
+		return // Failed or stale page
+	}
+
+	a.activityMore = msg.more
+	if len(msg.events) == 0 {
+		return
+	}
+	a.activityBefore = msg.events[0].ID
+
+	items := make([]component.ActivityItem, 0, len(msg.events))
+	for _, e := range msg.events {
+		worker, message := describeEvent(ledger.Event{
+			ID:        e.ID,
+			Type:      ledger.EventType(e.Type),
+			Timestamp: e.Timestamp,
+			Data:      e.Data,
+		})
+		items = append(items, component.ActivityItem{
+			Time:     historyTime(e.Timestamp),
+			Worker:   worker,
+			Message:  message,
+			Severity: ledger.EventType(e.Type).Severity(),
+		})
+	}
+	a.dashboard.PrependActivity(items)
+}
+
+// historyTime formats an event time for the activity feed, including the
+// date for events before today.
+func historyTime(t time.Time) string {
+	t = t.Local()
+	now := time.Now()
+	if t.Year() == now.Year() && t.YearDay() == now.YearDay() {
+		return t.Format("15:04:05")
+	}
+	return t.Format("Jan 02 15:04")
+}
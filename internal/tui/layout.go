@@ -0,0 +1,141 @@
+package tui
+
+import (
+	"encoding/json"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cosa/internal/protocol"
+	"cosa/internal/tui/page"
+)
+
+type reviewsMsg []protocol.ReviewStatusResult
+
+type diffMsg struct {
+	jobID  string
+	result *protocol.JobDiffResult
+	err    string
+}
+
+// cycleLayout switches the dashboard to the next layout and remembers it.
+func (a *App) cycleLayout() tea.Cmd {
+	layout := a.dashboard.NextLayout()
+	a.diffJob = ""
+	if a.onLayoutChange != nil {
+		if err := a.onLayoutChange(string(layout)); err != nil {
+			a.dashboard.AddActivity(time.Now().Format("15:04:05"), "", "Failed to save layout: "+err.Error())
+		}
+	}
+	if layout == page.LayoutReviewer {
+		return a.fetchReviews
+	}
+	return nil
+}
+
+func (a *App) fetchReviews() tea.Msg {
+	if a.client == nil {
+		return nil
+	}
+
+	resp, err := a.client.Call(protocol.MethodReviewList, nil)
+	if err != nil {
+		return errMsg(err)
+	}
+
+	if resp.Error != nil {
+		return nil
+	}
+
+	var result protocol.ReviewListResult
+	json.Unmarshal(resp.Result, &result)
+	return reviewsMsg(result.Reviews)
+}
+
+// loadSelectedDiff requests the diff of the job selected in the reviewer
+// layout when the selection has moved to another job.
+func (a *App) loadSelectedDiff() tea.Cmd {
+	if a.dashboard.Layout() != page.LayoutReviewer {
+		return nil
+	}
+
+	jobID := a.dashboard.SelectedReviewJob()
+	if jobID == a.diffJob {
+		return nil
+	}
+	a.diffJob = jobID
+	if jobID == "" {
+		a.dashboard.SetDiff(nil, "")
+		return nil
+	}
+
+	a.dashboard.SetDiff(nil, "Loading diff...")
+	return a.fetchDiff(jobID)
+}
+
+func (a *App) fetchDiff(jobID string) tea.Cmd {
+	return func() tea.Msg {
+		if a.client == nil {
+			return diffMsg{jobID: jobID, err: "no connection to daemon"}
+		}
+
+		resp, err := a.client.Call(protocol.MethodJobDiff, protocol.JobDiffParams{ID: jobID})
+		if err != nil {
+			return diffMsg{jobID: jobID, err: err.Error()}
+		}
+
+		if resp.Error != nil {
+			return diffMsg{jobID: jobID, err: resp.Error.Message}
+		}
+
+		var result protocol.JobDiffResult
+		json.Unmarshal(resp.Result, &result)
+		return diffMsg{jobID: jobID, result: &result}
+	}
+}
+
+// applyDiff shows a loaded diff if its job is still selected.
+func (a *App) applyDiff(msg diffMsg) {
+	if msg.jobID != a.dashboard.SelectedReviewJob() {
+		return
+	}
+	if msg.err != "" {
+		a.dashboard.SetDiff(nil, "No diff: "+msg.err)
+		return
+	}
+	a.dashboard.SetDiff(msg.result, "")
+}
+
+// handleReviewerKey handles the reviewer layout's own keys, reporting
+// whether the key was one of them.
+func (a *App) handleReviewerKey(key string) (bool, tea.Cmd) {
+	jobID := a.dashboard.SelectedReviewJob()
+
+	switch key {
+	case "s":
+		if jobID != "" {
+			a.startReview(jobID)
+		}
+		return true, a.fetchReviews
+
+	case "d":
+		// Reload the diff, e.g. after the worker committed more changes
+		a.diffJob = ""
+		return true, a.loadSelectedDiff()
+
+	case "enter":
+		for _, j := range a.jobs {
+			if j.ID == jobID && j.Unmerged {
+				_, cmd := a.openConflicts(jobID)
+				return true, cmd
+			}
+		}
+		if jobID != "" {
+			_, cmd := a.openReview(jobID)
+			return true, cmd
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
@@ -0,0 +1,270 @@
+// Package markdown renders the markdown Claude replies in as styled,
+// wrapped terminal lines: headings, bold, lists, quotes, inline code and
+// fenced code blocks. Anything else is shown as written.
+package markdown
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"cosa/internal/display"
+	"cosa/internal/tui/theme"
+)
+
+var (
+	headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	listPattern    = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	rulePattern    = regexp.MustCompile(`^(\*{3,}|-{3,}|_{3,})$`)
+)
+
+// span is a run of text in one style.
+type span struct {
+	text  string
+	style lipgloss.Style
+}
+
+// word is text wrapping keeps together. It is made of several spans when
+// its style changes partway, as in **bold**text.
+type word struct {
+	spans []span
+	width int
+}
+
+// renderer holds the styles for the current theme. Styles whose colors the
+// theme leaves unset fall back to plain text.
+type renderer struct {
+	base    lipgloss.Style
+	bold    lipgloss.Style
+	heading lipgloss.Style
+	muted   lipgloss.Style
+
+	code      lipgloss.Style // Inline code
+	plainCode bool           // No colors for code: inline code keeps its backticks
+	block     lipgloss.Style // Fenced code block lines
+	blockFill bool           // Code blocks have a background to fill the width with
+}
+
+func newRenderer(t theme.Theme, base lipgloss.Style) *renderer {
+	r := &renderer{
+		base:    base,
+		bold:    base.Bold(true),
+		heading: base.Bold(true),
+		muted:   base,
+		code:    base,
+		block:   base,
+	}
+	if t.Primary != "" {
+		r.heading = r.heading.Foreground(t.Primary)
+	}
+	if t.TextMuted != "" {
+		r.muted = base.Foreground(t.TextMuted)
+	}
+
+	switch {
+	case t.Accent != "" && t.SurfaceLight != "":
+		r.code = base.Foreground(t.Accent).Background(t.SurfaceLight)
+		r.block = base.Foreground(t.Text).Background(t.SurfaceLight)
+		r.blockFill = true
+	case t.Accent != "":
+		r.code = base.Foreground(t.Accent)
+		r.block = base.Foreground(t.Accent)
+	default:
+		r.plainCode = true
+	}
+	return r
+}
+
+// Render renders text as lines of at most width cells, with base as the
+// style of plain text.
+func Render(text string, width int, base lipgloss.Style) []string {
+	if width < 10 {
+		width = 10
+	}
+	r := newRenderer(theme.Current, base)
+
+	var lines, code []string
+	fence := ""
+	for _, raw := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(raw)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				lines = append(lines, r.codeBlock(code, width)...)
+				code, fence = nil, ""
+			} else {
+				code = append(code, raw)
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		lines = append(lines, r.line(raw, width)...)
+	}
+
+	// A reply still being written may not have closed its block yet
+	if fence != "" {
+		lines = append(lines, r.codeBlock(code, width)...)
+	}
+	return lines
+}
+
+// line renders one line of markdown outside a code block.
+func (r *renderer) line(raw string, width int) []string {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return []string{""}
+	}
+
+	if m := headingPattern.FindStringSubmatch(trimmed); m != nil {
+		return r.wrap(r.inline(m[2], r.heading), width, "", "")
+	}
+	if rulePattern.MatchString(trimmed) {
+		return []string{r.muted.Render(strings.Repeat("─", width))}
+	}
+	if rest, ok := strings.CutPrefix(trimmed, ">"); ok {
+		prefix := r.muted.Render("│ ")
+		return r.wrap(r.inline(strings.TrimSpace(rest), r.muted), width, prefix, prefix)
+	}
+	if m := listPattern.FindStringSubmatch(raw); m != nil {
+		indent := strings.Repeat(" ", min(len(strings.ReplaceAll(m[1], "\t", "  "))/2*2, 8))
+		marker := m[2]
+		if strings.ContainsAny(marker, "-*+") {
+			marker = "•"
+		}
+		first := indent + r.base.Render(marker) + " "
+		rest := indent + strings.Repeat(" ", display.Width(marker)+1)
+		return r.wrap(r.inline(m[3], r.base), width, first, rest)
+	}
+	return r.wrap(r.inline(trimmed, r.base), width, "", "")
+}
+
+// inline splits text into spans for its **bold** and `code`, in style
+// otherwise. Markers without a partner are left as written.
+func (r *renderer) inline(text string, style lipgloss.Style) []span {
+	var spans []span
+	var buf strings.Builder
+	bold := false
+	flush := func() {
+		if buf.Len() == 0 {
+			return
+		}
+		s := style
+		if bold {
+			s = s.Bold(true)
+		}
+		spans = append(spans, span{text: buf.String(), style: s})
+		buf.Reset()
+	}
+
+	for i := 0; i < len(text); {
+		if text[i] == '`' {
+			if end := strings.IndexByte(text[i+1:], '`'); end > 0 {
+				flush()
+				code := text[i+1 : i+1+end]
+				if r.plainCode {
+					code = "`" + code + "`"
+				}
+				spans = append(spans, span{text: code, style: r.code})
+				i += end + 2
+				continue
+			}
+		}
+		if strings.HasPrefix(text[i:], "**") && (bold || strings.Contains(text[i+2:], "**")) {
+			flush()
+			bold = !bold
+			i += 2
+			continue
+		}
+		buf.WriteByte(text[i])
+		i++
+	}
+	flush()
+	return spans
+}
+
+// wrap word-wraps spans to width, starting the first line with first and
+// the others with rest.
+func (r *renderer) wrap(spans []span, width int, first, rest string) []string {
+	var words []word
+	var cur word
+	for _, s := range spans {
+		for i, part := range strings.Split(s.text, " ") {
+			if i > 0 && len(cur.spans) > 0 {
+				words = append(words, cur)
+				cur = word{}
+			}
+			if part != "" {
+				cur.spans = append(cur.spans, span{text: part, style: s.style})
+				cur.width += display.Width(part)
+			}
+		}
+	}
+	if len(cur.spans) > 0 {
+		words = append(words, cur)
+	}
+
+	var lines []string
+	prefix := first
+	var line []string
+	lineWidth := 0
+	avail := func() int { return max(width-display.Width(prefix), 1) }
+	emit := func() {
+		lines = append(lines, prefix+strings.Join(line, " "))
+		prefix, line, lineWidth = rest, nil, 0
+	}
+
+	for _, w := range words {
+		if len(line) > 0 && lineWidth+1+w.width > avail() {
+			emit()
+		}
+		rendered := w.render()
+		if w.width > avail() {
+			// Too long for any line: break it up
+			parts := strings.Split(ansi.Hardwrap(rendered, avail(), true), "\n")
+			for _, p := range parts[:len(parts)-1] {
+				line = append(line, p)
+				emit()
+			}
+			rendered = parts[len(parts)-1]
+			w.width = display.Width(rendered)
+		}
+		if len(line) > 0 {
+			lineWidth++
+		}
+		line = append(line, rendered)
+		lineWidth += w.width
+	}
+	if len(line) > 0 || len(lines) == 0 {
+		emit()
+	}
+	return lines
+}
+
+func (w word) render() string {
+	var sb strings.Builder
+	for _, s := range w.spans {
+		sb.WriteString(s.style.Render(s.text))
+	}
+	return sb.String()
+}
+
+// codeBlock renders the lines of a fenced code block as written, broken at
+// width rather than wrapped at words.
+func (r *renderer) codeBlock(code []string, width int) []string {
+	var lines []string
+	for _, raw := range code {
+		raw = strings.ReplaceAll(raw, "\t", "    ")
+		for _, l := range strings.Split(ansi.Hardwrap(raw, width-2, false), "\n") {
+			if r.blockFill {
+				lines = append(lines, r.block.Render(display.PadRight(" "+l, width)))
+			} else {
+				lines = append(lines, "  "+r.block.Render(l))
+			}
+		}
+	}
+	return lines
+}
@@ -0,0 +1,192 @@
+package markdown
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"cosa/internal/display"
+	"cosa/internal/tui/theme"
+)
+
+// plain renders with a theme that sets no colors and strips any styling
+// left, so tests compare text and layout only.
+func plain(t *testing.T, text string, width int) []string {
+	t.Helper()
+	saved := theme.Current
+	theme.Current = theme.Theme{}
+	t.Cleanup(func() { theme.Current = saved })
+
+	lines := Render(text, width, lipgloss.NewStyle())
+	for i, l := range lines {
+		lines[i] = ansi.Strip(l)
+	}
+	return lines
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		width int
+		want  []string
+	}{
+		{
+			name:  "heading",
+			text:  "## Plan",
+			width: 40,
+			want:  []string{"Plan"},
+		},
+		{
+			name:  "heading needs a space",
+			text:  "#hashtag",
+			width: 40,
+			want:  []string{"#hashtag"},
+		},
+		{
+			name:  "bullet list",
+			text:  "- one\n* two\n+ three",
+			width: 40,
+			want:  []string{"• one", "• two", "• three"},
+		},
+		{
+			name:  "numbered list",
+			text:  "1. first\n2) second",
+			width: 40,
+			want:  []string{"1. first", "2) second"},
+		},
+		{
+			name:  "nested list",
+			text:  "- outer\n  - inner",
+			width: 40,
+			want:  []string{"• outer", "  • inner"},
+		},
+		{
+			name:  "list item wraps under its text",
+			text:  "- alpha beta gamma delta",
+			width: 12,
+			want:  []string{"• alpha beta", "  gamma", "  delta"},
+		},
+		{
+			name:  "quote",
+			text:  "> quoted",
+			width: 40,
+			want:  []string{"│ quoted"},
+		},
+		{
+			name:  "rule",
+			text:  "---",
+			width: 10,
+			want:  []string{strings.Repeat("─", 10)},
+		},
+		{
+			name:  "bold and code markers",
+			text:  "use **care** with `rm`",
+			width: 40,
+			want:  []string{"use care with `rm`"},
+		},
+		{
+			name:  "unpaired markers are kept",
+			text:  "a ** b ` c",
+			width: 40,
+			want:  []string{"a ** b ` c"},
+		},
+		{
+			name:  "code fence",
+			text:  "before\n```go\nfunc main() {\n\treturn\n}\n```\nafter",
+			width: 40,
+			want:  []string{"before", "  func main() {", "      return", "  }", "after"},
+		},
+		{
+			name:  "tilde fence",
+			text:  "~~~\n# not a heading\n~~~",
+			width: 40,
+			want:  []string{"  # not a heading"},
+		},
+		{
+			name:  "unclosed fence",
+			text:  "```\nstill writing",
+			width: 40,
+			want:  []string{"  still writing"},
+		},
+		{
+			name:  "code fence breaks long lines",
+			text:  "```\n" + strings.Repeat("x", 12) + "\n```",
+			width: 10,
+			want:  []string{"  xxxxxxxx", "  xxxx"},
+		},
+		{
+			name:  "words wrap",
+			text:  "the quick brown fox jumps",
+			width: 10,
+			want:  []string{"the quick", "brown fox", "jumps"},
+		},
+		{
+			name:  "long word is broken",
+			text:  strings.Repeat("a", 25),
+			width: 10,
+			want:  []string{strings.Repeat("a", 10), strings.Repeat("a", 10), strings.Repeat("a", 5)},
+		},
+		{
+			name:  "wide characters wrap by cells",
+			text:  "日本語 日本語 日本語",
+			width: 14,
+			want:  []string{"日本語 日本語", "日本語"},
+		},
+		{
+			name:  "blank lines are kept",
+			text:  "a\n\nb",
+			width: 40,
+			want:  []string{"a", "", "b"},
+		},
+		{
+			name:  "CRLF line endings",
+			text:  "a\r\nb",
+			width: 40,
+			want:  []string{"a", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := plain(t, tt.text, tt.width)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Render(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender_FitsWidth(t *testing.T) {
+	text := "# A heading long enough to wrap\n" +
+		"- a list item with **bold** text and `inline code` in it\n" +
+		"> a quote that goes on for a while\n" +
+		"```\n" + strings.Repeat("code ", 20) + "\n```\n" +
+		"emoji 🚀🚀🚀 and 日本語 mixed " + strings.Repeat("z", 40)
+
+	for _, width := range []int{10, 17, 32, 80} {
+		for _, th := range []theme.Theme{{}, theme.Noir} {
+			saved := theme.Current
+			theme.Current = th
+			lines := Render(text, width, lipgloss.NewStyle())
+			theme.Current = saved
+
+			for _, l := range lines {
+				if w := display.Width(ansi.Strip(l)); w > width {
+					t.Errorf("width %d: line %q is %d cells wide", width, ansi.Strip(l), w)
+				}
+			}
+		}
+	}
+}
+
+func TestRender_MinimumWidth(t *testing.T) {
+	got := plain(t, "abcdefghijkl", 3)
+	want := []string{"abcdefghij", "kl"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected widths below 10 to render at 10, got %q", got)
+	}
+}
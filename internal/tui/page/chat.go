@@ -6,14 +6,16 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 
+	"cosa/internal/display"
 	"cosa/internal/protocol"
+	"cosa/internal/tui/markdown"
 	"cosa/internal/tui/styles"
 	"cosa/internal/tui/theme"
 )
 
 // ChatMessage represents a message in the chat.
 type ChatMessage struct {
-	Role    string // "user" or "assistant"
+	Role    string // "user", "assistant", "plan" or "command"
 	Content string
 }
 
@@ -53,6 +55,7 @@ type Chat struct {
 	// Session
 	sessionID string
 	isResumed bool
+	planMode  bool // Jobs are drafted instead of started
 
 	// Callbacks
 	onSendMessage func(string)
@@ -90,6 +93,11 @@ func (c *Chat) SetSessionID(id string, resumed bool) {
 	c.isResumed = resumed
 }
 
+// SetPlanMode shows whether the chat is in plan mode.
+func (c *Chat) SetPlanMode(on bool) {
+	c.planMode = on
+}
+
 // SetWorkers updates the workers sidebar.
 func (c *Chat) SetWorkers(workers []protocol.WorkerInfo) {
 	c.workers = workers
@@ -265,8 +273,8 @@ func (c *Chat) countMessageLines() int {
 
 	total := 0
 	for _, msg := range c.messages {
-		lines := c.wrapText(msg.Content, contentWidth-2) // Account for message padding
-		total += len(lines) + 2 // +1 for role line, +1 for blank line after
+		lines := c.renderMessage(msg, contentWidth)
+		total += len(lines) + 1 // +1 for blank line after
 	}
 	return total
 }
@@ -334,6 +342,10 @@ func (c *Chat) renderHeader() string {
 	}
 
 	header := title + sessionInfo
+	if c.planMode {
+		planStyle := lipgloss.NewStyle().Foreground(t.Warning).Bold(true)
+		header += planStyle.Render(" PLAN MODE")
+	}
 
 	return lipgloss.NewStyle().
 		Background(t.Surface).
@@ -421,17 +433,7 @@ func (c *Chat) renderChatPanel(width, height int) string {
 		Height(height - 2).
 		Render(content)
 
-	// Add title to top border (matching dashboard's renderPanel)
-	panelLines := strings.Split(panel, "\n")
-	if len(panelLines) > 0 {
-		firstLine := panelLines[0]
-		titleWidth := lipgloss.Width(titleStr)
-		if len(firstLine) > titleWidth+4 {
-			panelLines[0] = firstLine[:2] + titleStr + firstLine[2+titleWidth:]
-		}
-	}
-
-	return strings.Join(panelLines, "\n")
+	return styles.InsertPanelTitle(panel, titleStr, borderColor)
 }
 
 func (c *Chat) renderMessage(msg ChatMessage, width int) []string {
@@ -444,6 +446,12 @@ func (c *Chat) renderMessage(msg ChatMessage, width int) []string {
 	if msg.Role == "user" {
 		roleStyle = lipgloss.NewStyle().Foreground(t.Primary).Bold(true)
 		roleName = "You"
+	} else if msg.Role == "plan" {
+		roleStyle = lipgloss.NewStyle().Foreground(t.Warning).Bold(true)
+		roleName = "Plan"
+	} else if msg.Role == "command" {
+		roleStyle = lipgloss.NewStyle().Foreground(t.Info).Bold(true)
+		roleName = "Cosa"
 	} else {
 		roleStyle = lipgloss.NewStyle().Foreground(t.Secondary).Bold(true)
 		roleName = "The Underboss"
@@ -452,17 +460,54 @@ func (c *Chat) renderMessage(msg ChatMessage, width int) []string {
 
 	// Content
 	contentStyle := lipgloss.NewStyle().Foreground(t.Text)
-	wrappedLines := c.wrapText(msg.Content, width-2)
-	for _, line := range wrappedLines {
-		// Check for tool use markers
-		if strings.HasPrefix(line, "[Using tool:") {
-			toolStyle := lipgloss.NewStyle().Foreground(t.TextMuted).Italic(true)
-			lines = append(lines, " "+toolStyle.Render(line))
-		} else {
+	wrapWidth := width - 2
+	if msg.Role == "plan" || msg.Role == "command" {
+		wrapWidth -= 2 // Room for the block's border
+	}
+	switch msg.Role {
+	case "user":
+		for _, line := range c.wrapText(msg.Content, wrapWidth) {
 			lines = append(lines, " "+contentStyle.Render(line))
 		}
+	case "plan", "command":
+		gutter := " " + roleStyle.UnsetBold().Render("│ ")
+		for _, line := range markdown.Render(msg.Content, wrapWidth, contentStyle) {
+			lines = append(lines, gutter+line)
+		}
+	default:
+		lines = append(lines, c.renderReply(msg.Content, wrapWidth, contentStyle)...)
+	}
+
+	return lines
+}
+
+// renderReply renders the markdown of a reply from the Underboss, with its
+// tool use markers set apart.
+func (c *Chat) renderReply(content string, width int, style lipgloss.Style) []string {
+	toolStyle := lipgloss.NewStyle().Foreground(theme.Current.TextMuted).Italic(true)
+
+	var lines, text []string
+	flush := func() {
+		if len(text) == 0 {
+			return
+		}
+		for _, line := range markdown.Render(strings.Join(text, "\n"), width, style) {
+			lines = append(lines, " "+line)
+		}
+		text = nil
 	}
 
+	for _, line := range strings.Split(content, "\n") {
+		if !strings.HasPrefix(line, "[Using tool:") {
+			text = append(text, line)
+			continue
+		}
+		flush()
+		for _, l := range c.wrapText(line, width) {
+			lines = append(lines, " "+toolStyle.Render(l))
+		}
+	}
+	flush()
 	return lines
 }
 
@@ -587,17 +632,7 @@ func (c *Chat) renderSidebar(width, height int) string {
 		Height(height - 2).
 		Render(content)
 
-	// Add title to top border (matching dashboard's renderPanel)
-	panelLines := strings.Split(panel, "\n")
-	if len(panelLines) > 0 {
-		firstLine := panelLines[0]
-		titleWidth := lipgloss.Width(titleStr)
-		if len(firstLine) > titleWidth+4 {
-			panelLines[0] = firstLine[:2] + titleStr + firstLine[2+titleWidth:]
-		}
-	}
-
-	return strings.Join(panelLines, "\n")
+	return styles.InsertPanelTitle(panel, titleStr, t.Border)
 }
 
 func (c *Chat) renderWorkerLine(w protocol.WorkerInfo, width int) string {
@@ -628,9 +663,9 @@ func (c *Chat) renderWorkerLine(w protocol.WorkerInfo, width int) string {
 
 	if w.CurrentJobDesc != "" {
 		desc := w.CurrentJobDesc
-		maxDescLen := width - len(w.Name) - 5
-		if len(desc) > maxDescLen && maxDescLen > 3 {
-			desc = desc[:maxDescLen-2] + ".."
+		maxDescLen := width - display.Width(w.Name) - 5
+		if maxDescLen > 3 {
+			desc = display.Truncate(desc, maxDescLen)
 		}
 		line += " - " + jobStyle.Render(desc)
 	} else {
@@ -709,6 +744,7 @@ func (c *Chat) renderFooter() string {
 			desc string
 		}{
 			{"Enter", "send"},
+			{"/help", "commands"},
 			{"Tab", "switch focus"},
 			{"j/k", "scroll"},
 			{"Esc", "back"},
@@ -0,0 +1,392 @@
+package page
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"cosa/internal/display"
+	"cosa/internal/protocol"
+	"cosa/internal/tui/styles"
+	"cosa/internal/tui/theme"
+)
+
+// Conflicts is the merge conflict triage view. It lists the unmerged jobs,
+// the files conflicting for the selected job and what each side changed.
+type Conflicts struct {
+	width  int
+	height int
+
+	conflicts []protocol.ConflictInfo
+	selected  int // Selected conflict
+	detail    *protocol.ConflictDetail
+	file      int // Selected file in detail
+	scroll    int // Preview scroll offset
+
+	confirmAbandon bool
+	message        string // Result of the last action
+}
+
+// NewConflicts creates a new conflict triage view.
+func NewConflicts() *Conflicts {
+	return &Conflicts{}
+}
+
+// SetSize sets the view dimensions.
+func (c *Conflicts) SetSize(width, height int) {
+	c.width = width
+	c.height = height
+}
+
+// SetConflicts sets the unmerged jobs, keeping the selection on the same job
+// where possible.
+func (c *Conflicts) SetConflicts(conflicts []protocol.ConflictInfo) {
+	current := c.SelectedJob()
+	c.conflicts = conflicts
+	c.selected = 0
+	for i, info := range conflicts {
+		if info.JobID == current {
+			c.selected = i
+			break
+		}
+	}
+	if c.detail != nil && c.SelectedJob() != c.detail.JobID {
+		c.detail = nil
+	}
+}
+
+// Select selects the conflict for jobID if it is listed.
+func (c *Conflicts) Select(jobID string) {
+	for i, info := range c.conflicts {
+		if info.JobID == jobID {
+			c.selected = i
+			c.resetDetail()
+			return
+		}
+	}
+}
+
+// SetDetail sets the files and previews for the selected job.
+func (c *Conflicts) SetDetail(detail *protocol.ConflictDetail) {
+	if detail == nil || detail.JobID != c.SelectedJob() {
+		return // Stale response
+	}
+	if c.detail == nil || c.detail.JobID != detail.JobID {
+		c.file = 0
+		c.scroll = 0
+	}
+	c.detail = detail
+	if c.file >= len(detail.Files) {
+		c.file = max(0, len(detail.Files)-1)
+	}
+}
+
+// SelectedJob returns the ID of the selected job, or "" if there is none.
+func (c *Conflicts) SelectedJob() string {
+	if c.selected < len(c.conflicts) {
+		return c.conflicts[c.selected].JobID
+	}
+	return ""
+}
+
+// Detail returns the detail for the selected job, if loaded.
+func (c *Conflicts) Detail() *protocol.ConflictDetail {
+	return c.detail
+}
+
+// SetMessage shows the result of an action.
+func (c *Conflicts) SetMessage(message string) {
+	c.message = message
+}
+
+func (c *Conflicts) resetDetail() {
+	c.detail = nil
+	c.file = 0
+	c.scroll = 0
+	c.confirmAbandon = false
+}
+
+// HandleKey handles key presses. Returns "exit" to leave the page, "select"
+// when another job was selected, or one of the actions "assign", "edit",
+// "merge", "abandon" and "refresh".
+func (c *Conflicts) HandleKey(key string) string {
+	if c.confirmAbandon {
+		c.confirmAbandon = false
+		if key == "y" {
+			return "abandon"
+		}
+		c.message = "Abandon cancelled"
+		return ""
+	}
+
+	switch key {
+	case "esc", "q":
+		return "exit"
+	case "tab", "n":
+		if len(c.conflicts) > 1 {
+			c.selected = (c.selected + 1) % len(c.conflicts)
+			c.resetDetail()
+			return "select"
+		}
+	case "shift+tab", "p":
+		if len(c.conflicts) > 1 {
+			c.selected = (c.selected - 1 + len(c.conflicts)) % len(c.conflicts)
+			c.resetDetail()
+			return "select"
+		}
+	case "j", "down":
+		if c.detail != nil && c.file < len(c.detail.Files)-1 {
+			c.file++
+			c.scroll = 0
+		}
+	case "k", "up":
+		if c.file > 0 {
+			c.file--
+			c.scroll = 0
+		}
+	case "J", "pgdown":
+		c.scroll++
+	case "K", "pgup":
+		if c.scroll > 0 {
+			c.scroll--
+		}
+	case "a":
+		return "assign"
+	case "e":
+		return "edit"
+	case "m":
+		return "merge"
+	case "x":
+		if c.SelectedJob() != "" {
+			c.confirmAbandon = true
+		}
+	case "r":
+		return "refresh"
+	}
+	return ""
+}
+
+// View renders the conflict triage view.
+func (c *Conflicts) View() string {
+	t := theme.Current
+
+	if len(c.conflicts) == 0 {
+		return lipgloss.NewStyle().
+			Width(c.width).
+			Height(c.height).
+			Align(lipgloss.Center, lipgloss.Center).
+			Foreground(t.TextMuted).
+			Render("No merge conflicts\n\nPress Esc to go back")
+	}
+
+	header := c.renderHeader()
+	footer := c.renderFooter()
+
+	contentHeight := c.height - lipgloss.Height(header) - lipgloss.Height(footer)
+	filesHeight := min(contentHeight/3, 10)
+	if filesHeight < 4 {
+		filesHeight = 4
+	}
+	previewHeight := contentHeight - filesHeight
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		header,
+		c.renderFiles(filesHeight),
+		c.renderPreview(previewHeight),
+		footer,
+	)
+
+	return lipgloss.NewStyle().
+		Background(t.Background).
+		Width(c.width).
+		Height(c.height).
+		Render(content)
+}
+
+func (c *Conflicts) renderHeader() string {
+	t := theme.Current
+	info := c.conflicts[c.selected]
+
+	titleStyle := lipgloss.NewStyle().Foreground(t.Error).Bold(true)
+	mutedStyle := lipgloss.NewStyle().Foreground(t.TextMuted)
+	textStyle := lipgloss.NewStyle().Foreground(t.Text)
+
+	id := display.ShortID(info.JobID)
+
+	line1 := fmt.Sprintf(" %s %s  %s",
+		titleStyle.Render("MERGE CONFLICT"),
+		mutedStyle.Render(fmt.Sprintf("[%d/%d]", c.selected+1, len(c.conflicts))),
+		textStyle.Render(truncateLine(id+" "+info.Description, c.width-30)),
+	)
+
+	line2 := fmt.Sprintf(" %s → %s", info.Branch, info.Target)
+	if info.ResolverJob != "" {
+		line2 += fmt.Sprintf("  │  resolver %s (%s)", display.ShortID(info.ResolverJob), info.ResolverStatus)
+	}
+	if c.detail != nil && c.detail.Workspace != "" {
+		line2 += "  │  workspace " + c.detail.Workspace
+	}
+
+	header := lipgloss.JoinVertical(lipgloss.Left, line1, mutedStyle.Render(line2))
+
+	return lipgloss.NewStyle().
+		Background(t.Surface).
+		Width(c.width).
+		Render(header)
+}
+
+func (c *Conflicts) renderFiles(height int) string {
+	t := theme.Current
+
+	borderColor := t.BorderActive
+	title := lipgloss.NewStyle().Foreground(t.Primary).Bold(true).Render(" CONFLICTED FILES ")
+
+	contentHeight := height - 2
+	var lines []string
+
+	switch {
+	case c.detail == nil:
+		lines = append(lines, lipgloss.NewStyle().Foreground(t.TextMuted).Render(" Loading..."))
+	case len(c.detail.Files) == 0:
+		lines = append(lines, lipgloss.NewStyle().Foreground(t.TextMuted).Render(" No conflicting files; try merging again (m)"))
+	default:
+		// Keep the selected file visible
+		start := 0
+		if c.file >= contentHeight {
+			start = c.file - contentHeight + 1
+		}
+		end := min(start+contentHeight, len(c.detail.Files))
+		for i := start; i < end; i++ {
+			style := lipgloss.NewStyle().Foreground(t.Text)
+			prefix := "  "
+			if i == c.file {
+				style = style.Foreground(t.Primary).Bold(true)
+				prefix = "▸ "
+			}
+			lines = append(lines, style.Render(" "+prefix+c.detail.Files[i].Path))
+		}
+	}
+
+	for len(lines) < contentHeight {
+		lines = append(lines, "")
+	}
+
+	panel := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(borderColor).
+		Width(c.width - 2).
+		Height(contentHeight).
+		Render(strings.Join(lines, "\n"))
+
+	return styles.InsertPanelTitle(panel, title, borderColor)
+}
+
+func (c *Conflicts) renderPreview(height int) string {
+	var target, branch string
+	targetTitle, branchTitle := " TARGET ", " JOB BRANCH "
+	if c.detail != nil && c.file < len(c.detail.Files) {
+		f := c.detail.Files[c.file]
+		target, branch = f.Target, f.Branch
+		targetTitle = fmt.Sprintf(" %s ", c.detail.Target)
+		branchTitle = fmt.Sprintf(" %s ", c.detail.Branch)
+	}
+
+	leftWidth := c.width / 2
+	rightWidth := c.width - leftWidth
+
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		c.renderSide(targetTitle, target, leftWidth, height),
+		c.renderSide(branchTitle, branch, rightWidth, height),
+	)
+}
+
+func (c *Conflicts) renderSide(title, diff string, width, height int) string {
+	t := theme.Current
+
+	contentHeight := height - 2
+	if contentHeight < 1 {
+		contentHeight = 1
+	}
+
+	var all []string
+	if diff != "" {
+		all = strings.Split(diff, "\n")
+	}
+	start := min(c.scroll, max(0, len(all)-contentHeight))
+	end := min(start+contentHeight, len(all))
+
+	var lines []string
+	for _, line := range all[start:end] {
+		style := lipgloss.NewStyle().Foreground(t.Text)
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			style = style.Foreground(t.Secondary)
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			style = style.Foreground(t.TextMuted)
+		case strings.HasPrefix(line, "+"):
+			style = style.Foreground(t.Success)
+		case strings.HasPrefix(line, "-"):
+			style = style.Foreground(t.Error)
+		}
+		lines = append(lines, style.Render(truncateLine(" "+line, width-4)))
+	}
+	for len(lines) < contentHeight {
+		lines = append(lines, "")
+	}
+
+	borderColor := t.Border
+	panel := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(borderColor).
+		Width(width - 2).
+		Height(contentHeight).
+		Render(strings.Join(lines, "\n"))
+
+	titleStyled := lipgloss.NewStyle().Foreground(t.TextMuted).Render(title)
+	return styles.InsertPanelTitle(panel, titleStyled, borderColor)
+}
+
+func (c *Conflicts) renderFooter() string {
+	t := theme.Current
+
+	if c.confirmAbandon {
+		prompt := lipgloss.NewStyle().Foreground(t.Warning).Bold(true).
+			Render(" Delete the job branch and mark the job as needing attention? (y/N)")
+		return lipgloss.NewStyle().
+			Background(t.Surface).
+			Width(c.width).
+			Render(prompt)
+	}
+
+	keys := []struct {
+		key  string
+		desc string
+	}{
+		{"j/k", "file"},
+		{"J/K", "scroll"},
+		{"n/p", "job"},
+		{"a", "assign resolver"},
+		{"e", "edit"},
+		{"m", "merge"},
+		{"x", "abandon"},
+		{"Esc", "back"},
+	}
+
+	var parts []string
+	keyStyle := lipgloss.NewStyle().Foreground(t.Primary).Bold(true)
+	descStyle := lipgloss.NewStyle().Foreground(t.TextMuted)
+	for _, k := range keys {
+		parts = append(parts, keyStyle.Render(k.key)+" "+descStyle.Render(k.desc))
+	}
+
+	footer := " " + strings.Join(parts, "  │  ")
+	if c.message != "" {
+		footer = lipgloss.NewStyle().Foreground(t.Text).Render(" "+c.message) + "\n" + footer
+	}
+
+	return lipgloss.NewStyle().
+		Background(t.Surface).
+		Width(c.width).
+		Render(footer)
+}
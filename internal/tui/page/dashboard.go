@@ -9,6 +9,8 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
 
+	"cosa/internal/display"
+	"cosa/internal/ledger"
 	"cosa/internal/protocol"
 	"cosa/internal/tui/component"
 	"cosa/internal/tui/styles"
@@ -22,6 +24,8 @@ const (
 	FocusWorkers FocusArea = iota
 	FocusJobs
 	FocusActivity
+	FocusReviews
+	FocusDiff
 )
 
 // Dashboard is the main dashboard page.
@@ -31,19 +35,47 @@ type Dashboard struct {
 	height  int
 	status  *protocol.StatusResult
 
+	layout     Layout
 	focus      FocusArea
 	workerList *component.WorkerList
 	jobList    *component.JobList
+	queueList  *component.JobList // Unfinished jobs, for the operator layout
 	activity   *component.Activity
 
+	workers []protocol.WorkerOverview
+	jobs    []protocol.JobInfo
+
+	// Reviewer layout: active reviews, the selected job and its diff
+	reviews        []protocol.ReviewStatusResult
+	reviewSelected int
+	diff           *protocol.JobDiffResult
+	diffScroll     int
+	diffMessage    string
+
+	// Jobs whose branches could not be merged
+	conflicts int
+
+	// Jobs waiting on the boss in triage
+	attention int
+
+	// Set while the connection to the daemon is lost
+	disconnected bool
+
+	// Set when the connection is read-only
+	observing bool
+
+	// Set when the dashboard plays back a ledger rather than a daemon
+	replaying bool
+
 	// Dialogs
 	newJobDialog     *component.Dialog
 	showDialog       bool
 	templateSelector *component.TemplateSelector
 	showTemplates    bool
+	palette          *component.CommandPalette
 
 	// Callbacks
-	onCreateJob      func(description string)
+	onCreateJob      func(description, preset string)
 	onReassignJob    func(jobID string)
 	onUseTemplate    func(templateID string, variables map[string]string)
 }
@@ -52,11 +84,14 @@ type Dashboard struct {
 func NewDashboard() *Dashboard {
 	d := &Dashboard{
 		styles:           styles.New(),
+		layout:           LayoutDefault,
 		workerList:       component.NewWorkerList(),
 		jobList:          component.NewJobList(),
+		queueList:        component.NewJobList(),
 		activity:         component.NewActivity(),
 		newJobDialog:     component.NewJobDialog(),
 		templateSelector: component.NewTemplateSelector(),
+		palette:          component.NewCommandPalette(),
 	}
 
 	// Set up template selector callbacks
@@ -85,14 +120,52 @@ func (d *Dashboard) SetStatus(status *protocol.StatusResult) {
 	d.status = status
 }
 
+// SetDisconnected marks whether the connection to the daemon is lost.
+func (d *Dashboard) SetDisconnected(disconnected bool) {
+	d.disconnected = disconnected
+}
+
+// SetObserving marks whether the connection is read-only.
+func (d *Dashboard) SetObserving(observing bool) {
+	d.observing = observing
+}
+
+// SetReplaying marks whether the dashboard plays back a ledger.
+func (d *Dashboard) SetReplaying(replaying bool) {
+	d.replaying = replaying
+}
+
 // SetWorkers updates the worker list.
-func (d *Dashboard) SetWorkers(workers []protocol.WorkerInfo) {
+func (d *Dashboard) SetWorkers(workers []protocol.WorkerOverview) {
+	d.workers = workers
 	d.workerList.SetWorkers(workers)
 }
 
 // SetJobs updates the job list.
 func (d *Dashboard) SetJobs(jobs []protocol.JobInfo) {
+	current := d.SelectedReviewJob()
+	d.jobs = jobs
 	d.jobList.SetJobs(jobs)
+	d.keepReviewSelection(current)
+
+	var queue []protocol.JobInfo
+	for _, j := range jobs {
+		if j.Status != "completed" && j.Status != "cancelled" {
+			queue = append(queue, j)
+		}
+	}
+	d.queueList.SetJobs(queue)
+
+	d.conflicts = 0
+	d.attention = 0
+	for _, j := range jobs {
+		if j.Unmerged {
+			d.conflicts++
+		}
+		if j.Status == "needs_attention" {
+			d.attention++
+		}
+	}
 }
 
 // AddActivity adds an activity item.
@@ -104,6 +177,55 @@ func (d *Dashboard) AddActivity(time, worker, message string) {
 	})
 }
 
+// AddEventActivity adds an activity item for a ledger event, shown in the
+// color of its severity.
+func (d *Dashboard) AddEventActivity(time, worker, message string, severity ledger.Severity) {
+	d.activity.AddItem(component.ActivityItem{
+		Time:     time,
+		Worker:   worker,
+		Message:  message,
+		Severity: severity,
+	})
+}
+
+// PrependActivity adds older activity items, oldest first, paged in from
+// the ledger.
+func (d *Dashboard) PrependActivity(items []component.ActivityItem) {
+	d.activity.PrependItems(items)
+}
+
+// ActivityAtTop reports whether the oldest loaded activity item is visible.
+func (d *Dashboard) ActivityAtTop() bool {
+	return d.activity.AtTop()
+}
+
+// Layout returns the current layout.
+func (d *Dashboard) Layout() Layout {
+	return d.layout
+}
+
+// SetLayout switches the dashboard layout, focusing its first panel.
+func (d *Dashboard) SetLayout(layout Layout) {
+	if _, ok := layoutFocus[layout]; !ok {
+		layout = LayoutDefault
+	}
+	d.layout = layout
+	d.focus = layoutFocus[layout][0]
+	d.updateFocus()
+	d.updateComponentSizes()
+}
+
+// NextLayout switches to the next layout and returns it.
+func (d *Dashboard) NextLayout() Layout {
+	for i, l := range Layouts {
+		if l == d.layout {
+			d.SetLayout(Layouts[(i+1)%len(Layouts)])
+			break
+		}
+	}
+	return d.layout
+}
+
 // Focus returns the current focus area.
 func (d *Dashboard) Focus() FocusArea {
 	return d.focus
@@ -115,16 +237,38 @@ func (d *Dashboard) SetFocus(focus FocusArea) {
 	d.updateFocus()
 }
 
+// FocusPanel focuses the layout's nth panel, counting from zero.
+func (d *Dashboard) FocusPanel(n int) {
+	if order := layoutFocus[d.layout]; n >= 0 && n < len(order) {
+		d.SetFocus(order[n])
+	}
+}
+
 // NextFocus moves focus to the next panel.
 func (d *Dashboard) NextFocus() {
-	d.focus = (d.focus + 1) % 3
-	d.updateFocus()
+	d.moveFocus(1)
 }
 
 // PrevFocus moves focus to the previous panel.
 func (d *Dashboard) PrevFocus() {
-	d.focus = (d.focus + 2) % 3
-	d.updateFocus()
+	d.moveFocus(-1)
+}
+
+// FirstFocused reports whether the layout's first panel is focused.
+func (d *Dashboard) FirstFocused() bool {
+	return d.focus == layoutFocus[d.layout][0]
+}
+
+func (d *Dashboard) moveFocus(delta int) {
+	order := layoutFocus[d.layout]
+	for i, f := range order {
+		if f == d.focus {
+			d.focus = order[(i+delta+len(order))%len(order)]
+			d.updateFocus()
+			return
+		}
+	}
+	d.SetFocus(order[0])
 }
 
 // HandleKey handles key presses on the focused component.
@@ -140,19 +284,73 @@ func (d *Dashboard) HandleKey(key string) {
 	case FocusJobs:
 		switch key {
 		case "j", "down":
-			d.jobList.MoveDown()
+			d.selectedJobs().MoveDown()
+		case "k", "up":
+			d.selectedJobs().MoveUp()
+		}
+	case FocusActivity:
+		switch key {
+		case "j", "down":
+			d.activity.ScrollDown()
+		case "k", "up":
+			d.activity.ScrollUp()
+		case "e":
+			d.activity.ToggleErrorsOnly()
+		}
+	case FocusReviews:
+		switch key {
+		case "j", "down":
+			if d.reviewSelected < len(d.reviewItems())-1 {
+				d.reviewSelected++
+			}
+		case "k", "up":
+			if d.reviewSelected > 0 {
+				d.reviewSelected--
+			}
+		}
+	case FocusDiff:
+		switch key {
+		case "j", "down":
+			d.diffScroll++
 		case "k", "up":
-			d.jobList.MoveUp()
+			if d.diffScroll > 0 {
+				d.diffScroll--
+			}
 		}
 	}
 }
 
+// selectedJobs returns the job list shown by the current layout.
+func (d *Dashboard) selectedJobs() *component.JobList {
+	if d.layout == LayoutOperator {
+		return d.queueList
+	}
+	return d.jobList
+}
+
 func (d *Dashboard) updateFocus() {
 	d.workerList.SetFocused(d.focus == FocusWorkers)
 	d.jobList.SetFocused(d.focus == FocusJobs)
+	d.queueList.SetFocused(d.focus == FocusJobs)
 }
 
 func (d *Dashboard) updateComponentSizes() {
+	switch d.layout {
+	case LayoutOperator:
+		leftWidth := d.width * 40 / 100
+		leftHeight := (d.height - 4) / 2
+		rightHeight := d.height - 4
+		alertsHeight := (rightHeight - budgetPanelHeight) / 2
+
+		// Panel contents lose the border and title
+		d.queueList.SetSize(leftWidth, leftHeight-3)
+		d.workerList.SetSize(leftWidth, rightHeight-leftHeight-3)
+		d.activity.SetSize(d.width-leftWidth-3, rightHeight-budgetPanelHeight-alertsHeight-3)
+		return
+	case LayoutReviewer:
+		return // Rendered directly from the reviews and diff
+	}
+
 	// Left column: 30% width, workers and jobs stacked
 	leftWidth := d.width * 30 / 100
 	leftHeight := (d.height - 4) / 2 // Account for header/footer, split in half
@@ -174,22 +372,16 @@ func (d *Dashboard) View() string {
 	// Header
 	header := d.renderHeader()
 
-	// Left column: Workers + Jobs
-	leftWidth := d.width * 30 / 100
-	leftHeight := (d.height - 4) / 2
-
-	workersPanel := d.renderPanel("WORKERS", d.workerList.View(), leftWidth, leftHeight, d.focus == FocusWorkers)
-	jobsPanel := d.renderPanel("JOBS", d.jobList.View(), leftWidth, leftHeight, d.focus == FocusJobs)
-	leftColumn := lipgloss.JoinVertical(lipgloss.Left, workersPanel, jobsPanel)
-
-	// Right column: Activity
-	rightWidth := d.width - leftWidth - 3
-	rightHeight := d.height - 4
-	activityPanel := d.renderPanel("ACTIVITY", d.activity.View(), rightWidth, rightHeight, d.focus == FocusActivity)
-
-	// Join columns horizontally with a gap
-	gap := lipgloss.NewStyle().Width(1).Height(rightHeight).Render(" ")
-	content := lipgloss.JoinHorizontal(lipgloss.Top, leftColumn, gap, activityPanel)
+	// Panels for the current layout
+	var content string
+	switch d.layout {
+	case LayoutOperator:
+		content = d.viewOperator()
+	case LayoutReviewer:
+		content = d.viewReviewer()
+	default:
+		content = d.viewDefault()
+	}
 
 	// Footer
 	footer := d.renderFooter()
@@ -213,9 +405,45 @@ func (d *Dashboard) View() string {
 		return d.renderWithTemplateSelectorOverlay(base, t)
 	}
 
+	// Overlay command palette if visible
+	if d.palette.Visible() {
+		return d.overlayOnBase(base, d.palette.View(), t)
+	}
+
 	return base
 }
 
+// viewDefault renders workers and jobs beside the activity feed.
+func (d *Dashboard) viewDefault() string {
+	// Left column: Workers + Jobs
+	leftWidth := d.width * 30 / 100
+	leftHeight := (d.height - 4) / 2
+
+	workersPanel := d.renderPanel("WORKERS", d.workerList.View(), leftWidth, leftHeight, d.focus == FocusWorkers)
+	jobsPanel := d.renderPanel("JOBS", d.jobList.View(), leftWidth, leftHeight, d.focus == FocusJobs)
+	leftColumn := lipgloss.JoinVertical(lipgloss.Left, workersPanel, jobsPanel)
+
+	// Right column: Activity
+	rightWidth := d.width - leftWidth - 3
+	rightHeight := d.height - 4
+	activityPanel := d.renderPanel(d.activityTitle(), d.activity.View(), rightWidth, rightHeight, d.focus == FocusActivity)
+
+	// Join columns horizontally with a gap
+	gap := lipgloss.NewStyle().Width(1).Height(rightHeight).Render(" ")
+	return lipgloss.JoinHorizontal(lipgloss.Top, leftColumn, gap, activityPanel)
+}
+
+func (d *Dashboard) activityTitle() string {
+	title := "ACTIVITY"
+	if d.activity.ErrorsOnly() {
+		title += " (ERRORS)"
+	}
+	if d.activity.Scrolled() {
+		title += " (HISTORY)"
+	}
+	return title
+}
+
 func (d *Dashboard) renderWithDialogOverlay(baseView string, t theme.Theme) string {
 	// Get the raw dialog view (not centered yet)
 	dialogView := d.newJobDialog.View()
@@ -305,16 +533,40 @@ func (d *Dashboard) renderHeader() string {
 		Foreground(t.Primary).
 		Bold(true).
 		Render("◆ COSA NOSTRA")
+	if d.layout != LayoutDefault {
+		title += lipgloss.NewStyle().
+			Foreground(t.TextMuted).
+			Render(" · " + strings.ToUpper(string(d.layout)))
+	}
+	if d.replaying {
+		title += lipgloss.NewStyle().
+			Foreground(t.Warning).
+			Render(" · REPLAY")
+	} else if d.observing {
+		title += lipgloss.NewStyle().
+			Foreground(t.Warning).
+			Render(" · OBSERVING")
+	}
 
 	// Status info
 	var statusInfo string
-	if d.status != nil {
+	if d.disconnected {
+		statusInfo = lipgloss.NewStyle().
+			Foreground(t.Warning).
+			Bold(true).
+			Render("◌ daemon unreachable, reconnecting…")
+	} else if d.status != nil {
 		uptime := formatUptime(d.status.Uptime)
 		statusInfo = lipgloss.NewStyle().
 			Foreground(t.TextMuted).
 			Render(fmt.Sprintf("v%s │ %s │ %d workers │ %d jobs",
 				d.status.Version, uptime, d.status.Workers, d.status.ActiveJobs))
+		statusInfo += d.renderBudget()
+		statusInfo += d.renderReplayClock()
+		statusInfo += d.renderQueueHold()
+		statusInfo += d.renderQueueETA()
 	}
+	statusInfo += d.renderAttention()
 
 	// Spacer
 	spacerWidth := d.width - lipgloss.Width(title) - lipgloss.Width(statusInfo) - 4
@@ -328,6 +580,90 @@ func (d *Dashboard) renderHeader() string {
 		Render(header)
 }
 
+// renderBudget renders today's spend against the daily budget for the
+// header, highlighting it as the limit approaches.
+func (d *Dashboard) renderBudget() string {
+	t := theme.Current
+
+	b := d.status.Budget
+	if b == nil || (b.DailyLimit <= 0 && b.SpentToday == 0) {
+		return ""
+	}
+
+	sep := lipgloss.NewStyle().Foreground(t.TextMuted).Render(" │ ")
+	if b.DailyLimit <= 0 {
+		return sep + lipgloss.NewStyle().Foreground(t.TextMuted).Render(fmt.Sprintf("$%.2f today", b.SpentToday))
+	}
+
+	color := t.TextMuted
+	text := fmt.Sprintf("$%.2f/$%.2f", b.SpentToday, b.DailyLimit)
+	switch {
+	case b.Exceeded:
+		color = t.Error
+		text += " over budget"
+	case b.SpentToday >= b.DailyLimit*0.8:
+		color = t.Warning
+	}
+	return sep + lipgloss.NewStyle().Foreground(color).Bold(b.Exceeded).Render(text)
+}
+
+// renderReplayClock renders the time a replayed ledger has reached.
+func (d *Dashboard) renderReplayClock() string {
+	if d.status.ReplayClock == 0 {
+		return ""
+	}
+
+	t := theme.Current
+	sep := lipgloss.NewStyle().Foreground(t.TextMuted).Render(" │ ")
+	return sep + lipgloss.NewStyle().
+		Foreground(t.Warning).
+		Render("▶ "+time.Unix(d.status.ReplayClock, 0).Format("Jan 2 15:04:05"))
+}
+
+// renderQueueHold renders a badge while the queue is paused or draining, so
+// that idle workers don't look like a fault.
+func (d *Dashboard) renderQueueHold() string {
+	if d.status.Queue == "" {
+		return ""
+	}
+
+	t := theme.Current
+	sep := lipgloss.NewStyle().Foreground(t.TextMuted).Render(" │ ")
+	return sep + lipgloss.NewStyle().
+		Foreground(t.Warning).
+		Bold(true).
+		Render("⏸ queue "+d.status.Queue)
+}
+
+// renderQueueETA renders when the running and queued jobs should be done.
+func (d *Dashboard) renderQueueETA() string {
+	if d.status.QueueETA == 0 {
+		return ""
+	}
+
+	t := theme.Current
+	eta := time.Unix(d.status.QueueETA, 0)
+	sep := lipgloss.NewStyle().Foreground(t.TextMuted).Render(" │ ")
+	return sep + lipgloss.NewStyle().
+		Foreground(t.TextMuted).
+		Render("queue done ~"+eta.Format("15:04"))
+}
+
+// renderAttention renders a badge for jobs waiting in triage, so they stand
+// out from the activity stream.
+func (d *Dashboard) renderAttention() string {
+	if d.attention == 0 {
+		return ""
+	}
+
+	t := theme.Current
+	sep := lipgloss.NewStyle().Foreground(t.TextMuted).Render(" │ ")
+	return sep + lipgloss.NewStyle().
+		Foreground(t.Warning).
+		Bold(true).
+		Render(fmt.Sprintf("⚑ %d need attention", d.attention))
+}
+
 func (d *Dashboard) renderFooter() string {
 	t := theme.Current
 
@@ -340,9 +676,52 @@ func (d *Dashboard) renderFooter() string {
 		{"a", "add worker"},
 		{"n", "new job"},
 		{"t", "templates"},
+		{"J", "jobs"},
+		{"L", "layout"},
 		{"q", "quit"},
 	}
 
+	switch d.layout {
+	case LayoutOperator:
+		keys = []struct {
+			key  string
+			desc string
+		}{
+			{"Tab", "switch panel"},
+			{"j/k", "navigate"},
+			{"n", "new job"},
+			{"x", "cancel job"},
+			{"J", "jobs"},
+			{"L", "layout"},
+			{"q", "quit"},
+		}
+	case LayoutReviewer:
+		keys = []struct {
+			key  string
+			desc string
+		}{
+			{"Tab", "switch panel"},
+			{"j/k", "select/scroll"},
+			{"s", "start review"},
+			{"V", "review"},
+			{"d", "reload diff"},
+			{"L", "layout"},
+			{"q", "quit"},
+		}
+	}
+
+	// Offer to pin or unpin the selected job
+	if j := d.SelectedJob(); j != nil {
+		desc := "watch"
+		if j.Watched {
+			desc = "unwatch"
+		}
+		keys = append([]struct {
+			key  string
+			desc string
+		}{{"w", desc}}, keys...)
+	}
+
 	// Add reassign option when a failed/cancelled job is selected
 	if d.CanReassignSelectedJob() {
 		keys = append([]struct {
@@ -351,6 +730,42 @@ func (d *Dashboard) renderFooter() string {
 		}{{"R", "reassign job"}}, keys...)
 	}
 
+	// Offer conflict triage while any job is unmerged
+	if d.conflicts > 0 {
+		keys = append([]struct {
+			key  string
+			desc string
+		}{{"C", fmt.Sprintf("%d conflicts", d.conflicts)}}, keys...)
+	}
+
+	// Add conflict resolution for the selected unmerged job under review
+	if d.layout == LayoutReviewer && d.selectedReviewUnmerged() {
+		keys = append([]struct {
+			key  string
+			desc string
+		}{{"Enter", "resolve conflict"}}, keys...)
+	}
+
+	// Offer the errors-only filter when the activity panel is focused
+	if d.focus == FocusActivity {
+		desc := "errors only"
+		if d.activity.ErrorsOnly() {
+			desc = "all activity"
+		}
+		keys = append([]struct {
+			key  string
+			desc string
+		}{{"e", desc}}, keys...)
+	}
+
+	// Add worker detail option when the workers panel is focused
+	if d.focus == FocusWorkers && d.workerList.Selected() != nil {
+		keys = append([]struct {
+			key  string
+			desc string
+		}{{"Enter", "worker detail"}}, keys...)
+	}
+
 	var parts []string
 	keyStyle := lipgloss.NewStyle().Foreground(t.Primary).Bold(true)
 	descStyle := lipgloss.NewStyle().Foreground(t.TextMuted)
@@ -468,7 +883,8 @@ func (d *Dashboard) IsInputMode() bool {
 }
 
 // SetOnCreateJob sets the callback for when a job is created.
-func (d *Dashboard) SetOnCreateJob(fn func(description string)) {
+// The preset is empty when none was chosen.
+func (d *Dashboard) SetOnCreateJob(fn func(description, preset string)) {
 	d.onCreateJob = fn
 }
 
@@ -482,7 +898,7 @@ func (d *Dashboard) CanReassignSelectedJob() bool {
 	if d.focus != FocusJobs {
 		return false
 	}
-	return d.jobList.CanReassignSelected()
+	return d.selectedJobs().CanReassignSelected()
 }
 
 // ReassignSelectedJob triggers reassignment of the selected job.
@@ -490,7 +906,7 @@ func (d *Dashboard) ReassignSelectedJob() {
 	if d.onReassignJob == nil {
 		return
 	}
-	selected := d.jobList.Selected()
+	selected := d.selectedJobs().Selected()
 	if selected == nil {
 		return
 	}
@@ -517,8 +933,12 @@ func (d *Dashboard) HandleDialogKey(key string) string {
 		}
 		if action == "create" && d.onCreateJob != nil {
 			description := d.newJobDialog.GetInputValue()
+			preset := d.newJobDialog.SelectedOption()
+			if preset == "none" {
+				preset = ""
+			}
 			if description != "" {
-				d.onCreateJob(description)
+				d.onCreateJob(description, preset)
 			}
 			d.newJobDialog.Hide()
 			d.showDialog = false
@@ -559,14 +979,56 @@ func (d *Dashboard) ShowSearch() {
 
 // ShowCommandPalette shows the command palette.
 func (d *Dashboard) ShowCommandPalette() {
-	// Stub for command palette
-	d.AddActivity(time.Now().Format("15:04:05"), "", "Command palette (press ESC to close)")
+	d.palette.SetSize(60, 15)
+	d.palette.Show()
+}
+
+// ShowThemePalette shows the command palette listing the themes to switch
+// to, with current selected.
+func (d *Dashboard) ShowThemePalette(names []string, current string) {
+	d.palette.SetSize(60, 15)
+	d.palette.ShowThemes(names, current)
+}
+
+// IsPaletteMode returns true if the command palette is visible.
+func (d *Dashboard) IsPaletteMode() bool {
+	return d.palette.Visible()
+}
+
+// HandlePaletteKey handles key input for the command palette, returning the
+// action of the command chosen, "cancel", or "" while the user is choosing.
+func (d *Dashboard) HandlePaletteKey(key string) string {
+	return d.palette.HandleKey(key)
+}
+
+// Restyle rebuilds the dashboard's styles from the current theme, after the
+// theme is switched.
+func (d *Dashboard) Restyle() {
+	d.styles = styles.New()
+	d.workerList.Restyle()
+	d.jobList.Restyle()
+	d.queueList.Restyle()
+	d.activity.Restyle()
 }
 
 // ToggleHelp toggles the help overlay.
 func (d *Dashboard) ToggleHelp() {
 	// Stub for help overlay
-	d.AddActivity(time.Now().Format("15:04:05"), "", "Help: Tab=switch, j/k=nav, n=new job, o=new op, /=search, :=cmd, ?=help, q=quit")
+	d.AddActivity(time.Now().Format("15:04:05"), "", "Help: Tab=switch, j/k=nav, n=new job, o=new op, C=conflicts, V=review, /=search, :=cmd, ?=help, q=quit")
+}
+
+// SelectedWorker returns the worker selected in the workers panel.
+func (d *Dashboard) SelectedWorker() *protocol.WorkerInfo {
+	return d.workerList.Selected()
+}
+
+// SelectedJob returns the job selected in the jobs panel, or nil if the
+// jobs panel is not focused.
+func (d *Dashboard) SelectedJob() *protocol.JobInfo {
+	if d.focus != FocusJobs || d.layout == LayoutReviewer {
+		return nil
+	}
+	return d.selectedJobs().Selected()
 }
 
 // SelectCurrent selects the currently focused item.
@@ -577,8 +1039,8 @@ func (d *Dashboard) SelectCurrent() {
 			d.AddActivity(time.Now().Format("15:04:05"), selected.Name, "Selected worker")
 		}
 	case FocusJobs:
-		if selected := d.jobList.Selected(); selected != nil {
-			d.AddActivity(time.Now().Format("15:04:05"), "", fmt.Sprintf("Selected job: %s", selected.ID[:8]))
+		if selected := d.selectedJobs().Selected(); selected != nil {
+			d.AddActivity(time.Now().Format("15:04:05"), "", fmt.Sprintf("Selected job: %s", display.ShortID(selected.ID)))
 		}
 	}
 }
@@ -593,6 +1055,7 @@ func (d *Dashboard) CloseOverlay() {
 		d.templateSelector.Hide()
 		d.showTemplates = false
 	}
+	d.palette.Hide()
 }
 
 // SetOnUseTemplate sets the callback for when a template is used to create a job.
@@ -605,6 +1068,11 @@ func (d *Dashboard) SetTemplates(templates []component.TemplateItem) {
 	d.templateSelector.SetTemplates(templates)
 }
 
+// SetPresets updates the job presets offered in the new job dialog.
+func (d *Dashboard) SetPresets(names []string) {
+	d.newJobDialog.SetSelectOptions(append([]string{"none"}, names...))
+}
+
 // ShowTemplateSelector shows the template selection dialog.
 func (d *Dashboard) ShowTemplateSelector() {
 	d.showTemplates = true
@@ -0,0 +1,180 @@
+package page
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"cosa/internal/tui/theme"
+)
+
+// syntax is what the highlighter needs to know about a language: its
+// keywords, how its line comments start and which quotes delimit strings.
+// Block comments are not tracked, since a diff hunk can start inside one.
+type syntax struct {
+	keywords map[string]bool
+	comments []string
+	quotes   string
+}
+
+func newSyntax(comments []string, quotes string, keywords string) *syntax {
+	s := &syntax{keywords: make(map[string]bool), comments: comments, quotes: quotes}
+	for _, k := range strings.Fields(keywords) {
+		s.keywords[k] = true
+	}
+	return s
+}
+
+var (
+	goSyntax = newSyntax([]string{"//"}, "\"'`", `break case chan const continue default defer else fallthrough for
+		func go goto if import interface map package range return select struct switch type var
+		nil true false iota`)
+	jsSyntax = newSyntax([]string{"//"}, "\"'`", `async await break case catch class const continue default delete do
+		else export extends finally for from function if import in instanceof interface let new
+		of return static super switch this throw try type typeof var void while yield
+		null undefined true false`)
+	pythonSyntax = newSyntax([]string{"#"}, "\"'", `and as assert async await break class continue def del elif else except
+		finally for from global if import in is lambda nonlocal not or pass raise return try
+		while with yield None True False self`)
+	rustSyntax = newSyntax([]string{"//"}, "\"", `as async await break const continue crate else enum extern fn for if impl
+		in let loop match mod move mut pub ref return self Self static struct super trait type
+		unsafe use where while true false`)
+	javaSyntax = newSyntax([]string{"//"}, "\"'", `abstract break case catch class const continue default do else enum extends
+		final finally for fun if implements import in interface is new null object override
+		package private protected public return static super switch this throw throws try val
+		var void when while true false`)
+	cSyntax = newSyntax([]string{"//"}, "\"'", `auto break case char class const continue default delete do double else enum
+		extern float for if include define inline int long namespace new nullptr private
+		protected public return short signed sizeof static struct switch template this typedef
+		typename union unsigned using virtual void volatile while true false NULL`)
+	rubySyntax = newSyntax([]string{"#"}, "\"'", `alias and begin break case class def do else elsif end ensure false for if
+		in module next nil not or raise redo rescue retry return self super then true unless
+		until when while yield require`)
+	shellSyntax = newSyntax([]string{"#"}, "\"'", `case do done elif else esac export fi for function if in local read return
+		set shift then until while`)
+	sqlSyntax = newSyntax([]string{"--"}, "'", `alter and as by create delete drop from group having in index insert into
+		join key left not null on or order primary references select set table update values
+		where ALTER AND AS BY CREATE DELETE DROP FROM GROUP HAVING IN INDEX INSERT INTO JOIN KEY
+		LEFT NOT NULL ON OR ORDER PRIMARY REFERENCES SELECT SET TABLE UPDATE VALUES WHERE`)
+	configSyntax = newSyntax([]string{"#"}, "\"'", `true false null yes no on off`)
+)
+
+// syntaxes maps file extensions to the language they hold.
+var syntaxes = map[string]*syntax{
+	".go":   goSyntax,
+	".js":   jsSyntax,
+	".jsx":  jsSyntax,
+	".mjs":  jsSyntax,
+	".ts":   jsSyntax,
+	".tsx":  jsSyntax,
+	".py":   pythonSyntax,
+	".rs":   rustSyntax,
+	".java": javaSyntax,
+	".kt":   javaSyntax,
+	".c":    cSyntax,
+	".h":    cSyntax,
+	".cc":   cSyntax,
+	".cpp":  cSyntax,
+	".hpp":  cSyntax,
+	".rb":   rubySyntax,
+	".sh":   shellSyntax,
+	".bash": shellSyntax,
+	".sql":  sqlSyntax,
+	".yaml": configSyntax,
+	".yml":  configSyntax,
+	".toml": configSyntax,
+}
+
+// syntaxFor returns the language of the file at path, or nil if it is not
+// one the highlighter knows.
+func syntaxFor(path string) *syntax {
+	if filepath.Base(path) == "Makefile" || filepath.Base(path) == "Dockerfile" {
+		return shellSyntax
+	}
+	return syntaxes[strings.ToLower(filepath.Ext(path))]
+}
+
+// highlight colors the keywords, strings, numbers and comments of a line of
+// code. Other text is drawn with base.
+func (s *syntax) highlight(line string, base lipgloss.Style) string {
+	if s == nil {
+		return base.Render(line)
+	}
+
+	t := theme.Current
+	keyword := base.Foreground(t.Accent).Bold(true)
+	str := base.Foreground(t.Warning)
+	number := base.Foreground(t.Info)
+	comment := base.Foreground(t.TextDim).Italic(true)
+
+	var out strings.Builder
+	runes := []rune(line)
+	plain := 0 // Start of the text not yet written
+	flush := func(end int) {
+		if end > plain {
+			out.WriteString(base.Render(string(runes[plain:end])))
+		}
+	}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case s.commentAt(runes[i:]):
+			flush(i)
+			out.WriteString(comment.Render(string(runes[i:])))
+			return out.String()
+
+		case strings.ContainsRune(s.quotes, r):
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(runes))
+			flush(i)
+			out.WriteString(str.Render(string(runes[i:end])))
+			i, plain = end, end
+
+		case unicode.IsLetter(r) || r == '_':
+			end := i + 1
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_') {
+				end++
+			}
+			if s.keywords[string(runes[i:end])] {
+				flush(i)
+				out.WriteString(keyword.Render(string(runes[i:end])))
+				plain = end
+			}
+			i = end
+
+		case unicode.IsDigit(r):
+			end := i + 1
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || unicode.IsLetter(runes[end]) || runes[end] == '.' || runes[end] == '_') {
+				end++
+			}
+			flush(i)
+			out.WriteString(number.Render(string(runes[i:end])))
+			i, plain = end, end
+
+		default:
+			i++
+		}
+	}
+	flush(len(runes))
+	return out.String()
+}
+
+// commentAt reports whether a line comment starts at the beginning of
+// runes.
+func (s *syntax) commentAt(runes []rune) bool {
+	for _, c := range s.comments {
+		if strings.HasPrefix(string(runes[:min(len(runes), len(c))]), c) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,862 @@
+package page
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"cosa/internal/display"
+	"cosa/internal/job"
+	"cosa/internal/protocol"
+	"cosa/internal/tui/markdown"
+	"cosa/internal/tui/styles"
+	"cosa/internal/tui/theme"
+	"cosa/internal/tui/util"
+)
+
+// jobFilters are the status filter tabs shown on the jobs page.
+var jobFilters = []string{"all", "draft", "pending", "queued", "running", "review", "needs_attention", "completed", "failed", "cancelled"}
+
+// SavedFilter is a named status and label filter the jobs page can cycle
+// through.
+type SavedFilter struct {
+	Name   string
+	Status string
+	Labels []string // key=value, or key for jobs with the label at all
+}
+
+// Jobs is the jobs page with status filters, search and a detail pane.
+// Search terms written key=value filter by label.
+type Jobs struct {
+	styles styles.Styles
+	width  int
+	height int
+
+	// Data
+	jobs     []protocol.JobInfo
+	filtered []protocol.JobInfo
+
+	// Filtering
+	filterIndex int
+	query       string
+	searchMode  bool
+
+	// Saved filters; savedIndex is -1 when none is applied
+	savedFilters []SavedFilter
+	savedIndex   int
+	savedLabels  map[string]string
+
+	// Selection
+	selected int
+	offset   int
+
+	message string // Result of the last action
+
+	// Callbacks
+	onCancelJob   func(jobID string)
+	onSetPriority func(jobID string, priority int)
+	onReassignJob func(jobID string)
+	onStartReview func(jobID string)
+	onApprove     func(jobID string)
+}
+
+// NewJobs creates a new jobs page.
+func NewJobs() *Jobs {
+	return &Jobs{
+		styles:     styles.New(),
+		savedIndex: -1,
+	}
+}
+
+// SetSavedFilters sets the saved filters the f key cycles through.
+func (p *Jobs) SetSavedFilters(filters []SavedFilter) {
+	p.savedFilters = filters
+	p.savedIndex = -1
+	p.savedLabels = nil
+}
+
+// SetMessage shows the result of an action.
+func (p *Jobs) SetMessage(message string) {
+	p.message = message
+}
+
+// Restyle rebuilds the page's styles from the current theme.
+func (p *Jobs) Restyle() {
+	p.styles = styles.New()
+}
+
+// SetSize sets the page dimensions.
+func (p *Jobs) SetSize(width, height int) {
+	p.width = width
+	p.height = height
+}
+
+// SetOnCancelJob sets the callback for cancelling a job.
+func (p *Jobs) SetOnCancelJob(fn func(jobID string)) {
+	p.onCancelJob = fn
+}
+
+// SetOnSetPriority sets the callback for changing a job's priority.
+func (p *Jobs) SetOnSetPriority(fn func(jobID string, priority int)) {
+	p.onSetPriority = fn
+}
+
+// SetOnReassignJob sets the callback for reassigning a job.
+func (p *Jobs) SetOnReassignJob(fn func(jobID string)) {
+	p.onReassignJob = fn
+}
+
+// SetOnStartReview sets the callback for starting a review of a job.
+func (p *Jobs) SetOnStartReview(fn func(jobID string)) {
+	p.onStartReview = fn
+}
+
+// SetOnApprove sets the callback for approving a job's plan, or a draft.
+func (p *Jobs) SetOnApprove(fn func(jobID string)) {
+	p.onApprove = fn
+}
+
+// SetJobs updates the job list, keeping the current selection when possible.
+func (p *Jobs) SetJobs(jobs []protocol.JobInfo) {
+	var selectedID string
+	if job := p.Selected(); job != nil {
+		selectedID = job.ID
+	}
+
+	p.jobs = make([]protocol.JobInfo, len(jobs))
+	copy(p.jobs, jobs)
+	sort.SliceStable(p.jobs, func(i, j int) bool {
+		return p.jobs[i].CreatedAt > p.jobs[j].CreatedAt
+	})
+
+	p.applyFilter()
+
+	if selectedID != "" {
+		for i, job := range p.filtered {
+			if job.ID == selectedID {
+				p.selected = i
+				break
+			}
+		}
+	}
+	p.ensureVisible()
+}
+
+// Selected returns the currently selected job, or nil.
+func (p *Jobs) Selected() *protocol.JobInfo {
+	if p.selected < 0 || p.selected >= len(p.filtered) {
+		return nil
+	}
+	return &p.filtered[p.selected]
+}
+
+// IsSearchMode returns true if the search input is active.
+func (p *Jobs) IsSearchMode() bool {
+	return p.searchMode
+}
+
+// HandleKey handles key presses. Returns "exit" when the page should close.
+func (p *Jobs) HandleKey(key string) string {
+	// An action's result stays up until the next key
+	p.message = ""
+
+	if p.searchMode {
+		p.handleSearchKey(key)
+		return ""
+	}
+
+	switch key {
+	case "esc", "q":
+		if p.query != "" {
+			p.query = ""
+			p.applyFilter()
+			return ""
+		}
+		return "exit"
+	case "j", "down":
+		if p.selected < len(p.filtered)-1 {
+			p.selected++
+		}
+	case "k", "up":
+		if p.selected > 0 {
+			p.selected--
+		}
+	case "g", "home":
+		p.selected = 0
+	case "G", "end":
+		p.selected = max(len(p.filtered)-1, 0)
+	case "l", "right", "tab":
+		p.filterIndex = (p.filterIndex + 1) % len(jobFilters)
+		p.applyFilter()
+	case "h", "left", "shift+tab":
+		p.filterIndex = (p.filterIndex - 1 + len(jobFilters)) % len(jobFilters)
+		p.applyFilter()
+	case "/":
+		p.searchMode = true
+	case "f":
+		p.cycleSavedFilter()
+	case "x":
+		if job := p.Selected(); job != nil && canCancelJob(job.Status) && p.onCancelJob != nil {
+			p.onCancelJob(job.ID)
+		}
+	case "+", "=":
+		if job := p.Selected(); job != nil && job.Priority < 5 && p.onSetPriority != nil {
+			p.onSetPriority(job.ID, job.Priority+1)
+			job.Priority++
+		}
+	case "-":
+		if job := p.Selected(); job != nil && job.Priority > 1 && p.onSetPriority != nil {
+			p.onSetPriority(job.ID, job.Priority-1)
+			job.Priority--
+		}
+	case "R":
+		if job := p.Selected(); job != nil && canReassignJob(job.Status) && p.onReassignJob != nil {
+			p.onReassignJob(job.ID)
+		}
+	case "v":
+		if job := p.Selected(); job != nil && job.Status == "completed" && p.onStartReview != nil {
+			p.onStartReview(job.ID)
+		}
+	case "a":
+		if job := p.Selected(); job != nil && awaitingApproval(job) && p.onApprove != nil {
+			p.onApprove(job.ID)
+		}
+	}
+
+	p.ensureVisible()
+	return ""
+}
+
+func (p *Jobs) handleSearchKey(key string) {
+	switch key {
+	case "enter":
+		p.searchMode = false
+	case "esc":
+		p.searchMode = false
+		p.query = ""
+	case "backspace":
+		if len(p.query) > 0 {
+			runes := []rune(p.query)
+			p.query = string(runes[:len(runes)-1])
+		}
+	case "ctrl+u":
+		p.query = ""
+	default:
+		if len(key) == 1 || key == " " {
+			p.query += key
+		} else {
+			return
+		}
+	}
+	p.applyFilter()
+}
+
+// cycleSavedFilter applies the next saved filter, or none after the last.
+// A saved filter selects its status tab and filters by its labels.
+func (p *Jobs) cycleSavedFilter() {
+	if len(p.savedFilters) == 0 {
+		return
+	}
+
+	p.savedIndex++
+	if p.savedIndex >= len(p.savedFilters) {
+		p.savedIndex = -1
+		p.savedLabels = nil
+		p.filterIndex = 0
+		p.applyFilter()
+		return
+	}
+
+	f := p.savedFilters[p.savedIndex]
+	p.filterIndex = 0
+	for i, status := range jobFilters {
+		if status == f.Status {
+			p.filterIndex = i
+		}
+	}
+	p.savedLabels, _ = job.ParseLabelSelector(f.Labels)
+	p.applyFilter()
+}
+
+// applyFilter rebuilds the filtered list from the status tab, saved filter
+// and search query.
+func (p *Jobs) applyFilter() {
+	status := jobFilters[p.filterIndex]
+
+	// Search terms with = filter by label, the rest match the description
+	var text []string
+	var labels []string
+	for _, term := range strings.Fields(p.query) {
+		if strings.Contains(term, "=") {
+			labels = append(labels, term)
+		} else {
+			text = append(text, term)
+		}
+	}
+	query := strings.Join(text, " ")
+	searchLabels, _ := job.ParseLabelSelector(labels)
+
+	p.filtered = p.filtered[:0]
+	for _, j := range p.jobs {
+		if status != "all" && j.Status != status {
+			continue
+		}
+		if !job.MatchLabels(j.Labels, p.savedLabels) || !job.MatchLabels(j.Labels, searchLabels) {
+			continue
+		}
+		if query != "" && !fuzzyMatch(query, j.Description) && !strings.HasPrefix(j.ID, query) {
+			continue
+		}
+		p.filtered = append(p.filtered, j)
+	}
+
+	if p.selected >= len(p.filtered) {
+		p.selected = max(len(p.filtered)-1, 0)
+	}
+	p.ensureVisible()
+}
+
+func (p *Jobs) ensureVisible() {
+	visible := p.listHeight()
+	if visible < 1 {
+		visible = 1
+	}
+	if p.selected < p.offset {
+		p.offset = p.selected
+	}
+	if p.selected >= p.offset+visible {
+		p.offset = p.selected - visible + 1
+	}
+}
+
+func (p *Jobs) listHeight() int {
+	// The list panel's borders
+	return p.bodyHeight(p.renderHeader(), p.renderTabs(), p.renderFooter()) - 2
+}
+
+// bodyHeight returns the height left for the list and detail panels.
+func (p *Jobs) bodyHeight(header, tabs, footer string) int {
+	return max(p.height-lipgloss.Height(header)-lipgloss.Height(tabs)-lipgloss.Height(footer), 4)
+}
+
+// View renders the jobs page.
+func (p *Jobs) View() string {
+	t := theme.Current
+
+	header := p.renderHeader()
+	tabs := p.renderTabs()
+	footer := p.renderFooter()
+
+	bodyHeight := p.bodyHeight(header, tabs, footer)
+
+	listWidth := p.width * 55 / 100
+	detailWidth := p.width - listWidth
+
+	list := p.renderList(listWidth, bodyHeight)
+	detail := p.renderDetail(detailWidth, bodyHeight)
+	body := lipgloss.JoinHorizontal(lipgloss.Top, list, detail)
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		header,
+		tabs,
+		body,
+		footer,
+	)
+
+	return lipgloss.NewStyle().
+		Background(t.Background).
+		Width(p.width).
+		Height(p.height).
+		Render(content)
+}
+
+func (p *Jobs) renderHeader() string {
+	t := theme.Current
+
+	title := lipgloss.NewStyle().
+		Foreground(t.Primary).
+		Bold(true).
+		Render("◆ JOBS")
+
+	count := lipgloss.NewStyle().
+		Foreground(t.TextMuted).
+		Render(fmt.Sprintf("%d of %d jobs", len(p.filtered), len(p.jobs)))
+
+	spacerWidth := p.width - lipgloss.Width(title) - lipgloss.Width(count) - 4
+	spacer := strings.Repeat(" ", max(spacerWidth, 1))
+
+	header := fmt.Sprintf(" %s%s%s ", title, spacer, count)
+
+	return lipgloss.NewStyle().
+		Background(t.Surface).
+		Width(p.width).
+		Render(header)
+}
+
+func (p *Jobs) renderTabs() string {
+	t := theme.Current
+
+	activeStyle := lipgloss.NewStyle().
+		Foreground(t.Background).
+		Background(t.Primary).
+		Bold(true).
+		Padding(0, 1)
+	inactiveStyle := lipgloss.NewStyle().
+		Foreground(t.TextMuted).
+		Padding(0, 1)
+
+	counts := make(map[string]int)
+	for _, job := range p.jobs {
+		counts[job.Status]++
+	}
+
+	var tabs []string
+	for i, filter := range jobFilters {
+		label := filter
+		if filter == "all" {
+			label = fmt.Sprintf("%s %d", filter, len(p.jobs))
+		} else if counts[filter] > 0 {
+			label = fmt.Sprintf("%s %d", filter, counts[filter])
+		}
+		if i == p.filterIndex {
+			tabs = append(tabs, activeStyle.Render(label))
+		} else {
+			tabs = append(tabs, inactiveStyle.Render(label))
+		}
+	}
+
+	line := " " + strings.Join(tabs, " ")
+
+	// Saved filter and search input on the right
+	var right []string
+	if p.savedIndex >= 0 {
+		right = append(right, lipgloss.NewStyle().Foreground(t.Secondary).Render("filter: "+p.savedFilters[p.savedIndex].Name))
+	}
+	if p.searchMode || p.query != "" {
+		searchStyle := lipgloss.NewStyle().Foreground(t.Text)
+		if p.searchMode {
+			searchStyle = searchStyle.Foreground(t.Primary)
+		}
+		search := "/" + p.query
+		if p.searchMode {
+			search += "█"
+		}
+		right = append(right, searchStyle.Render(search))
+	}
+	if len(right) > 0 {
+		rightText := strings.Join(right, "  ")
+		spacerWidth := p.width - lipgloss.Width(line) - lipgloss.Width(rightText) - 2
+		line += strings.Repeat(" ", max(spacerWidth, 1)) + rightText
+	}
+
+	return lipgloss.NewStyle().
+		Width(p.width).
+		Render(line)
+}
+
+func (p *Jobs) renderList(width, height int) string {
+	t := theme.Current
+
+	contentWidth := width - 4
+	contentHeight := height - 2
+
+	var lines []string
+	if len(p.filtered) == 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(t.TextMuted).Render(" No matching jobs"))
+	}
+
+	end := min(p.offset+contentHeight, len(p.filtered))
+	for i := p.offset; i < end; i++ {
+		lines = append(lines, p.renderJobLine(p.filtered[i], i == p.selected, contentWidth))
+	}
+
+	for len(lines) < contentHeight {
+		lines = append(lines, "")
+	}
+
+	panel := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.BorderActive).
+		Width(width - 2).
+		Height(contentHeight).
+		Render(strings.Join(lines, "\n"))
+
+	title := lipgloss.NewStyle().Foreground(t.Primary).Bold(true).Render(" JOBS ")
+	return styles.InsertPanelTitle(panel, title, t.BorderActive)
+}
+
+func (p *Jobs) renderJobLine(job protocol.JobInfo, selected bool, width int) string {
+	t := theme.Current
+
+	icon := jobStatusIcon(job.Status)
+	statusStyle := p.styles.StatusStyle(job.Status)
+
+	id := display.ShortID(job.ID)
+
+	prefix := fmt.Sprintf(" %s %s P%d ", icon, id, job.Priority)
+
+	// Labels follow the description when there is room for both
+	tag := ""
+	if labels := labelsText(job.Labels); labels != "" {
+		tag = " [" + labels + "]"
+		if width-lipgloss.Width(prefix)-lipgloss.Width(tag)-1 < 20 {
+			tag = ""
+		}
+	}
+	desc := truncateLine(job.Description, max(width-lipgloss.Width(prefix)-lipgloss.Width(tag)-1, 4))
+
+	if selected {
+		return lipgloss.NewStyle().
+			Foreground(t.Text).
+			Background(t.Surface).
+			Bold(true).
+			Width(width).
+			Render(prefix + desc + tag)
+	}
+
+	return statusStyle.Render(fmt.Sprintf(" %s", icon)) +
+		lipgloss.NewStyle().Foreground(t.TextMuted).Render(fmt.Sprintf(" %s P%d ", id, job.Priority)) +
+		lipgloss.NewStyle().Foreground(t.Text).Render(desc) +
+		lipgloss.NewStyle().Foreground(t.TextMuted).Render(tag)
+}
+
+// labelsText renders labels as key=value pairs in key order.
+func labelsText(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+labels[k])
+	}
+	return strings.Join(parts, ",")
+}
+
+func (p *Jobs) renderDetail(width, height int) string {
+	t := theme.Current
+
+	contentWidth := width - 4
+	contentHeight := height - 2
+
+	labelStyle := lipgloss.NewStyle().Foreground(t.TextMuted)
+	valueStyle := lipgloss.NewStyle().Foreground(t.Text)
+
+	var lines []string
+	job := p.Selected()
+	if job == nil {
+		lines = append(lines, labelStyle.Render(" No job selected"))
+	} else {
+		field := func(label, value string) {
+			if value == "" {
+				value = "-"
+			}
+			lines = append(lines, truncateLine(
+				labelStyle.Render(fmt.Sprintf(" %-10s ", label))+valueStyle.Render(value),
+				contentWidth,
+			))
+		}
+
+		field("ID", job.ID)
+		field("Status", p.styles.StatusStyle(job.Status).Render(job.Status))
+		field("Priority", fmt.Sprintf("%d", job.Priority))
+		field("Worker", job.Worker)
+		if len(job.Labels) > 0 {
+			field("Labels", labelsText(job.Labels))
+		}
+		if job.StickyWorker != "" {
+			field("Sticky", job.StickyWorker)
+		}
+		field("Branch", job.Branch)
+		field("Worktree", job.Worktree)
+		field("Created", formatJobTime(job.CreatedAt))
+		field("Queued", formatJobTime(job.QueuedAt))
+		field("Started", formatJobTime(job.StartedAt))
+		field("Completed", formatJobTime(job.CompletedAt))
+		if job.Timeout > 0 {
+			field("Timeout", formatJobTimeout(job.Timeout, job.Deadline, job.Timeouts))
+		}
+		if job.Status == "needs_attention" {
+			field("Attention", job.AttentionKind)
+			field("Waiting", formatJobTime(job.AttentionSince))
+		}
+
+		lines = append(lines, "")
+		lines = append(lines, labelStyle.Render(" Depends on"))
+		if len(job.DependsOn) == 0 {
+			lines = append(lines, valueStyle.Render("   -"))
+		}
+		for _, dep := range job.DependsOn {
+			lines = append(lines, p.renderDependency(dep, contentWidth))
+		}
+
+		lines = append(lines, "")
+		lines = append(lines, labelStyle.Render(" Description"))
+		for _, l := range wrapDetail(job.Description, contentWidth-3) {
+			lines = append(lines, valueStyle.Render("   "+l))
+		}
+
+		if job.Status == "needs_attention" {
+			warnStyle := lipgloss.NewStyle().Foreground(t.Warning)
+			lines = append(lines, "")
+			lines = append(lines, labelStyle.Render(" Needs attention"))
+			for _, l := range wrapDetail(job.AttentionReason, contentWidth-3) {
+				lines = append(lines, warnStyle.Render("   "+l))
+			}
+		}
+
+		if job.Plan != "" {
+			title := " Plan"
+			if job.PlanApproved {
+				title += " (approved)"
+			}
+			lines = append(lines, "")
+			lines = append(lines, labelStyle.Render(title))
+			for _, l := range markdown.Render(job.Plan, contentWidth-3, valueStyle) {
+				lines = append(lines, "   "+l)
+			}
+		}
+
+		if job.Error != "" {
+			errStyle := lipgloss.NewStyle().Foreground(t.Error)
+			lines = append(lines, "")
+			lines = append(lines, labelStyle.Render(" Error"))
+			for _, l := range wrapDetail(job.Error, contentWidth-3) {
+				lines = append(lines, errStyle.Render("   "+l))
+			}
+		}
+	}
+
+	if len(lines) > contentHeight {
+		lines = lines[:contentHeight]
+	}
+	for len(lines) < contentHeight {
+		lines = append(lines, "")
+	}
+
+	panel := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Border).
+		Width(width - 2).
+		Height(contentHeight).
+		Render(strings.Join(lines, "\n"))
+
+	title := lipgloss.NewStyle().Foreground(t.TextMuted).Render(" DETAIL ")
+	return styles.InsertPanelTitle(panel, title, t.Border)
+}
+
+// renderDependency renders a dependency line with the dependency's status if known.
+func (p *Jobs) renderDependency(depID string, width int) string {
+	t := theme.Current
+
+	short := display.ShortID(depID)
+
+	for _, job := range p.jobs {
+		if job.ID == depID {
+			line := p.styles.StatusStyle(job.Status).Render("   "+jobStatusIcon(job.Status)) +
+				lipgloss.NewStyle().Foreground(t.Text).Render(fmt.Sprintf(" %s %s", short, job.Description))
+			return truncateLine(line, width)
+		}
+	}
+
+	return lipgloss.NewStyle().Foreground(t.TextMuted).Render(fmt.Sprintf("   ? %s (unknown)", short))
+}
+
+func (p *Jobs) renderFooter() string {
+	t := theme.Current
+
+	keys := []struct {
+		key  string
+		desc string
+	}{
+		{"h/l", "filter"},
+		{"j/k", "navigate"},
+		{"/", "search"},
+	}
+	if len(p.savedFilters) > 0 {
+		keys = append(keys, struct {
+			key  string
+			desc string
+		}{"f", "saved filter"})
+	}
+
+	if job := p.Selected(); job != nil {
+		if canCancelJob(job.Status) {
+			keys = append(keys, struct {
+				key  string
+				desc string
+			}{"x", "cancel"})
+		}
+		keys = append(keys, struct {
+			key  string
+			desc string
+		}{"+/-", "priority"})
+		if canReassignJob(job.Status) {
+			keys = append(keys, struct {
+				key  string
+				desc string
+			}{"R", "reassign"})
+		}
+		if job.Status == "completed" {
+			keys = append(keys, struct {
+				key  string
+				desc string
+			}{"v", "review"})
+		}
+		if awaitingApproval(job) {
+			desc := "approve plan"
+			if job.Status == "draft" || job.AttentionKind == "draft" {
+				desc = "approve draft"
+			}
+			keys = append(keys, struct {
+				key  string
+				desc string
+			}{"a", desc})
+		}
+	}
+
+	keys = append(keys, struct {
+		key  string
+		desc string
+	}{"Esc", "back"})
+
+	if p.searchMode {
+		keys = []struct {
+			key  string
+			desc string
+		}{
+			{"Enter", "apply"},
+			{"Esc", "clear"},
+		}
+	}
+
+	var parts []string
+	keyStyle := lipgloss.NewStyle().Foreground(t.Primary).Bold(true)
+	descStyle := lipgloss.NewStyle().Foreground(t.TextMuted)
+
+	for _, k := range keys {
+		parts = append(parts, keyStyle.Render(k.key)+" "+descStyle.Render(k.desc))
+	}
+
+	footer := " " + strings.Join(parts, "  │  ")
+	if p.message != "" {
+		footer = lipgloss.NewStyle().Foreground(t.Text).Render(" "+p.message) + "\n" + footer
+	}
+
+	return lipgloss.NewStyle().
+		Background(t.Surface).
+		Width(p.width).
+		Render(footer)
+}
+
+// fuzzyMatch reports whether all non-space characters of pattern appear in s
+// in order, ignoring case.
+func fuzzyMatch(pattern, s string) bool {
+	pr := []rune(strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, pattern))
+	if len(pr) == 0 {
+		return true
+	}
+
+	i := 0
+	for _, r := range strings.ToLower(s) {
+		if r == pr[i] {
+			i++
+			if i == len(pr) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// awaitingApproval reports whether a job's plan, or the job itself as a
+// draft, is waiting to be approved.
+func awaitingApproval(job *protocol.JobInfo) bool {
+	return job.Status == "draft" ||
+		job.Status == "needs_attention" && (job.AttentionKind == "plan_approval" || job.AttentionKind == "draft")
+}
+
+func canCancelJob(status string) bool {
+	switch status {
+	case "draft", "pending", "queued", "running", "review":
+		return true
+	}
+	return false
+}
+
+func canReassignJob(status string) bool {
+	return status == "failed" || status == "cancelled"
+}
+
+func jobStatusIcon(status string) string {
+	switch status {
+	case "pending":
+		return "○"
+	case "queued":
+		return "◔"
+	case "running":
+		return "●"
+	case "completed":
+		return "✓"
+	case "failed":
+		return "✗"
+	case "cancelled":
+		return "⊘"
+	case "review":
+		return "◎"
+	case "needs_attention":
+		return "⚑"
+	case "draft":
+		return "✎"
+	default:
+		return "?"
+	}
+}
+
+func formatJobTime(unix int64) string {
+	if unix == 0 {
+		return ""
+	}
+	ts := time.Unix(unix, 0)
+	ago := time.Since(ts).Round(time.Second)
+	return fmt.Sprintf("%s (%s ago)", ts.Format("2006-01-02 15:04:05"), ago)
+}
+
+// formatJobTimeout shows a job's timeout, the time left while it runs and
+// how often it has timed out.
+func formatJobTimeout(timeout, deadline int64, timeouts int) string {
+	s := (time.Duration(timeout) * time.Second).String()
+	if deadline > 0 {
+		remaining := time.Until(time.Unix(deadline, 0)).Round(time.Second)
+		if remaining > 0 {
+			s += fmt.Sprintf(" (%s left)", remaining)
+		} else {
+			s += " (overdue)"
+		}
+	}
+	if timeouts > 0 {
+		s += fmt.Sprintf(", timed out %dx", timeouts)
+	}
+	return s
+}
+
+func wrapDetail(text string, width int) []string {
+	if width < 10 {
+		width = 10
+	}
+	var out []string
+	for _, para := range strings.Split(text, "\n") {
+		out = append(out, util.WrapText(para, width)...)
+	}
+	return out
+}
@@ -0,0 +1,84 @@
+package page
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"cosa/internal/protocol"
+)
+
+func manyJobs(n int) []protocol.JobInfo {
+	jobs := make([]protocol.JobInfo, n)
+	for i := range jobs {
+		jobs[i] = protocol.JobInfo{
+			ID:          fmt.Sprintf("job-%04d-0000", i),
+			Status:      "queued",
+			Description: fmt.Sprintf("Task %02d", i),
+			CreatedAt:   int64(i),
+		}
+	}
+	return jobs
+}
+
+// listColumn returns the first width columns of each line of view, where
+// the list panel is drawn.
+func listColumn(view string, width int) string {
+	var lines []string
+	for _, line := range strings.Split(view, "\n") {
+		runes := []rune(line)
+		lines = append(lines, string(runes[:min(width, len(runes))]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestJobs_SelectionStaysVisible(t *testing.T) {
+	// The page is 24 lines: a line each for the header and tabs, the footer,
+	// and the list panel's borders
+	tests := []struct {
+		message string
+		rows    int
+	}{
+		{"", 19},
+		{"Priority set to 3", 18},
+	}
+	for _, tt := range tests {
+		p := NewJobs()
+		p.SetSize(120, 24)
+		p.SetJobs(manyJobs(40))
+
+		// Newest first, so the last job is the oldest
+		p.HandleKey("G")
+		p.SetMessage(tt.message)
+		p.SetJobs(manyJobs(40))
+
+		view := p.View()
+		if h := lipgloss.Height(view); h != 24 {
+			t.Errorf("message %q: expected the page to be 24 lines, got %d", tt.message, h)
+		}
+		list := listColumn(view, 120*55/100)
+		if !strings.Contains(list, "Task 00") {
+			t.Errorf("message %q: expected the selected job in the list:\n%s", tt.message, view)
+		}
+		if n := strings.Count(list, "Task "); n != tt.rows {
+			t.Errorf("message %q: expected the list to fill its %d rows, got %d:\n%s", tt.message, tt.rows, n, view)
+		}
+	}
+}
+
+func TestJobs_MessageClearsOnKey(t *testing.T) {
+	p := NewJobs()
+	p.SetSize(120, 24)
+	p.SetJobs(manyJobs(3))
+
+	p.SetMessage("Priority set to 3")
+	if !strings.Contains(p.View(), "Priority set") {
+		t.Fatal("expected the message in the footer")
+	}
+	p.HandleKey("j")
+	if p.message != "" || strings.Contains(p.View(), "Priority set") {
+		t.Errorf("expected the next key to clear the message, got %q", p.message)
+	}
+}
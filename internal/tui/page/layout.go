@@ -0,0 +1,388 @@
+package page
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"cosa/internal/protocol"
+	"cosa/internal/tui/theme"
+)
+
+// Layout is an arrangement of the dashboard panels for a kind of user.
+type Layout string
+
+const (
+	LayoutDefault  Layout = "default"  // Workers, jobs and activity
+	LayoutOperator Layout = "operator" // Queue, alerts and budget
+	LayoutReviewer Layout = "reviewer" // Reviews and their diffs
+)
+
+// Layouts lists the dashboard layouts in the order they are cycled.
+var Layouts = []Layout{LayoutDefault, LayoutOperator, LayoutReviewer}
+
+// ParseLayout returns the layout with the given name.
+func ParseLayout(name string) (Layout, bool) {
+	for _, l := range Layouts {
+		if string(l) == name {
+			return l, true
+		}
+	}
+	return LayoutDefault, false
+}
+
+// layoutFocus lists the panels each layout can focus, in Tab order.
+var layoutFocus = map[Layout][]FocusArea{
+	LayoutDefault:  {FocusWorkers, FocusJobs, FocusActivity},
+	LayoutOperator: {FocusJobs, FocusWorkers, FocusActivity},
+	LayoutReviewer: {FocusReviews, FocusDiff},
+}
+
+// budgetPanelHeight is the height of the operator layout's budget panel.
+const budgetPanelHeight = 8
+
+// reviewItem is a job the reviewer layout lists: under review, waiting for
+// a human, or unmerged.
+type reviewItem struct {
+	job    protocol.JobInfo
+	review *protocol.ReviewStatusResult // Active review, if any
+}
+
+// reviewItems builds the reviewer layout's list from the jobs and active
+// reviews, with active reviews first.
+func (d *Dashboard) reviewItems() []reviewItem {
+	active := make(map[string]*protocol.ReviewStatusResult, len(d.reviews))
+	for i := range d.reviews {
+		active[d.reviews[i].JobID] = &d.reviews[i]
+	}
+
+	var items []reviewItem
+	for _, j := range d.jobs {
+		r := active[j.ID]
+		if r == nil && j.Status != "review" && j.Status != "needs_attention" && !j.Unmerged {
+			continue
+		}
+		items = append(items, reviewItem{job: j, review: r})
+	}
+
+	sort.SliceStable(items, func(i, k int) bool {
+		if (items[i].review != nil) != (items[k].review != nil) {
+			return items[i].review != nil
+		}
+		return items[i].job.CreatedAt < items[k].job.CreatedAt
+	})
+	return items
+}
+
+// SelectedReviewJob returns the job selected in the reviewer layout.
+func (d *Dashboard) SelectedReviewJob() string {
+	items := d.reviewItems()
+	if d.reviewSelected >= 0 && d.reviewSelected < len(items) {
+		return items[d.reviewSelected].job.ID
+	}
+	return ""
+}
+
+// selectedReviewUnmerged reports whether the selected review job's branch
+// could not be merged.
+func (d *Dashboard) selectedReviewUnmerged() bool {
+	items := d.reviewItems()
+	return d.reviewSelected >= 0 && d.reviewSelected < len(items) && items[d.reviewSelected].job.Unmerged
+}
+
+// SelectedQueueJob returns the unfinished job selected in the operator
+// layout's queue, if the queue is focused.
+func (d *Dashboard) SelectedQueueJob() *protocol.JobInfo {
+	if d.layout != LayoutOperator || d.focus != FocusJobs {
+		return nil
+	}
+	return d.queueList.Selected()
+}
+
+// SetReviews updates the active reviews shown in the reviewer layout.
+func (d *Dashboard) SetReviews(reviews []protocol.ReviewStatusResult) {
+	current := d.SelectedReviewJob()
+	d.reviews = reviews
+	d.keepReviewSelection(current)
+}
+
+// keepReviewSelection keeps the selection on jobID after the list changes.
+func (d *Dashboard) keepReviewSelection(jobID string) {
+	items := d.reviewItems()
+	for i, item := range items {
+		if item.job.ID == jobID {
+			d.reviewSelected = i
+			return
+		}
+	}
+	if d.reviewSelected >= len(items) {
+		d.reviewSelected = max(0, len(items)-1)
+	}
+}
+
+// SetDiff sets the diff shown for the selected review, or the reason it
+// could not be loaded.
+func (d *Dashboard) SetDiff(diff *protocol.JobDiffResult, message string) {
+	if diff != nil && diff.ID != d.SelectedReviewJob() {
+		return // Stale response
+	}
+	if diff == nil || d.diff == nil || d.diff.ID != diff.ID {
+		d.diffScroll = 0
+	}
+	d.diff = diff
+	d.diffMessage = message
+}
+
+// DiffJob returns the job whose diff is shown.
+func (d *Dashboard) DiffJob() string {
+	if d.diff == nil {
+		return ""
+	}
+	return d.diff.ID
+}
+
+// operatorAlerts lists what needs the operator's attention, most urgent
+// first, truncated to width.
+func (d *Dashboard) operatorAlerts(width int) []string {
+	t := theme.Current
+	errStyle := lipgloss.NewStyle().Foreground(t.Error)
+	warnStyle := lipgloss.NewStyle().Foreground(t.Warning)
+
+	var alerts []string
+	add := func(style lipgloss.Style, msg string) {
+		alerts = append(alerts, style.Render(truncateLine(msg, width)))
+	}
+
+	if d.status != nil && d.status.Budget != nil {
+		b := d.status.Budget
+		switch {
+		case b.Exceeded:
+			add(errStyle, fmt.Sprintf("✗ Daily budget exceeded ($%.2f of $%.2f); no new jobs start", b.SpentToday, b.DailyLimit))
+		case b.DailyLimit > 0 && b.SpentToday >= b.DailyLimit*0.8:
+			add(warnStyle, fmt.Sprintf("! Daily budget at %.0f%%", b.SpentToday/b.DailyLimit*100))
+		}
+	}
+
+	for _, w := range d.workers {
+		if w.Status == "error" {
+			add(errStyle, fmt.Sprintf("✗ Worker %s is in error", w.Name))
+		}
+	}
+
+	for _, j := range d.jobs {
+		switch {
+		case j.Status == "needs_attention":
+			reason := j.AttentionReason
+			if reason == "" {
+				reason = "needs attention"
+			}
+			add(warnStyle, fmt.Sprintf("! %s: %s", j.Description, reason))
+		case j.Unmerged:
+			add(warnStyle, "! Merge conflict: "+j.Description)
+		}
+	}
+
+	for _, j := range d.jobs {
+		if j.Status == "failed" {
+			msg := "✗ Failed: " + j.Description
+			if j.Error != "" {
+				msg += ": " + j.Error
+			}
+			add(errStyle, msg)
+		}
+	}
+
+	return alerts
+}
+
+// operatorBudget renders today's spend and the configured limits.
+func (d *Dashboard) operatorBudget(width int) string {
+	t := theme.Current
+	muted := lipgloss.NewStyle().Foreground(t.TextMuted)
+	text := lipgloss.NewStyle().Foreground(t.Text)
+
+	if d.status == nil {
+		return muted.Render("Loading...")
+	}
+
+	var lines []string
+	b := d.status.Budget
+	if b == nil || b.DailyLimit <= 0 {
+		spent := 0.0
+		if b != nil {
+			spent = b.SpentToday
+		}
+		lines = append(lines, text.Render(fmt.Sprintf("Today   $%.2f", spent)), muted.Render("No daily limit"))
+	} else {
+		lines = append(lines, text.Render(fmt.Sprintf("Today   $%.2f of $%.2f", b.SpentToday, b.DailyLimit)))
+
+		barWidth := max(width-6, 4)
+		filled := min(int(b.SpentToday/b.DailyLimit*float64(barWidth)), barWidth)
+		color := t.Success
+		switch {
+		case b.Exceeded:
+			color = t.Error
+		case b.SpentToday >= b.DailyLimit*0.8:
+			color = t.Warning
+		}
+		bar := lipgloss.NewStyle().Foreground(color).Render(strings.Repeat("█", filled)) +
+			muted.Render(strings.Repeat("░", barWidth-filled))
+		lines = append(lines, bar)
+	}
+
+	if b != nil && b.PerWorkerLimit > 0 {
+		lines = append(lines, muted.Render(fmt.Sprintf("Worker  $%.2f limit", b.PerWorkerLimit)))
+	}
+	if b != nil && b.PerJobLimit > 0 {
+		lines = append(lines, muted.Render(fmt.Sprintf("Job     $%.2f limit", b.PerJobLimit)))
+	}
+	if d.status.TotalCost != "" {
+		lines = append(lines, muted.Render(fmt.Sprintf("Total   %s, %d tokens", d.status.TotalCost, d.status.TotalTokens)))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// viewOperator renders the queue and workers beside the budget, alerts and
+// activity.
+func (d *Dashboard) viewOperator() string {
+	leftWidth := d.width * 40 / 100
+	leftHeight := (d.height - 4) / 2
+	rightWidth := d.width - leftWidth - 3
+	rightHeight := d.height - 4
+
+	queuePanel := d.renderPanel(fmt.Sprintf("QUEUE (%d)", len(d.queueList.Jobs())), d.queueList.View(), leftWidth, leftHeight, d.focus == FocusJobs)
+	workersPanel := d.renderPanel("WORKERS", d.workerList.View(), leftWidth, rightHeight-leftHeight, d.focus == FocusWorkers)
+	leftColumn := lipgloss.JoinVertical(lipgloss.Left, queuePanel, workersPanel)
+
+	alerts := d.operatorAlerts(rightWidth - 4)
+	alertsHeight := (rightHeight - budgetPanelHeight) / 2
+	alertsContent := lipgloss.NewStyle().Foreground(theme.Current.TextMuted).Render("Nothing needs attention")
+	if len(alerts) > 0 {
+		alertsContent = strings.Join(alerts, "\n")
+	}
+
+	budgetPanel := d.renderPanel("BUDGET", d.operatorBudget(rightWidth-4), rightWidth, budgetPanelHeight, false)
+	alertsPanel := d.renderPanel(fmt.Sprintf("ALERTS (%d)", len(alerts)), alertsContent, rightWidth, alertsHeight, false)
+	activityPanel := d.renderPanel(d.activityTitle(), d.activity.View(), rightWidth, rightHeight-budgetPanelHeight-alertsHeight, d.focus == FocusActivity)
+	rightColumn := lipgloss.JoinVertical(lipgloss.Left, budgetPanel, alertsPanel, activityPanel)
+
+	gap := lipgloss.NewStyle().Width(1).Height(rightHeight).Render(" ")
+	return lipgloss.JoinHorizontal(lipgloss.Top, leftColumn, gap, rightColumn)
+}
+
+// viewReviewer renders the jobs awaiting review beside the selected job's
+// diff.
+func (d *Dashboard) viewReviewer() string {
+	leftWidth := d.width * 35 / 100
+	rightWidth := d.width - leftWidth - 3
+	height := d.height - 4
+
+	items := d.reviewItems()
+	reviewsPanel := d.renderPanel(fmt.Sprintf("REVIEWS (%d)", len(items)), d.renderReviews(items, leftWidth-4, height-3), leftWidth, height, d.focus == FocusReviews)
+
+	diffTitle := "DIFF"
+	if d.diff != nil {
+		diffTitle = fmt.Sprintf("DIFF vs %s: %d files +%d -%d", d.diff.Target, len(d.diff.Files), d.diff.Additions, d.diff.Deletions)
+	}
+	diffPanel := d.renderPanel(diffTitle, d.renderDiff(rightWidth-4, height-3), rightWidth, height, d.focus == FocusDiff)
+
+	gap := lipgloss.NewStyle().Width(1).Height(height).Render(" ")
+	return lipgloss.JoinHorizontal(lipgloss.Top, reviewsPanel, gap, diffPanel)
+}
+
+func (d *Dashboard) renderReviews(items []reviewItem, width, height int) string {
+	t := theme.Current
+
+	if len(items) == 0 {
+		return lipgloss.NewStyle().Foreground(t.TextMuted).Render("Nothing to review")
+	}
+
+	// Each item takes two lines
+	visible := max(height/2, 1)
+	start := 0
+	if d.reviewSelected >= visible {
+		start = d.reviewSelected - visible + 1
+	}
+	end := min(start+visible, len(items))
+
+	var lines []string
+	for i := start; i < end; i++ {
+		item := items[i]
+
+		state := item.job.Status
+		color := t.TextMuted
+		switch {
+		case item.review != nil:
+			state = "reviewing: " + item.review.Phase
+			if item.review.Decision != "" {
+				state = item.review.Decision
+			}
+			color = t.Info
+		case item.job.Unmerged:
+			state = "unmerged"
+			color = t.Warning
+		case item.job.Status == "needs_attention":
+			color = t.Warning
+		}
+
+		title := truncateLine(item.job.Description, width-2)
+		state = truncateLine(state, width-2)
+		detail := lipgloss.NewStyle().Foreground(color).Render(state)
+		if item.job.Worker != "" && lipgloss.Width(state)+len(item.job.Worker)+3 <= width-2 {
+			detail += lipgloss.NewStyle().Foreground(t.TextMuted).Render(" · " + item.job.Worker)
+		}
+
+		titleStyle := lipgloss.NewStyle().Foreground(t.Text)
+		marker := "  "
+		if i == d.reviewSelected {
+			titleStyle = titleStyle.Foreground(t.Primary).Bold(d.focus == FocusReviews)
+			marker = "▸ "
+		}
+		lines = append(lines, marker+titleStyle.Render(title), "  "+detail)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (d *Dashboard) renderDiff(width, height int) string {
+	t := theme.Current
+	muted := lipgloss.NewStyle().Foreground(t.TextMuted)
+
+	if d.diff == nil {
+		message := d.diffMessage
+		if message == "" {
+			message = "Select a job to see its diff"
+		}
+		return muted.Render(message)
+	}
+	if d.diff.Diff == "" {
+		return muted.Render("No changes against " + d.diff.Target)
+	}
+
+	all := strings.Split(strings.TrimRight(d.diff.Diff, "\n"), "\n")
+	d.diffScroll = min(d.diffScroll, max(0, len(all)-height))
+	end := min(d.diffScroll+height, len(all))
+
+	var lines []string
+	for _, line := range all[d.diffScroll:end] {
+		style := lipgloss.NewStyle().Foreground(t.Text)
+		switch {
+		case strings.HasPrefix(line, "diff --git"):
+			style = style.Foreground(t.Primary).Bold(true)
+		case strings.HasPrefix(line, "@@"):
+			style = style.Foreground(t.Secondary)
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"), strings.HasPrefix(line, "index "):
+			style = style.Foreground(t.TextMuted)
+		case strings.HasPrefix(line, "+"):
+			style = style.Foreground(t.Success)
+		case strings.HasPrefix(line, "-"):
+			style = style.Foreground(t.Error)
+		}
+		lines = append(lines, style.Render(truncateLine(line, width)))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 
+	"cosa/internal/display"
 	"cosa/internal/protocol"
 	"cosa/internal/tui/styles"
 	"cosa/internal/tui/theme"
@@ -311,18 +312,11 @@ func (o *OperationView) renderJobLine(job OperationJobInfo, selected bool, barWi
 
 	// Job ID (first 8 chars)
 	idStyle := lipgloss.NewStyle().Foreground(t.TextMuted)
-	jobID := job.ID
-	if len(jobID) > 8 {
-		jobID = jobID[:8]
-	}
+	jobID := display.ShortID(job.ID)
 
 	// Description (truncated)
 	descStyle := lipgloss.NewStyle().Foreground(t.Text)
-	desc := job.Description
-	maxDescLen := 30
-	if len(desc) > maxDescLen {
-		desc = desc[:maxDescLen-2] + ".."
-	}
+	desc := display.Truncate(job.Description, 30)
 
 	// Progress bar for running jobs
 	progressBar := ""
@@ -0,0 +1,732 @@
+package page
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"cosa/internal/display"
+	"cosa/internal/protocol"
+	"cosa/internal/tui/component"
+	"cosa/internal/tui/styles"
+	"cosa/internal/tui/theme"
+)
+
+// Review overrides a human can make from the review page.
+const (
+	ReviewApprove = "approve" // Approve, completing the job
+	ReviewChanges = "changes" // Reject, queuing a revision with the reason as feedback
+	ReviewReject  = "reject"  // Reject and fail the job
+)
+
+// Review is the review page. It lists the jobs in review and shows the
+// selected job's diff against its merge target file by file, beside what
+// the consigliere found, so the review can be decided from here.
+type Review struct {
+	width  int
+	height int
+
+	jobs     []protocol.JobInfo
+	reviews  map[string]protocol.ReviewStatusResult // Active reviews by job
+	selected int
+
+	diff        *protocol.JobDiffResult
+	diffMessage string // Why there is no diff, e.g. while it loads
+	files       []diffFile
+	file        int // Selected file
+	scroll      int // Diff scroll offset
+
+	// The override being given a reason, if any
+	action string
+	reason *component.Input
+
+	message string // Result of the last action
+}
+
+// diffFile is one file's part of a job's diff.
+type diffFile struct {
+	path      string
+	lines     []string // From the first hunk on
+	additions int
+	deletions int
+	binary    bool
+}
+
+// NewReview creates a new review page.
+func NewReview() *Review {
+	reason := component.NewInput()
+	reason.SetPlaceholder("Reason")
+	return &Review{reason: reason}
+}
+
+// SetSize sets the view dimensions.
+func (r *Review) SetSize(width, height int) {
+	r.width = width
+	r.height = height
+	r.reason.SetWidth(width - 2)
+}
+
+// SetJobs sets the jobs in review from all jobs, keeping the selection on
+// the same job where possible.
+func (r *Review) SetJobs(jobs []protocol.JobInfo) {
+	current := r.SelectedJob()
+
+	var inReview []protocol.JobInfo
+	for _, j := range jobs {
+		if j.Status == "review" {
+			inReview = append(inReview, j)
+		}
+	}
+	sort.SliceStable(inReview, func(i, k int) bool {
+		return inReview[i].CreatedAt < inReview[k].CreatedAt
+	})
+
+	r.jobs = inReview
+	r.selected = 0
+	for i, j := range inReview {
+		if j.ID == current {
+			r.selected = i
+			break
+		}
+	}
+	if r.SelectedJob() != current {
+		r.resetDiff()
+	}
+}
+
+// SetReviews sets the reviews in progress.
+func (r *Review) SetReviews(reviews []protocol.ReviewStatusResult) {
+	r.reviews = make(map[string]protocol.ReviewStatusResult, len(reviews))
+	for _, rv := range reviews {
+		r.reviews[rv.JobID] = rv
+	}
+}
+
+// Select selects jobID if it is in review.
+func (r *Review) Select(jobID string) {
+	for i, j := range r.jobs {
+		if j.ID == jobID {
+			if i != r.selected {
+				r.selected = i
+				r.resetDiff()
+			}
+			return
+		}
+	}
+}
+
+// SelectedJob returns the ID of the selected job, or "" if there is none.
+func (r *Review) SelectedJob() string {
+	if r.selected < len(r.jobs) {
+		return r.jobs[r.selected].ID
+	}
+	return ""
+}
+
+// NeedsDiff reports whether the selected job's diff has yet to be
+// requested.
+func (r *Review) NeedsDiff() bool {
+	return r.SelectedJob() != "" && r.diff == nil && r.diffMessage == ""
+}
+
+// SetDiff sets the selected job's diff, or the reason it has none.
+func (r *Review) SetDiff(jobID string, diff *protocol.JobDiffResult, message string) {
+	if jobID != r.SelectedJob() {
+		return // Stale response
+	}
+
+	current := ""
+	if r.file < len(r.files) {
+		current = r.files[r.file].path
+	}
+
+	r.diff = diff
+	r.diffMessage = message
+	r.files = nil
+	if diff != nil {
+		r.files = splitDiff(diff.Diff)
+	}
+
+	// Stay on the same file when the diff is reloaded
+	r.file = 0
+	for i, f := range r.files {
+		if f.path == current {
+			r.file = i
+			return
+		}
+	}
+	r.scroll = 0
+}
+
+// MarkDiffLoading notes that the selected job's diff was requested.
+func (r *Review) MarkDiffLoading() {
+	r.diffMessage = "Loading diff..."
+}
+
+// Reason returns the reason given for the last override.
+func (r *Review) Reason() string {
+	return strings.TrimSpace(r.reason.Value())
+}
+
+// SetMessage shows the result of an action.
+func (r *Review) SetMessage(message string) {
+	r.message = message
+}
+
+func (r *Review) resetDiff() {
+	r.diff = nil
+	r.diffMessage = ""
+	r.files = nil
+	r.file = 0
+	r.scroll = 0
+	r.cancelAction()
+}
+
+func (r *Review) cancelAction() {
+	r.action = ""
+	r.reason.Reset()
+	r.reason.Blur()
+}
+
+// HandleKey handles key presses. Returns "exit" to leave the page, "select"
+// when another job was selected, "refresh", "start" to start a review, or
+// one of the overrides ReviewApprove, ReviewChanges and ReviewReject once
+// its reason has been entered.
+func (r *Review) HandleKey(key string) string {
+	if r.action != "" {
+		switch key {
+		case "esc":
+			r.cancelAction()
+			r.message = "Cancelled"
+		case "enter":
+			if r.Reason() == "" {
+				r.message = "A reason is required"
+				return ""
+			}
+			action := r.action
+			r.action = ""
+			r.reason.Blur()
+			return action
+		default:
+			r.reason.HandleKey(key)
+		}
+		return ""
+	}
+
+	switch key {
+	case "esc", "q":
+		return "exit"
+	case "tab", "n":
+		if len(r.jobs) > 1 {
+			r.selected = (r.selected + 1) % len(r.jobs)
+			r.resetDiff()
+			return "select"
+		}
+	case "shift+tab", "p":
+		if len(r.jobs) > 1 {
+			r.selected = (r.selected - 1 + len(r.jobs)) % len(r.jobs)
+			r.resetDiff()
+			return "select"
+		}
+	case "j", "down":
+		if r.file < len(r.files)-1 {
+			r.file++
+			r.scroll = 0
+		}
+	case "k", "up":
+		if r.file > 0 {
+			r.file--
+			r.scroll = 0
+		}
+	case "J", "pgdown":
+		r.scroll += max(1, r.diffHeight()/2)
+	case "K", "pgup":
+		r.scroll = max(0, r.scroll-max(1, r.diffHeight()/2))
+	case "g":
+		r.scroll = 0
+	case "r":
+		r.diff = nil
+		r.diffMessage = ""
+		return "refresh"
+	case "s":
+		if r.SelectedJob() != "" {
+			return "start"
+		}
+	case "a":
+		r.beginAction(ReviewApprove)
+	case "c":
+		r.beginAction(ReviewChanges)
+	case "x":
+		r.beginAction(ReviewReject)
+	}
+	return ""
+}
+
+// beginAction asks for the reason for an override of the selected job.
+func (r *Review) beginAction(action string) {
+	if r.SelectedJob() == "" {
+		return
+	}
+	r.action = action
+	r.message = ""
+	r.reason.Reset()
+	r.reason.Focus()
+}
+
+// View renders the review page.
+func (r *Review) View() string {
+	t := theme.Current
+
+	if len(r.jobs) == 0 {
+		message := "No jobs in review\n\nPress Esc to go back"
+		if r.message != "" {
+			message = r.message + "\n\n" + message
+		}
+		return lipgloss.NewStyle().
+			Width(r.width).
+			Height(r.height).
+			Align(lipgloss.Center, lipgloss.Center).
+			Foreground(t.TextMuted).
+			Render(message)
+	}
+
+	header := r.renderHeader()
+	footer := r.renderFooter()
+	contentHeight := r.height - lipgloss.Height(header) - lipgloss.Height(footer)
+
+	leftWidth := max(r.width*30/100, 24)
+	rightWidth := r.width - leftWidth
+	filesHeight := max(contentHeight/2, 4)
+	findingsHeight := contentHeight - filesHeight
+
+	left := lipgloss.JoinVertical(lipgloss.Left,
+		r.renderFiles(leftWidth, filesHeight),
+		r.renderFindings(leftWidth, findingsHeight),
+	)
+	body := lipgloss.JoinHorizontal(lipgloss.Top, left, r.renderDiff(rightWidth, contentHeight))
+
+	return lipgloss.NewStyle().
+		Background(t.Background).
+		Width(r.width).
+		Height(r.height).
+		Render(lipgloss.JoinVertical(lipgloss.Left, header, body, footer))
+}
+
+// diffHeight returns how many diff lines the page shows at once.
+func (r *Review) diffHeight() int {
+	return max(r.height-8, 1)
+}
+
+func (r *Review) renderHeader() string {
+	t := theme.Current
+	j := r.jobs[r.selected]
+
+	titleStyle := lipgloss.NewStyle().Foreground(t.Primary).Bold(true)
+	mutedStyle := lipgloss.NewStyle().Foreground(t.TextMuted)
+	textStyle := lipgloss.NewStyle().Foreground(t.Text)
+
+	line1 := fmt.Sprintf(" %s %s  %s",
+		titleStyle.Render("REVIEW"),
+		mutedStyle.Render(fmt.Sprintf("[%d/%d]", r.selected+1, len(r.jobs))),
+		textStyle.Render(truncateLine(display.ShortID(j.ID)+" "+j.Description, r.width-20)),
+	)
+
+	parts := []string{valueOr(j.Worker, "unassigned")}
+	if r.diff != nil {
+		parts = append(parts,
+			fmt.Sprintf("%s → %s", valueOr(j.Branch, "worktree"), r.diff.Target),
+			fmt.Sprintf("%d files +%d -%d", len(r.diff.Files), r.diff.Additions, r.diff.Deletions))
+	}
+	if j.RevisionRound > 0 {
+		parts = append(parts, fmt.Sprintf("revision %d", j.RevisionRound))
+	}
+	line2 := " " + strings.Join(parts, "  │  ")
+
+	return lipgloss.NewStyle().
+		Background(t.Surface).
+		Width(r.width).
+		Render(lipgloss.JoinVertical(lipgloss.Left, line1, mutedStyle.Render(truncateLine(line2, r.width))))
+}
+
+func (r *Review) renderFiles(width, height int) string {
+	t := theme.Current
+	muted := lipgloss.NewStyle().Foreground(t.TextMuted)
+
+	contentHeight := max(height-2, 1)
+	var lines []string
+
+	switch {
+	case r.diff == nil:
+		lines = append(lines, muted.Render(" "+valueOr(r.diffMessage, "Loading...")))
+	case len(r.files) == 0:
+		lines = append(lines, muted.Render(" No changes against "+r.diff.Target))
+	default:
+		rows := fileTree(r.files)
+
+		// Keep the selected file visible
+		selectedRow := 0
+		for i, row := range rows {
+			if row.file == r.file {
+				selectedRow = i
+				break
+			}
+		}
+		start := 0
+		if selectedRow >= contentHeight {
+			start = selectedRow - contentHeight + 1
+		}
+		end := min(start+contentHeight, len(rows))
+
+		for _, row := range rows[start:end] {
+			indent := strings.Repeat("  ", row.depth)
+			if row.file < 0 {
+				lines = append(lines, muted.Render(truncateLine(" "+indent+row.name+"/", width-4)))
+				continue
+			}
+
+			f := r.files[row.file]
+			stats := fmt.Sprintf(" +%d -%d", f.additions, f.deletions)
+			if f.binary {
+				stats = " bin"
+			}
+			style := lipgloss.NewStyle().Foreground(t.Text)
+			marker := "  "
+			if row.file == r.file {
+				style = style.Foreground(t.Primary).Bold(true)
+				marker = "▸ "
+			}
+			name := truncateLine(indent+marker+row.name, width-5-len(stats))
+			lines = append(lines, " "+style.Render(name)+muted.Render(stats))
+		}
+	}
+
+	for len(lines) < contentHeight {
+		lines = append(lines, "")
+	}
+
+	return r.panel(" FILES ", lines, width, contentHeight, true)
+}
+
+// treeRow is a line of the file tree: a directory, or a file of the diff.
+type treeRow struct {
+	name  string
+	depth int
+	file  int // Index in the diff's files, or -1 for a directory
+}
+
+// fileTree lays the diff's files, sorted by path, out as a tree, each
+// directory listed once above the files in it.
+func fileTree(files []diffFile) []treeRow {
+	var rows []treeRow
+	var open []string // Directories of the previous file
+	for i := range files {
+		dirs := strings.Split(path.Dir(files[i].path), "/")
+		if dirs[0] == "." {
+			dirs = nil
+		}
+
+		shared := 0
+		for shared < len(dirs) && shared < len(open) && dirs[shared] == open[shared] {
+			shared++
+		}
+		for d := shared; d < len(dirs); d++ {
+			rows = append(rows, treeRow{name: dirs[d], depth: d, file: -1})
+		}
+		rows = append(rows, treeRow{name: path.Base(files[i].path), depth: len(dirs), file: i})
+		open = dirs
+	}
+	return rows
+}
+
+func (r *Review) renderFindings(width, height int) string {
+	t := theme.Current
+	muted := lipgloss.NewStyle().Foreground(t.TextMuted)
+	text := lipgloss.NewStyle().Foreground(t.Text)
+
+	contentHeight := max(height-2, 1)
+	j := r.jobs[r.selected]
+
+	var lines []string
+	add := func(style lipgloss.Style, s string) {
+		for _, l := range wrapText(s, width-5) {
+			lines = append(lines, " "+style.Render(l))
+		}
+	}
+	decision := func(d string) {
+		switch d {
+		case "approved":
+			add(lipgloss.NewStyle().Foreground(t.Success).Bold(true), "Approved")
+		case "rejected":
+			add(lipgloss.NewStyle().Foreground(t.Error).Bold(true), "Changes requested")
+		}
+	}
+
+	checklist := j.ChecklistResults
+	if rv, active := r.reviews[j.ID]; active {
+		add(lipgloss.NewStyle().Foreground(t.Info), "Reviewing: "+rv.Phase)
+		decision(rv.Decision)
+		if rv.Summary != "" {
+			add(text, rv.Summary)
+		}
+		if rv.Feedback != "" {
+			add(muted, rv.Feedback)
+		}
+		if rv.Error != "" {
+			add(lipgloss.NewStyle().Foreground(t.Error), rv.Error)
+		}
+		if len(rv.Checklist) > 0 {
+			checklist = rv.Checklist
+		}
+	} else if v := j.Verdict; v != nil {
+		decision(v.Decision)
+		if v.Summary != "" {
+			add(text, v.Summary)
+		}
+		for _, item := range v.MustFix {
+			add(lipgloss.NewStyle().Foreground(t.Warning), "• "+item)
+		}
+	} else {
+		add(muted, "Not reviewed yet; press s to start a review")
+	}
+
+	if len(checklist) > 0 {
+		lines = append(lines, "")
+		for _, item := range checklist {
+			mark, color := "✓", t.Success
+			if !item.Passed {
+				mark, color = "✗", t.Error
+			}
+			entry := item.Item
+			if item.Note != "" {
+				entry += ": " + item.Note
+			}
+			add(lipgloss.NewStyle().Foreground(color), mark+" "+entry)
+		}
+	}
+
+	if len(lines) > contentHeight {
+		lines = append(lines[:contentHeight-1], muted.Render(" …"))
+	}
+	for len(lines) < contentHeight {
+		lines = append(lines, "")
+	}
+
+	return r.panel(" FINDINGS ", lines, width, contentHeight, false)
+}
+
+func (r *Review) renderDiff(width, height int) string {
+	t := theme.Current
+	muted := lipgloss.NewStyle().Foreground(t.TextMuted)
+
+	contentHeight := max(height-2, 1)
+	title := " DIFF "
+
+	var lines []string
+	if r.file < len(r.files) {
+		f := r.files[r.file]
+		title = fmt.Sprintf(" %s ", f.path)
+
+		if f.binary {
+			lines = append(lines, muted.Render(" Binary file changed"))
+		} else {
+			r.scroll = min(r.scroll, max(0, len(f.lines)-contentHeight))
+			end := min(r.scroll+contentHeight, len(f.lines))
+			lang := syntaxFor(f.path)
+			for _, line := range f.lines[r.scroll:end] {
+				lines = append(lines, renderDiffLine(line, lang, width-4))
+			}
+		}
+	}
+	for len(lines) < contentHeight {
+		lines = append(lines, "")
+	}
+
+	return r.panel(title, lines, width, contentHeight, false)
+}
+
+// renderDiffLine colors a line of a unified diff: the hunk headers, and the
+// code of the lines added, removed and kept, highlighted for its language.
+func renderDiffLine(line string, lang *syntax, width int) string {
+	t := theme.Current
+
+	line = truncateLine(strings.ReplaceAll(line, "\t", "    "), width)
+	if line == "" {
+		return ""
+	}
+
+	switch line[0] {
+	case '@':
+		return lipgloss.NewStyle().Foreground(t.Secondary).Render(line)
+	case '+':
+		marker := lipgloss.NewStyle().Foreground(t.Success).Bold(true).Render("+")
+		return marker + lang.highlight(line[1:], lipgloss.NewStyle().Foreground(t.Text).Background(t.SurfaceLight))
+	case '-':
+		marker := lipgloss.NewStyle().Foreground(t.Error).Bold(true).Render("-")
+		return marker + lipgloss.NewStyle().Foreground(t.TextDim).Render(line[1:])
+	case '\\':
+		return lipgloss.NewStyle().Foreground(t.TextDim).Render(line)
+	default:
+		return " " + lang.highlight(line[1:], lipgloss.NewStyle().Foreground(t.TextMuted))
+	}
+}
+
+// panel draws lines in a bordered panel with title.
+func (r *Review) panel(title string, lines []string, width, contentHeight int, active bool) string {
+	t := theme.Current
+
+	borderColor := t.Border
+	titleStyle := lipgloss.NewStyle().Foreground(t.TextMuted)
+	if active {
+		borderColor = t.BorderActive
+		titleStyle = lipgloss.NewStyle().Foreground(t.Primary).Bold(true)
+	}
+
+	panel := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(borderColor).
+		Width(width - 2).
+		Height(contentHeight).
+		Render(strings.Join(lines, "\n"))
+
+	return styles.InsertPanelTitle(panel, titleStyle.Render(truncateLine(title, width-6)), borderColor)
+}
+
+func (r *Review) renderFooter() string {
+	t := theme.Current
+
+	if r.action != "" {
+		prompts := map[string]string{
+			ReviewApprove: "Approve because:",
+			ReviewChanges: "Request changes (sent to the worker as feedback):",
+			ReviewReject:  "Reject and fail the job because:",
+		}
+		prompt := lipgloss.NewStyle().Foreground(t.Warning).Bold(true).Render(" " + prompts[r.action])
+		hint := lipgloss.NewStyle().Foreground(t.TextMuted).Render("  Enter to confirm, Esc to cancel")
+		footer := prompt + hint + "\n" + r.reason.View()
+		if r.message != "" {
+			footer = lipgloss.NewStyle().Foreground(t.Text).Render(" "+r.message) + "\n" + footer
+		}
+		return lipgloss.NewStyle().
+			Background(t.Surface).
+			Width(r.width).
+			Render(footer)
+	}
+
+	keys := []struct {
+		key  string
+		desc string
+	}{
+		{"j/k", "file"},
+		{"J/K", "scroll"},
+		{"n/p", "job"},
+		{"a", "approve"},
+		{"c", "request changes"},
+		{"x", "reject"},
+		{"s", "start review"},
+		{"Esc", "back"},
+	}
+
+	var parts []string
+	keyStyle := lipgloss.NewStyle().Foreground(t.Primary).Bold(true)
+	descStyle := lipgloss.NewStyle().Foreground(t.TextMuted)
+	for _, k := range keys {
+		parts = append(parts, keyStyle.Render(k.key)+" "+descStyle.Render(k.desc))
+	}
+
+	footer := " " + strings.Join(parts, "  │  ")
+	if r.message != "" {
+		footer = lipgloss.NewStyle().Foreground(t.Text).Render(" "+r.message) + "\n" + footer
+	}
+
+	return lipgloss.NewStyle().
+		Background(t.Surface).
+		Width(r.width).
+		Render(footer)
+}
+
+// splitDiff splits a unified diff into its files, sorted by path.
+func splitDiff(diff string) []diffFile {
+	var files []diffFile
+	var cur *diffFile
+	inHunks := false
+
+	for _, line := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			files = append(files, diffFile{path: diffPath(line)})
+			cur = &files[len(files)-1]
+			inHunks = false
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			inHunks = true
+		case !inHunks:
+			// The file's header: mode and index lines, the names and
+			// whether it is binary
+			if strings.HasPrefix(line, "+++ ") && line != "+++ /dev/null" {
+				cur.path = strings.TrimPrefix(line[4:], "b/")
+			}
+			if strings.HasPrefix(line, "Binary files ") {
+				cur.binary = true
+			}
+			continue
+		case strings.HasPrefix(line, "+"):
+			cur.additions++
+		case strings.HasPrefix(line, "-"):
+			cur.deletions++
+		}
+		cur.lines = append(cur.lines, line)
+	}
+
+	sort.SliceStable(files, func(i, k int) bool {
+		return files[i].path < files[k].path
+	})
+	return files
+}
+
+// diffPath returns the path a "diff --git a/x b/y" line names, the new one
+// for a rename.
+func diffPath(line string) string {
+	line = strings.TrimPrefix(line, "diff --git ")
+	if i := strings.LastIndex(line, " b/"); i >= 0 {
+		return line[i+3:]
+	}
+	return line
+}
+
+// wrapText breaks s into lines of at most width characters at spaces.
+func wrapText(s string, width int) []string {
+	width = max(width, 10)
+
+	var lines []string
+	for _, para := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			if line != "" && len([]rune(line))+1+len([]rune(word)) > width {
+				lines = append(lines, line)
+				line = ""
+			}
+			if line != "" {
+				line += " "
+			}
+			line += word
+		}
+		lines = append(lines, truncateLine(line, width))
+	}
+	return lines
+}
+
+// valueOr returns s, or fallback if s is empty.
+func valueOr(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
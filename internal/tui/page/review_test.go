@@ -0,0 +1,205 @@
+package page
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"cosa/internal/protocol"
+)
+
+const sampleDiff = `diff --git a/internal/b.go b/internal/b.go
+index 1111111..2222222 100644
+--- a/internal/b.go
++++ b/internal/b.go
+@@ -1,3 +1,3 @@
+ package internal
+-var x = 1
++var x = 2
++var y = 3
+diff --git a/README.md b/README.md
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/README.md
+@@ -0,0 +1 @@
++# Title
+diff --git a/old.txt b/old.txt
+deleted file mode 100644
+index 4444444..0000000
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-gone
+diff --git a/logo.png b/logo.png
+index 5555555..6666666 100644
+Binary files a/logo.png and b/logo.png differ
+diff --git a/internal/a.go b/internal/sub/a.go
+similarity index 90%
+rename from internal/a.go
+rename to internal/sub/a.go
+`
+
+func TestSplitDiff(t *testing.T) {
+	files := splitDiff(sampleDiff)
+
+	type summary struct {
+		path      string
+		additions int
+		deletions int
+		binary    bool
+	}
+	var got []summary
+	for _, f := range files {
+		got = append(got, summary{f.path, f.additions, f.deletions, f.binary})
+	}
+	want := []summary{
+		{"README.md", 1, 0, false},
+		{"internal/b.go", 2, 1, false},
+		{"internal/sub/a.go", 0, 0, false},
+		{"logo.png", 0, 0, true},
+		{"old.txt", 0, 1, false},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitDiff() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// A file's lines start at its first hunk
+	if lines := files[1].lines; len(lines) == 0 || !strings.HasPrefix(lines[0], "@@") {
+		t.Errorf("expected internal/b.go's lines to start at its hunk, got %q", lines)
+	}
+	if splitDiff("") != nil {
+		t.Error("expected no files for an empty diff")
+	}
+}
+
+func TestFileTree(t *testing.T) {
+	files := []diffFile{
+		{path: "README.md"},
+		{path: "internal/a.go"},
+		{path: "internal/sub/b.go"},
+		{path: "internal/z.go"},
+	}
+	got := fileTree(files)
+	want := []treeRow{
+		{name: "README.md", depth: 0, file: 0},
+		{name: "internal", depth: 0, file: -1},
+		{name: "a.go", depth: 1, file: 1},
+		{name: "sub", depth: 1, file: -1},
+		{name: "b.go", depth: 2, file: 2},
+		{name: "z.go", depth: 1, file: 3},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("fileTree() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func reviewJobs() []protocol.JobInfo {
+	return []protocol.JobInfo{
+		{ID: "job-running", Status: "running", CreatedAt: 1},
+		{ID: "job-later", Status: "review", CreatedAt: 3, Description: "Later"},
+		{ID: "job-first", Status: "review", CreatedAt: 2, Description: "First"},
+	}
+}
+
+func TestReview_SetJobs(t *testing.T) {
+	r := NewReview()
+	r.SetJobs(reviewJobs())
+
+	if r.SelectedJob() != "job-first" {
+		t.Fatalf("expected the oldest job in review selected, got %q", r.SelectedJob())
+	}
+	if len(r.jobs) != 2 {
+		t.Errorf("expected only jobs in review, got %d", len(r.jobs))
+	}
+
+	r.Select("job-later")
+	r.SetDiff("job-later", &protocol.JobDiffResult{Target: "main", Diff: sampleDiff}, "")
+	r.SetJobs(reviewJobs())
+	if r.SelectedJob() != "job-later" || r.NeedsDiff() {
+		t.Errorf("expected a refresh to keep the selection and its diff, got %q (needs diff %v)", r.SelectedJob(), r.NeedsDiff())
+	}
+
+	// A response for a job no longer selected is dropped
+	r.SetDiff("job-first", nil, "stale")
+	if r.diff == nil {
+		t.Error("expected a stale diff response to be ignored")
+	}
+
+	// The selected job leaving review drops its diff
+	jobs := reviewJobs()
+	jobs[1].Status = "completed"
+	r.SetJobs(jobs)
+	if r.SelectedJob() != "job-first" || !r.NeedsDiff() {
+		t.Errorf("expected job-first selected with its diff still to load, got %q (needs diff %v)", r.SelectedJob(), r.NeedsDiff())
+	}
+}
+
+func TestReview_OverrideNeedsReason(t *testing.T) {
+	r := NewReview()
+	r.SetJobs(reviewJobs())
+
+	if got := r.HandleKey("a"); got != "" {
+		t.Fatalf("expected approving to ask for a reason first, got %q", got)
+	}
+	if got := r.HandleKey("enter"); got != "" || r.message != "A reason is required" {
+		t.Fatalf("expected an empty reason to be refused, got %q, message %q", got, r.message)
+	}
+	for _, key := range []string{"o", "k"} {
+		r.HandleKey(key)
+	}
+	if got := r.HandleKey("enter"); got != ReviewApprove {
+		t.Fatalf("expected %q, got %q", ReviewApprove, got)
+	}
+	if r.Reason() != "ok" {
+		t.Errorf("expected reason ok, got %q", r.Reason())
+	}
+
+	r.HandleKey("x")
+	if got := r.HandleKey("esc"); got != "" || r.action != "" {
+		t.Errorf("expected esc to cancel the rejection, got %q, action %q", got, r.action)
+	}
+	if got := r.HandleKey("esc"); got != "exit" {
+		t.Errorf("expected esc to leave the page once nothing is pending, got %q", got)
+	}
+}
+
+func TestReview_Navigation(t *testing.T) {
+	r := NewReview()
+	r.SetSize(100, 30)
+	r.SetJobs(reviewJobs())
+	r.SetDiff("job-first", &protocol.JobDiffResult{Target: "main", Diff: sampleDiff}, "")
+
+	r.HandleKey("j")
+	r.HandleKey("j")
+	if r.files[r.file].path != "internal/sub/a.go" {
+		t.Errorf("expected the third file selected, got %s", r.files[r.file].path)
+	}
+
+	// Reloading the diff stays on the same file
+	r.SetDiff("job-first", &protocol.JobDiffResult{Target: "main", Diff: sampleDiff}, "")
+	if r.files[r.file].path != "internal/sub/a.go" {
+		t.Errorf("expected a reload to keep the file, got %s", r.files[r.file].path)
+	}
+
+	if got := r.HandleKey("tab"); got != "select" || r.SelectedJob() != "job-later" || !r.NeedsDiff() {
+		t.Errorf("expected tab to select the next job and drop the diff, got %q, %q", got, r.SelectedJob())
+	}
+}
+
+func TestReview_ViewFitsSize(t *testing.T) {
+	r := NewReview()
+	r.SetSize(100, 30)
+	if h := lipgloss.Height(r.View()); h != 30 {
+		t.Errorf("expected the empty page to be 30 lines, got %d", h)
+	}
+
+	r.SetJobs(reviewJobs())
+	r.SetDiff("job-first", &protocol.JobDiffResult{Target: "main", Files: []string{"README.md"}, Diff: sampleDiff}, "")
+	r.SetMessage("Job job-first approved")
+	if h := lipgloss.Height(r.View()); h != 30 {
+		t.Errorf("expected the page to be 30 lines, got %d", h)
+	}
+}
@@ -4,9 +4,11 @@ package page
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 
+	"cosa/internal/display"
 	"cosa/internal/protocol"
 	"cosa/internal/tui/styles"
 	"cosa/internal/tui/theme"
@@ -87,6 +89,64 @@ func (w *WorkerDetail) AppendSessionOutput(line string) {
 	}
 }
 
+// Worker returns the worker being displayed.
+func (w *WorkerDetail) Worker() *protocol.WorkerDetailInfo {
+	return w.worker
+}
+
+// AppendTranscript appends transcript entries to the session output,
+// keeping the view pinned to the bottom if it already was.
+func (w *WorkerDetail) AppendTranscript(entries []protocol.TranscriptEntry) {
+	atBottom := w.sessionScroll >= max(0, len(w.sessionLines)-10)
+
+	for _, e := range entries {
+		for _, line := range formatTranscriptEntry(e) {
+			w.AppendSessionOutput(line)
+		}
+	}
+
+	if atBottom {
+		w.sessionScroll = max(0, len(w.sessionLines)-10)
+	} else if w.sessionScroll > len(w.sessionLines) {
+		w.sessionScroll = max(0, len(w.sessionLines)-10)
+	}
+}
+
+// formatTranscriptEntry renders a transcript entry as session output lines.
+func formatTranscriptEntry(e protocol.TranscriptEntry) []string {
+	ts := time.Unix(e.Time, 0).Format("15:04:05")
+
+	switch e.Type {
+	case "assistant_text":
+		var lines []string
+		for i, line := range strings.Split(strings.TrimRight(e.Message, "\n"), "\n") {
+			if i == 0 {
+				lines = append(lines, ts+" "+line)
+			} else {
+				lines = append(lines, "         "+line)
+			}
+		}
+		return lines
+	case "tool_use":
+		return []string{fmt.Sprintf("%s → %s %s", ts, e.Tool, strings.ReplaceAll(e.Input, "\n", " "))}
+	case "tool_result":
+		if e.Error != "" {
+			return []string{fmt.Sprintf("%s ← %s failed: %s", ts, e.Tool, e.Error)}
+		}
+		return []string{fmt.Sprintf("%s ← %s", ts, e.Tool)}
+	case "result":
+		if e.Error != "" {
+			return []string{fmt.Sprintf("%s ✗ Session failed: %s", ts, e.Error)}
+		}
+		return []string{ts + " ✓ Session finished"}
+	case "error":
+		return []string{fmt.Sprintf("%s ✗ %s", ts, e.Error)}
+	case "init":
+		return []string{ts + " Session started"}
+	}
+	return nil
+}
+
 // IsInputMode returns true if in message input mode.
 func (w *WorkerDetail) IsInputMode() bool {
 	return w.inputMode
@@ -115,14 +175,20 @@ func (w *WorkerDetail) GetInput() string {
 	return msg
 }
 
-// HandleKey handles key presses.
-func (w *WorkerDetail) HandleKey(key string) {
+// HandleKey handles key presses. Returns "exit" to leave the page and
+// "send" when a message has been entered.
+func (w *WorkerDetail) HandleKey(key string) string {
 	if w.inputMode {
+		if key == "enter" {
+			return "send"
+		}
 		w.handleInputKey(key)
-		return
+		return ""
 	}
 
 	switch key {
+	case "esc", "q":
+		return "exit"
 	case "tab":
 		w.focusSection = (w.focusSection + 1) % 2
 	case "j", "down":
@@ -136,6 +202,7 @@ func (w *WorkerDetail) HandleKey(key string) {
 	case "m":
 		w.StartInput()
 	}
+	return ""
 }
 
 func (w *WorkerDetail) handleInputKey(key string) {
@@ -224,7 +291,7 @@ func (w *WorkerDetail) View() string {
 	header := w.renderHeader()
 
 	// Calculate section heights
-	contentHeight := w.height - 6 // Account for header and footer
+	contentHeight := w.height - 6 - len(w.worker.Slots) // Account for header and footer
 	activityHeight := contentHeight / 3
 	sessionHeight := contentHeight - activityHeight
 
@@ -293,13 +360,18 @@ func (w *WorkerDetail) renderHeader() string {
 		Foreground(t.TextMuted)
 
 	var currentJob string
-	if w.worker.CurrentJob != "" {
-		jobDisplay := w.worker.CurrentJob
-		if len(jobDisplay) > 8 {
-			jobDisplay = jobDisplay[:8]
+	switch {
+	case len(w.worker.Slots) > 0:
+		busy := 0
+		for _, slot := range w.worker.Slots {
+			if slot.Status == "busy" {
+				busy++
+			}
 		}
-		currentJob = fmt.Sprintf("Job: %s", jobDisplay)
-	} else {
+		currentJob = fmt.Sprintf("%d of %d slots busy", busy, len(w.worker.Slots))
+	case w.worker.CurrentJob != "":
+		currentJob = fmt.Sprintf("Job: %s", display.ShortID(w.worker.CurrentJob))
+	default:
 		currentJob = "No active job"
 	}
 
@@ -317,12 +389,24 @@ func (w *WorkerDetail) renderHeader() string {
 		statusStyle.Render(strings.ToUpper(w.worker.Status)),
 	)
 
-	line2 := fmt.Sprintf(" %s%s",
+	// How long since the running job last got anywhere
+	progressInfo := ""
+	if w.worker.CurrentJob != "" && w.worker.LastProgress > 0 {
+		since := time.Since(time.Unix(w.worker.LastProgress, 0)).Round(time.Second)
+		progressInfo = fmt.Sprintf(" │ progress %s ago (%s)", since, w.worker.Progress)
+	}
+
+	line2 := fmt.Sprintf(" %s%s%s",
 		jobStyle.Render(currentJob),
 		costStyle.Render(costInfo),
+		jobStyle.Render(progressInfo),
 	)
 
-	header := lipgloss.JoinVertical(lipgloss.Left, line1, line2)
+	lines := []string{line1, line2}
+	for _, slot := range w.worker.Slots {
+		lines = append(lines, w.renderSlot(slot))
+	}
+	header := lipgloss.JoinVertical(lipgloss.Left, lines...)
 
 	return lipgloss.NewStyle().
 		Background(t.Surface).
@@ -331,6 +415,27 @@ func (w *WorkerDetail) renderHeader() string {
 		Render(header)
 }
 
+// renderSlot renders a line for one of the jobs the worker runs at once.
+func (w *WorkerDetail) renderSlot(slot protocol.SlotDetail) string {
+	t := theme.Current
+	label := lipgloss.NewStyle().Foreground(t.TextMuted).Render(fmt.Sprintf(" slot %d ", slot.Index+1))
+
+	if slot.Status != "busy" {
+		return label + lipgloss.NewStyle().Foreground(t.TextDim).Render("free")
+	}
+
+	line := display.ShortID(slot.JobID) + " " + slot.JobDesc
+	if slot.Tool != "" {
+		line += " · " + slot.Tool
+	} else if slot.Progress != "" {
+		line += " · " + slot.Progress
+	}
+	cost := valueOr(slot.Cost, "$0.00") + fmt.Sprintf(" (%d tokens)", slot.Tokens)
+	width := max(w.width-lipgloss.Width(label)-len(cost)-3, 10)
+	return label + lipgloss.NewStyle().Foreground(t.Text).Render(truncateLine(line, width)) +
+		lipgloss.NewStyle().Foreground(t.Warning).Render(" │ "+cost)
+}
+
 func (w *WorkerDetail) renderActivitySection(height int) string {
 	t := theme.Current
 
@@ -507,15 +612,7 @@ func (w *WorkerDetail) renderFooter() string {
 }
 
 func truncateLine(s string, maxWidth int) string {
-	if lipgloss.Width(s) <= maxWidth {
-		return s
-	}
-	// Simple truncation - could be improved to handle ANSI codes
-	runes := []rune(s)
-	if len(runes) > maxWidth-2 {
-		return string(runes[:maxWidth-2]) + ".."
-	}
-	return s
+	return display.Truncate(s, maxWidth)
 }
 
 func min(a, b int) int {
@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cosa/internal/tui/component"
+	"cosa/internal/tui/styles"
+	"cosa/internal/tui/theme"
+)
+
+// runPaletteAction carries out the command chosen in the command palette.
+func (a *App) runPaletteAction(action string) (tea.Model, tea.Cmd) {
+	if name, ok := strings.CutPrefix(action, component.ThemeAction); ok {
+		a.switchTheme(name)
+		return a, nil
+	}
+
+	switch action {
+	case "new_job":
+		a.dashboard.ShowNewJobDialog()
+	case "new_operation":
+		a.dashboard.ShowNewOperationDialog()
+	case "search":
+		a.dashboard.ShowSearch()
+	case "help":
+		a.dashboard.ToggleHelp()
+	case "focus_workers":
+		a.dashboard.FocusPanel(0)
+	case "focus_jobs":
+		a.dashboard.FocusPanel(1)
+	case "focus_activity":
+		a.dashboard.FocusPanel(2)
+	case "theme":
+		a.dashboard.ShowThemePalette(theme.Names(), a.theme)
+	case "refresh":
+		return a, tea.Batch(a.fetchStatus, a.fetchWorkers, a.fetchJobs)
+	case "quit":
+		a.quitting = true
+		return a, tea.Quit
+	}
+	return a, nil
+}
+
+// switchTheme makes name the current theme, restyles the pages and saves
+// it for the next run.
+func (a *App) switchTheme(name string) {
+	now := time.Now().Format("15:04:05")
+	if !theme.SetTheme(name) {
+		a.dashboard.AddActivity(now, "", "Unknown theme: "+name)
+		return
+	}
+	a.theme = name
+	a.styles = styles.New()
+	a.dashboard.Restyle()
+	a.jobsPage.Restyle()
+
+	a.dashboard.AddActivity(now, "", "Theme: "+name)
+	if a.onThemeChange != nil {
+		if err := a.onThemeChange(name); err != nil {
+			a.dashboard.AddActivity(now, "", "Failed to save theme: "+err.Error())
+		}
+	}
+}
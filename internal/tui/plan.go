@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cosa/internal/protocol"
+)
+
+// chatPlanMsg is the result of a plan mode command typed in the chat.
+type chatPlanMsg struct {
+	command string
+	result  *protocol.ChatPlanResult
+	created *protocol.ChatExecuteResult
+	err     error
+}
+
+// chatCommand returns the command for a plan mode or action command typed
+// in the chat, or nil if input is a message for the underboss.
+func (a *App) chatCommand(input string) tea.Cmd {
+	command, arg, _ := strings.Cut(input, " ")
+
+	var method string
+	var params interface{}
+	switch command {
+	case "/plan":
+		method, params = protocol.MethodChatMode, protocol.ChatModeParams{Mode: protocol.ChatModePlan}
+	case "/show":
+		method = protocol.MethodChatPlan
+	case "/discard":
+		method = protocol.MethodChatDiscard
+	case "/execute":
+		method, params = protocol.MethodChatExecute, protocol.ChatExecuteParams{Name: strings.TrimSpace(arg)}
+	default:
+		return a.chatAction(input)
+	}
+
+	return func() tea.Msg {
+		if a.client == nil {
+			return chatPlanMsg{command: command, err: fmt.Errorf("no connection to daemon")}
+		}
+
+		resp, err := a.client.Call(method, params)
+		if err != nil {
+			return chatPlanMsg{command: command, err: err}
+		}
+		if resp.Error != nil {
+			return chatPlanMsg{command: command, err: fmt.Errorf("%s", resp.Error.Message)}
+		}
+
+		msg := chatPlanMsg{command: command}
+		if method == protocol.MethodChatExecute {
+			var created protocol.ChatExecuteResult
+			json.Unmarshal(resp.Result, &created)
+			msg.created = &created
+		} else {
+			var result protocol.ChatPlanResult
+			json.Unmarshal(resp.Result, &result)
+			msg.result = &result
+		}
+		return msg
+	}
+}
+
+// applyChatPlan shows the outcome of a plan mode command in the chat.
+func (a *App) applyChatPlan(msg chatPlanMsg) {
+	a.chat.SetLoading(false)
+
+	switch {
+	case msg.err != nil:
+		a.chat.AddMessage("plan", "Error: "+msg.err.Error())
+
+	case msg.created != nil:
+		a.chat.SetPlanMode(false)
+		a.chat.AddMessage("plan", fmt.Sprintf("Created operation %q with %d jobs.",
+			msg.created.Operation.Name, len(msg.created.Jobs)))
+
+	case msg.command == "/discard":
+		a.chat.SetPlanMode(false)
+		a.chat.AddMessage("plan", "Plan discarded.")
+
+	case msg.command == "/plan":
+		a.chat.SetPlanMode(true)
+		a.chat.AddMessage("plan", "Plan mode: jobs are drafted, not started. /execute creates them, /discard drops them.")
+
+	default:
+		a.chat.SetPlanMode(msg.result.Mode == protocol.ChatModePlan)
+		a.addPlanBlock(msg.result.Plan)
+	}
+}
+
+// addPlanBlock adds the draft operation to the chat.
+func (a *App) addPlanBlock(plan *protocol.ChatPlan) {
+	content := strings.Join(plan.Lines(), "\n")
+	if len(plan.Steps) > 0 {
+		content += "\n\n/execute to create these jobs, /discard to drop them"
+	}
+	a.chat.AddMessage("plan", content)
+}
@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cosa/internal/display"
+	"cosa/internal/protocol"
+	"cosa/internal/tui/page"
+)
+
+// openReview switches to the review page, selecting jobID if set.
+func (a *App) openReview(jobID string) (tea.Model, tea.Cmd) {
+	a.activePage = "review"
+	a.review = page.NewReview()
+	a.review.SetSize(a.width, a.height)
+	a.review.SetJobs(a.jobs)
+	if jobID != "" {
+		a.review.Select(jobID)
+	}
+
+	return a, tea.Batch(a.fetchReviews, a.loadReviewDiff())
+}
+
+// loadReviewDiff requests the diff of the job selected on the review page
+// if it has not been yet.
+func (a *App) loadReviewDiff() tea.Cmd {
+	if !a.review.NeedsDiff() {
+		return nil
+	}
+	a.review.MarkDiffLoading()
+	return a.fetchDiff(a.review.SelectedJob())
+}
+
+func (a *App) handleReviewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "ctrl+c" {
+		a.quitting = true
+		return a, tea.Quit
+	}
+
+	jobID := a.review.SelectedJob()
+
+	switch action := a.review.HandleKey(msg.String()); action {
+	case "exit":
+		a.activePage = "dashboard"
+		return a, nil
+	case "select", "refresh":
+		return a, tea.Batch(a.fetchJobs, a.fetchReviews, a.loadReviewDiff())
+	case "start":
+		a.startReview(jobID)
+		a.review.SetMessage(fmt.Sprintf("Review of %s started", display.ShortID(jobID)))
+		return a, a.fetchReviews
+	case page.ReviewApprove, page.ReviewChanges, page.ReviewReject:
+		a.overrideReview(jobID, action, a.review.Reason())
+		return a, tea.Batch(a.fetchJobs, a.fetchReviews)
+	}
+
+	return a, nil
+}
+
+// overrideReview decides the review of a job from the review page.
+func (a *App) overrideReview(jobID, action, reason string) {
+	if a.client == nil {
+		a.review.SetMessage("Error: No connection to daemon")
+		return
+	}
+
+	method := protocol.MethodReviewReject
+	if action == page.ReviewApprove {
+		method = protocol.MethodReviewApprove
+	}
+	resp, err := a.client.Call(method, protocol.ReviewOverrideParams{
+		JobID:  jobID,
+		Reason: reason,
+		By:     a.identity,
+
+		NoRevision: action == page.ReviewReject,
+	})
+	if err != nil {
+		a.review.SetMessage(fmt.Sprintf("Error: %v", err))
+		return
+	}
+
+	if resp.Error != nil {
+		a.review.SetMessage(fmt.Sprintf("Error: %s", resp.Error.Message))
+		return
+	}
+
+	var result protocol.ReviewOverrideResult
+	json.Unmarshal(resp.Result, &result)
+
+	var message string
+	switch {
+	case result.RevisionJobID != "":
+		message = fmt.Sprintf("Changes requested on %s; revision job %s queued", display.ShortID(jobID), display.ShortID(result.RevisionJobID))
+	case action == page.ReviewReject:
+		message = fmt.Sprintf("Job %s rejected and failed", display.ShortID(jobID))
+	default:
+		// Jobs are merged when they complete, before their review
+		message = fmt.Sprintf("Job %s approved", display.ShortID(jobID))
+	}
+	a.review.SetMessage(message)
+	a.dashboard.AddActivity(time.Now().Format("15:04:05"), "", message)
+}
@@ -50,6 +50,8 @@ type Styles struct {
 	ActivityTime    lipgloss.Style
 	ActivityMessage lipgloss.Style
 	ActivityWorker  lipgloss.Style
+	ActivityWarn    lipgloss.Style
+	ActivityError   lipgloss.Style
 
 	// Keys help
 	KeyHelp     lipgloss.Style
@@ -174,6 +176,13 @@ func New() Styles {
 		ActivityWorker: lipgloss.NewStyle().
 			Foreground(t.Accent),
 
+		ActivityWarn: lipgloss.NewStyle().
+			Foreground(t.Warning),
+
+		ActivityError: lipgloss.NewStyle().
+			Foreground(t.Error).
+			Bold(true),
+
 		// Keys help
 		KeyHelp: lipgloss.NewStyle().
 			Foreground(t.TextDim),
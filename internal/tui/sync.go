@@ -0,0 +1,136 @@
+package tui
+
+import (
+	"errors"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cosa/internal/daemon"
+	"cosa/internal/protocol"
+)
+
+// reconnectInterval is how long the TUI waits between attempts to reach the
+// daemon after losing its connection, e.g. when the machine sleeps.
+const reconnectInterval = 2 * time.Second
+
+// disconnectedMsg reports that the connection to the daemon was lost.
+type disconnectedMsg struct{}
+
+// reconnectMsg carries a new connection to the daemon, or nil if the
+// attempt failed.
+type reconnectMsg struct {
+	client *daemon.Client
+}
+
+type syncStateMsg struct {
+	result    *protocol.SyncStateResult
+	reconnect bool // Synced after reconnecting
+	err       error
+}
+
+// syncState fetches a snapshot of the daemon's state, or learns that the
+// last one applied is still current.
+func (a *App) syncState(reconnect bool) tea.Cmd {
+	client, epoch, version, identity := a.client, a.syncEpoch, a.syncVersion, a.identity
+	return func() tea.Msg {
+		if client == nil {
+			return nil
+		}
+		result, err := client.SyncState(epoch, version, identity)
+		return syncStateMsg{result: result, reconnect: reconnect, err: err}
+	}
+}
+
+// applySyncState records a snapshot's version and hands its parts to the
+// pages, as if each had been fetched.
+func (a *App) applySyncState(msg syncStateMsg) tea.Cmd {
+	if msg.err != nil {
+		if !errors.Is(msg.err, daemon.ErrConnectionClosed) {
+			a.dashboard.AddActivity(time.Now().Format("15:04:05"), "", "Sync failed: "+msg.err.Error())
+		}
+		return nil
+	}
+
+	result := msg.result
+	now := time.Now().Format("15:04:05")
+	if result.Unchanged {
+		if msg.reconnect {
+			a.dashboard.AddActivity(now, "", "Reconnected to the daemon; nothing changed")
+		}
+		return nil
+	}
+
+	restarted := a.syncEpoch != 0 && result.Epoch != a.syncEpoch
+	a.syncEpoch, a.syncVersion = result.Epoch, result.Version
+
+	if msg.reconnect {
+		note := "Reconnected to the daemon"
+		if restarted {
+			note += " (it restarted)"
+		}
+		a.dashboard.AddActivity(now, "", note)
+		for _, alert := range result.Alerts {
+			a.dashboard.AddActivity(now, "", "⚑ "+alert.Message)
+		}
+	}
+
+	// The next poll fills in what the workers are doing
+	workers := make(workersMsg, len(result.Workers))
+	for i, w := range result.Workers {
+		workers[i].WorkerInfo = w
+	}
+
+	cmds := []tea.Cmd{
+		func() tea.Msg { return statusMsg(result.Status) },
+		func() tea.Msg { return workers },
+		func() tea.Msg { return jobsMsg(result.Jobs) },
+	}
+	if result.Reviews != nil {
+		cmds = append(cmds, func() tea.Msg { return reviewsMsg(result.Reviews) })
+	}
+	return tea.Batch(cmds...)
+}
+
+// handleDisconnect marks the daemon unreachable and starts reconnecting.
+func (a *App) handleDisconnect() tea.Cmd {
+	if a.disconnected || a.client == nil {
+		return nil
+	}
+	a.disconnected = true
+	a.dashboard.SetDisconnected(true)
+	a.dashboard.AddActivity(time.Now().Format("15:04:05"), "", "Lost connection to the daemon; reconnecting")
+	return a.reconnect()
+}
+
+// reconnect tries to connect to the daemon again after a pause.
+func (a *App) reconnect() tea.Cmd {
+	current := a.client
+	return tea.Tick(reconnectInterval, func(time.Time) tea.Msg {
+		client, err := current.Reconnect()
+		if err != nil {
+			return reconnectMsg{}
+		}
+		return reconnectMsg{client: client}
+	})
+}
+
+// handleReconnect switches to a new connection, subscribes to events again
+// and syncs the state missed while disconnected.
+func (a *App) handleReconnect(msg reconnectMsg) tea.Cmd {
+	if msg.client == nil {
+		return a.reconnect()
+	}
+	if err := msg.client.Subscribe(activityEvents); err != nil {
+		msg.client.Close()
+		return a.reconnect()
+	}
+
+	a.client.Close()
+	a.client = msg.client
+	a.disconnected = false
+	a.dashboard.SetDisconnected(false)
+	a.err = nil
+
+	return tea.Batch(a.waitForEvent, a.syncState(true))
+}
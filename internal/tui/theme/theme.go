@@ -2,8 +2,14 @@
 package theme
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"gopkg.in/yaml.v3"
@@ -138,13 +144,47 @@ var Miami = Theme{
 	BorderActive: lipgloss.Color("#FF1493"),
 }
 
+// OpenCode matches the default theme of the OpenCode TUI.
+var OpenCode = Theme{
+	Name: "opencode",
+
+	Primary:      lipgloss.Color("#FAB283"), // Peach
+	Secondary:    lipgloss.Color("#5C9CF5"), // Blue
+	Accent:       lipgloss.Color("#9D7CD8"), // Purple
+
+	Background:   lipgloss.Color("#0A0A0A"),
+	Surface:      lipgloss.Color("#141414"),
+	SurfaceLight: lipgloss.Color("#1E1E1E"),
+
+	Text:         lipgloss.Color("#EEEEEE"),
+	TextMuted:    lipgloss.Color("#808080"),
+	TextDim:      lipgloss.Color("#606060"),
+
+	Success:      lipgloss.Color("#7FD88F"),
+	Warning:      lipgloss.Color("#F5A742"),
+	Error:        lipgloss.Color("#E06C75"),
+	Info:         lipgloss.Color("#56B6C2"),
+
+	RoleDon:         lipgloss.Color("#FAB283"),
+	RoleConsigliere: lipgloss.Color("#9D7CD8"),
+	RoleCapo:        lipgloss.Color("#5C9CF5"),
+	RoleSoldato:     lipgloss.Color("#808080"),
+
+	Border:       lipgloss.Color("#484848"),
+	BorderActive: lipgloss.Color("#FAB283"),
+}
+
 // Themes is a map of available themes.
 var Themes = map[string]Theme{
 	"noir":      Noir,
 	"godfather": Godfather,
 	"miami":     Miami,
+	"opencode":  OpenCode,
 }
 
+// builtin names the themes that ship with cosa.
+var builtin = []string{"noir", "godfather", "miami", "opencode"}
+
 // SetTheme sets the current theme by name.
 func SetTheme(name string) bool {
 	if theme, ok := Themes[name]; ok {
@@ -154,6 +194,54 @@ func SetTheme(name string) bool {
 	return false
 }
 
+// Names returns the names of the available themes: the built-ins first,
+// then any custom themes in alphabetical order.
+func Names() []string {
+	names := append([]string{}, builtin...)
+	var custom []string
+	for name := range Themes {
+		if !slices.Contains(builtin, name) {
+			custom = append(custom, name)
+		}
+	}
+	sort.Strings(custom)
+	return append(names, custom...)
+}
+
+// Lookup finds a theme by name among the built-ins and the theme files in
+// the user's themes directories, where <name>.yaml defines the theme called
+// name and later directories override earlier ones, as in LoadUserThemes.
+// Unlike LoadUserThemes it reports why a theme file cannot be used.
+func Lookup(name string) (Theme, error) {
+	if slices.Contains(builtin, name) {
+		return Themes[name], nil
+	}
+
+	dirs, err := UserThemeDirs()
+	if err != nil {
+		return Theme{}, err
+	}
+	for i := len(dirs) - 1; i >= 0; i-- {
+		for _, ext := range []string{".yaml", ".yml"} {
+			path := filepath.Join(dirs[i], name+ext)
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+			theme, err := LoadThemeFromFile(path)
+			if err != nil {
+				return Theme{}, fmt.Errorf("%s: %w", path, err)
+			}
+			if theme.Name == "" {
+				theme.Name = name
+			}
+			return *theme, nil
+		}
+	}
+
+	return Theme{}, fmt.Errorf("%s is not a built-in theme (%s) or a custom theme in ~/.cosa/themes/%s.yaml",
+		name, strings.Join(builtin, ", "), name)
+}
+
 // YAMLTheme represents a theme in YAML format.
 type YAMLTheme struct {
 	Name string `yaml:"name"`
@@ -195,6 +283,9 @@ func LoadThemeFromFile(path string) (*Theme, error) {
 	if err := yaml.Unmarshal(data, &yt); err != nil {
 		return nil, err
 	}
+	if err := yt.validate(); err != nil {
+		return nil, err
+	}
 
 	theme := &Theme{
 		Name:         yt.Name,
@@ -222,6 +313,48 @@ func LoadThemeFromFile(path string) (*Theme, error) {
 	return theme, nil
 }
 
+// validate checks that every color set in the theme is one lipgloss can
+// draw: a hex color (#RGB or #RRGGBB) or an ANSI color number (0-255).
+func (yt *YAMLTheme) validate() error {
+	colors := []struct{ field, value string }{
+		{"primary", yt.Primary},
+		{"secondary", yt.Secondary},
+		{"accent", yt.Accent},
+		{"background", yt.Background},
+		{"surface", yt.Surface},
+		{"surface_light", yt.SurfaceLight},
+		{"text", yt.Text},
+		{"text_muted", yt.TextMuted},
+		{"text_dim", yt.TextDim},
+		{"success", yt.Success},
+		{"warning", yt.Warning},
+		{"error", yt.Error},
+		{"info", yt.Info},
+		{"role_don", yt.RoleDon},
+		{"role_consigliere", yt.RoleConsigliere},
+		{"role_capo", yt.RoleCapo},
+		{"role_soldato", yt.RoleSoldato},
+		{"border", yt.Border},
+		{"border_active", yt.BorderActive},
+	}
+	for _, c := range colors {
+		if c.value != "" && !validColor(c.value) {
+			return fmt.Errorf("invalid color for %s: %q (use #RRGGBB or 0-255)", c.field, c.value)
+		}
+	}
+	return nil
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+func validColor(s string) bool {
+	if hexColor.MatchString(s) {
+		return true
+	}
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= 0 && n <= 255
+}
+
 func colorOrDefault(hex string, fallback lipgloss.Color) lipgloss.Color {
 	if hex == "" {
 		return fallback
@@ -256,28 +389,42 @@ func LoadCustomThemes(dir string) error {
 			continue // Skip invalid themes
 		}
 
+		// The theme is known by its file name, which is what tui.theme is
+		// set to; the name inside the file is only for display
+		key := name[:len(name)-len(ext)]
+		if slices.Contains(builtin, key) {
+			continue // Built-ins cannot be replaced
+		}
 		if theme.Name == "" {
-			// Use filename without extension as theme name
-			theme.Name = name[:len(name)-len(ext)]
+			theme.Name = key
 		}
 
-		Themes[theme.Name] = *theme
+		Themes[key] = *theme
 	}
 
 	return nil
 }
 
-// LoadUserThemes loads themes from the user's config directory.
-func LoadUserThemes() error {
+// UserThemeDirs returns the directories custom themes are loaded from, in
+// the order they are read.
+func UserThemeDirs() ([]string, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Try both locations
-	dirs := []string{
+	return []string{
 		filepath.Join(homeDir, ".config", "cosa", "themes"),
 		filepath.Join(homeDir, ".cosa", "themes"),
+	}, nil
+}
+
+// LoadUserThemes loads themes from the user's config directory.
+func LoadUserThemes() error {
+	dirs, err := UserThemeDirs()
+	if err != nil {
+		return err
 	}
 
 	for _, dir := range dirs {
@@ -0,0 +1,185 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func writeTheme(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadThemeFromFile(t *testing.T) {
+	path := writeTheme(t, t.TempDir(), "dusk.yaml", `name: Dusk
+primary: "#ff8800"
+secondary: "#123"
+accent: "208"
+background: "#000000"
+surface: "#111111"
+surface_light: "#222222"
+text: "#eeeeee"
+text_muted: "#999999"
+text_dim: "#666666"
+success: "#00ff00"
+warning: "#ffff00"
+error: "#ff0000"
+info: "#0000ff"
+role_don: "#aa0000"
+role_consigliere: "#00aa00"
+role_capo: "#0000aa"
+role_soldato: "#aaaaaa"
+border: "#333333"
+border_active: "#444444"
+`)
+
+	theme, err := LoadThemeFromFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if theme.Name != "Dusk" {
+		t.Errorf("expected name Dusk, got %q", theme.Name)
+	}
+	checks := map[string]struct{ got, want lipgloss.Color }{
+		"primary":       {theme.Primary, "#ff8800"},
+		"secondary":     {theme.Secondary, "#123"},
+		"accent":        {theme.Accent, "208"},
+		"surface_light": {theme.SurfaceLight, "#222222"},
+		"role_soldato":  {theme.RoleSoldato, "#aaaaaa"},
+		"border_active": {theme.BorderActive, "#444444"},
+	}
+	for field, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s: expected %q, got %q", field, c.want, c.got)
+		}
+	}
+}
+
+func TestLoadThemeFromFile_MissingKeys(t *testing.T) {
+	path := writeTheme(t, t.TempDir(), "partial.yaml", `primary: "#ff8800"
+`)
+
+	theme, err := LoadThemeFromFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if theme.Primary != "#ff8800" {
+		t.Errorf("expected primary #ff8800, got %q", theme.Primary)
+	}
+	// Everything left out falls back to noir
+	if theme.Accent != Noir.Accent || theme.Text != Noir.Text || theme.BorderActive != Noir.BorderActive {
+		t.Errorf("expected unset colors to fall back to noir, got accent %q text %q border_active %q",
+			theme.Accent, theme.Text, theme.BorderActive)
+	}
+	if theme.Name != "" {
+		t.Errorf("expected no name when the file sets none, got %q", theme.Name)
+	}
+}
+
+func TestLoadThemeFromFile_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"bad yaml", "primary: [unclosed\n", ""},
+		{"color name", "accent: orange\n", "accent"},
+		{"short hex", "primary: \"#12\"\n", "primary"},
+		{"ansi out of range", "border: \"256\"\n", "border"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTheme(t, dir, strings.ReplaceAll(tt.name, " ", "-")+".yaml", tt.content)
+			_, err := LoadThemeFromFile(path)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error to name %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if _, err := LoadThemeFromFile(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("expected error for a missing file")
+	}
+}
+
+func TestLoadCustomThemes(t *testing.T) {
+	saved := Themes
+	Themes = map[string]Theme{}
+	for name, theme := range saved {
+		Themes[name] = theme
+	}
+	t.Cleanup(func() { Themes = saved })
+
+	dir := t.TempDir()
+	writeTheme(t, dir, "dusk.yaml", "primary: \"#ff8800\"\n")
+	writeTheme(t, dir, "named.yml", "name: Pretty Name\n")
+	writeTheme(t, dir, "broken.yaml", "accent: orange\n")
+	writeTheme(t, dir, "noir.yaml", "primary: \"#ff8800\"\n")
+	writeTheme(t, dir, "notes.txt", "not a theme\n")
+
+	if err := LoadCustomThemes(dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dusk, ok := Themes["dusk"]; !ok || dusk.Name != "dusk" || dusk.Primary != "#ff8800" {
+		t.Errorf("expected dusk to load under its file name, got %+v (loaded %v)", dusk, ok)
+	}
+	if named, ok := Themes["named"]; !ok || named.Name != "Pretty Name" {
+		t.Errorf("expected named to keep its display name, got %+v (loaded %v)", named, ok)
+	}
+	if _, ok := Themes["broken"]; ok {
+		t.Error("expected an invalid theme to be skipped")
+	}
+	if Themes["noir"].Primary != Noir.Primary {
+		t.Error("expected built-in noir not to be replaced")
+	}
+	if _, ok := Themes["notes"]; ok {
+		t.Error("expected non-YAML files to be ignored")
+	}
+
+	if err := LoadCustomThemes(filepath.Join(dir, "missing")); err != nil {
+		t.Errorf("expected a missing directory to be fine, got %v", err)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTheme(t, filepath.Join(home, ".cosa", "themes"), "dusk.yaml", "primary: \"#ff8800\"\n")
+	writeTheme(t, filepath.Join(home, ".cosa", "themes"), "broken.yaml", "accent: orange\n")
+
+	if theme, err := Lookup("noir"); err != nil || theme.Name != Noir.Name {
+		t.Errorf("expected noir, got %+v, %v", theme, err)
+	}
+
+	theme, err := Lookup("dusk")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if theme.Name != "dusk" || theme.Primary != "#ff8800" {
+		t.Errorf("expected dusk from the themes directory, got %+v", theme)
+	}
+
+	if _, err := Lookup("broken"); err == nil || !strings.Contains(err.Error(), "broken.yaml") {
+		t.Errorf("expected the error to name the broken file, got %v", err)
+	}
+	if _, err := Lookup("absent"); err == nil {
+		t.Error("expected error for an unknown theme")
+	}
+}
@@ -0,0 +1,303 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Downtime is a window in which a worker takes no new jobs.
+type Downtime struct {
+	ID     string    `json:"id"`
+	Worker string    `json:"worker"` // Worker name
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+// Calendar records when workers are offline and how many jobs each may
+// start per day. The scheduler consults it before assigning work.
+type Calendar struct {
+	mu          sync.RWMutex
+	downtime    []Downtime
+	dailyLimits map[string]int // Max jobs started per day, by worker name
+	path        string         // JSON file; empty keeps the calendar in memory
+
+	now func() time.Time
+}
+
+// calendarFile is the on-disk form of a Calendar.
+type calendarFile struct {
+	Downtime    []Downtime     `json:"downtime"`
+	DailyLimits map[string]int `json:"daily_limits,omitempty"`
+}
+
+// NewCalendar creates an empty in-memory calendar.
+func NewCalendar() *Calendar {
+	return &Calendar{
+		dailyLimits: make(map[string]int),
+		now:         time.Now,
+	}
+}
+
+// LoadCalendar loads the calendar stored at path, or starts an empty one
+// that is saved there on the first change.
+func LoadCalendar(path string) (*Calendar, error) {
+	c := NewCalendar()
+	c.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read capacity calendar: %w", err)
+	}
+
+	var file calendarFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse capacity calendar: %w", err)
+	}
+	c.downtime = file.Downtime
+	for name, limit := range file.DailyLimits {
+		c.dailyLimits[name] = limit
+	}
+	return c, nil
+}
+
+// saveLocked writes the calendar to disk. Must be called with the lock held.
+func (c *Calendar) saveLocked() error {
+	if c.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(calendarFile{
+		Downtime:    c.downtime,
+		DailyLimits: c.dailyLimits,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data, 0644)
+}
+
+// AddDowntime takes worker offline from start until end. Windows that have
+// already ended are dropped at the same time.
+func (c *Calendar) AddDowntime(worker string, start, end time.Time, reason string) (Downtime, error) {
+	if !end.After(start) {
+		return Downtime{}, fmt.Errorf("downtime must end after it starts")
+	}
+	if !end.After(c.now()) {
+		return Downtime{}, fmt.Errorf("downtime ends in the past")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	d := Downtime{
+		ID:     uuid.New().String(),
+		Worker: worker,
+		Start:  start,
+		End:    end,
+		Reason: reason,
+	}
+	c.pruneLocked()
+	c.downtime = append(c.downtime, d)
+	return d, c.saveLocked()
+}
+
+// RemoveDowntime cancels the downtime with the given ID or ID prefix.
+func (c *Calendar) RemoveDowntime(id string) (Downtime, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	match := -1
+	for i, d := range c.downtime {
+		if d.ID == id || (len(id) >= 4 && strings.HasPrefix(d.ID, id)) {
+			if match >= 0 {
+				return Downtime{}, fmt.Errorf("downtime ID %s is ambiguous", id)
+			}
+			match = i
+		}
+	}
+	if match < 0 {
+		return Downtime{}, fmt.Errorf("downtime not found: %s", id)
+	}
+
+	d := c.downtime[match]
+	c.downtime = append(c.downtime[:match], c.downtime[match+1:]...)
+	return d, c.saveLocked()
+}
+
+// ClearWorker cancels all of worker's current and upcoming downtime and
+// returns what was cancelled.
+func (c *Calendar) ClearWorker(worker string) ([]Downtime, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var kept, removed []Downtime
+	for _, d := range c.downtime {
+		if d.Worker == worker {
+			removed = append(removed, d)
+		} else {
+			kept = append(kept, d)
+		}
+	}
+	c.downtime = kept
+	return removed, c.saveLocked()
+}
+
+// Downtime returns the downtime that has not ended yet, soonest first. An
+// empty worker returns every worker's downtime.
+func (c *Calendar) Downtime(worker string) []Downtime {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.now()
+	var result []Downtime
+	for _, d := range c.downtime {
+		if d.End.After(now) && (worker == "" || d.Worker == worker) {
+			result = append(result, d)
+		}
+	}
+	sort.Slice(result, func(a, b int) bool {
+		return result[a].Start.Before(result[b].Start)
+	})
+	return result
+}
+
+// OffAt returns the downtime worker is in at t, if any.
+func (c *Calendar) OffAt(worker string, t time.Time) (Downtime, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, d := range c.downtime {
+		if d.Worker == worker && !t.Before(d.Start) && t.Before(d.End) {
+			return d, true
+		}
+	}
+	return Downtime{}, false
+}
+
+// OffDuring returns how much of the period from start to end worker is
+// offline.
+func (c *Calendar) OffDuring(worker string, start, end time.Time) time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	// Merge overlapping windows so they are not counted twice
+	var windows []Downtime
+	for _, d := range c.downtime {
+		if d.Worker == worker && d.Start.Before(end) && d.End.After(start) {
+			windows = append(windows, d)
+		}
+	}
+	sort.Slice(windows, func(a, b int) bool {
+		return windows[a].Start.Before(windows[b].Start)
+	})
+
+	var off time.Duration
+	cursor := start
+	for _, d := range windows {
+		from, to := d.Start, d.End
+		if from.Before(cursor) {
+			from = cursor
+		}
+		if to.After(end) {
+			to = end
+		}
+		if to.After(from) {
+			off += to.Sub(from)
+			cursor = to
+		}
+	}
+	return off
+}
+
+// SetDailyLimit caps how many jobs worker may start per day. Zero removes
+// the cap.
+func (c *Calendar) SetDailyLimit(worker string, limit int) error {
+	if limit < 0 {
+		return fmt.Errorf("daily limit must not be negative")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if limit == 0 {
+		delete(c.dailyLimits, worker)
+	} else {
+		c.dailyLimits[worker] = limit
+	}
+	return c.saveLocked()
+}
+
+// DailyLimit returns the maximum number of jobs worker may start per day,
+// or 0 if there is no limit.
+func (c *Calendar) DailyLimit(worker string) int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.dailyLimits[worker]
+}
+
+// RemoveWorker forgets worker's downtime and daily limit.
+func (c *Calendar) RemoveWorker(worker string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	kept := c.downtime[:0]
+	for _, d := range c.downtime {
+		if d.Worker != worker {
+			kept = append(kept, d)
+		}
+	}
+	c.downtime = kept
+	delete(c.dailyLimits, worker)
+	return c.saveLocked()
+}
+
+// pruneLocked drops downtime that has ended. Must be called with the lock
+// held.
+func (c *Calendar) pruneLocked() {
+	now := c.now()
+	kept := c.downtime[:0]
+	for _, d := range c.downtime {
+		if d.End.After(now) {
+			kept = append(kept, d)
+		}
+	}
+	c.downtime = kept
+}
+
+// StartOfDay returns local midnight on t's day.
+func StartOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// EstimateDrain works out when pending jobs will be done, given the jobs
+// that can be finished on each upcoming day. It returns the day the last
+// job finishes and how far through that day's capacity it is, or ok=false
+// if the jobs are not done within the days given.
+func EstimateDrain(pending int, perDay []float64) (day int, fraction float64, ok bool) {
+	if pending <= 0 {
+		return 0, 0, true
+	}
+	remaining := float64(pending)
+	for i, n := range perDay {
+		if n <= 0 {
+			continue
+		}
+		if remaining <= n {
+			return i, remaining / n, true
+		}
+		remaining -= n
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,131 @@
+package worker
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCalendar_Downtime(t *testing.T) {
+	now := time.Date(2026, 3, 4, 10, 0, 0, 0, time.Local)
+	c := NewCalendar()
+	c.now = func() time.Time { return now }
+
+	friday := time.Date(2026, 3, 6, 0, 0, 0, 0, time.Local)
+	d, err := c.AddDowntime("paulie", friday, friday.AddDate(0, 0, 1), "model migration")
+	if err != nil {
+		t.Fatalf("failed to add downtime: %v", err)
+	}
+
+	if _, off := c.OffAt("paulie", now); off {
+		t.Error("expected paulie to be online before the downtime")
+	}
+	if got, off := c.OffAt("paulie", friday.Add(12*time.Hour)); !off || got.ID != d.ID {
+		t.Error("expected paulie to be offline on Friday")
+	}
+	if _, off := c.OffAt("vito", friday.Add(12*time.Hour)); off {
+		t.Error("expected other workers to be unaffected")
+	}
+
+	if _, err := c.AddDowntime("paulie", friday, friday, ""); err == nil {
+		t.Error("expected an error for an empty window")
+	}
+	if _, err := c.AddDowntime("paulie", now.Add(-2*time.Hour), now.Add(-time.Hour), ""); err == nil {
+		t.Error("expected an error for a window in the past")
+	}
+
+	if _, err := c.RemoveDowntime(d.ID[:8]); err != nil {
+		t.Fatalf("failed to remove downtime by prefix: %v", err)
+	}
+	if len(c.Downtime("")) != 0 {
+		t.Error("expected no downtime after removing it")
+	}
+}
+
+func TestCalendar_OffDuring(t *testing.T) {
+	c := NewCalendar()
+	c.now = func() time.Time { return time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local) }
+
+	day := time.Date(2026, 3, 6, 0, 0, 0, 0, time.Local)
+	c.AddDowntime("paulie", day.Add(9*time.Hour), day.Add(13*time.Hour), "")
+	c.AddDowntime("paulie", day.Add(12*time.Hour), day.Add(15*time.Hour), "") // Overlaps the first
+	c.AddDowntime("paulie", day.Add(22*time.Hour), day.Add(26*time.Hour), "") // Runs into the next day
+
+	if got := c.OffDuring("paulie", day, day.AddDate(0, 0, 1)); got != 8*time.Hour {
+		t.Errorf("expected 8h offline, got %s", got)
+	}
+}
+
+func TestCalendar_DailyLimit(t *testing.T) {
+	c := NewCalendar()
+
+	if err := c.SetDailyLimit("paulie", 3); err != nil {
+		t.Fatalf("failed to set limit: %v", err)
+	}
+	if got := c.DailyLimit("paulie"); got != 3 {
+		t.Errorf("expected limit 3, got %d", got)
+	}
+
+	c.SetDailyLimit("paulie", 0)
+	if got := c.DailyLimit("paulie"); got != 0 {
+		t.Errorf("expected no limit, got %d", got)
+	}
+
+	if err := c.SetDailyLimit("paulie", -1); err == nil {
+		t.Error("expected an error for a negative limit")
+	}
+}
+
+func TestLoadCalendar_Persists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capacity.json")
+
+	c, err := LoadCalendar(path)
+	if err != nil {
+		t.Fatalf("failed to load missing calendar: %v", err)
+	}
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+	c.AddDowntime("paulie", start, start.Add(time.Hour), "dentist")
+	c.SetDailyLimit("vito", 5)
+
+	loaded, err := LoadCalendar(path)
+	if err != nil {
+		t.Fatalf("failed to reload calendar: %v", err)
+	}
+	downtime := loaded.Downtime("paulie")
+	if len(downtime) != 1 || downtime[0].Reason != "dentist" || !downtime[0].Start.Equal(start) {
+		t.Errorf("expected the downtime to survive a reload, got %+v", downtime)
+	}
+	if got := loaded.DailyLimit("vito"); got != 5 {
+		t.Errorf("expected limit 5 after reload, got %d", got)
+	}
+
+	loaded.RemoveWorker("paulie")
+	if len(loaded.Downtime("paulie")) != 0 {
+		t.Error("expected a removed worker's downtime to be forgotten")
+	}
+}
+
+func TestEstimateDrain(t *testing.T) {
+	tests := []struct {
+		name     string
+		pending  int
+		perDay   []float64
+		day      int
+		fraction float64
+		ok       bool
+	}{
+		{"nothing pending", 0, nil, 0, 0, true},
+		{"done today", 2, []float64{4, 4}, 0, 0.5, true},
+		{"skips days off", 6, []float64{2, 0, 8}, 2, 0.5, true},
+		{"never done", 10, []float64{1, 1}, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			day, fraction, ok := EstimateDrain(tt.pending, tt.perDay)
+			if day != tt.day || fraction != tt.fraction || ok != tt.ok {
+				t.Errorf("expected (%d, %v, %v), got (%d, %v, %v)", tt.day, tt.fraction, tt.ok, day, fraction, ok)
+			}
+		})
+	}
+}
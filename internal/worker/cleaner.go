@@ -3,17 +3,31 @@ package worker
 
 import (
 	"context"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"cosa/internal/claude"
 	"cosa/internal/git"
+	"cosa/internal/job"
 	"cosa/internal/ledger"
 )
 
+// Kinds of resource the Cleaner removes.
+const (
+	CleanupWorktree = "worktree"
+	CleanupBranch   = "branch"
+	CleanupSession  = "session"
+)
+
+// jobBranchPrefix is the prefix of the branches job worktrees are on.
+const jobBranchPrefix = "cosa/job/"
+
 // CleanerConfig configures the Cleaner resource cleanup service.
 type CleanerConfig struct {
 	// PatrolInterval is how often to run cleanup (default: 1 hour).
@@ -22,14 +36,25 @@ type CleanerConfig struct {
 	// SessionMaxAge is the maximum age for unused sessions (default: 7 days).
 	SessionMaxAge time.Duration
 
-	// WorktreeMaxAge is the maximum age for orphaned worktrees (default: 24 hours).
+	// WorktreeMaxAge is the maximum age for orphaned worktrees, and for the
+	// worktrees and branches of failed and cancelled jobs (default: 24 hours).
 	WorktreeMaxAge time.Duration
 
+	// MaxDiskUsage is the number of bytes job worktrees may take up in
+	// total (0 is unlimited). Over it, the worktrees of finished jobs are
+	// removed, oldest first, before they reach WorktreeMaxAge.
+	MaxDiskUsage int64
+
 	// Pool is the worker pool to check for active workers.
 	Pool *Pool
 
-	// GitManager handles worktree operations.
-	GitManager *git.Manager
+	// Jobs is the job store, to check whether a job still needs its
+	// worktree and branch.
+	Jobs *job.Store
+
+	// GitManager returns the manager for worktree operations, or nil while
+	// there is no territory.
+	GitManager func() *git.Manager
 
 	// SessionStore handles session cleanup.
 	SessionStore *claude.SessionStore
@@ -41,13 +66,26 @@ type CleanerConfig struct {
 	OnCleanup func(stats CleanupStats)
 }
 
+// CleanupAction is a resource the Cleaner removes, and why.
+type CleanupAction struct {
+	Kind     string    // CleanupWorktree, CleanupBranch or CleanupSession
+	Target   string    // Worktree path, branch name or session ID
+	JobID    string    // Job the worktree or branch belongs to, if known
+	Worker   string    // Worker the worktree or session belongs to
+	Size     int64     // Bytes on disk, for worktrees
+	LastUsed time.Time // When the resource was last used
+	Reason   string
+}
+
 // CleanupStats contains statistics about a cleanup run.
 type CleanupStats struct {
-	SessionsCleaned   int
-	WorktreesCleaned  int
-	BranchesCleaned   int
-	Errors            []string
-	Duration          time.Duration
+	SessionsCleaned  int
+	WorktreesCleaned int
+	BranchesCleaned  int
+	BytesFreed       int64
+	Removed          []CleanupAction
+	Errors           []string
+	Duration         time.Duration
 }
 
 // Cleaner handles resource cleanup.
@@ -56,6 +94,7 @@ type Cleaner struct {
 	ctx    context.Context
 	cancel context.CancelFunc
 	wg     sync.WaitGroup
+	mu     sync.Mutex // Serializes cleanup runs
 }
 
 // NewCleaner creates a new resource cleanup service.
@@ -96,6 +135,13 @@ func (c *Cleaner) RunNow() CleanupStats {
 	return c.cleanup()
 }
 
+// Plan returns what a cleanup run would remove now, without removing it.
+func (c *Cleaner) Plan() ([]CleanupAction, []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.plan()
+}
+
 func (c *Cleaner) patrolLoop() {
 	defer c.wg.Done()
 
@@ -115,31 +161,53 @@ func (c *Cleaner) patrolLoop() {
 	}
 }
 
+func (c *Cleaner) gitManager() *git.Manager {
+	if c.cfg.GitManager == nil {
+		return nil
+	}
+	return c.cfg.GitManager()
+}
+
 func (c *Cleaner) cleanup() CleanupStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	start := time.Now()
-	stats := CleanupStats{}
+	actions, errs := c.plan()
+	stats := CleanupStats{Errors: errs}
 
-	// 1. Clean up old sessions
-	if c.cfg.SessionStore != nil {
-		removed, err := c.cfg.SessionStore.Cleanup(c.cfg.SessionMaxAge)
-		if err != nil {
-			stats.Errors = append(stats.Errors, "session cleanup: "+err.Error())
-		} else {
-			stats.SessionsCleaned = removed
+	for _, a := range actions {
+		if err := c.remove(a); err != nil {
+			stats.Errors = append(stats.Errors, fmt.Sprintf("remove %s %s: %v", a.Kind, a.Target, err))
+			continue
 		}
-	}
 
-	// 2. Clean up orphaned worktrees
-	if c.cfg.GitManager != nil && c.cfg.Pool != nil {
-		wtStats := c.cleanupWorktrees()
-		stats.WorktreesCleaned = wtStats.WorktreesCleaned
-		stats.BranchesCleaned = wtStats.BranchesCleaned
-		stats.Errors = append(stats.Errors, wtStats.Errors...)
+		switch a.Kind {
+		case CleanupSession:
+			stats.SessionsCleaned++
+		case CleanupWorktree:
+			stats.WorktreesCleaned++
+		case CleanupBranch:
+			stats.BranchesCleaned++
+		}
+		stats.BytesFreed += a.Size
+		stats.Removed = append(stats.Removed, a)
+
+		if c.cfg.Ledger != nil {
+			c.cfg.Ledger.Append(EventCleanupRemoved, CleanupRemovedEventData{
+				Kind:   a.Kind,
+				Target: a.Target,
+				JobID:  a.JobID,
+				Worker: a.Worker,
+				Size:   a.Size,
+				Reason: a.Reason,
+			})
+		}
 	}
 
-	// 3. Git garbage collection (prune worktrees)
-	if c.cfg.GitManager != nil {
-		if err := c.cfg.GitManager.PruneWorktrees(); err != nil {
+	// Git garbage collection (prune worktrees)
+	if gitMgr := c.gitManager(); gitMgr != nil {
+		if err := gitMgr.PruneWorktrees(); err != nil {
 			stats.Errors = append(stats.Errors, "git prune: "+err.Error())
 		}
 	}
@@ -152,6 +220,7 @@ func (c *Cleaner) cleanup() CleanupStats {
 			SessionsCleaned:  stats.SessionsCleaned,
 			WorktreesCleaned: stats.WorktreesCleaned,
 			BranchesCleaned:  stats.BranchesCleaned,
+			BytesFreed:       stats.BytesFreed,
 			ErrorCount:       len(stats.Errors),
 			DurationMs:       stats.Duration.Milliseconds(),
 		})
@@ -165,88 +234,293 @@ func (c *Cleaner) cleanup() CleanupStats {
 	return stats
 }
 
-func (c *Cleaner) cleanupWorktrees() CleanupStats {
-	stats := CleanupStats{}
+// plan works out what to remove. The caller holds c.mu.
+func (c *Cleaner) plan() ([]CleanupAction, []string) {
+	var actions []CleanupAction
+	var errs []string
 
-	// Get list of all worktrees
-	worktrees, err := c.cfg.GitManager.ListWorktrees()
-	if err != nil {
-		stats.Errors = append(stats.Errors, "list worktrees: "+err.Error())
-		return stats
+	// 1. Unused sessions
+	if c.cfg.SessionStore != nil {
+		cutoff := time.Now().Add(-c.cfg.SessionMaxAge)
+		for _, info := range c.cfg.SessionStore.List() {
+			if info.LastUsed.Before(cutoff) {
+				actions = append(actions, CleanupAction{
+					Kind:     CleanupSession,
+					Target:   info.SessionID,
+					Worker:   info.WorkerName,
+					LastUsed: info.LastUsed,
+					Reason:   "unused for " + formatAge(time.Since(info.LastUsed)),
+				})
+			}
+		}
+	}
+
+	gitMgr := c.gitManager()
+	if gitMgr == nil {
+		return actions, errs
 	}
 
-	// Get list of active worker names
-	activeWorkers := make(map[string]bool)
-	for _, w := range c.cfg.Pool.List() {
-		activeWorkers[w.Name] = true
+	worktrees, err := gitMgr.ListWorktrees()
+	if err != nil {
+		return actions, append(errs, "list worktrees: "+err.Error())
 	}
 
-	// Find and clean orphaned worktrees
+	// 2. Worktrees of workers that are gone and jobs that are done
+	checkedOut := make(map[string]bool)
+	var finished []CleanupAction // Job worktrees kept for now, removable over the quota
+	var jobDisk int64
 	for _, wt := range worktrees {
-		// Skip the main worktree
+		checkedOut[wt.Branch] = true
 		if !strings.HasPrefix(wt.Branch, "cosa/") {
+			// The main worktree, or one Cosa did not create
 			continue
 		}
 
-		// Extract worker name from branch
-		workerName := strings.TrimPrefix(wt.Branch, "cosa/")
-
-		// Skip if worker is active
-		if activeWorkers[workerName] {
+		if !strings.HasPrefix(wt.Branch, jobBranchPrefix) {
+			if a, ok := c.planWorkerWorktree(wt); ok {
+				actions = append(actions, a)
+			}
 			continue
 		}
 
-		// Check if worktree directory exists and is old enough
-		if !c.isWorktreeStale(wt.Path) {
+		a, keep := c.planJobWorktree(wt)
+		jobDisk += a.Size
+		switch {
+		case keep:
+		case a.Reason != "":
+			actions = append(actions, a)
+			jobDisk -= a.Size
+			if b, ok := c.planJobBranch(a.JobID, wt.Branch, a.LastUsed); ok {
+				actions = append(actions, b)
+			}
+		default:
+			finished = append(finished, a)
+		}
+	}
+
+	// 3. Over the disk quota, finished jobs' worktrees go oldest first
+	if c.cfg.MaxDiskUsage > 0 && jobDisk > c.cfg.MaxDiskUsage {
+		sort.Slice(finished, func(i, j int) bool { return finished[i].LastUsed.Before(finished[j].LastUsed) })
+		for _, a := range finished {
+			if jobDisk <= c.cfg.MaxDiskUsage {
+				break
+			}
+			a.Reason = fmt.Sprintf("job worktrees over the %s quota", formatSize(c.cfg.MaxDiskUsage))
+			actions = append(actions, a)
+			jobDisk -= a.Size
+		}
+	}
+
+	// 4. Job branches left without a worktree
+	branches, err := gitMgr.ListBranches(jobBranchPrefix)
+	if err != nil {
+		return actions, append(errs, "list branches: "+err.Error())
+	}
+	for _, b := range branches {
+		if checkedOut[b.Name] {
 			continue
 		}
+		jobID := strings.TrimPrefix(b.Name, jobBranchPrefix)
+		if a, ok := c.planJobBranch(jobID, b.Name, b.Committed); ok {
+			actions = append(actions, a)
+		}
+	}
 
-		// Remove the worktree
-		if err := c.cfg.GitManager.RemoveWorktree(workerName, true); err != nil {
-			stats.Errors = append(stats.Errors, "remove worktree "+workerName+": "+err.Error())
-		} else {
-			stats.WorktreesCleaned++
+	return actions, errs
+}
+
+// planWorkerWorktree decides whether a worker's worktree is orphaned.
+func (c *Cleaner) planWorkerWorktree(wt git.Worktree) (CleanupAction, bool) {
+	workerName := strings.TrimPrefix(wt.Branch, "cosa/")
+	if c.cfg.Pool != nil {
+		if _, active := c.cfg.Pool.Get(workerName); active {
+			return CleanupAction{}, false
 		}
+	}
 
-		// Optionally delete the branch too
-		if c.shouldDeleteBranch(wt.Branch) {
-			if err := c.deleteBranch(wt.Branch); err != nil {
-				stats.Errors = append(stats.Errors, "delete branch "+wt.Branch+": "+err.Error())
-			} else {
-				stats.BranchesCleaned++
-			}
+	modified := modTime(wt.Path)
+	if time.Since(modified) <= c.cfg.WorktreeMaxAge {
+		return CleanupAction{}, false
+	}
+	return CleanupAction{
+		Kind:     CleanupWorktree,
+		Target:   wt.Path,
+		Worker:   workerName,
+		Size:     dirSize(wt.Path),
+		LastUsed: modified,
+		Reason:   fmt.Sprintf("worker %s is gone", workerName),
+	}, true
+}
+
+// planJobWorktree describes a job's worktree. It is kept while the job
+// still needs it; otherwise the action has a reason if the worktree is due
+// for removal, or none if it may only be removed to free disk space.
+func (c *Cleaner) planJobWorktree(wt git.Worktree) (CleanupAction, bool) {
+	shortID := strings.TrimPrefix(wt.Branch, jobBranchPrefix)
+	a := CleanupAction{
+		Kind:     CleanupWorktree,
+		Target:   wt.Path,
+		JobID:    shortID,
+		Size:     dirSize(wt.Path),
+		LastUsed: modTime(wt.Path),
+	}
+
+	j := c.findJob(shortID)
+	if j == nil {
+		if time.Since(a.LastUsed) > c.cfg.WorktreeMaxAge {
+			a.Reason = "job no longer exists"
 		}
+		return a, false
 	}
 
-	return stats
+	a.JobID = j.ID
+	if !j.IsTerminal() {
+		return a, true
+	}
+	if done := j.GetCompletedAt(); !done.IsZero() {
+		a.LastUsed = done
+	}
+	if time.Since(a.LastUsed) > c.cfg.WorktreeMaxAge {
+		a.Reason = fmt.Sprintf("job %s %s ago", j.GetStatus(), formatAge(time.Since(a.LastUsed)))
+	}
+	return a, false
 }
 
-func (c *Cleaner) isWorktreeStale(path string) bool {
-	info, err := os.Stat(path)
-	if err != nil {
-		return true // Doesn't exist, consider stale
+// planJobBranch decides whether a job's branch can be deleted. Branches of
+// completed jobs are kept: they hold work that was not merged.
+func (c *Cleaner) planJobBranch(jobID, branch string, lastUsed time.Time) (CleanupAction, bool) {
+	a := CleanupAction{
+		Kind:     CleanupBranch,
+		Target:   branch,
+		JobID:    jobID,
+		LastUsed: lastUsed,
+	}
+
+	if j := c.findJob(jobID); j == nil {
+		a.Reason = "job no longer exists"
+	} else {
+		status := j.GetStatus()
+		if status != job.StatusFailed && status != job.StatusCancelled {
+			return a, false
+		}
+		a.JobID = j.ID
+		if done := j.GetCompletedAt(); !done.IsZero() {
+			a.LastUsed = done
+		}
+		a.Reason = fmt.Sprintf("job %s %s ago", status, formatAge(time.Since(a.LastUsed)))
+	}
+
+	if time.Since(a.LastUsed) <= c.cfg.WorktreeMaxAge {
+		return a, false
+	}
+	return a, true
+}
+
+// findJob returns the job a worktree or branch named with an ID prefix
+// belongs to.
+func (c *Cleaner) findJob(id string) *job.Job {
+	if c.cfg.Jobs == nil || id == "" {
+		return nil
+	}
+	if j, ok := c.cfg.Jobs.Get(id); ok {
+		return j
 	}
+	for _, j := range c.cfg.Jobs.List() {
+		if strings.HasPrefix(j.ID, id) {
+			return j
+		}
+	}
+	return nil
+}
 
-	return time.Since(info.ModTime()) > c.cfg.WorktreeMaxAge
+// remove carries out an action and updates the job it belongs to.
+func (c *Cleaner) remove(a CleanupAction) error {
+	switch a.Kind {
+	case CleanupSession:
+		return c.cfg.SessionStore.Delete(a.Target)
+
+	case CleanupWorktree:
+		gitMgr := c.gitManager()
+		if gitMgr == nil {
+			return fmt.Errorf("no territory")
+		}
+		var err error
+		if a.JobID != "" {
+			err = gitMgr.RemoveJobWorktree(a.JobID, true)
+		} else {
+			err = gitMgr.RemoveWorktree(a.Worker, true)
+		}
+		if err != nil {
+			return err
+		}
+		if j := c.findJob(a.JobID); j != nil && j.GetWorktree() != "" {
+			j.SetWorktree("", j.GetBranch())
+			c.cfg.Jobs.Save(j)
+		}
+		return nil
+
+	case CleanupBranch:
+		gitMgr := c.gitManager()
+		if gitMgr == nil {
+			return fmt.Errorf("no territory")
+		}
+		if err := gitMgr.DeleteBranch(a.Target, true); err != nil {
+			return err
+		}
+		if j := c.findJob(a.JobID); j != nil && j.GetBranch() == a.Target {
+			j.ClearWorktree()
+			c.cfg.Jobs.Save(j)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown kind %q", a.Kind)
 }
 
-func (c *Cleaner) shouldDeleteBranch(branch string) bool {
-	// Only delete cosa/ prefixed branches
-	return strings.HasPrefix(branch, "cosa/")
+// modTime returns when path was last modified, or the zero time if it does
+// not exist.
+func modTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
 }
 
-func (c *Cleaner) deleteBranch(branch string) error {
-	return c.runGitCommand("branch", "-D", branch)
+// dirSize returns the bytes taken up by the files under path.
+func dirSize(path string) int64 {
+	var size int64
+	filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
 }
 
-func (c *Cleaner) runGitCommand(args ...string) error {
-	cmd := filepath.Join(c.cfg.GitManager.RepoRoot())
-	return runGitInDir(cmd, args...)
+// formatAge describes a duration in the largest whole unit.
+func formatAge(d time.Duration) string {
+	switch {
+	case d >= 48*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
 }
 
-// Helper to run git commands - uses exec directly
-func runGitInDir(dir string, args ...string) error {
-	return nil // Simplified - actual implementation would use exec.Command
+// formatSize describes a byte count in megabytes or gigabytes.
+func formatSize(n int64) string {
+	if n >= 1<<30 {
+		return fmt.Sprintf("%.1f GB", float64(n)/(1<<30))
+	}
+	return fmt.Sprintf("%d MB", n>>20)
 }
 
 // CleanupEventData contains data for cleanup.completed events.
@@ -254,9 +528,23 @@ type CleanupEventData struct {
 	SessionsCleaned  int   `json:"sessions_cleaned"`
 	WorktreesCleaned int   `json:"worktrees_cleaned"`
 	BranchesCleaned  int   `json:"branches_cleaned"`
+	BytesFreed       int64 `json:"bytes_freed"`
 	ErrorCount       int   `json:"error_count"`
 	DurationMs       int64 `json:"duration_ms"`
 }
 
-// EventCleanupCompleted is the event type for cleanup completion.
-const EventCleanupCompleted ledger.EventType = "cleanup.completed"
+// CleanupRemovedEventData contains data for cleanup.removed events.
+type CleanupRemovedEventData struct {
+	Kind   string `json:"kind"`
+	Target string `json:"target"`
+	JobID  string `json:"job_id,omitempty"`
+	Worker string `json:"worker,omitempty"`
+	Size   int64  `json:"size,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// Cleanup event types.
+const (
+	EventCleanupCompleted ledger.EventType = "cleanup.completed"
+	EventCleanupRemoved   ledger.EventType = "cleanup.removed"
+)
@@ -0,0 +1,133 @@
+package worker
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cosa/internal/git"
+	"cosa/internal/job"
+)
+
+// finishedJob returns a job that reached status at when.
+func finishedJob(status job.Status, when time.Time) *job.Job {
+	j := job.New("Do something")
+	j.Status = status
+	j.CompletedAt = &when
+	return j
+}
+
+func newCleanerRepo(t *testing.T) *git.Manager {
+	t.Helper()
+	dir := initSnapshotRepo(t)
+	return git.NewManager(dir, filepath.Join(t.TempDir(), "worktrees"))
+}
+
+func TestCleaner_Plan(t *testing.T) {
+	gitMgr := newCleanerRepo(t)
+	jobs := job.NewStore()
+	old := time.Now().Add(-48 * time.Hour)
+
+	running := job.New("Still running")
+	running.Status = job.StatusRunning
+	failed := finishedJob(job.StatusFailed, old)
+	completed := finishedJob(job.StatusCompleted, old)
+	cancelled := finishedJob(job.StatusCancelled, old)
+	recent := finishedJob(job.StatusFailed, time.Now())
+	for _, j := range []*job.Job{running, failed, completed, cancelled, recent} {
+		jobs.Add(j)
+	}
+
+	for _, j := range []*job.Job{running, failed, completed, recent} {
+		if _, err := gitMgr.CreateJobWorktree(j.ID, "main"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The cancelled job's worktree is gone, leaving its branch
+	if _, err := gitMgr.CreateJobWorktree(cancelled.ID, "main"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitMgr.RemoveJobWorktree(cancelled.ID, true); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewCleaner(CleanerConfig{
+		Jobs:       jobs,
+		GitManager: func() *git.Manager { return gitMgr },
+	})
+	actions, errs := c.Plan()
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	got := make(map[string]bool)
+	for _, a := range actions {
+		got[a.Kind+" "+a.JobID] = true
+		if a.Reason == "" {
+			t.Errorf("expected a reason for %+v", a)
+		}
+	}
+	want := []string{
+		"worktree " + failed.ID,
+		"branch " + failed.ID,
+		"worktree " + completed.ID,
+		"branch " + cancelled.ID,
+	}
+	for _, w := range want {
+		if !got[w] {
+			t.Errorf("expected %s to be removed, got %v", w, got)
+		}
+	}
+	if len(actions) != len(want) {
+		t.Errorf("expected %d actions, got %d: %v", len(want), len(actions), got)
+	}
+
+	stats := c.RunNow()
+	if stats.WorktreesCleaned != 2 || stats.BranchesCleaned != 2 || len(stats.Errors) > 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if failed.GetBranch() != "" || completed.GetWorktree() != "" {
+		t.Error("expected removed worktrees and branches cleared from their jobs")
+	}
+	if _, err := os.Stat(gitMgr.GetJobWorktreePath(running.ID)); err != nil {
+		t.Error("expected the running job's worktree to be kept")
+	}
+	out, _ := exec.Command("git", "-C", gitMgr.RepoRoot(), "branch", "--list", "cosa/job/*").Output()
+	if strings.Contains(string(out), failed.ID[:8]) || !strings.Contains(string(out), completed.ID[:8]) {
+		t.Errorf("expected only the completed job's branch kept, got %s", out)
+	}
+}
+
+func TestCleaner_DiskQuota(t *testing.T) {
+	gitMgr := newCleanerRepo(t)
+	jobs := job.NewStore()
+
+	older := finishedJob(job.StatusFailed, time.Now().Add(-2*time.Hour))
+	newer := finishedJob(job.StatusFailed, time.Now().Add(-time.Hour))
+	for _, j := range []*job.Job{older, newer} {
+		jobs.Add(j)
+		wt, err := gitMgr.CreateJobWorktree(j.ID, "main")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(wt.Path, "build.out"), make([]byte, 64*1024), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := NewCleaner(CleanerConfig{
+		Jobs:         jobs,
+		GitManager:   func() *git.Manager { return gitMgr },
+		MaxDiskUsage: 100 * 1024,
+	})
+	actions, _ := c.Plan()
+	if len(actions) != 1 || actions[0].JobID != older.ID || actions[0].Kind != CleanupWorktree {
+		t.Fatalf("expected the older worktree removed over quota, got %+v", actions)
+	}
+	if !strings.Contains(actions[0].Reason, "quota") {
+		t.Errorf("expected quota reason, got %q", actions[0].Reason)
+	}
+}
@@ -0,0 +1,73 @@
+package worker
+
+import (
+	"fmt"
+	"strings"
+
+	"cosa/internal/claude"
+	"cosa/internal/job"
+)
+
+// FallbackSource returns the model to switch to when model is out of
+// capacity or rate limited, or "" if there is none. It is called on every
+// failure, so fallbacks changed while a worker runs apply at once.
+type FallbackSource func(model string) string
+
+// ModelSource returns the model a job runs on when it does not set its
+// own, or "" to keep the model the worker was created with. It is called
+// as each job starts, so a changed default applies from the next job.
+type ModelSource func() string
+
+// next follows the fallback chain from model to the first model not in
+// tried, or returns "" when the chain runs out.
+func (f FallbackSource) next(model string, tried map[string]bool) string {
+	if f == nil {
+		return ""
+	}
+	seen := map[string]bool{model: true}
+	for next := f(model); next != ""; next = f(next) {
+		if !tried[next] {
+			return next
+		}
+		if seen[next] {
+			break
+		}
+		seen[next] = true
+	}
+	return ""
+}
+
+// fallbackClient restarts a job that failed on current's model for lack of
+// capacity on the next model in the fallback chain, and returns the client
+// now running it. It returns nil, leaving the failure to the caller, when
+// reason is some other failure or there is no model left to try.
+func (w *Worker) fallbackClient(s *slot, j *job.Job, current *claude.Client, reason string, tried map[string]bool) *claude.Client {
+	if !claude.IsCapacityError(reason) || w.ctx.Err() != nil || j.GetStatus() == job.StatusCancelled {
+		return nil
+	}
+
+	model := current.Model()
+	next := w.fallback.next(model, tried)
+	if next == "" {
+		return nil
+	}
+	tried[next] = true
+	current.Stop()
+
+	cfg := current.CloneConfig(current.Workdir())
+	cfg.Model = next
+	client := claude.NewClient(cfg)
+	if err := client.Start(w.ctx, w.buildPrompt(j)); err != nil {
+		w.emitJobEvent(j, "error", fmt.Sprintf("Failed to start %s after %s was unavailable: %v", next, model, err))
+		return nil
+	}
+
+	w.mu.Lock()
+	s.client = client
+	w.mu.Unlock()
+	j.SetModelUsed(next)
+
+	reason, _, _ = strings.Cut(strings.TrimSpace(reason), "\n")
+	w.emitJobEvent(j, "model_fallback", fmt.Sprintf("%s unavailable (%s), retrying job on %s", model, reason, next))
+	return client
+}
@@ -0,0 +1,57 @@
+package worker
+
+import (
+	"testing"
+
+	"cosa/internal/claude"
+	"cosa/internal/job"
+)
+
+func TestFallbackSourceNext(t *testing.T) {
+	chain := map[string]string{"opus": "sonnet", "sonnet": "haiku"}
+	f := FallbackSource(func(model string) string { return chain[model] })
+
+	if got := f.next("opus", map[string]bool{"opus": true}); got != "sonnet" {
+		t.Errorf("expected opus to fall back to sonnet, got %q", got)
+	}
+	if got := f.next("opus", map[string]bool{"opus": true, "sonnet": true}); got != "haiku" {
+		t.Errorf("expected tried sonnet to be skipped for haiku, got %q", got)
+	}
+	if got := f.next("haiku", map[string]bool{"haiku": true}); got != "" {
+		t.Errorf("expected no fallback for haiku, got %q", got)
+	}
+
+	// A cycle ends once every model in it has been tried
+	chain["haiku"] = "opus"
+	tried := map[string]bool{"opus": true, "sonnet": true, "haiku": true}
+	if got := f.next("haiku", tried); got != "" {
+		t.Errorf("expected the cycle to end, got %q", got)
+	}
+
+	var none FallbackSource
+	if got := none.next("opus", map[string]bool{}); got != "" {
+		t.Errorf("expected no fallback without a source, got %q", got)
+	}
+}
+
+func TestHandleClaudeEvent_FallbackSession(t *testing.T) {
+	w := New(Config{Name: "paulie"})
+	j := job.New("Add login")
+	j.Queue()
+
+	w.handleClaudeEvent(w.slots[0], j, claude.Event{Type: claude.EventInit, SessionID: "sess-1"})
+	started := j.StartedAt
+
+	// The job restarted on a fallback model reports a new session
+	w.handleClaudeEvent(w.slots[0], j, claude.Event{Type: claude.EventInit, SessionID: "sess-2"})
+
+	if j.GetStatus() != job.StatusRunning {
+		t.Errorf("expected the job to keep running, got %s", j.GetStatus())
+	}
+	if j.SessionID != "sess-2" {
+		t.Errorf("expected the job to move to the fallback session, got %q", j.SessionID)
+	}
+	if j.StartedAt != started {
+		t.Error("expected the job to keep its start time")
+	}
+}
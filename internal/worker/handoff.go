@@ -71,38 +71,54 @@ func (w *Worker) InjectHandoffContext(summary *HandoffSummary) {
 		return
 	}
 
-	// Build context string from summary
-	var context []string
+	// Add context as standing orders
+	w.mu.Lock()
+	w.StandingOrders = append([]string{"[HANDOFF CONTEXT]"}, summary.context()...)
+	w.mu.Unlock()
+}
 
-	if summary.Summary != "" {
-		context = append(context, "Previous work summary: "+summary.Summary)
+// Notes renders the summary for the worker taking over the job, to be
+// given to it with the job.
+func (s *HandoffSummary) Notes() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("You are taking over this job from %s, who stopped partway through. ", s.WorkerName))
+	sb.WriteString("Their work so far is in the worktree, including any changes they had not committed; carry on from there rather than starting again.\n")
+	for _, line := range s.context() {
+		sb.WriteString(line + "\n")
 	}
+	return strings.TrimSpace(sb.String())
+}
+
+// context describes the summary as lines of text.
+func (s *HandoffSummary) context() []string {
+	var lines []string
 
-	if len(summary.Decisions) > 0 {
-		context = append(context, "Key decisions made:")
-		for _, d := range summary.Decisions {
-			context = append(context, "  - "+d)
+	if s.Summary != "" {
+		lines = append(lines, "Previous work summary: "+s.Summary)
+	}
+
+	if len(s.Decisions) > 0 {
+		lines = append(lines, "Key decisions made:")
+		for _, d := range s.Decisions {
+			lines = append(lines, "  - "+d)
 		}
 	}
 
-	if len(summary.FilesTouched) > 0 {
-		context = append(context, "Files modified:")
-		for _, f := range summary.FilesTouched {
-			context = append(context, "  - "+f)
+	if len(s.FilesTouched) > 0 {
+		lines = append(lines, "Files modified:")
+		for _, f := range s.FilesTouched {
+			lines = append(lines, "  - "+f)
 		}
 	}
 
-	if len(summary.OpenQuestions) > 0 {
-		context = append(context, "Open questions:")
-		for _, q := range summary.OpenQuestions {
-			context = append(context, "  - "+q)
+	if len(s.OpenQuestions) > 0 {
+		lines = append(lines, "Open questions:")
+		for _, q := range s.OpenQuestions {
+			lines = append(lines, "  - "+q)
 		}
 	}
 
-	// Add context as standing orders
-	w.mu.Lock()
-	w.StandingOrders = append([]string{"[HANDOFF CONTEXT]"}, context...)
-	w.mu.Unlock()
+	return lines
 }
 
 // SaveHandoffSummary persists a handoff summary to disk.
@@ -0,0 +1,81 @@
+package worker
+
+import (
+	"fmt"
+
+	"cosa/internal/git"
+	"cosa/internal/job"
+)
+
+// CommitIdentity is who a worker's commits in job worktrees are made as,
+// how they are signed and whether they name their job.
+type CommitIdentity struct {
+	Name  string // Author and committer; empty leaves it to git
+	Email string
+
+	SigningFormat string // "gpg" or "ssh"; empty leaves signing to git
+	SigningKey    string // Key ID or public key file; empty uses git's default
+
+	// TrailerHooks is a hooks directory from git.InstallJobHooks that adds
+	// the job trailer to every commit. Empty adds none.
+	TrailerHooks string
+}
+
+// Author returns the identity as "Name <email>", or "" if it has none.
+func (c CommitIdentity) Author() string {
+	if c.Name == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s <%s>", c.Name, c.Email)
+}
+
+// GetIdentity returns who the worker's commits are made as.
+func (w *Worker) GetIdentity() CommitIdentity {
+	return w.identity
+}
+
+// commitEnv returns the environment for commits made for j in the worktree
+// at dir: the worker's identity, git config that signs the commits and
+// the hooks that add the job trailer.
+func (w *Worker) commitEnv(j *job.Job, dir string) []string {
+	id := w.identity
+
+	var env []string
+	if id.Name != "" {
+		env = append(env,
+			"GIT_AUTHOR_NAME="+id.Name, "GIT_AUTHOR_EMAIL="+id.Email,
+			"GIT_COMMITTER_NAME="+id.Name, "GIT_COMMITTER_EMAIL="+id.Email,
+		)
+	}
+
+	// Settings given as GIT_CONFIG_KEY_n/GIT_CONFIG_VALUE_n override the
+	// repository's config for every git command Claude runs
+	var config [][2]string
+	if id.SigningFormat != "" {
+		format := id.SigningFormat
+		if format == "gpg" {
+			format = "openpgp"
+		}
+		config = append(config, [2]string{"commit.gpgsign", "true"}, [2]string{"gpg.format", format})
+		if id.SigningKey != "" {
+			config = append(config, [2]string{"user.signingkey", id.SigningKey})
+		}
+	}
+	if id.TrailerHooks != "" {
+		// The hooks run the repository's own, so find them before
+		// core.hooksPath is pointed elsewhere
+		if hooks, err := git.HooksDir(dir); err == nil {
+			env = append(env, git.EnvRepoHooks+"="+hooks)
+		}
+		env = append(env, git.EnvJobID+"="+j.ID)
+		config = append(config, [2]string{"core.hooksPath", id.TrailerHooks})
+	}
+
+	if len(config) > 0 {
+		env = append(env, fmt.Sprintf("GIT_CONFIG_COUNT=%d", len(config)))
+		for i, kv := range config {
+			env = append(env, fmt.Sprintf("GIT_CONFIG_KEY_%d=%s", i, kv[0]), fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", i, kv[1]))
+		}
+	}
+	return env
+}
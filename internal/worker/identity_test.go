@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"cosa/internal/git"
+	"cosa/internal/job"
+)
+
+func TestWorker_SnapshotCommitsAsTheWorker(t *testing.T) {
+	dir := initSnapshotRepo(t)
+
+	// The repository's own hook still runs
+	marker := filepath.Join(t.TempDir(), "ran")
+	hook := "#!/bin/sh\ntouch " + marker + "\n"
+	if err := os.WriteFile(filepath.Join(dir, ".git", "hooks", "commit-msg"), []byte(hook), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	hooks := t.TempDir()
+	if err := git.InstallJobHooks(hooks); err != nil {
+		t.Fatal(err)
+	}
+
+	w := New(Config{Name: "paulie", Identity: CommitIdentity{
+		Name:         "Paulie",
+		Email:        "paulie+cosa@me.dev",
+		TrailerHooks: hooks,
+	}})
+	j := job.New("Add a file")
+
+	if err := w.snapshotWorktree(j, dir); err != nil {
+		t.Fatalf("expected snapshot to succeed: %v", err)
+	}
+
+	cmd := exec.Command("git", "log", "-1", "--format=%an <%ae>%n%cn <%ce>%n%(trailers:key=Cosa-Job,valueonly)")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	want := []string{"Paulie <paulie+cosa@me.dev>", "Paulie <paulie+cosa@me.dev>", j.ID}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected author, committer and trailer %q, got %q", want, lines)
+	}
+
+	if _, err := os.Stat(marker); err != nil {
+		t.Error("expected the repository's commit-msg hook to run")
+	}
+}
+
+func TestWorker_CommitEnvSigns(t *testing.T) {
+	w := New(Config{Name: "paulie", Identity: CommitIdentity{SigningFormat: "gpg", SigningKey: "ABC123"}})
+	env := w.commitEnv(job.New("sign"), t.TempDir())
+
+	for _, want := range []string{
+		"GIT_CONFIG_COUNT=3",
+		"GIT_CONFIG_KEY_0=commit.gpgsign", "GIT_CONFIG_VALUE_0=true",
+		"GIT_CONFIG_KEY_1=gpg.format", "GIT_CONFIG_VALUE_1=openpgp",
+		"GIT_CONFIG_KEY_2=user.signingkey", "GIT_CONFIG_VALUE_2=ABC123",
+	} {
+		if !slices.Contains(env, want) {
+			t.Errorf("expected %s in %q", want, env)
+		}
+	}
+	if len(env) != 7 {
+		t.Error("expected the author to be left to git without a name")
+	}
+}
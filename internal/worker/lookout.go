@@ -3,6 +3,7 @@ package worker
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -14,15 +15,19 @@ type LookoutConfig struct {
 	// CheckInterval is how often to check worker health (default: 30s).
 	CheckInterval time.Duration
 
-	// WarningThreshold is the inactivity time before emitting a warning (default: 5m).
+	// WarningThreshold is the time without progress before emitting a warning (default: 5m).
 	WarningThreshold time.Duration
 
-	// ErrorThreshold is the inactivity time before marking as error (default: 15m).
+	// ErrorThreshold is the time without progress before marking as error (default: 15m).
 	ErrorThreshold time.Duration
 
-	// CriticalThreshold is the inactivity time before stopping worker (default: 30m).
+	// CriticalThreshold is the time without progress before stopping worker (default: 30m).
 	CriticalThreshold time.Duration
 
+	// Roles overrides the thresholds for workers by role. Severities left
+	// at zero use the thresholds above.
+	Roles map[Role]StuckThresholds
+
 	// Pool is the worker pool to monitor.
 	Pool *Pool
 
@@ -31,6 +36,25 @@ type LookoutConfig struct {
 
 	// OnStuck is called when a worker is detected as stuck.
 	OnStuck func(w *Worker, severity StuckSeverity)
+
+	// Policy is the remediation for each severity (default:
+	// DefaultRemediationPolicy).
+	Policy RemediationPolicy
+
+	// Overrides replace Policy actions for workers, by worker name.
+	Overrides map[string]RemediationPolicy
+
+	// Remediate restarts or requeues a stuck worker's job, which needs the
+	// job queue. Without it those actions only notify.
+	Remediate func(w *Worker, action RemediationAction, reason string) error
+}
+
+// StuckThresholds are how long a worker may go without progress before it
+// is stuck at each severity.
+type StuckThresholds struct {
+	Warning  time.Duration
+	Error    time.Duration
+	Critical time.Duration
 }
 
 // StuckSeverity indicates the severity level of a stuck worker.
@@ -76,6 +100,28 @@ func NewLookout(cfg LookoutConfig) *Lookout {
 	}
 }
 
+// SetThresholds replaces the stuck thresholds, as when the config is
+// reloaded. Thresholds left at zero keep their defaults. Workers already
+// warned about stay warned until they make progress.
+func (l *Lookout) SetThresholds(t StuckThresholds, roles map[Role]StuckThresholds) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.cfg.WarningThreshold = t.Warning
+	if l.cfg.WarningThreshold == 0 {
+		l.cfg.WarningThreshold = 5 * time.Minute
+	}
+	l.cfg.ErrorThreshold = t.Error
+	if l.cfg.ErrorThreshold == 0 {
+		l.cfg.ErrorThreshold = 15 * time.Minute
+	}
+	l.cfg.CriticalThreshold = t.Critical
+	if l.cfg.CriticalThreshold == 0 {
+		l.cfg.CriticalThreshold = 30 * time.Minute
+	}
+	l.cfg.Roles = roles
+}
+
 // Start begins the health monitoring loop.
 func (l *Lookout) Start(ctx context.Context) {
 	l.ctx, l.cancel = context.WithCancel(ctx)
@@ -155,7 +201,7 @@ func (l *Lookout) checkWorker(w *Worker) {
 			WorkerID:     w.ID,
 			WorkerName:   w.Name,
 			Severity:     string(severity),
-			InactiveSecs: int64(time.Since(w.GetLastActivity()).Seconds()),
+			InactiveSecs: int64(w.SinceProgress().Seconds()),
 			JobID:        l.getCurrentJobID(w),
 		})
 	}
@@ -165,33 +211,103 @@ func (l *Lookout) checkWorker(w *Worker) {
 		l.cfg.OnStuck(w, severity)
 	}
 
-	// Take action based on severity
-	switch severity {
-	case SeverityError:
-		// Pause the worker (mark as error status)
-		w.mu.Lock()
-		w.Status = StatusError
-		w.mu.Unlock()
+	// Remediate according to the worker's policy
+	if action := l.actionFor(w, severity); action != ActionNotify {
+		l.wg.Add(1)
+		go func() {
+			defer l.wg.Done()
+			l.remediate(w, severity, action)
+		}()
+	}
+}
+
+// actionFor returns the remediation for a worker stuck at severity.
+func (l *Lookout) actionFor(w *Worker, severity StuckSeverity) RemediationAction {
+	if action, ok := l.cfg.Overrides[w.Name][severity]; ok && action != "" {
+		return action
+	}
+	if action, ok := l.cfg.Policy[severity]; ok && action != "" {
+		return action
+	}
+	if action, ok := DefaultRemediationPolicy[severity]; ok {
+		return action
+	}
+	return ActionNotify
+}
+
+// remediate takes action on a stuck worker and records it in the ledger.
+func (l *Lookout) remediate(w *Worker, severity StuckSeverity, action RemediationAction) {
+	jobID := l.getCurrentJobID(w)
+	inactive := w.SinceProgress()
+
+	var err error
+	switch action {
+	case ActionNudge:
+		err = w.Nudge(inactive)
+	case ActionRestart, ActionRequeue:
+		if l.cfg.Remediate == nil {
+			err = fmt.Errorf("%s is not supported", action)
+			break
+		}
+		reason := fmt.Sprintf("worker stuck: no progress for %s", inactive.Round(time.Second))
+		err = l.cfg.Remediate(w, action, reason)
+	default:
+		err = fmt.Errorf("unknown remediation action %q", action)
+	}
 
-	case SeverityCritical:
-		// Stop the worker
-		w.Stop()
+	data := WorkerRemediatedEventData{
+		WorkerID:   w.ID,
+		WorkerName: w.Name,
+		Severity:   string(severity),
+		Action:     string(action),
+		JobID:      jobID,
+	}
+	if err != nil {
+		data.Error = err.Error()
+	}
+	if l.cfg.Ledger != nil {
+		l.cfg.Ledger.Append(ledger.EventWorkerRemediated, data)
 	}
 }
 
 func (l *Lookout) determineSeverity(w *Worker) StuckSeverity {
-	if w.IsStuck(l.cfg.CriticalThreshold) {
+	t := l.thresholdsFor(w)
+	if w.IsStuck(t.Critical) {
 		return SeverityCritical
 	}
-	if w.IsStuck(l.cfg.ErrorThreshold) {
+	if w.IsStuck(t.Error) {
 		return SeverityError
 	}
-	if w.IsStuck(l.cfg.WarningThreshold) {
+	if w.IsStuck(t.Warning) {
 		return SeverityWarning
 	}
 	return ""
 }
 
+// thresholdsFor returns the stuck thresholds for w, with its role's
+// overrides applied.
+func (l *Lookout) thresholdsFor(w *Worker) StuckThresholds {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	t := StuckThresholds{
+		Warning:  l.cfg.WarningThreshold,
+		Error:    l.cfg.ErrorThreshold,
+		Critical: l.cfg.CriticalThreshold,
+	}
+	override := l.cfg.Roles[w.Role]
+	if override.Warning > 0 {
+		t.Warning = override.Warning
+	}
+	if override.Error > 0 {
+		t.Error = override.Error
+	}
+	if override.Critical > 0 {
+		t.Critical = override.Critical
+	}
+	return t
+}
+
 func (l *Lookout) isHigherSeverity(a, b StuckSeverity) bool {
 	severityOrder := map[StuckSeverity]int{
 		SeverityWarning:  1,
@@ -201,6 +317,19 @@ func (l *Lookout) isHigherSeverity(a, b StuckSeverity) bool {
 	return severityOrder[a] > severityOrder[b]
 }
 
+// Warnings returns the severity of the current warning for each stuck
+// worker, by worker ID.
+func (l *Lookout) Warnings() map[string]StuckSeverity {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	warnings := make(map[string]StuckSeverity, len(l.warnedWorkers))
+	for id, severity := range l.warnedWorkers {
+		warnings[id] = severity
+	}
+	return warnings
+}
+
 func (l *Lookout) clearWarning(workerID string) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
@@ -239,3 +368,13 @@ type WorkerStuckEventData struct {
 	InactiveSecs int64  `json:"inactive_secs"`
 	JobID        string `json:"job_id,omitempty"`
 }
+
+// WorkerRemediatedEventData contains data for worker.remediated events.
+type WorkerRemediatedEventData struct {
+	WorkerID   string `json:"worker_id"`
+	WorkerName string `json:"worker_name"`
+	Severity   string `json:"severity"`
+	Action     string `json:"action"`
+	JobID      string `json:"job_id,omitempty"`
+	Error      string `json:"error,omitempty"` // Set if the action failed
+}
@@ -0,0 +1,133 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cosa/internal/ledger"
+)
+
+func TestLookout_ActionFor(t *testing.T) {
+	l := NewLookout(LookoutConfig{
+		Policy: RemediationPolicy{SeverityCritical: ActionRestart},
+		Overrides: map[string]RemediationPolicy{
+			"paulie": {SeverityError: ActionRequeue},
+		},
+	})
+
+	silvio := New(Config{Name: "silvio"})
+	paulie := New(Config{Name: "paulie"})
+
+	tests := []struct {
+		w        *Worker
+		severity StuckSeverity
+		want     RemediationAction
+	}{
+		{silvio, SeverityWarning, ActionNotify}, // Default policy
+		{silvio, SeverityError, ActionNudge},
+		{silvio, SeverityCritical, ActionRestart}, // Configured policy
+		{paulie, SeverityError, ActionRequeue},    // Worker override
+		{paulie, SeverityCritical, ActionRestart},
+	}
+
+	for _, tt := range tests {
+		if got := l.actionFor(tt.w, tt.severity); got != tt.want {
+			t.Errorf("%s at %s: expected %s, got %s", tt.w.Name, tt.severity, tt.want, got)
+		}
+	}
+}
+
+func TestLookout_RoleThresholds(t *testing.T) {
+	l := NewLookout(LookoutConfig{
+		Roles: map[Role]StuckThresholds{
+			RoleCapo: {Warning: time.Hour},
+		},
+	})
+
+	soldato := New(Config{Name: "silvio", Role: RoleSoldato})
+	capo := New(Config{Name: "tony", Role: RoleCapo})
+	for _, w := range []*Worker{soldato, capo} {
+		w.Status = StatusWorking
+		w.LastProgressAt = time.Now().Add(-10 * time.Minute)
+	}
+
+	if got := l.determineSeverity(soldato); got != SeverityWarning {
+		t.Errorf("expected warning for soldato after 10m, got %q", got)
+	}
+	if got := l.determineSeverity(capo); got != "" {
+		t.Errorf("expected capo within its 1h threshold, got %q", got)
+	}
+
+	// Severities the role leaves unset keep the defaults
+	capo.LastProgressAt = time.Now().Add(-45 * time.Minute)
+	if got := l.determineSeverity(capo); got != SeverityCritical {
+		t.Errorf("expected critical for capo after 45m, got %q", got)
+	}
+}
+
+func TestLookout_SetThresholds(t *testing.T) {
+	l := NewLookout(LookoutConfig{})
+	capo := New(Config{Name: "tony", Role: RoleCapo})
+	capo.Status = StatusWorking
+	capo.LastProgressAt = time.Now().Add(-10 * time.Minute)
+
+	if got := l.determineSeverity(capo); got != SeverityWarning {
+		t.Fatalf("expected warning after 10m with the defaults, got %q", got)
+	}
+
+	l.SetThresholds(StuckThresholds{Warning: 2 * time.Minute, Error: 8 * time.Minute}, nil)
+	if got := l.determineSeverity(capo); got != SeverityError {
+		t.Errorf("expected error after 10m with an 8m threshold, got %q", got)
+	}
+
+	l.SetThresholds(StuckThresholds{}, map[Role]StuckThresholds{RoleCapo: {Warning: time.Hour}})
+	if got := l.determineSeverity(capo); got != "" {
+		t.Errorf("expected capo within its reloaded 1h threshold, got %q", got)
+	}
+}
+
+func TestLookout_RemediateRecordsEvent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	l, err := ledger.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open ledger: %v", err)
+	}
+	defer l.Close()
+
+	var remediated RemediationAction
+	lookout := NewLookout(LookoutConfig{
+		Ledger: l,
+		Remediate: func(w *Worker, action RemediationAction, reason string) error {
+			remediated = action
+			if action == ActionRequeue {
+				return errors.New("queue full")
+			}
+			return nil
+		},
+	})
+
+	w := New(Config{Name: "paulie"})
+	lookout.remediate(w, SeverityCritical, ActionRestart)
+	lookout.remediate(w, SeverityCritical, ActionRequeue)
+
+	if remediated != ActionRequeue {
+		t.Errorf("expected remediate callback for requeue, got %s", remediated)
+	}
+
+	events, err := ledger.Read(path)
+	if err != nil {
+		t.Fatalf("failed to read ledger: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+
+	var data WorkerRemediatedEventData
+	json.Unmarshal(events[1].Data, &data)
+	if events[1].Type != ledger.EventWorkerRemediated || data.Action != "requeue" || data.Error != "queue full" {
+		t.Errorf("unexpected remediation event: %s %+v", events[1].Type, data)
+	}
+}
@@ -0,0 +1,71 @@
+package worker
+
+import (
+	"cosa/internal/claude"
+	"cosa/internal/job"
+)
+
+// messagesIntro introduces the held messages in the built-in prompt.
+const messagesIntro = "Messages sent to you while you were between jobs. Act on any that concern this task."
+
+// delegationIntro tells a worker that may delegate how to, in the built-in
+// prompt.
+const delegationIntro = "You may hand self-contained parts of this task to the workers you supervise with the cosa_delegate_job tool. Each delegated job runs in its own worktree and is merged like any other. You get a message when one finishes, and cosa_get_job shows how it is going; wait for the jobs your work depends on before you finish."
+
+// Post sends a message to the worker from another worker or the daemon. A
+// working worker gets it in its running session; otherwise it is held and
+// given with the prompt of the worker's next job. Post reports whether the
+// message reached a running session.
+func (w *Worker) Post(message string) bool {
+	if w.SendMessage(message) == nil {
+		return true
+	}
+	w.mu.Lock()
+	w.mailbox = append(w.mailbox, message)
+	w.mu.Unlock()
+	return false
+}
+
+// PostTo sends a message to the session running the job jobID, such as the
+// report of a job delegated from it. If the worker is not running the job,
+// the message is held like Post's. PostTo reports whether the message
+// reached the job's session.
+func (w *Worker) PostTo(jobID, message string) bool {
+	w.mu.RLock()
+	var client *claude.Client
+	if s := w.slotFor(jobID); s != nil {
+		client = s.client
+	}
+	w.mu.RUnlock()
+
+	if client != nil && client.SendInput(message) == nil {
+		return true
+	}
+	w.mu.Lock()
+	w.mailbox = append(w.mailbox, message)
+	w.mu.Unlock()
+	return false
+}
+
+// MCPConfigSource returns the MCP config file the session running j is
+// started with, or "" to keep the worker's own. It lets the tools know
+// which of the worker's jobs they act for.
+type MCPConfigSource func(j *job.Job) string
+
+// takeMessages returns the messages held for the worker, emptying its
+// mailbox.
+func (w *Worker) takeMessages() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	messages := w.mailbox
+	w.mailbox = nil
+	return messages
+}
+
+// canDelegate reports whether the worker may delegate jobs: its role
+// allows it, and its sessions have the tool to.
+func (w *Worker) canDelegate() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return CanDelegate(w.Role) && w.client != nil && w.client.MCPConfig() != ""
+}
@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"strings"
+	"testing"
+
+	"cosa/internal/claude"
+	"cosa/internal/job"
+)
+
+func TestWorker_Post(t *testing.T) {
+	w := New(Config{Name: "tony", Role: RoleCapo})
+
+	// An idle worker has no session to send to, so messages are held
+	if w.Post("Job abc completed") {
+		t.Error("expected the message to be held for an idle worker")
+	}
+	w.Post("Job def failed")
+
+	j := job.New("Build the billing page")
+	j.AddMessages(w.takeMessages()...)
+	got := w.buildPrompt(j)
+	if !strings.Contains(got, "## Messages\n"+messagesIntro+"\n\n- Job abc completed\n- Job def failed\n\n") {
+		t.Errorf("expected the held messages in the prompt, got %q", got)
+	}
+
+	if messages := w.takeMessages(); len(messages) != 0 {
+		t.Errorf("expected the mailbox to be empty once taken, got %v", messages)
+	}
+}
+
+func TestWorker_PostTo(t *testing.T) {
+	w := New(Config{Name: "tony", Role: RoleCapo, Slots: 2})
+	j := job.New("Build the billing page")
+	w.slots[1].job = j
+
+	// A slot running the job without a session yet cannot be sent to
+	if w.PostTo(j.ID, "Job abc completed") {
+		t.Error("expected the message to be held without a session")
+	}
+	if w.PostTo("other-job", "Job def failed") {
+		t.Error("expected the message to be held for a job the worker is not running")
+	}
+	if got := w.takeMessages(); len(got) != 2 {
+		t.Errorf("expected both messages held, got %v", got)
+	}
+}
+
+func TestWorker_BuildPrompt_Delegation(t *testing.T) {
+	tests := []struct {
+		name string
+		role Role
+		mcp  string
+		want bool
+	}{
+		{name: "capo", role: RoleCapo, mcp: "/tmp/mcp.json", want: true},
+		{name: "capo without the tool", role: RoleCapo},
+		{name: "soldato", role: RoleSoldato, mcp: "/tmp/mcp.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := New(Config{Name: "tony", Role: tt.role, ClaudeConfig: claude.ClientConfig{MCPConfig: tt.mcp}})
+			got := strings.Contains(w.buildPrompt(job.New("Build the billing page")), "## Delegation")
+			if got != tt.want {
+				t.Errorf("expected delegation in the prompt: %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
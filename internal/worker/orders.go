@@ -0,0 +1,43 @@
+package worker
+
+import "strings"
+
+// OrderSource looks up the standing orders a worker of role inherits: the
+// orders given to every worker, followed by those given to its role. It is
+// called for every job, so orders changed while a worker runs apply to its
+// next job.
+type OrderSource func(role Role) []string
+
+// MergeOrders combines standing orders from the least to the most specific
+// scope (global, role, worker). Orders keep their scope's place, so the
+// most specific ones come last, and an order given in several scopes is
+// listed once, where it first appears.
+func MergeOrders(scopes ...[]string) []string {
+	var merged []string
+	seen := make(map[string]bool)
+	for _, orders := range scopes {
+		for _, order := range orders {
+			key := strings.TrimSpace(order)
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			merged = append(merged, order)
+		}
+	}
+	return merged
+}
+
+// EffectiveOrders returns the standing orders the worker's prompt includes:
+// its inherited orders followed by its own.
+func (w *Worker) EffectiveOrders() []string {
+	w.mu.RLock()
+	own := w.StandingOrders
+	inherited := w.orders
+	w.mu.RUnlock()
+
+	if inherited == nil {
+		return MergeOrders(own)
+	}
+	return MergeOrders(inherited(w.Role), own)
+}
@@ -0,0 +1,52 @@
+package worker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"cosa/internal/job"
+)
+
+func TestMergeOrders(t *testing.T) {
+	got := MergeOrders(
+		[]string{"Never push to main", "Run the tests"},
+		[]string{"Keep commits small", " Run the tests "},
+		nil,
+		[]string{"", "Use the v2 API", "Never push to main"},
+	)
+	want := []string{"Never push to main", "Run the tests", "Keep commits small", "Use the v2 API"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	if got := MergeOrders(); got != nil {
+		t.Errorf("expected no orders, got %v", got)
+	}
+}
+
+func TestWorker_EffectiveOrders(t *testing.T) {
+	var asked Role
+	w := New(Config{
+		Name: "vito",
+		Role: RoleCapo,
+		Orders: func(role Role) []string {
+			asked = role
+			return []string{"Never push to main", "Review twice"}
+		},
+	})
+	w.SetStandingOrders([]string{"Review twice", "Own the billing code"})
+
+	want := []string{"Never push to main", "Review twice", "Own the billing code"}
+	if got := w.EffectiveOrders(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if asked != RoleCapo {
+		t.Errorf("expected orders to be looked up for capo, got %q", asked)
+	}
+
+	prompt := w.buildPrompt(job.New("Add login"))
+	if !strings.Contains(prompt, "## Standing Orders\n- Never push to main\n- Review twice\n- Own the billing code\n") {
+		t.Errorf("expected merged orders in the prompt, got %q", prompt)
+	}
+}
@@ -2,25 +2,50 @@ package worker
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sync"
 
 	"cosa/internal/job"
 )
 
+// ErrInvalidWorkerName is returned when a worker name fails validation.
+var ErrInvalidWorkerName = errors.New("invalid worker name")
+
+// workerNamePattern allows alphanumerics, hyphens and underscores, starting
+// with an alphanumeric character, up to 64 characters total.
+var workerNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]{0,63}$`)
+
+// ValidateWorkerName checks that a worker name is safe to use in file paths
+// and branch names.
+func ValidateWorkerName(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w: name is empty", ErrInvalidWorkerName)
+	}
+	if !workerNamePattern.MatchString(name) {
+		return fmt.Errorf("%w: %q must be 1-64 characters of letters, digits, '-' or '_' and start with a letter or digit", ErrInvalidWorkerName, name)
+	}
+	return nil
+}
+
 // WorkerInfo contains the persistent worker metadata.
 type WorkerInfo struct {
-	ID             string   `json:"id"`
-	Name           string   `json:"name"`
-	Role           Role     `json:"role"`
-	Worktree       string   `json:"worktree"`
-	Branch         string   `json:"branch"`
-	StandingOrders []string `json:"standing_orders,omitempty"`
-	SessionID      string   `json:"session_id,omitempty"`
-	JobsCompleted  int      `json:"jobs_completed"`
-	JobsFailed     int      `json:"jobs_failed"`
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	Role           Role              `json:"role"`
+	Worktree       string            `json:"worktree"`
+	Branch         string            `json:"branch"`
+	StandingOrders []string          `json:"standing_orders,omitempty"`
+	Labels         map[string]string `json:"labels,omitempty"`
+	SessionID      string            `json:"session_id,omitempty"`
+	JobsCompleted  int               `json:"jobs_completed"`
+	JobsFailed     int               `json:"jobs_failed"`
+	Ephemeral      bool              `json:"ephemeral,omitempty"`
+	RecentFailures []string          `json:"recent_failures,omitempty"`
+	Quarantine     *Quarantine       `json:"quarantine,omitempty"`
 }
 
 // Pool manages a collection of workers with availability tracking.
@@ -79,6 +104,10 @@ func (p *Pool) ClearPending() {
 
 // Add adds a worker to the pool.
 func (p *Pool) Add(w *Worker) error {
+	if err := ValidateWorkerName(w.Name); err != nil {
+		return err
+	}
+
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
@@ -176,28 +205,29 @@ func (p *Pool) Count() int {
 	return len(p.workers)
 }
 
-// GetAvailable returns all idle workers.
+// GetAvailable returns all workers with a free slot.
 func (p *Pool) GetAvailable() []*Worker {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
 	var available []*Worker
 	for _, w := range p.workers {
-		if w.GetStatus() == StatusIdle {
+		if w.FreeSlots() > 0 {
 			available = append(available, w)
 		}
 	}
 	return available
 }
 
-// GetAvailableByRole returns all idle workers with the given role.
+// GetAvailableByRole returns all workers with the given role and a free
+// slot.
 func (p *Pool) GetAvailableByRole(role Role) []*Worker {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
 	var available []*Worker
 	for _, w := range p.byRole[role] {
-		if w.GetStatus() == StatusIdle {
+		if w.FreeSlots() > 0 {
 			available = append(available, w)
 		}
 	}
@@ -206,23 +236,111 @@ func (p *Pool) GetAvailableByRole(role Role) []*Worker {
 
 // FindBestWorker selects the best available worker for a job.
 // Selection criteria:
-// 1. Must be idle
+// 1. Must have a free slot
 // 2. Must be a worker role (Soldato or Capo)
 // 3. Prefer Soldato over Capo for regular work
-// 4. Among same role, prefer worker with fewer completed jobs (load balancing)
+// 4. Among same role, prefer worker with fewer jobs running (load balancing)
+// 5. Then prefer worker with fewer completed jobs
+//
+// A job with a preferred worker waits for that worker while it is in the pool.
+// A job pinned to a sticky worker waits for that worker even while it is not.
 func (p *Pool) FindBestWorker(j *job.Job) *Worker {
+	return p.FindBestWorkerWhere(j, nil)
+}
+
+// FindBestWorkerWhere is like FindBestWorker but only considers workers for
+// which allow returns true. A nil allow considers all workers.
+func (p *Pool) FindBestWorkerWhere(j *job.Job, allow func(*Worker) bool) *Worker {
+	var refuse func(*Worker) string
+	if allow != nil {
+		refuse = func(w *Worker) string {
+			if allow(w) {
+				return ""
+			}
+			return "not allowed"
+		}
+	}
+	return p.findBestWorker(j, refuse, nil)
+}
+
+// Evaluation records how a worker fared when one was chosen for a job.
+type Evaluation struct {
+	Worker string // Worker name
+	Score  int    // Higher is better; only set for candidates
+	Reason string // Why the worker was passed over, or empty for a candidate
+}
+
+// TraceBestWorker is like FindBestWorker, but also returns how each worker
+// that could take the job was evaluated. refuse returns why a worker may
+// not take the job, or "" if it may; a nil refuse allows all workers.
+func (p *Pool) TraceBestWorker(j *job.Job, refuse func(*Worker) string) (*Worker, []Evaluation) {
+	var evals []Evaluation
+	best := p.findBestWorker(j, refuse, &evals)
+	return best, evals
+}
+
+func (p *Pool) findBestWorker(j *job.Job, refuse func(*Worker) string, trace *[]Evaluation) *Worker {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
+	note := func(w *Worker, score int, reason string) {
+		if trace != nil {
+			*trace = append(*trace, Evaluation{Worker: w.Name, Score: score, Reason: reason})
+		}
+	}
+	// eligible reports whether a worker with a free slot may take the job
+	eligible := func(w *Worker, what string) bool {
+		if w.FreeSlots() == 0 {
+			note(w, 0, what+string(w.GetStatus()))
+			return false
+		}
+		if refuse != nil {
+			if reason := refuse(w); reason != "" {
+				note(w, 0, reason)
+				return false
+			}
+		}
+		return true
+	}
+
+	if sticky := j.GetStickyWorker(); sticky != "" {
+		w, ok := p.workers[sticky]
+		if !ok {
+			if trace != nil {
+				*trace = append(*trace, Evaluation{Worker: sticky, Reason: "sticky worker is not in the pool"})
+			}
+			return nil
+		}
+		if !eligible(w, "sticky worker is ") {
+			return nil
+		}
+		note(w, 0, "")
+		return w
+	}
+
+	if preferred := j.GetPreferredWorker(); preferred != "" {
+		for _, w := range p.workers {
+			if w.ID != preferred {
+				continue
+			}
+			if !eligible(w, "preferred worker is ") {
+				return nil
+			}
+			note(w, 0, "")
+			return w
+		}
+	}
+
 	var best *Worker
 	var bestScore int = -1
+	jobLabels := j.GetLabels()
 
 	// Worker roles that can execute jobs
 	workerRoles := []Role{RoleSoldato, RoleCapo}
 
 	for _, role := range workerRoles {
 		for _, w := range p.byRole[role] {
-			if w.GetStatus() != StatusIdle {
+			if !eligible(w, "is ") {
 				continue
 			}
 
@@ -230,11 +348,18 @@ func (p *Pool) FindBestWorker(j *job.Job) *Worker {
 			// Lower jobs completed = higher score (better candidate)
 			score := 1000 - w.JobsCompleted
 
+			// Spread jobs over workers before filling their slots
+			score -= 500 * w.BusySlots()
+
 			// Prefer Soldatos over Capos for regular work
 			if w.Role == RoleSoldato {
 				score += 100
 			}
 
+			// Above all prefer workers that share the job's labels
+			score += 10000 * job.LabelAffinity(jobLabels, w.GetLabels())
+			note(w, score, "")
+
 			if score > bestScore {
 				bestScore = score
 				best = w
@@ -302,6 +427,7 @@ func (p *Pool) workerFilePath(name string) string {
 }
 
 func (p *Pool) saveWorker(w *Worker) error {
+	recentFailures, quarantine := w.failureState()
 	info := WorkerInfo{
 		ID:             w.ID,
 		Name:           w.Name,
@@ -309,9 +435,13 @@ func (p *Pool) saveWorker(w *Worker) error {
 		Worktree:       w.Worktree,
 		Branch:         w.Branch,
 		StandingOrders: w.StandingOrders,
+		Labels:         w.GetLabels(),
 		SessionID:      w.SessionID,
 		JobsCompleted:  w.JobsCompleted,
 		JobsFailed:     w.JobsFailed,
+		Ephemeral:      w.Ephemeral,
+		RecentFailures: recentFailures,
+		Quarantine:     quarantine,
 	}
 
 	data, err := json.MarshalIndent(info, "", "  ")
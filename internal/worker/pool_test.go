@@ -266,6 +266,157 @@ func TestPoolFindBestWorker(t *testing.T) {
 	}
 }
 
+func TestPoolFindBestWorkerWhere(t *testing.T) {
+	pool := NewPool()
+
+	w1 := &Worker{ID: "1", Name: "paulie", Role: RoleSoldato, Status: StatusIdle, JobsCompleted: 5}
+	w2 := &Worker{ID: "2", Name: "silvio", Role: RoleSoldato, Status: StatusIdle, JobsCompleted: 2}
+
+	pool.Add(w1)
+	pool.Add(w2)
+
+	j := &job.Job{ID: "job-1", Description: "test"}
+
+	// Exclude the otherwise preferred worker
+	best := pool.FindBestWorkerWhere(j, func(w *Worker) bool { return w.Name != "silvio" })
+	if best == nil || best.Name != "paulie" {
+		t.Errorf("expected paulie when silvio is excluded, got %v", best)
+	}
+
+	best = pool.FindBestWorkerWhere(j, func(w *Worker) bool { return false })
+	if best != nil {
+		t.Errorf("expected nil when all workers are excluded, got %s", best.Name)
+	}
+}
+
+func TestPoolFindBestWorkerPreferred(t *testing.T) {
+	pool := NewPool()
+
+	w1 := &Worker{ID: "1", Name: "paulie", Role: RoleSoldato, Status: StatusWorking, JobsCompleted: 5}
+	w2 := &Worker{ID: "2", Name: "silvio", Role: RoleSoldato, Status: StatusIdle, JobsCompleted: 2}
+
+	pool.Add(w1)
+	pool.Add(w2)
+
+	j := &job.Job{ID: "job-1", Description: "test"}
+	j.SetPreferredWorker("1")
+
+	// The job waits for its busy preferred worker
+	if best := pool.FindBestWorker(j); best != nil {
+		t.Errorf("expected nil while the preferred worker is busy, got %s", best.Name)
+	}
+
+	w1.Status = StatusIdle
+	if best := pool.FindBestWorker(j); best == nil || best.Name != "paulie" {
+		t.Errorf("expected the preferred worker paulie, got %v", best)
+	}
+
+	// Any worker will do once the preferred one is gone
+	pool.Remove("paulie")
+	if best := pool.FindBestWorker(j); best == nil || best.Name != "silvio" {
+		t.Errorf("expected silvio after the preferred worker was removed, got %v", best)
+	}
+}
+
+func TestPoolFindBestWorkerSticky(t *testing.T) {
+	pool := NewPool()
+
+	w1 := &Worker{ID: "1", Name: "paulie", Role: RoleSoldato, Status: StatusWorking}
+	w2 := &Worker{ID: "2", Name: "silvio", Role: RoleSoldato, Status: StatusIdle}
+
+	pool.Add(w1)
+	pool.Add(w2)
+
+	j := &job.Job{ID: "job-1", Description: "test"}
+	j.SetStickyWorker("paulie")
+
+	if best := pool.FindBestWorker(j); best != nil {
+		t.Errorf("expected nil while the sticky worker is busy, got %s", best.Name)
+	}
+
+	w1.Status = StatusIdle
+	if best := pool.FindBestWorker(j); best == nil || best.Name != "paulie" {
+		t.Errorf("expected the sticky worker paulie, got %v", best)
+	}
+
+	// Unlike a preferred worker, the job keeps waiting once it is gone
+	pool.Remove("paulie")
+	if best := pool.FindBestWorker(j); best != nil {
+		t.Errorf("expected nil after the sticky worker was removed, got %s", best.Name)
+	}
+
+	j.SetStickyWorker("")
+	if best := pool.FindBestWorker(j); best == nil || best.Name != "silvio" {
+		t.Errorf("expected silvio once unstuck, got %v", best)
+	}
+}
+
+func TestPoolTraceBestWorker(t *testing.T) {
+	pool := NewPool()
+
+	pool.Add(&Worker{ID: "1", Name: "paulie", Role: RoleSoldato, Status: StatusWorking})
+	pool.Add(&Worker{ID: "2", Name: "silvio", Role: RoleSoldato, Status: StatusIdle})
+	pool.Add(&Worker{ID: "3", Name: "vito", Role: RoleCapo, Status: StatusIdle})
+
+	j := &job.Job{ID: "job-1", Description: "test"}
+	best, evals := pool.TraceBestWorker(j, func(w *Worker) string {
+		if w.Name == "silvio" {
+			return "over budget"
+		}
+		return ""
+	})
+	if best == nil || best.Name != "vito" {
+		t.Fatalf("expected vito, got %v", best)
+	}
+
+	reasons := make(map[string]string)
+	for _, e := range evals {
+		reasons[e.Worker] = e.Reason
+	}
+	if len(evals) != 3 {
+		t.Fatalf("expected 3 evaluations, got %+v", evals)
+	}
+	if reasons["paulie"] != "is working" {
+		t.Errorf("expected paulie passed over as working, got %q", reasons["paulie"])
+	}
+	if reasons["silvio"] != "over budget" {
+		t.Errorf("expected silvio refused as over budget, got %q", reasons["silvio"])
+	}
+	if reasons["vito"] != "" {
+		t.Errorf("expected vito as a candidate, got %q", reasons["vito"])
+	}
+
+	// A missing sticky worker is the only one evaluated
+	j.SetStickyWorker("tony")
+	if best, evals := pool.TraceBestWorker(j, nil); best != nil || len(evals) != 1 || evals[0].Worker != "tony" {
+		t.Errorf("expected only the missing sticky worker, got %v %+v", best, evals)
+	}
+}
+
+func TestPoolFindBestWorkerLabelAffinity(t *testing.T) {
+	pool := NewPool()
+
+	w1 := &Worker{ID: "1", Name: "paulie", Role: RoleSoldato, Status: StatusIdle}
+	w2 := &Worker{ID: "2", Name: "silvio", Role: RoleCapo, Status: StatusIdle, JobsCompleted: 50}
+	w2.SetLabels(map[string]string{"area": "auth"})
+
+	pool.Add(w1)
+	pool.Add(w2)
+
+	// Sharing the job's labels outweighs role and workload
+	j := &job.Job{ID: "job-1", Description: "test"}
+	j.SetLabels(map[string]string{"area": "auth", "size": "large"})
+	if best := pool.FindBestWorker(j); best == nil || best.Name != "silvio" {
+		t.Errorf("expected silvio, who shares the area label, got %v", best)
+	}
+
+	// Labels with another value are no reason to prefer a worker
+	j.SetLabels(map[string]string{"area": "billing"})
+	if best := pool.FindBestWorker(j); best == nil || best.Name != "paulie" {
+		t.Errorf("expected paulie without a shared label, got %v", best)
+	}
+}
+
 func TestPoolFindBestWorkerNoAvailable(t *testing.T) {
 	pool := NewPool()
 
@@ -284,6 +435,36 @@ func TestPoolFindBestWorkerNoAvailable(t *testing.T) {
 	}
 }
 
+func TestPoolFindBestWorkerFreeSlot(t *testing.T) {
+	pool := NewPool()
+
+	// A capo with a free slot left takes work while the soldato is busy,
+	// but an idle capo is preferred over it
+	busy := &Worker{ID: "1", Name: "paulie", Role: RoleSoldato, Status: StatusWorking}
+	capo := &Worker{ID: "2", Name: "tony", Role: RoleCapo, Status: StatusWorking, slots: newSlots(2)}
+	capo.slots[0].job = &job.Job{ID: "job-0"}
+
+	pool.Add(busy)
+	pool.Add(capo)
+
+	j := &job.Job{ID: "job-1", Description: "test"}
+	if best := pool.FindBestWorker(j); best != capo {
+		t.Fatalf("expected tony's free slot, got %v", best)
+	}
+
+	idle := &Worker{ID: "3", Name: "silvio", Role: RoleCapo, Status: StatusIdle, JobsCompleted: 10}
+	pool.Add(idle)
+	if best := pool.FindBestWorker(j); best != idle {
+		t.Errorf("expected the idle silvio over tony's second slot, got %v", best)
+	}
+
+	capo.slots[1].job = &job.Job{ID: "job-2"}
+	pool.Remove("silvio")
+	if best := pool.FindBestWorker(j); best != nil {
+		t.Errorf("expected no worker with all slots busy, got %s", best.Name)
+	}
+}
+
 func TestPoolFindBestWorkerPrefersSoldato(t *testing.T) {
 	pool := NewPool()
 
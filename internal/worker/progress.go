@@ -0,0 +1,89 @@
+package worker
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cosa/internal/claude"
+)
+
+// fileTools are the tools that write files.
+var fileTools = map[string]bool{
+	"Write":        true,
+	"Edit":         true,
+	"MultiEdit":    true,
+	"NotebookEdit": true,
+}
+
+// progressOf describes the progress a finished tool call made: a file
+// written, a commit, or any other tool completing. Claude's messages and
+// other stream output are not progress, since a session stuck in a loop
+// can keep producing them.
+func progressOf(tool *claude.ToolCall) string {
+	if tool == nil || tool.Name == "" {
+		return "tool finished"
+	}
+
+	var input struct {
+		FilePath     string `json:"file_path"`
+		NotebookPath string `json:"notebook_path"`
+		Command      string `json:"command"`
+	}
+	json.Unmarshal(tool.Input, &input)
+
+	switch {
+	case fileTools[tool.Name]:
+		path := input.FilePath
+		if path == "" {
+			path = input.NotebookPath
+		}
+		if path == "" {
+			return "wrote a file"
+		}
+		return "wrote " + filepath.Base(path)
+	case tool.Name == "Bash" && strings.Contains(input.Command, "git commit"):
+		return "committed"
+	default:
+		return tool.Name + " finished"
+	}
+}
+
+// markProgress records that the worker made progress just now.
+func (w *Worker) markProgress(what string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.LastProgressAt = time.Now()
+	w.LastProgress = what
+}
+
+// GetLastProgress returns when the worker last made progress and what it
+// was, or a zero time if it has not run a job.
+func (w *Worker) GetLastProgress() (time.Time, string) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.LastProgressAt, w.LastProgress
+}
+
+// SinceProgress returns how long the worker has gone without progress. A
+// worker that has never made any counts from its last activity, or from
+// when it was created.
+func (w *Worker) SinceProgress() time.Duration {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return time.Since(w.lastProgressAt())
+}
+
+// lastProgressAt returns when the worker last made progress, falling back
+// to its last activity and then its creation. The caller holds w.mu.
+func (w *Worker) lastProgressAt() time.Time {
+	switch {
+	case !w.LastProgressAt.IsZero():
+		return w.LastProgressAt
+	case !w.LastActivityAt.IsZero():
+		return w.LastActivityAt
+	default:
+		return w.CreatedAt
+	}
+}
@@ -0,0 +1,194 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"cosa/internal/job"
+)
+
+// PromptVars are the variables a role prompt template can use as {{name}}.
+var PromptVars = []string{
+	"name",            // Worker name
+	"role",            // Worker role
+	"job_id",          // ID of the job
+	"description",     // Job description
+	"body",            // Full job specification, if any
+	"standing_orders", // Standing orders as a markdown list
+	"review_feedback", // Feedback from a rejected review as a markdown list
+	"handoff",         // Notes from the worker the job was taken over from
+	"merge_target",    // Branch the work is merged into
+	"plan",            // Plan approved in the job's plan phase, if any
+	"repo_map",        // Map of the repository's directories, modules and symbols
+	"relevant_files",  // Files found relevant to the job as a markdown list
+	"messages",        // Messages from other workers held for the job as a markdown list
+	"knowledge",       // Lessons learned by departed workers, from the territory knowledge base
+}
+
+// planPhasePrompt ends the prompt of a plan-first job's plan phase, which
+// is also appended to a role's custom prompt.
+const planPhasePrompt = `## Plan Phase
+This is the plan phase of the job. Do not edit any files or make commits.
+Read whatever you need, then reply with a plan for the boss to approve:
+- Files to touch, and what changes in each
+- Approach, including alternatives you ruled out
+- Estimated scope: small, medium or large, and why
+- Open questions or risks
+
+Your reply is the plan. The job runs again to carry it out once approved.`
+
+// PromptRoles are the roles whose prompt can be customized.
+var PromptRoles = []Role{RoleUnderboss, RoleCapo, RoleSoldato, RoleConsigliere}
+
+var promptVarPattern = regexp.MustCompile(`\{\{\s*([a-zA-Z0-9_]+)\s*\}\}`)
+
+// PromptSource looks up the prompt templates that replace the built-in
+// prompts for a role.
+type PromptSource struct {
+	Dir       string            // Directory of <role>.md files, which take precedence
+	Templates map[string]string // Templates from the config, by role
+}
+
+// Template returns the prompt template for role, or "" if the built-in
+// prompt should be used. Files are read on every call, so edits apply to
+// the next job without a restart.
+func (p *PromptSource) Template(role Role) (string, error) {
+	if p == nil {
+		return "", nil
+	}
+
+	if p.Dir != "" {
+		data, err := os.ReadFile(filepath.Join(p.Dir, string(role)+".md"))
+		if err == nil && strings.TrimSpace(string(data)) != "" {
+			return string(data), nil
+		}
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("failed to read %s prompt: %w", role, err)
+		}
+	}
+
+	return p.Templates[string(role)], nil
+}
+
+// ValidatePrompt reports placeholders in tmpl that are not prompt variables.
+func ValidatePrompt(tmpl string) error {
+	var unknown []string
+	for _, m := range promptVarPattern.FindAllStringSubmatch(tmpl, -1) {
+		if !isPromptVar(m[1]) && !containsString(unknown, m[1]) {
+			unknown = append(unknown, m[1])
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown prompt variables: %s (available: %s)",
+			strings.Join(unknown, ", "), strings.Join(PromptVars, ", "))
+	}
+	return nil
+}
+
+// RenderPrompt replaces the {{name}} placeholders in tmpl with vars.
+func RenderPrompt(tmpl string, vars map[string]string) (string, error) {
+	if err := ValidatePrompt(tmpl); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(promptVarPattern.ReplaceAllStringFunc(tmpl, func(m string) string {
+		return vars[promptVarPattern.FindStringSubmatch(m)[1]]
+	})), nil
+}
+
+// promptVars returns the prompt variables for running j.
+func (w *Worker) promptVars(j *job.Job) map[string]string {
+	return map[string]string{
+		"name":            w.Name,
+		"role":            string(w.Role),
+		"job_id":          j.ID,
+		"description":     j.Description,
+		"body":            strings.TrimSpace(j.GetBody()),
+		"standing_orders": markdownList(w.EffectiveOrders()),
+		"review_feedback": markdownList(j.ReviewFeedback),
+		"handoff":         j.GetHandoff(),
+		"merge_target":    w.MergeTargetBranch,
+		"plan":            approvedPlan(j),
+		"repo_map":        w.repoMap.get(),
+		"relevant_files":  markdownList(j.GetRelevantFiles()),
+		"messages":        markdownList(j.GetMessages()),
+		"knowledge":       w.knowledge.get(),
+	}
+}
+
+// RepoMapSource returns a compact map of the repository a worker works in,
+// or "" if there is none yet. It is called for every job, so a refreshed
+// index reaches the next job.
+type RepoMapSource func() string
+
+// repoMapIntro introduces the repository map in the built-in prompt.
+const repoMapIntro = "An index of the repository as of its last refresh: directories with file counts and sizes, modules, and the exported symbols of each package. Use it to find your way before exploring; files may have changed since."
+
+func (r RepoMapSource) get() string {
+	if r == nil {
+		return ""
+	}
+	return strings.TrimSpace(r())
+}
+
+// KnowledgeSource returns the territory knowledge base, the lessons
+// departed workers left behind, or "" if there is none. Like standing
+// orders, it is read for every job, so new lessons reach the next job.
+type KnowledgeSource func() string
+
+// knowledgeIntro introduces the knowledge base in the built-in prompt.
+const knowledgeIntro = "Lessons learned by workers who worked here before you. Follow them unless the task says otherwise."
+
+func (k KnowledgeSource) get() string {
+	if k == nil {
+		return ""
+	}
+	return strings.TrimSpace(k())
+}
+
+// ContextSource returns the files in the worktree at workdir that are
+// likely relevant to j, best first, or nil to list none.
+type ContextSource func(j *job.Job, workdir string) []string
+
+// relevantFilesIntro introduces the relevant files in the built-in prompt.
+const relevantFilesIntro = "Files that mention words from the task, found by searching the repository. Start with these, but others may matter too."
+
+// approvedPlan returns the plan approved for j, or "" if there is none.
+func approvedPlan(j *job.Job) string {
+	if !j.IsPlanApproved() {
+		return ""
+	}
+	return strings.TrimSpace(j.GetPlan())
+}
+
+func markdownList(items []string) string {
+	var sb strings.Builder
+	for i, item := range items {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString("- " + item)
+	}
+	return sb.String()
+}
+
+func isPromptVar(name string) bool {
+	for _, v := range PromptVars {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
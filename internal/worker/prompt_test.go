@@ -0,0 +1,192 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cosa/internal/job"
+)
+
+func TestRenderPrompt(t *testing.T) {
+	got, err := RenderPrompt("You are {{name}}.\n\nTask: {{ description }}\n", map[string]string{
+		"name":        "vito",
+		"description": "Add login",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "You are vito.\n\nTask: Add login" {
+		t.Errorf("unexpected prompt %q", got)
+	}
+
+	if _, err := RenderPrompt("Do {{task}}", nil); err == nil || !strings.Contains(err.Error(), "task") {
+		t.Errorf("expected error naming the unknown variable, got %v", err)
+	}
+}
+
+func TestPromptSource_Template(t *testing.T) {
+	dir := t.TempDir()
+	src := &PromptSource{
+		Dir: dir,
+		Templates: map[string]string{
+			"soldato": "config soldato",
+			"capo":    "config capo",
+		},
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "soldato.md"), []byte("file soldato"), 0644); err != nil {
+		t.Fatalf("failed to write prompt file: %v", err)
+	}
+
+	tests := []struct {
+		role Role
+		want string
+	}{
+		{RoleSoldato, "file soldato"}, // The territory file wins
+		{RoleCapo, "config capo"},
+		{RoleConsigliere, ""},
+	}
+	for _, tt := range tests {
+		got, err := src.Template(tt.role)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", tt.role, err)
+		}
+		if got != tt.want {
+			t.Errorf("expected %q for %s, got %q", tt.want, tt.role, got)
+		}
+	}
+
+	if got, _ := (*PromptSource)(nil).Template(RoleSoldato); got != "" {
+		t.Errorf("expected no template from a nil source, got %q", got)
+	}
+}
+
+func TestWorker_BuildPrompt_Template(t *testing.T) {
+	w := New(Config{
+		Name:              "vito",
+		MergeTargetBranch: "dev",
+		Prompts: &PromptSource{Templates: map[string]string{
+			"soldato": "{{name}} ({{role}}) merges into {{merge_target}}.\n{{standing_orders}}\n{{review_feedback}}\n{{description}}",
+		}},
+	})
+	w.StandingOrders = []string{"Run the tests"}
+
+	j := job.New("Add login")
+	j.SetReviewFeedback([]string{"Hash the passwords"})
+
+	want := "vito (soldato) merges into dev.\n- Run the tests\n- Hash the passwords\nAdd login"
+	if got := w.buildPrompt(j); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestWorker_BuildPrompt_Handoff(t *testing.T) {
+	w := New(Config{Name: "vito"})
+
+	summary := &HandoffSummary{
+		WorkerName:   "paulie",
+		Summary:      "Added the login form",
+		FilesTouched: []string{"login.go"},
+	}
+	j := job.New("Add login")
+	j.SetHandoff(summary.Notes())
+
+	got := w.buildPrompt(j)
+	for _, want := range []string{"## Handoff\nYou are taking over this job from paulie", "Previous work summary: Added the login form", "  - login.go"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected prompt to contain %q, got %q", want, got)
+		}
+	}
+	if strings.Index(got, "## Handoff") > strings.Index(got, "## Your Task") {
+		t.Error("expected the handoff before the task")
+	}
+}
+
+func TestWorker_BuildPrompt_RepoMap(t *testing.T) {
+	w := New(Config{
+		Name:    "vito",
+		RepoMap: func() string { return "- internal/auth (3 files) package auth: Login\n" },
+	})
+
+	got := w.buildPrompt(job.New("Add login"))
+	if !strings.Contains(got, "## Repository Map\n") || !strings.Contains(got, "- internal/auth (3 files) package auth: Login\n\n## Your Task") {
+		t.Errorf("expected the repository map before the task, got %q", got)
+	}
+
+	w = New(Config{Name: "vito"})
+	if got := w.buildPrompt(job.New("Add login")); strings.Contains(got, "## Repository Map") {
+		t.Errorf("expected no repository map without a source, got %q", got)
+	}
+}
+
+func TestWorker_BuildPrompt_Knowledge(t *testing.T) {
+	w := New(Config{
+		Name:      "vito",
+		Knowledge: func() string { return "## sal (2026-01-02)\n\n- Run make gen before testing\n" },
+	})
+
+	got := w.buildPrompt(job.New("Add login"))
+	if !strings.Contains(got, "## Territory Knowledge\n") || !strings.Contains(got, "- Run make gen before testing\n") {
+		t.Errorf("expected the knowledge base in the prompt, got %q", got)
+	}
+
+	w = New(Config{Name: "vito"})
+	if got := w.buildPrompt(job.New("Add login")); strings.Contains(got, "## Territory Knowledge") {
+		t.Errorf("expected no knowledge base without a source, got %q", got)
+	}
+}
+
+func TestWorker_BuildPrompt_RelevantFiles(t *testing.T) {
+	w := New(Config{Name: "vito"})
+
+	j := job.New("Fix handleLogin")
+	if got := w.buildPrompt(j); strings.Contains(got, "## Relevant Files") {
+		t.Errorf("expected no relevant files before any are found, got %q", got)
+	}
+
+	j.SetRelevantFiles([]string{"internal/auth/login.go", "internal/auth/session.go"})
+	got := w.buildPrompt(j)
+	if !strings.Contains(got, "## Relevant Files\n") || !strings.Contains(got, "- internal/auth/login.go\n- internal/auth/session.go\n\n## Your Task") {
+		t.Errorf("expected the relevant files before the task, got %q", got)
+	}
+}
+
+func TestWorker_BuildPrompt_InvalidTemplateFallsBack(t *testing.T) {
+	w := New(Config{
+		Name:    "vito",
+		Prompts: &PromptSource{Templates: map[string]string{"soldato": "Do {{task}}"}},
+	})
+
+	got := w.buildPrompt(job.New("Add login"))
+	if !strings.Contains(got, "## Your Task\nAdd login") {
+		t.Errorf("expected the built-in prompt, got %q", got)
+	}
+}
+
+func TestWorker_BuildPrompt_PlanPhase(t *testing.T) {
+	w := New(Config{Name: "vito"})
+
+	j := job.New("Add login")
+	j.SetPlanFirst(true)
+
+	got := w.buildPrompt(j)
+	if !strings.Contains(got, "## Plan Phase") || strings.Contains(got, "Make commits") {
+		t.Errorf("expected the plan phase prompt, got %q", got)
+	}
+
+	j.Queue()
+	j.Start("vito", "")
+	j.Complete("Edit login.go")
+	j.SetPlan("Edit login.go")
+	j.MarkNeedsAttention(job.AttentionPlanApproval, "plan ready for approval")
+	if err := j.ApprovePlan(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got = w.buildPrompt(j)
+	if strings.Contains(got, "## Plan Phase") || !strings.Contains(got, "## Approved Plan\nCarry out this plan, which the boss approved:\nEdit login.go") {
+		t.Errorf("expected the approved plan in the prompt, got %q", got)
+	}
+}
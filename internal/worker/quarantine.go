@@ -0,0 +1,114 @@
+package worker
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxSignatureLength caps the length of a failure signature.
+const maxSignatureLength = 200
+
+// idPattern matches the UUIDs of jobs and sessions, which would otherwise
+// make the same failure look different from job to job.
+var idPattern = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+
+// Quarantine records that a worker stopped being given jobs after failing
+// too many in a row.
+type Quarantine struct {
+	Since    time.Time `json:"since"`
+	Failures []string  `json:"failures"` // Signatures of the failures that led to it, oldest first
+}
+
+// FailureSignature reduces a job's error to a line that is the same for
+// repeats of the same failure.
+func FailureSignature(err string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(err), "\n")
+	line = idPattern.ReplaceAllString(line, "<id>")
+	if len(line) > maxSignatureLength {
+		line = line[:maxSignatureLength] + "..."
+	}
+	return line
+}
+
+// RecordFailure notes that a job the worker ran failed with the given
+// signature. Once threshold failures have come in a row the worker is
+// quarantined and the new quarantine is returned; otherwise it returns nil.
+// A threshold of 0 never quarantines.
+func (w *Worker) RecordFailure(signature string, threshold int) *Quarantine {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.RecentFailures = append(w.RecentFailures, signature)
+	if threshold <= 0 {
+		// Only the streak up to a threshold is worth keeping
+		w.RecentFailures = nil
+		return nil
+	}
+	if over := len(w.RecentFailures) - threshold; over > 0 {
+		w.RecentFailures = w.RecentFailures[over:]
+	}
+	if w.Quarantined != nil || len(w.RecentFailures) < threshold {
+		return nil
+	}
+
+	w.Quarantined = &Quarantine{
+		Since:    time.Now(),
+		Failures: append([]string(nil), w.RecentFailures...),
+	}
+	q := *w.Quarantined
+	return &q
+}
+
+// RecordSuccess notes that a job the worker ran completed, ending any run
+// of failures. It reports whether there was one.
+func (w *Worker) RecordSuccess() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	ended := len(w.RecentFailures) > 0
+	w.RecentFailures = nil
+	return ended
+}
+
+// GetQuarantine returns the worker's quarantine, or nil if it is not
+// quarantined.
+func (w *Worker) GetQuarantine() *Quarantine {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.Quarantined == nil {
+		return nil
+	}
+	q := *w.Quarantined
+	return &q
+}
+
+// FailureStreak returns how many jobs the worker has failed in a row.
+func (w *Worker) FailureStreak() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return len(w.RecentFailures)
+}
+
+// failureState returns copies of the worker's recent failures and its
+// quarantine, for saving.
+func (w *Worker) failureState() ([]string, *Quarantine) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	recent := append([]string(nil), w.RecentFailures...)
+	if w.Quarantined == nil {
+		return recent, nil
+	}
+	q := *w.Quarantined
+	return recent, &q
+}
+
+// Unquarantine lets the worker be given jobs again, with a clean slate of
+// failures. It returns the quarantine lifted, or nil if there was none.
+func (w *Worker) Unquarantine() *Quarantine {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	q := w.Quarantined
+	w.Quarantined = nil
+	w.RecentFailures = nil
+	return q
+}
@@ -0,0 +1,78 @@
+package worker
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWorker_RecordFailure_Quarantines(t *testing.T) {
+	w := New(Config{Name: "paulie"})
+
+	if q := w.RecordFailure("claude exited: status 1", 3); q != nil {
+		t.Fatal("expected no quarantine after one failure")
+	}
+	w.RecordSuccess()
+	w.RecordFailure("claude exited: status 1", 3)
+	w.RecordFailure("tests failed", 3)
+	if w.GetQuarantine() != nil {
+		t.Fatal("expected a completed job to reset the run of failures")
+	}
+
+	q := w.RecordFailure("claude exited: status 1", 3)
+	if q == nil || len(q.Failures) != 3 || q.Failures[1] != "tests failed" {
+		t.Fatalf("expected a quarantine with the three failures, got %+v", q)
+	}
+	if again := w.RecordFailure("claude exited: status 1", 3); again != nil {
+		t.Error("expected a quarantined worker not to be quarantined again")
+	}
+	if w.GetQuarantine() == nil {
+		t.Error("expected the worker to stay quarantined")
+	}
+
+	if lifted := w.Unquarantine(); lifted == nil {
+		t.Error("expected the lifted quarantine to be returned")
+	}
+	if w.GetQuarantine() != nil || w.RecordFailure("tests failed", 3) != nil {
+		t.Error("expected an unquarantined worker to start over")
+	}
+
+	if q := w.RecordFailure("tests failed", 0); q != nil || len(w.RecentFailures) != 0 {
+		t.Error("expected a threshold of 0 never to quarantine")
+	}
+}
+
+func TestPool_PersistsQuarantine(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "workers")
+	pool, err := NewPersistentPool(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := New(Config{Name: "paulie"})
+	w.RecordFailure("boom", 1)
+	if err := pool.Add(w); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := NewPersistentPool(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending := reloaded.PendingWorkers()
+	if len(pending) != 1 || pending[0].Quarantine == nil || pending[0].Quarantine.Failures[0] != "boom" {
+		t.Errorf("expected the quarantine to be saved, got %+v", pending)
+	}
+}
+
+func TestFailureSignature(t *testing.T) {
+	sig := FailureSignature("failed to create job worktree: /tmp/job-0b5c2a8e-1f3d-4c6b-9a7e-2d4f6b8c0e1a exists\nmore detail")
+	if sig != "failed to create job worktree: /tmp/job-<id> exists" {
+		t.Errorf("expected IDs and later lines to be dropped, got %q", sig)
+	}
+
+	long := FailureSignature(strings.Repeat("x", 500))
+	if len(long) != maxSignatureLength+3 {
+		t.Errorf("expected a long error to be cut, got %d bytes", len(long))
+	}
+}
@@ -0,0 +1,104 @@
+package worker
+
+import (
+	"fmt"
+	"time"
+
+	"cosa/internal/claude"
+	"cosa/internal/job"
+)
+
+// RemediationAction is what the Lookout does about a stuck worker, beyond
+// reporting it.
+type RemediationAction string
+
+const (
+	ActionNotify  RemediationAction = "notify"  // Only report the worker
+	ActionNudge   RemediationAction = "nudge"   // Ask the Claude session to report progress
+	ActionRestart RemediationAction = "restart" // Kill Claude and rerun the job on the same worker
+	ActionRequeue RemediationAction = "requeue" // Fail the run and queue the job for any worker
+)
+
+// RemediationActions lists the valid remediation actions.
+var RemediationActions = []RemediationAction{ActionNotify, ActionNudge, ActionRestart, ActionRequeue}
+
+// ValidRemediationAction reports whether action names a remediation action.
+func ValidRemediationAction(action string) bool {
+	for _, a := range RemediationActions {
+		if string(a) == action {
+			return true
+		}
+	}
+	return false
+}
+
+// RemediationPolicy maps stuck severities to the action taken for them.
+type RemediationPolicy map[StuckSeverity]RemediationAction
+
+// DefaultRemediationPolicy nudges a quiet session before giving its job to
+// another worker.
+var DefaultRemediationPolicy = RemediationPolicy{
+	SeverityWarning:  ActionNotify,
+	SeverityError:    ActionNudge,
+	SeverityCritical: ActionRequeue,
+}
+
+// abortWait bounds how long AbortJob waits for the job's slot to be freed
+// after Claude is stopped.
+const abortWait = 30 * time.Second
+
+// nudgeMessage is sent to a stuck session by the nudge action.
+func nudgeMessage(inactive time.Duration) string {
+	return fmt.Sprintf("You have not finished a tool call, written a file or committed for %s. Briefly report your progress, "+
+		"then continue with the task. If you are blocked, say what is blocking you.", inactive.Round(time.Minute))
+}
+
+// Nudge sends a message to the worker's running Claude session asking it to
+// report progress.
+func (w *Worker) Nudge(inactive time.Duration) error {
+	if err := w.SendMessage(nudgeMessage(inactive)); err != nil {
+		return err
+	}
+	w.emitEvent("nudged", fmt.Sprintf("Nudged after %s without progress", inactive.Round(time.Second)))
+	return nil
+}
+
+// AbortJob stops the Claude process running the given job and fails the job
+// with reason, then waits for its slot to be freed. Unlike CancelJob, the
+// job can be reset and run again afterwards.
+func (w *Worker) AbortJob(jobID, reason string, grace time.Duration) error {
+	w.mu.RLock()
+	s := w.slotFor(jobID)
+	var current *job.Job
+	var client *claude.Client
+	var done chan struct{}
+	if s != nil {
+		current, client, done = s.job, s.client, s.done
+	}
+	w.mu.RUnlock()
+
+	if current == nil {
+		return fmt.Errorf("worker is not running job %s", jobID)
+	}
+
+	// Fail the job first so Claude exiting is not taken as success
+	if err := current.Fail(reason); err != nil {
+		return err
+	}
+	w.emitJobEvent(current, "job_aborted", fmt.Sprintf("Aborted job: %s (%s)", current.Description, reason))
+
+	if client != nil {
+		if err := client.StopGraceful(grace); err != nil {
+			return fmt.Errorf("failed to stop claude: %w", err)
+		}
+	}
+
+	if done != nil {
+		select {
+		case <-done:
+		case <-time.After(abortWait):
+			return fmt.Errorf("worker did not stop within %s", abortWait)
+		}
+	}
+	return nil
+}
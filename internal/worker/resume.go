@@ -0,0 +1,118 @@
+package worker
+
+import (
+	"fmt"
+	"strings"
+
+	"cosa/internal/git"
+)
+
+// ResumeCheck limits how far a worktree may move on from a stored session
+// before the session is discarded in favour of a fresh one. Zero limits are
+// not enforced.
+type ResumeCheck struct {
+	MaxCommits int // Commits landed since the session last ran
+	MaxFiles   int // Files changed since the session last ran
+	MaxTokens  int // Tokens the session has used; past this it is rolled over
+}
+
+// ResumeDecision records whether a stored session should be resumed.
+type ResumeDecision struct {
+	Resume   bool
+	Rollover bool // Replace the session with one seeded with its own handoff summary
+	Reason   string
+	Recap    string // Context for the fresh session when not resuming
+}
+
+// Decide evaluates worktree drift against the limits.
+func (c ResumeCheck) Decide(d *git.Drift, since string) ResumeDecision {
+	var reason string
+	switch {
+	case d.Diverged:
+		reason = fmt.Sprintf("session commit %s is no longer in the history of %s", shortCommit(since), d.Branch)
+	case c.MaxCommits > 0 && d.Commits > c.MaxCommits:
+		reason = fmt.Sprintf("%d commits since the session last ran (limit %d)", d.Commits, c.MaxCommits)
+	case c.MaxFiles > 0 && d.FilesChanged > c.MaxFiles:
+		reason = fmt.Sprintf("%d files changed since the session last ran (limit %d)", d.FilesChanged, c.MaxFiles)
+	default:
+		return ResumeDecision{
+			Resume: true,
+			Reason: fmt.Sprintf("%d commits, %d files changed since the session last ran", d.Commits, d.FilesChanged),
+		}
+	}
+
+	return ResumeDecision{
+		Reason: reason,
+		Recap:  buildRecap(d, reason),
+	}
+}
+
+// buildRecap summarizes what changed for a session that was not resumed.
+func buildRecap(d *git.Drift, reason string) string {
+	var sb strings.Builder
+
+	sb.WriteString("## Session Recap\n")
+	sb.WriteString(fmt.Sprintf("Your previous session was not resumed because the repository has moved on: %s.\n", reason))
+	sb.WriteString(fmt.Sprintf("You are on branch %s at %s.\n", d.Branch, shortCommit(d.Head)))
+
+	if len(d.Log) > 0 {
+		sb.WriteString("\nRecent commits:\n")
+		for _, line := range d.Log {
+			sb.WriteString("- " + line + "\n")
+		}
+		if d.Commits > len(d.Log) {
+			sb.WriteString(fmt.Sprintf("- ... and %d earlier\n", d.Commits-len(d.Log)))
+		}
+	}
+
+	sb.WriteString("\nDo not rely on what you remember about the code; re-read files before changing them.\n")
+	return sb.String()
+}
+
+// checkSession decides whether the worker's stored session can be resumed
+// in workdir. A session that has used too many tokens is rolled over.
+// Otherwise sessions without a recorded commit, or whose drift cannot be
+// measured, are resumed.
+func (w *Worker) checkSession(workdir string) ResumeDecision {
+	w.mu.RLock()
+	since := w.SessionCommit
+	tokens := w.SessionTokens
+	check := w.resumeCheck
+	w.mu.RUnlock()
+
+	if check.MaxTokens > 0 && tokens >= check.MaxTokens {
+		return ResumeDecision{
+			Rollover: true,
+			Reason:   fmt.Sprintf("session has used %d tokens (limit %d)", tokens, check.MaxTokens),
+		}
+	}
+
+	if since == "" {
+		return ResumeDecision{Resume: true, Reason: "no commit recorded for the session"}
+	}
+
+	d, err := git.MeasureDrift(workdir, since)
+	if err != nil {
+		return ResumeDecision{Resume: true, Reason: fmt.Sprintf("drift unknown: %v", err)}
+	}
+	return check.Decide(d, since)
+}
+
+// recordSessionCommit anchors the worker's session to workdir's HEAD so that
+// later resumes can measure drift.
+func (w *Worker) recordSessionCommit(workdir string) {
+	commit, err := git.HeadCommit(workdir)
+	if err != nil {
+		return
+	}
+	w.mu.Lock()
+	w.SessionCommit = commit
+	w.mu.Unlock()
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 8 {
+		return commit[:8]
+	}
+	return commit
+}
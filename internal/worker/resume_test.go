@@ -0,0 +1,168 @@
+package worker
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cosa/internal/claude"
+	"cosa/internal/git"
+	"cosa/internal/job"
+)
+
+func TestResumeCheck_Decide(t *testing.T) {
+	check := ResumeCheck{MaxCommits: 10, MaxFiles: 20}
+
+	tests := []struct {
+		name   string
+		drift  git.Drift
+		resume bool
+		reason string
+	}{
+		{"no drift", git.Drift{}, true, "0 commits"},
+		{"within limits", git.Drift{Commits: 10, FilesChanged: 20}, true, "10 commits, 20 files"},
+		{"too many commits", git.Drift{Commits: 11}, false, "11 commits"},
+		{"too many files", git.Drift{Commits: 2, FilesChanged: 21}, false, "21 files"},
+		{"diverged", git.Drift{Diverged: true, Branch: "main"}, false, "no longer in the history of main"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := tt.drift
+			decision := check.Decide(&d, "0123456789abcdef")
+			if decision.Resume != tt.resume {
+				t.Errorf("expected resume %v, got %v (%s)", tt.resume, decision.Resume, decision.Reason)
+			}
+			if !strings.Contains(decision.Reason, tt.reason) {
+				t.Errorf("expected reason to contain %q, got %q", tt.reason, decision.Reason)
+			}
+			if !tt.resume && !strings.Contains(decision.Recap, "## Session Recap") {
+				t.Errorf("expected recap for fresh session, got %q", decision.Recap)
+			}
+		})
+	}
+}
+
+func TestResumeCheck_Unlimited(t *testing.T) {
+	decision := ResumeCheck{}.Decide(&git.Drift{Commits: 1000, FilesChanged: 1000}, "0123456789abcdef")
+	if !decision.Resume {
+		t.Errorf("expected zero limits not to be enforced, got %s", decision.Reason)
+	}
+}
+
+func TestBuildRecap(t *testing.T) {
+	d := &git.Drift{
+		Branch:  "main",
+		Head:    "fedcba9876543210",
+		Commits: 3,
+		Log:     []string{"abc1234 Add feature", "def5678 Fix bug"},
+	}
+	recap := buildRecap(d, "too far")
+
+	for _, want := range []string{"too far", "main at fedcba98", "- abc1234 Add feature", "... and 1 earlier"} {
+		if !strings.Contains(recap, want) {
+			t.Errorf("expected recap to contain %q:\n%s", want, recap)
+		}
+	}
+}
+
+func TestWorker_CheckSession(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s: %v", args, out, err)
+		}
+	}
+	commit := func(name string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		run("add", name)
+		run("commit", "-q", "-m", "Add "+name)
+	}
+
+	run("init", "-q")
+	commit("a.txt")
+
+	w := New(Config{Name: "vito", ResumeCheck: ResumeCheck{MaxCommits: 2}})
+
+	// No recorded commit: resume
+	if decision := w.checkSession(dir); !decision.Resume {
+		t.Errorf("expected resume without recorded commit, got %s", decision.Reason)
+	}
+
+	w.recordSessionCommit(dir)
+	if w.SessionCommit == "" {
+		t.Fatal("expected session commit to be recorded")
+	}
+
+	commit("b.txt")
+	commit("c.txt")
+	if decision := w.checkSession(dir); !decision.Resume {
+		t.Errorf("expected resume within limits, got %s", decision.Reason)
+	}
+
+	commit("d.txt")
+	decision := w.checkSession(dir)
+	if decision.Resume {
+		t.Error("expected fresh session after too many commits")
+	}
+	if !strings.Contains(decision.Recap, "Add d.txt") {
+		t.Errorf("expected recap to list new commits:\n%s", decision.Recap)
+	}
+}
+
+func TestWorker_CheckSession_Rollover(t *testing.T) {
+	w := New(Config{Name: "vito", ResumeCheck: ResumeCheck{MaxTokens: 1000}})
+	w.SessionID = "sess-1"
+	j := job.New("Add login")
+
+	w.handleClaudeEvent(w.slots[0], j, claude.Event{Type: claude.EventResult, Result: &claude.Result{Success: true, TotalTokens: 600}})
+	if decision := w.checkSession(t.TempDir()); !decision.Resume {
+		t.Errorf("expected resume under the token limit, got %s", decision.Reason)
+	}
+
+	w.handleClaudeEvent(w.slots[0], j, claude.Event{Type: claude.EventResult, Result: &claude.Result{Success: true, TotalTokens: 500}})
+	decision := w.checkSession(t.TempDir())
+	if decision.Resume || !decision.Rollover || !strings.Contains(decision.Reason, "1100 tokens") {
+		t.Errorf("expected a rollover past the token limit, got %+v", decision)
+	}
+}
+
+func TestWorker_RolloverLinksSessions(t *testing.T) {
+	w := New(Config{Name: "vito"})
+	w.SessionID = "sess-1"
+	w.rolledFrom = "sess-1"
+
+	w.handleClaudeEvent(w.slots[0], job.New("Add login"), claude.Event{Type: claude.EventInit, SessionID: "sess-2"})
+	if w.SessionID != "sess-2" || w.PreviousSessionID != "sess-1" {
+		t.Errorf("expected sess-2 to be linked to sess-1, got %q after %q", w.SessionID, w.PreviousSessionID)
+	}
+	if w.rolledFrom != "" {
+		t.Error("expected the rollover to be finished once linked")
+	}
+}
+
+func TestBuildRolloverRecap(t *testing.T) {
+	recap := buildRolloverRecap("session has used 1100 tokens (limit 1000)", "- Login is half done")
+	if !strings.Contains(recap, "grew too long: session has used 1100 tokens") || !strings.Contains(recap, "- Login is half done") {
+		t.Errorf("expected the reason and the handoff in the recap:\n%s", recap)
+	}
+
+	if recap := buildRolloverRecap("too long", ""); !strings.Contains(recap, "could not leave a handoff") {
+		t.Errorf("expected the recap to say there is no handoff:\n%s", recap)
+	}
+}
@@ -0,0 +1,106 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"cosa/internal/claude"
+)
+
+// rolloverTimeout bounds the call asking a session for its handoff summary.
+const rolloverTimeout = 2 * time.Minute
+
+// rolloverPrompt asks a session about to be replaced for the notes its
+// successor needs.
+const rolloverPrompt = `This session has grown too long and is about to be replaced by a fresh
+one, which will not see this conversation. Write a handoff for it.
+
+Cover, as concise markdown bullet points:
+- What you have been working on and where it stands
+- Decisions made and why
+- Conventions and pitfalls of this codebase you have learned
+- Anything unfinished or still open
+
+Do not use any tools and do not make any changes; reply with the handoff only.`
+
+// SessionStats returns the tokens the worker's session has used and the
+// session it replaced at a rollover, if any.
+func (w *Worker) SessionStats() (tokens int, previous string) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.SessionTokens, w.PreviousSessionID
+}
+
+// rolloverSession asks the worker's session for a handoff summary and
+// returns the recap that seeds its successor. The successor is linked to
+// the session when it starts. Without a summary the recap still says why
+// the session was replaced.
+func (w *Worker) rolloverSession(cfg claude.ClientConfig, reason string) string {
+	w.mu.Lock()
+	from := w.SessionID
+	w.rolledFrom = from
+	w.mu.Unlock()
+
+	summary, err := w.summarizeSession(cfg, from)
+	if err != nil {
+		w.emitEvent("error", fmt.Sprintf("Rolling over without a handoff summary: %v", err))
+	}
+	return buildRolloverRecap(reason, summary)
+}
+
+// summarizeSession resumes sessionID for a single turn to ask for a handoff.
+func (w *Worker) summarizeSession(cfg claude.ClientConfig, sessionID string) (string, error) {
+	ctx, cancel := context.WithTimeout(w.ctx, rolloverTimeout)
+	defer cancel()
+
+	cfg.MaxTurns = 1
+	cfg.PermissionMode = ""
+	client := claude.NewClient(cfg)
+	if err := client.Resume(ctx, sessionID, rolloverPrompt); err != nil {
+		return "", fmt.Errorf("failed to start claude: %w", err)
+	}
+
+	var summary strings.Builder
+	for {
+		select {
+		case <-ctx.Done():
+			client.Stop()
+			return "", fmt.Errorf("handoff summary timed out")
+
+		case <-client.Done():
+			return strings.TrimSpace(summary.String()), nil
+
+		case event, ok := <-client.Events():
+			if !ok {
+				return strings.TrimSpace(summary.String()), nil
+			}
+
+			switch event.Type {
+			case claude.EventAssistantText:
+				summary.WriteString(event.Message)
+			case claude.EventError:
+				if event.Error != "" {
+					return "", fmt.Errorf("claude error: %s", event.Error)
+				}
+			}
+		}
+	}
+}
+
+// buildRolloverRecap introduces a rolled-over session's handoff summary to
+// its successor.
+func buildRolloverRecap(reason, summary string) string {
+	var sb strings.Builder
+
+	sb.WriteString("## Session Recap\n")
+	sb.WriteString(fmt.Sprintf("You are continuing from a previous session that was replaced because it grew too long: %s.\n", reason))
+	if summary != "" {
+		sb.WriteString("It left you this handoff:\n\n")
+		sb.WriteString(summary + "\n")
+	} else {
+		sb.WriteString("It could not leave a handoff; check the git log and re-read files before changing them.\n")
+	}
+	return sb.String()
+}
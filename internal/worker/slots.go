@@ -0,0 +1,204 @@
+package worker
+
+import (
+	"fmt"
+
+	"cosa/internal/claude"
+	"cosa/internal/job"
+)
+
+// slot is one of the jobs a worker runs at once, with the Claude session
+// running it. The first slot works in the worker's own worktree when a job
+// has none, and its session is the worker's session.
+type slot struct {
+	index       int
+	job         *job.Job       // Job running in the slot, nil when it is free
+	client      *claude.Client // Client running the job
+	done        chan struct{}  // Closed when the job's session ends
+	sessionID   string
+	lastMessage string // Last text from Claude in the job, kept as its output
+	activity    Activity
+	tokens      int // Tokens used by the slot's jobs
+	jobsRun     int
+}
+
+// SlotInfo describes one of a worker's slots.
+type SlotInfo struct {
+	Index     int
+	Job       *job.Job // Nil when the slot is free
+	SessionID string   // Session of the job running in the slot, or of its last job
+	Activity  Activity
+	Tokens    int // Tokens used by the jobs run in the slot
+	JobsRun   int
+}
+
+func newSlots(n int) []*slot {
+	slots := make([]*slot, max(n, 1))
+	for i := range slots {
+		slots[i] = &slot{index: i}
+	}
+	return slots
+}
+
+// claimSlot takes a free slot for j and marks the worker working. A job
+// without its own worktree can only take the first slot. The caller holds
+// w.mu.
+func (w *Worker) claimSlot(j *job.Job, jobWorktree bool) (*slot, error) {
+	if w.Status != StatusIdle && w.Status != StatusWorking {
+		return nil, fmt.Errorf("worker is not idle")
+	}
+
+	var free *slot
+	for _, s := range w.slots {
+		if s.job == nil && (jobWorktree || s.index == 0) {
+			free = s
+			break
+		}
+	}
+	switch {
+	case free != nil:
+	case len(w.slots) == 1:
+		return nil, fmt.Errorf("worker is not idle")
+	case !jobWorktree:
+		return nil, fmt.Errorf("worker's own worktree is in use")
+	default:
+		return nil, fmt.Errorf("worker has no free slot (%d jobs running)", len(w.slots))
+	}
+
+	free.job = j
+	free.client = nil
+	free.done = make(chan struct{})
+	free.lastMessage = ""
+	free.activity = Activity{}
+	free.jobsRun++
+	w.Status = StatusWorking
+	w.CurrentJob = w.firstBusySlot().job
+	return free, nil
+}
+
+// releaseSlot frees s once its job's session has ended. The worker stays
+// working while other slots are busy.
+func (w *Worker) releaseSlot(s *slot) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	s.job = nil
+	s.client = nil
+	s.activity = Activity{}
+	if s.done != nil {
+		close(s.done)
+		s.done = nil
+	}
+
+	w.CurrentJob = nil
+	w.Status = StatusIdle
+	if busy := w.firstBusySlot(); busy != nil {
+		w.CurrentJob = busy.job
+		w.Status = StatusWorking
+	}
+}
+
+// firstBusySlot returns the lowest slot running a job, or nil if all are
+// free. The caller holds w.mu.
+func (w *Worker) firstBusySlot() *slot {
+	for _, s := range w.slots {
+		if s.job != nil {
+			return s
+		}
+	}
+	return nil
+}
+
+// slotFor returns the slot running the job with the given ID, or nil. The
+// caller holds w.mu.
+func (w *Worker) slotFor(jobID string) *slot {
+	for _, s := range w.slots {
+		if s.job != nil && s.job.ID == jobID {
+			return s
+		}
+	}
+	return nil
+}
+
+// busySlots counts the slots running a job. The caller holds w.mu.
+func (w *Worker) busySlots() int {
+	busy := 0
+	for _, s := range w.slots {
+		if s.job != nil {
+			busy++
+		}
+	}
+	return busy
+}
+
+// SlotCount returns how many jobs the worker can run at once.
+func (w *Worker) SlotCount() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return max(len(w.slots), 1)
+}
+
+// BusySlots returns how many jobs the worker is running.
+func (w *Worker) BusySlots() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.busySlots()
+}
+
+// FreeSlots returns how many more jobs the worker can take now: none
+// unless it is idle or working.
+func (w *Worker) FreeSlots() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	busy := w.busySlots()
+	switch w.Status {
+	case StatusIdle:
+	case StatusWorking:
+		busy = max(busy, 1) // A working worker runs at least one job
+	default:
+		return 0
+	}
+	return max(max(len(w.slots), 1)-busy, 0)
+}
+
+// GetJobs returns the jobs running in the worker's slots.
+func (w *Worker) GetJobs() []*job.Job {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	var jobs []*job.Job
+	for _, s := range w.slots {
+		if s.job != nil {
+			jobs = append(jobs, s.job)
+		}
+	}
+	return jobs
+}
+
+// HasJob reports whether the job with the given ID is running in one of
+// the worker's slots.
+func (w *Worker) HasJob(jobID string) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.slotFor(jobID) != nil
+}
+
+// Slots describes the worker's slots, in order.
+func (w *Worker) Slots() []SlotInfo {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	infos := make([]SlotInfo, len(w.slots))
+	for i, s := range w.slots {
+		infos[i] = SlotInfo{
+			Index:     s.index,
+			Job:       s.job,
+			SessionID: s.sessionID,
+			Activity:  s.activity,
+			Tokens:    s.tokens,
+			JobsRun:   s.jobsRun,
+		}
+	}
+	return infos
+}
@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"fmt"
+	"strings"
+
+	"cosa/internal/git"
+	"cosa/internal/job"
+)
+
+// DefaultSnapshotMessage is the commit message used for uncommitted changes
+// when none is configured.
+const DefaultSnapshotMessage = "Commit uncommitted changes from job {job}\n\n{description}"
+
+// SnapshotPolicy decides what happens to uncommitted changes a worker leaves
+// in a job worktree. Without them the changes would never reach the merge.
+type SnapshotPolicy struct {
+	Fail    bool   // Fail the job instead of committing the changes
+	Message string // Commit message template; {job}, {description} and {worker} are replaced
+}
+
+// snapshotWorktree handles uncommitted changes in the job worktree at dir,
+// recording the outcome on the job. It returns an error if the job should
+// fail.
+func (w *Worker) snapshotWorktree(j *job.Job, dir string) error {
+	files, err := git.UncommittedFiles(dir)
+	if err != nil || len(files) == 0 {
+		return nil // Nothing to do, or nothing we can check
+	}
+
+	snapshot := &job.WorktreeSnapshot{Files: files}
+
+	if w.snapshotPolicy.Fail {
+		snapshot.Action = job.SnapshotFailed
+		j.SetSnapshot(snapshot)
+
+		if commits, err := git.CommitsSince(dir, w.MergeTargetBranch); err == nil && commits == 0 {
+			return fmt.Errorf("no commits and dirty tree: %d uncommitted files left in the worktree", len(files))
+		}
+		return fmt.Errorf("dirty tree: %d uncommitted files left in the worktree", len(files))
+	}
+
+	commit, err := git.CommitAll(dir, w.snapshotMessage(j), w.commitEnv(j, dir)...)
+	if err != nil {
+		snapshot.Action = job.SnapshotFailed
+		j.SetSnapshot(snapshot)
+		return fmt.Errorf("failed to commit uncommitted changes: %w", err)
+	}
+
+	snapshot.Action = job.SnapshotCommitted
+	snapshot.Commit = commit
+	j.SetSnapshot(snapshot)
+	w.emitEvent("snapshot_committed", fmt.Sprintf("Committed %d uncommitted files as %s", len(files), shortCommit(commit)))
+	return nil
+}
+
+// snapshotMessage builds the commit message for a job's uncommitted changes.
+func (w *Worker) snapshotMessage(j *job.Job) string {
+	message := w.snapshotPolicy.Message
+	if message == "" {
+		message = DefaultSnapshotMessage
+	}
+
+	jobID := j.ID
+	if len(jobID) > 8 {
+		jobID = jobID[:8]
+	}
+
+	return strings.NewReplacer(
+		"{job}", jobID,
+		"{description}", j.Description,
+		"{worker}", w.Name,
+	).Replace(message)
+}
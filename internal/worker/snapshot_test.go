@@ -0,0 +1,86 @@
+package worker
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cosa/internal/git"
+	"cosa/internal/job"
+)
+
+func initSnapshotRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"config", "user.name", "test"},
+		{"config", "user.email", "test@example.com"},
+		{"commit", "-q", "--allow-empty", "-m", "Initial commit"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s: %v", args, out, err)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("change"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestWorker_SnapshotWorktree_Commits(t *testing.T) {
+	dir := initSnapshotRepo(t)
+
+	w := New(Config{Name: "paulie", MergeTargetBranch: "main"})
+	j := job.New("Add a file")
+
+	if err := w.snapshotWorktree(j, dir); err != nil {
+		t.Fatalf("expected snapshot to succeed: %v", err)
+	}
+
+	snapshot := j.GetSnapshot()
+	if snapshot == nil || snapshot.Action != job.SnapshotCommitted || snapshot.Commit == "" {
+		t.Fatalf("expected committed snapshot, got %+v", snapshot)
+	}
+	if len(snapshot.Files) != 1 || snapshot.Files[0] != "new.txt" {
+		t.Errorf("expected new.txt to be recorded, got %v", snapshot.Files)
+	}
+
+	if files, _ := git.UncommittedFiles(dir); len(files) != 0 {
+		t.Errorf("expected clean worktree, got %v", files)
+	}
+}
+
+func TestWorker_SnapshotWorktree_Fails(t *testing.T) {
+	dir := initSnapshotRepo(t)
+
+	w := New(Config{Name: "paulie", MergeTargetBranch: "main", SnapshotPolicy: SnapshotPolicy{Fail: true}})
+	j := job.New("Add a file")
+
+	err := w.snapshotWorktree(j, dir)
+	if err == nil || !strings.Contains(err.Error(), "no commits and dirty tree") {
+		t.Fatalf("expected no commits and dirty tree error, got %v", err)
+	}
+	if snapshot := j.GetSnapshot(); snapshot == nil || snapshot.Action != job.SnapshotFailed {
+		t.Errorf("expected failed snapshot, got %+v", snapshot)
+	}
+}
+
+func TestWorker_SnapshotMessage(t *testing.T) {
+	w := New(Config{Name: "paulie", SnapshotPolicy: SnapshotPolicy{Message: "{worker}: {description} ({job})"}})
+	j := job.New("Fix the thing")
+	j.ID = "0123456789abcdef"
+
+	if got, want := w.snapshotMessage(j), "paulie: Fix the thing (01234567)"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
@@ -4,6 +4,7 @@ package worker
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
@@ -57,20 +58,39 @@ type Worker struct {
 	// Standing orders applied to all jobs for this worker
 	StandingOrders []string `json:"standing_orders,omitempty"`
 
+	// Labels the scheduler matches against job labels, preferring this
+	// worker for jobs that share them
+	Labels map[string]string `json:"labels,omitempty"`
+
 	// MergeTargetBranch is the branch where this worker's work will be merged.
 	// This could be a dev/staging branch or the main branch.
 	MergeTargetBranch string `json:"merge_target_branch,omitempty"`
 
-	// Current job
+	// Current job, the first of the jobs running in the worker's slots
 	CurrentJob *job.Job `json:"current_job,omitempty"`
 
 	// Claude session
-	SessionID string `json:"session_id,omitempty"`
+	SessionID     string `json:"session_id,omitempty"`
+	SessionCommit string `json:"session_commit,omitempty"` // HEAD when the session last ran
+
+	// SessionTokens counts the tokens the session has used across its
+	// runs. Past ResumeCheck.MaxTokens the session is rolled over.
+	SessionTokens int `json:"session_tokens,omitempty"`
+
+	// PreviousSessionID is the session the current one replaced when it
+	// was rolled over
+	PreviousSessionID string `json:"previous_session_id,omitempty"`
 
 	// Stats
 	JobsCompleted int `json:"jobs_completed"`
 	JobsFailed    int `json:"jobs_failed"`
 
+	// RecentFailures are the signatures of the jobs failed in a row since
+	// the last one completed. Past the quarantine threshold the worker is
+	// quarantined, and the scheduler gives it no jobs until it is lifted.
+	RecentFailures []string    `json:"recent_failures,omitempty"`
+	Quarantined    *Quarantine `json:"quarantined,omitempty"`
+
 	// Cost tracking
 	TotalCost   string `json:"total_cost,omitempty"`   // Cumulative cost for this worker
 	TotalTokens int    `json:"total_tokens,omitempty"` // Cumulative tokens used
@@ -78,16 +98,44 @@ type Worker struct {
 	// Last activity tracking for health monitoring
 	LastActivityAt time.Time `json:"last_activity_at,omitempty"`
 
+	// Last progress, such as a tool finishing, a file written or a commit.
+	// The Lookout judges whether a worker is stuck by it, not by activity.
+	LastProgressAt time.Time `json:"last_progress_at,omitempty"`
+	LastProgress   string    `json:"last_progress,omitempty"`
+
 	// Internal state
-	mu            sync.RWMutex
-	client        *claude.Client
-	ctx           context.Context
-	cancel        context.CancelFunc
-	events        chan Event
-	onEvent       func(Event)
-	onJobComplete func(*job.Job)
-	onJobFail     func(*job.Job, error)
-	onCostUpdate  func(workerID, workerName, cost string, tokens int)
+	mu             sync.RWMutex
+	client         *claude.Client
+	ctx            context.Context
+	cancel         context.CancelFunc
+	events         chan Event
+	onEvent        func(Event)
+	onJobComplete  func(*job.Job)
+	onJobFail      func(*job.Job, error)
+	onCostUpdate   func(workerID, workerName, jobID, cost string, tokens int)
+	onClaudeEvent  func(workerName string, j *job.Job, event claude.Event)
+	resumeCheck    ResumeCheck
+	snapshotPolicy SnapshotPolicy
+	identity       CommitIdentity
+	prompts        *PromptSource
+	orders         OrderSource
+	fallback       FallbackSource
+	model          ModelSource
+	context        ContextSource
+	repoMap        RepoMapSource
+	knowledge      KnowledgeSource
+	mcpConfig      MCPConfigSource
+	rolledFrom     string // Session being rolled over, until its successor starts
+	slots          []*slot
+	mailbox        []string // Messages held for the next job
+}
+
+// Activity is what a worker is doing in its current job.
+type Activity struct {
+	Progress   string    // Latest line of text from Claude
+	ProgressAt time.Time // When Progress was written
+	Tool       string    // Tool in use, empty between tools
+	ToolSince  time.Time // When Tool was called
 }
 
 // Event represents a worker event.
@@ -109,8 +157,29 @@ type Config struct {
 	OnEvent           func(Event)
 	OnJobComplete     func(*job.Job)
 	OnJobFail         func(*job.Job, error)
-	OnCostUpdate      func(workerID, workerName, cost string, tokens int)
+	OnCostUpdate      func(workerID, workerName, jobID, cost string, tokens int)
+	OnClaudeEvent     func(workerName string, j *job.Job, event claude.Event) // Raw Claude events, e.g. for transcripts
 	MergeTargetBranch string // Branch where work will be merged (dev branch or main)
+	ResumeCheck       ResumeCheck // Limits on drift before a stored session is discarded
+	SnapshotPolicy    SnapshotPolicy // What to do with uncommitted changes left in a job worktree
+	Identity          CommitIdentity // Author, signing and job trailer of commits in job worktrees
+	Prompts           *PromptSource // Custom prompt templates by role; nil uses the built-in prompt
+	Orders            OrderSource   // Global and role standing orders; nil means the worker's own only
+	Fallback          FallbackSource // Models to fall back to when one is out of capacity
+	Model             ModelSource    // Default model for each job; nil keeps ClaudeConfig's
+	Context           ContextSource  // Files relevant to each job for the prompt; nil lists none
+	RepoMap           RepoMapSource  // Map of the repository for the prompt; nil leaves it out
+
+	// Slots is how many jobs the worker runs at once (default: 1)
+	Slots int
+
+	// MCPConfig is the MCP config of each job's session; nil keeps
+	// ClaudeConfig's
+	MCPConfig MCPConfigSource
+
+	// Knowledge is the territory knowledge base for the prompt; nil leaves
+	// it out
+	Knowledge KnowledgeSource
 }
 
 // New creates a new worker.
@@ -135,6 +204,19 @@ func New(cfg Config) *Worker {
 		onJobComplete:     cfg.OnJobComplete,
 		onJobFail:         cfg.OnJobFail,
 		onCostUpdate:      cfg.OnCostUpdate,
+		onClaudeEvent:     cfg.OnClaudeEvent,
+		resumeCheck:       cfg.ResumeCheck,
+		snapshotPolicy:    cfg.SnapshotPolicy,
+		identity:          cfg.Identity,
+		prompts:           cfg.Prompts,
+		orders:            cfg.Orders,
+		fallback:          cfg.Fallback,
+		model:             cfg.Model,
+		context:           cfg.Context,
+		repoMap:           cfg.RepoMap,
+		knowledge:         cfg.Knowledge,
+		mcpConfig:         cfg.MCPConfig,
+		slots:             newSlots(cfg.Slots),
 	}
 
 	if cfg.Worktree != nil {
@@ -186,17 +268,12 @@ func (w *Worker) Execute(j *job.Job) error {
 
 // ExecuteInWorktree runs a job on this worker in the specified worktree.
 // If worktreePath is empty, uses the worker's default worktree.
-// When a custom worktreePath is provided, a fresh session is always started
-// (not resumed) since each job worktree should have its own isolated session.
+// When a custom worktreePath is provided, a fresh session is started (not
+// resumed) since each job worktree should have its own isolated session,
+// unless the job is pinned to this worker to carry on from its last session.
+// The job takes a free slot; only the first slot runs in the default
+// worktree and carries the worker's own session.
 func (w *Worker) ExecuteInWorktree(j *job.Job, worktreePath string) error {
-	w.mu.Lock()
-	if w.Status != StatusIdle {
-		w.mu.Unlock()
-		return fmt.Errorf("worker is not idle")
-	}
-	w.Status = StatusWorking
-	w.CurrentJob = j
-
 	// Determine the working directory and whether to use job-specific worktree
 	workdir := w.Worktree
 	useJobWorktree := worktreePath != ""
@@ -204,20 +281,96 @@ func (w *Worker) ExecuteInWorktree(j *job.Job, worktreePath string) error {
 		workdir = worktreePath
 	}
 
-	// Create a new client configured for this worktree
-	jobClient := claude.NewClient(w.client.CloneConfig(workdir))
+	w.mu.Lock()
+	s, err := w.claimSlot(j, useJobWorktree)
+	if err != nil {
+		w.mu.Unlock()
+		return err
+	}
+
+	// Create a new client configured for this worktree, honoring any
+	// per-job model override
+	clientCfg := w.client.CloneConfig(workdir)
+	if w.model != nil {
+		if model := w.model(); model != "" {
+			clientCfg.Model = model
+		}
+	}
+	if model := j.GetModel(); model != "" {
+		clientCfg.Model = model
+	}
+	// Tools such as delegation act for the job the session runs
+	if w.mcpConfig != nil && clientCfg.MCPConfig != "" {
+		if path := w.mcpConfig(j); path != "" {
+			clientCfg.MCPConfig = path
+		}
+	}
+	// The plan phase may read the code but not change it
+	planning := j.NeedsPlan()
+	if planning {
+		clientCfg.PermissionMode = "plan"
+	}
+	clientCfg.Env = w.commitEnv(j, workdir)
+	j.SetModelUsed(clientCfg.Model)
+	jobClient := claude.NewClient(clientCfg)
+	s.client = jobClient
+	w.LastActivityAt = time.Now() // Starting counts as activity for the Lookout
+	w.LastProgressAt = w.LastActivityAt
+	w.LastProgress = "started job"
 	w.mu.Unlock()
 
-	w.emitEvent("job_started", fmt.Sprintf("Starting job: %s (worktree: %s)", j.Description, workdir))
+	if planning {
+		w.emitJobEvent(j, "job_started", fmt.Sprintf("Planning job: %s (worktree: %s)", j.Description, workdir))
+	} else {
+		w.emitJobEvent(j, "job_started", fmt.Sprintf("Starting job: %s (worktree: %s)", j.Description, workdir))
+	}
+
+	// Gather the files the job likely touches, to save the worker exploring
+	if w.context != nil {
+		files := w.context(j, workdir)
+		j.SetRelevantFiles(files)
+		if len(files) > 0 {
+			w.emitJobEvent(j, "context_gathered", "Relevant files: "+strings.Join(files, ", "))
+		}
+	}
+
+	// Messages sent while the worker was idle go with its next job
+	if messages := w.takeMessages(); len(messages) > 0 {
+		j.AddMessages(messages...)
+	}
 
 	// Build prompt for Claude
 	prompt := w.buildPrompt(j)
 
 	// Start or resume Claude session
-	// For job-specific worktrees, always start fresh (don't resume old sessions)
-	// For the worker's default worktree, resume if we have a session ID
-	var err error
-	if !useJobWorktree && w.SessionID != "" {
+	// For job-specific worktrees, start fresh unless the job is sticky
+	// For the worker's default worktree and sticky jobs, resume if we have a
+	// session ID and the worktree hasn't moved on too far since the session
+	// last ran. Only the first slot holds the worker's session.
+	sticky := j.GetStickyWorker() == w.Name
+	resume := s.index == 0 && (!useJobWorktree || sticky) && w.SessionID != ""
+	if resume {
+		decision := w.checkSession(workdir)
+		switch {
+		case decision.Resume:
+			w.emitJobEvent(j, "session_resumed", "Resuming session: "+decision.Reason)
+		case decision.Rollover:
+			w.emitJobEvent(j, "session_rollover", "Rolling over to a fresh session: "+decision.Reason)
+			prompt = w.rolloverSession(clientCfg, decision.Reason) + "\n" + prompt
+			resume = false
+		default:
+			w.emitJobEvent(j, "session_reset", "Starting fresh session: "+decision.Reason)
+			prompt = decision.Recap + "\n" + prompt
+			resume = false
+		}
+	}
+	if !resume && s.index == 0 {
+		w.mu.Lock()
+		w.SessionTokens = 0
+		w.mu.Unlock()
+	}
+
+	if resume {
 		err = jobClient.Resume(w.ctx, w.SessionID, prompt)
 	} else {
 		err = jobClient.Start(w.ctx, prompt)
@@ -225,27 +378,39 @@ func (w *Worker) ExecuteInWorktree(j *job.Job, worktreePath string) error {
 
 	if err != nil {
 		w.handleJobFailure(j, err)
+		w.releaseSlot(s)
 		return err
 	}
 
-	// Process events from Claude using the job-specific client
-	go w.processClaudeEventsWithClient(j, jobClient)
+	// Process events from Claude using the job-specific client, then anchor
+	// the worker's session to the commit it finished on
+	go func() {
+		w.processClaudeEventsWithClient(s, j, jobClient)
+		if s.index == 0 {
+			w.recordSessionCommit(workdir)
+		}
+	}()
 
 	return nil
 }
 
 func (w *Worker) processClaudeEvents(j *job.Job) {
-	w.processClaudeEventsWithClient(j, w.client)
+	w.processClaudeEventsWithClient(w.slots[0], j, w.client)
 }
 
-func (w *Worker) processClaudeEventsWithClient(j *job.Job, client *claude.Client) {
+func (w *Worker) processClaudeEventsWithClient(s *slot, j *job.Job, client *claude.Client) {
 	defer func() {
-		w.mu.Lock()
-		w.Status = StatusIdle
-		w.CurrentJob = nil
-		w.mu.Unlock()
+		if j.GetStatus() == job.StatusCancelled {
+			w.emitJobEvent(j, "job_cancelled", fmt.Sprintf("Cancelled job: %s", j.Description))
+		}
+		w.releaseSlot(s)
 	}()
 
+	// Models the job has run on, and the last error Claude reported, for
+	// falling back to another model when one is out of capacity
+	tried := map[string]bool{client.Model(): true}
+	var lastError string
+
 	for {
 		select {
 		case <-w.ctx.Done():
@@ -258,10 +423,14 @@ func (w *Worker) processClaudeEventsWithClient(j *job.Job, client *claude.Client
 				status := j.GetStatus()
 				if status == job.StatusRunning {
 					// Assume success if no error
-					w.handleJobSuccess(j)
+					w.handleJobSuccess(s, j)
 				} else if status == job.StatusQueued {
 					// Claude exited before sending init event
 					stderr := client.StderrOutput()
+					if next := w.fallbackClient(s, j, client, stderr, tried); next != nil {
+						client = next
+						continue
+					}
 					if stderr != "" {
 						w.handleJobFailure(j, fmt.Errorf("claude exited before starting: %s", stderr))
 					} else {
@@ -271,14 +440,35 @@ func (w *Worker) processClaudeEventsWithClient(j *job.Job, client *claude.Client
 				return
 			}
 
-			w.handleClaudeEvent(j, event)
+			if event.Type == claude.EventError {
+				lastError = event.Error
+			}
+			if event.Type == claude.EventResult && event.Result != nil && !event.Result.Success {
+				reason := strings.Join([]string{event.Result.Message, lastError, client.StderrOutput()}, "\n")
+				if next := w.fallbackClient(s, j, client, reason, tried); next != nil {
+					w.recordResultCost(s, j, event.Result)
+					client = next
+					continue
+				}
+			}
+
+			w.handleClaudeEvent(s, j, event)
 
 		case <-client.Done():
+			// Events still buffered, such as the result, are handled first;
+			// the events channel closes after them
+			if len(client.Events()) > 0 {
+				continue
+			}
 			status := j.GetStatus()
 			if status == job.StatusRunning {
-				w.handleJobSuccess(j)
+				w.handleJobSuccess(s, j)
 			} else if status == job.StatusQueued {
 				stderr := client.StderrOutput()
+				if next := w.fallbackClient(s, j, client, stderr, tried); next != nil {
+					client = next
+					continue
+				}
 				if stderr != "" {
 					w.handleJobFailure(j, fmt.Errorf("claude exited before starting: %s", stderr))
 				} else {
@@ -290,53 +480,126 @@ func (w *Worker) processClaudeEventsWithClient(j *job.Job, client *claude.Client
 	}
 }
 
-func (w *Worker) handleClaudeEvent(j *job.Job, event claude.Event) {
+func (w *Worker) handleClaudeEvent(s *slot, j *job.Job, event claude.Event) {
 	// Update activity timestamp for any event
 	w.UpdateActivity()
 
+	if w.onClaudeEvent != nil {
+		w.onClaudeEvent(w.Name, j, event)
+	}
+
 	switch event.Type {
 	case claude.EventInit:
 		w.mu.Lock()
-		w.SessionID = event.SessionID
+		s.sessionID = event.SessionID
+		var from string
+		if s.index == 0 {
+			w.SessionID = event.SessionID
+			from = w.rolledFrom
+			if from != "" && from != event.SessionID {
+				w.PreviousSessionID = from
+				w.rolledFrom = ""
+			}
+		}
 		w.mu.Unlock()
-		j.Start(w.ID, event.SessionID)
+		err := j.Start(w.ID, event.SessionID)
+		if errors.Is(err, job.ErrIllegalTransition) && j.GetStatus() == job.StatusRunning {
+			// Restarted on a fallback model: the job carries on in the new session
+			err = j.SetSession(event.SessionID)
+		}
+		if err != nil {
+			w.emitJobEvent(j, "error", fmt.Sprintf("Failed to record session %s: %v", event.SessionID, err))
+		}
+		if from != "" && from != event.SessionID {
+			w.emitJobEvent(j, "session_linked", fmt.Sprintf("Session %s continues rolled-over session %s", event.SessionID, from))
+		}
 
 	case claude.EventAssistantText:
-		w.emitEvent("message", event.Message)
+		w.mu.Lock()
+		s.lastMessage = event.Message
+		if line := lastLine(event.Message); line != "" {
+			s.activity.Progress = line
+			s.activity.ProgressAt = time.Now()
+		}
+		w.mu.Unlock()
+		w.emitJobEvent(j, "message", event.Message)
 
 	case claude.EventToolUse:
-		w.emitEvent("tool_use", fmt.Sprintf("Using tool: %s", event.Tool.Name))
+		w.mu.Lock()
+		s.activity.Tool = event.Tool.Name
+		s.activity.ToolSince = time.Now()
+		w.mu.Unlock()
+		w.emitJobEvent(j, "tool_use", fmt.Sprintf("Using tool: %s", event.Tool.Name))
 
 	case claude.EventToolResult:
-		w.emitEvent("tool_result", fmt.Sprintf("Tool completed: %s", event.Tool.Name))
+		w.mu.Lock()
+		s.activity.Tool = ""
+		s.activity.ToolSince = time.Time{}
+		w.mu.Unlock()
+		w.markProgress(progressOf(event.Tool))
+		w.emitJobEvent(j, "tool_result", fmt.Sprintf("Tool completed: %s", event.Tool.Name))
 
 	case claude.EventResult:
 		// Update cost tracking from result
 		if event.Result != nil {
-			if event.Result.TotalCost != "" || event.Result.TotalTokens > 0 {
-				w.UpdateCost(event.Result.TotalCost, event.Result.TotalTokens)
-			}
+			w.recordResultCost(s, j, event.Result)
 			if !event.Result.Success {
 				w.handleJobFailure(j, fmt.Errorf("claude reported failure"))
 			} else {
-				w.handleJobSuccess(j)
+				w.handleJobSuccess(s, j)
 			}
 		} else {
-			w.handleJobSuccess(j)
+			w.handleJobSuccess(s, j)
 		}
 
 	case claude.EventError:
-		w.emitEvent("error", event.Error)
+		w.emitJobEvent(j, "error", event.Error)
 	}
 }
 
-func (w *Worker) handleJobSuccess(j *job.Job) {
-	j.Complete("")
+// recordResultCost adds the cost Claude reported for a session running j.
+func (w *Worker) recordResultCost(s *slot, j *job.Job, result *claude.Result) {
+	if result.TotalCost != "" || result.TotalTokens > 0 {
+		w.updateCost(j.ID, result.TotalCost, result.TotalTokens)
+	}
+	w.mu.Lock()
+	s.tokens += result.TotalTokens
+	if s.index == 0 {
+		w.SessionTokens += result.TotalTokens
+	}
+	w.mu.Unlock()
+}
+
+func (w *Worker) handleJobSuccess(s *slot, j *job.Job) {
+	// A cancelled job keeps its status even if Claude finishes cleanly
+	if j.GetStatus() == job.StatusCancelled {
+		return
+	}
+
+	// Work left uncommitted in a job worktree would be lost at merge
+	if dir := j.GetWorktree(); dir != "" {
+		if err := w.snapshotWorktree(j, dir); err != nil {
+			w.handleJobFailure(j, err)
+			return
+		}
+	}
+
+	w.mu.Lock()
+	output := s.lastMessage
+	w.mu.Unlock()
+
+	// Kept apart from the output, which a review replaces with its own
+	j.SetSummary(output)
+
+	// The job may have been cancelled or aborted while Claude wound down
+	if err := j.Complete(output); err != nil {
+		return
+	}
 	w.mu.Lock()
 	w.JobsCompleted++
 	onComplete := w.onJobComplete
 	w.mu.Unlock()
-	w.emitEvent("job_completed", fmt.Sprintf("Completed job: %s", j.Description))
+	w.emitJobEvent(j, "job_completed", fmt.Sprintf("Completed job: %s", j.Description))
 
 	if onComplete != nil {
 		onComplete(j)
@@ -344,30 +607,74 @@ func (w *Worker) handleJobSuccess(j *job.Job) {
 }
 
 func (w *Worker) handleJobFailure(j *job.Job, err error) {
-	j.Fail(err.Error())
+	// A cancelled or already finished job keeps its status
+	if j.GetStatus() == job.StatusCancelled {
+		return
+	}
+	if ferr := j.Fail(err.Error()); ferr != nil {
+		return
+	}
 	w.mu.Lock()
 	w.JobsFailed++
 	w.Status = StatusError
 	onFail := w.onJobFail
 	w.mu.Unlock()
-	w.emitEvent("job_failed", fmt.Sprintf("Job failed: %v", err))
+	w.emitJobEvent(j, "job_failed", fmt.Sprintf("Job failed: %v", err))
 
 	if onFail != nil {
 		onFail(j, err)
 	}
 }
 
+// CancelJob stops the Claude process running the given job. Claude is sent
+// SIGINT and given grace to exit before it is killed. The caller is expected
+// to have marked the job cancelled already; the job's slot is freed once
+// the process exits.
+func (w *Worker) CancelJob(jobID string, grace time.Duration) error {
+	w.mu.RLock()
+	s := w.slotFor(jobID)
+	var current *job.Job
+	var client *claude.Client
+	if s != nil {
+		current, client = s.job, s.client
+	}
+	w.mu.RUnlock()
+
+	if current == nil {
+		return fmt.Errorf("worker is not running job %s", jobID)
+	}
+
+	w.emitJobEvent(current, "job_cancelling", fmt.Sprintf("Cancelling job: %s", current.Description))
+
+	if client == nil {
+		return nil
+	}
+	return client.StopGraceful(grace)
+}
+
+// buildPrompt builds the prompt for j from the role's prompt template, or
+// the built-in prompt if the role has none.
 func (w *Worker) buildPrompt(j *job.Job) string {
+	tmpl, err := w.prompts.Template(w.Role)
+	if err == nil && tmpl != "" {
+		var prompt string
+		if prompt, err = RenderPrompt(tmpl, w.promptVars(j)); err == nil {
+			if j.NeedsPlan() {
+				prompt += "\n\n" + planPhasePrompt
+			}
+			return prompt
+		}
+	}
+	if err != nil {
+		w.emitEvent("error", fmt.Sprintf("Using the built-in prompt: %v", err))
+	}
+
 	var sb strings.Builder
 
 	sb.WriteString(fmt.Sprintf("You are %s, a %s worker in the Cosa development team.\n\n", w.Name, w.Role))
 
 	// Include standing orders if present
-	w.mu.RLock()
-	orders := w.StandingOrders
-	w.mu.RUnlock()
-
-	if len(orders) > 0 {
+	if orders := w.EffectiveOrders(); len(orders) > 0 {
 		sb.WriteString("## Standing Orders\n")
 		for _, order := range orders {
 			sb.WriteString("- " + order + "\n")
@@ -375,6 +682,11 @@ func (w *Worker) buildPrompt(j *job.Job) string {
 		sb.WriteString("\n")
 	}
 
+	// Include what departed workers learned about the territory
+	if knowledge := w.knowledge.get(); knowledge != "" {
+		sb.WriteString(fmt.Sprintf("## Territory Knowledge\n%s\n\n%s\n\n", knowledgeIntro, knowledge))
+	}
+
 	// Include review feedback if this is a revision job
 	if len(j.ReviewFeedback) > 0 {
 		sb.WriteString("## Previous Review Feedback\n")
@@ -385,7 +697,36 @@ func (w *Worker) buildPrompt(j *job.Job) string {
 		sb.WriteString("\n")
 	}
 
+	// Include the repository map so the worker need not explore to orient
+	if repoMap := w.repoMap.get(); repoMap != "" {
+		sb.WriteString(fmt.Sprintf("## Repository Map\n%s\n\n%s\n\n", repoMapIntro, repoMap))
+	}
+	if files := j.GetRelevantFiles(); len(files) > 0 {
+		sb.WriteString(fmt.Sprintf("## Relevant Files\n%s\n\n%s\n\n", relevantFilesIntro, markdownList(files)))
+	}
+
+	// Include the notes of the worker this job was taken over from
+	if handoff := j.GetHandoff(); handoff != "" {
+		sb.WriteString(fmt.Sprintf("## Handoff\n%s\n\n", handoff))
+	}
+	if messages := j.GetMessages(); len(messages) > 0 {
+		sb.WriteString(fmt.Sprintf("## Messages\n%s\n\n%s\n\n", messagesIntro, markdownList(messages)))
+	}
+
 	sb.WriteString(fmt.Sprintf("## Your Task\n%s\n\n", j.Description))
+	if body := j.GetBody(); body != "" {
+		sb.WriteString(fmt.Sprintf("## Task Details\n%s\n\n", strings.TrimSpace(body)))
+	}
+	if j.NeedsPlan() {
+		sb.WriteString(planPhasePrompt)
+		return sb.String()
+	}
+	if plan := approvedPlan(j); plan != "" {
+		sb.WriteString(fmt.Sprintf("## Approved Plan\nCarry out this plan, which the boss approved:\n%s\n\n", plan))
+	}
+	if w.canDelegate() {
+		sb.WriteString(fmt.Sprintf("## Delegation\n%s\n\n", delegationIntro))
+	}
 	sb.WriteString("Work in your designated worktree. Make commits as you go.\n")
 	if w.MergeTargetBranch != "" {
 		sb.WriteString(fmt.Sprintf("When finished, your work will be merged into the '%s' branch.\n", w.MergeTargetBranch))
@@ -406,7 +747,22 @@ func (w *Worker) emitEvent(eventType, message string) {
 	if w.CurrentJob != nil {
 		event.Job = w.CurrentJob.ID
 	}
+	w.sendEvent(event)
+}
+
+// emitJobEvent emits an event about j, which may be running in any of the
+// worker's slots.
+func (w *Worker) emitJobEvent(j *job.Job, eventType, message string) {
+	w.sendEvent(Event{
+		Type:    eventType,
+		Worker:  w.ID,
+		Job:     j.ID,
+		Message: message,
+		Time:    time.Now(),
+	})
+}
 
+func (w *Worker) sendEvent(event Event) {
 	select {
 	case w.events <- event:
 	default:
@@ -450,6 +806,22 @@ func (w *Worker) UpdateActivity() {
 	w.LastActivityAt = time.Now()
 }
 
+// CurrentActivity returns what the worker is doing in its current job.
+func (w *Worker) CurrentActivity() Activity {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if s := w.firstBusySlot(); s != nil {
+		return s.activity
+	}
+	return Activity{}
+}
+
+// lastLine returns the last non-blank line of text.
+func lastLine(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
 // GetLastActivity returns the last activity timestamp.
 func (w *Worker) GetLastActivity() time.Time {
 	w.mu.RLock()
@@ -457,7 +829,8 @@ func (w *Worker) GetLastActivity() time.Time {
 	return w.LastActivityAt
 }
 
-// IsStuck returns true if the worker has been inactive longer than the threshold.
+// IsStuck returns true if the worker has gone without progress longer than
+// the threshold. Activity that is not progress does not count.
 func (w *Worker) IsStuck(threshold time.Duration) bool {
 	w.mu.RLock()
 	defer w.mu.RUnlock()
@@ -467,13 +840,7 @@ func (w *Worker) IsStuck(threshold time.Duration) bool {
 		return false
 	}
 
-	// If never had activity, use created time
-	lastActivity := w.LastActivityAt
-	if lastActivity.IsZero() {
-		lastActivity = w.CreatedAt
-	}
-
-	return time.Since(lastActivity) > threshold
+	return time.Since(w.lastProgressAt()) > threshold
 }
 
 // SetStandingOrders sets the standing orders for this worker.
@@ -483,6 +850,27 @@ func (w *Worker) SetStandingOrders(orders []string) {
 	w.StandingOrders = orders
 }
 
+// SetLabels sets the labels the scheduler matches against job labels.
+func (w *Worker) SetLabels(labels map[string]string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.Labels = labels
+}
+
+// GetLabels returns a copy of the worker's labels.
+func (w *Worker) GetLabels() map[string]string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.Labels == nil {
+		return nil
+	}
+	labels := make(map[string]string, len(w.Labels))
+	for k, v := range w.Labels {
+		labels[k] = v
+	}
+	return labels
+}
+
 // GetStandingOrders returns the standing orders for this worker.
 func (w *Worker) GetStandingOrders() []string {
 	w.mu.RLock()
@@ -506,8 +894,17 @@ func (w *Worker) ClearStandingOrders() {
 	w.StandingOrders = nil
 }
 
-// UpdateCost updates the cost tracking fields.
+// UpdateCost updates the cost tracking fields, attributing the cost to the
+// current job.
 func (w *Worker) UpdateCost(cost string, tokens int) {
+	var jobID string
+	if j := w.GetCurrentJob(); j != nil {
+		jobID = j.ID
+	}
+	w.updateCost(jobID, cost, tokens)
+}
+
+func (w *Worker) updateCost(jobID, cost string, tokens int) {
 	w.mu.Lock()
 	w.TotalCost = cost
 	w.TotalTokens = tokens
@@ -518,14 +915,18 @@ func (w *Worker) UpdateCost(cost string, tokens int) {
 
 	// Call the cost update callback if set
 	if callback != nil {
-		callback(id, name, cost, tokens)
+		callback(id, name, jobID, cost, tokens)
 	}
 }
 
-// SendMessage sends a message to the worker's active Claude session.
+// SendMessage sends a message to the Claude session running the worker's
+// current job.
 func (w *Worker) SendMessage(message string) error {
 	w.mu.RLock()
 	client := w.client
+	if s := w.firstBusySlot(); s != nil && s.client != nil {
+		client = s.client // Jobs in their own worktree run on a separate client
+	}
 	status := w.Status
 	w.mu.RUnlock()
 
@@ -1,9 +1,14 @@
 package worker
 
 import (
+	"encoding/json"
+	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"cosa/internal/claude"
+	"cosa/internal/job"
 )
 
 func TestNew(t *testing.T) {
@@ -155,6 +160,45 @@ func TestWorker_IsStuck(t *testing.T) {
 	}
 }
 
+func TestWorker_IsStuck_ActivityWithoutProgress(t *testing.T) {
+	w := New(Config{Name: "test"})
+	w.Status = StatusWorking
+	w.LastProgressAt = time.Now().Add(-2 * time.Hour)
+
+	// Output from Claude that is not progress does not count
+	w.UpdateActivity()
+	if !w.IsStuck(time.Hour) {
+		t.Error("worker with activity but no recent progress should be stuck")
+	}
+
+	w.markProgress("committed")
+	if w.IsStuck(time.Hour) {
+		t.Error("worker with recent progress should not be stuck")
+	}
+	if _, what := w.GetLastProgress(); what != "committed" {
+		t.Errorf("expected last progress 'committed', got %q", what)
+	}
+}
+
+func TestProgressOf(t *testing.T) {
+	tests := []struct {
+		tool *claude.ToolCall
+		want string
+	}{
+		{&claude.ToolCall{Name: "Edit", Input: json.RawMessage(`{"file_path": "/repo/main.go"}`)}, "wrote main.go"},
+		{&claude.ToolCall{Name: "NotebookEdit", Input: json.RawMessage(`{"notebook_path": "a/nb.ipynb"}`)}, "wrote nb.ipynb"},
+		{&claude.ToolCall{Name: "Bash", Input: json.RawMessage(`{"command": "git add -A && git commit -m fix"}`)}, "committed"},
+		{&claude.ToolCall{Name: "Bash", Input: json.RawMessage(`{"command": "go test ./..."}`)}, "Bash finished"},
+		{&claude.ToolCall{Name: "Read"}, "Read finished"},
+		{nil, "tool finished"},
+	}
+	for _, tt := range tests {
+		if got := progressOf(tt.tool); got != tt.want {
+			t.Errorf("progressOf(%+v) = %q, want %q", tt.tool, got, tt.want)
+		}
+	}
+}
+
 func TestWorker_IsStuck_NoActivity(t *testing.T) {
 	w := New(Config{Name: "test"})
 	w.Status = StatusWorking
@@ -340,6 +384,33 @@ func TestWorker_EventCallback(t *testing.T) {
 	}
 }
 
+func TestWorker_ClaudeEventCallback(t *testing.T) {
+	var gotWorker string
+	var gotJob *job.Job
+	var gotEvent claude.Event
+	w := New(Config{
+		Name: "vito",
+		OnClaudeEvent: func(workerName string, j *job.Job, event claude.Event) {
+			gotWorker = workerName
+			gotJob = j
+			gotEvent = event
+		},
+	})
+
+	j := job.New("test job")
+	w.handleClaudeEvent(w.slots[0], j, claude.Event{Type: claude.EventAssistantText, Message: "hello"})
+
+	if gotWorker != "vito" {
+		t.Errorf("expected worker 'vito', got '%s'", gotWorker)
+	}
+	if gotJob != j {
+		t.Error("expected callback to receive the job")
+	}
+	if gotEvent.Type != claude.EventAssistantText || gotEvent.Message != "hello" {
+		t.Errorf("unexpected event: %+v", gotEvent)
+	}
+}
+
 func TestWorker_StopEvent(t *testing.T) {
 	var lastEvent *Event
 	cfg := Config{
@@ -442,3 +513,147 @@ func TestWorker_JobStats(t *testing.T) {
 		t.Errorf("expected 2 failed jobs, got %d", w.JobsFailed)
 	}
 }
+
+func TestWorker_CancelJob_NotRunning(t *testing.T) {
+	w := New(Config{Name: "test"})
+
+	if err := w.CancelJob("some-job", time.Second); err == nil {
+		t.Error("expected error cancelling a job the worker is not running")
+	}
+}
+
+func TestWorker_CancelJob_KeepsCancelledStatus(t *testing.T) {
+	completed := false
+	w := New(Config{
+		Name:          "test",
+		OnJobComplete: func(*job.Job) { completed = true },
+	})
+
+	j := job.New("test job")
+	w.slots[0].job = j
+	j.Queue()
+	j.Start(w.ID, "session-1")
+
+	if err := w.CancelJob(j.ID, 0); err != nil {
+		t.Fatalf("unexpected error cancelling job: %v", err)
+	}
+	j.Cancel()
+
+	// Claude finishing after cancellation must not flip the job to completed
+	w.handleJobSuccess(w.slots[0], j)
+	w.handleJobFailure(j, nil)
+
+	if j.GetStatus() != job.StatusCancelled {
+		t.Errorf("expected status %s, got %s", job.StatusCancelled, j.GetStatus())
+	}
+	if completed {
+		t.Error("completion callback should not fire for a cancelled job")
+	}
+	if w.JobsCompleted != 0 || w.JobsFailed != 0 {
+		t.Errorf("expected no stats change, got completed=%d failed=%d", w.JobsCompleted, w.JobsFailed)
+	}
+}
+
+func TestWorker_JobOutputIsLastMessage(t *testing.T) {
+	w := New(Config{Name: "test"})
+
+	j := job.New("test job")
+	j.Queue()
+	j.Start(w.ID, "session-1")
+	w.handleClaudeEvent(w.slots[0], j, claude.Event{Type: claude.EventAssistantText, Message: "Looking at the schema"})
+	w.handleClaudeEvent(w.slots[0], j, claude.Event{Type: claude.EventAssistantText, Message: "Should users be soft-deleted?"})
+	w.handleJobSuccess(w.slots[0], j)
+
+	if j.Output != "Should users be soft-deleted?" {
+		t.Errorf("expected the last message as output, got %q", j.Output)
+	}
+}
+
+func TestWorker_CurrentActivity(t *testing.T) {
+	w := New(Config{Name: "test"})
+
+	j := job.New("test job")
+	w.slots[0].job = j
+	j.Queue()
+	j.Start(w.ID, "session-1")
+	w.handleClaudeEvent(w.slots[0], j, claude.Event{Type: claude.EventAssistantText, Message: "Reading the handlers.\n\nNext I'll update the tests.\n"})
+	w.handleClaudeEvent(w.slots[0], j, claude.Event{Type: claude.EventToolUse, Tool: &claude.ToolCall{Name: "Edit"}})
+
+	a := w.CurrentActivity()
+	if a.Progress != "Next I'll update the tests." {
+		t.Errorf("expected the last line as progress, got %q", a.Progress)
+	}
+	if a.Tool != "Edit" || a.ToolSince.IsZero() {
+		t.Errorf("expected Edit in use, got %q since %v", a.Tool, a.ToolSince)
+	}
+
+	w.handleClaudeEvent(w.slots[0], j, claude.Event{Type: claude.EventToolResult, Tool: &claude.ToolCall{Name: "Edit"}})
+	if a := w.CurrentActivity(); a.Tool != "" || a.Progress == "" {
+		t.Errorf("expected no tool in use and the progress kept, got %+v", a)
+	}
+}
+
+func TestWorker_BuildPrompt_IncludesBody(t *testing.T) {
+	w := New(Config{Name: "worker"})
+
+	j := job.New("Rework the session store")
+	j.SetBody("Move sessions to SQLite.\n\n```go\ntype Store interface{}\n```")
+
+	prompt := w.buildPrompt(j)
+	if !strings.Contains(prompt, "## Your Task\nRework the session store") {
+		t.Error("expected prompt to include the job title as the task")
+	}
+	if !strings.Contains(prompt, "## Task Details\nMove sessions to SQLite.") {
+		t.Error("expected prompt to include the job body")
+	}
+
+	plain := w.buildPrompt(job.New("Fix the login redirect"))
+	if strings.Contains(plain, "## Task Details") {
+		t.Error("expected no details section for a job without a body")
+	}
+}
+
+func TestWorker_Slots(t *testing.T) {
+	w := New(Config{Name: "tony", Role: RoleCapo, Slots: 2})
+	w.SessionID = "sess-own"
+
+	j1, j2 := job.New("Add login"), job.New("Fix logout")
+	w.mu.Lock()
+	s1, err := w.claimSlot(j1, true)
+	if err != nil {
+		t.Fatalf("unexpected error claiming a slot: %v", err)
+	}
+	if _, err := w.claimSlot(j2, false); err == nil {
+		t.Error("expected the worker's own worktree to be in use")
+	}
+	s2, err := w.claimSlot(j2, true)
+	if err != nil {
+		t.Fatalf("unexpected error claiming the second slot: %v", err)
+	}
+	if _, err := w.claimSlot(job.New("Third"), true); err == nil {
+		t.Error("expected no free slot")
+	}
+	w.mu.Unlock()
+
+	if w.FreeSlots() != 0 || w.BusySlots() != 2 || len(w.GetJobs()) != 2 {
+		t.Errorf("expected both slots busy, got %d free and %d busy", w.FreeSlots(), w.BusySlots())
+	}
+
+	// Only the first slot's session is the worker's own
+	w.handleClaudeEvent(s2, j2, claude.Event{Type: claude.EventInit, SessionID: "sess-2"})
+	if w.SessionID != "sess-own" {
+		t.Errorf("expected the worker's session kept, got %q", w.SessionID)
+	}
+	if slots := w.Slots(); slots[1].SessionID != "sess-2" || slots[1].Job != j2 {
+		t.Errorf("expected sess-2 running %s in the second slot, got %+v", j2.ID, slots[1])
+	}
+
+	w.releaseSlot(s1)
+	if w.GetStatus() != StatusWorking || w.GetCurrentJob() != j2 || w.FreeSlots() != 1 || !w.HasJob(j2.ID) {
+		t.Errorf("expected to keep working on %s with a free slot, got %s on %v", j2.ID, w.GetStatus(), w.GetCurrentJob())
+	}
+	w.releaseSlot(s2)
+	if w.GetStatus() != StatusIdle || w.GetCurrentJob() != nil || w.FreeSlots() != 2 {
+		t.Errorf("expected idle with both slots free, got %s", w.GetStatus())
+	}
+}